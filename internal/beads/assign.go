@@ -0,0 +1,153 @@
+package beads
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// assignableTypes are the bead types assignment rules apply to. Gas Town's
+// own beads (agents, merge requests, messages, molecules, ...) use the
+// assignee for claims and routing, so rules never touch them.
+var assignableTypes = map[string]bool{
+	"":        true,
+	"task":    true,
+	"bug":     true,
+	"feature": true,
+	"chore":   true,
+	"epic":    true,
+}
+
+// typeMarkerLabels are gt: labels that mark a bead rather than type it.
+var typeMarkerLabels = map[string]bool{
+	"gt:keep":         true,
+	"gt:owned":        true,
+	"gt:owned-direct": true,
+}
+
+// issueType returns the issue's type, from issue_type or a gt:<type> label.
+func issueType(issue *Issue) string {
+	if issue.Type != "" {
+		return issue.Type
+	}
+	for _, l := range issue.Labels {
+		if strings.HasPrefix(l, "gt:") && !typeMarkerLabels[l] {
+			return strings.TrimPrefix(l, "gt:")
+		}
+	}
+	return ""
+}
+
+// RuleCheck is the outcome of checking one assignment rule against a bead.
+type RuleCheck struct {
+	Rule    config.AssignmentRule `json:"rule"`
+	Matched bool                  `json:"matched"`
+	Reason  string                `json:"reason"` // why it matched or didn't
+}
+
+// AssignmentDecision explains which assignee a bead gets and why.
+type AssignmentDecision struct {
+	Assignee string      `json:"assignee,omitempty"` // "" = leave unassigned
+	Rule     int         `json:"rule"`               // 1-based matching rule; 0 = default or none
+	Owner    string      `json:"owner,omitempty"`    // team or worker from the town ownership map
+	Reason   string      `json:"reason"`
+	Checks   []RuleCheck `json:"checks,omitempty"` // every rule checked, in order
+}
+
+// ResolveAssignee applies a rig's assignment rules to a bead. Rules are
+// checked in order and the first match wins; the default applies when no
+// rule matches. Ephemeral beads and Gas Town's own bead types are never
+// assigned.
+func ResolveAssignee(cfg *config.AssignmentConfig, issue *Issue) AssignmentDecision {
+	if cfg.IsEmpty() {
+		return AssignmentDecision{Reason: "rig has no assignment rules"}
+	}
+	if reason := notAssignable(issue); reason != "" {
+		return AssignmentDecision{Reason: reason}
+	}
+	typ := issueType(issue)
+
+	var d AssignmentDecision
+	for i, rule := range cfg.Rules {
+		check := RuleCheck{Rule: rule, Matched: true}
+		var why []string
+		if rule.Type != "" {
+			if typ != rule.Type {
+				check.Matched = false
+				why = append(why, fmt.Sprintf("type is %q, not %q", typ, rule.Type))
+			} else {
+				why = append(why, "type is "+typ)
+			}
+		}
+		if rule.Label != "" {
+			if !HasLabel(issue, rule.Label) {
+				check.Matched = false
+				why = append(why, "no label "+rule.Label)
+			} else {
+				why = append(why, "has label "+rule.Label)
+			}
+		}
+		if rule.Path != "" {
+			if ok, _ := path.Match(rule.Path, issue.CreatedBy); !ok {
+				check.Matched = false
+				why = append(why, fmt.Sprintf("creator %q doesn't match %s", issue.CreatedBy, rule.Path))
+			} else {
+				why = append(why, fmt.Sprintf("creator %s matches %s", issue.CreatedBy, rule.Path))
+			}
+		}
+		check.Reason = strings.Join(why, ", ")
+		d.Checks = append(d.Checks, check)
+
+		if check.Matched && d.Rule == 0 {
+			d.Assignee = rule.Assignee
+			d.Rule = i + 1
+			d.Reason = fmt.Sprintf("rule %d (%s): %s", i+1, rule.Describe(), check.Reason)
+		}
+	}
+	if d.Rule != 0 {
+		return d
+	}
+	if cfg.Default != "" {
+		d.Assignee = cfg.Default
+		d.Reason = "no rule matched; rig default"
+	} else {
+		d.Reason = "no rule matched and the rig has no default"
+	}
+	return d
+}
+
+// ResolveAssigneeWithOwners is ResolveAssignee with the town ownership map
+// as a fallback: a bead the rig's rules leave unassigned goes to the owner
+// of one of its labels (a team's lead, or a worker).
+func ResolveAssigneeWithOwners(cfg *config.AssignmentConfig, owners *config.OwnersConfig, issue *Issue) AssignmentDecision {
+	d := ResolveAssignee(cfg, issue)
+	if d.Assignee != "" || owners.IsEmpty() || notAssignable(issue) != "" {
+		return d
+	}
+	rule, ok := owners.OwnerForLabels(issue.Labels)
+	if !ok {
+		return d
+	}
+	assignee := owners.Assignee(rule.Owner)
+	if assignee == "" {
+		d.Reason += fmt.Sprintf("; label %s is owned by team %s, which has no lead", rule.Label, rule.Owner)
+		return d
+	}
+	d.Assignee = assignee
+	d.Owner = rule.Owner
+	d.Reason = fmt.Sprintf("owners: label %s is owned by %s", rule.Label, rule.Owner)
+	return d
+}
+
+// notAssignable returns why issue is never auto-assigned, or "".
+func notAssignable(issue *Issue) string {
+	if issue.Ephemeral {
+		return "ephemeral beads are not auto-assigned"
+	}
+	if typ := issueType(issue); !assignableTypes[typ] {
+		return fmt.Sprintf("%s beads are not auto-assigned", typ)
+	}
+	return ""
+}
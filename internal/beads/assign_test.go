@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestResolveAssignee(t *testing.T) {
+	cfg := &config.AssignmentConfig{
+		Default: "gastown/crew/triage",
+		Rules: []config.AssignmentRule{
+			{Label: "infra", Assignee: "gastown/crew/max"},
+			{Type: "bug", Assignee: "gastown/crew/bugs"},
+			{Type: "epic", Path: "gastown/polecats/*", Assignee: "mayor/"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		issue    *Issue
+		assignee string
+		rule     int
+	}{
+		{"label wins over later type rule", &Issue{Type: "bug", Labels: []string{"infra"}}, "gastown/crew/max", 1},
+		{"type from issue_type", &Issue{Type: "bug"}, "gastown/crew/bugs", 2},
+		{"type from gt label", &Issue{Labels: []string{"gt:keep", "gt:bug"}}, "gastown/crew/bugs", 2},
+		{"path glob on creator", &Issue{Type: "epic", CreatedBy: "gastown/polecats/nux"}, "mayor/", 3},
+		{"path glob mismatch falls to default", &Issue{Type: "epic", CreatedBy: "gastown/crew/max"}, "gastown/crew/triage", 0},
+		{"untyped gets default", &Issue{}, "gastown/crew/triage", 0},
+		{"merge requests are never assigned", &Issue{Labels: []string{"gt:merge-request", "infra"}}, "", 0},
+		{"ephemeral beads are never assigned", &Issue{Type: "task", Ephemeral: true}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ResolveAssignee(cfg, tt.issue)
+			if d.Assignee != tt.assignee || d.Rule != tt.rule {
+				t.Errorf("got assignee %q rule %d (%s), want %q rule %d", d.Assignee, d.Rule, d.Reason, tt.assignee, tt.rule)
+			}
+		})
+	}
+}
+
+func TestResolveAssignee_ExplainsEveryRule(t *testing.T) {
+	cfg := &config.AssignmentConfig{Rules: []config.AssignmentRule{
+		{Type: "task", Assignee: "a"},
+		{Label: "infra", Assignee: "b"},
+	}}
+	d := ResolveAssignee(cfg, &Issue{Type: "task", Labels: []string{"infra"}})
+	if len(d.Checks) != 2 || !d.Checks[0].Matched || !d.Checks[1].Matched {
+		t.Fatalf("checks = %+v, want both rules checked and matched", d.Checks)
+	}
+	if d.Assignee != "a" {
+		t.Errorf("Assignee = %q, want first match", d.Assignee)
+	}
+
+	d = ResolveAssignee(cfg, &Issue{Type: "bug"})
+	if d.Assignee != "" || d.Checks[0].Reason != `type is "bug", not "task"` {
+		t.Errorf("unexpected decision: %+v", d)
+	}
+
+	if d := ResolveAssignee(nil, &Issue{Type: "task"}); d.Assignee != "" || d.Reason == "" {
+		t.Errorf("nil config: %+v", d)
+	}
+}
+
+func TestResolveAssigneeWithOwners_Fallback(t *testing.T) {
+	owners := &config.OwnersConfig{
+		Teams: []config.TeamDef{
+			{Name: "core", Members: []string{"gastown/crew/max"}},
+			{Name: "web", Members: []string{"gastown/crew/a", "gastown/crew/b"}},
+		},
+		Rules: []config.OwnerRule{
+			{Label: "infra", Owner: "core"},
+			{Label: "frontend", Owner: "web"},
+		},
+	}
+	rules := &config.AssignmentConfig{Rules: []config.AssignmentRule{{Type: "bug", Assignee: "gastown/crew/triage"}}}
+
+	if d := ResolveAssigneeWithOwners(rules, owners, &Issue{Type: "bug", Labels: []string{"infra"}}); d.Assignee != "gastown/crew/triage" {
+		t.Errorf("rig rule should win over owners, got %+v", d)
+	}
+	d := ResolveAssigneeWithOwners(nil, owners, &Issue{Type: "task", Labels: []string{"infra"}})
+	if d.Assignee != "gastown/crew/max" || d.Owner != "core" {
+		t.Errorf("owners fallback = %+v, want core's only member", d)
+	}
+	if d := ResolveAssigneeWithOwners(nil, owners, &Issue{Type: "task", Labels: []string{"frontend"}}); d.Assignee != "" {
+		t.Errorf("team without a lead should leave the bead unassigned, got %+v", d)
+	}
+	if d := ResolveAssigneeWithOwners(nil, owners, &Issue{Type: "merge-request", Labels: []string{"infra"}}); d.Assignee != "" {
+		t.Errorf("Gas Town beads must not be routed, got %+v", d)
+	}
+}
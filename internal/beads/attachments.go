@@ -0,0 +1,246 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AttachmentsDir is the directory in a beads directory holding attached
+// files. Content is stored once per SHA-256 under blobs/, so attaching the
+// same log to several beads costs nothing extra; index.jsonl records which
+// bead each attachment belongs to, one JSON line per attach.
+const AttachmentsDir = "attachments"
+
+// MaxAttachmentSize is the largest file that can be attached.
+const MaxAttachmentSize = 50 << 20
+
+// HistoryAttachment is the history entry kind for an attached file; Value
+// is its name.
+const HistoryAttachment = "attachment"
+
+// Attachment is a file attached to a bead.
+type Attachment struct {
+	IssueID     string `json:"issue_id"`
+	Name        string `json:"name"`
+	SHA256      string `json:"sha256"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+	AddedAt     string `json:"added_at"`
+	AddedBy     string `json:"added_by,omitempty"`
+}
+
+// ShortHash is the attachment's hash abbreviated for display.
+func (a Attachment) ShortHash() string {
+	if len(a.SHA256) > 12 {
+		return a.SHA256[:12]
+	}
+	return a.SHA256
+}
+
+func attachmentIndexPath(beadsDir string) string {
+	return filepath.Join(beadsDir, AttachmentsDir, "index.jsonl")
+}
+
+// AttachmentBlobPath returns where the content with hash sum is stored.
+func AttachmentBlobPath(beadsDir, sum string) string {
+	return filepath.Join(beadsDir, AttachmentsDir, "blobs", sum[:2], sum)
+}
+
+// Attach stores the file at path and attaches it to issueID under name
+// (default: the file's base name). Attaching the same content under the
+// same name again returns the existing attachment.
+func (b *Beads) Attach(issueID, path, name string) (*Attachment, error) {
+	if _, err := b.Show(issueID); err != nil {
+		return nil, err
+	}
+	a, added, err := attachFile(b.getResolvedBeadsDir(), issueID, path, name, b.getActor())
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		b.recordHistory(HistoryEntry{IssueID: issueID, Kind: HistoryAttachment, Value: a.Name, Detail: a.ShortHash()})
+	}
+	return a, nil
+}
+
+// attachFile stores the file and records it in the index of beadsDir.
+// added is false when the same content was already attached under name.
+func attachFile(beadsDir, issueID, path, name, actor string) (a *Attachment, added bool, err error) {
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, false, fmt.Errorf("invalid attachment name %q", name)
+	}
+
+	sum, size, sniffed, err := storeAttachmentBlob(beadsDir, path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := ListAttachments(beadsDir, issueID)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range existing {
+		if existing[i].Name == name && existing[i].SHA256 == sum {
+			return &existing[i], false, nil
+		}
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = sniffed
+	}
+	a = &Attachment{
+		IssueID:     issueID,
+		Name:        name,
+		SHA256:      sum,
+		Size:        size,
+		ContentType: contentType,
+		AddedAt:     currentTimestamp(),
+		AddedBy:     actor,
+	}
+	line, err := json.Marshal(a)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshaling attachment: %w", err)
+	}
+	// One write per attach so concurrent writers never interleave lines
+	f, err := os.OpenFile(attachmentIndexPath(beadsDir), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, false, fmt.Errorf("opening attachment index: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, false, fmt.Errorf("writing attachment index: %w", err)
+	}
+	return a, true, nil
+}
+
+// storeAttachmentBlob copies the file at path into the blob store, returning
+// its hash, size and sniffed content type.
+func storeAttachmentBlob(beadsDir, path string) (sum string, size int64, contentType string, err error) {
+	src, err := os.Open(path) //nolint:gosec // G304: the user names the file to attach
+	if err != nil {
+		return "", 0, "", fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return "", 0, "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, "", fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > MaxAttachmentSize {
+		return "", 0, "", fmt.Errorf("%s is %d bytes; attachments are limited to %d MiB", path, info.Size(), MaxAttachmentSize>>20)
+	}
+
+	blobsDir := filepath.Join(beadsDir, AttachmentsDir, "blobs")
+	if err := os.MkdirAll(blobsDir, 0755); err != nil {
+		return "", 0, "", fmt.Errorf("creating attachment store: %w", err)
+	}
+	tmp, err := os.CreateTemp(blobsDir, ".incoming-*")
+	if err != nil {
+		return "", 0, "", fmt.Errorf("creating attachment: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after the rename
+
+	h := sha256.New()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(src, head)
+	contentType = http.DetectContentType(head[:n])
+	size, err = io.Copy(io.MultiWriter(tmp, h), io.MultiReader(bytes.NewReader(head[:n]), io.LimitReader(src, MaxAttachmentSize)))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, "", fmt.Errorf("copying %s: %w", path, err)
+	}
+	sum = hex.EncodeToString(h.Sum(nil))
+
+	dest := AttachmentBlobPath(beadsDir, sum)
+	if _, err := os.Stat(dest); err == nil {
+		return sum, size, contentType, nil // already stored
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", 0, "", fmt.Errorf("creating attachment store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", 0, "", fmt.Errorf("storing attachment: %w", err)
+	}
+	return sum, size, contentType, nil
+}
+
+// ListAttachments returns the attachments of issueID in beadsDir, oldest
+// first. A beads directory without attachments has none.
+func ListAttachments(beadsDir, issueID string) ([]Attachment, error) {
+	f, err := os.Open(attachmentIndexPath(beadsDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening attachment index: %w", err)
+	}
+	defer f.Close()
+
+	var attachments []Attachment
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var a Attachment
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil || len(a.SHA256) != sha256.Size*2 {
+			continue // skip a torn line rather than hide every attachment
+		}
+		if a.IssueID == issueID {
+			attachments = append(attachments, a)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading attachment index: %w", err)
+	}
+	return attachments, nil
+}
+
+// FindAttachment returns issueID's attachment named ref, or whose hash
+// starts with ref. The newest match wins when a name was attached twice.
+func FindAttachment(beadsDir, issueID, ref string) (*Attachment, error) {
+	attachments, err := ListAttachments(beadsDir, issueID)
+	if err != nil {
+		return nil, err
+	}
+	var byHash *Attachment
+	for i := len(attachments) - 1; i >= 0; i-- {
+		a := &attachments[i]
+		if a.Name == ref {
+			return a, nil
+		}
+		if byHash == nil && len(ref) >= 6 && strings.HasPrefix(a.SHA256, ref) {
+			byHash = a
+		}
+	}
+	if byHash != nil {
+		return byHash, nil
+	}
+	return nil, fmt.Errorf("%s has no attachment %q", issueID, ref)
+}
+
+// OpenAttachment opens an attachment's content.
+func OpenAttachment(beadsDir string, a *Attachment) (*os.File, error) {
+	f, err := os.Open(AttachmentBlobPath(beadsDir, a.SHA256)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, fmt.Errorf("opening attachment %s: %w", a.Name, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,67 @@
+package beads
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttachFile(t *testing.T) {
+	beadsDir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(src, []byte("ok\nFAIL TestFoo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, added, err := attachFile(beadsDir, "gt-abc", src, "", "gastown/polecats/toast")
+	if err != nil {
+		t.Fatalf("attachFile: %v", err)
+	}
+	if !added || a.Name != "build.log" || a.Size != 16 || a.AddedBy != "gastown/polecats/toast" {
+		t.Errorf("attachment = %+v, added = %v", a, added)
+	}
+	if _, err := os.Stat(AttachmentBlobPath(beadsDir, a.SHA256)); err != nil {
+		t.Errorf("blob not stored: %v", err)
+	}
+
+	// Same content under the same name is not attached twice.
+	if _, added, err := attachFile(beadsDir, "gt-abc", src, "", ""); err != nil || added {
+		t.Errorf("re-attach: added = %v, err = %v; want existing", added, err)
+	}
+	// The same content on another bead shares the blob.
+	b, _, err := attachFile(beadsDir, "gt-def", src, "log.txt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.SHA256 != a.SHA256 {
+		t.Errorf("same content hashed differently: %s vs %s", b.SHA256, a.SHA256)
+	}
+
+	list, err := ListAttachments(beadsDir, "gt-abc")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListAttachments(gt-abc) = %v, %v; want 1", list, err)
+	}
+
+	for _, ref := range []string{"build.log", a.SHA256[:8]} {
+		found, err := FindAttachment(beadsDir, "gt-abc", ref)
+		if err != nil {
+			t.Fatalf("FindAttachment(%q): %v", ref, err)
+		}
+		f, err := OpenAttachment(beadsDir, found)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(f)
+		f.Close()
+		if string(data) != "ok\nFAIL TestFoo\n" {
+			t.Errorf("content via %q = %q", ref, data)
+		}
+	}
+	if _, err := FindAttachment(beadsDir, "gt-abc", "log.txt"); err == nil {
+		t.Error("found another bead's attachment")
+	}
+	if _, _, err := attachFile(beadsDir, "gt-abc", src, "../escape", ""); err == nil {
+		t.Error("accepted a name with a path separator")
+	}
+}
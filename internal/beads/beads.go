@@ -9,8 +9,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/runtime"
 )
@@ -124,14 +126,19 @@ type IssueDep struct {
 
 // ListOptions specifies filters for listing issues.
 type ListOptions struct {
-	Status     string // "open", "closed", "all"
-	Type       string // Deprecated: use Label instead. "task", "bug", "feature", "epic"
-	Label      string // Label filter (e.g., "gt:agent", "gt:merge-request")
-	Priority   int    // 0-4, -1 for no filter
-	Parent     string // filter by parent ID
-	Assignee   string // filter by assignee (e.g., "gastown/Toast")
-	NoAssignee bool   // filter for issues with no assignee
-	Limit      int    // Max results (0 = unlimited, overrides bd default of 50)
+	Status       string    // "open", "closed", "deleted", "all"; empty for everything not closed or deleted
+	Statuses     []string  // any of these statuses (overrides Status)
+	Type         string    // Deprecated: use Label or Types instead. "task", "bug", "feature", "epic"
+	Types        []string  // any of these types, by their gt:<type> label (e.g., "merge-request")
+	Label        string    // Label filter (e.g., "gt:agent", "gt:merge-request")
+	Priorities   []int     // any of these priorities (0-4); empty for all
+	Parent       string    // filter by parent ID
+	Convoy       string    // filter to issues tracked by this convoy (e.g., "hq-cv-abc")
+	Assignee     string    // filter by assignee (e.g., "gastown/Toast")
+	NoAssignee   bool      // filter for issues with no assignee
+	UpdatedSince time.Time // only issues updated at or after this time
+	Text         string    // case-insensitive substring of the title or description
+	Limit        int       // Max results (0 = unlimited, overrides bd default of 50)
 }
 
 // CreateOptions specifies options for creating an issue.
@@ -234,6 +241,10 @@ func (b *Beads) Init(prefix string) error {
 
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) ([]byte, error) {
+	if err := b.waitForWriteQuota(args); err != nil {
+		return nil, err
+	}
+
 	// Use --allow-stale to prevent failures when db is out of sync with JSONL
 	// (e.g., after daemon is killed during shutdown before syncing).
 	fullArgs := append([]string{"--allow-stale"}, args...)
@@ -264,7 +275,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	if b.isolated {
 		env = filterBeadsEnv(os.Environ())
 	} else {
-		env = os.Environ()
+		env = withRevisionDatabase(withRigDoltPassword(os.Environ(), beadsDir), beadsDir)
 	}
 	cmd.Env = append(env, "BEADS_DIR="+beadsDir)
 
@@ -293,6 +304,10 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 // (e.g., setting an hq-* hook bead on a gt-* agent bead).
 // See: sling_helpers.go verifyBeadExists/hookBeadWithRetry for the same pattern.
 func (b *Beads) runWithRouting(args ...string) ([]byte, error) { //nolint:unparam // mirrors run() signature for consistency
+	if err := b.waitForWriteQuota(args); err != nil {
+		return nil, err
+	}
+
 	fullArgs := append([]string{"--allow-stale"}, args...)
 
 	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
@@ -304,7 +319,7 @@ func (b *Beads) runWithRouting(args ...string) ([]byte, error) { //nolint:unpara
 	if b.isolated {
 		env = filterBeadsEnv(os.Environ())
 	} else {
-		for _, e := range os.Environ() {
+		for _, e := range withDoltPassword(os.Environ()) {
 			if !strings.HasPrefix(e, "BEADS_DIR=") {
 				env = append(env, e)
 			}
@@ -360,6 +375,72 @@ func (b *Beads) wrapError(err error, stderr string, args []string) error {
 	return fmt.Errorf("bd %s: %w", strings.Join(args, " "), err)
 }
 
+// withDoltPassword forwards GT_DOLT_PASSWORD to bd as BEADS_DOLT_PASSWORD.
+// The Dolt server host/port/user reach bd through metadata.json, but the
+// password is kept out of that file, so a remote server needs it from the
+// environment. An explicit BEADS_DOLT_PASSWORD wins.
+func withDoltPassword(environ []string) []string {
+	var password string
+	for _, env := range environ {
+		if strings.HasPrefix(env, "BEADS_DOLT_PASSWORD=") {
+			return environ
+		}
+		if v, ok := strings.CutPrefix(env, "GT_DOLT_PASSWORD="); ok {
+			password = v
+		}
+	}
+	if password == "" {
+		return environ
+	}
+	return append(environ, "BEADS_DOLT_PASSWORD="+password)
+}
+
+// withRigDoltPassword is withDoltPassword for a rig whose metadata.json
+// names its own password variable (dolt_server_password_env, set for rigs
+// mapped to a remote server in settings/dolt-servers.json). That variable
+// is forwarded instead of GT_DOLT_PASSWORD; an explicit BEADS_DOLT_PASSWORD
+// still wins.
+func withRigDoltPassword(environ []string, beadsDir string) []string {
+	var meta struct {
+		PasswordEnv string `json:"dolt_server_password_env"`
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil || json.Unmarshal(data, &meta) != nil || meta.PasswordEnv == "" {
+		return withDoltPassword(environ)
+	}
+	var password string
+	for _, env := range environ {
+		if strings.HasPrefix(env, "BEADS_DOLT_PASSWORD=") {
+			return environ
+		}
+		if v, ok := strings.CutPrefix(env, meta.PasswordEnv+"="); ok {
+			password = v
+		}
+	}
+	if password == "" {
+		return environ
+	}
+	return append(environ, "BEADS_DOLT_PASSWORD="+password)
+}
+
+// withRevisionDatabase drops BD_BRANCH for a rig whose metadata.json names
+// a revision database ("town/gastown"): its beads are on a branch of the
+// town's shared Dolt database, which the database name already selects, and
+// bd's branch override would move it off the rig's branch. Such rigs get no
+// per-polecat branches.
+func withRevisionDatabase(environ []string, beadsDir string) []string {
+	if !strings.Contains(doltDatabase(beadsDir), "/") {
+		return environ
+	}
+	filtered := make([]string, 0, len(environ))
+	for _, env := range environ {
+		if !strings.HasPrefix(env, "BD_BRANCH=") {
+			filtered = append(filtered, env)
+		}
+	}
+	return filtered
+}
+
 // filterBeadsEnv removes beads-related environment variables from the given
 // environment slice. This ensures test isolation by preventing inherited
 // BD_ACTOR, BEADS_DB, GT_ROOT, HOME etc. from routing commands to production databases.
@@ -383,47 +464,60 @@ func filterBeadsEnv(environ []string) []string {
 
 // List returns issues matching the given options.
 func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
-	args := []string{"list", "--json"}
+	args, exact := opts.bdListArgs()
 
-	if opts.Status != "" {
-		args = append(args, "--status="+opts.Status)
-	}
-	// Prefer Label over Type (Type is deprecated)
-	if opts.Label != "" {
-		args = append(args, "--label="+opts.Label)
-	} else if opts.Type != "" {
-		// Deprecated: convert type to label for backward compatibility
-		args = append(args, "--label=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
-	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
-	}
-	if opts.Assignee != "" {
-		args = append(args, "--assignee="+opts.Assignee)
-	}
-	if opts.NoAssignee {
-		args = append(args, "--no-assignee")
+	var issues []*Issue
+	fromSQL := false
+	if !exact {
+		// bd can't express every filter; ask the Dolt server directly
+		// rather than loading a superset and filtering it here.
+		issues, fromSQL = b.listViaSQL(opts)
+	}
+	if !fromSQL {
+		out, err := b.run(args...)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parsing bd list output: %w", err)
+		}
 	}
-	if opts.Limit > 0 {
-		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
-	} else {
-		// Override bd's default limit of 50 to avoid silent truncation
-		args = append(args, "--limit=0")
+	if !exact && !fromSQL {
+		var filtered []*Issue
+		for _, issue := range issues {
+			if opts.Match(issue) {
+				filtered = append(filtered, issue)
+			}
+		}
+		issues = filtered
+	} else if !fromSQL && opts.defaultStatus() {
+		// bd's default list includes trashed beads.
+		issues = slices.DeleteFunc(issues, func(issue *Issue) bool { return issue.Status == StatusDeleted })
 	}
 
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
+	// bd list has no convoy filter: convoys track issues with 'tracks'
+	// dependencies, which may cross rigs, so filter on the tracked IDs.
+	if opts.Convoy != "" {
+		tracked, err := b.ConvoyTracked(opts.Convoy)
+		if err != nil {
+			return nil, err
+		}
+		inConvoy := make(map[string]bool, len(tracked))
+		for _, id := range tracked {
+			inConvoy[id] = true
+		}
+		var filtered []*Issue
+		for _, issue := range issues {
+			if inConvoy[issue.ID] {
+				filtered = append(filtered, issue)
+			}
+		}
+		issues = filtered
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	if opts.Limit > 0 && len(issues) > opts.Limit {
+		issues = issues[:opts.Limit]
 	}
-
 	return issues, nil
 }
 
@@ -433,7 +527,6 @@ func (b *Beads) ListByAssignee(assignee string) ([]*Issue, error) {
 	return b.List(ListOptions{
 		Status:   "all", // Include both open and closed for state derivation
 		Assignee: assignee,
-		Priority: -1, // No priority filter
 	})
 }
 
@@ -443,7 +536,6 @@ func (b *Beads) GetAssignedIssue(assignee string) (*Issue, error) {
 	issues, err := b.List(ListOptions{
 		Status:   "open",
 		Assignee: assignee,
-		Priority: -1,
 	})
 	if err != nil {
 		return nil, err
@@ -454,7 +546,6 @@ func (b *Beads) GetAssignedIssue(assignee string) (*Issue, error) {
 		issues, err = b.List(ListOptions{
 			Status:   "in_progress",
 			Assignee: assignee,
-			Priority: -1,
 		})
 		if err != nil {
 			return nil, err
@@ -466,7 +557,6 @@ func (b *Beads) GetAssignedIssue(assignee string) (*Issue, error) {
 		issues, err = b.List(ListOptions{
 			Status:   "hooked",
 			Assignee: assignee,
-			Priority: -1,
 		})
 		if err != nil {
 			return nil, err
@@ -739,8 +829,11 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 		}
 	}
 
-	_, err := b.run(args...)
-	return err
+	if _, err := b.run(args...); err != nil {
+		return err
+	}
+	b.recordHistory(updateHistory(id, opts)...)
+	return nil
 }
 
 // Close closes one or more issues.
@@ -758,8 +851,11 @@ func (b *Beads) Close(ids ...string) error {
 		args = append(args, "--session="+sessionID)
 	}
 
-	_, err := b.run(args...)
-	return err
+	if _, err := b.run(args...); err != nil {
+		return err
+	}
+	b.recordStatus("closed", "", ids...)
+	return nil
 }
 
 // CloseWithReason closes one or more issues with a reason.
@@ -778,10 +874,29 @@ func (b *Beads) CloseWithReason(reason string, ids ...string) error {
 		args = append(args, "--session="+sessionID)
 	}
 
-	_, err := b.run(args...)
+	if _, err := b.run(args...); err != nil {
+		return err
+	}
+	b.recordStatus("closed", reason, ids...)
+	return nil
+}
+
+// DeleteHard permanently deletes an issue (no tombstone). Meant for
+// throwaway beads, such as the ones gt rig verify creates.
+func (b *Beads) DeleteHard(id string) error {
+	_, err := b.run("delete", id, "--hard", "--force")
 	return err
 }
 
+// AddComment appends a comment to an issue.
+func (b *Beads) AddComment(id, text string) error {
+	if _, err := b.run("comment", id, text); err != nil {
+		return err
+	}
+	b.recordHistory(HistoryEntry{IssueID: id, Kind: HistoryComment, Value: text})
+	return nil
+}
+
 // ForceCloseWithReason closes one or more issues with --force, bypassing
 // dependency checks. Used by gt done where the polecat is about to be nuked
 // and open molecule wisps should not block issue closure.
@@ -798,8 +913,11 @@ func (b *Beads) ForceCloseWithReason(reason string, ids ...string) error {
 		args = append(args, "--session="+sessionID)
 	}
 
-	_, err := b.run(args...)
-	return err
+	if _, err := b.run(args...); err != nil {
+		return err
+	}
+	b.recordStatus("closed", reason, ids...)
+	return nil
 }
 
 // Release moves an in_progress issue back to open status.
@@ -819,8 +937,11 @@ func (b *Beads) ReleaseWithReason(id, reason string) error {
 		args = append(args, "--notes=Released: "+reason)
 	}
 
-	_, err := b.run(args...)
-	return err
+	if _, err := b.run(args...); err != nil {
+		return err
+	}
+	b.recordStatus("open", reason, id)
+	return nil
 }
 
 // AddDependency adds a dependency: issue depends on dependsOn.
@@ -829,6 +950,37 @@ func (b *Beads) AddDependency(issue, dependsOn string) error {
 	return err
 }
 
+// AddTypedDependency adds a dependency of the given type (blocks, related,
+// discovered-from, ...).
+func (b *Beads) AddTypedDependency(issue, dependsOn, depType string) error {
+	_, err := b.run("dep", "add", issue, dependsOn, "--type="+depType)
+	return err
+}
+
+// ConvoyTracked returns the IDs of the issues a convoy tracks. Convoys live
+// in the town's beads, so the lookup runs there, from anywhere in the town.
+func (b *Beads) ConvoyTracked(convoyID string) ([]string, error) {
+	town := b
+	if root := b.getTownRoot(); root != "" {
+		town = New(root)
+	}
+	out, err := town.run("dep", "list", convoyID, "--direction=down", "--type=tracks", "--json")
+	if err != nil {
+		return nil, fmt.Errorf("listing issues tracked by %s: %w", convoyID, err)
+	}
+	var deps []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(out, &deps); err != nil {
+		return nil, fmt.Errorf("parsing issues tracked by %s: %w", convoyID, err)
+	}
+	ids := make([]string, len(deps))
+	for i, dep := range deps {
+		ids[i] = ExtractIssueID(dep.ID)
+	}
+	return ids, nil
+}
+
 // RemoveDependency removes a dependency.
 func (b *Beads) RemoveDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "remove", issue, dependsOn)
@@ -841,6 +993,19 @@ func (b *Beads) Sync() error {
 	return err
 }
 
+// SyncExport exports pending changes to the JSONL snapshot, commits it to
+// the sync branch and pushes it, without pulling.
+func (b *Beads) SyncExport() error {
+	_, err := b.run("sync", "--no-pull")
+	return err
+}
+
+// SyncImport imports the JSONL snapshot into the database.
+func (b *Beads) SyncImport() error {
+	_, err := b.run("sync", "--import-only")
+	return err
+}
+
 // SyncFromMain syncs beads updates from main branch.
 func (b *Beads) SyncFromMain() error {
 	_, err := b.run("sync", "--from-main")
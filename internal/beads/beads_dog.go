@@ -57,9 +57,8 @@ func (b *Beads) CreateDogAgentBead(name, location string) (*Issue, error) {
 func (b *Beads) FindDogAgentBead(name string) (*Issue, error) {
 	// List all agent beads and filter by role_type:dog label
 	issues, err := b.List(ListOptions{
-		Label:    "gt:agent",
-		Status:   "all",
-		Priority: -1, // No priority filter
+		Label:  "gt:agent",
+		Status: "all",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listing agents: %w", err)
@@ -258,8 +258,11 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	}
 
 	// Close the issue
-	_, err = b.run("close", id, "--reason="+reason)
-	return err
+	if _, err := b.run("close", id, "--reason="+reason); err != nil {
+		return err
+	}
+	b.recordStatus("closed", reason, id)
+	return nil
 }
 
 // GetEscalationBead retrieves an escalation bead by ID.
@@ -23,9 +23,9 @@ func TestNew(t *testing.T) {
 // TestListOptions verifies ListOptions defaults.
 func TestListOptions(t *testing.T) {
 	opts := ListOptions{
-		Status:   "open",
-		Type:     "task",
-		Priority: 1,
+		Status:     "open",
+		Type:       "task",
+		Priorities: []int{1},
 	}
 	if opts.Status != "open" {
 		t.Errorf("Status = %q, want open", opts.Status)
@@ -2359,3 +2359,70 @@ func TestIsAgentBead(t *testing.T) {
 		})
 	}
 }
+
+// TestWithDoltPassword verifies GT_DOLT_PASSWORD is forwarded to bd.
+func TestWithDoltPassword(t *testing.T) {
+	env := withDoltPassword([]string{"PATH=/bin", "GT_DOLT_PASSWORD=s3cret"})
+	if env[len(env)-1] != "BEADS_DOLT_PASSWORD=s3cret" {
+		t.Errorf("password not forwarded: %v", env)
+	}
+
+	explicit := []string{"GT_DOLT_PASSWORD=s3cret", "BEADS_DOLT_PASSWORD=other"}
+	if got := withDoltPassword(explicit); len(got) != len(explicit) {
+		t.Errorf("explicit BEADS_DOLT_PASSWORD should win: %v", got)
+	}
+
+	if got := withDoltPassword([]string{"PATH=/bin"}); len(got) != 1 {
+		t.Errorf("no password: got %v", got)
+	}
+}
+
+// TestWithRigDoltPassword verifies a rig's own password variable is forwarded
+// instead of GT_DOLT_PASSWORD.
+func TestWithRigDoltPassword(t *testing.T) {
+	beadsDir := t.TempDir()
+	environ := []string{"GT_DOLT_PASSWORD=town", "BETA_DOLT_PW=beta"}
+
+	// No metadata: behaves like withDoltPassword.
+	if env := withRigDoltPassword(environ, beadsDir); env[len(env)-1] != "BEADS_DOLT_PASSWORD=town" {
+		t.Errorf("without metadata: %v", env)
+	}
+
+	meta := `{"dolt_server_host": "build-02.lan", "dolt_server_password_env": "BETA_DOLT_PW"}`
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(meta), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if env := withRigDoltPassword(environ, beadsDir); env[len(env)-1] != "BEADS_DOLT_PASSWORD=beta" {
+		t.Errorf("rig password not forwarded: %v", env)
+	}
+	if env := withRigDoltPassword([]string{"GT_DOLT_PASSWORD=town"}, beadsDir); len(env) != 1 {
+		t.Errorf("town password must not reach a rig's own server: %v", env)
+	}
+	explicit := append(environ, "BEADS_DOLT_PASSWORD=other")
+	if env := withRigDoltPassword(explicit, beadsDir); len(env) != len(explicit) {
+		t.Errorf("explicit BEADS_DOLT_PASSWORD should win: %v", env)
+	}
+}
+
+// TestWithRevisionDatabase verifies BD_BRANCH is dropped for a rig on a
+// branch of the shared database, and kept otherwise.
+func TestWithRevisionDatabase(t *testing.T) {
+	beadsDir := t.TempDir()
+	environ := []string{"PATH=/bin", "BD_BRANCH=polecat-toast-1"}
+
+	if env := withRevisionDatabase(environ, beadsDir); len(env) != 2 {
+		t.Errorf("without metadata: %v", env)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_database": "gastown"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if env := withRevisionDatabase(environ, beadsDir); len(env) != 2 {
+		t.Errorf("own database: %v", env)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_database": "town/gastown"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if env := withRevisionDatabase(environ, beadsDir); len(env) != 1 || env[0] != "PATH=/bin" {
+		t.Errorf("BD_BRANCH kept for a branched rig: %v", env)
+	}
+}
@@ -0,0 +1,316 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BenchLabel marks synthetic beads created by the benchmark harness.
+const BenchLabel = "gt:bench"
+
+// Benchmark operation names, in report order.
+const (
+	BenchOpCreate       = "create"
+	BenchOpList         = "list"
+	BenchOpListFiltered = "list-filtered"
+	BenchOpSearch       = "search"
+	BenchOpExport       = "export"
+)
+
+var benchOpOrder = []string{BenchOpCreate, BenchOpList, BenchOpListFiltered, BenchOpSearch, BenchOpExport}
+
+// BenchOptions configures a benchmark run.
+type BenchOptions struct {
+	Count   int  // Number of synthetic beads to create
+	Samples int  // Repetitions of each read operation
+	Cleanup bool // Hard-delete synthetic beads when done
+}
+
+// BenchTarget names a beads workspace to benchmark.
+type BenchTarget struct {
+	Name     string // Label in the report (e.g., "sqlite", "dolt-server")
+	WorkDir  string // Directory the bd commands run from
+	Isolated bool   // Suppress inherited beads env vars (scratch workspaces)
+}
+
+// BenchOpStats summarizes latencies for one operation.
+type BenchOpStats struct {
+	Op     string        `json:"op"`
+	N      int           `json:"n"`
+	Errors int           `json:"errors,omitempty"`
+	Min    time.Duration `json:"min"`
+	Mean   time.Duration `json:"mean"`
+	P50    time.Duration `json:"p50"`
+	P95    time.Duration `json:"p95"`
+	Max    time.Duration `json:"max"`
+}
+
+// BenchResult is the benchmark outcome for one target.
+type BenchResult struct {
+	Target  string         `json:"target"`
+	Backend string         `json:"backend"`
+	Beads   int            `json:"beads"`
+	Ops     []BenchOpStats `json:"ops"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Op returns the stats for the named operation, or nil.
+func (r *BenchResult) Op(name string) *BenchOpStats {
+	for i := range r.Ops {
+		if r.Ops[i].Op == name {
+			return &r.Ops[i]
+		}
+	}
+	return nil
+}
+
+// RunBench creates opts.Count synthetic beads in the target workspace and
+// measures create, list, filtered list, search, and export latencies.
+// Failures of individual operations are counted, not fatal; a failure to
+// create any beads at all is reported in BenchResult.Error.
+func RunBench(target BenchTarget, opts BenchOptions) *BenchResult {
+	if opts.Count <= 0 {
+		opts.Count = 100
+	}
+	if opts.Samples <= 0 {
+		opts.Samples = 5
+	}
+
+	b := New(target.WorkDir)
+	if target.Isolated {
+		b = NewIsolated(target.WorkDir)
+	}
+	result := &BenchResult{
+		Target:  target.Name,
+		Backend: DetectBackend(b.getResolvedBeadsDir()),
+	}
+
+	runID := time.Now().UTC().Format("20060102-150405")
+	var created []string
+	var createTimes []time.Duration
+	createErrs := 0
+	for i := 0; i < opts.Count; i++ {
+		start := time.Now()
+		issue, err := b.Create(CreateOptions{
+			Title:       fmt.Sprintf("bench %s #%d", runID, i),
+			Type:        "bench",
+			Priority:    i % 5,
+			Description: syntheticDescription(i),
+		})
+		createTimes = append(createTimes, time.Since(start))
+		if err != nil {
+			createErrs++
+			continue
+		}
+		created = append(created, issue.ID)
+	}
+	result.Beads = len(created)
+	result.Ops = append(result.Ops, SummarizeLatencies(BenchOpCreate, createTimes, createErrs))
+	if len(created) == 0 {
+		result.Error = "no synthetic beads could be created"
+		return result
+	}
+
+	reads := map[string]func() error{
+		BenchOpList: func() error {
+			_, err := b.List(ListOptions{Status: "all"})
+			return err
+		},
+		BenchOpListFiltered: func() error {
+			_, err := b.List(ListOptions{Status: "open", Label: BenchLabel, Priorities: []int{2}})
+			return err
+		},
+		BenchOpSearch: func() error {
+			_, err := b.run("search", "bench "+runID, "--json")
+			return err
+		},
+		BenchOpExport: func() error {
+			_, err := b.run("export")
+			return err
+		},
+	}
+	for _, op := range benchOpOrder[1:] {
+		var times []time.Duration
+		errs := 0
+		for s := 0; s < opts.Samples; s++ {
+			start := time.Now()
+			if err := reads[op](); err != nil {
+				errs++
+			}
+			times = append(times, time.Since(start))
+		}
+		result.Ops = append(result.Ops, SummarizeLatencies(op, times, errs))
+	}
+
+	if opts.Cleanup {
+		// Batch deletes to keep argument lists bounded.
+		const batch = 50
+		for i := 0; i < len(created); i += batch {
+			end := i + batch
+			if end > len(created) {
+				end = len(created)
+			}
+			args := append([]string{"delete"}, created[i:end]...)
+			args = append(args, "--hard", "--force")
+			_, _ = b.run(args...)
+		}
+	}
+
+	return result
+}
+
+// syntheticDescription produces a description with enough body text for
+// search and export to do representative work.
+func syntheticDescription(i int) string {
+	words := []string{"refinery", "convoy", "polecat", "witness", "molecule", "wisp", "hook", "merge"}
+	var sb strings.Builder
+	for j := 0; j < 20; j++ {
+		sb.WriteString(words[(i+j)%len(words)])
+		sb.WriteByte(' ')
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// SummarizeLatencies computes min/mean/p50/p95/max for a set of samples.
+func SummarizeLatencies(op string, samples []time.Duration, errors int) BenchOpStats {
+	stats := BenchOpStats{Op: op, N: len(samples), Errors: errors}
+	if len(samples) == 0 {
+		return stats
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, s := range sorted {
+		total += s
+	}
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = total / time.Duration(len(sorted))
+	stats.P50 = percentile(sorted, 50)
+	stats.P95 = percentile(sorted, 95)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// DetectBackend describes the storage backend of a beads directory from its
+// metadata.json (e.g., "sqlite", "dolt", "dolt-server").
+func DetectBackend(beadsDir string) string {
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return "unknown"
+	}
+	var meta struct {
+		Backend  string `json:"backend"`
+		Database string `json:"database"`
+		DoltMode string `json:"dolt_mode"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return "unknown"
+	}
+	backend := meta.Backend
+	if backend == "" {
+		backend = meta.Database
+	}
+	if backend == "" {
+		backend = "sqlite"
+	}
+	if backend == "dolt" && meta.DoltMode == "server" {
+		return "dolt-server"
+	}
+	return backend
+}
+
+// BenchRegression describes an operation that slowed down versus a baseline.
+type BenchRegression struct {
+	Target   string
+	Op       string
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+// Ratio returns current/baseline p50.
+func (r BenchRegression) Ratio() float64 {
+	if r.Baseline == 0 {
+		return 0
+	}
+	return float64(r.Current) / float64(r.Baseline)
+}
+
+// CompareBench reports operations whose p50 latency grew by more than
+// threshold (e.g., 1.5 = 50% slower) relative to a baseline run.
+// Targets and operations missing from either side are ignored.
+func CompareBench(baseline, current []*BenchResult, threshold float64) []BenchRegression {
+	base := make(map[string]*BenchResult, len(baseline))
+	for _, r := range baseline {
+		base[r.Target] = r
+	}
+	var regressions []BenchRegression
+	for _, cur := range current {
+		prev, ok := base[cur.Target]
+		if !ok {
+			continue
+		}
+		for _, op := range cur.Ops {
+			prevOp := prev.Op(op.Op)
+			if prevOp == nil || prevOp.P50 == 0 {
+				continue
+			}
+			if float64(op.P50) > float64(prevOp.P50)*threshold {
+				regressions = append(regressions, BenchRegression{
+					Target:   cur.Target,
+					Op:       op.Op,
+					Baseline: prevOp.P50,
+					Current:  op.P50,
+				})
+			}
+		}
+	}
+	return regressions
+}
+
+// FormatBenchComparison renders a side-by-side p50/p95 table with one row
+// per operation and one column pair per target.
+func FormatBenchComparison(results []*BenchResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-14s", "OPERATION")
+	for _, r := range results {
+		fmt.Fprintf(&sb, "  %-24s", fmt.Sprintf("%s (%s)", r.Target, r.Backend))
+	}
+	sb.WriteString("\n")
+	for _, op := range benchOpOrder {
+		fmt.Fprintf(&sb, "%-14s", op)
+		for _, r := range results {
+			cell := "-"
+			if s := r.Op(op); s != nil && s.N > 0 {
+				cell = fmt.Sprintf("p50 %s / p95 %s", roundLatency(s.P50), roundLatency(s.P95))
+				if s.Errors > 0 {
+					cell += fmt.Sprintf(" (%d err)", s.Errors)
+				}
+			}
+			fmt.Fprintf(&sb, "  %-24s", cell)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func roundLatency(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(100 * time.Microsecond)
+}
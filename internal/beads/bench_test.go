@@ -0,0 +1,112 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarizeLatencies(t *testing.T) {
+	var samples []time.Duration
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	// Shuffle order to ensure sorting happens.
+	samples[0], samples[19] = samples[19], samples[0]
+
+	s := SummarizeLatencies("list", samples, 2)
+	if s.N != 20 || s.Errors != 2 {
+		t.Errorf("N/Errors = %d/%d, want 20/2", s.N, s.Errors)
+	}
+	if s.Min != time.Millisecond || s.Max != 20*time.Millisecond {
+		t.Errorf("Min/Max = %v/%v", s.Min, s.Max)
+	}
+	if s.P50 != 10*time.Millisecond {
+		t.Errorf("P50 = %v, want 10ms", s.P50)
+	}
+	if s.P95 != 19*time.Millisecond {
+		t.Errorf("P95 = %v, want 19ms", s.P95)
+	}
+	if s.Mean != 10500*time.Microsecond {
+		t.Errorf("Mean = %v, want 10.5ms", s.Mean)
+	}
+}
+
+func TestSummarizeLatencies_Empty(t *testing.T) {
+	s := SummarizeLatencies("export", nil, 0)
+	if s.N != 0 || s.P95 != 0 {
+		t.Errorf("expected zero stats, got %+v", s)
+	}
+}
+
+func TestDetectBackend(t *testing.T) {
+	tests := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{"missing", "", "unknown"},
+		{"default sqlite", `{}`, "sqlite"},
+		{"dolt embedded", `{"backend":"dolt"}`, "dolt"},
+		{"dolt server", `{"backend":"dolt","dolt_mode":"server"}`, "dolt-server"},
+		{"legacy database field", `{"database":"dolt"}`, "dolt"},
+		{"garbage", `not json`, "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.meta != "" {
+				if err := os.WriteFile(filepath.Join(dir, "metadata.json"), []byte(tt.meta), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := DetectBackend(dir); got != tt.want {
+				t.Errorf("DetectBackend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareBench(t *testing.T) {
+	baseline := []*BenchResult{{
+		Target: "dolt",
+		Ops: []BenchOpStats{
+			{Op: BenchOpCreate, P50: 10 * time.Millisecond},
+			{Op: BenchOpList, P50: 20 * time.Millisecond},
+		},
+	}}
+	current := []*BenchResult{
+		{
+			Target: "dolt",
+			Ops: []BenchOpStats{
+				{Op: BenchOpCreate, P50: 30 * time.Millisecond},
+				{Op: BenchOpList, P50: 22 * time.Millisecond},
+				{Op: BenchOpSearch, P50: 99 * time.Millisecond},
+			},
+		},
+		{Target: "sqlite", Ops: []BenchOpStats{{Op: BenchOpCreate, P50: time.Second}}},
+	}
+
+	regs := CompareBench(baseline, current, 1.5)
+	if len(regs) != 1 {
+		t.Fatalf("expected 1 regression, got %+v", regs)
+	}
+	if regs[0].Op != BenchOpCreate || regs[0].Ratio() != 3 {
+		t.Errorf("unexpected regression %+v (ratio %.2f)", regs[0], regs[0].Ratio())
+	}
+}
+
+func TestFormatBenchComparison(t *testing.T) {
+	results := []*BenchResult{
+		{Target: "a", Backend: "sqlite", Ops: []BenchOpStats{{Op: BenchOpCreate, N: 1, P50: time.Millisecond, P95: time.Millisecond}}},
+		{Target: "b", Backend: "dolt-server", Ops: []BenchOpStats{{Op: BenchOpCreate, N: 1, Errors: 1, P50: 2 * time.Second}}},
+	}
+	out := FormatBenchComparison(results)
+	for _, want := range []string{"a (sqlite)", "b (dolt-server)", "p50 1ms", "p50 2s", "(1 err)", BenchOpExport} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bulk operations supported by ApplyBulk.
+const (
+	BulkClose        = "close"
+	BulkReprioritize = "reprioritize"
+	BulkRetag        = "retag"
+	BulkReassign     = "reassign"
+)
+
+// BulkFilter selects the issues a bulk operation applies to.
+// Empty fields do not filter.
+type BulkFilter struct {
+	Type      string        // issue_type or gt:<type> label
+	Status    string        // "open", "closed", "all", ... (passed to bd list)
+	Label     string        // Required label
+	Assignee  string        // Current assignee
+	Convoy    string        // Convoy tracking the issue
+	OlderThan time.Duration // Not updated within this window
+}
+
+// Matches reports whether issue passes the client-side parts of the filter.
+// Status, Label, Assignee and Convoy are applied by List in BulkSelect.
+func (f BulkFilter) Matches(issue *Issue, now time.Time) bool {
+	if f.Type != "" && issue.Type != f.Type && !HasLabel(issue, "gt:"+f.Type) {
+		return false
+	}
+	if f.OlderThan > 0 {
+		updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+		if err != nil || now.Sub(updated) < f.OlderThan {
+			return false
+		}
+	}
+	return true
+}
+
+// BulkAction describes the change applied to each selected issue.
+type BulkAction struct {
+	Op           string
+	Reason       string   // close: close reason
+	Priority     int      // reprioritize: new priority (0-4)
+	AddLabels    []string // retag: labels to add
+	RemoveLabels []string // retag: labels to remove
+	Assignee     string   // reassign: new assignee ("" unassigns)
+}
+
+// Validate checks the action is complete.
+func (a BulkAction) Validate() error {
+	switch a.Op {
+	case BulkClose, BulkReassign:
+		return nil
+	case BulkReprioritize:
+		if a.Priority < 0 || a.Priority > 4 {
+			return fmt.Errorf("priority must be 0-4, got %d", a.Priority)
+		}
+		return nil
+	case BulkRetag:
+		if len(a.AddLabels) == 0 && len(a.RemoveLabels) == 0 {
+			return errors.New("retag needs labels to add or remove")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown bulk operation %q", a.Op)
+	}
+}
+
+// Changes reports whether the action would modify issue, and describes the
+// change for dry-run output (e.g., "P3 → P1").
+func (a BulkAction) Changes(issue *Issue) (bool, string) {
+	switch a.Op {
+	case BulkClose:
+		return issue.Status != "closed", issue.Status + " → closed"
+	case BulkReprioritize:
+		return issue.Priority != a.Priority, fmt.Sprintf("P%d → P%d", issue.Priority, a.Priority)
+	case BulkRetag:
+		add, remove := a.labelDelta(issue)
+		var parts []string
+		for _, l := range add {
+			parts = append(parts, "+"+l)
+		}
+		for _, l := range remove {
+			parts = append(parts, "-"+l)
+		}
+		return len(parts) > 0, strings.Join(parts, " ")
+	case BulkReassign:
+		from, to := issue.Assignee, a.Assignee
+		if from == "" {
+			from = "(none)"
+		}
+		if to == "" {
+			to = "(none)"
+		}
+		return issue.Assignee != a.Assignee, from + " → " + to
+	}
+	return false, ""
+}
+
+// labelDelta returns the labels retag would actually add and remove on issue.
+func (a BulkAction) labelDelta(issue *Issue) (add, remove []string) {
+	for _, l := range a.AddLabels {
+		if !HasLabel(issue, l) {
+			add = append(add, l)
+		}
+	}
+	for _, l := range a.RemoveLabels {
+		if HasLabel(issue, l) {
+			remove = append(remove, l)
+		}
+	}
+	return add, remove
+}
+
+// bulkUpdater is the subset of Beads used by ApplyBulk.
+type bulkUpdater interface {
+	Update(id string, opts UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+	Reopen(id, reason string) error
+}
+
+// BulkResult reports what ApplyBulk did.
+type BulkResult struct {
+	Applied    []string // Issues changed and kept
+	RolledBack []string // Issues changed, then restored after a failure
+	Skipped    []string // Issues the action would not change
+}
+
+// BulkSelect lists the issues matching filter. It reads the live database
+// only, so bulk changes are never selected from a stale snapshot.
+func (b *Beads) BulkSelect(filter BulkFilter, now time.Time) ([]*Issue, error) {
+	issues, err := b.List(ListOptions{
+		Status:   filter.Status,
+		Label:    filter.Label,
+		Assignee: filter.Assignee,
+		Convoy:   filter.Convoy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var matched []*Issue
+	for _, issue := range issues {
+		if filter.Matches(issue, now) {
+			matched = append(matched, issue)
+		}
+	}
+	return matched, nil
+}
+
+// ApplyBulk applies action to each issue. It is not transactional: bd
+// writes each issue separately, and other writers see the partial change.
+// If a write fails, ApplyBulk makes a best-effort rollback, restoring every
+// issue it already changed (newest first) from the state it selected. The
+// returned error includes any restore failures, which need manual repair.
+func ApplyBulk(u bulkUpdater, issues []*Issue, action BulkAction) (*BulkResult, error) {
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{}
+	var applied []*Issue
+	for _, issue := range issues {
+		if changes, _ := action.Changes(issue); !changes {
+			result.Skipped = append(result.Skipped, issue.ID)
+			continue
+		}
+		if err := applyBulkOne(u, issue, action); err != nil {
+			failure := fmt.Errorf("%s: %w", issue.ID, err)
+			var rollbackErrs []error
+			for i := len(applied) - 1; i >= 0; i-- {
+				prev := applied[i]
+				if rbErr := revertBulkOne(u, prev, action); rbErr != nil {
+					rollbackErrs = append(rollbackErrs, fmt.Errorf("restoring %s: %w", prev.ID, rbErr))
+					continue
+				}
+				result.RolledBack = append(result.RolledBack, prev.ID)
+			}
+			if len(rollbackErrs) > 0 {
+				return result, fmt.Errorf("%w; rollback incomplete: %w", failure, errors.Join(rollbackErrs...))
+			}
+			return result, fmt.Errorf("%w (rolled back %d change(s))", failure, len(result.RolledBack))
+		}
+		applied = append(applied, issue)
+	}
+
+	for _, issue := range applied {
+		result.Applied = append(result.Applied, issue.ID)
+	}
+	return result, nil
+}
+
+func applyBulkOne(u bulkUpdater, issue *Issue, action BulkAction) error {
+	switch action.Op {
+	case BulkClose:
+		reason := action.Reason
+		if reason == "" {
+			reason = "bulk close"
+		}
+		return u.CloseWithReason(reason, issue.ID)
+	case BulkReprioritize:
+		p := action.Priority
+		return u.Update(issue.ID, UpdateOptions{Priority: &p})
+	case BulkRetag:
+		add, remove := action.labelDelta(issue)
+		return u.Update(issue.ID, UpdateOptions{AddLabels: add, RemoveLabels: remove})
+	case BulkReassign:
+		assignee := action.Assignee
+		return u.Update(issue.ID, UpdateOptions{Assignee: &assignee})
+	}
+	return fmt.Errorf("unknown bulk operation %q", action.Op)
+}
+
+// revertBulkOne restores issue (its pre-change snapshot) after applyBulkOne.
+func revertBulkOne(u bulkUpdater, issue *Issue, action BulkAction) error {
+	switch action.Op {
+	case BulkClose:
+		if err := u.Reopen(issue.ID, "bulk operation rolled back"); err != nil {
+			return err
+		}
+		if issue.Status != "" && issue.Status != "open" {
+			status := issue.Status
+			return u.Update(issue.ID, UpdateOptions{Status: &status})
+		}
+		return nil
+	case BulkReprioritize:
+		p := issue.Priority
+		return u.Update(issue.ID, UpdateOptions{Priority: &p})
+	case BulkRetag:
+		add, remove := action.labelDelta(issue)
+		return u.Update(issue.ID, UpdateOptions{AddLabels: remove, RemoveLabels: add})
+	case BulkReassign:
+		assignee := issue.Assignee
+		return u.Update(issue.ID, UpdateOptions{Assignee: &assignee})
+	}
+	return nil
+}
+
+// Reopen reopens a closed issue with a reason.
+func (b *Beads) Reopen(id, reason string) error {
+	_, err := b.run("reopen", id, "--reason="+reason)
+	return err
+}
@@ -0,0 +1,178 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBulkStore records bulk writes and fails on a chosen issue.
+type fakeBulkStore struct {
+	failOn string
+	calls  []string
+}
+
+func (f *fakeBulkStore) Update(id string, opts UpdateOptions) error {
+	if id == f.failOn {
+		return errors.New("boom")
+	}
+	call := "update " + id
+	if opts.Priority != nil {
+		call += " priority"
+	}
+	if opts.Status != nil {
+		call += " status=" + *opts.Status
+	}
+	if opts.Assignee != nil {
+		call += " assignee=" + *opts.Assignee
+	}
+	for _, l := range opts.AddLabels {
+		call += " +" + l
+	}
+	for _, l := range opts.RemoveLabels {
+		call += " -" + l
+	}
+	f.calls = append(f.calls, call)
+	return nil
+}
+
+func (f *fakeBulkStore) CloseWithReason(reason string, ids ...string) error {
+	if ids[0] == f.failOn {
+		return errors.New("boom")
+	}
+	f.calls = append(f.calls, "close "+ids[0])
+	return nil
+}
+
+func (f *fakeBulkStore) Reopen(id, reason string) error {
+	f.calls = append(f.calls, "reopen "+id)
+	return nil
+}
+
+func TestBulkFilter_Matches(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	old := &Issue{ID: "gt-1", Type: "task", UpdatedAt: "2026-01-01T00:00:00Z"}
+	fresh := &Issue{ID: "gt-2", Labels: []string{"gt:task"}, UpdatedAt: "2026-01-09T12:00:00Z"}
+	bug := &Issue{ID: "gt-3", Type: "bug", UpdatedAt: "2026-01-01T00:00:00Z"}
+
+	f := BulkFilter{Type: "task", OlderThan: 72 * time.Hour}
+	if !f.Matches(old, now) {
+		t.Error("old task should match")
+	}
+	if f.Matches(fresh, now) {
+		t.Error("recently updated task should not match")
+	}
+	if f.Matches(bug, now) {
+		t.Error("bug should not match type=task")
+	}
+	if !(BulkFilter{Type: "task"}).Matches(fresh, now) {
+		t.Error("gt:task label should satisfy type filter")
+	}
+}
+
+func TestApplyBulk_SkipsUnchanged(t *testing.T) {
+	store := &fakeBulkStore{}
+	issues := []*Issue{{ID: "gt-1", Priority: 1}, {ID: "gt-2", Priority: 3}}
+	res, err := ApplyBulk(store, issues, BulkAction{Op: BulkReprioritize, Priority: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Applied) != 1 || res.Applied[0] != "gt-2" || len(res.Skipped) != 1 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestApplyBulk_RollsBackOnFailure(t *testing.T) {
+	store := &fakeBulkStore{failOn: "gt-3"}
+	issues := []*Issue{
+		{ID: "gt-1", Status: "open"},
+		{ID: "gt-2", Status: "in_progress"},
+		{ID: "gt-3", Status: "open"},
+	}
+	res, err := ApplyBulk(store, issues, BulkAction{Op: BulkClose, Reason: "stale"})
+	if err == nil || !strings.Contains(err.Error(), "gt-3") {
+		t.Fatalf("expected failure on gt-3, got %v", err)
+	}
+	if len(res.Applied) != 0 || len(res.RolledBack) != 2 {
+		t.Errorf("result = %+v", res)
+	}
+	want := []string{"close gt-1", "close gt-2", "reopen gt-2", "update gt-2 status=in_progress", "reopen gt-1"}
+	if strings.Join(store.calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v, want %v", store.calls, want)
+	}
+}
+
+func TestApplyBulk_RetagRollbackInvertsDelta(t *testing.T) {
+	store := &fakeBulkStore{failOn: "gt-2"}
+	issues := []*Issue{{ID: "gt-1", Labels: []string{"old"}}, {ID: "gt-2"}}
+	_, err := ApplyBulk(store, issues, BulkAction{Op: BulkRetag, AddLabels: []string{"new"}, RemoveLabels: []string{"old"}})
+	if err == nil {
+		t.Fatal("expected failure")
+	}
+	want := []string{"update gt-1 +new -old", "update gt-1 +old -new"}
+	if strings.Join(store.calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v, want %v", store.calls, want)
+	}
+}
+
+func TestBulkAction_Validate(t *testing.T) {
+	if err := (BulkAction{Op: "explode"}).Validate(); err == nil {
+		t.Error("unknown op should fail")
+	}
+	if err := (BulkAction{Op: BulkRetag}).Validate(); err == nil {
+		t.Error("retag without labels should fail")
+	}
+	if err := (BulkAction{Op: BulkReprioritize, Priority: 7}).Validate(); err == nil {
+		t.Error("priority out of range should fail")
+	}
+}
+
+func TestBulkSelect_NoSnapshotFallback(t *testing.T) {
+	// bd fails as it does when the Dolt server is down.
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte("#!/bin/sh\necho 'connection refused' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	writeSnapshotFile(t, filepath.Join(beadsDir, "issues.jsonl"), `{"id":"gt-1","status":"open"}`+"\n")
+	b := NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+
+	issues, err := b.BulkSelect(BulkFilter{Status: "open"}, time.Now())
+	if err == nil || issues != nil {
+		t.Fatalf("BulkSelect = %v, %v; want the bd error, not the snapshot", issues, err)
+	}
+}
+
+func TestBulkSelect_ConvoyFilter(t *testing.T) {
+	// The convoy tracks gt-2 across rigs, so bd wraps its ID.
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+*"dep list hq-cv-1"*) echo '[{"id":"external:gt:gt-2"}]' ;;
+*list*) echo '[{"id":"gt-1","status":"open"},{"id":"gt-2","status":"open"}]' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	b := NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+
+	issues, err := b.BulkSelect(BulkFilter{Status: "open", Convoy: "hq-cv-1"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].ID != "gt-2" {
+		t.Errorf("BulkSelect = %v, want only gt-2", issues)
+	}
+}
@@ -0,0 +1,72 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Comment is one message in a bead's discussion, from a human or an agent.
+// Bodies are Markdown. Comments are stored in bd and recorded in the
+// activity log (see history.go), which is where they are read from.
+type Comment struct {
+	Author    string `json:"author,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Body      string `json:"body"`
+}
+
+// Comment adds a Markdown comment by author to the discussion of issue id.
+// An empty author falls back to BD_ACTOR.
+func (b *Beads) Comment(id, author, body string) error {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return fmt.Errorf("comment is empty")
+	}
+	if author == "" {
+		author = b.getActor()
+	}
+	args := []string{"comment"}
+	if author != "" {
+		args = append(args, "--actor="+author)
+	}
+	// "--" keeps a body starting with "-" from being read as a flag
+	if _, err := b.run(append(args, id, "--", body)...); err != nil {
+		return err
+	}
+	b.recordHistory(HistoryEntry{IssueID: id, Kind: HistoryComment, Value: body, Actor: author})
+	return nil
+}
+
+// commentFromHistory converts a comment entry of the activity log.
+func commentFromHistory(e HistoryEntry) Comment {
+	return Comment{Author: e.Actor, Timestamp: e.Timestamp, Body: e.Value}
+}
+
+// ReadComments returns the discussion of issueID from the activity log of
+// beadsDir, oldest first.
+func ReadComments(beadsDir, issueID string) ([]Comment, error) {
+	var comments []Comment
+	err := scanHistory(beadsDir, func(e HistoryEntry) {
+		if e.IssueID == issueID && e.Kind == HistoryComment {
+			comments = append(comments, commentFromHistory(e))
+		}
+	})
+	return comments, err
+}
+
+// ReadAllComments returns the discussions of every bead in the activity
+// log of beadsDir, by bead ID, each oldest first.
+func ReadAllComments(beadsDir string) (map[string][]Comment, error) {
+	comments := make(map[string][]Comment)
+	err := scanHistory(beadsDir, func(e HistoryEntry) {
+		if e.Kind == HistoryComment {
+			comments[e.IssueID] = append(comments[e.IssueID], commentFromHistory(e))
+		}
+	})
+	return comments, err
+}
+
+// AllComments returns the discussions in the activity log of b's database,
+// by bead ID.
+func (b *Beads) AllComments() (map[string][]Comment, error) {
+	return ReadAllComments(b.getResolvedBeadsDir())
+}
@@ -0,0 +1,40 @@
+package beads
+
+import "testing"
+
+func TestReadComments(t *testing.T) {
+	dir := t.TempDir()
+	b := &Beads{beadsDir: dir}
+	if err := b.RecordHistory(
+		HistoryEntry{IssueID: "gt-a", Kind: HistoryComment, Value: "First", Actor: "overseer", Timestamp: "2026-01-02T00:00:00Z"},
+		HistoryEntry{IssueID: "gt-a", Kind: HistoryStatus, Value: "closed"},
+		HistoryEntry{IssueID: "gt-b", Kind: HistoryComment, Value: "Other bead", Actor: "gastown/crew/max"},
+		HistoryEntry{IssueID: "gt-a", Kind: HistoryComment, Value: "**Second**", Actor: "gastown/polecats/nux", Timestamp: "2026-01-03T00:00:00Z"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	comments, err := ReadComments(dir, "gt-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Comment{
+		{Author: "overseer", Timestamp: "2026-01-02T00:00:00Z", Body: "First"},
+		{Author: "gastown/polecats/nux", Timestamp: "2026-01-03T00:00:00Z", Body: "**Second**"},
+	}
+	if len(comments) != len(want) || comments[0] != want[0] || comments[1] != want[1] {
+		t.Errorf("ReadComments = %+v, want %+v", comments, want)
+	}
+
+	all, err := b.AllComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || len(all["gt-a"]) != 2 || len(all["gt-b"]) != 1 {
+		t.Errorf("AllComments = %+v", all)
+	}
+
+	if comments, err := ReadComments(t.TempDir(), "gt-a"); err != nil || comments != nil {
+		t.Errorf("missing log: %v, %v", comments, err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package beads provides the per-issue cost ledger.
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// CostFile is the append-only cost ledger in a beads directory. Agent
+// sessions report their usage against the bead they are working on (gt costs
+// record, from the Stop hook). A report carries the session's totals so far,
+// not an increment, so repeated reports from one session are safe; see
+// CostDeltas.
+const CostFile = "costs.jsonl"
+
+// CostUsage is the estimated cost of agent work.
+type CostUsage struct {
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens,omitempty"`
+	CacheCreationTokens int     `json:"cache_creation_tokens,omitempty"`
+	CostUSD             float64 `json:"cost_usd"`
+	ComputeMinutes      float64 `json:"compute_minutes"`
+}
+
+// Tokens returns the total token count.
+func (u CostUsage) Tokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheCreationTokens
+}
+
+// Add adds o to u.
+func (u *CostUsage) Add(o CostUsage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheReadTokens += o.CacheReadTokens
+	u.CacheCreationTokens += o.CacheCreationTokens
+	u.CostUSD += o.CostUSD
+	u.ComputeMinutes += o.ComputeMinutes
+}
+
+// since returns u minus an earlier report prev of the same session. A
+// session whose totals went down was restarted, so all of u is new.
+func (u CostUsage) since(prev CostUsage) CostUsage {
+	d := CostUsage{
+		InputTokens:         u.InputTokens - prev.InputTokens,
+		OutputTokens:        u.OutputTokens - prev.OutputTokens,
+		CacheReadTokens:     u.CacheReadTokens - prev.CacheReadTokens,
+		CacheCreationTokens: u.CacheCreationTokens - prev.CacheCreationTokens,
+		CostUSD:             u.CostUSD - prev.CostUSD,
+		ComputeMinutes:      u.ComputeMinutes - prev.ComputeMinutes,
+	}
+	if d.InputTokens < 0 || d.OutputTokens < 0 || d.CacheReadTokens < 0 ||
+		d.CacheCreationTokens < 0 || d.CostUSD < 0 || d.ComputeMinutes < 0 {
+		return u
+	}
+	return d
+}
+
+// CostReport is one session's usage report against an issue.
+type CostReport struct {
+	Timestamp string `json:"timestamp"`
+	IssueID   string `json:"issue_id"`
+	Session   string `json:"session"` // transcript session; reports of one session are cumulative
+	Rig       string `json:"rig,omitempty"`
+	Worker    string `json:"worker,omitempty"` // polecat or crew name, else the role
+	Model     string `json:"model,omitempty"`
+	CostUsage
+}
+
+// CostPath returns the cost ledger of a beads directory.
+func CostPath(beadsDir string) string {
+	return filepath.Join(beadsDir, CostFile)
+}
+
+// RecordCost appends a report to the cost ledger of beadsDir, filling in
+// the timestamp.
+func RecordCost(beadsDir string, r CostReport) error {
+	if r.IssueID == "" || r.Session == "" {
+		return fmt.Errorf("cost report needs an issue and a session")
+	}
+	if r.Timestamp == "" {
+		r.Timestamp = currentTimestamp()
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshaling cost report: %w", err)
+	}
+	f, err := os.OpenFile(CostPath(beadsDir), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening cost ledger: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing cost ledger: %w", err)
+	}
+	return nil
+}
+
+// ReadCostReports returns the reports in the cost ledger of beadsDir, in
+// the order they were written. A missing ledger has no reports.
+func ReadCostReports(beadsDir string) ([]CostReport, error) {
+	f, err := os.Open(CostPath(beadsDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening cost ledger: %w", err)
+	}
+	defer f.Close()
+
+	var reports []CostReport
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r CostReport
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.IssueID == "" {
+			continue // skip a torn or foreign line rather than hide the rest
+		}
+		reports = append(reports, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading cost ledger: %w", err)
+	}
+	return reports, nil
+}
+
+// CostDeltas turns cumulative reports into increments: each returned report
+// carries only the usage since its session's previous report, which may have
+// been against another issue. Reports are taken in timestamp order.
+func CostDeltas(reports []CostReport) []CostReport {
+	sorted := make([]CostReport, len(reports))
+	copy(sorted, reports)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	last := make(map[string]CostUsage)
+	for i := range sorted {
+		cur := sorted[i].CostUsage
+		if prev, ok := last[sorted[i].Session]; ok {
+			sorted[i].CostUsage = cur.since(prev)
+		}
+		last[sorted[i].Session] = cur
+	}
+	return sorted
+}
+
+// SumCosts totals increments (see CostDeltas) by key. Reports with an empty
+// key are left out.
+func SumCosts(deltas []CostReport, key func(CostReport) string) map[string]*CostUsage {
+	totals := make(map[string]*CostUsage)
+	for _, r := range deltas {
+		k := key(r)
+		if k == "" {
+			continue
+		}
+		if totals[k] == nil {
+			totals[k] = &CostUsage{}
+		}
+		totals[k].Add(r.CostUsage)
+	}
+	return totals
+}
+
+// IssueSessions returns the sessions that reported work on issueID, in the
+// order they first reported.
+func IssueSessions(reports []CostReport, issueID string) []string {
+	sorted := make([]CostReport, len(reports))
+	copy(sorted, reports)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	var sessions []string
+	seen := make(map[string]bool)
+	for _, r := range sorted {
+		if r.IssueID != issueID || r.Session == "" || seen[r.Session] {
+			continue
+		}
+		seen[r.Session] = true
+		sessions = append(sessions, r.Session)
+	}
+	return sessions
+}
@@ -0,0 +1,79 @@
+package beads
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRecordAndReadCostReports(t *testing.T) {
+	dir := t.TempDir()
+	reports, err := ReadCostReports(dir)
+	if err != nil || reports != nil {
+		t.Fatalf("missing ledger = %v, %v; want nil, nil", reports, err)
+	}
+
+	if err := RecordCost(dir, CostReport{IssueID: "gt-1", Session: "s1", CostUsage: CostUsage{InputTokens: 10, CostUSD: 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordCost(dir, CostReport{IssueID: "gt-1"}); err == nil {
+		t.Error("report without a session was recorded")
+	}
+	reports, err = ReadCostReports(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Timestamp == "" || reports[0].InputTokens != 10 {
+		t.Errorf("reports = %+v", reports)
+	}
+}
+
+func TestIssueSessions(t *testing.T) {
+	reports := []CostReport{
+		{Timestamp: "2026-03-02T10:10:00Z", IssueID: "gt-a", Session: "s2"},
+		{Timestamp: "2026-03-02T10:00:00Z", IssueID: "gt-a", Session: "s1"},
+		{Timestamp: "2026-03-02T10:20:00Z", IssueID: "gt-a", Session: "s1"},
+		{Timestamp: "2026-03-02T10:05:00Z", IssueID: "gt-b", Session: "s3"},
+	}
+	got := IssueSessions(reports, "gt-a")
+	if len(got) != 2 || got[0] != "s1" || got[1] != "s2" {
+		t.Errorf("IssueSessions = %v, want [s1 s2]", got)
+	}
+	if got := IssueSessions(reports, "gt-c"); got != nil {
+		t.Errorf("IssueSessions for an unknown issue = %v", got)
+	}
+}
+
+func TestCostDeltas(t *testing.T) {
+	reports := []CostReport{
+		{Timestamp: "2026-03-02T10:00:00Z", IssueID: "gt-a", Session: "s1", Worker: "toast", CostUsage: CostUsage{OutputTokens: 100, CostUSD: 1, ComputeMinutes: 10}},
+		{Timestamp: "2026-03-02T10:05:00Z", IssueID: "gt-a", Session: "s1", Worker: "toast", CostUsage: CostUsage{OutputTokens: 150, CostUSD: 1.5, ComputeMinutes: 15}},
+		// Same session moves on to another bead: only the increment counts.
+		{Timestamp: "2026-03-02T10:20:00Z", IssueID: "gt-b", Session: "s1", Worker: "toast", CostUsage: CostUsage{OutputTokens: 400, CostUSD: 4, ComputeMinutes: 30}},
+		// Another session on the first bead.
+		{Timestamp: "2026-03-02T10:10:00Z", IssueID: "gt-a", Session: "s2", Worker: "nux", CostUsage: CostUsage{OutputTokens: 50, CostUSD: 0.25, ComputeMinutes: 5}},
+		// s2 restarted: its totals went down, so all of it is new.
+		{Timestamp: "2026-03-02T11:00:00Z", IssueID: "gt-a", Session: "s2", Worker: "nux", CostUsage: CostUsage{OutputTokens: 20, CostUSD: 0.1, ComputeMinutes: 2}},
+	}
+
+	byBead := SumCosts(CostDeltas(reports), func(r CostReport) string { return r.IssueID })
+	if got := byBead["gt-a"]; got.OutputTokens != 150+50+20 || !near(got.CostUSD, 1.85) || !near(got.ComputeMinutes, 22) {
+		t.Errorf("gt-a = %+v", got)
+	}
+	if got := byBead["gt-b"]; got.OutputTokens != 250 || !near(got.CostUSD, 2.5) || !near(got.ComputeMinutes, 15) {
+		t.Errorf("gt-b = %+v", got)
+	}
+
+	byWorker := SumCosts(CostDeltas(reports), func(r CostReport) string { return r.Worker })
+	if !near(byWorker["toast"].CostUSD, 4) || !near(byWorker["nux"].CostUSD, 0.35) {
+		t.Errorf("by worker: toast=%+v nux=%+v", byWorker["toast"], byWorker["nux"])
+	}
+
+	// The input is left alone.
+	if reports[1].OutputTokens != 150 {
+		t.Errorf("CostDeltas modified its input: %+v", reports[1])
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
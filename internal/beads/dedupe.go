@@ -0,0 +1,347 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultDuplicateScore is the similarity at which two beads are reported
+// as likely duplicates.
+const DefaultDuplicateScore = 0.6
+
+// DuplicatePair is two beads that likely describe the same work. Keep is
+// the suggested survivor: an open bead over a closed one, then the older.
+type DuplicatePair struct {
+	Keep      string  `json:"keep"`
+	Duplicate string  `json:"duplicate"`
+	KeepTitle string  `json:"keep_title"`
+	DupTitle  string  `json:"duplicate_title"`
+	Score     float64 `json:"score"`
+}
+
+// dedupeSkipLabels mark infrastructure beads (agents, MRs, mail, ...)
+// whose titles are formulaic, and sealed beads whose content is opaque.
+var dedupeSkipLabels = map[string]bool{
+	"gt:agent": true, "gt:merge-request": true, "gt:message": true, "gt:molecule": true,
+	"gt:rig": true, "gt:role": true, "gt:group": true, "gt:channel": true, "gt:queue": true,
+	"gt:escalation": true, "gt:bench": true, SensitiveLabel: true,
+}
+
+var dedupeSkipTypes = map[string]bool{
+	"agent": true, "merge-request": true, "message": true, "molecule": true,
+	"convoy": true, "event": true, "gate": true, "role": true, "rig": true,
+}
+
+// dedupeCandidate reports whether rec is a work bead worth comparing.
+func dedupeCandidate(rec FsckRecord, includeClosed bool) bool {
+	if dedupeSkipTypes[rec.Type] || strings.TrimSpace(rec.Title) == "" {
+		return false
+	}
+	if !includeClosed && rec.Status == "closed" {
+		return false
+	}
+	for _, l := range rec.Labels {
+		if dedupeSkipLabels[l] {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupeDoc is a bead's text reduced to sets for comparison.
+type dedupeDoc struct {
+	rec      FsckRecord
+	trigrams map[string]bool // of the title
+	words    map[string]bool // of the description
+}
+
+// minDescriptionWords is how many distinct words both descriptions need
+// before they count towards the score; shorter ones are mostly templates.
+const minDescriptionWords = 5
+
+func newDedupeDoc(rec FsckRecord) dedupeDoc {
+	title := normalizeDedupeText(rec.Title)
+	doc := dedupeDoc{rec: rec, trigrams: map[string]bool{}, words: map[string]bool{}}
+	padded := " " + title + " "
+	runes := []rune(padded)
+	for i := 0; i+3 <= len(runes); i++ {
+		doc.trigrams[string(runes[i:i+3])] = true
+	}
+	for _, w := range strings.Fields(normalizeDedupeText(rec.Description)) {
+		if len(w) >= 3 {
+			doc.words[w] = true
+		}
+	}
+	return doc
+}
+
+// normalizeDedupeText lowercases s and reduces it to words separated by
+// single spaces.
+func normalizeDedupeText(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Similarity scores how alike two beads are, from 0 to 1: the Jaccard
+// similarity of their title trigrams, blended with that of their
+// description words when both have enough of them.
+func Similarity(a, b FsckRecord) float64 {
+	return newDedupeDoc(a).similarity(newDedupeDoc(b))
+}
+
+func (d dedupeDoc) similarity(o dedupeDoc) float64 {
+	title := jaccard(d.trigrams, o.trigrams)
+	if len(d.words) < minDescriptionWords || len(o.words) < minDescriptionWords {
+		return title
+	}
+	return 0.7*title + 0.3*jaccard(d.words, o.words)
+}
+
+// FindDuplicates returns the pairs of work beads in records scoring at
+// least minScore, best first. Closed beads are compared only with
+// includeClosed.
+//
+// Only pairs sharing a title trigram are scored. Trigrams found in most
+// titles (e.g., " fi", "fix") say little, so they do not make pairs on
+// their own.
+func FindDuplicates(records []FsckRecord, minScore float64, includeClosed bool) []DuplicatePair {
+	var docs []dedupeDoc
+	for _, rec := range records {
+		if dedupeCandidate(rec, includeClosed) {
+			docs = append(docs, newDedupeDoc(rec))
+		}
+	}
+
+	postings := make(map[string][]int)
+	for i, d := range docs {
+		for t := range d.trigrams {
+			postings[t] = append(postings[t], i)
+		}
+	}
+	common := len(docs) / 4
+	if common < 50 {
+		common = 50
+	}
+	seen := make(map[[2]int]bool)
+	var pairs []DuplicatePair
+	for _, list := range postings {
+		if len(list) > common {
+			continue
+		}
+		for x := 0; x < len(list); x++ {
+			for y := x + 1; y < len(list); y++ {
+				key := [2]int{list[x], list[y]}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				a, b := docs[list[x]], docs[list[y]]
+				if score := a.similarity(b); score >= minScore {
+					pairs = append(pairs, newDuplicatePair(a.rec, b.rec, score))
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].Keep != pairs[j].Keep {
+			return pairs[i].Keep < pairs[j].Keep
+		}
+		return pairs[i].Duplicate < pairs[j].Duplicate
+	})
+	return pairs
+}
+
+func newDuplicatePair(a, b FsckRecord, score float64) DuplicatePair {
+	if keepFirst(b, a) {
+		a, b = b, a
+	}
+	return DuplicatePair{Keep: a.ID, Duplicate: b.ID, KeepTitle: a.Title, DupTitle: b.Title, Score: score}
+}
+
+// keepFirst reports whether a should survive a merge with b.
+func keepFirst(a, b FsckRecord) bool {
+	if (a.Status == "closed") != (b.Status == "closed") {
+		return b.Status == "closed"
+	}
+	if a.CreatedAt != b.CreatedAt && a.CreatedAt != "" && b.CreatedAt != "" {
+		return a.CreatedAt < b.CreatedAt
+	}
+	return a.ID < b.ID
+}
+
+// FindDuplicates exports the database and runs FindDuplicates on it.
+func (b *Beads) FindDuplicates(minScore float64, includeClosed bool) ([]DuplicatePair, error) {
+	records, err := b.exportRecords()
+	if err != nil {
+		return nil, err
+	}
+	return FindDuplicates(records, minScore, includeClosed), nil
+}
+
+func (b *Beads) exportRecords() ([]FsckRecord, error) {
+	out, err := b.run("export")
+	if err != nil {
+		return nil, fmt.Errorf("exporting beads: %w", err)
+	}
+	return ParseFsckRecords(out)
+}
+
+// MergePlan is what merging a duplicate into the bead kept involves.
+type MergePlan struct {
+	Keep       string   `json:"keep"`
+	Duplicate  string   `json:"duplicate"`
+	Labels     []string `json:"labels,omitempty"`     // Duplicate's labels the kept bead lacks
+	MRs        []string `json:"mrs,omitempty"`        // Merge requests whose source_issue moves to the kept bead
+	Dependents []string `json:"dependents,omitempty"` // Beads whose dependency moves to the kept bead
+}
+
+// PlanMerge works out how to merge dupID into keepID from exported records.
+func PlanMerge(records []FsckRecord, dupID, keepID string) (*MergePlan, error) {
+	if dupID == keepID {
+		return nil, fmt.Errorf("cannot merge %s into itself", dupID)
+	}
+	byID := make(map[string]FsckRecord, len(records))
+	for _, rec := range records {
+		byID[rec.ID] = rec
+	}
+	dup, ok := byID[dupID]
+	if !ok {
+		return nil, fmt.Errorf("bead %s not found", dupID)
+	}
+	keep, ok := byID[keepID]
+	if !ok {
+		return nil, fmt.Errorf("bead %s not found", keepID)
+	}
+
+	plan := &MergePlan{Keep: keepID, Duplicate: dupID}
+	have := make(map[string]bool, len(keep.Labels))
+	for _, l := range keep.Labels {
+		have[l] = true
+	}
+	for _, l := range dup.Labels {
+		if !have[l] {
+			plan.Labels = append(plan.Labels, l)
+		}
+	}
+	for _, rec := range records {
+		if rec.ID == dupID || rec.ID == keepID {
+			continue
+		}
+		issue := &Issue{ID: rec.ID, Description: rec.Description, Labels: rec.Labels}
+		if HasLabel(issue, "gt:merge-request") || rec.Type == "merge-request" {
+			if fields := ParseMRFields(issue); fields != nil && fields.SourceIssue == dupID {
+				plan.MRs = append(plan.MRs, rec.ID)
+			}
+		}
+		for _, dep := range rec.Dependencies {
+			if dep.DependsOnID == dupID {
+				plan.Dependents = append(plan.Dependents, rec.ID)
+				break
+			}
+		}
+	}
+	return plan, nil
+}
+
+// PlanMerge exports the database and works out how to merge dupID into
+// keepID, without changing anything.
+func (b *Beads) PlanMerge(dupID, keepID string) (*MergePlan, error) {
+	records, err := b.exportRecords()
+	if err != nil {
+		return nil, err
+	}
+	return PlanMerge(records, dupID, keepID)
+}
+
+// MergeDuplicate folds dupID into keepID and closes it:
+//   - the duplicate's labels are added to the kept bead
+//   - merge requests for the duplicate are pointed at the kept bead
+//   - beads depending on the duplicate depend on the kept bead instead
+//   - the duplicate's comments and MR links are copied into the kept bead's
+//     activity log, so its history survives
+//   - the duplicate is linked to the kept bead ("duplicates") and closed
+func (b *Beads) MergeDuplicate(dupID, keepID string) (*MergePlan, error) {
+	plan, err := b.PlanMerge(dupID, keepID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.Labels) > 0 {
+		if err := b.Update(keepID, UpdateOptions{AddLabels: plan.Labels}); err != nil {
+			return nil, fmt.Errorf("copying labels: %w", err)
+		}
+	}
+	for _, mrID := range plan.MRs {
+		mr, err := b.Show(mrID)
+		if err != nil {
+			return nil, err
+		}
+		fields := ParseMRFields(mr)
+		fields.SourceIssue = keepID
+		description := SetMRFields(mr, fields)
+		if err := b.Update(mrID, UpdateOptions{Description: &description}); err != nil {
+			return nil, fmt.Errorf("repointing %s: %w", mrID, err)
+		}
+	}
+	for _, id := range plan.Dependents {
+		if err := b.AddDependency(id, keepID); err != nil {
+			return nil, fmt.Errorf("repointing %s: %w", id, err)
+		}
+		if err := b.RemoveDependency(id, dupID); err != nil {
+			return nil, fmt.Errorf("repointing %s: %w", id, err)
+		}
+	}
+
+	history, err := ReadHistory(b.getResolvedBeadsDir(), dupID)
+	if err != nil {
+		return nil, err
+	}
+	var carried []HistoryEntry
+	linked := make(map[string]bool)
+	for _, e := range history {
+		if e.Kind == HistoryComment || e.Kind == HistoryMR {
+			if e.Kind == HistoryMR {
+				linked[e.Value] = true
+			}
+			e.IssueID = keepID
+			e.Detail = "from " + dupID
+			carried = append(carried, e)
+		}
+	}
+	for _, mrID := range plan.MRs {
+		if !linked[mrID] {
+			carried = append(carried, HistoryEntry{IssueID: keepID, Kind: HistoryMR, Value: mrID, Detail: "from " + dupID})
+		}
+	}
+	b.recordHistory(carried...)
+
+	if err := b.AddTypedDependency(dupID, keepID, "duplicates"); err != nil {
+		return nil, fmt.Errorf("linking %s to %s: %w", dupID, keepID, err)
+	}
+	if err := b.AddComment(keepID, fmt.Sprintf("Merged duplicate %s", dupID)); err != nil {
+		return nil, err
+	}
+	if err := b.CloseWithReason("duplicate of "+keepID, dupID); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
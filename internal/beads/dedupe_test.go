@@ -0,0 +1,76 @@
+package beads
+
+import "testing"
+
+func TestFindDuplicates(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-a", Title: "Refinery crashes on empty merge queue", Status: "open", CreatedAt: "2026-01-02T00:00:00Z"},
+		{ID: "gt-b", Title: "refinery crashes on an empty merge queue!", Status: "open", CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "gt-c", Title: "Add dark mode to the dashboard", Status: "open"},
+		{ID: "gt-d", Title: "Refinery crashes on empty merge queue", Status: "closed"},
+		{ID: "gt-e", Title: "Refinery crashes on empty merge queue", Status: "open", Labels: []string{"gt:merge-request"}},
+	}
+
+	pairs := FindDuplicates(records, DefaultDuplicateScore, false)
+	if len(pairs) != 1 {
+		t.Fatalf("pairs = %+v, want one", pairs)
+	}
+	// The older bead is kept.
+	if p := pairs[0]; p.Keep != "gt-b" || p.Duplicate != "gt-a" || p.Score < 0.8 {
+		t.Errorf("pair = %+v", p)
+	}
+
+	// With closed beads, the open ones are kept over the closed one.
+	pairs = FindDuplicates(records, DefaultDuplicateScore, true)
+	if len(pairs) != 3 {
+		t.Fatalf("pairs with closed = %+v, want three", pairs)
+	}
+	for _, p := range pairs {
+		if p.Keep == "gt-d" {
+			t.Errorf("closed bead kept: %+v", p)
+		}
+		if p.Keep == "gt-e" || p.Duplicate == "gt-e" {
+			t.Errorf("merge request compared: %+v", p)
+		}
+	}
+}
+
+func TestSimilarityDescriptions(t *testing.T) {
+	a := FsckRecord{Title: "Fix login", Description: "Users cannot log in after the session cookie expires overnight"}
+	b := FsckRecord{Title: "Fix login", Description: "Nightly billing export writes duplicate invoice rows to storage"}
+	if s := Similarity(a, a); s != 1 {
+		t.Errorf("Similarity(a, a) = %v, want 1", s)
+	}
+	if s := Similarity(a, b); s >= 0.75 {
+		t.Errorf("different descriptions scored %v", s)
+	}
+}
+
+func TestPlanMerge(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-keep", Title: "Crash", Labels: []string{"bug"}},
+		{ID: "gt-dup", Title: "Crash!", Labels: []string{"bug", "refinery"}},
+		{ID: "gt-mr", Type: "merge-request", Labels: []string{"gt:merge-request"}, Description: "branch: polecat/x\nsource_issue: gt-dup"},
+		{ID: "gt-other", Dependencies: []FsckDependency{{IssueID: "gt-other", DependsOnID: "gt-dup"}}},
+	}
+	plan, err := PlanMerge(records, "gt-dup", "gt-keep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Labels) != 1 || plan.Labels[0] != "refinery" {
+		t.Errorf("labels = %v", plan.Labels)
+	}
+	if len(plan.MRs) != 1 || plan.MRs[0] != "gt-mr" {
+		t.Errorf("MRs = %v", plan.MRs)
+	}
+	if len(plan.Dependents) != 1 || plan.Dependents[0] != "gt-other" {
+		t.Errorf("dependents = %v", plan.Dependents)
+	}
+
+	if _, err := PlanMerge(records, "gt-dup", "gt-dup"); err == nil {
+		t.Error("merging a bead into itself succeeded")
+	}
+	if _, err := PlanMerge(records, "gt-nope", "gt-keep"); err == nil {
+		t.Error("merging a missing bead succeeded")
+	}
+}
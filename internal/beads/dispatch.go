@@ -0,0 +1,321 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// filesKey is the description field listing the paths a bead expects to
+// touch, comma-separated CODEOWNERS-style patterns ("files: internal/mq/,
+// docs/*.md"). Dispatch uses it to keep two workers out of the same files.
+const filesKey = "files"
+
+// Dispatch score weights. Priority dominates; beads already assigned to the
+// worker come before anything new.
+const (
+	dispatchAssignedScore = 1000
+	dispatchPriorityScore = 100 // per level above P4
+	dispatchOwnerScore    = 50
+	dispatchMaxAgeScore   = 14 // one point per day waiting, capped
+)
+
+// ParseFiles returns the paths listed in issue's files: field.
+func ParseFiles(issue *Issue) []string {
+	if issue == nil {
+		return nil
+	}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.TrimSpace(key) != filesKey {
+			continue
+		}
+		var files []string
+		for _, f := range strings.Split(value, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				files = append(files, f)
+			}
+		}
+		return files
+	}
+	return nil
+}
+
+// filesOverlap returns the first path of a that overlaps one of b: equal,
+// or one pattern matching the other.
+func filesOverlap(a, b []string) (string, bool) {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y || config.MatchOwnerPattern(x, y) || config.MatchOwnerPattern(y, x) {
+				return x, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DispatchOptions are the inputs to PlanDispatch besides the beads.
+type DispatchOptions struct {
+	Worker string                 // address of the worker asking for work
+	Config *config.DispatchConfig // rig dispatch settings; nil = defaults
+	Owners *config.OwnersConfig   // town ownership map; nil = none
+	Now    time.Time
+}
+
+// DispatchCandidate is a ready bead and how it ranks for the worker.
+type DispatchCandidate struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Priority int      `json:"priority"`
+	Score    int      `json:"score"`
+	Reasons  []string `json:"reasons"`           // what raised or lowered the score
+	Skipped  string   `json:"skipped,omitempty"` // why it can't go to the worker
+}
+
+// DispatchPlan is the ranked work queue for one worker.
+type DispatchPlan struct {
+	Worker   string   `json:"worker"`
+	WIP      []string `json:"wip"` // beads the worker has in progress
+	WIPLimit int      `json:"wip_limit"`
+	// Held explains why nothing is dispatched right now (a WIP limit is
+	// reached); the queue is still ranked.
+	Held    string              `json:"held,omitempty"`
+	Next    *DispatchCandidate  `json:"next,omitempty"`
+	Queue   []DispatchCandidate `json:"queue"`   // eligible, best first
+	Skipped []DispatchCandidate `json:"skipped"` // ready but not for this worker
+}
+
+// PlanDispatch ranks ready beads for a worker. ready should come from bd
+// ready, which already leaves out beads with open blockers; inProgress is
+// the rig's in_progress and hooked beads, used for WIP limits and file
+// collisions.
+//
+// A bead is skipped when it is not work (ephemeral, an epic, or one of Gas
+// Town's own types), assigned or leased to someone else, carries a skip
+// label, touches files another worker's bead in progress touches, or (with
+// strict ownership) is owned by a team the worker is not on. The rest are
+// scored by priority, assignment to the worker, ownership, label weights,
+// and age, and sorted best first.
+func PlanDispatch(ready, inProgress []*Issue, opts DispatchOptions) *DispatchPlan {
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = &config.DispatchConfig{}
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	plan := &DispatchPlan{Worker: opts.Worker, WIP: []string{}, WIPLimit: cfg.WorkerWIPLimit()}
+
+	type busy struct {
+		id, holder string
+		files      []string
+	}
+	var others []busy
+	working := 0
+	for _, issue := range inProgress {
+		if notDispatchable(issue) != "" {
+			continue // molecules, agent beads, ... are not WIP
+		}
+		working++
+		holder := issue.Assignee
+		if l := ParseLease(issue); l != nil {
+			holder = l.Holder
+		}
+		if holder != "" && holder == opts.Worker {
+			plan.WIP = append(plan.WIP, issue.ID)
+			continue
+		}
+		if files := ParseFiles(issue); len(files) > 0 {
+			others = append(others, busy{id: issue.ID, holder: holder, files: files})
+		}
+	}
+	switch {
+	case len(plan.WIP) >= plan.WIPLimit:
+		plan.Held = fmt.Sprintf("%s is at its WIP limit (%d/%d): %s",
+			opts.Worker, len(plan.WIP), plan.WIPLimit, strings.Join(plan.WIP, ", "))
+	case cfg.RigWIPLimit > 0 && working >= cfg.RigWIPLimit:
+		plan.Held = fmt.Sprintf("rig is at its WIP limit (%d/%d beads in progress)", working, cfg.RigWIPLimit)
+	}
+
+	teams := opts.Owners.TeamsOf(opts.Worker)
+	ownedByWorker := func(owner string) bool {
+		return owner == opts.Worker || containsStr(teams, owner)
+	}
+
+	plan.Queue = []DispatchCandidate{}
+	plan.Skipped = []DispatchCandidate{}
+	created := make(map[string]time.Time)
+	for _, issue := range ready {
+		c := DispatchCandidate{ID: issue.ID, Title: issue.Title, Priority: issue.Priority}
+		skip := func(format string, args ...interface{}) {
+			c.Skipped = fmt.Sprintf(format, args...)
+			plan.Skipped = append(plan.Skipped, c)
+		}
+
+		if issue.Status != "open" {
+			skip("status is %s", issue.Status)
+			continue
+		}
+		if reason := notDispatchable(issue); reason != "" {
+			skip("%s", reason)
+			continue
+		}
+		if issue.BlockedByCount > 0 {
+			skip("blocked by %d open bead(s)", issue.BlockedByCount)
+			continue
+		}
+		if issue.Assignee != "" && issue.Assignee != opts.Worker {
+			skip("assigned to %s", issue.Assignee)
+			continue
+		}
+		if err := leaseConflict(issue, opts.Worker, now); err != nil {
+			skip("%v", err)
+			continue
+		}
+		if label := firstLabelIn(issue, cfg.SkipLabels); label != "" {
+			skip("has skip label %s", label)
+			continue
+		}
+		files := ParseFiles(issue)
+		collision := ""
+		for _, o := range others {
+			if f, ok := filesOverlap(files, o.files); ok {
+				collision = fmt.Sprintf("touches %s, like %s in progress by %s", f, o.id, o.holder)
+				break
+			}
+		}
+		if collision != "" {
+			skip("%s", collision)
+			continue
+		}
+
+		owner, ownedBy := "", ""
+		if r, ok := opts.Owners.OwnerForLabels(issue.Labels); ok {
+			owner, ownedBy = r.Owner, "label "+r.Label
+		} else {
+			for _, f := range files {
+				if r, ok := opts.Owners.OwnerForPath(f); ok {
+					owner, ownedBy = r.Owner, f
+					break
+				}
+			}
+		}
+		if owner != "" && !ownedByWorker(owner) && cfg.StrictOwnership {
+			skip("owned by %s (%s)", owner, ownedBy)
+			continue
+		}
+
+		add := func(points int, format string, args ...interface{}) {
+			c.Score += points
+			c.Reasons = append(c.Reasons, fmt.Sprintf("%+d ", points)+fmt.Sprintf(format, args...))
+		}
+		if issue.Assignee == opts.Worker && opts.Worker != "" {
+			add(dispatchAssignedScore, "assigned to you")
+		}
+		if p := issue.Priority; p >= 0 && p < 4 {
+			add((4-p)*dispatchPriorityScore, "P%d", p)
+		}
+		switch {
+		case owner == "":
+		case ownedByWorker(owner):
+			add(dispatchOwnerScore, "owned by %s (%s)", owner, ownedBy)
+		default:
+			add(-dispatchOwnerScore, "owned by %s (%s)", owner, ownedBy)
+		}
+		labels := append([]string(nil), issue.Labels...)
+		sort.Strings(labels)
+		for _, l := range labels {
+			if w := cfg.LabelWeights[l]; w != 0 {
+				add(w, "label %s", l)
+			}
+		}
+		if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+			created[issue.ID] = t
+			if days := int(now.Sub(t).Hours() / 24); days > 0 {
+				if days > dispatchMaxAgeScore {
+					days = dispatchMaxAgeScore
+				}
+				add(days, "waiting %dd", days)
+			}
+		}
+		plan.Queue = append(plan.Queue, c)
+	}
+
+	sort.SliceStable(plan.Queue, func(i, j int) bool {
+		a, b := plan.Queue[i], plan.Queue[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if ta, tb := created[a.ID], created[b.ID]; !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.ID < b.ID
+	})
+	if plan.Held == "" && len(plan.Queue) > 0 {
+		plan.Next = &plan.Queue[0]
+	}
+	return plan
+}
+
+// notDispatchable returns why a bead is not work a worker can pick up, or "".
+func notDispatchable(issue *Issue) string {
+	if issue.Ephemeral {
+		return "ephemeral"
+	}
+	switch typ := issueType(issue); {
+	case typ == "epic":
+		return "epics are not dispatched; their children are"
+	case !assignableTypes[typ]:
+		return typ + " beads are not dispatched"
+	}
+	return ""
+}
+
+// firstLabelIn returns the first of labels the issue carries, or "".
+func firstLabelIn(issue *Issue, labels []string) string {
+	for _, l := range labels {
+		if HasLabel(issue, l) {
+			return l
+		}
+	}
+	return ""
+}
+
+func containsStr(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchReadyLimit bounds the ready beads considered for dispatch.
+const dispatchReadyLimit = 500
+
+// PlanDispatch ranks the rig's ready beads for worker (see PlanDispatch).
+func (b *Beads) PlanDispatch(opts DispatchOptions) (*DispatchPlan, error) {
+	out, err := b.run("ready", "--json", "-n", fmt.Sprint(dispatchReadyLimit))
+	if err != nil {
+		return nil, fmt.Errorf("listing ready beads: %w", err)
+	}
+	var ready []*Issue
+	if err := json.Unmarshal(out, &ready); err != nil {
+		return nil, fmt.Errorf("parsing bd ready output: %w", err)
+	}
+
+	var inProgress []*Issue
+	for _, status := range []string{"in_progress", StatusHooked} {
+		issues, err := b.List(ListOptions{Status: status})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s beads: %w", status, err)
+		}
+		inProgress = append(inProgress, issues...)
+	}
+	return PlanDispatch(ready, inProgress, opts), nil
+}
@@ -0,0 +1,146 @@
+package beads
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseFiles(t *testing.T) {
+	issue := &Issue{Description: "Fix the queue.\n\nfiles: internal/mq/, docs/*.md ,\nrig: gastown"}
+	got := ParseFiles(issue)
+	want := []string{"internal/mq/", "docs/*.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFiles = %v, want %v", got, want)
+	}
+	if got := ParseFiles(&Issue{Description: "no files here"}); got != nil {
+		t.Errorf("ParseFiles without field = %v, want nil", got)
+	}
+}
+
+func dispatchIDs(cs []DispatchCandidate) []string {
+	ids := make([]string, 0, len(cs))
+	for _, c := range cs {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestPlanDispatchRanking(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := func(n int) string { return now.Add(-time.Duration(n) * 24 * time.Hour).Format(time.RFC3339) }
+	const worker = "gastown/polecats/toast"
+	owners := &config.OwnersConfig{
+		Teams: []config.TeamDef{
+			{Name: "mq", Members: []string{worker}},
+			{Name: "web", Members: []string{"gastown/polecats/nux"}},
+		},
+		Rules: []config.OwnerRule{
+			{Label: "frontend", Owner: "web"},
+			{Path: "internal/refinery/", Owner: "mq"},
+		},
+	}
+	ready := []*Issue{
+		{ID: "gt-p2", Status: "open", Priority: 2, CreatedAt: day(1)},
+		{ID: "gt-p1", Status: "open", Priority: 1, CreatedAt: day(0)},
+		{ID: "gt-mine", Status: "open", Priority: 3, Assignee: worker},
+		{ID: "gt-web", Status: "open", Priority: 1, Labels: []string{"frontend"}, CreatedAt: day(0)},
+		{ID: "gt-ours", Status: "open", Priority: 2, Description: "files: internal/refinery/engineer.go", CreatedAt: day(0)},
+		{ID: "gt-old", Status: "open", Priority: 2, CreatedAt: day(30)},
+		{ID: "gt-urgent", Status: "open", Priority: 3, Labels: []string{"urgent"}},
+		{ID: "gt-theirs", Status: "open", Priority: 0, Assignee: "gastown/polecats/nux"},
+		{ID: "gt-epic", Status: "open", Priority: 0, Type: "epic"},
+		{ID: "gt-wisp", Status: "open", Priority: 0, Ephemeral: true},
+		{ID: "gt-human", Status: "open", Priority: 0, Labels: []string{"needs-human"}},
+		{ID: "gt-clash", Status: "open", Priority: 0, Description: "files: internal/mq/queue.go"},
+	}
+	inProgress := []*Issue{
+		{ID: "gt-busy", Status: "in_progress", Assignee: "gastown/polecats/nux", Description: "files: internal/mq/"},
+	}
+	cfg := &config.DispatchConfig{
+		LabelWeights: map[string]int{"urgent": 250},
+		SkipLabels:   []string{"needs-human"},
+	}
+
+	plan := PlanDispatch(ready, inProgress, DispatchOptions{Worker: worker, Config: cfg, Owners: owners, Now: now})
+
+	wantQueue := []string{"gt-mine", "gt-urgent", "gt-p1", "gt-ours", "gt-web", "gt-old", "gt-p2"}
+	if got := dispatchIDs(plan.Queue); !reflect.DeepEqual(got, wantQueue) {
+		t.Errorf("queue = %v, want %v", got, wantQueue)
+	}
+	if plan.Next == nil || plan.Next.ID != "gt-mine" {
+		t.Errorf("next = %+v, want gt-mine", plan.Next)
+	}
+	if plan.Held != "" {
+		t.Errorf("held = %q, want none", plan.Held)
+	}
+
+	skipped := make(map[string]string)
+	for _, c := range plan.Skipped {
+		skipped[c.ID] = c.Skipped
+	}
+	wantSkipped := map[string]string{
+		"gt-theirs": "assigned to",
+		"gt-epic":   "epics",
+		"gt-wisp":   "ephemeral",
+		"gt-human":  "skip label needs-human",
+		"gt-clash":  "like gt-busy",
+	}
+	for id, want := range wantSkipped {
+		if !strings.Contains(skipped[id], want) {
+			t.Errorf("%s skipped = %q, want it to mention %q", id, skipped[id], want)
+		}
+	}
+	if len(skipped) != len(wantSkipped) {
+		t.Errorf("skipped = %v, want %d beads", skipped, len(wantSkipped))
+	}
+}
+
+func TestPlanDispatchStrictOwnership(t *testing.T) {
+	owners := &config.OwnersConfig{
+		Teams: []config.TeamDef{{Name: "web", Members: []string{"gastown/polecats/nux"}}},
+		Rules: []config.OwnerRule{{Label: "frontend", Owner: "web"}},
+	}
+	ready := []*Issue{{ID: "gt-web", Status: "open", Labels: []string{"frontend"}}}
+	cfg := &config.DispatchConfig{StrictOwnership: true}
+
+	plan := PlanDispatch(ready, nil, DispatchOptions{Worker: "gastown/polecats/toast", Config: cfg, Owners: owners})
+	if plan.Next != nil || len(plan.Skipped) != 1 {
+		t.Fatalf("plan = %+v, want gt-web skipped", plan)
+	}
+	plan = PlanDispatch(ready, nil, DispatchOptions{Worker: "gastown/polecats/nux", Config: cfg, Owners: owners})
+	if plan.Next == nil || plan.Next.ID != "gt-web" {
+		t.Errorf("next for the owning team = %+v, want gt-web", plan.Next)
+	}
+}
+
+func TestPlanDispatchWIPLimits(t *testing.T) {
+	const worker = "gastown/polecats/toast"
+	ready := []*Issue{{ID: "gt-a", Status: "open", Priority: 1}}
+	inProgress := []*Issue{
+		{ID: "gt-w1", Status: "in_progress", Assignee: worker},
+		{ID: "gt-mol", Status: "in_progress", Assignee: worker, Type: "molecule"},
+		{ID: "gt-o1", Status: StatusHooked, Assignee: "gastown/polecats/nux"},
+	}
+
+	plan := PlanDispatch(ready, inProgress, DispatchOptions{Worker: worker})
+	if plan.Next != nil || !strings.Contains(plan.Held, "WIP limit (1/1)") {
+		t.Errorf("default limit: next=%v held=%q, want held at 1/1", plan.Next, plan.Held)
+	}
+	if len(plan.Queue) != 1 {
+		t.Errorf("queue should still be ranked while held, got %v", dispatchIDs(plan.Queue))
+	}
+
+	plan = PlanDispatch(ready, inProgress, DispatchOptions{Worker: worker, Config: &config.DispatchConfig{WIPLimit: 2}})
+	if plan.Next == nil || plan.Next.ID != "gt-a" {
+		t.Errorf("wip_limit 2: next = %v, held = %q, want gt-a", plan.Next, plan.Held)
+	}
+
+	plan = PlanDispatch(ready, inProgress, DispatchOptions{Worker: worker, Config: &config.DispatchConfig{WIPLimit: 2, RigWIPLimit: 2}})
+	if plan.Next != nil || !strings.Contains(plan.Held, "rig is at its WIP limit (2/2") {
+		t.Errorf("rig_wip_limit 2: next = %v, held = %q, want rig held", plan.Next, plan.Held)
+	}
+}
@@ -227,6 +227,34 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Required checks (CI gate): the refinery blocks the merge until all pass
+	RequiredChecks string // Comma-separated check specs (e.g., "test, gh:ci.yml, script:scripts/e2e.sh")
+	CheckResults   string // Latest results (e.g., "test=pass, gh:ci.yml=pending")
+	ChecksAt       string // When CheckResults were recorded (ISO 8601)
+
+	// Default reviewers from the town ownership map (settings/owners.json)
+	Reviewers string // Comma-separated worker addresses
+
+	// Stacked MRs: the refinery merges the parent first, then rebases this
+	// MR's branch from StackBase onto the target before merging it
+	Parent    string // Parent MR bead ID this MR is stacked on
+	StackBase string // Parent branch head when the parent landed (set by the refinery)
+
+	// Merge ordering: the refinery merges this MR only after these have
+	// merged, without stacking it on them
+	MergeAfter string // Comma-separated MR bead IDs
+
+	// Agent sessions whose transcripts produced the change, oldest first
+	Sessions string // Comma-separated session IDs
+
+	// Dependency updates: the refinery makes the change itself from this
+	// spec on the current target instead of merging a branch
+	DepUpdate string // Semicolon-separated specs (e.g., "gomod golang.org/x/net@v0.30.0")
+
+	// Supersession (gt mq supersede): a rewrite replaces an MR in the queue
+	Supersedes   string // MR bead ID this MR replaced
+	SupersededBy string // MR bead ID that replaced this one
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -301,6 +329,39 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "required_checks", "required-checks", "requiredchecks":
+			fields.RequiredChecks = value
+			hasFields = true
+		case "check_results", "check-results", "checkresults":
+			fields.CheckResults = value
+			hasFields = true
+		case "checks_at", "checks-at", "checksat":
+			fields.ChecksAt = value
+			hasFields = true
+		case "reviewers":
+			fields.Reviewers = value
+			hasFields = true
+		case "parent_mr", "parent-mr", "parentmr":
+			fields.Parent = value
+			hasFields = true
+		case "stack_base", "stack-base", "stackbase":
+			fields.StackBase = value
+			hasFields = true
+		case "merge_after", "merge-after", "mergeafter":
+			fields.MergeAfter = value
+			hasFields = true
+		case "sessions":
+			fields.Sessions = value
+			hasFields = true
+		case "dep_update", "dep-update", "depupdate":
+			fields.DepUpdate = value
+			hasFields = true
+		case "supersedes":
+			fields.Supersedes = value
+			hasFields = true
+		case "superseded_by", "superseded-by", "supersededby":
+			fields.SupersededBy = value
+			hasFields = true
 		}
 	}
 
@@ -365,6 +426,39 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.RequiredChecks != "" {
+		lines = append(lines, "required_checks: "+fields.RequiredChecks)
+	}
+	if fields.CheckResults != "" {
+		lines = append(lines, "check_results: "+fields.CheckResults)
+	}
+	if fields.ChecksAt != "" {
+		lines = append(lines, "checks_at: "+fields.ChecksAt)
+	}
+	if fields.Reviewers != "" {
+		lines = append(lines, "reviewers: "+fields.Reviewers)
+	}
+	if fields.Parent != "" {
+		lines = append(lines, "parent_mr: "+fields.Parent)
+	}
+	if fields.StackBase != "" {
+		lines = append(lines, "stack_base: "+fields.StackBase)
+	}
+	if fields.MergeAfter != "" {
+		lines = append(lines, "merge_after: "+fields.MergeAfter)
+	}
+	if fields.Sessions != "" {
+		lines = append(lines, "sessions: "+fields.Sessions)
+	}
+	if fields.DepUpdate != "" {
+		lines = append(lines, "dep_update: "+fields.DepUpdate)
+	}
+	if fields.Supersedes != "" {
+		lines = append(lines, "supersedes: "+fields.Supersedes)
+	}
+	if fields.SupersededBy != "" {
+		lines = append(lines, "superseded_by: "+fields.SupersededBy)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -411,6 +505,33 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"required_checks":    true,
+		"required-checks":    true,
+		"requiredchecks":     true,
+		"check_results":      true,
+		"check-results":      true,
+		"checkresults":       true,
+		"checks_at":          true,
+		"checks-at":          true,
+		"checksat":           true,
+		"reviewers":          true,
+		"parent_mr":          true,
+		"parent-mr":          true,
+		"parentmr":           true,
+		"stack_base":         true,
+		"stack-base":         true,
+		"stackbase":          true,
+		"merge_after":        true,
+		"merge-after":        true,
+		"mergeafter":         true,
+		"sessions":           true,
+		"dep_update":         true,
+		"dep-update":         true,
+		"depupdate":          true,
+		"supersedes":         true,
+		"superseded_by":      true,
+		"superseded-by":      true,
+		"supersededby":       true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -267,3 +267,66 @@ func TestParseAgentFieldsFromDescription(t *testing.T) {
 		t.Errorf("NotificationLevel = %q, want %q", got.NotificationLevel, "verbose")
 	}
 }
+
+func TestMRFieldsChecksRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "branch: polecat/nux\nrequired_checks: test, gh:ci.yml\ncheck_results: test=fail\n\nNotes stay."}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.RequiredChecks != "test, gh:ci.yml" || fields.CheckResults != "test=fail" {
+		t.Fatalf("parsed = %+v", fields)
+	}
+
+	fields.CheckResults = "test=pass, gh:ci.yml=pass"
+	fields.ChecksAt = "2026-03-01T12:00:00Z"
+	desc := SetMRFields(issue, fields)
+	if strings.Count(desc, "check_results:") != 1 || !strings.Contains(desc, "Notes stay.") {
+		t.Errorf("SetMRFields = %q", desc)
+	}
+	parsed := ParseMRFields(&Issue{Description: desc})
+	if parsed.CheckResults != fields.CheckResults || parsed.ChecksAt != fields.ChecksAt || parsed.RequiredChecks != fields.RequiredChecks {
+		t.Errorf("round trip = %+v", parsed)
+	}
+}
+
+func TestMRFieldsStackRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "branch: polecat/nux/gt-b\ntarget: main\nparent_mr: gt-mr-a"}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.Parent != "gt-mr-a" || fields.StackBase != "" {
+		t.Fatalf("parsed = %+v", fields)
+	}
+
+	fields.StackBase = "abc123"
+	desc := SetMRFields(issue, fields)
+	parsed := ParseMRFields(&Issue{Description: desc})
+	if parsed.Parent != "gt-mr-a" || parsed.StackBase != "abc123" || strings.Count(desc, "parent_mr:") != 1 {
+		t.Errorf("round trip = %+v (%q)", parsed, desc)
+	}
+}
+
+func TestMRFieldsMergeAfterRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "branch: polecat/nux/gt-b\nmerge_after: gt-mr-a, gt-mr-c"}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.MergeAfter != "gt-mr-a, gt-mr-c" {
+		t.Fatalf("parsed = %+v", fields)
+	}
+
+	fields.MergeAfter = ""
+	desc := SetMRFields(issue, fields)
+	if strings.Contains(desc, "merge_after") {
+		t.Errorf("cleared merge_after still in %q", desc)
+	}
+}
+
+func TestMRFieldsSessionsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "branch: polecat/nux/gt-b\nsessions: s1, s2\n\nNotes stay."}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.Sessions != "s1, s2" {
+		t.Fatalf("parsed = %+v", fields)
+	}
+
+	fields.Sessions = "s1, s2, s3"
+	desc := SetMRFields(issue, fields)
+	parsed := ParseMRFields(&Issue{Description: desc})
+	if parsed.Sessions != "s1, s2, s3" || strings.Count(desc, "sessions:") != 1 || !strings.Contains(desc, "Notes stay.") {
+		t.Errorf("round trip = %+v (%q)", parsed, desc)
+	}
+}
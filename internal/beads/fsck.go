@@ -0,0 +1,230 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Integrity problem kinds reported by CheckIntegrity.
+const (
+	FsckDanglingDependency = "dangling-dependency"    // Dependency on a bead that does not exist
+	FsckDanglingMRLink     = "dangling-mr-link"       // active_mr or MR field naming a missing bead
+	FsckOrphanedAttachment = "orphaned-attachment"    // attached_molecule naming a missing molecule
+	FsckDuplicateExternal  = "duplicate-external-ref" // Several beads share one external_ref
+)
+
+// FsckRecord is the subset of a bd export row used by the integrity pass,
+// duplicate detection and epic planning.
+type FsckRecord struct {
+	ID           string           `json:"id"`
+	Title        string           `json:"title,omitempty"`
+	Status       string           `json:"status"`
+	Type         string           `json:"issue_type"`
+	Priority     int              `json:"priority"`
+	Labels       []string         `json:"labels,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	ExternalRef  string           `json:"external_ref,omitempty"`
+	CreatedAt    string           `json:"created_at,omitempty"`
+	Dependencies []FsckDependency `json:"dependencies,omitempty"`
+}
+
+// FsckDependency is a dependency edge as written by bd export.
+type FsckDependency struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+	Type        string `json:"type"`
+}
+
+// FsckProblem is one integrity problem.
+type FsckProblem struct {
+	Kind    string `json:"kind"`
+	IssueID string `json:"issue_id"`         // Bead holding the bad reference
+	Target  string `json:"target,omitempty"` // Missing or duplicated reference
+	Detail  string `json:"detail"`
+	Fixable bool   `json:"fixable"`
+}
+
+// FsckReport is the result of an integrity pass.
+type FsckReport struct {
+	Checked  int           `json:"checked"`
+	Problems []FsckProblem `json:"problems"`
+}
+
+// ParseFsckRecords parses bd export JSONL output.
+func ParseFsckRecords(data []byte) ([]FsckRecord, error) {
+	var records []FsckRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec FsckRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("parsing export row: %w", err)
+		}
+		if rec.ID != "" {
+			records = append(records, rec)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CheckIntegrity finds references between beads that no longer resolve.
+//
+// Only references whose prefix belongs to this database are checked; a
+// cross-rig reference (e.g., hq-cv-abc from a rig database) cannot be
+// resolved locally and is not reported.
+func CheckIntegrity(records []FsckRecord) *FsckReport {
+	report := &FsckReport{Checked: len(records), Problems: []FsckProblem{}}
+
+	exists := make(map[string]bool, len(records))
+	prefixes := make(map[string]bool)
+	for _, rec := range records {
+		exists[rec.ID] = true
+		prefixes[idPrefix(rec.ID)] = true
+	}
+	missing := func(ref string) bool {
+		return ref != "" && !exists[ref] && prefixes[idPrefix(ref)]
+	}
+
+	byExternal := make(map[string][]string)
+	for _, rec := range records {
+		for _, dep := range rec.Dependencies {
+			if missing(dep.DependsOnID) {
+				report.Problems = append(report.Problems, FsckProblem{
+					Kind:    FsckDanglingDependency,
+					IssueID: rec.ID,
+					Target:  dep.DependsOnID,
+					Detail:  fmt.Sprintf("%s dependency on missing bead %s", depTypeOrDefault(dep.Type), dep.DependsOnID),
+					Fixable: true,
+				})
+			}
+		}
+
+		issue := &Issue{ID: rec.ID, Description: rec.Description, Labels: rec.Labels}
+
+		if HasLabel(issue, "gt:agent") || rec.Type == "agent" {
+			if fields := ParseAgentFields(rec.Description); fields != nil && missing(fields.ActiveMR) {
+				report.Problems = append(report.Problems, FsckProblem{
+					Kind:    FsckDanglingMRLink,
+					IssueID: rec.ID,
+					Target:  fields.ActiveMR,
+					Detail:  "active_mr points at missing merge request " + fields.ActiveMR,
+					Fixable: true,
+				})
+			}
+		}
+
+		if HasLabel(issue, "gt:merge-request") || rec.Type == "merge-request" {
+			if fields := ParseMRFields(issue); fields != nil {
+				if missing(fields.SourceIssue) {
+					report.Problems = append(report.Problems, FsckProblem{
+						Kind:    FsckDanglingMRLink,
+						IssueID: rec.ID,
+						Target:  fields.SourceIssue,
+						Detail:  "merge request source_issue " + fields.SourceIssue + " is missing",
+					})
+				}
+			}
+		}
+
+		if fields := ParseAttachmentFields(issue); fields != nil && missing(fields.AttachedMolecule) {
+			report.Problems = append(report.Problems, FsckProblem{
+				Kind:    FsckOrphanedAttachment,
+				IssueID: rec.ID,
+				Target:  fields.AttachedMolecule,
+				Detail:  "attached_molecule " + fields.AttachedMolecule + " is missing",
+				Fixable: true,
+			})
+		}
+
+		if ref := strings.TrimSpace(rec.ExternalRef); ref != "" {
+			byExternal[ref] = append(byExternal[ref], rec.ID)
+		}
+	}
+
+	refs := make([]string, 0, len(byExternal))
+	for ref, ids := range byExternal {
+		if len(ids) > 1 {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		ids := byExternal[ref]
+		sort.Strings(ids)
+		report.Problems = append(report.Problems, FsckProblem{
+			Kind:    FsckDuplicateExternal,
+			IssueID: ids[0],
+			Target:  ref,
+			Detail:  fmt.Sprintf("external_ref %s is shared by %s", ref, strings.Join(ids, ", ")),
+		})
+	}
+
+	return report
+}
+
+// idPrefix returns the bead ID prefix ("gt" for "gt-abc").
+func idPrefix(id string) string {
+	if i := strings.Index(id, "-"); i > 0 {
+		return id[:i]
+	}
+	return ""
+}
+
+func depTypeOrDefault(t string) string {
+	if t == "" {
+		return "blocks"
+	}
+	return t
+}
+
+// Fsck exports the database and checks it with CheckIntegrity.
+func (b *Beads) Fsck() (*FsckReport, error) {
+	records, err := b.exportRecords()
+	if err != nil {
+		return nil, err
+	}
+	return CheckIntegrity(records), nil
+}
+
+// FsckFix repairs a fixable problem by removing the dangling reference.
+// Problems that need a human decision (duplicate external refs, MR source
+// issues) return an error.
+func (b *Beads) FsckFix(p FsckProblem) error {
+	if !p.Fixable {
+		return fmt.Errorf("%s on %s is not automatically fixable", p.Kind, p.IssueID)
+	}
+	switch p.Kind {
+	case FsckDanglingDependency:
+		return b.RemoveDependency(p.IssueID, p.Target)
+	case FsckDanglingMRLink:
+		return b.UpdateAgentActiveMR(p.IssueID, "")
+	case FsckOrphanedAttachment:
+		_, err := b.DetachMolecule(p.IssueID)
+		return err
+	}
+	return fmt.Errorf("unknown problem kind %q", p.Kind)
+}
+
+// FsckFixAction describes what FsckFix would do, for dry-run output.
+func FsckFixAction(p FsckProblem) string {
+	switch p.Kind {
+	case FsckDanglingDependency:
+		return fmt.Sprintf("bd dep remove %s %s", p.IssueID, p.Target)
+	case FsckDanglingMRLink:
+		return fmt.Sprintf("clear active_mr on %s", p.IssueID)
+	case FsckOrphanedAttachment:
+		return fmt.Sprintf("detach molecule %s from %s", p.Target, p.IssueID)
+	}
+	return ""
+}
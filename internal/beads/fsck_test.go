@@ -0,0 +1,80 @@
+package beads
+
+import (
+	"testing"
+)
+
+func TestParseFsckRecords(t *testing.T) {
+	data := []byte(`{"id":"gt-1","status":"open","issue_type":"task","dependencies":[{"issue_id":"gt-1","depends_on_id":"gt-9","type":"blocks"}]}
+
+{"id":"gt-2","status":"closed","external_ref":"gh-12"}
+`)
+	records, err := ParseFsckRecords(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if len(records[0].Dependencies) != 1 || records[0].Dependencies[0].DependsOnID != "gt-9" {
+		t.Errorf("dependencies = %+v", records[0].Dependencies)
+	}
+	if records[1].ExternalRef != "gh-12" {
+		t.Errorf("external_ref = %q", records[1].ExternalRef)
+	}
+
+	if _, err := ParseFsckRecords([]byte("{not json\n")); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-1", Dependencies: []FsckDependency{
+			{IssueID: "gt-1", DependsOnID: "gt-2"},
+			{IssueID: "gt-1", DependsOnID: "gt-gone", Type: "parent-child"},
+			{IssueID: "gt-1", DependsOnID: "hq-cv-abc"}, // cross-database, not checked
+		}},
+		{ID: "gt-2", ExternalRef: "gh-7"},
+		{ID: "gt-3", ExternalRef: "gh-7"},
+		{ID: "gt-agent", Labels: []string{"gt:agent"}, Description: "role_type: polecat\nactive_mr: gt-mr-missing"},
+		{ID: "gt-mr", Labels: []string{"gt:merge-request"}, Description: "branch: polecat/nux\nsource_issue: gt-lost"},
+		{ID: "gt-hook", Description: "attached_molecule: gt-wisp-old\nattached_at: 2026-01-01T00:00:00Z"},
+	}
+
+	report := CheckIntegrity(records)
+	if report.Checked != len(records) {
+		t.Errorf("Checked = %d, want %d", report.Checked, len(records))
+	}
+
+	want := map[string]FsckProblem{
+		"gt-gone":       {Kind: FsckDanglingDependency, IssueID: "gt-1", Fixable: true},
+		"gt-mr-missing": {Kind: FsckDanglingMRLink, IssueID: "gt-agent", Fixable: true},
+		"gt-lost":       {Kind: FsckDanglingMRLink, IssueID: "gt-mr", Fixable: false},
+		"gt-wisp-old":   {Kind: FsckOrphanedAttachment, IssueID: "gt-hook", Fixable: true},
+		"gh-7":          {Kind: FsckDuplicateExternal, IssueID: "gt-2", Fixable: false},
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %+v", len(report.Problems), len(want), report.Problems)
+	}
+	for _, p := range report.Problems {
+		w, ok := want[p.Target]
+		if !ok {
+			t.Errorf("unexpected problem %+v", p)
+			continue
+		}
+		if p.Kind != w.Kind || p.IssueID != w.IssueID || p.Fixable != w.Fixable {
+			t.Errorf("problem for %s = %+v, want %+v", p.Target, p, w)
+		}
+	}
+}
+
+func TestCheckIntegrity_Clean(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-1", Dependencies: []FsckDependency{{IssueID: "gt-1", DependsOnID: "gt-2"}}},
+		{ID: "gt-2", Description: "attached_molecule: gt-1"},
+	}
+	if report := CheckIntegrity(records); len(report.Problems) != 0 {
+		t.Errorf("expected no problems, got %+v", report.Problems)
+	}
+}
@@ -26,7 +26,7 @@ func HandoffBeadTitle(role string) string {
 // FindHandoffBead finds the pinned handoff bead for a role by title.
 // Returns nil if not found (not an error).
 func (b *Beads) FindHandoffBead(role string) (*Issue, error) {
-	issues, err := b.List(ListOptions{Status: StatusPinned, Priority: -1})
+	issues, err := b.List(ListOptions{Status: StatusPinned})
 	if err != nil {
 		return nil, fmt.Errorf("listing pinned issues: %w", err)
 	}
@@ -110,9 +110,8 @@ type ClearMailResult struct {
 func (b *Beads) ClearMail(reason string) (*ClearMailResult, error) {
 	// List all open messages
 	issues, err := b.List(ListOptions{
-		Status:   "open",
-		Label:    "gt:message",
-		Priority: -1,
+		Status: "open",
+		Label:  "gt:message",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listing messages: %w", err)
@@ -0,0 +1,150 @@
+// Package beads provides the per-issue activity log.
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// HistoryFile is the append-only activity log in a beads directory. Every
+// status change, priority change, comment and MR link made through this
+// package is appended as one JSON line; entries are never rewritten.
+const HistoryFile = "history.jsonl"
+
+// History entry kinds.
+const (
+	HistoryStatus   = "status"   // Value is the new status
+	HistoryPriority = "priority" // Value is the new priority (0-4)
+	HistoryComment  = "comment"  // Value is the comment text
+	HistoryMR       = "mr"       // Value is the merge-request bead ID
+)
+
+// HistoryEntry is one change to an issue.
+type HistoryEntry struct {
+	Timestamp string `json:"timestamp"`
+	IssueID   string `json:"issue_id"`
+	Kind      string `json:"kind"`
+	Value     string `json:"value"`
+	Detail    string `json:"detail,omitempty"` // e.g., close reason
+	Actor     string `json:"actor,omitempty"`
+}
+
+// HistoryPath returns the activity log of a beads directory.
+func HistoryPath(beadsDir string) string {
+	return filepath.Join(beadsDir, HistoryFile)
+}
+
+// RecordHistory appends entries to the activity log of the beads directory
+// this wrapper operates on, filling in the timestamp and actor.
+func (b *Beads) RecordHistory(entries ...HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var data []byte
+	for _, e := range entries {
+		if e.Timestamp == "" {
+			e.Timestamp = currentTimestamp()
+		}
+		if e.Actor == "" {
+			e.Actor = b.getActor()
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshaling history entry: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	// One write per call so concurrent writers never interleave lines
+	f, err := os.OpenFile(HistoryPath(b.getResolvedBeadsDir()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening history log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing history log: %w", err)
+	}
+	return nil
+}
+
+// RecordMRLink records that mrID was submitted for issueID.
+func (b *Beads) RecordMRLink(issueID, mrID string) error {
+	return b.RecordHistory(HistoryEntry{IssueID: issueID, Kind: HistoryMR, Value: mrID})
+}
+
+// recordHistory is RecordHistory for bookkeeping after a successful change:
+// the change has been made, so a failure is only a warning.
+func (b *Beads) recordHistory(entries ...HistoryEntry) {
+	if _, err := os.Stat(b.getResolvedBeadsDir()); err != nil {
+		return // not a beads directory (e.g., routed through bd)
+	}
+	if err := b.RecordHistory(entries...); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record bead history: %v\n", err)
+	}
+}
+
+// recordStatus records a status change for each of ids.
+func (b *Beads) recordStatus(status, detail string, ids ...string) {
+	entries := make([]HistoryEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = HistoryEntry{IssueID: id, Kind: HistoryStatus, Value: status, Detail: detail}
+	}
+	b.recordHistory(entries...)
+}
+
+// updateHistory returns the history entries for an Update of id.
+func updateHistory(id string, opts UpdateOptions) []HistoryEntry {
+	var entries []HistoryEntry
+	if opts.Status != nil {
+		entries = append(entries, HistoryEntry{IssueID: id, Kind: HistoryStatus, Value: *opts.Status})
+	}
+	if opts.Priority != nil {
+		entries = append(entries, HistoryEntry{IssueID: id, Kind: HistoryPriority, Value: strconv.Itoa(*opts.Priority)})
+	}
+	return entries
+}
+
+// ReadHistory returns the entries for issueID in the activity log of
+// beadsDir, oldest first. A missing log has no entries.
+func ReadHistory(beadsDir, issueID string) ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	err := scanHistory(beadsDir, func(e HistoryEntry) {
+		if e.IssueID == issueID {
+			entries = append(entries, e)
+		}
+	})
+	return entries, err
+}
+
+// scanHistory calls fn for each entry in the activity log of beadsDir,
+// oldest first. A missing log has no entries.
+func scanHistory(beadsDir string, fn func(HistoryEntry)) error {
+	f, err := os.Open(HistoryPath(beadsDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("opening history log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024) // comments can be long
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // skip a torn or foreign line rather than hide the rest
+		}
+		fn(e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading history log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package beads
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRecordReadHistory(t *testing.T) {
+	dir := t.TempDir()
+	b := NewWithBeadsDir(dir, dir)
+	t.Setenv("BD_ACTOR", "gastown/polecats/nux")
+
+	status, priority := "in_progress", 1
+	b.recordHistory(updateHistory("gt-1", UpdateOptions{Status: &status, Priority: &priority})...)
+	b.recordHistory(HistoryEntry{IssueID: "gt-2", Kind: HistoryComment, Value: "other issue"})
+	b.recordStatus("closed", "done", "gt-1")
+	if err := b.RecordMRLink("gt-1", "gt-mr-9"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadHistory(dir, "gt-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ kind, value, detail string }{
+		{HistoryStatus, "in_progress", ""},
+		{HistoryPriority, "1", ""},
+		{HistoryStatus, "closed", "done"},
+		{HistoryMR, "gt-mr-9", ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Kind != w.kind || e.Value != w.value || e.Detail != w.detail {
+			t.Errorf("entry %d = %+v, want %+v", i, e, w)
+		}
+		if e.Actor != "gastown/polecats/nux" || e.Timestamp == "" {
+			t.Errorf("entry %d missing actor or timestamp: %+v", i, e)
+		}
+	}
+}
+
+func TestReadHistory_MissingAndTorn(t *testing.T) {
+	dir := t.TempDir()
+	if entries, err := ReadHistory(dir, "gt-1"); err != nil || entries != nil {
+		t.Fatalf("missing log: %v, %v", entries, err)
+	}
+
+	log := `{"timestamp":"2026-01-01T00:00:00Z","issue_id":"gt-1","kind":"status","value":"open"}
+{"timestamp":"2026-01-01T00:01:00Z","issue_id":"gt-1","ki
+{"timestamp":"2026-01-01T00:02:00Z","issue_id":"gt-1","kind":"comment","value":"hi"}
+`
+	if err := os.WriteFile(HistoryPath(dir), []byte(log), 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadHistory(dir, "gt-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2 (torn line skipped)", len(entries))
+	}
+}
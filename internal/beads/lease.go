@@ -0,0 +1,275 @@
+// Package beads provides time-limited claims (leases) on beads.
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLeaseTTL is how long a claim lasts when no duration is given.
+const DefaultLeaseTTL = 2 * time.Hour
+
+// Lease field keys in a bead's description.
+const (
+	leaseHolderKey   = "lease_holder"
+	leaseAcquiredKey = "lease_acquired"
+	leaseExpiresKey  = "lease_expires"
+)
+
+// Lease is a time-limited claim on a bead. The holder is also the bead's
+// assignee and the bead is in_progress while the lease lasts. An expired
+// lease returns the bead to the pool (see ReapExpiredLeases), so work
+// claimed by an agent that died does not stay stuck.
+type Lease struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the lease has run out at now.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// LeaseHeldError is returned when a bead is claimed by someone else.
+type LeaseHeldError struct {
+	ID     string
+	Holder string
+	// ExpiresAt is zero when the bead is assigned without a lease.
+	ExpiresAt time.Time
+}
+
+func (e *LeaseHeldError) Error() string {
+	if e.ExpiresAt.IsZero() {
+		return fmt.Sprintf("%s is assigned to %s (no lease)", e.ID, e.Holder)
+	}
+	return fmt.Sprintf("%s is claimed by %s until %s", e.ID, e.Holder, e.ExpiresAt.Local().Format("2006-01-02 15:04"))
+}
+
+// ParseLease returns the lease recorded in issue's description, or nil.
+func ParseLease(issue *Issue) *Lease {
+	if issue == nil {
+		return nil
+	}
+	l := &Lease{}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case leaseHolderKey:
+			l.Holder = value
+		case leaseAcquiredKey:
+			l.AcquiredAt, _ = time.Parse(time.RFC3339, value)
+		case leaseExpiresKey:
+			l.ExpiresAt, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	if l.Holder == "" || l.ExpiresAt.IsZero() {
+		return nil
+	}
+	return l
+}
+
+// SetLease returns issue's description with its lease replaced by l, or
+// removed when l is nil. Other lines are kept.
+func SetLease(issue *Issue, l *Lease) string {
+	var lines []string
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok {
+			switch strings.TrimSpace(key) {
+			case leaseHolderKey, leaseAcquiredKey, leaseExpiresKey:
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if l != nil {
+		lines = append(lines,
+			leaseHolderKey+": "+l.Holder,
+			leaseAcquiredKey+": "+l.AcquiredAt.UTC().Format(time.RFC3339),
+			leaseExpiresKey+": "+l.ExpiresAt.UTC().Format(time.RFC3339),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Claim leases id to holder for ttl. Claiming a bead holder already holds
+// renews the lease. A bead leased to someone else, or assigned and in
+// progress without a lease, is refused with a *LeaseHeldError unless force
+// is set. Claims are serialized per bead on this machine and verified by
+// reading the bead back, so two agents never both win the same bead.
+func (b *Beads) Claim(id, holder string, ttl time.Duration, force bool) (*Lease, error) {
+	if holder == "" {
+		return nil, fmt.Errorf("claiming %s: no holder identity", id)
+	}
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Status == "closed" {
+		return nil, fmt.Errorf("%s is closed", id)
+	}
+	now := time.Now()
+	if !force {
+		if err := leaseConflict(issue, holder, now); err != nil {
+			return nil, err
+		}
+	}
+
+	lease := &Lease{Holder: holder, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+	if current := ParseLease(issue); current != nil && current.Holder == holder && !current.Expired(now) {
+		lease.AcquiredAt = current.AcquiredAt // a renewal keeps the original claim time
+	}
+	desc := SetLease(issue, lease)
+	status := "in_progress"
+	if err := b.Update(id, UpdateOptions{Status: &status, Assignee: &holder, Description: &desc}); err != nil {
+		return nil, fmt.Errorf("claiming %s: %w", id, err)
+	}
+
+	// Another machine may have claimed it between our read and write.
+	after, err := b.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("verifying claim on %s: %w", id, err)
+	}
+	if got := ParseLease(after); got == nil || got.Holder != holder || after.Assignee != holder {
+		if got != nil {
+			return nil, &LeaseHeldError{ID: id, Holder: got.Holder, ExpiresAt: got.ExpiresAt}
+		}
+		return nil, &LeaseHeldError{ID: id, Holder: after.Assignee}
+	}
+	return lease, nil
+}
+
+// leaseConflict returns a *LeaseHeldError if issue is held by someone other
+// than holder at now.
+func leaseConflict(issue *Issue, holder string, now time.Time) error {
+	if l := ParseLease(issue); l != nil {
+		if l.Holder != holder && !l.Expired(now) {
+			return &LeaseHeldError{ID: issue.ID, Holder: l.Holder, ExpiresAt: l.ExpiresAt}
+		}
+		return nil
+	}
+	if issue.Assignee != "" && issue.Assignee != holder &&
+		(issue.Status == "in_progress" || issue.Status == StatusHooked) {
+		return &LeaseHeldError{ID: issue.ID, Holder: issue.Assignee}
+	}
+	return nil
+}
+
+// Unclaim ends holder's lease on id and returns the bead to the pool (open,
+// unassigned). Unless force is set, only the holder may release it.
+func (b *Beads) Unclaim(id, holder string, force bool) error {
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	l := ParseLease(issue)
+	if l == nil {
+		return fmt.Errorf("%s has no lease", id)
+	}
+	if l.Holder != holder && !force {
+		return &LeaseHeldError{ID: id, Holder: l.Holder, ExpiresAt: l.ExpiresAt}
+	}
+	return b.returnToPool(issue)
+}
+
+// returnToPool reopens a leased bead, unassigned and without its lease.
+func (b *Beads) returnToPool(issue *Issue) error {
+	desc := SetLease(issue, nil)
+	status, assignee := "open", ""
+	if err := b.Update(issue.ID, UpdateOptions{Status: &status, Assignee: &assignee, Description: &desc}); err != nil {
+		return fmt.Errorf("releasing %s: %w", issue.ID, err)
+	}
+	return nil
+}
+
+// ListLeases returns the in-progress beads that carry a lease.
+func (b *Beads) ListLeases() ([]*Issue, error) {
+	issues, err := b.List(ListOptions{Status: "in_progress"})
+	if err != nil {
+		return nil, err
+	}
+	var leased []*Issue
+	for _, issue := range issues {
+		if ParseLease(issue) != nil {
+			leased = append(leased, issue)
+		}
+	}
+	return leased, nil
+}
+
+// ReapExpiredLeases returns every bead whose lease has expired at now to
+// the pool, with a comment naming the lapsed holder, and returns their IDs.
+func (b *Beads) ReapExpiredLeases(now time.Time) ([]string, error) {
+	leased, err := b.ListLeases()
+	if err != nil {
+		return nil, err
+	}
+	var reaped []string
+	var errs []string
+	for _, candidate := range leased {
+		if l := ParseLease(candidate); !l.Expired(now) {
+			continue
+		}
+		id, err := b.reapLease(candidate.ID, now)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if id != "" {
+			reaped = append(reaped, id)
+		}
+	}
+	if len(errs) > 0 {
+		return reaped, fmt.Errorf("reaping leases: %s", strings.Join(errs, "; "))
+	}
+	return reaped, nil
+}
+
+// reapLease returns id to the pool if its lease is still expired once the
+// bead is locked (it may have been renewed meanwhile).
+func (b *Beads) reapLease(id string, now time.Time) (string, error) {
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return "", fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return "", err
+	}
+	l := ParseLease(issue)
+	if l == nil || !l.Expired(now) {
+		return "", nil
+	}
+	if err := b.returnToPool(issue); err != nil {
+		return "", err
+	}
+	_ = b.AddComment(id, fmt.Sprintf("Lease held by %s expired at %s; returned to the pool", l.Holder, l.ExpiresAt.UTC().Format(time.RFC3339)))
+	return id, nil
+}
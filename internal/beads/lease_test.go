@@ -0,0 +1,65 @@
+package beads
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeaseRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	issue := &Issue{ID: "gt-1", Description: "Fix the widget.\n\nattached_molecule: gt-wisp-1\n"}
+	if ParseLease(issue) != nil {
+		t.Fatal("lease found on an unclaimed bead")
+	}
+
+	issue.Description = SetLease(issue, &Lease{Holder: "gastown/polecats/toast", AcquiredAt: now, ExpiresAt: now.Add(2 * time.Hour)})
+	l := ParseLease(issue)
+	if l == nil || l.Holder != "gastown/polecats/toast" || !l.AcquiredAt.Equal(now) || !l.ExpiresAt.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("lease = %+v", l)
+	}
+	if l.Expired(now.Add(time.Hour)) || !l.Expired(now.Add(2*time.Hour)) {
+		t.Error("lease expiry is off")
+	}
+
+	// Renewing replaces the lease rather than adding a second one.
+	issue.Description = SetLease(issue, &Lease{Holder: "gastown/polecats/toast", AcquiredAt: now, ExpiresAt: now.Add(4 * time.Hour)})
+	if n := strings.Count(issue.Description, "lease_expires:"); n != 1 {
+		t.Errorf("%d lease_expires lines after renewal:\n%s", n, issue.Description)
+	}
+
+	issue.Description = SetLease(issue, nil)
+	if ParseLease(issue) != nil {
+		t.Error("lease still present after removal")
+	}
+	if !strings.Contains(issue.Description, "attached_molecule: gt-wisp-1") || !strings.HasPrefix(issue.Description, "Fix the widget.") {
+		t.Errorf("other description lines lost:\n%s", issue.Description)
+	}
+}
+
+func TestLeaseConflict(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	leased := &Issue{ID: "gt-1", Status: "in_progress", Assignee: "gastown/polecats/toast"}
+	leased.Description = SetLease(leased, &Lease{Holder: "gastown/polecats/toast", AcquiredAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	var held *LeaseHeldError
+	if err := leaseConflict(leased, "gastown/polecats/nux", now); !errors.As(err, &held) || held.Holder != "gastown/polecats/toast" {
+		t.Errorf("claim of a held bead: %v", err)
+	}
+	if err := leaseConflict(leased, "gastown/polecats/toast", now); err != nil {
+		t.Errorf("renewal by the holder: %v", err)
+	}
+	if err := leaseConflict(leased, "gastown/polecats/nux", now.Add(time.Hour)); err != nil {
+		t.Errorf("claim of an expired lease: %v", err)
+	}
+
+	// Assigned and in progress without a lease still counts as taken.
+	assigned := &Issue{ID: "gt-2", Status: "in_progress", Assignee: "gastown/crew/joe"}
+	if err := leaseConflict(assigned, "gastown/polecats/nux", now); !errors.As(err, &held) || !held.ExpiresAt.IsZero() {
+		t.Errorf("claim of an assigned bead: %v", err)
+	}
+	if err := leaseConflict(&Issue{ID: "gt-3", Status: "open", Assignee: "gastown/crew/joe"}, "gastown/polecats/nux", now); err != nil {
+		t.Errorf("claim of an open bead: %v", err)
+	}
+}
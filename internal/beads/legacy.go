@@ -0,0 +1,292 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LegacyPreservedSuffix is appended to a JSONL-only rig's issues.jsonl when
+// it is migrated to Dolt. The preserved file is the verification source.
+const LegacyPreservedSuffix = ".legacy"
+
+// LegacyRecord is one issue from a bd JSONL export, with every relation it
+// declares as an edge from the issue (blocks, parent-child, related, ...).
+type LegacyRecord struct {
+	Issue     *Issue
+	Relations []FsckDependency
+}
+
+// legacyRow accepts both relation shapes found in old issues.jsonl files:
+// {issue_id, depends_on_id, type} edges and {id, dependency_type} entries.
+type legacyRow struct {
+	Issue
+	Dependencies []struct {
+		DependsOnID    string `json:"depends_on_id"`
+		Type           string `json:"type"`
+		ID             string `json:"id"`
+		DependencyType string `json:"dependency_type"`
+	} `json:"dependencies,omitempty"`
+}
+
+// ParseLegacyJSONL reads a bd JSONL export. Relations come from the
+// dependencies edges and from the older depends_on and parent fields.
+func ParseLegacyJSONL(r io.Reader) ([]*LegacyRecord, error) {
+	var records []*LegacyRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row legacyRow
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("JSONL line %d: %w", line, err)
+		}
+		if row.ID == "" {
+			continue
+		}
+		issue := row.Issue
+		rec := &LegacyRecord{Issue: &issue}
+		for _, d := range row.Dependencies {
+			target, typ := d.DependsOnID, d.Type
+			if target == "" {
+				target, typ = d.ID, d.DependencyType
+			}
+			rec.addRelation(target, depTypeOrDefault(typ))
+		}
+		for _, id := range issue.DependsOn {
+			rec.addRelation(id, "blocks")
+		}
+		if issue.Parent != "" {
+			rec.addRelation(issue.Parent, "parent-child")
+		}
+		issue.Parent, issue.DependsOn, issue.Dependencies = "", nil, nil
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *LegacyRecord) addRelation(target, typ string) {
+	if target == "" || target == r.Issue.ID {
+		return
+	}
+	for _, rel := range r.Relations {
+		if rel.DependsOnID == target && rel.Type == typ {
+			return
+		}
+	}
+	r.Relations = append(r.Relations, FsckDependency{IssueID: r.Issue.ID, DependsOnID: target, Type: typ})
+}
+
+// parent returns the record's parent-child target, if any.
+func (r *LegacyRecord) parent() string {
+	for _, rel := range r.Relations {
+		if rel.Type == "parent-child" {
+			return rel.DependsOnID
+		}
+	}
+	return ""
+}
+
+// LegacyPlan is what a JSONL-to-Dolt migration will import.
+type LegacyPlan struct {
+	Records  []*LegacyRecord
+	Inferred []FsckDependency // parent-child edges inferred from hierarchical IDs
+	Dangling []FsckDependency // edges to issues not in the file; not imported
+}
+
+// PlanLegacyImport reconstructs relations before import. A hierarchical
+// child ID (gt-abc.2) with no recorded parent gets a parent-child edge to
+// its parent (gt-abc) when that issue is in the file. Edges to issues that
+// aren't in the file are dropped and reported.
+func PlanLegacyImport(records []*LegacyRecord) *LegacyPlan {
+	plan := &LegacyPlan{Records: records}
+	known := make(map[string]bool, len(records))
+	for _, rec := range records {
+		known[rec.Issue.ID] = true
+	}
+	for _, rec := range records {
+		if rec.parent() == "" {
+			if parent := hierarchicalParent(rec.Issue.ID); parent != "" && known[parent] {
+				rec.addRelation(parent, "parent-child")
+				plan.Inferred = append(plan.Inferred, rec.Relations[len(rec.Relations)-1])
+			}
+		}
+		kept := rec.Relations[:0]
+		for _, rel := range rec.Relations {
+			if known[rel.DependsOnID] {
+				kept = append(kept, rel)
+			} else {
+				plan.Dangling = append(plan.Dangling, rel)
+			}
+		}
+		rec.Relations = kept
+	}
+	return plan
+}
+
+// hierarchicalParent returns "gt-abc" for "gt-abc.2", or "".
+func hierarchicalParent(id string) string {
+	i := strings.LastIndex(id, ".")
+	if i <= 0 {
+		return ""
+	}
+	if _, err := strconv.Atoi(id[i+1:]); err != nil {
+		return ""
+	}
+	return id[:i]
+}
+
+// RelationCount returns the number of relation edges in the plan.
+func (p *LegacyPlan) RelationCount() int {
+	n := 0
+	for _, rec := range p.Records {
+		n += len(rec.Relations)
+	}
+	return n
+}
+
+// legacyImporter is the subset of Beads used by ImportLegacy.
+type legacyImporter interface {
+	importer
+	AddTypedDependency(issue, dependsOn, depType string) error
+}
+
+// ImportLegacy imports a plan into an empty database, keeping every
+// issue's ID. Parent-child and blocks relations are created by
+// ImportIssues; other relation types (related, discovered-from, ...) are
+// added afterwards. Failures are reported per record, as in ImportIssues.
+func ImportLegacy(b legacyImporter, plan *LegacyPlan) []ImportAction {
+	issues := make([]*Issue, len(plan.Records))
+	for i, rec := range plan.Records {
+		issue := *rec.Issue
+		issue.Parent = rec.parent()
+		for _, rel := range rec.Relations {
+			if rel.Type == "blocks" {
+				issue.DependsOn = append(issue.DependsOn, rel.DependsOnID)
+			}
+		}
+		issues[i] = &issue
+	}
+
+	actions := ImportIssues(b, issues, ImportOptions{OnCollision: CollisionSkip})
+	idMap := make(map[string]string, len(actions))
+	for _, a := range actions {
+		if a.ID != "" {
+			idMap[a.SourceID] = a.ID
+		}
+	}
+
+	for i, rec := range plan.Records {
+		a := &actions[i]
+		if a.Action != "created" {
+			continue
+		}
+		var errs []string
+		for _, rel := range rec.Relations {
+			if rel.Type == "blocks" || rel.Type == "parent-child" {
+				continue
+			}
+			target := remapID(idMap, rel.DependsOnID)
+			if err := b.AddTypedDependency(a.ID, target, rel.Type); err != nil {
+				errs = append(errs, fmt.Sprintf("%s %s: %v", rel.Type, target, err))
+			}
+		}
+		if len(errs) > 0 {
+			a.Detail = strings.TrimPrefix(a.Detail+"; "+strings.Join(errs, "; "), "; ")
+		}
+	}
+	return actions
+}
+
+// LegacyDiff is one difference between the source JSONL and the migrated
+// database.
+type LegacyDiff struct {
+	ID     string `json:"id"`
+	Field  string `json:"field"` // missing, extra, title, status, ...
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+}
+
+// DiffLegacy compares the migrated database (target, from bd export)
+// against the plan it was imported from. Relations to issues outside the
+// file are ignored, as they were never imported. Gas Town stores an
+// issue's type as a gt:<type> label, so a type matches either way.
+func DiffLegacy(plan *LegacyPlan, target []*LegacyRecord) []LegacyDiff {
+	byID := make(map[string]*LegacyRecord, len(target))
+	for _, rec := range target {
+		byID[rec.Issue.ID] = rec
+	}
+
+	var diffs []LegacyDiff
+	seen := make(map[string]bool, len(plan.Records))
+	for _, src := range plan.Records {
+		id := src.Issue.ID
+		seen[id] = true
+		dst := byID[id]
+		if dst == nil {
+			diffs = append(diffs, LegacyDiff{ID: id, Field: "missing", Source: src.Issue.Title})
+			continue
+		}
+		s, d := src.Issue, dst.Issue
+		add := func(field, a, b string) {
+			if a != b {
+				diffs = append(diffs, LegacyDiff{ID: id, Field: field, Source: a, Target: b})
+			}
+		}
+		add("title", s.Title, d.Title)
+		add("status", s.Status, d.Status)
+		add("priority", strconv.Itoa(s.Priority), strconv.Itoa(d.Priority))
+		add("assignee", s.Assignee, d.Assignee)
+		if s.Type != "" && s.Type != d.Type && !HasLabel(d, "gt:"+s.Type) {
+			add("type", s.Type, d.Type)
+		}
+		add("labels", legacyLabels(s.Labels, ""), legacyLabels(d.Labels, "gt:"+s.Type))
+		add("relations", legacyRelations(src.Relations), legacyRelations(dst.Relations))
+	}
+	for _, rec := range target {
+		if !seen[rec.Issue.ID] {
+			diffs = append(diffs, LegacyDiff{ID: rec.Issue.ID, Field: "extra", Target: rec.Issue.Title})
+		}
+	}
+	return diffs
+}
+
+func legacyLabels(labels []string, skip string) string {
+	var out []string
+	for _, l := range labels {
+		if l != skip {
+			out = append(out, l)
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+func legacyRelations(rels []FsckDependency) string {
+	out := make([]string, 0, len(rels))
+	for _, r := range rels {
+		out = append(out, r.Type+":"+r.DependsOnID)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+// ExportRecords exports the database with bd export and parses it.
+func (b *Beads) ExportRecords() ([]*LegacyRecord, error) {
+	out, err := b.run("export")
+	if err != nil {
+		return nil, fmt.Errorf("exporting beads: %w", err)
+	}
+	return ParseLegacyJSONL(bytes.NewReader(out))
+}
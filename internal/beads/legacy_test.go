@@ -0,0 +1,112 @@
+package beads
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const legacyJSONL = `{"id":"gt-ep","title":"Epic","status":"open","priority":1,"issue_type":"epic"}
+{"id":"gt-ep.1","title":"Child","status":"in_progress","priority":2,"issue_type":"task","labels":["infra"],"dependencies":[{"issue_id":"gt-ep.1","depends_on_id":"gt-b","type":"blocks"},{"issue_id":"gt-ep.1","depends_on_id":"gt-gone","type":"blocks"}]}
+{"id":"gt-b","title":"Blocker","status":"closed","priority":0,"issue_type":"bug","depends_on":["gt-ep"],"dependencies":[{"id":"gt-ep.1","dependency_type":"related"}]}
+`
+
+func TestParseLegacyJSONL_AndPlan(t *testing.T) {
+	records, err := ParseLegacyJSONL(strings.NewReader(legacyJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if got := legacyRelations(records[2].Relations); got != "blocks:gt-ep,related:gt-ep.1" {
+		t.Errorf("gt-b relations = %q", got)
+	}
+
+	plan := PlanLegacyImport(records)
+	if len(plan.Inferred) != 1 || plan.Inferred[0].IssueID != "gt-ep.1" || plan.Inferred[0].DependsOnID != "gt-ep" {
+		t.Errorf("Inferred = %+v, want gt-ep.1 → gt-ep", plan.Inferred)
+	}
+	if len(plan.Dangling) != 1 || plan.Dangling[0].DependsOnID != "gt-gone" {
+		t.Errorf("Dangling = %+v, want the gt-gone edge", plan.Dangling)
+	}
+	if got := legacyRelations(records[1].Relations); got != "blocks:gt-b,parent-child:gt-ep" {
+		t.Errorf("gt-ep.1 relations = %q", got)
+	}
+	if plan.RelationCount() != 4 {
+		t.Errorf("RelationCount = %d, want 4", plan.RelationCount())
+	}
+}
+
+func TestHierarchicalParent(t *testing.T) {
+	for id, want := range map[string]string{
+		"gt-abc.2":   "gt-abc",
+		"gt-abc.2.1": "gt-abc.2",
+		"gt-abc":     "",
+		"gt-v1.x":    "",
+	} {
+		if got := hierarchicalParent(id); got != want {
+			t.Errorf("hierarchicalParent(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+// fakeLegacyImporter records typed dependencies on top of fakeImporter.
+type fakeLegacyImporter struct {
+	*fakeImporter
+	typed []string
+}
+
+func (f *fakeLegacyImporter) AddTypedDependency(issue, dependsOn, depType string) error {
+	f.typed = append(f.typed, issue+"-"+depType+"->"+dependsOn)
+	return nil
+}
+
+func TestImportLegacy_KeepsIDsAndRelations(t *testing.T) {
+	records, _ := ParseLegacyJSONL(strings.NewReader(legacyJSONL))
+	plan := PlanLegacyImport(records)
+	f := &fakeLegacyImporter{fakeImporter: newFakeImporter()}
+
+	actions := ImportLegacy(f, plan)
+	for _, a := range actions {
+		if a.Action != "created" || a.ID != a.SourceID {
+			t.Errorf("action = %+v, want created with the source ID", a)
+		}
+	}
+	if f.issues["gt-ep.1"].Parent != "gt-ep" {
+		t.Errorf("gt-ep.1 parent = %q, want gt-ep", f.issues["gt-ep.1"].Parent)
+	}
+	sort.Strings(f.deps)
+	if want := []string{"gt-b->gt-ep", "gt-ep.1->gt-b"}; !reflect.DeepEqual(f.deps, want) {
+		t.Errorf("deps = %v, want %v", f.deps, want)
+	}
+	if want := []string{"gt-b-related->gt-ep.1"}; !reflect.DeepEqual(f.typed, want) {
+		t.Errorf("typed deps = %v, want %v", f.typed, want)
+	}
+}
+
+func TestDiffLegacy(t *testing.T) {
+	records, _ := ParseLegacyJSONL(strings.NewReader(legacyJSONL))
+	plan := PlanLegacyImport(records)
+
+	// Target as bd export would show it: types as gt: labels, one status
+	// drifted, one issue missing, one unexpected.
+	target, err := ParseLegacyJSONL(strings.NewReader(`{"id":"gt-ep","title":"Epic","status":"open","priority":1,"issue_type":"task","labels":["gt:epic"]}
+{"id":"gt-ep.1","title":"Child","status":"open","priority":2,"issue_type":"task","labels":["infra","gt:task"],"dependencies":[{"issue_id":"gt-ep.1","depends_on_id":"gt-b","type":"blocks"},{"issue_id":"gt-ep.1","depends_on_id":"gt-ep","type":"parent-child"}]}
+{"id":"gt-x","title":"Stray","status":"open","priority":2}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs := DiffLegacy(plan, target)
+	var got []string
+	for _, d := range diffs {
+		got = append(got, d.ID+" "+d.Field)
+	}
+	want := []string{"gt-ep.1 status", "gt-b missing", "gt-x extra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ListQueryFunc runs a read-only SQL query against the Dolt server holding
+// the beads of beadsDir and returns its `dolt sql -r json` output.
+type ListQueryFunc func(beadsDir, query string) ([]byte, error)
+
+var (
+	listQueryMu sync.RWMutex
+	listQuery   ListQueryFunc
+)
+
+// RegisterListQuery installs the function List uses to answer queries in
+// SQL on a dolt-server backend. The doltserver package registers it; beads
+// cannot import doltserver, which imports beads.
+func RegisterListQuery(fn ListQueryFunc) {
+	listQueryMu.Lock()
+	defer listQueryMu.Unlock()
+	listQuery = fn
+}
+
+func registeredListQuery() ListQueryFunc {
+	listQueryMu.RLock()
+	defer listQueryMu.RUnlock()
+	return listQuery
+}
+
+// label returns the single label filter, honoring the deprecated Type.
+func (o ListOptions) label() string {
+	if o.Label == "" && o.Type != "" {
+		return "gt:" + o.Type
+	}
+	return o.Label
+}
+
+// defaultStatus reports whether o selects everything not closed, deleted
+// or tombstoned.
+func (o ListOptions) defaultStatus() bool {
+	return len(o.Statuses) == 0 && o.Status == ""
+}
+
+// Match reports whether issue passes every filter of o except Convoy,
+// which needs the convoy's dependencies, and Limit.
+func (o ListOptions) Match(issue *Issue) bool {
+	if len(o.Statuses) > 0 {
+		if !slices.Contains(o.Statuses, issue.Status) {
+			return false
+		}
+	} else {
+		switch o.Status {
+		case "all":
+		case "":
+			if issue.Status == "closed" || issue.Status == "tombstone" || issue.Status == StatusDeleted {
+				return false
+			}
+		default:
+			if issue.Status != o.Status {
+				return false
+			}
+		}
+	}
+	if label := o.label(); label != "" && !HasLabel(issue, label) {
+		return false
+	}
+	if len(o.Types) > 0 && !slices.ContainsFunc(o.Types, func(t string) bool { return HasLabel(issue, "gt:"+t) }) {
+		return false
+	}
+	if len(o.Priorities) > 0 && !slices.Contains(o.Priorities, issue.Priority) {
+		return false
+	}
+	if o.Parent != "" && issue.Parent != o.Parent {
+		return false
+	}
+	if o.Assignee != "" && issue.Assignee != o.Assignee {
+		return false
+	}
+	if o.NoAssignee && issue.Assignee != "" {
+		return false
+	}
+	if !o.UpdatedSince.IsZero() {
+		updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+		if err != nil || updated.Before(o.UpdatedSince) {
+			return false
+		}
+	}
+	if o.Text != "" {
+		text := strings.ToLower(o.Text)
+		if !strings.Contains(strings.ToLower(issue.Title), text) &&
+			!strings.Contains(strings.ToLower(issue.Description), text) {
+			return false
+		}
+	}
+	return true
+}
+
+// bdListArgs returns the bd list arguments for o. exact is false when some
+// filter has no bd flag, so the output is a superset that must go through
+// Match; Limit is then left to the caller.
+func (o ListOptions) bdListArgs() (args []string, exact bool) {
+	args = []string{"list", "--json"}
+	exact = true
+
+	switch {
+	case len(o.Statuses) == 1:
+		args = append(args, "--status="+o.Statuses[0])
+	case len(o.Statuses) > 1:
+		args = append(args, "--status=all")
+		exact = false
+	case o.Status != "":
+		args = append(args, "--status="+o.Status)
+	}
+
+	label := o.label()
+	if label != "" {
+		args = append(args, "--label="+label)
+	}
+	switch {
+	case len(o.Types) == 1 && label == "":
+		args = append(args, "--label=gt:"+o.Types[0])
+	case len(o.Types) > 0:
+		exact = false
+	}
+
+	switch {
+	case len(o.Priorities) == 1:
+		args = append(args, fmt.Sprintf("--priority=%d", o.Priorities[0]))
+	case len(o.Priorities) > 1:
+		exact = false
+	}
+
+	if o.Parent != "" {
+		args = append(args, "--parent="+o.Parent)
+	}
+	if o.Assignee != "" {
+		args = append(args, "--assignee="+o.Assignee)
+	}
+	if o.NoAssignee {
+		args = append(args, "--no-assignee")
+	}
+	if !o.UpdatedSince.IsZero() || o.Text != "" {
+		exact = false
+	}
+
+	// bd lists trashed beads as open, so List drops them itself and bd
+	// must not cut the list short first.
+	if o.Limit > 0 && exact && o.Convoy == "" && !o.defaultStatus() {
+		args = append(args, fmt.Sprintf("--limit=%d", o.Limit))
+	} else {
+		// Override bd's default limit of 50 to avoid silent truncation
+		args = append(args, "--limit=0")
+	}
+	return args, exact
+}
+
+// listSQL returns a query selecting the issues of database db that match o,
+// except for Convoy. Limit is applied only without a convoy filter.
+func (o ListOptions) listSQL(db string) string {
+	t := func(table string) string { return "`" + strings.ReplaceAll(db, "`", "``") + "`." + table }
+
+	var where []string
+	switch {
+	case len(o.Statuses) > 0:
+		where = append(where, "i.status IN ("+sqlStringList(o.Statuses)+")")
+	case o.Status == "all":
+	case o.Status == "":
+		where = append(where, "i.status NOT IN ('closed', 'tombstone', "+sqlQuote(StatusDeleted)+")")
+	default:
+		where = append(where, "i.status = "+sqlQuote(o.Status))
+	}
+	if label := o.label(); label != "" {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM %s l WHERE l.issue_id = i.id AND l.label = %s)",
+			t("labels"), sqlQuote(label)))
+	}
+	if len(o.Types) > 0 {
+		labels := make([]string, len(o.Types))
+		for i, typ := range o.Types {
+			labels[i] = "gt:" + typ
+		}
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM %s l WHERE l.issue_id = i.id AND l.label IN (%s))",
+			t("labels"), sqlStringList(labels)))
+	}
+	if len(o.Priorities) > 0 {
+		ps := make([]string, len(o.Priorities))
+		for i, p := range o.Priorities {
+			ps[i] = strconv.Itoa(p)
+		}
+		where = append(where, "i.priority IN ("+strings.Join(ps, ", ")+")")
+	}
+	if o.Parent != "" {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM %s d WHERE d.issue_id = i.id AND d.type = 'parent-child' AND d.depends_on_id = %s)",
+			t("dependencies"), sqlQuote(o.Parent)))
+	}
+	if o.Assignee != "" {
+		where = append(where, "i.assignee = "+sqlQuote(o.Assignee))
+	}
+	if o.NoAssignee {
+		where = append(where, "(i.assignee IS NULL OR i.assignee = '')")
+	}
+	if !o.UpdatedSince.IsZero() {
+		where = append(where, "i.updated_at >= "+sqlQuote(o.UpdatedSince.UTC().Format("2006-01-02 15:04:05")))
+	}
+	if o.Text != "" {
+		like := sqlQuote("%" + sqlLikeEscape(strings.ToLower(o.Text)) + "%")
+		where = append(where, fmt.Sprintf("(LOWER(i.title) LIKE %s OR LOWER(i.description) LIKE %s)", like, like))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT i.id, i.title, i.description, i.status, i.priority, i.issue_type, i.assignee, "+
+		"i.created_at, i.created_by, i.updated_at, i.closed_at, i.ephemeral, "+
+		"(SELECT GROUP_CONCAT(l.label SEPARATOR '\\n') FROM %s l WHERE l.issue_id = i.id) AS labels, "+
+		"(SELECT d.depends_on_id FROM %s d WHERE d.issue_id = i.id AND d.type = 'parent-child' LIMIT 1) AS parent "+
+		"FROM %s i", t("labels"), t("dependencies"), t("issues"))
+	if len(where) > 0 {
+		sb.WriteString(" WHERE " + strings.Join(where, " AND "))
+	}
+	sb.WriteString(" ORDER BY i.priority, i.created_at DESC")
+	if o.Limit > 0 && o.Convoy == "" {
+		fmt.Fprintf(&sb, " LIMIT %d", o.Limit)
+	}
+	return sb.String()
+}
+
+// listViaSQL answers o with a SQL query when the beads live on a Dolt
+// server and a query function is registered. ok is false when the caller
+// should use bd instead. Issues carry their columns, labels and parent, but
+// not the dependency details bd list adds, so only queries bd cannot
+// express exactly come here.
+func (b *Beads) listViaSQL(o ListOptions) (issues []*Issue, ok bool) {
+	query := registeredListQuery()
+	if query == nil {
+		return nil, false
+	}
+	beadsDir := b.getResolvedBeadsDir()
+	if DetectBackend(beadsDir) != "dolt-server" {
+		return nil, false
+	}
+	db := doltDatabase(beadsDir)
+	if db == "" {
+		return nil, false
+	}
+	out, err := query(beadsDir, o.listSQL(db))
+	if err != nil {
+		return nil, false
+	}
+	issues, err = parseSQLIssues(out)
+	if err != nil {
+		return nil, false
+	}
+	return issues, true
+}
+
+// doltDatabase returns the dolt_database named in beadsDir's metadata.json.
+func doltDatabase(beadsDir string) string {
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ""
+	}
+	var meta struct {
+		DoltDatabase string `json:"dolt_database"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return ""
+	}
+	return meta.DoltDatabase
+}
+
+// parseSQLIssues decodes `dolt sql -r json` output of a listSQL query.
+// Dolt leaves NULL columns out of a row.
+func parseSQLIssues(output []byte) ([]*Issue, error) {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("parsing dolt sql output: %w", err)
+	}
+	issues := make([]*Issue, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		issue := &Issue{
+			ID:          sqlRowString(row, "id"),
+			Title:       sqlRowString(row, "title"),
+			Description: sqlRowString(row, "description"),
+			Status:      sqlRowString(row, "status"),
+			Priority:    int(sqlRowInt(row, "priority")),
+			Type:        sqlRowString(row, "issue_type"),
+			Assignee:    sqlRowString(row, "assignee"),
+			CreatedAt:   sqlRowTime(row, "created_at"),
+			CreatedBy:   sqlRowString(row, "created_by"),
+			UpdatedAt:   sqlRowTime(row, "updated_at"),
+			ClosedAt:    sqlRowTime(row, "closed_at"),
+			Parent:      sqlRowString(row, "parent"),
+			Ephemeral:   sqlRowInt(row, "ephemeral") != 0,
+		}
+		if labels := sqlRowString(row, "labels"); labels != "" {
+			issue.Labels = strings.Split(labels, "\n")
+			sort.Strings(issue.Labels)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func sqlRowString(row map[string]any, col string) string {
+	switch v := row[col].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+func sqlRowInt(row map[string]any, col string) int64 {
+	switch v := row[col].(type) {
+	case float64:
+		return int64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// sqlRowTime converts a DATETIME column to the RFC 3339 form bd prints.
+func sqlRowTime(row map[string]any, col string) string {
+	s := sqlRowString(row, col)
+	if s == "" {
+		return ""
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return s
+}
+
+// sqlQuote quotes a MySQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''") + "'"
+}
+
+func sqlStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = sqlQuote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sqlLikeEscape escapes LIKE wildcards so s matches literally.
+func sqlLikeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,219 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListOptionsMatch(t *testing.T) {
+	issue := &Issue{
+		ID: "gt-1", Title: "Fix merge race", Description: "Seen in the refinery",
+		Status: "in_progress", Priority: 0, Labels: []string{"gt:merge-request"},
+		Assignee: "gastown/crew/max", UpdatedAt: "2026-03-01T12:00:00Z",
+	}
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts ListOptions
+		want bool
+	}{
+		{"zero value keeps P0", ListOptions{}, true},
+		{"statuses", ListOptions{Statuses: []string{"open", "in_progress"}}, true},
+		{"statuses miss", ListOptions{Statuses: []string{"open", "blocked"}}, false},
+		{"types", ListOptions{Types: []string{"task", "merge-request"}}, true},
+		{"types miss", ListOptions{Types: []string{"task", "epic"}}, false},
+		{"priorities", ListOptions{Priorities: []int{0, 1}}, true},
+		{"priorities miss", ListOptions{Priorities: []int{2, 3}}, false},
+		{"updated since", ListOptions{UpdatedSince: since}, true},
+		{"updated since miss", ListOptions{UpdatedSince: since.Add(24 * time.Hour)}, false},
+		{"text in title", ListOptions{Text: "MERGE"}, true},
+		{"text in description", ListOptions{Text: "refinery"}, true},
+		{"text miss", ListOptions{Text: "polecat"}, false},
+		{"combined", ListOptions{Label: "gt:merge-request", Assignee: "gastown/crew/max", Priorities: []int{0}}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.Match(issue); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	trashed := &Issue{ID: "gt-2", Status: StatusDeleted}
+	if (ListOptions{}).Match(trashed) {
+		t.Error("zero value matches a trashed bead")
+	}
+	if !(ListOptions{Status: StatusDeleted}).Match(trashed) || !(ListOptions{Status: "all"}).Match(trashed) {
+		t.Error("explicit status misses a trashed bead")
+	}
+}
+
+func TestListOptionsBdListArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ListOptions
+		wantArgs  []string
+		wantExact bool
+	}{
+		{"zero value", ListOptions{},
+			[]string{"list", "--json", "--limit=0"}, true},
+		{"single values become flags", ListOptions{Statuses: []string{"open"}, Types: []string{"epic"}, Priorities: []int{0}, Limit: 5},
+			[]string{"list", "--json", "--status=open", "--label=gt:epic", "--priority=0", "--limit=5"}, true},
+		{"several statuses", ListOptions{Statuses: []string{"open", "closed"}, Limit: 5},
+			[]string{"list", "--json", "--status=all", "--limit=0"}, false},
+		{"text", ListOptions{Label: "gt:task", Text: "race"},
+			[]string{"list", "--json", "--label=gt:task", "--limit=0"}, false},
+		{"convoy limits after filtering", ListOptions{Convoy: "hq-cv-1", Limit: 5},
+			[]string{"list", "--json", "--limit=0"}, true},
+	}
+	for _, tt := range tests {
+		args, exact := tt.opts.bdListArgs()
+		if !slices.Equal(args, tt.wantArgs) || exact != tt.wantExact {
+			t.Errorf("%s: got %v exact=%v, want %v exact=%v", tt.name, args, exact, tt.wantArgs, tt.wantExact)
+		}
+	}
+}
+
+func TestListOptionsListSQL(t *testing.T) {
+	opts := ListOptions{
+		Statuses:     []string{"open", "in_progress"},
+		Types:        []string{"merge-request"},
+		Priorities:   []int{0, 1},
+		NoAssignee:   true,
+		UpdatedSince: time.Date(2026, 3, 1, 8, 30, 0, 0, time.FixedZone("PST", -8*3600)),
+		Text:         "it's 100%",
+		Limit:        10,
+	}
+	query := opts.listSQL("gastown")
+	for _, want := range []string{
+		"FROM `gastown`.issues i WHERE ",
+		"i.status IN ('open', 'in_progress')",
+		"l.label IN ('gt:merge-request')",
+		"i.priority IN (0, 1)",
+		"(i.assignee IS NULL OR i.assignee = '')",
+		"i.updated_at >= '2026-03-01 16:30:00'",
+		`LOWER(i.title) LIKE '%it''s 100\\%%'`,
+		" LIMIT 10",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+
+	if q := (ListOptions{}).listSQL("hq"); !strings.Contains(q, "i.status NOT IN ('closed', 'tombstone', 'deleted')") || strings.Contains(q, "priority IN") {
+		t.Errorf("zero value query = %s", q)
+	}
+	if q := (ListOptions{Convoy: "hq-cv-1", Limit: 3}).listSQL("hq"); strings.Contains(q, "LIMIT 3") {
+		t.Errorf("convoy query limited before filtering: %s", q)
+	}
+}
+
+func TestParseSQLIssues(t *testing.T) {
+	output := `{"rows": [
+		{"id": "gt-1", "title": "MR", "status": "open", "priority": 1, "issue_type": "task",
+		 "created_at": "2026-03-01 08:00:00", "updated_at": "2026-03-02 09:30:00.123", "ephemeral": 1,
+		 "labels": "gt:merge-request\nbranch:x", "parent": "gt-epic"},
+		{"id": "gt-2", "title": "Plain", "status": "closed", "priority": 0, "closed_at": "2026-03-03 10:00:00"}
+	]}`
+	issues, err := parseSQLIssues([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	mr := issues[0]
+	if mr.Priority != 1 || !mr.Ephemeral || mr.Parent != "gt-epic" || mr.Assignee != "" {
+		t.Errorf("issue = %+v", mr)
+	}
+	if mr.UpdatedAt != "2026-03-02T09:30:00Z" || mr.CreatedAt != "2026-03-01T08:00:00Z" {
+		t.Errorf("times = %q, %q; want RFC 3339", mr.CreatedAt, mr.UpdatedAt)
+	}
+	if !slices.Equal(mr.Labels, []string{"branch:x", "gt:merge-request"}) {
+		t.Errorf("labels = %v", mr.Labels)
+	}
+	if issues[1].ClosedAt != "2026-03-03T10:00:00Z" || issues[1].Labels != nil {
+		t.Errorf("issue = %+v", issues[1])
+	}
+
+	if issues, err := parseSQLIssues(nil); err != nil || issues != nil {
+		t.Errorf("empty output = %v, %v", issues, err)
+	}
+}
+
+func TestListUsesRegisteredQuery(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	meta := `{"backend": "dolt", "dolt_mode": "server", "dolt_database": "gastown"}`
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(meta), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotDir, gotQuery string
+	RegisterListQuery(func(dir, query string) ([]byte, error) {
+		gotDir, gotQuery = dir, query
+		return []byte(`{"rows": [{"id": "gt-1", "status": "open", "priority": 0}]}`), nil
+	})
+	t.Cleanup(func() { RegisterListQuery(nil) })
+
+	b := NewWithBeadsDir(t.TempDir(), beadsDir)
+	issues, err := b.List(ListOptions{Priorities: []int{0, 1}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "gt-1" {
+		t.Errorf("issues = %+v", issues)
+	}
+	if gotDir != beadsDir || !strings.Contains(gotQuery, "FROM `gastown`.issues") {
+		t.Errorf("query(%q, %q)", gotDir, gotQuery)
+	}
+}
+
+func TestListOmitsTrashedByDefault(t *testing.T) {
+	// bd lists trashed beads alongside open ones unless asked for a status.
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+*--status=deleted*) echo '[{"id":"gt-trash","status":"deleted"}]' ;;
+*) echo '[{"id":"gt-open","status":"open"},{"id":"gt-trash","status":"deleted"}]' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	b := NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+
+	ids := func(opts ListOptions) []string {
+		t.Helper()
+		issues, err := b.List(opts)
+		if err != nil {
+			t.Fatalf("List(%+v): %v", opts, err)
+		}
+		var ids []string
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	if got := ids(ListOptions{}); !slices.Equal(got, []string{"gt-open"}) {
+		t.Errorf("default List = %v, want [gt-open]", got)
+	}
+	if got := ids(ListOptions{Limit: 1}); !slices.Equal(got, []string{"gt-open"}) {
+		t.Errorf("limited List = %v, want [gt-open]", got)
+	}
+	if got := ids(ListOptions{Status: StatusDeleted}); !slices.Equal(got, []string{"gt-trash"}) {
+		t.Errorf("deleted List = %v, want [gt-trash]", got)
+	}
+	if got := ids(ListOptions{Status: "all"}); !slices.Contains(got, "gt-trash") {
+		t.Errorf("all List = %v, want gt-trash included", got)
+	}
+}
@@ -0,0 +1,200 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// A bead mirror is a read-only, static copy of a rig's open beads, for
+// stakeholders without gt: beads.json for tools and index.html for people.
+// It is sanitized: only open work items are published, Gas Town's own
+// beads (agents, mail, merge requests, ...), wisps and sensitive beads are
+// left out, internal gt: labels are dropped, and sealed values are masked.
+
+// Mirror output files.
+const (
+	MirrorJSONFile = "beads.json"
+	MirrorHTMLFile = "index.html"
+)
+
+// mirrorMask replaces sealed values in published descriptions.
+const mirrorMask = "[redacted]"
+
+// mirrorStatuses are the statuses a mirror publishes.
+var mirrorStatuses = map[string]bool{
+	"open":        true,
+	"in_progress": true,
+	"blocked":     true,
+	"deferred":    true,
+	StatusHooked:  true,
+}
+
+// Mirror is the published content of a bead mirror.
+type Mirror struct {
+	Rig         string       `json:"rig"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Beads       []MirrorBead `json:"beads"`
+}
+
+// MirrorBead is a published bead.
+type MirrorBead struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Type        string   `json:"type"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Parent      string   `json:"parent,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// MirrorOptions controls what a mirror publishes.
+type MirrorOptions struct {
+	IncludeDescriptions bool
+	ExcludeLabels       []string
+}
+
+// BuildMirror selects and sanitizes the beads of a mirror, ordered by
+// priority, then most recently updated.
+func BuildMirror(rig string, issues []*Issue, opts MirrorOptions, now time.Time) *Mirror {
+	m := &Mirror{Rig: rig, GeneratedAt: now.UTC(), Beads: []MirrorBead{}}
+	for _, issue := range issues {
+		if !mirrored(issue, opts) {
+			continue
+		}
+		b := MirrorBead{
+			ID:        issue.ID,
+			Title:     MaskSealed(issue.Title, mirrorMask),
+			Status:    issue.Status,
+			Priority:  issue.Priority,
+			Type:      issue.Type,
+			Assignee:  issue.Assignee,
+			Parent:    issue.Parent,
+			CreatedAt: issue.CreatedAt,
+			UpdatedAt: issue.UpdatedAt,
+		}
+		for _, l := range issue.Labels {
+			if !strings.HasPrefix(l, "gt:") {
+				b.Labels = append(b.Labels, l)
+			}
+		}
+		if opts.IncludeDescriptions {
+			b.Description = MaskSealed(issue.Description, mirrorMask)
+		}
+		m.Beads = append(m.Beads, b)
+	}
+	sort.SliceStable(m.Beads, func(i, j int) bool {
+		if m.Beads[i].Priority != m.Beads[j].Priority {
+			return m.Beads[i].Priority < m.Beads[j].Priority
+		}
+		return m.Beads[i].UpdatedAt > m.Beads[j].UpdatedAt
+	})
+	return m
+}
+
+// mirrored reports whether issue belongs in a mirror.
+func mirrored(issue *Issue, opts MirrorOptions) bool {
+	if !mirrorStatuses[issue.Status] || issue.Ephemeral {
+		return false
+	}
+	// Sealed description fields are masked; a sensitive bead is left out
+	// altogether, since even its title may say too much.
+	if HasLabel(issue, SensitiveLabel) || issue.Title == SensitiveTitle || strings.HasPrefix(issue.Description, sealedBeadPrefix) {
+		return false
+	}
+	for _, t := range constants.BeadsCustomTypesList() {
+		if issue.Type == t || HasLabel(issue, "gt:"+t) {
+			return false
+		}
+	}
+	for _, l := range opts.ExcludeLabels {
+		if HasLabel(issue, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteMirror writes m to dir as MirrorJSONFile and MirrorHTMLFile. Each
+// file is replaced atomically, so a web server never serves half of one.
+func WriteMirror(dir string, m *Mirror) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating mirror directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	var page bytes.Buffer
+	if err := mirrorPage.Execute(&page, m); err != nil {
+		return fmt.Errorf("rendering mirror page: %w", err)
+	}
+	if err := writeMirrorFile(filepath.Join(dir, MirrorJSONFile), append(data, '\n')); err != nil {
+		return err
+	}
+	return writeMirrorFile(filepath.Join(dir, MirrorHTMLFile), page.Bytes())
+}
+
+func writeMirrorFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: published for reading
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+var mirrorPage = template.Must(template.New("mirror").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Rig}} backlog</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.4em 0.6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { background: #f4f4f4; }
+code { font-size: 0.9em; }
+.label { display: inline-block; background: #eef; border-radius: 3px; padding: 0 0.4em; margin-right: 0.3em; font-size: 0.85em; }
+.meta { color: #777; }
+details { margin-top: 0.3em; }
+pre { white-space: pre-wrap; margin: 0.3em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Rig}} backlog</h1>
+<p class="meta">{{len .Beads}} open bead(s) &middot; generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}} &middot; read-only mirror, also as <a href="beads.json">JSON</a></p>
+<table>
+<tr><th>ID</th><th>P</th><th>Type</th><th>Status</th><th>Title</th><th>Assignee</th><th>Updated</th></tr>
+{{- range .Beads}}
+<tr id="{{.ID}}">
+<td><code>{{.ID}}</code></td>
+<td>P{{.Priority}}</td>
+<td>{{.Type}}</td>
+<td>{{.Status}}</td>
+<td>{{.Title}}{{range .Labels}} <span class="label">{{.}}</span>{{end}}
+{{- if .Parent}}<div class="meta">parent <a href="#{{.Parent}}">{{.Parent}}</a></div>{{end}}
+{{- if .Description}}<details><summary>Description</summary><pre>{{.Description}}</pre></details>{{end}}</td>
+<td>{{.Assignee}}</td>
+<td class="meta">{{.UpdatedAt}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
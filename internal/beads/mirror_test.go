@@ -0,0 +1,84 @@
+package beads
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMirror(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issues := []*Issue{
+		{ID: "gt-low", Title: "Polish docs", Status: "open", Priority: 3, Type: "task", UpdatedAt: "2026-02-01T00:00:00Z"},
+		{ID: "gt-bug", Title: "Crash on start", Status: "in_progress", Priority: 1, Type: "bug", Assignee: "gastown/polecats/nux",
+			Labels: []string{"gt:triaged", "backend"}, Description: "Stack trace\napi_key: gt:sealed:v1:0123abcd:c2VjcmV0", UpdatedAt: "2026-02-10T00:00:00Z"},
+		{ID: "gt-new", Title: "Newer P1", Status: "open", Priority: 1, Type: "feature", UpdatedAt: "2026-02-20T00:00:00Z"},
+		{ID: "gt-done", Title: "Done", Status: "closed", Type: "task"},
+		{ID: "gt-trash", Title: "Trashed", Status: StatusDeleted, Type: "task"},
+		{ID: "gt-pin", Title: "Pinned", Status: StatusPinned, Type: "task"},
+		{ID: "gt-agent", Title: "Agent", Status: "open", Type: "task", Labels: []string{"gt:agent"}},
+		{ID: "gt-mr", Title: "Merge", Status: "open", Type: "merge-request"},
+		{ID: "gt-wisp", Title: "Wisp", Status: "open", Type: "task", Ephemeral: true},
+		{ID: "gt-secret", Title: SensitiveTitle, Status: "open", Type: "bug", Labels: []string{SensitiveLabel}, Description: sealedBeadPrefix + "x"},
+		{ID: "gt-private", Title: "Internal only", Status: "open", Type: "task", Labels: []string{"internal"}},
+	}
+
+	m := BuildMirror("gastown", issues, MirrorOptions{ExcludeLabels: []string{"internal"}}, now)
+	var ids []string
+	for _, b := range m.Beads {
+		ids = append(ids, b.ID)
+	}
+	if got := strings.Join(ids, ","); got != "gt-new,gt-bug,gt-low" {
+		t.Fatalf("mirrored %s, want gt-new,gt-bug,gt-low", got)
+	}
+	bug := m.Beads[1]
+	if len(bug.Labels) != 1 || bug.Labels[0] != "backend" {
+		t.Errorf("labels = %v, want internal gt: labels dropped", bug.Labels)
+	}
+	if bug.Description != "" {
+		t.Errorf("description published without include_descriptions: %q", bug.Description)
+	}
+
+	m = BuildMirror("gastown", issues, MirrorOptions{IncludeDescriptions: true}, now)
+	for _, b := range m.Beads {
+		if b.ID == "gt-bug" && b.Description != "Stack trace\napi_key: [redacted]" {
+			t.Errorf("description = %q, want sealed value masked", b.Description)
+		}
+	}
+}
+
+func TestWriteMirror(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "public")
+	m := BuildMirror("gastown", []*Issue{
+		{ID: "gt-xss", Title: `<script>alert(1)</script>`, Status: "open", Priority: 2, Type: "bug"},
+	}, MirrorOptions{}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err := WriteMirror(dir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, MirrorJSONFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Mirror
+	if err := json.Unmarshal(data, &got); err != nil || got.Rig != "gastown" || len(got.Beads) != 1 {
+		t.Fatalf("beads.json = %+v, %v", got, err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, MirrorHTMLFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), "<script>") {
+		t.Error("index.html does not escape bead titles")
+	}
+	if !strings.Contains(string(page), "gt-xss") {
+		t.Error("index.html is missing the bead")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
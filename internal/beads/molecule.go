@@ -274,9 +274,8 @@ func (b *Beads) InstantiateMolecule(mol *Issue, parent *Issue, opts InstantiateO
 
 	// FORMAT BRIDGE: Try new format first (child issues), fall back to old format (markdown)
 	templateChildren, err := b.List(ListOptions{
-		Parent:   mol.ID,
-		Status:   "all",
-		Priority: -1,
+		Parent: mol.ID,
+		Status: "all",
 	})
 	if err != nil {
 		// Non-fatal - might not have children, continue to old format
@@ -0,0 +1,306 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultEstimate is assumed for a task without an estimate.
+const DefaultEstimate = time.Hour
+
+// PlanTask is one open child of an epic, scheduled as if every ready task
+// had a worker. Times are minutes from now.
+type PlanTask struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Priority  int      `json:"priority"`
+	Order     int      `json:"order"`            // Position in the suggested dispatch order, from 1
+	Wave      int      `json:"wave"`             // Tasks of one wave can run in parallel once earlier waves land
+	Estimate  int      `json:"estimate_minutes"` // DefaultEstimate when Estimated is false
+	Estimated bool     `json:"estimated"`
+	Start     int      `json:"start_minutes"` // Earliest start
+	Finish    int      `json:"finish_minutes"`
+	Slack     int      `json:"slack_minutes"` // How far it can slip without delaying the epic
+	Critical  bool     `json:"critical"`
+	Ready     bool     `json:"ready"`                // Open with nothing to wait for: dispatch now
+	DependsOn []string `json:"depends_on,omitempty"` // Open tasks of the epic it waits for
+	BlockedBy []string `json:"blocked_by,omitempty"` // Open beads outside the epic it waits for
+}
+
+// EpicPlan is the critical path and suggested dispatch order of an epic's
+// open children.
+type EpicPlan struct {
+	Epic         string     `json:"epic"`
+	Title        string     `json:"title"`
+	Tasks        []PlanTask `json:"tasks"` // In dispatch order
+	Done         []string   `json:"done,omitempty"`
+	CriticalPath []string   `json:"critical_path"`
+	Length       int        `json:"length_minutes"` // Of the critical path
+}
+
+// Ready returns the tasks that can be dispatched now, in dispatch order.
+func (p *EpicPlan) Ready() []PlanTask {
+	var ready []PlanTask
+	for _, t := range p.Tasks {
+		if t.Ready {
+			ready = append(ready, t)
+		}
+	}
+	return ready
+}
+
+// ParseEstimate parses a task estimate: a duration ("90m", "2h30m") or a
+// bare number of hours ("1.5").
+func ParseEstimate(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		hours, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return 0, fmt.Errorf("invalid estimate %q (expected e.g. 90m, 2h or 1.5)", s)
+		}
+		d = time.Duration(hours * float64(time.Hour))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid estimate %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// recordEstimate returns a bead's estimate, from an "estimate: 2h" line in
+// its description or an estimate:2h label.
+func recordEstimate(rec FsckRecord) (time.Duration, bool) {
+	for _, line := range strings.Split(rec.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "estimate") {
+			if d, err := ParseEstimate(value); err == nil {
+				return d, true
+			}
+		}
+	}
+	for _, l := range rec.Labels {
+		if value, ok := strings.CutPrefix(l, "estimate:"); ok {
+			if d, err := ParseEstimate(value); err == nil {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ordersWork reports whether a dependency of type t makes a task wait.
+// Hierarchy does not: children do not wait for their epic.
+func ordersWork(t string) bool {
+	t = depTypeOrDefault(t)
+	if t == "parent-child" {
+		return false
+	}
+	for _, r := range schemaRelations {
+		if r.Name == t {
+			return r.Blocking
+		}
+	}
+	return false
+}
+
+// PlanEpic schedules the open children of epicID from exported records:
+// earliest start and finish of each task given the blocking dependencies
+// between them, the critical path, and a dispatch order that starts the
+// earliest, least slack, highest priority work first. A dependency on an
+// open bead outside the epic holds a task back without delaying the plan.
+func PlanEpic(records []FsckRecord, epicID string) (*EpicPlan, error) {
+	byID := make(map[string]FsckRecord, len(records))
+	for _, rec := range records {
+		byID[rec.ID] = rec
+	}
+	epic, ok := byID[epicID]
+	if !ok {
+		return nil, fmt.Errorf("bead %s not found", epicID)
+	}
+
+	var children []FsckRecord
+	for _, rec := range records {
+		for _, dep := range rec.Dependencies {
+			if dep.Type == "parent-child" && dep.DependsOnID == epicID {
+				children = append(children, rec)
+				break
+			}
+		}
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("%s has no child beads", epicID)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+
+	plan := &EpicPlan{Epic: epicID, Title: epic.Title}
+	tasks := make(map[string]*PlanTask)
+	var ids []string
+	for _, rec := range children {
+		if rec.Status == "closed" {
+			plan.Done = append(plan.Done, rec.ID)
+			continue
+		}
+		est, estimated := recordEstimate(rec)
+		if !estimated {
+			est = DefaultEstimate
+		}
+		minutes := int(est.Round(time.Minute) / time.Minute)
+		if minutes < 1 {
+			minutes = 1
+		}
+		tasks[rec.ID] = &PlanTask{
+			ID:        rec.ID,
+			Title:     rec.Title,
+			Status:    rec.Status,
+			Priority:  rec.Priority,
+			Estimate:  minutes,
+			Estimated: estimated,
+		}
+		ids = append(ids, rec.ID)
+	}
+
+	// Dependencies, and who waits on each task
+	dependents := make(map[string][]string)
+	for _, id := range ids {
+		t := tasks[id]
+		seen := make(map[string]bool)
+		for _, dep := range byID[id].Dependencies {
+			target := dep.DependsOnID
+			if !ordersWork(dep.Type) || target == id || seen[target] {
+				continue
+			}
+			seen[target] = true
+			if tasks[target] != nil {
+				t.DependsOn = append(t.DependsOn, target)
+				dependents[target] = append(dependents[target], id)
+			} else if rec, ok := byID[target]; !ok || rec.Status != "closed" {
+				t.BlockedBy = append(t.BlockedBy, target)
+			}
+		}
+		sort.Strings(t.DependsOn)
+		sort.Strings(t.BlockedBy)
+	}
+
+	// Topological order; what is left over is a cycle
+	indegree := make(map[string]int, len(ids))
+	for _, id := range ids {
+		indegree[id] = len(tasks[id].DependsOn)
+	}
+	var queue, topo []string
+	for _, id := range ids {
+		if indegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		topo = append(topo, id)
+		for _, next := range dependents[id] {
+			if indegree[next]--; indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if len(topo) < len(ids) {
+		var cycle []string
+		for _, id := range ids {
+			if indegree[id] > 0 {
+				cycle = append(cycle, id)
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle among %s", strings.Join(cycle, ", "))
+	}
+
+	// Forward pass: earliest start and finish
+	for _, id := range topo {
+		t := tasks[id]
+		t.Wave = 1
+		for _, d := range t.DependsOn {
+			if f := tasks[d].Finish; f > t.Start {
+				t.Start = f
+			}
+			if w := tasks[d].Wave + 1; w > t.Wave {
+				t.Wave = w
+			}
+		}
+		t.Finish = t.Start + t.Estimate
+		if t.Finish > plan.Length {
+			plan.Length = t.Finish
+		}
+	}
+
+	// Backward pass: slack
+	latestStart := make(map[string]int, len(ids))
+	for i := len(topo) - 1; i >= 0; i-- {
+		t := tasks[topo[i]]
+		latestFinish := plan.Length
+		for _, d := range dependents[t.ID] {
+			if ls := latestStart[d]; ls < latestFinish {
+				latestFinish = ls
+			}
+		}
+		latestStart[t.ID] = latestFinish - t.Estimate
+		t.Slack = latestFinish - t.Finish
+		t.Critical = t.Slack == 0
+		t.Ready = t.Status == "open" && len(t.DependsOn) == 0 && len(t.BlockedBy) == 0
+	}
+
+	// Critical path: back from the critical task that finishes last
+	better := func(a, b *PlanTask) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	}
+	var cur *PlanTask
+	for _, id := range ids {
+		if t := tasks[id]; t.Critical && t.Finish == plan.Length && (cur == nil || better(t, cur)) {
+			cur = t
+		}
+	}
+	for cur != nil {
+		plan.CriticalPath = append([]string{cur.ID}, plan.CriticalPath...)
+		var prev *PlanTask
+		for _, d := range cur.DependsOn {
+			if t := tasks[d]; t.Critical && t.Finish == cur.Start && (prev == nil || better(t, prev)) {
+				prev = t
+			}
+		}
+		cur = prev
+	}
+
+	// Dispatch order: earliest start, then least slack, then priority.
+	// Estimates are positive, so every task comes after its dependencies.
+	for _, id := range ids {
+		plan.Tasks = append(plan.Tasks, *tasks[id])
+	}
+	sort.SliceStable(plan.Tasks, func(i, j int) bool {
+		a, b := plan.Tasks[i], plan.Tasks[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.Slack != b.Slack {
+			return a.Slack < b.Slack
+		}
+		return better(&a, &b)
+	})
+	for i := range plan.Tasks {
+		plan.Tasks[i].Order = i + 1
+	}
+	return plan, nil
+}
+
+// PlanEpic exports the database and schedules an epic's children with
+// PlanEpic.
+func (b *Beads) PlanEpic(epicID string) (*EpicPlan, error) {
+	records, err := b.exportRecords()
+	if err != nil {
+		return nil, err
+	}
+	return PlanEpic(records, epicID)
+}
@@ -0,0 +1,102 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func child(id, status, estimate string, deps ...string) FsckRecord {
+	rec := FsckRecord{ID: id, Title: "Task " + id, Status: status, Description: estimate}
+	rec.Dependencies = append(rec.Dependencies, FsckDependency{IssueID: id, DependsOnID: "gt-epic", Type: "parent-child"})
+	for _, d := range deps {
+		rec.Dependencies = append(rec.Dependencies, FsckDependency{IssueID: id, DependsOnID: d, Type: "blocks"})
+	}
+	return rec
+}
+
+func TestPlanEpic(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-epic", Title: "Auth rewrite", Type: "epic", Status: "open"},
+		child("gt-done", "closed", "estimate: 1h"),
+		child("gt-a", "open", "estimate: 2h", "gt-done"),
+		child("gt-b", "open", "estimate: 30m"),
+		child("gt-c", "open", "estimate: 1h", "gt-a", "gt-b"),
+		child("gt-d", "open", "", "gt-b"), // no estimate: DefaultEstimate
+		child("gt-e", "open", "estimate: 1h", "gt-ext"),
+		{ID: "gt-ext", Status: "in_progress"},
+	}
+
+	plan, err := PlanEpic(records, "gt-epic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Title != "Auth rewrite" || len(plan.Done) != 1 || plan.Done[0] != "gt-done" {
+		t.Errorf("plan = %+v", plan)
+	}
+	if plan.Length != 180 || strings.Join(plan.CriticalPath, " ") != "gt-a gt-c" {
+		t.Errorf("length %d, critical path %v; want 180, [gt-a gt-c]", plan.Length, plan.CriticalPath)
+	}
+
+	var order []string
+	tasks := make(map[string]PlanTask)
+	for _, task := range plan.Tasks {
+		order = append(order, task.ID)
+		tasks[task.ID] = task
+	}
+	// Zero-slack gt-a goes first; gt-d and gt-c wait on earlier work.
+	if got := strings.Join(order, " "); got != "gt-a gt-b gt-e gt-d gt-c" {
+		t.Errorf("dispatch order = %s", got)
+	}
+	if d := tasks["gt-d"]; d.Estimated || d.Estimate != int(DefaultEstimate/time.Minute) || d.Start != 30 || d.Slack != 90 || d.Wave != 2 {
+		t.Errorf("gt-d = %+v", d)
+	}
+	if c := tasks["gt-c"]; c.Start != 120 || !c.Critical || c.Ready || c.Wave != 2 {
+		t.Errorf("gt-c = %+v", c)
+	}
+	if e := tasks["gt-e"]; e.Ready || len(e.BlockedBy) != 1 || e.BlockedBy[0] != "gt-ext" {
+		t.Errorf("gt-e = %+v", e)
+	}
+
+	var ready []string
+	for _, task := range plan.Ready() {
+		ready = append(ready, task.ID)
+	}
+	if got := strings.Join(ready, " "); got != "gt-a gt-b" {
+		t.Errorf("ready = %s", got)
+	}
+}
+
+func TestPlanEpicErrors(t *testing.T) {
+	records := []FsckRecord{
+		{ID: "gt-epic", Status: "open"},
+		{ID: "gt-lonely", Status: "open"},
+		child("gt-a", "open", "", "gt-b"),
+		child("gt-b", "open", "", "gt-a"),
+	}
+	if _, err := PlanEpic(records, "gt-epic"); err == nil || !strings.Contains(err.Error(), "cycle among gt-a, gt-b") {
+		t.Errorf("cycle: err = %v", err)
+	}
+	if _, err := PlanEpic(records, "gt-lonely"); err == nil {
+		t.Error("planned a bead without children")
+	}
+	if _, err := PlanEpic(records, "gt-nope"); err == nil {
+		t.Error("planned a missing bead")
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	for in, want := range map[string]time.Duration{"90m": 90 * time.Minute, "2h30m": 150 * time.Minute, "1.5": 90 * time.Minute} {
+		if got, err := ParseEstimate(in); err != nil || got != want {
+			t.Errorf("ParseEstimate(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "soon", "-1h", "0"} {
+		if _, err := ParseEstimate(in); err == nil {
+			t.Errorf("ParseEstimate(%q) succeeded", in)
+		}
+	}
+	if d, ok := recordEstimate(FsckRecord{Labels: []string{"estimate:45m"}}); !ok || d != 45*time.Minute {
+		t.Errorf("label estimate = %v, %v", d, ok)
+	}
+}
@@ -0,0 +1,102 @@
+// Package beads provides one-line bead capture.
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// quickAddTypes are the bead types a quick-add line may start with.
+var quickAddTypes = map[string]bool{
+	"bug": true, "feature": true, "task": true, "epic": true, "chore": true,
+}
+
+// QuickAdd is a bead parsed from a single line of text (see ParseQuickAdd).
+type QuickAdd struct {
+	Title    string   `json:"title"`
+	Type     string   `json:"type"`
+	Priority int      `json:"priority"` // -1 = not given
+	Labels   []string `json:"labels,omitempty"`
+	Assignee string   `json:"assignee,omitempty"` // first @mention, unresolved
+	Mentions []string `json:"mentions,omitempty"` // the other @mentions
+}
+
+// ParseQuickAdd parses a one-line bead description:
+//
+//	P1 bug in refinery: test gate hangs on timeout @crew-lead #refinery
+//
+// A P0-P4 token anywhere sets the priority. A bead type (bug, feature,
+// task, epic, chore) as the first word of the title sets the type,
+// defaulting to task; the word stays in the title. #word adds a label, the
+// first @word is the assignee and later ones are mentions. Everything else
+// is the title. Tokens like "#123" that can't be labels stay in the title.
+func ParseQuickAdd(line string) (*QuickAdd, error) {
+	q := &QuickAdd{Type: "task", Priority: -1}
+	var title []string
+	for _, word := range strings.Fields(line) {
+		bare := strings.TrimRight(word, ".,;:!?") // "@joe," or "#ui." mid-sentence
+		switch {
+		case isPriorityToken(word):
+			p, _ := strconv.Atoi(word[1:])
+			if q.Priority >= 0 && q.Priority != p {
+				return nil, fmt.Errorf("conflicting priorities P%d and %s", q.Priority, word)
+			}
+			q.Priority = p
+			continue
+		case len(bare) > 1 && bare[0] == '#' && isQuickAddLabel(bare[1:]):
+			if label := bare[1:]; !containsString(q.Labels, label) {
+				q.Labels = append(q.Labels, label)
+			}
+			continue
+		case len(bare) > 1 && bare[0] == '@':
+			who := bare[1:]
+			if q.Assignee == "" {
+				q.Assignee = who
+			} else if who != q.Assignee && !containsString(q.Mentions, who) {
+				q.Mentions = append(q.Mentions, who)
+			}
+			continue
+		}
+		if t := strings.ToLower(bare); len(title) == 0 && quickAddTypes[t] {
+			q.Type = t
+		}
+		title = append(title, word)
+	}
+
+	q.Title = strings.TrimSpace(strings.Join(title, " "))
+	if q.Title == "" {
+		return nil, fmt.Errorf("no title left after parsing %q", line)
+	}
+	return q, nil
+}
+
+// isPriorityToken reports whether word is P0-P4 (either case).
+func isPriorityToken(word string) bool {
+	return len(word) == 2 && (word[0] == 'P' || word[0] == 'p') && word[1] >= '0' && word[1] <= '4'
+}
+
+// isQuickAddLabel reports whether s can be a label: it has a letter and only
+// letters, digits, '-', '_', ':' and '.'.
+func isQuickAddLabel(s string) bool {
+	hasLetter := false
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case r >= '0' && r <= '9', r == '-', r == '_', r == ':', r == '.':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	tests := []struct {
+		line string
+		want QuickAdd
+	}{
+		{
+			line: "P1 bug in refinery: test gate hangs on timeout @crew-lead #refinery",
+			want: QuickAdd{Title: "bug in refinery: test gate hangs on timeout", Type: "bug", Priority: 1,
+				Labels: []string{"refinery"}, Assignee: "crew-lead"},
+		},
+		{
+			line: "Feature: dark mode #ui #ui @max, @joe @max",
+			want: QuickAdd{Title: "Feature: dark mode", Type: "feature", Priority: -1,
+				Labels: []string{"ui"}, Assignee: "max", Mentions: []string{"joe"}},
+		},
+		{
+			line: "fix the bug from #123 p0",
+			want: QuickAdd{Title: "fix the bug from #123", Type: "task", Priority: 0},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuickAdd(tt.line)
+		if err != nil {
+			t.Errorf("ParseQuickAdd(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ParseQuickAdd(%q)\n got  %+v\n want %+v", tt.line, *got, tt.want)
+		}
+	}
+}
+
+func TestParseQuickAdd_Errors(t *testing.T) {
+	for _, line := range []string{"P1 #ops @joe", "P1 P2 two priorities", ""} {
+		if _, err := ParseQuickAdd(line); err == nil {
+			t.Errorf("ParseQuickAdd(%q) succeeded, want error", line)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package beads
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/ratelimit"
+)
+
+// writeCommands are the bd commands that always write.
+var writeCommands = map[string]bool{
+	"create":  true,
+	"update":  true,
+	"close":   true,
+	"reopen":  true,
+	"delete":  true,
+	"comment": true,
+	"import":  true,
+}
+
+// groupCommands are the bd commands whose subcommand decides: they write
+// unless it is one of readSubcommands.
+var groupCommands = map[string]bool{
+	"dep":        true,
+	"label":      true,
+	"slot":       true,
+	"config":     true,
+	"merge-slot": true,
+	"agent":      true,
+	"comments":   true,
+}
+
+var readSubcommands = map[string]bool{
+	"get":   true,
+	"list":  true,
+	"show":  true,
+	"check": true,
+	"tree":  true,
+}
+
+// isWriteCommand reports whether a bd command line writes to the database.
+func isWriteCommand(args []string) bool {
+	var words []string
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if !strings.HasPrefix(a, "-") {
+			words = append(words, a)
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	if writeCommands[words[0]] {
+		return true
+	}
+	if !groupCommands[words[0]] {
+		return false
+	}
+	// "bd comments <id>" lists; "bd comments add <id> ..." writes.
+	return len(words) > 1 && !readSubcommands[words[1]] && (words[0] != "comments" || words[1] == "add")
+}
+
+// waitForWriteQuota holds a write until the actor's rate limit allows it
+// (see the ratelimit package). Tests (isolated mode) and directories
+// outside a town are not limited.
+func (b *Beads) waitForWriteQuota(args []string) error {
+	if b.isolated || !isWriteCommand(args) {
+		return nil
+	}
+	townRoot := b.getTownRoot()
+	if townRoot == "" {
+		return nil
+	}
+	return ratelimit.ForTown(townRoot).Wait(b.getActor())
+}
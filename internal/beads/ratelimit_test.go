@@ -0,0 +1,33 @@
+package beads
+
+import "testing"
+
+func TestIsWriteCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"create", "--json", "--title=x"}, true},
+		{[]string{"update", "gt-1", "--status=open"}, true},
+		{[]string{"close", "gt-1", "-r", "done"}, true},
+		{[]string{"dep", "add", "gt-1", "gt-2"}, true},
+		{[]string{"dep", "list", "gt-1", "--json"}, false},
+		{[]string{"slot", "set", "gt-1", "hook", "gt-2"}, true},
+		{[]string{"slot", "get", "gt-1", "hook"}, false},
+		{[]string{"config", "get", "issue_prefix"}, false},
+		{[]string{"config", "set", "issue_prefix", "gt"}, true},
+		{[]string{"merge-slot", "check", "--json"}, false},
+		{[]string{"merge-slot", "acquire", "--holder=x"}, true},
+		{[]string{"comments", "gt-1", "--json"}, false},
+		{[]string{"comments", "add", "gt-1", "hi"}, true},
+		{[]string{"--allow-stale", "list", "--json"}, false},
+		{[]string{"show", "gt-1"}, false},
+		{[]string{"search", "--", "create"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isWriteCommand(tt.args); got != tt.want {
+			t.Errorf("isWriteCommand(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
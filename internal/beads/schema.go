@@ -0,0 +1,295 @@
+package beads
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// SchemaVersion is bumped when the shape of Schema changes incompatibly.
+const SchemaVersion = 1
+
+// Schema describes the beads a rig can hold: issue fields, types, statuses,
+// relation types, and the structured "key: value" description fields Gas
+// Town and bead templates use. Generate prompts and integrations from it
+// rather than hardcoding lists that drift.
+type Schema struct {
+	Version   int              `json:"version"`
+	Fields    []SchemaField    `json:"fields"`
+	Types     []SchemaType     `json:"types"`
+	Statuses  []SchemaStatus   `json:"statuses"`
+	Relations []SchemaRelation `json:"relations"`
+}
+
+// SchemaField is an issue field, or a description field of a bead type.
+type SchemaField struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type,omitempty"` // string, int, bool, timestamp, []string
+	Description string   `json:"description,omitempty"`
+	Values      []string `json:"values,omitempty"` // Allowed values, when enumerated
+	Source      string   `json:"source,omitempty"` // builtin, gastown, or template:<name>
+}
+
+// SchemaType is an issue type. Fields lists the structured description
+// fields beads of this type carry.
+type SchemaType struct {
+	Name   string        `json:"name"`
+	Source string        `json:"source"` // builtin, gastown, or custom
+	Fields []SchemaField `json:"fields,omitempty"`
+}
+
+// SchemaStatus is an issue status.
+type SchemaStatus struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"` // builtin, gastown, or custom
+	Terminal    bool   `json:"terminal,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SchemaRelation is a dependency type between beads.
+type SchemaRelation struct {
+	Name        string `json:"name"`
+	Blocking    bool   `json:"blocking"` // Affects ready work
+	Description string `json:"description,omitempty"`
+}
+
+// SchemaConfig is the live, per-database part of the schema.
+type SchemaConfig struct {
+	CustomTypes    []string // bd config types.custom
+	CustomStatuses []string // bd config status.custom
+	Templates      []*Template
+}
+
+// Schema sources.
+const (
+	SchemaBuiltin = "builtin"
+	SchemaGastown = "gastown"
+	SchemaCustom  = "custom"
+)
+
+var schemaIssueFields = []SchemaField{
+	{Name: "id", Type: "string", Description: "Bead ID, <prefix>-<hash>"},
+	{Name: "title", Type: "string"},
+	{Name: "description", Type: "string", Description: "Markdown body; may start with structured key: value fields"},
+	{Name: "status", Type: "string"},
+	{Name: "priority", Type: "int", Description: "0 (critical) to 4 (backlog)", Values: []string{"0", "1", "2", "3", "4"}},
+	{Name: "issue_type", Type: "string"},
+	{Name: "assignee", Type: "string", Description: "Agent address, e.g. gastown/polecats/Toast"},
+	{Name: "labels", Type: "[]string"},
+	{Name: "parent", Type: "string"},
+	{Name: "created_at", Type: "timestamp"},
+	{Name: "created_by", Type: "string"},
+	{Name: "updated_at", Type: "timestamp"},
+	{Name: "closed_at", Type: "timestamp"},
+	{Name: "ephemeral", Type: "bool", Description: "Wisp; not synced to git"},
+	{Name: "hook_bead", Type: "string", Description: "Agent beads: work on the agent's hook"},
+	{Name: "agent_state", Type: "string", Description: "Agent beads: lifecycle state", Values: []string{"spawning", "working", "done", "stuck"}},
+}
+
+var schemaBuiltinTypes = []string{"bug", "feature", "task", "epic", "chore"}
+
+// schemaTypeFields are the description fields Gas Town parses per type.
+var schemaTypeFields = map[string][]SchemaField{
+	"merge-request": {
+		{Name: "branch", Type: "string"},
+		{Name: "target", Type: "string"},
+		{Name: "source_issue", Type: "string"},
+		{Name: "worker", Type: "string"},
+		{Name: "rig", Type: "string"},
+		{Name: "merge_commit", Type: "string"},
+		{Name: "close_reason", Type: "string", Values: []string{"merged", "rejected", "conflict", "superseded"}},
+		{Name: "agent_bead", Type: "string"},
+		{Name: "retry_count", Type: "int"},
+		{Name: "last_conflict_sha", Type: "string"},
+		{Name: "conflict_task_id", Type: "string"},
+		{Name: "convoy_id", Type: "string"},
+		{Name: "convoy_created_at", Type: "timestamp"},
+		{Name: "required_checks", Type: "string"},
+		{Name: "check_results", Type: "string"},
+		{Name: "checks_at", Type: "timestamp"},
+		{Name: "dep_update", Type: "string"},
+		{Name: "supersedes", Type: "string"},
+		{Name: "superseded_by", Type: "string"},
+	},
+	"agent": {
+		{Name: "role_type", Type: "string", Values: []string{"mayor", "deacon", "witness", "refinery", "crew", "polecat"}},
+		{Name: "rig", Type: "string"},
+		{Name: "agent_state", Type: "string"},
+		{Name: "hook_bead", Type: "string"},
+		{Name: "cleanup_status", Type: "string", Values: []string{"clean", "has_uncommitted", "has_stash", "has_unpushed"}},
+		{Name: "active_mr", Type: "string"},
+		{Name: "notification_level", Type: "string", Values: []string{NotifyVerbose, NotifyNormal, NotifyMuted}},
+		{Name: "mode", Type: "string"},
+	},
+}
+
+// schemaAttachmentFields may appear on any bead that carries hooked work.
+var schemaAttachmentFields = []SchemaField{
+	{Name: "attached_molecule", Type: "string"},
+	{Name: "attached_at", Type: "timestamp"},
+	{Name: "attached_args", Type: "string"},
+	{Name: "dispatched_by", Type: "string"},
+	{Name: "no_merge", Type: "bool"},
+	{Name: "mode", Type: "string"},
+	{Name: "convoy_id", Type: "string"},
+	{Name: "merge_strategy", Type: "string"},
+}
+
+var schemaStatuses = []SchemaStatus{
+	{Name: "open", Source: SchemaBuiltin},
+	{Name: "in_progress", Source: SchemaBuiltin},
+	{Name: "blocked", Source: SchemaBuiltin},
+	{Name: "deferred", Source: SchemaBuiltin},
+	{Name: "closed", Source: SchemaBuiltin, Terminal: true},
+	{Name: StatusHooked, Source: SchemaGastown, Description: "On an agent's hook"},
+	{Name: StatusPinned, Source: SchemaGastown, Description: "Permanent; never closed"},
+	{Name: StatusDeleted, Source: SchemaGastown, Description: "In the trash; purged after the retention period"},
+	{Name: StatusVerify, Source: SchemaGastown, Description: "Done; awaiting verification evidence before closing"},
+}
+
+var schemaRelations = []SchemaRelation{
+	{Name: "blocks", Blocking: true},
+	{Name: "conditional-blocks", Blocking: true, Description: "Blocks unless the blocker fails"},
+	{Name: "waits-for", Blocking: true, Description: "Gate: waits for an async condition"},
+	{Name: "parent-child", Blocking: true, Description: "Epic or molecule hierarchy"},
+	{Name: "related"},
+	{Name: "discovered-from", Description: "Found while working on another bead"},
+	{Name: "tracks", Description: "Convoy tracks a bead across rigs"},
+}
+
+// BuildSchema combines the built-in schema with a database's custom types
+// and statuses and the fields declared by its templates.
+func BuildSchema(cfg SchemaConfig) *Schema {
+	s := &Schema{
+		Version:   SchemaVersion,
+		Fields:    append([]SchemaField(nil), schemaIssueFields...),
+		Statuses:  append([]SchemaStatus(nil), schemaStatuses...),
+		Relations: append([]SchemaRelation(nil), schemaRelations...),
+	}
+
+	types := make(map[string]*SchemaType)
+	var order []string
+	addType := func(name, source string) {
+		if name == "" || types[name] != nil {
+			return
+		}
+		types[name] = &SchemaType{Name: name, Source: source, Fields: schemaFieldsFor(name)}
+		order = append(order, name)
+	}
+	for _, name := range schemaBuiltinTypes {
+		addType(name, SchemaBuiltin)
+	}
+	for _, name := range constants.BeadsCustomTypesList() {
+		addType(name, SchemaGastown)
+	}
+	for _, name := range cfg.CustomTypes {
+		addType(strings.TrimSpace(name), SchemaCustom)
+	}
+
+	for _, tmpl := range cfg.Templates {
+		addType(tmpl.Type, SchemaCustom)
+		t := types[tmpl.Type]
+		if t == nil {
+			continue
+		}
+		keys := make([]string, 0, len(tmpl.Fields))
+		for k := range tmpl.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !hasSchemaField(t.Fields, k) {
+				t.Fields = append(t.Fields, SchemaField{Name: k, Type: "string", Source: "template:" + tmpl.Name})
+			}
+		}
+	}
+	for _, name := range order {
+		s.Types = append(s.Types, *types[name])
+	}
+
+	for _, name := range cfg.CustomStatuses {
+		name = strings.TrimSpace(name)
+		if name == "" || hasSchemaStatus(s.Statuses, name) {
+			continue
+		}
+		s.Statuses = append(s.Statuses, SchemaStatus{Name: name, Source: SchemaCustom})
+	}
+	return s
+}
+
+// schemaFieldsFor returns the description fields Gas Town defines for a type.
+// Work types (anything that can be hooked) carry the attachment fields.
+func schemaFieldsFor(typeName string) []SchemaField {
+	fields, ok := schemaTypeFields[typeName]
+	if !ok {
+		switch typeName {
+		case "bug", "feature", "task", "epic", "chore", "molecule":
+			fields = schemaAttachmentFields
+		}
+	}
+	out := make([]SchemaField, len(fields))
+	for i, f := range fields {
+		f.Source = SchemaGastown
+		out[i] = f
+	}
+	return out
+}
+
+func hasSchemaField(fields []SchemaField, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSchemaStatus(statuses []SchemaStatus, name string) bool {
+	for _, s := range statuses {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigList reads a comma-separated bd config value, e.g. types.custom.
+// Unset keys return nil.
+func (b *Beads) ConfigList(key string) []string {
+	out, err := b.run("config", "get", key)
+	if err != nil {
+		return nil
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Note:") || strings.Contains(line, "(not set)") {
+			continue
+		}
+		var values []string
+		for _, v := range strings.Split(line, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// ConfigSet sets a bd config value.
+func (b *Beads) ConfigSet(key, value string) error {
+	_, err := b.run("config", "set", key, value)
+	return err
+}
+
+// LoadSchema builds the live schema for the beads database at b, using
+// templates found in templateDirs.
+func (b *Beads) LoadSchema(templateDirs []string) *Schema {
+	templates, _ := ListTemplates(templateDirs)
+	return BuildSchema(SchemaConfig{
+		CustomTypes:    b.ConfigList("types.custom"),
+		CustomStatuses: b.ConfigList("status.custom"),
+		Templates:      templates,
+	})
+}
@@ -0,0 +1,66 @@
+package beads
+
+import "testing"
+
+func TestBuildSchema(t *testing.T) {
+	s := BuildSchema(SchemaConfig{
+		CustomTypes:    []string{"agent", "incident", " "},
+		CustomStatuses: []string{"review", "open"},
+		Templates: []*Template{
+			{Name: "incident", Type: "incident", Fields: map[string]string{"severity": "sev2", "pager": ""}},
+			{Name: "mr", Type: "merge-request", Fields: map[string]string{"branch": "", "reviewer": ""}},
+		},
+	})
+
+	if s.Version != SchemaVersion || len(s.Fields) == 0 || len(s.Relations) == 0 {
+		t.Fatalf("schema = %+v", s)
+	}
+
+	types := make(map[string]SchemaType)
+	for _, typ := range s.Types {
+		if _, dup := types[typ.Name]; dup {
+			t.Errorf("type %q listed twice", typ.Name)
+		}
+		types[typ.Name] = typ
+	}
+	if types["bug"].Source != SchemaBuiltin || types["agent"].Source != SchemaGastown || types["incident"].Source != SchemaCustom {
+		t.Errorf("type sources wrong: bug=%q agent=%q incident=%q",
+			types["bug"].Source, types["agent"].Source, types["incident"].Source)
+	}
+
+	incident := types["incident"].Fields
+	if len(incident) != 2 || incident[0].Name != "pager" || incident[1].Source != "template:incident" {
+		t.Errorf("incident fields = %+v", incident)
+	}
+	var branches, reviewer int
+	for _, f := range types["merge-request"].Fields {
+		switch f.Name {
+		case "branch":
+			branches++
+		case "reviewer":
+			reviewer++
+		}
+	}
+	if branches != 1 || reviewer != 1 {
+		t.Errorf("merge-request fields: branch x%d, reviewer x%d", branches, reviewer)
+	}
+	if !hasSchemaField(types["task"].Fields, "attached_molecule") {
+		t.Error("task should carry attachment fields")
+	}
+
+	var review, opens int
+	for _, st := range s.Statuses {
+		switch st.Name {
+		case "review":
+			review++
+			if st.Source != SchemaCustom {
+				t.Errorf("review source = %q", st.Source)
+			}
+		case "open":
+			opens++
+		}
+	}
+	if review != 1 || opens != 1 {
+		t.Errorf("statuses = %+v", s.Statuses)
+	}
+}
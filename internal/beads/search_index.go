@@ -0,0 +1,455 @@
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SearchIndexFile is the search index 'gt bead indexd' keeps in a beads
+// directory. It holds each bead's words, file references and @mentions,
+// and how far it has read the change feed: bead updated_at times and the
+// activity log (see history.go). Search reads it without asking bd.
+const SearchIndexFile = "search-index.json"
+
+// searchIndexVersion changes when tokenizing does; an index written by
+// another version is rebuilt.
+const searchIndexVersion = 1
+
+// SearchIndexPath returns the search index of a beads directory.
+func SearchIndexPath(beadsDir string) string {
+	return filepath.Join(beadsDir, SearchIndexFile)
+}
+
+// SearchIndex maps words, file references and mentions to beads.
+type SearchIndex struct {
+	Version       int                     `json:"version"`
+	HistoryOffset int64                   `json:"history_offset"` // Bytes of the activity log read
+	Docs          map[string]*IndexedBead `json:"docs"`
+
+	// Postings, built from Docs on load.
+	terms    map[string]map[string]bool
+	files    map[string]map[string]bool
+	mentions map[string]map[string]bool
+}
+
+// IndexedBead is one bead's entry in the index. Body tokens come from the
+// bead itself and are replaced when it changes; comment tokens accumulate
+// from the activity log.
+type IndexedBead struct {
+	Title     string      `json:"title"`
+	Status    string      `json:"status"`
+	UpdatedAt string      `json:"updated_at"`
+	Body      IndexTokens `json:"body"`
+	Comments  IndexTokens `json:"comments,omitempty"`
+}
+
+// IndexTokens is what a piece of bead text contributes to the index.
+type IndexTokens struct {
+	Terms    []string `json:"terms,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// IndexStats reports what an Update changed.
+type IndexStats struct {
+	Indexed  int // Beads added or re-tokenized
+	Removed  int // Beads no longer in the database
+	Comments int // Comments read from the activity log
+}
+
+// Changed reports whether the update touched the index.
+func (s IndexStats) Changed() bool {
+	return s.Indexed > 0 || s.Removed > 0 || s.Comments > 0
+}
+
+// NewSearchIndex returns an empty index.
+func NewSearchIndex() *SearchIndex {
+	ix := &SearchIndex{Version: searchIndexVersion, Docs: make(map[string]*IndexedBead)}
+	ix.buildPostings()
+	return ix
+}
+
+// LoadSearchIndex reads the index at path. A missing index, or one written
+// by another version, returns os.ErrNotExist.
+func LoadSearchIndex(path string) (*SearchIndex, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var ix SearchIndex
+	if err := json.Unmarshal(data, &ix); err != nil {
+		return nil, fmt.Errorf("parsing search index: %w", err)
+	}
+	if ix.Version != searchIndexVersion {
+		return nil, os.ErrNotExist
+	}
+	if ix.Docs == nil {
+		ix.Docs = make(map[string]*IndexedBead)
+	}
+	ix.buildPostings()
+	return &ix, nil
+}
+
+// Save atomically writes the index to path.
+func (ix *SearchIndex) Save(path string) error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".search-index-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // no-op after rename
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Update brings the index up to date with issues, the database's current
+// beads, and the activity log at historyPath from where the last Update
+// stopped. Only beads whose updated_at changed are re-tokenized.
+func (ix *SearchIndex) Update(issues []*Issue, historyPath string) (IndexStats, error) {
+	var stats IndexStats
+	live := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		live[issue.ID] = true
+		doc := ix.Docs[issue.ID]
+		if doc != nil && doc.UpdatedAt == issue.UpdatedAt && doc.UpdatedAt != "" {
+			continue
+		}
+		if doc == nil {
+			doc = &IndexedBead{}
+			ix.Docs[issue.ID] = doc
+		}
+		doc.Title, doc.Status, doc.UpdatedAt = issue.Title, issue.Status, issue.UpdatedAt
+		doc.Body = beadTokens(issue)
+		stats.Indexed++
+	}
+	for id := range ix.Docs {
+		if !live[id] {
+			delete(ix.Docs, id)
+			stats.Removed++
+		}
+	}
+
+	n, err := ix.readHistory(historyPath)
+	stats.Comments = n
+	ix.buildPostings()
+	return stats, err
+}
+
+// readHistory adds the comments appended to the activity log since the
+// last read. A log shorter than the saved offset was replaced, and is read
+// from the start.
+func (ix *SearchIndex) readHistory(path string) (int, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			ix.HistoryOffset = 0
+			return 0, nil
+		}
+		return 0, fmt.Errorf("opening history log: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size() < ix.HistoryOffset {
+		ix.HistoryOffset = 0
+	}
+	if _, err := f.Seek(ix.HistoryOffset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	comments := 0
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// A line without its newline is still being written; read it next time.
+			if err == io.EOF {
+				return comments, nil
+			}
+			return comments, fmt.Errorf("reading history log: %w", err)
+		}
+		ix.HistoryOffset += int64(len(line))
+
+		var e HistoryEntry
+		if json.Unmarshal(line, &e) != nil || e.Kind != HistoryComment {
+			continue
+		}
+		doc := ix.Docs[e.IssueID]
+		if doc == nil {
+			continue // not in this database (yet); its comments are lost until --rebuild
+		}
+		t := textTokens(e.Value)
+		doc.Comments.Terms = mergeSorted(doc.Comments.Terms, t.Terms)
+		doc.Comments.Files = mergeSorted(doc.Comments.Files, t.Files)
+		doc.Comments.Mentions = mergeSorted(doc.Comments.Mentions, t.Mentions)
+		comments++
+	}
+}
+
+func (ix *SearchIndex) buildPostings() {
+	ix.terms = make(map[string]map[string]bool)
+	ix.files = make(map[string]map[string]bool)
+	ix.mentions = make(map[string]map[string]bool)
+	add := func(postings map[string]map[string]bool, keys []string, id string) {
+		for _, k := range keys {
+			if postings[k] == nil {
+				postings[k] = make(map[string]bool)
+			}
+			postings[k][id] = true
+		}
+	}
+	for id, doc := range ix.Docs {
+		for _, t := range []IndexTokens{doc.Body, doc.Comments} {
+			add(ix.terms, t.Terms, id)
+			add(ix.files, t.Files, id)
+			add(ix.mentions, t.Mentions, id)
+		}
+	}
+}
+
+// SearchQuery selects beads from the index. Every given part must match.
+type SearchQuery struct {
+	Text    string // Words; the last may be a prefix
+	File    string // A referenced path, or its trailing components
+	Mention string // An @mentioned worker, with or without the @
+	Status  string // Restrict to a status ("" for any)
+}
+
+// SearchHit is a bead matching a query.
+type SearchHit struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Search returns the beads matching q, most recently updated first.
+func (ix *SearchIndex) Search(q SearchQuery) []SearchHit {
+	var sets []map[string]bool
+	words := indexWords(q.Text)
+	for i, w := range words {
+		if i == len(words)-1 {
+			sets = append(sets, prefixPostings(ix.terms, w))
+		} else {
+			sets = append(sets, ix.terms[w])
+		}
+	}
+	if q.File != "" {
+		want := strings.TrimPrefix(q.File, "./")
+		matched := make(map[string]bool)
+		for f, ids := range ix.files {
+			if f == want || strings.HasSuffix(f, "/"+want) {
+				for id := range ids {
+					matched[id] = true
+				}
+			}
+		}
+		sets = append(sets, matched)
+	}
+	if q.Mention != "" {
+		sets = append(sets, ix.mentions[strings.ToLower(strings.TrimPrefix(q.Mention, "@"))])
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	var hits []SearchHit
+	for id := range sets[0] {
+		ok := true
+		for _, s := range sets[1:] {
+			if !s[id] {
+				ok = false
+				break
+			}
+		}
+		doc := ix.Docs[id]
+		if !ok || doc == nil || (q.Status != "" && doc.Status != q.Status) {
+			continue
+		}
+		hits = append(hits, SearchHit{ID: id, Title: doc.Title, Status: doc.Status, UpdatedAt: doc.UpdatedAt})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].UpdatedAt != hits[j].UpdatedAt {
+			return hits[i].UpdatedAt > hits[j].UpdatedAt
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
+
+func prefixPostings(postings map[string]map[string]bool, prefix string) map[string]bool {
+	matched := make(map[string]bool)
+	for term, ids := range postings {
+		if strings.HasPrefix(term, prefix) {
+			for id := range ids {
+				matched[id] = true
+			}
+		}
+	}
+	return matched
+}
+
+// beadTokens tokenizes a bead's title, description and labels. Sealed
+// beads contribute only their labels: their content is ciphertext.
+func beadTokens(issue *Issue) IndexTokens {
+	text := issue.Title + "\n" + issue.Description
+	if HasLabel(issue, SensitiveLabel) {
+		text = ""
+	}
+	t := textTokens(text)
+	for _, l := range issue.Labels {
+		t.Terms = mergeSorted(t.Terms, indexWords(l))
+	}
+	return t
+}
+
+var (
+	indexWordRe = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+	// A path with a directory, or a bare file name with a known extension,
+	// optionally followed by :line. URLs don't match: the path must start
+	// the word.
+	indexFileRe = regexp.MustCompile("(?:^|[\\s\"'(`\\[])(\\.?/?(?:[\\w.-]+/)*[\\w-][\\w.-]*\\.[A-Za-z][A-Za-z0-9]{0,4})(?::\\d+)?")
+
+	// An @mention, or the "mentions:" line 'gt bead quickadd' writes.
+	indexMentionRe     = regexp.MustCompile(`(?:^|[\s(])@([\w][\w./-]*)`)
+	indexMentionLineRe = regexp.MustCompile(`(?m)^mentions: (.+)$`)
+)
+
+// indexFileExts are the extensions a bare file name (no directory) needs
+// to count as a file reference, so "e.g." and version numbers don't.
+var indexFileExts = map[string]bool{
+	"go": true, "md": true, "json": true, "jsonl": true, "yaml": true, "yml": true, "toml": true,
+	"sh": true, "py": true, "ts": true, "tsx": true, "js": true, "rs": true, "sql": true, "txt": true,
+	"mod": true, "sum": true, "html": true, "css": true, "proto": true,
+}
+
+// textTokens extracts the words, file references and mentions in s.
+func textTokens(s string) IndexTokens {
+	var t IndexTokens
+	t.Terms = mergeSorted(nil, indexWords(s))
+
+	var files []string
+	for _, m := range indexFileRe.FindAllStringSubmatch(s, -1) {
+		path := strings.TrimPrefix(strings.TrimRight(m[1], "."), "./")
+		ext := path[strings.LastIndex(path, ".")+1:]
+		if strings.Contains(path, "/") || indexFileExts[strings.ToLower(ext)] {
+			files = append(files, path)
+		}
+	}
+	t.Files = mergeSorted(nil, files)
+
+	var mentions []string
+	for _, m := range indexMentionRe.FindAllStringSubmatch(s, -1) {
+		mentions = append(mentions, strings.ToLower(strings.TrimRight(m[1], "./")))
+	}
+	for _, m := range indexMentionLineRe.FindAllStringSubmatch(s, -1) {
+		for _, who := range strings.Split(m[1], ",") {
+			if who = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(who), "@")); who != "" {
+				mentions = append(mentions, strings.ToLower(who))
+			}
+		}
+	}
+	t.Mentions = mergeSorted(nil, mentions)
+	return t
+}
+
+// indexWords lowercases s and splits it into words of two or more characters.
+func indexWords(s string) []string {
+	var words []string
+	for _, w := range indexWordRe.FindAllString(strings.ToLower(s), -1) {
+		if len([]rune(w)) >= 2 {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// mergeSorted returns the sorted, distinct union of a (already sorted and
+// distinct) and b.
+func mergeSorted(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// UpdateSearchIndex brings the search index of b's database up to date,
+// creating it if needed; rebuild discards the existing index first. An
+// index with nothing to update is only touched, so its modification time
+// always tells when it was last brought up to date.
+func (b *Beads) UpdateSearchIndex(rebuild bool) (*SearchIndex, IndexStats, error) {
+	beadsDir := b.getResolvedBeadsDir()
+	path := SearchIndexPath(beadsDir)
+	ix, err := LoadSearchIndex(path)
+	fresh := rebuild || err != nil
+	if fresh {
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: rebuilding search index: %v\n", err)
+		}
+		ix = NewSearchIndex()
+	}
+
+	issues, err := b.List(ListOptions{Status: "all"})
+	if err != nil {
+		return nil, IndexStats{}, fmt.Errorf("listing beads: %w", err)
+	}
+	stats, err := ix.Update(issues, HistoryPath(beadsDir))
+	if err != nil {
+		return nil, stats, err
+	}
+	if !fresh && !stats.Changed() {
+		now := time.Now()
+		return ix, stats, os.Chtimes(path, now, now)
+	}
+	if err := ix.Save(path); err != nil {
+		return nil, stats, fmt.Errorf("saving search index: %w", err)
+	}
+	return ix, stats, nil
+}
+
+// OpenSearchIndex loads the search index of b's database and when it was
+// last brought up to date. A missing index returns os.ErrNotExist.
+func (b *Beads) OpenSearchIndex() (*SearchIndex, time.Time, error) {
+	path := SearchIndexPath(b.getResolvedBeadsDir())
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	ix, err := LoadSearchIndex(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return ix, info.ModTime(), nil
+}
@@ -0,0 +1,110 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func searchIDs(hits []SearchHit) []string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearchIndex_Update(t *testing.T) {
+	dir := t.TempDir()
+	historyPath := HistoryPath(dir)
+	issues := []*Issue{
+		{ID: "gt-a", Title: "Refinery crashes on empty queue", Status: "open", UpdatedAt: "2026-01-02T00:00:00Z",
+			Description: "Panic in internal/refinery/engineer.go:412 when the queue drains.\n\nmentions: gastown/crew/joe"},
+		{ID: "gt-b", Title: "Dark mode for the dashboard", Status: "closed", UpdatedAt: "2026-01-03T00:00:00Z",
+			Description: "See https://example.com/theme.css and @mayor, e.g. the login page."},
+		{ID: "gt-c", Title: "[sensitive]", Status: "open", UpdatedAt: "2026-01-01T00:00:00Z",
+			Description: "refinery secret", Labels: []string{SensitiveLabel}},
+	}
+
+	ix := NewSearchIndex()
+	stats, err := ix.Update(issues, historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Indexed != 3 {
+		t.Errorf("Indexed = %d, want 3", stats.Indexed)
+	}
+
+	tests := []struct {
+		name string
+		q    SearchQuery
+		want []string
+	}{
+		{"words", SearchQuery{Text: "refinery queue"}, []string{"gt-a"}},
+		{"prefix", SearchQuery{Text: "dash"}, []string{"gt-b"}},
+		{"file suffix", SearchQuery{File: "engineer.go"}, []string{"gt-a"}},
+		{"file path", SearchQuery{File: "./internal/refinery/engineer.go"}, []string{"gt-a"}},
+		{"url is not a file", SearchQuery{File: "theme.css"}, nil},
+		{"mention line", SearchQuery{Mention: "gastown/crew/joe"}, []string{"gt-a"}},
+		{"at mention", SearchQuery{Mention: "@mayor"}, []string{"gt-b"}},
+		{"status", SearchQuery{Text: "dark", Status: "open"}, nil},
+		{"sealed content", SearchQuery{Text: "secret"}, nil},
+		{"sealed label", SearchQuery{Text: "sensitive"}, []string{"gt-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchIDs(ix.Search(tt.q)); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%+v) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+
+	// Only changed beads are re-read; comments come from the activity log.
+	b := &Beads{beadsDir: dir}
+	if err := b.RecordHistory(
+		HistoryEntry{IssueID: "gt-b", Kind: HistoryComment, Value: "Reopening: flaky in cmd/theme_test.go"},
+		HistoryEntry{IssueID: "gt-b", Kind: HistoryStatus, Value: "open"},
+	); err != nil {
+		t.Fatal(err)
+	}
+	issues[1] = &Issue{ID: "gt-b", Title: "Dark mode for the dashboard", Status: "open", UpdatedAt: "2026-01-04T00:00:00Z"}
+	issues = issues[:2]
+	stats, err = ix.Update(issues, historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (IndexStats{Indexed: 1, Removed: 1, Comments: 1}) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if got := searchIDs(ix.Search(SearchQuery{Text: "flaky", File: "theme_test.go", Status: "open"})); !slices.Equal(got, []string{"gt-b"}) {
+		t.Errorf("comment search = %v", got)
+	}
+	if got := searchIDs(ix.Search(SearchQuery{Text: "refinery"})); !slices.Equal(got, []string{"gt-a"}) {
+		t.Errorf("removed bead still found: %v", got)
+	}
+
+	// A saved index picks up where it stopped.
+	path := SearchIndexPath(dir)
+	if err := ix.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSearchIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := loaded.Update(issues, historyPath); stats.Changed() {
+		t.Errorf("reloaded index changed: %+v", stats)
+	}
+	if got := searchIDs(loaded.Search(SearchQuery{Text: "flaky"})); !slices.Equal(got, []string{"gt-b"}) {
+		t.Errorf("reloaded search = %v", got)
+	}
+
+	// A replaced (shorter) log is read from the start.
+	if err := os.WriteFile(historyPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.Update(issues, filepath.Join(dir, HistoryFile)); err != nil || loaded.HistoryOffset != 0 {
+		t.Errorf("offset after truncation = %d, err %v", loaded.HistoryOffset, err)
+	}
+}
@@ -0,0 +1,290 @@
+package beads
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Sensitive beads (e.g., security incidents tracked alongside normal work)
+// keep their content encrypted at rest: sealed values are stored in the
+// bead as tokens that only a holder of the town's bead key can open. A
+// whole bead can be sealed, replacing its title with SensitiveTitle, or
+// just chosen "key: value" fields of its description.
+//
+// Dolt keeps every earlier version of a bead, so content written in the
+// clear stays in its history after sealing; seal new content as it is
+// written (SealValue) to keep it out entirely.
+
+// SensitiveLabel marks a bead with sealed content.
+const SensitiveLabel = "gt:sensitive"
+
+// SensitiveTitle replaces the title of a wholly sealed bead.
+const SensitiveTitle = "[sensitive]"
+
+// SensitiveKeyEnv names the environment variable holding the bead key.
+const SensitiveKeyEnv = "GT_BEADS_KEY"
+
+// Sealed token prefixes: a single value, or a whole bead (title and
+// description).
+const (
+	sealedValuePrefix = "gt:sealed:v1:"
+	sealedBeadPrefix  = "gt:sealed-bead:v1:"
+)
+
+// sealedAAD binds ciphertext to this scheme.
+var sealedAAD = []byte("gastown sealed v1")
+
+var sealedToken = regexp.MustCompile(`gt:sealed(?:-bead)?:v1:[0-9a-f]{8}:[A-Za-z0-9_-]+`)
+
+// ErrNoSensitiveKey is returned when sealed content is opened without a key.
+var ErrNoSensitiveKey = errors.New(SensitiveKeyEnv + " is not set (generate one with: gt bead keygen)")
+
+// SealKey is the town's bead encryption key.
+type SealKey struct {
+	key []byte
+	id  string // first 8 hex digits of the key's SHA-256, stored in tokens
+}
+
+// GenerateSealKey returns a new random key in the form SensitiveKeyEnv holds.
+func GenerateSealKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseSealKey parses a base64 256-bit key.
+func ParseSealKey(s string) (*SealKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be a base64 256-bit key (generate one with: gt bead keygen)", SensitiveKeyEnv)
+	}
+	sum := sha256.Sum256(key)
+	return &SealKey{key: key, id: hex.EncodeToString(sum[:4])}, nil
+}
+
+// LoadSealKey reads the key from SensitiveKeyEnv.
+func LoadSealKey() (*SealKey, error) {
+	s := os.Getenv(SensitiveKeyEnv)
+	if s == "" {
+		return nil, ErrNoSensitiveKey
+	}
+	return ParseSealKey(s)
+}
+
+func (k *SealKey) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func (k *SealKey) seal(prefix string, plaintext []byte) (string, error) {
+	gcm, err := k.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, sealedAAD)
+	return prefix + k.id + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a sealed token, returning its prefix and plaintext.
+func (k *SealKey) open(token string) (prefix string, plaintext []byte, err error) {
+	switch {
+	case strings.HasPrefix(token, sealedBeadPrefix):
+		prefix = sealedBeadPrefix
+	case strings.HasPrefix(token, sealedValuePrefix):
+		prefix = sealedValuePrefix
+	default:
+		return "", nil, fmt.Errorf("not a sealed value")
+	}
+	keyID, data, ok := strings.Cut(strings.TrimPrefix(token, prefix), ":")
+	if !ok {
+		return "", nil, fmt.Errorf("malformed sealed value")
+	}
+	if keyID != k.id {
+		return "", nil, fmt.Errorf("sealed with key %s, but %s is key %s", keyID, SensitiveKeyEnv, k.id)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("malformed sealed value: %w", err)
+	}
+	gcm, err := k.gcm()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", nil, fmt.Errorf("malformed sealed value")
+	}
+	plaintext, err = gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], sealedAAD)
+	if err != nil {
+		return "", nil, fmt.Errorf("decrypting sealed value: corrupted")
+	}
+	return prefix, plaintext, nil
+}
+
+// sealedBead is the plaintext of a whole-bead token.
+type sealedBead struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// IsSealed reports whether s contains sealed content.
+func IsSealed(s string) bool {
+	return sealedToken.MatchString(s)
+}
+
+// SealBead seals a bead's title and description into a single token,
+// returning SensitiveTitle and the token as the new title and description.
+func SealBead(title, description string, k *SealKey) (newTitle, newDescription string, err error) {
+	if strings.HasPrefix(description, sealedBeadPrefix) {
+		return "", "", fmt.Errorf("bead is already sealed")
+	}
+	payload, err := json.Marshal(sealedBead{Title: title, Description: description})
+	if err != nil {
+		return "", "", err
+	}
+	token, err := k.seal(sealedBeadPrefix, payload)
+	if err != nil {
+		return "", "", err
+	}
+	return SensitiveTitle, token, nil
+}
+
+// SealFields seals the values of the named "key: value" description
+// fields (matched case-insensitively), returning the new description and
+// the names of the fields sealed. Values already sealed are left alone.
+func SealFields(description string, fields []string, k *SealKey) (string, []string, error) {
+	want := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		want[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+	var sealed []string
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		name := strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || !want[name] || value == "" || IsSealed(value) {
+			continue
+		}
+		token, err := k.seal(sealedValuePrefix, []byte(value))
+		if err != nil {
+			return "", nil, err
+		}
+		lines[i] = key + ": " + token
+		sealed = append(sealed, strings.TrimSpace(key))
+	}
+	return strings.Join(lines, "\n"), sealed, nil
+}
+
+// SealValue seals an arbitrary value, such as a description that should
+// never be stored in the clear.
+func SealValue(value string, k *SealKey) (string, error) {
+	return k.seal(sealedValuePrefix, []byte(value))
+}
+
+// MaskSealed replaces each sealed token in text with mask.
+func MaskSealed(text, mask string) string {
+	return sealedToken.ReplaceAllLiteralString(text, mask)
+}
+
+// UnsealText replaces each sealed token in text with its plaintext. A
+// whole-bead token yields its description; the bead's real title is
+// returned separately ("" when text has no whole-bead token).
+func UnsealText(text string, k *SealKey) (string, string, error) {
+	var title string
+	var firstErr error
+	out := sealedToken.ReplaceAllStringFunc(text, func(token string) string {
+		prefix, plaintext, err := k.open(token)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return token
+		}
+		if prefix != sealedBeadPrefix {
+			return string(plaintext)
+		}
+		var bead sealedBead
+		if err := json.Unmarshal(plaintext, &bead); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("decoding sealed bead: %w", err)
+			}
+			return token
+		}
+		title = bead.Title
+		return bead.Description
+	})
+	if firstErr != nil {
+		return "", "", firstErr
+	}
+	return out, title, nil
+}
+
+// Seal encrypts an issue's content in place and labels it SensitiveLabel:
+// the named description fields, or with none the whole bead.
+func (b *Beads) Seal(id string, fields []string, k *SealKey) ([]string, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	opts := UpdateOptions{AddLabels: []string{SensitiveLabel}}
+	var sealed []string
+	if len(fields) == 0 {
+		title, description, err := SealBead(issue.Title, issue.Description, k)
+		if err != nil {
+			return nil, err
+		}
+		opts.Title, opts.Description = &title, &description
+		sealed = []string{"title", "description"}
+	} else {
+		description, names, err := SealFields(issue.Description, fields, k)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s has no unsealed %s field", id, strings.Join(fields, ", "))
+		}
+		opts.Description = &description
+		sealed = names
+	}
+	if err := b.Update(id, opts); err != nil {
+		return nil, err
+	}
+	return sealed, nil
+}
+
+// Unseal decrypts an issue's sealed content in place and removes
+// SensitiveLabel.
+func (b *Beads) Unseal(id string, k *SealKey) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	description, title, err := UnsealText(issue.Description, k)
+	if err != nil {
+		return err
+	}
+	opts := UpdateOptions{Description: &description, RemoveLabels: []string{SensitiveLabel}}
+	if title != "" {
+		opts.Title = &title
+	}
+	return b.Update(id, opts)
+}
@@ -0,0 +1,90 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSealKey(t *testing.T) *SealKey {
+	t.Helper()
+	s, err := GenerateSealKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := ParseSealKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestSealBeadRoundTrip(t *testing.T) {
+	k := testSealKey(t)
+	title, desc, err := SealBead("Leaked API token", "Token abc123 found in logs", k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != SensitiveTitle || strings.Contains(desc, "abc123") || !IsSealed(desc) {
+		t.Fatalf("sealed = %q, %q", title, desc)
+	}
+	if _, _, err := SealBead(title, desc, k); err == nil {
+		t.Error("sealing a sealed bead succeeded")
+	}
+
+	shown := "gt-abc: " + title + "\n\nDESCRIPTION\n" + desc + "\n"
+	out, realTitle, err := UnsealText(shown, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if realTitle != "Leaked API token" || !strings.Contains(out, "Token abc123 found in logs") {
+		t.Errorf("unsealed = %q (title %q)", out, realTitle)
+	}
+
+	masked := MaskSealed(shown, "[masked]")
+	if IsSealed(masked) || !strings.Contains(masked, "[masked]") {
+		t.Errorf("masked = %q", masked)
+	}
+}
+
+func TestSealFields(t *testing.T) {
+	k := testSealKey(t)
+	desc := "Incident report\nReporter: alice@example.com\naffected-host: db-3\nseverity: high"
+	out, sealed, err := SealFields(desc, []string{"reporter", "Affected-Host", "missing"}, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sealed) != 2 || sealed[0] != "Reporter" || sealed[1] != "affected-host" {
+		t.Errorf("sealed fields = %v", sealed)
+	}
+	if strings.Contains(out, "alice") || strings.Contains(out, "db-3") || !strings.Contains(out, "severity: high") {
+		t.Errorf("description = %q", out)
+	}
+
+	// Sealing again leaves sealed values alone.
+	if _, again, err := SealFields(out, []string{"reporter"}, k); err != nil || len(again) != 0 {
+		t.Errorf("resealed %v, %v", again, err)
+	}
+
+	plain, title, err := UnsealText(out, k)
+	if err != nil || plain != desc || title != "" {
+		t.Errorf("UnsealText = %q, %q, %v", plain, title, err)
+	}
+}
+
+func TestUnsealWrongKey(t *testing.T) {
+	sealed, err := SealValue("secret", testSealKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := UnsealText(sealed, testSealKey(t)); err == nil || !strings.Contains(err.Error(), "sealed with key") {
+		t.Errorf("wrong key: err = %v", err)
+	}
+
+	if _, err := ParseSealKey("too-short"); err == nil {
+		t.Error("ParseSealKey accepted a bad key")
+	}
+	t.Setenv(SensitiveKeyEnv, "")
+	if _, err := LoadSealKey(); err != ErrNoSensitiveKey {
+		t.Errorf("LoadSealKey without key = %v", err)
+	}
+}
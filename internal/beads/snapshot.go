@@ -0,0 +1,177 @@
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SnapshotExport is the JSONL file bd exports next to its database.
+const SnapshotExport = "issues.jsonl"
+
+// Snapshot is a read-only view of beads loaded from JSONL files.
+//
+// When the Dolt server is unreachable, a few human-facing read commands
+// serve a snapshot instead (see Beads.Snapshot) so humans can still triage.
+// List and Show themselves never fall back. The data
+// is only as fresh as the newest source file, and ephemeral beads (wisps,
+// merge requests) are only present if a caller saved them with
+// WriteSnapshot, since bd leaves them out of its export.
+type Snapshot struct {
+	Sources []string  // Files the snapshot was loaded from
+	TakenAt time.Time // Modification time of the newest source
+	Issues  []*Issue
+}
+
+// snapshotRow is a JSONL row. bd export writes dependencies as
+// {issue_id, depends_on_id, type} edges rather than the IssueDep shape.
+type snapshotRow struct {
+	Issue
+	Dependencies []FsckDependency `json:"dependencies,omitempty"`
+}
+
+// LoadSnapshot reads issues from the given JSONL files. Missing files are
+// skipped; a later file's copy of an issue replaces an earlier one.
+// Returns an error if none of the files exist.
+func LoadSnapshot(paths ...string) (*Snapshot, error) {
+	s := &Snapshot{}
+	index := make(map[string]int)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		rows, err := readSnapshotFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s.Sources = append(s.Sources, path)
+		if info.ModTime().After(s.TakenAt) {
+			s.TakenAt = info.ModTime()
+		}
+		for _, issue := range rows {
+			if i, ok := index[issue.ID]; ok {
+				s.Issues[i] = issue
+				continue
+			}
+			index[issue.ID] = len(s.Issues)
+			s.Issues = append(s.Issues, issue)
+		}
+	}
+	if len(s.Sources) == 0 {
+		return nil, fmt.Errorf("no snapshot found (looked for %s)", strings.Join(paths, ", "))
+	}
+	return s, nil
+}
+
+func readSnapshotFile(path string) ([]*Issue, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a beads snapshot file
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var issues []*Issue
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var row snapshotRow
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if row.ID == "" {
+			continue
+		}
+		issue := row.Issue
+		for _, dep := range row.Dependencies {
+			switch dep.Type {
+			case "parent-child":
+				if issue.Parent == "" {
+					issue.Parent = dep.DependsOnID
+				}
+			case "", "blocks":
+				issue.DependsOn = appendUnique(issue.DependsOn, dep.DependsOnID)
+			}
+		}
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return issues, nil
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// List returns the snapshot issues matching opts, mirroring bd list
+// filtering. An empty Status excludes closed issues, as bd list does.
+func (s *Snapshot) List(opts ListOptions) []*Issue {
+	var out []*Issue
+	for _, issue := range s.Issues {
+		if !opts.Match(issue) {
+			continue
+		}
+		out = append(out, issue)
+		if opts.Limit > 0 && len(out) == opts.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// Show returns the snapshot copy of an issue, or ErrNotFound.
+func (s *Snapshot) Show(id string) (*Issue, error) {
+	for _, issue := range s.Issues {
+		if issue.ID == id {
+			return issue, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// WriteSnapshot atomically writes issues to path as JSONL, for use as an
+// extra snapshot source (see Beads.Snapshot).
+func WriteSnapshot(path string, issues []*Issue) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // no-op after rename
+
+	enc := json.NewEncoder(tmp)
+	for _, issue := range issues {
+		if err := enc.Encode(issue); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Snapshot loads the stale snapshot for b's database: bd's JSONL export,
+// then any extra files (for ephemeral beads bd leaves out of the export).
+func (b *Beads) Snapshot(extra ...string) (*Snapshot, error) {
+	paths := append([]string{filepath.Join(b.getResolvedBeadsDir(), SnapshotExport)}, extra...)
+	return LoadSnapshot(paths...)
+}
@@ -0,0 +1,111 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSnapshotFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSnapshot_List(t *testing.T) {
+	dir := t.TempDir()
+	export := filepath.Join(dir, "issues.jsonl")
+	writeSnapshotFile(t, export, `{"id":"gt-1","title":"Epic","status":"open","priority":1,"labels":["gt:epic"]}
+{"id":"gt-2","title":"Child","status":"in_progress","priority":2,"assignee":"gastown/crew/max","dependencies":[{"issue_id":"gt-2","depends_on_id":"gt-1","type":"parent-child"},{"issue_id":"gt-2","depends_on_id":"gt-3","type":"blocks"}]}
+{"id":"gt-3","title":"Done","status":"closed","priority":2}
+`)
+	extra := filepath.Join(dir, "mq-snapshot.jsonl")
+	writeSnapshotFile(t, extra, `{"id":"gt-mr1","title":"Merge","status":"open","priority":2,"labels":["gt:merge-request"]}
+{"id":"gt-3","title":"Done (reopened)","status":"open","priority":2}
+`)
+
+	s, err := LoadSnapshot(export, extra, filepath.Join(dir, "missing.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Sources) != 2 || len(s.Issues) != 4 {
+		t.Fatalf("sources=%v issues=%d", s.Sources, len(s.Issues))
+	}
+
+	child, err := s.Show("gt-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.Parent != "gt-1" || len(child.DependsOn) != 1 || child.DependsOn[0] != "gt-3" {
+		t.Errorf("child = %+v", child)
+	}
+	if reopened, _ := s.Show("gt-3"); reopened.Status != "open" {
+		t.Errorf("later source should win, got status %q", reopened.Status)
+	}
+	if _, err := s.Show("gt-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Show(missing) err = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want int
+	}{
+		{"default excludes closed", ListOptions{}, 4},
+		{"status", ListOptions{Status: "in_progress"}, 1},
+		{"label", ListOptions{Label: "gt:merge-request"}, 1},
+		{"type", ListOptions{Type: "epic"}, 1},
+		{"priority", ListOptions{Priorities: []int{2}}, 3},
+		{"parent", ListOptions{Parent: "gt-1"}, 1},
+		{"assignee", ListOptions{Assignee: "gastown/crew/max"}, 1},
+		{"no assignee", ListOptions{NoAssignee: true}, 3},
+		{"limit", ListOptions{Status: "all", Limit: 2}, 2},
+	}
+	for _, tt := range tests {
+		if got := len(s.List(tt.opts)); got != tt.want {
+			t.Errorf("%s: got %d issues, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSnapshot_NoSources(t *testing.T) {
+	if _, err := LoadSnapshot(filepath.Join(t.TempDir(), "issues.jsonl")); err == nil {
+		t.Error("expected error when no snapshot exists")
+	}
+}
+
+func TestWriteSnapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".runtime", "mq-snapshot.jsonl")
+	issues := []*Issue{{ID: "gt-mr1", Status: "open", Labels: []string{"gt:merge-request"}}}
+	if err := WriteSnapshot(path, issues); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Issues) != 1 || s.Issues[0].ID != "gt-mr1" {
+		t.Errorf("round trip = %+v", s.Issues)
+	}
+}
+
+func TestBeadsSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	writeSnapshotFile(t, filepath.Join(beadsDir, "issues.jsonl"), `{"id":"gt-1","status":"open"}`+"\n")
+	extra := filepath.Join(dir, ".runtime", "mq-snapshot.jsonl")
+	writeSnapshotFile(t, extra, `{"id":"gt-mr1","status":"open"}`+"\n")
+
+	s, err := NewWithBeadsDir(dir, beadsDir).Snapshot(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Issues) != 2 || len(s.Sources) != 2 {
+		t.Errorf("snapshot = %d issues from %v, want 2 from both files", len(s.Issues), s.Sources)
+	}
+}
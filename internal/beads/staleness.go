@@ -0,0 +1,250 @@
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// StalenessLogFile is the append-only log, in a rig's beads directory, of
+// every action taken by a staleness policy. It is also what keeps a policy
+// from escalating the same bead twice.
+const StalenessLogFile = "staleness.jsonl"
+
+// StaleMatch is a bead past one of its rig's staleness policies.
+type StaleMatch struct {
+	Issue  *Issue
+	Policy config.StalenessPolicy
+	Age    time.Duration // how long since the bead was created or last updated
+}
+
+// StalenessLogEntry records one action taken by a staleness policy.
+type StalenessLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Rig       string `json:"rig,omitempty"`
+	IssueID   string `json:"issue_id"`
+	Title     string `json:"title,omitempty"`
+	Policy    string `json:"policy"`
+	Action    string `json:"action"`
+	Age       string `json:"age"`             // e.g., "2d4h"
+	Result    string `json:"result"`          // escalation bead ID, or the new status
+	Error     string `json:"error,omitempty"` // set when the action failed
+	Actor     string `json:"actor,omitempty"`
+}
+
+// FindStale returns the beads past a policy's age, for each policy they
+// match, ordered by policy then oldest first. Only work beads (tasks, bugs,
+// features, chores, epics) are considered. Policies whose age doesn't
+// parse are skipped; Validate reports them.
+func FindStale(cfg *config.StalenessConfig, issues []*Issue, now time.Time) []StaleMatch {
+	if cfg == nil {
+		return nil
+	}
+	var matches []StaleMatch
+	for _, p := range cfg.Policies {
+		maxAge, err := p.AgeDuration()
+		if err != nil {
+			continue
+		}
+		var found []StaleMatch
+		for _, issue := range issues {
+			if !stalenessApplies(p, issue) {
+				continue
+			}
+			age, ok := staleAge(p, issue, now)
+			if ok && age > maxAge {
+				found = append(found, StaleMatch{Issue: issue, Policy: p, Age: age})
+			}
+		}
+		sort.SliceStable(found, func(i, j int) bool { return found[i].Age > found[j].Age })
+		matches = append(matches, found...)
+	}
+	return matches
+}
+
+// stalenessApplies reports whether a policy's conditions match a bead.
+func stalenessApplies(p config.StalenessPolicy, issue *Issue) bool {
+	if issue.Ephemeral || !assignableTypes[issueType(issue)] {
+		return false
+	}
+	if issue.Status != p.MatchStatus() {
+		return false
+	}
+	if p.Priority != nil && issue.Priority > *p.Priority {
+		return false
+	}
+	if p.Type != "" && issueType(issue) != p.Type {
+		return false
+	}
+	if p.Label != "" && !containsString(issue.Labels, p.Label) {
+		return false
+	}
+	return true
+}
+
+// staleAge returns how long ago the bead was created, or last updated if
+// the policy measures from updates.
+func staleAge(p config.StalenessPolicy, issue *Issue, now time.Time) (time.Duration, bool) {
+	stamp := issue.CreatedAt
+	if p.Since == "updated" && issue.UpdatedAt != "" {
+		stamp = issue.UpdatedAt
+	}
+	t, err := time.Parse(time.RFC3339, stamp)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(t), true
+}
+
+// EscalationSeverity returns the severity to escalate a bead with: the
+// policy's, else one matching the bead's priority (P0 critical, P1 high,
+// P2 medium, lower low).
+func (m StaleMatch) EscalationSeverity() string {
+	if m.Policy.Severity != "" {
+		return m.Policy.Severity
+	}
+	switch m.Issue.Priority {
+	case 0:
+		return config.SeverityCritical
+	case 1:
+		return config.SeverityHigh
+	case 2:
+		return config.SeverityMedium
+	default:
+		return config.SeverityLow
+	}
+}
+
+// ReopenStale returns a bead that went stale in progress to open and
+// unassigned (dropping any lease), with a comment naming the policy. It
+// returns false if the bead changed since it was matched and no longer
+// applies.
+func (b *Beads) ReopenStale(m StaleMatch, now time.Time) (bool, error) {
+	unlock, err := b.lockBead(m.Issue.ID)
+	if err != nil {
+		return false, fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(m.Issue.ID)
+	if err != nil {
+		return false, err
+	}
+	maxAge, err := m.Policy.AgeDuration()
+	if err != nil {
+		return false, err
+	}
+	if !stalenessApplies(m.Policy, issue) {
+		return false, nil
+	}
+	if age, ok := staleAge(m.Policy, issue, now); !ok || age <= maxAge {
+		return false, nil
+	}
+
+	desc := SetLease(issue, nil)
+	status, assignee := "open", ""
+	if err := b.Update(issue.ID, UpdateOptions{Status: &status, Assignee: &assignee, Description: &desc}); err != nil {
+		return false, fmt.Errorf("reopening %s: %w", issue.ID, err)
+	}
+	from := issue.Status
+	if issue.Assignee != "" {
+		from += " with " + issue.Assignee
+	}
+	_ = b.AddComment(issue.ID, fmt.Sprintf("Stale policy %q (%s): %s for %s; returned to open", m.Policy.Name, m.Policy.Describe(), from, FormatStaleAge(m.Age)))
+	return true, nil
+}
+
+// FormatStaleAge formats an age in days and hours, e.g. "2d4h" or "5h".
+func FormatStaleAge(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d%(24*time.Hour)) / int(time.Hour)
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	if hours == 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	return fmt.Sprintf("%dd%dh", days, hours)
+}
+
+// StalenessLogPath returns the staleness log of a beads directory.
+func StalenessLogPath(beadsDir string) string {
+	return filepath.Join(beadsDir, StalenessLogFile)
+}
+
+// RecordStaleness appends an entry to the staleness log of the beads
+// directory this wrapper operates on, filling in the timestamp and actor.
+func (b *Beads) RecordStaleness(e StalenessLogEntry) error {
+	if e.Timestamp == "" {
+		e.Timestamp = currentTimestamp()
+	}
+	if e.Actor == "" {
+		e.Actor = b.getActor()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling staleness log entry: %w", err)
+	}
+	f, err := os.OpenFile(StalenessLogPath(b.getResolvedBeadsDir()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening staleness log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing staleness log: %w", err)
+	}
+	return nil
+}
+
+// ReadStalenessLog returns the entries in the staleness log of beadsDir,
+// oldest first. A missing log has no entries.
+func ReadStalenessLog(beadsDir string) ([]StalenessLogEntry, error) {
+	f, err := os.Open(StalenessLogPath(beadsDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening staleness log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []StalenessLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e StalenessLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // skip a torn line rather than hide the rest
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading staleness log: %w", err)
+	}
+	return entries, nil
+}
+
+// StalenessEscalated returns the "<issue>\x00<policy>" keys of the beads a
+// policy has already escalated, so a sweep escalates each once. Reopening
+// needs no such record: a reopened bead no longer matches until it is
+// picked up and goes stale again.
+func StalenessEscalated(entries []StalenessLogEntry) map[string]bool {
+	done := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.Action == config.StaleActionEscalate && e.Error == "" {
+			done[StalenessKey(e.IssueID, e.Policy)] = true
+		}
+	}
+	return done
+}
+
+// StalenessKey identifies a bead and policy in StalenessEscalated.
+func StalenessKey(issueID, policy string) string {
+	return issueID + "\x00" + policy
+}
@@ -0,0 +1,85 @@
+package beads
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+	p0 := 0
+	cfg := &config.StalenessConfig{Policies: []config.StalenessPolicy{
+		{Name: "p0-open", Priority: &p0, Age: "2d", Action: config.StaleActionEscalate},
+		{Name: "abandoned", Status: "in_progress", Age: "1w", Since: "updated", Action: config.StaleActionReopen},
+	}}
+	issues := []*Issue{
+		{ID: "gt-old", Status: "open", Priority: 0, Type: "bug", CreatedAt: ago(72 * time.Hour)},
+		{ID: "gt-older", Status: "open", Priority: 0, CreatedAt: ago(96 * time.Hour)},
+		{ID: "gt-young", Status: "open", Priority: 0, CreatedAt: ago(24 * time.Hour)},
+		{ID: "gt-p1", Status: "open", Priority: 1, CreatedAt: ago(96 * time.Hour)},
+		{ID: "gt-mr", Status: "open", Priority: 0, Labels: []string{"gt:merge-request"}, CreatedAt: ago(96 * time.Hour)},
+		{ID: "gt-idle", Status: "in_progress", Priority: 2, CreatedAt: ago(30 * 24 * time.Hour), UpdatedAt: ago(8 * 24 * time.Hour)},
+		{ID: "gt-busy", Status: "in_progress", Priority: 2, CreatedAt: ago(30 * 24 * time.Hour), UpdatedAt: ago(time.Hour)},
+	}
+
+	matches := FindStale(cfg, issues, now)
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Policy.Name+":"+m.Issue.ID)
+	}
+	want := []string{"p0-open:gt-older", "p0-open:gt-old", "abandoned:gt-idle"}
+	if len(got) != len(want) {
+		t.Fatalf("FindStale = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("match %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if s := matches[0].EscalationSeverity(); s != config.SeverityCritical {
+		t.Errorf("P0 severity = %s, want critical", s)
+	}
+	if a := FormatStaleAge(matches[2].Age); a != "8d" {
+		t.Errorf("age = %s, want 8d", a)
+	}
+}
+
+func TestStalenessLog(t *testing.T) {
+	dir := t.TempDir()
+	b := NewWithBeadsDir(dir, dir)
+	t.Setenv("BD_ACTOR", "deacon")
+
+	if entries, err := ReadStalenessLog(dir); err != nil || entries != nil {
+		t.Fatalf("missing log = %v, %v", entries, err)
+	}
+	for _, e := range []StalenessLogEntry{
+		{IssueID: "gt-1", Policy: "p0-open", Action: config.StaleActionEscalate, Result: "hq-9"},
+		{IssueID: "gt-2", Policy: "p0-open", Action: config.StaleActionEscalate, Error: "dolt down"},
+		{IssueID: "gt-3", Policy: "abandoned", Action: config.StaleActionReopen, Result: "open"},
+	} {
+		if err := b.RecordStaleness(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ReadStalenessLog(dir)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("ReadStalenessLog = %+v, %v", entries, err)
+	}
+	if entries[0].Actor != "deacon" || entries[0].Timestamp == "" {
+		t.Errorf("entry missing actor or timestamp: %+v", entries[0])
+	}
+	escalated := StalenessEscalated(entries)
+	if !escalated[StalenessKey("gt-1", "p0-open")] {
+		t.Error("gt-1 not recorded as escalated")
+	}
+	if escalated[StalenessKey("gt-2", "p0-open")] {
+		t.Error("a failed escalation counts as done")
+	}
+	if escalated[StalenessKey("gt-3", "abandoned")] {
+		t.Error("a reopen counts as an escalation")
+	}
+}
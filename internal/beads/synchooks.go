@@ -0,0 +1,167 @@
+package beads
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// SyncHookNames are the git hooks that keep a clone's beads in step with
+// the sync branch: pre-push exports and pushes it, post-merge imports what
+// a pull brought in.
+var SyncHookNames = []string{"pre-push", "post-merge"}
+
+// SyncHooksVersion is bumped when the hook scripts change, so that doctor
+// can flag clones running an older copy.
+const SyncHooksVersion = 1
+
+// syncHookMarker starts the second line of every hook gt installs.
+const syncHookMarker = "# gt-managed beads-sync v"
+
+// SyncHookEnv is set while a hook runs gt, so that the sync branch push
+// it makes does not run the hook again.
+const SyncHookEnv = "GT_BEADS_SYNC_HOOK"
+
+// Sync hook states reported by CheckSyncHooks and InstallSyncHooks.
+const (
+	SyncHookCurrent   = "current"   // Installed at SyncHooksVersion
+	SyncHookMissing   = "missing"   // Not installed
+	SyncHookOutdated  = "outdated"  // Installed at an older version
+	SyncHookInstalled = "installed" // Newly written
+	SyncHookUpdated   = "updated"   // Rewritten from an older version
+	SyncHookChained   = "chained"   // Written; the clone's own hook was kept as <hook>.local
+)
+
+// SyncHookScript returns the script installed as the named hook.
+func SyncHookScript(name string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s%d: installed by 'gt hooks install beads-sync'; edits are overwritten.
+# The clone's own %s hook, if it had one, was kept as %s.local and runs first.
+if [ -x "$0.local" ]; then
+	"$0.local" "$@" || exit $?
+fi
+[ -n "$%s" ] && exit 0
+command -v gt >/dev/null 2>&1 || exit 0
+%s=1 gt bead sync hook %s >&2 || true
+exit 0
+`, syncHookMarker, SyncHooksVersion, name, name, SyncHookEnv, SyncHookEnv, name)
+}
+
+// syncHookVersion returns the version of a gt-managed hook script, or 0
+// if the script is not one of ours.
+func syncHookVersion(script []byte) int {
+	lines := bytes.SplitN(script, []byte("\n"), 3)
+	if len(lines) < 2 || !bytes.HasPrefix(lines[1], []byte(syncHookMarker)) {
+		return 0
+	}
+	var v int
+	if _, err := fmt.Sscanf(string(lines[1][len(syncHookMarker):]), "%d", &v); err != nil {
+		return 0
+	}
+	return v
+}
+
+// ErrSyncHooksBypassed is returned for a clone whose core.hooksPath points
+// outside its git directory (e.g., hooks tracked in the repository); gt
+// only writes hooks the clone owns.
+var ErrSyncHooksBypassed = errors.New("core.hooksPath points outside the clone's git directory")
+
+// SyncHooksDir returns the hooks directory of the clone at clonePath,
+// or ErrSyncHooksBypassed if gt must not write there.
+func SyncHooksDir(clonePath string) (string, error) {
+	g := git.NewGit(clonePath)
+	dir, err := g.HooksDir()
+	if err != nil {
+		return "", err
+	}
+	common, err := g.CommonDir()
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(common, dir); err != nil || strings.HasPrefix(rel, "..") {
+		return dir, fmt.Errorf("%w (%s)", ErrSyncHooksBypassed, dir)
+	}
+	return dir, nil
+}
+
+// CheckSyncHooks returns the state of each sync hook in the clone at
+// clonePath, by hook name.
+func CheckSyncHooks(clonePath string) (map[string]string, error) {
+	dir, err := SyncHooksDir(clonePath)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]string, len(SyncHookNames))
+	for _, name := range SyncHookNames {
+		script, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: path is constructed internally
+		switch v := syncHookVersion(script); {
+		case err != nil || v == 0:
+			states[name] = SyncHookMissing
+		case v < SyncHooksVersion:
+			states[name] = SyncHookOutdated
+		default:
+			states[name] = SyncHookCurrent
+		}
+	}
+	return states, nil
+}
+
+// InstallSyncHooks writes the sync hooks into the clone at clonePath and
+// returns what it did to each, by hook name. A hook the clone already had
+// is renamed to <hook>.local and run first by ours. With dryRun nothing is
+// written.
+func InstallSyncHooks(clonePath string, dryRun bool) (map[string]string, error) {
+	dir, err := SyncHooksDir(clonePath)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]string, len(SyncHookNames))
+	for _, name := range SyncHookNames {
+		path := filepath.Join(dir, name)
+		existing, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		result := SyncHookInstalled
+		switch v := syncHookVersion(existing); {
+		case err != nil:
+			if !errors.Is(err, os.ErrNotExist) {
+				return results, fmt.Errorf("reading %s hook: %w", name, err)
+			}
+		case v >= SyncHooksVersion:
+			results[name] = SyncHookCurrent
+			continue
+		case v > 0:
+			result = SyncHookUpdated
+		default:
+			if _, err := os.Stat(path + ".local"); err == nil {
+				return results, fmt.Errorf("%s and %s.local both exist; merge them by hand", path, name)
+			}
+			result = SyncHookChained
+		}
+		results[name] = result
+		if dryRun {
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return results, fmt.Errorf("creating hooks directory: %w", err)
+		}
+		if result == SyncHookChained {
+			if err := os.Rename(path, path+".local"); err != nil {
+				return results, fmt.Errorf("keeping %s hook: %w", name, err)
+			}
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(SyncHookScript(name)), 0755); err != nil { //nolint:gosec // G306: hooks must be executable
+			return results, fmt.Errorf("writing %s hook: %w", name, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			_ = os.Remove(tmp)
+			return results, fmt.Errorf("writing %s hook: %w", name, err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,90 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initHookRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, out)
+	}
+	return dir
+}
+
+func TestInstallSyncHooks(t *testing.T) {
+	repo := initHookRepo(t)
+	hooksDir := filepath.Join(repo, ".git", "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	own := "#!/bin/sh\necho mine\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte(own), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := CheckSyncHooks(repo)
+	if err != nil || states["pre-push"] != SyncHookMissing || states["post-merge"] != SyncHookMissing {
+		t.Fatalf("before install: %v, %v", states, err)
+	}
+
+	// A dry run writes nothing.
+	results, err := InstallSyncHooks(repo, true)
+	if err != nil || results["pre-push"] != SyncHookChained || results["post-merge"] != SyncHookInstalled {
+		t.Fatalf("dry run = %v, %v", results, err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "post-merge")); err == nil {
+		t.Fatal("dry run wrote a hook")
+	}
+
+	if _, err := InstallSyncHooks(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(hooksDir, "pre-push.local")); string(data) != own {
+		t.Errorf("clone's own hook not kept: %q", data)
+	}
+	script, _ := os.ReadFile(filepath.Join(hooksDir, "post-merge"))
+	if !strings.Contains(string(script), "gt bead sync hook post-merge") {
+		t.Errorf("post-merge = %q", script)
+	}
+	if states, _ := CheckSyncHooks(repo); states["pre-push"] != SyncHookCurrent || states["post-merge"] != SyncHookCurrent {
+		t.Errorf("after install: %v", states)
+	}
+
+	// Installing again changes nothing.
+	results, err = InstallSyncHooks(repo, false)
+	if err != nil || results["pre-push"] != SyncHookCurrent || results["post-merge"] != SyncHookCurrent {
+		t.Errorf("reinstall = %v, %v", results, err)
+	}
+}
+
+func TestSyncHookVersion(t *testing.T) {
+	if v := syncHookVersion([]byte(SyncHookScript("pre-push"))); v != SyncHooksVersion {
+		t.Errorf("version = %d, want %d", v, SyncHooksVersion)
+	}
+	if v := syncHookVersion([]byte("#!/bin/sh\n# gt-managed beads-sync v0: old\n")); v != 0 {
+		t.Errorf("v0 = %d", v)
+	}
+	if v := syncHookVersion([]byte("#!/bin/sh\nexit 0\n")); v != 0 {
+		t.Errorf("unmanaged = %d", v)
+	}
+}
+
+func TestSyncHooksBypassed(t *testing.T) {
+	repo := initHookRepo(t)
+	if out, err := exec.Command("git", "-C", repo, "config", "core.hooksPath", ".githooks").CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v: %s", err, out)
+	}
+	if _, err := InstallSyncHooks(repo, false); !errors.Is(err, ErrSyncHooksBypassed) {
+		t.Errorf("install with tracked hooksPath: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".githooks")); err == nil {
+		t.Error("wrote into the repository's hooks directory")
+	}
+}
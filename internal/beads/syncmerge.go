@@ -0,0 +1,384 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SyncJSONLPath is where bd keeps the issue export on the sync branch.
+const SyncJSONLPath = ".beads/issues.jsonl"
+
+// SyncStrategy decides a field that changed differently on both sides of a
+// diverged sync branch.
+type SyncStrategy string
+
+// Field-level merge strategies.
+const (
+	SyncNewest   SyncStrategy = "newest"   // Side whose bead has the later updated_at
+	SyncLocal    SyncStrategy = "local"    // This clone's value
+	SyncRemote   SyncStrategy = "remote"   // origin's value
+	SyncUnion    SyncStrategy = "union"    // List fields: keep additions from both sides
+	SyncProgress SyncStrategy = "progress" // Status: the furthest along (closed > in_progress > open)
+)
+
+// SyncStrategies lists the valid strategies.
+var SyncStrategies = []SyncStrategy{SyncNewest, SyncLocal, SyncRemote, SyncUnion, SyncProgress}
+
+// DefaultSyncFieldStrategies are the per-field strategies used unless
+// overridden. Other fields use SyncMergeOptions.Default.
+var DefaultSyncFieldStrategies = map[string]SyncStrategy{
+	"labels":       SyncUnion,
+	"dependencies": SyncUnion,
+	"comments":     SyncUnion,
+	"status":       SyncProgress,
+}
+
+// ParseSyncStrategy validates a strategy name.
+func ParseSyncStrategy(s string) (SyncStrategy, error) {
+	for _, st := range SyncStrategies {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	return "", fmt.Errorf("unknown strategy %q (want newest, local, remote, union, or progress)", s)
+}
+
+// SyncMergeOptions configures MergeSyncJSONL.
+type SyncMergeOptions struct {
+	// Default applies to fields without a field strategy. Empty means SyncNewest.
+	Default SyncStrategy
+
+	// Fields overrides DefaultSyncFieldStrategies per field.
+	Fields map[string]SyncStrategy
+}
+
+func (o SyncMergeOptions) strategy(field string) SyncStrategy {
+	if s, ok := o.Fields[field]; ok {
+		return s
+	}
+	if s, ok := DefaultSyncFieldStrategies[field]; ok {
+		return s
+	}
+	if o.Default != "" {
+		return o.Default
+	}
+	return SyncNewest
+}
+
+// SyncConflict is a bead field changed differently on both sides. An empty
+// Field means the bead was deleted on one side and modified on the other;
+// the modified bead is kept.
+type SyncConflict struct {
+	ID       string          `json:"id"`
+	Field    string          `json:"field,omitempty"`
+	Local    json.RawMessage `json:"local"`
+	Remote   json.RawMessage `json:"remote"`
+	Resolved json.RawMessage `json:"resolved"`
+	Strategy SyncStrategy    `json:"strategy,omitempty"`
+}
+
+// SyncMergeResult is the outcome of merging two diverged sync-branch exports.
+type SyncMergeResult struct {
+	Data      []byte         `json:"-"`
+	Beads     int            `json:"beads"`
+	Merged    int            `json:"merged"`  // Beads changed on both sides
+	Deleted   int            `json:"deleted"` // Beads deleted on one side and untouched on the other
+	Conflicts []SyncConflict `json:"conflicts,omitempty"`
+}
+
+// syncRecord is one bead line from a JSONL export, with its fields kept raw
+// so that fields this package does not know about survive the merge.
+type syncRecord struct {
+	line   []byte
+	fields map[string]json.RawMessage
+}
+
+// MergeSyncJSONL three-way merges bead exports from the merge base, this
+// clone's sync branch, and origin's. Beads changed on only one side take that
+// side's version; beads changed on both are merged field by field, and fields
+// changed differently on both sides are resolved by strategy and reported.
+// Unchanged lines are copied byte for byte.
+func MergeSyncJSONL(base, local, remote []byte, opts SyncMergeOptions) (*SyncMergeResult, error) {
+	baseRecs, err := parseSyncJSONL(base)
+	if err != nil {
+		return nil, fmt.Errorf("base: %w", err)
+	}
+	localRecs, err := parseSyncJSONL(local)
+	if err != nil {
+		return nil, fmt.Errorf("local: %w", err)
+	}
+	remoteRecs, err := parseSyncJSONL(remote)
+	if err != nil {
+		return nil, fmt.Errorf("remote: %w", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, recs := range []map[string]*syncRecord{baseRecs, localRecs, remoteRecs} {
+		for id := range recs {
+			ids[id] = true
+		}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	result := &SyncMergeResult{}
+	var out bytes.Buffer
+	for _, id := range sorted {
+		b, l, r := baseRecs[id], localRecs[id], remoteRecs[id]
+		var line []byte
+		switch {
+		case l == nil && r == nil:
+			continue
+		case l == nil || r == nil:
+			present := l
+			if present == nil {
+				present = r
+			}
+			switch {
+			case b == nil:
+				line = present.line // Added on one side
+			case b.equal(present):
+				result.Deleted++ // Deleted on one side, untouched on the other
+				continue
+			default:
+				line = present.line
+				c := SyncConflict{ID: id, Resolved: present.line}
+				if l != nil {
+					c.Local = l.line
+				} else {
+					c.Remote = r.line
+				}
+				result.Conflicts = append(result.Conflicts, c)
+			}
+		case l.equal(r), b != nil && b.equal(r):
+			line = l.line
+		case b != nil && b.equal(l):
+			line = r.line
+		default:
+			merged, conflicts := mergeSyncRecord(id, b, l, r, opts)
+			result.Conflicts = append(result.Conflicts, conflicts...)
+			result.Merged++
+			switch {
+			case l.equalFields(merged):
+				line = l.line
+			case r.equalFields(merged):
+				line = r.line
+			default:
+				if line, err = json.Marshal(merged); err != nil {
+					return nil, fmt.Errorf("encoding %s: %w", id, err)
+				}
+			}
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+		result.Beads++
+	}
+	result.Data = out.Bytes()
+	return result, nil
+}
+
+func parseSyncJSONL(data []byte) (map[string]*syncRecord, error) {
+	recs := make(map[string]*syncRecord)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		var id string
+		if err := json.Unmarshal(fields["id"], &id); err != nil || id == "" {
+			return nil, fmt.Errorf("line %d: missing id", n)
+		}
+		recs[id] = &syncRecord{line: append([]byte(nil), line...), fields: fields}
+	}
+	return recs, scanner.Err()
+}
+
+func (r *syncRecord) equal(o *syncRecord) bool {
+	return bytes.Equal(r.line, o.line) || r.equalFields(o.fields)
+}
+
+func (r *syncRecord) equalFields(fields map[string]json.RawMessage) bool {
+	if len(r.fields) != len(fields) {
+		return false
+	}
+	for k, v := range r.fields {
+		if w, ok := fields[k]; !ok || !rawEqual(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *syncRecord) updatedAt() time.Time {
+	var s string
+	_ = json.Unmarshal(r.fields["updated_at"], &s)
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// mergeSyncRecord merges a bead changed on both sides field by field.
+func mergeSyncRecord(id string, b, l, r *syncRecord, opts SyncMergeOptions) (map[string]json.RawMessage, []SyncConflict) {
+	var baseFields map[string]json.RawMessage
+	if b != nil {
+		baseFields = b.fields
+	}
+	keys := make(map[string]bool)
+	for k := range l.fields {
+		keys[k] = true
+	}
+	for k := range r.fields {
+		keys[k] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	localNewer := l.updatedAt().After(r.updatedAt())
+	merged := make(map[string]json.RawMessage, len(keys))
+	var conflicts []SyncConflict
+	for _, k := range sortedKeys {
+		lv, lok := l.fields[k]
+		rv, rok := r.fields[k]
+		bv, bok := baseFields[k]
+		var v json.RawMessage
+		switch {
+		case lok == rok && rawEqual(lv, rv):
+			v = lv
+		case bok == lok && rawEqual(bv, lv):
+			v = rv
+		case bok == rok && rawEqual(bv, rv):
+			v = lv
+		case k == "updated_at":
+			// Always the later of the two; not worth reporting.
+			v = rv
+			if localNewer {
+				v = lv
+			}
+		default:
+			strategy := opts.strategy(k)
+			v = resolveSyncField(strategy, bv, lv, rv, localNewer)
+			conflicts = append(conflicts, SyncConflict{
+				ID: id, Field: k, Local: lv, Remote: rv, Resolved: v, Strategy: strategy,
+			})
+		}
+		if v != nil {
+			merged[k] = v
+		}
+	}
+	return merged, conflicts
+}
+
+func resolveSyncField(strategy SyncStrategy, base, local, remote json.RawMessage, localNewer bool) json.RawMessage {
+	newest := remote
+	if localNewer {
+		newest = local
+	}
+	switch strategy {
+	case SyncLocal:
+		return local
+	case SyncRemote:
+		return remote
+	case SyncUnion:
+		if v, ok := unionSyncList(base, local, remote); ok {
+			return v
+		}
+	case SyncProgress:
+		var ls, rs string
+		if json.Unmarshal(local, &ls) == nil && json.Unmarshal(remote, &rs) == nil {
+			switch lp, rp := statusProgress(ls), statusProgress(rs); {
+			case lp > rp:
+				return local
+			case rp > lp:
+				return remote
+			}
+		}
+	}
+	return newest
+}
+
+// unionSyncList merges JSON arrays: elements added on either side are kept
+// and elements either side removed since base are dropped. Local order first.
+func unionSyncList(base, local, remote json.RawMessage) (json.RawMessage, bool) {
+	var b, l, r []json.RawMessage
+	if len(base) > 0 && string(base) != "null" {
+		if json.Unmarshal(base, &b) != nil {
+			return nil, false
+		}
+	}
+	if json.Unmarshal(local, &l) != nil || json.Unmarshal(remote, &r) != nil {
+		return nil, false
+	}
+
+	key := func(v json.RawMessage) string {
+		var c bytes.Buffer
+		if json.Compact(&c, v) != nil {
+			return string(v)
+		}
+		return c.String()
+	}
+	inBase, inLocal, inRemote := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, v := range b {
+		inBase[key(v)] = true
+	}
+	for _, v := range l {
+		inLocal[key(v)] = true
+	}
+	for _, v := range r {
+		inRemote[key(v)] = true
+	}
+
+	out := make([]json.RawMessage, 0, len(l)+len(r))
+	seen := make(map[string]bool)
+	for _, v := range append(l, r...) {
+		k := key(v)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if inBase[k] && (!inLocal[k] || !inRemote[k]) {
+			continue // Removed on one side
+		}
+		out = append(out, v)
+	}
+	data, err := json.Marshal(out)
+	return data, err == nil
+}
+
+// statusProgress ranks statuses by how far along the work is.
+func statusProgress(status string) int {
+	switch status {
+	case "closed", "tombstone":
+		return 3
+	case "in_progress", StatusHooked, StatusPinned:
+		return 2
+	case "blocked", "deferred":
+		return 1
+	}
+	return 0
+}
+
+func rawEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
@@ -0,0 +1,115 @@
+package beads
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func jsonl(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func TestMergeSyncJSONL_OneSidedChanges(t *testing.T) {
+	base := jsonl(
+		`{"id":"gt-1","title":"one","status":"open"}`,
+		`{"id":"gt-2","title":"two","status":"open"}`,
+		`{"id":"gt-3","title":"three","status":"open"}`,
+	)
+	local := jsonl(
+		`{"id":"gt-1","title":"one (local)","status":"open"}`,
+		`{"id":"gt-2","title":"two","status":"open"}`,
+		`{"id":"gt-4","title":"new local","status":"open"}`,
+	)
+	remote := jsonl(
+		`{"id":"gt-1","title":"one","status":"open"}`,
+		`{"id":"gt-2", "title":"two", "status":"closed"}`,
+		`{"id":"gt-3","title":"three","status":"open"}`,
+		`{"id":"gt-5","title":"new remote","status":"open"}`,
+	)
+
+	res, err := MergeSyncJSONL(base, local, remote, SyncMergeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := jsonl(
+		`{"id":"gt-1","title":"one (local)","status":"open"}`,
+		`{"id":"gt-2", "title":"two", "status":"closed"}`,
+		`{"id":"gt-4","title":"new local","status":"open"}`,
+		`{"id":"gt-5","title":"new remote","status":"open"}`,
+	)
+	if string(res.Data) != string(want) {
+		t.Errorf("merged =\n%s\nwant\n%s", res.Data, want)
+	}
+	if res.Beads != 4 || res.Deleted != 1 || res.Merged != 0 || len(res.Conflicts) != 0 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestMergeSyncJSONL_FieldLevel(t *testing.T) {
+	base := jsonl(`{"id":"gt-1","title":"t","priority":2,"status":"open","labels":["a","b"],"updated_at":"2026-01-01T00:00:00Z"}`)
+	local := jsonl(`{"id":"gt-1","title":"t (local)","priority":1,"status":"in_progress","labels":["a","b","local"],"updated_at":"2026-01-02T00:00:00Z"}`)
+	remote := jsonl(`{"id":"gt-1","title":"t","priority":3,"status":"closed","labels":["b","remote"],"updated_at":"2026-01-03T00:00:00Z"}`)
+
+	res, err := MergeSyncJSONL(base, local, remote, SyncMergeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(res.Data, &got); err != nil {
+		t.Fatal(err)
+	}
+	// title changed only locally; priority conflicts and remote is newer;
+	// status is furthest along; labels keep both additions and the removal.
+	if got["title"] != "t (local)" || got["priority"] != 3.0 || got["status"] != "closed" {
+		t.Errorf("merged = %v", got)
+	}
+	if labels, _ := json.Marshal(got["labels"]); string(labels) != `["b","local","remote"]` {
+		t.Errorf("labels = %s", labels)
+	}
+	if got["updated_at"] != "2026-01-03T00:00:00Z" {
+		t.Errorf("updated_at = %v", got["updated_at"])
+	}
+	if res.Merged != 1 || len(res.Conflicts) != 3 {
+		t.Fatalf("result = %+v", res)
+	}
+	fields := []string{res.Conflicts[0].Field, res.Conflicts[1].Field, res.Conflicts[2].Field}
+	if strings.Join(fields, ",") != "labels,priority,status" {
+		t.Errorf("conflicts = %v", fields)
+	}
+
+	res, _ = MergeSyncJSONL(base, local, remote, SyncMergeOptions{
+		Default: SyncLocal,
+		Fields:  map[string]SyncStrategy{"status": SyncLocal},
+	})
+	_ = json.Unmarshal(res.Data, &got)
+	if got["priority"] != 1.0 || got["status"] != "in_progress" {
+		t.Errorf("prefer local: %v", got)
+	}
+}
+
+func TestMergeSyncJSONL_DeleteModify(t *testing.T) {
+	base := jsonl(`{"id":"gt-1","title":"t"}`)
+	local := jsonl()
+	remote := jsonl(`{"id":"gt-1","title":"t (edited)"}`)
+
+	res, err := MergeSyncJSONL(base, local, remote, SyncMergeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res.Data), "t (edited)") {
+		t.Errorf("modified bead should survive a delete, got %s", res.Data)
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Field != "" || res.Conflicts[0].Local != nil {
+		t.Errorf("conflicts = %+v", res.Conflicts)
+	}
+}
+
+func TestMergeSyncJSONL_Invalid(t *testing.T) {
+	if _, err := MergeSyncJSONL(nil, jsonl(`{"title":"no id"}`), nil, SyncMergeOptions{}); err == nil {
+		t.Error("expected error for a bead without an id")
+	}
+	if _, err := ParseSyncStrategy("mine"); err == nil {
+		t.Error("expected error for an unknown strategy")
+	}
+}
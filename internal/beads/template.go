@@ -0,0 +1,258 @@
+package beads
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplatesDir is the directory under a .beads directory holding templates.
+const TemplatesDir = "templates"
+
+// Template pre-populates a new bead of a given type. Templates are YAML
+// files named <name>.yaml:
+//
+//	type: bug
+//	priority: 1
+//	labels: [triage]
+//	title_prefix: "bug: "
+//	sections: [Steps to reproduce, Expected, Actual]
+//	description: |
+//	  Link any related convoy or MR.
+//	fields:
+//	  target: main
+//
+// Fields are written as "key: value" lines at the top of the description,
+// the same place merge requests keep branch, target, and so on.
+type Template struct {
+	Name        string            `yaml:"-" json:"name"`
+	Source      string            `yaml:"-" json:"source"` // File path, or "builtin"
+	Type        string            `yaml:"type" json:"type"`
+	Priority    *int              `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Labels      []string          `yaml:"labels,omitempty" json:"labels,omitempty"`
+	TitlePrefix string            `yaml:"title_prefix,omitempty" json:"title_prefix,omitempty"`
+	Sections    []string          `yaml:"sections,omitempty" json:"sections,omitempty"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Fields      map[string]string `yaml:"fields,omitempty" json:"fields,omitempty"`
+}
+
+func intPtr(n int) *int { return &n }
+
+// builtinTemplates are available in every rig unless a template file of the
+// same name overrides them.
+var builtinTemplates = map[string]Template{
+	"bug": {
+		Type:     "bug",
+		Priority: intPtr(1),
+		Sections: []string{"Steps to reproduce", "Expected behavior", "Actual behavior", "Environment"},
+	},
+	"merge-request": {
+		Type:     "merge-request",
+		Priority: intPtr(2),
+		Sections: []string{"Summary", "Testing"},
+		Fields:   map[string]string{"branch": "", "target": "main", "source_issue": ""},
+	},
+	"convoy-task": {
+		Type:     "task",
+		Priority: intPtr(2),
+		Labels:   []string{"convoy"},
+		Sections: []string{"Goal", "Acceptance criteria", "Notes"},
+	},
+}
+
+// TemplateDirs returns the template directories searched for a rig, most
+// specific first: the rig's .beads/templates, then the town's.
+func TemplateDirs(townRoot, rigPath string) []string {
+	var dirs []string
+	if rigPath != "" {
+		dirs = append(dirs, filepath.Join(ResolveBeadsDir(rigPath), TemplatesDir))
+	}
+	if townRoot != "" {
+		townDir := filepath.Join(townRoot, ".beads", TemplatesDir)
+		if len(dirs) == 0 || dirs[0] != townDir {
+			dirs = append(dirs, townDir)
+		}
+	}
+	return dirs
+}
+
+// LoadTemplate finds a template by name in dirs, falling back to the
+// built-in templates.
+func LoadTemplate(dirs []string, name string) (*Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	for _, dir := range dirs {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return parseTemplateFile(path, name)
+			}
+		}
+	}
+	if t, ok := builtinTemplates[name]; ok {
+		t.Name, t.Source = name, "builtin"
+		return &t, nil
+	}
+	return nil, fmt.Errorf("no template %q (looked in %s and the built-ins)", name, strings.Join(dirs, ", "))
+}
+
+// ListTemplates returns every template visible from dirs, sorted by name.
+// A template in an earlier directory shadows later ones and the built-ins.
+// Files that fail to parse are returned in errs.
+func ListTemplates(dirs []string) (templates []*Template, errs []error) {
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			name := strings.TrimSuffix(e.Name(), ext)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			t, err := parseTemplateFile(filepath.Join(dir, e.Name()), name)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			templates = append(templates, t)
+		}
+	}
+	for name, t := range builtinTemplates {
+		if seen[name] {
+			continue
+		}
+		t.Name, t.Source = name, "builtin"
+		templates = append(templates, &t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, errs
+}
+
+func parseTemplateFile(path, name string) (*Template, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the template search dirs
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", path, err)
+	}
+	var t Template
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", path, err)
+	}
+	t.Name, t.Source = name, path
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// Validate checks a template's values.
+func (t *Template) Validate() error {
+	if t.Priority != nil && (*t.Priority < 0 || *t.Priority > 4) {
+		return fmt.Errorf("priority %d out of range (0-4)", *t.Priority)
+	}
+	for key := range t.Fields {
+		if key == "" || strings.ContainsAny(key, ": \n") {
+			return fmt.Errorf("invalid field name %q", key)
+		}
+	}
+	return nil
+}
+
+// TemplateInput holds the per-bead values applied on top of a template.
+type TemplateInput struct {
+	Title       string
+	Priority    int               // -1 = template default
+	Labels      []string          // Added to the template's labels
+	Description string            // Added after the template's sections
+	Fields      map[string]string // Override template fields
+}
+
+// TemplateBead is a bead ready to create from a template.
+type TemplateBead struct {
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels,omitempty"`
+	Description string   `json:"description"`
+}
+
+// Apply fills in a bead from the template and the caller's input.
+func (t *Template) Apply(in TemplateInput) (*TemplateBead, error) {
+	title := strings.TrimSpace(in.Title)
+	if title == "" {
+		return nil, fmt.Errorf("a title is required")
+	}
+	if t.TitlePrefix != "" && !strings.HasPrefix(title, t.TitlePrefix) {
+		title = t.TitlePrefix + title
+	}
+
+	b := &TemplateBead{Title: title, Type: t.Type, Priority: 2}
+	if t.Priority != nil {
+		b.Priority = *t.Priority
+	}
+	if in.Priority >= 0 {
+		b.Priority = in.Priority
+	}
+
+	seen := make(map[string]bool)
+	for _, l := range append(append([]string{}, t.Labels...), in.Labels...) {
+		if l = strings.TrimSpace(l); l != "" && !seen[l] {
+			seen[l] = true
+			b.Labels = append(b.Labels, l)
+		}
+	}
+
+	fields := make(map[string]string, len(t.Fields)+len(in.Fields))
+	for k, v := range t.Fields {
+		fields[k] = v
+	}
+	for k, v := range in.Fields {
+		if _, ok := t.Fields[k]; !ok && len(t.Fields) > 0 {
+			return nil, fmt.Errorf("template %s has no field %q (has: %s)", t.Name, k, strings.Join(sortedKeys(t.Fields), ", "))
+		}
+		fields[k] = v
+	}
+
+	var parts []string
+	if len(fields) > 0 {
+		var lines []string
+		for _, k := range sortedKeys(fields) {
+			lines = append(lines, strings.TrimRight(k+": "+fields[k], " "))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	if d := strings.TrimSpace(in.Description); d != "" {
+		parts = append(parts, d)
+	}
+	for _, s := range t.Sections {
+		parts = append(parts, "## "+s)
+	}
+	if d := strings.TrimSpace(t.Description); d != "" {
+		parts = append(parts, d)
+	}
+	b.Description = strings.Join(parts, "\n\n")
+	return b, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
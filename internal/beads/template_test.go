@@ -0,0 +1,140 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplate_SearchOrder(t *testing.T) {
+	rigDir := filepath.Join(t.TempDir(), "rig")
+	townDir := filepath.Join(t.TempDir(), "town")
+	writeTemplate(t, rigDir, "bug.yaml", "type: bug\npriority: 0\nlabels: [urgent]\n")
+	writeTemplate(t, townDir, "bug.yaml", "type: bug\npriority: 3\n")
+	writeTemplate(t, townDir, "spike.yml", "type: task\ntitle_prefix: \"spike: \"\n")
+	dirs := []string{rigDir, townDir}
+
+	bug, err := LoadTemplate(dirs, "bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *bug.Priority != 0 || bug.Source != filepath.Join(rigDir, "bug.yaml") {
+		t.Errorf("rig template should win: %+v", bug)
+	}
+
+	spike, err := LoadTemplate(dirs, "spike")
+	if err != nil || spike.TitlePrefix != "spike: " {
+		t.Errorf("town .yml template: %+v, %v", spike, err)
+	}
+
+	mr, err := LoadTemplate(dirs, "merge-request")
+	if err != nil || mr.Source != "builtin" || mr.Fields["target"] != "main" {
+		t.Errorf("builtin merge-request: %+v, %v", mr, err)
+	}
+
+	for _, name := range []string{"missing", "../bug", ""} {
+		if _, err := LoadTemplate(dirs, name); err == nil {
+			t.Errorf("LoadTemplate(%q): expected error", name)
+		}
+	}
+}
+
+func TestLoadTemplate_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "typo.yaml", "type: bug\nlables: [x]\n")
+	writeTemplate(t, dir, "range.yaml", "type: bug\npriority: 9\n")
+
+	for _, name := range []string{"typo", "range"} {
+		if _, err := LoadTemplate([]string{dir}, name); err == nil {
+			t.Errorf("LoadTemplate(%q): expected error", name)
+		}
+	}
+
+	templates, errs := ListTemplates([]string{dir})
+	if len(errs) != 2 {
+		t.Errorf("ListTemplates errs = %v, want 2", errs)
+	}
+	if len(templates) != len(builtinTemplates) {
+		t.Errorf("ListTemplates returned %d templates, want the %d built-ins", len(templates), len(builtinTemplates))
+	}
+}
+
+func TestListTemplates_Shadowing(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "bug.yaml", "type: bug\n")
+	writeTemplate(t, dir, "notes.txt", "not a template")
+
+	templates, errs := ListTemplates([]string{dir, filepath.Join(dir, "missing")})
+	if len(errs) != 0 {
+		t.Fatalf("errs = %v", errs)
+	}
+	var names []string
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name+"@"+filepath.Base(tmpl.Source))
+	}
+	want := "bug@bug.yaml convoy-task@builtin merge-request@builtin"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("templates = %s, want %s", got, want)
+	}
+}
+
+func TestTemplateApply(t *testing.T) {
+	p := 1
+	tmpl := &Template{
+		Name:        "mr",
+		Type:        "merge-request",
+		Priority:    &p,
+		Labels:      []string{"review"},
+		TitlePrefix: "MR: ",
+		Sections:    []string{"Summary", "Testing"},
+		Description: "Link the convoy.",
+		Fields:      map[string]string{"target": "main", "branch": ""},
+	}
+
+	b, err := tmpl.Apply(TemplateInput{
+		Title:       "Add retry",
+		Priority:    -1,
+		Labels:      []string{"review", "infra"},
+		Description: "Retries fetches.",
+		Fields:      map[string]string{"branch": "polecat/Toast/gt-abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Title != "MR: Add retry" || b.Type != "merge-request" || b.Priority != 1 {
+		t.Errorf("bead = %+v", b)
+	}
+	if strings.Join(b.Labels, ",") != "review,infra" {
+		t.Errorf("labels = %v", b.Labels)
+	}
+	want := "branch: polecat/Toast/gt-abc\ntarget: main\n\nRetries fetches.\n\n## Summary\n\n## Testing\n\nLink the convoy."
+	if b.Description != want {
+		t.Errorf("description =\n%q\nwant\n%q", b.Description, want)
+	}
+	if mr := ParseMRFields(&Issue{Description: b.Description}); mr == nil || mr.Target != "main" || mr.Branch != "polecat/Toast/gt-abc" {
+		t.Errorf("MR fields not parseable: %+v", mr)
+	}
+
+	b, _ = tmpl.Apply(TemplateInput{Title: "MR: Already prefixed", Priority: 3})
+	if b.Title != "MR: Already prefixed" || b.Priority != 3 {
+		t.Errorf("bead = %+v", b)
+	}
+
+	if _, err := tmpl.Apply(TemplateInput{Title: "x", Priority: -1, Fields: map[string]string{"nope": "1"}}); err == nil {
+		t.Error("expected error for a field the template doesn't define")
+	}
+	if _, err := tmpl.Apply(TemplateInput{Title: "  ", Priority: -1}); err == nil {
+		t.Error("expected error for an empty title")
+	}
+}
@@ -0,0 +1,847 @@
+package beads
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Export/import formats for gt beads export and gt beads import.
+const (
+	FormatJSONL    = "jsonl"    // One bd issue JSON object per line
+	FormatCSV      = "csv"      // Spreadsheet/Jira-friendly columns
+	FormatGitHub   = "github"   // JSON array shaped like `gh issue list --json`
+	FormatMarkdown = "markdown" // Human-readable document, one section per bead
+)
+
+// TransferFormats lists the supported export/import formats.
+var TransferFormats = []string{FormatJSONL, FormatCSV, FormatGitHub, FormatMarkdown}
+
+// csvColumns are the columns written by CSV export, in order.
+var csvColumns = []string{
+	"id", "title", "status", "priority", "type", "assignee", "labels",
+	"parent", "depends_on", "created_at", "updated_at", "closed_at", "description",
+}
+
+// csvColumnAliases maps common headers from GitHub and Jira CSV exports
+// (lowercased) to bead fields. --map entries take precedence.
+var csvColumnAliases = map[string]string{
+	"issue key":    "id",
+	"key":          "id",
+	"number":       "id",
+	"summary":      "title",
+	"state":        "status",
+	"issue type":   "type",
+	"issue_type":   "type",
+	"body":         "description",
+	"assignees":    "assignee",
+	"parent id":    "parent",
+	"created":      "created_at",
+	"updated":      "updated_at",
+	"resolved":     "closed_at",
+	"depends on":   "depends_on",
+	"blocked by":   "depends_on",
+	"parent_id":    "parent",
+	"dependencies": "depends_on",
+}
+
+// beadIDPattern matches IDs that can be kept on import (e.g., gt-abc12,
+// hq-cv-x9.2). External keys like "#12" or "PROJ-123" get new IDs.
+var beadIDPattern = regexp.MustCompile(`^[a-z][a-z0-9]*-[a-z0-9][a-z0-9.-]*$`)
+
+// ExportIssues writes issues to w in the given format.
+func ExportIssues(w io.Writer, format string, issues []*Issue) error {
+	return ExportIssuesWithComments(w, format, issues, nil)
+}
+
+// jsonlExportRow is a JSONL export line: the bd issue plus its discussion.
+type jsonlExportRow struct {
+	*Issue
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+// ExportIssuesWithComments writes issues to w in the given format, with
+// each bead's discussion from comments (by bead ID). CSV has no place for
+// a discussion and leaves it out.
+func ExportIssuesWithComments(w io.Writer, format string, issues []*Issue, comments map[string][]Comment) error {
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, issue := range issues {
+			if err := enc.Encode(jsonlExportRow{Issue: issue, Comments: comments[issue.ID]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		return exportCSV(w, issues)
+	case FormatGitHub:
+		out := make([]githubIssue, 0, len(issues))
+		for _, issue := range issues {
+			gh := toGitHubIssue(issue)
+			for _, c := range comments[issue.ID] {
+				gh.Comments = append(gh.Comments, githubComment{Author: githubUser{Login: c.Author}, Body: c.Body, CreatedAt: c.Timestamp})
+			}
+			out = append(out, gh)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case FormatMarkdown:
+		return exportMarkdown(w, issues, comments)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(TransferFormats, ", "))
+}
+
+// ParseImport reads issues in the given format. fieldMap maps CSV column
+// headers to bead fields (e.g., "Story Points" → "" drops a column,
+// "Epic Link" → "parent"); it is ignored by the other formats.
+func ParseImport(r io.Reader, format string, fieldMap map[string]string) ([]*Issue, error) {
+	switch format {
+	case FormatJSONL:
+		return parseJSONL(r)
+	case FormatCSV:
+		return parseCSV(r, fieldMap)
+	case FormatGitHub:
+		var in []githubIssue
+		if err := json.NewDecoder(r).Decode(&in); err != nil {
+			return nil, fmt.Errorf("parsing GitHub issues: %w", err)
+		}
+		issues := make([]*Issue, 0, len(in))
+		for _, gh := range in {
+			issues = append(issues, fromGitHubIssue(gh))
+		}
+		return issues, nil
+	case FormatMarkdown:
+		return parseMarkdown(r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want %s)", format, strings.Join(TransferFormats, ", "))
+}
+
+// exportDependsOn returns the IDs issue depends on (blocking dependencies).
+func exportDependsOn(issue *Issue) []string {
+	seen := make(map[string]bool)
+	var deps []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			deps = append(deps, id)
+		}
+	}
+	for _, id := range issue.DependsOn {
+		add(id)
+	}
+	for _, d := range issue.Dependencies {
+		if d.DependencyType == "" || d.DependencyType == "blocks" {
+			add(d.ID)
+		}
+	}
+	return deps
+}
+
+// --- CSV ---
+
+func exportCSV(w io.Writer, issues []*Issue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		row := []string{
+			issue.ID, issue.Title, issue.Status, strconv.Itoa(issue.Priority), issue.Type,
+			issue.Assignee, strings.Join(issue.Labels, ";"), issue.Parent,
+			strings.Join(exportDependsOn(issue), ";"), issue.CreatedAt, issue.UpdatedAt,
+			issue.ClosedAt, issue.Description,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func parseCSV(r io.Reader, fieldMap map[string]string) ([]*Issue, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	userMap := make(map[string]string, len(fieldMap))
+	for k, v := range fieldMap {
+		userMap[strings.ToLower(strings.TrimSpace(k))] = strings.ToLower(strings.TrimSpace(v))
+	}
+	known := make(map[string]bool, len(csvColumns))
+	for _, c := range csvColumns {
+		known[c] = true
+	}
+
+	fields := make([]string, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		field, ok := userMap[h]
+		if !ok {
+			if known[h] {
+				field = h
+			} else {
+				field = csvColumnAliases[h] // Unknown columns are dropped
+			}
+		}
+		if field != "" && !known[field] {
+			return nil, fmt.Errorf("--map %q: unknown bead field %q (want one of %s)", h, field, strings.Join(csvColumns, ", "))
+		}
+		fields[i] = field
+	}
+
+	var issues []*Issue
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV line %d: %w", line, err)
+		}
+		issue := &Issue{Priority: -1}
+		for i, value := range record {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			if err := setImportField(issue, fields[i], value); err != nil {
+				return nil, fmt.Errorf("CSV line %d: %w", line, err)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// setImportField sets one bead field from an imported text value.
+// Repeated list columns (Jira exports one "Labels" column per label) append.
+func setImportField(issue *Issue, field, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	switch field {
+	case "id":
+		issue.ID = value
+	case "title":
+		issue.Title = value
+	case "status":
+		issue.Status = NormalizeImportStatus(value)
+	case "priority":
+		p, ok := ParseImportPriority(value)
+		if !ok {
+			return fmt.Errorf("unrecognized priority %q", value)
+		}
+		issue.Priority = p
+	case "type":
+		issue.Type = strings.ToLower(value)
+	case "assignee":
+		issue.Assignee = value
+	case "labels":
+		issue.Labels = append(issue.Labels, splitList(value)...)
+	case "parent":
+		issue.Parent = value
+	case "depends_on":
+		issue.DependsOn = append(issue.DependsOn, splitList(value)...)
+	case "created_at":
+		issue.CreatedAt = value
+	case "updated_at":
+		issue.UpdatedAt = value
+	case "closed_at":
+		issue.ClosedAt = value
+	case "description":
+		issue.Description = value
+	}
+	return nil
+}
+
+// splitList splits a ";"- or ","-separated list cell.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// ParseImportPriority parses a priority from beads (0-4, P0-P4), GitHub
+// labels, or Jira names (Highest..Lowest, Blocker, Critical, ...).
+func ParseImportPriority(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "priority:")
+	s = strings.TrimPrefix(s, "p")
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 4 {
+		return n, true
+	}
+	switch s {
+	case "highest", "blocker", "critical", "urgent":
+		return 0, true
+	case "high", "major":
+		return 1, true
+	case "medium", "normal":
+		return 2, true
+	case "low", "minor":
+		return 3, true
+	case "lowest", "trivial":
+		return 4, true
+	}
+	return 0, false
+}
+
+// NormalizeImportStatus maps GitHub and Jira states to bead statuses.
+// Unknown values pass through lowercased for bd to validate.
+func NormalizeImportStatus(s string) string {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "open", "new", "to do", "todo", "backlog", "reopened", "selected for development":
+		return "open"
+	case "in progress", "in-progress", "in_progress", "in review", "doing":
+		return "in_progress"
+	case "closed", "done", "resolved", "complete", "completed", "won't do", "wontfix":
+		return "closed"
+	default:
+		return strings.ReplaceAll(v, " ", "_")
+	}
+}
+
+// --- JSONL ---
+
+func parseJSONL(r io.Reader) ([]*Issue, error) {
+	var issues []*Issue
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var issue Issue
+		if err := json.Unmarshal([]byte(text), &issue); err != nil {
+			return nil, fmt.Errorf("JSONL line %d: %w", line, err)
+		}
+		issue.DependsOn = exportDependsOn(&issue)
+		issue.Dependencies = nil
+		issues = append(issues, &issue)
+	}
+	return issues, scanner.Err()
+}
+
+// --- GitHub ---
+
+// githubIssue mirrors `gh issue list --json number,title,body,state,labels,assignees,url,comments`.
+type githubIssue struct {
+	Number    int             `json:"number,omitempty"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	State     string          `json:"state"`
+	Labels    []githubLabel   `json:"labels,omitempty"`
+	Assignees []githubUser    `json:"assignees,omitempty"`
+	URL       string          `json:"url,omitempty"`
+	Comments  []githubComment `json:"comments,omitempty"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubComment struct {
+	Author    githubUser `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt string     `json:"createdAt,omitempty"`
+}
+
+// githubTrailerRe matches the hidden trailer that carries bead metadata
+// GitHub has no field for, so exports round-trip.
+var githubTrailerRe = regexp.MustCompile(`(?m)\n*^<!-- gt:bead (.*) -->\s*$`)
+
+func toGitHubIssue(issue *Issue) githubIssue {
+	gh := githubIssue{Title: issue.Title, State: "OPEN"}
+	if issue.Status == "closed" {
+		gh.State = "CLOSED"
+	} else if issue.Status != "" && issue.Status != "open" {
+		gh.Labels = append(gh.Labels, githubLabel{Name: "status:" + issue.Status})
+	}
+	gh.Labels = append(gh.Labels, githubLabel{Name: fmt.Sprintf("priority:P%d", issue.Priority)})
+	if issue.Type != "" {
+		gh.Labels = append(gh.Labels, githubLabel{Name: "type:" + issue.Type})
+	}
+	for _, l := range issue.Labels {
+		gh.Labels = append(gh.Labels, githubLabel{Name: l})
+	}
+	if issue.Assignee != "" {
+		gh.Assignees = []githubUser{{Login: issue.Assignee}}
+	}
+
+	meta := []string{"id=" + issue.ID}
+	if issue.Parent != "" {
+		meta = append(meta, "parent="+issue.Parent)
+	}
+	if deps := exportDependsOn(issue); len(deps) > 0 {
+		meta = append(meta, "depends_on="+strings.Join(deps, ","))
+	}
+	gh.Body = strings.TrimRight(issue.Description, "\n") + "\n\n<!-- gt:bead " + strings.Join(meta, " ") + " -->"
+	return gh
+}
+
+func fromGitHubIssue(gh githubIssue) *Issue {
+	issue := &Issue{Title: gh.Title, Priority: -1, Status: NormalizeImportStatus(gh.State)}
+	body := gh.Body
+	if m := githubTrailerRe.FindStringSubmatchIndex(body); m != nil {
+		for _, kv := range strings.Fields(body[m[2]:m[3]]) {
+			k, v, _ := strings.Cut(kv, "=")
+			switch k {
+			case "id":
+				issue.ID = v
+			case "parent":
+				issue.Parent = v
+			case "depends_on":
+				issue.DependsOn = splitList(v)
+			}
+		}
+		body = body[:m[0]] + body[m[1]:]
+	}
+	issue.Description = strings.TrimSpace(body)
+	if issue.ID == "" {
+		issue.ID = gh.URL
+		if issue.ID == "" && gh.Number > 0 {
+			issue.ID = fmt.Sprintf("#%d", gh.Number)
+		}
+	}
+
+	for _, l := range gh.Labels {
+		name := l.Name
+		switch {
+		case strings.HasPrefix(name, "status:"):
+			issue.Status = NormalizeImportStatus(strings.TrimPrefix(name, "status:"))
+		case strings.HasPrefix(name, "priority:"):
+			if p, ok := ParseImportPriority(name); ok {
+				issue.Priority = p
+			}
+		case strings.HasPrefix(name, "type:"):
+			issue.Type = strings.TrimPrefix(name, "type:")
+		case name == "bug" || name == "enhancement":
+			// GitHub's default labels double as issue types
+			if issue.Type == "" {
+				issue.Type = map[string]string{"bug": "bug", "enhancement": "feature"}[name]
+			}
+			issue.Labels = append(issue.Labels, name)
+		default:
+			issue.Labels = append(issue.Labels, name)
+		}
+	}
+	if len(gh.Assignees) > 0 {
+		issue.Assignee = gh.Assignees[0].Login
+	}
+	return issue
+}
+
+// --- Markdown ---
+
+var (
+	markdownHeadingRe = regexp.MustCompile(`^## (\S+): (.*)$`)
+	markdownMetaRe    = regexp.MustCompile(`^- ([A-Za-z ]+): (.*)$`)
+)
+
+// markdownCommentsHeading starts a bead's discussion in a markdown export.
+// Description lines starting with "#" are escaped, so it can't be one.
+const markdownCommentsHeading = "### Comments"
+
+// markdownMetaFields maps markdown metadata keys to bead fields.
+var markdownMetaFields = []struct{ key, field string }{
+	{"Status", "status"},
+	{"Priority", "priority"},
+	{"Type", "type"},
+	{"Assignee", "assignee"},
+	{"Labels", "labels"},
+	{"Parent", "parent"},
+	{"Depends on", "depends_on"},
+	{"Created", "created_at"},
+	{"Updated", "updated_at"},
+	{"Closed", "closed_at"},
+}
+
+func exportMarkdown(w io.Writer, issues []*Issue, comments map[string][]Comment) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Beads export\n")
+	for _, issue := range issues {
+		fmt.Fprintf(bw, "\n## %s: %s\n\n", issue.ID, issue.Title)
+		values := map[string]string{
+			"status":     issue.Status,
+			"priority":   fmt.Sprintf("P%d", issue.Priority),
+			"type":       issue.Type,
+			"assignee":   issue.Assignee,
+			"labels":     strings.Join(issue.Labels, ", "),
+			"parent":     issue.Parent,
+			"depends_on": strings.Join(exportDependsOn(issue), ", "),
+			"created_at": issue.CreatedAt,
+			"updated_at": issue.UpdatedAt,
+			"closed_at":  issue.ClosedAt,
+		}
+		for _, m := range markdownMetaFields {
+			if v := values[m.field]; v != "" {
+				fmt.Fprintf(bw, "- %s: %s\n", m.key, v)
+			}
+		}
+		if desc := strings.TrimSpace(issue.Description); desc != "" {
+			bw.WriteString("\n")
+			writeMarkdownText(bw, desc)
+		}
+		if discussion := comments[issue.ID]; len(discussion) > 0 {
+			fmt.Fprintf(bw, "\n%s\n", markdownCommentsHeading)
+			for _, c := range discussion {
+				author := c.Author
+				if author == "" {
+					author = "unknown"
+				}
+				fmt.Fprintf(bw, "\n#### %s, %s\n\n", author, c.Timestamp)
+				writeMarkdownText(bw, strings.TrimSpace(c.Body))
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// writeMarkdownText writes text line by line, escaping headings so they
+// can't be mistaken for a new bead or a discussion.
+func writeMarkdownText(bw *bufio.Writer, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, `\`) {
+			line = `\` + line
+		}
+		bw.WriteString(line + "\n")
+	}
+}
+
+func parseMarkdown(r io.Reader) ([]*Issue, error) {
+	keyField := make(map[string]string, len(markdownMetaFields))
+	for _, m := range markdownMetaFields {
+		keyField[m.key] = m.field
+	}
+
+	var issues []*Issue
+	var cur *Issue
+	var desc []string
+	inMeta, inComments := false, false
+	flush := func() {
+		if cur != nil {
+			cur.Description = strings.TrimSpace(strings.Join(desc, "\n"))
+			issues = append(issues, cur)
+		}
+		desc = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if m := markdownHeadingRe.FindStringSubmatch(text); m != nil {
+			flush()
+			cur = &Issue{ID: m[1], Title: m[2], Priority: -1}
+			inMeta, inComments = true, false
+			continue
+		}
+		if cur == nil || inComments {
+			continue // Document title and preamble; discussions aren't imported
+		}
+		if text == markdownCommentsHeading {
+			inComments = true
+			continue
+		}
+		if inMeta {
+			if m := markdownMetaRe.FindStringSubmatch(text); m != nil {
+				if field, ok := keyField[m[1]]; ok {
+					if err := setImportField(cur, field, m[2]); err != nil {
+						return nil, fmt.Errorf("markdown line %d: %w", line, err)
+					}
+					continue
+				}
+			}
+			if strings.TrimSpace(text) == "" && len(desc) == 0 {
+				continue
+			}
+			inMeta = false
+		}
+		if strings.HasPrefix(text, `\`) {
+			text = text[1:]
+		}
+		desc = append(desc, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return issues, nil
+}
+
+// --- Import ---
+
+// ID collision policies for ImportIssues.
+const (
+	CollisionSkip   = "skip"   // Leave the existing bead alone
+	CollisionNew    = "new"    // Import under a freshly generated ID
+	CollisionUpdate = "update" // Overwrite the existing bead's fields
+)
+
+// ImportOptions controls ImportIssues.
+type ImportOptions struct {
+	OnCollision string // skip (default), new, or update
+	NewIDs      bool   // Never keep source IDs
+	DryRun      bool   // Plan only; write nothing
+}
+
+// ImportAction is what ImportIssues did (or would do) with one record.
+type ImportAction struct {
+	SourceID string `json:"source_id,omitempty"`
+	ID       string `json:"id,omitempty"` // Bead ID ("" for a dry-run create with a new ID)
+	Action   string `json:"action"`       // created, updated, skipped, failed
+	Detail   string `json:"detail,omitempty"`
+}
+
+// importer is the subset of Beads used by ImportIssues.
+type importer interface {
+	Show(id string) (*Issue, error)
+	Create(opts CreateOptions) (*Issue, error)
+	CreateWithID(id string, opts CreateOptions) (*Issue, error)
+	Update(id string, opts UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+	AddDependency(issue, dependsOn string) error
+}
+
+// ImportIssues creates (or updates) beads from parsed records. Source IDs
+// that look like bead IDs are kept unless they collide; other IDs (GitHub
+// numbers, Jira keys) get new bead IDs and are noted in the description.
+// Parent and dependency references are rewritten to the imported IDs;
+// parents are created before their children. Records are processed
+// independently: a failed record is reported and the
+// rest still import.
+func ImportIssues(b importer, issues []*Issue, opts ImportOptions) []ImportAction {
+	onCollision := opts.OnCollision
+	if onCollision == "" {
+		onCollision = CollisionSkip
+	}
+
+	actions := make([]ImportAction, len(issues))
+	idMap := make(map[string]string) // source ID → bead ID
+
+	for _, i := range importOrder(issues) {
+		issue := issues[i]
+		a := &actions[i]
+		a.SourceID = issue.ID
+		if strings.TrimSpace(issue.Title) == "" {
+			a.Action, a.Detail = "failed", "missing title"
+			continue
+		}
+
+		keep := !opts.NewIDs && beadIDPattern.MatchString(issue.ID)
+		var existing *Issue
+		if keep {
+			if ex, err := b.Show(issue.ID); err == nil {
+				existing = ex
+			} else if !errors.Is(err, ErrNotFound) {
+				a.Action, a.Detail = "failed", err.Error()
+				continue
+			}
+		}
+
+		if existing != nil {
+			switch onCollision {
+			case CollisionSkip:
+				a.ID, a.Action, a.Detail = existing.ID, "skipped", "ID already exists"
+				idMap[issue.ID] = existing.ID
+				continue
+			case CollisionUpdate:
+				a.ID, a.Action = existing.ID, "updated"
+				idMap[issue.ID] = existing.ID
+				if !opts.DryRun {
+					if err := updateImported(b, existing, issue); err != nil {
+						a.Action, a.Detail = "failed", err.Error()
+					}
+				}
+				continue
+			case CollisionNew:
+				keep = false
+				a.Detail = "ID already exists; imported with a new ID"
+			default:
+				a.Action, a.Detail = "failed", fmt.Sprintf("unknown collision policy %q", onCollision)
+				continue
+			}
+		}
+
+		a.Action = "created"
+		if opts.DryRun {
+			if keep {
+				a.ID = issue.ID
+				idMap[issue.ID] = issue.ID
+			}
+			continue
+		}
+
+		parent := ""
+		if issue.Parent != "" {
+			parent = remapID(idMap, issue.Parent)
+		}
+		created, err := createImported(b, issue, keep, parent)
+		if created == nil {
+			a.Action, a.Detail = "failed", err.Error()
+			continue
+		}
+		if err != nil {
+			a.Detail = err.Error() // Created, but some fields didn't apply
+		}
+		a.ID = created.ID
+		if issue.ID != "" {
+			idMap[issue.ID] = created.ID
+		}
+	}
+
+	if opts.DryRun {
+		return actions
+	}
+
+	// Second pass: dependencies, once every record has its final ID
+	for i, issue := range issues {
+		a := &actions[i]
+		if a.Action != "created" && a.Action != "updated" {
+			continue
+		}
+		var errs []string
+		for _, dep := range issue.DependsOn {
+			dep = remapID(idMap, dep)
+			if err := b.AddDependency(a.ID, dep); err != nil {
+				errs = append(errs, fmt.Sprintf("dependency %s: %v", dep, err))
+			}
+		}
+		if len(errs) > 0 {
+			a.Detail = strings.TrimPrefix(a.Detail+"; "+strings.Join(errs, "; "), "; ")
+		}
+	}
+	return actions
+}
+
+// importOrder returns record indexes with every parent that is part of the
+// import ahead of its children, otherwise preserving input order.
+func importOrder(issues []*Issue) []int {
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		if issue.ID != "" {
+			index[issue.ID] = i
+		}
+	}
+	order := make([]int, 0, len(issues))
+	state := make([]int, len(issues)) // 0 = pending, 1 = visiting, 2 = done
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return // Done, or a parent cycle: fall back to input order
+		}
+		state[i] = 1
+		if p, ok := index[issues[i].Parent]; ok && p != i {
+			visit(p)
+		}
+		state[i] = 2
+		order = append(order, i)
+	}
+	for i := range issues {
+		visit(i)
+	}
+	return order
+}
+
+func remapID(idMap map[string]string, id string) string {
+	if mapped, ok := idMap[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+func createImported(b importer, issue *Issue, keepID bool, parent string) (*Issue, error) {
+	priority := issue.Priority
+	if priority < 0 {
+		priority = 2
+	}
+	opts := CreateOptions{
+		Title:       issue.Title,
+		Type:        issue.Type,
+		Priority:    priority,
+		Description: issue.Description,
+		Parent:      parent,
+	}
+
+	var created *Issue
+	var err error
+	if keepID {
+		created, err = b.CreateWithID(issue.ID, opts)
+	} else {
+		if issue.ID != "" {
+			opts.Description = strings.TrimSpace(opts.Description + "\n\nImported from: " + issue.ID)
+		}
+		created, err = b.Create(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	update := UpdateOptions{AddLabels: issue.Labels}
+	if issue.Assignee != "" {
+		update.Assignee = &issue.Assignee
+	}
+	if issue.Status != "" && issue.Status != "open" && issue.Status != "closed" {
+		update.Status = &issue.Status
+	}
+	if update.Assignee != nil || update.Status != nil || len(update.AddLabels) > 0 {
+		if err := b.Update(created.ID, update); err != nil {
+			return created, fmt.Errorf("created %s but setting fields failed: %w", created.ID, err)
+		}
+	}
+	if issue.Status == "closed" {
+		if err := b.CloseWithReason("imported", created.ID); err != nil {
+			return created, fmt.Errorf("created %s but closing failed: %w", created.ID, err)
+		}
+	}
+	return created, nil
+}
+
+func updateImported(b importer, existing, issue *Issue) error {
+	update := UpdateOptions{
+		Title:       &issue.Title,
+		Description: &issue.Description,
+	}
+	if issue.Priority >= 0 {
+		update.Priority = &issue.Priority
+	}
+	if issue.Assignee != existing.Assignee {
+		update.Assignee = &issue.Assignee
+	}
+	if issue.Status != "" && issue.Status != existing.Status && issue.Status != "closed" {
+		update.Status = &issue.Status
+	}
+	if len(issue.Labels) > 0 {
+		labels := append([]string(nil), issue.Labels...)
+		sort.Strings(labels)
+		update.SetLabels = labels
+	}
+	if err := b.Update(existing.ID, update); err != nil {
+		return err
+	}
+	if issue.Status == "closed" && existing.Status != "closed" {
+		return b.CloseWithReason("imported", existing.ID)
+	}
+	return nil
+}
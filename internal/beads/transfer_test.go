@@ -0,0 +1,272 @@
+package beads
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleTransferIssues() []*Issue {
+	return []*Issue{
+		{
+			ID: "gt-epic", Title: "Epic", Status: "open", Priority: 1, Type: "epic",
+			Labels: []string{"sprint-4"},
+		},
+		{
+			ID: "gt-a1", Title: "Child, with comma", Status: "in_progress", Priority: 2, Type: "task",
+			Assignee: "gastown/crew/max", Parent: "gt-epic", DependsOn: []string{"gt-b2"},
+			Description: "Line one\n## Not a heading\nLine three",
+		},
+		{
+			ID: "gt-b2", Title: "Done thing", Status: "closed", Priority: 3, Type: "bug",
+		},
+	}
+}
+
+func sampleTransferComments() map[string][]Comment {
+	return map[string][]Comment{
+		"gt-a1": {
+			{Author: "overseer", Timestamp: "2026-01-02T03:04:05Z", Body: "# Repro\nRun it twice."},
+			{Author: "gastown/crew/max", Timestamp: "2026-01-02T04:00:00Z", Body: "Fixed in `engineer.go`."},
+		},
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	for _, format := range TransferFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			// Discussions are exported but not imported, and mustn't leak into descriptions
+			if err := ExportIssuesWithComments(&buf, format, sampleTransferIssues(), sampleTransferComments()); err != nil {
+				t.Fatalf("ExportIssues: %v", err)
+			}
+			got, err := ParseImport(&buf, format, nil)
+			if err != nil {
+				t.Fatalf("ParseImport: %v\n%s", err, buf.String())
+			}
+			want := sampleTransferIssues()
+			if len(got) != len(want) {
+				t.Fatalf("got %d issues, want %d", len(got), len(want))
+			}
+			for i := range want {
+				g, w := got[i], want[i]
+				if g.ID != w.ID || g.Title != w.Title || g.Status != w.Status ||
+					g.Priority != w.Priority || g.Type != w.Type || g.Assignee != w.Assignee ||
+					g.Parent != w.Parent || g.Description != w.Description {
+					t.Errorf("issue %d: got %+v, want %+v", i, g, w)
+				}
+				if !reflect.DeepEqual(g.DependsOn, w.DependsOn) {
+					t.Errorf("issue %d DependsOn = %v, want %v", i, g.DependsOn, w.DependsOn)
+				}
+				if !reflect.DeepEqual(g.Labels, w.Labels) {
+					t.Errorf("issue %d Labels = %v, want %v", i, g.Labels, w.Labels)
+				}
+			}
+		})
+	}
+}
+
+func TestExportIssuesWithComments(t *testing.T) {
+	for _, format := range []string{FormatJSONL, FormatGitHub, FormatMarkdown} {
+		var buf bytes.Buffer
+		if err := ExportIssuesWithComments(&buf, format, sampleTransferIssues(), sampleTransferComments()); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		out := buf.String()
+		for _, want := range []string{"Run it twice.", "gastown/crew/max"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s export is missing %q:\n%s", format, want, out)
+			}
+		}
+		if format == FormatMarkdown && !strings.Contains(out, "\\# Repro") {
+			t.Errorf("markdown comment heading not escaped:\n%s", out)
+		}
+	}
+}
+
+func TestParseImport_JiraCSV(t *testing.T) {
+	in := "Issue key,Summary,Status,Priority,Issue Type,Labels,Labels,Story Points,Epic Link\n" +
+		"PROJ-7,Fix login,To Do,Highest,Bug,auth,web,3,PROJ-1\n"
+
+	got, err := ParseImport(strings.NewReader(in), FormatCSV, map[string]string{"Epic Link": "parent"})
+	if err != nil {
+		t.Fatalf("ParseImport: %v", err)
+	}
+	want := &Issue{
+		ID: "PROJ-7", Title: "Fix login", Status: "open", Priority: 0, Type: "bug",
+		Labels: []string{"auth", "web"}, Parent: "PROJ-1",
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+
+	if _, err := ParseImport(strings.NewReader(in), FormatCSV, map[string]string{"Story Points": "points"}); err == nil {
+		t.Error("expected error mapping to an unknown field")
+	}
+	if _, err := ParseImport(strings.NewReader("title,priority\nx,someday\n"), FormatCSV, nil); err == nil {
+		t.Error("expected error for unrecognized priority")
+	}
+}
+
+func TestParseImport_GitHubWithoutTrailer(t *testing.T) {
+	in := `[{"number": 12, "title": "Crash", "body": "It crashes", "state": "CLOSED",
+		"labels": [{"name": "bug"}, {"name": "P1"}], "assignees": [{"login": "octo"}]}]`
+	got, err := ParseImport(strings.NewReader(in), FormatGitHub, nil)
+	if err != nil {
+		t.Fatalf("ParseImport: %v", err)
+	}
+	g := got[0]
+	if g.ID != "#12" || g.Status != "closed" || g.Type != "bug" || g.Assignee != "octo" || g.Description != "It crashes" {
+		t.Errorf("got %+v", g)
+	}
+	if g.Priority != -1 {
+		t.Errorf("Priority = %d, want -1 (unset)", g.Priority)
+	}
+}
+
+// fakeImporter is an in-memory importer.
+type fakeImporter struct {
+	issues map[string]*Issue
+	deps   []string
+	calls  []string
+	next   int
+}
+
+func newFakeImporter(existing ...*Issue) *fakeImporter {
+	f := &fakeImporter{issues: make(map[string]*Issue)}
+	for _, issue := range existing {
+		f.issues[issue.ID] = issue
+	}
+	return f
+}
+
+func (f *fakeImporter) Show(id string) (*Issue, error) {
+	if issue, ok := f.issues[id]; ok {
+		return issue, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (f *fakeImporter) Create(opts CreateOptions) (*Issue, error) {
+	f.next++
+	return f.CreateWithID(fmt.Sprintf("gt-new%d", f.next), opts)
+}
+
+func (f *fakeImporter) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	if opts.Title == "boom" {
+		return nil, fmt.Errorf("create failed")
+	}
+	issue := &Issue{ID: id, Title: opts.Title, Description: opts.Description, Parent: opts.Parent, Status: "open"}
+	f.issues[id] = issue
+	f.calls = append(f.calls, "create "+id+" parent="+opts.Parent)
+	return issue, nil
+}
+
+func (f *fakeImporter) Update(id string, opts UpdateOptions) error {
+	f.calls = append(f.calls, "update "+id)
+	if opts.Title != nil {
+		f.issues[id].Title = *opts.Title
+	}
+	return nil
+}
+
+func (f *fakeImporter) CloseWithReason(reason string, ids ...string) error {
+	f.calls = append(f.calls, "close "+ids[0])
+	return nil
+}
+
+func (f *fakeImporter) AddDependency(issue, dependsOn string) error {
+	f.deps = append(f.deps, issue+"->"+dependsOn)
+	return nil
+}
+
+func TestImportIssues_RemapsParentsAndDeps(t *testing.T) {
+	f := newFakeImporter()
+	records := []*Issue{
+		{ID: "PROJ-2", Title: "Child", Priority: -1, Parent: "PROJ-1", DependsOn: []string{"PROJ-3"}},
+		{ID: "PROJ-1", Title: "Epic", Priority: -1},
+		{ID: "PROJ-3", Title: "Blocker", Priority: -1, Status: "closed"},
+		{ID: "PROJ-4", Title: "boom", Priority: -1},
+	}
+
+	actions := ImportIssues(f, records, ImportOptions{})
+
+	// Parent is created first so the child can be created under it
+	wantCalls := []string{
+		"create gt-new1 parent=",
+		"create gt-new2 parent=gt-new1",
+		"create gt-new3 parent=",
+		"close gt-new3",
+	}
+	if !reflect.DeepEqual(f.calls, wantCalls) {
+		t.Errorf("calls = %v, want %v", f.calls, wantCalls)
+	}
+	if !reflect.DeepEqual(f.deps, []string{"gt-new2->gt-new3"}) {
+		t.Errorf("deps = %v", f.deps)
+	}
+	if !strings.Contains(f.issues["gt-new2"].Description, "Imported from: PROJ-2") {
+		t.Errorf("description missing source ID: %q", f.issues["gt-new2"].Description)
+	}
+	if actions[0].ID != "gt-new2" || actions[0].Action != "created" {
+		t.Errorf("actions[0] = %+v", actions[0])
+	}
+	if actions[3].Action != "failed" {
+		t.Errorf("actions[3] = %+v, want failed", actions[3])
+	}
+}
+
+func TestImportIssues_Collisions(t *testing.T) {
+	records := func() []*Issue {
+		return []*Issue{
+			{ID: "gt-a1", Title: "Imported title", Priority: -1},
+			{ID: "gt-z9", Title: "Fresh", Priority: -1, DependsOn: []string{"gt-a1"}},
+		}
+	}
+
+	tests := []struct {
+		policy     string
+		wantAction string
+		wantID     string
+		wantTitle  string
+	}{
+		{CollisionSkip, "skipped", "gt-a1", "Existing"},
+		{CollisionUpdate, "updated", "gt-a1", "Imported title"},
+		{CollisionNew, "created", "gt-new1", "Existing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			f := newFakeImporter(&Issue{ID: "gt-a1", Title: "Existing", Status: "open"})
+			actions := ImportIssues(f, records(), ImportOptions{OnCollision: tt.policy})
+			if actions[0].Action != tt.wantAction || actions[0].ID != tt.wantID {
+				t.Errorf("actions[0] = %+v", actions[0])
+			}
+			if f.issues["gt-a1"].Title != tt.wantTitle {
+				t.Errorf("existing title = %q, want %q", f.issues["gt-a1"].Title, tt.wantTitle)
+			}
+			if actions[1].ID != "gt-z9" {
+				t.Errorf("non-colliding bead ID not kept: %+v", actions[1])
+			}
+			// Dependencies follow the colliding record to wherever it went
+			if want := []string{"gt-z9->" + tt.wantID}; !reflect.DeepEqual(f.deps, want) {
+				t.Errorf("deps = %v, want %v", f.deps, want)
+			}
+		})
+	}
+}
+
+func TestImportIssues_DryRun(t *testing.T) {
+	f := newFakeImporter(&Issue{ID: "gt-a1", Title: "Existing"})
+	actions := ImportIssues(f, []*Issue{
+		{ID: "gt-a1", Title: "x", Priority: -1},
+		{ID: "gt-b2", Title: "y", Priority: -1},
+	}, ImportOptions{DryRun: true})
+
+	if len(f.calls) != 0 || len(f.deps) != 0 {
+		t.Errorf("dry run wrote: %v %v", f.calls, f.deps)
+	}
+	if actions[0].Action != "skipped" || actions[1].Action != "created" || actions[1].ID != "gt-b2" {
+		t.Errorf("actions = %+v", actions)
+	}
+}
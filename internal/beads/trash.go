@@ -0,0 +1,180 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trash: deleting a bead through Gas Town moves it to the "deleted" status
+// instead of removing it. A trashed bead keeps its content, comments and
+// relations and can be restored to the status it had; it is purged (hard
+// deleted) once it has been in the trash for the retention period. The
+// status it was trashed from is kept in its activity log.
+
+// StatusDeleted is the status of a bead in the trash.
+const StatusDeleted = "deleted"
+
+// DefaultTrashRetention is how long a trashed bead is kept before purging.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// trashedFromPrefix starts the Detail of the history entry recorded when a
+// bead is trashed; the status it was trashed from follows.
+const trashedFromPrefix = "from "
+
+// TrashedBead is a bead in the trash.
+type TrashedBead struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Type      string    `json:"type,omitempty"`
+	From      string    `json:"from,omitempty"` // Status it was trashed from
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by,omitempty"`
+}
+
+// PurgeAt returns when the bead becomes due for purging.
+func (t TrashedBead) PurgeAt(retention time.Duration) time.Time {
+	return t.DeletedAt.Add(retention)
+}
+
+// ensureCustomStatus registers status as a custom bd status.
+func (b *Beads) ensureCustomStatus(status string) error {
+	custom := b.ConfigList("status.custom")
+	for _, s := range custom {
+		if s == status {
+			return nil
+		}
+	}
+	if err := b.ConfigSet("status.custom", strings.Join(append(custom, status), ",")); err != nil {
+		return fmt.Errorf("registering %s status: %w", status, err)
+	}
+	return nil
+}
+
+// Trash moves id to the trash. reason, if set, is recorded with it.
+func (b *Beads) Trash(id, reason string) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if issue.Status == StatusDeleted {
+		return fmt.Errorf("%s is already in the trash", id)
+	}
+	if err := b.ensureCustomStatus(StatusDeleted); err != nil {
+		return err
+	}
+	if _, err := b.run("update", id, "--status="+StatusDeleted); err != nil {
+		return err
+	}
+	detail := trashedFromPrefix + issue.Status
+	if reason != "" {
+		detail += ": " + reason
+	}
+	b.recordStatus(StatusDeleted, detail, id)
+	return nil
+}
+
+// Restore takes id out of the trash. An empty status restores the status it
+// was trashed from, or open when that is not known. It returns the status
+// the bead was restored to.
+func (b *Beads) Restore(id, status string) (string, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return "", err
+	}
+	if issue.Status != StatusDeleted {
+		return "", fmt.Errorf("%s is not in the trash (status %s)", id, issue.Status)
+	}
+	if status == "" {
+		entries, _ := ReadHistory(b.getResolvedBeadsDir(), id)
+		status = trashedFrom(entries).From
+	}
+	if status == "" || status == StatusDeleted {
+		status = "open"
+	}
+	if _, err := b.run("update", id, "--status="+status); err != nil {
+		return "", err
+	}
+	b.recordStatus(status, "restored from trash", id)
+	return status, nil
+}
+
+// ListTrash returns the beads in the trash, oldest deletion first.
+func (b *Beads) ListTrash() ([]TrashedBead, error) {
+	issues, err := b.List(ListOptions{Status: StatusDeleted})
+	if err != nil {
+		return nil, err
+	}
+	trashed := make([]TrashedBead, 0, len(issues))
+	for _, issue := range issues {
+		entries, _ := ReadHistory(b.getResolvedBeadsDir(), issue.ID)
+		t := trashedFrom(entries)
+		t.ID, t.Title, t.Type = issue.ID, issue.Title, issue.Type
+		if t.DeletedAt.IsZero() {
+			// Trashed outside gt: the last update is the best we have
+			t.DeletedAt, _ = time.Parse(time.RFC3339, issue.UpdatedAt)
+		}
+		trashed = append(trashed, t)
+	}
+	sort.Slice(trashed, func(i, j int) bool { return trashed[i].DeletedAt.Before(trashed[j].DeletedAt) })
+	return trashed, nil
+}
+
+// PurgeTrash hard deletes the trashed beads that have been in the trash for
+// longer than retention, returning their IDs. With dryRun nothing is
+// deleted.
+func (b *Beads) PurgeTrash(retention time.Duration, now time.Time, dryRun bool) ([]string, error) {
+	trashed, err := b.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	var purged []string
+	var errs []string
+	for _, t := range ExpiredTrash(trashed, retention, now) {
+		if !dryRun {
+			if err := b.DeleteHard(t.ID); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", t.ID, err))
+				continue
+			}
+		}
+		purged = append(purged, t.ID)
+	}
+	if len(errs) > 0 {
+		return purged, fmt.Errorf("purging trash: %s", strings.Join(errs, "; "))
+	}
+	return purged, nil
+}
+
+// ExpiredTrash returns the beads in trashed that are due for purging at
+// now. Beads with no known deletion time are kept.
+func ExpiredTrash(trashed []TrashedBead, retention time.Duration, now time.Time) []TrashedBead {
+	var expired []TrashedBead
+	for _, t := range trashed {
+		if !t.DeletedAt.IsZero() && !now.Before(t.PurgeAt(retention)) {
+			expired = append(expired, t)
+		}
+	}
+	return expired
+}
+
+// trashedFrom returns when, by whom and from which status a bead was last
+// trashed, according to its activity log. Fields are zero when the log has
+// no record of it.
+func trashedFrom(entries []HistoryEntry) TrashedBead {
+	var t TrashedBead
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Kind != HistoryStatus || e.Value != StatusDeleted {
+			continue
+		}
+		t.DeletedAt, _ = time.Parse(time.RFC3339, e.Timestamp)
+		t.DeletedBy = e.Actor
+		if from, ok := strings.CutPrefix(e.Detail, trashedFromPrefix); ok {
+			from, _, _ = strings.Cut(from, ":")
+			t.From = from
+		}
+		break
+	}
+	return t
+}
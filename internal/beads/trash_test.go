@@ -0,0 +1,43 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrashedFrom(t *testing.T) {
+	entries := []HistoryEntry{
+		{Timestamp: "2026-03-01T10:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: StatusDeleted, Detail: "from open", Actor: "mayor"},
+		{Timestamp: "2026-03-02T10:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: "open", Detail: "restored from trash"},
+		{Timestamp: "2026-03-03T10:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: StatusDeleted, Detail: "from in_progress: duplicate of gt-b", Actor: "gastown/crew/joe"},
+		{Timestamp: "2026-03-04T10:00:00Z", IssueID: "gt-a", Kind: HistoryComment, Value: "oops"},
+	}
+	got := trashedFrom(entries)
+	if got.From != "in_progress" || got.DeletedBy != "gastown/crew/joe" {
+		t.Errorf("trashedFrom = %+v", got)
+	}
+	if want := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC); !got.DeletedAt.Equal(want) {
+		t.Errorf("DeletedAt = %s, want %s", got.DeletedAt, want)
+	}
+
+	if got := trashedFrom(entries[1:2]); !got.DeletedAt.IsZero() || got.From != "" {
+		t.Errorf("no trash entry: trashedFrom = %+v", got)
+	}
+}
+
+func TestExpiredTrash(t *testing.T) {
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	trashed := []TrashedBead{
+		{ID: "gt-old", DeletedAt: now.Add(-31 * 24 * time.Hour)},
+		{ID: "gt-edge", DeletedAt: now.Add(-DefaultTrashRetention)},
+		{ID: "gt-new", DeletedAt: now.Add(-time.Hour)},
+		{ID: "gt-unknown"},
+	}
+	expired := ExpiredTrash(trashed, DefaultTrashRetention, now)
+	if len(expired) != 2 || expired[0].ID != "gt-old" || expired[1].ID != "gt-edge" {
+		t.Errorf("expired = %+v", expired)
+	}
+	if expired := ExpiredTrash(trashed, 0, now); len(expired) != 3 {
+		t.Errorf("zero retention: expired = %+v", expired)
+	}
+}
@@ -0,0 +1,168 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Verification: a rig can require evidence before beads of some types close
+// (config.VerifyConfig), so "done" means independently verified rather than
+// self-reported. Finished work waits in the verify status while evidence is
+// attached: a test run link, an artifact, a sign-off by someone other than
+// the assignee. Evidence is kept in the bead's activity log, with who added
+// it.
+
+// StatusVerify is the status of a bead whose work is done and awaits
+// verification.
+const StatusVerify = "verify"
+
+// HistoryEvidence is the history entry kind for verification evidence;
+// Value is the evidence kind and Detail its reference.
+const HistoryEvidence = "evidence"
+
+// ErrUnverified is returned when a bead lacks the evidence its type requires
+// to close.
+var ErrUnverified = errors.New("not verified")
+
+// Evidence is one piece of verification evidence.
+type Evidence struct {
+	Kind string `json:"kind"`          // config.EvidenceTest, EvidenceArtifact, or EvidenceSignoff
+	Ref  string `json:"ref,omitempty"` // Link or attachment name; a note for sign-offs
+	By   string `json:"by,omitempty"`
+	At   string `json:"at"`
+}
+
+// AddEvidence records evidence of kind for id. Tests need a link; artifacts
+// a link or the name of one of the bead's attachments. The assignee can't
+// sign off their own work.
+func (b *Beads) AddEvidence(id, kind, ref string) (*Evidence, error) {
+	if !slices.Contains(config.EvidenceKinds, kind) {
+		return nil, fmt.Errorf("unknown evidence kind %q (expected %s)", kind, strings.Join(config.EvidenceKinds, ", "))
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	actor := b.getActor()
+	switch kind {
+	case config.EvidenceTest:
+		if ref == "" {
+			return nil, fmt.Errorf("test evidence needs a link to the test run")
+		}
+	case config.EvidenceArtifact:
+		if ref == "" {
+			return nil, fmt.Errorf("artifact evidence needs a link or an attachment name")
+		}
+		if !strings.Contains(ref, "://") {
+			a, err := FindAttachment(b.getResolvedBeadsDir(), id, ref)
+			if err != nil {
+				return nil, fmt.Errorf("artifact %q: %w (attach it first with 'gt bead attach')", ref, err)
+			}
+			ref = a.Name + "@" + a.ShortHash()
+		}
+	case config.EvidenceSignoff:
+		if actor != "" && actor == issue.Assignee {
+			return nil, fmt.Errorf("%s is assigned to %s, who can't sign off their own work", id, actor)
+		}
+	}
+
+	e := HistoryEntry{Timestamp: currentTimestamp(), IssueID: id, Kind: HistoryEvidence, Value: kind, Detail: ref, Actor: actor}
+	if err := b.RecordHistory(e); err != nil {
+		return nil, err
+	}
+	return &Evidence{Kind: kind, Ref: ref, By: actor, At: e.Timestamp}, nil
+}
+
+// ListEvidence returns the evidence recorded for id, oldest first.
+func ListEvidence(beadsDir, id string) ([]Evidence, error) {
+	entries, err := ReadHistory(beadsDir, id)
+	if err != nil {
+		return nil, err
+	}
+	return evidenceFromHistory(entries), nil
+}
+
+func evidenceFromHistory(entries []HistoryEntry) []Evidence {
+	var evidence []Evidence
+	for _, e := range entries {
+		if e.Kind == HistoryEvidence {
+			evidence = append(evidence, Evidence{Kind: e.Value, Ref: e.Detail, By: e.Actor, At: e.Timestamp})
+		}
+	}
+	return evidence
+}
+
+// MissingEvidence returns the required kinds evidence doesn't cover, in
+// required's order. Sign-offs by the assignee don't count.
+func MissingEvidence(required []string, evidence []Evidence, assignee string) []string {
+	have := make(map[string]bool)
+	for _, e := range evidence {
+		if e.Kind == config.EvidenceSignoff && e.By != "" && e.By == assignee {
+			continue
+		}
+		have[e.Kind] = true
+	}
+	var missing []string
+	for _, kind := range required {
+		if !have[kind] && !slices.Contains(missing, kind) {
+			missing = append(missing, kind)
+		}
+	}
+	return missing
+}
+
+// CheckVerified returns an ErrUnverified error naming the missing evidence
+// if issue can't close under cfg.
+func (b *Beads) CheckVerified(issue *Issue, cfg *config.VerifyConfig) error {
+	required := cfg.Required(issue.Type)
+	if len(required) == 0 {
+		return nil
+	}
+	evidence, err := ListEvidence(b.getResolvedBeadsDir(), issue.ID)
+	if err != nil {
+		return err
+	}
+	if missing := MissingEvidence(required, evidence, issue.Assignee); len(missing) > 0 {
+		return fmt.Errorf("%s %w: missing %s evidence", issue.ID, ErrUnverified, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// RequestVerify moves id to the verify status.
+func (b *Beads) RequestVerify(id, detail string) error {
+	if err := b.ensureCustomStatus(StatusVerify); err != nil {
+		return err
+	}
+	if _, err := b.run("update", id, "--status="+StatusVerify); err != nil {
+		return err
+	}
+	b.recordStatus(StatusVerify, detail, id)
+	return nil
+}
+
+// CloseOrVerify closes id with reason if it has the evidence cfg requires,
+// and otherwise moves it to the verify status. It reports whether it closed
+// the bead.
+func (b *Beads) CloseOrVerify(reason string, cfg *config.VerifyConfig, id string) (bool, error) {
+	if cfg == nil || len(cfg.Types) == 0 {
+		return true, b.CloseWithReason(reason, id)
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return false, err
+	}
+	if err := b.CheckVerified(issue, cfg); err != nil {
+		if !errors.Is(err, ErrUnverified) {
+			return false, err
+		}
+		if issue.Status == StatusVerify {
+			return false, nil
+		}
+		return false, b.RequestVerify(id, reason)
+	}
+	return true, b.CloseWithReason(reason, id)
+}
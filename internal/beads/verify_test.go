@@ -0,0 +1,50 @@
+package beads
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMissingEvidence(t *testing.T) {
+	required := []string{config.EvidenceTest, config.EvidenceSignoff}
+	evidence := []Evidence{
+		{Kind: config.EvidenceTest, Ref: "https://ci/1", By: "gastown/polecats/nux"},
+		{Kind: config.EvidenceSignoff, By: "gastown/polecats/nux"}, // The assignee's own
+	}
+	if got := MissingEvidence(required, evidence, "gastown/polecats/nux"); strings.Join(got, ",") != "signoff" {
+		t.Errorf("missing = %v, want [signoff]: the assignee can't sign off", got)
+	}
+
+	evidence = append(evidence, Evidence{Kind: config.EvidenceSignoff, By: "gastown/crew/max"})
+	if got := MissingEvidence(required, evidence, "gastown/polecats/nux"); len(got) != 0 {
+		t.Errorf("missing = %v, want none", got)
+	}
+}
+
+func TestCheckVerified(t *testing.T) {
+	dir := t.TempDir()
+	b := NewWithBeadsDir(dir, dir)
+	cfg := &config.VerifyConfig{Types: map[string][]string{"feature": {config.EvidenceTest}}}
+	feature := &Issue{ID: "gt-1", Type: "feature", Assignee: "gastown/polecats/nux"}
+
+	if err := b.CheckVerified(&Issue{ID: "gt-2", Type: "chore"}, cfg); err != nil {
+		t.Errorf("chore isn't verified, got %v", err)
+	}
+	err := b.CheckVerified(feature, cfg)
+	if !errors.Is(err, ErrUnverified) || !strings.Contains(err.Error(), "test") {
+		t.Fatalf("CheckVerified = %v, want missing test evidence", err)
+	}
+
+	t.Setenv("BD_ACTOR", "gastown/polecats/nux")
+	b.recordHistory(HistoryEntry{IssueID: "gt-1", Kind: HistoryEvidence, Value: config.EvidenceTest, Detail: "https://ci/1"})
+	if err := b.CheckVerified(feature, cfg); err != nil {
+		t.Errorf("CheckVerified after evidence = %v", err)
+	}
+	evidence, _ := ListEvidence(dir, "gt-1")
+	if len(evidence) != 1 || evidence[0].Ref != "https://ci/1" || evidence[0].By != "gastown/polecats/nux" {
+		t.Errorf("ListEvidence = %+v", evidence)
+	}
+}
@@ -0,0 +1,148 @@
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watchlists are personal: each user or agent identity (a mail address
+// such as "gastown/crew/joe" or "mayor/") keeps its own list of beads to
+// follow, independent of what it is assigned. Changes to watched beads are
+// read from their activity logs (see history.go).
+
+// WatchlistDir returns where a town keeps its watchlists.
+func WatchlistDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "watchlists")
+}
+
+// WatchlistPath returns the watchlist file of identity.
+func WatchlistPath(townRoot, identity string) string {
+	name := strings.ReplaceAll(strings.Trim(identity, "/"), "/", "--")
+	if name == "" {
+		name = "overseer"
+	}
+	return filepath.Join(WatchlistDir(townRoot), name+".json")
+}
+
+// Watchlist is one identity's watched beads, and how far it has read.
+type Watchlist struct {
+	Identity string        `json:"identity"`
+	Beads    []WatchedBead `json:"beads"`
+
+	// FeedSeen and DigestSent are the timestamps up to which changes were
+	// shown in the feed and sent in a digest. They advance separately, so
+	// reading the feed does not empty the next digest.
+	FeedSeen   string `json:"feed_seen,omitempty"`
+	DigestSent string `json:"digest_sent,omitempty"`
+}
+
+// WatchedBead is a bead on a watchlist.
+type WatchedBead struct {
+	ID    string `json:"id"`
+	Added string `json:"added"`
+}
+
+// LoadWatchlist returns identity's watchlist; a missing one is empty.
+func LoadWatchlist(townRoot, identity string) (*Watchlist, error) {
+	w := &Watchlist{Identity: identity}
+	data, err := os.ReadFile(WatchlistPath(townRoot, identity)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return w, nil
+		}
+		return nil, fmt.Errorf("reading watchlist: %w", err)
+	}
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, fmt.Errorf("parsing watchlist %s: %w", WatchlistPath(townRoot, identity), err)
+	}
+	return w, nil
+}
+
+// Save writes the watchlist.
+func (w *Watchlist) Save(townRoot string) error {
+	path := WatchlistPath(townRoot, w.Identity)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating watchlist directory: %w", err)
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: not sensitive
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	return nil
+}
+
+// Watching reports whether id is on the watchlist.
+func (w *Watchlist) Watching(id string) bool {
+	for _, b := range w.Beads {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Add puts id on the watchlist and reports whether it was new.
+func (w *Watchlist) Add(id string, now time.Time) bool {
+	if w.Watching(id) {
+		return false
+	}
+	w.Beads = append(w.Beads, WatchedBead{ID: id, Added: now.UTC().Format(time.RFC3339)})
+	sort.Slice(w.Beads, func(i, j int) bool { return w.Beads[i].ID < w.Beads[j].ID })
+	return true
+}
+
+// Remove takes id off the watchlist and reports whether it was there.
+func (w *Watchlist) Remove(id string) bool {
+	for i, b := range w.Beads {
+		if b.ID == id {
+			w.Beads = append(w.Beads[:i], w.Beads[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// WatchChanges returns the entries of a watched bead's activity log that
+// are newer than since and than when the bead was watched, leaving out the
+// watcher's own changes. Timestamps are RFC 3339; an empty since means the
+// beginning.
+func (w *Watchlist) WatchChanges(b WatchedBead, entries []HistoryEntry, since string) []HistoryEntry {
+	after := latestTime(since, b.Added)
+	var changes []HistoryEntry
+	for _, e := range entries {
+		if e.Actor != "" && strings.Trim(e.Actor, "/") == strings.Trim(w.Identity, "/") {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || !t.After(after) {
+			continue
+		}
+		changes = append(changes, e)
+	}
+	return changes
+}
+
+// latestTime returns the later of two RFC 3339 timestamps; unparseable
+// ones count as the zero time.
+func latestTime(a, b string) time.Time {
+	ta, _ := time.Parse(time.RFC3339, a)
+	tb, _ := time.Parse(time.RFC3339, b)
+	if tb.After(ta) {
+		return tb
+	}
+	return ta
+}
@@ -0,0 +1,64 @@
+package beads
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchlistPersistence(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := WatchlistPath(townRoot, "gastown/crew/joe"); filepath.Base(got) != "gastown--crew--joe.json" {
+		t.Errorf("path = %s", got)
+	}
+	if got := WatchlistPath(townRoot, "mayor/"); filepath.Base(got) != "mayor.json" {
+		t.Errorf("path = %s", got)
+	}
+
+	w, err := LoadWatchlist(townRoot, "gastown/crew/joe")
+	if err != nil || len(w.Beads) != 0 {
+		t.Fatalf("new watchlist = %+v, %v", w, err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if !w.Add("gt-b", now) || !w.Add("gt-a", now) || w.Add("gt-a", now) {
+		t.Error("Add did not report new beads correctly")
+	}
+	if err := w.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another identity's list is separate.
+	other, _ := LoadWatchlist(townRoot, "mayor/")
+	if len(other.Beads) != 0 {
+		t.Errorf("mayor sees joe's watchlist: %+v", other.Beads)
+	}
+
+	w, err = LoadWatchlist(townRoot, "gastown/crew/joe")
+	if err != nil || len(w.Beads) != 2 || w.Beads[0].ID != "gt-a" {
+		t.Fatalf("reloaded = %+v, %v", w, err)
+	}
+	if !w.Remove("gt-a") || w.Remove("gt-a") || w.Watching("gt-a") || !w.Watching("gt-b") {
+		t.Error("Remove did not take gt-a off")
+	}
+}
+
+func TestWatchChanges(t *testing.T) {
+	w := &Watchlist{Identity: "gastown/crew/joe"}
+	b := WatchedBead{ID: "gt-a", Added: "2026-03-01T10:00:00Z"}
+	entries := []HistoryEntry{
+		{Timestamp: "2026-03-01T09:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: "open"},              // before watching
+		{Timestamp: "2026-03-01T11:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: "in_progress"},       // new
+		{Timestamp: "2026-03-01T12:00:00Z", IssueID: "gt-a", Kind: HistoryComment, Actor: "gastown/crew/joe"}, // own change
+		{Timestamp: "2026-03-01T13:00:00Z", IssueID: "gt-a", Kind: HistoryStatus, Value: "closed"},            // new
+	}
+
+	if got := w.WatchChanges(b, entries, ""); len(got) != 2 || got[0].Value != "in_progress" || got[1].Value != "closed" {
+		t.Errorf("since watched = %+v", got)
+	}
+	if got := w.WatchChanges(b, entries, "2026-03-01T11:00:00Z"); len(got) != 1 || got[0].Value != "closed" {
+		t.Errorf("since 11:00 = %+v", got)
+	}
+	if got := w.WatchChanges(b, entries, "2026-03-01T13:00:00Z"); len(got) != 0 {
+		t.Errorf("since 13:00 = %+v", got)
+	}
+}
@@ -0,0 +1,199 @@
+// Package changelog assembles release notes from merged merge requests and
+// the beads they delivered.
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/version"
+)
+
+// TemplateFileName is the per-rig changelog template, stored under <rig>/settings/.
+const TemplateFileName = "changelog.md.tmpl"
+
+// Categories, in the order they are rendered.
+const (
+	CategoryFeatures = "Features"
+	CategoryFixes    = "Fixes"
+	CategoryChores   = "Chores"
+	CategoryOther    = "Other"
+)
+
+var categoryOrder = []string{CategoryFeatures, CategoryFixes, CategoryChores, CategoryOther}
+
+// categoryLabels maps source-bead labels (and issue types) to a category.
+var categoryLabels = map[string]string{
+	"feature":     CategoryFeatures,
+	"enhancement": CategoryFeatures,
+	"bug":         CategoryFixes,
+	"fix":         CategoryFixes,
+	"regression":  CategoryFixes,
+	"chore":       CategoryChores,
+	"docs":        CategoryChores,
+	"refactor":    CategoryChores,
+	"ci":          CategoryChores,
+	"deps":        CategoryChores,
+}
+
+// Entry is one merged change.
+type Entry struct {
+	MRID        string    `json:"mr_id"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Title       string    `json:"title"`
+	Labels      []string  `json:"labels,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	MergeCommit string    `json:"merge_commit,omitempty"`
+	MergedAt    time.Time `json:"merged_at"`
+	Category    string    `json:"category"`
+}
+
+// Section is a group of entries under one category heading.
+type Section struct {
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// Changelog is the data passed to the template.
+type Changelog struct {
+	Rig      string    `json:"rig"`
+	Since    time.Time `json:"since"`
+	SinceRef string    `json:"since_ref"` // Tag or date the user asked for
+	Until    time.Time `json:"until"`
+	Sections []Section `json:"sections"`
+	Total    int       `json:"total"`
+}
+
+// Categorize picks a category from the source bead's labels, falling back to
+// its issue type. Labels may be bare ("bug") or namespaced ("type:bug").
+func Categorize(labels []string, issueType string) string {
+	for _, l := range labels {
+		key := strings.ToLower(l)
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			key = key[i+1:]
+		}
+		if c, ok := categoryLabels[key]; ok {
+			return c
+		}
+	}
+	if c, ok := categoryLabels[strings.ToLower(issueType)]; ok {
+		return c
+	}
+	return CategoryOther
+}
+
+// Collect returns the MRs merged in [since, until), each joined with its
+// source bead. MRs closed for any reason other than a merge are skipped.
+func Collect(b *beads.Beads, since, until time.Time) ([]Entry, error) {
+	mrs, err := b.List(beads.ListOptions{
+		Label:  "gt:merge-request",
+		Status: "closed",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing merge requests: %w", err)
+	}
+
+	var entries []Entry
+	for _, mr := range mrs {
+		closedAt, err := time.Parse(time.RFC3339, mr.ClosedAt)
+		if err != nil || closedAt.Before(since) || !closedAt.Before(until) {
+			continue
+		}
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.CloseReason != "merged" {
+			continue
+		}
+
+		entry := Entry{
+			MRID:        mr.ID,
+			SourceIssue: fields.SourceIssue,
+			Title:       mr.Title,
+			Worker:      fields.Worker,
+			MergeCommit: fields.MergeCommit,
+			MergedAt:    closedAt,
+		}
+		issueType := ""
+		if fields.SourceIssue != "" {
+			// A missing source bead still leaves the MR itself worth listing.
+			if src, err := b.Show(fields.SourceIssue); err == nil {
+				entry.Title = src.Title
+				entry.Labels = src.Labels
+				issueType = src.Type
+			}
+		}
+		entry.Category = Categorize(entry.Labels, issueType)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Build groups entries into sections in category order, oldest merge first.
+// Empty categories are omitted.
+func Build(rig, sinceRef string, since, until time.Time, entries []Entry) *Changelog {
+	byCategory := make(map[string][]Entry)
+	for _, e := range entries {
+		byCategory[e.Category] = append(byCategory[e.Category], e)
+	}
+
+	cl := &Changelog{Rig: rig, Since: since, SinceRef: sinceRef, Until: until, Total: len(entries)}
+	for _, c := range categoryOrder {
+		group := byCategory[c]
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].MergedAt.Before(group[j].MergedAt)
+		})
+		cl.Sections = append(cl.Sections, Section{Title: c, Entries: group})
+	}
+	return cl
+}
+
+// DefaultTemplate renders a Markdown changelog.
+const DefaultTemplate = `# {{.Rig}} changes since {{.SinceRef}}
+{{range .Sections}}
+## {{.Title}}
+{{range .Entries}}
+- {{.Title}}{{if .SourceIssue}} ({{.SourceIssue}}){{end}}{{if .Worker}} — {{.Worker}}{{end}}
+{{- end}}
+{{end}}{{if not .Sections}}
+No merged changes.
+{{end}}`
+
+// TemplatePath returns the changelog template path for a rig.
+func TemplatePath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", TemplateFileName)
+}
+
+// LoadTemplate parses the rig's changelog template, or DefaultTemplate if
+// the rig has none.
+func LoadTemplate(rigPath string) (*template.Template, error) {
+	text := DefaultTemplate
+	data, err := os.ReadFile(TemplatePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err == nil {
+		text = string(data)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading changelog template: %w", err)
+	}
+
+	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
+		"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+		"short": version.ShortCommit,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing changelog template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render executes the template against a changelog.
+func (cl *Changelog) Render(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, cl)
+}
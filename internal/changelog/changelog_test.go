@@ -0,0 +1,100 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		labels    []string
+		issueType string
+		want      string
+	}{
+		{[]string{"gt:task", "feature"}, "task", CategoryFeatures},
+		{[]string{"type:bug"}, "task", CategoryFixes},
+		{[]string{"Docs"}, "", CategoryChores},
+		{nil, "bug", CategoryFixes},
+		{nil, "feature", CategoryFeatures},
+		{[]string{"urgent"}, "task", CategoryOther},
+	}
+	for _, tt := range tests {
+		if got := Categorize(tt.labels, tt.issueType); got != tt.want {
+			t.Errorf("Categorize(%v, %q) = %q, want %q", tt.labels, tt.issueType, got, tt.want)
+		}
+	}
+}
+
+func TestBuildAndRender(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(7 * 24 * time.Hour)
+	entries := []Entry{
+		{MRID: "gt-mr3", Title: "Fix crash", SourceIssue: "gt-3", Category: CategoryFixes, MergedAt: since.Add(3 * time.Hour)},
+		{MRID: "gt-mr2", Title: "Add widget", SourceIssue: "gt-2", Worker: "gastown/Nux", Category: CategoryFeatures, MergedAt: since.Add(2 * time.Hour)},
+		{MRID: "gt-mr1", Title: "Add gadget", SourceIssue: "gt-1", Category: CategoryFeatures, MergedAt: since.Add(time.Hour)},
+	}
+
+	cl := Build("gastown", "v1.0.0", since, until, entries)
+	if cl.Total != 3 || len(cl.Sections) != 2 {
+		t.Fatalf("changelog = %+v", cl)
+	}
+	if cl.Sections[0].Title != CategoryFeatures || cl.Sections[0].Entries[0].MRID != "gt-mr1" {
+		t.Errorf("first section = %+v, want features oldest first", cl.Sections[0])
+	}
+
+	tmpl, err := LoadTemplate(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := cl.Render(&sb, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{"# gastown changes since v1.0.0", "## Features", "- Add widget (gt-2) — gastown/Nux", "## Fixes"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Chores") {
+		t.Errorf("empty category rendered:\n%s", out)
+	}
+}
+
+func TestLoadTemplate_RigOverride(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := `{{range .Sections}}{{range .Entries}}* {{.MRID}} {{date .MergedAt}} {{short .MergeCommit}}
+{{end}}{{end}}`
+	if err := os.WriteFile(TemplatePath(rigPath), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := LoadTemplate(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := Build("gastown", "2026-01-01", time.Time{}, time.Time{}, []Entry{{
+		MRID: "gt-mr1", Category: CategoryOther, MergeCommit: "0123456789abcdef",
+		MergedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}})
+	var sb strings.Builder
+	if err := cl.Render(&sb, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != "* gt-mr1 2026-01-02 0123456789ab\n" {
+		t.Errorf("rendered %q", got)
+	}
+
+	if err := os.WriteFile(TemplatePath(rigPath), []byte("{{.Broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplate(rigPath); err == nil {
+		t.Error("expected parse error for broken template")
+	}
+}
@@ -264,8 +264,7 @@ func collectBeadsActivity(townRoot, actor string, since time.Time) ([]AuditEntry
 
 	// List all issues to filter by created_by and assignee
 	issues, err := b.List(beads.ListOptions{
-		Status:   "all",
-		Priority: -1,
+		Status: "all",
 	})
 	if err != nil {
 		return nil, err
@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var beadCmd = &cobra.Command{
 	Use:     "bead",
-	Aliases: []string{"bd"},
+	Aliases: []string{"bd", "beads"},
 	GroupID: GroupWork,
 	Short:   "Bead management utilities",
 	Long: `Utilities for managing beads across repositories.
@@ -26,7 +29,34 @@ prefix-based routing.
 Subcommands:
   move    Move a bead from one repository to another
   show    Show details of a bead (routes by prefix)
-  read    Alias for show`,
+  read    Alias for show
+  bench   Benchmark beads backend operation latencies
+  bulk    Close, reprioritize, retag, or reassign many beads at once
+  attach  Attach files (logs, diffs, screenshots) to a bead
+  comment   Add a comment to a bead's discussion (comments to read it)
+  seal    Encrypt a sensitive bead's content (unseal, keygen)
+  claim   Claim a bead under an expiring lease (unclaim, leases)
+  sweep   Escalate or reopen beads past their rig's staleness policies
+  next    Show (or claim) the next bead a worker should pick up
+  cost    Show agent cost per bead, epic, worker, or rig
+  fsck    Find and repair dangling relations and links
+  dedupe  Find likely-duplicate beads, and merge them
+  search  Search beads by text, referenced file, or mention
+  indexd  Keep the bead search indexes up to date in the background
+  delete  Move beads to the trash (restore, trash list, trash purge)
+  plan    Critical path and dispatch order for an epic's children
+  watch   Add beads to your watchlist, or remove them
+  watchlist  Your watched beads, their change feed, and a mailed digest
+  export  Export beads as jsonl, csv, github, or markdown
+  mirror  Publish a read-only static mirror of a rig's open beads
+  import  Import beads from those formats (GitHub/Jira migration)
+  assign  Auto-assign new beads by per-rig rules, and explain assignees
+  new     Create a bead from a template (bug, merge-request, convoy-task, ...)
+  quickadd  Create a bead from one line ("P1 bug: ... @who #label")
+  templates  List bead templates, or show one
+  schema  Show bead fields, types, statuses, and relations
+  labels  List, add, and rename labels in the town label registry
+  sync    Inspect and repair the beads sync branch across clones`,
 }
 
 var beadMoveCmd = &cobra.Command{
@@ -35,7 +65,9 @@ var beadMoveCmd = &cobra.Command{
 	Long: `Move a bead from one repository to another.
 
 This creates a copy of the bead in the target repository (with the new prefix)
-and closes the source bead with a reference to the new location.
+and closes the source bead with a reference to the new location. If closing
+the source fails, the copy's rows are removed from the target database again,
+so the bead is never left open in both.
 
 The target prefix determines which repository receives the bead.
 Common prefixes: gt- (gastown), bd- (beads), hq- (headquarters)
@@ -57,15 +89,28 @@ var beadShowCmd = &cobra.Command{
 
 This is an alias for 'gt show'. All bd show flags are supported.
 
+A bead's recorded agent cost (see 'gt bead cost'), its attachments
+(see 'gt bead attach') and its discussion (see 'gt bead comment') are
+shown after its details. --download <name>
+saves an attachment (-o <path> to choose where, -o - for stdout).
+
+--history adds the bead's activity log: every status change, priority
+change, comment and merge request gt recorded for it, oldest first. The log
+is append-only (.beads/history.jsonl). With --json only the log is printed.
+
+Content sealed with 'gt bead seal' is masked; --unlock decrypts it with
+GT_BEADS_KEY (each unlock is recorded in the town's event log).
+
 Examples:
   gt bead show gt-abc123          # Show a gastown issue
   gt bead show hq-xyz789          # Show a town-level bead
   gt bead show bd-def456          # Show a beads issue
-  gt bead show gt-abc123 --json   # Output as JSON`,
+  gt bead show gt-abc123 --json   # Output as JSON
+  gt bead show gt-abc123 --history  # Include the activity log
+  gt bead show gt-abc123 --download build.log  # Save an attachment
+  gt bead show gt-abc123 --unlock   # Decrypt sealed content`,
 	DisableFlagParsing: true, // Pass all flags through to bd show
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return runShow(cmd, args)
-	},
+	RunE:               runBeadShow,
 }
 
 var beadReadCmd = &cobra.Command{
@@ -81,9 +126,7 @@ Examples:
   gt bead read bd-def456          # Show a beads issue
   gt bead read gt-abc123 --json   # Output as JSON`,
 	DisableFlagParsing: true, // Pass all flags through to bd show
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return runShow(cmd, args)
-	},
+	RunE:               runBeadShow,
 }
 
 func init() {
@@ -94,6 +137,67 @@ func init() {
 	rootCmd.AddCommand(beadCmd)
 }
 
+// runBeadShow runs bd show, or reads the stale snapshot during a Dolt outage.
+func runBeadShow(cmd *cobra.Command, args []string) error {
+	if rest, ref, output := splitDownloadFlags(args); ref != "" {
+		return runBeadDownload(rest, ref, output)
+	}
+	if args, history := splitHistoryFlag(args); history {
+		return runBeadShowHistory(args)
+	}
+	args, unlock := splitUnlockFlag(args)
+	if served, err := showFromSnapshot(args); served {
+		return err
+	}
+	if id := beadShowID(args); id != "" {
+		cost, attachments, comments := beadCost(id), beadAttachments(id), beadComments(id)
+		if unlock || beadIsSensitive(id) {
+			if err := showSealedBead(id, args, unlock); err != nil {
+				return err
+			}
+		} else if cost != nil || len(attachments) > 0 || len(comments) > 0 {
+			// bd show knows nothing of costs, attachments or the discussion; run it, then add them
+			show := exec.Command("bd", append([]string{"show"}, args...)...) //nolint:gosec // G204: bd is a trusted internal tool
+			show.Stdout = os.Stdout
+			show.Stderr = os.Stderr
+			if err := show.Run(); err != nil {
+				return fmt.Errorf("bd show %s: %w", id, err)
+			}
+		} else {
+			return runShow(cmd, args)
+		}
+		if cost != nil {
+			printBeadCost(cost)
+		}
+		if len(attachments) > 0 {
+			printBeadAttachments(attachments)
+		}
+		if len(comments) > 0 {
+			printBeadComments(comments)
+		}
+		return nil
+	}
+	if unlock {
+		return fmt.Errorf("--unlock needs a bead ID and cannot be combined with --json")
+	}
+	return runShow(cmd, args)
+}
+
+// beadShowID returns the bead ID of human-readable gt bead show args, or ""
+// for --json and --help, whose output is left to bd.
+func beadShowID(args []string) string {
+	var id string
+	for _, arg := range args {
+		switch {
+		case arg == "--json" || arg == "--help" || arg == "-h":
+			return ""
+		case id == "" && !strings.HasPrefix(arg, "-"):
+			id = arg
+		}
+	}
+	return id
+}
+
 // moveBeadInfo holds the essential fields we need to copy when moving beads
 type moveBeadInfo struct {
 	ID          string   `json:"id"`
@@ -153,10 +257,27 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sourceRig, sourceDir, err := moveRigForPrefix(townRoot, beads.ExtractPrefix(sourceID))
+	if err != nil {
+		return err
+	}
+	targetRig, targetDir, err := moveRigForPrefix(townRoot, targetPrefix)
+	if err != nil {
+		return err
+	}
+
+	// The new ID is chosen up front so the coordinator knows which rows
+	// the create writes, and can remove them if closing the source fails.
+	newID := targetPrefix + generateShortID()
+
 	// Build create command for target
 	createArgs := []string{
 		"create",
-		"--prefix", targetPrefix,
+		"--id=" + newID,
 		"--title=" + source.Title,
 		"--type", source.Type,
 		"--priority", fmt.Sprintf("%d", source.Priority),
@@ -173,32 +294,30 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 		createArgs = append(createArgs, "--label", label)
 	}
 
-	// Create the new bead
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Stderr = os.Stderr
-	newIDBytes, err := createCmd.Output()
-	if err != nil {
-		return fmt.Errorf("creating new bead: %w", err)
-	}
-	newID := strings.TrimSpace(string(newIDBytes))
-
-	fmt.Printf("%s Created %s\n", style.Bold.Render("✓"), newID)
-
-	// Close the source bead with reference
-	closeReason := fmt.Sprintf("Moved to %s", newID)
-	closeCmd := exec.Command("bd", "close", sourceID, "--reason", closeReason)
-	closeCmd.Stderr = os.Stderr
-	if err := closeCmd.Run(); err != nil {
-		// Clean up the new bead since we couldn't close the source
-		fmt.Fprintf(os.Stderr, "Warning: failed to close source bead: %v\n", err)
-		cleanupCmd := exec.Command("bd", "close", newID, "--reason", "Cleanup: source bead close failed during move")
-		if cleanupErr := cleanupCmd.Run(); cleanupErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: also failed to clean up new bead %s: %v\n", newID, cleanupErr)
-			fmt.Fprintf(os.Stderr, "Both %s and %s remain open - manual cleanup needed\n", sourceID, newID)
-		} else {
-			fmt.Fprintf(os.Stderr, "Cleaned up new bead %s\n", newID)
+	tx := doltserver.NewMultiDBTx(townRoot)
+	tx.Add(targetRig, func() error {
+		createCmd := exec.Command("bd", createArgs...) //nolint:gosec // G204: bd is a trusted internal tool
+		createCmd.Dir = targetDir
+		createCmd.Stderr = os.Stderr
+		if err := createCmd.Run(); err != nil {
+			return fmt.Errorf("creating new bead: %w", err)
 		}
-		return err
+		fmt.Printf("%s Created %s\n", style.Bold.Render("✓"), newID)
+		return nil
+	}, moveBeadRows(newID)...)
+	tx.Add(sourceRig, func() error {
+		// Close the source bead with reference
+		closeCmd := exec.Command("bd", "close", sourceID, "--reason", fmt.Sprintf("Moved to %s", newID)) //nolint:gosec // G204: bd is a trusted internal tool
+		closeCmd.Dir = sourceDir
+		closeCmd.Stderr = os.Stderr
+		if err := closeCmd.Run(); err != nil {
+			return fmt.Errorf("closing %s: %w", sourceID, err)
+		}
+		return nil
+	}, moveBeadRows(sourceID)...)
+
+	if err := tx.Run(fmt.Sprintf("gt bead move %s to %s", sourceID, newID)); err != nil {
+		return fmt.Errorf("moving %s (reverted what was written): %w", sourceID, err)
 	}
 
 	fmt.Printf("%s Closed %s (moved to %s)\n", style.Bold.Render("✓"), sourceID, newID)
@@ -206,3 +325,28 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// moveRigForPrefix returns the rig ("hq" for town beads) and directory
+// holding beads with prefix.
+func moveRigForPrefix(townRoot, prefix string) (string, string, error) {
+	dir := beads.GetRigPathForPrefix(townRoot, prefix)
+	if dir == "" {
+		return "", "", fmt.Errorf("no route for prefix %q in %s", prefix, filepath.Join(townRoot, ".beads", "routes.jsonl"))
+	}
+	rig := beads.GetRigNameForPrefix(townRoot, prefix)
+	if rig == "" {
+		rig = "hq"
+	}
+	return rig, dir, nil
+}
+
+// moveBeadRows returns the rows bd writes when creating or closing id.
+func moveBeadRows(id string) []doltserver.RowSet {
+	return []doltserver.RowSet{
+		{Table: "issues", Column: "id", Values: []string{id}},
+		{Table: "labels", Column: "issue_id", Values: []string{id}},
+		{Table: "dependencies", Column: "issue_id", Values: []string{id}},
+		{Table: "comments", Column: "issue_id", Values: []string{id}},
+		{Table: "events", Column: "issue_id", Values: []string{id}},
+	}
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead assign command flags
+var (
+	beadAssignDryRun bool
+	beadAssignHook   bool
+	beadAssignJSON   bool
+	beadAssignForce  bool
+)
+
+// assignHookMarker identifies an on_create hook written by gt bead assign install.
+const assignHookMarker = "gt bead assign apply"
+
+var beadAssignCmd = &cobra.Command{
+	Use:   "assign",
+	Short: "Auto-assign new beads by type, label, or creator",
+	RunE:  requireSubcommand,
+	Long: `Assign new work beads from per-rig rules.
+
+Rules live under "assignment" in the rig's settings/config.json. Each rule
+matches on any of type, label, and path (a glob on the creator's address);
+the first matching rule wins, and "default" applies when none match:
+
+  {
+    "assignment": {
+      "default": "gastown/crew/triage",
+      "rules": [
+        {"label": "infra", "assignee": "gastown/crew/max"},
+        {"type": "bug", "assignee": "gastown/crew/triage"},
+        {"path": "gastown/polecats/*", "type": "epic", "assignee": "mayor/"}
+      ]
+    }
+  }
+
+Set them with 'gt rig settings set <rig> assignment '<json>''.
+
+Beads no rule or default claims go to the owner of one of their labels in
+the town ownership map (see 'gt owners'): a team's lead, or a worker.
+
+'gt bead new' applies the rules when it creates a bead. Beads created
+directly with bd get them once the rig's beads on_create hook is installed
+('gt bead assign install <rig>'). Only unassigned task, bug,
+feature, chore, and epic beads are assigned; ephemeral beads and Gas Town's
+own beads (merge requests, agents, messages, ...) are never touched.
+
+Subcommands:
+  apply     Apply the rules to a bead now
+  explain   Show which rule gives a bead its assignee, and why
+  install   Install the on_create hook for a rig`,
+}
+
+var beadAssignApplyCmd = &cobra.Command{
+	Use:   "apply <bead-id>",
+	Short: "Apply the rig's assignment rules to a bead",
+	Long: `Assign a bead according to its rig's rules, if it is unassigned.
+
+The rig is found from the bead's prefix. A comment records the rule that
+chose the assignee. The on_create hook runs this with --hook, which never
+fails so bead creation is not disrupted.
+
+Examples:
+  gt bead assign apply gt-abc12
+  gt bead assign apply gt-abc12 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadAssignApply,
+}
+
+var beadAssignExplainCmd = &cobra.Command{
+	Use:   "explain <bead-id>",
+	Short: "Explain why a bead has (or would get) its assignee",
+	Long: `Show every assignment rule checked against a bead, which one matched,
+and whether the bead's current assignee came from the rules.
+
+Examples:
+  gt bead assign explain gt-abc12
+  gt bead assign explain gt-abc12 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadAssignExplain,
+}
+
+var beadAssignInstallCmd = &cobra.Command{
+	Use:   "install <rig>",
+	Short: "Install the on_create hook that applies assignment rules",
+	Long: `Install a beads on_create hook in the rig's beads directory that runs
+'gt bead assign apply' for every new bead.
+
+An existing on_create hook not written by gt is left alone unless --force
+is given.
+
+Examples:
+  gt bead assign install gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadAssignInstall,
+}
+
+func init() {
+	beadAssignApplyCmd.Flags().BoolVar(&beadAssignDryRun, "dry-run", false, "Show the assignee without assigning")
+	beadAssignApplyCmd.Flags().BoolVar(&beadAssignHook, "hook", false, "Run as the on_create hook (quiet, never fails)")
+	_ = beadAssignApplyCmd.Flags().MarkHidden("hook")
+	beadAssignExplainCmd.Flags().BoolVar(&beadAssignJSON, "json", false, "Output as JSON")
+	beadAssignInstallCmd.Flags().BoolVar(&beadAssignForce, "force", false, "Replace an existing on_create hook")
+
+	beadAssignCmd.AddCommand(beadAssignApplyCmd)
+	beadAssignCmd.AddCommand(beadAssignExplainCmd)
+	beadAssignCmd.AddCommand(beadAssignInstallCmd)
+	beadCmd.AddCommand(beadAssignCmd)
+}
+
+// loadBeadForAssignment finds the bead's rig from its prefix and returns a
+// beads client for the rig, the bead, the rig's rules, and the town's
+// ownership map.
+func loadBeadForAssignment(id string) (*beads.Beads, *beads.Issue, *config.AssignmentConfig, *config.OwnersConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id))
+	if rigPath == "" {
+		return nil, nil, nil, nil, fmt.Errorf("no rig found for bead %s", id)
+	}
+	bd := beads.New(rigPath)
+	issue, err := bd.Show(id)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("loading %s: %w", id, err)
+	}
+	return bd, issue, config.LoadRigAssignment(rigPath), config.LoadTownOwners(townRoot), nil
+}
+
+func runBeadAssignApply(cmd *cobra.Command, args []string) error {
+	err := applyBeadAssignment(args[0])
+	if beadAssignHook {
+		return nil // Never fail bead creation
+	}
+	return err
+}
+
+func applyBeadAssignment(id string) error {
+	bd, issue, rules, owners, err := loadBeadForAssignment(id)
+	if err != nil {
+		return err
+	}
+	if issue.Assignee != "" {
+		if !beadAssignHook {
+			fmt.Printf("%s is already assigned to %s\n", id, issue.Assignee)
+		}
+		return nil
+	}
+
+	d := beads.ResolveAssigneeWithOwners(rules, owners, issue)
+	if d.Assignee == "" {
+		if !beadAssignHook {
+			fmt.Printf("%s left unassigned: %s\n", id, d.Reason)
+		}
+		return nil
+	}
+	if beadAssignDryRun {
+		fmt.Printf("Would assign %s to %s (%s)\n", id, d.Assignee, d.Reason)
+		return nil
+	}
+
+	if err := assignFromRules(bd, id, d); err != nil {
+		return err
+	}
+	if !beadAssignHook {
+		fmt.Printf("%s Assigned %s to %s\n", style.SuccessPrefix, id, d.Assignee)
+		fmt.Printf("  %s\n", style.Dim.Render(d.Reason))
+	}
+	return nil
+}
+
+// assignFromRules sets the assignee chosen by the rules and records the
+// rule that chose it in a comment.
+func assignFromRules(bd beadCreator, id string, d beads.AssignmentDecision) error {
+	if err := bd.Update(id, beads.UpdateOptions{Assignee: &d.Assignee}); err != nil {
+		return fmt.Errorf("assigning %s: %w", id, err)
+	}
+	_ = bd.AddComment(id, fmt.Sprintf("Auto-assigned to %s by %s", d.Assignee, d.Reason))
+	return nil
+}
+
+// assignmentExplanation is the gt bead assign explain --json output.
+type assignmentExplanation struct {
+	ID              string                   `json:"id"`
+	Assignee        string                   `json:"assignee,omitempty"`
+	Decision        beads.AssignmentDecision `json:"decision"`
+	FromRules       bool                     `json:"from_rules"`
+	Default         string                   `json:"default,omitempty"`
+	RulesConfigured int                      `json:"rules_configured"`
+}
+
+func runBeadAssignExplain(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	_, issue, rules, owners, err := loadBeadForAssignment(id)
+	if err != nil {
+		return err
+	}
+
+	d := beads.ResolveAssigneeWithOwners(rules, owners, issue)
+	out := assignmentExplanation{
+		ID:        id,
+		Assignee:  issue.Assignee,
+		Decision:  d,
+		FromRules: issue.Assignee != "" && issue.Assignee == d.Assignee,
+	}
+	if rules != nil {
+		out.Default = rules.Default
+		out.RulesConfigured = len(rules.Rules)
+	}
+
+	if beadAssignJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(id), issue.Title)
+	current := issue.Assignee
+	if current == "" {
+		current = style.Dim.Render("(unassigned)")
+	}
+	fmt.Printf("  Assignee: %s\n\n", current)
+
+	for i, c := range d.Checks {
+		icon := style.Dim.Render("✗")
+		if c.Matched {
+			icon = style.Success.Render("✓")
+			if d.Rule != i+1 {
+				icon = style.Dim.Render("✓") // Matched, but an earlier rule won
+			}
+		}
+		fmt.Printf("  %s rule %d: %s → %s\n", icon, i+1, c.Rule.Describe(), c.Rule.Assignee)
+		fmt.Printf("      %s\n", style.Dim.Render(c.Reason))
+	}
+	if rules != nil && rules.Default != "" {
+		fmt.Printf("  %s default → %s\n", style.Dim.Render("·"), rules.Default)
+	}
+	if len(d.Checks) > 0 || (rules != nil && rules.Default != "") {
+		fmt.Println()
+	}
+
+	switch {
+	case d.Assignee == "":
+		fmt.Printf("Rules: leave unassigned (%s)\n", d.Reason)
+	default:
+		fmt.Printf("Rules: %s (%s)\n", d.Assignee, d.Reason)
+	}
+	switch {
+	case out.FromRules:
+		fmt.Println("The current assignee matches the rules.")
+	case issue.Assignee != "" && d.Assignee != "":
+		fmt.Println("The current assignee differs from the rules: it was set by hand, or the rules changed after creation.")
+	case issue.Assignee != "":
+		fmt.Println("The current assignee was not set by the rules.")
+	}
+	return nil
+}
+
+func runBeadAssignInstall(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	hooksDir := filepath.Join(beads.ResolveBeadsDir(r.Path), "hooks")
+	hookPath := filepath.Join(hooksDir, "on_create")
+	if data, err := os.ReadFile(hookPath); err == nil {
+		if !strings.Contains(string(data), assignHookMarker) && !beadAssignForce {
+			return fmt.Errorf("%s already exists and was not written by gt (use --force to replace it)", hookPath)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading existing hook: %w", err)
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+# Installed by 'gt bead assign install %s'. Applies the rig's assignment
+# rules (settings/config.json "assignment") to each new bead.
+# bd passes the bead ID and event name.
+%s "$1" --hook >/dev/null 2>&1 || true
+`, rigName, assignHookMarker)
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("creating hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil { //nolint:gosec // G306: hook must be executable
+		return fmt.Errorf("writing hook: %w", err)
+	}
+
+	fmt.Printf("%s Installed on_create assignment hook for %s\n", style.SuccessPrefix, rigName)
+	fmt.Printf("  %s\n", style.Dim.Render(hookPath))
+	if config.LoadRigAssignment(r.Path) == nil {
+		style.PrintWarning("rig %s has no assignment rules yet; see 'gt bead assign --help'", rigName)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var beadAttachName string
+
+var beadAttachCmd = &cobra.Command{
+	Use:   "attach <bead-id> <file>...",
+	Short: "Attach files (logs, diffs, screenshots) to a bead",
+	Long: `Attach files to a bead.
+
+Files are stored content-addressed in the rig's .beads/attachments/, so the
+same log attached to several beads is kept once. Attaching a file again
+under the same name with the same content does nothing; changed content is
+kept as a new version. Files are limited to 50 MiB.
+
+'gt bead show <id>' lists a bead's attachments, and downloads one with
+--download <name or hash prefix>.
+
+Examples:
+  gt bead attach gt-abc build.log
+  gt bead attach gt-abc before.png after.png
+  git diff | tee /tmp/fix.diff && gt bead attach gt-abc /tmp/fix.diff
+  gt bead attach gt-abc out.txt --name test-output.txt
+  gt bead show gt-abc --download build.log
+  gt bead show gt-abc --download build.log -o -    # To stdout`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runBeadAttach,
+}
+
+func init() {
+	beadAttachCmd.Flags().StringVar(&beadAttachName, "name", "", "Attachment name (default: the file name; one file only)")
+
+	beadCmd.AddCommand(beadAttachCmd)
+}
+
+func runBeadAttach(cmd *cobra.Command, args []string) error {
+	id, files := args[0], args[1:]
+	if beadAttachName != "" && len(files) > 1 {
+		return fmt.Errorf("--name needs a single file")
+	}
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		a, err := bd.Attach(id, file, beadAttachName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Attached %s to %s (%s, %s)\n", style.SuccessPrefix, style.Bold.Render(a.Name), id,
+			formatBytes(a.Size), a.ShortHash())
+	}
+	return nil
+}
+
+// splitDownloadFlags removes gt's --download and --output (-o) flags from
+// gt bead show args, returning the attachment ref and output path.
+func splitDownloadFlags(args []string) (rest []string, ref, output string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--download" || arg == "--output" || arg == "-o":
+			if i+1 < len(args) {
+				if arg == "--download" {
+					ref = args[i+1]
+				} else {
+					output = args[i+1]
+				}
+				i++
+			}
+		case strings.HasPrefix(arg, "--download="):
+			ref = strings.TrimPrefix(arg, "--download=")
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, ref, output
+}
+
+// runBeadDownload writes one of a bead's attachments to output (default:
+// its name in the current directory; "-" for stdout).
+func runBeadDownload(args []string, ref, output string) error {
+	id := beadShowID(args)
+	if id == "" {
+		return fmt.Errorf("bead ID required\n\nUsage: gt bead show <bead-id> --download <name>")
+	}
+	beadsDir := beadHistoryDir(id)
+	a, err := beads.FindAttachment(beadsDir, id, ref)
+	if err != nil {
+		return err
+	}
+	src, err := beads.OpenAttachment(beadsDir, a)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if output == "-" {
+		_, err := io.Copy(os.Stdout, src)
+		return err
+	}
+	if output == "" {
+		output = a.Name
+	} else if info, err := os.Stat(output); err == nil && info.IsDir() {
+		output = filepath.Join(output, a.Name)
+	}
+	dst, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644) //nolint:gosec // G304: the user names the destination
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", output, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("writing %s: %w", output, err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", output, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s Wrote %s (%s)\n", style.SuccessPrefix, output, formatBytes(a.Size))
+	return nil
+}
+
+// beadAttachments returns id's attachments for gt bead show, or nil.
+func beadAttachments(id string) []beads.Attachment {
+	attachments, err := beads.ListAttachments(beadHistoryDir(id), id)
+	if err != nil {
+		style.PrintWarning("could not read attachments: %v", err)
+		return nil
+	}
+	return attachments
+}
+
+// printBeadAttachments prints the attachments section of gt bead show.
+func printBeadAttachments(attachments []beads.Attachment) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Attachments"))
+	for _, a := range attachments {
+		by := ""
+		if a.AddedBy != "" {
+			by = "  by " + a.AddedBy
+		}
+		fmt.Printf("  %-30s %9s  %s  %s\n", a.Name, formatBytes(a.Size), a.ShortHash(),
+			style.Dim.Render(formatHistoryTime(a.AddedAt)+by))
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Download with: gt bead show "+attachments[0].IssueID+" --download <name>"))
+}
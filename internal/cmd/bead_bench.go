@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Bead bench command flags
+var (
+	beadBenchTargets   []string
+	beadBenchCount     int
+	beadBenchSamples   int
+	beadBenchKeep      bool
+	beadBenchJSON      bool
+	beadBenchSave      string
+	beadBenchBaseline  string
+	beadBenchThreshold float64
+)
+
+var beadBenchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark beads backend operation latencies",
+	Long: `Generate synthetic beads and measure backend operation latencies.
+
+For each target, creates --count synthetic beads (labeled gt:bench) and times
+create, list, filtered list, search, and export. Targets are compared side by
+side so backend choices (SQLite vs Dolt embedded vs Dolt server) can be made
+from data rather than guesswork.
+
+Targets are given as name=path, where path is a directory containing (or
+redirecting to) a .beads workspace. With no --target, a scratch workspace is
+initialized in a temporary directory and removed afterwards.
+
+Use --save to record a run and --baseline to compare against a saved run.
+Any operation whose p50 latency exceeds the baseline by more than --threshold
+is reported as a regression and the command exits non-zero.
+
+Examples:
+  gt bead bench
+  gt bead bench --count 500 --target sqlite=/tmp/sq --target dolt=~/gt/gastown
+  gt bead bench --target hq=~/gt --save bench.json
+  gt bead bench --target hq=~/gt --baseline bench.json --threshold 1.25`,
+	RunE: runBeadBench,
+}
+
+func init() {
+	beadBenchCmd.Flags().StringArrayVar(&beadBenchTargets, "target", nil, "Target as name=path (repeatable; default: scratch workspace)")
+	beadBenchCmd.Flags().IntVar(&beadBenchCount, "count", 100, "Number of synthetic beads to create per target")
+	beadBenchCmd.Flags().IntVar(&beadBenchSamples, "samples", 5, "Repetitions of each read operation")
+	beadBenchCmd.Flags().BoolVar(&beadBenchKeep, "keep", false, "Keep synthetic beads instead of deleting them")
+	beadBenchCmd.Flags().BoolVar(&beadBenchJSON, "json", false, "Output as JSON")
+	beadBenchCmd.Flags().StringVar(&beadBenchSave, "save", "", "Write results to this JSON file")
+	beadBenchCmd.Flags().StringVar(&beadBenchBaseline, "baseline", "", "Compare against results saved with --save")
+	beadBenchCmd.Flags().Float64Var(&beadBenchThreshold, "threshold", 1.5, "Regression threshold as a p50 ratio")
+
+	beadCmd.AddCommand(beadBenchCmd)
+}
+
+func runBeadBench(cmd *cobra.Command, args []string) error {
+	targets, cleanup, err := resolveBenchTargets(beadBenchTargets)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	opts := beads.BenchOptions{
+		Count:   beadBenchCount,
+		Samples: beadBenchSamples,
+		Cleanup: !beadBenchKeep,
+	}
+
+	var results []*beads.BenchResult
+	for _, t := range targets {
+		if !beadBenchJSON {
+			fmt.Printf("%s Benchmarking %s (%d beads)...\n", style.ArrowPrefix, t.Name, opts.Count)
+		}
+		results = append(results, beads.RunBench(t, opts))
+	}
+
+	if beadBenchSave != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(beadBenchSave, data, 0644); err != nil { //nolint:gosec // G306: benchmark results are not sensitive
+			return fmt.Errorf("saving results: %w", err)
+		}
+	}
+
+	var regressions []beads.BenchRegression
+	if beadBenchBaseline != "" {
+		data, err := os.ReadFile(beadBenchBaseline) //nolint:gosec // G304: path is user-provided flag
+		if err != nil {
+			return fmt.Errorf("reading baseline: %w", err)
+		}
+		var baseline []*beads.BenchResult
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return fmt.Errorf("parsing baseline: %w", err)
+		}
+		regressions = beads.CompareBench(baseline, results, beadBenchThreshold)
+	}
+
+	if beadBenchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		fmt.Print(beads.FormatBenchComparison(results))
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("%s %s: %s\n", style.ErrorPrefix, r.Target, r.Error)
+			}
+		}
+		if beadBenchBaseline != "" {
+			fmt.Println()
+			if len(regressions) == 0 {
+				fmt.Printf("%s No regressions vs %s\n", style.SuccessPrefix, beadBenchBaseline)
+			}
+			for _, reg := range regressions {
+				fmt.Printf("%s %s %s: p50 %v → %v (%.2fx)\n", style.WarningPrefix,
+					reg.Target, reg.Op, reg.Baseline, reg.Current, reg.Ratio())
+			}
+		}
+	}
+
+	if len(regressions) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// resolveBenchTargets parses name=path target specs. With no specs, it
+// initializes a scratch workspace and returns a cleanup func that removes it.
+func resolveBenchTargets(specs []string) ([]beads.BenchTarget, func(), error) {
+	noop := func() {}
+	if len(specs) == 0 {
+		dir, err := os.MkdirTemp("", "gt-bench-*")
+		if err != nil {
+			return nil, noop, fmt.Errorf("creating scratch workspace: %w", err)
+		}
+		cleanup := func() { _ = os.RemoveAll(dir) }
+		if err := beads.NewIsolated(dir).Init("bench"); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("initializing scratch workspace: %w", err)
+		}
+		return []beads.BenchTarget{{Name: "scratch", WorkDir: dir, Isolated: true}}, cleanup, nil
+	}
+
+	var targets []beads.BenchTarget
+	for _, spec := range specs {
+		name, path, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || path == "" {
+			return nil, noop, fmt.Errorf("invalid --target %q: expected name=path", spec)
+		}
+		abs, err := filepath.Abs(util.ExpandHome(path))
+		if err != nil {
+			return nil, noop, fmt.Errorf("resolving %s: %w", path, err)
+		}
+		if _, err := os.Stat(beads.ResolveBeadsDir(abs)); err != nil {
+			return nil, noop, fmt.Errorf("target %s: no beads workspace at %s", name, abs)
+		}
+		targets = append(targets, beads.BenchTarget{Name: name, WorkDir: abs})
+	}
+	return targets, noop, nil
+}
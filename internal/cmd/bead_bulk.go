@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead bulk command flags
+var (
+	beadBulkType      string
+	beadBulkStatus    string
+	beadBulkLabel     string
+	beadBulkAssignee  string
+	beadBulkConvoy    string
+	beadBulkOlderThan string
+	beadBulkAll       bool
+	beadBulkDryRun    bool
+	beadBulkReason    string
+	beadBulkAdd       []string
+	beadBulkRemove    []string
+)
+
+var beadBulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Apply one change to many beads at once",
+	RunE:  requireSubcommand,
+	Long: `Close, reprioritize, retag, or reassign every bead matching a filter.
+
+Filters (combine freely):
+  --type         Issue type (matches issue_type or the gt:<type> label)
+  --status       Status passed to bd list (default: open)
+  --label        Required label
+  --assignee     Current assignee
+  --convoy       Tracked by this convoy (see gt convoy)
+  --older-than   Not updated within this window (e.g., 30d, 12h)
+
+At least one of --type, --label, --assignee, --convoy or --older-than is
+required, unless --all is given.
+
+Use --dry-run to list the matching beads and the change each would get.
+Beads the change would not affect are skipped.
+
+Bulk changes are not transactional. Each bead is updated separately; if an
+update fails, gt makes a best-effort rollback of the beads it already
+changed and reports any it could not restore for manual repair. Bulk
+changes are refused while the Dolt server is unreachable, since the beads
+could only be selected from a stale snapshot.
+
+Labels added by retag must be allowed by the town label registry
+(gt bead labels).
+
+Examples:
+  gt beads bulk close --older-than 30d --type task --reason "stale" --dry-run
+  gt beads bulk reprioritize 1 --label incident
+  gt beads bulk retag --label sprint-4 --add sprint-5 --remove sprint-4
+  gt beads bulk reassign gastown/crew/max --assignee gastown/polecats/nux
+  gt beads bulk close --convoy hq-cv-abc --reason "convoy abandoned"`,
+}
+
+var beadBulkCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "Close matching beads",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBeadBulk(beads.BulkAction{Op: beads.BulkClose, Reason: beadBulkReason})
+	},
+}
+
+var beadBulkReprioritizeCmd = &cobra.Command{
+	Use:   "reprioritize <priority>",
+	Short: "Set the priority (0-4) of matching beads",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid priority %q: must be 0-4", args[0])
+		}
+		return runBeadBulk(beads.BulkAction{Op: beads.BulkReprioritize, Priority: p})
+	},
+}
+
+var beadBulkRetagCmd = &cobra.Command{
+	Use:   "retag",
+	Short: "Add and/or remove labels on matching beads",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBeadBulk(beads.BulkAction{Op: beads.BulkRetag, AddLabels: beadBulkAdd, RemoveLabels: beadBulkRemove})
+	},
+}
+
+var beadBulkReassignCmd = &cobra.Command{
+	Use:   "reassign <assignee>",
+	Short: "Assign matching beads to someone else (\"\" to unassign)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBeadBulk(beads.BulkAction{Op: beads.BulkReassign, Assignee: args[0]})
+	},
+}
+
+func init() {
+	flags := beadBulkCmd.PersistentFlags()
+	flags.StringVar(&beadBulkType, "type", "", "Filter by issue type")
+	flags.StringVar(&beadBulkStatus, "status", "open", "Filter by status (open, in_progress, closed, all, ...)")
+	flags.StringVar(&beadBulkLabel, "label", "", "Filter by label")
+	flags.StringVar(&beadBulkAssignee, "assignee", "", "Filter by current assignee")
+	flags.StringVar(&beadBulkConvoy, "convoy", "", "Filter by tracking convoy")
+	flags.StringVar(&beadBulkOlderThan, "older-than", "", "Only beads not updated within this window (e.g., 30d, 12h)")
+	flags.BoolVar(&beadBulkAll, "all", false, "Allow operating on every bead with the given status")
+	flags.BoolVar(&beadBulkDryRun, "dry-run", false, "Show what would change without changing anything")
+
+	beadBulkCloseCmd.Flags().StringVar(&beadBulkReason, "reason", "bulk close", "Close reason")
+	beadBulkRetagCmd.Flags().StringSliceVar(&beadBulkAdd, "add", nil, "Labels to add (repeatable)")
+	beadBulkRetagCmd.Flags().StringSliceVar(&beadBulkRemove, "remove", nil, "Labels to remove (repeatable)")
+
+	beadBulkCmd.AddCommand(beadBulkCloseCmd)
+	beadBulkCmd.AddCommand(beadBulkReprioritizeCmd)
+	beadBulkCmd.AddCommand(beadBulkRetagCmd)
+	beadBulkCmd.AddCommand(beadBulkReassignCmd)
+	beadCmd.AddCommand(beadBulkCmd)
+}
+
+func runBeadBulk(action beads.BulkAction) error {
+	if err := action.Validate(); err != nil {
+		return err
+	}
+	if err := checkTownLabels(action.AddLabels); err != nil {
+		return err
+	}
+
+	filter := beads.BulkFilter{
+		Type:     beadBulkType,
+		Status:   beadBulkStatus,
+		Label:    beadBulkLabel,
+		Assignee: beadBulkAssignee,
+		Convoy:   beadBulkConvoy,
+	}
+	if beadBulkOlderThan != "" {
+		d, err := parseDuration(beadBulkOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+		filter.OlderThan = d
+	}
+	if !beadBulkAll && filter.Type == "" && filter.Label == "" && filter.Assignee == "" && filter.Convoy == "" && filter.OlderThan == 0 {
+		return fmt.Errorf("no filter given: use --type, --label, --assignee, --convoy or --older-than (or --all)")
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(workDir)
+
+	issues, err := bd.BulkSelect(filter, time.Now())
+	if err != nil {
+		return fmt.Errorf("listing beads: %w", err)
+	}
+
+	var targets []*beads.Issue
+	for _, issue := range issues {
+		changes, desc := action.Changes(issue)
+		if !changes {
+			continue
+		}
+		targets = append(targets, issue)
+		if beadBulkDryRun {
+			fmt.Printf("  %s  %s  %s\n", style.Bold.Render(issue.ID), desc, style.Dim.Render(truncateString(issue.Title, 60)))
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("No beads to %s (%d matched the filter, none would change).\n", action.Op, len(issues))
+		return nil
+	}
+	if beadBulkDryRun {
+		fmt.Printf("\nDry run: would %s %d bead(s). Nothing changed.\n", action.Op, len(targets))
+		return nil
+	}
+
+	result, err := beads.ApplyBulk(bd, targets, action)
+	if err != nil {
+		if result != nil && len(result.RolledBack) > 0 {
+			fmt.Printf("%s Restored %d bead(s) after failure\n", style.WarningPrefix, len(result.RolledBack))
+		}
+		return fmt.Errorf("bulk %s failed: %w", action.Op, err)
+	}
+
+	fmt.Printf("%s Applied %s to %d bead(s)\n", style.SuccessPrefix, action.Op, len(result.Applied))
+	return nil
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadClaimFor   string
+	beadClaimAs    string
+	beadClaimForce bool
+	beadLeasesRig  string
+	beadLeasesReap bool
+	beadLeasesJSON bool
+)
+
+var beadClaimCmd = &cobra.Command{
+	Use:   "claim <bead-id>",
+	Short: "Claim a bead for a limited time (lease)",
+	Long: `Claim a bead: assign it to you and mark it in_progress, under a lease
+that expires after --for (default 2h).
+
+A bead leased to someone else, or assigned and in progress without a lease,
+cannot be claimed, so two agents never silently pick up the same issue.
+Claiming a bead you already hold renews the lease. When a lease expires the
+daemon returns the bead to the pool (open, unassigned); see 'gt bead leases'.
+
+Examples:
+  gt bead claim gt-abc              # Claim for 2h as the current agent
+  gt bead claim gt-abc --for 30m    # Shorter lease (or renew for 30m)
+  gt bead claim gt-abc --as gastown/polecats/toast
+  gt bead claim gt-abc --force      # Take over someone else's claim`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadClaim,
+}
+
+var beadUnclaimCmd = &cobra.Command{
+	Use:   "unclaim <bead-id>",
+	Short: "Release a claimed bead back to the pool",
+	Long: `Release your lease on a bead: it goes back to open and unassigned.
+
+Only the holder can release a lease, unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadUnclaim,
+}
+
+var beadLeasesCmd = &cobra.Command{
+	Use:   "leases",
+	Short: "List bead leases, and reap expired ones",
+	Long: `List the beads claimed with 'gt bead claim' and when their leases expire.
+
+With --reap, beads whose lease has expired are returned to the pool (open,
+unassigned) with a comment naming the lapsed holder. The daemon does this
+for every rig periodically.
+
+Examples:
+  gt bead leases                  # Leases in the current rig
+  gt bead leases --rig gastown    # Leases in another rig
+  gt bead leases --reap           # Return expired leases to the pool`,
+	RunE: runBeadLeases,
+}
+
+func init() {
+	beadClaimCmd.Flags().StringVar(&beadClaimFor, "for", "", "Lease duration (e.g. 30m, 4h, 1d; default 2h)")
+	beadClaimCmd.Flags().StringVar(&beadClaimAs, "as", "", "Holder identity (default: the current agent)")
+	beadClaimCmd.Flags().BoolVar(&beadClaimForce, "force", false, "Claim even if someone else holds the bead")
+	beadUnclaimCmd.Flags().StringVar(&beadClaimAs, "as", "", "Holder identity (default: the current agent)")
+	beadUnclaimCmd.Flags().BoolVar(&beadClaimForce, "force", false, "Release someone else's lease")
+	beadLeasesCmd.Flags().StringVar(&beadLeasesRig, "rig", "", "Rig to list (default: the current directory's)")
+	beadLeasesCmd.Flags().BoolVar(&beadLeasesReap, "reap", false, "Return beads with expired leases to the pool")
+	beadLeasesCmd.Flags().BoolVar(&beadLeasesJSON, "json", false, "Output as JSON")
+
+	beadCmd.AddCommand(beadClaimCmd)
+	beadCmd.AddCommand(beadUnclaimCmd)
+	beadCmd.AddCommand(beadLeasesCmd)
+}
+
+func runBeadClaim(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	var ttl time.Duration
+	if beadClaimFor != "" {
+		d, err := parseDuration(beadClaimFor)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --for %q: want a positive duration like 30m or 4h", beadClaimFor)
+		}
+		ttl = d
+	}
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+
+	lease, err := bd.Claim(id, claimHolder(), ttl, beadClaimForce)
+	var held *beads.LeaseHeldError
+	if errors.As(err, &held) {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.WarningPrefix, held.Error())
+		return NewSilentExit(1)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Claimed %s as %s until %s\n", style.SuccessPrefix, style.Bold.Render(id), lease.Holder,
+		lease.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func runBeadUnclaim(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+	if err := bd.Unclaim(id, claimHolder(), beadClaimForce); err != nil {
+		return err
+	}
+	fmt.Printf("%s Released %s back to the pool\n", style.SuccessPrefix, style.Bold.Render(id))
+	return nil
+}
+
+// beadLeaseRow is one lease, for output.
+type beadLeaseRow struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Expired    bool      `json:"expired"`
+}
+
+func runBeadLeases(cmd *cobra.Command, args []string) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	if beadLeasesRig != "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if _, _, err := getRig(beadLeasesRig); err != nil {
+			return err
+		}
+		workDir = filepath.Join(townRoot, beadLeasesRig)
+	}
+	bd := beads.New(workDir)
+	now := time.Now()
+
+	var reaped []string
+	if beadLeasesReap {
+		var reapErr error
+		reaped, reapErr = bd.ReapExpiredLeases(now)
+		if reapErr != nil {
+			style.PrintWarning("%v", reapErr)
+		}
+	}
+
+	leased, err := bd.ListLeases()
+	if err != nil {
+		return fmt.Errorf("listing leases: %w", err)
+	}
+	rows := make([]beadLeaseRow, 0, len(leased))
+	for _, issue := range leased {
+		l := beads.ParseLease(issue)
+		rows = append(rows, beadLeaseRow{
+			ID: issue.ID, Title: issue.Title, Holder: l.Holder,
+			AcquiredAt: l.AcquiredAt, ExpiresAt: l.ExpiresAt, Expired: l.Expired(now),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ExpiresAt.Before(rows[j].ExpiresAt) })
+
+	if beadLeasesJSON {
+		if reaped == nil {
+			reaped = []string{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Leases []beadLeaseRow `json:"leases"`
+			Reaped []string       `json:"reaped"`
+		}{rows, reaped})
+	}
+
+	for _, id := range reaped {
+		fmt.Printf("%s Lease on %s expired; returned to the pool\n", style.SuccessPrefix, style.Bold.Render(id))
+	}
+	if len(rows) == 0 {
+		fmt.Println(style.Dim.Render("No active leases"))
+		return nil
+	}
+	for _, r := range rows {
+		left := r.ExpiresAt.Sub(now).Round(time.Minute)
+		when := fmt.Sprintf("expires in %s", left)
+		if r.Expired {
+			when = style.Warning.Render(fmt.Sprintf("expired %s ago", -left))
+		}
+		fmt.Printf("  %s  %-30s %s  %s\n", style.Bold.Render(r.ID), r.Holder, when, style.Dim.Render(truncateString(r.Title, 50)))
+	}
+	return nil
+}
+
+// beadsForID returns a beads client for the rig that owns id, found by its
+// prefix, falling back to the current directory.
+func beadsForID(id string) (*beads.Beads, error) {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id)); rigPath != "" {
+			return beads.New(rigPath), nil
+		}
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	return beads.New(workDir), nil
+}
+
+// claimHolder returns --as, else the current agent's address.
+func claimHolder() string {
+	if beadClaimAs != "" {
+		return beadClaimAs
+	}
+	return detectSender()
+}
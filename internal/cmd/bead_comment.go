@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead comment command flags
+var (
+	beadCommentMessage string
+	beadCommentFile    string
+	beadCommentAs      string
+	beadCommentsJSON   bool
+)
+
+var beadCommentCmd = &cobra.Command{
+	Use:   "comment <bead-id> -m <text>",
+	Short: "Add a comment to a bead's discussion",
+	Args:  cobra.ExactArgs(1),
+	Long: `Add a comment to a bead, so back-and-forth between humans and agents
+stays with the issue instead of scrolling away in a terminal.
+
+Comments are Markdown. Give the text with -m, or read it from a file with
+-F (-F - reads stdin). The author is the current agent, or "overseer" for
+a human at a terminal; --as overrides it.
+
+The discussion appears in 'gt bead show', 'gt bead comments', and the
+jsonl, github and markdown exports.
+
+Examples:
+  gt bead comment gt-abc -m "Repro is in the attached log; looks like a race."
+  gt bead comment gt-abc -F review-notes.md
+  git diff --stat | gt bead comment gt-abc -F -`,
+	RunE: runBeadComment,
+}
+
+var beadCommentsCmd = &cobra.Command{
+	Use:   "comments <bead-id>",
+	Short: "Show a bead's discussion",
+	Args:  cobra.ExactArgs(1),
+	Long: `Show every comment on a bead, oldest first, with its author and time.
+
+Examples:
+  gt bead comments gt-abc
+  gt bead comments gt-abc --json`,
+	RunE: runBeadComments,
+}
+
+func init() {
+	beadCommentCmd.Flags().StringVarP(&beadCommentMessage, "message", "m", "", "Comment text (Markdown)")
+	beadCommentCmd.Flags().StringVarP(&beadCommentFile, "file", "F", "", "Read the comment from a file (- for stdin)")
+	beadCommentCmd.Flags().StringVar(&beadCommentAs, "as", "", "Author (default: the current agent)")
+	beadCommentCmd.MarkFlagsMutuallyExclusive("message", "file")
+	beadCommentsCmd.Flags().BoolVar(&beadCommentsJSON, "json", false, "Output comments as JSON")
+	beadCmd.AddCommand(beadCommentCmd)
+	beadCmd.AddCommand(beadCommentsCmd)
+}
+
+func runBeadComment(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	body := beadCommentMessage
+	switch {
+	case beadCommentFile == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading comment from stdin: %w", err)
+		}
+		body = string(data)
+	case beadCommentFile != "":
+		data, err := os.ReadFile(beadCommentFile) //nolint:gosec // G304: user-chosen file
+		if err != nil {
+			return fmt.Errorf("reading comment: %w", err)
+		}
+		body = string(data)
+	case body == "":
+		return fmt.Errorf("comment text required: use -m <text> or -F <file>")
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment is empty")
+	}
+
+	author := beadCommentAs
+	if author == "" {
+		author = detectSender()
+	}
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+	if err := bd.Comment(id, author, body); err != nil {
+		return fmt.Errorf("commenting on %s: %w", id, err)
+	}
+	fmt.Printf("%s Commented on %s as %s\n", style.SuccessPrefix, style.Bold.Render(id), author)
+	return nil
+}
+
+func runBeadComments(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	comments, err := beads.ReadComments(beadHistoryDir(id), id)
+	if err != nil {
+		return err
+	}
+	if beadCommentsJSON {
+		if comments == nil {
+			comments = []beads.Comment{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(comments)
+	}
+	if len(comments) == 0 {
+		fmt.Println(style.Dim.Render("No comments on " + id))
+		return nil
+	}
+	printBeadComments(comments)
+	return nil
+}
+
+// beadComments returns the discussion of id for gt bead show; failures
+// only warn.
+func beadComments(id string) []beads.Comment {
+	comments, err := beads.ReadComments(beadHistoryDir(id), id)
+	if err != nil {
+		style.PrintWarning("could not read comments: %v", err)
+		return nil
+	}
+	return comments
+}
+
+// printBeadComments prints the discussion section of gt bead show.
+func printBeadComments(comments []beads.Comment) {
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Discussion (%d)", len(comments))))
+	for _, c := range comments {
+		author := c.Author
+		if author == "" {
+			author = "unknown"
+		}
+		fmt.Printf("\n  %s  %s\n", style.Bold.Render(author), style.Dim.Render(formatHistoryTime(c.Timestamp)))
+		for _, line := range strings.Split(strings.TrimSpace(c.Body), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadCostBy   string
+	beadCostJSON bool
+)
+
+// beadCostGroupings are the --by values of gt bead cost.
+var beadCostGroupings = []string{"bead", "epic", "worker", "rig"}
+
+var beadCostCmd = &cobra.Command{
+	Use:   "cost [bead-id...]",
+	Short: "Show what agent work on beads has cost",
+	Long: `Show the estimated agent cost of work on beads: tokens, API dollars and
+compute minutes (wall-clock time of the agent sessions).
+
+Agent sessions report their usage from the Stop hook ('gt costs record')
+against the bead they are working on: the --work-item flag, else the
+session's GT_ISSUE, else the agent's hooked bead. Reports are kept in each
+rig's .beads/costs.jsonl; a session reporting again only adds what it used
+since its last report.
+
+With bead IDs, only those beads are counted. 'gt bead show' includes a
+bead's cost when it has any.
+
+Examples:
+  gt bead cost                  # Every bead with recorded cost, most expensive first
+  gt bead cost --by epic        # Totals per epic (beads without one are grouped)
+  gt bead cost --by worker      # Totals per polecat/crew member
+  gt bead cost gt-abc gt-def    # Just these beads
+  gt bead cost --by rig --json`,
+	RunE: runBeadCost,
+}
+
+func init() {
+	beadCostCmd.Flags().StringVar(&beadCostBy, "by", "bead", "Group by: "+strings.Join(beadCostGroupings, ", "))
+	beadCostCmd.Flags().BoolVar(&beadCostJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadCostCmd)
+}
+
+// beadCostRow is one group's total, for output.
+type beadCostRow struct {
+	Key string `json:"key"`
+	beads.CostUsage
+}
+
+func runBeadCost(cmd *cobra.Command, args []string) error {
+	by := strings.ToLower(beadCostBy)
+	valid := false
+	for _, g := range beadCostGroupings {
+		valid = valid || g == by
+	}
+	if !valid {
+		return fmt.Errorf("invalid --by %q: want one of %s", beadCostBy, strings.Join(beadCostGroupings, ", "))
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	reports, err := townCostReports(townRoot)
+	if err != nil {
+		return err
+	}
+	deltas := beads.CostDeltas(reports)
+	if len(args) > 0 {
+		want := make(map[string]bool, len(args))
+		for _, id := range args {
+			want[id] = true
+		}
+		var kept []beads.CostReport
+		for _, r := range deltas {
+			if want[r.IssueID] {
+				kept = append(kept, r)
+			}
+		}
+		deltas = kept
+	}
+
+	var key func(beads.CostReport) string
+	switch by {
+	case "bead":
+		key = func(r beads.CostReport) string { return r.IssueID }
+	case "epic":
+		bd := beads.New(townRoot)
+		epics := make(map[string]string)
+		key = func(r beads.CostReport) string {
+			if _, ok := epics[r.IssueID]; !ok {
+				epics[r.IssueID] = epicOfBead(bd, r.IssueID)
+			}
+			if epics[r.IssueID] == "" {
+				return "(no epic)"
+			}
+			return epics[r.IssueID]
+		}
+	case "worker":
+		key = func(r beads.CostReport) string { return orUnknown(r.Worker) }
+	case "rig":
+		key = func(r beads.CostReport) string { return orUnknown(r.Rig) }
+	}
+
+	var rows []beadCostRow
+	var total beads.CostUsage
+	for k, u := range beads.SumCosts(deltas, key) {
+		rows = append(rows, beadCostRow{Key: k, CostUsage: *u})
+		total.Add(*u)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CostUSD != rows[j].CostUSD {
+			return rows[i].CostUSD > rows[j].CostUSD
+		}
+		return rows[i].Key < rows[j].Key
+	})
+
+	if beadCostJSON {
+		if rows == nil {
+			rows = []beadCostRow{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			By    string          `json:"by"`
+			Rows  []beadCostRow   `json:"rows"`
+			Total beads.CostUsage `json:"total"`
+		}{by, rows, total})
+	}
+
+	fmt.Printf("\n%s Bead costs by %s\n\n", style.Bold.Render("💰"), by)
+	if len(rows) == 0 {
+		fmt.Println(style.Dim.Render("No recorded cost"))
+		return nil
+	}
+	fmt.Printf("%-30s %10s %10s %9s\n", strings.ToUpper(by[:1])+by[1:], "Cost", "Tokens", "Minutes")
+	fmt.Println(strings.Repeat("─", 62))
+	for _, r := range rows {
+		fmt.Printf("%-30s %10s %10s %9.0f\n", r.Key, fmt.Sprintf("$%.2f", r.CostUSD), formatTokenCount(r.Tokens()), r.ComputeMinutes)
+	}
+	fmt.Println(strings.Repeat("─", 62))
+	fmt.Printf("%-30s %10s %10s %9.0f\n", style.Bold.Render("Total"), fmt.Sprintf("$%.2f", total.CostUSD), formatTokenCount(total.Tokens()), total.ComputeMinutes)
+	return nil
+}
+
+// townCostReports reads the cost ledgers of the town and every rig.
+func townCostReports(townRoot string) ([]beads.CostReport, error) {
+	dirs := []string{beads.ResolveBeadsDir(townRoot)}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		var names []string
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dirs = append(dirs, beads.ResolveBeadsDir(filepath.Join(townRoot, name)))
+		}
+	}
+
+	var reports []beads.CostReport
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if seen[dir] {
+			continue // rigs sharing a redirected beads directory
+		}
+		seen[dir] = true
+		r, err := beads.ReadCostReports(dir)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r...)
+	}
+	return reports, nil
+}
+
+// beadCost returns the recorded cost of id, or nil when it has none.
+func beadCost(id string) *beads.CostUsage {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	reports, err := townCostReports(townRoot)
+	if err != nil {
+		return nil
+	}
+	totals := beads.SumCosts(beads.CostDeltas(reports), func(r beads.CostReport) string {
+		if r.IssueID == id {
+			return id
+		}
+		return ""
+	})
+	return totals[id]
+}
+
+// printBeadCost prints the cost section of gt bead show.
+func printBeadCost(u *beads.CostUsage) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Cost"))
+	fmt.Printf("  $%.2f  ·  %s tokens (%s in, %s out)  ·  %.0f compute minutes\n",
+		u.CostUSD, formatTokenCount(u.Tokens()),
+		formatTokenCount(u.InputTokens+u.CacheReadTokens+u.CacheCreationTokens),
+		formatTokenCount(u.OutputTokens), u.ComputeMinutes)
+}
+
+// epicOfBead returns the epic id belongs to, following parents, or "" if
+// it is not under an epic.
+func epicOfBead(bd *beads.Beads, id string) string {
+	for depth := 0; id != "" && depth < 10; depth++ {
+		issue, err := bd.Show(id)
+		if err != nil {
+			return ""
+		}
+		if issue.Type == "epic" {
+			return issue.ID
+		}
+		id = issue.Parent
+	}
+	return ""
+}
+
+// detectCostWorkItem returns the bead a session's cost is attributed to when
+// gt costs record is not told: the session's GT_ISSUE, else the agent's
+// hooked bead.
+func detectCostWorkItem(session, workDir string) string {
+	if issue, err := tmux.NewTmux().GetEnvironment(session, "GT_ISSUE"); err == nil && issue != "" {
+		return issue
+	}
+	if issue := os.Getenv("GT_ISSUE"); issue != "" {
+		return issue
+	}
+	if workDir == "" {
+		return ""
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return ""
+	}
+	roleInfo, err := GetRoleWithContext(workDir, townRoot)
+	if err != nil {
+		return ""
+	}
+	return detectHookedBead(workDir, roleInfo)
+}
+
+// recordBeadCost appends a session's usage so far to the cost ledger of the
+// rig issueID belongs to.
+func recordBeadCost(issueID, session, rig, worker, role string, usage *TokenUsage, cost float64) error {
+	dir := beadHistoryDir(issueID)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("no beads directory for %s", issueID)
+	}
+	if worker == "" {
+		worker = role
+	}
+	sessionID := usage.SessionID
+	if sessionID == "" {
+		sessionID = session
+	}
+	return beads.RecordCost(dir, beads.CostReport{
+		IssueID: issueID,
+		Session: sessionID,
+		Rig:     rig,
+		Worker:  worker,
+		Model:   usage.Model,
+		CostUsage: beads.CostUsage{
+			InputTokens:         usage.InputTokens,
+			OutputTokens:        usage.OutputTokens,
+			CacheReadTokens:     usage.CacheReadInputTokens,
+			CacheCreationTokens: usage.CacheCreationInputTokens,
+			CostUSD:             cost,
+			ComputeMinutes:      usage.ComputeMinutes(),
+		},
+	})
+}
+
+// formatTokenCount renders a token count compactly (950, 12.3k, 4.1M).
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "(unknown)"
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead dedupe command flags
+var (
+	beadDedupeThreshold float64
+	beadDedupeAll       bool
+	beadDedupeLimit     int
+	beadDedupeJSON      bool
+	beadDedupeDryRun    bool
+)
+
+var beadDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find likely-duplicate beads, and merge them",
+	Args:  cobra.NoArgs,
+	Long: `Find beads that likely describe the same work.
+
+Beads are scored from 0 to 1 by the similarity of their titles (shared
+character trigrams), blended with that of their descriptions when both
+have a few words. Pairs scoring at least --threshold (default 0.6) are
+listed best first, with the suggested survivor: an open bead over a closed
+one, then the older. Infrastructure beads (agents, merge requests, mail,
+...) and sealed beads are not compared; closed beads only with --all.
+
+Merge a pair with 'gt beads dedupe merge <duplicate> <keep>'.
+
+Examples:
+  gt beads dedupe
+  gt beads dedupe --threshold 0.8 --all
+  gt beads dedupe --json`,
+	RunE: runBeadDedupe,
+}
+
+var beadDedupeMergeCmd = &cobra.Command{
+	Use:   "merge <duplicate-id> <keep-id>",
+	Short: "Merge a duplicate bead into the one kept",
+	Args:  cobra.ExactArgs(2),
+	Long: `Fold a duplicate bead into the one kept, then close it.
+
+  - The duplicate's labels are added to the kept bead
+  - Merge requests for the duplicate get the kept bead as source_issue
+  - Beads depending on the duplicate depend on the kept bead instead
+  - The duplicate's comments and MR links are copied into the kept bead's
+    activity log (see 'gt bead show --history')
+  - The duplicate is linked to the kept bead as a duplicate and closed
+
+Examples:
+  gt beads dedupe merge gt-def gt-abc
+  gt beads dedupe merge gt-def gt-abc --dry-run`,
+	RunE: runBeadDedupeMerge,
+}
+
+func init() {
+	beadDedupeCmd.Flags().Float64Var(&beadDedupeThreshold, "threshold", beads.DefaultDuplicateScore, "Minimum similarity (0-1) to report")
+	beadDedupeCmd.Flags().BoolVar(&beadDedupeAll, "all", false, "Compare closed beads too")
+	beadDedupeCmd.Flags().IntVar(&beadDedupeLimit, "limit", 50, "Maximum pairs to show (0 for all)")
+	beadDedupeCmd.Flags().BoolVar(&beadDedupeJSON, "json", false, "Output pairs as JSON")
+	beadDedupeMergeCmd.Flags().BoolVarP(&beadDedupeDryRun, "dry-run", "n", false, "Show what would change without merging")
+	beadDedupeCmd.AddCommand(beadDedupeMergeCmd)
+	beadCmd.AddCommand(beadDedupeCmd)
+}
+
+func runBeadDedupe(cmd *cobra.Command, args []string) error {
+	if beadDedupeThreshold <= 0 || beadDedupeThreshold > 1 {
+		return fmt.Errorf("--threshold must be between 0 and 1")
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	pairs, err := beads.New(workDir).FindDuplicates(beadDedupeThreshold, beadDedupeAll)
+	if err != nil {
+		return err
+	}
+	total := len(pairs)
+	if beadDedupeLimit > 0 && len(pairs) > beadDedupeLimit {
+		pairs = pairs[:beadDedupeLimit]
+	}
+
+	if beadDedupeJSON {
+		if pairs == nil {
+			pairs = []beads.DuplicatePair{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pairs)
+	}
+
+	if total == 0 {
+		fmt.Printf("%s No likely duplicates (threshold %.2f)\n", style.SuccessPrefix, beadDedupeThreshold)
+		return nil
+	}
+	fmt.Printf("%d likely duplicate pair(s):\n\n", total)
+	for _, p := range pairs {
+		fmt.Printf("  %s  keep %s  %s\n", style.Bold.Render(fmt.Sprintf("%.2f", p.Score)),
+			style.Bold.Render(p.Keep), truncateString(p.KeepTitle, 60))
+		fmt.Printf("        dup  %s  %s\n", style.Bold.Render(p.Duplicate), truncateString(p.DupTitle, 60))
+		fmt.Printf("        %s\n\n", style.Dim.Render("gt beads dedupe merge "+p.Duplicate+" "+p.Keep))
+	}
+	if len(pairs) < total {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("... and %d more (use --limit 0 to see all)", total-len(pairs))))
+	}
+	return nil
+}
+
+func runBeadDedupeMerge(cmd *cobra.Command, args []string) error {
+	dupID, keepID := args[0], args[1]
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(workDir)
+
+	if beadDedupeDryRun {
+		plan, err := bd.PlanMerge(dupID, keepID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Would merge %s into %s:\n", style.Bold.Render(dupID), style.Bold.Render(keepID))
+		printMergePlan(plan)
+		fmt.Printf("  close %s as a duplicate of %s\n", dupID, keepID)
+		return nil
+	}
+
+	plan, err := bd.MergeDuplicate(dupID, keepID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Merged %s into %s\n", style.SuccessPrefix, style.Bold.Render(dupID), style.Bold.Render(keepID))
+	printMergePlan(plan)
+	return nil
+}
+
+func printMergePlan(plan *beads.MergePlan) {
+	if len(plan.Labels) > 0 {
+		fmt.Printf("  labels:     %s\n", strings.Join(plan.Labels, ", "))
+	}
+	if len(plan.MRs) > 0 {
+		fmt.Printf("  MRs:        %s\n", strings.Join(plan.MRs, ", "))
+	}
+	if len(plan.Dependents) > 0 {
+		fmt.Printf("  dependents: %s\n", strings.Join(plan.Dependents, ", "))
+	}
+}
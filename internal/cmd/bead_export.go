@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead export command flags
+var (
+	beadExportFormat   string
+	beadExportOutput   string
+	beadExportType     string
+	beadExportStatus   string
+	beadExportLabel    string
+	beadExportAssignee string
+	beadExportConvoy   string
+)
+
+var beadExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export beads as jsonl, csv, github, or markdown",
+	Args:  cobra.NoArgs,
+	RunE:  runBeadExport,
+	Long: `Export beads from the current directory's database in a portable format.
+
+Formats:
+  jsonl      One bd issue JSON object per line (lossless)
+  csv        Spreadsheet columns: id, title, status, priority, type, assignee,
+             labels, parent, depends_on, created_at, updated_at, closed_at,
+             description. Imports into Jira and Google Sheets.
+  github     JSON array shaped like 'gh issue list --json'. Type, priority,
+             and non-open/closed status become labels (type:bug, priority:P1,
+             status:in_progress); the bead ID, parent, and dependencies ride
+             in a hidden trailer in the body.
+  markdown   One "## <id>: <title>" section per bead, for docs and review
+
+Each bead's discussion (see 'gt bead comment') is included in the jsonl,
+github and markdown formats.
+
+Every format can be read back with 'gt beads import'; discussions are not
+imported.
+
+Examples:
+  gt beads export --format csv -o issues.csv
+  gt beads export --format github --status open --label sprint-4
+  gt beads export --format markdown --type epic > epics.md
+  gt beads export --format markdown --convoy hq-cv-abc`,
+}
+
+func init() {
+	beadExportCmd.Flags().StringVar(&beadExportFormat, "format", beads.FormatJSONL, "Output format: jsonl, csv, github, markdown")
+	beadExportCmd.Flags().StringVarP(&beadExportOutput, "output", "o", "", "Write to file instead of stdout")
+	beadExportCmd.Flags().StringVar(&beadExportType, "type", "", "Filter by issue type")
+	beadExportCmd.Flags().StringVar(&beadExportStatus, "status", "all", "Filter by status (open, in_progress, closed, all, ...)")
+	beadExportCmd.Flags().StringVar(&beadExportLabel, "label", "", "Filter by label")
+	beadExportCmd.Flags().StringVar(&beadExportAssignee, "assignee", "", "Filter by assignee")
+	beadExportCmd.Flags().StringVar(&beadExportConvoy, "convoy", "", "Filter by tracking convoy")
+	beadCmd.AddCommand(beadExportCmd)
+}
+
+func runBeadExport(cmd *cobra.Command, args []string) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(workDir)
+
+	issues, err := bd.BulkSelect(beads.BulkFilter{
+		Type:     beadExportType,
+		Status:   beadExportStatus,
+		Label:    beadExportLabel,
+		Assignee: beadExportAssignee,
+		Convoy:   beadExportConvoy,
+	}, time.Now())
+	if err != nil {
+		return fmt.Errorf("listing beads: %w", err)
+	}
+
+	// bd list omits relations; bd show has parent and dependencies
+	if len(issues) > 0 {
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		details, err := bd.ShowMultiple(ids)
+		if err != nil {
+			return fmt.Errorf("loading bead details: %w", err)
+		}
+		for i, issue := range issues {
+			if full, ok := details[issue.ID]; ok {
+				issues[i] = full
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if beadExportOutput != "" {
+		f, err := os.Create(beadExportOutput)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	comments, err := bd.AllComments()
+	if err != nil {
+		style.PrintWarning("could not read comments: %v", err)
+	}
+	if err := beads.ExportIssuesWithComments(w, beadExportFormat, issues, comments); err != nil {
+		return err
+	}
+	if beadExportOutput != "" {
+		fmt.Printf("%s Exported %d bead(s) to %s\n", style.SuccessPrefix, len(issues), beadExportOutput)
+	}
+	return nil
+}
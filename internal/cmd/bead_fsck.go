@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead fsck command flags
+var (
+	beadFsckFix    bool
+	beadFsckDryRun bool
+	beadFsckJSON   bool
+)
+
+var beadFsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Find and repair dangling relations and links",
+	Args:  cobra.NoArgs,
+	Long: `Check the beads database for references that no longer resolve.
+
+Reports:
+  dangling-dependency     Dependency on a bead that does not exist (fixable)
+  dangling-mr-link        Agent active_mr naming a missing merge request (fixable),
+                          or an MR whose source_issue is missing
+  orphaned-attachment     attached_molecule naming a missing molecule (fixable)
+  duplicate-external-ref  Several beads sharing one external_ref
+
+References to other databases (e.g., hq-* from a rig) are not checked.
+With --fix, fixable problems are repaired by removing the dangling
+reference; the rest are left for manual review.
+
+The same pass runs across all databases with: gt doctor --fsck
+
+Examples:
+  gt beads fsck
+  gt beads fsck --fix --dry-run
+  gt beads fsck --json`,
+	RunE: runBeadFsck,
+}
+
+func init() {
+	beadFsckCmd.Flags().BoolVar(&beadFsckFix, "fix", false, "Remove dangling references")
+	beadFsckCmd.Flags().BoolVar(&beadFsckDryRun, "dry-run", false, "With --fix, show repairs without applying them")
+	beadFsckCmd.Flags().BoolVar(&beadFsckJSON, "json", false, "Output report as JSON")
+	beadCmd.AddCommand(beadFsckCmd)
+}
+
+func runBeadFsck(cmd *cobra.Command, args []string) error {
+	if beadFsckDryRun && !beadFsckFix {
+		return fmt.Errorf("--dry-run requires --fix")
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(workDir)
+
+	report, err := bd.Fsck()
+	if err != nil {
+		return err
+	}
+
+	if beadFsckJSON && !beadFsckFix {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(report.Problems) == 0 {
+		fmt.Printf("%s Checked %d bead(s), no problems found\n", style.SuccessPrefix, report.Checked)
+		return nil
+	}
+
+	fmt.Printf("Checked %d bead(s), %d problem(s):\n\n", report.Checked, len(report.Problems))
+	for _, p := range report.Problems {
+		fmt.Printf("  %s  %s  %s\n", style.Bold.Render(p.IssueID), p.Kind, style.Dim.Render(p.Detail))
+	}
+	fmt.Println()
+
+	if !beadFsckFix {
+		fmt.Printf("Run %s to repair fixable problems.\n", style.Bold.Render("gt beads fsck --fix"))
+		return NewSilentExit(1)
+	}
+
+	var fixed, failed, manual int
+	for _, p := range report.Problems {
+		if !p.Fixable {
+			manual++
+			continue
+		}
+		if beadFsckDryRun {
+			fmt.Printf("  would: %s\n", beads.FsckFixAction(p))
+			fixed++
+			continue
+		}
+		if err := bd.FsckFix(p); err != nil {
+			style.PrintWarning("could not fix %s (%s): %v", p.IssueID, p.Kind, err)
+			failed++
+			continue
+		}
+		fixed++
+	}
+
+	if beadFsckDryRun {
+		fmt.Printf("\nDry run: would repair %d problem(s). Nothing changed.\n", fixed)
+		return nil
+	}
+	fmt.Printf("%s Repaired %d problem(s)", style.SuccessPrefix, fixed)
+	if manual > 0 {
+		fmt.Printf(", %d need manual review", manual)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d repair(s) failed", failed)
+	}
+	return nil
+}
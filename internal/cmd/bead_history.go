@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// splitHistoryFlag removes --history from the bd show args and reports
+// whether it was there.
+func splitHistoryFlag(args []string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == "--history" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// runBeadShowHistory shows a bead followed by its activity log. With
+// --json only the log is printed, as a JSON array.
+func runBeadShowHistory(args []string) error {
+	var id string
+	jsonOut := false
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOut = true
+		} else if id == "" && !strings.HasPrefix(arg, "-") {
+			id = arg
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("bead ID required\n\nUsage: gt bead show <bead-id> --history")
+	}
+
+	entries, err := beads.ReadHistory(beadHistoryDir(id), id)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		if entries == nil {
+			entries = []beads.HistoryEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if served, err := showFromSnapshot(args); served {
+		if err != nil {
+			return err
+		}
+	} else {
+		show := exec.Command("bd", append([]string{"show"}, args...)...) //nolint:gosec // G204: bd is a trusted internal tool
+		show.Stdout = os.Stdout
+		show.Stderr = os.Stderr
+		if err := show.Run(); err != nil {
+			return fmt.Errorf("bd show %s: %w", id, err)
+		}
+	}
+
+	if cost := beadCost(id); cost != nil {
+		printBeadCost(cost)
+	}
+	if attachments := beadAttachments(id); len(attachments) > 0 {
+		printBeadAttachments(attachments)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("History"))
+	if len(entries) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No recorded activity"))
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Printf("  %s  %s\n", style.Dim.Render(formatHistoryTime(e.Timestamp)), describeHistoryEntry(e))
+	}
+	return nil
+}
+
+// beadHistoryDir returns the beads directory holding id's activity log: the
+// one its prefix routes to, or the current directory's.
+func beadHistoryDir(id string) string {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id)); rigPath != "" {
+			return beads.ResolveBeadsDir(rigPath)
+		}
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return beads.ResolveBeadsDir(cwd)
+}
+
+func formatHistoryTime(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// describeHistoryEntry renders one activity log entry as a line of text.
+func describeHistoryEntry(e beads.HistoryEntry) string {
+	line := historyEntryText(e)
+	if e.Actor != "" {
+		line += style.Dim.Render("  by " + e.Actor)
+	}
+	return line
+}
+
+// historyEntryText describes what an activity log entry changed, in plain
+// text without the actor.
+func historyEntryText(e beads.HistoryEntry) string {
+	var line string
+	switch e.Kind {
+	case beads.HistoryStatus:
+		line = "status → " + e.Value
+	case beads.HistoryPriority:
+		line = "priority → P" + e.Value
+	case beads.HistoryComment:
+		comment := strings.Join(strings.Fields(e.Value), " ")
+		if len(comment) > 80 {
+			comment = comment[:77] + "..."
+		}
+		line = "comment: " + comment
+	case beads.HistoryMR:
+		line = "merge request " + e.Value
+	case beads.HistoryAttachment:
+		line = "attached " + e.Value
+	case beads.HistoryEvidence:
+		line = e.Value + " evidence"
+		if e.Detail != "" {
+			return line + ": " + e.Detail
+		}
+		return line
+	default:
+		line = e.Kind + " " + e.Value
+	}
+	if e.Detail != "" {
+		line += " (" + e.Detail + ")"
+	}
+	return line
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead import command flags
+var (
+	beadImportFormat      string
+	beadImportOnCollision string
+	beadImportMap         []string
+	beadImportNewIDs      bool
+	beadImportDryRun      bool
+	beadImportJSON        bool
+)
+
+var beadImportCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Import beads from jsonl, csv, github, or markdown",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBeadImport,
+	Long: `Create beads from a file in any 'gt beads export' format, or from
+GitHub and Jira exports. Use - to read from stdin.
+
+IDs:
+  Source IDs that look like bead IDs (gt-abc12) are kept. Other IDs (GitHub
+  issue numbers, Jira keys like PROJ-123) get new bead IDs, and the source
+  ID is noted in the description. Parent and dependency references are
+  rewritten to the new IDs, and parents are created before their children.
+
+ID collisions (--on-collision), when a kept ID already exists:
+  skip     Leave the existing bead alone (default)
+  update   Overwrite its title, description, priority, status, assignee,
+           and labels
+  new      Import the record under a new ID
+
+Labels:
+  Imported labels follow the same rules as 'gt bead labels': reserved gt:
+  labels are refused, and in a town with a label registry every label must
+  be registered. Nothing is imported if any label is rejected.
+
+Field mapping (csv):
+  Columns named after bead fields are read directly. Common GitHub and Jira
+  headers are recognized too (Issue key, Summary, Issue Type, Body, State,
+  Created, Resolved, ...), priorities like Highest/High/P1, and statuses like
+  "To Do", "In Progress", and "Done". Unknown columns are ignored. Use --map
+  to map any other column: --map "Epic Link=parent", or --map "Key=" to
+  ignore a column.
+
+GitHub issues can be fetched with:
+  gh issue list --state all --json number,title,body,state,labels,assignees,url
+
+Examples:
+  gt beads import issues.csv --format csv --dry-run
+  gt beads import jira.csv --format csv --map "Epic Link=parent" --map "Sprint=labels"
+  gh issue list --state all --json number,title,body,state,labels,assignees,url | gt beads import - --format github
+  gt beads import backup.jsonl --on-collision update`,
+}
+
+func init() {
+	beadImportCmd.Flags().StringVar(&beadImportFormat, "format", beads.FormatJSONL, "Input format: jsonl, csv, github, markdown")
+	beadImportCmd.Flags().StringVar(&beadImportOnCollision, "on-collision", beads.CollisionSkip, "When a kept ID already exists: skip, update, new")
+	beadImportCmd.Flags().StringArrayVar(&beadImportMap, "map", nil, "Map a CSV column to a bead field: <column>=<field> (repeatable)")
+	beadImportCmd.Flags().BoolVar(&beadImportNewIDs, "new-ids", false, "Give every imported bead a new ID")
+	beadImportCmd.Flags().BoolVar(&beadImportDryRun, "dry-run", false, "Show what would be imported without changing anything")
+	beadImportCmd.Flags().BoolVar(&beadImportJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadImportCmd)
+}
+
+func runBeadImport(cmd *cobra.Command, args []string) error {
+	switch beadImportOnCollision {
+	case beads.CollisionSkip, beads.CollisionUpdate, beads.CollisionNew:
+	default:
+		return fmt.Errorf("invalid --on-collision %q: must be skip, update, or new", beadImportOnCollision)
+	}
+
+	fieldMap := make(map[string]string, len(beadImportMap))
+	for _, m := range beadImportMap {
+		column, field, ok := strings.Cut(m, "=")
+		if !ok {
+			return fmt.Errorf("invalid --map %q: expected <column>=<field>", m)
+		}
+		fieldMap[column] = field
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening input: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	records, err := beads.ParseImport(r, beadImportFormat, fieldMap)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Println("No records to import.")
+		return nil
+	}
+	if err := checkImportLabels(records); err != nil {
+		return err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	actions := beads.ImportIssues(beads.New(workDir), records, beads.ImportOptions{
+		OnCollision: beadImportOnCollision,
+		NewIDs:      beadImportNewIDs,
+		DryRun:      beadImportDryRun,
+	})
+
+	counts := make(map[string]int)
+	for _, a := range actions {
+		counts[a.Action]++
+	}
+
+	if beadImportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(actions); err != nil {
+			return err
+		}
+	} else {
+		for _, a := range actions {
+			id := a.ID
+			if id == "" {
+				id = style.Dim.Render("(new)")
+			}
+			line := fmt.Sprintf("  %-8s %s", a.Action, id)
+			if a.SourceID != "" && a.SourceID != a.ID {
+				line += style.Dim.Render(" ← " + a.SourceID)
+			}
+			if a.Detail != "" {
+				line += style.Dim.Render("  " + a.Detail)
+			}
+			if a.Action == "failed" {
+				line = style.Error.Render(line)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+		summary := fmt.Sprintf("%d created, %d updated, %d skipped, %d failed",
+			counts["created"], counts["updated"], counts["skipped"], counts["failed"])
+		switch {
+		case beadImportDryRun:
+			fmt.Printf("Dry run: would import %s. Nothing changed.\n", summary)
+		case counts["failed"] > 0:
+			fmt.Printf("%s Imported with failures: %s\n", style.WarningPrefix, summary)
+		default:
+			fmt.Printf("%s Imported: %s\n", style.SuccessPrefix, summary)
+		}
+	}
+
+	if counts["failed"] > 0 && !beadImportDryRun {
+		return NewSilentExit(1)
+	}
+	return nil
+}
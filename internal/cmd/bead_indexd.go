@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead indexd command flags
+var (
+	beadIndexdRig      string
+	beadIndexdInterval time.Duration
+	beadIndexdOnce     bool
+	beadIndexdRebuild  bool
+)
+
+var beadIndexdCmd = &cobra.Command{
+	Use:   "indexd",
+	Short: "Keep the bead search indexes up to date in the background",
+	Args:  cobra.NoArgs,
+	Long: `Maintain the search index of the town's and every rig's beads, so
+'gt bead search' answers from the index instead of scanning beads.
+
+The index holds each bead's words, the file paths it references, and who
+it @mentions. It is updated incrementally from the change feed: beads
+whose updated_at moved are re-read, and comments are picked up from the
+activity log where the last pass stopped. Sealed beads are indexed by
+their labels only.
+
+Run it in its own session; it checks for changes every --interval until
+interrupted. --once makes a single pass, and --rebuild discards the
+indexes and starts over.
+
+Examples:
+  gt bead indexd
+  gt bead indexd --rig gastown --interval 10s
+  gt bead indexd --once --rebuild`,
+	RunE: runBeadIndexd,
+}
+
+func init() {
+	beadIndexdCmd.Flags().StringVar(&beadIndexdRig, "rig", "", "Index only this rig (default: the town and all rigs)")
+	beadIndexdCmd.Flags().DurationVar(&beadIndexdInterval, "interval", 30*time.Second, "How often to check for changes")
+	beadIndexdCmd.Flags().BoolVar(&beadIndexdOnce, "once", false, "Update the indexes once and exit")
+	beadIndexdCmd.Flags().BoolVar(&beadIndexdRebuild, "rebuild", false, "Discard the indexes and rebuild them")
+	beadCmd.AddCommand(beadIndexdCmd)
+}
+
+// indexTarget is a beads database the indexer keeps an index for.
+type indexTarget struct {
+	name string
+	bd   *beads.Beads
+}
+
+func runBeadIndexd(cmd *cobra.Command, args []string) error {
+	if !beadIndexdOnce && beadIndexdInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	targets, err := beadIndexTargets(beadIndexdRig)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rebuild := beadIndexdRebuild
+	for {
+		for _, t := range targets {
+			start := time.Now()
+			_, stats, err := t.bd.UpdateSearchIndex(rebuild)
+			if err != nil {
+				style.PrintWarning("%s: %v", t.name, err)
+				continue
+			}
+			if stats.Changed() || beadIndexdOnce {
+				fmt.Printf("%s %s: %d indexed, %d removed, %d comment(s) %s\n",
+					style.Dim.Render(time.Now().Format("15:04:05")), style.Bold.Render(t.name),
+					stats.Indexed, stats.Removed, stats.Comments,
+					style.Dim.Render(fmt.Sprintf("(%s)", time.Since(start).Round(time.Millisecond))))
+			}
+		}
+		rebuild = false
+		if beadIndexdOnce {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(beadIndexdInterval):
+		}
+	}
+}
+
+// beadIndexTargets returns the town's beads and every rig's, or only
+// rigName's when it is set.
+func beadIndexTargets(rigName string) ([]indexTarget, error) {
+	if rigName != "" {
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return nil, err
+		}
+		return []indexTarget{{name: r.Name, bd: beads.New(r.Path)}}, nil
+	}
+
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return nil, err
+	}
+	targets := []indexTarget{{name: "town", bd: beads.New(townRoot)}}
+	for _, r := range rigs {
+		targets = append(targets, indexTarget{name: r.Name, bd: beads.New(r.Path)})
+	}
+	return targets, nil
+}
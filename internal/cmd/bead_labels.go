@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead labels command flags
+var (
+	beadLabelsJSON        bool
+	beadLabelsColor       string
+	beadLabelsDescription string
+	beadLabelsPrefix      bool
+	beadLabelsDryRun      bool
+)
+
+var beadLabelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Manage the town's shared label taxonomy",
+	RunE:  requireSubcommand,
+	Long: `Manage the town-wide label registry in settings/labels.json.
+
+Each registered label has an optional color and description. Allowed
+prefixes accept whole families of labels (e.g. "sprint-" accepts sprint-4).
+Once the registry lists any label or prefix, 'gt bead new --label' and
+'gt bead bulk retag --add' reject labels it doesn't allow. Gas Town's own
+gt: labels are always allowed. An empty registry allows everything.
+
+Examples:
+  gt bead labels list
+  gt bead labels add incident --color "#d73a4a" --description "Production incident"
+  gt bead labels add sprint- --prefix
+  gt bead labels rename infra infrastructure --dry-run`,
+}
+
+var beadLabelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered labels and allowed prefixes",
+	Args:  cobra.NoArgs,
+	RunE:  runBeadLabelsList,
+}
+
+var beadLabelsAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Register a label (or, with --prefix, an allowed prefix)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBeadLabelsAdd,
+}
+
+var beadLabelsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a label in the registry and on every bead in the town",
+	Long: `Rename a label in the registry, then relabel every bead carrying it,
+open or closed, in the town beads and each rig's beads.
+
+Renaming an unregistered label is allowed when the registry accepts the
+new name, to clean up labels created before the registry existed.
+
+If relabeling fails partway through a database, the beads already changed
+in that database are restored. Re-running the rename picks up where it
+left off.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBeadLabelsRename,
+}
+
+func init() {
+	beadLabelsListCmd.Flags().BoolVar(&beadLabelsJSON, "json", false, "Output as JSON")
+	beadLabelsAddCmd.Flags().StringVar(&beadLabelsColor, "color", "", "Hex color, e.g. #d73a4a")
+	beadLabelsAddCmd.Flags().StringVar(&beadLabelsDescription, "description", "", "What the label means")
+	beadLabelsAddCmd.Flags().BoolVar(&beadLabelsPrefix, "prefix", false, "Allow every label starting with <name>")
+	beadLabelsRenameCmd.Flags().BoolVar(&beadLabelsDryRun, "dry-run", false, "Show the beads that would be relabeled")
+
+	beadLabelsCmd.AddCommand(beadLabelsListCmd)
+	beadLabelsCmd.AddCommand(beadLabelsAddCmd)
+	beadLabelsCmd.AddCommand(beadLabelsRenameCmd)
+	beadCmd.AddCommand(beadLabelsCmd)
+}
+
+func loadTownLabels() (string, *config.LabelRegistry, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	reg, err := config.LoadOrCreateLabelRegistry(config.LabelsConfigPath(townRoot))
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, reg, nil
+}
+
+// checkTownLabels validates labels for a new or retagged bead against the
+// town registry. Outside a workspace there is no registry to check.
+func checkTownLabels(labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	reg, err := config.LoadOrCreateLabelRegistry(config.LabelsConfigPath(townRoot))
+	if err != nil {
+		return err
+	}
+	return reg.CheckLabels(labels)
+}
+
+// checkImportLabels applies the label rules of 'gt bead labels' to imported
+// beads: names must be valid and outside the reserved gt: prefix, and
+// registered if the town keeps a registry.
+func checkImportLabels(issues []*beads.Issue) error {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		for _, l := range issue.Labels {
+			if seen[l] {
+				continue
+			}
+			seen[l] = true
+			if err := config.ValidateLabelName(l); err != nil {
+				if issue.ID != "" {
+					return fmt.Errorf("%s: %w", issue.ID, err)
+				}
+				return err
+			}
+			labels = append(labels, l)
+		}
+	}
+	return checkTownLabels(labels)
+}
+
+func runBeadLabelsList(cmd *cobra.Command, args []string) error {
+	_, reg, err := loadTownLabels()
+	if err != nil {
+		return err
+	}
+
+	if beadLabelsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reg)
+	}
+
+	if reg.IsEmpty() {
+		fmt.Println("No labels registered; any label is allowed.")
+		fmt.Println(style.Dim.Render("Register one with: gt bead labels add <name>"))
+		return nil
+	}
+	if len(reg.Labels) > 0 {
+		fmt.Printf("%s\n", style.Bold.Render("Labels"))
+		for _, l := range reg.Labels {
+			fmt.Printf("  %-20s %-8s %s\n", l.Name, l.Color, style.Dim.Render(l.Description))
+		}
+	}
+	if len(reg.AllowedPrefixes) > 0 {
+		fmt.Printf("%s\n", style.Bold.Render("Allowed prefixes"))
+		for _, p := range reg.AllowedPrefixes {
+			fmt.Printf("  %s*\n", p)
+		}
+	}
+	return nil
+}
+
+func runBeadLabelsAdd(cmd *cobra.Command, args []string) error {
+	townRoot, reg, err := loadTownLabels()
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if beadLabelsPrefix {
+		if beadLabelsColor != "" || beadLabelsDescription != "" {
+			return fmt.Errorf("--color and --description apply to labels, not prefixes")
+		}
+		err = reg.AddPrefix(name)
+	} else {
+		err = reg.Add(config.LabelDef{Name: name, Color: beadLabelsColor, Description: beadLabelsDescription})
+	}
+	if err != nil {
+		return err
+	}
+	if err := config.SaveLabelRegistry(config.LabelsConfigPath(townRoot), reg); err != nil {
+		return err
+	}
+
+	if beadLabelsPrefix {
+		fmt.Printf("%s Allowed labels starting with %s\n", style.SuccessPrefix, style.Bold.Render(name))
+	} else {
+		fmt.Printf("%s Registered label %s\n", style.SuccessPrefix, style.Bold.Render(name))
+	}
+	return nil
+}
+
+func runBeadLabelsRename(cmd *cobra.Command, args []string) error {
+	townRoot, reg, err := loadTownLabels()
+	if err != nil {
+		return err
+	}
+	from, to := args[0], args[1]
+	if from == to {
+		return fmt.Errorf("old and new names are the same")
+	}
+	// Reserved gt: labels drive Gas Town itself; renaming them in bulk would
+	// orphan every agent, MR and convoy that carries them.
+	for _, name := range []string{from, to} {
+		if err := config.ValidateLabelName(name); err != nil {
+			return err
+		}
+	}
+
+	if reg.Find(from) != nil {
+		if err := reg.Rename(from, to); err != nil {
+			return err
+		}
+	} else {
+		if err := reg.CheckLabels([]string{to}); err != nil {
+			return err
+		}
+	}
+
+	dirs := []string{townRoot}
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	for _, r := range rigs {
+		dirs = append(dirs, r.Path)
+	}
+
+	// Registry first: if relabeling fails, re-running the rename finds the
+	// new name registered and finishes the job.
+	if !beadLabelsDryRun && reg.Find(to) != nil {
+		if err := config.SaveLabelRegistry(config.LabelsConfigPath(townRoot), reg); err != nil {
+			return err
+		}
+	}
+
+	action := beads.BulkAction{Op: beads.BulkRetag, AddLabels: []string{to}, RemoveLabels: []string{from}}
+	total := 0
+	for _, dir := range dirs {
+		bd := beads.New(dir)
+		issues, err := bd.BulkSelect(beads.BulkFilter{Status: "all", Label: from}, time.Now())
+		if err != nil {
+			style.PrintWarning("skipping %s: %v", dir, err)
+			continue
+		}
+		if len(issues) == 0 {
+			continue
+		}
+		if beadLabelsDryRun {
+			for _, issue := range issues {
+				fmt.Printf("  %s  -%s +%s  %s\n", style.Bold.Render(issue.ID), from, to, style.Dim.Render(truncateString(issue.Title, 60)))
+			}
+			total += len(issues)
+			continue
+		}
+		result, err := beads.ApplyBulk(bd, issues, action)
+		if err != nil {
+			if result != nil && len(result.RolledBack) > 0 {
+				fmt.Printf("%s Restored %d bead(s) in %s after failure\n", style.WarningPrefix, len(result.RolledBack), dir)
+			}
+			return fmt.Errorf("relabeling beads in %s: %w", dir, err)
+		}
+		total += len(result.Applied)
+	}
+
+	if beadLabelsDryRun {
+		fmt.Printf("\nDry run: would rename %s → %s on %d bead(s). Nothing changed.\n", from, to, total)
+		return nil
+	}
+	fmt.Printf("%s Renamed %s → %s on %d bead(s)\n", style.SuccessPrefix, from, to, total)
+	return nil
+}
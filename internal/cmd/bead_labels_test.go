@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestCheckImportLabels_RejectsReserved(t *testing.T) {
+	t.Chdir(t.TempDir()) // outside a workspace: no registry, name rules only
+
+	ok := []*beads.Issue{{ID: "gt-1", Labels: []string{"frontend", "needs-review"}}}
+	if err := checkImportLabels(ok); err != nil {
+		t.Errorf("checkImportLabels(valid) = %v", err)
+	}
+
+	bad := []*beads.Issue{
+		{ID: "gt-1", Labels: []string{"frontend"}},
+		{ID: "gt-2", Labels: []string{"gt:agent"}},
+	}
+	err := checkImportLabels(bad)
+	if err == nil || !strings.Contains(err.Error(), "gt-2") {
+		t.Errorf("checkImportLabels(gt:agent) = %v, want an error naming gt-2", err)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadMirrorRig    string
+	beadMirrorDir    string
+	beadMirrorDryRun bool
+)
+
+var beadMirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: "Publish a read-only static mirror of a rig's open beads",
+	Long: `Write a static, read-only copy of a rig's open beads for people who
+don't have gt: beads.json for tools and index.html for browsers. Point an
+internal web server at the directory.
+
+The mirror is sanitized. It holds open work items only (open, in_progress,
+blocked, deferred, hooked); Gas Town's own beads (agents, mail, merge
+requests, ...), wisps and sensitive beads are left out, gt: labels are
+dropped, and sealed values are masked. Descriptions are published only
+when the rig opts in.
+
+Configure it in the rig's settings/config.json; the daemon then refreshes
+it every interval:
+
+    "mirror": {
+      "dir": "/srv/www/backlog/gastown",
+      "interval": "10m",
+      "include_descriptions": false,
+      "exclude_labels": ["internal"]
+    }
+
+A relative dir is under the rig. --dir writes a one-off mirror elsewhere.
+
+Examples:
+  gt bead mirror --rig gastown
+  gt bead mirror --rig gastown --dir /tmp/backlog
+  gt bead mirror --rig gastown --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBeadMirror,
+}
+
+func init() {
+	beadMirrorCmd.Flags().StringVar(&beadMirrorRig, "rig", "", "Rig to mirror (default: current rig)")
+	beadMirrorCmd.Flags().StringVar(&beadMirrorDir, "dir", "", "Output directory (default: the rig's mirror.dir)")
+	beadMirrorCmd.Flags().BoolVar(&beadMirrorDryRun, "dry-run", false, "Show what would be published without writing")
+	beadCmd.AddCommand(beadMirrorCmd)
+}
+
+func runBeadMirror(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := beadMirrorRig
+	if rigName == "" {
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return fmt.Errorf("could not determine rig (use --rig): %w", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.LoadRigMirror(r.Path)
+	if cfg == nil {
+		cfg = &config.MirrorConfig{}
+	}
+	dir := beadMirrorDir
+	if dir == "" {
+		if cfg.Dir == "" {
+			return fmt.Errorf("rig %s has no mirror.dir in %s; set one or pass --dir", rigName, config.RigSettingsPath(r.Path))
+		}
+		dir = cfg.OutputDir(r.Path)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	issues, err := beads.New(r.Path).List(beads.ListOptions{Status: "all"})
+	if err != nil {
+		return fmt.Errorf("listing beads: %w", err)
+	}
+	m := beads.BuildMirror(rigName, issues, beads.MirrorOptions{
+		IncludeDescriptions: cfg.IncludeDescriptions,
+		ExcludeLabels:       cfg.ExcludeLabels,
+	}, time.Now())
+
+	if beadMirrorDryRun {
+		fmt.Printf("Would publish %d of %d bead(s) to %s\n", len(m.Beads), len(issues), dir)
+		for _, b := range m.Beads {
+			fmt.Printf("  %s  P%d %-11s %s\n", style.Bold.Render(b.ID), b.Priority, b.Status, style.Dim.Render(truncateString(b.Title, 60)))
+		}
+		return nil
+	}
+	if err := beads.WriteMirror(dir, m); err != nil {
+		return err
+	}
+	fmt.Printf("%s Published %d bead(s) to %s\n", style.SuccessPrefix, len(m.Beads), dir)
+	return nil
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead new command flags
+var (
+	beadNewTemplate    string
+	beadNewRig         string
+	beadNewPriority    int
+	beadNewLabels      []string
+	beadNewDescription string
+	beadNewFields      []string
+	beadNewParent      string
+	beadNewDryRun      bool
+	beadNewJSON        bool
+	beadTemplatesRig   string
+	beadTemplatesJSON  bool
+)
+
+var beadNewCmd = &cobra.Command{
+	Use:   "new <title>",
+	Short: "Create a bead from a template",
+	Long: `Create a bead pre-populated from a template.
+
+A template sets the bead's type, default priority, labels, description
+sections, and "key: value" fields such as a merge request's target. Flags
+add to or override the template.
+
+Templates are YAML files in .beads/templates/<name>.yaml, searched in the
+rig's beads directory, then the town's. Built-in templates (bug,
+merge-request, convoy-task) apply unless a file of the same name overrides
+them. See 'gt bead templates' for what's available.
+
+--label values must be allowed by the town label registry, if it defines
+any labels (see 'gt bead labels'). The rig's assignment rules pick the
+assignee (see 'gt bead assign').
+
+Example template (.beads/templates/bug.yaml):
+
+  type: bug
+  priority: 1
+  labels: [triage]
+  sections: [Steps to reproduce, Expected behavior, Actual behavior]
+
+Examples:
+  gt bead new --template bug "Login fails after token refresh"
+  gt bead new -t merge-request "Add retry to fetch" --field branch=polecat/Toast/gt-abc
+  gt bead new -t convoy-task "Port scanner" --rig gastown --parent gt-cv1
+  gt bead new -t bug "Crash on start" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadNew,
+}
+
+var beadTemplatesCmd = &cobra.Command{
+	Use:   "templates [name]",
+	Short: "List bead templates, or show one",
+	Long: `List the bead templates available in a rig, or show what a template
+produces.
+
+Examples:
+  gt bead templates
+  gt bead templates bug
+  gt bead templates --rig gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBeadTemplates,
+}
+
+func init() {
+	beadNewCmd.Flags().StringVarP(&beadNewTemplate, "template", "t", "", "Template to start from")
+	beadNewCmd.Flags().StringVar(&beadNewRig, "rig", "", "Rig to create the bead in (default: current rig, else town)")
+	beadNewCmd.Flags().IntVarP(&beadNewPriority, "priority", "p", -1, "Priority 0-4 (default: from the template)")
+	beadNewCmd.Flags().StringArrayVarP(&beadNewLabels, "label", "l", nil, "Add a label (repeatable)")
+	beadNewCmd.Flags().StringVarP(&beadNewDescription, "description", "d", "", "Text placed before the template's sections")
+	beadNewCmd.Flags().StringArrayVar(&beadNewFields, "field", nil, "Set a template field as key=value (repeatable)")
+	beadNewCmd.Flags().StringVar(&beadNewParent, "parent", "", "Parent bead ID")
+	beadNewCmd.Flags().BoolVar(&beadNewDryRun, "dry-run", false, "Show the bead without creating it")
+	beadNewCmd.Flags().BoolVar(&beadNewJSON, "json", false, "Output as JSON")
+
+	beadTemplatesCmd.Flags().StringVar(&beadTemplatesRig, "rig", "", "Rig whose templates to list (default: current rig)")
+	beadTemplatesCmd.Flags().BoolVar(&beadTemplatesJSON, "json", false, "Output as JSON")
+
+	beadCmd.AddCommand(beadNewCmd)
+	beadCmd.AddCommand(beadTemplatesCmd)
+}
+
+// resolveTemplateRig returns the beads working directory for rigName, the
+// current rig, or the town when neither applies, plus the template search
+// directories. rigPath is empty for the town.
+func resolveTemplateRig(rigName string) (workDir, rigPath string, dirs []string, err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", nil, err
+	}
+	if rigName == "" {
+		if inferred, err := inferRigFromCwd(townRoot); err == nil {
+			if _, r, err := getRig(inferred); err == nil {
+				return r.Path, r.Path, beads.TemplateDirs(townRoot, r.Path), nil
+			}
+		}
+		return townRoot, "", beads.TemplateDirs(townRoot, ""), nil
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return r.Path, r.Path, beads.TemplateDirs(townRoot, r.Path), nil
+}
+
+// beadCreator is the part of *beads.Beads that gt bead new writes through.
+type beadCreator interface {
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	AddComment(id, text string) error
+}
+
+// createTemplateBead creates bead and applies the rig's assignment rules to
+// it, so rules take effect without the optional on_create hook. Label and
+// assignment failures are warnings: the bead exists by then.
+func createTemplateBead(bd beadCreator, bead *beads.TemplateBead, parent string, rules *config.AssignmentConfig, owners *config.OwnersConfig) (*beads.Issue, error) {
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       bead.Title,
+		Type:        bead.Type,
+		Priority:    bead.Priority,
+		Description: bead.Description,
+		Parent:      parent,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating bead: %w", err)
+	}
+	if len(bead.Labels) > 0 {
+		if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: bead.Labels}); err != nil {
+			style.PrintWarning("created %s but could not add labels: %v", issue.ID, err)
+		} else {
+			issue.Labels = append(issue.Labels, bead.Labels...)
+		}
+	}
+
+	// bd records the type as a gt:<type> label; match rules on the
+	// template's type rather than bd's default issue_type.
+	issue.Type = bead.Type
+	if issue.Assignee == "" {
+		if d := beads.ResolveAssigneeWithOwners(rules, owners, issue); d.Assignee != "" {
+			if err := assignFromRules(bd, issue.ID, d); err != nil {
+				style.PrintWarning("created %s but could not assign it: %v", issue.ID, err)
+			} else {
+				issue.Assignee = d.Assignee
+			}
+		}
+	}
+	return issue, nil
+}
+
+func runBeadNew(cmd *cobra.Command, args []string) error {
+	workDir, rigPath, dirs, err := resolveTemplateRig(beadNewRig)
+	if err != nil {
+		return err
+	}
+
+	tmpl := &beads.Template{Name: "(none)", Type: "task"}
+	if beadNewTemplate != "" {
+		if tmpl, err = beads.LoadTemplate(dirs, beadNewTemplate); err != nil {
+			return err
+		}
+	}
+
+	fields := make(map[string]string)
+	for _, f := range beadNewFields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --field %q: expected key=value", f)
+		}
+		fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if beadNewPriority > 4 {
+		return fmt.Errorf("invalid --priority %d: must be 0-4", beadNewPriority)
+	}
+	if err := checkTownLabels(beadNewLabels); err != nil {
+		return err
+	}
+
+	bead, err := tmpl.Apply(beads.TemplateInput{
+		Title:       args[0],
+		Priority:    beadNewPriority,
+		Labels:      beadNewLabels,
+		Description: beadNewDescription,
+		Fields:      fields,
+	})
+	if err != nil {
+		return err
+	}
+
+	var rules *config.AssignmentConfig
+	if rigPath != "" {
+		rules = config.LoadRigAssignment(rigPath)
+	}
+	owners := loadCwdTownOwners()
+
+	if beadNewDryRun {
+		if beadNewJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(bead)
+		}
+		fmt.Printf("Would create %s bead from template %s:\n\n", bead.Type, tmpl.Name)
+		printTemplateBead(bead)
+		// The creator is unknown before creation, so path rules can't match here.
+		if d := beads.ResolveAssigneeWithOwners(rules, owners, &beads.Issue{Type: bead.Type, Labels: bead.Labels}); d.Assignee != "" {
+			fmt.Printf("\n  Assignee: %s %s\n", d.Assignee, style.Dim.Render("("+d.Reason+")"))
+		}
+		return nil
+	}
+
+	issue, err := createTemplateBead(beads.New(workDir), bead, beadNewParent, rules, owners)
+	if err != nil {
+		return err
+	}
+
+	if beadNewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			ID       string `json:"id"`
+			Assignee string `json:"assignee,omitempty"`
+			*beads.TemplateBead
+		}{issue.ID, issue.Assignee, bead})
+	}
+	fmt.Printf("%s Created %s: %s\n", style.SuccessPrefix, style.Bold.Render(issue.ID), bead.Title)
+	if issue.Assignee != "" {
+		fmt.Printf("  Assigned to %s\n", issue.Assignee)
+	}
+	if beadNewTemplate != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("from template "+tmpl.Name+" ("+tmpl.Source+")"))
+	}
+	return nil
+}
+
+func runBeadTemplates(cmd *cobra.Command, args []string) error {
+	_, _, dirs, err := resolveTemplateRig(beadTemplatesRig)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		tmpl, err := beads.LoadTemplate(dirs, args[0])
+		if err != nil {
+			return err
+		}
+		if beadTemplatesJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(tmpl)
+		}
+		preview, err := tmpl.Apply(beads.TemplateInput{Title: "<title>", Priority: -1})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n\n", style.Bold.Render(tmpl.Name), style.Dim.Render(tmpl.Source))
+		printTemplateBead(preview)
+		return nil
+	}
+
+	templates, errs := beads.ListTemplates(dirs)
+	if beadTemplatesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(templates); err != nil {
+			return err
+		}
+	} else {
+		for _, t := range templates {
+			priority := "-"
+			if t.Priority != nil {
+				priority = fmt.Sprintf("P%d", *t.Priority)
+			}
+			fmt.Printf("  %-16s %-14s %-3s %s\n", t.Name, t.Type, priority, style.Dim.Render(t.Source))
+		}
+	}
+	for _, err := range errs {
+		style.PrintWarning("%v", err)
+	}
+	if len(errs) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printTemplateBead prints a bead built from a template.
+func printTemplateBead(b *beads.TemplateBead) {
+	fmt.Printf("  Title:    %s\n", b.Title)
+	fmt.Printf("  Type:     %s\n", b.Type)
+	fmt.Printf("  Priority: P%d\n", b.Priority)
+	if len(b.Labels) > 0 {
+		fmt.Printf("  Labels:   %s\n", strings.Join(b.Labels, ", "))
+	}
+	fmt.Println()
+	for _, line := range strings.Split(b.Description, "\n") {
+		fmt.Printf("  %s\n", style.Dim.Render(line))
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeBeadCreator records the writes gt bead new makes.
+type fakeBeadCreator struct {
+	created  beads.CreateOptions
+	labels   []string
+	assignee string
+	comments []string
+}
+
+func (f *fakeBeadCreator) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	f.created = opts
+	return &beads.Issue{ID: "gt-new1", Title: opts.Title, Type: "task", CreatedBy: "gastown/crew/max"}, nil
+}
+
+func (f *fakeBeadCreator) Update(id string, opts beads.UpdateOptions) error {
+	f.labels = append(f.labels, opts.AddLabels...)
+	if opts.Assignee != nil {
+		f.assignee = *opts.Assignee
+	}
+	return nil
+}
+
+func (f *fakeBeadCreator) AddComment(id, text string) error {
+	f.comments = append(f.comments, text)
+	return nil
+}
+
+func TestCreateTemplateBead_AppliesAssignmentRules(t *testing.T) {
+	rules := &config.AssignmentConfig{
+		Default: "gastown/crew/triage",
+		Rules: []config.AssignmentRule{
+			{Label: "infra", Assignee: "gastown/crew/max"},
+			{Type: "bug", Assignee: "gastown/crew/bugs"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		bead beads.TemplateBead
+		want string
+	}{
+		{"label rule", beads.TemplateBead{Title: "Disk full", Type: "task", Labels: []string{"infra"}}, "gastown/crew/max"},
+		{"type rule uses template type", beads.TemplateBead{Title: "Crash", Type: "bug"}, "gastown/crew/bugs"},
+		{"default", beads.TemplateBead{Title: "Docs", Type: "chore"}, "gastown/crew/triage"},
+		{"never gt beads", beads.TemplateBead{Title: "Merge", Type: "merge-request"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := &fakeBeadCreator{}
+			issue, err := createTemplateBead(bd, &tt.bead, "", rules, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bd.assignee != tt.want || issue.Assignee != tt.want {
+				t.Errorf("assignee = %q (issue %q), want %q", bd.assignee, issue.Assignee, tt.want)
+			}
+			if tt.want != "" && (len(bd.comments) != 1 || !strings.Contains(bd.comments[0], tt.want)) {
+				t.Errorf("comments = %q, want one recording the rule", bd.comments)
+			}
+		})
+	}
+
+	// No rules: the bead is left unassigned.
+	bd := &fakeBeadCreator{}
+	if _, err := createTemplateBead(bd, &beads.TemplateBead{Title: "x", Type: "task"}, "", nil, nil); err != nil || bd.assignee != "" {
+		t.Errorf("without rules: assignee %q, err %v", bd.assignee, err)
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadNextRig   string
+	beadNextAll   bool
+	beadNextClaim bool
+	beadNextJSON  bool
+)
+
+var beadNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the next bead a worker should pick up",
+	Long: `Rank the rig's ready beads for a worker and show the best one.
+
+Only beads with no open blockers are considered (as in 'bd ready'). A bead
+is skipped, with the reason shown under --all, when it is:
+
+  - not work: ephemeral, an epic, or one of Gas Town's own bead types
+  - assigned or leased to someone else
+  - labelled with one of the rig's dispatch skip_labels
+  - touching files another worker's in-progress bead touches
+  - owned by another team, when strict_ownership is set
+
+The rest are scored: beads assigned to the worker first, then by priority
+(P0 highest), ownership (+50 for the worker's team, -50 for another's),
+label weights, and one point per day waiting (up to 14).
+
+A bead lists the paths it expects to touch in a "files:" description line
+(comma-separated, CODEOWNERS-style patterns); beads without one never
+collide. Ownership comes from settings/owners.json, by label or by those
+files.
+
+Nothing is dispatched while the worker has wip_limit beads in progress
+(default 1), or the rig has rig_wip_limit. Set these in the rig's
+settings/config.json:
+
+    "dispatch": {
+      "wip_limit": 2,
+      "rig_wip_limit": 8,
+      "label_weights": {"urgent": 150, "nice-to-have": -50},
+      "skip_labels": ["needs-human"],
+      "strict_ownership": false
+    }
+
+With --claim the next bead is claimed for the worker under a lease (see
+'gt bead claim'), so two workers asking at once never get the same bead.
+
+Examples:
+  gt bead next                              # Next bead for the current agent
+  gt bead next --as gastown/polecats/toast  # ... for another worker
+  gt bead next --all                        # Whole ranked queue, with skips
+  gt bead next --claim --for 4h             # Take it
+  gt bead next --json                       # For dispatch scripts`,
+	Args: cobra.NoArgs,
+	RunE: runBeadNext,
+}
+
+func init() {
+	beadNextCmd.Flags().StringVar(&beadNextRig, "rig", "", "Rig to dispatch from (default: the current directory's)")
+	beadNextCmd.Flags().StringVar(&beadClaimAs, "as", "", "Worker to rank for (default: the current agent)")
+	beadNextCmd.Flags().BoolVar(&beadNextAll, "all", false, "Show the whole ranked queue and skipped beads")
+	beadNextCmd.Flags().BoolVar(&beadNextClaim, "claim", false, "Claim the next bead for the worker")
+	beadNextCmd.Flags().StringVar(&beadClaimFor, "for", "", "Lease duration with --claim (default 2h)")
+	beadNextCmd.Flags().BoolVar(&beadNextJSON, "json", false, "Output as JSON")
+
+	beadCmd.AddCommand(beadNextCmd)
+}
+
+func runBeadNext(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := beadNextRig
+	if rigName == "" {
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return fmt.Errorf("could not determine rig (use --rig): %w", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	var ttl time.Duration
+	if beadClaimFor != "" {
+		d, err := parseDuration(beadClaimFor)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --for %q: want a positive duration like 30m or 4h", beadClaimFor)
+		}
+		ttl = d
+	}
+	worker := claimHolder()
+	if worker == "" {
+		return fmt.Errorf("could not determine the worker (use --as)")
+	}
+
+	bd := beads.New(r.BeadsPath())
+	opts := beads.DispatchOptions{
+		Worker: worker,
+		Config: config.LoadRigDispatch(r.Path),
+		Owners: config.LoadTownOwners(townRoot),
+		Now:    time.Now(),
+	}
+	// A claim can lose a race with another worker; rank again and take the
+	// next one rather than failing.
+	var plan *beads.DispatchPlan
+	var lease *beads.Lease
+	for attempt := 0; attempt < 3; attempt++ {
+		if plan, err = bd.PlanDispatch(opts); err != nil {
+			return err
+		}
+		if !beadNextClaim || plan.Next == nil {
+			break
+		}
+		lease, err = bd.Claim(plan.Next.ID, worker, ttl, false)
+		var held *beads.LeaseHeldError
+		if errors.As(err, &held) {
+			lease = nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+	raced := beadNextClaim && plan.Next != nil && lease == nil
+	if raced {
+		plan.Next = nil
+	}
+
+	if beadNextJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		out := struct {
+			*beads.DispatchPlan
+			Claimed *beads.Lease `json:"claimed,omitempty"`
+		}{plan, lease}
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printDispatchPlan(plan, lease, raced)
+	}
+	if plan.Next == nil {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printDispatchPlan(plan *beads.DispatchPlan, lease *beads.Lease, raced bool) {
+	if plan.Held != "" {
+		fmt.Printf("%s %s\n", style.WarningPrefix, plan.Held)
+	}
+	switch {
+	case plan.Next != nil && lease != nil:
+		fmt.Printf("%s Claimed %s for %s until %s\n", style.SuccessPrefix, style.Bold.Render(plan.Next.ID),
+			lease.Holder, lease.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	case plan.Next != nil:
+		fmt.Printf("Next for %s: %s\n", plan.Worker, style.Bold.Render(plan.Next.ID))
+	case plan.Held == "" && len(plan.Queue) == 0:
+		fmt.Printf("No ready beads for %s\n", plan.Worker)
+	}
+	if plan.Next != nil {
+		printDispatchCandidate(*plan.Next)
+	} else if raced {
+		fmt.Printf("%s Other workers claimed the top candidates first; try again\n", style.WarningPrefix)
+	}
+	if !beadNextAll {
+		return
+	}
+
+	fmt.Printf("\n%s (%d)\n", style.Bold.Render("Queue"), len(plan.Queue))
+	for _, c := range plan.Queue {
+		printDispatchCandidate(c)
+	}
+	if len(plan.Skipped) > 0 {
+		fmt.Printf("\n%s (%d)\n", style.Bold.Render("Skipped"), len(plan.Skipped))
+		for _, c := range plan.Skipped {
+			fmt.Printf("  %s  %s  %s\n", c.ID, style.Dim.Render(truncateString(c.Title, 50)), c.Skipped)
+		}
+	}
+}
+
+func printDispatchCandidate(c beads.DispatchCandidate) {
+	fmt.Printf("  %s  %5d  %s\n", c.ID, c.Score, truncateString(c.Title, 60))
+	if len(c.Reasons) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(strings.Repeat(" ", len(c.ID))+"         "+strings.Join(c.Reasons, ", ")))
+	}
+}
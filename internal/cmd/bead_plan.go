@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead plan command flags
+var (
+	beadPlanJSON  bool
+	beadPlanReady bool
+)
+
+var beadPlanCmd = &cobra.Command{
+	Use:   "plan <epic-id>",
+	Short: "Critical path and dispatch order for an epic's children",
+	Args:  cobra.ExactArgs(1),
+	Long: `Schedule the open children of an epic.
+
+Each child is scheduled as early as its blocking dependencies on other
+children allow, as if every ready task had a polecat. The plan shows the
+critical path (the chain of work that sets the epic's length), each
+task's slack, and a suggested dispatch order: earliest start first, then
+least slack, then priority. Tasks of one wave can run in parallel once
+the earlier waves land.
+
+Estimates come from an "estimate: 2h" line in a bead's description (or
+an estimate:2h label); a duration like 90m or 2h30m, or a number of hours.
+Tasks without one are assumed to take 1h and flagged.
+
+A task is ready when it is open and waits on nothing. Open beads outside
+the epic hold a task back but do not lengthen the plan.
+
+Use --ready for the tasks to sling now, in order:
+  gt beads plan gt-epic --ready --json | jq -r '.[].id'
+
+Examples:
+  gt beads plan gt-abc
+  gt beads plan gt-abc --json
+  gt beads plan gt-abc --ready`,
+	RunE: runBeadPlan,
+}
+
+func init() {
+	beadPlanCmd.Flags().BoolVar(&beadPlanJSON, "json", false, "Output as JSON")
+	beadPlanCmd.Flags().BoolVar(&beadPlanReady, "ready", false, "Only tasks that can be dispatched now")
+	beadCmd.AddCommand(beadPlanCmd)
+}
+
+func runBeadPlan(cmd *cobra.Command, args []string) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	plan, err := beads.New(workDir).PlanEpic(args[0])
+	if err != nil {
+		return err
+	}
+
+	if beadPlanJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if beadPlanReady {
+			ready := plan.Ready()
+			if ready == nil {
+				ready = []beads.PlanTask{}
+			}
+			return enc.Encode(ready)
+		}
+		return enc.Encode(plan)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("📐 "+plan.Epic+":"), plan.Title)
+	fmt.Printf("   %d open, %d done · critical path %s\n\n", len(plan.Tasks), len(plan.Done), formatPlanMinutes(plan.Length))
+
+	tasks := plan.Tasks
+	if beadPlanReady {
+		tasks = plan.Ready()
+	}
+	if len(tasks) == 0 {
+		if beadPlanReady {
+			fmt.Println("Nothing ready to dispatch.")
+		} else {
+			fmt.Printf("%s All children are done\n", style.SuccessPrefix)
+		}
+		return nil
+	}
+
+	var unestimated []string
+	for _, t := range tasks {
+		mark := " "
+		if t.Critical {
+			mark = style.Bold.Render("★")
+		}
+		est := formatPlanMinutes(t.Estimate)
+		if !t.Estimated {
+			est += "?"
+			unestimated = append(unestimated, t.ID)
+		}
+		state := t.Status
+		switch {
+		case t.Ready:
+			state = style.Success.Render("ready")
+		case len(t.BlockedBy) > 0:
+			state = "blocked by " + strings.Join(t.BlockedBy, ", ")
+		case len(t.DependsOn) > 0 && t.Status == "open":
+			state = "after " + strings.Join(t.DependsOn, ", ")
+		}
+		fmt.Printf("  %2d %s %-12s w%d  est %-6s start +%-6s slack %-6s %s  %s\n",
+			t.Order, mark, t.ID, t.Wave, est, formatPlanMinutes(t.Start), formatPlanMinutes(t.Slack),
+			truncateString(t.Title, 40), style.Dim.Render("["+state+"]"))
+	}
+
+	if len(plan.CriticalPath) > 0 {
+		fmt.Printf("\n%s %s\n", style.Bold.Render("Critical path:"), strings.Join(plan.CriticalPath, " → "))
+	}
+	if len(unestimated) > 0 {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("No estimate (assumed %s): %s — add an \"estimate: 2h\" line to the description",
+			formatPlanMinutes(int(beads.DefaultEstimate/time.Minute)), strings.Join(unestimated, ", "))))
+	}
+	fmt.Printf("%s\n", style.Dim.Render("Dispatch ready work with: gt sling <bead> <rig>"))
+	return nil
+}
+
+// formatPlanMinutes renders minutes as a short duration (45m, 2h, 1h30m).
+func formatPlanMinutes(m int) string {
+	switch {
+	case m < 60:
+		return fmt.Sprintf("%dm", m)
+	case m%60 == 0:
+		return fmt.Sprintf("%dh", m/60)
+	}
+	return fmt.Sprintf("%dh%dm", m/60, m%60)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead quickadd command flags
+var (
+	beadQuickAddRig    string
+	beadQuickAddDryRun bool
+	beadQuickAddJSON   bool
+)
+
+var beadQuickAddCmd = &cobra.Command{
+	Use:   "quickadd <line>",
+	Short: "Create a bead from one line of text",
+	Long: `Create a bead from a single line, for fast capture mid-incident.
+
+The line is parsed for:
+  P0-P4        Priority (default 2)
+  bug, feature, task, epic, chore
+               Type, when it is the first word of the title (default task)
+  #label       Label (must be allowed by the town label registry)
+  @who         The first is the assignee, later ones are mentions
+
+Everything else is the title. @who may be a team from the ownership map
+(assigned to its lead), a crew member or polecat of the rig, or a full
+address such as gastown/crew/joe. Without an @assignee the rig's assignment
+rules apply, as for 'gt bead new'. Mentions are recorded in the description.
+
+Quote the line so the shell leaves # and @ alone.
+
+Examples:
+  gt bead quickadd "P1 bug in refinery: test gate hangs on timeout @crew-lead #refinery"
+  gt bead quickadd "feature: dark mode for the dashboard #ui @max @joe"
+  gt bead quickadd "P0 dolt server down on hq" --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadQuickAdd,
+}
+
+func init() {
+	beadQuickAddCmd.Flags().StringVar(&beadQuickAddRig, "rig", "", "Rig to create the bead in (default: current rig, else town)")
+	beadQuickAddCmd.Flags().BoolVar(&beadQuickAddDryRun, "dry-run", false, "Show the parsed bead without creating it")
+	beadQuickAddCmd.Flags().BoolVar(&beadQuickAddJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadQuickAddCmd)
+}
+
+func runBeadQuickAdd(cmd *cobra.Command, args []string) error {
+	// Accept the line unquoted too, as long as the shell left it intact
+	line := strings.Join(args, " ")
+	q, err := beads.ParseQuickAdd(line)
+	if err != nil {
+		return err
+	}
+	for _, l := range q.Labels {
+		if err := config.ValidateLabelName(l); err != nil {
+			return fmt.Errorf("label #%s: %w", l, err)
+		}
+	}
+	if err := checkTownLabels(q.Labels); err != nil {
+		return err
+	}
+
+	workDir, rigPath, _, err := resolveTemplateRig(beadQuickAddRig)
+	if err != nil {
+		return err
+	}
+	owners := loadCwdTownOwners()
+
+	priority := q.Priority
+	if priority < 0 {
+		priority = 2
+	}
+	assignee := ""
+	if q.Assignee != "" {
+		if assignee = resolveMention(q.Assignee, rigPath, owners); assignee == "" {
+			return fmt.Errorf("@%s is a team with several members and no lead; name a member", q.Assignee)
+		}
+	}
+	var mentions []string
+	for _, m := range q.Mentions {
+		if who := resolveMention(m, rigPath, owners); who != "" {
+			mentions = append(mentions, who)
+		} else {
+			mentions = append(mentions, m)
+		}
+	}
+	description := "Captured with gt bead quickadd:\n" + line
+	if len(mentions) > 0 {
+		description += "\n\nmentions: " + strings.Join(mentions, ", ")
+	}
+	bead := &beads.TemplateBead{
+		Title:       q.Title,
+		Type:        q.Type,
+		Priority:    priority,
+		Labels:      q.Labels,
+		Description: description,
+	}
+
+	if beadQuickAddDryRun {
+		if beadQuickAddJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(quickAddResult{Assignee: assignee, Mentions: mentions, TemplateBead: bead})
+		}
+		fmt.Println("Would create:")
+		fmt.Println()
+		printTemplateBead(bead)
+		if assignee != "" {
+			fmt.Printf("\n  Assignee: %s\n", assignee)
+		}
+		return nil
+	}
+
+	bd := beads.New(workDir)
+	var issue *beads.Issue
+	if assignee == "" {
+		var rules *config.AssignmentConfig
+		if rigPath != "" {
+			rules = config.LoadRigAssignment(rigPath)
+		}
+		issue, err = createTemplateBead(bd, bead, "", rules, owners)
+	} else {
+		issue, err = createTemplateBead(bd, bead, "", nil, nil)
+		if err == nil {
+			if err := bd.Update(issue.ID, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+				style.PrintWarning("created %s but could not assign it: %v", issue.ID, err)
+			} else {
+				issue.Assignee = assignee
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if beadQuickAddJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(quickAddResult{ID: issue.ID, Assignee: issue.Assignee, Mentions: mentions, TemplateBead: bead})
+	}
+	fmt.Printf("%s Created %s: %s\n", style.SuccessPrefix, style.Bold.Render(issue.ID), bead.Title)
+	fmt.Printf("  %s P%d", bead.Type, bead.Priority)
+	if len(bead.Labels) > 0 {
+		fmt.Printf("  #%s", strings.Join(bead.Labels, " #"))
+	}
+	fmt.Println()
+	if issue.Assignee != "" {
+		fmt.Printf("  Assigned to %s\n", issue.Assignee)
+	}
+	if len(mentions) > 0 {
+		fmt.Printf("  Mentions: %s\n", strings.Join(mentions, ", "))
+	}
+	return nil
+}
+
+// quickAddResult is the gt bead quickadd --json output.
+type quickAddResult struct {
+	ID       string   `json:"id,omitempty"`
+	Assignee string   `json:"assignee,omitempty"`
+	Mentions []string `json:"mentions,omitempty"`
+	*beads.TemplateBead
+}
+
+// resolveMention turns an @mention into a worker: a team in the ownership
+// map becomes its lead (or only member, "" if it has neither), a bare name
+// becomes the rig's crew member or polecat of that name, and anything else
+// (a full address, or a name the rig doesn't know) is used as given.
+func resolveMention(who, rigPath string, owners *config.OwnersConfig) string {
+	if owners.FindTeam(who) != nil {
+		return owners.Assignee(who)
+	}
+	if strings.Contains(who, "/") || rigPath == "" {
+		return who
+	}
+	rigName := filepath.Base(rigPath)
+	for _, role := range []string{"crew", "polecats"} {
+		if info, err := os.Stat(filepath.Join(rigPath, role, who)); err == nil && info.IsDir() {
+			return rigName + "/" + role + "/" + who
+		}
+	}
+	return who
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestResolveMention(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	for _, dir := range []string{"crew/joe", "polecats/nux"} {
+		if err := os.MkdirAll(filepath.Join(rigPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owners := &config.OwnersConfig{Teams: []config.TeamDef{
+		{Name: "crew-lead", Members: []string{"gastown/crew/joe", "gastown/crew/max"}, Lead: "gastown/crew/max"},
+		{Name: "infra", Members: []string{"gastown/crew/joe", "gastown/crew/max"}},
+	}}
+
+	tests := map[string]string{
+		"crew-lead":        "gastown/crew/max",
+		"infra":            "", // several members, no lead
+		"joe":              "gastown/crew/joe",
+		"nux":              "gastown/polecats/nux",
+		"beads/crew/wolf":  "beads/crew/wolf",
+		"someone-external": "someone-external",
+	}
+	for who, want := range tests {
+		if got := resolveMention(who, rigPath, owners); got != want {
+			t.Errorf("resolveMention(%q) = %q, want %q", who, got, want)
+		}
+	}
+	if got := resolveMention("joe", "", nil); got != "joe" {
+		t.Errorf("resolveMention without a rig = %q, want joe", got)
+	}
+}
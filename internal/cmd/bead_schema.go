@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead schema command flags
+var (
+	beadSchemaRig  string
+	beadSchemaJSON bool
+)
+
+var beadSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Show the bead schema (fields, types, statuses, relations)",
+	Long: `Show the live bead schema for a rig: issue fields, issue types with the
+structured description fields each carries, statuses, and relation types.
+
+Built-in entries come from beads and Gas Town. Custom types and statuses
+are read from the rig's bd config (types.custom, status.custom), and
+custom description fields from its bead templates (gt bead templates).
+
+Use --json to generate agent prompts or integrations from the schema
+instead of hardcoding field lists. The dashboard serves the same document
+at /api/beads/schema.
+
+Examples:
+  gt bead schema
+  gt bead schema --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runBeadSchema,
+}
+
+func init() {
+	beadSchemaCmd.Flags().StringVar(&beadSchemaRig, "rig", "", "Rig whose schema to show (default: current rig, or town)")
+	beadSchemaCmd.Flags().BoolVar(&beadSchemaJSON, "json", false, "Output as JSON")
+
+	beadCmd.AddCommand(beadSchemaCmd)
+}
+
+func runBeadSchema(cmd *cobra.Command, args []string) error {
+	workDir, _, dirs, err := resolveTemplateRig(beadSchemaRig)
+	if err != nil {
+		return err
+	}
+	schema := beads.New(workDir).LoadSchema(dirs)
+
+	if beadSchemaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(schema)
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Fields"))
+	for _, f := range schema.Fields {
+		printSchemaField(f)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Types"))
+	for _, t := range schema.Types {
+		fmt.Printf("  %-16s %s\n", t.Name, style.Dim.Render(t.Source))
+		for _, f := range t.Fields {
+			fmt.Print("    ")
+			printSchemaField(f)
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Statuses"))
+	for _, s := range schema.Statuses {
+		note := s.Source
+		if s.Terminal {
+			note += ", terminal"
+		}
+		if s.Description != "" {
+			note += " - " + s.Description
+		}
+		fmt.Printf("  %-16s %s\n", s.Name, style.Dim.Render(note))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Relations"))
+	for _, r := range schema.Relations {
+		note := ""
+		if r.Blocking {
+			note = "blocking"
+		}
+		if r.Description != "" {
+			note = strings.TrimPrefix(note+" - "+r.Description, " - ")
+		}
+		fmt.Printf("  %-20s %s\n", r.Name, style.Dim.Render(note))
+	}
+	return nil
+}
+
+func printSchemaField(f beads.SchemaField) {
+	note := f.Type
+	if len(f.Values) > 0 {
+		note += " (" + strings.Join(f.Values, "|") + ")"
+	}
+	if f.Source != "" && f.Source != beads.SchemaGastown {
+		note += " [" + f.Source + "]"
+	}
+	if f.Description != "" {
+		note += " - " + f.Description
+	}
+	fmt.Printf("  %-20s %s\n", f.Name, style.Dim.Render(note))
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/town"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadSealFields      []string
+	beadSealDescription string
+)
+
+var beadSealCmd = &cobra.Command{
+	Use:   "seal <bead-id>",
+	Short: "Encrypt a bead's sensitive content",
+	Long: `Mark a bead sensitive (e.g., a security incident) and encrypt its content
+at rest with the town's bead key (GT_BEADS_KEY, see 'gt bead keygen').
+
+With no flags the whole bead is sealed: its title becomes "[sensitive]" in
+lists and its description is encrypted. --field seals only the values of
+"key: value" lines in the description, leaving the rest readable.
+
+'gt bead show' masks sealed content; --unlock decrypts it for whoever holds
+the key, and is recorded in the town's event log.
+
+Dolt keeps every earlier version of a bead, so content that was written in
+the clear stays in its history. For new sensitive content, seal the bead
+first and write the content with --description, which never stores it
+unencrypted.
+
+Examples:
+  gt bead seal gt-abc                       # Seal title and description
+  gt bead seal gt-abc --field reporter --field affected-host
+  gt bead seal gt-abc --description notes.md  # Replace with sealed content
+  gt bead show gt-abc --unlock              # Read it back`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadSeal,
+}
+
+var beadUnsealCmd = &cobra.Command{
+	Use:   "unseal <bead-id>",
+	Short: "Decrypt a sealed bead for good",
+	Long: `Decrypt a bead's sealed content in place and drop its sensitive label.
+
+To read a sealed bead without decrypting it in the database, use
+'gt bead show <bead-id> --unlock'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadUnseal,
+}
+
+var beadKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate the town's key for sealed beads",
+	Long: `Generate a key for 'gt bead seal' and write it as GT_BEADS_KEY to the
+town's .runtime/secrets.env (mode 0600). The key is never printed.
+
+Load it with: source <town>/.runtime/secrets.env
+
+Share the key only with those who may read sensitive beads. Beads sealed
+with a key cannot be read without it, so back it up; an existing key is
+not replaced unless --force is given.`,
+	Args: cobra.NoArgs,
+	RunE: runBeadKeygen,
+}
+
+var beadKeygenForce bool
+
+func init() {
+	beadSealCmd.Flags().StringArrayVar(&beadSealFields, "field", nil, "Seal only this description field (repeatable)")
+	beadSealCmd.Flags().StringVar(&beadSealDescription, "description", "", "Replace the description with this file's content, sealed (- for stdin)")
+	beadKeygenCmd.Flags().BoolVar(&beadKeygenForce, "force", false, "Replace an existing key (beads sealed with it become unreadable)")
+	beadCmd.AddCommand(beadSealCmd)
+	beadCmd.AddCommand(beadUnsealCmd)
+	beadCmd.AddCommand(beadKeygenCmd)
+}
+
+func runBeadSeal(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	if beadSealDescription != "" && len(beadSealFields) > 0 {
+		return fmt.Errorf("--description and --field cannot be combined")
+	}
+	key, err := beads.LoadSealKey()
+	if err != nil {
+		return err
+	}
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+
+	if beadSealDescription != "" {
+		content, err := readSealInput(beadSealDescription)
+		if err != nil {
+			return err
+		}
+		sealed, err := beads.SealValue(content, key)
+		if err != nil {
+			return err
+		}
+		if err := bd.Update(id, beads.UpdateOptions{Description: &sealed, AddLabels: []string{beads.SensitiveLabel}}); err != nil {
+			return err
+		}
+		fmt.Printf("%s Sealed new description of %s\n", style.SuccessPrefix, style.Bold.Render(id))
+		return nil
+	}
+
+	sealed, err := bd.Seal(id, beadSealFields, key)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Sealed %s of %s\n", style.SuccessPrefix, strings.Join(sealed, ", "), style.Bold.Render(id))
+	fmt.Printf("  %s\n", style.Dim.Render("Earlier versions stay in Dolt history; read it with: gt bead show "+id+" --unlock"))
+	return nil
+}
+
+// readSealInput reads content to seal from a file, or stdin for "-".
+func readSealInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path) //nolint:gosec // G304: path is user-provided by design
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+func runBeadUnseal(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	key, err := beads.LoadSealKey()
+	if err != nil {
+		return err
+	}
+	bd, err := beadsForID(id)
+	if err != nil {
+		return err
+	}
+	if err := bd.Unseal(id, key); err != nil {
+		return err
+	}
+	publishBeadUnlocked(id, "unseal")
+	fmt.Printf("%s Unsealed %s\n", style.SuccessPrefix, style.Bold.Render(id))
+	return nil
+}
+
+func runBeadKeygen(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if data, err := os.ReadFile(town.SecretsFile(townRoot)); err == nil && !beadKeygenForce && //nolint:gosec // G304: path is constructed internally
+		strings.Contains(string(data), "export "+beads.SensitiveKeyEnv+"=") {
+		return fmt.Errorf("%s already has %s\n\nUse --force to replace it (beads sealed with it become unreadable)",
+			town.SecretsFile(townRoot), beads.SensitiveKeyEnv)
+	}
+	key, err := beads.GenerateSealKey()
+	if err != nil {
+		return err
+	}
+	path, err := town.WriteSecrets(townRoot, map[string]string{beads.SensitiveKeyEnv: key})
+	if err != nil {
+		return fmt.Errorf("writing key: %w", err)
+	}
+	fmt.Printf("%s Wrote %s to %s\n", style.SuccessPrefix, beads.SensitiveKeyEnv, path)
+	fmt.Printf("  Load it with: source %s\n", path)
+	return nil
+}
+
+// splitUnlockFlag removes --unlock from gt bead show args, reporting
+// whether it was present.
+func splitUnlockFlag(args []string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == "--unlock" {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// beadIsSensitive reports whether id is labeled as having sealed content.
+func beadIsSensitive(id string) bool {
+	bd, err := beadsForID(id)
+	if err != nil {
+		return false
+	}
+	issue, err := bd.Show(id)
+	if err != nil {
+		return false
+	}
+	return beads.HasLabel(issue, beads.SensitiveLabel)
+}
+
+// showSealedBead runs bd show for a sensitive bead, masking its sealed
+// content, or with unlock decrypting it.
+func showSealedBead(id string, args []string, unlock bool) error {
+	show := exec.Command("bd", append([]string{"show"}, args...)...) //nolint:gosec // G204: bd is a trusted internal tool
+	show.Stderr = os.Stderr
+	out, err := show.Output()
+	if err != nil {
+		return fmt.Errorf("bd show %s: %w", id, err)
+	}
+	text := string(out)
+	if !unlock {
+		fmt.Print(beads.MaskSealed(text, "[sensitive — gt bead show "+id+" --unlock]"))
+		return nil
+	}
+
+	key, err := beads.LoadSealKey()
+	if err != nil {
+		return err
+	}
+	text, title, err := beads.UnsealText(text, key)
+	if err != nil {
+		return fmt.Errorf("unlocking %s: %w", id, err)
+	}
+	if title != "" {
+		text = strings.Replace(text, beads.SensitiveTitle, title, 1)
+	}
+	publishBeadUnlocked(id, "show")
+	fmt.Print(text)
+	return nil
+}
+
+// publishBeadUnlocked records who read or decrypted a sealed bead.
+func publishBeadUnlocked(id, how string) {
+	_ = events.Publish("", events.SourceGT, events.TypeBeadUnlocked, detectSender(), map[string]interface{}{
+		"bead": id,
+		"how":  how,
+	}, events.VisibilityAudit)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Bead search command flags
+var (
+	beadSearchRig     string
+	beadSearchFile    string
+	beadSearchMention string
+	beadSearchStatus  string
+	beadSearchLimit   int
+	beadSearchJSON    bool
+)
+
+// beadSearchStaleAfter is how old an index can get before search says the
+// indexer doesn't seem to be running.
+const beadSearchStaleAfter = 10 * time.Minute
+
+var beadSearchCmd = &cobra.Command{
+	Use:   "search [words...]",
+	Short: "Search beads by text, referenced file, or mention",
+	Long: `Find beads from the search index that 'gt bead indexd' maintains.
+
+Words match the title, description, labels and comments; every word must
+match, and the last may be a prefix. --file matches beads referencing a
+path (or its trailing components, e.g. engineer.go), and --mention beads
+that @mention a worker. Results are most recently updated first.
+
+Without a running indexer, the first search builds the index; later ones
+warn when it is getting old.
+
+Examples:
+  gt bead search merge conflict
+  gt bead search --file internal/refinery/engineer.go
+  gt bead search --mention gastown/crew/joe --status open
+  gt bead search flaky --rig gastown --json`,
+	RunE: runBeadSearch,
+}
+
+func init() {
+	beadSearchCmd.Flags().StringVar(&beadSearchRig, "rig", "", "Rig to search (default: current directory's beads)")
+	beadSearchCmd.Flags().StringVar(&beadSearchFile, "file", "", "Only beads referencing this file")
+	beadSearchCmd.Flags().StringVar(&beadSearchMention, "mention", "", "Only beads mentioning this worker")
+	beadSearchCmd.Flags().StringVar(&beadSearchStatus, "status", "", "Only beads with this status")
+	beadSearchCmd.Flags().IntVar(&beadSearchLimit, "limit", 20, "Maximum results (0 for all)")
+	beadSearchCmd.Flags().BoolVar(&beadSearchJSON, "json", false, "Output results as JSON")
+	beadCmd.AddCommand(beadSearchCmd)
+}
+
+func runBeadSearch(cmd *cobra.Command, args []string) error {
+	q := beads.SearchQuery{
+		Text:    strings.Join(args, " "),
+		File:    beadSearchFile,
+		Mention: beadSearchMention,
+		Status:  beadSearchStatus,
+	}
+	if strings.TrimSpace(q.Text) == "" && q.File == "" && q.Mention == "" {
+		return fmt.Errorf("give words to search for, --file, or --mention")
+	}
+
+	var bd *beads.Beads
+	if beadSearchRig != "" {
+		_, r, err := getRig(beadSearchRig)
+		if err != nil {
+			return err
+		}
+		bd = beads.New(r.Path)
+	} else {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		bd = beads.New(workDir)
+	}
+
+	ix, updated, err := bd.OpenSearchIndex()
+	if errors.Is(err, os.ErrNotExist) {
+		if !beadSearchJSON {
+			fmt.Fprintln(os.Stderr, style.Dim.Render("Building the search index; run 'gt bead indexd' to keep it current."))
+		}
+		ix, _, err = bd.UpdateSearchIndex(false)
+		updated = time.Now()
+	}
+	if err != nil {
+		return err
+	}
+	if age := time.Since(updated); age > beadSearchStaleAfter && !beadSearchJSON {
+		fmt.Fprintln(os.Stderr, style.Dim.Render(fmt.Sprintf("Index last updated %s ago; is 'gt bead indexd' running?", formatDuration(age))))
+	}
+
+	hits := ix.Search(q)
+	total := len(hits)
+	if beadSearchLimit > 0 && len(hits) > beadSearchLimit {
+		hits = hits[:beadSearchLimit]
+	}
+
+	if beadSearchJSON {
+		if hits == nil {
+			hits = []beads.SearchHit{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hits)
+	}
+
+	if total == 0 {
+		fmt.Println(style.Dim.Render("No matching beads."))
+		return nil
+	}
+	for _, h := range hits {
+		fmt.Printf("  %s %s  %s\n", getStatusIcon(h.Status), style.Bold.Render(h.ID), truncateString(h.Title, 70))
+	}
+	if len(hits) < total {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("... and %d more (use --limit 0 to see all)", total-len(hits))))
+	}
+	return nil
+}
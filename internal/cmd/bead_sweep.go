@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadSweepRig    string
+	beadSweepDryRun bool
+	beadSweepJSON   bool
+	beadSweepLimit  int
+)
+
+var beadSweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Enforce the rigs' staleness policies on aging beads",
+	Long: `Check every rig's open work beads against its staleness (SLA) policies,
+and escalate or reopen the beads that have been left too long.
+
+Policies live under "staleness" in the rig's settings/config.json. Each
+matches beads by status (default open), priority (this or more urgent),
+type, and label, and acts once they are older than age, measured from
+creation or, with "since": "updated", from the last change:
+
+  "staleness": {
+    "policies": [
+      {"name": "p0-open", "priority": 0, "age": "2d", "action": "escalate"},
+      {"name": "abandoned", "status": "in_progress", "age": "1w",
+       "since": "updated", "action": "reopen"}
+    ]
+  }
+
+escalate files an escalation (see 'gt escalate') about the bead, once per
+bead and policy, with the policy's severity or one from the bead's
+priority (P0 critical, P1 high, P2 medium, lower low). reopen returns the
+bead to open and unassigned, dropping any lease, with a comment naming the
+policy.
+
+Every action is appended to the rig's escalation log; 'gt bead sweep log'
+shows it. The daemon sweeps rigs with policies every 15 minutes.
+
+Examples:
+  gt bead sweep                   # Sweep every rig
+  gt bead sweep --rig gastown --dry-run
+  gt bead sweep log               # What the policies have done`,
+	Args: cobra.NoArgs,
+	RunE: runBeadSweep,
+}
+
+var beadSweepLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the escalations and reopens made by staleness policies",
+	Long: `Show the staleness log of every rig (or --rig), newest first: which
+policy acted on which bead, when, and the escalation it filed.
+
+Examples:
+  gt bead sweep log
+  gt bead sweep log --rig gastown --limit 100
+  gt bead sweep log --json`,
+	Args: cobra.NoArgs,
+	RunE: runBeadSweepLog,
+}
+
+func init() {
+	beadSweepCmd.PersistentFlags().StringVar(&beadSweepRig, "rig", "", "Only this rig (default: every rig)")
+	beadSweepCmd.PersistentFlags().BoolVar(&beadSweepJSON, "json", false, "Output as JSON")
+	beadSweepCmd.Flags().BoolVar(&beadSweepDryRun, "dry-run", false, "Show what would be escalated or reopened")
+	beadSweepLogCmd.Flags().IntVar(&beadSweepLimit, "limit", 50, "Entries to show (0 for all)")
+
+	beadSweepCmd.AddCommand(beadSweepLogCmd)
+	beadCmd.AddCommand(beadSweepCmd)
+}
+
+// sweepRigs returns --rig, else every rig in the town, and the town root.
+func sweepRigs() ([]*rig.Rig, string, error) {
+	if beadSweepRig != "" {
+		townRoot, r, err := getRig(beadSweepRig)
+		if err != nil {
+			return nil, "", err
+		}
+		return []*rig.Rig{r}, townRoot, nil
+	}
+	return getAllRigs()
+}
+
+func runBeadSweep(cmd *cobra.Command, args []string) error {
+	rigs, townRoot, err := sweepRigs()
+	if err != nil {
+		return err
+	}
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	actions := []beads.StalenessLogEntry{}
+	swept := 0
+	for _, r := range rigs {
+		policies := config.LoadRigStaleness(r.Path)
+		if policies == nil {
+			if beadSweepRig != "" && !beadSweepJSON {
+				fmt.Println(style.Dim.Render(fmt.Sprintf("Rig %s has no staleness policies; see 'gt bead sweep --help'", r.Name)))
+			}
+			continue
+		}
+		swept++
+		done, err := sweepRig(townRoot, r, policies, escalationConfig)
+		actions = append(actions, done...)
+		if err != nil {
+			style.PrintWarning("sweeping %s: %v", r.Name, err)
+		}
+	}
+
+	if beadSweepJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(actions)
+	}
+	if swept > 0 && len(actions) == 0 {
+		fmt.Println(style.Dim.Render("No beads past their staleness policies"))
+	}
+	return nil
+}
+
+// sweepRig applies a rig's staleness policies, returning the actions taken
+// (or, with --dry-run, that would be).
+func sweepRig(townRoot string, r *rig.Rig, policies *config.StalenessConfig, escalationConfig *config.EscalationConfig) ([]beads.StalenessLogEntry, error) {
+	bd := beads.New(r.Path)
+	var statuses []string
+	for _, p := range policies.Policies {
+		statuses = append(statuses, p.MatchStatus())
+	}
+	issues, err := bd.List(beads.ListOptions{Statuses: statuses})
+	if err != nil {
+		return nil, fmt.Errorf("listing beads: %w", err)
+	}
+	logged, err := beads.ReadStalenessLog(beads.ResolveBeadsDir(r.Path))
+	if err != nil {
+		return nil, err
+	}
+	escalated := beads.StalenessEscalated(logged)
+
+	now := time.Now()
+	var actions []beads.StalenessLogEntry
+	for _, m := range beads.FindStale(policies, issues, now) {
+		if m.Policy.Action == config.StaleActionEscalate && escalated[beads.StalenessKey(m.Issue.ID, m.Policy.Name)] {
+			continue
+		}
+		entry := beads.StalenessLogEntry{
+			Rig:     r.Name,
+			IssueID: m.Issue.ID,
+			Title:   m.Issue.Title,
+			Policy:  m.Policy.Name,
+			Action:  m.Policy.Action,
+			Age:     beads.FormatStaleAge(m.Age),
+		}
+		if beadSweepDryRun {
+			entry.Result = "dry-run"
+			actions = append(actions, entry)
+			if !beadSweepJSON {
+				fmt.Printf("Would %s %s (policy %s: %s for %s)\n", m.Policy.Action, style.Bold.Render(m.Issue.ID),
+					m.Policy.Name, m.Issue.Status, entry.Age)
+			}
+			continue
+		}
+
+		switch m.Policy.Action {
+		case config.StaleActionEscalate:
+			entry.Result, err = escalateStale(townRoot, bd, m, escalationConfig)
+		case config.StaleActionReopen:
+			var reopened bool
+			reopened, err = bd.ReopenStale(m, now)
+			if err == nil && !reopened {
+				continue // changed since it was listed
+			}
+			entry.Result = "open"
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if logErr := bd.RecordStaleness(entry); logErr != nil {
+			style.PrintWarning("recording %s in the staleness log: %v", m.Issue.ID, logErr)
+		}
+		actions = append(actions, entry)
+
+		if beadSweepJSON {
+			continue
+		}
+		switch {
+		case err != nil:
+			style.PrintWarning("%s %s (policy %s): %v", m.Policy.Action, m.Issue.ID, m.Policy.Name, err)
+		case m.Policy.Action == config.StaleActionEscalate:
+			fmt.Printf("%s Escalated %s as %s (policy %s: %s for %s)\n", style.SuccessPrefix, style.Bold.Render(m.Issue.ID),
+				entry.Result, m.Policy.Name, m.Issue.Status, entry.Age)
+		default:
+			fmt.Printf("%s Reopened %s (policy %s: %s for %s)\n", style.SuccessPrefix, style.Bold.Render(m.Issue.ID),
+				m.Policy.Name, m.Issue.Status, entry.Age)
+		}
+	}
+	return actions, nil
+}
+
+// escalateStale files an escalation about a stale bead and notes it on the
+// bead, returning the escalation's ID.
+func escalateStale(townRoot string, bd *beads.Beads, m beads.StaleMatch, escalationConfig *config.EscalationConfig) (string, error) {
+	from := detectSender()
+	if from == "" {
+		from = "daemon"
+	}
+	age := beads.FormatStaleAge(m.Age)
+	issue, _, _, err := fileEscalation(townRoot, escalationConfig, escalationRequest{
+		Description: fmt.Sprintf("%s P%d %s for %s: %s", m.Issue.ID, m.Issue.Priority, m.Issue.Status, age, truncateString(m.Issue.Title, 60)),
+		Severity:    m.EscalationSeverity(),
+		Reason:      fmt.Sprintf("Past staleness policy %q (%s)", m.Policy.Name, m.Policy.Describe()),
+		Source:      "staleness:" + m.Policy.Name,
+		From:        from,
+		RelatedBead: m.Issue.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	_ = bd.AddComment(m.Issue.ID, fmt.Sprintf("Escalated as %s by stale policy %q: %s for %s", issue.ID, m.Policy.Name, m.Issue.Status, age))
+	return issue.ID, nil
+}
+
+func runBeadSweepLog(cmd *cobra.Command, args []string) error {
+	rigs, _, err := sweepRigs()
+	if err != nil {
+		return err
+	}
+	entries := []beads.StalenessLogEntry{}
+	for _, r := range rigs {
+		logged, err := beads.ReadStalenessLog(beads.ResolveBeadsDir(r.Path))
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+			continue
+		}
+		for _, e := range logged {
+			if e.Rig == "" {
+				e.Rig = r.Name
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp > entries[j].Timestamp })
+	if beadSweepLimit > 0 && len(entries) > beadSweepLimit {
+		entries = entries[:beadSweepLimit]
+	}
+
+	if beadSweepJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println(style.Dim.Render("No staleness policy has acted yet"))
+		return nil
+	}
+
+	t := style.NewTable(
+		style.Column{Name: "WHEN", Width: 10},
+		style.Column{Name: "RIG", Width: 12},
+		style.Column{Name: "BEAD", Width: 12},
+		style.Column{Name: "POLICY", Width: 16},
+		style.Column{Name: "ACTION", Width: 8},
+		style.Column{Name: "AGE", Width: 7},
+		style.Column{Name: "RESULT", Width: 30},
+	)
+	for _, e := range entries {
+		when := "?"
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+			when = style.HumanizeAge(time.Since(ts)) + " ago"
+		}
+		result := e.Result
+		if e.Error != "" {
+			result = style.Error.Render("failed: " + truncateString(e.Error, 22))
+		}
+		t.AddRow(when, e.Rig, e.IssueID, e.Policy, e.Action, e.Age, result)
+	}
+	fmt.Print(t.Render())
+	return nil
+}
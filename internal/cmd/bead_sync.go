@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead sync command flags
+var (
+	beadSyncApply      bool
+	beadSyncPrefer     string
+	beadSyncStrategies []string
+	beadSyncNoFetch    bool
+	beadSyncJSON       bool
+)
+
+// Sync-branch states for a clone, relative to origin.
+const (
+	syncStateInSync   = "in-sync"
+	syncStateAhead    = "ahead"
+	syncStateBehind   = "behind"
+	syncStateDiverged = "diverged"
+	syncStateMissing  = "no-branch"
+)
+
+var beadSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Inspect and repair the beads sync branch",
+	RunE:  requireSubcommand,
+	Long: `Inspect and repair the beads sync branch (` + constants.BranchBeadsSync + `).
+
+Each clone of a rig (mayor, refinery, witness, crew, polecats) commits bead
+changes to its own copy of the sync branch and pushes them to origin. When
+two clones commit concurrently the branch diverges, the push is rejected,
+and beads from that clone silently stop propagating.
+
+Subcommands:
+  resolve   Find diverged clones and merge their bead changes`,
+}
+
+var beadSyncResolveCmd = &cobra.Command{
+	Use:   "resolve [rig]",
+	Short: "Find diverged sync branches and merge them bead by bead",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Check every clone of a rig for a diverged beads sync branch, show the
+conflicting bead changes, and with --apply merge and push them.
+
+For each diverged clone the issue export (` + beads.SyncJSONLPath + `) is merged
+three ways against origin. Beads changed on one side take that side's
+version; beads changed on both are merged field by field. A field changed
+differently on both sides is a conflict, decided by strategy:
+
+  newest    The side whose bead has the later updated_at (default)
+  local     This clone's value
+  remote    origin's value
+  union     List fields: keep additions from both sides, drop removals
+  progress  Status: the furthest along (closed > in_progress > open)
+
+labels, dependencies, and comments default to union and status to progress;
+--prefer sets the strategy for every other field, and --strategy overrides
+any single field.
+
+--apply commits the merge onto the clone's sync branch (both sides as
+parents) and pushes it. Only the issue export is merged: a diverged clone
+whose sync branch also changes other files is refused, and those changes
+must be merged by hand. Clones that are only ahead are pushed; clones that
+are behind are left for bd to fast-forward.
+
+Exits non-zero when a clone is diverged or ahead and --apply was not given.
+
+Examples:
+  gt bead sync resolve
+  gt bead sync resolve gastown --apply
+  gt bead sync resolve --prefer remote --strategy priority=local
+  gt bead sync resolve --json`,
+	RunE: runBeadSyncResolve,
+}
+
+func init() {
+	beadSyncResolveCmd.Flags().BoolVar(&beadSyncApply, "apply", false, "Commit merges and push diverged or ahead clones")
+	beadSyncResolveCmd.Flags().StringVar(&beadSyncPrefer, "prefer", string(beads.SyncNewest), "Strategy for fields without one: newest, local, remote")
+	beadSyncResolveCmd.Flags().StringArrayVar(&beadSyncStrategies, "strategy", nil, "Field strategy as field=strategy (repeatable)")
+	beadSyncResolveCmd.Flags().BoolVar(&beadSyncNoFetch, "no-fetch", false, "Use the origin refs already fetched in each clone")
+	beadSyncResolveCmd.Flags().BoolVar(&beadSyncJSON, "json", false, "Output as JSON")
+
+	beadSyncCmd.AddCommand(beadSyncResolveCmd)
+	beadCmd.AddCommand(beadSyncCmd)
+}
+
+// syncCloneReport is the sync-branch state of one clone.
+type syncCloneReport struct {
+	Clone   string                 `json:"clone"`
+	Path    string                 `json:"path"`
+	State   string                 `json:"state"`
+	Ahead   int                    `json:"ahead,omitempty"`
+	Behind  int                    `json:"behind,omitempty"`
+	Merge   *beads.SyncMergeResult `json:"merge,omitempty"`
+	Other   []string               `json:"other_changes,omitempty"` // Local changes outside the issue export
+	Applied bool                   `json:"applied,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+func runBeadSyncResolve(cmd *cobra.Command, args []string) error {
+	opts, err := beadSyncMergeOptions()
+	if err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	rigName := ""
+	if len(args) == 1 {
+		rigName = args[0]
+	} else if rigName, err = inferRigFromCwd(townRoot); err != nil {
+		return fmt.Errorf("not in a rig; specify one: gt bead sync resolve <rig>")
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	var reports []*syncCloneReport
+	for _, clone := range rigSyncClones(r) {
+		report := inspectSyncClone(clone[0], clone[1], opts)
+		if beadSyncApply && report.Error == "" {
+			if err := applySyncClone(report); err != nil {
+				report.Error = err.Error()
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	pending := false
+	for _, rep := range reports {
+		if !rep.Applied && (rep.State == syncStateDiverged || rep.State == syncStateAhead) {
+			pending = true
+		}
+	}
+
+	if beadSyncJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printSyncReports(r.Name, reports)
+		if pending && !beadSyncApply {
+			fmt.Printf("\nRun with --apply to merge and push.\n")
+		}
+	}
+	if pending {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func beadSyncMergeOptions() (beads.SyncMergeOptions, error) {
+	opts := beads.SyncMergeOptions{Fields: make(map[string]beads.SyncStrategy)}
+	prefer, err := beads.ParseSyncStrategy(beadSyncPrefer)
+	if err != nil {
+		return opts, fmt.Errorf("--prefer: %w", err)
+	}
+	opts.Default = prefer
+	for _, s := range beadSyncStrategies {
+		field, name, ok := strings.Cut(s, "=")
+		if !ok || field == "" {
+			return opts, fmt.Errorf("invalid --strategy %q: expected field=strategy", s)
+		}
+		strategy, err := beads.ParseSyncStrategy(name)
+		if err != nil {
+			return opts, fmt.Errorf("--strategy %s: %w", field, err)
+		}
+		opts.Fields[field] = strategy
+	}
+	return opts, nil
+}
+
+// rigSyncClones returns the rig's git clones as (name, path) pairs.
+func rigSyncClones(r *rig.Rig) [][2]string {
+	var clones [][2]string
+	add := func(name, path string) {
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			clones = append(clones, [2]string{name, path})
+		}
+	}
+	for _, role := range []string{"mayor", "refinery", "witness"} {
+		add(role, filepath.Join(r.Path, role, "rig"))
+	}
+	for _, group := range []string{"crew", "polecats"} {
+		entries, err := os.ReadDir(filepath.Join(r.Path, group))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(r.Path, group, e.Name())
+			// Polecats use polecats/<name>/<rig>/; older layouts are polecats/<name>/.
+			if group == "polecats" {
+				if _, err := os.Stat(filepath.Join(path, r.Name, ".git")); err == nil {
+					path = filepath.Join(path, r.Name)
+				}
+			}
+			add(group+"/"+e.Name(), path)
+		}
+	}
+	return clones
+}
+
+// inspectSyncClone compares a clone's sync branch with origin's and, when
+// they have diverged, previews the bead-level merge.
+func inspectSyncClone(name, path string, opts beads.SyncMergeOptions) *syncCloneReport {
+	report := &syncCloneReport{Clone: name, Path: path}
+	g := git.NewGit(path)
+	branch := constants.BranchBeadsSync
+	local, remote := "refs/heads/"+branch, "refs/remotes/origin/"+branch
+
+	if !beadSyncNoFetch {
+		// A missing remote branch shows up below as no-branch.
+		_ = g.FetchBranch("origin", "+"+branch+":"+remote)
+	}
+	hasLocal, _ := g.RefExists(local)
+	hasRemote, _ := g.RefExists(remote)
+	if !hasLocal || !hasRemote {
+		report.State = syncStateMissing
+		return report
+	}
+
+	var err error
+	if report.Ahead, err = g.CommitsAhead(remote, local); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	if report.Behind, err = g.CommitsAhead(local, remote); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	switch {
+	case report.Ahead == 0 && report.Behind == 0:
+		report.State = syncStateInSync
+		return report
+	case report.Behind == 0:
+		report.State = syncStateAhead
+		return report
+	case report.Ahead == 0:
+		report.State = syncStateBehind
+		return report
+	}
+	report.State = syncStateDiverged
+
+	base, err := g.MergeBase(local, remote)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	// Only the issue export is merged; --apply must not drop anything else.
+	changed, err := g.ChangedFiles(remote, local)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	for _, path := range changed {
+		if path != beads.SyncJSONLPath {
+			report.Other = append(report.Other, path)
+		}
+	}
+
+	// A side without the file (e.g., a base before bd's first export) is empty.
+	baseData, _ := g.ShowFile(base, beads.SyncJSONLPath)
+	localData, _ := g.ShowFile(local, beads.SyncJSONLPath)
+	remoteData, _ := g.ShowFile(remote, beads.SyncJSONLPath)
+	if report.Merge, err = beads.MergeSyncJSONL(baseData, localData, remoteData, opts); err != nil {
+		report.Error = fmt.Sprintf("merging %s: %v", beads.SyncJSONLPath, err)
+	}
+	return report
+}
+
+// applySyncClone commits the previewed merge onto the clone's sync branch
+// and pushes it, or pushes a clone that is only ahead.
+func applySyncClone(report *syncCloneReport) error {
+	if report.State != syncStateDiverged && report.State != syncStateAhead {
+		return nil
+	}
+	g := git.NewGit(report.Path)
+	branch := constants.BranchBeadsSync
+	local, remote := "refs/heads/"+branch, "refs/remotes/origin/"+branch
+
+	if report.State == syncStateDiverged {
+		if len(report.Other) > 0 {
+			return fmt.Errorf("local changes to %s would be lost; merge origin/%s by hand in %s",
+				strings.Join(report.Other, ", "), branch, report.Path)
+		}
+
+		// bd keeps the sync branch checked out in a worktree of its own;
+		// refuse to move it out from under uncommitted changes there.
+		var wt *git.Worktree
+		if worktrees, err := g.WorktreeList(); err == nil {
+			for i := range worktrees {
+				if worktrees[i].Branch == branch {
+					wt = &worktrees[i]
+				}
+			}
+		}
+		if wt != nil {
+			if dirty, err := git.NewGit(wt.Path).HasUncommittedChanges(); err != nil || dirty {
+				return fmt.Errorf("sync worktree %s has uncommitted changes; run 'bd sync' there first", wt.Path)
+			}
+		}
+
+		localSHA, err := g.Rev(local)
+		if err != nil {
+			return err
+		}
+		remoteSHA, err := g.Rev(remote)
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("bd sync: merge %s from %s (%d bead conflict(s) resolved)",
+			branch, report.Clone, len(report.Merge.Conflicts))
+		commit, err := g.CommitFile(remoteSHA, beads.SyncJSONLPath, report.Merge.Data, msg, localSHA, remoteSHA)
+		if err != nil {
+			return fmt.Errorf("committing merge: %w", err)
+		}
+		if err := g.UpdateRef(local, commit, localSHA); err != nil {
+			return fmt.Errorf("updating %s: %w", branch, err)
+		}
+		if wt != nil {
+			if err := git.NewGit(wt.Path).ResetHard("HEAD"); err != nil {
+				return fmt.Errorf("refreshing sync worktree %s: %w", wt.Path, err)
+			}
+		}
+	}
+
+	if err := g.Push("origin", branch, false); err != nil {
+		return fmt.Errorf("pushing %s (origin may have moved; re-run): %w", branch, err)
+	}
+	report.Applied = true
+	return nil
+}
+
+func printSyncReports(rigName string, reports []*syncCloneReport) {
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Beads sync branch for"), style.Bold.Render(rigName))
+	if len(reports) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no clones found"))
+		return
+	}
+	width := 0
+	for _, rep := range reports {
+		width = max(width, len(rep.Clone))
+	}
+
+	for _, rep := range reports {
+		var state string
+		switch rep.State {
+		case syncStateInSync:
+			state = style.Success.Render("in sync")
+		case syncStateMissing:
+			state = style.Dim.Render("no sync branch")
+		case syncStateBehind:
+			state = fmt.Sprintf("behind %d %s", rep.Behind, style.Dim.Render("(bd sync will fast-forward)"))
+		case syncStateAhead:
+			state = style.Warning.Render(fmt.Sprintf("ahead %d, not pushed", rep.Ahead))
+		case syncStateDiverged:
+			state = style.Error.Render(fmt.Sprintf("diverged: %d local, %d remote commit(s)", rep.Ahead, rep.Behind))
+		}
+		if rep.Applied {
+			state += " " + style.Success.Render("→ pushed")
+		}
+		fmt.Printf("  %-*s  %s\n", width, rep.Clone, state)
+		if rep.Error != "" {
+			fmt.Printf("  %-*s  %s %s\n", width, "", style.Error.Render("error:"), rep.Error)
+		}
+
+		if len(rep.Other) > 0 {
+			fmt.Printf("  %-*s    %s\n", width, "", style.Warning.Render(
+				"also changes "+strings.Join(rep.Other, ", ")+" (not merged by --apply)"))
+		}
+
+		if m := rep.Merge; m != nil {
+			for _, c := range m.Conflicts {
+				if c.Field == "" {
+					side := "locally"
+					if c.Local == nil {
+						side = "on origin"
+					}
+					fmt.Printf("  %-*s    %s deleted %s, kept modified copy\n", width, "", c.ID, side)
+					continue
+				}
+				fmt.Printf("  %-*s    %s %s: local %s, remote %s → %s %s\n", width, "", c.ID, c.Field,
+					truncateSyncValue(c.Local), truncateSyncValue(c.Remote), truncateSyncValue(c.Resolved),
+					style.Dim.Render("("+string(c.Strategy)+")"))
+			}
+			fmt.Printf("  %-*s    %s\n", width, "", style.Dim.Render(fmt.Sprintf(
+				"%d bead(s), %d changed on both sides, %d deleted, %d conflict(s)",
+				m.Beads, m.Merged, m.Deleted, len(m.Conflicts))))
+		}
+	}
+}
+
+func truncateSyncValue(v json.RawMessage) string {
+	s := string(v)
+	if s == "" {
+		s = "null"
+	}
+	if len(s) > 40 {
+		s = s[:37] + "..."
+	}
+	return s
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func syncTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// syncTestCommit writes the issue export in clone and commits it to beads-sync.
+func syncTestCommit(t *testing.T, clone, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(clone, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(clone, beads.SyncJSONLPath), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	syncTestGit(t, clone, "add", ".")
+	syncTestGit(t, clone, "commit", "-q", "-m", "bd sync")
+}
+
+func TestBeadSyncResolve_DivergedClones(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	syncTestGit(t, origin, "init", "-q", "--bare")
+
+	mayor := filepath.Join(rigPath, "mayor", "rig")
+	crew := filepath.Join(rigPath, "crew", "max")
+	syncTestGit(t, filepath.Dir(origin), "clone", "-q", origin, mayor)
+	syncTestGit(t, mayor, "checkout", "-q", "-b", "beads-sync")
+	syncTestCommit(t, mayor, `{"id":"gt-1","title":"one","status":"open","labels":["a"]}`+"\n")
+	syncTestGit(t, mayor, "push", "-q", "origin", "beads-sync")
+	syncTestGit(t, filepath.Dir(origin), "clone", "-q", "-b", "beads-sync", origin, crew)
+	for _, clone := range []string{mayor, crew} {
+		syncTestGit(t, clone, "config", "user.email", "test@test.com")
+		syncTestGit(t, clone, "config", "user.name", "Test User")
+	}
+
+	// Both clones change gt-1; mayor pushes first, so crew's push would be rejected.
+	syncTestCommit(t, mayor, `{"id":"gt-1","title":"one","status":"closed","labels":["a","mayor"]}`+"\n")
+	syncTestGit(t, mayor, "push", "-q", "origin", "beads-sync")
+	syncTestCommit(t, crew, `{"id":"gt-1","title":"one (crew)","status":"in_progress","labels":["a","crew"]}`+"\n"+
+		`{"id":"gt-2","title":"two","status":"open"}`+"\n")
+
+	r := &rig.Rig{Name: "gastown", Path: rigPath}
+	clones := rigSyncClones(r)
+	if len(clones) != 2 || clones[0][0] != "mayor" || clones[1][0] != "crew/max" {
+		t.Fatalf("clones = %v", clones)
+	}
+
+	report := inspectSyncClone("crew/max", crew, beads.SyncMergeOptions{})
+	if report.Error != "" || report.State != syncStateDiverged || report.Ahead != 1 || report.Behind != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Merge == nil || len(report.Merge.Conflicts) != 2 {
+		t.Fatalf("merge = %+v", report.Merge)
+	}
+
+	if err := applySyncClone(report); err != nil {
+		t.Fatal(err)
+	}
+	got := syncTestGit(t, origin, "show", "beads-sync:"+beads.SyncJSONLPath)
+	for _, want := range []string{`"title":"one (crew)"`, `"status":"closed"`, `"labels":["a","crew","mayor"]`, `"id":"gt-2"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged export missing %s:\n%s", want, got)
+		}
+	}
+	if parents := syncTestGit(t, origin, "log", "-1", "--format=%P", "beads-sync"); len(strings.Fields(parents)) != 2 {
+		t.Errorf("merge commit parents = %q, want two", parents)
+	}
+
+	if report := inspectSyncClone("mayor", mayor, beads.SyncMergeOptions{}); report.State != syncStateBehind {
+		t.Errorf("mayor after resolve = %+v, want behind", report)
+	}
+}
+
+func TestBeadSyncResolve_RefusesOtherLocalChanges(t *testing.T) {
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	syncTestGit(t, origin, "init", "-q", "--bare")
+
+	mayor := filepath.Join(t.TempDir(), "mayor")
+	crew := filepath.Join(t.TempDir(), "crew")
+	syncTestGit(t, filepath.Dir(origin), "clone", "-q", origin, mayor)
+	syncTestGit(t, mayor, "checkout", "-q", "-b", "beads-sync")
+	syncTestCommit(t, mayor, `{"id":"gt-1","title":"one","status":"open"}`+"\n")
+	syncTestGit(t, mayor, "push", "-q", "origin", "beads-sync")
+	syncTestGit(t, filepath.Dir(origin), "clone", "-q", "-b", "beads-sync", origin, crew)
+
+	syncTestCommit(t, mayor, `{"id":"gt-1","title":"one","status":"closed"}`+"\n")
+	syncTestGit(t, mayor, "push", "-q", "origin", "beads-sync")
+	if err := os.WriteFile(filepath.Join(crew, ".beads", "config.yaml"), []byte("sync-branch: beads-sync\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	syncTestCommit(t, crew, `{"id":"gt-2","title":"two","status":"open"}`+"\n")
+	before := syncTestGit(t, origin, "rev-parse", "beads-sync")
+
+	report := inspectSyncClone("crew", crew, beads.SyncMergeOptions{})
+	if report.State != syncStateDiverged || len(report.Other) != 1 || report.Other[0] != ".beads/config.yaml" {
+		t.Fatalf("report = %+v, want diverged with .beads/config.yaml", report)
+	}
+	if err := applySyncClone(report); err == nil || !strings.Contains(err.Error(), ".beads/config.yaml") {
+		t.Fatalf("applySyncClone err = %v, want refusal naming the file", err)
+	}
+	if after := syncTestGit(t, origin, "rev-parse", "beads-sync"); after != before || report.Applied {
+		t.Error("origin moved despite the refusal")
+	}
+}
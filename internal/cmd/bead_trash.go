@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadDeleteReason    string
+	beadRestoreStatus   string
+	beadTrashRig        string
+	beadTrashJSON       bool
+	beadTrashOlderThan  string
+	beadTrashPurgeDry   bool
+	beadTrashPurgeJSON  bool
+	beadTrashPurgeForce bool
+)
+
+var beadDeleteCmd = &cobra.Command{
+	Use:   "delete <bead-id>...",
+	Short: "Move beads to the trash",
+	Long: `Move beads to the trash instead of deleting them.
+
+A trashed bead gets the "deleted" status and keeps its content, comments
+and relations. Restore it with 'gt bead restore'. Beads stay in the trash
+for 30 days; then the daemon purges them for good (see 'gt bead trash
+purge').
+
+Examples:
+  gt bead delete gt-abc
+  gt bead delete gt-abc gt-def --reason "duplicates of gt-xyz"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadDelete,
+}
+
+var beadRestoreCmd = &cobra.Command{
+	Use:   "restore <bead-id>...",
+	Short: "Restore beads from the trash",
+	Long: `Take beads out of the trash, back to the status they had when they
+were deleted (open if that is not known), or to --status.
+
+Examples:
+  gt bead restore gt-abc
+  gt bead restore gt-abc --status open`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadRestore,
+}
+
+var beadTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List or purge deleted beads",
+	Long: `Beads deleted with 'gt bead delete' are kept in the trash for 30 days.
+
+Examples:
+  gt bead trash list
+  gt bead trash list --rig gastown --json
+  gt bead trash purge --dry-run
+  gt bead trash purge --older-than 7d`,
+	RunE: requireSubcommand,
+}
+
+var beadTrashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the beads in the trash",
+	Args:  cobra.NoArgs,
+	RunE:  runBeadTrashList,
+}
+
+var beadTrashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete beads that have been in the trash too long",
+	Long: `Permanently delete the beads that have been in the trash for longer
+than --older-than (default 30d). The daemon runs this for every rig.
+
+--force purges every trashed bead regardless of age.`,
+	Args: cobra.NoArgs,
+	RunE: runBeadTrashPurge,
+}
+
+func init() {
+	beadDeleteCmd.Flags().StringVar(&beadDeleteReason, "reason", "", "Why the beads are deleted (kept in their history)")
+	beadRestoreCmd.Flags().StringVar(&beadRestoreStatus, "status", "", "Status to restore to (default: the status before deletion)")
+
+	beadTrashCmd.PersistentFlags().StringVar(&beadTrashRig, "rig", "", "Rig whose trash to use (default: current directory)")
+	beadTrashListCmd.Flags().BoolVar(&beadTrashJSON, "json", false, "Output as JSON")
+	beadTrashPurgeCmd.Flags().StringVar(&beadTrashOlderThan, "older-than", "30d", "Purge beads trashed longer ago than this (e.g. 7d, 12h)")
+	beadTrashPurgeCmd.Flags().BoolVar(&beadTrashPurgeDry, "dry-run", false, "Show what would be purged")
+	beadTrashPurgeCmd.Flags().BoolVar(&beadTrashPurgeJSON, "json", false, "Output as JSON")
+	beadTrashPurgeCmd.Flags().BoolVar(&beadTrashPurgeForce, "force", false, "Purge every trashed bead, whatever its age")
+
+	beadTrashCmd.AddCommand(beadTrashListCmd)
+	beadTrashCmd.AddCommand(beadTrashPurgeCmd)
+	beadCmd.AddCommand(beadDeleteCmd)
+	beadCmd.AddCommand(beadRestoreCmd)
+	beadCmd.AddCommand(beadTrashCmd)
+}
+
+func runBeadDelete(cmd *cobra.Command, args []string) error {
+	var failed int
+	for _, id := range args {
+		bd, err := beadsForID(id)
+		if err != nil {
+			return err
+		}
+		if err := bd.Trash(id, beadDeleteReason); err != nil {
+			style.PrintWarning("%s: %v", id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Moved %s to the trash (restore with: gt bead restore %s)\n", style.SuccessPrefix, style.Bold.Render(id), id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bead(s) not deleted", failed, len(args))
+	}
+	return nil
+}
+
+func runBeadRestore(cmd *cobra.Command, args []string) error {
+	var failed int
+	for _, id := range args {
+		bd, err := beadsForID(id)
+		if err != nil {
+			return err
+		}
+		status, err := bd.Restore(id, beadRestoreStatus)
+		if err != nil {
+			style.PrintWarning("%s: %v", id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Restored %s (status %s)\n", style.SuccessPrefix, style.Bold.Render(id), status)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bead(s) not restored", failed, len(args))
+	}
+	return nil
+}
+
+// trashBeads returns the beads client for --rig, else the current directory.
+func trashBeads() (*beads.Beads, error) {
+	if beadTrashRig != "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if _, _, err := getRig(beadTrashRig); err != nil {
+			return nil, err
+		}
+		return beads.New(filepath.Join(townRoot, beadTrashRig)), nil
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	return beads.New(workDir), nil
+}
+
+func runBeadTrashList(cmd *cobra.Command, args []string) error {
+	bd, err := trashBeads()
+	if err != nil {
+		return err
+	}
+	trashed, err := bd.ListTrash()
+	if err != nil {
+		return fmt.Errorf("listing trash: %w", err)
+	}
+
+	if beadTrashJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trashed)
+	}
+
+	if len(trashed) == 0 {
+		fmt.Println(style.Dim.Render("Trash is empty"))
+		return nil
+	}
+	now := time.Now()
+	for _, t := range trashed {
+		when := style.Dim.Render("deleted at unknown time")
+		if !t.DeletedAt.IsZero() {
+			left := t.PurgeAt(beads.DefaultTrashRetention).Sub(now)
+			when = fmt.Sprintf("purged in %s", formatTrashAge(left))
+			if left <= 0 {
+				when = style.Warning.Render("due for purge")
+			}
+		}
+		from := t.From
+		if from == "" {
+			from = "?"
+		}
+		fmt.Printf("  %s  %-12s %-20s %s\n", style.Bold.Render(t.ID), "was "+from, when, style.Dim.Render(truncateString(t.Title, 50)))
+	}
+	return nil
+}
+
+// formatTrashAge renders a retention countdown in days, or hours under a day.
+func formatTrashAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func runBeadTrashPurge(cmd *cobra.Command, args []string) error {
+	retention, err := parseDuration(beadTrashOlderThan)
+	if err != nil || retention < 0 {
+		return fmt.Errorf("invalid --older-than %q", beadTrashOlderThan)
+	}
+	if beadTrashPurgeForce {
+		retention = 0
+	}
+	bd, err := trashBeads()
+	if err != nil {
+		return err
+	}
+	purged, purgeErr := bd.PurgeTrash(retention, time.Now(), beadTrashPurgeDry)
+
+	if beadTrashPurgeJSON {
+		if purged == nil {
+			purged = []string{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Purged []string `json:"purged"`
+			DryRun bool     `json:"dry_run,omitempty"`
+		}{purged, beadTrashPurgeDry}); err != nil {
+			return err
+		}
+		return purgeErr
+	}
+
+	verb := "Purged"
+	if beadTrashPurgeDry {
+		verb = "Would purge"
+	}
+	for _, id := range purged {
+		fmt.Printf("%s %s %s\n", style.SuccessPrefix, verb, style.Bold.Render(id))
+	}
+	if len(purged) == 0 && purgeErr == nil {
+		fmt.Println(style.Dim.Render("Nothing to purge"))
+	}
+	return purgeErr
+}
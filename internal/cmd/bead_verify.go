@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead verify command flags
+var (
+	beadVerifyTest     string
+	beadVerifyArtifact string
+	beadVerifyNote     string
+	beadVerifyJSON     bool
+)
+
+var beadVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verification phase: evidence required before beads close",
+	Long: `Manage the verification phase of beads.
+
+A rig can require evidence before beads of some types close, so "done"
+means independently verified rather than self-reported. Configure it in the
+rig's settings/config.json, per bead type ("*" for any other type):
+
+  "verify": {
+    "types": {
+      "feature": ["test", "signoff"],
+      "bug": ["test"]
+    }
+  }
+
+Evidence kinds:
+  test       Link to a passing test run
+  artifact   Link to a build artifact, or the name of an attachment
+             ('gt bead attach')
+  signoff    Sign-off by someone other than the bead's assignee
+
+'gt close' refuses to close a bead missing its evidence. When the refinery
+merges work for such a bead, it moves the bead to the "verify" status
+instead of closing it; close it once the evidence is in.
+
+Commands:
+  gt bead verify request <id>                 Move a bead to verify
+  gt bead verify add <id> --test <url>        Attach evidence
+  gt bead verify signoff <id> [--note <text>] Sign off as a reviewer
+  gt bead verify status <id>                  Show required and attached evidence`,
+	RunE: requireSubcommand,
+}
+
+var beadVerifyRequestCmd = &cobra.Command{
+	Use:   "request <bead-id>",
+	Short: "Move a bead to the verify status",
+	Long: `Mark a bead's work as done and awaiting verification.
+
+Examples:
+  gt bead verify request gt-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadVerifyRequest,
+}
+
+var beadVerifyAddCmd = &cobra.Command{
+	Use:   "add <bead-id>",
+	Short: "Attach test or artifact evidence to a bead",
+	Long: `Record evidence that a bead's work was verified.
+
+Examples:
+  gt bead verify add gt-abc --test https://ci.example.com/runs/4812
+  gt bead attach gt-abc coverage.html && gt bead verify add gt-abc --artifact coverage.html
+  gt bead verify add gt-abc --test https://ci.example.com/runs/4812 --artifact https://example.com/build/77`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadVerifyAdd,
+}
+
+var beadVerifySignoffCmd = &cobra.Command{
+	Use:   "signoff <bead-id>",
+	Short: "Sign off a bead's work as a reviewer",
+	Long: `Record your sign-off on a bead's work. The bead's assignee can't sign off
+their own work.
+
+Examples:
+  gt bead verify signoff gt-abc
+  gt bead verify signoff gt-abc --note "checked the migration on staging"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadVerifySignoff,
+}
+
+var beadVerifyStatusCmd = &cobra.Command{
+	Use:   "status <bead-id>",
+	Short: "Show a bead's required and attached evidence",
+	Long: `Show the evidence a bead needs to close, the evidence attached, and what
+is missing.
+
+Examples:
+  gt bead verify status gt-abc
+  gt bead verify status gt-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadVerifyStatus,
+}
+
+func init() {
+	beadVerifyAddCmd.Flags().StringVar(&beadVerifyTest, "test", "", "Link to a passing test run")
+	beadVerifyAddCmd.Flags().StringVar(&beadVerifyArtifact, "artifact", "", "Link to an artifact, or an attachment name")
+	beadVerifySignoffCmd.Flags().StringVar(&beadVerifyNote, "note", "", "What you checked")
+	beadVerifyStatusCmd.Flags().BoolVar(&beadVerifyJSON, "json", false, "Output as JSON")
+
+	beadVerifyCmd.AddCommand(beadVerifyRequestCmd)
+	beadVerifyCmd.AddCommand(beadVerifyAddCmd)
+	beadVerifyCmd.AddCommand(beadVerifySignoffCmd)
+	beadVerifyCmd.AddCommand(beadVerifyStatusCmd)
+	beadCmd.AddCommand(beadVerifyCmd)
+}
+
+func runBeadVerifyRequest(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	bd, issue, _, err := loadBeadForVerify(id)
+	if err != nil {
+		return err
+	}
+	if issue.Status == "closed" {
+		return fmt.Errorf("%s is already closed", id)
+	}
+	if err := bd.RequestVerify(id, ""); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s awaits verification\n", style.SuccessPrefix, id)
+	return nil
+}
+
+func runBeadVerifyAdd(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	if beadVerifyTest == "" && beadVerifyArtifact == "" {
+		return fmt.Errorf("nothing to add: use --test and/or --artifact")
+	}
+	bd, _, _, err := loadBeadForVerify(id)
+	if err != nil {
+		return err
+	}
+	for _, ev := range [][2]string{{config.EvidenceTest, beadVerifyTest}, {config.EvidenceArtifact, beadVerifyArtifact}} {
+		if ev[1] == "" {
+			continue
+		}
+		e, err := bd.AddEvidence(id, ev[0], ev[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Added %s evidence to %s: %s\n", style.SuccessPrefix, e.Kind, id, e.Ref)
+	}
+	return printVerifyRemaining(id)
+}
+
+func runBeadVerifySignoff(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	bd, _, _, err := loadBeadForVerify(id)
+	if err != nil {
+		return err
+	}
+	e, err := bd.AddEvidence(id, config.EvidenceSignoff, beadVerifyNote)
+	if err != nil {
+		return err
+	}
+	by := e.By
+	if by == "" {
+		by = "you"
+	}
+	fmt.Printf("%s %s signed off by %s\n", style.SuccessPrefix, id, by)
+	return printVerifyRemaining(id)
+}
+
+// beadVerifyStatus is the JSON output of gt bead verify status.
+type beadVerifyStatus struct {
+	ID       string           `json:"id"`
+	Status   string           `json:"status"`
+	Required []string         `json:"required"`
+	Evidence []beads.Evidence `json:"evidence"`
+	Missing  []string         `json:"missing"`
+}
+
+func runBeadVerifyStatus(cmd *cobra.Command, args []string) error {
+	st, err := verifyStatusFor(args[0])
+	if err != nil {
+		return err
+	}
+	if beadVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(st.ID), style.Dim.Render("("+st.Status+")"))
+	if len(st.Required) == 0 {
+		fmt.Println("  No evidence required for this bead's type")
+	} else {
+		fmt.Printf("  Required: %s\n", strings.Join(st.Required, ", "))
+	}
+	for _, e := range st.Evidence {
+		line := fmt.Sprintf("  %s %s", style.SuccessPrefix, e.Kind)
+		if e.Ref != "" {
+			line += ": " + e.Ref
+		}
+		if e.By != "" {
+			line += style.Dim.Render("  by " + e.By)
+		}
+		fmt.Printf("%s  %s\n", line, style.Dim.Render(formatHistoryTime(e.At)))
+	}
+	if len(st.Missing) > 0 {
+		fmt.Printf("  %s Missing: %s\n", style.WarningPrefix, strings.Join(st.Missing, ", "))
+	} else if len(st.Required) > 0 {
+		fmt.Printf("  Verified: ready to close\n")
+	}
+	return nil
+}
+
+func verifyStatusFor(id string) (*beadVerifyStatus, error) {
+	_, issue, cfg, err := loadBeadForVerify(id)
+	if err != nil {
+		return nil, err
+	}
+	evidence, err := beads.ListEvidence(beadHistoryDir(id), id)
+	if err != nil {
+		return nil, err
+	}
+	required := cfg.Required(issue.Type)
+	return &beadVerifyStatus{
+		ID:       id,
+		Status:   issue.Status,
+		Required: required,
+		Evidence: evidence,
+		Missing:  beads.MissingEvidence(required, evidence, issue.Assignee),
+	}, nil
+}
+
+// printVerifyRemaining says what evidence id still needs, if any.
+func printVerifyRemaining(id string) error {
+	st, err := verifyStatusFor(id)
+	if err != nil {
+		return err
+	}
+	if len(st.Missing) > 0 {
+		fmt.Printf("  Still missing: %s\n", strings.Join(st.Missing, ", "))
+	} else if len(st.Required) > 0 {
+		fmt.Printf("  Verified: close with %s\n", style.Dim.Render("gt close "+id))
+	}
+	return nil
+}
+
+// loadBeadForVerify finds the bead's rig from its prefix and returns a
+// beads client for the rig, the bead, and the rig's verify settings.
+func loadBeadForVerify(id string) (*beads.Beads, *beads.Issue, *config.VerifyConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id))
+	if rigPath == "" {
+		return nil, nil, nil, fmt.Errorf("no rig found for bead %s", id)
+	}
+	bd := beads.New(rigPath)
+	issue, err := bd.Show(id)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("loading %s: %w", id, err)
+	}
+	return bd, issue, config.LoadRigVerify(rigPath), nil
+}
+
+// checkCloseVerified refuses to close beads that lack the evidence their
+// rig requires. Beads gt can't find are left to bd to report.
+func checkCloseVerified(ids []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	var unverified []string
+	for _, id := range ids {
+		rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id))
+		if rigPath == "" {
+			continue
+		}
+		cfg := config.LoadRigVerify(rigPath)
+		if len(cfg.Types) == 0 {
+			continue
+		}
+		bd := beads.New(rigPath)
+		issue, err := bd.Show(id)
+		if err != nil {
+			continue
+		}
+		if err := bd.CheckVerified(issue, cfg); err != nil {
+			if !errors.Is(err, beads.ErrUnverified) {
+				return err
+			}
+			unverified = append(unverified, err.Error())
+		}
+	}
+	if len(unverified) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s\n\nAdd evidence with 'gt bead verify add' or 'gt bead verify signoff' (see 'gt bead verify status <id>')",
+		strings.Join(unverified, "\n"))
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadWatchAs         string
+	beadWatchlistJSON   bool
+	beadWatchlistFeed   bool
+	beadWatchlistDigest bool
+	beadWatchlistDryRun bool
+)
+
+var beadWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Add beads to (or remove them from) your watchlist",
+	Long: `Follow beads you are not assigned to.
+
+Each user or agent identity has its own watchlist. Status and priority
+changes, comments, MR links and attachments on watched beads show up in
+'gt bead watchlist --feed' and in the watchlist digest. Your own changes
+are left out.
+
+Examples:
+  gt bead watch add gt-abc gt-def
+  gt bead watch remove gt-abc
+  gt bead watch add gt-abc --as gastown/crew/joe`,
+	RunE: requireSubcommand,
+}
+
+var beadWatchAddCmd = &cobra.Command{
+	Use:   "add <bead-id>...",
+	Short: "Watch beads",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runBeadWatchAdd,
+}
+
+var beadWatchRemoveCmd = &cobra.Command{
+	Use:     "remove <bead-id>...",
+	Aliases: []string{"rm"},
+	Short:   "Stop watching beads",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runBeadWatchRemove,
+}
+
+var beadWatchlistCmd = &cobra.Command{
+	Use:   "watchlist",
+	Short: "Show your watched beads and what changed on them",
+	Long: `Show the beads on your watchlist, with how many changes each has had
+since you last read the feed.
+
+  --feed     Show the changes since you last read the feed, then mark
+             them read
+  --digest   Mail yourself a digest of the changes since the last digest
+             (nothing is sent if there are none); schedule it for a daily
+             summary. With --dry-run the digest is printed instead.
+
+The feed and the digest keep separate places, so reading the feed does not
+empty the next digest.
+
+Examples:
+  gt bead watchlist
+  gt bead watchlist --feed
+  gt bead watchlist --digest --dry-run
+  gt bead watchlist --json`,
+	Args: cobra.NoArgs,
+	RunE: runBeadWatchlist,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{beadWatchCmd, beadWatchlistCmd} {
+		c.PersistentFlags().StringVar(&beadWatchAs, "as", "", "Watchlist identity (default: the current agent)")
+	}
+	beadWatchlistCmd.Flags().BoolVar(&beadWatchlistJSON, "json", false, "Output as JSON")
+	beadWatchlistCmd.Flags().BoolVar(&beadWatchlistFeed, "feed", false, "Show changes since the feed was last read, and mark them read")
+	beadWatchlistCmd.Flags().BoolVar(&beadWatchlistDigest, "digest", false, "Mail a digest of changes since the last digest")
+	beadWatchlistCmd.Flags().BoolVar(&beadWatchlistDryRun, "dry-run", false, "With --digest, print the digest instead of sending it")
+
+	beadWatchCmd.AddCommand(beadWatchAddCmd)
+	beadWatchCmd.AddCommand(beadWatchRemoveCmd)
+	beadCmd.AddCommand(beadWatchCmd)
+	beadCmd.AddCommand(beadWatchlistCmd)
+}
+
+// watchIdentity returns --as, else the current agent's address.
+func watchIdentity() string {
+	if beadWatchAs != "" {
+		return beadWatchAs
+	}
+	return detectSender()
+}
+
+func loadWatchlist() (string, *beads.Watchlist, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, err
+	}
+	w, err := beads.LoadWatchlist(townRoot, watchIdentity())
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, w, nil
+}
+
+func runBeadWatchAdd(cmd *cobra.Command, args []string) error {
+	townRoot, w, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, id := range args {
+		bd, err := beadsForID(id)
+		if err != nil {
+			return err
+		}
+		if _, err := bd.Show(id); err != nil {
+			return fmt.Errorf("bead %s: %w", id, err)
+		}
+		if w.Add(id, now) {
+			fmt.Printf("%s Watching %s\n", style.SuccessPrefix, id)
+		} else {
+			fmt.Printf("%s Already watching %s\n", style.Dim.Render("○"), id)
+		}
+	}
+	return w.Save(townRoot)
+}
+
+func runBeadWatchRemove(cmd *cobra.Command, args []string) error {
+	townRoot, w, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		if w.Remove(id) {
+			fmt.Printf("%s Stopped watching %s\n", style.SuccessPrefix, id)
+		} else {
+			fmt.Printf("%s Not watching %s\n", style.Dim.Render("○"), id)
+		}
+	}
+	return w.Save(townRoot)
+}
+
+// watchedBeadView is a watched bead as listed by 'gt bead watchlist'.
+type watchedBeadView struct {
+	ID      string               `json:"id"`
+	Title   string               `json:"title,omitempty"`
+	Status  string               `json:"status,omitempty"`
+	Added   string               `json:"added"`
+	Changes []beads.HistoryEntry `json:"changes"` // since the feed was last read
+	Error   string               `json:"error,omitempty"`
+}
+
+// watchedBeadViews looks up each watched bead and its changes since since.
+func watchedBeadViews(w *beads.Watchlist, since string) []watchedBeadView {
+	views := make([]watchedBeadView, 0, len(w.Beads))
+	for _, b := range w.Beads {
+		v := watchedBeadView{ID: b.ID, Added: b.Added, Changes: []beads.HistoryEntry{}}
+		if bd, err := beadsForID(b.ID); err == nil {
+			if issue, err := bd.Show(b.ID); err == nil {
+				v.Title, v.Status = issue.Title, issue.Status
+			} else {
+				v.Error = err.Error()
+			}
+		}
+		entries, err := beads.ReadHistory(beadHistoryDir(b.ID), b.ID)
+		if err != nil && v.Error == "" {
+			v.Error = err.Error()
+		}
+		if changes := w.WatchChanges(b, entries, since); changes != nil {
+			v.Changes = changes
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// latestChange returns the newest change timestamp in views, or "".
+func latestChange(views []watchedBeadView) string {
+	latest := ""
+	for _, v := range views {
+		for _, c := range v.Changes {
+			if c.Timestamp > latest {
+				latest = c.Timestamp
+			}
+		}
+	}
+	return latest
+}
+
+func runBeadWatchlist(cmd *cobra.Command, args []string) error {
+	if beadWatchlistDryRun && !beadWatchlistDigest {
+		return fmt.Errorf("--dry-run requires --digest")
+	}
+	if beadWatchlistFeed && beadWatchlistDigest {
+		return fmt.Errorf("--feed and --digest cannot be combined")
+	}
+	townRoot, w, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+
+	if beadWatchlistDigest {
+		return runBeadWatchlistDigest(townRoot, w)
+	}
+
+	views := watchedBeadViews(w, w.FeedSeen)
+	if beadWatchlistJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(views); err != nil {
+			return err
+		}
+	} else if beadWatchlistFeed {
+		printWatchFeed(w.Identity, views)
+	} else {
+		printWatchlist(w.Identity, views)
+	}
+
+	if beadWatchlistFeed {
+		if latest := latestChange(views); latest != "" {
+			w.FeedSeen = latest
+			return w.Save(townRoot)
+		}
+	}
+	return nil
+}
+
+func printWatchlist(identity string, views []watchedBeadView) {
+	fmt.Printf("%s %s\n", style.Bold.Render("👁 Watchlist of"), identity)
+	if len(views) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Not watching any beads. Add one with: gt bead watch add <bead-id>"))
+		return
+	}
+	for _, v := range views {
+		status := v.Status
+		if v.Error != "" {
+			status = "?"
+		}
+		changes := style.Dim.Render("-")
+		if n := len(v.Changes); n > 0 {
+			changes = style.Bold.Render(fmt.Sprintf("%d new", n))
+		}
+		fmt.Printf("  %-14s %-12s %-8s %s\n", v.ID, status, changes, truncateString(v.Title, 50))
+	}
+}
+
+func printWatchFeed(identity string, views []watchedBeadView) {
+	fmt.Printf("%s %s\n", style.Bold.Render("👁 Watched-bead changes for"), identity)
+	shown := 0
+	for _, v := range views {
+		if len(v.Changes) == 0 {
+			continue
+		}
+		fmt.Printf("\n  %s %s\n", style.Bold.Render(v.ID), truncateString(v.Title, 60))
+		for _, c := range v.Changes {
+			fmt.Printf("    %s  %s\n", style.Dim.Render(formatHistoryTime(c.Timestamp)), describeHistoryEntry(c))
+		}
+		shown++
+	}
+	if shown == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No changes since you last looked"))
+	}
+}
+
+// formatWatchDigest renders the changes in views as a plain-text mail body,
+// or "" when nothing changed.
+func formatWatchDigest(views []watchedBeadView) string {
+	var b strings.Builder
+	for _, v := range views {
+		if len(v.Changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s  %s [%s]\n", v.ID, v.Title, v.Status)
+		for _, c := range v.Changes {
+			line := historyEntryText(c)
+			if c.Actor != "" {
+				line += "  by " + c.Actor
+			}
+			fmt.Fprintf(&b, "  %s  %s\n", formatHistoryTime(c.Timestamp), line)
+		}
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	b.WriteString("Manage your watchlist with: gt bead watch add|remove <bead-id>\n")
+	return b.String()
+}
+
+func runBeadWatchlistDigest(townRoot string, w *beads.Watchlist) error {
+	views := watchedBeadViews(w, w.DigestSent)
+	body := formatWatchDigest(views)
+	if body == "" {
+		fmt.Printf("%s No watched-bead changes since the last digest\n", style.Dim.Render("○"))
+		return nil
+	}
+	changed := 0
+	for _, v := range views {
+		if len(v.Changes) > 0 {
+			changed++
+		}
+	}
+	subject := fmt.Sprintf("Watchlist digest: %d bead(s) changed", changed)
+
+	if beadWatchlistDryRun {
+		fmt.Printf("%s\n\n%s", style.Bold.Render(subject), body)
+		return nil
+	}
+
+	router := mail.NewRouter(townRoot)
+	defer router.WaitPendingNotifications()
+	msg := mail.NewMessage("mayor/", w.Identity, subject, body)
+	msg.Priority = mail.PriorityLow
+	if err := router.Send(msg); err != nil {
+		return fmt.Errorf("sending watchlist digest: %w", err)
+	}
+	w.DigestSent = latestChange(views)
+	if err := w.Save(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Sent watchlist digest (%d bead(s) changed) to %s\n", style.SuccessPrefix, changed, w.Identity)
+	return nil
+}
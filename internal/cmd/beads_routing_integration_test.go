@@ -334,8 +334,7 @@ func TestBeadsListFromPolecatDirectory(t *testing.T) {
 	issue := createTestIssue(t, rigPath, "Polecat list redirect test")
 
 	issues, err := beads.New(polecatDir).List(beads.ListOptions{
-		Status:   "open",
-		Priority: -1,
+		Status: "open",
 	})
 	if err != nil {
 		t.Fatalf("bd list from polecat dir failed: %v", err)
@@ -362,8 +361,7 @@ func TestBeadsListFromCrewDirectory(t *testing.T) {
 	issue := createTestIssue(t, rigPath, "Crew list redirect test")
 
 	issues, err := beads.New(crewDir).List(beads.ListOptions{
-		Status:   "open",
-		Priority: -1,
+		Status: "open",
 	})
 	if err != nil {
 		t.Fatalf("bd list from crew dir failed: %v", err)
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Changelog command flags
+var (
+	changelogRig    string
+	changelogSince  string
+	changelogUntil  string
+	changelogOutput string
+	changelogJSON   bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog",
+	GroupID: GroupWork,
+	Short:   "Generate a changelog from merged MRs and their beads",
+	Long: `Assemble a changelog from the merge requests merged into a rig.
+
+Each merged MR is joined with its source bead and grouped by the bead's
+labels (or issue type):
+
+  Features  feature, enhancement
+  Fixes     bug, fix, regression
+  Chores    chore, docs, refactor, ci, deps
+  Other     everything else
+
+--since accepts a date (2006-01-02 or RFC 3339) or a git tag; a tag is
+resolved to its commit date in the rig's repository.
+
+Output is rendered with <rig>/settings/changelog.md.tmpl (Go text/template)
+if present, otherwise a built-in Markdown template. The template receives
+.Rig, .SinceRef, .Since, .Until, .Total and .Sections; each section has
+.Title and .Entries with .MRID, .SourceIssue, .Title, .Labels, .Worker,
+.MergeCommit, .MergedAt and .Category. Helpers: date, short.
+
+Examples:
+  gt changelog --rig gastown --since v0.4.0
+  gt changelog --rig gastown --since 2026-01-01 --until 2026-01-08
+  gt changelog --rig gastown --since v0.4.0 -o RELEASE_NOTES.md
+  gt changelog --rig gastown --since v0.4.0 --json`,
+	Args: cobra.NoArgs,
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogRig, "rig", "", "Rig name (default: infer from current directory)")
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Start at a tag or date (required)")
+	changelogCmd.Flags().StringVar(&changelogUntil, "until", "", "End at a tag or date (default: now)")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "Write to a file instead of stdout")
+	changelogCmd.Flags().BoolVar(&changelogJSON, "json", false, "Output grouped entries as JSON")
+	_ = changelogCmd.MarkFlagRequired("since")
+
+	rootCmd.AddCommand(changelogCmd)
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(changelogRig)
+	if err != nil {
+		return err
+	}
+
+	since, err := resolveChangelogRef(r, changelogSince)
+	if err != nil {
+		return err
+	}
+	until := time.Now()
+	if changelogUntil != "" {
+		if until, err = resolveChangelogRef(r, changelogUntil); err != nil {
+			return err
+		}
+	}
+	if !since.Before(until) {
+		return fmt.Errorf("--since (%s) must be before --until (%s)", since.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+
+	entries, err := changelog.Collect(beads.New(r.BeadsPath()), since, until)
+	if err != nil {
+		return err
+	}
+	cl := changelog.Build(r.Name, changelogSince, since, until, entries)
+
+	out := os.Stdout
+	if changelogOutput != "" {
+		f, err := os.Create(changelogOutput)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if changelogJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cl)
+	}
+
+	tmpl, err := changelog.LoadTemplate(r.Path)
+	if err != nil {
+		return err
+	}
+	if err := cl.Render(out, tmpl); err != nil {
+		return fmt.Errorf("rendering changelog: %w", err)
+	}
+	if changelogOutput != "" {
+		fmt.Printf("Wrote %d change(s) to %s\n", cl.Total, changelogOutput)
+	}
+	return nil
+}
+
+// resolveChangelogRef turns a date or git tag into a time. Tags are looked up
+// in the refinery's clone, falling back to the mayor's.
+func resolveChangelogRef(r *rig.Rig, ref string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, ref, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	var lastErr error
+	for _, dir := range []string{filepath.Join(r.Path, "refinery", "rig"), filepath.Join(r.Path, "mayor", "rig")} {
+		g := git.NewGit(dir)
+		if !g.IsRepo() {
+			continue
+		}
+		t, err := g.CommitTime(ref)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date or a known tag: %w", ref, lastErr)
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date, and no rig clone was found to resolve it as a tag", ref)
+}
@@ -237,7 +237,6 @@ func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, st
 	issues, err := b.List(beads.ListOptions{
 		Status:   "in_progress",
 		Assignee: assignee,
-		Priority: -1,
 	})
 	if err != nil || len(issues) == 0 {
 		return "", "", ""
@@ -280,7 +279,6 @@ func detectHookedBead(workDir string, ctx RoleInfo) string {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: assignee,
-		Priority: -1,
 	})
 	if err != nil || len(hookedBeads) == 0 {
 		return ""
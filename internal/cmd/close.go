@@ -22,7 +22,10 @@ Examples:
   gt close gt-abc gt-def       # Close multiple beads
   gt close --reason "Done"     # Close with reason
   gt close --comment "Done"    # Same as --reason (alias)
-  gt close --force             # Force close pinned beads`,
+  gt close --force             # Force close pinned beads
+
+Beads of types the rig verifies (see 'gt bead verify') close only once their
+required evidence is attached.`,
 	DisableFlagParsing: true, // Pass all flags through to bd close
 	RunE:               runClose,
 }
@@ -49,6 +52,11 @@ func runClose(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Beads the rig requires evidence for close only once it's attached
+	if err := checkCloseVerified(closeArgIDs(convertedArgs)); err != nil {
+		return err
+	}
+
 	// Build bd close command with all args passed through
 	bdArgs := append([]string{"close"}, convertedArgs...)
 	bdCmd := exec.Command("bd", bdArgs...)
@@ -57,3 +65,20 @@ func runClose(cmd *cobra.Command, args []string) error {
 	bdCmd.Stderr = os.Stderr
 	return bdCmd.Run()
 }
+
+// closeArgIDs returns the bead IDs among bd close args: the positional
+// arguments, skipping the values of flags that take one.
+func closeArgIDs(args []string) []string {
+	var ids []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--reason" || arg == "-r" || arg == "--session" || arg == "--actor" || arg == "--db":
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			ids = append(ids, arg)
+		}
+	}
+	return ids
+}
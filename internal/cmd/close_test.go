@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloseArgIDs(t *testing.T) {
+	args := []string{"gt-abc", "--reason", "Done here", "gt-def", "--force", "-r", "x", "--session=s1", "gt-ghi"}
+	if got := strings.Join(closeArgIDs(args), ","); got != "gt-abc,gt-def,gt-ghi" {
+		t.Errorf("closeArgIDs = %s", got)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +31,11 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config get <key>                Show a setting (--origin: where it's set)
+  gt config set <key> <value>        Change a setting (--scope rig|town|user)
+  gt config unset <key>              Remove a setting from one layer
+  gt config list                     Show every setting and where it's set`,
 }
 
 // Agent subcommands
@@ -592,135 +598,280 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// configSetCmd sets a town config value by dot-notation key.
+// Layered config flags
+var (
+	configScope      string
+	configRig        string
+	configGetOrigin  bool
+	configListJSON   bool
+	configListSchema bool
+)
+
+// configSetCmd sets a config value by dot-notation key.
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Long: `Set a town configuration value using dot-notation keys.
+	Long: `Set a configuration value using dot-notation keys.
+
+Settings are layered: a value set for a rig overrides the town's, which
+overrides your user settings, which override the default. Each key has the
+scopes it may be set in; by default set writes to the first of them.
 
-Supported keys:
+  rig    <rig>/settings/config.json (refinery keys: <rig>/config.json)
+  town   <town>/settings/config.json
+  user   ~/.config/gastown/config.json
+
+Values are checked against the key's type before anything is written. Run
+'gt config list --schema' for every key, its type, scopes and default.
+
+Commonly used keys:
   convoy.notify_on_complete   Push notification to Mayor session on convoy
                               completion (true/false, default: false)
   cli_theme                   CLI color scheme ("dark", "light", "auto")
   default_agent               Default agent preset name
+  log.<module>                Log level for a module in logs/gt.log
+                              (debug, info, warn, error, off; default: info).
+                              Modules: doltserver, mq, doctor, cmd, or
+                              "default" for all others
+  merge_queue.test_command    Rig test command
+
+The value may also be given as key=value.
 
 Examples:
   gt config set convoy.notify_on_complete true
-  gt config set cli_theme dark
-  gt config set default_agent claude`,
-	Args: cobra.ExactArgs(2),
+  gt config set cli_theme dark --scope user
+  gt config set default_agent claude
+  gt config set log.doltserver=debug
+  gt config set merge_queue.poll_interval 1m --rig gastown`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runConfigSet,
 }
 
-// configGetCmd gets a town config value by dot-notation key.
+// configGetCmd gets a config value by dot-notation key.
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
 	Short: "Get a configuration value",
-	Long: `Get a town configuration value using dot-notation keys.
+	Long: `Get the effective value of a configuration key.
 
-Supported keys:
-  convoy.notify_on_complete   Push notification to Mayor session on convoy
-                              completion (true/false, default: false)
-  cli_theme                   CLI color scheme
-  default_agent               Default agent preset name
+The value comes from the most specific layer that sets it (rig, town,
+user) or the key's default. --origin also prints where it came from.
 
 Examples:
   gt config get convoy.notify_on_complete
-  gt config get cli_theme`,
+  gt config get cli_theme --origin
+  gt config get log.doltserver
+  gt config get merge_queue.test_command --rig gastown`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConfigGet,
 }
 
+// configListCmd lists every config value with its origin.
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configuration values and where they come from",
+	Long: `List the effective value of every configuration key, and the layer and
+file it comes from ("default" if no layer sets it).
+
+Rig keys are resolved for the rig given by --rig, or the rig you're in.
+
+Examples:
+  gt config list
+  gt config list --rig gastown --json
+  gt config list --schema          # Keys, types, scopes and defaults`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+// configUnsetCmd removes a config value from one layer.
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long: `Remove a key from one layer, so the next layer (or the default) applies.
+
+Like set, unset works on the key's first scope unless --scope is given.
+
+Examples:
+  gt config unset cli_theme --scope user
+  gt config unset merge_queue.test_command --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigUnset,
+}
+
 func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
-	value := args[1]
+	var value string
+	if len(args) == 2 {
+		value = args[1]
+	} else if k, v, ok := strings.Cut(key, "="); ok {
+		key, value = k, v
+	} else {
+		return fmt.Errorf("missing value for %s\n\nUsage: gt config set <key> <value> (or <key>=<value>)", key)
+	}
 
-	townRoot, err := workspace.FindFromCwd()
+	layers, err := configLayers()
 	if err != nil {
-		return fmt.Errorf("finding town root: %w", err)
+		return err
+	}
+	scope, err := configWriteScope(key)
+	if err != nil {
+		return err
+	}
+	path, stored, err := layers.Set(scope, key, value)
+	if err != nil {
+		return err
 	}
 
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	fmt.Printf("Set %s = %s %s\n", style.Bold.Render(key), stored, style.Dim.Render("("+scope+": "+path+")"))
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	layers, err := configLayers()
 	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
+		return err
+	}
+	v, err := layers.Get(args[0])
+	if err != nil {
+		return err
 	}
 
-	switch key {
-	case "convoy.notify_on_complete":
-		b, err := parseBool(value)
-		if err != nil {
-			return fmt.Errorf("invalid value for %s: %w (expected true/false)", key, err)
-		}
-		if townSettings.Convoy == nil {
-			townSettings.Convoy = &config.ConvoyConfig{}
-		}
-		townSettings.Convoy.NotifyOnComplete = b
-
-	case "cli_theme":
-		switch value {
-		case "dark", "light", "auto":
-			townSettings.CLITheme = value
-		default:
-			return fmt.Errorf("invalid cli_theme: %q (expected dark, light, or auto)", value)
-		}
+	if configGetOrigin {
+		fmt.Printf("%s\t%s\n", v.Value, configOrigin(v))
+		return nil
+	}
+	fmt.Println(v.Value)
+	return nil
+}
 
-	case "default_agent":
-		townSettings.DefaultAgent = value
+func runConfigList(cmd *cobra.Command, args []string) error {
+	if configListSchema {
+		return printConfigSchema()
+	}
 
-	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
+	layers, err := configLayers()
+	if err != nil {
+		return err
+	}
+	values, err := layers.List()
+	if err != nil {
+		return err
 	}
 
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
-		return fmt.Errorf("saving town settings: %w", err)
+	if configListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
 	}
 
-	fmt.Printf("Set %s = %s\n", style.Bold.Render(key), value)
+	width := 0
+	for _, v := range values {
+		width = max(width, len(v.Key))
+	}
+	for _, v := range values {
+		value := v.Value
+		if value == "" {
+			value = style.Dim.Render("(unset)")
+		}
+		fmt.Printf("  %-*s  %s  %s\n", width, v.Key, value, style.Dim.Render(configOrigin(v)))
+	}
+	if layers.RigPath == "" {
+		fmt.Printf("\n%s\n", style.Dim.Render("Rig settings show defaults: run inside a rig or use --rig <name>."))
+	}
 	return nil
 }
 
-func runConfigGet(cmd *cobra.Command, args []string) error {
+func runConfigUnset(cmd *cobra.Command, args []string) error {
 	key := args[0]
-
-	townRoot, err := workspace.FindFromCwd()
+	layers, err := configLayers()
 	if err != nil {
-		return fmt.Errorf("finding town root: %w", err)
+		return err
 	}
-
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	scope, err := configWriteScope(key)
 	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
+		return err
+	}
+	path, removed, err := layers.Unset(scope, key)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Printf("%s is not set in %s\n", style.Bold.Render(key), style.Dim.Render(path))
+		return nil
 	}
 
-	var value string
-	switch key {
-	case "convoy.notify_on_complete":
-		if townSettings.Convoy != nil && townSettings.Convoy.NotifyOnComplete {
-			value = "true"
-		} else {
-			value = "false"
+	fmt.Printf("Unset %s %s\n", style.Bold.Render(key), style.Dim.Render("("+scope+": "+path+")"))
+	return nil
+}
+
+func printConfigSchema() error {
+	if configListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(config.ConfigSchema)
+	}
+	for _, k := range config.ConfigSchema {
+		key := strings.Replace(k.Key, "*", "<module>", 1)
+		typ := string(k.Type)
+		if k.Type == config.KeyEnum {
+			typ = strings.Join(k.Values, "|")
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render(key), style.Dim.Render("("+typ+")"))
+		fmt.Printf("    %s\n", k.Description)
+		fmt.Printf("    scopes: %s", strings.Join(k.Scopes, ", "))
+		if k.Default != "" {
+			fmt.Printf("   default: %s", k.Default)
 		}
+		fmt.Println()
+	}
+	return nil
+}
 
-	case "cli_theme":
-		value = townSettings.CLITheme
-		if value == "" {
-			value = "auto"
+// configLayers locates the config files for the current town, the rig given
+// by --rig or the one the current directory is in, and the user.
+func configLayers() (config.ConfigLayers, error) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return config.ConfigLayers{}, fmt.Errorf("finding town root: %w", err)
+	}
+	if configRig != "" {
+		_, r, err := getRig(configRig)
+		if err != nil {
+			return config.ConfigLayers{}, err
 		}
+		return config.NewConfigLayers(townRoot, r.Path), nil
+	}
 
-	case "default_agent":
-		value = townSettings.DefaultAgent
-		if value == "" {
-			value = "claude"
+	var rigPath string
+	if townRoot != "" {
+		if rigName, err := inferRigFromCwd(townRoot); err == nil {
+			if rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+				if _, ok := rigs.Rigs[rigName]; ok {
+					rigPath = filepath.Join(townRoot, rigName)
+				}
+			}
 		}
+	}
+	return config.NewConfigLayers(townRoot, rigPath), nil
+}
 
-	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
+// configWriteScope returns --scope, or the key's default scope.
+func configWriteScope(key string) (string, error) {
+	if configScope != "" {
+		switch configScope {
+		case config.ScopeRig, config.ScopeTown, config.ScopeUser:
+			return configScope, nil
+		}
+		return "", fmt.Errorf("invalid --scope %q (expected rig, town, or user)", configScope)
 	}
+	return config.DefaultScope(key)
+}
 
-	fmt.Println(value)
-	return nil
+// configOrigin describes where a config value came from.
+func configOrigin(v *config.ConfigValue) string {
+	if v.Path == "" {
+		return v.Scope
+	}
+	return v.Scope + ": " + v.Path
 }
 
 // parseBool parses a boolean string (true/false, yes/no, 1/0).
@@ -738,6 +889,14 @@ func parseBool(s string) (bool, error) {
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
+	for _, c := range []*cobra.Command{configSetCmd, configGetCmd, configListCmd, configUnsetCmd} {
+		c.Flags().StringVar(&configRig, "rig", "", "Rig for rig settings (default: infer from current directory)")
+	}
+	configSetCmd.Flags().StringVar(&configScope, "scope", "", "Layer to write: rig, town, or user (default: the key's first scope)")
+	configUnsetCmd.Flags().StringVar(&configScope, "scope", "", "Layer to remove from: rig, town, or user (default: the key's first scope)")
+	configGetCmd.Flags().BoolVar(&configGetOrigin, "origin", false, "Also print the layer and file the value comes from")
+	configListCmd.Flags().BoolVar(&configListJSON, "json", false, "Output as JSON")
+	configListCmd.Flags().BoolVar(&configListSchema, "schema", false, "List the keys: type, scopes, default")
 
 	// Add agent subcommands
 	configAgentCmd := &cobra.Command{
@@ -761,6 +920,8 @@ config values such as the default AI model or provider.`,
 	configCmd.AddCommand(configAgentEmailDomainCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configUnsetCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
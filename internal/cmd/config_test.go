@@ -715,6 +715,40 @@ func TestConfigSetGet(t *testing.T) {
 		}
 	})
 
+	t.Run("set log level with key=value", func(t *testing.T) {
+		townRoot := setupTestTownForConfig(t)
+		settingsPath := config.TownSettingsPath(townRoot)
+
+		originalWd, _ := os.Getwd()
+		defer os.Chdir(originalWd)
+		if err := os.Chdir(townRoot); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		cmd := &cobra.Command{}
+		if err := runConfigSet(cmd, []string{"log.doltserver=DEBUG"}); err != nil {
+			t.Fatalf("runConfigSet failed: %v", err)
+		}
+		if err := runConfigSet(cmd, []string{"log.cmd", "warning"}); err != nil {
+			t.Fatalf("runConfigSet failed: %v", err)
+		}
+
+		loaded, err := config.LoadOrCreateTownSettings(settingsPath)
+		if err != nil {
+			t.Fatalf("load settings: %v", err)
+		}
+		if loaded.Log["doltserver"] != "debug" || loaded.Log["cmd"] != "warn" {
+			t.Errorf("Log = %v, want doltserver=debug cmd=warn", loaded.Log)
+		}
+
+		if err := runConfigSet(cmd, []string{"log.doltserver", "loud"}); err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("bad level: err = %v, want 'invalid value'", err)
+		}
+		if err := runConfigSet(cmd, []string{"log.doltserver"}); err == nil {
+			t.Error("expected error for missing value")
+		}
+	})
+
 	t.Run("convoy.notify_on_complete rejects non-boolean", func(t *testing.T) {
 		townRoot := setupTestTownForConfig(t)
 
@@ -82,6 +82,10 @@ due to database availability.
 Session costs are aggregated daily by 'gt costs digest' into a single
 permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
+The session's usage is also reported against the bead it is working on
+(--work-item, else the session's GT_ISSUE, else the agent's hooked bead)
+in that rig's .beads/costs.jsonl, for 'gt bead cost'.
+
 Examples:
   gt costs record --session gt-gastown-toast
   gt costs record --session gt-gastown-toast --work-item gt-abc123`,
@@ -192,6 +196,7 @@ type TranscriptMessage struct {
 	Type      string                 `json:"type"`
 	SessionID string                 `json:"sessionId"`
 	CWD       string                 `json:"cwd"`
+	Timestamp string                 `json:"timestamp"`
 	Message   *TranscriptMessageBody `json:"message,omitempty"`
 }
 
@@ -213,10 +218,22 @@ type TranscriptUsage struct {
 // TokenUsage aggregates token usage across a session.
 type TokenUsage struct {
 	Model                    string
+	SessionID                string
 	InputTokens              int
 	CacheCreationInputTokens int
 	CacheReadInputTokens     int
 	OutputTokens             int
+
+	// First and last message times, for the session's compute minutes
+	FirstAt, LastAt time.Time
+}
+
+// ComputeMinutes returns the time between the session's first and last message.
+func (u *TokenUsage) ComputeMinutes() float64 {
+	if u.FirstAt.IsZero() || !u.LastAt.After(u.FirstAt) {
+		return 0
+	}
+	return u.LastAt.Sub(u.FirstAt).Minutes()
 }
 
 // Model pricing per million tokens (as of Jan 2025).
@@ -772,6 +789,16 @@ func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
 			continue // Skip malformed lines
 		}
 
+		if usage.SessionID == "" && msg.SessionID != "" {
+			usage.SessionID = msg.SessionID
+		}
+		if ts, err := time.Parse(time.RFC3339, msg.Timestamp); err == nil {
+			if usage.FirstAt.IsZero() {
+				usage.FirstAt = ts
+			}
+			usage.LastAt = ts
+		}
+
 		// Only process assistant messages with usage info
 		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
 			continue
@@ -821,22 +848,31 @@ func calculateCost(usage *TokenUsage) float64 {
 // extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
 // This reads the most recent transcript file and sums all token usage.
 func extractCostFromWorkDir(workDir string) (float64, error) {
+	usage, err := extractUsageFromWorkDir(workDir)
+	if err != nil {
+		return 0, err
+	}
+	return calculateCost(usage), nil
+}
+
+// extractUsageFromWorkDir sums the token usage of the most recent Claude Code
+// transcript for a working directory.
+func extractUsageFromWorkDir(workDir string) (*TokenUsage, error) {
 	projectDir, err := getClaudeProjectDir(workDir)
 	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
+		return nil, fmt.Errorf("getting project dir: %w", err)
 	}
 
 	transcriptPath, err := findLatestTranscript(projectDir)
 	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
+		return nil, fmt.Errorf("finding transcript: %w", err)
 	}
 
 	usage, err := parseTranscriptUsage(transcriptPath)
 	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+		return nil, fmt.Errorf("parsing transcript: %w", err)
 	}
-
-	return calculateCost(usage), nil
+	return usage, nil
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -989,20 +1025,33 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 
 	// Extract cost from Claude transcript
 	var cost float64
+	var usage *TokenUsage
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		usage, err = extractUsageFromWorkDir(workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
 			}
-			cost = 0.0
+		} else {
+			cost = calculateCost(usage)
 		}
 	}
 
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
 
+	// Attribute the session's cost to the bead it is working on
+	workItem := recordWorkItem
+	if workItem == "" {
+		workItem = detectCostWorkItem(session, workDir)
+	}
+	if workItem != "" && usage != nil {
+		if err := recordBeadCost(workItem, session, rig, worker, role, usage, cost); err != nil && costsVerbose {
+			fmt.Fprintf(os.Stderr, "[costs] could not record cost for %s: %v\n", workItem, err)
+		}
+	}
+
 	// Build log entry
 	entry := CostLogEntry{
 		SessionID: session,
@@ -1011,7 +1060,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		Worker:    worker,
 		CostUSD:   cost,
 		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
+		WorkItem:  workItem,
 	}
 
 	// Marshal to JSON
@@ -1044,10 +1093,10 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output confirmation (silent if cost is zero and no work item)
-	if cost > 0 || recordWorkItem != "" {
+	if cost > 0 || workItem != "" {
 		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
+		if workItem != "" {
+			fmt.Printf(" (work: %s)", workItem)
 		}
 		fmt.Println()
 	}
@@ -157,6 +157,8 @@ func TestDeriveSessionName(t *testing.T) {
 				for key, val := range saved {
 					if val != "" {
 						os.Setenv(key, val)
+					} else {
+						os.Unsetenv(key)
 					}
 				}
 			}()
@@ -264,3 +266,25 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestParseTranscriptUsage_SessionAndMinutes(t *testing.T) {
+	path := t.TempDir() + "/session.jsonl"
+	lines := `{"type":"user","sessionId":"abc-123","timestamp":"2026-03-02T10:00:00.000Z"}
+{"type":"assistant","sessionId":"abc-123","timestamp":"2026-03-02T10:01:30.500Z","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"output_tokens":20}}}
+{"type":"assistant","sessionId":"abc-123","timestamp":"2026-03-02T10:12:00.000Z","message":{"usage":{"input_tokens":50,"output_tokens":5}}}
+`
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := parseTranscriptUsage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.SessionID != "abc-123" || usage.InputTokens != 150 || usage.OutputTokens != 25 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if got := usage.ComputeMinutes(); got != 12 {
+		t.Errorf("ComputeMinutes() = %v, want 12", got)
+	}
+}
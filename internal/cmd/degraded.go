@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// mqSnapshotFile is the rig-local snapshot of open merge requests. MR beads
+// are ephemeral and left out of the bd export, so gt mq list saves them here
+// for use during an outage.
+func mqSnapshotFile(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "mq-snapshot.jsonl")
+}
+
+// outageSnapshot returns the stale snapshot for b (plus extra files) and
+// the reason the Dolt server is unreachable. It returns nil if the server is
+// up, no snapshot exists, or the caller is an agent: agent sessions (GT_ROLE
+// set) must fail hard rather than act on stale state.
+func outageSnapshot(b *beads.Beads, extra ...string) (*beads.Snapshot, error) {
+	if os.Getenv("GT_ROLE") != "" {
+		return nil, nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil, nil
+	}
+	if len(doltserver.HasServerModeMetadata(townRoot)) == 0 {
+		return nil, nil // Not using a Dolt server: nothing to degrade from
+	}
+	cause := doltserver.CheckServerReachable(townRoot)
+	if cause == nil {
+		return nil, nil
+	}
+	snap, err := b.Snapshot(extra...)
+	if err != nil {
+		return nil, nil
+	}
+	return snap, cause
+}
+
+// listOrSnapshot runs b.List and, if it fails because the Dolt server is
+// down, serves the listing from the stale snapshot with a warning. The
+// returned snapshot is nil when the listing is live.
+func listOrSnapshot(b *beads.Beads, opts beads.ListOptions, extra ...string) ([]*beads.Issue, *beads.Snapshot, error) {
+	issues, err := b.List(opts)
+	if err == nil || errors.Is(err, beads.ErrNotInstalled) {
+		return issues, nil, err
+	}
+	snap, cause := outageSnapshot(b, extra...)
+	if snap == nil {
+		return nil, nil, err
+	}
+	printStaleSnapshotWarning(snap, cause)
+	return snap.List(opts), snap, nil
+}
+
+func printStaleSnapshotWarning(s *beads.Snapshot, cause error) {
+	sources := make([]string, len(s.Sources))
+	for i, src := range s.Sources {
+		sources[i] = filepath.Base(src)
+	}
+	fmt.Fprintf(os.Stderr, "\n%s Dolt server unreachable: showing a STALE read-only snapshot\n",
+		style.Bold.Render("⚠️  WARNING:"))
+	fmt.Fprintf(os.Stderr, "   Snapshot: %s (%s)\n", strings.Join(sources, ", "), formatAge(s.TakenAt))
+	fmt.Fprintf(os.Stderr, "   Cause: %v\n", cause)
+	fmt.Fprintf(os.Stderr, "   Changes will fail until the server is back. Check: %s\n\n",
+		style.Dim.Render("gt dolt status"))
+}
+
+// showFromSnapshot serves gt bead show from the stale snapshot when the Dolt
+// server is down (bd show would fail). Returns false if the live path
+// should be used.
+func showFromSnapshot(args []string) (bool, error) {
+	var id string
+	jsonOut := false
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOut = true
+		} else if id == "" && !strings.HasPrefix(arg, "-") {
+			id = arg
+		}
+	}
+	if id == "" {
+		return false, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, nil
+	}
+	snap, cause := outageSnapshot(beads.New(cwd))
+	if snap == nil {
+		return false, nil
+	}
+	printStaleSnapshotWarning(snap, cause)
+	issue, err := snap.Show(id)
+	if err != nil {
+		return true, fmt.Errorf("%s not in snapshot: %w", id, err)
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return true, enc.Encode([]*beads.Issue{issue})
+	}
+	fmt.Printf("%s: %s %s\n", style.Bold.Render(issue.ID), issue.Title, style.Dim.Render("(stale snapshot)"))
+	fmt.Printf("Status: %s   Priority: P%d   Type: %s\n", issue.Status, issue.Priority, issue.Type)
+	if issue.Assignee != "" {
+		fmt.Printf("Assignee: %s\n", issue.Assignee)
+	}
+	if issue.Parent != "" {
+		fmt.Printf("Parent: %s\n", issue.Parent)
+	}
+	if len(issue.DependsOn) > 0 {
+		fmt.Printf("Depends on: %s\n", strings.Join(issue.DependsOn, ", "))
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if issue.UpdatedAt != "" {
+		fmt.Printf("Updated: %s\n", issue.UpdatedAt)
+	}
+	if issue.Description != "" {
+		fmt.Printf("\n%s\n", issue.Description)
+	}
+	return true, nil
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestOutageSnapshot_AgentsFailHard(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, beads.SnapshotExport), []byte(`{"id":"gt-1","status":"open"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The refinery patrol runs gt mq list with GT_ROLE set: it must see the
+	// error, never a stale queue.
+	t.Setenv("GT_ROLE", "gastown/refinery")
+	if snap, _ := outageSnapshot(beads.NewWithBeadsDir(dir, beadsDir)); snap != nil {
+		t.Error("agent callers must not be served a stale snapshot")
+	}
+}
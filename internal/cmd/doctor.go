@@ -12,10 +12,15 @@ import (
 
 var (
 	doctorFix             bool
+	doctorDryRun          bool
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorSlow            string
+	doctorFsck            bool
+	doctorNoCache         bool
+	doctorOnly            []string
+	doctorProfile         string
 )
 
 var doctorCmd = &cobra.Command{
@@ -31,7 +36,7 @@ Workspace checks:
   - town-config-exists       Check mayor/town.json exists
   - town-config-valid        Check mayor/town.json is valid
   - rigs-registry-exists     Check mayor/rigs.json exists (fixable)
-  - rigs-registry-valid      Check registered rigs exist (fixable)
+  - rigs-registry-valid      Check rigs.json schema and rig directories (fixable)
   - mayor-exists             Check mayor/ directory structure
 
 Town root protection:
@@ -42,6 +47,7 @@ Town root protection:
 Infrastructure checks:
   - stale-binary             Check if gt binary is up to date with repo
   - beads-binary             Check that beads (bd) is installed and meets minimum version
+  - rig-toolchain            Verify installed go/node/dolt match rig pins (gt rig pin)
   - daemon                   Check if daemon is running (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
 
@@ -51,6 +57,7 @@ Cleanup checks (fixable):
   - session-name-format      Detect sessions with outdated naming format (fixable)
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
   - stale-beads-redirect     Detect stale files in .beads directories with redirects
+  - orphaned-mq-entries      Detect MRs with a missing branch, closed bead, or missing claimant
 
 Clone divergence checks:
   - persistent-role-branches Detect crew/witness/refinery not on main
@@ -84,13 +91,19 @@ Session hook checks:
   - session-hooks            Check settings.json use session-start.sh
   - claude-settings          Check Claude settings.json match templates (fixable)
   - deprecated-merge-queue-keys  Detect stale deprecated keys in merge_queue config (fixable)
+  - owners                   Validate the component ownership map (settings/owners.json)
   - stale-task-dispatch      Detect stale task-dispatch guard in settings.json (fixable)
 
 Dolt checks:
-  - dolt-binary              Check that dolt is installed and in PATH
+  - dolt-binary              Check that dolt is installed, in PATH and a supported version
   - dolt-metadata            Check dolt metadata tables exist
   - dolt-server-reachable    Check dolt sql-server is reachable
+  - dolt-server-version      Check server version against town bounds and known-bad releases
   - dolt-orphaned-databases  Detect orphaned dolt databases
+  - dolt-missing-databases   Detect server-mode rigs missing their database (fixable)
+
+Optional checks:
+  - beads-fsck               Detect dangling bead relations and links (--fsck, fixable)
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -98,24 +111,71 @@ Patrol checks:
   - patrol-not-stuck         Detect stale wisps (>1h)
   - patrol-plugins-accessible Verify plugin directories
 
-Use --fix to attempt automatic fixes for issues that support it.
+Use --fix to attempt automatic fixes for issues that support it. Fixes
+are applied in dependency order (e.g., dolt-metadata before agent-beads-exist),
+and a check is not fixed while a check it depends on still fails.
+Use --fix --dry-run to preview the files, lines, and commands each fix would
+change without applying anything.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Expensive checks (beads-fsck, default-branch-all-rigs) reuse results cached
+in .runtime/doctor-cache.json until their TTL expires. Use --no-cache to
+re-run them. --fix always runs checks fresh.
+
+Each run is recorded under .runtime/doctor-history/. Use 'gt doctor history'
+to list past runs and 'gt doctor diff' to see which checks regressed.
+'gt doctor serve' repeats the run on an interval and alerts when a check
+starts failing.
+Each run is also scored 0-100 by check weight ('gt doctor score'), and the
+score badge in .runtime/ is refreshed ('gt doctor badge').
+
+Subsets:
+  --only <groups>      Run only checks in these groups: beads, dolt, git,
+                       agents, config (comma-separated; a check can be in
+                       several)
+  --profile <name>     Run a curated set of checks:
+                         pre-flight    Fast checks before starting agents
+                         post-upgrade  After upgrading gt, bd or dolt
+                         agents        Sessions, patrols and agent state
+  --only and --profile combine: 'gt doctor --profile post-upgrade --only dolt'.
+
+Suppressions:
+  Known-acceptable warnings can be hidden with settings/doctor-suppress.json
+  in the town or in a rig. A rig's file only covers findings about that rig.
+  Errors are never suppressed.
+
+    {"suppress": [
+      {"check": "orphan-sessions", "match": "gt-legacy", "reason": "kept for demo"}
+    ]}
+
+  "match" limits the rule to findings containing that text; without it every
+  warning of the check is suppressed. Suppressed findings are counted in the
+  summary and are not fixed by --fix.`,
 	RunE: runDoctor,
 }
 
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
+	doctorCmd.Flags().BoolVar(&doctorDryRun, "dry-run", false, "With --fix, show what would be changed without applying fixes")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
+	doctorCmd.Flags().BoolVar(&doctorFsck, "fsck", false, "Also run the beads integrity pass (exports every beads database)")
+	doctorCmd.Flags().BoolVar(&doctorNoCache, "no-cache", false, "Re-run expensive checks instead of reusing cached results")
+	doctorCmd.Flags().StringSliceVar(&doctorOnly, "only", nil, "Run only checks in these groups (beads, dolt, git, agents, config)")
+	doctorCmd.Flags().StringVar(&doctorProfile, "profile", "", "Run a curated check profile (pre-flight, post-upgrade, agents)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorDryRun && !doctorFix {
+		return fmt.Errorf("--dry-run requires --fix")
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -128,9 +188,75 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		RigName:         doctorRig,
 		Verbose:         doctorVerbose,
 		RestartSessions: doctorRestartSessions,
+		NoCache:         doctorNoCache,
+	}
+	// Let fixes ask before changes that need a human's call
+	if doctorFix && !doctorDryRun && isStdinTerminal() {
+		ctx.Confirm = func(question string) bool {
+			fmt.Println()
+			return promptYesNo("    " + question)
+		}
 	}
 
 	// Create doctor and register checks
+	d := newTownDoctor(doctorRig, doctorFsck)
+	keep, err := doctor.Selection(doctorOnly, doctorProfile)
+	if err != nil {
+		return err
+	}
+	d.Filter(keep)
+	if len(d.Checks()) == 0 {
+		return fmt.Errorf("no checks selected by --only/--profile")
+	}
+
+	suppressions, err := doctor.LoadSuppressions(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading doctor suppressions: %w", err)
+	}
+	d.SetSuppressions(suppressions)
+
+	// Parse slow threshold (0 = disabled)
+	var slowThreshold time.Duration
+	if doctorSlow != "" {
+		var err error
+		slowThreshold, err = time.ParseDuration(doctorSlow)
+		if err != nil {
+			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+		}
+	}
+
+	// Run checks with streaming output
+	fmt.Println() // Initial blank line
+	var report *doctor.Report
+	if doctorFix && doctorDryRun {
+		report = d.FixDryRunStreaming(ctx, os.Stdout, slowThreshold)
+	} else if doctorFix {
+		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
+	} else {
+		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+	}
+
+	// Print summary (checks were already printed during streaming)
+	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+
+	// Record the run so 'gt doctor diff' can spot regressions (dry runs change
+	// nothing; subsets would show the unselected checks as gone)
+	if !doctorDryRun && len(doctorOnly) == 0 && doctorProfile == "" {
+		recordDoctorRun(townRoot, report)
+	}
+
+	// Exit with error code if there are errors
+	if report.HasErrors() {
+		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	}
+
+	return nil
+}
+
+// newTownDoctor returns a doctor with every built-in check registered.
+// Rig-specific checks are added when rigName is set; the beads integrity
+// pass only when fsck is set.
+func newTownDoctor(rigName string, fsck bool) *doctor.Doctor {
 	d := doctor.NewDoctor()
 
 	// Register workspace-level checks first (fundamental)
@@ -141,6 +267,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Register built-in checks
 	d.Register(doctor.NewStaleBinaryCheck())
 	d.Register(doctor.NewBeadsBinaryCheck())
+	d.Register(doctor.NewRigToolchainCheck())
 	// All database queries go through bd CLI
 	d.Register(doctor.NewTownGitCheck())
 	d.Register(doctor.NewTownRootBranchCheck())
@@ -180,6 +307,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewPatrolPluginsAccessibleCheck())
 	d.Register(doctor.NewAgentBeadsCheck())
 	d.Register(doctor.NewStaleAgentBeadsCheck())
+	d.Register(doctor.NewOrphanedMRCheck())
 	d.Register(doctor.NewRigBeadsCheck())
 	d.Register(doctor.NewRoleBeadsCheck())
 
@@ -192,6 +320,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
 	d.Register(doctor.NewDeprecatedMergeQueueKeysCheck())
+	d.Register(doctor.NewOwnersCheck())
 	d.Register(doctor.NewLandWorktreeGitignoreCheck())
 	d.Register(doctor.NewHooksPathAllRigsCheck())
 
@@ -217,48 +346,29 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Hooks sync check
 	d.Register(doctor.NewStaleTaskDispatchCheck())
 	d.Register(doctor.NewHooksSyncCheck())
+	d.Register(doctor.NewBeadsSyncHooksCheck())
 
 	// Dolt health checks
 	d.Register(doctor.NewDoltBinaryCheck())
 	d.Register(doctor.NewDoltMetadataCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
+	d.Register(doctor.NewDoltServerVersionCheck())
 	d.Register(doctor.NewDoltOrphanedDatabaseCheck())
+	d.Register(doctor.NewDoltMissingDatabaseCheck())
 
 	// Worktree gitdir validity (runs across all rigs, or specific rig with --rig)
 	d.Register(doctor.NewWorktreeGitdirCheck())
 
-	// Rig-specific checks (only when --rig is specified)
-	if doctorRig != "" {
-		d.RegisterAll(doctor.RigChecks()...)
-	}
-
-	// Parse slow threshold (0 = disabled)
-	var slowThreshold time.Duration
-	if doctorSlow != "" {
-		var err error
-		slowThreshold, err = time.ParseDuration(doctorSlow)
-		if err != nil {
-			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
-		}
-	}
-
-	// Run checks with streaming output
-	fmt.Println() // Initial blank line
-	var report *doctor.Report
-	if doctorFix {
-		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
-	} else {
-		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+	// Beads integrity pass (opt-in: exports every database)
+	if fsck {
+		d.Register(doctor.NewBeadsFsckCheck())
 	}
 
-	// Print summary (checks were already printed during streaming)
-	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
-
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	// Rig-specific checks (only when --rig is specified)
+	if rigName != "" {
+		d.RegisterAll(doctor.RigChecks()...)
 	}
 
-	return nil
+	return d
 }
 
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doctorHistoryLimit int
+	doctorHistoryJSON  bool
+	doctorDiffSince    string
+	doctorDiffRig      string
+	doctorDiffJSON     bool
+)
+
+var doctorHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recorded doctor runs",
+	Long: `List past doctor runs, newest first.
+
+Every 'gt doctor' run (except --fix --dry-run) is recorded under
+.runtime/doctor-history/; the last 100 runs are kept. The REGRESSED column
+counts checks that got worse since the previous run with the same scope.
+
+Examples:
+  gt doctor history
+  gt doctor history --limit 5
+  gt doctor history --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorHistory,
+}
+
+var doctorDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show which checks changed since an earlier doctor run",
+	Long: `Compare the latest doctor run with an earlier one.
+
+By default the latest run is compared with the run before it. Use --since to
+compare with the newest run older than a duration, to spot config drift an
+agent introduced over a longer period. Only runs with the same scope (whole
+town, or the same --rig) are compared.
+
+Exits non-zero if any check regressed.
+
+Examples:
+  gt doctor diff
+  gt doctor diff --since 24h
+  gt doctor diff --rig gastown
+  gt doctor diff --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorDiff,
+}
+
+func init() {
+	doctorHistoryCmd.Flags().IntVarP(&doctorHistoryLimit, "limit", "n", 20, "Maximum number of runs to show")
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryJSON, "json", false, "Output as JSON")
+	doctorDiffCmd.Flags().StringVar(&doctorDiffSince, "since", "", "Compare with the newest run older than this (e.g. 24h, 7d)")
+	doctorDiffCmd.Flags().StringVar(&doctorDiffRig, "rig", "", "Compare runs scoped to this rig")
+	doctorDiffCmd.Flags().BoolVar(&doctorDiffJSON, "json", false, "Output as JSON")
+
+	doctorCmd.AddCommand(doctorHistoryCmd)
+	doctorCmd.AddCommand(doctorDiffCmd)
+}
+
+// recordDoctorRun saves a report to the doctor history and points out any
+// regressions since the previous run with the same scope.
+func recordDoctorRun(townRoot string, report *doctor.Report) {
+	entry := doctor.NewHistoryEntry(report, doctorRig, doctorFix)
+
+	// Load before saving so the baseline is never this run
+	history, _ := doctor.LoadHistory(townRoot)
+	if err := doctor.SaveHistory(townRoot, entry); err != nil {
+		style.PrintWarning("could not record doctor history: %v", err)
+		return
+	}
+	recordDoctorScore(townRoot, entry)
+
+	baseline := doctor.FindBaseline(history, doctorRig, entry.Timestamp)
+	if baseline == nil {
+		return
+	}
+	regressed := 0
+	for _, c := range doctor.DiffHistory(baseline, entry) {
+		if c.Regressed {
+			regressed++
+		}
+	}
+	if regressed > 0 {
+		fmt.Printf("%s %d check(s) regressed since the last run (%s). See: %s\n",
+			style.WarningPrefix, regressed, formatAge(baseline.Timestamp), style.Dim.Render("gt doctor diff"))
+	}
+}
+
+func runDoctorHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	history, err := doctor.LoadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+
+	scoring, err := doctor.LoadScoreConfig(townRoot)
+	if err != nil {
+		return err
+	}
+
+	type historyRow struct {
+		*doctor.HistoryEntry
+		Score     *int `json:"score,omitempty"`
+		Regressed int  `json:"regressed"`
+	}
+	var rows []historyRow
+	for i := len(history) - 1; i >= 0 && (doctorHistoryLimit <= 0 || len(rows) < doctorHistoryLimit); i-- {
+		row := historyRow{HistoryEntry: history[i]}
+		if score, ok := scoring.Score(history[i].Checks); ok {
+			row.Score = &score
+		}
+		if baseline := doctor.FindBaseline(history[:i], history[i].Rig, history[i].Timestamp); baseline != nil {
+			for _, c := range doctor.DiffHistory(baseline, history[i]) {
+				if c.Regressed {
+					row.Regressed++
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if doctorHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No doctor runs recorded yet. Run 'gt doctor' first.")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-12s %5s %5s %5s %5s %10s\n", "WHEN", "SCOPE", "SCORE", "OK", "WARN", "ERR", "REGRESSED")
+	for _, row := range rows {
+		scope := "town"
+		if row.Rig != "" {
+			scope = row.Rig
+		}
+		if row.Fix {
+			scope += " (fix)"
+		}
+		score := "-"
+		if row.Score != nil {
+			score = fmt.Sprintf("%d", *row.Score)
+		}
+		fmt.Printf("%-20s %-12s %5s %5d %5d %5d %10d\n",
+			row.Timestamp.Local().Format("2006-01-02 15:04:05"), scope, score,
+			row.Summary.OK, row.Summary.Warnings, row.Summary.Errors, row.Regressed)
+	}
+	return nil
+}
+
+func runDoctorDiff(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	history, err := doctor.LoadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+
+	latest := doctor.FindBaseline(history, doctorDiffRig, time.Now().Add(time.Second))
+	if latest == nil {
+		return fmt.Errorf("no doctor runs recorded for this scope; run 'gt doctor' first")
+	}
+
+	before := latest.Timestamp
+	if doctorDiffSince != "" {
+		since, err := parseDuration(doctorDiffSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", doctorDiffSince, err)
+		}
+		if cutoff := time.Now().Add(-since); cutoff.Before(before) {
+			before = cutoff
+		}
+	}
+	baseline := doctor.FindBaseline(history, doctorDiffRig, before)
+	if baseline == nil {
+		return fmt.Errorf("no earlier doctor run to compare with")
+	}
+
+	changes := doctor.DiffHistory(baseline, latest)
+	regressed := 0
+	for _, c := range changes {
+		if c.Regressed {
+			regressed++
+		}
+	}
+
+	if doctorDiffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]interface{}{
+			"from":    baseline.Timestamp,
+			"to":      latest.Timestamp,
+			"changes": changes,
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Comparing %s → %s\n\n",
+			baseline.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			latest.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		if len(changes) == 0 {
+			fmt.Printf("%s No check changed status\n", style.SuccessPrefix)
+		}
+		for _, c := range changes {
+			prefix := style.SuccessPrefix
+			if c.Regressed {
+				prefix = style.ErrorPrefix
+			}
+			from, to := c.From, c.To
+			if from == "" {
+				from = "(new)"
+			}
+			if to == "" {
+				to = "(removed)"
+			}
+			fmt.Printf("%s %-32s %s → %s\n", prefix, c.Name, from, to)
+			if c.Regressed && c.Message != "" {
+				fmt.Printf("    %s\n", style.Dim.Render(c.Message))
+			}
+		}
+	}
+
+	if regressed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doctorScoreJSON   bool
+	doctorBadgeRig    string
+	doctorBadgeOutput string
+	doctorBadgeLabel  string
+)
+
+var doctorScoreCmd = &cobra.Command{
+	Use:   "score",
+	Short: "Show the health score of the town and each rig",
+	Long: `Show the weighted health score (0-100) of the latest doctor run for the
+town and for each rig that has been checked with 'gt doctor --rig'.
+
+A passing check earns its full weight, a warning a share of it (the warning
+credit), an error nothing. Weights come from settings/doctor-score.json in
+the town; unlisted checks use their category's weight (Core 3,
+Infrastructure 2, Rig 2, Cleanup 0.5, others 1). A weight of 0 leaves a
+check out.
+
+    {
+      "warning_credit": 0.5,
+      "categories": {"Cleanup": 0},
+      "checks": {"dolt-server-reachable": 5, "theme": 0}
+    }
+
+The score also appears in 'gt status' and the dashboard, and every doctor
+run refreshes a badge in .runtime/ (see 'gt doctor badge').
+
+Examples:
+  gt doctor score
+  gt doctor score --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorScore,
+}
+
+var doctorBadgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Write an SVG badge of the health score",
+	Long: `Write an SVG badge ("town health | 92%") of the latest doctor run's score,
+to embed in a README or wiki.
+
+Every doctor run also refreshes .runtime/doctor-badge.svg (or
+.runtime/doctor-badge-<rig>.svg for --rig runs), so a badge served from
+there stays current.
+
+Examples:
+  gt doctor badge -o docs/health.svg
+  gt doctor badge --rig gastown -o gastown/docs/health.svg
+  gt doctor badge --label "gas town"`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorBadge,
+}
+
+func init() {
+	doctorScoreCmd.Flags().BoolVar(&doctorScoreJSON, "json", false, "Output as JSON")
+	doctorBadgeCmd.Flags().StringVar(&doctorBadgeRig, "rig", "", "Badge for this rig's latest --rig run")
+	doctorBadgeCmd.Flags().StringVarP(&doctorBadgeOutput, "output", "o", "", "Where to write the badge (default: the one in .runtime/)")
+	doctorBadgeCmd.Flags().StringVar(&doctorBadgeLabel, "label", "", `Badge label (default "town health" or "<rig> health")`)
+
+	doctorCmd.AddCommand(doctorScoreCmd)
+	doctorCmd.AddCommand(doctorBadgeCmd)
+}
+
+// townHealthScores scores the latest doctor run of the town and each rig.
+func townHealthScores(townRoot string) ([]doctor.HealthScore, error) {
+	cfg, err := doctor.LoadScoreConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	history, err := doctor.LoadHistory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return doctor.LatestScores(history, cfg), nil
+}
+
+func runDoctorScore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scores, err := townHealthScores(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if doctorScoreJSON {
+		if scores == nil {
+			scores = []doctor.HealthScore{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(scores)
+	}
+
+	if len(scores) == 0 {
+		fmt.Println("No doctor runs recorded yet. Run 'gt doctor' first.")
+		return nil
+	}
+	fmt.Printf("%-16s %6s %5s %5s  %s\n", "SCOPE", "SCORE", "WARN", "ERR", "CHECKED")
+	for _, s := range scores {
+		scope := s.Rig
+		if scope == "" {
+			scope = "town"
+		}
+		fmt.Printf("%-16s %6s %5d %5d  %s\n", scope, formatHealthScore(s.Score), s.Warnings, s.Errors, formatAge(s.Timestamp))
+	}
+	return nil
+}
+
+func runDoctorBadge(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scores, err := townHealthScores(townRoot)
+	if err != nil {
+		return err
+	}
+	var score *doctor.HealthScore
+	for i := range scores {
+		if scores[i].Rig == doctorBadgeRig {
+			score = &scores[i]
+		}
+	}
+	if score == nil {
+		if doctorBadgeRig != "" {
+			return fmt.Errorf("no doctor run recorded for rig %s; run 'gt doctor --rig %s' first", doctorBadgeRig, doctorBadgeRig)
+		}
+		return fmt.Errorf("no doctor runs recorded yet; run 'gt doctor' first")
+	}
+
+	path := doctorBadgeOutput
+	if path == "" {
+		path = doctor.BadgePath(townRoot, doctorBadgeRig)
+	}
+	label := doctorBadgeLabel
+	if label == "" {
+		label = doctor.BadgeLabel(doctorBadgeRig)
+	}
+	if err := doctor.WriteBadge(path, label, score.Score); err != nil {
+		return err
+	}
+	fmt.Printf("%s Wrote %s (%s)\n", style.SuccessPrefix, path, formatHealthScore(score.Score))
+	return nil
+}
+
+// formatHealthScore renders a score colored by how healthy it is.
+func formatHealthScore(score int) string {
+	s := fmt.Sprintf("%d/100", score)
+	switch {
+	case score >= 90:
+		return style.Success.Render(s)
+	case score >= 60:
+		return style.Warning.Render(s)
+	}
+	return style.Error.Render(s)
+}
+
+// recordDoctorScore prints a run's score and refreshes its scope's badge.
+func recordDoctorScore(townRoot string, entry *doctor.HistoryEntry) {
+	cfg, err := doctor.LoadScoreConfig(townRoot)
+	if err != nil {
+		style.PrintWarning("could not load doctor scoring: %v", err)
+		return
+	}
+	score, ok := cfg.Score(entry.Checks)
+	if !ok {
+		return
+	}
+	fmt.Printf("Health score: %s\n", formatHealthScore(score))
+	if err := doctor.WriteBadge(doctor.BadgePath(townRoot, entry.Rig), doctor.BadgeLabel(entry.Rig), score); err != nil {
+		style.PrintWarning("could not write health badge: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doctorServeInterval time.Duration
+	doctorServeOnce     bool
+)
+
+var doctorServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the doctor checks on an interval and alert on new failures",
+	Long: `Run the town-wide doctor checks every --interval until interrupted.
+
+Each run is recorded in the doctor history (see 'gt doctor history') and
+scored like a manual 'gt doctor' run. When a check goes from OK to error,
+an alert is raised through the channels enabled in
+settings/doctor-monitor.json:
+
+    {
+      "interval": "10m",
+      "desktop": true,
+      "webhooks": [{"url": "https://hooks.example.com/gt", "secret": "..."}],
+      "create_beads": true
+    }
+
+  desktop        notify-send (Linux) or osascript (macOS)
+  webhooks       POST {"event": "doctor.check_failed", "check": ..., ...};
+                 with a secret the body is signed as for merge queue webhooks
+  create_beads   file a P1 bug "doctor: <check> failing" in the town beads,
+                 unless one is already open
+
+Warnings and checks that were already failing do not alert. The first run
+is compared with the latest recorded town-wide run. Without a settings
+file runs are only recorded.
+
+Run it in the background, e.g. in a tmux window, or from cron with --once.
+
+Examples:
+  gt doctor serve
+  gt doctor serve --interval 5m
+  gt doctor serve --once`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorServe,
+}
+
+func init() {
+	doctorServeCmd.Flags().DurationVar(&doctorServeInterval, "interval", 0, "Time between runs (default: settings, else 15m)")
+	doctorServeCmd.Flags().BoolVar(&doctorServeOnce, "once", false, "Run once, alert, and exit")
+
+	doctorCmd.AddCommand(doctorServeCmd)
+}
+
+func runDoctorServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := doctor.LoadMonitorConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	interval := doctorServeInterval
+	if interval == 0 {
+		if interval, err = cfg.IntervalDuration(); err != nil {
+			return err
+		}
+	} else if interval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	monitor := doctor.NewMonitor(townRoot, cfg)
+	history, _ := doctor.LoadHistory(townRoot)
+	prev := doctor.FindBaseline(history, "", time.Now())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !doctorServeOnce {
+		fmt.Printf("Running doctor checks every %s (Ctrl-C to stop)\n", interval)
+	}
+	for {
+		entry, err := doctorServeRun(townRoot)
+		if err != nil {
+			style.PrintWarning("doctor run failed: %v", err)
+		} else {
+			alerts := doctor.Alerts(prev, entry)
+			for _, a := range alerts {
+				fmt.Printf("  %s %s\n", style.ErrorPrefix, doctor.FormatAlert(a))
+			}
+			if err := monitor.Notify(alerts); err != nil {
+				style.PrintWarning("could not raise all alerts: %v", err)
+			}
+			prev = entry
+		}
+		if doctorServeOnce {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// doctorServeRun runs the town-wide checks once and records the run.
+func doctorServeRun(townRoot string) (*doctor.HistoryEntry, error) {
+	d := newTownDoctor("", false)
+	suppressions, err := doctor.LoadSuppressions(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading doctor suppressions: %w", err)
+	}
+	d.SetSuppressions(suppressions)
+
+	report := d.Run(&doctor.CheckContext{TownRoot: townRoot})
+	entry := doctor.NewHistoryEntry(report, "", false)
+	fmt.Printf("%s %d ok, %d warning(s), %d error(s)\n",
+		style.Dim.Render(entry.Timestamp.Local().Format("15:04:05")),
+		entry.Summary.OK, entry.Summary.Warnings, entry.Summary.Errors)
+	if err := doctor.SaveHistory(townRoot, entry); err != nil {
+		style.PrintWarning("could not record doctor history: %v", err)
+	} else {
+		recordDoctorScore(townRoot, entry)
+	}
+	return entry, nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+// Every fixable check must say what 'gt doctor --fix --dry-run' would do.
+func TestNewTownDoctor_FixableChecksPreviewFixes(t *testing.T) {
+	d := newTownDoctor("gastown", true)
+	for _, check := range d.Checks() {
+		if !check.CanFix() {
+			continue
+		}
+		if _, ok := check.(doctor.FixPreviewer); !ok {
+			t.Errorf("%s (%T) can fix but does not implement doctor.FixPreviewer", check.Name(), check)
+		}
+	}
+}
+
+// Every check must be reachable through 'gt doctor --only', and profiles
+// must only name checks that exist.
+func TestNewTownDoctor_ChecksHaveGroups(t *testing.T) {
+	d := newTownDoctor("gastown", true)
+	names := make(map[string]bool)
+	for _, check := range d.Checks() {
+		names[check.Name()] = true
+		if len(doctor.CheckGroups(check)) == 0 {
+			t.Errorf("%s (%T) is in no check group", check.Name(), check)
+		}
+	}
+	for profile, p := range doctor.Profiles {
+		for _, name := range p.Checks {
+			if !names[name] {
+				t.Errorf("profile %s names unknown check %s", profile, name)
+			}
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 var doltCmd = &cobra.Command{
@@ -27,10 +28,15 @@ The Dolt server provides multi-client access to all rig databases,
 avoiding the single-writer limitation of embedded Dolt mode.
 
 Server configuration:
-  - Port: 3307 (avoids conflict with MySQL on 3306)
+  - Port: 3307 (avoids conflict with MySQL on 3306). Towns sharing a host
+    each get their own: if another town's server holds the port, 'gt dolt
+    start' takes the next free one and records it in metadata.json
   - User: root (default Dolt user, no password for localhost)
   - Data directory: .dolt-data/ (contains all rig databases)
 
+To share one server across machines, point the town at it with
+'gt dolt connect <host[:port]>'.
+
 Each rig (hq, gastown, beads) has its own database subdirectory.`,
 }
 
@@ -45,7 +51,11 @@ where metadata.json says backend=dolt but the database is missing from .dolt-dat
 
 For each broken workspace, it will:
   1. Check if local .beads/dolt/ data exists and migrate it
-  2. Otherwise, create a fresh database in .dolt-data/
+  2. Otherwise, if .beads/issues.jsonl exists, create the database on the
+     running server and import it (kept as issues.jsonl.legacy)
+  3. Otherwise, create a fresh database in .dolt-data/
+
+'gt doctor --fix' runs the same repair (check dolt-missing-databases).
 
 This is safe to run multiple times (idempotent). It will not modify workspaces
 that are already healthy.`,
@@ -57,7 +67,17 @@ var doltStartCmd = &cobra.Command{
 	Short: "Start the Dolt server",
 	Long: `Start the Dolt SQL server in the background.
 
-The server will run until stopped with 'gt dolt stop'.`,
+The server will run until stopped with 'gt dolt stop'.
+
+With --supervise, a background supervisor restarts the server with backoff
+if it dies (crash, OOM kill) before 'gt dolt stop', instead of leaving
+every bd command failing until someone notices. If the server is already
+running, --supervise just starts the supervisor. Restarts are logged to
+daemon/dolt-restarts.log and published to the town event log.
+
+Examples:
+  gt dolt start
+  gt dolt start --supervise`,
 	RunE: runDoltStart,
 }
 
@@ -123,12 +143,30 @@ centralized .dolt-data/ directory structure.
 
 This command will:
 1. Detect existing dolt databases in .beads/dolt/ directories
-2. Move them to .dolt-data/<rigname>/
-3. Remove the old empty directories
+2. Count each table's rows
+3. Move them to .dolt-data/<rigname>/ (copying, with progress and an
+   estimate of the time left, when .dolt-data is on another filesystem)
+4. Check every table has the same row count after the move
+
+Up to --jobs databases (default 4) are migrated at once. Progress lines
+are prefixed with the rig's name, and each finished database reports an
+estimate of the time left for the whole run. A database that fails doesn't
+stop the others.
+
+Progress is journaled in .dolt-data/.migrating/. If a migration is
+interrupted, or a database fails, run 'gt dolt migrate' again: it continues
+where it stopped, skipping files that were already copied.
 
 Use --dry-run to preview what would be moved (source/target paths and sizes)
 without making any changes.
 
+Examples:
+  gt dolt migrate --dry-run
+  gt dolt migrate --jobs 8
+
+Rigs whose beads only exist in issues.jsonl have no database to move; use
+'gt dolt migrate-jsonl' for those.
+
 After migration, start the server with 'gt dolt start'.`,
 	RunE: runDoltMigrate,
 }
@@ -170,27 +208,50 @@ for immediate recovery without waiting for the daemon's health check loop.`,
 
 var doltSyncCmd = &cobra.Command{
 	Use:   "sync",
-	Short: "Push Dolt databases to DoltHub remotes",
-	Long: `Push all local Dolt databases to their configured DoltHub remotes.
+	Short: "Push and pull Dolt databases with their remotes",
+	Long: `Sync all local Dolt databases with their Dolt remotes (DoltHub or a
+self-hosted remotesrv).
 
-This command automates the tedious process of pushing each database individually:
-  1. Stops the Dolt server (required for CLI push)
+This command automates the tedious process of syncing each database individually:
+  1. Stops the Dolt server (required for CLI push/pull)
   2. Optionally purges closed ephemeral beads (--gc)
   3. Iterates databases in .dolt-data/
-  4. For each database with a configured remote, runs dolt push
-  5. Reports success/failure per database
-  6. Restarts the Dolt server
-
-Use --db to sync a single database, --dry-run to preview, or --force for force-push.
+  4. For each database with a remote, commits working changes, fetches
+     origin and reports how far main is ahead of and behind origin/main
+  5. Pulls remote commits, then pushes local ones
+  6. Reports success/failure per database
+  7. Restarts the Dolt server
+
+Remotes come from the database's origin, or from settings/dolt-remotes.json,
+which adds origin where it is missing:
+
+  {
+    "default": {"url": "https://doltremoteapi.dolthub.com/acme"},
+    "databases": {
+      "gastown": {"url": "http://dolt.internal:50051/{db}",
+                  "user": "gastown", "password_env": "GASTOWN_DOLT_REMOTE_PASSWORD"}
+    }
+  }
+
+A url without {db} gets the database name appended. Remotes with a user
+authenticate as that user, with the password read from the variable named
+by password_env; DoltHub remotes use the machine's 'dolt creds'. Without
+any configuration, DOLTHUB_TOKEN and DOLTHUB_ORG set up DoltHub remotes.
+
+Use --db to sync a single database, --dry-run to only fetch and report
+ahead/behind, or --push-only/--pull-only to sync one way. --force
+force-pushes, overwriting the remote instead of pulling from it.
 Use --gc to purge closed ephemeral beads (wisps, convoys) before pushing.
 
 Examples:
-  gt dolt sync                # Push all databases with remotes
-  gt dolt sync --dry-run      # Preview what would be pushed
-  gt dolt sync --db gastown   # Push only the gastown database
+  gt dolt sync                # Pull and push all databases with remotes
+  gt dolt sync --dry-run      # Show ahead/behind without changing anything
+  gt dolt sync --db gastown   # Sync only the gastown database
+  gt dolt sync --push-only    # Only push local commits
+  gt dolt sync --pull-only    # Only pull remote commits
   gt dolt sync --force        # Force-push all databases
-  gt dolt sync --gc           # Purge closed ephemeral beads, then push
-  gt dolt sync --gc --dry-run # Preview purge + push without changes`,
+  gt dolt sync --gc           # Purge closed ephemeral beads, then sync
+  gt dolt sync --gc --dry-run # Preview purge + sync without changes`,
 	RunE: runDoltSync,
 }
 
@@ -236,6 +297,7 @@ var (
 	doltLogLines     int
 	doltLogFollow    bool
 	doltMigrateDry   bool
+	doltMigrateJobs  int
 	doltCleanupDry   bool
 	doltRollbackDry  bool
 	doltRollbackList bool
@@ -243,6 +305,8 @@ var (
 	doltSyncForce    bool
 	doltSyncDB       string
 	doltSyncGC       bool
+	doltSyncPushOnly bool
+	doltSyncPullOnly bool
 )
 
 func init() {
@@ -267,14 +331,18 @@ func init() {
 	doltLogsCmd.Flags().BoolVarP(&doltLogFollow, "follow", "f", false, "Follow log output")
 
 	doltMigrateCmd.Flags().BoolVar(&doltMigrateDry, "dry-run", false, "Preview what would be migrated without making changes")
+	doltMigrateCmd.Flags().IntVarP(&doltMigrateJobs, "jobs", "j", doltserver.DefaultMigrateJobs, "Number of databases to migrate at once")
 
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackDry, "dry-run", false, "Show what would be restored without making changes")
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackList, "list", false, "List available backups and exit")
 
-	doltSyncCmd.Flags().BoolVar(&doltSyncDry, "dry-run", false, "Preview what would be pushed without pushing")
+	doltSyncCmd.Flags().BoolVar(&doltSyncDry, "dry-run", false, "Fetch and report ahead/behind without pulling or pushing")
 	doltSyncCmd.Flags().BoolVar(&doltSyncForce, "force", false, "Force-push to remotes")
 	doltSyncCmd.Flags().StringVar(&doltSyncDB, "db", "", "Sync a single database instead of all")
 	doltSyncCmd.Flags().BoolVar(&doltSyncGC, "gc", false, "Purge closed ephemeral beads before push (requires bd purge)")
+	doltSyncCmd.Flags().BoolVar(&doltSyncPushOnly, "push-only", false, "Push local commits without pulling")
+	doltSyncCmd.Flags().BoolVar(&doltSyncPullOnly, "pull-only", false, "Pull remote commits without pushing")
+	doltSyncCmd.MarkFlagsMutuallyExclusive("push-only", "pull-only")
 
 	rootCmd.AddCommand(doltCmd)
 }
@@ -298,6 +366,13 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
 	}
 
+	if doltStartSupervise {
+		if running, pid, _ := doltserver.IsRunning(townRoot); running {
+			fmt.Printf("%s Dolt server already running (PID %d)\n", style.Bold.Render("●"), pid)
+			return startDoltSupervisor(townRoot)
+		}
+	}
+
 	if err := doltserver.Start(townRoot); err != nil {
 		return err
 	}
@@ -328,6 +403,9 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s All %d databases verified\n", style.Bold.Render("✓"), len(served))
 	}
 
+	if doltStartSupervise {
+		return startDoltSupervisor(townRoot)
+	}
 	return nil
 }
 
@@ -344,6 +422,13 @@ func runDoltStop(cmd *cobra.Command, args []string) error {
 
 	_, pid, _ := doltserver.IsRunning(townRoot)
 
+	// Stop the supervisor first so it doesn't race the shutdown
+	if stopped, err := doltserver.StopSupervisor(townRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if stopped {
+		fmt.Printf("%s Dolt supervisor stopped\n", style.Bold.Render("✓"))
+	}
+
 	if err := doltserver.Stop(townRoot); err != nil {
 		return err
 	}
@@ -413,6 +498,7 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 			}
 			fmt.Printf("  Connection: %s\n", doltserver.GetConnectionString(townRoot))
 		}
+		printDoltSupervisorStatus(townRoot)
 
 		// Resource metrics
 		metrics := doltserver.GetHealthMetrics(townRoot)
@@ -644,6 +730,8 @@ func runDoltInit(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("!"), ws.RigName, ws.ConfiguredDB)
 		if ws.HasLocalData {
 			fmt.Printf("    Local data found at %s\n", style.Dim.Render(ws.LocalDataPath))
+		} else if ws.JSONLPath != "" {
+			fmt.Printf("    JSONL export found at %s\n", style.Dim.Render(ws.JSONLPath))
 		}
 
 		action, err := doltserver.RepairWorkspace(townRoot, ws)
@@ -770,18 +858,35 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Dolt server is running. Stop it first with: gt dolt stop")
 	}
 
-	// Find databases to migrate
-	migrations := doltserver.FindMigratableDatabases(townRoot)
-	if len(migrations) == 0 {
+	// Find databases to migrate, picking up an interrupted run first
+	resumed, err := doltserver.LoadMigrationJournal(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading migration journal: %w", err)
+	}
+	journal := doltserver.NewMigrationJournal(townRoot, doltserver.FindMigratableDatabases(townRoot), resumed)
+	if len(journal.Entries) == 0 {
+		if resumed != nil && !doltMigrateDry {
+			_ = journal.Finish()
+		}
 		fmt.Println("No databases found to migrate.")
 		return nil
 	}
 
-	fmt.Printf("Found %d database(s) to migrate:\n\n", len(migrations))
-	for _, m := range migrations {
-		sizeStr := dirSizeHuman(m.SourcePath)
-		fmt.Printf("  %s (%s)\n", m.SourcePath, sizeStr)
-		fmt.Printf("    → %s\n\n", m.TargetPath)
+	if resumed != nil {
+		fmt.Printf("Resuming migration started %s.\n\n", journal.Started.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("Found %d database(s) to migrate:\n\n", len(journal.Entries))
+	for _, e := range journal.Entries {
+		state := ""
+		if e.State != doltserver.MigrationPending {
+			state = " " + style.Dim.Render("["+string(e.State)+"]")
+		}
+		if _, err := os.Stat(e.SourcePath); err == nil {
+			fmt.Printf("  %s (%s)%s\n", e.SourcePath, dirSizeHuman(e.SourcePath), state)
+		} else {
+			fmt.Printf("  %s%s\n", e.SourcePath, state)
+		}
+		fmt.Printf("    → %s\n\n", e.TargetPath)
 	}
 
 	if doltMigrateDry {
@@ -790,21 +895,30 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Perform migrations
-	for _, m := range migrations {
-		fmt.Printf("Migrating %s...\n", m.RigName)
-		if err := doltserver.MigrateRigFromBeads(townRoot, m.RigName, m.SourcePath); err != nil {
-			return fmt.Errorf("migrating %s: %w", m.RigName, err)
-		}
-		fmt.Printf("  %s Migrated to %s\n", style.Bold.Render("✓"), m.TargetPath)
+	if doltMigrateJobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
 	}
-
-	// Update metadata.json for all migrated rigs
-	updated, metaErrs := doltserver.EnsureAllMetadata(townRoot)
-	if len(updated) > 0 {
-		fmt.Printf("\nUpdated metadata.json for: %s\n", strings.Join(updated, ", "))
+	if err := journal.Save(); err != nil {
+		return fmt.Errorf("writing migration journal: %w", err)
+	}
+	jobs := min(doltMigrateJobs, len(journal.Entries))
+	if jobs > 1 {
+		fmt.Printf("Migrating %d database(s), %d at a time...\n", len(journal.Entries), jobs)
 	}
-	for _, err := range metaErrs {
-		fmt.Printf("  %s metadata.json update failed: %v\n", style.Dim.Render("⚠"), err)
+	progress := newMigrateProgressPrinter(journal, jobs)
+	errs := doltserver.MigrateAll(townRoot, journal, jobs, progress.report)
+	progress.done()
+	if len(errs) > 0 {
+		fmt.Println()
+		for _, e := range journal.Entries {
+			if err := errs[e.RigName]; err != nil {
+				fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), e.RigName, err)
+			}
+		}
+		return fmt.Errorf("%d of %d database(s) failed to migrate\n\nProgress is saved; run 'gt dolt migrate' again to resume", len(errs), len(journal.Entries))
+	}
+	if err := journal.Finish(); err != nil {
+		fmt.Printf("  %s removing migration journal: %v\n", style.Dim.Render("⚠"), err)
 	}
 
 	fmt.Printf("\n%s Migration complete.\n", style.Bold.Render("✓"))
@@ -852,6 +966,94 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// migrateProgressPrinter prints doltserver.MigrationProgress: a line per
+// table and per finished database, and a copy progress line that updates in
+// place on a terminal (or every 10% otherwise). With migrations running side
+// by side, lines are prefixed with the rig's name and copy lines never
+// update in place, since they would overwrite each other.
+type migrateProgressPrinter struct {
+	tty      bool
+	parallel bool
+	width    int  // Longest rig name, to align prefixed lines
+	inline   bool // a copy line is waiting for its newline
+	lastTick map[string]int64
+	started  map[string]bool
+	targets  map[string]string
+}
+
+func newMigrateProgressPrinter(j *doltserver.MigrationJournal, jobs int) *migrateProgressPrinter {
+	p := &migrateProgressPrinter{
+		tty:      term.IsTerminal(int(os.Stdout.Fd())),
+		parallel: jobs > 1,
+		lastTick: make(map[string]int64),
+		started:  make(map[string]bool),
+		targets:  make(map[string]string),
+	}
+	for _, e := range j.Entries {
+		p.width = max(p.width, len(e.RigName))
+		p.targets[e.RigName] = e.TargetPath
+	}
+	return p
+}
+
+func (p *migrateProgressPrinter) report(mp doltserver.MigrationProgress) {
+	if !p.parallel && !p.started[mp.RigName] {
+		fmt.Printf("Migrating %s...\n", mp.RigName)
+	}
+	p.started[mp.RigName] = true
+
+	prefix := "  "
+	if p.parallel {
+		prefix = fmt.Sprintf("  %-*s ", p.width, mp.RigName)
+	}
+	eta := ""
+	if mp.Remaining > 0 {
+		eta = style.Dim.Render(fmt.Sprintf("  ~%s left", mp.Remaining.Round(time.Second)))
+	}
+	switch mp.Phase {
+	case "tables", "verify":
+		p.done()
+		verb := "counted"
+		if mp.Phase == "verify" {
+			verb = "verified"
+		}
+		fmt.Printf("%s[%d/%d] %-28s %8d rows %s%s\n", prefix, mp.TablesDone, mp.TablesTotal, mp.Table, mp.TableRows, verb, eta)
+	case "copy":
+		pct := int64(100)
+		if mp.BytesTotal > 0 {
+			pct = mp.BytesDone * 100 / mp.BytesTotal
+		}
+		line := fmt.Sprintf("%scopying %3d%% (%s / %s)%s", prefix, pct, formatBytes(mp.BytesDone), formatBytes(mp.BytesTotal), eta)
+		last, seen := p.lastTick[mp.RigName]
+		if p.tty && !p.parallel {
+			fmt.Printf("\r\033[K%s", line)
+			p.inline = true
+		} else if tick := pct / 10; !seen || tick != last {
+			p.lastTick[mp.RigName] = tick
+			fmt.Println(line)
+		}
+	case "done":
+		p.done()
+		overall := fmt.Sprintf("[%d/%d]", mp.RigsDone, mp.RigsTotal)
+		if mp.TotalRemaining > 0 {
+			overall += fmt.Sprintf(" ~%s left overall", mp.TotalRemaining.Round(time.Second))
+		}
+		if mp.Err != nil {
+			fmt.Printf("%s%s Failed: %v  %s\n", prefix, style.Error.Render("✗"), mp.Err, style.Dim.Render(overall))
+			return
+		}
+		fmt.Printf("%s%s Migrated to %s  %s\n", prefix, style.Bold.Render("✓"), p.targets[mp.RigName], style.Dim.Render(overall))
+	}
+}
+
+// done ends an in-place copy line.
+func (p *migrateProgressPrinter) done() {
+	if p.inline {
+		fmt.Println()
+		p.inline = false
+	}
+}
+
 // dirSizeHuman returns a human-readable size string for a directory tree.
 func dirSizeHuman(path string) string {
 	var total int64
@@ -1193,9 +1395,11 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := doltserver.SyncOptions{
-		Force:  doltSyncForce,
-		DryRun: doltSyncDry,
-		Filter: doltSyncDB,
+		Force:    doltSyncForce,
+		DryRun:   doltSyncDry,
+		Filter:   doltSyncDB,
+		PushOnly: doltSyncPushOnly,
+		PullOnly: doltSyncPullOnly,
 	}
 
 	results := doltserver.SyncDatabases(townRoot, opts)
@@ -1207,7 +1411,7 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\nSyncing %d database(s)...\n", len(results))
 
-	var pushed, skipped, failed, totalPurged int
+	var pushed, pulled, current, skipped, failed, totalPurged int
 	for _, r := range results {
 		fmt.Println()
 		// Show purge results if --gc was used
@@ -1225,26 +1429,40 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+		status := style.Dim.Render(syncAheadBehind(r))
 		switch {
-		case r.Pushed:
-			fmt.Printf("  %s %s → origin main\n", style.Bold.Render("✓"), r.Database)
-			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
-			pushed++
-		case r.DryRun:
-			fmt.Printf("  %s %s → origin main (dry run)\n", style.Bold.Render("~"), r.Database)
-			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
-			pushed++ // count as would-push for summary
-		case r.Skipped:
-			fmt.Printf("  %s %s — no remote configured\n", style.Dim.Render("○"), r.Database)
-			skipped++
 		case r.Error != nil:
-			fmt.Printf("  %s %s → origin main\n", style.Bold.Render("✗"), r.Database)
+			fmt.Printf("  %s %s ↔ origin main\n", style.Bold.Render("✗"), r.Database)
+			if r.Remote != "" {
+				fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
+			}
 			fmt.Printf("    error: %v\n", r.Error)
 			failed++
+		case r.Skipped:
+			fmt.Printf("  %s %s — no remote configured\n", style.Dim.Render("○"), r.Database)
+			skipped++
+		case r.DryRun:
+			fmt.Printf("  %s %s ↔ origin main %s (dry run)\n", style.Bold.Render("~"), r.Database, status)
+			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
+		case r.Pushed || r.Pulled:
+			var did []string
+			if r.Pulled {
+				did = append(did, "pulled")
+				pulled++
+			}
+			if r.Pushed {
+				did = append(did, "pushed")
+				pushed++
+			}
+			fmt.Printf("  %s %s ↔ origin main: %s %s\n", style.Bold.Render("✓"), r.Database, strings.Join(did, ", "), status)
+			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
+		default:
+			fmt.Printf("  %s %s ↔ origin main %s\n", style.Bold.Render("✓"), r.Database, status)
+			current++
 		}
 	}
 
-	summary := fmt.Sprintf("Summary: %d pushed, %d skipped, %d failed", pushed, skipped, failed)
+	summary := fmt.Sprintf("Summary: %d pushed, %d pulled, %d unchanged, %d skipped, %d failed", pushed, pulled, current, skipped, failed)
 	if doltSyncGC && totalPurged > 0 {
 		if doltSyncDry {
 			summary += fmt.Sprintf(", %d would be purged", totalPurged)
@@ -1260,4 +1478,17 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-
+// syncAheadBehind describes how a database's main compared with origin/main
+// before syncing.
+func syncAheadBehind(r doltserver.SyncResult) string {
+	switch {
+	case r.AddedRemote && r.DryRun:
+		return "(origin not added yet)"
+	case r.NewRemote:
+		return "(remote is empty)"
+	case r.Ahead == 0 && r.Behind == 0:
+		return "(up to date)"
+	default:
+		return fmt.Sprintf("(%d ahead, %d behind)", r.Ahead, r.Behind)
+	}
+}
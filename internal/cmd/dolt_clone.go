@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltCloneOrg     string
+	doltCloneDBs     []string
+	doltCloneDry     bool
+	doltCloneNoStart bool
+)
+
+var doltCloneCmd = &cobra.Command{
+	Use:   "clone [remote-url]",
+	Short: "Clone the town's Dolt databases onto this machine",
+	Long: `Set up the Dolt databases of a new machine in one step.
+
+Run this after cloning the town itself (so mayor/rigs.json lists the rigs):
+  1. Clones hq and each rig's database into .dolt-data/ from a Dolt remote
+  2. Writes metadata.json for hq and every rig so bd uses the server
+  3. Starts the Dolt server and verifies it serves every database
+
+Each database is cloned from <remote-url>/<database>. The remote can be any
+Dolt remote: a directory (file:///mnt/dolt-remotes), cloud storage, or
+another machine's sql-server remotesapi (http://relay:50051). With --org,
+databases are cloned from DoltHub, from the repos 'gt dolt sync' pushes to
+(DOLTHUB_ORG is the default org when no remote is given).
+
+Databases already in .dolt-data/ are kept, so the command can be re-run
+after a partial failure. Rigs mapped to their own server (gt dolt connect
+--rig) are not cloned; their server is only checked for reachability.
+
+Examples:
+  gt dolt clone http://relay.lan:50051
+  gt dolt clone file:///mnt/backup/dolt-remotes --db hq --db gastown
+  gt dolt clone --org my-town --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoltClone,
+}
+
+func init() {
+	doltCloneCmd.Flags().StringVar(&doltCloneOrg, "org", "", "Clone from this DoltHub organization")
+	doltCloneCmd.Flags().StringSliceVar(&doltCloneDBs, "db", nil, "Clone only these databases (repeatable)")
+	doltCloneCmd.Flags().BoolVar(&doltCloneDry, "dry-run", false, "Show what would be cloned without cloning")
+	doltCloneCmd.Flags().BoolVar(&doltCloneNoStart, "no-start", false, "Don't start the Dolt server afterwards")
+	doltCmd.AddCommand(doltCloneCmd)
+}
+
+func runDoltClone(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	config := doltserver.DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — clone sets up a local server; use 'gt dolt connect --local' first", config.HostPort())
+	}
+
+	opts := doltserver.CloneOptions{
+		DoltHubOrg: doltCloneOrg,
+		Databases:  doltCloneDBs,
+		DryRun:     doltCloneDry,
+	}
+	switch {
+	case len(args) > 0 && doltCloneOrg != "":
+		return fmt.Errorf("give either a remote URL or --org, not both")
+	case len(args) > 0:
+		opts.Remote = args[0]
+	case doltCloneOrg == "":
+		if opts.DoltHubOrg = doltserver.DoltHubOrg(); opts.DoltHubOrg == "" {
+			return fmt.Errorf("no remote given: pass a remote URL or --org (or set DOLTHUB_ORG)")
+		}
+	}
+
+	if running, pid, _ := doltserver.IsRunning(townRoot); running && !doltCloneDry {
+		// The server only picks up databases at startup.
+		fmt.Printf("Stopping Dolt server (PID %d)...\n", pid)
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+		fmt.Printf("%s Dolt server stopped\n", style.Bold.Render("✓"))
+	}
+
+	results := doltserver.CloneDatabases(townRoot, opts)
+	fmt.Printf("\nCloning %d database(s)...\n\n", len(results))
+
+	var cloned, existing, failed int
+	for _, r := range results {
+		switch {
+		case r.Cloned:
+			fmt.Printf("  %s %s\n", style.Bold.Render("✓"), r.Database)
+			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
+			cloned++
+		case r.DryRun:
+			fmt.Printf("  %s %s (dry run)\n", style.Bold.Render("~"), r.Database)
+			fmt.Printf("    %s\n", style.Dim.Render(r.Remote))
+			cloned++ // count as would-clone for summary
+		case r.Exists:
+			fmt.Printf("  %s %s — already in .dolt-data/\n", style.Dim.Render("○"), r.Database)
+			existing++
+		case r.Error != nil:
+			fmt.Printf("  %s %s\n", style.Bold.Render("✗"), r.Database)
+			fmt.Printf("    error: %v\n", r.Error)
+			failed++
+		}
+	}
+	fmt.Printf("\nSummary: %d cloned, %d already present, %d failed\n", cloned, existing, failed)
+	if doltCloneDry {
+		return nil
+	}
+
+	// Point bd at the server for hq and every rig, including rigs on their own server.
+	var metaFailed []string
+	for _, db := range doltserver.TownDatabases(townRoot) {
+		if err := doltserver.EnsureMetadata(townRoot, db.Rig); err != nil {
+			style.PrintWarning("metadata.json for %s: %v", db.Rig, err)
+			metaFailed = append(metaFailed, db.Rig)
+		}
+	}
+	servers, err := doltserver.LoadRigServers(townRoot)
+	if err != nil {
+		style.PrintWarning("%v", err)
+		servers = &doltserver.RigServers{}
+	}
+	for _, rigName := range servers.Names() {
+		if err := doltserver.EnsureMetadata(townRoot, rigName); err != nil {
+			style.PrintWarning("metadata.json for %s: %v", rigName, err)
+			metaFailed = append(metaFailed, rigName)
+		}
+	}
+	if len(metaFailed) == 0 {
+		fmt.Printf("%s metadata.json written for hq and all rigs\n", style.Bold.Render("✓"))
+	}
+
+	if !doltCloneNoStart {
+		fmt.Printf("\nStarting Dolt server...\n")
+		if err := doltserver.Start(townRoot); err != nil {
+			return fmt.Errorf("starting Dolt server: %w", err)
+		}
+		if err := doltserver.CheckServerReachable(townRoot); err != nil {
+			return err
+		}
+		fmt.Printf("%s Dolt server accepting connections on %s\n", style.Bold.Render("✓"), config.HostPort())
+
+		_, missing, err := doltserver.VerifyDatabasesWithRetry(townRoot, 3)
+		if err != nil {
+			style.PrintWarning("could not verify served databases: %v", err)
+		} else if len(missing) > 0 {
+			fmt.Printf("%s Not served: %s\n", style.WarningPrefix, strings.Join(missing, ", "))
+			failed += len(missing)
+		}
+	}
+	for _, rigName := range servers.Names() {
+		if err := doltserver.CheckRigServerReachable(townRoot, rigName); err != nil {
+			style.PrintWarning("%v", err)
+			failed++
+		} else {
+			fmt.Printf("%s Rig %s server reachable at %s\n", style.Bold.Render("✓"), rigName, doltserver.ConfigForRig(townRoot, rigName).HostPort())
+		}
+	}
+
+	if failed > 0 || len(metaFailed) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
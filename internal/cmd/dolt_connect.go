@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltConnectUser        string
+	doltConnectLocal       bool
+	doltConnectForce       bool
+	doltConnectRig         string
+	doltConnectTLS         bool
+	doltConnectPasswordEnv string
+)
+
+var doltConnectCmd = &cobra.Command{
+	Use:   "connect [host[:port]]",
+	Short: "Point this town at a remote Dolt server",
+	Long: `Configure the town to use a Dolt sql-server on another machine, so several
+machines can share one server.
+
+The endpoint is stored in the town's .beads/metadata.json and copied into
+every rig's metadata.json, where bd reads it. GT_DOLT_HOST, GT_DOLT_PORT and
+GT_DOLT_USER still override it for a single shell.
+
+Passwords are never written to metadata.json. Export GT_DOLT_PASSWORD;
+gt forwards it to bd as BEADS_DOLT_PASSWORD.
+
+The server must be reachable (skip the check with --force). Databases are
+not copied: rigs whose database the remote server does not serve are
+listed so you can push them there first.
+
+With no argument, shows the current endpoint. Use --local to return to a
+local server started with 'gt dolt start'.
+
+Per-rig servers:
+  A rig that lives on another machine can keep its database on a Dolt
+  server there. --rig maps just that rig, in settings/dolt-servers.json;
+  bd and gt then connect to that server for the rig's beads while other
+  rigs keep using the town's server. --tls requires an encrypted
+  connection. The password is read from the variable named by
+  --password-env (default GT_DOLT_PASSWORD_<RIG>); GT_DOLT_PASSWORD is
+  never sent to a rig's own server. --rig with --local removes the mapping.
+
+Examples:
+  gt dolt connect dolt.lan
+  gt dolt connect 10.0.0.5:3307 --user gastown
+  gt dolt connect --local
+  gt dolt connect --rig beta build-02.lan:3307 --tls
+  gt dolt connect --rig beta --local`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoltConnect,
+}
+
+func init() {
+	doltConnectCmd.Flags().StringVar(&doltConnectUser, "user", "", "MySQL user for the remote server (default: root)")
+	doltConnectCmd.Flags().BoolVar(&doltConnectLocal, "local", false, "Clear the remote endpoint and use a local server")
+	doltConnectCmd.Flags().BoolVar(&doltConnectForce, "force", false, "Save the endpoint even if the server is unreachable")
+	doltConnectCmd.Flags().StringVar(&doltConnectRig, "rig", "", "Map only this rig to the server")
+	doltConnectCmd.Flags().BoolVar(&doltConnectTLS, "tls", false, "Require TLS (with --rig)")
+	doltConnectCmd.Flags().StringVar(&doltConnectPasswordEnv, "password-env", "", "Variable holding the rig server's password (with --rig; default GT_DOLT_PASSWORD_<RIG>)")
+	doltCmd.AddCommand(doltConnectCmd)
+}
+
+func runDoltConnect(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doltConnectRig != "" {
+		return runDoltConnectRig(townRoot, doltConnectRig, args)
+	}
+	if doltConnectTLS || doltConnectPasswordEnv != "" {
+		return fmt.Errorf("--tls and --password-env apply to a rig's own server; use them with --rig")
+	}
+
+	if doltConnectLocal {
+		if len(args) > 0 {
+			return fmt.Errorf("--local takes no address")
+		}
+		return applyDoltEndpoint(townRoot, doltserver.ServerEndpoint{})
+	}
+
+	if len(args) == 0 {
+		config := doltserver.DefaultConfig(townRoot)
+		if config.IsRemote() {
+			fmt.Printf("Dolt server: %s (remote, user %s)\n", style.Bold.Render(config.HostPort()), config.User)
+		} else {
+			fmt.Printf("Dolt server: %s (local)\n", style.Bold.Render(config.HostPort()))
+		}
+		return printRigServers(townRoot)
+	}
+
+	ep, err := doltserver.ParseHostPort(args[0])
+	if err != nil {
+		return err
+	}
+	ep.User = doltConnectUser
+	if !(&doltserver.Config{Host: ep.Host}).IsRemote() {
+		return fmt.Errorf("%s is a local address; use 'gt dolt connect --local'", ep.Host)
+	}
+	return applyDoltEndpoint(townRoot, ep)
+}
+
+// applyDoltEndpoint saves the endpoint, verifies it unless forced, and
+// propagates it to every rig's metadata.json.
+func applyDoltEndpoint(townRoot string, ep doltserver.ServerEndpoint) error {
+	previous := doltserver.LoadServerEndpoint(townRoot)
+	if err := doltserver.SetServerEndpoint(townRoot, ep); err != nil {
+		return err
+	}
+
+	config := doltserver.DefaultConfig(townRoot)
+	if err := doltserver.CheckServerReachable(townRoot); err != nil {
+		if !doltConnectForce {
+			// Leave the town as it was rather than pointing bd at a dead server.
+			_ = doltserver.SetServerEndpoint(townRoot, previous)
+			return fmt.Errorf("%w\n\nUse --force to save the endpoint anyway", err)
+		}
+		style.PrintWarning("saved unreachable endpoint: %v", err)
+	}
+
+	updated, errs := doltserver.EnsureAllMetadata(townRoot)
+	for _, err := range errs {
+		style.PrintWarning("metadata.json update failed: %v", err)
+	}
+
+	if config.IsRemote() {
+		fmt.Printf("%s Using remote Dolt server %s\n", style.SuccessPrefix, style.Bold.Render(config.HostPort()))
+	} else {
+		fmt.Printf("%s Using local Dolt server %s\n", style.SuccessPrefix, style.Bold.Render(config.HostPort()))
+	}
+	if len(updated) > 0 {
+		fmt.Printf("  Updated metadata.json: %s\n", strings.Join(updated, ", "))
+	}
+
+	if config.IsRemote() {
+		if missing := rigsMissingFromServer(townRoot, updated); len(missing) > 0 {
+			fmt.Printf("\n%s Not served by %s (push these databases there):\n", style.WarningPrefix, config.HostPort())
+			for _, name := range missing {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+	}
+	return nil
+}
+
+// rigsMissingFromServer returns server-mode rigs whose database the server
+// does not serve.
+func rigsMissingFromServer(townRoot string, served []string) []string {
+	have := make(map[string]bool, len(served))
+	for _, db := range served {
+		have[db] = true
+	}
+	var missing []string
+	for _, rigName := range doltserver.HasServerModeMetadata(townRoot) {
+		if !have[rigName] {
+			missing = append(missing, rigName)
+		}
+	}
+	return missing
+}
+
+// runDoltConnectRig shows, sets or removes (--local) rigName's own server.
+func runDoltConnectRig(townRoot, rigName string, args []string) error {
+	if _, _, err := getRig(rigName); err != nil {
+		return err
+	}
+	servers, err := doltserver.LoadRigServers(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 && !doltConnectLocal {
+		config := doltserver.ConfigForRig(townRoot, rigName)
+		if _, ok := servers.Rigs[rigName]; !ok {
+			fmt.Printf("Rig %s: town server %s\n", rigName, style.Bold.Render(config.HostPort()))
+			return nil
+		}
+		fmt.Printf("Rig %s: %s\n", rigName, describeRigServer(servers.Rigs[rigName], config))
+		return nil
+	}
+
+	previous, hadPrevious := servers.Rigs[rigName]
+	if doltConnectLocal {
+		if len(args) > 0 {
+			return fmt.Errorf("--local takes no address")
+		}
+		if !hadPrevious {
+			fmt.Printf("Rig %s already uses the town server\n", rigName)
+			return nil
+		}
+		delete(servers.Rigs, rigName)
+	} else {
+		ep, err := doltserver.ParseHostPort(args[0])
+		if err != nil {
+			return err
+		}
+		if !(&doltserver.Config{Host: ep.Host}).IsRemote() {
+			return fmt.Errorf("%s is a local address; use 'gt dolt connect --rig %s --local' to use the town server", ep.Host, rigName)
+		}
+		ep.User = doltConnectUser
+		ep.TLS = doltConnectTLS
+		ep.PasswordEnv = doltConnectPasswordEnv
+		if ep.PasswordEnv == "" {
+			ep.PasswordEnv = rigPasswordEnv(rigName)
+		}
+		servers.Rigs[rigName] = ep
+	}
+	if err := doltserver.SaveRigServers(townRoot, servers); err != nil {
+		return err
+	}
+
+	if _, mapped := servers.Rigs[rigName]; mapped {
+		if err := doltserver.CheckRigServerReachable(townRoot, rigName); err != nil {
+			if !doltConnectForce {
+				// Leave the rig as it was rather than pointing bd at a dead server.
+				if hadPrevious {
+					servers.Rigs[rigName] = previous
+				} else {
+					delete(servers.Rigs, rigName)
+				}
+				_ = doltserver.SaveRigServers(townRoot, servers)
+				return fmt.Errorf("%w\n\nUse --force to save the endpoint anyway", err)
+			}
+			style.PrintWarning("saved unreachable endpoint: %v", err)
+		}
+	}
+
+	if err := doltserver.EnsureMetadata(townRoot, rigName); err != nil {
+		style.PrintWarning("metadata.json update failed: %v", err)
+	}
+
+	config := doltserver.ConfigForRig(townRoot, rigName)
+	if ep, mapped := servers.Rigs[rigName]; mapped {
+		fmt.Printf("%s Rig %s uses %s\n", style.SuccessPrefix, rigName, describeRigServer(ep, config))
+		if os.Getenv(ep.PasswordEnv) == "" {
+			fmt.Printf("  Export %s with the server's password if it needs one\n", ep.PasswordEnv)
+		}
+	} else {
+		fmt.Printf("%s Rig %s uses the town server %s\n", style.SuccessPrefix, rigName, style.Bold.Render(config.HostPort()))
+	}
+	return nil
+}
+
+// printRigServers lists the rigs mapped to their own server, if any.
+func printRigServers(townRoot string) error {
+	servers, err := doltserver.LoadRigServers(townRoot)
+	if err != nil {
+		return err
+	}
+	for _, rigName := range servers.Names() {
+		config := doltserver.ConfigForRig(townRoot, rigName)
+		fmt.Printf("  %s %s\n", style.Dim.Render(rigName+":"), describeRigServer(servers.Rigs[rigName], config))
+	}
+	return nil
+}
+
+// describeRigServer renders a rig's own server for display.
+func describeRigServer(ep doltserver.ServerEndpoint, config *doltserver.Config) string {
+	parts := []string{"user " + config.User}
+	if ep.TLS {
+		parts = append(parts, "TLS")
+	}
+	if ep.PasswordEnv != "" {
+		parts = append(parts, "password from $"+ep.PasswordEnv)
+	}
+	return fmt.Sprintf("%s (%s)", style.Bold.Render(config.HostPort()), strings.Join(parts, ", "))
+}
+
+// rigPasswordEnv returns the default password variable for a rig's own
+// server, e.g. GT_DOLT_PASSWORD_MY_RIG for my-rig.
+func rigPasswordEnv(rigName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, rigName)
+	return "GT_DOLT_PASSWORD_" + name
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltGCAll    bool
+	doltGCDB     string
+	doltGCDryRun bool
+	doltDUDB     string
+	doltDUJSON   bool
+)
+
+var doltGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Garbage-collect Dolt databases to reclaim disk space",
+	Long: `Run dolt gc on local Dolt databases.
+
+Dolt keeps every chunk it has ever written until garbage collection, so
+databases grow without bound under agent write load. This command:
+  1. Stops the Dolt server (pauses all writers; dolt gc needs exclusive access)
+  2. Runs dolt gc on each selected database
+  3. Reports the space reclaimed per database
+  4. Restarts the Dolt server
+
+bd commands fail while the server is down, so run this when the town is quiet.
+Use --dry-run to see how much space could be reclaimed without stopping anything.
+
+Examples:
+  gt dolt gc --all            # Collect every database
+  gt dolt gc --db gastown     # Collect only the gastown database
+  gt dolt gc --all --dry-run  # Show reclaimable space per database`,
+	Args: cobra.NoArgs,
+	RunE: runDoltGC,
+}
+
+var doltDUCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show per-database disk usage and reclaimable space",
+	Long: `Report the on-disk size of each local Dolt database.
+
+Columns:
+  SIZE         Total size of the database directory
+  LIVE         Chunks compacted by the last gc
+  RECLAIMABLE  Upper bound on what 'gt dolt gc' would free (chunks
+               written since the last gc, some of which are still live)
+
+Examples:
+  gt dolt du
+  gt dolt du --db gastown
+  gt dolt du --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltDU,
+}
+
+func init() {
+	doltGCCmd.Flags().BoolVar(&doltGCAll, "all", false, "Collect every database")
+	doltGCCmd.Flags().StringVar(&doltGCDB, "db", "", "Collect a single database")
+	doltGCCmd.Flags().BoolVar(&doltGCDryRun, "dry-run", false, "Show reclaimable space without running gc")
+	doltDUCmd.Flags().StringVar(&doltDUDB, "db", "", "Report a single database")
+	doltDUCmd.Flags().BoolVar(&doltDUJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltGCCmd)
+	doltCmd.AddCommand(doltDUCmd)
+}
+
+func runDoltGC(cmd *cobra.Command, args []string) error {
+	if doltGCAll == (doltGCDB != "") {
+		return fmt.Errorf("specify exactly one of --all or --db <name>")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	config := doltserver.DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — gc requires local server access", config.HostPort())
+	}
+	if doltGCDB != "" && !doltserver.DatabaseExists(townRoot, doltGCDB) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", doltGCDB)
+	}
+
+	if doltGCDryRun {
+		usage, err := doltserver.DiskUsage(townRoot, doltGCDB)
+		if err != nil {
+			return err
+		}
+		var total int64
+		for _, u := range usage {
+			fmt.Printf("  %s %s: up to %s reclaimable (%s on disk)\n",
+				style.Bold.Render("~"), u.Database, formatBytes(u.ReclaimableBytes), formatBytes(u.Bytes))
+			total += u.ReclaimableBytes
+		}
+		fmt.Printf("\nDry run: gc could reclaim up to %s. Nothing changed.\n", formatBytes(total))
+		return nil
+	}
+
+	wasRunning, pid, _ := doltserver.IsRunning(townRoot)
+	if wasRunning {
+		fmt.Printf("Stopping Dolt server (PID %d) to pause writers...\n", pid)
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+		fmt.Printf("%s Dolt server stopped\n", style.Bold.Render("✓"))
+
+		// Guarantee restart even if gc fails
+		defer func() {
+			fmt.Printf("\nRestarting Dolt server...\n")
+			if startErr := doltserver.Start(townRoot); startErr != nil {
+				fmt.Printf("%s Failed to restart Dolt server: %v\n", style.Bold.Render("✗"), startErr)
+				fmt.Printf("  Start manually with: %s\n", style.Dim.Render("gt dolt start"))
+				return
+			}
+			fmt.Printf("%s Dolt server restarted (accepting connections)\n", style.Bold.Render("✓"))
+		}()
+	}
+
+	results := doltserver.GCDatabases(townRoot, doltGCDB)
+	if len(results) == 0 {
+		fmt.Println("No databases to collect.")
+		return nil
+	}
+
+	fmt.Printf("\nCollecting %d database(s)...\n\n", len(results))
+	var failed int
+	var reclaimed int64
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Printf("  %s %s: %v\n", style.Bold.Render("✗"), r.Database, r.Error)
+			failed++
+			continue
+		}
+		freed := r.Before - r.After
+		if freed < 0 {
+			freed = 0
+		}
+		reclaimed += freed
+		fmt.Printf("  %s %s: %s → %s (freed %s)\n", style.Bold.Render("✓"), r.Database,
+			formatBytes(r.Before), formatBytes(r.After), formatBytes(freed))
+	}
+
+	fmt.Printf("\nReclaimed %s", formatBytes(reclaimed))
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to gc", failed)
+	}
+	return nil
+}
+
+func runDoltDU(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doltDUDB != "" && !doltserver.DatabaseExists(townRoot, doltDUDB) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", doltDUDB)
+	}
+
+	usage, err := doltserver.DiskUsage(townRoot, doltDUDB)
+	if err != nil {
+		return err
+	}
+
+	if doltDUJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usage)
+	}
+
+	if len(usage) == 0 {
+		fmt.Println("No databases found.")
+		return nil
+	}
+
+	var total, reclaimable int64
+	fmt.Printf("%-20s %10s %10s %12s\n", "DATABASE", "SIZE", "LIVE", "RECLAIMABLE")
+	for _, u := range usage {
+		fmt.Printf("%-20s %10s %10s %12s\n", u.Database,
+			formatBytes(u.Bytes), formatBytes(u.LiveBytes), formatBytes(u.ReclaimableBytes))
+		total += u.Bytes
+		reclaimable += u.ReclaimableBytes
+	}
+	fmt.Printf("%-20s %10s %10s %12s\n", "TOTAL", formatBytes(total), "", formatBytes(reclaimable))
+
+	if reclaimable > 0 {
+		fmt.Printf("\nReclaim with: %s\n", style.Dim.Render("gt dolt gc --all"))
+	}
+	return nil
+}
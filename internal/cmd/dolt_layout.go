@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltLayoutDatabase string
+	doltLayoutAll      bool
+	doltLayoutForce    bool
+)
+
+var doltLayoutCmd = &cobra.Command{
+	Use:   "layout",
+	Short: "Show or change how rigs' databases are laid out",
+	Long: `Show how the rigs' beads are stored on the Dolt server.
+
+By default each rig has a database of its own, and the server keeps every
+one of them open. In the branch-per-rig layout, rigs instead live on
+branches of one shared database (named "town" unless set otherwise), one
+branch per rig, which takes far less server memory in towns with many rigs.
+A rig's metadata.json then names the revision database "town/<rig>", which
+routes bd and gt to its branch.
+
+hq and rigs on their own server (gt dolt connect --rig) always keep their
+own database. Branched rigs write to their branch directly: polecats get no
+branches of their own.
+
+The layout is stored in settings/dolt-layout.json.
+
+Examples:
+  gt dolt layout                          # Show each rig's storage
+  gt dolt layout set branch-per-rig       # New rigs get a branch
+  gt dolt layout migrate gastown          # Move an existing rig to a branch
+  gt dolt layout migrate --all`,
+	Args: cobra.NoArgs,
+	RunE: runDoltLayout,
+}
+
+var doltLayoutSetCmd = &cobra.Command{
+	Use:   "set <database-per-rig|branch-per-rig>",
+	Short: "Choose the layout new rigs get",
+	Long: `Choose whether rigs added from now on get a database of their own
+(database-per-rig) or a branch of the shared database (branch-per-rig).
+Existing rigs are not moved; see 'gt dolt layout migrate'.
+
+--database names the shared database; it can only be changed while no rig
+is on a branch.
+
+Examples:
+  gt dolt layout set branch-per-rig
+  gt dolt layout set branch-per-rig --database rigs`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDoltLayoutSet,
+}
+
+var doltLayoutMigrateCmd = &cobra.Command{
+	Use:   "migrate [rig...]",
+	Short: "Move rigs from their own database onto branches of the shared database",
+	Long: `Move each rig's beads, with their history, from its own database onto
+a branch of the shared database, and route the rig there. Works with the
+server running or stopped.
+
+Park the rigs first (gt rig park): writes made to a rig's old database
+during the move are lost. A database with branches besides main, left by
+polecats still at work, is refused unless --force, which leaves those
+branches behind.
+
+The old databases are kept but no longer used; once the rigs check out,
+remove them with 'gt dolt cleanup'.
+
+Examples:
+  gt dolt layout migrate gastown beads
+  gt dolt layout migrate --all`,
+	RunE: runDoltLayoutMigrate,
+}
+
+func init() {
+	doltLayoutSetCmd.Flags().StringVar(&doltLayoutDatabase, "database", "", "Name of the shared database (default: town)")
+	doltLayoutMigrateCmd.Flags().BoolVar(&doltLayoutAll, "all", false, "Migrate every rig that can be")
+	doltLayoutMigrateCmd.Flags().BoolVar(&doltLayoutForce, "force", false, "Migrate even if the database has branches besides main")
+
+	doltLayoutCmd.AddCommand(doltLayoutSetCmd)
+	doltLayoutCmd.AddCommand(doltLayoutMigrateCmd)
+	doltCmd.AddCommand(doltLayoutCmd)
+}
+
+func runDoltLayout(cmd *cobra.Command, args []string) error {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	layout, err := doltserver.LoadLayout(townRoot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Layout: %s (new rigs)\n", style.Bold.Render(layout.Mode))
+	if len(layout.Rigs) > 0 || layout.Mode == doltserver.LayoutBranchPerRig {
+		fmt.Printf("Shared database: %s\n", style.Bold.Render(layout.Database))
+	}
+	fmt.Println()
+	for _, r := range rigs {
+		db := doltserver.RigDatabaseName(townRoot, r.Name)
+		if layout.IsBranched(r.Name) {
+			fmt.Printf("  %-20s branch %s of %s\n", r.Name, r.Name, layout.Database)
+		} else {
+			fmt.Printf("  %-20s %s\n", r.Name, style.Dim.Render("database "+db))
+		}
+	}
+	return nil
+}
+
+func runDoltLayoutSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	layout, err := doltserver.LoadLayout(townRoot)
+	if err != nil {
+		return err
+	}
+
+	layout.Mode = args[0]
+	if doltLayoutDatabase != "" && doltLayoutDatabase != layout.Database {
+		if len(layout.Rigs) > 0 {
+			return fmt.Errorf("%d rig(s) are on branches of %s; the shared database can't be renamed", len(layout.Rigs), layout.Database)
+		}
+		layout.Database = doltLayoutDatabase
+	}
+	if err := doltserver.SaveLayout(townRoot, layout); err != nil {
+		return err
+	}
+
+	if layout.Mode == doltserver.LayoutBranchPerRig {
+		fmt.Printf("%s New rigs get a branch of %s\n", style.SuccessPrefix, style.Bold.Render(layout.Database))
+		fmt.Printf("  Move existing rigs with: %s\n", style.Dim.Render("gt dolt layout migrate --all"))
+	} else {
+		fmt.Printf("%s New rigs get a database of their own\n", style.SuccessPrefix)
+		if len(layout.Rigs) > 0 {
+			fmt.Printf("  %d rig(s) stay on branches of %s\n", len(layout.Rigs), layout.Database)
+		}
+	}
+	return nil
+}
+
+func runDoltLayoutMigrate(cmd *cobra.Command, args []string) error {
+	if doltLayoutAll == (len(args) > 0) {
+		return fmt.Errorf("name the rigs to migrate, or pass --all")
+	}
+	names := args
+	var townRoot string
+	if doltLayoutAll {
+		rigs, root, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		townRoot = root
+		layout, err := doltserver.LoadLayout(townRoot)
+		if err != nil {
+			return err
+		}
+		servers, err := doltserver.LoadRigServers(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, r := range rigs {
+			if _, own := servers.Rigs[r.Name]; !own && !layout.IsBranched(r.Name) {
+				names = append(names, r.Name)
+			}
+		}
+	} else {
+		for _, name := range names {
+			root, _, err := getRig(name)
+			if err != nil {
+				return err
+			}
+			townRoot = root
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println(style.Dim.Render("Every rig is already on a branch"))
+		return nil
+	}
+
+	var failed int
+	for _, name := range names {
+		fmt.Printf("Migrating %s...\n", style.Bold.Render(name))
+		if err := doltserver.MigrateRigToBranch(townRoot, name, doltLayoutForce); err != nil {
+			style.PrintWarning("%s: %v", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s is on its branch\n", style.SuccessPrefix, name)
+	}
+
+	if failed < len(names) {
+		fmt.Printf("\nThe rigs' old databases are kept; once the rigs check out, remove them with %s\n",
+			style.Dim.Render("gt dolt cleanup"))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rig(s) not migrated", failed, len(names))
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Dolt migrate-jsonl command flags
+var (
+	doltMigrateJSONLDry    bool
+	doltMigrateJSONLVerify bool
+)
+
+var doltMigrateJSONLCmd = &cobra.Command{
+	Use:   "migrate-jsonl [rig]",
+	Short: "Migrate rigs whose beads only exist in issues.jsonl to Dolt",
+	Long: `Import the beads of rigs that never had a SQLite or Dolt database -
+only .beads/issues.jsonl - into a new database on the Dolt server.
+
+For each rig (or every such rig, if none is named):
+  1. issues.jsonl is moved aside to issues.jsonl.legacy and kept
+  2. A server database is created and metadata.json pointed at it
+  3. Every issue is imported under its original ID
+  4. Relations are rebuilt: parent-child and blocks edges, other edge
+     types (related, discovered-from, ...), and parents of hierarchical
+     IDs (gt-abc.1 → gt-abc) that recorded none
+  5. The database is exported and diffed against issues.jsonl.legacy
+
+Relations to issues outside the file are reported and skipped. Use
+--dry-run to see what would be imported, and --verify to re-run the diff
+for an already migrated rig.
+
+The daemon must be stopped first; the Dolt server is started if needed.
+
+Examples:
+  gt dolt migrate-jsonl --dry-run
+  gt dolt migrate-jsonl oldrig
+  gt dolt migrate-jsonl oldrig --verify`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoltMigrateJSONL,
+}
+
+func init() {
+	doltMigrateJSONLCmd.Flags().BoolVar(&doltMigrateJSONLDry, "dry-run", false, "Show what would be imported without changing anything")
+	doltMigrateJSONLCmd.Flags().BoolVar(&doltMigrateJSONLVerify, "verify", false, "Diff a migrated rig's database against its preserved issues.jsonl")
+	doltCmd.AddCommand(doltMigrateJSONLCmd)
+}
+
+func runDoltMigrateJSONL(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doltMigrateJSONLVerify {
+		if len(args) == 0 {
+			return fmt.Errorf("--verify needs a rig name")
+		}
+		m := doltserver.JSONLMigration{
+			RigName:  args[0],
+			BeadsDir: beads.ResolveBeadsDir(filepath.Join(townRoot, args[0])),
+		}
+		plan, err := loadLegacyPlan(m.PreservedPath())
+		if err != nil {
+			return err
+		}
+		return verifyJSONLMigration(townRoot, m, plan)
+	}
+
+	var migrations []doltserver.JSONLMigration
+	if len(args) > 0 {
+		if _, _, err := getRig(args[0]); err != nil {
+			return err
+		}
+		m, ok := doltserver.JSONLOnlyRig(townRoot, args[0])
+		if !ok {
+			return fmt.Errorf("rig %s already has a beads database, or no issues.jsonl to migrate", args[0])
+		}
+		migrations = append(migrations, m)
+	} else {
+		migrations = doltserver.FindJSONLOnlyRigs(townRoot)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("No JSONL-only rigs found.")
+		return nil
+	}
+
+	plans := make([]*beads.LegacyPlan, len(migrations))
+	for i, m := range migrations {
+		plan, err := loadLegacyPlan(m.SourcePath)
+		if err != nil {
+			return err
+		}
+		plans[i] = plan
+		fmt.Printf("%s %s\n", style.Bold.Render(m.RigName), style.Dim.Render(m.SourcePath))
+		fmt.Printf("  %d issues, %d relations", len(plan.Records), plan.RelationCount())
+		if len(plan.Inferred) > 0 {
+			fmt.Printf(" (%d inferred from hierarchical IDs)", len(plan.Inferred))
+		}
+		fmt.Println()
+		for _, d := range plan.Dangling {
+			fmt.Printf("  %s %s %s %s: not in the file, skipped\n", style.Dim.Render("⚠"), d.IssueID, d.Type, d.DependsOnID)
+		}
+	}
+
+	if doltMigrateJSONLDry {
+		fmt.Println("\nDry run: no changes made.")
+		return nil
+	}
+
+	if cfg := doltserver.DefaultConfig(townRoot); cfg.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — migration requires local server access", cfg.HostPort())
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); running {
+		return fmt.Errorf("Gas Town daemon is running. Stop it first with: gt daemon stop")
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		fmt.Println("\nStarting Dolt server...")
+		if err := doltserver.Start(townRoot); err != nil {
+			return fmt.Errorf("starting Dolt server: %w", err)
+		}
+	}
+
+	var failed []string
+	for i, m := range migrations {
+		fmt.Printf("\nMigrating %s...\n", m.RigName)
+		if err := migrateJSONLRig(townRoot, m, plans[i]); err != nil {
+			fmt.Printf("  %s %v\n", style.ErrorPrefix, err)
+			failed = append(failed, m.RigName)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("migration failed for: %v (source kept as %s%s)", failed, beads.SnapshotExport, beads.LegacyPreservedSuffix)
+	}
+	fmt.Printf("\n%s Migration complete.\n", style.Bold.Render("✓"))
+	return nil
+}
+
+func loadLegacyPlan(path string) (*beads.LegacyPlan, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a rig's beads export
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := beads.ParseLegacyJSONL(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return beads.PlanLegacyImport(records), nil
+}
+
+func migrateJSONLRig(townRoot string, m doltserver.JSONLMigration, plan *beads.LegacyPlan) error {
+	preserved := m.PreservedPath()
+	if m.SourcePath != preserved {
+		if _, err := os.Stat(preserved); err == nil {
+			return fmt.Errorf("%s already exists; move it away or migrate from it", preserved)
+		}
+		if err := os.Rename(m.SourcePath, preserved); err != nil {
+			return fmt.Errorf("preserving source: %w", err)
+		}
+		fmt.Printf("  %s Kept source as %s\n", style.Bold.Render("✓"), preserved)
+	}
+
+	if _, _, err := doltserver.InitRig(townRoot, m.RigName); err != nil {
+		return fmt.Errorf("creating database: %w", err)
+	}
+	b := beads.New(filepath.Join(townRoot, m.RigName))
+	if err := b.ConfigSet("issue_prefix", config.GetRigPrefix(townRoot, m.RigName)); err != nil {
+		return fmt.Errorf("setting issue_prefix: %w", err)
+	}
+	_ = b.ConfigSet("types.custom", constants.BeadsCustomTypes)
+	fmt.Printf("  %s Created database %s\n", style.Bold.Render("✓"), m.RigName)
+
+	actions := beads.ImportLegacy(b, plan)
+	created, failures := 0, 0
+	for _, a := range actions {
+		switch {
+		case a.Action == "failed":
+			failures++
+			fmt.Printf("  %s %s: %s\n", style.ErrorPrefix, a.SourceID, a.Detail)
+		case a.Action == "created":
+			created++
+			if a.ID != a.SourceID {
+				fmt.Printf("  %s %s imported as %s\n", style.WarningPrefix, a.SourceID, a.ID)
+			}
+			if a.Detail != "" {
+				fmt.Printf("  %s %s: %s\n", style.WarningPrefix, a.ID, a.Detail)
+			}
+		}
+	}
+	fmt.Printf("  %s Imported %d of %d issues\n", style.Bold.Render("✓"), created, len(plan.Records))
+
+	if err := verifyJSONLMigration(townRoot, m, plan); err != nil {
+		return err
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d issue(s) failed to import", failures)
+	}
+	return nil
+}
+
+func verifyJSONLMigration(townRoot string, m doltserver.JSONLMigration, plan *beads.LegacyPlan) error {
+	target, err := beads.New(filepath.Join(townRoot, m.RigName)).ExportRecords()
+	if err != nil {
+		return err
+	}
+	diffs := beads.DiffLegacy(plan, target)
+	if len(diffs) == 0 {
+		fmt.Printf("  %s Verified: %d issues match %s\n", style.Bold.Render("✓"), len(plan.Records), filepath.Base(m.PreservedPath()))
+		return nil
+	}
+	fmt.Printf("  %s %d difference(s) from %s:\n", style.WarningPrefix, len(diffs), filepath.Base(m.PreservedPath()))
+	for _, d := range diffs {
+		switch d.Field {
+		case "missing":
+			fmt.Printf("    %s  missing from database (%s)\n", d.ID, d.Source)
+		case "extra":
+			fmt.Printf("    %s  not in source (%s)\n", d.ID, d.Target)
+		default:
+			fmt.Printf("    %s  %s: %q → %q\n", d.ID, d.Field, d.Source, d.Target)
+		}
+	}
+	return fmt.Errorf("verification found %d difference(s)", len(diffs))
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltStandbyUser        string
+	doltStandbyTLS         bool
+	doltStandbyRemote      string
+	doltStandbyClear       bool
+	doltStandbyNoReplicate bool
+	doltStandbyJSON        bool
+
+	doltFailoverForce    bool
+	doltFailoverNoNotify bool
+)
+
+var doltStandbyCmd = &cobra.Command{
+	Use:   "standby [host[:port]]",
+	Short: "Configure or check the warm standby Dolt server",
+	Long: `Set up a warm standby: a Dolt sql-server on another machine that
+replicates the town's server, ready for 'gt dolt failover' to promote when
+the primary wedges.
+
+Replication goes through a Dolt remote (--remote, default origin) that
+every database has on both servers: the primary pushes each commit to it,
+and the standby pulls at the start of each transaction. Setting a standby
+configures both servers for this (skip with --no-replicate if you set
+replication up yourself). The standby uses the town's credentials
+(GT_DOLT_PASSWORD), since after a failover it is the town's server.
+
+With no argument, shows the standby and whether each database's main
+branch matches the primary's.
+
+Examples:
+  gt dolt standby dolt-b.lan
+  gt dolt standby dolt-b.lan:3307 --user gastown --remote backup
+  gt dolt standby                  # Replication status
+  gt dolt standby --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoltStandby,
+}
+
+var doltFailoverCmd = &cobra.Command{
+	Use:   "failover",
+	Short: "Promote the warm standby to be the town's Dolt server",
+	Long: `Fail over to the warm standby set with 'gt dolt standby'.
+
+The standby stops replicating and starts accepting writes, the town's
+endpoint and every rig's metadata.json are rewritten to point at it (rigs
+mapped to their own server are left alone), and running agents are nudged
+to retry. The old primary is not touched, so it can be debugged; once it
+is healthy, make it the new standby.
+
+If the primary still answers and the standby is behind it on any database,
+failover refuses unless --force: promoting would lose those commits. A
+wedged or unreachable primary cannot be compared, so the standby is used
+as it is.
+
+Examples:
+  gt dolt failover
+  gt dolt failover --force        # Even if the standby is behind`,
+	Args: cobra.NoArgs,
+	RunE: runDoltFailover,
+}
+
+func init() {
+	doltStandbyCmd.Flags().StringVar(&doltStandbyUser, "user", "", "MySQL user on the standby (default: root)")
+	doltStandbyCmd.Flags().BoolVar(&doltStandbyTLS, "tls", false, "Require TLS to the standby")
+	doltStandbyCmd.Flags().StringVar(&doltStandbyRemote, "remote", "", "Dolt remote replication goes through (default: origin)")
+	doltStandbyCmd.Flags().BoolVar(&doltStandbyClear, "clear", false, "Remove the standby")
+	doltStandbyCmd.Flags().BoolVar(&doltStandbyNoReplicate, "no-replicate", false, "Record the standby without configuring replication")
+	doltStandbyCmd.Flags().BoolVar(&doltStandbyJSON, "json", false, "Output status as JSON")
+
+	doltFailoverCmd.Flags().BoolVar(&doltFailoverForce, "force", false, "Fail over even if the standby is behind the primary")
+	doltFailoverCmd.Flags().BoolVar(&doltFailoverNoNotify, "no-notify", false, "Don't nudge running agents")
+
+	doltCmd.AddCommand(doltStandbyCmd)
+	doltCmd.AddCommand(doltFailoverCmd)
+}
+
+func runDoltStandby(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := doltserver.LoadStandby(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if doltStandbyClear {
+		if len(args) > 0 {
+			return fmt.Errorf("--clear takes no address")
+		}
+		if state.Standby == nil {
+			fmt.Println("No standby configured")
+			return nil
+		}
+		state.Standby = nil
+		if err := doltserver.SaveStandby(townRoot, state); err != nil {
+			return err
+		}
+		fmt.Printf("%s Standby removed (replication settings on the servers are unchanged)\n", style.SuccessPrefix)
+		return nil
+	}
+
+	if len(args) == 0 {
+		return printDoltStandby(townRoot, state)
+	}
+
+	ep, err := doltserver.ParseHostPort(args[0])
+	if err != nil {
+		return err
+	}
+	ep.User = doltStandbyUser
+	ep.TLS = doltStandbyTLS
+	state.Standby = &ep
+	if doltStandbyRemote != "" {
+		state.Remote = doltStandbyRemote
+	}
+	if err := state.Validate(); err != nil {
+		return err
+	}
+	if !doltStandbyNoReplicate {
+		if err := doltserver.ConfigureReplication(townRoot, state); err != nil {
+			return fmt.Errorf("%w\n\nFix the servers, or record the standby anyway with --no-replicate", err)
+		}
+	}
+	if err := doltserver.SaveStandby(townRoot, state); err != nil {
+		return err
+	}
+	config := doltserver.StandbyConfig(townRoot, ep)
+	fmt.Printf("%s Standby: %s (replicating through remote %s)\n", style.SuccessPrefix,
+		style.Bold.Render(config.HostPort()), state.ReplicationRemote())
+	fmt.Printf("  Check it with: gt dolt standby\n")
+	return nil
+}
+
+func printDoltStandby(townRoot string, state *doltserver.StandbyState) error {
+	if state.Standby == nil {
+		if doltStandbyJSON {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"standby": nil, "last_failover": state.LastFailover})
+		}
+		fmt.Println("No standby configured")
+		printLastFailover(state.LastFailover)
+		return nil
+	}
+
+	status, err := doltserver.CheckStandby(townRoot, state)
+	if doltStandbyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(status); encErr != nil {
+			return encErr
+		}
+		if err != nil {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Primary: %s", style.Bold.Render(status.Primary))
+	if !status.PrimaryReachable {
+		fmt.Printf("  %s", style.Error.Render("not answering: "+status.PrimaryError))
+	}
+	fmt.Printf("\nStandby: %s %s\n\n", style.Bold.Render(status.Standby),
+		style.Dim.Render("(remote "+state.ReplicationRemote()+")"))
+	for _, db := range status.Databases {
+		mark := style.SuccessPrefix
+		detail := "in sync"
+		switch {
+		case db.StandbyHead == "":
+			mark, detail = style.ErrorPrefix, "missing on standby"
+		case db.PrimaryHead == "":
+			mark, detail = style.Dim.Render("?"), "primary unknown, standby at "+shortHash(db.StandbyHead)
+		case !db.InSync():
+			mark, detail = style.WarningPrefix, fmt.Sprintf("behind (standby %s, primary %s)", shortHash(db.StandbyHead), shortHash(db.PrimaryHead))
+		}
+		fmt.Printf("  %s %-20s %s\n", mark, db.Name, detail)
+	}
+	printLastFailover(state.LastFailover)
+	return nil
+}
+
+func printLastFailover(f *doltserver.FailoverRecord) {
+	if f == nil {
+		return
+	}
+	by := ""
+	if f.By != "" {
+		by = " by " + f.By
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Last failover: %s → %s, %s%s", f.From, f.To, f.At.Local().Format("2006-01-02 15:04"), by)))
+}
+
+func shortHash(h string) string {
+	if len(h) > 8 {
+		return h[:8]
+	}
+	return h
+}
+
+func runDoltFailover(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := doltserver.LoadStandby(townRoot)
+	if err != nil {
+		return err
+	}
+
+	status, err := doltserver.CheckStandby(townRoot, state)
+	if err != nil {
+		return err
+	}
+	if missing := missingOnStandby(status); len(missing) > 0 && !doltFailoverForce {
+		return fmt.Errorf("standby %s has no %s\n\nUse --force to fail over anyway", status.Standby, strings.Join(missing, ", "))
+	}
+	if behind := status.Behind(); len(behind) > 0 && !doltFailoverForce {
+		return fmt.Errorf("standby %s is behind the primary on %s; promoting it would lose those commits\n\nWait for replication, or use --force", status.Standby, strings.Join(behind, ", "))
+	}
+	if !status.PrimaryReachable {
+		fmt.Printf("%s Primary %s not answering (%s); promoting the standby as is\n", style.WarningPrefix, status.Primary, status.PrimaryError)
+	}
+
+	by := detectSender()
+	record, err := doltserver.Failover(townRoot, by)
+	if err != nil {
+		if record == nil {
+			return err
+		}
+		style.PrintWarning("%v", err)
+	}
+	fmt.Printf("%s Failed over: %s → %s\n", style.SuccessPrefix, record.From, style.Bold.Render(record.To))
+	fmt.Printf("  bd and gt now use the standby. The old primary was left running for debugging.\n")
+	fmt.Printf("  Once it is healthy, make it the new standby: gt dolt standby <host[:port]>\n")
+
+	_ = events.Publish(townRoot, events.SourceDolt, events.TypeDoltFailover, by, map[string]interface{}{
+		"from": record.From,
+		"to":   record.To,
+	}, events.VisibilityBoth)
+
+	if !doltFailoverNoNotify {
+		notifyDoltFailover(townRoot, record)
+	}
+	return nil
+}
+
+// missingOnStandby returns databases the standby does not have.
+func missingOnStandby(status *doltserver.StandbyStatus) []string {
+	var missing []string
+	for _, db := range status.Databases {
+		if db.StandbyHead == "" {
+			missing = append(missing, db.Name)
+		}
+	}
+	return missing
+}
+
+// notifyDoltFailover nudges every running agent that the town's Dolt
+// server moved, so those stuck on the old primary retry.
+func notifyDoltFailover(townRoot string, record *doltserver.FailoverRecord) {
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		style.PrintWarning("could not list agents to notify: %v", err)
+		return
+	}
+	msg := fmt.Sprintf("Dolt failover: the town's database server moved from %s to %s. "+
+		"Retry any bd or gt command that failed or hung; no action needed otherwise.", record.From, record.To)
+	t := tmux.NewTmux()
+	notified := 0
+	for i, agent := range agents {
+		if shouldSend, _, _ := shouldNudgeTarget(townRoot, formatAgentName(agent), false); !shouldSend {
+			continue
+		}
+		if err := t.NudgeSession(agent.Name, msg); err == nil {
+			notified++
+		}
+		if i < len(agents)-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	fmt.Printf("  Notified %d agent(s)\n", notified)
+}
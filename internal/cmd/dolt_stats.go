@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltStatsDB     string
+	doltStatsTables bool
+	doltStatsJSON   bool
+	doltStatsNoSave bool
+)
+
+var doltStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show table row counts, sizes, and growth per database",
+	Long: `Report per-database row counts, on-disk size, and commit counts.
+
+Each run is cached in daemon/dolt-stats.json, and growth is shown since the
+previous run. The cached numbers also feed 'gt rig list' and the warnings
+in 'gt dolt status' (databases over 1 GB, or that grew more than 256 MB
+between runs).
+
+On-disk size is only available for a local server.
+
+Examples:
+  gt dolt stats                  # All databases
+  gt dolt stats --db gastown --tables
+  gt dolt stats --json
+  gt dolt stats --no-save        # Don't update the cache`,
+	Args: cobra.NoArgs,
+	RunE: runDoltStats,
+}
+
+func init() {
+	doltStatsCmd.Flags().StringVar(&doltStatsDB, "db", "", "Report a single database")
+	doltStatsCmd.Flags().BoolVar(&doltStatsTables, "tables", false, "Show row counts per table")
+	doltStatsCmd.Flags().BoolVar(&doltStatsJSON, "json", false, "Output as JSON")
+	doltStatsCmd.Flags().BoolVar(&doltStatsNoSave, "no-save", false, "Don't cache this run (growth stays relative to the last saved run)")
+
+	doltCmd.AddCommand(doltStatsCmd)
+}
+
+func runDoltStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	prev, err := doltserver.LoadStatsSnapshot(townRoot)
+	if err != nil {
+		style.PrintWarning("ignoring stats cache: %v", err)
+		prev = &doltserver.StatsSnapshot{}
+	}
+	stats, err := doltserver.CollectStats(townRoot, doltStatsDB, prev)
+	if err != nil {
+		return err
+	}
+	if !doltStatsNoSave {
+		if err := doltserver.SaveStatsSnapshot(townRoot, stats); err != nil {
+			style.PrintWarning("could not save stats cache: %v", err)
+		}
+	}
+
+	if doltStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No databases found.")
+		return nil
+	}
+
+	var failed int
+	fmt.Printf("%-20s %10s %12s %10s  %s\n", "DATABASE", "SIZE", "ROWS", "COMMITS", "GROWTH")
+	for _, s := range stats {
+		if s.Error != "" {
+			fmt.Printf("%-20s %s\n", s.Database, style.Error.Render("error: "+s.Error))
+			failed++
+			continue
+		}
+		fmt.Printf("%-20s %10s %12d %10d  %s\n", s.Database, formatBytes(s.Bytes), s.Rows, s.Commits,
+			style.Dim.Render(formatStatsGrowth(s.Growth)))
+		if doltStatsTables {
+			for _, t := range s.Tables {
+				fmt.Printf("  %-18s %10s %12d\n", t.Name, "", t.Rows)
+			}
+		}
+	}
+
+	if warnings := doltserver.StatsWarnings(stats); len(warnings) > 0 {
+		fmt.Println()
+		for _, w := range warnings {
+			fmt.Printf("%s %s\n", style.WarningPrefix, w)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) could not be queried", failed)
+	}
+	return nil
+}
+
+// formatStatsGrowth renders growth since the previous stats run.
+func formatStatsGrowth(g *doltserver.StatsGrowth) string {
+	if g == nil {
+		return "first run"
+	}
+	sign := "+"
+	bytes := g.Bytes
+	if bytes < 0 {
+		sign, bytes = "-", -bytes
+	}
+	return fmt.Sprintf("%s%s, %+d rows, %+d commits since %s",
+		sign, formatBytes(bytes), g.Rows, g.Commits, g.Since.Local().Format("Jan 2 15:04"))
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltStartSupervise bool
+	doltSuperviseEvery time.Duration
+)
+
+var doltSuperviseCmd = &cobra.Command{
+	Use:   "supervise",
+	Short: "Run the Dolt server supervisor in the foreground (internal)",
+	Long: `Watch the Dolt server and restart it if it dies while it should be running.
+
+This is started in the background by 'gt dolt start --supervise'. The
+server should be running from 'gt dolt start' until 'gt dolt stop'; if the
+process disappears or stops answering queries, the supervisor restarts it
+with the daemon's backoff, escalating to the mayor after 5 restarts in 10
+minutes.
+
+Restarts are logged to daemon/dolt-restarts.log and published to the town
+event log (see 'gt events tail --filter source=doltserver').`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runDoltSupervise,
+}
+
+func init() {
+	doltStartCmd.Flags().BoolVar(&doltStartSupervise, "supervise", false, "Restart the server automatically if it dies")
+	doltSuperviseCmd.Flags().DurationVar(&doltSuperviseEvery, "interval", daemon.DefaultDoltSuperviseInterval, "How often to check the server")
+
+	doltCmd.AddCommand(doltSuperviseCmd)
+}
+
+func runDoltSupervise(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	interval := daemon.DefaultDoltSuperviseInterval
+	if doltSuperviseEvery > 0 {
+		interval = doltSuperviseEvery
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return daemon.RunDoltSupervisor(ctx, townRoot, interval)
+}
+
+// startDoltSupervisor launches 'gt dolt supervise' in the background unless
+// one is already running.
+func startDoltSupervisor(townRoot string) error {
+	if running, pid := doltserver.SupervisorRunning(townRoot); running {
+		fmt.Printf("  Supervisor: %s\n", style.Dim.Render(fmt.Sprintf("already running (PID %d)", pid)))
+		return nil
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	supervisorCmd := exec.Command(gtPath, "dolt", "supervise")
+	supervisorCmd.Dir = townRoot
+
+	// Detach from terminal
+	supervisorCmd.Stdin = nil
+	supervisorCmd.Stdout = nil
+	supervisorCmd.Stderr = nil
+
+	if err := supervisorCmd.Start(); err != nil {
+		return fmt.Errorf("starting supervisor: %w", err)
+	}
+
+	// Wait a moment for the supervisor to take its lock and write its PID
+	for i := 0; i < 10; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if running, pid := doltserver.SupervisorRunning(townRoot); running {
+			fmt.Printf("  Supervisor: running (PID %d), restarts logged to %s\n",
+				pid, style.Dim.Render(doltserver.RestartLogFile(townRoot)))
+			return nil
+		}
+	}
+	return fmt.Errorf("supervisor failed to start (see %s)", doltserver.RestartLogFile(townRoot))
+}
+
+// printDoltSupervisorStatus prints the supervisor line for gt dolt status.
+func printDoltSupervisorStatus(townRoot string) {
+	running, pid := doltserver.SupervisorRunning(townRoot)
+	if !running {
+		fmt.Printf("  Supervisor: %s\n", style.Dim.Render("not running (start with 'gt dolt start --supervise')"))
+		return
+	}
+	fmt.Printf("  Supervisor: running (PID %d)\n", pid)
+	if last := lastDoltRestart(townRoot); last != "" {
+		fmt.Printf("  Last restart: %s\n", style.Dim.Render(last))
+	}
+}
+
+// lastDoltRestart returns the most recent restart line from the restart log.
+func lastDoltRestart(townRoot string) string {
+	data, err := os.ReadFile(doltserver.RestartLogFile(townRoot))
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], "restart succeeded") || strings.Contains(lines[i], "restart failed") {
+			return lines[i]
+		}
+	}
+	return ""
+}
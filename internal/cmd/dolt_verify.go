@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltVerifyDB   string
+	doltVerifyJSON bool
+)
+
+// doltVerifyMaxMissing caps the missing bead IDs listed per database.
+const doltVerifyMaxMissing = 10
+
+var doltVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check Dolt databases for corruption",
+	Long: `Verify the integrity of each database in .dolt-data/.
+
+Two checks run per database:
+  1. dolt fsck: walks the chunk store and fails on missing or corrupt chunks
+  2. Export check: every bead in the rig's issues.jsonl export must still be
+     in the database's issues table. The database may hold more than the
+     export (wisps and merge requests are never exported), but a bead in the
+     export and not the database has been lost
+
+Run it before 'gt dolt sync', so corruption is caught before it is pushed
+to the remotes. dolt fsck only reads, so the server can stay up; the export
+check needs it running. Exits non-zero if any database fails.
+
+Examples:
+  gt dolt verify
+  gt dolt verify --db gastown
+  gt dolt verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltVerify,
+}
+
+func init() {
+	doltVerifyCmd.Flags().StringVar(&doltVerifyDB, "db", "", "Verify a single database")
+	doltVerifyCmd.Flags().BoolVar(&doltVerifyJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltVerifyCmd)
+}
+
+func runDoltVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if doltVerifyDB != "" && !doltserver.DatabaseExists(townRoot, doltVerifyDB) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", doltVerifyDB)
+	}
+
+	results, err := doltserver.CheckIntegrity(townRoot, doltVerifyDB)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for i := range results {
+		if !results[i].OK() {
+			failed++
+		}
+	}
+
+	if doltVerifyJSON {
+		if results == nil {
+			results = []doltserver.VerifyResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No databases to verify.")
+		return nil
+	}
+	for i := range results {
+		printVerifyResult(&results[i])
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d database(s) failed verification", failed, len(results))
+	}
+	fmt.Printf("%s %d database(s) verified\n", style.SuccessPrefix, len(results))
+	return nil
+}
+
+func printVerifyResult(r *doltserver.VerifyResult) {
+	mark := style.Bold.Render("✓")
+	if !r.OK() {
+		mark = style.Error.Render("✗")
+	}
+	detail := "fsck " + r.Fsck
+	if r.Export != "" {
+		detail += fmt.Sprintf(", %d rows (%d exported)", r.Rows, r.ExportRows)
+	}
+	fmt.Printf("  %s %s: %s\n", mark, r.Database, detail)
+
+	for _, p := range r.Problems {
+		fmt.Printf("      %s\n", p)
+	}
+	if len(r.Missing) > 0 {
+		shown := r.Missing
+		if len(shown) > doltVerifyMaxMissing {
+			shown = shown[:doltVerifyMaxMissing]
+		}
+		more := ""
+		if n := len(r.Missing) - len(shown); n > 0 {
+			more = fmt.Sprintf(" (+%d more; see --json)", n)
+		}
+		fmt.Printf("      missing: %s%s\n", strings.Join(shown, ", "), more)
+	}
+	if r.FsckOutput != "" {
+		for _, line := range strings.Split(r.FsckOutput, "\n") {
+			fmt.Printf("      %s\n", style.Dim.Render(line))
+		}
+	}
+	for _, n := range r.Notes {
+		fmt.Printf("      %s\n", style.Dim.Render(n))
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mq"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -731,6 +732,17 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
 			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
 		} else {
+			// A frozen queue takes no new submissions. The branch is pushed,
+			// so the witness can resubmit once the freeze lifts.
+			if freeze, err := mq.ActiveFreeze(filepath.Join(townRoot, rigName), time.Now()); err != nil {
+				style.PrintWarning("could not check queue freeze: %v", err)
+			} else if freeze != nil {
+				errMsg := freeze.SubmitError(rigName).Error()
+				doneErrors = append(doneErrors, errMsg)
+				style.PrintWarning("%s\nBranch is pushed but MR bead not created. Witness will be notified.", errMsg)
+				goto notifyWitness
+			}
+
 			// Build MR bead title and description
 			title := fmt.Sprintf("Merge: %s", issueID)
 			description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
@@ -741,6 +753,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if sessions := mrSessions(issueID); len(sessions) > 0 {
+				description += fmt.Sprintf("\nsessions: %s", strings.Join(sessions, ", "))
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -764,6 +779,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				goto notifyWitness
 			}
 			mrID = mrIssue.ID
+			if err := bd.RecordMRLink(issueID, mrID); err != nil {
+				style.PrintWarning("could not record MR link in %s history: %v", issueID, err)
+			}
 
 			// Update agent bead with active_mr reference (for traceability)
 			if agentBeadID != "" {
@@ -79,67 +79,18 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Create escalation bead
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
-	fields := &beads.EscalationFields{
+	issue, actions, targets, err := fileEscalation(townRoot, escalationConfig, escalationRequest{
+		Description: description,
 		Severity:    severity,
 		Reason:      escalateReason,
 		Source:      escalateSource,
-		EscalatedBy: agentID,
-		EscalatedAt: time.Now().Format(time.RFC3339),
+		From:        agentID,
 		RelatedBead: escalateRelatedBead,
-	}
-
-	issue, err := bd.CreateEscalationBead(description, fields)
+	})
 	if err != nil {
-		return fmt.Errorf("creating escalation bead: %w", err)
-	}
-
-	// Get routing actions for this severity
-	actions := escalationConfig.GetRouteForSeverity(severity)
-	targets := extractMailTargetsFromActions(actions)
-
-	// Send mail to each target (actions with "mail:" prefix)
-	router := mail.NewRouter(townRoot)
-	defer router.WaitPendingNotifications()
-	for _, target := range targets {
-		msg := &mail.Message{
-			From:    agentID,
-			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
-			Type:    mail.TypeTask,
-		}
-
-		// Set priority based on severity
-		switch severity {
-		case config.SeverityCritical:
-			msg.Priority = mail.PriorityUrgent
-		case config.SeverityHigh:
-			msg.Priority = mail.PriorityHigh
-		case config.SeverityMedium:
-			msg.Priority = mail.PriorityNormal
-		default:
-			msg.Priority = mail.PriorityLow
-		}
-
-		if err := router.Send(msg); err != nil {
-			style.PrintWarning("failed to send to %s: %v", target, err)
-		}
+		return err
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
-
-	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
-	payload["severity"] = severity
-	payload["actions"] = strings.Join(actions, ",")
-	if escalateSource != "" {
-		payload["source"] = escalateSource
-	}
-	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
-
 	// Output
 	if escalateJSON {
 		result := map[string]interface{}{
@@ -670,3 +621,81 @@ func detectSenderFallback() string {
 	}
 	return ""
 }
+
+// escalationRequest describes an escalation to file.
+type escalationRequest struct {
+	Description string
+	Severity    string
+	Reason      string
+	Source      string
+	From        string
+	RelatedBead string
+}
+
+// fileEscalation creates an escalation bead and routes it by severity
+// (mail targets and external actions), logging it to the activity feed.
+// Returns the bead and the routing actions and mail targets used.
+func fileEscalation(townRoot string, escalationConfig *config.EscalationConfig, req escalationRequest) (*beads.Issue, []string, []string, error) {
+	// Create escalation bead
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	fields := &beads.EscalationFields{
+		Severity:    req.Severity,
+		Reason:      req.Reason,
+		Source:      req.Source,
+		EscalatedBy: req.From,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: req.RelatedBead,
+	}
+
+	issue, err := bd.CreateEscalationBead(req.Description, fields)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("creating escalation bead: %w", err)
+	}
+
+	// Get routing actions for this severity
+	actions := escalationConfig.GetRouteForSeverity(req.Severity)
+	targets := extractMailTargetsFromActions(actions)
+
+	// Send mail to each target (actions with "mail:" prefix)
+	router := mail.NewRouter(townRoot)
+	defer router.WaitPendingNotifications()
+	for _, target := range targets {
+		msg := &mail.Message{
+			From:    req.From,
+			To:      target,
+			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(req.Severity), req.Description),
+			Body:    formatEscalationMailBody(issue.ID, req.Severity, req.Reason, req.From, req.RelatedBead),
+			Type:    mail.TypeTask,
+		}
+
+		// Set priority based on severity
+		switch req.Severity {
+		case config.SeverityCritical:
+			msg.Priority = mail.PriorityUrgent
+		case config.SeverityHigh:
+			msg.Priority = mail.PriorityHigh
+		case config.SeverityMedium:
+			msg.Priority = mail.PriorityNormal
+		default:
+			msg.Priority = mail.PriorityLow
+		}
+
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("failed to send to %s: %v", target, err)
+		}
+	}
+
+	// Process external notification actions (email:, sms:, slack)
+	executeExternalActions(actions, escalationConfig, issue.ID, req.Severity, req.Description)
+
+	// Log to activity feed
+	payload := events.EscalationPayload(issue.ID, req.From, strings.Join(targets, ","), req.Description)
+	payload["severity"] = req.Severity
+	payload["actions"] = strings.Join(actions, ",")
+	if req.Source != "" {
+		payload["source"] = req.Source
+	}
+	_ = events.LogFeed(events.TypeEscalationSent, req.From, payload)
+
+	return issue, actions, targets, nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsTailLines  int
+	eventsTailFollow bool
+	eventsTailFilter []string
+	eventsTailSince  string
+	eventsTailJSON   bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Inspect the town event log",
+	RunE:    requireSubcommand,
+	Long: `Inspect the town event log (.events.jsonl at the town root).
+
+Commands log their actions here, and long-running subsystems publish
+structured events as they work:
+  refinery     merge_started, merged, merge_failed, merge_conflicted
+  doltserver   dolt_started, dolt_stopped, dolt_crashed, dolt_restarted
+  polecat      polecat_added, polecat_removed
+  doctor       doctor_fix
+
+Subcommands:
+  tail     Show recent events, optionally following new ones
+  replay   Replay a stretch of the log at its original pace`,
+}
+
+var eventsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show recent events, optionally following new ones",
+	Long: `Show the most recent events in the town event log.
+
+Filters take key=value and are ANDed. Keys are type, source, actor,
+visibility, or a payload key (payload.rig or just rig). A value matches
+exactly, as a prefix (type=merge matches merged and merge_failed), or as a
+glob; separate alternatives with commas.
+
+Examples:
+  gt events tail                                 # Last 20 events
+  gt events tail --follow --filter type=merge    # Watch the merge queue
+  gt events tail --since 12h --filter source=doctor,doltserver -n 0
+  gt events tail --filter rig=gastown --filter actor='*/refinery'
+  gt events tail --json -f | jq .payload`,
+	Args: cobra.NoArgs,
+	RunE: runEventsTail,
+}
+
+func init() {
+	eventsTailCmd.Flags().IntVarP(&eventsTailLines, "lines", "n", 20, "Number of recent events to show (0 = all)")
+	eventsTailCmd.Flags().BoolVarP(&eventsTailFollow, "follow", "f", false, "Keep printing new events as they are published")
+	eventsTailCmd.Flags().StringArrayVar(&eventsTailFilter, "filter", nil, "Filter events by key=value (repeatable)")
+	eventsTailCmd.Flags().StringVar(&eventsTailSince, "since", "", "Only show events newer than this (e.g., 30m, 12h, 2d)")
+	eventsTailCmd.Flags().BoolVar(&eventsTailJSON, "json", false, "Print events as JSON lines")
+
+	eventsCmd.AddCommand(eventsTailCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsTail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	filter, err := events.ParseFilter(eventsTailFilter)
+	if err != nil {
+		return err
+	}
+	if eventsTailSince != "" {
+		d, err := parseDuration(eventsTailSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", eventsTailSince, err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+
+	logPath := filepath.Join(townRoot, events.EventsFile)
+	recent, offset, err := events.ReadLast(logPath, eventsTailLines, filter)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", logPath, err)
+	}
+	for _, e := range recent {
+		printTownEvent(e)
+	}
+	if !eventsTailFollow {
+		if len(recent) == 0 && !eventsTailJSON {
+			fmt.Println(style.Dim.Render("No matching events."))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return events.Follow(ctx, logPath, offset, 500*time.Millisecond, filter, printTownEvent)
+}
+
+// printTownEvent prints one event as a line: time, source, type, actor, payload.
+func printTownEvent(e events.Event) {
+	if eventsTailJSON {
+		data, err := json.Marshal(e)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%s %-10s %-18s %s", style.Dim.Render(ts), e.Source, style.Bold.Render(e.Type), e.Actor)
+	if payload := formatEventPayload(e.Payload); payload != "" {
+		fmt.Printf("  %s", style.Dim.Render(payload))
+	}
+	fmt.Println()
+}
+
+// formatEventPayload renders a payload as sorted key=value pairs.
+func formatEventPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := fmt.Sprint(payload[k])
+		if strings.ContainsAny(v, " \t\n") {
+			v = fmt.Sprintf("%q", v)
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
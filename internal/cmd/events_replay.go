@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events replay command flags
+var (
+	eventsReplayFrom   string
+	eventsReplayTo     string
+	eventsReplaySpeed  string
+	eventsReplayMaxGap time.Duration
+	eventsReplayFilter []string
+	eventsReplaySink   string
+	eventsReplayJSON   bool
+	eventsReplayStep   bool
+)
+
+var eventsReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replay a stretch of the event log at its original pace",
+	Long: `Re-emit the events between two times in the order and at the pace they
+happened, so an incident review can step through what the town's
+subsystems did.
+
+By default events play as a timeline on the terminal, each marked with its
+offset from the first. --speed plays faster (10x) or without pauses (max),
+and --max-gap caps the pause between events so quiet stretches don't stall
+the replay; skipped time is marked in the timeline. --step waits for Enter
+before each event.
+
+--sink sends the events elsewhere as JSON lines instead: a file path, or an
+http(s) URL that receives each event as a JSON POST. The town's own event
+log can't be a sink.
+
+Times are RFC3339, "2006-01-02 15:04[:05]", "15:04" (today), or a duration
+ago (30m, 12h, 2d). --to defaults to now.
+
+Filters work as in 'gt events tail'.
+
+Examples:
+  gt events replay --from 2h --speed 10x
+  gt events replay --from "2026-03-14 09:00" --to "2026-03-14 09:30" --filter source=refinery,doltserver
+  gt events replay --from 09:00 --step
+  gt events replay --from 1d --speed max --sink http://localhost:8080/events`,
+	Args: cobra.NoArgs,
+	RunE: runEventsReplay,
+}
+
+func init() {
+	eventsReplayCmd.Flags().StringVar(&eventsReplayFrom, "from", "", "Start of the replay (time, or duration ago) (required)")
+	eventsReplayCmd.Flags().StringVar(&eventsReplayTo, "to", "", "End of the replay (time, or duration ago; default now)")
+	eventsReplayCmd.Flags().StringVar(&eventsReplaySpeed, "speed", "1x", "Playback speed (e.g., 10x), or 'max' for no pauses")
+	eventsReplayCmd.Flags().DurationVar(&eventsReplayMaxGap, "max-gap", 5*time.Second, "Longest pause between events (0 = no cap)")
+	eventsReplayCmd.Flags().StringArrayVar(&eventsReplayFilter, "filter", nil, "Filter events by key=value (repeatable)")
+	eventsReplayCmd.Flags().StringVar(&eventsReplaySink, "sink", "", "Send events to a file or http(s) URL as JSON")
+	eventsReplayCmd.Flags().BoolVar(&eventsReplayJSON, "json", false, "Print events as JSON lines")
+	eventsReplayCmd.Flags().BoolVar(&eventsReplayStep, "step", false, "Wait for Enter before each event")
+	_ = eventsReplayCmd.MarkFlagRequired("from")
+
+	eventsCmd.AddCommand(eventsReplayCmd)
+}
+
+func runEventsReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter, err := events.ParseFilter(eventsReplayFilter)
+	if err != nil {
+		return err
+	}
+	if filter.Since, err = parseReplayTime(eventsReplayFrom, now); err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	if eventsReplayTo != "" {
+		if filter.Until, err = parseReplayTime(eventsReplayTo, now); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+		if !filter.Until.After(filter.Since) {
+			return fmt.Errorf("--to must be after --from")
+		}
+	}
+	speed, err := parseReplaySpeed(eventsReplaySpeed)
+	if err != nil {
+		return err
+	}
+	if eventsReplayStep {
+		if !isStdinTerminal() {
+			return fmt.Errorf("--step needs an interactive terminal")
+		}
+		speed = 0
+	}
+
+	logPath := filepath.Join(townRoot, events.EventsFile)
+	evs, _, err := events.ReadLast(logPath, 0, filter)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", logPath, err)
+	}
+	if len(evs) == 0 {
+		fmt.Println(style.Dim.Render("No matching events in that range."))
+		return nil
+	}
+
+	sink, closeSink, err := openReplaySink(eventsReplaySink, logPath)
+	if err != nil {
+		return err
+	}
+	defer closeSink()
+
+	if eventsReplaySink != "" || !eventsReplayJSON {
+		fmt.Fprintf(os.Stderr, "Replaying %d event(s) from %s at %s\n", len(evs),
+			evs[0].Timestamp, eventsReplaySpeed)
+	}
+
+	if eventsReplayStep {
+		stdin := bufio.NewReader(os.Stdin)
+		inner := sink
+		sink = func(e events.Event) error {
+			fmt.Fprint(os.Stderr, style.Dim.Render("[Enter] next "))
+			if _, err := stdin.ReadString('\n'); err != nil {
+				return err
+			}
+			return inner(e)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := events.Replay(ctx, evs, speed, eventsReplayMaxGap, sink); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// parseReplayTime parses an absolute time, a time of day today, or a
+// duration before now.
+func parseReplayTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+	if d, err := parseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time or a duration", s)
+}
+
+// parseReplaySpeed parses a playback speed such as "10x" or "0.5"; "max"
+// is 0, replaying without pauses.
+func parseReplaySpeed(s string) (float64, error) {
+	if s == "max" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --speed %q: expected e.g. 10x, or max", s)
+	}
+	return speed, nil
+}
+
+// openReplaySink returns the function replayed events are sent to.
+func openReplaySink(target, logPath string) (sink func(events.Event) error, closeFn func(), err error) {
+	switch {
+	case target == "" || target == "-":
+		return replayTimeline(), func() {}, nil
+
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		client := &http.Client{Timeout: 10 * time.Second}
+		return func(e events.Event) error {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Post(target, "application/json", bytes.NewReader(data))
+			if err != nil {
+				return fmt.Errorf("sending event to sink: %w", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("sink returned HTTP %d", resp.StatusCode)
+			}
+			return nil
+		}, func() {}, nil
+
+	default:
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil, nil, err
+		}
+		if abs == logPath {
+			return nil, nil, fmt.Errorf("refusing to replay into the town event log")
+		}
+		f, err := os.OpenFile(abs, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G304: user-chosen sink
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening sink: %w", err)
+		}
+		enc := json.NewEncoder(f)
+		return func(e events.Event) error { return enc.Encode(e) }, func() { _ = f.Close() }, nil
+	}
+}
+
+// replayTimeline prints events with their offset from the first, marking
+// gaps longer than --max-gap. With --json it prints plain JSON lines.
+func replayTimeline() func(events.Event) error {
+	var start, prev time.Time
+	return func(e events.Event) error {
+		if eventsReplayJSON {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		offset := "         "
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+			if start.IsZero() {
+				start = ts
+			}
+			if gap := ts.Sub(prev); !prev.IsZero() && eventsReplayMaxGap > 0 && gap > eventsReplayMaxGap {
+				fmt.Printf("          %s\n", style.Dim.Render("⋮ "+formatDuration(gap)+" later"))
+			}
+			prev = ts
+			offset = formatReplayOffset(ts.Sub(start))
+		}
+		fmt.Printf("%s │ ", style.Dim.Render(offset))
+		printTownEvent(e)
+		return nil
+	}
+}
+
+// formatReplayOffset formats an offset as +HH:MM:SS.
+func formatReplayOffset(d time.Duration) string {
+	s := int(d.Seconds())
+	return fmt.Sprintf("+%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseReplayTime(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-14T09:30:00Z", time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-13 22:15", time.Date(2026, 3, 13, 22, 15, 0, 0, time.UTC)},
+		{"09:05", time.Date(2026, 3, 14, 9, 5, 0, 0, time.UTC)},
+		{"2h", time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)},
+		{"1d", time.Date(2026, 3, 13, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseReplayTime(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseReplayTime(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseReplayTime("yesterday", now); err == nil {
+		t.Error("parseReplayTime(yesterday) should fail")
+	}
+}
+
+func TestParseReplaySpeed(t *testing.T) {
+	for in, want := range map[string]float64{"10x": 10, "1": 1, "0.5x": 0.5, "max": 0} {
+		if got, err := parseReplaySpeed(in); err != nil || got != want {
+			t.Errorf("parseReplaySpeed(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"0x", "-2", "fast"} {
+		if _, err := parseReplaySpeed(in); err == nil {
+			t.Errorf("parseReplaySpeed(%q) should fail", in)
+		}
+	}
+}
+
+func TestFormatReplayOffset(t *testing.T) {
+	if got := formatReplayOffset(time.Hour + 2*time.Minute + 3*time.Second); got != "+01:02:03" {
+		t.Errorf("formatReplayOffset = %q", got)
+	}
+}
+
+func TestOpenReplaySink_RefusesEventLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), ".events.jsonl")
+	if _, _, err := openReplaySink(logPath, logPath); err == nil {
+		t.Error("replaying into the town event log should be refused")
+	}
+}
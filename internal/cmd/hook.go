@@ -284,7 +284,6 @@ func runHook(_ *cobra.Command, args []string) error {
 	existingPinned, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		return fmt.Errorf("checking existing hooked beads: %w", err)
@@ -465,7 +464,6 @@ func runHookShow(cmd *cobra.Command, args []string) error {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: target,
-		Priority: -1,
 	})
 	if err != nil {
 		return fmt.Errorf("listing hooked beads: %w", err)
@@ -484,7 +482,6 @@ func runHookShow(cmd *cobra.Command, args []string) error {
 				townHooked, err := townBeads.List(beads.ListOptions{
 					Status:   beads.StatusHooked,
 					Assignee: target,
-					Priority: -1,
 				})
 				if err == nil && len(townHooked) > 0 {
 					hookedBeads = townHooked
@@ -122,7 +122,6 @@ func TestHookSlot_BasicHook(t *testing.T) {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list hooked beads: %v", err)
@@ -193,7 +192,6 @@ func TestHookSlot_Singleton(t *testing.T) {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list hooked beads: %v", err)
@@ -256,7 +254,6 @@ func TestHookSlot_Unhook(t *testing.T) {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list hooked beads: %v", err)
@@ -327,7 +324,6 @@ func TestHookSlot_DifferentAgents(t *testing.T) {
 	agent1Hooks, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agent1,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list agent1 hooks: %v", err)
@@ -336,7 +332,6 @@ func TestHookSlot_DifferentAgents(t *testing.T) {
 	agent2Hooks, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agent2,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list agent2 hooks: %v", err)
@@ -398,7 +393,6 @@ func TestHookSlot_HookPersistence(t *testing.T) {
 	hookedBeads, err := b2.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		t.Fatalf("list hooked beads with new instance: %v", err)
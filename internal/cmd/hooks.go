@@ -22,7 +22,7 @@ Subcommands:
   list       Show all managed settings.json locations
   scan       Scan workspace for existing hooks
   registry   List hooks from the registry
-  install    Install a hook from the registry
+  install    Install a hook from the registry (or the beads-sync git hooks)
 
 Config structure:
   Base:      ~/.gt/hooks-base.json
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// beadsSyncHookName is the 'gt hooks install' name for the git hooks that
+// keep rig clones' beads in step with the sync branch. Unlike registry
+// hooks, they go into each clone's git hooks directory.
+const beadsSyncHookName = "beads-sync"
+
+var beadSyncHookCmd = &cobra.Command{
+	Use:    "hook <pre-push|post-merge>",
+	Short:  "Run a beads sync git hook (called by the installed hooks)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runBeadSyncHook,
+}
+
+func init() {
+	beadSyncCmd.AddCommand(beadSyncHookCmd)
+}
+
+// runInstallBeadsSyncHooks installs the beads sync git hooks into the
+// clones of the current rig, or of every rig with --all-rigs.
+func runInstallBeadsSyncHooks() error {
+	var rigs []*rig.Rig
+	if installAllRigs {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	} else {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return err
+		}
+		rigName, err := inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("not in a rig; run from a rig or use --all-rigs")
+		}
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	clonePrefix := ""
+	switch installRole {
+	case "":
+	case "mayor", "refinery", "witness":
+		clonePrefix = installRole
+	case "crew":
+		clonePrefix = "crew/"
+	case "polecat":
+		clonePrefix = "polecats/"
+	default:
+		return fmt.Errorf("invalid --role %q for %s (expected mayor, refinery, witness, crew, or polecat)", installRole, beadsSyncHookName)
+	}
+
+	installed, failed := 0, 0
+	for _, r := range rigs {
+		for _, clone := range rigSyncClones(r) {
+			name, path := clone[0], clone[1]
+			if clonePrefix != "" && !strings.HasPrefix(name, clonePrefix) {
+				continue
+			}
+			results, err := beads.InstallSyncHooks(path, installDryRun)
+			label := r.Name + "/" + name
+			if errors.Is(err, beads.ErrSyncHooksBypassed) {
+				fmt.Printf("  %s %s: %v\n", style.Warning.Render("⚠"), label, err)
+				failed++
+				continue
+			}
+			if err != nil {
+				fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), label, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  %s %s  %s\n", style.Success.Render("✓"), label, formatSyncHookResults(results))
+			installed++
+		}
+	}
+
+	if installDryRun {
+		fmt.Printf("\n%s Would install %q in %d clone(s)\n", style.Dim.Render("Dry run:"), beadsSyncHookName, installed)
+	} else {
+		fmt.Printf("\n%s Installed %q in %d clone(s)\n", style.Success.Render("Done:"), beadsSyncHookName, installed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d clone(s) not installed", failed)
+	}
+	return nil
+}
+
+func formatSyncHookResults(results map[string]string) string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+" "+results[name])
+	}
+	return style.Dim.Render(strings.Join(parts, ", "))
+}
+
+// runBeadSyncHook does the work of an installed beads sync hook. Failures
+// are warnings: a hook must never stop a push or a pull.
+func runBeadSyncHook(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	if info, err := os.Stat(beads.ResolveBeadsDir(cwd)); err != nil || !info.IsDir() {
+		return nil // clone without beads
+	}
+	bd := beads.New(cwd)
+
+	switch args[0] {
+	case "pre-push":
+		if err := bd.SyncExport(); err != nil {
+			style.PrintWarning("beads sync branch not pushed: %v\n  If it has diverged, run: gt bead sync resolve --apply", err)
+		}
+	case "post-merge":
+		if err := bd.SyncImport(); err != nil {
+			style.PrintWarning("beads snapshot not imported: %v\n  Run 'bd sync' to retry", err)
+		}
+	default:
+		return fmt.Errorf("unknown beads sync hook %q (expected pre-push or post-merge)", args[0])
+	}
+	return nil
+}
@@ -27,11 +27,20 @@ var hooksInstallCmd = &cobra.Command{
 By default, installs to the current worktree. Use --role to install
 to all worktrees of a specific role in the current rig.
 
+The name beads-sync installs git hooks instead: pre-push exports the
+beads snapshot and pushes the sync branch, post-merge imports the
+snapshot a pull brought in. They go into every clone of the current rig
+(--all-rigs: every rig; --role: one kind of clone). A hook a clone
+already has is kept as <hook>.local and runs first. Clones whose
+core.hooksPath points at hooks tracked in the repository are skipped.
+'gt doctor' checks the hooks are present and current.
+
 Examples:
   gt hooks install pr-workflow-guard              # Install to current worktree
   gt hooks install pr-workflow-guard --role crew  # Install to all crew in current rig
   gt hooks install session-prime --role crew --all-rigs  # Install to all crew everywhere
-  gt hooks install pr-workflow-guard --dry-run    # Preview what would be installed`,
+  gt hooks install pr-workflow-guard --dry-run    # Preview what would be installed
+  gt hooks install beads-sync --all-rigs          # Beads sync git hooks in every clone`,
 	Args: cobra.ExactArgs(1),
 	RunE: runHooksInstall,
 }
@@ -46,6 +55,9 @@ func init() {
 
 func runHooksInstall(cmd *cobra.Command, args []string) error {
 	hookName := args[0]
+	if hookName == beadsSyncHookName {
+		return runInstallBeadsSyncHooks()
+	}
 
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -634,9 +634,8 @@ func initTownAgentBeads(townPath string) error {
 	}
 
 	existingAgents, err := bd.List(beads.ListOptions{
-		Status:   "all",
-		Type:     "agent",
-		Priority: -1,
+		Status: "all",
+		Type:   "agent",
 	})
 	if err != nil {
 		return fmt.Errorf("listing existing agent beads: %w", err)
@@ -83,7 +83,6 @@ func runMoleculeAttachFromMail(cmd *cobra.Command, args []string) error {
 	pinnedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusPinned,
 		Assignee: agentIdentity,
-		Priority: -1,
 	})
 	if err != nil {
 		return fmt.Errorf("listing pinned beads: %w", err)
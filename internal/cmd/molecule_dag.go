@@ -84,9 +84,8 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 
 	// Find all children of the root issue
 	children, err := b.List(beads.ListOptions{
-		Parent:   rootID,
-		Status:   "all",
-		Priority: -1,
+		Parent: rootID,
+		Status: "all",
 	})
 	if err != nil {
 		return fmt.Errorf("listing children: %w", err)
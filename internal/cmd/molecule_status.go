@@ -163,9 +163,8 @@ func runMoleculeProgress(cmd *cobra.Command, args []string) error {
 
 	// Find all children of the root issue
 	children, err := b.List(beads.ListOptions{
-		Parent:   rootID,
-		Status:   "all",
-		Priority: -1,
+		Parent: rootID,
+		Status: "all",
 	})
 	if err != nil {
 		return fmt.Errorf("listing children: %w", err)
@@ -435,7 +434,6 @@ func runMoleculeStatus(cmd *cobra.Command, args []string) error {
 		hookedBeads, err := b.List(beads.ListOptions{
 			Status:   beads.StatusHooked,
 			Assignee: target,
-			Priority: -1,
 		})
 		if err != nil {
 			return fmt.Errorf("listing hooked beads: %w", err)
@@ -448,7 +446,6 @@ func runMoleculeStatus(cmd *cobra.Command, args []string) error {
 			inProgressBeads, err := b.List(beads.ListOptions{
 				Status:   "in_progress",
 				Assignee: target,
-				Priority: -1,
 			})
 			if err == nil && len(inProgressBeads) > 0 {
 				// Use the first in_progress bead (should typically be only one)
@@ -540,9 +537,8 @@ func getMoleculeProgressInfo(b *beads.Beads, moleculeRootID string) (*MoleculePr
 
 	// Find all children of the root issue
 	children, err := b.List(beads.ListOptions{
-		Parent:   moleculeRootID,
-		Status:   "all",
-		Priority: -1,
+		Parent: moleculeRootID,
+		Status: "all",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listing children: %w", err)
@@ -895,9 +891,8 @@ func runMoleculeCurrent(cmd *cobra.Command, args []string) error {
 
 	// Find all children (steps) of the molecule root
 	children, err := b.List(beads.ListOptions{
-		Parent:   attachment.AttachedMolecule,
-		Status:   "all",
-		Priority: -1,
+		Parent: attachment.AttachedMolecule,
+		Status: "all",
 	})
 	if err != nil {
 		// No steps - just an issue, not a molecule instance
@@ -1083,7 +1078,6 @@ func scanAllRigsForHookedBeads(townRoot, target string) []*beads.Issue {
 		hookedBeads, err := b.List(beads.ListOptions{
 			Status:   beads.StatusHooked,
 			Assignee: target,
-			Priority: -1,
 		})
 		if err != nil {
 			continue
@@ -1097,7 +1091,6 @@ func scanAllRigsForHookedBeads(townRoot, target string) []*beads.Issue {
 		inProgressBeads, err := b.List(beads.ListOptions{
 			Status:   "in_progress",
 			Assignee: target,
-			Priority: -1,
 		})
 		if err != nil {
 			continue
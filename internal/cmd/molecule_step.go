@@ -213,9 +213,8 @@ func findAllReadySteps(b *beads.Beads, moleculeID string) ([]*beads.Issue, bool,
 	// bd ready --mol only returns open+unblocked steps, so it can't distinguish
 	// "all complete" from "all blocked".
 	children, err := b.List(beads.ListOptions{
-		Parent:   moleculeID,
-		Status:   "all",
-		Priority: -1,
+		Parent: moleculeID,
+		Status: "all",
 	})
 	if err != nil {
 		return nil, false, fmt.Errorf("listing molecule steps: %w", err)
@@ -457,7 +456,6 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 		pinnedBeads, err := b.List(beads.ListOptions{
 			Status:   beads.StatusPinned,
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if err == nil && len(pinnedBeads) > 0 {
 			// Unpin by setting status to open
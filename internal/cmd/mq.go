@@ -23,6 +23,9 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitChecks    []string
+	mqSubmitParent    string
+	mqSubmitAfter     []string
 
 	// Retry flags
 	mqRetryNow bool
@@ -68,7 +71,13 @@ var mqCmd = &cobra.Command{
 Alias: 'gt mr' is equivalent to 'gt mq' (merge request vs merge queue).
 
 The merge queue tracks work branches from polecats waiting to be merged.
-Use these commands to view, submit, retry, and manage merge requests.`,
+Use these commands to view, submit, retry, and manage merge requests.
+
+If the Dolt server is unreachable, 'gt mq list' and 'gt mq next' run by a
+human show the queue from a stale local snapshot (saved by the last
+successful 'gt mq list') with a warning, so the queue can still be triaged.
+In agent sessions (GT_ROLE set) they fail instead, so nothing acts on stale
+state. Changes fail until the server is back.`,
 }
 
 var mqSubmitCmd = &cobra.Command{
@@ -101,12 +110,40 @@ Polecat auto-cleanup:
   Use --no-cleanup to disable this behavior (e.g., if you want to submit
   multiple MRs or continue working).
 
+Required checks:
+  --check declares a check the Refinery must see pass before merging:
+    test              Run the rig's merge gates (or test_command)
+    test:<gate>       Run one named gate from merge_queue.gates
+    gh:<workflow>     Require a successful GitHub Actions run for the branch head
+    script:<path>     Run a script committed on the branch
+  The merge waits while a check is pending and fails if one fails. Results
+  are recorded on the MR (see 'gt mq show <id>').
+
+Reviewers:
+  When the town has an ownership map (see 'gt owners'), the owners of the
+  files the branch changes are recorded on the MR as its default reviewers.
+
+Stacked MRs:
+  --parent <mr-id> submits a branch built on another MR's branch. It takes
+  the parent's target and is blocked on the parent: the Refinery merges the
+  parent first, then rebases this branch onto the target (dropping the
+  parent's commits) and merges it. If the parent fails, the MRs stacked on
+  it are marked stuck; if it is rejected, they fail instead of merging.
+
+Merge order:
+  --after <mr-id> makes the Refinery merge this MR only after that one has
+  merged, without stacking on it (the branches stay independent). Use it
+  when the work depends on another MR landing first. See 'gt mq order'.
+
 Examples:
   gt mq submit                           # Auto-detect everything + auto-cleanup
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
-  gt mq submit --no-cleanup              # Submit without auto-cleanup`,
+  gt mq submit --no-cleanup              # Submit without auto-cleanup
+  gt mq submit --check gh:ci.yml --check script:scripts/e2e.sh
+  gt mq submit --parent gt-mr-abc        # Stack on an open MR
+  gt mq submit --after gt-mr-abc         # Merge only after gt-mr-abc`,
 	RunE: runMqSubmit,
 }
 
@@ -139,6 +176,9 @@ Output format:
   gt-mr-003   blocked      P1        polecat/Capable/gt-def    Capable 8m
               (waiting on gt-mr-001)
 
+AGE turns yellow once an MR is aging and red once it is stale, with
+thresholds per priority (P0 4h/1d up to P4 14d/90d; town settings "aging").
+
 Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
@@ -164,14 +204,29 @@ Examples:
 }
 
 var mqStatusCmd = &cobra.Command{
-	Use:   "status <id>",
-	Short: "Show detailed merge request status",
+	Use:     "show <id>",
+	Aliases: []string{"status"},
+	Short:   "Show detailed merge request status",
 	Long: `Display detailed information about a merge request.
 
-Shows all MR fields, current status with timestamps, dependencies,
-blockers, and processing history.
+Shows all MR fields, current status with timestamps, required checks
+and their latest results, dependencies, blockers, and processing history.
 
-Example:
+For context on the change it also shows, where it can find them:
+  - The linked source bead, with its status and assignee
+  - Files, insertions and deletions on the branch against its target
+    (from the refinery's clone)
+  - Merge attempts from the town event log, with conflict retries
+  - For open MRs, the position among ready MRs in the order the refinery
+    takes them, and an ETA from the rig's merges per day over the last week
+
+MRs submitted by agents list the sessions that produced the change, with
+the path of each session's transcript when it is on this machine. Use
+'gt seance --talk <session>' to ask a session about its work.
+
+Examples:
+  gt mq show gp-mr-abc123
+  gt mq show gp-mr-abc123 --json
   gt mq status gp-mr-abc123`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMqStatus,
@@ -285,6 +340,9 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().StringSliceVar(&mqSubmitChecks, "check", nil, "Required check before merge (test, test:<gate>, gh:<workflow>, script:<path>; repeatable)")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitParent, "parent", "", "Stack on this open MR: merge only after it lands")
+	mqSubmitCmd.Flags().StringSliceVar(&mqSubmitAfter, "after", nil, "Merge only after this MR has merged, without stacking (repeatable)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
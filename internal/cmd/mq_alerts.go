@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MQ alerts command flags
+var (
+	mqAlertsAll    bool
+	mqAlertsDryRun bool
+	mqAlertsJSON   bool
+)
+
+var mqAlertsCmd = &cobra.Command{
+	Use:   "alerts [rig]",
+	Short: "Check queue throughput and wait-time thresholds",
+	Long: `Check a rig's merge queue against its alert thresholds.
+
+Thresholds live in <rig>/settings/mq-alerts.json and are measured over a
+sliding window:
+
+  low-throughput  Work has been waiting a full window but fewer than
+                  min_processed MRs left the queue (refinery stuck)
+  high-wait       The oldest open MR has waited longer than max_wait
+                  (test gate hanging)
+
+Each breach is filed as an escalation bead (source mq:<rig>:<kind>) and
+routed through the escalation config like 'gt escalate'. An alert is not
+re-filed until its cooldown (default: the window) has passed. The daemon
+runs this check in the background every 10 minutes for rigs with a config
+file.
+
+Example config:
+  {"window": "1h", "min_processed": 1, "max_wait": "2h", "severity": "high", "cooldown": "4h"}
+
+Examples:
+  gt mq alerts gastown            # Check one rig and file escalations
+  gt mq alerts --all              # Check every rig with a config
+  gt mq alerts gastown --dry-run  # Show breaches without filing`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQAlerts,
+}
+
+func init() {
+	mqAlertsCmd.Flags().BoolVar(&mqAlertsAll, "all", false, "Check every rig with an alert config")
+	mqAlertsCmd.Flags().BoolVar(&mqAlertsDryRun, "dry-run", false, "Show breaches without filing escalations")
+	mqAlertsCmd.Flags().BoolVar(&mqAlertsJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqAlertsCmd)
+}
+
+// mqAlertResult is one rig's alert check, for JSON output.
+type mqAlertResult struct {
+	Rig         string     `json:"rig"`
+	Alerts      []mq.Alert `json:"alerts"`
+	Escalations []string   `json:"escalations,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+func runMQAlerts(cmd *cobra.Command, args []string) error {
+	if mqAlertsAll && len(args) > 0 {
+		return fmt.Errorf("cannot use --all with a rig name")
+	}
+
+	var rigs []*rig.Rig
+	if mqAlertsAll {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	} else {
+		rigName := ""
+		if len(args) > 0 {
+			rigName = args[0]
+		}
+		_, r, _, err := getRefineryManager(rigName)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	from := detectSender()
+	if from == "" {
+		from = "mq-alerts"
+	}
+
+	var results []mqAlertResult
+	failed := 0
+	for _, r := range rigs {
+		cfg, err := mq.LoadAlertConfig(r.Path)
+		if err != nil {
+			results = append(results, mqAlertResult{Rig: r.Name, Error: err.Error()})
+			failed++
+			continue
+		}
+		if cfg == nil {
+			if !mqAlertsAll {
+				return fmt.Errorf("rig %s has no alert config (%s)", r.Name, mq.AlertsPath(r.Path))
+			}
+			continue
+		}
+
+		result, err := checkMQAlerts(townRoot, escalationConfig, r, cfg, from)
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if mqAlertsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printMQAlertResults(results)
+	}
+
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// checkMQAlerts samples a rig's queue, evaluates its thresholds, and files
+// an escalation for each breach that is outside its cooldown.
+func checkMQAlerts(townRoot string, escalationConfig *config.EscalationConfig, r *rig.Rig, cfg *mq.AlertConfig, from string) (mqAlertResult, error) {
+	result := mqAlertResult{Rig: r.Name}
+	now := time.Now()
+
+	sample, err := sampleMergeQueue(beads.New(r.BeadsPath()))
+	if err != nil {
+		return result, err
+	}
+
+	state, err := mq.LoadAlertState(r.Path)
+	if err != nil {
+		return result, fmt.Errorf("loading alert state: %w", err)
+	}
+
+	result.Alerts = cfg.Due(state, cfg.Evaluate(sample, now), now)
+	if mqAlertsDryRun || len(result.Alerts) == 0 {
+		return result, nil
+	}
+
+	for _, alert := range result.Alerts {
+		issue, _, _, err := fileEscalation(townRoot, escalationConfig, escalationRequest{
+			Description: fmt.Sprintf("Merge queue %s on %s", alert.Kind, r.Name),
+			Severity:    alert.Severity,
+			Reason:      alert.Message,
+			Source:      fmt.Sprintf("mq:%s:%s", r.Name, alert.Kind),
+			From:        from,
+			RelatedBead: alert.MRID,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Escalations = append(result.Escalations, issue.ID)
+
+		// Record each alert as soon as it is filed, so a later failure
+		// doesn't cause it to be filed again before its cooldown.
+		state.LastFired[alert.Kind] = now
+		if err := state.Save(r.Path); err != nil {
+			return result, fmt.Errorf("saving alert state: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// sampleMergeQueue builds a queue sample from a rig's merge-request beads:
+// open MRs are waiting since creation, closed MRs were processed when closed.
+func sampleMergeQueue(b *beads.Beads) (mq.QueueSample, error) {
+	sample := mq.QueueSample{Waiting: map[string]time.Time{}}
+
+	open, err := b.List(beads.ListOptions{Label: "gt:merge-request", Status: "open"})
+	if err != nil {
+		return sample, fmt.Errorf("querying merge queue: %w", err)
+	}
+	for _, issue := range open {
+		if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+			sample.Waiting[issue.ID] = t
+		}
+	}
+
+	closed, err := b.List(beads.ListOptions{Label: "gt:merge-request", Status: "closed"})
+	if err != nil {
+		return sample, fmt.Errorf("querying processed MRs: %w", err)
+	}
+	for _, issue := range closed {
+		if t, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil {
+			sample.Processed = append(sample.Processed, t)
+		}
+	}
+	return sample, nil
+}
+
+func printMQAlertResults(results []mqAlertResult) {
+	if len(results) == 0 {
+		fmt.Println("No rigs have an alert config.")
+		return
+	}
+	for _, res := range results {
+		switch {
+		case res.Error != "":
+			fmt.Printf("%s %s: %s\n", style.ErrorPrefix, res.Rig, res.Error)
+		case len(res.Alerts) == 0:
+			fmt.Printf("%s %s: queue within thresholds\n", style.SuccessPrefix, res.Rig)
+		default:
+			for i, alert := range res.Alerts {
+				fmt.Printf("%s %s: %s — %s\n", style.WarningPrefix, res.Rig, alert.Kind, alert.Message)
+				if i < len(res.Escalations) {
+					fmt.Printf("    Escalated: %s\n", res.Escalations[i])
+				} else if mqAlertsDryRun {
+					fmt.Printf("    %s\n", style.Dim.Render("(dry run: not filed)"))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MQ archive command flags
+var (
+	mqArchiveOlderThan string
+	mqArchiveAll       bool
+	mqArchiveDryRun    bool
+	mqArchiveJSON      bool
+)
+
+var mqArchiveCmd = &cobra.Command{
+	Use:   "archive [rig]",
+	Short: "Move closed merge requests into a Dolt history table",
+	Long: `Archive closed merge requests (merged, rejected or superseded) older
+than --older-than.
+
+Each MR is written to the gt_mq_archive table in the rig's Dolt database,
+with its full description, and committed to Dolt history; only then is the
+bead deleted. The live queue stays small, so bd queries the refinery and
+'gt mq list' run stay fast, while the history remains queryable:
+
+  gt dolt sql
+  > SELECT worker, COUNT(*) FROM gt_mq_archive
+    WHERE close_reason = 'merged' GROUP BY worker;
+
+'gt mq stats' reads archived MRs alongside live ones. The daemon archives
+every rig daily with the default threshold.
+
+Examples:
+  gt mq archive                       # Current rig, closed over 7 days ago
+  gt mq archive gastown --older-than 30d --dry-run
+  gt mq archive --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQArchive,
+}
+
+func init() {
+	mqArchiveCmd.Flags().StringVar(&mqArchiveOlderThan, "older-than", "7d", "Archive MRs closed longer ago than this (e.g., 24h, 7d)")
+	mqArchiveCmd.Flags().BoolVar(&mqArchiveAll, "all", false, "Archive every rig")
+	mqArchiveCmd.Flags().BoolVarP(&mqArchiveDryRun, "dry-run", "n", false, "Show what would be archived without changing anything")
+	mqArchiveCmd.Flags().BoolVar(&mqArchiveJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqArchiveCmd)
+}
+
+// mqArchiveResult is what gt mq archive did for one rig.
+type mqArchiveResult struct {
+	Rig      string   `json:"rig"`
+	Archived []string `json:"archived"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func runMQArchive(cmd *cobra.Command, args []string) error {
+	if mqArchiveAll && len(args) > 0 {
+		return fmt.Errorf("cannot use --all with a rig name")
+	}
+	age, err := parseDuration(mqArchiveOlderThan)
+	if err != nil || age < 0 {
+		return fmt.Errorf("invalid --older-than %q", mqArchiveOlderThan)
+	}
+
+	var rigs []*rig.Rig
+	if mqArchiveAll {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	} else {
+		rigName := ""
+		if len(args) > 0 {
+			rigName = args[0]
+		}
+		_, r, _, err := getRefineryManager(rigName)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-age)
+	var results []mqArchiveResult
+	failed := false
+	for _, r := range rigs {
+		res := mqArchiveResult{Rig: r.Name, Archived: []string{}}
+		ids, err := archiveRigMRs(townRoot, r, cutoff, now, mqArchiveDryRun)
+		res.Archived = append(res.Archived, ids...)
+		if err != nil {
+			res.Error = err.Error()
+			failed = true
+		}
+		results = append(results, res)
+	}
+
+	if mqArchiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		verb := "Archived"
+		if mqArchiveDryRun {
+			verb = "Would archive"
+		}
+		for _, res := range results {
+			if res.Error != "" {
+				fmt.Printf("%s %s: %s\n", style.ErrorPrefix, res.Rig, res.Error)
+			}
+			if len(res.Archived) == 0 {
+				if res.Error == "" {
+					fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("%s: nothing closed over %s ago", res.Rig, mqArchiveOlderThan)))
+				}
+				continue
+			}
+			fmt.Printf("%s %s %d merge request(s) from %s to %s\n", style.SuccessPrefix, verb, len(res.Archived), res.Rig, doltserver.MQArchiveTable)
+			if mqArchiveDryRun {
+				for _, id := range res.Archived {
+					fmt.Printf("  %s\n", id)
+				}
+			}
+		}
+	}
+	if failed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// archiveRigMRs archives r's merge requests closed before cutoff, returning
+// their IDs. Beads are deleted only after the archive is committed; a bead
+// that fails to delete stays in the queue and is archived again next time.
+func archiveRigMRs(townRoot string, r *rig.Rig, cutoff, now time.Time, dryRun bool) ([]string, error) {
+	b := beads.New(r.BeadsPath())
+	issues, err := b.List(beads.ListOptions{Label: "gt:merge-request", Status: "closed"})
+	if err != nil {
+		return nil, fmt.Errorf("querying merge requests: %w", err)
+	}
+
+	var mrs []doltserver.ArchivedMR
+	var ids []string
+	for _, issue := range issues {
+		mr := archivedMR(issue, r.Name, now)
+		if mr.ClosedAt.IsZero() || !mr.ClosedAt.Before(cutoff) {
+			continue
+		}
+		mrs = append(mrs, mr)
+		ids = append(ids, issue.ID)
+	}
+	if dryRun || len(mrs) == 0 {
+		return ids, nil
+	}
+
+	if err := doltserver.ArchiveMRs(townRoot, r.Name, mrs); err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		if err := b.DeleteHard(id); err != nil {
+			return ids[:i], fmt.Errorf("deleting archived %s: %w", id, err)
+		}
+	}
+	return ids, nil
+}
+
+// archivedMR converts a closed merge-request bead to its archive row.
+func archivedMR(issue *beads.Issue, rigName string, now time.Time) doltserver.ArchivedMR {
+	mr := doltserver.ArchivedMR{
+		ID:          issue.ID,
+		Rig:         rigName,
+		Title:       issue.Title,
+		Priority:    issue.Priority,
+		ArchivedAt:  now.UTC(),
+		Description: issue.Description,
+	}
+	mr.CreatedAt, _ = time.Parse(time.RFC3339, issue.CreatedAt)
+	mr.ClosedAt, _ = time.Parse(time.RFC3339, issue.ClosedAt)
+	if fields := beads.ParseMRFields(issue); fields != nil {
+		mr.Branch = fields.Branch
+		mr.Target = fields.Target
+		mr.SourceIssue = fields.SourceIssue
+		mr.Worker = fields.Worker
+		mr.MergeCommit = fields.MergeCommit
+		mr.CloseReason = fields.CloseReason
+	}
+	return mr
+}
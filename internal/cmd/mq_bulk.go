@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ bulk command flags
+var (
+	mqBulkFilters    []string
+	mqBulkYes        bool
+	mqBulkReason     string
+	mqBulkCloseIssue bool
+	mqBulkKeepBranch bool
+)
+
+const mqBulkFilterHelp = `Filters (--filter key=value, repeatable, all must match):
+  worker=<name>     MR worker (e.g. Toast)
+  target=<glob>     Target branch (e.g. release/1.3, release/*)
+  branch=<glob>     Source branch (e.g. polecat/Toast/*)
+  issue=<id>        Source issue
+
+Every run first previews the matching MRs. In a terminal you are then
+asked to confirm; otherwise nothing changes unless --yes is given.`
+
+var mqCancelCmd = &cobra.Command{
+	Use:   "cancel [rig] <mr-id> | [rig] --filter key=value",
+	Short: "Cancel a queued MR, or every queued MR matching a filter",
+	Long: `Remove merge requests from the queue without merging.
+
+Give one MR ID, or sweep the queue of entries from a misbehaving worker or
+for an abandoned target with --filter. For each MR:
+  1. The MR is closed with reason "cancelled"
+  2. With --close-issue, its source issue is closed too
+  3. Its polecat branch is deleted from origin, unless --keep-branch
+
+The steps are all-or-nothing per MR: if one fails, the MR (and issue) are
+reopened. Workers are not notified (use 'gt mq reject' for that).
+
+` + mqBulkFilterHelp + `
+
+Examples:
+  gt mq cancel gt-mr-abc123
+  gt mq cancel gastown gt-mr-abc123 --close-issue
+  gt mq cancel --filter worker=polecat-7 --keep-branch
+  gt mq cancel gastown --filter target=release/1.3 --reason "release abandoned"
+  gt mq cancel --filter worker=Toast --filter branch='polecat/Toast/*' --yes`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMQBulk(args, "cancel")
+	},
+}
+
+var mqHoldCmd = &cobra.Command{
+	Use:   "hold [rig] --filter key=value",
+	Short: "Hold every queued MR matching a filter",
+	Long: `Put every queued merge request matching a filter on hold.
+
+The refinery skips held MRs and 'gt mq list' shows them as held. They stay
+in the queue until released with 'gt mq unhold'.
+
+` + mqBulkFilterHelp + `
+
+Examples:
+  gt mq hold --filter target=release/1.3
+  gt mq hold gastown --filter worker=polecat-7 --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMQBulk(args, "hold")
+	},
+}
+
+var mqUnholdCmd = &cobra.Command{
+	Use:   "unhold [rig] --filter key=value",
+	Short: "Release held MRs matching a filter",
+	Long: `Release held merge requests matching a filter so the refinery
+processes them again.
+
+` + mqBulkFilterHelp + `
+
+Examples:
+  gt mq unhold --filter target=release/1.3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMQBulk(args, "unhold")
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{mqCancelCmd, mqHoldCmd, mqUnholdCmd} {
+		c.Flags().StringArrayVar(&mqBulkFilters, "filter", nil, "Filter as key=value (repeatable)")
+		c.Flags().BoolVarP(&mqBulkYes, "yes", "y", false, "Apply after the preview without asking")
+		mqCmd.AddCommand(c)
+	}
+	_ = mqHoldCmd.MarkFlagRequired("filter")
+	_ = mqUnholdCmd.MarkFlagRequired("filter")
+	mqCancelCmd.Flags().StringVarP(&mqBulkReason, "reason", "r", "bulk cancel", "Cancel reason recorded on each MR")
+	mqCancelCmd.Flags().BoolVar(&mqBulkCloseIssue, "close-issue", false, "Also close each MR's source issue")
+	mqCancelCmd.Flags().BoolVar(&mqBulkKeepBranch, "keep-branch", false, "Don't delete the polecat branch from origin")
+}
+
+func runMQBulk(args []string, op string) error {
+	filter, err := refinery.ParseQueueFilter(mqBulkFilters)
+	if err != nil {
+		return err
+	}
+
+	// cancel also takes a single MR ID in place of --filter
+	rigName, mrID := "", ""
+	switch {
+	case len(args) == 2:
+		rigName, mrID = args[0], args[1]
+	case len(args) == 1 && op == "cancel" && filter.IsEmpty():
+		mrID = args[0]
+	case len(args) == 1:
+		rigName = args[0]
+	}
+	if mrID != "" && !filter.IsEmpty() {
+		return fmt.Errorf("give an MR ID or --filter, not both")
+	}
+	if mrID == "" && filter.IsEmpty() {
+		if op == "cancel" {
+			return fmt.Errorf("an MR ID or --filter is required")
+		}
+		return fmt.Errorf("--filter is required")
+	}
+
+	mgr, _, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	var matched []*refinery.MergeRequest
+	if mrID != "" {
+		mr, err := mgr.FindMR(mrID)
+		if err != nil {
+			return err
+		}
+		matched = []*refinery.MergeRequest{mr}
+	} else if matched, err = mgr.SelectMRs(filter); err != nil {
+		return fmt.Errorf("querying merge queue: %w", err)
+	}
+	var targets []*refinery.MergeRequest
+	for _, mr := range matched {
+		// hold/unhold skip MRs already in the requested state
+		if (op == "hold" && mr.Held) || (op == "unhold" && !mr.Held) {
+			continue
+		}
+		targets = append(targets, mr)
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("No merge requests in %s to %s (%d matched the filter).\n", rigName, op, len(matched))
+		return nil
+	}
+
+	fmt.Printf("%s Would %s %d merge request(s) in %s:\n\n", style.Bold.Render("📋"), op, len(targets), rigName)
+	for _, mr := range targets {
+		fmt.Printf("  %-14s %-32s %-12s → %s\n", mr.ID, mr.Branch, mr.Worker, mr.TargetBranch)
+	}
+	if op == "cancel" {
+		fmt.Println()
+		if mqBulkCloseIssue {
+			fmt.Println("  Source issues will be closed.")
+		}
+		if !mqBulkKeepBranch {
+			fmt.Println("  Polecat branches will be deleted from origin.")
+		}
+	}
+	fmt.Println()
+
+	if !mqBulkYes {
+		if !isStdinTerminal() {
+			fmt.Printf("Nothing changed. Re-run with %s to apply.\n", style.Bold.Render("--yes"))
+			return nil
+		}
+		if !promptYesNo(fmt.Sprintf("%s these %d merge request(s)?", op, len(targets))) {
+			fmt.Println("Aborted. Nothing changed.")
+			return nil
+		}
+	}
+
+	var result *refinery.BulkMRResult
+	switch op {
+	case "cancel":
+		result = mgr.CancelMRs(targets, refinery.CancelOptions{
+			Reason:     mqBulkReason,
+			CloseIssue: mqBulkCloseIssue,
+			KeepBranch: mqBulkKeepBranch,
+		})
+	case "hold":
+		result = mgr.HoldMRs(targets, true)
+	case "unhold":
+		result = mgr.HoldMRs(targets, false)
+	}
+
+	for _, id := range result.FailedIDs() {
+		fmt.Printf("  %s %s: %s\n", style.ErrorPrefix, id, result.Failed[id])
+	}
+	fmt.Printf("%s Applied %s to %d merge request(s)\n", style.SuccessPrefix, op, len(result.Done))
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d merge request(s) failed to %s", len(result.Failed), op)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MQ bump command flags
+var (
+	mqBumpRig      string
+	mqBumpTarget   string
+	mqBumpIssue    string
+	mqBumpPriority int
+	mqBumpChecks   []string
+)
+
+var mqBumpCmd = &cobra.Command{
+	Use:   "bump <spec>...",
+	Short: "Queue a dependency update for the refinery to make",
+	Long: `Queue a dependency update: a submodule pointer or go.mod bump that the
+refinery makes itself rather than merging a branch.
+
+Bumps made on a branch conflict with almost every other change to the same
+files. A bump MR carries only the spec; on every attempt the refinery cuts
+a fresh branch from the current target, applies the spec, runs the gates,
+and merges. A retry after the target moved regenerates the change, so it
+never conflicts.
+
+Specs:
+  submodule <path>@<ref>              Move the submodule at <path> to a
+                                      commit, tag, or branch of its origin
+  gomod <module>@<version> [dir=<d>]  go get <module>@<version> and
+                                      go mod tidy (in <d>, default the root)
+
+An MR whose specs change nothing fails with "nothing to update".
+
+Examples:
+  gt mq bump "gomod golang.org/x/net@v0.30.0"
+  gt mq bump "submodule vendor/beads@v0.9.2" --rig gastown
+  gt mq bump "gomod github.com/spf13/cobra@latest dir=tools" --check test`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMqBump,
+}
+
+func init() {
+	mqBumpCmd.Flags().StringVar(&mqBumpRig, "rig", "", "Rig to update (default: current rig)")
+	mqBumpCmd.Flags().StringVar(&mqBumpTarget, "target", "", "Target branch (default: the rig's default branch)")
+	mqBumpCmd.Flags().StringVar(&mqBumpIssue, "issue", "", "Issue the update is for (closed when it merges)")
+	mqBumpCmd.Flags().IntVarP(&mqBumpPriority, "priority", "p", 2, "Priority (0-4)")
+	mqBumpCmd.Flags().StringSliceVar(&mqBumpChecks, "check", nil, "Required check before merge (test, test:<gate>, script:<path>; repeatable)")
+
+	mqCmd.AddCommand(mqBumpCmd)
+}
+
+func runMqBump(cmd *cobra.Command, args []string) error {
+	specs, err := refinery.ParseDepSpecs(strings.Join(args, ";"))
+	if err != nil {
+		return err
+	}
+	if mqBumpPriority < 0 || mqBumpPriority > 4 {
+		return fmt.Errorf("--priority must be 0-4")
+	}
+
+	// gh: checks look at CI runs on the pushed branch, which a bump lacks
+	var requiredChecks []string
+	for _, c := range mqBumpChecks {
+		checkSpecs, err := refinery.ParseCheckSpecs(c)
+		if err != nil {
+			return err
+		}
+		for _, spec := range checkSpecs {
+			if spec.Kind == refinery.CheckKindGitHub {
+				return fmt.Errorf("--check %s: dependency updates have no pushed branch for CI to run on", spec)
+			}
+			requiredChecks = append(requiredChecks, spec.String())
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, rigName, err := getRefineryManager(mqBumpRig)
+	if err != nil {
+		return err
+	}
+	target := mqBumpTarget
+	if target == "" {
+		target = r.DefaultBranch()
+	}
+
+	if freeze, err := mq.ActiveFreeze(filepath.Join(townRoot, rigName), time.Now()); err != nil {
+		style.PrintWarning("could not check queue freeze: %v", err)
+	} else if freeze != nil {
+		return freeze.SubmitError(rigName)
+	}
+
+	fields := &beads.MRFields{
+		Target:      target,
+		SourceIssue: mqBumpIssue,
+		Rig:         rigName,
+		DepUpdate:   refinery.FormatDepSpecs(specs),
+	}
+	if len(requiredChecks) > 0 {
+		fields.RequiredChecks = strings.Join(requiredChecks, ", ")
+	}
+	bd := beads.New(r.Path)
+	mrIssue, err := bd.Create(beads.CreateOptions{
+		Title:       "Deps: " + refinery.DepSummary(specs),
+		Type:        "merge-request",
+		Priority:    mqBumpPriority,
+		Description: beads.FormatMRFields(fields),
+		Ephemeral:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("creating merge request bead: %w", err)
+	}
+	if mqBumpIssue != "" {
+		if err := bd.RecordMRLink(mqBumpIssue, mrIssue.ID); err != nil {
+			style.PrintWarning("could not record MR link in %s history: %v", mqBumpIssue, err)
+		}
+	}
+
+	nudgeRefinery(rigName, fmt.Sprintf("MR submitted: %s dep_update=%s", mrIssue.ID, fields.DepUpdate))
+	if hooks, err := mq.LoadDispatcher(rigName, filepath.Join(townRoot, rigName)); err != nil {
+		style.PrintWarning("could not load mq webhooks: %v", err)
+	} else if err := hooks.Dispatch(mq.EventSubmitted, mq.WebhookPayload{
+		MRID:        mrIssue.ID,
+		Target:      target,
+		SourceIssue: mqBumpIssue,
+	}); err != nil {
+		style.PrintWarning("mq webhook delivery: %v", err)
+	}
+
+	fmt.Printf("%s Queued dependency update\n", style.Bold.Render("✓"))
+	fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrIssue.ID))
+	for _, spec := range specs {
+		fmt.Printf("  Update: %s\n", spec)
+	}
+	fmt.Printf("  Target: %s\n", target)
+	fmt.Printf("  Priority: P%d\n", mqBumpPriority)
+	return nil
+}
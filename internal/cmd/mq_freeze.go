@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ freeze command flags
+var (
+	mqFreezeDrain  bool
+	mqFreezeUntil  string
+	mqFreezeReason string
+)
+
+var mqFreezeCmd = &cobra.Command{
+	Use:   "freeze [rig]",
+	Short: "Stop accepting merge queue submissions",
+	Long: `Freeze a rig's merge queue.
+
+A frozen queue accepts no new submissions: 'gt mq submit' refuses, and
+'gt done' pushes the branch but leaves the MR for the witness to resubmit.
+
+  gt mq freeze          Merges are held too; the queue stands still
+  gt mq freeze --drain  The refinery keeps merging what is already queued,
+                        so the queue empties (e.g., for a release cut)
+
+'gt mq list' and 'gt refinery ready' show a banner while the queue is
+frozen, and say when a draining queue is empty. With --until the freeze
+lifts itself at the deadline: a duration (2h), a time of day (17:30, today
+or tomorrow) or an RFC 3339 timestamp.
+
+Examples:
+  gt mq freeze gastown --drain --until 2h --reason "cutting v1.4"
+  gt mq freeze gastown --reason "main is broken"
+  gt mq unfreeze gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQFreeze,
+}
+
+var mqUnfreezeCmd = &cobra.Command{
+	Use:   "unfreeze [rig]",
+	Short: "Lift a merge queue freeze",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runMQUnfreeze,
+}
+
+func init() {
+	mqFreezeCmd.Flags().BoolVar(&mqFreezeDrain, "drain", false, "Keep merging queued MRs while refusing new ones")
+	mqFreezeCmd.Flags().StringVar(&mqFreezeUntil, "until", "", "Unfreeze automatically at this deadline (2h, 17:30, RFC 3339)")
+	mqFreezeCmd.Flags().StringVar(&mqFreezeReason, "reason", "", "Why the queue is frozen")
+
+	mqCmd.AddCommand(mqFreezeCmd)
+	mqCmd.AddCommand(mqUnfreezeCmd)
+}
+
+func runMQFreeze(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state := &mq.FreezeState{
+		Drain:    mqFreezeDrain,
+		Reason:   mqFreezeReason,
+		FrozenAt: now.UTC(),
+		FrozenBy: detectSender(),
+	}
+	if mqFreezeUntil != "" {
+		until, err := mq.ParseDeadline(mqFreezeUntil, now)
+		if err != nil {
+			return err
+		}
+		state.Until = until.UTC()
+	}
+
+	if err := mq.Freeze(r.Path, state); err != nil {
+		return fmt.Errorf("freezing merge queue: %w", err)
+	}
+	fmt.Printf("%s Merge queue for '%s' frozen\n", style.SuccessPrefix, rigName)
+	fmt.Printf("  %s\n", state.Banner(now))
+	return nil
+}
+
+func runMQUnfreeze(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	freeze, err := mq.ActiveFreeze(r.Path, time.Now())
+	if err != nil {
+		return err
+	}
+	if freeze == nil {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("Merge queue for '%s' is not frozen", rigName)))
+		return nil
+	}
+	if err := mq.Unfreeze(r.Path); err != nil {
+		return fmt.Errorf("unfreezing merge queue: %w", err)
+	}
+	fmt.Printf("%s Merge queue for '%s' unfrozen\n", style.SuccessPrefix, rigName)
+	return nil
+}
+
+// printFreezeBanner prints the queue's freeze, if any, above a listing.
+// A draining queue with nothing left in it is reported as drained.
+func printFreezeBanner(freeze *mq.FreezeState, empty bool) {
+	if freeze == nil {
+		return
+	}
+	now := time.Now()
+	fmt.Printf("  %s %s\n", style.WarningPrefix, freeze.Banner(now))
+	if freeze.Drain && empty {
+		fmt.Printf("  %s Queue drained\n", style.SuccessPrefix)
+	}
+	fmt.Println()
+}
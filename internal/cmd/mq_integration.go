@@ -552,9 +552,8 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 	// 4. Verify all epic children are closed
 	fmt.Printf("Checking epic children status...\n")
 	children, err := bd.List(beads.ListOptions{
-		Parent:   epicID,
-		Status:   "all",
-		Priority: -1,
+		Parent: epicID,
+		Status: "all",
 	})
 	if err != nil {
 		return fmt.Errorf("checking epic children: %w", err)
@@ -740,9 +739,8 @@ func findOpenMRsForIntegration(bd *beads.Beads, targetBranch string) ([]*beads.I
 	// List all merge requests at any priority (MRs have Type: "task" with label "gt:merge-request").
 	// Use Status "all" to catch in_progress MRs that the refinery may have picked up.
 	opts := beads.ListOptions{
-		Label:    "gt:merge-request",
-		Status:   "all",
-		Priority: -1,
+		Label:  "gt:merge-request",
+		Status: "all",
 	}
 	allMRs, err := bd.List(opts)
 	if err != nil {
@@ -881,9 +879,8 @@ func runMqIntegrationStatus(cmd *cobra.Command, args []string) error {
 
 	// Get all merge-request issues (MRs have Type: "task" with label "gt:merge-request")
 	allMRs, err := bd.List(beads.ListOptions{
-		Label:    "gt:merge-request",
-		Status:   "all",
-		Priority: -1,
+		Label:  "gt:merge-request",
+		Status: "all",
 	})
 	if err != nil {
 		return fmt.Errorf("querying merge requests: %w", err)
@@ -917,11 +914,9 @@ func runMqIntegrationStatus(cmd *cobra.Command, args []string) error {
 
 	// Query children of the epic to determine if ready to land
 	// Use status "all" to include both open and closed children
-	// Use Priority -1 to disable priority filtering
 	children, err := bd.List(beads.ListOptions{
-		Parent:   epicID,
-		Status:   "all",
-		Priority: -1,
+		Parent: epicID,
+		Status: "all",
 	})
 	childrenTotal := 0
 	childrenClosed := 0
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -36,10 +37,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build list options - query for merge-request label
-	// Priority -1 means no priority filter (otherwise 0 would filter to P0 only)
 	opts := beads.ListOptions{
-		Label:    "gt:merge-request",
-		Priority: -1,
+		Label: "gt:merge-request",
 	}
 
 	// Apply status filter if specified
@@ -51,27 +50,42 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	}
 
 	var issues []*beads.Issue
+	var openQueue []*beads.Issue // Full open queue, when this listing fetched it
+	var stale *beads.Snapshot    // Set when served from the outage snapshot
 
 	if mqListReady {
 		// Query all open MRs and filter out blocked ones manually.
 		// Cannot use b.Ready() because it excludes ephemeral beads,
 		// and MRs are ephemeral by design (see gt-t5t6y).
 		opts.Status = "open"
-		allOpen, err := b.List(opts)
+		allOpen, snap, err := listOrSnapshot(b, opts, mqSnapshotFile(r.Path))
 		if err != nil {
 			return fmt.Errorf("querying ready MRs: %w", err)
 		}
+		stale = snap
+		openQueue = allOpen
 		for _, issue := range allOpen {
 			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
 				continue // Skip blocked issues
 			}
+			if beads.HasLabel(issue, refinery.HeldLabel) {
+				continue // Skip held MRs
+			}
 			issues = append(issues, issue)
 		}
 	} else {
-		issues, err = b.List(opts)
+		issues, stale, err = listOrSnapshot(b, opts, mqSnapshotFile(r.Path))
 		if err != nil {
 			return fmt.Errorf("querying merge queue: %w", err)
 		}
+		if opts.Status == "open" {
+			openQueue = issues
+		}
+	}
+
+	// Keep a snapshot of the open queue for triage during a Dolt outage.
+	if openQueue != nil && stale == nil {
+		_ = beads.WriteSnapshot(mqSnapshotFile(r.Path), openQueue)
 	}
 
 	// Apply additional filters and calculate scores
@@ -176,6 +190,16 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	// Human-readable output
 	fmt.Printf("%s Merge queue for '%s':\n\n", style.Bold.Render("📋"), rigName)
+	if freeze, err := mq.ActiveFreeze(r.Path, now); err != nil {
+		style.PrintWarning("could not check queue freeze: %v", err)
+	} else {
+		printFreezeBanner(freeze, len(filtered) == 0)
+	}
+	if hold, err := mq.ActiveHold(r.Path, now); err != nil {
+		style.PrintWarning("could not check refinery pause: %v", err)
+	} else {
+		printHoldBanner(hold)
+	}
 
 	if len(filtered) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(empty)"))
@@ -194,9 +218,9 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	if mqListVerify {
 		columns = append(columns, style.Column{Name: "GIT", Width: 8})
 	}
-	columns = append(columns, style.Column{Name: "AGE", Width: 6, Align: style.AlignRight})
+	columns = append(columns, style.Column{Name: "AGE", Width: 6, Align: style.AlignRight, Age: true})
 
-	table := style.NewTable(columns...)
+	table := style.NewTable(columns...).SetNow(now)
 
 	// Add rows using scored items (already sorted by score)
 	for _, item := range scored {
@@ -206,7 +230,9 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		// Determine display status
 		displayStatus := issue.Status
 		if issue.Status == "open" {
-			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
+			if beads.HasLabel(issue, refinery.HeldLabel) {
+				displayStatus = "held"
+			} else if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
 				displayStatus = "blocked"
 			} else {
 				displayStatus = "ready"
@@ -222,6 +248,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			styledStatus = style.Warning.Render("active")
 		case "blocked":
 			styledStatus = style.Dim.Render("blocked")
+		case "held":
+			styledStatus = style.Warning.Render("held")
 		case "closed":
 			styledStatus = style.Dim.Render("closed")
 		}
@@ -267,20 +295,18 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Calculate age
-		age := formatMRAge(issue.CreatedAt)
-
 		// Truncate ID if needed
 		displayID := issue.ID
 		if len(displayID) > 12 {
 			displayID = displayID[:12]
 		}
 
-		// Build row with conditional GIT column
+		// Build row with conditional GIT column; the table fills in AGE
+		createdAt := parseCreatedAt(issue.CreatedAt)
 		if mqListVerify {
-			table.AddRow(displayID, scoreStr, priority, convoyDisplay, branch, styledStatus, gitStatus, style.Dim.Render(age))
+			table.AddAgedRow(createdAt, issue.Priority, displayID, scoreStr, priority, convoyDisplay, branch, styledStatus, gitStatus)
 		} else {
-			table.AddRow(displayID, scoreStr, priority, convoyDisplay, branch, styledStatus, style.Dim.Render(age))
+			table.AddAgedRow(createdAt, issue.Priority, displayID, scoreStr, priority, convoyDisplay, branch, styledStatus)
 		}
 	}
 
@@ -323,27 +349,25 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 // formatMRAge formats the age of an MR from its created_at timestamp.
 func formatMRAge(createdAt string) string {
+	t := parseCreatedAt(createdAt)
+	if t.IsZero() {
+		return "?"
+	}
+	return style.HumanizeAge(time.Since(t))
+}
+
+// parseCreatedAt parses a bead's created_at timestamp, returning the zero
+// time when it can't be parsed.
+func parseCreatedAt(createdAt string) time.Time {
 	t, err := time.Parse(time.RFC3339, createdAt)
 	if err != nil {
 		// Try other formats
 		t, err = time.Parse("2006-01-02T15:04:05Z", createdAt)
 		if err != nil {
-			return "?"
+			return time.Time{}
 		}
 	}
-
-	d := time.Since(t)
-
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	}
-	if d < time.Hour {
-		return fmt.Sprintf("%dm", int(d.Minutes()))
-	}
-	if d < 24*time.Hour {
-		return fmt.Sprintf("%dh", int(d.Hours()))
-	}
-	return fmt.Sprintf("%dd", int(d.Hours()/24))
+	return t
 }
 
 // outputJSON outputs data as JSON.
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ merge-order command flags
+var mqMergeOrderJSON bool
+
+// mqMergeOrderMaxFiles caps the shared files listed per collision.
+const mqMergeOrderMaxFiles = 3
+
+var mqMergeOrderCmd = &cobra.Command{
+	Use:   "merge-order [rig]",
+	Short: "Predict the order the queue will merge in, and which MRs will collide",
+	Long: `Simulate the Refinery processing the open queue, without touching it, and
+print the predicted merge order.
+
+The simulation takes MRs the way the Refinery does: MRs already claimed by
+a worker first, then the highest-scoring MR (see 'gt mq next') whose
+blockers, stack parent and merge order predecessors ('gt mq order') have
+merged. MRs nothing in the queue can free (held, blocked by a task, or
+caught in a merge order cycle) are listed as stuck.
+
+An MR that changes files an MR predicted to merge before it into the same
+target also changes is flagged as colliding: once that MR lands it has to
+be rebased and may conflict. Re-stack such branches ahead of time. Changed
+files are read from the Refinery clone's remote-tracking branches.
+
+Examples:
+  gt mq merge-order
+  gt mq merge-order gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQMergeOrder,
+}
+
+func init() {
+	mqMergeOrderCmd.Flags().BoolVar(&mqMergeOrderJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqMergeOrderCmd)
+}
+
+func runMQMergeOrder(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	sim, err := refinery.NewEngineer(r).SimulateMergeOrder()
+	if err != nil {
+		return err
+	}
+
+	if mqMergeOrderJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sim)
+	}
+
+	fmt.Printf("%s Predicted merge order for '%s':\n\n", style.Bold.Render("🔮"), rigName)
+	if len(sim.Order) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(nothing will merge)"))
+	}
+	colliding := 0
+	for i, p := range sim.Order {
+		line := fmt.Sprintf("  %d. [P%d] %s %s → %s", i+1, p.Priority, style.Bold.Render(p.ID), p.Branch, p.Target)
+		switch {
+		case p.InFlight:
+			line += " " + style.Dim.Render("(merging now)")
+		case len(p.After) > 0:
+			line += " " + style.Dim.Render("(after "+strings.Join(p.After, ", ")+")")
+		}
+		fmt.Println(line)
+		if len(p.Collisions) > 0 {
+			colliding++
+		}
+		for _, c := range p.Collisions {
+			files := c.Files
+			more := ""
+			if len(files) > mqMergeOrderMaxFiles {
+				more = fmt.Sprintf(" (+%d more)", len(files)-mqMergeOrderMaxFiles)
+				files = files[:mqMergeOrderMaxFiles]
+			}
+			fmt.Printf("     %s collides with %s: %s%s\n", style.WarningPrefix, c.With, strings.Join(files, ", "), more)
+		}
+	}
+
+	if len(sim.Stuck) > 0 {
+		fmt.Printf("\n%s Stuck:\n\n", style.Bold.Render("🚧"))
+		for _, s := range sim.Stuck {
+			fmt.Printf("  %s %s: %s\n", style.Bold.Render(s.ID), s.Branch, style.Dim.Render(s.Reason))
+		}
+	}
+
+	if len(sim.FilesUnknown) > 0 {
+		fmt.Println()
+		style.PrintWarning("could not read the changes of %s; collisions with them are not shown", strings.Join(sim.FilesUnknown, ", "))
+	}
+	if colliding > 0 {
+		fmt.Printf("\n%d MR(s) will need a rebase once the MRs ahead of them land.\n", colliding)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -60,28 +61,17 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 
 	// Query for open merge-requests (ready to process)
 	opts := beads.ListOptions{
-		Label:    "gt:merge-request",
-		Status:   "open",
-		Priority: -1, // No priority filter
+		Label:  "gt:merge-request",
+		Status: "open",
 	}
 
-	issues, err := b.List(opts)
+	issues, _, err := listOrSnapshot(b, opts, mqSnapshotFile(r.Path))
 	if err != nil {
 		return fmt.Errorf("querying merge queue: %w", err)
 	}
 
-	// Filter to only ready MRs (no blockers)
-	var ready []*beads.Issue
-	for _, issue := range issues {
-		// Skip closed MRs (workaround for bd list not respecting --status filter)
-		if issue.Status != "open" {
-			continue
-		}
-		if len(issue.BlockedBy) == 0 && issue.BlockedByCount == 0 {
-			ready = append(ready, issue)
-		}
-	}
-
+	now := time.Now()
+	ready := readyQueue(issues, mqNextStrategy, now)
 	if len(ready) == 0 {
 		if mqNextQuiet {
 			return nil // Silent exit
@@ -90,39 +80,6 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	now := time.Now()
-
-	// Sort based on strategy
-	if mqNextStrategy == "fifo" {
-		// FIFO: oldest first by creation time
-		sort.Slice(ready, func(i, j int) bool {
-			ti, _ := time.Parse(time.RFC3339, ready[i].CreatedAt)
-			tj, _ := time.Parse(time.RFC3339, ready[j].CreatedAt)
-			return ti.Before(tj)
-		})
-	} else {
-		// Priority: highest score first
-		type scoredIssue struct {
-			issue *beads.Issue
-			score float64
-		}
-		scored := make([]scoredIssue, len(ready))
-		for i, issue := range ready {
-			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
-			scored[i] = scoredIssue{issue: issue, score: score}
-		}
-
-		sort.Slice(scored, func(i, j int) bool {
-			return scored[i].score > scored[j].score
-		})
-
-		// Rebuild ready slice in sorted order
-		for i, s := range scored {
-			ready[i] = s.issue
-		}
-	}
-
 	// Get the top MR
 	next := ready[0]
 	fields := beads.ParseMRFields(next)
@@ -161,7 +118,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("  Age:      %s\n", formatMRAge(next.CreatedAt))
+	fmt.Printf("  Age:      %s\n", style.RenderAge(parseCreatedAt(next.CreatedAt), now, next.Priority))
 
 	if len(ready) > 1 {
 		fmt.Printf("\n  %s\n", style.Dim.Render(fmt.Sprintf("(%d more in queue)", len(ready)-1)))
@@ -169,3 +126,42 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// readyQueue returns the open MRs the refinery can take, in the order it
+// takes them: held and blocked MRs are left out, and the rest sorted by
+// strategy ("fifo" for oldest first, otherwise highest score first).
+func readyQueue(issues []*beads.Issue, strategy string, now time.Time) []*beads.Issue {
+	var ready []*beads.Issue
+	for _, issue := range issues {
+		// Skip closed MRs (workaround for bd list not respecting --status filter)
+		if issue.Status != "open" {
+			continue
+		}
+		if beads.HasLabel(issue, refinery.HeldLabel) {
+			continue
+		}
+		if len(issue.BlockedBy) == 0 && issue.BlockedByCount == 0 {
+			ready = append(ready, issue)
+		}
+	}
+
+	if strategy == "fifo" {
+		// FIFO: oldest first by creation time
+		sort.SliceStable(ready, func(i, j int) bool {
+			ti, _ := time.Parse(time.RFC3339, ready[i].CreatedAt)
+			tj, _ := time.Parse(time.RFC3339, ready[j].CreatedAt)
+			return ti.Before(tj)
+		})
+		return ready
+	}
+
+	// Priority: highest score first
+	scores := make(map[string]float64, len(ready))
+	for _, issue := range ready {
+		scores[issue.ID] = calculateMRScore(issue, beads.ParseMRFields(issue), now)
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return scores[ready[i].ID] > scores[ready[j].ID]
+	})
+	return ready
+}
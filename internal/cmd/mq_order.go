@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ order command flags
+var (
+	mqOrderRig   string
+	mqOrderClear bool
+)
+
+var mqOrderCmd = &cobra.Command{
+	Use:   "order <mr-id> <mr-id>...",
+	Short: "Require MRs to merge in a given order",
+	Long: `Require open merge requests to merge in the order given: each only after
+the one before it has merged. Unlike stacking (gt mq submit --parent), the
+branches stay independent; only the merge order is enforced.
+
+The Refinery passes an MR over while an MR it is ordered after is open. If
+that MR is rejected or closed without merging, the MR is held until its
+constraint is cleared; if it is superseded, its replacement is waited for.
+Constraints are added to the ones already declared, and refused if they
+would form a cycle.
+
+With --clear, the given MRs' constraints are dropped. To see the order the
+queue is predicted to merge in, run 'gt mq merge-order'.
+
+Examples:
+  gt mq order gt-mr-abc gt-mr-def              # abc merges before def
+  gt mq order gt-mr-abc gt-mr-def gt-mr-ghi    # abc, then def, then ghi
+  gt mq order --clear gt-mr-def                # def no longer waits`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMQOrder,
+}
+
+func init() {
+	mqOrderCmd.Flags().StringVar(&mqOrderRig, "rig", "", "Rig name (default: infer from current directory)")
+	mqOrderCmd.Flags().BoolVar(&mqOrderClear, "clear", false, "Drop the given MRs' merge order constraints")
+	mqCmd.AddCommand(mqOrderCmd)
+}
+
+func runMQOrder(cmd *cobra.Command, args []string) error {
+	mgr, _, _, err := getRefineryManager(mqOrderRig)
+	if err != nil {
+		return err
+	}
+
+	if mqOrderClear {
+		for _, id := range args {
+			cleared, err := mgr.ClearMergeOrder(id)
+			if err != nil {
+				return err
+			}
+			if len(cleared) == 0 {
+				fmt.Printf("%s %s has no merge order constraints\n", style.Dim.Render("○"), id)
+				continue
+			}
+			fmt.Printf("%s %s no longer waits for %s\n", style.SuccessPrefix, id, strings.Join(cleared, ", "))
+		}
+		return nil
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("give at least two MRs, in merge order (or --clear)")
+	}
+	changed, err := mgr.OrderMRs(args)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		fmt.Printf("%s Already ordered: %s\n", style.Dim.Render("○"), strings.Join(args, " → "))
+		return nil
+	}
+	fmt.Printf("%s Merge order: %s\n", style.SuccessPrefix, strings.Join(args, " → "))
+	return nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ pause command flags
+var (
+	mqPauseUntil  string
+	mqPauseReason string
+)
+
+var mqPauseCmd = &cobra.Command{
+	Use:   "pause [rig]",
+	Short: "Pause the refinery: defer merges, keep accepting submissions",
+	Long: `Pause a rig's refinery.
+
+A paused refinery merges nothing, but the queue keeps accepting
+submissions, so work piles up in order and merges once the pause lifts
+(unlike 'gt mq freeze', which refuses submissions). With --until the pause
+lifts itself at the deadline: a duration (2h), a time of day (17:30, today
+or tomorrow) or an RFC 3339 timestamp.
+
+Scheduled maintenance windows defer merges the same way without anyone
+running this. Set them in the merge_queue section of the rig's config.json:
+
+    "maintenance_windows": [
+      {"days": ["sun"], "start": "02:00", "end": "04:00",
+       "timezone": "America/New_York", "reason": "weekly backups"}
+    ]
+
+Days are mon..sun (empty = every day); end before start wraps midnight.
+
+'gt mq list', 'gt refinery ready' and 'gt status' show a banner while the
+refinery is paused or in a maintenance window.
+
+Examples:
+  gt mq pause gastown --reason "CI is down"
+  gt mq pause gastown --until 17:30
+  gt mq resume gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQPause,
+}
+
+var mqResumeCmd = &cobra.Command{
+	Use:   "resume [rig]",
+	Short: "Resume a paused refinery",
+	Long: `Lift a 'gt mq pause'. A maintenance window still defers merges until it
+ends; remove it from the rig's config.json to merge sooner.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQResume,
+}
+
+func init() {
+	mqPauseCmd.Flags().StringVar(&mqPauseUntil, "until", "", "Resume automatically at this deadline (2h, 17:30, RFC 3339)")
+	mqPauseCmd.Flags().StringVar(&mqPauseReason, "reason", "", "Why the refinery is paused")
+
+	mqCmd.AddCommand(mqPauseCmd)
+	mqCmd.AddCommand(mqResumeCmd)
+}
+
+func runMQPause(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state := &mq.PauseState{
+		Reason:   mqPauseReason,
+		PausedAt: now.UTC(),
+		PausedBy: detectSender(),
+	}
+	if mqPauseUntil != "" {
+		until, err := mq.ParseDeadline(mqPauseUntil, now)
+		if err != nil {
+			return err
+		}
+		state.Until = until.UTC()
+	}
+
+	if err := mq.Pause(r.Path, state); err != nil {
+		return fmt.Errorf("pausing refinery: %w", err)
+	}
+	fmt.Printf("%s Refinery for '%s' paused\n", style.SuccessPrefix, rigName)
+	hold := &mq.Hold{Kind: mq.HoldPaused, Reason: state.Reason, By: state.PausedBy, Until: state.Until}
+	fmt.Printf("  %s\n", hold.Banner(now))
+	return nil
+}
+
+func runMQResume(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	pause, err := mq.ActivePause(r.Path, now)
+	if err != nil {
+		return err
+	}
+	if pause == nil {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("Refinery for '%s' is not paused", rigName)))
+	} else {
+		if err := mq.Resume(r.Path); err != nil {
+			return fmt.Errorf("resuming refinery: %w", err)
+		}
+		fmt.Printf("%s Refinery for '%s' resumed\n", style.SuccessPrefix, rigName)
+	}
+	if hold, err := mq.ActiveHold(r.Path, now); err != nil {
+		style.PrintWarning("could not check maintenance windows: %v", err)
+	} else if hold != nil {
+		fmt.Printf("  %s %s\n", style.WarningPrefix, hold.Banner(now))
+	}
+	return nil
+}
+
+// printHoldBanner prints the refinery's pause or maintenance window, if
+// any, above a listing.
+func printHoldBanner(hold *mq.Hold) {
+	if hold == nil {
+		return
+	}
+	fmt.Printf("  %s %s\n\n", style.WarningPrefix, hold.Banner(time.Now()))
+}
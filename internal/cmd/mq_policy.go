@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ policy command flags
+var (
+	mqPolicyRig    string
+	mqPolicyJSON   bool
+	mqPolicyAs     string
+	mqPolicyRevoke bool
+)
+
+var mqPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect the rig's declarative merge policy",
+	RunE:  requireSubcommand,
+	Long: `Inspect the declarative merge policy for a rig.
+
+Merge policies live in <rig>/settings/merge-policy.json and are evaluated by a
+single engine. The refinery evaluates the policy before every merge; an MR
+that fails any rule is held in the queue and retried on the next poll. An
+unreadable policy file holds every MR until it is fixed.
+
+Supported rule types:
+
+  approvals         Require N approved-by:<who> labels on the MR
+  freshness         Require the MR to have been updated within max_age
+  test_gate         Block MRs carrying failing-gate labels (default: needs-fix)
+  window            Only allow merges during a day/time window (start == end = all day)
+  protected_branch  Block MRs targeting protected branches unless allow-labeled
+  lane              Only allow MRs at or above max_priority into matching branches
+
+Example policy:
+  {
+    "version": 1,
+    "rules": [
+      {"name": "two-approvals", "type": "approvals", "min_approvals": 2},
+      {"type": "freshness", "max_age": "72h"},
+      {"type": "window", "days": ["mon","tue","wed","thu","fri"], "start": "09:00", "end": "17:00"},
+      {"type": "protected_branch", "branches": ["release/*"], "allow_labels": ["release-ok"]}
+    ]
+  }
+
+Record approvals with 'gt mq policy approve <mr-id>'. An approvals rule with
+"carry_over": true lets approvals follow an MR to the MR that supersedes it
+('gt mq supersede'); approvals carry over only if every approvals rule
+allows it.`,
+}
+
+var mqPolicyCheckCmd = &cobra.Command{
+	Use:   "check <mr-id>",
+	Short: "Explain which policy rules pass or fail for a merge request",
+	Long: `Evaluate the rig's merge policy against a merge request.
+
+Every rule is evaluated and reported with the reason it passed or failed.
+Exits non-zero if any rule fails.
+
+Examples:
+  gt mq policy check gt-mr-abc123
+  gt mq policy check gt-mr-abc123 --rig gastown
+  gt mq policy check gt-mr-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqPolicyCheck,
+}
+
+var mqPolicyApproveCmd = &cobra.Command{
+	Use:   "approve <mr-id>",
+	Short: "Record an approval on a merge request",
+	Long: `Add an approved-by:<who> label to a merge request, counted by the
+policy's approvals rules. The approver defaults to your agent identity.
+
+Examples:
+  gt mq policy approve gt-mr-abc123
+  gt mq policy approve gt-mr-abc123 --as mayor
+  gt mq policy approve gt-mr-abc123 --revoke`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqPolicyApprove,
+}
+
+func init() {
+	mqPolicyApproveCmd.Flags().StringVar(&mqPolicyRig, "rig", "", "Rig name (default: infer from current directory)")
+	mqPolicyApproveCmd.Flags().StringVar(&mqPolicyAs, "as", "", "Approver name (default: your agent identity)")
+	mqPolicyApproveCmd.Flags().BoolVar(&mqPolicyRevoke, "revoke", false, "Remove the approval instead of adding it")
+	mqPolicyCheckCmd.Flags().StringVar(&mqPolicyRig, "rig", "", "Rig name (default: infer from current directory)")
+	mqPolicyCheckCmd.Flags().BoolVar(&mqPolicyJSON, "json", false, "Output as JSON")
+
+	mqPolicyCmd.AddCommand(mqPolicyCheckCmd)
+	mqPolicyCmd.AddCommand(mqPolicyApproveCmd)
+	mqCmd.AddCommand(mqPolicyCmd)
+}
+
+func runMqPolicyCheck(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	_, r, _, err := getRefineryManager(mqPolicyRig)
+	if err != nil {
+		return err
+	}
+
+	policy, err := mq.LoadPolicy(r.Path)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(r.BeadsPath())
+	issue, err := b.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return fmt.Errorf("merge request '%s' not found", mrID)
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+
+	eval := policy.Evaluate(policyEntryFromIssue(issue), time.Now())
+
+	if mqPolicyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(eval); err != nil {
+			return err
+		}
+	} else {
+		printPolicyEvaluation(eval)
+	}
+
+	if !eval.Allowed() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runMqPolicyApprove(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	_, r, _, err := getRefineryManager(mqPolicyRig)
+	if err != nil {
+		return err
+	}
+
+	approver := mqPolicyAs
+	if approver == "" {
+		approver = strings.TrimSuffix(detectActor(), "/")
+	}
+	if approver == "" || approver == "unknown" {
+		return fmt.Errorf("cannot determine approver identity; use --as")
+	}
+	if strings.ContainsAny(approver, ", \t\n") {
+		return fmt.Errorf("approver %q may not contain commas or whitespace", approver)
+	}
+	label := mq.ApprovalLabelPrefix + approver
+
+	b := beads.New(r.BeadsPath())
+	issue, err := b.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return fmt.Errorf("merge request '%s' not found", mrID)
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	if !beads.HasLabel(issue, "gt:merge-request") {
+		return fmt.Errorf("%s is not a merge request", mrID)
+	}
+
+	opts := beads.UpdateOptions{AddLabels: []string{label}}
+	if mqPolicyRevoke {
+		opts = beads.UpdateOptions{RemoveLabels: []string{label}}
+	}
+	if err := b.Update(mrID, opts); err != nil {
+		return fmt.Errorf("updating %s: %w", mrID, err)
+	}
+
+	if mqPolicyRevoke {
+		fmt.Printf("%s Revoked %s's approval of %s\n", style.SuccessPrefix, approver, mrID)
+	} else {
+		fmt.Printf("%s %s approved %s\n", style.SuccessPrefix, approver, mrID)
+	}
+	return nil
+}
+
+// policyEntryFromIssue builds a policy engine entry from an MR bead.
+func policyEntryFromIssue(issue *beads.Issue) *mq.Entry {
+	entry := &mq.Entry{
+		ID:       issue.ID,
+		Priority: issue.Priority,
+		Labels:   issue.Labels,
+	}
+	if fields := beads.ParseMRFields(issue); fields != nil {
+		entry.Branch = fields.Branch
+		entry.Target = fields.Target
+		entry.Worker = fields.Worker
+	}
+	if t, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+		entry.CreatedAt = t
+	}
+	if t, err := time.Parse(time.RFC3339, issue.UpdatedAt); err == nil {
+		entry.UpdatedAt = t
+	}
+	return entry
+}
+
+func printPolicyEvaluation(eval *mq.Evaluation) {
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Merge policy for"), eval.EntryID)
+	if len(eval.Results) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No policy rules configured"))
+		return
+	}
+	for _, r := range eval.Results {
+		icon := style.SuccessPrefix
+		if !r.Passed {
+			icon = style.ErrorPrefix
+		}
+		fmt.Printf("  %s %-20s %s\n", icon, r.Name, style.Dim.Render(r.Reason))
+	}
+	fmt.Println()
+	if eval.Allowed() {
+		fmt.Printf("%s All %d rule(s) pass\n", style.SuccessPrefix, len(eval.Results))
+	} else {
+		fmt.Printf("%s %d of %d rule(s) fail\n", style.ErrorPrefix, len(eval.Failed()), len(eval.Results))
+	}
+}
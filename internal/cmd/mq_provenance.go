@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ provenance command flags
+var (
+	mqProvenanceRig  string
+	mqProvenanceJSON bool
+)
+
+var mqProvenanceCmd = &cobra.Command{
+	Use:   "provenance <commit>",
+	Short: "Show how a commit was merged (MR, worker, approvals, tests, policy)",
+	Long: `Show the merge provenance the refinery recorded for a commit.
+
+When the refinery lands an MR it attaches a git note to the squash commit
+under refs/notes/gastown-provenance with the MR bead ID, source issue,
+worker, approvals, a digest of the quality gate results, the required
+checks and the merge policy evaluation. The notes are pushed to origin, so
+the history explains itself in any clone, without the town:
+
+  git fetch origin refs/notes/gastown-provenance:refs/notes/gastown-provenance
+  git log --notes=gastown-provenance
+
+This command reads the note from the git repository in the current
+directory, or the rig's clone with --rig, fetching the notes from origin
+if the commit's note isn't there yet.
+
+Examples:
+  gt mq provenance HEAD
+  gt mq provenance 1a2b3c4 --rig gastown
+  gt mq provenance 1a2b3c4 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqProvenance,
+}
+
+func init() {
+	mqProvenanceCmd.Flags().StringVar(&mqProvenanceRig, "rig", "", "Read from the rig's clone instead of the current directory")
+	mqProvenanceCmd.Flags().BoolVar(&mqProvenanceJSON, "json", false, "Output the raw provenance as JSON")
+	mqCmd.AddCommand(mqProvenanceCmd)
+}
+
+func runMqProvenance(cmd *cobra.Command, args []string) error {
+	commit := args[0]
+
+	dir := "."
+	if mqProvenanceRig != "" {
+		_, r, err := getRig(mqProvenanceRig)
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(r.Path, "mayor", "rig")
+	}
+	g := git.NewGit(dir)
+	if !g.IsRepo() {
+		return fmt.Errorf("%s is not a git repository", dir)
+	}
+
+	p, err := refinery.ReadProvenance(g, "origin", commit)
+	if err != nil {
+		if errors.Is(err, refinery.ErrNoProvenance) {
+			return fmt.Errorf("%s: %w (it may predate provenance or not have landed through the merge queue)", commit, err)
+		}
+		return err
+	}
+
+	if mqProvenanceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	}
+	printProvenance(commit, p)
+	return nil
+}
+
+func printProvenance(commit string, p *refinery.Provenance) {
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Merge provenance for"), commit)
+	fmt.Printf("  MR:        %s\n", p.MR)
+	if p.SourceIssue != "" {
+		fmt.Printf("  Issue:     %s\n", p.SourceIssue)
+	}
+	if p.Worker != "" {
+		fmt.Printf("  Worker:    %s\n", p.Worker)
+	}
+	if p.Rig != "" {
+		fmt.Printf("  Rig:       %s\n", p.Rig)
+	}
+	fmt.Printf("  Branch:    %s → %s\n", p.Branch, p.Target)
+	fmt.Printf("  Merged:    %s\n", p.MergedAt.Format("2006-01-02 15:04:05 MST"))
+	if p.Rebase != nil {
+		fmt.Printf("  Rebased:   onto %s (%d commit(s) behind)\n", shortDigest(p.Rebase.Onto), p.Rebase.Behind)
+	}
+	if len(p.Approvals) > 0 {
+		fmt.Printf("  Approvals: %s\n", strings.Join(p.Approvals, ", "))
+	} else {
+		fmt.Printf("  Approvals: %s\n", style.Dim.Render("none"))
+	}
+
+	if len(p.Tests) > 0 {
+		fmt.Printf("\n  %s %s\n", style.Bold.Render("Tests"), style.Dim.Render("digest "+shortDigest(p.TestsDigest)))
+		for _, t := range p.Tests {
+			icon := style.SuccessPrefix
+			if !t.Passed {
+				icon = style.ErrorPrefix
+			}
+			fmt.Printf("    %s %-20s %s\n", icon, t.Name, style.Dim.Render(t.Elapsed+"  output "+shortDigest(t.OutputSHA256)))
+		}
+	}
+	if len(p.Checks) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Required checks"))
+		for _, c := range p.Checks {
+			fmt.Printf("    %-22s %s %s\n", c.Check, c.State, style.Dim.Render(c.Detail))
+		}
+	}
+	if len(p.Policy) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Merge policy"))
+		for _, r := range p.Policy {
+			icon := style.SuccessPrefix
+			if !r.Passed {
+				icon = style.ErrorPrefix
+			}
+			fmt.Printf("    %s %-20s %s\n", icon, r.Name, style.Dim.Render(r.Reason))
+		}
+	}
+}
+
+// shortDigest abbreviates a hex digest for display.
+func shortDigest(d string) string {
+	if len(d) > 12 {
+		return d[:12]
+	}
+	return d
+}
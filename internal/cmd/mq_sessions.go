@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/runtime"
+)
+
+// MRSession is an agent session linked from a merge request, with its
+// transcript when it is on this machine.
+type MRSession struct {
+	ID         string `json:"id"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// mrSessions returns the agent sessions that produced the work on issueID,
+// for the MR's sessions field: those that reported cost against the issue,
+// oldest first, then the submitting session if it has not reported yet.
+func mrSessions(issueID string) []string {
+	var sessions []string
+	if dir := beadHistoryDir(issueID); dir != "" {
+		if reports, err := beads.ReadCostReports(dir); err == nil {
+			sessions = beads.IssueSessions(reports, issueID)
+		}
+	}
+	if current := runtime.SessionIDFromEnv(); current != "" {
+		for _, s := range sessions {
+			if s == current {
+				return sessions
+			}
+		}
+		sessions = append(sessions, current)
+	}
+	return sessions
+}
+
+// splitMRSessions splits an MR's sessions field.
+func splitMRSessions(field string) []string {
+	var sessions []string
+	for _, s := range strings.Split(field, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// resolveMRSessions finds the local transcript of each session in field.
+func resolveMRSessions(townRoot, field string) []MRSession {
+	var out []MRSession
+	for _, id := range splitMRSessions(field) {
+		out = append(out, MRSession{ID: id, Transcript: findSessionTranscript(townRoot, id)})
+	}
+	return out
+}
+
+// findSessionTranscript returns the path of a session's transcript: in the
+// account that holds it (see gt seance), else in the default Claude config
+// directory. It returns "" if the transcript is not on this machine.
+func findSessionTranscript(townRoot, sessionID string) string {
+	if strings.ContainsAny(sessionID, `/\`) {
+		return ""
+	}
+	if loc := findSessionLocation(townRoot, sessionID); loc != nil {
+		path := filepath.Join(loc.configDir, "projects", loc.projectDir, sessionID+".jsonl")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(home, ".claude", "projects", "*", sessionID+".jsonl"))
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
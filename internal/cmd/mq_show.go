@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// mqShowETAWindow is how far back gt mq show looks at merge throughput to
+// estimate when a queued MR will land.
+const mqShowETAWindow = 7 * 24 * time.Hour
+
+// MRDiffStat summarizes the changes on an MR's branch since it left its target.
+type MRDiffStat struct {
+	Files      int `json:"files"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// MRSourceBead is the work bead an MR lands.
+type MRSourceBead struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Type     string `json:"type,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// MRAttempt is one merge attempt from the town event log.
+type MRAttempt struct {
+	At     string `json:"at"`
+	Result string `json:"result"` // merged, failed, conflicted, skipped
+	Reason string `json:"reason,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// MRQueuePosition is where an open MR stands in its rig's queue.
+type MRQueuePosition struct {
+	Position     int           `json:"position,omitempty"` // 1 = next to merge; 0 when not ready
+	Ready        int           `json:"ready"`              // Ready MRs in the queue
+	NotReady     string        `json:"not_ready,omitempty"`
+	MergesPerDay float64       `json:"merges_per_day"`
+	ETA          time.Duration `json:"eta_ns,omitempty"`
+}
+
+// attemptResults maps merge event types to attempt results.
+var attemptResults = map[string]string{
+	events.TypeMerged:          "merged",
+	events.TypeMergeFailed:     "failed",
+	events.TypeMergeConflicted: "conflicted",
+	events.TypeMergeSkipped:    "skipped",
+}
+
+// addMRShowDetails fills in what gt mq show reports beyond the MR bead
+// itself: the linked source bead, the branch diff, merge attempts, and the
+// MR's place in the queue. Each part is best effort; what can't be looked
+// up from here is left out.
+func addMRShowDetails(output *MRStatusOutput, issue *beads.Issue, mrFields *beads.MRFields) {
+	if mrFields == nil {
+		return
+	}
+	if mrFields.SourceIssue != "" {
+		if bd, err := beadsForID(mrFields.SourceIssue); err == nil {
+			if src, err := bd.Show(mrFields.SourceIssue); err == nil {
+				output.SourceBead = &MRSourceBead{ID: src.ID, Title: src.Title, Status: src.Status, Type: src.Type, Assignee: src.Assignee}
+			}
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	output.Attempts = mrAttempts(townRoot, issue.ID)
+
+	rigName := mrFields.Rig
+	if rigName == "" {
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return
+	}
+	if mrFields.Branch != "" {
+		target := mrFields.Target
+		if target == "" {
+			target = "main"
+		}
+		output.Diff = mrDiffStat(git.NewGit(filepath.Join(r.Path, "refinery", "rig")), target, mrFields.Branch)
+	}
+	if issue.Status == "open" {
+		output.Queue = mrQueuePosition(townRoot, r, issue)
+	}
+}
+
+// mrAttempts returns id's merge attempts, oldest first.
+func mrAttempts(townRoot, id string) []MRAttempt {
+	filter, err := events.ParseFilter([]string{
+		"type=" + events.TypeMerged + "," + events.TypeMergeFailed + "," + events.TypeMergeConflicted + "," + events.TypeMergeSkipped,
+		"payload.mr=" + id,
+	})
+	if err != nil {
+		return nil
+	}
+	evs, _, err := events.ReadLast(filepath.Join(townRoot, events.EventsFile), 0, filter)
+	if err != nil {
+		return nil
+	}
+	var attempts []MRAttempt
+	for _, e := range evs {
+		a := MRAttempt{At: e.Timestamp, Result: attemptResults[e.Type]}
+		a.Reason, _ = e.Payload["reason"].(string)
+		a.Commit, _ = e.Payload["commit"].(string)
+		attempts = append(attempts, a)
+	}
+	return attempts
+}
+
+// mrDiffStat diffs branch against target in the refinery's clone, preferring
+// the remote-tracking refs the refinery merges from.
+func mrDiffStat(g *git.Git, target, branch string) *MRDiffStat {
+	ref := func(name string) string {
+		if ok, _ := g.RefExists("refs/remotes/origin/" + name); ok {
+			return "origin/" + name
+		}
+		return name
+	}
+	out, err := g.DiffShortStat(ref(target), ref(branch))
+	if err != nil {
+		return nil
+	}
+	return parseShortStat(out)
+}
+
+var shortStatRe = regexp.MustCompile(`(\d+) (file|insertion|deletion)`)
+
+// parseShortStat parses git diff --shortstat output, e.g.
+// "3 files changed, 10 insertions(+), 2 deletions(-)". Empty output is an
+// empty diff.
+func parseShortStat(out string) *MRDiffStat {
+	stat := &MRDiffStat{}
+	for _, m := range shortStatRe.FindAllStringSubmatch(out, -1) {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "file":
+			stat.Files = n
+		case "insertion":
+			stat.Insertions = n
+		case "deletion":
+			stat.Deletions = n
+		}
+	}
+	return stat
+}
+
+// mrQueuePosition ranks issue among r's ready MRs the way the refinery
+// takes them, and estimates when it merges from recent throughput.
+func mrQueuePosition(townRoot string, r *rig.Rig, issue *beads.Issue) *MRQueuePosition {
+	b := beads.New(r.BeadsPath())
+	opts := beads.ListOptions{Label: "gt:merge-request", Status: "open"}
+	issues, _, err := listOrSnapshot(b, opts, mqSnapshotFile(r.Path))
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	ready := readyQueue(issues, "priority", now)
+
+	q := &MRQueuePosition{Ready: len(ready)}
+	switch {
+	case beads.HasLabel(issue, refinery.HeldLabel):
+		q.NotReady = "held"
+	case len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0:
+		q.NotReady = "blocked"
+	default:
+		q.Position = queuePosition(ready, issue.ID)
+	}
+
+	since := now.Add(-mqShowETAWindow)
+	if records, err := mqStatsRecords(townRoot, []*rig.Rig{r}, since); err == nil {
+		q.MergesPerDay = mq.ComputeStats(records, since, now).PerDay
+	}
+	q.ETA = queueETA(q.Position, q.MergesPerDay)
+	return q
+}
+
+// queuePosition returns id's 1-based place in ready, or 0 if it isn't there.
+func queuePosition(ready []*beads.Issue, id string) int {
+	for i, issue := range ready {
+		if issue.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// queueETA estimates how long until the MR at position merges, at
+// mergesPerDay. It is zero when there is no position or no throughput to go by.
+func queueETA(position int, mergesPerDay float64) time.Duration {
+	if position <= 0 || mergesPerDay <= 0 {
+		return 0
+	}
+	return time.Duration(float64(position) * float64(24*time.Hour) / mergesPerDay)
+}
+
+// printMRShowDetails prints the sections addMRShowDetails filled in.
+func printMRShowDetails(output *MRStatusOutput, mrFields *beads.MRFields) {
+	if src := output.SourceBead; src != nil {
+		fmt.Printf("\n%s\n", style.Bold.Render("Source Bead"))
+		fmt.Printf("   %s %s: %s %s\n", getStatusIcon(src.Status), src.ID, truncateString(src.Title, 50),
+			style.Dim.Render("["+src.Status+"]"))
+		if src.Assignee != "" {
+			fmt.Printf("   Assignee: %s\n", src.Assignee)
+		}
+	}
+
+	if d := output.Diff; d != nil {
+		fmt.Printf("\n%s\n", style.Bold.Render("Changes"))
+		fmt.Printf("   %d files, %s, %s\n", d.Files,
+			style.Success.Render(fmt.Sprintf("+%d", d.Insertions)),
+			style.Error.Render(fmt.Sprintf("-%d", d.Deletions)))
+	}
+
+	if len(output.Attempts) > 0 || (mrFields != nil && mrFields.RetryCount > 0) {
+		fmt.Printf("\n%s\n", style.Bold.Render("Attempts"))
+		for _, a := range output.Attempts {
+			icon := "○"
+			switch a.Result {
+			case "merged":
+				icon = style.Success.Render("✓")
+			case "failed", "conflicted":
+				icon = style.Error.Render("✗")
+			}
+			line := fmt.Sprintf("   %s %-10s %s", icon, a.Result, style.Dim.Render(formatHistoryTime(a.At)))
+			if a.Reason != "" {
+				line += "  " + truncateString(a.Reason, 60)
+			}
+			fmt.Println(line)
+		}
+		if mrFields != nil && mrFields.RetryCount > 0 {
+			fmt.Printf("   Conflict retries: %d\n", mrFields.RetryCount)
+			if mrFields.LastConflictSHA != "" {
+				fmt.Printf("   Last conflict at: %s\n", mrFields.LastConflictSHA)
+			}
+			if mrFields.ConflictTaskID != "" {
+				fmt.Printf("   Conflict task:    %s\n", mrFields.ConflictTaskID)
+			}
+		}
+	}
+
+	if q := output.Queue; q != nil {
+		fmt.Printf("\n%s\n", style.Bold.Render("Queue"))
+		switch {
+		case q.NotReady != "":
+			fmt.Printf("   Not ready: %s %s\n", q.NotReady, style.Dim.Render(fmt.Sprintf("(%d ready in queue)", q.Ready)))
+		case q.Position > 0:
+			fmt.Printf("   Position: %d of %d\n", q.Position, q.Ready)
+		}
+		if q.ETA > 0 {
+			fmt.Printf("   ETA:      ~%s %s\n", formatDuration(q.ETA),
+				style.Dim.Render(fmt.Sprintf("(at %.1f merges/day)", q.MergesPerDay)))
+		} else if q.Position > 0 {
+			fmt.Printf("   ETA:      %s\n", style.Dim.Render("unknown (no merges in the last 7 days)"))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestParseShortStat(t *testing.T) {
+	tests := []struct {
+		out  string
+		want MRDiffStat
+	}{
+		{" 3 files changed, 10 insertions(+), 2 deletions(-)", MRDiffStat{Files: 3, Insertions: 10, Deletions: 2}},
+		{" 1 file changed, 1 insertion(+)", MRDiffStat{Files: 1, Insertions: 1}},
+		{" 2 files changed, 7 deletions(-)", MRDiffStat{Files: 2, Deletions: 7}},
+		{"", MRDiffStat{}},
+	}
+	for _, tt := range tests {
+		if got := parseShortStat(tt.out); *got != tt.want {
+			t.Errorf("parseShortStat(%q) = %+v, want %+v", tt.out, *got, tt.want)
+		}
+	}
+}
+
+func TestReadyQueuePosition(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-time.Hour).Format(time.RFC3339)
+	issues := []*beads.Issue{
+		{ID: "gt-low", Status: "open", Priority: 3, CreatedAt: created},
+		{ID: "gt-held", Status: "open", Priority: 0, CreatedAt: created, Labels: []string{refinery.HeldLabel}},
+		{ID: "gt-blocked", Status: "open", Priority: 0, CreatedAt: created, BlockedByCount: 1},
+		{ID: "gt-high", Status: "open", Priority: 0, CreatedAt: created},
+		{ID: "gt-done", Status: "closed", Priority: 0, CreatedAt: created},
+	}
+
+	ready := readyQueue(issues, "priority", now)
+	if len(ready) != 2 {
+		t.Fatalf("readyQueue returned %d MRs, want 2", len(ready))
+	}
+	if got := queuePosition(ready, "gt-high"); got != 1 {
+		t.Errorf("position of gt-high = %d, want 1", got)
+	}
+	if got := queuePosition(ready, "gt-low"); got != 2 {
+		t.Errorf("position of gt-low = %d, want 2", got)
+	}
+	if got := queuePosition(ready, "gt-held"); got != 0 {
+		t.Errorf("position of held MR = %d, want 0", got)
+	}
+}
+
+func TestQueueETA(t *testing.T) {
+	if got := queueETA(2, 4); got != 12*time.Hour {
+		t.Errorf("queueETA(2, 4) = %v, want 12h", got)
+	}
+	if got := queueETA(3, 0); got != 0 {
+		t.Errorf("queueETA with no throughput = %v, want 0", got)
+	}
+	if got := queueETA(0, 4); got != 0 {
+		t.Errorf("queueETA for unqueued MR = %v, want 0", got)
+	}
+}
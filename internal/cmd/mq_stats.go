@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// MQ stats command flags
+var (
+	mqStatsAll   bool
+	mqStatsSince string
+	mqStatsJSON  bool
+)
+
+var mqStatsCmd = &cobra.Command{
+	Use:   "stats [rig]",
+	Short: "Show merge queue throughput and latency",
+	Long: `Show whether the refinery is keeping up: merges per day, time in queue,
+and failure and conflict rates by worker and rig.
+
+Merges and time in queue (MR creation to merge) come from the rig's
+merge-request beads, including those moved to Dolt by 'gt mq archive'.
+Failed and conflicted merge attempts come from the town event log, so they
+only cover the period the log has been recorded.
+
+Rates are per attempt: a merge, a failed attempt (build, test, or push),
+and a conflicted attempt each count as one.
+
+Examples:
+  gt mq stats                 # Current rig, last 7 days
+  gt mq stats gastown --since 30d
+  gt mq stats --all           # Every rig, with a per-rig breakdown
+  gt mq stats --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQStats,
+}
+
+func init() {
+	mqStatsCmd.Flags().BoolVar(&mqStatsAll, "all", false, "Aggregate across every rig")
+	mqStatsCmd.Flags().StringVar(&mqStatsSince, "since", "7d", "Window to report on (e.g., 24h, 7d, 30d)")
+	mqStatsCmd.Flags().BoolVar(&mqStatsJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqStatsCmd)
+}
+
+func runMQStats(cmd *cobra.Command, args []string) error {
+	if mqStatsAll && len(args) > 0 {
+		return fmt.Errorf("cannot use --all with a rig name")
+	}
+	window, err := parseDuration(mqStatsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q", mqStatsSince)
+	}
+
+	var rigs []*rig.Rig
+	if mqStatsAll {
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	} else {
+		rigName := ""
+		if len(args) > 0 {
+			rigName = args[0]
+		}
+		_, r, _, err := getRefineryManager(rigName)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	until := time.Now()
+	since := until.Add(-window)
+	records, err := mqStatsRecords(townRoot, rigs, since)
+	if err != nil {
+		return err
+	}
+	stats := mq.ComputeStats(records, since, until)
+
+	if mqStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	scope := rigs[0].Name
+	if mqStatsAll {
+		scope = fmt.Sprintf("%d rigs", len(rigs))
+	}
+	printMQStats(stats, scope, mqStatsSince)
+	return nil
+}
+
+// mqStatsRecords builds MR records for rigs from their merge-request beads,
+// live and archived, and attributes failed and conflicted attempts since the window start from
+// the town event log.
+func mqStatsRecords(townRoot string, rigs []*rig.Rig, since time.Time) ([]mq.MRRecord, error) {
+	var records []mq.MRRecord
+	index := make(map[string]int)
+	rigNames := make(map[string]bool)
+
+	for _, r := range rigs {
+		rigNames[r.Name] = true
+		b := beads.New(r.BeadsPath())
+		for _, status := range []string{"open", "in_progress", "closed"} {
+			issues, err := b.List(beads.ListOptions{Label: "gt:merge-request", Status: status})
+			if err != nil {
+				return nil, fmt.Errorf("querying %s merge requests: %w", r.Name, err)
+			}
+			for _, issue := range issues {
+				rec := mq.MRRecord{ID: issue.ID, Rig: r.Name}
+				rec.CreatedAt, _ = time.Parse(time.RFC3339, issue.CreatedAt)
+				if status == "closed" {
+					rec.ClosedAt, _ = time.Parse(time.RFC3339, issue.ClosedAt)
+					// Old MRs closed before the window carry no stats.
+					if rec.ClosedAt.Before(since) {
+						continue
+					}
+				}
+				if fields := beads.ParseMRFields(issue); fields != nil {
+					rec.Worker = fields.Worker
+					rec.CloseReason = fields.CloseReason
+				}
+				index[rec.ID] = len(records)
+				records = append(records, rec)
+			}
+		}
+
+		// MRs moved out of the queue by gt mq archive.
+		archived, err := doltserver.ArchivedMRs(townRoot, r.Name, since)
+		if err != nil {
+			return nil, fmt.Errorf("querying %s archived merge requests: %w", r.Name, err)
+		}
+		for _, mr := range archived {
+			if _, ok := index[mr.ID]; ok {
+				continue
+			}
+			index[mr.ID] = len(records)
+			records = append(records, mq.MRRecord{ID: mr.ID, Rig: r.Name, Worker: mr.Worker,
+				CreatedAt: mr.CreatedAt, ClosedAt: mr.ClosedAt, CloseReason: mr.CloseReason})
+		}
+	}
+
+	filter, err := events.ParseFilter([]string{"type=" + events.TypeMergeFailed + "," + events.TypeMergeConflicted})
+	if err != nil {
+		return nil, err
+	}
+	filter.Since = since
+	attempts, _, err := events.ReadLast(filepath.Join(townRoot, events.EventsFile), 0, filter)
+	if err != nil {
+		return nil, fmt.Errorf("reading event log: %w", err)
+	}
+	for _, e := range attempts {
+		mrID, _ := e.Payload["mr"].(string)
+		i, ok := index[mrID]
+		if !ok {
+			// The MR bead is gone or predates the window; keep the attempt
+			// if it belongs to one of the rigs being reported.
+			rigName, _ := e.Payload["rig"].(string)
+			if rigName == "" {
+				rigName, _, _ = strings.Cut(e.Actor, "/")
+			}
+			if mrID == "" || !rigNames[rigName] {
+				continue
+			}
+			worker, _ := e.Payload["worker"].(string)
+			i = len(records)
+			index[mrID] = i
+			records = append(records, mq.MRRecord{ID: mrID, Rig: rigName, Worker: worker})
+		}
+		if e.Type == events.TypeMergeConflicted {
+			records[i].Conflicts++
+		} else {
+			records[i].Failures++
+		}
+	}
+	return records, nil
+}
+
+func printMQStats(s *mq.QueueStats, scope, window string) {
+	fmt.Printf("%s %s %s\n\n", style.Bold.Render("Merge queue:"), scope, style.Dim.Render("(last "+window+")"))
+
+	t := s.Total
+	fmt.Printf("  Merged:         %d %s\n", t.Merged, style.Dim.Render(fmt.Sprintf("(%.1f/day)", s.PerDay)))
+	if t.Merged > 0 {
+		fmt.Printf("  Time in queue:  median %s, p90 %s\n", formatDuration(t.MedianWait), formatDuration(t.P90Wait))
+	}
+	fmt.Printf("  Failure rate:   %s\n", formatMQRate(t.FailureRate, t.Failed, t.Attempts))
+	fmt.Printf("  Conflict rate:  %s\n", formatMQRate(t.ConflictRate, t.Conflicts, t.Attempts))
+	if t.Rejected > 0 {
+		fmt.Printf("  Closed unmerged: %d\n", t.Rejected)
+	}
+	if s.Open > 0 {
+		fmt.Printf("  Open now:       %d %s\n", s.Open, style.Dim.Render("(oldest waiting "+formatDuration(s.OldestWait)+")"))
+	} else {
+		fmt.Printf("  Open now:       0\n")
+	}
+
+	if len(s.Daily) > 1 {
+		peak := 1
+		for _, d := range s.Daily {
+			peak = max(peak, d.Merged)
+		}
+		fmt.Printf("\n  %s\n", style.Bold.Render("Merges per day"))
+		for _, d := range s.Daily {
+			bar := strings.Repeat("█", (d.Merged*30+peak-1)/peak)
+			fmt.Printf("    %s  %-30s %d\n", d.Date, bar, d.Merged)
+		}
+	}
+
+	printMQStatsGroups("Worker", s.ByWorker)
+	if len(s.ByRig) > 1 {
+		printMQStatsGroups("Rig", s.ByRig)
+	}
+}
+
+func printMQStatsGroups(title string, groups []mq.GroupStats) {
+	if len(groups) == 0 {
+		return
+	}
+	width := len(title)
+	for _, g := range groups {
+		width = max(width, len(g.Name))
+	}
+	fmt.Printf("\n  %-*s  %6s  %6s  %9s  %5s  %5s  %s\n", width, strings.ToUpper(title),
+		"MERGED", "FAILED", "CONFLICTS", "FAIL%", "CONF%", "MEDIAN WAIT")
+	for _, g := range groups {
+		wait := "-"
+		if g.Merged > 0 {
+			wait = formatDuration(g.MedianWait)
+		}
+		fmt.Printf("  %-*s  %6d  %6d  %9d  %4.0f%%  %4.0f%%  %s\n", width, g.Name,
+			g.Merged, g.Failed, g.Conflicts, g.FailureRate*100, g.ConflictRate*100, wait)
+	}
+}
+
+func formatMQRate(rate float64, n, attempts int) string {
+	if attempts == 0 {
+		return style.Dim.Render("no attempts")
+	}
+	text := fmt.Sprintf("%.0f%% (%d of %d attempts)", rate*100, n, attempts)
+	if rate >= 0.25 {
+		return style.Warning.Render(text)
+	}
+	return text
+}
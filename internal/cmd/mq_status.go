@@ -9,10 +9,12 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// MRStatusOutput is the JSON output structure for gt mq status.
+// MRStatusOutput is the JSON output structure for gt mq show.
 type MRStatusOutput struct {
 	// Core issue fields
 	ID        string `json:"id"`
@@ -34,6 +36,44 @@ type MRStatusOutput struct {
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
 
+	// Default reviewers from the town ownership map
+	Reviewers []string `json:"reviewers,omitempty"`
+
+	// Stacked MRs
+	ParentMR  string `json:"parent_mr,omitempty"`
+	StackBase string `json:"stack_base,omitempty"`
+
+	// Merge order (gt mq order)
+	MergeAfter []string `json:"merge_after,omitempty"`
+
+	// Dependency updates the refinery makes itself (gt mq bump)
+	DepUpdate string `json:"dep_update,omitempty"`
+
+	// Supersession (gt mq supersede)
+	Supersedes   string `json:"supersedes,omitempty"`
+	SupersededBy string `json:"superseded_by,omitempty"`
+
+	// Required checks
+	RequiredChecks []string               `json:"required_checks,omitempty"`
+	CheckResults   []refinery.CheckResult `json:"check_results,omitempty"`
+	ChecksAt       string                 `json:"checks_at,omitempty"`
+
+	// Linked source bead and the branch's changes against its target
+	SourceBead *MRSourceBead `json:"source_bead,omitempty"`
+	Diff       *MRDiffStat   `json:"diff,omitempty"`
+
+	// Merge attempts and conflict retries
+	Attempts        []MRAttempt `json:"attempts,omitempty"`
+	RetryCount      int         `json:"retry_count,omitempty"`
+	LastConflictSHA string      `json:"last_conflict_sha,omitempty"`
+	ConflictTaskID  string      `json:"conflict_task_id,omitempty"`
+
+	// Place in the rig's queue, for open MRs
+	Queue *MRQueuePosition `json:"queue,omitempty"`
+
+	// Agent sessions that produced the change, with local transcripts
+	Sessions []MRSession `json:"sessions,omitempty"`
+
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
 	Blocks    []DependencyInfo `json:"blocks,omitempty"`
@@ -95,6 +135,31 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.ParentMR = mrFields.Parent
+		output.StackBase = mrFields.StackBase
+		output.MergeAfter = refinery.ParseMergeAfter(mrFields.MergeAfter)
+		output.DepUpdate = mrFields.DepUpdate
+		output.Supersedes = mrFields.Supersedes
+		output.SupersededBy = mrFields.SupersededBy
+		for _, r := range strings.Split(mrFields.Reviewers, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				output.Reviewers = append(output.Reviewers, r)
+			}
+		}
+		for _, c := range strings.Split(mrFields.RequiredChecks, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				output.RequiredChecks = append(output.RequiredChecks, c)
+			}
+		}
+		output.CheckResults = refinery.ParseCheckResults(mrFields.CheckResults)
+		output.ChecksAt = mrFields.ChecksAt
+		output.RetryCount = mrFields.RetryCount
+		output.LastConflictSHA = mrFields.LastConflictSHA
+		output.ConflictTaskID = mrFields.ConflictTaskID
+		if mrFields.Sessions != "" {
+			townRoot, _ := workspace.FindFromCwd()
+			output.Sessions = resolveMRSessions(townRoot, mrFields.Sessions)
+		}
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -119,6 +184,8 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	addMRShowDetails(&output, issue, mrFields)
+
 	// JSON output
 	if mqStatusJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -127,11 +194,11 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
-	return printMqStatus(issue, mrFields)
+	return printMqStatus(issue, mrFields, &output)
 }
 
 // printMqStatus prints detailed MR status in human-readable format.
-func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
+func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields, output *MRStatusOutput) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("📋 Merge Request:"), issue.ID)
 	fmt.Printf("   %s\n\n", issue.Title)
@@ -184,6 +251,70 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.CloseReason != "" {
 			fmt.Printf("   Close Reason: %s\n", mrFields.CloseReason)
 		}
+		if mrFields.Reviewers != "" {
+			fmt.Printf("   Reviewers:    %s\n", mrFields.Reviewers)
+		}
+		if mrFields.Parent != "" {
+			fmt.Printf("   Stacked on:   %s\n", mrFields.Parent)
+		}
+		if mrFields.MergeAfter != "" {
+			fmt.Printf("   Merges after: %s\n", mrFields.MergeAfter)
+		}
+		if mrFields.DepUpdate != "" {
+			fmt.Printf("   Update:       %s\n", mrFields.DepUpdate)
+		}
+		if mrFields.Supersedes != "" {
+			fmt.Printf("   Supersedes:   %s\n", mrFields.Supersedes)
+		}
+		if mrFields.SupersededBy != "" {
+			fmt.Printf("   Replaced by:  %s\n", mrFields.SupersededBy)
+		}
+	}
+
+	// Required checks and their latest results
+	if mrFields != nil && mrFields.RequiredChecks != "" {
+		results := make(map[string]string)
+		for _, r := range refinery.ParseCheckResults(mrFields.CheckResults) {
+			results[r.Check] = r.State
+		}
+		header := style.Bold.Render("Required Checks")
+		if mrFields.ChecksAt != "" {
+			header += " " + style.Dim.Render("(checked "+mrFields.ChecksAt+" "+formatTimeAgo(mrFields.ChecksAt)+")")
+		}
+		fmt.Printf("\n%s\n", header)
+		for _, c := range strings.Split(mrFields.RequiredChecks, ",") {
+			c = strings.TrimSpace(c)
+			if c == "" {
+				continue
+			}
+			state := results[c]
+			icon := "○"
+			switch state {
+			case refinery.CheckPass:
+				icon = style.Success.Render("✓")
+			case refinery.CheckFail:
+				icon = style.Error.Render("✗")
+			case "":
+				state = "not run"
+			}
+			fmt.Printf("   %s %s %s\n", icon, c, style.Dim.Render("["+state+"]"))
+		}
+	}
+
+	printMRShowDetails(output, mrFields)
+
+	// Agent sessions, so reviewers can read what the agent was thinking
+	if len(output.Sessions) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Sessions"))
+		for _, sess := range output.Sessions {
+			fmt.Printf("   %s\n", sess.ID)
+			if sess.Transcript != "" {
+				fmt.Printf("     %s\n", style.Dim.Render(sess.Transcript))
+			} else {
+				fmt.Printf("     %s\n", style.Dim.Render("transcript not on this machine"))
+			}
+		}
+		fmt.Printf("   %s\n", style.Dim.Render("Ask a session about its work: gt seance --talk <id>"))
 	}
 
 	// Dependencies (what this MR is waiting on)
@@ -314,20 +445,30 @@ func getDescriptionWithoutMRFields(description string) string {
 
 	// Known MR field keys (lowercase)
 	mrKeys := map[string]bool{
-		"branch":       true,
-		"target":       true,
-		"source_issue": true,
-		"source-issue": true,
-		"sourceissue":  true,
-		"worker":       true,
-		"rig":          true,
-		"merge_commit": true,
-		"merge-commit": true,
-		"mergecommit":  true,
-		"close_reason": true,
-		"close-reason": true,
-		"closereason":  true,
-		"type":         true,
+		"branch":          true,
+		"target":          true,
+		"source_issue":    true,
+		"source-issue":    true,
+		"sourceissue":     true,
+		"worker":          true,
+		"rig":             true,
+		"merge_commit":    true,
+		"merge-commit":    true,
+		"mergecommit":     true,
+		"close_reason":    true,
+		"close-reason":    true,
+		"closereason":     true,
+		"required_checks": true,
+		"required-checks": true,
+		"requiredchecks":  true,
+		"check_results":   true,
+		"check-results":   true,
+		"checkresults":    true,
+		"checks_at":       true,
+		"checks-at":       true,
+		"checksat":        true,
+		"sessions":        true,
+		"type":            true,
 	}
 
 	var lines []string
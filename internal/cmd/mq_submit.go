@@ -14,6 +14,8 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -123,6 +125,18 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Validate required checks before touching anything
+	var requiredChecks []string
+	for _, c := range mqSubmitChecks {
+		specs, err := refinery.ParseCheckSpecs(c)
+		if err != nil {
+			return err
+		}
+		for _, spec := range specs {
+			requiredChecks = append(requiredChecks, spec.String())
+		}
+	}
+
 	g := git.NewGit(cwd)
 
 	// Get current branch
@@ -161,9 +175,28 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	// Initialize beads for looking up source issue
 	bd := beads.New(cwd)
 
+	// A stacked MR follows its parent's target
+	var parent *beads.MRFields
+	if mqSubmitParent != "" {
+		if mqSubmitEpic != "" {
+			return fmt.Errorf("--parent and --epic are exclusive: a stacked MR takes its parent's target")
+		}
+		if parent, err = stackParentMR(bd, mqSubmitParent); err != nil {
+			return err
+		}
+	}
+
+	// MRs this one must merge after; merged ones are already satisfied
+	mergeAfter, err := refinery.CheckMergeAfter(bd, mqSubmitAfter)
+	if err != nil {
+		return fmt.Errorf("--after: %w", err)
+	}
+
 	// Determine target branch
 	target := defaultBranch
-	if mqSubmitEpic != "" {
+	if parent != nil && parent.Target != "" {
+		target = parent.Target
+	} else if mqSubmitEpic != "" {
 		// Explicit --epic flag: read stored branch name, fall back to template
 		rigPath := filepath.Join(townRoot, rigName)
 		target = resolveIntegrationBranchName(bd, rigPath, mqSubmitEpic)
@@ -202,6 +235,18 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Default reviewers: the owners of the files the branch changes
+	var reviewers []string
+	if owners := config.LoadTownOwners(townRoot); owners != nil {
+		author := detectSender()
+		if worker != "" {
+			author = rigName + "/polecats/" + worker
+		}
+		if files, err := g.ChangedFiles("origin/"+target, branch); err == nil {
+			reviewers = owners.ReviewersFor(files, author)
+		}
+	}
+
 	// Build MR bead title and description
 	title := fmt.Sprintf("Merge: %s", issueID)
 	description := fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s",
@@ -209,6 +254,21 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	if len(requiredChecks) > 0 {
+		description += fmt.Sprintf("\nrequired_checks: %s", strings.Join(requiredChecks, ", "))
+	}
+	if len(reviewers) > 0 {
+		description += fmt.Sprintf("\nreviewers: %s", strings.Join(reviewers, ", "))
+	}
+	if parent != nil {
+		description += fmt.Sprintf("\nparent_mr: %s", mqSubmitParent)
+	}
+	if len(mergeAfter) > 0 {
+		description += fmt.Sprintf("\nmerge_after: %s", strings.Join(mergeAfter, ", "))
+	}
+	if sessions := mrSessions(issueID); len(sessions) > 0 {
+		description += fmt.Sprintf("\nsessions: %s", strings.Join(sessions, ", "))
+	}
 
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
@@ -220,6 +280,13 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		mrIssue = existingMR
 		fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
 	} else {
+		// A frozen queue takes no new submissions
+		if freeze, err := mq.ActiveFreeze(filepath.Join(townRoot, rigName), time.Now()); err != nil {
+			style.PrintWarning("could not check queue freeze: %v", err)
+		} else if freeze != nil {
+			return freeze.SubmitError(rigName)
+		}
+
 		// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 		mrIssue, err = bd.Create(beads.CreateOptions{
 			Title:       title,
@@ -231,9 +298,34 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("creating merge request bead: %w", err)
 		}
+		// Block the stacked MR on its parent so the refinery merges in order
+		if parent != nil {
+			if err := bd.AddDependency(mrIssue.ID, mqSubmitParent); err != nil {
+				style.PrintWarning("could not block %s on parent %s: %v", mrIssue.ID, mqSubmitParent, err)
+			}
+		}
+		if issueID != "" {
+			if err := bd.RecordMRLink(issueID, mrIssue.ID); err != nil {
+				style.PrintWarning("could not record MR link in %s history: %v", issueID, err)
+			}
+		}
 
 		// Nudge refinery to pick up the new MR
 		nudgeRefinery(rigName, fmt.Sprintf("MR submitted: %s branch=%s", mrIssue.ID, branch))
+
+		// Notify queue webhooks and set the branch's commit status (best-effort)
+		if hooks, err := mq.LoadDispatcher(rigName, filepath.Join(townRoot, rigName)); err != nil {
+			style.PrintWarning("could not load mq webhooks: %v", err)
+		} else if err := hooks.Dispatch(mq.EventSubmitted, mq.WebhookPayload{
+			MRID:        mrIssue.ID,
+			Branch:      branch,
+			Target:      target,
+			SourceIssue: issueID,
+			Worker:      worker,
+			HeadSHA:     submitHeadSHA(hooks, g, branch),
+		}); err != nil {
+			style.PrintWarning("mq webhook delivery: %v", err)
+		}
 	}
 
 	// Success output
@@ -246,6 +338,15 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if parent != nil {
+		fmt.Printf("  Stacked on: %s (%s)\n", mqSubmitParent, parent.Branch)
+	}
+	if len(mergeAfter) > 0 {
+		fmt.Printf("  Merges after: %s\n", strings.Join(mergeAfter, ", "))
+	}
+	if len(reviewers) > 0 {
+		fmt.Printf("  Reviewers: %s\n", strings.Join(reviewers, ", "))
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
@@ -264,6 +365,35 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// stackParentMR returns the fields of the open MR a new MR is stacked on.
+func stackParentMR(bd *beads.Beads, id string) (*beads.MRFields, error) {
+	issue, err := bd.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("looking up parent MR %s: %w", id, err)
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Branch == "" {
+		return nil, fmt.Errorf("%s is not a merge request", id)
+	}
+	if issue.Status == "closed" {
+		return nil, fmt.Errorf("parent MR %s is already closed", id)
+	}
+	return fields, nil
+}
+
+// submitHeadSHA returns the submitted branch's head for the queued commit
+// status, or "" when the rig doesn't publish commit statuses.
+func submitHeadSHA(hooks *mq.Dispatcher, g *git.Git, branch string) string {
+	if !hooks.PublishesCommitStatus() {
+		return ""
+	}
+	sha, err := g.Rev("refs/heads/" + branch)
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
 // polecatCleanup sends a lifecycle shutdown request to the witness and waits for termination.
 // This is called after a polecat successfully submits an MR.
 func polecatCleanup(rigName, worker, townRoot string) error {
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ supersede command flags
+var (
+	mqSupersedeRig    string
+	mqSupersedeReason string
+)
+
+var mqSupersedeCmd = &cobra.Command{
+	Use:   "supersede <old-mr> <new-mr>",
+	Short: "Replace a queued MR with another (e.g., after a rewrite)",
+	Long: `Mark a merge request as replaced by another, so the refinery merges only
+the new one.
+
+  1. The old MR is closed with reason "superseded", and its superseded_by
+     field names the new MR
+  2. The new MR's supersedes field names the old one, and a non-blocking
+     "supersedes" dependency links the two beads
+  3. The old MR's reviewers are added to the new MR's
+  4. The old MR's approvals are copied to the new MR, if the rig's merge
+     policy allows it: every approvals rule must set "carry_over": true
+     (see 'gt mq policy'). Otherwise the new MR needs fresh approvals.
+
+Both MRs must be open, and the old one must not be mid-merge. The steps are
+all-or-nothing: if one fails, the ones already made are undone.
+
+Examples:
+  gt mq supersede gt-mr-abc123 gt-mr-def456
+  gt mq supersede gt-mr-abc123 gt-mr-def456 --rig gastown --reason "rewritten on the new API"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQSupersede,
+}
+
+func init() {
+	mqSupersedeCmd.Flags().StringVar(&mqSupersedeRig, "rig", "", "Rig name (default: infer from current directory)")
+	mqSupersedeCmd.Flags().StringVarP(&mqSupersedeReason, "reason", "r", "", "Why the MR was replaced, recorded on the old MR")
+	mqCmd.AddCommand(mqSupersedeCmd)
+}
+
+func runMQSupersede(cmd *cobra.Command, args []string) error {
+	oldID, newID := args[0], args[1]
+
+	mgr, r, _, err := getRefineryManager(mqSupersedeRig)
+	if err != nil {
+		return err
+	}
+	policy, err := mq.LoadPolicy(r.Path)
+	if err != nil {
+		return err
+	}
+
+	result, err := mgr.SupersedeMR(oldID, newID, refinery.SupersedeOptions{
+		Reason:         mqSupersedeReason,
+		CarryApprovals: policy.CarriesApprovals(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s superseded by %s\n", style.SuccessPrefix, oldID, newID)
+	if len(result.Reviewers) > 0 {
+		fmt.Printf("  Reviewers carried over: %s\n", strings.Join(result.Reviewers, ", "))
+	}
+	if len(result.Approvals) > 0 {
+		fmt.Printf("  Approvals carried over: %s\n", strings.Join(result.Approvals, ", "))
+	}
+	if len(result.DroppedApprovals) > 0 {
+		fmt.Printf("  %s Approvals not carried over (merge policy): %s\n", style.WarningPrefix, strings.Join(result.DroppedApprovals, ", "))
+		fmt.Printf("  Re-approve with: %s\n", style.Dim.Render("gt mq policy approve "+newID))
+	}
+	return nil
+}
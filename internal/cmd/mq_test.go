@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -131,6 +133,11 @@ func TestGetDescriptionWithoutMRFields(t *testing.T) {
 			description: "branch: polecat/Nux/gt-xyz\nSome custom notes\ntarget: main",
 			want:        "Some custom notes",
 		},
+		{
+			name:        "session links",
+			description: "branch: polecat/Nux/gt-xyz\nsessions: s1, s2\nSome custom notes",
+			want:        "Some custom notes",
+		},
 		{
 			name:        "no MR fields",
 			description: "Just a regular description\nWith multiple lines",
@@ -148,6 +155,27 @@ func TestGetDescriptionWithoutMRFields(t *testing.T) {
 	}
 }
 
+func TestResolveMRSessions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectDir := filepath.Join(home, ".claude", "projects", "-gt-gastown-polecats-nux")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	transcript := filepath.Join(projectDir, "s1.jsonl")
+	if err := os.WriteFile(transcript, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := resolveMRSessions("", "s1, s2,")
+	if len(got) != 2 || got[0].ID != "s1" || got[0].Transcript != transcript || got[1].ID != "s2" || got[1].Transcript != "" {
+		t.Errorf("resolveMRSessions = %+v", got)
+	}
+	if findSessionTranscript("", "../s1") != "" {
+		t.Error("session ID with a path separator resolved")
+	}
+}
+
 func TestTruncateString(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -424,3 +452,13 @@ func TestMRFilteringByLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestMQShowIsStatus(t *testing.T) {
+	cmd, _, err := mqCmd.Find([]string{"show", "gt-mr1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != mqStatusCmd {
+		t.Errorf("gt mq show resolved to %q, want the status command (which shows check results)", cmd.Name())
+	}
+}
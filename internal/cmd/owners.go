@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Owners command flags
+var (
+	ownersJSON    bool
+	ownersPath    string
+	ownersLabel   string
+	ownersMembers []string
+	ownersLead    string
+	ownersTeam    string
+)
+
+var ownersCmd = &cobra.Command{
+	Use:     "owners",
+	GroupID: GroupWork,
+	Short:   "Manage component ownership (teams, paths, labels)",
+	RunE:    requireSubcommand,
+	Long: `Manage the town's ownership map in settings/owners.json.
+
+Teams are named groups of workers. Rules map a file path pattern or a bead
+label to an owner, a team or a single worker. As in CODEOWNERS, the last
+matching rule wins. Path patterns:
+  *.md            Any markdown file, at any depth
+  vendor          Any file or directory named vendor
+  internal/mq/    Everything under internal/mq (anchored at the repo root)
+  /docs/**        Everything under docs
+
+The map is used to:
+  - pick default MR reviewers: 'gt mq submit' records the owners of the
+    changed files on the merge request
+  - route beads: a bead no rig assignment rule claims goes to the owner of
+    one of its labels (a team's lead, or its only member)
+  - group 'gt owners workload' by team
+
+'gt doctor' checks the map (owners check).
+
+Examples:
+  gt owners team add core --member gastown/crew/max --member gastown/crew/joe --lead gastown/crew/max
+  gt owners add core --path internal/
+  gt owners add gastown/crew/ann --path "*.md"
+  gt owners add core --label infra
+  gt owners who internal/mq/stats.go
+  gt owners workload --team core`,
+}
+
+var ownersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List teams and ownership rules",
+	Args:  cobra.NoArgs,
+	RunE:  runOwnersList,
+}
+
+var ownersAddCmd = &cobra.Command{
+	Use:   "add <owner> (--path <pattern> | --label <label>)",
+	Short: "Add an ownership rule",
+	Long: `Append a rule giving <owner> (a team name or a worker address) the files
+matching --path or the beads labeled --label. Later rules take precedence.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOwnersAdd,
+}
+
+var ownersRemoveCmd = &cobra.Command{
+	Use:   "remove <rule-number>",
+	Short: "Remove an ownership rule (numbers from 'gt owners list')",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOwnersRemove,
+}
+
+var ownersTeamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Manage teams",
+	RunE:  requireSubcommand,
+}
+
+var ownersTeamAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Define a team, or add members to an existing one",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOwnersTeamAdd,
+}
+
+var ownersTeamRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a team that no rule refers to",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOwnersTeamRemove,
+}
+
+var ownersWhoCmd = &cobra.Command{
+	Use:   "who <path|bead-id>",
+	Short: "Show who owns a file or a bead",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOwnersWho,
+}
+
+var ownersWorkloadCmd = &cobra.Command{
+	Use:   "workload",
+	Short: "Show open and in-progress beads per worker, grouped by team",
+	Long: `Count the open and in-progress beads assigned to each worker across the
+town and its rigs, grouped by team. Workers in no team are listed last.
+
+Examples:
+  gt owners workload
+  gt owners workload --team core --json`,
+	Args: cobra.NoArgs,
+	RunE: runOwnersWorkload,
+}
+
+func init() {
+	ownersListCmd.Flags().BoolVar(&ownersJSON, "json", false, "Output as JSON")
+	ownersAddCmd.Flags().StringVar(&ownersPath, "path", "", "File path pattern the owner owns")
+	ownersAddCmd.Flags().StringVar(&ownersLabel, "label", "", "Bead label the owner owns")
+	ownersTeamAddCmd.Flags().StringArrayVar(&ownersMembers, "member", nil, "Worker address to add (repeatable)")
+	ownersTeamAddCmd.Flags().StringVar(&ownersLead, "lead", "", "Member who receives beads routed to the team")
+	ownersWorkloadCmd.Flags().StringVar(&ownersTeam, "team", "", "Only show this team")
+	ownersWorkloadCmd.Flags().BoolVar(&ownersJSON, "json", false, "Output as JSON")
+
+	ownersTeamCmd.AddCommand(ownersTeamAddCmd)
+	ownersTeamCmd.AddCommand(ownersTeamRemoveCmd)
+	ownersCmd.AddCommand(ownersListCmd)
+	ownersCmd.AddCommand(ownersAddCmd)
+	ownersCmd.AddCommand(ownersRemoveCmd)
+	ownersCmd.AddCommand(ownersTeamCmd)
+	ownersCmd.AddCommand(ownersWhoCmd)
+	ownersCmd.AddCommand(ownersWorkloadCmd)
+	rootCmd.AddCommand(ownersCmd)
+}
+
+func loadTownOwnersConfig() (string, *config.OwnersConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	owners, err := config.LoadOrCreateOwnersConfig(config.OwnersConfigPath(townRoot))
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, owners, nil
+}
+
+// loadCwdTownOwners returns the ownership map of the town around the
+// current directory, or nil outside a town or if it has none.
+func loadCwdTownOwners() *config.OwnersConfig {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	return config.LoadTownOwners(townRoot)
+}
+
+func runOwnersList(cmd *cobra.Command, args []string) error {
+	_, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	if ownersJSON {
+		return outputJSON(owners)
+	}
+	if owners.IsEmpty() {
+		fmt.Println("No ownership map. Add teams with 'gt owners team add' and rules with 'gt owners add'.")
+		return nil
+	}
+
+	if len(owners.Teams) > 0 {
+		fmt.Println(style.Bold.Render("Teams"))
+		for _, t := range owners.Teams {
+			line := fmt.Sprintf("  %-14s %s", t.Name, strings.Join(t.Members, ", "))
+			if t.Lead != "" {
+				line += style.Dim.Render("  (lead " + t.Lead + ")")
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(owners.Rules) > 0 {
+		if len(owners.Teams) > 0 {
+			fmt.Println()
+		}
+		fmt.Println(style.Bold.Render("Rules") + style.Dim.Render(" (last match wins)"))
+		for i, r := range owners.Rules {
+			fmt.Printf("  %2d. %-28s → %s\n", i+1, describeOwnerRule(r), r.Owner)
+		}
+	}
+	return nil
+}
+
+func describeOwnerRule(r config.OwnerRule) string {
+	if r.Label != "" {
+		return "label " + r.Label
+	}
+	return "path " + r.Path
+}
+
+func runOwnersAdd(cmd *cobra.Command, args []string) error {
+	townRoot, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	rule := config.OwnerRule{Path: ownersPath, Label: ownersLabel, Owner: args[0]}
+	if !strings.Contains(rule.Owner, "/") && owners.FindTeam(rule.Owner) == nil {
+		return fmt.Errorf("no team %q: define it with 'gt owners team add', or give a worker address", rule.Owner)
+	}
+	if rule.Label != "" {
+		if err := checkTownLabels([]string{rule.Label}); err != nil {
+			return err
+		}
+	}
+	owners.Rules = append(owners.Rules, rule)
+	if err := config.SaveOwnersConfig(config.OwnersConfigPath(townRoot), owners); err != nil {
+		return err
+	}
+	fmt.Printf("%s Rule %d: %s → %s\n", style.SuccessPrefix, len(owners.Rules), describeOwnerRule(rule), rule.Owner)
+	return nil
+}
+
+func runOwnersRemove(cmd *cobra.Command, args []string) error {
+	townRoot, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(owners.Rules) {
+		return fmt.Errorf("no rule %s: see 'gt owners list'", args[0])
+	}
+	rule := owners.Rules[n-1]
+	owners.Rules = append(owners.Rules[:n-1], owners.Rules[n:]...)
+	if err := config.SaveOwnersConfig(config.OwnersConfigPath(townRoot), owners); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed rule %d: %s → %s\n", style.SuccessPrefix, n, describeOwnerRule(rule), rule.Owner)
+	return nil
+}
+
+func runOwnersTeamAdd(cmd *cobra.Command, args []string) error {
+	townRoot, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	team := owners.FindTeam(name)
+	created := team == nil
+	if created {
+		owners.Teams = append(owners.Teams, config.TeamDef{Name: name})
+		team = &owners.Teams[len(owners.Teams)-1]
+	}
+	for _, m := range ownersMembers {
+		if !strings.Contains(m, "/") {
+			return fmt.Errorf("member %q: expected a worker address like gastown/crew/max", m)
+		}
+		if !containsMember(team.Members, m) {
+			team.Members = append(team.Members, m)
+		}
+	}
+	if ownersLead != "" {
+		team.Lead = ownersLead
+	}
+	if err := config.SaveOwnersConfig(config.OwnersConfigPath(townRoot), owners); err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("%s Defined team %s\n", style.SuccessPrefix, style.Bold.Render(name))
+	} else {
+		fmt.Printf("%s Updated team %s\n", style.SuccessPrefix, style.Bold.Render(name))
+	}
+	return nil
+}
+
+func containsMember(members []string, m string) bool {
+	for _, existing := range members {
+		if existing == m {
+			return true
+		}
+	}
+	return false
+}
+
+func runOwnersTeamRemove(cmd *cobra.Command, args []string) error {
+	townRoot, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if owners.FindTeam(name) == nil {
+		return fmt.Errorf("no team %q", name)
+	}
+	for i, r := range owners.Rules {
+		if r.Owner == name {
+			return fmt.Errorf("team %s still owns rule %d (%s); remove it first", name, i+1, describeOwnerRule(r))
+		}
+	}
+	kept := owners.Teams[:0]
+	for _, t := range owners.Teams {
+		if t.Name != name {
+			kept = append(kept, t)
+		}
+	}
+	owners.Teams = kept
+	if err := config.SaveOwnersConfig(config.OwnersConfigPath(townRoot), owners); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed team %s\n", style.SuccessPrefix, name)
+	return nil
+}
+
+func runOwnersWho(cmd *cobra.Command, args []string) error {
+	townRoot, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	target := args[0]
+
+	var rule config.OwnerRule
+	var ok bool
+	if rigPath := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(target)); rigPath != "" && !strings.Contains(target, "/") {
+		issue, err := beads.New(rigPath).Show(target)
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", target, err)
+		}
+		rule, ok = owners.OwnerForLabels(issue.Labels)
+	} else {
+		rule, ok = owners.OwnerForPath(target)
+	}
+	if !ok {
+		fmt.Printf("%s has no owner\n", target)
+		return nil
+	}
+	fmt.Printf("%s is owned by %s %s\n", target, style.Bold.Render(rule.Owner), style.Dim.Render("("+describeOwnerRule(rule)+")"))
+	if t := owners.FindTeam(rule.Owner); t != nil {
+		fmt.Printf("  Members: %s\n", strings.Join(t.Members, ", "))
+		if a := owners.Assignee(rule.Owner); a != "" {
+			fmt.Printf("  Beads go to: %s\n", a)
+		}
+	}
+	return nil
+}
+
+// workerLoad is one row of 'gt owners workload'.
+type workerLoad struct {
+	Worker     string `json:"worker"`
+	Open       int    `json:"open"`
+	InProgress int    `json:"in_progress"`
+}
+
+// teamLoad is one team's section of 'gt owners workload'.
+type teamLoad struct {
+	Team    string       `json:"team"` // "" for workers in no team
+	Workers []workerLoad `json:"workers"`
+}
+
+func runOwnersWorkload(cmd *cobra.Command, args []string) error {
+	_, owners, err := loadTownOwnersConfig()
+	if err != nil {
+		return err
+	}
+	if ownersTeam != "" && owners.FindTeam(ownersTeam) == nil {
+		return fmt.Errorf("no team %q", ownersTeam)
+	}
+
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	dirs := []string{townRoot}
+	for _, r := range rigs {
+		dirs = append(dirs, r.Path)
+	}
+
+	counts := make(map[string]*workerLoad)
+	for _, dir := range dirs {
+		for _, status := range []string{"open", "in_progress"} {
+			issues, err := beads.New(dir).List(beads.ListOptions{Status: status})
+			if err != nil {
+				style.PrintWarning("listing beads in %s: %v", dir, err)
+				continue
+			}
+			for _, issue := range issues {
+				if issue.Assignee == "" {
+					continue
+				}
+				w := counts[issue.Assignee]
+				if w == nil {
+					w = &workerLoad{Worker: issue.Assignee}
+					counts[issue.Assignee] = w
+				}
+				if status == "open" {
+					w.Open++
+				} else {
+					w.InProgress++
+				}
+			}
+		}
+	}
+
+	report := buildWorkload(owners, counts, ownersTeam)
+	if ownersJSON {
+		return outputJSON(report)
+	}
+	if len(report) == 0 {
+		fmt.Println("No assigned open beads.")
+		return nil
+	}
+	for i, t := range report {
+		if i > 0 {
+			fmt.Println()
+		}
+		title := t.Team
+		if title == "" {
+			title = "(no team)"
+		}
+		fmt.Println(style.Bold.Render(title))
+		for _, w := range t.Workers {
+			fmt.Printf("  %-32s %3d open  %3d in progress\n", w.Worker, w.Open, w.InProgress)
+		}
+	}
+	return nil
+}
+
+// buildWorkload groups per-worker counts by team. Team members with no
+// beads are listed with zero counts; with team set, only that team is
+// returned.
+func buildWorkload(owners *config.OwnersConfig, counts map[string]*workerLoad, team string) []teamLoad {
+	var report []teamLoad
+	inTeam := make(map[string]bool)
+	for _, t := range owners.Teams {
+		for _, m := range t.Members {
+			inTeam[m] = true
+		}
+		if team != "" && t.Name != team {
+			continue
+		}
+		section := teamLoad{Team: t.Name}
+		for _, m := range t.Members {
+			w := workerLoad{Worker: m}
+			if c := counts[m]; c != nil {
+				w = *c
+			}
+			section.Workers = append(section.Workers, w)
+		}
+		report = append(report, section)
+	}
+	if team != "" {
+		return report
+	}
+
+	var rest []workerLoad
+	for worker, c := range counts {
+		if !inTeam[worker] {
+			rest = append(rest, *c)
+		}
+	}
+	if len(rest) > 0 {
+		sort.Slice(rest, func(i, j int) bool { return rest[i].Worker < rest[j].Worker })
+		report = append(report, teamLoad{Workers: rest})
+	}
+	return report
+}
@@ -196,6 +196,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// stay in working set. Caller must call CreateDoltBranch() after all writes
 	// are complete to flush the working set and create the branch.
 	doltBranch := doltserver.PolecatBranchName(polecatName)
+	if layout, err := doltserver.LoadLayout(townRoot); err == nil && layout.IsBranched(rigName) {
+		// The rig is itself a branch of the shared database (gt dolt layout);
+		// its polecats write to that branch directly.
+		doltBranch = ""
+	}
 
 	// Get session manager for session name (session start is deferred)
 	polecatSessMgr := polecat.NewSessionManager(t, r)
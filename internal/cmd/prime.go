@@ -433,7 +433,6 @@ func findAgentWork(ctx RoleContext) *beads.Issue {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil {
 		return nil
@@ -444,7 +443,6 @@ func findAgentWork(ctx RoleContext) *beads.Issue {
 		inProgressBeads, err := b.List(beads.ListOptions{
 			Status:   "in_progress",
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if err != nil || len(inProgressBeads) == 0 {
 			return nil
@@ -135,7 +135,6 @@ func outputMoleculeContext(ctx RoleContext) {
 	issues, err := b.List(beads.ListOptions{
 		Status:   "in_progress",
 		Assignee: ctx.Polecat,
-		Priority: -1,
 	})
 	if err != nil || len(issues) == 0 {
 		return
@@ -198,9 +197,8 @@ func showMoleculeProgress(b *beads.Beads, rootID string) {
 
 	// Find all children of the root issue
 	children, err := b.List(beads.ListOptions{
-		Parent:   rootID,
-		Status:   "all",
-		Priority: -1,
+		Parent: rootID,
+		Status: "all",
 	})
 	if err != nil || len(children) == 0 {
 		return
@@ -490,7 +490,6 @@ func outputAttachmentStatus(ctx RoleContext) {
 	pinnedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusPinned,
 		Assignee: assignee,
-		Priority: -1,
 	})
 	if err != nil || len(pinnedBeads) == 0 {
 		// No pinned beads - interactive mode
@@ -264,7 +264,6 @@ func detectSessionState(ctx RoleContext) SessionState {
 		hookedBeads, err := b.List(beads.ListOptions{
 			Status:   beads.StatusHooked,
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if err == nil && len(hookedBeads) > 0 {
 			state.State = "autonomous"
@@ -275,7 +274,6 @@ func detectSessionState(ctx RoleContext) SessionState {
 		inProgressBeads, err := b.List(beads.ListOptions{
 			Status:   "in_progress",
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if err == nil && len(inProgressBeads) > 0 {
 			state.State = "autonomous"
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/ratelimit"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Ratelimit command flags
+var ratelimitStatusJSON bool
+
+var ratelimitCmd = &cobra.Command{
+	Use:     "ratelimit",
+	GroupID: GroupDiag,
+	Short:   "Show and reset per-agent bead write quotas",
+	Long: `Bead writes (create, update, close, dependencies, labels, slots, ...)
+made through gt are rate limited per agent identity (BD_ACTOR), so an agent
+stuck in a retry loop can't flood the shared Dolt server.
+
+Each identity has a token bucket shared by all its processes: a burst of
+writes at once, refilled at a steady rate. A write over the quota waits for
+a token, slowing the loop down; one that would wait longer than max_wait
+fails with "rate limited". Writes without a BD_ACTOR (a human at a shell)
+are not limited. Dashboard API writes count as "dashboard" and get a 429
+instead of waiting.
+
+Quotas are set in settings/config.json (defaults: 300 writes/min, burst
+100, max_wait 30s):
+
+  "rate_limits": {
+    "per_minute": 300,
+    "burst": 100,
+    "max_wait": "30s",
+    "agents": {
+      "gastown/refinery": {"per_minute": 1200, "burst": 300},
+      "*/polecats/*": {"per_minute": 120},
+      "mayor": {"per_minute": -1}
+    }
+  }
+
+Agent keys are identities or path patterns; the most specific match wins,
+and a per_minute of -1 exempts an identity. Set "disabled": true to turn
+limiting off.`,
+	RunE: requireSubcommand,
+}
+
+var ratelimitStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show each identity's quota, tokens left and throttled/denied writes",
+	Args:  cobra.NoArgs,
+	RunE:  runRatelimitStatus,
+}
+
+var ratelimitResetCmd = &cobra.Command{
+	Use:   "reset <identity>",
+	Short: "Refill an identity's bucket and clear its counters",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRatelimitReset,
+}
+
+func init() {
+	ratelimitStatusCmd.Flags().BoolVar(&ratelimitStatusJSON, "json", false, "Output as JSON")
+
+	ratelimitCmd.AddCommand(ratelimitStatusCmd)
+	ratelimitCmd.AddCommand(ratelimitResetCmd)
+	rootCmd.AddCommand(ratelimitCmd)
+}
+
+func runRatelimitStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	buckets, err := ratelimit.ForTown(townRoot).Status()
+	if err != nil {
+		return err
+	}
+
+	if ratelimitStatusJSON {
+		if buckets == nil {
+			buckets = []ratelimit.BucketStatus{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(buckets)
+	}
+
+	if len(buckets) == 0 {
+		fmt.Println(style.Dim.Render("No agent has written beads through gt yet."))
+		return nil
+	}
+
+	t := style.NewTable(
+		style.Column{Name: "IDENTITY", Width: 32},
+		style.Column{Name: "QUOTA", Width: 22},
+		style.Column{Name: "TOKENS", Width: 8, Align: style.AlignRight},
+		style.Column{Name: "THROTTLED", Width: 9, Align: style.AlignRight},
+		style.Column{Name: "DENIED", Width: 6, Align: style.AlignRight},
+		style.Column{Name: "LAST DENIED", Width: 12},
+	)
+	for _, b := range buckets {
+		tokens := "-"
+		if !b.Quota.Unlimited() {
+			tokens = fmt.Sprintf("%.0f", b.Tokens)
+			if b.Tokens < 1 {
+				tokens = style.Warning.Render(tokens)
+			}
+		}
+		denied := fmt.Sprintf("%d", b.Denied)
+		lastDenied := "-"
+		if b.Denied > 0 {
+			denied = style.Error.Render(denied)
+		}
+		if !b.LastDenied.IsZero() {
+			lastDenied = style.HumanizeAge(time.Since(b.LastDenied)) + " ago"
+		}
+		t.AddRow(b.Identity, b.Quota.String(), tokens, fmt.Sprintf("%d", b.Throttled), denied, lastDenied)
+	}
+	fmt.Print(t.Render())
+	return nil
+}
+
+func runRatelimitReset(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := ratelimit.ForTown(townRoot).Reset(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Reset the write quota of %s\n", style.SuccessPrefix, style.Bold.Render(args[0]))
+	return nil
+}
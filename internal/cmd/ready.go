@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -35,6 +36,11 @@ Aggregates ready issues from:
 
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
+AGE turns yellow once an item is aging and red once it is stale, with
+thresholds per priority (P0 4h/1d up to P4 14d/90d). Override them in
+settings/config.json:
+
+  "aging": {"priorities": {"P1": {"aging": "12h", "stale": "2d"}}}
 
 Examples:
   gt ready              # Show all ready work
@@ -261,6 +267,8 @@ func printReadyHuman(result ReadyResult) error {
 
 	fmt.Printf("%s Ready work across town:\n\n", style.Bold.Render("📋"))
 
+	now := time.Now()
+
 	for _, src := range result.Sources {
 		if src.Error != "" {
 			fmt.Printf("%s %s\n", style.Dim.Render(src.Name+"/"), style.Warning.Render("(error: "+src.Error+")"))
@@ -274,6 +282,12 @@ func printReadyHuman(result ReadyResult) error {
 		}
 
 		fmt.Printf("%s (%d items)\n", style.Bold.Render(src.Name+"/"), count)
+		table := style.NewTable(
+			style.Column{Name: "PRI", Width: 3},
+			style.Column{Name: "ID", Width: 20},
+			style.Column{Name: "AGE", Width: 5, Align: style.AlignRight, Age: true},
+			style.Column{Name: "TITLE", Width: 60},
+		).SetHeaderSeparator(false).SetNow(now)
 		for _, issue := range src.Issues {
 			priorityStr := fmt.Sprintf("P%d", issue.Priority)
 			var priorityStyled string
@@ -288,14 +302,9 @@ func printReadyHuman(result ReadyResult) error {
 				priorityStyled = style.Dim.Render(priorityStr)
 			}
 
-			// Truncate title if too long
-			title := issue.Title
-			if len(title) > 60 {
-				title = title[:57] + "..."
-			}
-
-			fmt.Printf("  [%s] %s %s\n", priorityStyled, style.Dim.Render(issue.ID), title)
+			table.AddAgedRow(parseCreatedAt(issue.CreatedAt), issue.Priority, priorityStyled, style.Dim.Render(issue.ID), issue.Title)
 		}
+		fmt.Print(table.Render())
 		fmt.Println()
 	}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -138,8 +139,11 @@ var refineryClaimCmd = &cobra.Command{
 	Long: `Claim a merge request for processing by this refinery worker.
 
 When running multiple refinery workers in parallel, each worker must claim
-an MR before processing to prevent double-processing. Claims expire after
-10 minutes if not processed (for crash recovery).
+an MR before processing to prevent double-processing. The claim also takes
+the MR's target branch lock, so two workers never merge into the same
+target at once; it fails while another worker holds the target. Claims
+and locks expire after stale_claim_timeout if not processed (for crash
+recovery). 'gt refinery release' gives both back.
 
 The worker ID is automatically determined from the GT_REFINERY_WORKER
 environment variable, or defaults to "refinery-1".
@@ -559,10 +563,7 @@ func runRefineryRestart(cmd *cobra.Command, args []string) error {
 
 // getWorkerID returns the refinery worker ID from environment or default.
 func getWorkerID() string {
-	if id := os.Getenv("GT_REFINERY_WORKER"); id != "" {
-		return id
-	}
-	return "refinery-1"
+	return refinery.WorkerID()
 }
 
 func runRefineryClaim(cmd *cobra.Command, args []string) error {
@@ -585,7 +586,10 @@ func runRefineryClaim(cmd *cobra.Command, args []string) error {
 	}
 
 	eng := refinery.NewEngineer(r)
-	if err := eng.ClaimMR(mrID, workerID); err != nil {
+	if err := eng.LoadConfig(); err != nil {
+		style.PrintWarning("could not load merge queue config: %v", err)
+	}
+	if err := eng.ClaimMRAndTarget(mrID); err != nil {
 		return fmt.Errorf("claiming MR: %w", err)
 	}
 
@@ -634,9 +638,8 @@ func runRefineryUnclaimed(cmd *cobra.Command, args []string) error {
 	// Query beads for merge-request issues without assignee
 	b := beads.New(r.Path)
 	issues, err := b.List(beads.ListOptions{
-		Status:   "open",
-		Label:    "gt:merge-request",
-		Priority: -1,
+		Status: "open",
+		Label:  "gt:merge-request",
 	})
 	if err != nil {
 		return fmt.Errorf("listing merge requests: %w", err)
@@ -714,32 +717,48 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing queue anomalies: %w", err)
 	}
 
+	freeze, err := mq.ActiveFreeze(r.Path, time.Now())
+	if err != nil {
+		style.PrintWarning("could not check queue freeze: %v", err)
+	}
+	hold, err := mq.ActiveHold(r.Path, time.Now())
+	if err != nil {
+		style.PrintWarning("could not check refinery pause: %v", err)
+	}
+
 	// JSON output
 	if refineryReadyJSON {
 		type readyOutput struct {
 			Ready     []*refinery.MRInfo    `json:"ready"`
 			Anomalies []*refinery.MRAnomaly `json:"anomalies,omitempty"`
+			Freeze    *mq.FreezeState       `json:"freeze,omitempty"`
+			Hold      *mq.Hold              `json:"hold,omitempty"`
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(readyOutput{
 			Ready:     ready,
 			Anomalies: anomalies,
+			Freeze:    freeze,
+			Hold:      hold,
 		})
 	}
 
 	// Human-readable output
 	fmt.Printf("%s Ready MRs for '%s':\n\n", style.Bold.Render("🚀"), rigName)
+	printFreezeBanner(freeze, len(ready) == 0)
+	printHoldBanner(hold)
 
 	if len(ready) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(none ready)"))
 		return nil
 	}
 
+	now := time.Now()
 	for i, mr := range ready {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
-		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		fmt.Printf("     ID: %s  Worker: %s  Age: %s\n", mr.ID, mr.Worker, style.RenderAge(mr.CreatedAt, now, mr.Priority))
 	}
 
 	if len(anomalies) > 0 {
@@ -781,6 +800,7 @@ func runRefineryReadyAll(eng *refinery.Engineer, rigName string) error {
 		return nil
 	}
 
+	now := time.Now()
 	for i, mr := range mrs {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
@@ -789,11 +809,12 @@ func runRefineryReadyAll(eng *refinery.Engineer, rigName string) error {
 		if assignee == "" {
 			assignee = "(unclaimed)"
 		}
-		age := ""
+		updated := ""
 		if !mr.UpdatedAt.IsZero() {
-			age = fmt.Sprintf(" (updated %s ago)", time.Since(mr.UpdatedAt).Truncate(time.Second))
+			updated = fmt.Sprintf(" (updated %s ago)", now.Sub(mr.UpdatedAt).Truncate(time.Second))
 		}
-		fmt.Printf("     ID: %s  Worker: %s  Assignee: %s%s\n", mr.ID, mr.Worker, assignee, age)
+		fmt.Printf("     ID: %s  Worker: %s  Assignee: %s  Age: %s%s\n", mr.ID, mr.Worker, assignee,
+			style.RenderAge(mr.CreatedAt, now, mr.Priority), updated)
 
 		// Show branch status and blocked-by for --all mode
 		var flags []string
@@ -846,13 +867,18 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	now := time.Now()
 	for i, mr := range blocked {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
-		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		fmt.Printf("     ID: %s  Worker: %s  Age: %s\n", mr.ID, mr.Worker, style.RenderAge(mr.CreatedAt, now, mr.Priority))
 		if mr.BlockedBy != "" {
 			fmt.Printf("     Blocked by: %s\n", mr.BlockedBy)
 		}
+		if len(mr.OrderCycle) > 0 {
+			fmt.Printf("     %s Merge order cycle: %s (break it with 'gt mq order --clear %s')\n",
+				style.WarningPrefix, strings.Join(mr.OrderCycle, " → "), mr.ID)
+		}
 	}
 
 	return nil
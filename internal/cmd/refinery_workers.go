@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	refineryRunWorkers int
+	refineryLocksJSON  bool
+)
+
+var refineryRunCmd = &cobra.Command{
+	Use:   "run [rig]",
+	Short: "Process the merge queue with parallel workers",
+	Long: `Process the merge queue in the foreground with one or more workers,
+until interrupted.
+
+Each worker merges one MR at a time in its own worktree (the first uses the
+refinery's worktree, the others refinery/workers/<id>). Merges into the same
+target branch are serialized by a per-target lock; merges into different
+targets (main and integration branches) run concurrently, so a slow gate
+run on one target no longer holds up the rest of the queue.
+
+The worker count defaults to merge_queue.max_concurrent in the rig's
+config.json. Don't run this alongside the refinery agent on the same rig.
+
+Examples:
+  gt refinery run gastown
+  gt refinery run gastown --workers 3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryRun,
+}
+
+var refineryLocksCmd = &cobra.Command{
+	Use:   "locks [rig]",
+	Short: "Show merge target locks held by refinery workers",
+	Long: `Show which refinery worker holds each merge target's lock, and for
+which MR. A lock not renewed within stale_claim_timeout has been abandoned
+by a crashed worker and is taken over by the next one.
+
+Examples:
+  gt refinery locks
+  gt refinery locks gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryLocks,
+}
+
+func init() {
+	refineryRunCmd.Flags().IntVarP(&refineryRunWorkers, "workers", "w", 0, "Number of workers (default: merge_queue.max_concurrent)")
+	refineryLocksCmd.Flags().BoolVar(&refineryLocksJSON, "json", false, "Output as JSON")
+
+	refineryCmd.AddCommand(refineryRunCmd)
+	refineryCmd.AddCommand(refineryLocksCmd)
+}
+
+func runRefineryRun(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	if err := checkRigNotParkedOrDocked(rigName); err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	workers := refineryRunWorkers
+	if workers == 0 {
+		workers = eng.Config().MaxConcurrent
+	}
+	if workers < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("%s Processing %s merge queue with %d worker(s) (Ctrl-C to stop)\n", style.Bold.Render("▶"), rigName, workers)
+	return eng.RunWorkers(ctx, workers)
+}
+
+func runRefineryLocks(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	locks, err := mq.ListTargetLocks(r.Path)
+	if err != nil {
+		return fmt.Errorf("listing target locks: %w", err)
+	}
+	if refineryLocksJSON {
+		if locks == nil {
+			locks = []*mq.TargetLock{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(locks)
+	}
+
+	fmt.Printf("%s Merge target locks for '%s':\n\n", style.Bold.Render("🔒"), rigName)
+	if len(locks) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none held)"))
+		return nil
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		style.PrintWarning("could not load merge queue config: %v", err)
+	}
+	now := time.Now()
+	for _, lock := range locks {
+		line := fmt.Sprintf("  %s  %s", style.Bold.Render(lock.Target), lock.Holder)
+		if lock.MRID != "" {
+			line += "  " + lock.MRID
+		}
+		line += "  " + style.Dim.Render("held "+formatDuration(now.Sub(lock.AcquiredAt)))
+		if lock.Stale(eng.Config().StaleClaimTimeout, now) {
+			line += "  " + style.Warning.Render("(stale)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
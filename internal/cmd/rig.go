@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/polecat"
@@ -90,6 +91,7 @@ For each rig, displays:
   - Witness status (running/stopped)
   - Refinery status (running/stopped)
   - Number of polecats and crew members
+  - Beads database size, rows, and growth, as of the last 'gt dolt stats'
 
 Examples:
   gt rig list          # List all rigs with status
@@ -685,10 +687,14 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		Refinery string `json:"refinery"`
 		Polecats int    `json:"polecats"`
 		Crew     int    `json:"crew"`
+		// Beads database stats cached by the last 'gt dolt stats' run
+		DB *doltserver.DatabaseStats `json:"db,omitempty"`
 		// sorting fields (not exported to JSON)
 		sortPrio int
 	}
 
+	dbStats, _ := doltserver.LoadStatsSnapshot(townRoot)
+
 	var rigs []rigInfo
 
 	for name := range rigsConfig.Rigs {
@@ -722,6 +728,7 @@ func runRigList(cmd *cobra.Command, args []string) error {
 			Refinery: refineryStatus,
 			Polecats: summary.PolecatCount,
 			Crew:     summary.CrewCount,
+			DB:       dbStats.Get(doltserver.RigDatabaseName(townRoot, name)),
 			sortPrio: rigStatePriority(witnessRunning, refineryRunning, opState),
 		})
 	}
@@ -768,6 +775,13 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Witness: %s %s  Refinery: %s %s\n",
 			witnessIcon, ri.Witness, refineryIcon, ri.Refinery)
 		fmt.Printf("   Polecats: %d  Crew: %d\n", ri.Polecats, ri.Crew)
+		if ri.DB != nil {
+			line := fmt.Sprintf("%s, %d rows, %d commits", formatBytes(ri.DB.Bytes), ri.DB.Rows, ri.DB.Commits)
+			if ri.DB.Growth != nil {
+				line += " " + style.Dim.Render("("+formatStatsGrowth(ri.DB.Growth)+")")
+			}
+			fmt.Printf("   Beads DB: %s\n", line)
+		}
 		fmt.Println()
 	}
 
@@ -1158,8 +1172,7 @@ func runResetStale(bd *beads.Beads, dryRun bool) error {
 
 	// Get all in_progress issues
 	issues, err := bd.List(beads.ListOptions{
-		Status:   "in_progress",
-		Priority: -1, // All priorities
+		Status: "in_progress",
 	})
 	if err != nil {
 		return fmt.Errorf("listing in_progress issues: %w", err)
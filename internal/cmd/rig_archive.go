@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/wisp"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Rig archive command flags
+var (
+	rigArchiveOutput    string
+	rigArchiveKeepFiles bool
+	rigRestoreNoRestart bool
+)
+
+// rigArchiveDir is where archives go by default, relative to the town root.
+const rigArchiveDir = "archives"
+
+var rigArchiveCmd = &cobra.Command{
+	Use:   "archive <rig>",
+	Short: "Archive a rig to a tarball and remove it from the town",
+	Long: `Archive a rig so old rigs stop accumulating in the town.
+
+Archiving:
+  1. Parks the rig and stops all its agent sessions
+  2. Commits and exports its beads (beads.jsonl, for reading without Dolt)
+  3. Writes a tarball with the rig directory and a consistent copy of its
+     Dolt database (taken with DOLT_BACKUP while the server is running)
+  4. Verifies the tarball: re-reads it and opens the archived database
+  5. Removes the rig directory, the database, its beads route, and its
+     mayor/rigs.json entry
+
+If verification fails nothing is removed and the rig stays parked.
+
+Uncommitted work in crew and polecat worktrees is preserved in the
+tarball. Use 'gt rig restore' to bring the rig back.
+
+Archives are written to <town>/archives/<rig>-<timestamp>.tar.gz unless
+--output is given.
+
+Examples:
+  gt rig archive oldproject
+  gt rig archive oldproject -o /backups/oldproject.tar.gz
+  gt rig archive oldproject --keep-files   # Archive, but leave the rig in place`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigArchive,
+}
+
+var rigRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a rig from an archive",
+	Long: `Restore a rig archived with 'gt rig archive'.
+
+Restores the rig directory, its Dolt database under .dolt-data/, its
+beads route, and its mayor/rigs.json entry. A running Dolt server is
+restarted so it serves the restored database (--no-restart to skip). The
+rig's agents are not started; use 'gt rig start <rig>'.
+
+The rig name, directory, and database must not already exist.
+
+Examples:
+  gt rig restore archives/oldproject-20260301-120000.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigRestore,
+}
+
+func init() {
+	rigArchiveCmd.Flags().StringVarP(&rigArchiveOutput, "output", "o", "", "Archive path (default: <town>/archives/<rig>-<timestamp>.tar.gz)")
+	rigArchiveCmd.Flags().BoolVar(&rigArchiveKeepFiles, "keep-files", false, "Write the archive but keep the rig registered and on disk")
+	rigRestoreCmd.Flags().BoolVar(&rigRestoreNoRestart, "no-restart", false, "Don't restart a running Dolt server")
+
+	rigCmd.AddCommand(rigArchiveCmd)
+	rigCmd.AddCommand(rigRestoreCmd)
+}
+
+func runRigArchive(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, ok := rigsConfig.Rigs[rigName]
+	if !ok {
+		return fmt.Errorf("rig %q is not registered", rigName)
+	}
+
+	output := rigArchiveOutput
+	if output == "" {
+		dir := filepath.Join(townRoot, rigArchiveDir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating archive directory: %w", err)
+		}
+		output = filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", rigName, time.Now().Format("20060102-150405")))
+	}
+
+	fmt.Printf("Archiving rig %s...\n", style.Bold.Render(rigName))
+
+	// Park first so the daemon doesn't restart what we stop.
+	wispCfg := wisp.NewConfig(townRoot, rigName)
+	if err := wispCfg.Set(RigStatusKey, RigStatusParked); err != nil {
+		return fmt.Errorf("parking rig: %w", err)
+	}
+	t := tmux.NewTmux()
+	sessions, err := findRigSessions(t, rigName)
+	if err != nil {
+		return fmt.Errorf("listing rig sessions: %w", err)
+	}
+	for _, s := range sessions {
+		if err := t.KillSessionWithProcesses(s); err != nil {
+			return fmt.Errorf("stopping session %s: %w (rig left parked; 'gt rig unpark %s' to undo)", s, err, rigName)
+		}
+		fmt.Printf("  Stopped %s\n", s)
+	}
+
+	manifest := &rig.ArchiveManifest{
+		Rig:        rigName,
+		Entry:      entry,
+		RoutePath:  rigRoutePath(townRoot, rigName),
+		BeadCount:  -1,
+		ArchivedAt: time.Now().UTC(),
+	}
+	src := rig.ArchiveSources{RigDir: r.Path}
+
+	// The JSONL export is a readable fallback; the Dolt database is the
+	// real copy, so an export failure only warns.
+	bd := beads.New(r.BeadsPath())
+	if issues, err := bd.List(beads.ListOptions{Status: "all"}); err != nil {
+		fmt.Printf("  %s Could not export beads: %v\n", style.Warning.Render("!"), err)
+	} else {
+		comments, _ := bd.AllComments() // discussions are a bonus; the database has the rest
+		var buf bytes.Buffer
+		if err := beads.ExportIssuesWithComments(&buf, "jsonl", issues, comments); err != nil {
+			return fmt.Errorf("exporting beads: %w", err)
+		}
+		src.Beads = buf.Bytes()
+		manifest.BeadCount = len(issues)
+		fmt.Printf("  Exported %d bead(s)\n", len(issues))
+	}
+
+	layout, _ := doltserver.LoadLayout(townRoot)
+	if doltserver.DefaultConfig(townRoot).IsRemote() {
+		fmt.Printf("  %s Dolt server is remote; archiving the beads export only\n", style.Warning.Render("!"))
+	} else if layout != nil && layout.IsBranched(rigName) {
+		// The rig's branch shares a database with other rigs, so there is no
+		// database of its own to archive or remove; the branch is kept, and
+		// a restore reconnects to it.
+		fmt.Printf("  %s Beads are on branch %s of shared database %s; archiving the beads export only (branch kept)\n",
+			style.Warning.Render("!"), rigName, layout.Database)
+	} else {
+		db := doltserver.RigDatabaseName(townRoot, rigName)
+		if doltserver.DatabaseExists(townRoot, db) {
+			if running, _, _ := doltserver.IsRunning(townRoot); running {
+				if err := doltserver.CommitServerWorkingSet(townRoot, db, "gt rig archive"); err != nil {
+					fmt.Printf("  %s Could not commit Dolt working set: %v\n", style.Warning.Render("!"), err)
+				}
+			}
+			dir, cleanup, err := doltserver.SnapshotDatabase(townRoot, db)
+			if err != nil {
+				return fmt.Errorf("%w (rig left parked; 'gt rig unpark %s' to undo)", err, rigName)
+			}
+			defer cleanup()
+			manifest.Database = db
+			src.DoltDir = dir
+		}
+	}
+
+	if err := rig.WriteArchive(output, manifest, src); err != nil {
+		return fmt.Errorf("%w (rig left parked; 'gt rig unpark %s' to undo)", err, rigName)
+	}
+	fmt.Printf("  Wrote %s\n", output)
+
+	if err := verifyRigArchive(output, manifest); err != nil {
+		return fmt.Errorf("verifying %s: %w (nothing removed; rig left parked, 'gt rig unpark %s' to undo)", output, err, rigName)
+	}
+	fmt.Printf("  Verified archive\n")
+
+	if rigArchiveKeepFiles {
+		fmt.Printf("%s Rig %s archived to %s (kept in place, parked)\n", style.Success.Render("✓"), rigName, output)
+		return nil
+	}
+
+	// The archive is complete; from here, failures leave a partially
+	// removed rig that 'gt rig restore' can still replace.
+	if manifest.Database != "" {
+		if err := doltserver.RemoveDatabase(townRoot, manifest.Database); err != nil {
+			return fmt.Errorf("removing Dolt database: %w", err)
+		}
+	}
+	if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+		if err := beads.RemoveRoute(townRoot, entry.BeadsConfig.Prefix+"-"); err != nil {
+			fmt.Printf("  %s Could not remove route from routes.jsonl: %v\n", style.Warning.Render("!"), err)
+		}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	if err := mgr.RemoveRig(rigName); err != nil {
+		return fmt.Errorf("removing rig: %w", err)
+	}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if err := os.RemoveAll(r.Path); err != nil {
+		return fmt.Errorf("removing rig directory: %w", err)
+	}
+	_ = wispCfg.Clear()
+
+	fmt.Printf("%s Rig %s archived to %s\n", style.Success.Render("✓"), rigName, output)
+	fmt.Printf("  Restore with: %s\n", style.Dim.Render("gt rig restore "+output))
+	return nil
+}
+
+// verifyRigArchive re-reads a freshly written archive before the rig is
+// removed: the manifest must match, and an archived Dolt database must open
+// and hold the beads tables.
+func verifyRigArchive(path string, want *rig.ArchiveManifest) error {
+	m, err := rig.ReadArchiveManifest(path)
+	if err != nil {
+		return err
+	}
+	if m.Rig != want.Rig || m.Database != want.Database {
+		return fmt.Errorf("manifest is for rig %q, database %q; want %q, %q", m.Rig, m.Database, want.Rig, want.Database)
+	}
+	if want.Database == "" {
+		return nil
+	}
+
+	tmp, err := os.MkdirTemp("", "gt-archive-verify-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	doltDir := filepath.Join(tmp, want.Database)
+	if err := rig.ExtractArchive(path, "", doltDir); err != nil {
+		return fmt.Errorf("extracting Dolt database: %w", err)
+	}
+	return doltserver.VerifyDatabaseDir(doltDir, "issues")
+}
+
+func runRigRestore(cmd *cobra.Command, args []string) error {
+	archive := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	m, err := rig.ReadArchiveManifest(archive)
+	if err != nil {
+		return err
+	}
+	rigName := m.Rig
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	if _, exists := rigsConfig.Rigs[rigName]; exists {
+		return fmt.Errorf("rig %q is already registered", rigName)
+	}
+
+	var doltDest string
+	if m.Database != "" {
+		if doltserver.DefaultConfig(townRoot).IsRemote() {
+			return fmt.Errorf("archive holds a Dolt database but this town uses a remote Dolt server; extract beads.jsonl and use 'gt beads import' instead")
+		}
+		doltDest = doltserver.RigDatabaseDir(townRoot, m.Database)
+		if err := os.MkdirAll(filepath.Dir(doltDest), 0755); err != nil {
+			return fmt.Errorf("creating Dolt data directory: %w", err)
+		}
+	}
+
+	fmt.Printf("Restoring rig %s from %s...\n", style.Bold.Render(rigName), archive)
+	rigPath := filepath.Join(townRoot, rigName)
+	if err := rig.ExtractArchive(archive, rigPath, doltDest); err != nil {
+		return fmt.Errorf("extracting archive: %w", err)
+	}
+	fmt.Printf("  Restored %s\n", rigPath)
+
+	rigsConfig.Rigs[rigName] = m.Entry
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if m.Entry.BeadsConfig != nil && m.Entry.BeadsConfig.Prefix != "" && m.RoutePath != "" {
+		route := beads.Route{Prefix: m.Entry.BeadsConfig.Prefix + "-", Path: m.RoutePath}
+		if err := beads.AppendRoute(townRoot, route); err != nil {
+			fmt.Printf("  %s Could not update routes.jsonl: %v\n", style.Warning.Render("!"), err)
+		}
+	}
+	// Archive leaves the rig parked; a restored rig starts clean.
+	_ = wisp.NewConfig(townRoot, rigName).Clear()
+
+	if m.Database != "" {
+		if err := doltserver.EnsureMetadata(townRoot, rigName); err != nil {
+			fmt.Printf("  %s Could not update metadata.json: %v\n", style.Warning.Render("!"), err)
+		}
+		fmt.Printf("  Restored Dolt database %s\n", m.Database)
+		// A running server only serves databases it found at startup.
+		if running, _, _ := doltserver.IsRunning(townRoot); running {
+			if rigRestoreNoRestart {
+				fmt.Printf("  %s Dolt server not restarted; run 'gt dolt stop && gt dolt start' to serve %s\n",
+					style.Warning.Render("!"), m.Database)
+			} else {
+				fmt.Printf("  Restarting Dolt server...\n")
+				if err := doltserver.Stop(townRoot); err != nil {
+					return fmt.Errorf("stopping Dolt server: %w", err)
+				}
+				if err := doltserver.Start(townRoot); err != nil {
+					return fmt.Errorf("restarting Dolt server: %w", err)
+				}
+			}
+		}
+	}
+
+	fmt.Printf("%s Rig %s restored\n", style.Success.Render("✓"), rigName)
+	fmt.Printf("  Start it with: %s\n", style.Dim.Render("gt rig start "+rigName))
+	return nil
+}
+
+// rigRoutePath returns the routes.jsonl path for a rig's beads, matching
+// what 'gt rig add' registers.
+func rigRoutePath(townRoot, rigName string) string {
+	if _, err := os.Stat(filepath.Join(townRoot, rigName, "mayor", "rig", ".beads")); err == nil {
+		return rigName + "/mayor/rig"
+	}
+	return rigName
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestVerifyRigArchive(t *testing.T) {
+	src := t.TempDir()
+	rigDir := filepath.Join(src, "gastown")
+	doltDir := filepath.Join(src, "dolt", "gastown")
+	for _, path := range []string{filepath.Join(rigDir, "config.json"), filepath.Join(doltDir, "junk")} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain := filepath.Join(t.TempDir(), "plain.tar.gz")
+	if err := rig.WriteArchive(plain, &rig.ArchiveManifest{Rig: "gastown"}, rig.ArchiveSources{RigDir: rigDir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyRigArchive(plain, &rig.ArchiveManifest{Rig: "gastown"}); err != nil {
+		t.Errorf("archive without a database: %v", err)
+	}
+	if err := verifyRigArchive(plain, &rig.ArchiveManifest{Rig: "beads"}); err == nil {
+		t.Error("manifest for another rig should fail verification")
+	}
+
+	// A database directory that is not a Dolt database must never pass,
+	// whether or not dolt is installed.
+	broken := &rig.ArchiveManifest{Rig: "gastown", Database: "gastown"}
+	withDB := filepath.Join(t.TempDir(), "withdb.tar.gz")
+	if err := rig.WriteArchive(withDB, broken, rig.ArchiveSources{RigDir: rigDir, DoltDir: doltDir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyRigArchive(withDB, broken); err == nil {
+		t.Error("archive with an unreadable database should fail verification")
+	}
+}
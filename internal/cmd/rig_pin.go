@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var rigPinJSON bool
+
+var rigPinCmd = &cobra.Command{
+	Use:   "pin <rig> [tool=version...]",
+	Short: "Pin or show the toolchain versions for a rig",
+	Long: `Pin the go, node, and dolt versions a rig is built and tested with.
+
+Pins are stored under "toolchain" in the rig's settings/config.json and are
+exported into the environment of the rig's refinery (including merge gates),
+polecats, and crew:
+  - GT_TOOLCHAIN_GO, GT_TOOLCHAIN_NODE, GT_TOOLCHAIN_DOLT for every pin
+  - GOTOOLCHAIN=go<version> for a full Go version, so the go command runs
+    exactly that release
+
+Versions may be partial: "1.22" accepts any 1.22.x. Use an empty version
+(go=) to remove a pin. With no tool arguments, shows the pins and the
+versions the rig's agents actually get. 'gt doctor' (rig-toolchain) warns
+when a pin is not satisfied.
+
+Restart the rig's agents after changing pins ('gt rig restart <rig>').
+
+Examples:
+  gt rig pin gastown                          # Show pins
+  gt rig pin gastown go=1.22.3 node=20        # Pin go and node
+  gt rig pin gastown dolt=1.43                # Pin dolt to 1.43.x
+  gt rig pin gastown node=                    # Remove the node pin`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRigPin,
+}
+
+func init() {
+	rigPinCmd.Flags().BoolVar(&rigPinJSON, "json", false, "Output as JSON")
+	rigCmd.AddCommand(rigPinCmd)
+}
+
+// rigPinStatus is one row of gt rig pin output.
+type rigPinStatus struct {
+	Tool      string `json:"tool"`
+	Pinned    string `json:"pinned,omitempty"`
+	Installed string `json:"installed,omitempty"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runRigPin(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		return setRigPins(rigName, r.Path, args[1:])
+	}
+
+	tc := config.LoadRigToolchain(r.Path)
+	env := os.Environ()
+	for k, v := range config.ToolchainEnv(tc) {
+		env = append(env, k+"="+v)
+	}
+
+	var rows []rigPinStatus
+	for _, tool := range config.ToolchainTools {
+		row := rigPinStatus{Tool: tool, Pinned: tc.Get(tool)}
+		installed, err := deps.ToolVersion(tool, env)
+		if err != nil {
+			row.Error = err.Error()
+		}
+		row.Installed = installed
+		row.OK = row.Pinned == "" || (err == nil && deps.VersionMatches(row.Pinned, installed))
+		rows = append(rows, row)
+	}
+
+	if rigPinJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Toolchain for %s", rigName)))
+	for _, row := range rows {
+		pinned := row.Pinned
+		if pinned == "" {
+			pinned = style.Dim.Render("(not pinned)")
+		}
+		installed := row.Installed
+		if installed == "" {
+			installed = "not found"
+		}
+		icon := style.Success.Render("✓")
+		if !row.OK {
+			icon = style.Error.Render("✗")
+		} else if row.Pinned == "" {
+			icon = style.Dim.Render("-")
+		}
+		fmt.Printf("  %s %-5s %-14s %s\n", icon, row.Tool, pinned, style.Dim.Render("installed: "+installed))
+	}
+	return nil
+}
+
+// setRigPins applies tool=version arguments to the rig's settings.
+func setRigPins(rigName, rigPath string, pins []string) error {
+	settingsPath := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return fmt.Errorf("loading settings: %w", err)
+		}
+		settings = config.NewRigSettings()
+	}
+	if settings.Toolchain == nil {
+		settings.Toolchain = &config.ToolchainConfig{}
+	}
+
+	for _, pin := range pins {
+		tool, version, ok := strings.Cut(pin, "=")
+		if !ok {
+			return fmt.Errorf("invalid pin %q: expected <tool>=<version>", pin)
+		}
+		if err := settings.Toolchain.Set(tool, version); err != nil {
+			return err
+		}
+	}
+	if settings.Toolchain.IsEmpty() {
+		settings.Toolchain = nil
+	}
+
+	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+
+	for _, pin := range pins {
+		tool, _, _ := strings.Cut(pin, "=")
+		if v := settings.Toolchain.Get(tool); v != "" {
+			fmt.Printf("%s Pinned %s %s for rig %s\n", style.Success.Render("✓"), tool, v, rigName)
+		} else {
+			fmt.Printf("%s Removed %s pin for rig %s\n", style.Success.Render("✓"), tool, rigName)
+		}
+	}
+	fmt.Printf("  Restart the rig's agents to apply: %s\n", style.Dim.Render("gt rig restart "+rigName))
+	return nil
+}
@@ -29,6 +29,8 @@ Rig settings control behavioral configuration for a rig:
 - Namepool settings
 - Crew startup settings
 - Workflow settings
+- Toolchain pins (see gt rig pin)
+- New-bead assignment rules (see gt bead assign)
 
 Settings are stored in settings/config.json within each rig directory.
 Use dot notation to access nested keys (e.g., role_agents.witness).`,
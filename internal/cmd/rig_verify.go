@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	rigVerifyKeep bool
+	rigVerifyJSON bool
+)
+
+// rigVerifyHolder is who the smoke test's throwaway bead and MR belong to.
+const rigVerifyHolder = "gt-rig-verify"
+
+// Smoke test step results.
+const (
+	verifyPass = "pass"
+	verifyWarn = "warn"
+	verifyFail = "fail"
+	verifySkip = "skip"
+)
+
+var rigVerifyCmd = &cobra.Command{
+	Use:   "verify <rig>",
+	Short: "Run an end-to-end smoke test of a rig",
+	Long: `Run an end-to-end smoke test of a rig and print a single go/no-go
+verdict. Use it after setting up a rig or upgrading gt, bd, or Dolt.
+
+Steps:
+  bead write       Create a throwaway bead and claim it (lease)
+  bead read-back   Read the bead back from the database
+  clone visibility Every clone of the rig (mayor, refinery, crew, polecats) sees it
+  branch           Fetch origin and create a throwaway branch with one commit
+  merge request    Submit the branch to the merge queue, held so the refinery
+                   never merges it, and confirm the queue sees it
+  dry merge        Test-merge the branch into the target in a scratch worktree
+  sync branch      No clone's beads sync branch is diverged or unpushed
+
+The throwaway bead, merge request, branches, and worktree are removed at the
+end (--keep leaves them for debugging). Nothing is pushed.
+
+Exits non-zero on no-go.
+
+Examples:
+  gt rig verify gastown
+  gt rig verify gastown --json
+  gt rig verify gastown --keep`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigVerify,
+}
+
+func init() {
+	rigVerifyCmd.Flags().BoolVar(&rigVerifyKeep, "keep", false, "Leave the throwaway bead, MR, and branches in place")
+	rigVerifyCmd.Flags().BoolVar(&rigVerifyJSON, "json", false, "Output as JSON")
+	rigCmd.AddCommand(rigVerifyCmd)
+}
+
+// rigVerifyStep is the outcome of one smoke test step.
+type rigVerifyStep struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// rigVerifier runs the smoke test of one rig and remembers what it created.
+type rigVerifier struct {
+	rig    *rig.Rig
+	bd     *beads.Beads
+	git    *git.Git
+	stamp  string
+	target string
+
+	beadID     string
+	mrID       string
+	branch     string
+	baseBranch string
+	worktree   string
+
+	steps []rigVerifyStep
+}
+
+// step runs fn as the named step unless an earlier required step failed.
+// fn returns the step's detail, its status (pass or warn) and any failure.
+func (v *rigVerifier) step(name string, fn func() (string, string, error)) bool {
+	for _, s := range v.steps {
+		if s.Status == verifyFail {
+			v.steps = append(v.steps, rigVerifyStep{Name: name, Status: verifySkip, Detail: "earlier step failed"})
+			return false
+		}
+	}
+	start := time.Now()
+	detail, status, err := fn()
+	if err != nil {
+		status, detail = verifyFail, err.Error()
+	}
+	v.steps = append(v.steps, rigVerifyStep{Name: name, Status: status, Detail: detail, Duration: time.Since(start).Milliseconds()})
+	return err == nil
+}
+
+func runRigVerify(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	v := &rigVerifier{
+		rig:    r,
+		bd:     beads.New(r.Path),
+		stamp:  time.Now().UTC().Format("20060102-150405"),
+		target: r.DefaultBranch(),
+	}
+	v.run()
+	if !rigVerifyKeep {
+		v.cleanup()
+	}
+
+	goAhead := true
+	for _, s := range v.steps {
+		goAhead = goAhead && s.Status != verifyFail
+	}
+
+	if rigVerifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Rig   string          `json:"rig"`
+			Go    bool            `json:"go"`
+			Steps []rigVerifyStep `json:"steps"`
+		}{rigName, goAhead, v.steps}); err != nil {
+			return err
+		}
+	} else {
+		printRigVerify(rigName, goAhead, v.steps)
+	}
+	if !goAhead {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// run performs the smoke test steps in order.
+func (v *rigVerifier) run() {
+	title := "gt rig verify smoke test " + v.stamp
+	clones := rigSyncClones(v.rig)
+
+	v.step("bead write", func() (string, string, error) {
+		issue, err := v.bd.Create(beads.CreateOptions{
+			Title:       title,
+			Type:        "task",
+			Priority:    4,
+			Description: "Throwaway bead created by gt rig verify; removed when the check ends.",
+			Actor:       rigVerifyHolder,
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("creating bead: %w", err)
+		}
+		v.beadID = issue.ID
+		// Lease it so no agent picks up the throwaway bead meanwhile.
+		if _, err := v.bd.Claim(issue.ID, rigVerifyHolder, 15*time.Minute, false); err != nil {
+			return "", "", fmt.Errorf("claiming %s: %w", issue.ID, err)
+		}
+		return "created and claimed " + issue.ID, verifyPass, nil
+	})
+
+	v.step("bead read-back", func() (string, string, error) {
+		issue, err := v.bd.Show(v.beadID)
+		if err != nil {
+			return "", "", fmt.Errorf("reading %s: %w", v.beadID, err)
+		}
+		if issue.Title != title {
+			return "", "", fmt.Errorf("%s has title %q, want %q", v.beadID, issue.Title, title)
+		}
+		if l := beads.ParseLease(issue); l == nil || l.Holder != rigVerifyHolder || issue.Assignee != rigVerifyHolder {
+			return "", "", fmt.Errorf("%s claim was not persisted", v.beadID)
+		}
+		return v.beadID + " read back with its claim", verifyPass, nil
+	})
+
+	v.step("clone visibility", func() (string, string, error) {
+		if len(clones) == 0 {
+			return "no clones found", verifyWarn, nil
+		}
+		var missing []string
+		for _, c := range clones {
+			if _, err := beads.New(c[1]).Show(v.beadID); err != nil {
+				missing = append(missing, c[0])
+			}
+		}
+		if len(missing) > 0 {
+			return "", "", fmt.Errorf("%s not visible from %s", v.beadID, strings.Join(missing, ", "))
+		}
+		return fmt.Sprintf("visible from %d clone(s)", len(clones)), verifyPass, nil
+	})
+
+	v.step("branch", func() (string, string, error) {
+		repo := ""
+		for _, c := range clones {
+			if c[0] == "refinery" || (repo == "" && c[0] == "mayor") {
+				repo = c[1]
+			}
+		}
+		if repo == "" {
+			return "", "", fmt.Errorf("no refinery or mayor clone in %s", v.rig.Path)
+		}
+		v.git = git.NewGit(repo)
+		remoteRef := "refs/remotes/origin/" + v.target
+		if err := v.git.FetchBranch("origin", "+"+v.target+":"+remoteRef); err != nil {
+			return "", "", fmt.Errorf("fetching origin/%s: %w", v.target, err)
+		}
+		base, err := v.git.Rev(remoteRef)
+		if err != nil {
+			return "", "", fmt.Errorf("resolving origin/%s: %w", v.target, err)
+		}
+		commit, err := v.git.CommitFile(base, ".gt-verify-"+v.stamp, []byte(v.beadID+"\n"), "gt rig verify smoke test", base)
+		if err != nil {
+			return "", "", fmt.Errorf("committing: %w", err)
+		}
+		branch, baseBranch := "verify/smoke-"+v.stamp, "verify/smoke-"+v.stamp+"-base"
+		if err := v.git.UpdateRef("refs/heads/"+branch, commit, ""); err != nil {
+			return "", "", fmt.Errorf("creating %s: %w", branch, err)
+		}
+		v.branch = branch
+		if err := v.git.UpdateRef("refs/heads/"+baseBranch, base, ""); err != nil {
+			return "", "", fmt.Errorf("creating %s: %w", baseBranch, err)
+		}
+		v.baseBranch = baseBranch
+		return fmt.Sprintf("%s off origin/%s", branch, v.target), verifyPass, nil
+	})
+
+	v.step("merge request", func() (string, string, error) {
+		mr, err := v.bd.Create(beads.CreateOptions{
+			Title:    "Merge: " + v.beadID,
+			Type:     "merge-request",
+			Priority: 4,
+			Description: fmt.Sprintf("branch: %s\ntarget: %s\nsource_issue: %s\nrig: %s\nworker: %s",
+				v.branch, v.target, v.beadID, v.rig.Name, rigVerifyHolder),
+			Ephemeral: true,
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("creating merge request: %w", err)
+		}
+		v.mrID = mr.ID
+		// Held twice over: blocked on the open throwaway bead, and claimed.
+		if err := v.bd.AddDependency(mr.ID, v.beadID); err != nil {
+			return "", "", fmt.Errorf("blocking %s on %s: %w", mr.ID, v.beadID, err)
+		}
+		holder := rigVerifyHolder
+		if err := v.bd.Update(mr.ID, beads.UpdateOptions{Assignee: &holder}); err != nil {
+			return "", "", fmt.Errorf("claiming %s: %w", mr.ID, err)
+		}
+
+		blocked, err := refinery.NewEngineer(v.rig).ListBlockedMRs()
+		if err != nil {
+			return "", "", fmt.Errorf("listing the queue: %w", err)
+		}
+		for _, m := range blocked {
+			if m.ID == mr.ID {
+				if m.Branch != v.branch || m.Target != v.target {
+					return "", "", fmt.Errorf("queue reads %s as %s -> %s", mr.ID, m.Branch, m.Target)
+				}
+				return mr.ID + " queued and held", verifyPass, nil
+			}
+		}
+		return "", "", fmt.Errorf("%s is not in the merge queue", mr.ID)
+	})
+
+	v.step("dry merge", func() (string, string, error) {
+		dir, err := os.MkdirTemp("", "gt-rig-verify-*")
+		if err != nil {
+			return "", "", err
+		}
+		if err := v.git.WorktreeAddExisting(dir, v.baseBranch); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", "", fmt.Errorf("creating scratch worktree: %w", err)
+		}
+		v.worktree = dir
+		conflicts, err := git.NewGit(dir).CheckConflicts(v.branch, v.baseBranch)
+		if err != nil {
+			return "", "", fmt.Errorf("test merge: %w", err)
+		}
+		if len(conflicts) > 0 {
+			return "", "", fmt.Errorf("conflicts in %s", strings.Join(conflicts, ", "))
+		}
+		return "merges cleanly into " + v.target, verifyPass, nil
+	})
+
+	// Independent of the steps above: runs even if one of them failed.
+	start := time.Now()
+	detail, status := verifySyncClones(clones)
+	v.steps = append(v.steps, rigVerifyStep{Name: "sync branch", Status: status, Detail: detail, Duration: time.Since(start).Milliseconds()})
+}
+
+// verifySyncClones checks that no clone's beads sync branch has stopped
+// propagating.
+func verifySyncClones(clones [][2]string) (string, string) {
+	opts, _ := beadSyncMergeOptions()
+	var diverged, ahead, failed []string
+	missing := 0
+	for _, c := range clones {
+		report := inspectSyncClone(c[0], c[1], opts)
+		switch {
+		case report.Error != "":
+			failed = append(failed, c[0])
+		case report.State == syncStateDiverged:
+			diverged = append(diverged, c[0])
+		case report.State == syncStateAhead:
+			ahead = append(ahead, c[0])
+		case report.State == syncStateMissing:
+			missing++
+		}
+	}
+	switch {
+	case len(diverged) > 0:
+		return "diverged in " + strings.Join(diverged, ", ") + " (gt bead sync resolve)", verifyFail
+	case len(ahead) > 0 || len(failed) > 0:
+		var parts []string
+		if len(ahead) > 0 {
+			parts = append(parts, "unpushed in "+strings.Join(ahead, ", "))
+		}
+		if len(failed) > 0 {
+			parts = append(parts, "could not inspect "+strings.Join(failed, ", "))
+		}
+		return strings.Join(parts, "; "), verifyWarn
+	case missing == len(clones):
+		return "no sync branch in use", verifySkip
+	}
+	return fmt.Sprintf("in step across %d clone(s)", len(clones)-missing), verifyPass
+}
+
+// cleanup removes everything the smoke test created, recording what could
+// not be removed as a warning step.
+func (v *rigVerifier) cleanup() {
+	var leftover []string
+	if v.worktree != "" {
+		if err := v.git.WorktreeRemove(v.worktree, true); err != nil {
+			leftover = append(leftover, "worktree "+v.worktree)
+		}
+	}
+	for _, branch := range []string{v.branch, v.baseBranch} {
+		if branch != "" {
+			if err := v.git.DeleteBranch(branch, true); err != nil {
+				leftover = append(leftover, "branch "+branch)
+			}
+		}
+	}
+	for _, id := range []string{v.mrID, v.beadID} {
+		if id != "" {
+			if err := v.bd.DeleteHard(id); err != nil {
+				leftover = append(leftover, "bead "+id)
+			}
+		}
+	}
+	if len(leftover) > 0 {
+		v.steps = append(v.steps, rigVerifyStep{Name: "cleanup", Status: verifyWarn, Detail: "could not remove " + strings.Join(leftover, ", ")})
+	}
+}
+
+func printRigVerify(rigName string, goAhead bool, steps []rigVerifyStep) {
+	fmt.Printf("\n%s Verifying rig %s\n\n", style.Bold.Render("🔍"), style.Bold.Render(rigName))
+	for _, s := range steps {
+		prefix := style.SuccessPrefix
+		switch s.Status {
+		case verifyWarn:
+			prefix = style.WarningPrefix
+		case verifyFail:
+			prefix = style.ErrorPrefix
+		case verifySkip:
+			prefix = style.Dim.Render("-")
+		}
+		fmt.Printf("  %s %-17s %s", prefix, s.Name, s.Detail)
+		if s.Duration > 0 {
+			fmt.Printf(" %s", style.Dim.Render(fmt.Sprintf("(%dms)", s.Duration)))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	if goAhead {
+		fmt.Printf("%s GO: %s passed its smoke test\n", style.SuccessPrefix, rigName)
+	} else {
+		fmt.Printf("%s NO-GO: %s failed its smoke test\n", style.ErrorPrefix, rigName)
+	}
+}
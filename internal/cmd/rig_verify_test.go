@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRigVerifierStepSkipsAfterFailure(t *testing.T) {
+	v := &rigVerifier{}
+	ran := 0
+	ok := v.step("first", func() (string, string, error) { ran++; return "fine", verifyPass, nil })
+	if !ok {
+		t.Fatal("passing step reported failure")
+	}
+	v.step("second", func() (string, string, error) { ran++; return "", "", errors.New("boom") })
+	v.step("third", func() (string, string, error) { ran++; return "fine", verifyPass, nil })
+
+	if ran != 2 {
+		t.Errorf("ran %d steps, want 2", ran)
+	}
+	want := []string{verifyPass, verifyFail, verifySkip}
+	for i, s := range v.steps {
+		if s.Status != want[i] {
+			t.Errorf("step %s status = %s, want %s", s.Name, s.Status, want[i])
+		}
+	}
+	if v.steps[1].Detail != "boom" {
+		t.Errorf("failure detail = %q", v.steps[1].Detail)
+	}
+}
+
+func TestVerifySyncClonesNoClones(t *testing.T) {
+	if _, status := verifySyncClones(nil); status != verifySkip {
+		t.Errorf("status = %s, want %s", status, verifySkip)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/ui"
@@ -17,6 +20,12 @@ import (
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// townFlag is the global --town flag.
+var townFlag string
+
+// cmdLog is the logger for the cmd module.
+var cmdLog = logging.For("cmd")
+
 var rootCmd = &cobra.Command{
 	Use:     "gt", // Updated in init() based on GT_COMMAND
 	Short:   "Gas Town - Multi-agent workspace manager",
@@ -58,6 +67,8 @@ var beadsExemptCommands = map[string]bool{
 	"feed":       true,
 	"rig":        true,
 	"config":     true,
+	"secret":     true,
+	"ratelimit":  true,
 	"install":    true,
 	"tap":        true,
 	"dnd":        true,
@@ -93,9 +104,20 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Select the town before anything looks for it
+	if townFlag != "" {
+		if err := workspace.SetOverride(townFlag); err != nil {
+			return fmt.Errorf("--town: %w", err)
+		}
+	}
+
 	// Initialize CLI theme (dark/light mode support)
 	initCLITheme()
 
+	initLogging()
+	initAgeThresholds()
+	cmdLog.Debug("run", "command", cmd.CommandPath())
+
 	// Initialize session prefix registry from rigs.json.
 	// Best-effort: if town root not found, the default "gt" prefix is used.
 	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -104,6 +126,7 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(os.Stderr, "WARNING: failed to load agent registry %s: %v\n",
 				config.DefaultAgentRegistryPath(townRoot), err)
 		}
+		touchWorkerHeartbeat(townRoot, cmd)
 	}
 
 	// Get the root command name being run
@@ -143,12 +166,70 @@ func initCLITheme() {
 			configTheme = settings.CLITheme
 		}
 	}
+	// Fall back to the user's own setting (gt config set cli_theme --scope user)
+	if configTheme == "" {
+		if v, err := config.NewConfigLayers("", "").Get("cli_theme"); err == nil && v.Scope == config.ScopeUser {
+			configTheme = v.Value
+		}
+	}
 
 	// Initialize theme with config value (env var takes precedence inside InitTheme)
 	ui.InitTheme(configTheme)
 	ui.ApplyThemeMode()
 }
 
+// initLogging opens the town's module log with the levels from town
+// settings. Outside a town only warnings are printed, to stderr.
+func initLogging() {
+	var townRoot string
+	var levels map[string]string
+	if root, err := workspace.FindFromCwd(); err == nil && root != "" {
+		townRoot = root
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(root)); err == nil {
+			levels = settings.Log
+		}
+	}
+	if err := logging.Init(townRoot, levels); err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: logging: %v\n", err)
+	}
+}
+
+// initAgeThresholds applies the town's age band thresholds (settings
+// "aging") to the listings' age columns. Bad values are warned about and
+// leave the default in place.
+func initAgeThresholds() {
+	root, err := workspace.FindFromCwd()
+	if err != nil || root == "" {
+		return
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(root))
+	if err != nil || settings.Aging == nil {
+		return
+	}
+	for key, cfg := range settings.Aging.Priorities {
+		priority, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(key), "P"))
+		if err != nil || priority < 0 || priority > 4 {
+			fmt.Fprintf(os.Stderr, "WARNING: aging: unknown priority %q (want P0-P4)\n", key)
+			continue
+		}
+		threshold := func(name, value string) time.Duration {
+			if value == "" {
+				return 0
+			}
+			d, err := parseDuration(value)
+			if err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "WARNING: aging: %s %s: invalid duration %q\n", key, name, value)
+				return 0
+			}
+			return d
+		}
+		style.SetAgeThresholds(priority, style.AgeThresholds{
+			Aging: threshold("aging", cfg.Aging),
+			Stale: threshold("stale", cfg.Stale),
+		})
+	}
+}
+
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
 // This is a non-blocking warning to help catch accidental branch switches.
 func warnIfTownRootOffMain() {
@@ -267,8 +348,8 @@ func init() {
 	rootCmd.SetHelpCommandGroupID(GroupDiag)
 	rootCmd.SetCompletionCommandGroupID(GroupConfig)
 
-	// Global flags can be added here
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	rootCmd.PersistentFlags().StringVar(&townFlag, "town", "",
+		"Town to operate on, by path or name (default: the town containing the current directory; env "+workspace.TownEnvVar+")")
 }
 
 // buildCommandPath walks the command hierarchy to build the full command path.
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Secret command flags
+var (
+	secretGetReveal bool
+	secretListJSON  bool
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Manage the town's encrypted secrets",
+	Long: `Store API tokens, passwords and webhook URLs encrypted at rest, so
+settings files don't hold them in plaintext.
+
+Secrets live in <town>/.gastown/secrets/, one AES-256-GCM sealed file
+each. The key never lives in the town: it is read from GT_SECRETS_KEY
+(base64, 32 bytes), the OS keychain (macOS security, Linux secret-tool),
+or ~/.config/gastown/secrets.key (mode 0600), and the first 'gt secret set'
+creates one. A town moved to another host needs the same key there.
+
+Settings refer to a secret as "secret:<name>":
+  dolt.password               The Dolt server password, when GT_DOLT_PASSWORD
+                              is unset
+  mq-webhooks.json            "url" and "secret" of each webhook, and
+                              "token_secret" (a bare name) for commit statuses
+  doctor-monitor.json         "url" and "secret" of each webhook
+
+Examples:
+  gt secret set dolt.password          # prompts, without echo
+  printf %s "$TOKEN" | gt secret set github.token
+  gt secret list
+  gt secret get github.token | gh auth login --with-token`,
+	RunE: requireSubcommand,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret, read from stdin",
+	Long: `Encrypt a secret and store it under <name>.
+
+The value is read from stdin, never from the command line, so it stays
+out of shell history and process listings. On a terminal it is prompted
+for without echo; piped input is read to EOF, less one trailing newline.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretSet,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret's value, for piping into another command",
+	Long: `Decrypt a secret and write its value to stdout.
+
+To keep secrets off screens and out of scrollback, the value is only
+written when stdout is a pipe or file. Pass --reveal to print it to a
+terminal anyway.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretGet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the stored secrets, without their values",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretList,
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Delete a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretRm,
+}
+
+func init() {
+	secretGetCmd.Flags().BoolVar(&secretGetReveal, "reveal", false, "Print the value even when stdout is a terminal")
+	secretListCmd.Flags().BoolVar(&secretListJSON, "json", false, "Output as JSON")
+
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretRmCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("empty value: nothing stored")
+	}
+	if err := secrets.Set(townRoot, name, value); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Stored secret %s (key: %s)\n", style.SuccessPrefix, style.Bold.Render(name), secrets.KeySource())
+	return nil
+}
+
+// readSecretValue reads a secret from stdin: prompted without echo on a
+// terminal, else read to EOF.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading value: %w", err)
+		}
+		return string(b), nil
+	}
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading value: %w", err)
+	}
+	s := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(s, "\r"), nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if term.IsTerminal(int(os.Stdout.Fd())) && !secretGetReveal {
+		return fmt.Errorf("not printing %s to a terminal: pipe it to a command, or pass --reveal", args[0])
+	}
+	value, err := secrets.Get(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(os.Stdout, value)
+	return err
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	infos, err := secrets.List(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if secretListJSON {
+		if infos == nil {
+			infos = []secrets.Info{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No secrets. Store one with: gt secret set <name>"))
+		return nil
+	}
+	fmt.Printf("%s Secrets:\n\n", style.Bold.Render("🔐"))
+	for _, info := range infos {
+		updated := "?"
+		if !info.UpdatedAt.IsZero() {
+			updated = style.HumanizeAge(time.Since(info.UpdatedAt)) + " ago"
+		}
+		fmt.Printf("  %-32s %s\n", info.Name, style.Dim.Render("updated "+updated))
+	}
+	source := secrets.KeySource()
+	if source == "" {
+		source = "none on this host"
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Key: "+source))
+	return nil
+}
+
+func runSecretRm(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := secrets.Delete(townRoot, args[0]); err != nil {
+		if errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("secret %s is not set", args[0])
+		}
+		return err
+	}
+	fmt.Printf("%s Deleted secret %s\n", style.SuccessPrefix, style.Bold.Render(args[0]))
+	return nil
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+var (
+	serveMetricsAddr          string
+	serveMetricsInterval      time.Duration
+	serveMetricsLatencyWindow string
+)
+
+// mergeLatencyBuckets are the merge latency histogram bounds, in seconds:
+// 1m to 2d.
+var mergeLatencyBuckets = []float64{60, 300, 900, 1800, 3600, 7200, 14400, 28800, 86400, 172800}
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	GroupID: GroupDiag,
+	Short:   "Run optional HTTP endpoints (metrics)",
+	RunE:    requireSubcommand,
+	Long: `Run optional HTTP endpoints for the town.
+
+Subcommands:
+  metrics   Export town metrics in Prometheus format`,
+}
+
+var serveMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export town metrics in Prometheus format",
+	Long: `Serve town metrics at /metrics in the Prometheus text format.
+
+Metrics are collected in the background every --interval (collection runs
+bd, so scrapes return the latest snapshot rather than querying on demand):
+
+  gastown_mq_depth{rig,state}                 Merge requests: ready, blocked, in_flight
+  gastown_beads{rig,status}                   Beads by status ("town" for HQ)
+  gastown_dolt_up                             1 if the Dolt server is reachable
+  gastown_dolt_supervisor_up                  1 if 'gt dolt supervise' is running
+  gastown_doctor_checks{scope,status}         Latest doctor run's checks by status
+  gastown_doctor_score{scope}                 Its health score (see 'gt doctor score')
+  gastown_mq_merge_latency_seconds{rig}       Histogram of queue time of MRs merged
+                                              in the last --latency-window
+  gastown_metrics_collect_duration_seconds    How long the last collection took
+  gastown_metrics_collect_errors              Sources that failed in the last collection
+
+Doctor metrics come from recorded runs; schedule 'gt doctor' to keep them
+fresh. The scope label is "town" or the rig of a 'gt doctor --rig' run.
+
+Examples:
+  gt serve metrics                           # 127.0.0.1:9464
+  gt serve metrics --addr :9464 --interval 1m
+  gt serve metrics --latency-window 24h`,
+	Args: cobra.NoArgs,
+	RunE: runServeMetrics,
+}
+
+func init() {
+	serveMetricsCmd.Flags().StringVar(&serveMetricsAddr, "addr", "127.0.0.1:9464", "Address to listen on")
+	serveMetricsCmd.Flags().DurationVar(&serveMetricsInterval, "interval", 30*time.Second, "How often to collect metrics")
+	serveMetricsCmd.Flags().StringVar(&serveMetricsLatencyWindow, "latency-window", "7d", "Window of merged MRs in the latency histogram")
+
+	serveCmd.AddCommand(serveMetricsCmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+// metricsSnapshot holds the latest rendered collection.
+type metricsSnapshot struct {
+	mu   sync.RWMutex
+	body []byte
+}
+
+func (s *metricsSnapshot) set(body []byte) {
+	s.mu.Lock()
+	s.body = body
+	s.mu.Unlock()
+}
+
+func (s *metricsSnapshot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	body := s.body
+	s.mu.RUnlock()
+	if body == nil {
+		http.Error(w, "metrics not collected yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_, _ = w.Write(body)
+}
+
+func runServeMetrics(cmd *cobra.Command, args []string) error {
+	if serveMetricsInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	window, err := parseDuration(serveMetricsLatencyWindow)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --latency-window %q", serveMetricsLatencyWindow)
+	}
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+
+	snapshot := &metricsSnapshot{}
+	collect := func() {
+		families := collectTownMetrics(townRoot, rigs, window)
+		var buf bytes.Buffer
+		if err := metrics.Write(&buf, families); err != nil {
+			log.Printf("metrics: rendering: %v", err)
+			return
+		}
+		snapshot.set(buf.Bytes())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		collect()
+		ticker := time.NewTicker(serveMetricsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				collect()
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", snapshot)
+	server := &http.Server{Addr: serveMetricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving metrics at http://%s/metrics (collecting every %s)\n", serveMetricsAddr, serveMetricsInterval)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// collectTownMetrics gathers one snapshot of the town's metrics. A source
+// that fails is logged, counted in gastown_metrics_collect_errors, and left
+// out rather than failing the scrape.
+func collectTownMetrics(townRoot string, rigs []*rig.Rig, latencyWindow time.Duration) []*metrics.Family {
+	start := time.Now()
+	errCount := 0
+	fail := func(what string, err error) {
+		errCount++
+		log.Printf("metrics: %s: %v", what, err)
+	}
+
+	mqDepth := &metrics.Family{Name: "gastown_mq_depth", Type: metrics.Gauge, Help: "Merge requests in the queue, by state."}
+	for _, r := range rigs {
+		if summary := getMQSummary(r); summary != nil {
+			mqDepth.Add(float64(summary.Pending), metrics.Labels{"rig": r.Name, "state": "ready"})
+			mqDepth.Add(float64(summary.Blocked), metrics.Labels{"rig": r.Name, "state": "blocked"})
+			mqDepth.Add(float64(summary.InFlight), metrics.Labels{"rig": r.Name, "state": "in_flight"})
+		}
+	}
+
+	beadCounts := &metrics.Family{Name: "gastown_beads", Type: metrics.Gauge, Help: "Beads by status."}
+	beadDirs := map[string]string{"town": townRoot}
+	for _, r := range rigs {
+		beadDirs[r.Name] = r.BeadsPath()
+	}
+	for name, dir := range beadDirs {
+		issues, err := beads.New(dir).List(beads.ListOptions{Status: "all"})
+		if err != nil {
+			fail("listing "+name+" beads", err)
+			continue
+		}
+		byStatus := make(map[string]int)
+		for _, issue := range issues {
+			byStatus[issue.Status]++
+		}
+		for status, n := range byStatus {
+			beadCounts.Add(float64(n), metrics.Labels{"rig": name, "status": status})
+		}
+	}
+
+	doltUp := &metrics.Family{Name: "gastown_dolt_up", Type: metrics.Gauge, Help: "1 if the Dolt server is reachable."}
+	up := 0.0
+	if err := doltserver.CheckServerReachable(townRoot); err == nil {
+		up = 1
+	}
+	doltUp.Add(up, nil)
+	supervisorUp := &metrics.Family{Name: "gastown_dolt_supervisor_up", Type: metrics.Gauge, Help: "1 if the Dolt supervisor is running."}
+	supervisorRunning, _ := doltserver.SupervisorRunning(townRoot)
+	supervisorUp.Add(boolMetric(supervisorRunning), nil)
+
+	doctorChecks := &metrics.Family{Name: "gastown_doctor_checks", Type: metrics.Gauge, Help: "Checks of the latest doctor run, by status."}
+	doctorScore := &metrics.Family{Name: "gastown_doctor_score", Type: metrics.Gauge, Help: "Health score (0-100) of the latest doctor run."}
+	if scores, err := townHealthScores(townRoot); err != nil {
+		fail("doctor scores", err)
+	} else {
+		history, _ := doctor.LoadHistory(townRoot)
+		for _, s := range scores {
+			scope := s.Rig
+			if scope == "" {
+				scope = "town"
+			}
+			doctorScore.Add(float64(s.Score), metrics.Labels{"scope": scope})
+			if run := doctor.FindBaseline(history, s.Rig, s.Timestamp.Add(time.Nanosecond)); run != nil {
+				doctorChecks.Add(float64(run.Summary.OK), metrics.Labels{"scope": scope, "status": "ok"})
+				doctorChecks.Add(float64(run.Summary.Warnings), metrics.Labels{"scope": scope, "status": "warning"})
+				doctorChecks.Add(float64(run.Summary.Errors), metrics.Labels{"scope": scope, "status": "error"})
+			}
+		}
+	}
+
+	latency := &metrics.Family{
+		Name:    "gastown_mq_merge_latency_seconds",
+		Type:    metrics.Histogram,
+		Help:    "Time merged MRs spent in the queue, from submission to merge.",
+		Buckets: mergeLatencyBuckets,
+	}
+	if records, err := mqStatsRecords(townRoot, rigs, start.Add(-latencyWindow)); err != nil {
+		fail("merge queue history", err)
+	} else {
+		waits := make(map[string][]float64)
+		for _, rec := range records {
+			if rec.Merged() && !rec.CreatedAt.IsZero() {
+				waits[rec.Rig] = append(waits[rec.Rig], rec.ClosedAt.Sub(rec.CreatedAt).Seconds())
+			}
+		}
+		for _, r := range rigs {
+			latency.Observe(waits[r.Name], metrics.Labels{"rig": r.Name})
+		}
+	}
+
+	duration := &metrics.Family{Name: "gastown_metrics_collect_duration_seconds", Type: metrics.Gauge, Help: "Duration of the last metrics collection."}
+	duration.Add(time.Since(start).Seconds(), nil)
+	errs := &metrics.Family{Name: "gastown_metrics_collect_errors", Type: metrics.Gauge, Help: "Sources that failed in the last metrics collection."}
+	errs.Add(float64(errCount), nil)
+
+	return []*metrics.Family{mqDepth, beadCounts, doltUp, supervisorUp, doctorChecks, doctorScore, latency, duration, errs}
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -192,7 +192,6 @@ func checkStopSlungWork(townRoot string) string {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: identity,
-		Priority: -1,
 		Limit:    1,
 	})
 	if err == nil && len(hookedBeads) > 0 {
@@ -247,7 +247,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 		} else {
 			doltOK = true
 			mu.Lock()
-			fmt.Printf("  %s Dolt server started (port %d)\n", style.Bold.Render("✓"), doltserver.DefaultPort)
+			fmt.Printf("  %s Dolt server started (port %d)\n", style.Bold.Render("✓"), doltserver.DefaultConfig(townRoot).Port)
 			mu.Unlock()
 		}
 	}()
@@ -18,8 +18,11 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/doctor"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/mq"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -34,6 +37,9 @@ var statusWatch bool
 var statusInterval int
 var statusVerbose bool
 
+// statusTrendWindow is how much metrics history the status trends show.
+const statusTrendWindow = 24 * time.Hour
+
 var statusCmd = &cobra.Command{
 	Use:     "status",
 	Aliases: []string{"stat"},
@@ -41,7 +47,9 @@ var statusCmd = &cobra.Command{
 	Short:   "Show overall town status",
 	Long: `Display the current status of the Gas Town workspace.
 
-Shows town name, registered rigs, polecats, and witness status.
+Shows town name, registered rigs, polecats, and witness status, with
+sparklines of the last day of 'gt town metrics' when the daemon has
+recorded them.
 
 Use --fast to skip mail lookups for faster execution.
 Use --watch to continuously refresh status at regular intervals.`,
@@ -59,12 +67,14 @@ func init() {
 
 // TownStatus represents the overall status of the workspace.
 type TownStatus struct {
-	Name     string         `json:"name"`
-	Location string         `json:"location"`
-	Overseer *OverseerInfo  `json:"overseer,omitempty"` // Human operator
-	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
-	Rigs     []RigStatus    `json:"rigs"`
-	Summary  StatusSum      `json:"summary"`
+	Name     string              `json:"name"`
+	Location string              `json:"location"`
+	Overseer *OverseerInfo       `json:"overseer,omitempty"` // Human operator
+	Agents   []AgentRuntime      `json:"agents"`             // Global agents (Mayor, Deacon)
+	Rigs     []RigStatus         `json:"rigs"`
+	Summary  StatusSum           `json:"summary"`
+	Health   *doctor.HealthScore `json:"health,omitempty"` // Latest 'gt doctor' score
+	Trends   []metrics.Trend     `json:"trends,omitempty"` // Last day of 'gt town metrics'
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -95,16 +105,18 @@ type AgentRuntime struct {
 
 // RigStatus represents status of a single rig.
 type RigStatus struct {
-	Name         string          `json:"name"`
-	Polecats     []string        `json:"polecats"`
-	PolecatCount int             `json:"polecat_count"`
-	Crews        []string        `json:"crews"`
-	CrewCount    int             `json:"crew_count"`
-	HasWitness   bool            `json:"has_witness"`
-	HasRefinery  bool            `json:"has_refinery"`
-	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
-	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
-	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+	Name         string              `json:"name"`
+	Polecats     []string            `json:"polecats"`
+	PolecatCount int                 `json:"polecat_count"`
+	Crews        []string            `json:"crews"`
+	CrewCount    int                 `json:"crew_count"`
+	HasWitness   bool                `json:"has_witness"`
+	HasRefinery  bool                `json:"has_refinery"`
+	Hooks        []AgentHookInfo     `json:"hooks,omitempty"`
+	Agents       []AgentRuntime      `json:"agents,omitempty"`  // Runtime state of all agents in rig
+	MQ           *MQSummary          `json:"mq,omitempty"`      // Merge queue summary
+	Health       *doctor.HealthScore `json:"health,omitempty"`  // Latest 'gt doctor --rig' score
+	MQHold       *mq.Hold            `json:"mq_hold,omitempty"` // Refinery pause or maintenance window
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -798,6 +810,9 @@ func gatherStatus() (TownStatus, error) {
 			if !statusFast {
 				rs.MQ = getMQSummary(r)
 			}
+			if r.HasRefinery {
+				rs.MQHold, _ = mq.ActiveHold(r.Path, time.Now())
+			}
 
 			status.Rigs[idx] = rs
 		}(i, r)
@@ -835,6 +850,24 @@ func gatherStatus() (TownStatus, error) {
 	}
 	status.Summary.RigCount = len(rigs)
 
+	// Health scores from the latest doctor runs (none if doctor never ran)
+	if scores, err := townHealthScores(townRoot); err == nil {
+		for i := range scores {
+			if scores[i].Rig == "" {
+				status.Health = &scores[i]
+				continue
+			}
+			for j := range status.Rigs {
+				if status.Rigs[j].Name == scores[i].Rig {
+					status.Rigs[j].Health = &scores[i]
+				}
+			}
+		}
+	}
+
+	// Trends from the metrics history the daemon samples
+	status.Trends = townMetricTrends(townRoot, statusTrendWindow)
+
 	return status, nil
 }
 
@@ -864,6 +897,21 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		fmt.Fprintln(w)
 	}
 
+	// Health score of the latest doctor run
+	if status.Health != nil {
+		fmt.Fprintf(w, "🩺 %s %s %s\n\n", style.Bold.Render("Health:"), formatHealthScore(status.Health.Score),
+			style.Dim.Render("(gt doctor, "+formatAge(status.Health.Timestamp)+")"))
+	}
+
+	// Sparklines of the last day of metrics
+	if len(status.Trends) > 0 {
+		fmt.Fprintf(w, "📈 %s %s\n", style.Bold.Render("Trends"), style.Dim.Render("(24h)"))
+		for _, t := range status.Trends {
+			fmt.Fprintf(w, "   %-11s %s  %s\n", t.Label, t.Sparkline, t.Format(t.Latest))
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Role icons - uses centralized emojis from constants package
 	roleIcons := map[string]string{
 		constants.RoleMayor:    constants.EmojiMayor,
@@ -905,6 +953,12 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 	for _, r := range status.Rigs {
 		// Rig header with separator
 		fmt.Fprintf(w, "─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		if r.Health != nil {
+			fmt.Fprintf(w, "🩺 Health %s\n", formatHealthScore(r.Health.Score))
+		}
+		if r.MQHold != nil {
+			fmt.Fprintf(w, "⏸  %s\n", style.Warning.Render(r.MQHold.Banner(time.Now())))
+		}
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
@@ -1584,9 +1638,8 @@ func getMQSummary(r *rig.Rig) *MQSummary {
 
 	// Query for all open merge-request issues
 	opts := beads.ListOptions{
-		Label:    "gt:merge-request",
-		Status:   "open",
-		Priority: -1, // No priority filter
+		Label:  "gt:merge-request",
+		Status: "open",
 	}
 	openMRs, err := b.List(opts)
 	if err != nil {
@@ -673,7 +673,6 @@ func getHookedWork(identity string, maxLen int, beadsDir string) string {
 	hookedBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: identity,
-		Priority: -1,
 	})
 	if err != nil || len(hookedBeads) == 0 {
 		return ""
@@ -706,8 +705,7 @@ func getCurrentWork(t *tmux.Tmux, session string, maxLen int) string {
 	// Query beads for in_progress issues
 	b := beads.New(workDir)
 	issues, err := b.List(beads.ListOptions{
-		Status:   "in_progress",
-		Priority: -1,
+		Status: "in_progress",
 	})
 	if err != nil || len(issues) == 0 {
 		return ""
@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Top command flags
+var (
+	topInterval int
+	topOnce     bool
+	topJSON     bool
+	topSort     string
+)
+
+var topCmd = &cobra.Command{
+	Use:     "top",
+	GroupID: GroupDiag,
+	Short:   "Live resource view of town processes",
+	Long: `Show CPU, memory, and open files for the Dolt server, the daemon, and
+every agent session, refreshed until Ctrl+C.
+
+Each row covers a whole process tree: an agent's usage includes its runtime
+and everything it spawned (builds, tests, git). CPU is measured between
+refreshes, so the first frame shows the lifetime average reported by ps.
+Open file counts are only available on Linux.
+
+ACTIVITY is the agent's most recent entry in the town event log (sling,
+hook, done, merge, ...) and how long its tmux session has been idle.
+
+Examples:
+  gt top                  # Live view, refreshed every 2s
+  gt top -n 5 --sort mem  # Every 5s, biggest memory users first
+  gt top --once           # Single snapshot
+  gt top --json           # Single snapshot as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().IntVarP(&topInterval, "interval", "n", 2, "Refresh interval in seconds")
+	topCmd.Flags().BoolVar(&topOnce, "once", false, "Print one snapshot and exit")
+	topCmd.Flags().BoolVar(&topJSON, "json", false, "Output one snapshot as JSON")
+	topCmd.Flags().StringVar(&topSort, "sort", "cpu", "Sort by: cpu, mem, fds, name")
+
+	rootCmd.AddCommand(topCmd)
+}
+
+// TopEntry is one row of gt top: a town process and its descendants.
+type TopEntry struct {
+	Name     string  `json:"name"`
+	Kind     string  `json:"kind"` // dolt, daemon, agent
+	Session  string  `json:"session,omitempty"`
+	PID      int     `json:"pid"`
+	Procs    int     `json:"procs"`
+	CPU      float64 `json:"cpu_percent"`
+	RSS      int64   `json:"rss_bytes"`
+	FDs      int     `json:"fds"` // -1 when unavailable
+	Activity string  `json:"activity,omitempty"`
+	Idle     string  `json:"idle,omitempty"`
+}
+
+// procSample is one process from a ps snapshot.
+type procSample struct {
+	PPID    int
+	CPUTime time.Duration // Cumulative
+	PCPU    float64       // As reported by ps
+	RSS     int64         // Bytes
+}
+
+// procTable is a ps snapshot with the parent→children index.
+type procTable struct {
+	At       time.Time
+	Procs    map[int]procSample
+	Children map[int][]int
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	switch topSort {
+	case "cpu", "mem", "fds", "name":
+	default:
+		return fmt.Errorf("invalid --sort %q (want cpu, mem, fds, or name)", topSort)
+	}
+	if topInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", topInterval)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if topJSON || topOnce {
+		entries, _, err := gatherTop(townRoot, nil, newTopEvents(townRoot))
+		if err != nil {
+			return err
+		}
+		sortTopEntries(entries, topSort)
+		if topJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+		printTop(os.Stdout, entries)
+		return nil
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(time.Duration(topInterval) * time.Second)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	var prev *procTable
+	activity := newTopEvents(townRoot)
+	for {
+		var buf bytes.Buffer
+		if isTTY {
+			buf.WriteString("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+		header := fmt.Sprintf("[%s] gt top (every %ds, sorted by %s, Ctrl+C to stop)",
+			time.Now().Format("15:04:05"), topInterval, topSort)
+		fmt.Fprintf(&buf, "%s\n\n", style.Dim.Render(header))
+
+		entries, table, err := gatherTop(townRoot, prev, activity)
+		if err != nil {
+			fmt.Fprintf(&buf, "Error: %v\n", err)
+		} else {
+			prev = table
+			sortTopEntries(entries, topSort)
+			printTop(&buf, entries)
+		}
+		// Write the frame at once so the terminal never shows a blank screen.
+		_, _ = os.Stdout.Write(buf.Bytes())
+
+		select {
+		case <-sigChan:
+			if isTTY {
+				fmt.Println("\nStopped.")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// gatherTop snapshots town processes. When prev is non-nil, CPU is computed
+// from the CPU time used since prev; otherwise ps's own figure is used.
+func gatherTop(townRoot string, prev *procTable, activity *topEvents) ([]TopEntry, *procTable, error) {
+	table, err := readProcTable()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []TopEntry
+	if running, pid, _ := doltserver.IsRunning(townRoot); running {
+		entries = append(entries, TopEntry{Name: "dolt server", Kind: "dolt", PID: pid})
+	}
+	if running, pid, _ := daemon.IsRunning(townRoot); running {
+		entries = append(entries, TopEntry{Name: "daemon", Kind: "daemon", PID: pid})
+	}
+
+	latest := activity.refresh()
+	t := tmux.NewTmux()
+	sessions, _ := t.ListSessions()
+	for _, sess := range sessions {
+		if !session.IsKnownSession(sess) {
+			continue
+		}
+		pidStr, err := t.GetPanePID(sess)
+		if err != nil {
+			continue
+		}
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			continue
+		}
+		e := TopEntry{Name: sess, Kind: "agent", Session: sess, PID: pid}
+		if id, err := session.ParseSessionName(sess); err == nil && id.Address() != "" {
+			e.Name = id.Address()
+			if ev, ok := latest[e.Name]; ok {
+				e.Activity = topActivity(ev)
+			}
+		}
+		if last, err := t.GetSessionActivity(sess); err == nil {
+			e.Idle = formatWorkerAge(time.Since(last))
+		}
+		entries = append(entries, e)
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		tree := table.subtree(e.PID)
+		e.Procs = len(tree)
+		e.FDs = 0
+		for _, pid := range tree {
+			p := table.Procs[pid]
+			e.RSS += p.RSS
+			e.CPU += table.cpuPercent(pid, prev)
+			if e.FDs >= 0 {
+				if n := countFDs(pid); n >= 0 {
+					e.FDs += n
+				} else {
+					e.FDs = -1
+				}
+			}
+		}
+	}
+	return entries, table, nil
+}
+
+// readProcTable snapshots every process with ps, which works on Linux and macOS.
+func readProcTable() (*procTable, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,time=,pcpu=,rss=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+	return parseProcTable(string(out), time.Now()), nil
+}
+
+func parseProcTable(output string, at time.Time) *procTable {
+	table := &procTable{At: at, Procs: make(map[int]procSample), Children: make(map[int][]int)}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		pcpu, _ := strconv.ParseFloat(fields[3], 64)
+		rssKB, _ := strconv.ParseInt(fields[4], 10, 64)
+		table.Procs[pid] = procSample{PPID: ppid, CPUTime: parseCPUTime(fields[2]), PCPU: pcpu, RSS: rssKB * 1024}
+		table.Children[ppid] = append(table.Children[ppid], pid)
+	}
+	return table
+}
+
+// parseCPUTime parses ps cumulative CPU time: "[[dd-]hh:]mm:ss" on Linux,
+// "mm:ss.cc" or "h:mm:ss.cc" on macOS.
+func parseCPUTime(s string) time.Duration {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		days, _ = strconv.Atoi(d)
+		s = rest
+	}
+	var total float64
+	for _, part := range strings.Split(s, ":") {
+		v, _ := strconv.ParseFloat(part, 64)
+		total = total*60 + v
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(total*float64(time.Second))
+}
+
+// subtree returns root and all its descendants that are in the table.
+func (t *procTable) subtree(root int) []int {
+	if _, ok := t.Procs[root]; !ok {
+		return nil
+	}
+	tree := []int{root}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, t.Children[tree[i]]...)
+	}
+	return tree
+}
+
+// cpuPercent returns pid's CPU use since prev, or ps's figure when pid is
+// new or there is no previous snapshot.
+func (t *procTable) cpuPercent(pid int, prev *procTable) float64 {
+	cur := t.Procs[pid]
+	if prev == nil {
+		return cur.PCPU
+	}
+	old, ok := prev.Procs[pid]
+	wall := t.At.Sub(prev.At)
+	if !ok || wall <= 0 || cur.CPUTime < old.CPUTime {
+		return cur.PCPU
+	}
+	return float64(cur.CPUTime-old.CPUTime) / float64(wall) * 100
+}
+
+// countFDs returns the number of open files for pid, or -1 where /proc is
+// unavailable.
+func countFDs(pid int) int {
+	if runtime.GOOS != "linux" {
+		return -1
+	}
+	entries, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "fd"))
+	if err != nil {
+		// Usually a process that exited since the ps snapshot.
+		return 0
+	}
+	return len(entries)
+}
+
+// topEventsTailBytes is how far back from the end of the event log gt top
+// starts reading for recent activity.
+const topEventsTailBytes = 256 << 10
+
+// topEvents tracks each actor's most recent event across refreshes, reading
+// only what was appended to the town event log since the last one.
+type topEvents struct {
+	path   string
+	offset int64
+	latest map[string]events.Event
+}
+
+// newTopEvents starts near the end of the event log rather than scanning
+// all of it.
+func newTopEvents(townRoot string) *topEvents {
+	t := &topEvents{
+		path:   filepath.Join(townRoot, events.EventsFile),
+		latest: make(map[string]events.Event),
+	}
+	if info, err := os.Stat(t.path); err == nil && info.Size() > topEventsTailBytes {
+		t.offset = info.Size() - topEventsTailBytes
+	}
+	return t
+}
+
+// refresh reads new events and returns each actor's latest.
+func (t *topEvents) refresh() map[string]events.Event {
+	evs, offset, err := events.ReadFrom(t.path, t.offset, events.Filter{})
+	if err != nil {
+		return t.latest
+	}
+	if offset < t.offset {
+		// The log was truncated or replaced; drop activity from the old one.
+		t.latest = make(map[string]events.Event)
+	}
+	t.offset = offset
+	for _, e := range evs {
+		t.latest[e.Actor] = e
+	}
+	return t.latest
+}
+
+// topActivity summarizes an event as "<type> <subject> (<age>)".
+func topActivity(e events.Event) string {
+	parts := []string{e.Type}
+	for _, key := range []string{"bead", "mr", "subject", "target"} {
+		if v, ok := e.Payload[key].(string); ok && v != "" {
+			parts = append(parts, v)
+			break
+		}
+	}
+	if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		parts = append(parts, "("+formatWorkerAge(time.Since(ts))+" ago)")
+	}
+	return strings.Join(parts, " ")
+}
+
+func sortTopEntries(entries []TopEntry, by string) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch by {
+		case "mem":
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case "fds":
+			if a.FDs != b.FDs {
+				return a.FDs > b.FDs
+			}
+		case "cpu":
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+func printTop(w io.Writer, entries []TopEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No town processes running.")
+		return
+	}
+	var cpu float64
+	var rss int64
+	width := len("NAME")
+	for _, e := range entries {
+		cpu += e.CPU
+		rss += e.RSS
+		width = max(width, len(e.Name))
+	}
+	fmt.Fprintf(w, "%s %.1f%% CPU, %s memory across %d process trees\n\n",
+		style.Bold.Render("Town:"), cpu, formatBytes(rss), len(entries))
+
+	fmt.Fprintf(w, "%-*s  %7s  %6s  %10s  %5s  %5s  %s\n", width, "NAME", "PID", "CPU%", "MEM", "FDS", "IDLE", "ACTIVITY")
+	for _, e := range entries {
+		fds := "-"
+		if e.FDs >= 0 {
+			fds = strconv.Itoa(e.FDs)
+		}
+		idle := e.Idle
+		if idle == "" {
+			idle = "-"
+		}
+		cpuText := fmt.Sprintf("%6.1f", e.CPU)
+		if e.CPU >= 100 {
+			cpuText = style.Warning.Render(cpuText)
+		}
+		fmt.Fprintf(w, "%-*s  %7d  %s  %10s  %5s  %5s  %s\n", width, e.Name, e.PID, cpuText,
+			formatBytes(e.RSS), fds, idle, style.Dim.Render(e.Activity))
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestParseCPUTime(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:01:05":   65 * time.Second,
+		"01:00:00":   time.Hour,
+		"2-03:00:00": 51 * time.Hour,
+		"0:01.50":    1500 * time.Millisecond,
+		"1:02:03.00": time.Hour + 2*time.Minute + 3*time.Second,
+		"garbage":    0,
+	}
+	for in, want := range tests {
+		if got := parseCPUTime(in); got != want {
+			t.Errorf("parseCPUTime(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestProcTable_SubtreeAndCPU(t *testing.T) {
+	start := time.Unix(1000, 0)
+	ps := `    1     0 00:10:00  0.1  1000
+  100     1 00:00:10 50.0  2048
+  101   100 00:00:20 10.0  1024
+  102   101 00:00:05  1.0   512
+  200     1 00:00:01  0.0   100
+`
+	prev := parseProcTable(ps, start)
+	tree := prev.subtree(100)
+	sort.Ints(tree)
+	if len(tree) != 3 || tree[0] != 100 || tree[2] != 102 {
+		t.Fatalf("subtree(100) = %v", tree)
+	}
+	if got := prev.subtree(999); got != nil {
+		t.Errorf("subtree of missing pid = %v", got)
+	}
+	if p := prev.Procs[101]; p.PPID != 100 || p.RSS != 1024*1024 {
+		t.Errorf("proc 101 = %+v", p)
+	}
+
+	// First frame: ps's own figure.
+	if got := prev.cpuPercent(100, nil); got != 50 {
+		t.Errorf("cpuPercent without prev = %v", got)
+	}
+
+	// 101 used 1s of CPU over 2s of wall time.
+	cur := parseProcTable(`  100     1 00:00:10 50.0  2048
+  101   100 00:00:21 10.0  1024
+`, start.Add(2*time.Second))
+	if got := cur.cpuPercent(101, prev); got != 50 {
+		t.Errorf("cpuPercent(101) = %v, want 50", got)
+	}
+	if got := cur.cpuPercent(100, prev); got != 0 {
+		t.Errorf("cpuPercent(100) = %v, want 0", got)
+	}
+}
+
+func TestSortTopEntries(t *testing.T) {
+	entries := []TopEntry{
+		{Name: "b", CPU: 5, RSS: 10},
+		{Name: "a", CPU: 5, RSS: 30},
+		{Name: "c", CPU: 90, RSS: 20},
+	}
+	sortTopEntries(entries, "cpu")
+	if entries[0].Name != "c" || entries[1].Name != "a" {
+		t.Errorf("by cpu = %v", entries)
+	}
+	sortTopEntries(entries, "mem")
+	if entries[0].Name != "a" || entries[2].Name != "b" {
+		t.Errorf("by mem = %v", entries)
+	}
+}
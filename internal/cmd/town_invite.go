@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/town"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// bundlePassphraseEnv names the env var holding the bundle passphrase.
+// Read from the environment so it never appears in shell history or ps.
+const bundlePassphraseEnv = "GT_BUNDLE_PASSPHRASE"
+
+// Town invite/join command flags
+var (
+	townInviteOutput             string
+	townInviteExpires            time.Duration
+	townInviteDoltHost           string
+	townInviteIncludeCredentials bool
+	townInviteEncrypt            bool
+	townJoinDir                  string
+	townRevokeExpired            bool
+)
+
+var townInviteCmd = &cobra.Command{
+	Use:   "invite",
+	Short: "Generate a join bundle for a new machine",
+	Long: `Generate a portable bundle a new machine uses to join this town.
+
+The bundle contains the town identity (mayor/town.json), the rig registry
+(mayor/rigs.json, without machine-local paths), the Dolt server endpoint,
+and the Dolt remote URL of each database.
+
+Credentials are opt-in: --include-credentials creates a dedicated Dolt SQL
+user for the new machine, limited to the town's databases, and puts its
+password in the bundle. Your own server password is never shared, and each
+invite user can be revoked on its own with 'gt town revoke'. Bundles carrying
+credentials must be encrypted with the passphrase in $GT_BUNDLE_PASSPHRASE.
+
+DoltHub tokens are personal and are never bundled: the new machine runs
+'dolt login' with its own account.
+
+If the Dolt server listens only on localhost, pass --dolt-host with an
+address other machines can reach, or the endpoint is left out.
+
+Examples:
+  gt town invite --dolt-host dolt.lan
+  gt town invite -o laptop.json --expires 24h
+  GT_BUNDLE_PASSPHRASE=... gt town invite --encrypt --include-credentials --dolt-host dolt.lan`,
+	RunE: runTownInvite,
+}
+
+var townInvitesCmd = &cobra.Command{
+	Use:   "invites",
+	Short: "List Dolt credentials issued by 'gt town invite'",
+	Args:  cobra.NoArgs,
+	RunE:  runTownInvites,
+}
+
+var townRevokeCmd = &cobra.Command{
+	Use:   "revoke [user...]",
+	Short: "Revoke Dolt credentials issued by 'gt town invite'",
+	Long: `Drop invite users from the town's Dolt server. Machines that joined with
+the revoked credential lose access to the town's databases.
+
+Examples:
+  gt town revoke gt_invite_3f9a01c2
+  gt town revoke --expired`,
+	RunE: runTownRevoke,
+}
+
+var townJoinCmd = &cobra.Command{
+	Use:   "join <bundle>",
+	Short: "Join a town using a bundle from 'gt town invite'",
+	Long: `Make this machine a member of a town using a join bundle.
+
+Writes mayor/town.json under --dir (default: current directory) and prints
+the 'gt dolt connect' and 'gt rig add' commands to finish setup. The Dolt
+password from the bundle is stored encrypted as the town's dolt.password
+secret (see 'gt secret') and never printed. An existing town directory must
+belong to the same town.
+
+Encrypted bundles read their passphrase from $GT_BUNDLE_PASSPHRASE.
+
+Examples:
+  gt town join laptop.json --dir ~/gt
+  GT_BUNDLE_PASSPHRASE=... gt town join invite.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownJoin,
+}
+
+func init() {
+	townInviteCmd.Flags().StringVarP(&townInviteOutput, "output", "o", "town-invite.json", "Bundle output path")
+	townInviteCmd.Flags().DurationVar(&townInviteExpires, "expires", 72*time.Hour, "Bundle lifetime (0 = never expires)")
+	townInviteCmd.Flags().StringVar(&townInviteDoltHost, "dolt-host", "", "Dolt server address advertised to the new machine")
+	townInviteCmd.Flags().BoolVar(&townInviteIncludeCredentials, "include-credentials", false, "Create a revocable Dolt user for the new machine (requires --encrypt)")
+	townInviteCmd.Flags().BoolVar(&townInviteEncrypt, "encrypt", false, "Encrypt the bundle with $"+bundlePassphraseEnv)
+
+	townJoinCmd.Flags().StringVar(&townJoinDir, "dir", "", "Town directory to join into (default: current directory)")
+
+	townRevokeCmd.Flags().BoolVar(&townRevokeExpired, "expired", false, "Revoke every invite whose bundle has expired")
+
+	townCmd.AddCommand(townInviteCmd)
+	townCmd.AddCommand(townJoinCmd)
+	townCmd.AddCommand(townInvitesCmd)
+	townCmd.AddCommand(townRevokeCmd)
+}
+
+func runTownInvite(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	passphrase := ""
+	if townInviteEncrypt {
+		passphrase = os.Getenv(bundlePassphraseEnv)
+		if passphrase == "" {
+			return fmt.Errorf("--encrypt requires $%s to be set", bundlePassphraseEnv)
+		}
+	}
+
+	bundle, err := town.NewBundle(townRoot, townInviteExpires)
+	if err != nil {
+		return err
+	}
+
+	// Dolt endpoint: a localhost address is useless to another machine.
+	doltCfg := doltserver.DefaultConfig(townRoot)
+	host := townInviteDoltHost
+	if host == "" && doltCfg.IsRemote() {
+		host = doltCfg.Host
+	}
+	if host != "" {
+		bundle.Dolt = &town.DoltEndpoint{Host: host, Port: doltCfg.Port, User: doltCfg.User}
+	} else {
+		style.PrintWarning("Dolt server is local-only; pass --dolt-host to advertise it to the new machine")
+	}
+
+	var invite *town.Invite
+	if townInviteIncludeCredentials {
+		if bundle.Dolt == nil {
+			return fmt.Errorf("--include-credentials requires an advertised Dolt endpoint (--dolt-host)")
+		}
+		if passphrase == "" {
+			return fmt.Errorf("%w (use --encrypt)", town.ErrSecretsUnencrypted)
+		}
+		dbs, err := doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases to grant: %w", err)
+		}
+		user, password, err := doltserver.CreateScopedUser(townRoot, dbs)
+		if err != nil {
+			return err
+		}
+		bundle.Dolt.User = user
+		bundle.Dolt.Password = password
+		invite = &town.Invite{User: user, Databases: dbs, CreatedAt: bundle.CreatedAt, ExpiresAt: bundle.ExpiresAt}
+	}
+
+	// Dolt remotes are only discoverable from local database directories.
+	if !doltCfg.IsRemote() {
+		if dbs, err := doltserver.ListDatabases(townRoot); err == nil {
+			for _, db := range dbs {
+				if url, err := doltserver.HasRemote(filepath.Join(doltCfg.DataDir, db)); err == nil && url != "" {
+					if bundle.Remotes == nil {
+						bundle.Remotes = map[string]string{}
+					}
+					bundle.Remotes[db] = url
+				}
+			}
+		}
+	}
+
+	if err := town.WriteFile(townInviteOutput, bundle, passphrase); err != nil {
+		if invite != nil {
+			_ = doltserver.DropScopedUser(townRoot, invite.User)
+		}
+		if err == town.ErrSecretsUnencrypted {
+			return fmt.Errorf("%w (use --encrypt)", err)
+		}
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	if invite != nil {
+		invites, err := town.LoadInvites(townRoot)
+		if err == nil {
+			err = town.SaveInvites(townRoot, append(invites, *invite))
+		}
+		if err != nil {
+			style.PrintWarning("could not record invite %s (revoke it by name later): %v", invite.User, err)
+		}
+	}
+
+	fmt.Printf("%s Wrote join bundle for town %s to %s\n", style.SuccessPrefix, style.Bold.Render(bundle.Town.Name), townInviteOutput)
+	fmt.Printf("  Rigs: %d\n", len(bundle.Rigs))
+	if bundle.Dolt != nil {
+		fmt.Printf("  Dolt: %s:%d\n", bundle.Dolt.Host, bundle.Dolt.Port)
+	}
+	if invite != nil {
+		fmt.Printf("  Dolt user: %s (revoke with 'gt town revoke %s')\n", invite.User, invite.User)
+	}
+	if len(bundle.Remotes) > 0 {
+		fmt.Printf("  Dolt remotes: %d\n", len(bundle.Remotes))
+	}
+	if !bundle.ExpiresAt.IsZero() {
+		fmt.Printf("  Expires: %s\n", bundle.ExpiresAt.Local().Format(time.RFC1123))
+	}
+	if passphrase != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Encrypted: share the passphrase separately from the bundle"))
+	}
+	fmt.Printf("\nOn the new machine: %s\n", style.Bold.Render("gt town join "+filepath.Base(townInviteOutput)))
+	return nil
+}
+
+func runTownJoin(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0]) //nolint:gosec // G304: path is user-provided argument
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+
+	bundle, err := town.Decode(data, os.Getenv(bundlePassphraseEnv), time.Now())
+	if err != nil {
+		if err == town.ErrPassphraseRequired {
+			return fmt.Errorf("%w: set $%s", err, bundlePassphraseEnv)
+		}
+		return err
+	}
+
+	dir := townJoinDir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	missing, err := town.Apply(bundle, dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Joined town %s at %s\n", style.SuccessPrefix, style.Bold.Render(bundle.Town.Name), dir)
+
+	if bundle.Dolt != nil {
+		line := fmt.Sprintf("gt dolt connect %s:%d", bundle.Dolt.Host, bundle.Dolt.Port)
+		if bundle.Dolt.User != "" {
+			line += " --user " + bundle.Dolt.User
+		}
+		fmt.Printf("\nConnect to the town's Dolt server (from %s):\n  %s\n", dir, line)
+	}
+
+	// The password stays out of metadata.json and off the terminal: it goes
+	// to the encrypted secrets store, where the Dolt config finds it.
+	if bundle.Dolt != nil && bundle.Dolt.Password != "" {
+		if err := secrets.Set(dir, secrets.DoltPassword, bundle.Dolt.Password); err != nil {
+			return fmt.Errorf("storing Dolt password: %w", err)
+		}
+		fmt.Printf("\nStored the Dolt password as secret %s\n", secrets.DoltPassword)
+	}
+
+	if len(bundle.Remotes) > 0 {
+		names := make([]string, 0, len(bundle.Remotes))
+		for db := range bundle.Remotes {
+			names = append(names, db)
+		}
+		sort.Strings(names)
+		fmt.Printf("\nDolt remotes:\n")
+		needsLogin := false
+		for _, db := range names {
+			fmt.Printf("  %-16s %s\n", db, bundle.Remotes[db])
+			needsLogin = needsLogin || strings.Contains(bundle.Remotes[db], "dolthub.com")
+		}
+		if needsLogin {
+			fmt.Printf("  %s\n", style.Dim.Render("DoltHub remotes need your own account: run 'dolt login'"))
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("\nAdd the town's rigs (from %s):\n", dir)
+		for _, name := range missing {
+			entry := bundle.Rigs[name]
+			line := fmt.Sprintf("gt rig add %s %s", name, entry.GitURL)
+			if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+				line += " --prefix " + entry.BeadsConfig.Prefix
+			}
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+func runTownInvites(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	invites, err := town.LoadInvites(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(invites) == 0 {
+		fmt.Println("No invite credentials issued.")
+		return nil
+	}
+
+	// Mark users that no longer exist on the server, when it can be asked.
+	onServer := map[string]bool{}
+	users, listErr := doltserver.ListScopedUsers(townRoot)
+	for _, u := range users {
+		onServer[u] = true
+	}
+
+	now := time.Now()
+	for _, inv := range invites {
+		state := ""
+		switch {
+		case listErr == nil && !onServer[inv.User]:
+			state = style.Dim.Render("(revoked)")
+		case inv.Expired(now):
+			state = style.Warning.Render("(expired, still active)")
+		}
+		fmt.Printf("  %-20s issued %s  %s\n", inv.User, inv.CreatedAt.Local().Format("2006-01-02 15:04"), state)
+	}
+	if listErr != nil {
+		fmt.Printf("\n%s\n", style.Dim.Render("Dolt server unreachable: could not check which users are still active"))
+	}
+	return nil
+}
+
+func runTownRevoke(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !townRevokeExpired {
+		return fmt.Errorf("name the invite users to revoke, or pass --expired")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	invites, err := town.LoadInvites(townRoot)
+	if err != nil {
+		return err
+	}
+
+	targets := map[string]bool{}
+	for _, user := range args {
+		targets[user] = true
+	}
+	if townRevokeExpired {
+		now := time.Now()
+		for _, inv := range invites {
+			if inv.Expired(now) {
+				targets[inv.User] = true
+			}
+		}
+	}
+
+	var errs []string
+	for user := range targets {
+		if err := doltserver.DropScopedUser(townRoot, user); err != nil {
+			errs = append(errs, err.Error())
+			delete(targets, user)
+			continue
+		}
+		fmt.Printf("%s Revoked %s\n", style.SuccessPrefix, user)
+	}
+
+	kept := invites[:0]
+	for _, inv := range invites {
+		if !targets[inv.User] {
+			kept = append(kept, inv)
+		}
+	}
+	if err := town.SaveInvites(townRoot, kept); err != nil {
+		errs = append(errs, fmt.Sprintf("updating invite registry: %v", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	if len(targets) == 0 {
+		fmt.Println("No expired invites.")
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Town metrics command flags
+var (
+	townMetricsSince     string
+	townMetricsRig       string
+	townMetricsJSON      bool
+	townMetricsRetention string
+)
+
+// townMetricsWidth is the sparkline width of 'gt town metrics' and the
+// trends in 'gt status'.
+const townMetricsWidth = 48
+
+var townMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show trends of queue depth, beads, Dolt size and agents",
+	Args:  cobra.NoArgs,
+	Long: `Show how the town has been doing over time, from the metrics history the
+daemon samples every few minutes ('gt town metrics sample').
+
+Each series is drawn as a sparkline with its latest, lowest and highest
+value in the window:
+
+  MQ depth     Merge requests ready, blocked or in flight
+  Open beads   Beads not closed (the town's and the rigs')
+  Dolt size    Dolt databases on disk (local server only)
+  Agents       Sessions with a live agent
+
+The history is kept in daemon/metrics.jsonl for --retention (30 days by
+default); no Prometheus server is needed. For scraping, see 'gt serve
+metrics'.
+
+Examples:
+  gt town metrics
+  gt town metrics --since 7d
+  gt town metrics --rig gastown --json`,
+	RunE: runTownMetrics,
+}
+
+var townMetricsSampleCmd = &cobra.Command{
+	Use:   "sample",
+	Short: "Record one sample of the town metrics (run by the daemon)",
+	Args:  cobra.NoArgs,
+	Long: `Record the current queue depth, open beads, Dolt size and live agents to
+the metrics history, then drop samples older than --retention.
+
+The daemon runs this every few minutes; run it by hand to record a sample
+right away.`,
+	RunE: runTownMetricsSample,
+}
+
+func init() {
+	townMetricsCmd.Flags().StringVar(&townMetricsSince, "since", "24h", "Window to show (e.g., 6h, 7d)")
+	townMetricsCmd.Flags().StringVar(&townMetricsRig, "rig", "", "Show one rig's series instead of the town's")
+	townMetricsCmd.Flags().BoolVar(&townMetricsJSON, "json", false, "Output the samples as JSON")
+	townMetricsSampleCmd.Flags().StringVar(&townMetricsRetention, "retention", "30d", "Drop samples older than this")
+
+	townMetricsCmd.AddCommand(townMetricsSampleCmd)
+	townCmd.AddCommand(townMetricsCmd)
+}
+
+func runTownMetrics(cmd *cobra.Command, args []string) error {
+	window, err := parseDuration(townMetricsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q", townMetricsSince)
+	}
+	_, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	points, err := metrics.LoadPoints(metrics.SeriesPath(townRoot), time.Now().Add(-window))
+	if err != nil {
+		return err
+	}
+
+	if townMetricsJSON {
+		if points == nil {
+			points = []metrics.Point{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(points)
+	}
+
+	trends := metrics.Trends(points, townMetricsRig, townMetricsWidth)
+	if len(trends) == 0 {
+		fmt.Println(style.Dim.Render("No metrics recorded in the last " + townMetricsSince + "; the daemon samples them ('gt town metrics sample')."))
+		return nil
+	}
+	scope := "Town"
+	if townMetricsRig != "" {
+		scope = townMetricsRig
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render(scope+" metrics"), style.Dim.Render(fmt.Sprintf("(last %s, %d samples)", townMetricsSince, len(points))))
+	printMetricTrends(trends, "  ")
+	return nil
+}
+
+// printMetricTrends prints one line per trend: label, sparkline, and the
+// latest, lowest and highest values.
+func printMetricTrends(trends []metrics.Trend, indent string) {
+	for _, t := range trends {
+		fmt.Printf("%s%-11s %s  %s %s\n", indent, t.Label, t.Sparkline, style.Bold.Render(t.Format(t.Latest)),
+			style.Dim.Render(fmt.Sprintf("(min %s, max %s)", t.Format(t.Min), t.Format(t.Max))))
+	}
+}
+
+func runTownMetricsSample(cmd *cobra.Command, args []string) error {
+	retention, err := parseDuration(townMetricsRetention)
+	if err != nil || retention <= 0 {
+		return fmt.Errorf("invalid --retention %q", townMetricsRetention)
+	}
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	point := metrics.Point{Time: now.UTC(), Values: collectTownSample(townRoot, rigs)}
+	path := metrics.SeriesPath(townRoot)
+	if err := metrics.AppendPoint(path, point); err != nil {
+		return err
+	}
+	pruned, err := metrics.PrunePoints(path, retention, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Recorded %d values", style.SuccessPrefix, len(point.Values))
+	if pruned > 0 {
+		fmt.Printf(", dropped %d old sample(s)", pruned)
+	}
+	fmt.Println()
+	return nil
+}
+
+// collectTownSample gathers the current value of every metrics series, for
+// the town and per rig. A source that fails is warned about and left out,
+// so a gap in one series doesn't cost the others their sample.
+func collectTownSample(townRoot string, rigs []*rig.Rig) map[string]float64 {
+	values := make(map[string]float64)
+	add := func(series, rigName string, v float64) {
+		values[metrics.SeriesKey(series, rigName)] += v
+		values[series] += v
+	}
+
+	for _, r := range rigs {
+		if summary := getMQSummary(r); summary != nil {
+			add(metrics.SeriesMQDepth, r.Name, float64(summary.Pending+summary.Blocked+summary.InFlight))
+		}
+	}
+	if _, ok := values[metrics.SeriesMQDepth]; !ok && len(rigs) > 0 {
+		values[metrics.SeriesMQDepth] = 0
+	}
+
+	beadDirs := map[string]string{"hq": townRoot}
+	for _, r := range rigs {
+		beadDirs[r.Name] = r.BeadsPath()
+	}
+	for name, dir := range beadDirs {
+		issues, err := beads.New(dir).List(beads.ListOptions{})
+		if err != nil {
+			style.PrintWarning("listing %s beads: %v", name, err)
+			continue
+		}
+		add(metrics.SeriesBeadsOpen, name, float64(len(issues)))
+	}
+
+	sizes, err := doltserver.DatabaseSizes(townRoot)
+	if err != nil {
+		style.PrintWarning("measuring Dolt databases: %v", err)
+	}
+	for db, size := range sizes {
+		add(metrics.SeriesDoltBytes, db, float64(size))
+	}
+
+	t := tmux.NewTmux()
+	if sessions, err := t.ListSessions(); err == nil {
+		values[metrics.SeriesAgents] = 0
+		for _, s := range sessions {
+			if !session.IsKnownSession(s) || !t.IsAgentAlive(s) {
+				continue
+			}
+			rigName := ""
+			if id, err := session.ParseSessionName(s); err == nil {
+				rigName = id.Rig
+			}
+			if rigName == "" {
+				values[metrics.SeriesAgents]++
+				continue
+			}
+			add(metrics.SeriesAgents, rigName, 1)
+		}
+	}
+	return values
+}
+
+// townMetricTrends returns the town's trends over window for gt status, or
+// nil when fewer than two samples were recorded in it.
+func townMetricTrends(townRoot string, window time.Duration) []metrics.Trend {
+	points, err := metrics.LoadPoints(metrics.SeriesPath(townRoot), time.Now().Add(-window))
+	if err != nil || len(points) < 2 {
+		return nil
+	}
+	return metrics.Trends(points, "", townMetricsWidth)
+}
@@ -142,7 +142,6 @@ func runUnslingWith(cmd *cobra.Command, args []string, dryRun, force bool) error
 		hookedBeads, listErr := b.List(beads.ListOptions{
 			Status:   beads.StatusHooked,
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if listErr == nil && len(hookedBeads) > 0 {
 			hookedBeadID = hookedBeads[0].ID
@@ -252,7 +251,6 @@ func cleanStaleHookedBeads(cmd *cobra.Command, b *beads.Beads, agentID, targetBe
 	staleBeads, err := b.List(beads.ListOptions{
 		Status:   beads.StatusHooked,
 		Assignee: agentID,
-		Priority: -1,
 	})
 	if err != nil || len(staleBeads) == 0 {
 		return false
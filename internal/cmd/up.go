@@ -184,7 +184,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 			doltDetail = err.Error()
 		} else {
 			doltOK = true
-			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultPort)
+			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultConfig(townRoot).Port)
 		}
 	}()
 
@@ -829,7 +829,6 @@ func startPolecatsWithWork(townRoot, rigName string) ([]string, map[string]error
 		pinnedBeads, err := b.List(beads.ListOptions{
 			Status:   beads.StatusPinned,
 			Assignee: agentID,
-			Priority: -1,
 		})
 		if err != nil || len(pinnedBeads) == 0 {
 			// No pinned beads - skip
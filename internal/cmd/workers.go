@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workers"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Workers command flags
+var (
+	workersRig   string
+	workersStale bool
+	workersJSON  bool
+)
+
+var workersCmd = &cobra.Command{
+	Use:     "workers",
+	GroupID: GroupAgents,
+	Short:   "Track polecat and crew workers and release the work of dead ones",
+	RunE:    requireSubcommand,
+	Long: `Track the town's workers: the polecat and crew agents that claim beads.
+
+Every gt command a worker runs is its heartbeat, recording its rig, session,
+working directory and branch in the workers registry (.runtime/workers).
+A worker is:
+
+  active   Its agent is running and it ran gt recently
+  stale    Its agent is running but hasn't run gt for 30 minutes
+  dead     Its session or agent is gone
+
+When a worker has been dead for 10 minutes, the daemon releases the beads it
+claimed back to the pool and drops it from the registry ('gt workers reap').
+
+Commands:
+  gt workers list    List workers with their bead, branch and heartbeat
+  gt workers reap    Release the claimed beads of dead workers`,
+}
+
+var workersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workers with their bead, branch and heartbeat",
+	Args:  cobra.NoArgs,
+	Long: `List the registered workers: state, current bead, claimed beads, branch,
+session and PID, and when they last ran gt.
+
+Examples:
+  gt workers list
+  gt workers list --rig gastown
+  gt workers list --stale          # Only stale and dead workers
+  gt workers list --json`,
+	RunE: runWorkersList,
+}
+
+var workersReapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Release the claimed beads of dead workers (run by the daemon)",
+	Args:  cobra.NoArgs,
+	Long: `Release the beads claimed by workers that have been dead for 10 minutes
+back to the pool (open, unassigned), with a comment naming the worker, and
+drop those workers from the registry. The PID and bead of live workers are
+refreshed.
+
+The daemon runs this every few minutes; beads whose lease simply expires
+are returned by 'gt bead leases --reap'.`,
+	RunE: runWorkersReap,
+}
+
+func init() {
+	workersListCmd.Flags().StringVar(&workersRig, "rig", "", "Only list this rig's workers")
+	workersListCmd.Flags().BoolVar(&workersStale, "stale", false, "Only list stale and dead workers")
+	workersListCmd.Flags().BoolVar(&workersJSON, "json", false, "Output as JSON")
+	workersReapCmd.Flags().StringVar(&workersRig, "rig", "", "Only reap this rig's workers")
+	workersReapCmd.Flags().BoolVar(&workersJSON, "json", false, "Output as JSON")
+
+	workersCmd.AddCommand(workersListCmd)
+	workersCmd.AddCommand(workersReapCmd)
+	rootCmd.AddCommand(workersCmd)
+}
+
+// workerRow is a registered worker with its live state.
+type workerRow struct {
+	*workers.Record
+	Address string        `json:"address"`
+	State   workers.State `json:"state"`
+	Alive   bool          `json:"alive"`
+	Claimed []string      `json:"claimed,omitempty"` // Beads leased to the worker
+
+	beadsErr error // Listing the rig's beads failed; Claimed is unknown
+}
+
+func runWorkersList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	rows, err := inspectWorkers(townRoot, workersRig, now)
+	if err != nil {
+		return err
+	}
+	if workersStale {
+		rows = slices.DeleteFunc(rows, func(r workerRow) bool { return r.State == workers.StateActive })
+	}
+
+	if workersJSON {
+		if rows == nil {
+			rows = []workerRow{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Println(style.Dim.Render("No workers registered"))
+		return nil
+	}
+	for _, r := range rows {
+		state := string(r.State)
+		switch r.State {
+		case workers.StateStale:
+			state = style.Warning.Render(state)
+		case workers.StateDead:
+			state = style.Error.Render(state)
+		}
+		bead := r.Bead
+		if bead == "" {
+			bead = "-"
+		}
+		fmt.Printf("  %-32s %-6s  %-12s %s\n", style.Bold.Render(r.Address), state, bead,
+			style.Dim.Render(fmt.Sprintf("heartbeat %s ago", formatDurationAgo(now.Sub(r.LastHeartbeat)))))
+		var details []string
+		if r.Branch != "" {
+			details = append(details, "branch "+r.Branch)
+		}
+		details = append(details, "session "+r.Session)
+		if r.PID != 0 {
+			details = append(details, "pid "+strconv.Itoa(r.PID))
+		}
+		if len(r.Claimed) > 0 {
+			details = append(details, "claimed "+strings.Join(r.Claimed, ", "))
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(strings.Join(details, " · ")))
+	}
+	return nil
+}
+
+// workerRelease is a dead worker whose claimed beads were released. One
+// with errors stays in the registry, so the next run tries again.
+type workerRelease struct {
+	Address  string   `json:"address"`
+	Released []string `json:"released"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+func runWorkersReap(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	rows, err := inspectWorkers(townRoot, workersRig, now)
+	if err != nil {
+		return err
+	}
+
+	releases := []workerRelease{}
+	for _, r := range rows {
+		if !r.Releasable(r.Alive, now) {
+			// Refresh what the registry last saw of a live worker, on a
+			// fresh read so a heartbeat written meanwhile isn't lost.
+			if cur := workers.Read(townRoot, r.Rig, r.Role, r.Name); cur != nil && (cur.PID != r.PID || cur.Bead != r.Bead) {
+				cur.PID, cur.Bead = r.PID, r.Bead
+				_ = workers.Save(townRoot, cur)
+			}
+			continue
+		}
+		rel := releaseWorkerBeads(townRoot, r)
+		if len(rel.Errors) == 0 {
+			if err := workers.Remove(townRoot, r.Record); err != nil {
+				rel.Errors = append(rel.Errors, err.Error())
+			}
+		}
+		releases = append(releases, rel)
+	}
+
+	if workersJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(releases)
+	}
+	for _, rel := range releases {
+		switch {
+		case len(rel.Released) > 0:
+			fmt.Printf("%s %s is dead; released %s\n", style.SuccessPrefix, style.Bold.Render(rel.Address), strings.Join(rel.Released, ", "))
+		case len(rel.Errors) == 0:
+			fmt.Printf("%s %s is dead; dropped from the registry\n", style.SuccessPrefix, style.Bold.Render(rel.Address))
+		}
+		for _, e := range rel.Errors {
+			style.PrintWarning("%s: %s", rel.Address, e)
+		}
+	}
+	if len(releases) == 0 {
+		fmt.Println(style.Dim.Render("No dead workers to reap"))
+	}
+	return nil
+}
+
+// releaseWorkerBeads returns the beads leased to a dead worker to the pool,
+// each with a comment saying why.
+func releaseWorkerBeads(townRoot string, r workerRow) workerRelease {
+	rel := workerRelease{Address: r.Address, Released: []string{}}
+	if r.beadsErr != nil {
+		rel.Errors = append(rel.Errors, fmt.Sprintf("claimed beads unknown: %v", r.beadsErr))
+		return rel
+	}
+	bd := beads.New(filepath.Join(townRoot, r.Rig))
+	for _, id := range r.Claimed {
+		if err := bd.Unclaim(id, "", true); err != nil {
+			rel.Errors = append(rel.Errors, fmt.Sprintf("releasing %s: %v", id, err))
+			continue
+		}
+		_ = bd.AddComment(id, fmt.Sprintf("Worker %s died (last heartbeat %s); returned to the pool",
+			r.Address, r.LastHeartbeat.UTC().Format(time.RFC3339)))
+		rel.Released = append(rel.Released, id)
+	}
+	return rel
+}
+
+// inspectWorkers returns the registered workers of rig (all rigs when
+// empty) with their agent's liveness and PID from tmux, and their current
+// and claimed beads from the rig's beads.
+func inspectWorkers(townRoot, rig string, now time.Time) ([]workerRow, error) {
+	records, err := workers.List(townRoot, rig)
+	if err != nil {
+		return nil, fmt.Errorf("listing workers: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	rigIssues := make(map[string][]*beads.Issue)
+	rigErrs := make(map[string]error)
+	rows := make([]workerRow, 0, len(records))
+	for _, rec := range records {
+		row := workerRow{Record: rec, Address: rec.Address(), Alive: t.IsAgentAlive(rec.Session)}
+		row.State = rec.State(row.Alive, now)
+		if row.Alive {
+			if pid, err := t.GetPanePID(rec.Session); err == nil {
+				row.PID, _ = strconv.Atoi(pid)
+			}
+		}
+
+		issues, ok := rigIssues[rec.Rig]
+		if !ok {
+			issues, err = beads.New(filepath.Join(townRoot, rec.Rig)).List(beads.ListOptions{
+				Statuses: []string{beads.StatusHooked, "in_progress"},
+			})
+			if err != nil {
+				style.PrintWarning("listing %s beads: %v", rec.Rig, err)
+				rigErrs[rec.Rig] = err
+			}
+			rigIssues[rec.Rig] = issues
+		}
+		row.beadsErr = rigErrs[rec.Rig]
+		workerBeads(&row, issues)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// workerBeads sets row's current bead, the one hooked to it (else one in
+// progress), and the beads leased to it, from a rig's active issues. The
+// last seen bead is kept when none is found, as the beads may be
+// unreachable.
+func workerBeads(row *workerRow, issues []*beads.Issue) {
+	holders := row.Holders()
+	var hooked, inProgress string
+	for _, issue := range issues {
+		if l := beads.ParseLease(issue); l != nil && slices.Contains(holders, l.Holder) {
+			row.Claimed = append(row.Claimed, issue.ID)
+		}
+		if !slices.Contains(holders, issue.Assignee) {
+			continue
+		}
+		if issue.Status == beads.StatusHooked && hooked == "" {
+			hooked = issue.ID
+		} else if inProgress == "" {
+			inProgress = issue.ID
+		}
+	}
+	if hooked != "" {
+		row.Bead = hooked
+	} else if inProgress != "" {
+		row.Bead = inProgress
+	}
+}
+
+// touchWorkerHeartbeat records a heartbeat in the workers registry when
+// the command runs in a polecat or crew session. Best-effort: a failure
+// never gets in the command's way.
+func touchWorkerHeartbeat(townRoot string, cmd *cobra.Command) {
+	if os.Getenv("GT_ROLE") == "" {
+		return
+	}
+	id, err := session.ParseAddress(detectSender())
+	if err != nil || id.Rig == "" {
+		return
+	}
+	role := workers.RoleCrew
+	switch id.Role {
+	case session.RoleCrew:
+	case session.RolePolecat:
+		role = workers.RolePolecat
+	default:
+		return
+	}
+	workDir, _ := os.Getwd()
+	_ = workers.Heartbeat(townRoot, workers.Record{
+		Rig:         id.Rig,
+		Role:        role,
+		Name:        id.Name,
+		Session:     id.SessionName(),
+		Workdir:     workDir,
+		Branch:      workers.GitBranch(workDir),
+		LastCommand: cmd.CommandPath(),
+	}, time.Now())
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/workers"
+)
+
+func TestWorkerBeads(t *testing.T) {
+	lease := func(issue *beads.Issue, holder string) *beads.Issue {
+		now := time.Now()
+		issue.Description = beads.SetLease(issue, &beads.Lease{Holder: holder, AcquiredAt: now, ExpiresAt: now.Add(time.Hour)})
+		return issue
+	}
+	issues := []*beads.Issue{
+		lease(&beads.Issue{ID: "gt-1", Status: "in_progress", Assignee: "gastown/toast"}, "gastown/toast"),
+		{ID: "gt-2", Status: beads.StatusHooked, Assignee: "gastown/polecats/toast"},
+		lease(&beads.Issue{ID: "gt-3", Status: "in_progress", Assignee: "gastown/polecats/nux"}, "gastown/polecats/nux"),
+	}
+
+	row := workerRow{Record: &workers.Record{Rig: "gastown", Role: workers.RolePolecat, Name: "toast", Bead: "gt-old"}}
+	workerBeads(&row, issues)
+	if row.Bead != "gt-2" {
+		t.Errorf("Bead = %q, want the hooked gt-2", row.Bead)
+	}
+	if !slices.Equal(row.Claimed, []string{"gt-1"}) {
+		t.Errorf("Claimed = %v, want [gt-1]", row.Claimed)
+	}
+
+	idle := workerRow{Record: &workers.Record{Rig: "gastown", Role: workers.RoleCrew, Name: "max", Bead: "gt-old"}}
+	workerBeads(&idle, issues)
+	if idle.Bead != "gt-old" || idle.Claimed != nil {
+		t.Errorf("idle worker = %q, %v; want last seen bead kept and nothing claimed", idle.Bead, idle.Claimed)
+	}
+}
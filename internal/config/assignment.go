@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Validate checks that every rule has an assignee and at least one
+// condition, and that path globs are well-formed.
+func (a *AssignmentConfig) Validate() error {
+	if a == nil {
+		return nil
+	}
+	for i, r := range a.Rules {
+		if strings.TrimSpace(r.Assignee) == "" {
+			return fmt.Errorf("assignment rule %d: assignee is required", i+1)
+		}
+		if r.Type == "" && r.Label == "" && r.Path == "" {
+			return fmt.Errorf("assignment rule %d: needs a type, label, or path condition (use default for a catch-all)", i+1)
+		}
+		if r.Path != "" {
+			if _, err := path.Match(r.Path, ""); err != nil {
+				return fmt.Errorf("assignment rule %d: invalid path glob %q: %w", i+1, r.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether the config assigns nothing.
+func (a *AssignmentConfig) IsEmpty() bool {
+	return a == nil || (a.Default == "" && len(a.Rules) == 0)
+}
+
+// Describe returns the rule's conditions, e.g. "type=bug label=infra".
+func (r AssignmentRule) Describe() string {
+	var parts []string
+	if r.Type != "" {
+		parts = append(parts, "type="+r.Type)
+	}
+	if r.Label != "" {
+		parts = append(parts, "label="+r.Label)
+	}
+	if r.Path != "" {
+		parts = append(parts, "path="+r.Path)
+	}
+	return strings.Join(parts, " ")
+}
+
+// LoadRigAssignment returns a rig's assignment rules, or nil if the rig
+// has none (or its settings can't be read).
+func LoadRigAssignment(rigPath string) *AssignmentConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Assignment.IsEmpty() {
+		return nil
+	}
+	return settings.Assignment
+}
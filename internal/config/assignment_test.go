@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssignmentConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *AssignmentConfig
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"valid", &AssignmentConfig{Default: "x", Rules: []AssignmentRule{{Type: "bug", Assignee: "y"}}}, ""},
+		{"missing assignee", &AssignmentConfig{Rules: []AssignmentRule{{Type: "bug"}}}, "assignee is required"},
+		{"no condition", &AssignmentConfig{Rules: []AssignmentRule{{Assignee: "y"}}}, "needs a type, label, or path"},
+		{"bad glob", &AssignmentConfig{Rules: []AssignmentRule{{Path: "[", Assignee: "y"}}}, "invalid path glob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRigAssignment(t *testing.T) {
+	rigPath := t.TempDir()
+	if LoadRigAssignment(rigPath) != nil {
+		t.Error("expected nil without settings")
+	}
+
+	settings := NewRigSettings()
+	settings.Assignment = &AssignmentConfig{Rules: []AssignmentRule{{Label: "infra", Assignee: "gastown/crew/max"}}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	got := LoadRigAssignment(rigPath)
+	if got == nil || len(got.Rules) != 1 || got.Rules[0].Assignee != "gastown/crew/max" {
+		t.Errorf("LoadRigAssignment = %+v", got)
+	}
+
+	// Invalid rules make the settings file fail to load
+	data := `{"type": "rig-settings", "version": 1, "assignment": {"rules": [{"label": "x"}]}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil {
+		t.Error("expected invalid assignment rules to be rejected")
+	}
+}
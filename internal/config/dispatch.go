@@ -0,0 +1,39 @@
+package config
+
+import "fmt"
+
+// DefaultWIPLimit is how many beads a worker may have in progress when the
+// rig sets no dispatch.wip_limit.
+const DefaultWIPLimit = 1
+
+// Validate checks that the limits are not negative.
+func (d *DispatchConfig) Validate() error {
+	if d == nil {
+		return nil
+	}
+	if d.WIPLimit < 0 {
+		return fmt.Errorf("dispatch: wip_limit must not be negative")
+	}
+	if d.RigWIPLimit < 0 {
+		return fmt.Errorf("dispatch: rig_wip_limit must not be negative")
+	}
+	return nil
+}
+
+// WorkerWIPLimit returns how many beads a worker may have in progress.
+func (d *DispatchConfig) WorkerWIPLimit() int {
+	if d == nil || d.WIPLimit == 0 {
+		return DefaultWIPLimit
+	}
+	return d.WIPLimit
+}
+
+// LoadRigDispatch returns a rig's dispatch settings, or an empty config
+// (the defaults) if the rig has none or its settings can't be read.
+func LoadRigDispatch(rigPath string) *DispatchConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Dispatch == nil {
+		return &DispatchConfig{}
+	}
+	return settings.Dispatch
+}
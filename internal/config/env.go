@@ -31,6 +31,9 @@ type AgentEnvConfig struct {
 	// SessionIDEnv is the environment variable name that holds the session ID.
 	// Sets GT_SESSION_ID_ENV so the runtime knows where to find the session ID.
 	SessionIDEnv string
+
+	// Toolchain is the rig's pinned toolchain (see ToolchainEnv).
+	Toolchain *ToolchainConfig
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
@@ -114,6 +117,11 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_SESSION_ID_ENV"] = cfg.SessionIDEnv
 	}
 
+	// Apply the rig's toolchain pins
+	for k, v := range ToolchainEnv(cfg.Toolchain) {
+		env[k] = v
+	}
+
 	// Clear NODE_OPTIONS to prevent debugger flags (e.g., --inspect from VSCode)
 	// from being inherited through tmux into Claude's Node.js runtime.
 	// This is the PRIMARY guard: setting it here (the single source of truth
@@ -420,6 +420,49 @@ func TestEnvToSlice(t *testing.T) {
 
 // Helper functions
 
+func TestAgentEnv_Toolchain(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:      "refinery",
+		Rig:       "myrig",
+		TownRoot:  "/town",
+		Toolchain: &ToolchainConfig{Go: "1.22.3", Node: "20", Dolt: "1.43"},
+	})
+
+	assertEnv(t, env, "GOTOOLCHAIN", "go1.22.3")
+	assertEnv(t, env, "GT_TOOLCHAIN_GO", "1.22.3")
+	assertEnv(t, env, "GT_TOOLCHAIN_NODE", "20")
+	assertEnv(t, env, "GT_TOOLCHAIN_DOLT", "1.43")
+
+	// A partial Go pin can't name a release, so GOTOOLCHAIN is left alone
+	env = AgentEnv(AgentEnvConfig{Role: "refinery", Rig: "myrig", Toolchain: &ToolchainConfig{Go: "1.22"}})
+	assertEnv(t, env, "GT_TOOLCHAIN_GO", "1.22")
+	assertNotSet(t, env, "GOTOOLCHAIN")
+	assertNotSet(t, env, "GT_TOOLCHAIN_NODE")
+}
+
+func TestToolchainConfig_Set(t *testing.T) {
+	t.Parallel()
+	tc := &ToolchainConfig{}
+	if err := tc.Set("go", "go1.22.3"); err != nil || tc.Go != "1.22.3" {
+		t.Errorf("Set go = %q, %v", tc.Go, err)
+	}
+	if err := tc.Set("node", "v20.11.0"); err != nil || tc.Node != "20.11.0" {
+		t.Errorf("Set node = %q, %v", tc.Node, err)
+	}
+	if err := tc.Set("go", ""); err != nil || tc.Go != "" {
+		t.Errorf("clearing go pin = %q, %v", tc.Go, err)
+	}
+	for _, bad := range [][2]string{{"rust", "1.75"}, {"dolt", "latest"}, {"node", "20.x"}} {
+		if err := tc.Set(bad[0], bad[1]); err == nil {
+			t.Errorf("Set(%q, %q) should fail", bad[0], bad[1])
+		}
+	}
+	if err := validateRigSettings(&RigSettings{Toolchain: &ToolchainConfig{Dolt: "1.x"}}); err == nil {
+		t.Error("validateRigSettings accepted a malformed toolchain pin")
+	}
+}
+
 func assertEnv(t *testing.T, env map[string]string, key, expected string) {
 	t.Helper()
 	if got := env[key]; got != expected {
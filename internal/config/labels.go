@@ -0,0 +1,211 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ReservedLabelPrefix marks labels Gas Town manages itself (gt:agent,
+// gt:merge-request, ...). They are always allowed.
+const ReservedLabelPrefix = "gt:"
+
+var labelColorRE = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// LabelsConfigPath returns the path to the town label registry.
+func LabelsConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "labels.json")
+}
+
+// NewLabelRegistry returns an empty registry, which allows any label.
+func NewLabelRegistry() *LabelRegistry {
+	return &LabelRegistry{Type: "labels", Version: CurrentLabelRegistryVersion}
+}
+
+// LoadLabelRegistry loads and validates a label registry file.
+func LoadLabelRegistry(path string) (*LabelRegistry, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading label registry: %w", err)
+	}
+
+	var reg LabelRegistry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("parsing label registry: %w", err)
+	}
+	if err := reg.Validate(); err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+// LoadOrCreateLabelRegistry loads the label registry, returning an empty
+// one if the town has none.
+func LoadOrCreateLabelRegistry(path string) (*LabelRegistry, error) {
+	reg, err := LoadLabelRegistry(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewLabelRegistry(), nil
+		}
+		return nil, err
+	}
+	return reg, nil
+}
+
+// SaveLabelRegistry validates and writes a label registry.
+func SaveLabelRegistry(path string, reg *LabelRegistry) error {
+	if err := reg.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding label registry: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: label registry doesn't contain secrets
+		return fmt.Errorf("writing label registry: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the registry's type and version, that label names are
+// well-formed and unique, and that colors are hex.
+func (r *LabelRegistry) Validate() error {
+	if r.Type != "labels" && r.Type != "" {
+		return fmt.Errorf("%w: expected type 'labels', got '%s'", ErrInvalidType, r.Type)
+	}
+	if r.Version > CurrentLabelRegistryVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, r.Version, CurrentLabelRegistryVersion)
+	}
+	seen := make(map[string]bool)
+	for _, l := range r.Labels {
+		if err := ValidateLabelName(l.Name); err != nil {
+			return err
+		}
+		if seen[l.Name] {
+			return fmt.Errorf("label %q is registered twice", l.Name)
+		}
+		seen[l.Name] = true
+		if l.Color != "" && !labelColorRE.MatchString(l.Color) {
+			return fmt.Errorf("label %q: color %q is not a hex color like #d73a4a", l.Name, l.Color)
+		}
+	}
+	for _, p := range r.AllowedPrefixes {
+		if err := ValidateLabelName(p); err != nil {
+			return fmt.Errorf("allowed prefix: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateLabelName rejects names bd can't store as a single label.
+func ValidateLabelName(name string) error {
+	if name == "" {
+		return errors.New("label name is empty")
+	}
+	if strings.ContainsAny(name, ", \t\n") {
+		return fmt.Errorf("label %q: names may not contain commas or whitespace", name)
+	}
+	if strings.HasPrefix(name, ReservedLabelPrefix) {
+		return fmt.Errorf("label %q: the %s prefix is reserved for Gas Town", name, ReservedLabelPrefix)
+	}
+	return nil
+}
+
+// IsEmpty reports whether the registry restricts nothing.
+func (r *LabelRegistry) IsEmpty() bool {
+	return r == nil || (len(r.Labels) == 0 && len(r.AllowedPrefixes) == 0)
+}
+
+// Find returns the registered label with name, or nil.
+func (r *LabelRegistry) Find(name string) *LabelDef {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Labels {
+		if r.Labels[i].Name == name {
+			return &r.Labels[i]
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a bead may carry label.
+func (r *LabelRegistry) Allows(label string) bool {
+	if r.IsEmpty() || strings.HasPrefix(label, ReservedLabelPrefix) || r.Find(label) != nil {
+		return true
+	}
+	for _, p := range r.AllowedPrefixes {
+		if strings.HasPrefix(label, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckLabels returns an error naming every label the registry doesn't allow.
+func (r *LabelRegistry) CheckLabels(labels []string) error {
+	var bad []string
+	for _, l := range labels {
+		if !r.Allows(l) {
+			bad = append(bad, l)
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unregistered label(s) %s: add them with 'gt bead labels add' or see 'gt bead labels list'",
+		strings.Join(bad, ", "))
+}
+
+// Add registers a label. Adding an existing name is an error.
+func (r *LabelRegistry) Add(def LabelDef) error {
+	if r.Find(def.Name) != nil {
+		return fmt.Errorf("label %q is already registered", def.Name)
+	}
+	r.Labels = append(r.Labels, def)
+	if err := r.Validate(); err != nil {
+		r.Labels = r.Labels[:len(r.Labels)-1]
+		return err
+	}
+	return nil
+}
+
+// AddPrefix allows every label starting with prefix.
+func (r *LabelRegistry) AddPrefix(prefix string) error {
+	if err := ValidateLabelName(prefix); err != nil {
+		return fmt.Errorf("allowed prefix: %w", err)
+	}
+	for _, p := range r.AllowedPrefixes {
+		if p == prefix {
+			return fmt.Errorf("prefix %q is already allowed", prefix)
+		}
+	}
+	r.AllowedPrefixes = append(r.AllowedPrefixes, prefix)
+	return nil
+}
+
+// Rename renames a registered label, keeping its color and description.
+func (r *LabelRegistry) Rename(from, to string) error {
+	def := r.Find(from)
+	if def == nil {
+		return fmt.Errorf("label %q is not registered", from)
+	}
+	if err := ValidateLabelName(to); err != nil {
+		return err
+	}
+	if r.Find(to) != nil {
+		return fmt.Errorf("label %q is already registered", to)
+	}
+	def.Name = to
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLabelRegistry_Allows(t *testing.T) {
+	var empty *LabelRegistry
+	if !empty.Allows("anything") {
+		t.Error("nil registry should allow any label")
+	}
+
+	reg := &LabelRegistry{
+		Labels:          []LabelDef{{Name: "incident"}},
+		AllowedPrefixes: []string{"sprint-"},
+	}
+	for label, want := range map[string]bool{
+		"incident": true,
+		"sprint-5": true,
+		"gt:agent": true,
+		"infra":    false,
+		"sprint":   false,
+	} {
+		if got := reg.Allows(label); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", label, got, want)
+		}
+	}
+
+	err := reg.CheckLabels([]string{"incident", "infra", "misc"})
+	if err == nil || !strings.Contains(err.Error(), "infra, misc") {
+		t.Errorf("CheckLabels error = %v, want naming infra, misc", err)
+	}
+}
+
+func TestLabelRegistry_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		reg     LabelRegistry
+		wantErr string
+	}{
+		{"valid", LabelRegistry{Labels: []LabelDef{{Name: "bug", Color: "#d73a4a"}}}, ""},
+		{"short color", LabelRegistry{Labels: []LabelDef{{Name: "bug", Color: "#fff"}}}, ""},
+		{"bad color", LabelRegistry{Labels: []LabelDef{{Name: "bug", Color: "red"}}}, "not a hex color"},
+		{"duplicate", LabelRegistry{Labels: []LabelDef{{Name: "bug"}, {Name: "bug"}}}, "registered twice"},
+		{"comma", LabelRegistry{Labels: []LabelDef{{Name: "a,b"}}}, "commas or whitespace"},
+		{"reserved", LabelRegistry{Labels: []LabelDef{{Name: "gt:agent"}}}, "reserved"},
+		{"wrong type", LabelRegistry{Type: "escalation"}, "expected type 'labels'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLabelRegistry_AddRename(t *testing.T) {
+	reg := NewLabelRegistry()
+	if err := reg.Add(LabelDef{Name: "infra", Color: "#00ff00", Description: "Infrastructure"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(LabelDef{Name: "infra"}); err == nil {
+		t.Error("adding a duplicate label should fail")
+	}
+	if err := reg.Add(LabelDef{Name: "bad", Color: "green"}); err == nil || len(reg.Labels) != 1 {
+		t.Errorf("invalid add should fail and leave registry unchanged: err=%v labels=%v", err, reg.Labels)
+	}
+	if err := reg.AddPrefix("sprint-"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.AddPrefix("sprint-"); err == nil {
+		t.Error("adding a duplicate prefix should fail")
+	}
+
+	if err := reg.Rename("infra", "infrastructure"); err != nil {
+		t.Fatal(err)
+	}
+	def := reg.Find("infrastructure")
+	if def == nil || def.Color != "#00ff00" || def.Description != "Infrastructure" {
+		t.Errorf("renamed label = %+v, want color and description kept", def)
+	}
+	if reg.Find("infra") != nil {
+		t.Error("old name still registered")
+	}
+	if err := reg.Rename("missing", "x"); err == nil {
+		t.Error("renaming an unregistered label should fail")
+	}
+}
+
+func TestLabelRegistry_SaveLoad(t *testing.T) {
+	path := LabelsConfigPath(t.TempDir())
+	if _, err := LoadLabelRegistry(path); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing file error = %v, want ErrNotFound", err)
+	}
+	reg, err := LoadOrCreateLabelRegistry(path)
+	if err != nil || !reg.IsEmpty() {
+		t.Fatalf("LoadOrCreate on missing file = %+v, %v", reg, err)
+	}
+
+	_ = reg.Add(LabelDef{Name: "incident", Color: "#d73a4a"})
+	if err := SaveLabelRegistry(path, reg); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(filepath.Dir(path)) != "settings" {
+		t.Errorf("path = %s, want under settings/", path)
+	}
+	loaded, err := LoadLabelRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Type != "labels" || loaded.Find("incident") == nil {
+		t.Errorf("loaded = %+v", loaded)
+	}
+}
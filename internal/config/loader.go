@@ -219,6 +219,26 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Toolchain != nil {
+		if err := validateToolchainConfig(c.Toolchain); err != nil {
+			return err
+		}
+	}
+	if err := c.Assignment.Validate(); err != nil {
+		return err
+	}
+	if err := c.Dispatch.Validate(); err != nil {
+		return err
+	}
+	if err := c.Mirror.Validate(); err != nil {
+		return err
+	}
+	if err := c.Verify.Validate(); err != nil {
+		return err
+	}
+	if err := c.Staleness.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1980,9 +2000,10 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 // For town-level roles (mayor, deacon, boot), pass empty rig and rigPath, but provide townRoot.
 func BuildAgentStartupCommand(role, rig, townRoot, rigPath, prompt string) string {
 	envVars := AgentEnv(AgentEnvConfig{
-		Role:     role,
-		Rig:      rig,
-		TownRoot: townRoot,
+		Role:      role,
+		Rig:       rig,
+		TownRoot:  townRoot,
+		Toolchain: toolchainForRole(role, rigPath),
 	})
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
@@ -1990,9 +2011,10 @@ func BuildAgentStartupCommand(role, rig, townRoot, rigPath, prompt string) strin
 // BuildAgentStartupCommandWithAgentOverride is like BuildAgentStartupCommand, but uses agentOverride if non-empty.
 func BuildAgentStartupCommandWithAgentOverride(role, rig, townRoot, rigPath, prompt, agentOverride string) (string, error) {
 	envVars := AgentEnv(AgentEnvConfig{
-		Role:     role,
-		Rig:      rig,
-		TownRoot: townRoot,
+		Role:      role,
+		Rig:       rig,
+		TownRoot:  townRoot,
+		Toolchain: toolchainForRole(role, rigPath),
 	})
 	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
 }
@@ -2009,6 +2031,7 @@ func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt string) st
 		Rig:       rigName,
 		AgentName: polecatName,
 		TownRoot:  townRoot,
+		Toolchain: toolchainForRole("polecat", rigPath),
 	})
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
@@ -2024,6 +2047,7 @@ func BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, rigPath,
 		Rig:       rigName,
 		AgentName: polecatName,
 		TownRoot:  townRoot,
+		Toolchain: toolchainForRole("polecat", rigPath),
 	})
 	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
 }
@@ -2040,6 +2064,7 @@ func BuildCrewStartupCommand(rigName, crewName, rigPath, prompt string) string {
 		Rig:       rigName,
 		AgentName: crewName,
 		TownRoot:  townRoot,
+		Toolchain: toolchainForRole("crew", rigPath),
 	})
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
@@ -2055,6 +2080,7 @@ func BuildCrewStartupCommandWithAgentOverride(rigName, crewName, rigPath, prompt
 		Rig:       rigName,
 		AgentName: crewName,
 		TownRoot:  townRoot,
+		Toolchain: toolchainForRole("crew", rigPath),
 	})
 	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
 }
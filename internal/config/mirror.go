@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// DefaultMirrorInterval is how often the daemon refreshes a bead mirror
+// when the rig sets no mirror.interval.
+const DefaultMirrorInterval = 15 * time.Minute
+
+// Validate checks that the mirror has a directory and a sane interval.
+func (m *MirrorConfig) Validate() error {
+	if m == nil {
+		return nil
+	}
+	if m.Dir == "" {
+		return fmt.Errorf("mirror: dir is required")
+	}
+	if _, err := m.IntervalDuration(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IntervalDuration returns the refresh period, or the default.
+func (m *MirrorConfig) IntervalDuration() (time.Duration, error) {
+	if m == nil || m.Interval == "" {
+		return DefaultMirrorInterval, nil
+	}
+	d, err := time.ParseDuration(m.Interval)
+	if err != nil || d < time.Minute {
+		return 0, fmt.Errorf("mirror: invalid interval %q: must be a duration of at least 1m", m.Interval)
+	}
+	return d, nil
+}
+
+// OutputDir returns the mirror directory, resolving a relative Dir against
+// the rig.
+func (m *MirrorConfig) OutputDir(rigPath string) string {
+	if filepath.IsAbs(m.Dir) {
+		return m.Dir
+	}
+	return filepath.Join(rigPath, m.Dir)
+}
+
+// LoadRigMirror returns a rig's mirror settings, or nil if the rig publishes
+// no mirror or its settings can't be read.
+func LoadRigMirror(rigPath string) *MirrorConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Mirror
+}
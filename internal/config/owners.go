@@ -0,0 +1,291 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OwnersConfigPath returns the path to the town ownership map.
+func OwnersConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "owners.json")
+}
+
+// NewOwnersConfig returns an empty ownership map.
+func NewOwnersConfig() *OwnersConfig {
+	return &OwnersConfig{Type: "owners", Version: CurrentOwnersVersion}
+}
+
+// LoadOwnersConfig loads and validates an ownership map.
+func LoadOwnersConfig(path string) (*OwnersConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading owners: %w", err)
+	}
+
+	var owners OwnersConfig
+	if err := json.Unmarshal(data, &owners); err != nil {
+		return nil, fmt.Errorf("parsing owners: %w", err)
+	}
+	if err := owners.Validate(); err != nil {
+		return nil, err
+	}
+	return &owners, nil
+}
+
+// LoadOrCreateOwnersConfig loads the ownership map, returning an empty one
+// if the town has none.
+func LoadOrCreateOwnersConfig(path string) (*OwnersConfig, error) {
+	owners, err := LoadOwnersConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewOwnersConfig(), nil
+		}
+		return nil, err
+	}
+	return owners, nil
+}
+
+// LoadTownOwners returns the town's ownership map, or nil if it has none
+// (or it can't be read). Callers that only use ownership as a hint use this;
+// 'gt owners' and the doctor check surface load errors.
+func LoadTownOwners(townRoot string) *OwnersConfig {
+	owners, err := LoadOwnersConfig(OwnersConfigPath(townRoot))
+	if err != nil || owners.IsEmpty() {
+		return nil
+	}
+	return owners
+}
+
+// SaveOwnersConfig validates and writes an ownership map.
+func SaveOwnersConfig(path string, owners *OwnersConfig) error {
+	if err := owners.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(owners, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding owners: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: ownership map doesn't contain secrets
+		return fmt.Errorf("writing owners: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the map's type and version, that team names are unique
+// and leads are members, and that every rule has one well-formed condition
+// and an owner.
+func (o *OwnersConfig) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Type != "owners" && o.Type != "" {
+		return fmt.Errorf("%w: expected type 'owners', got '%s'", ErrInvalidType, o.Type)
+	}
+	if o.Version > CurrentOwnersVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, o.Version, CurrentOwnersVersion)
+	}
+	seen := make(map[string]bool)
+	for _, t := range o.Teams {
+		if err := ValidateTeamName(t.Name); err != nil {
+			return err
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("team %q is defined twice", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Lead != "" && !containsString(t.Members, t.Lead) {
+			return fmt.Errorf("team %q: lead %s is not a member", t.Name, t.Lead)
+		}
+	}
+	for i, r := range o.Rules {
+		if strings.TrimSpace(r.Owner) == "" {
+			return fmt.Errorf("owner rule %d: owner is required", i+1)
+		}
+		if (r.Path == "") == (r.Label == "") {
+			return fmt.Errorf("owner rule %d: needs exactly one of path or label", i+1)
+		}
+		if r.Path != "" {
+			if err := validateOwnerPattern(r.Path); err != nil {
+				return fmt.Errorf("owner rule %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTeamName rejects names that could be mistaken for a worker
+// address.
+func ValidateTeamName(name string) error {
+	if name == "" {
+		return errors.New("team name is empty")
+	}
+	if strings.ContainsAny(name, "/, \t\n") {
+		return fmt.Errorf("team %q: names may not contain slashes, commas or whitespace", name)
+	}
+	return nil
+}
+
+// IsEmpty reports whether the map defines nothing.
+func (o *OwnersConfig) IsEmpty() bool {
+	return o == nil || (len(o.Teams) == 0 && len(o.Rules) == 0)
+}
+
+// FindTeam returns the team named name, or nil.
+func (o *OwnersConfig) FindTeam(name string) *TeamDef {
+	if o == nil {
+		return nil
+	}
+	for i := range o.Teams {
+		if o.Teams[i].Name == name {
+			return &o.Teams[i]
+		}
+	}
+	return nil
+}
+
+// Members returns the workers behind an owner: a team's members, or the
+// owner itself when it is a worker address.
+func (o *OwnersConfig) Members(owner string) []string {
+	if t := o.FindTeam(owner); t != nil {
+		return t.Members
+	}
+	return []string{owner}
+}
+
+// Assignee returns who receives beads routed to owner: a team's lead (or
+// its only member), or the owner itself when it is a worker address. It
+// returns "" for a team with several members and no lead.
+func (o *OwnersConfig) Assignee(owner string) string {
+	t := o.FindTeam(owner)
+	switch {
+	case t == nil:
+		return owner
+	case t.Lead != "":
+		return t.Lead
+	case len(t.Members) == 1:
+		return t.Members[0]
+	}
+	return ""
+}
+
+// TeamsOf returns the names of the teams address belongs to.
+func (o *OwnersConfig) TeamsOf(address string) []string {
+	if o == nil {
+		return nil
+	}
+	var teams []string
+	for _, t := range o.Teams {
+		if containsString(t.Members, address) {
+			teams = append(teams, t.Name)
+		}
+	}
+	return teams
+}
+
+// OwnerForPath returns the rule owning file (a slash-separated path in the
+// rig repo). As in CODEOWNERS, the last matching rule wins.
+func (o *OwnersConfig) OwnerForPath(file string) (OwnerRule, bool) {
+	if o == nil {
+		return OwnerRule{}, false
+	}
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if r := o.Rules[i]; r.Path != "" && MatchOwnerPattern(r.Path, file) {
+			return r, true
+		}
+	}
+	return OwnerRule{}, false
+}
+
+// OwnerForLabels returns the rule owning a bead with labels. The last
+// matching rule wins.
+func (o *OwnersConfig) OwnerForLabels(labels []string) (OwnerRule, bool) {
+	if o == nil {
+		return OwnerRule{}, false
+	}
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if r := o.Rules[i]; r.Label != "" && containsString(labels, r.Label) {
+			return r, true
+		}
+	}
+	return OwnerRule{}, false
+}
+
+// ReviewersFor returns the workers owning any of files, sorted, without
+// exclude (typically the author).
+func (o *OwnersConfig) ReviewersFor(files []string, exclude string) []string {
+	set := make(map[string]bool)
+	for _, f := range files {
+		if r, ok := o.OwnerForPath(f); ok {
+			for _, m := range o.Members(r.Owner) {
+				if m != exclude {
+					set[m] = true
+				}
+			}
+		}
+	}
+	reviewers := make([]string, 0, len(set))
+	for m := range set {
+		reviewers = append(reviewers, m)
+	}
+	sort.Strings(reviewers)
+	return reviewers
+}
+
+// MatchOwnerPattern reports whether a CODEOWNERS-style pattern matches file.
+// A pattern without a slash matches a file or directory name at any depth
+// ("*.md", "vendor"); one with a slash is anchored at the repo root. A
+// trailing slash or "/**" matches everything under a directory. Patterns use
+// path.Match globs.
+func MatchOwnerPattern(pattern, file string) bool {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/**"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	parts := strings.Split(strings.Trim(path.Clean("/"+file), "/"), "/")
+
+	if !anchored {
+		for _, part := range parts {
+			if ok, _ := path.Match(pattern, part); ok {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range parts {
+		if ok, _ := path.Match(pattern, strings.Join(parts[:i+1], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func validateOwnerPattern(pattern string) error {
+	trimmed := strings.TrimSuffix(pattern, "/**")
+	if strings.Contains(trimmed, "**") {
+		return fmt.Errorf("path %q: ** is only supported as a trailing /**", pattern)
+	}
+	if _, err := path.Match(strings.Trim(trimmed, "/"), ""); err != nil {
+		return fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchOwnerPattern(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide/intro.md", true},
+		{"*.md", "main.go", false},
+		{"vendor", "third_party/vendor/x.go", true},
+		{"internal/mq/", "internal/mq/stats.go", true},
+		{"internal/mq/", "pkg/internal/mq/stats.go", false},
+		{"/docs", "docs/a.md", true},
+		{"/docs/**", "docs/guide/a.md", true},
+		{"/docs/**", "src/docs/a.md", false},
+		{"internal/*/doctor.go", "internal/cmd/doctor.go", true},
+	}
+	for _, tt := range tests {
+		if got := MatchOwnerPattern(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchOwnerPattern(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestOwnersConfig_LastMatchWins(t *testing.T) {
+	o := &OwnersConfig{
+		Teams: []TeamDef{
+			{Name: "core", Members: []string{"gastown/crew/max", "gastown/crew/joe"}, Lead: "gastown/crew/max"},
+			{Name: "docs", Members: []string{"gastown/crew/ann"}},
+		},
+		Rules: []OwnerRule{
+			{Path: "internal/", Owner: "core"},
+			{Path: "*.md", Owner: "docs"},
+			{Path: "internal/web/", Owner: "gastown/crew/lee"},
+			{Label: "frontend", Owner: "gastown/crew/lee"},
+			{Label: "infra", Owner: "core"},
+		},
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r, _ := o.OwnerForPath("internal/web/README.md"); r.Owner != "gastown/crew/lee" {
+		t.Errorf("internal/web/README.md owner = %q, want the later internal/web/ rule", r.Owner)
+	}
+	if r, _ := o.OwnerForLabels([]string{"infra", "frontend"}); r.Owner != "core" {
+		t.Errorf("label owner = %q, want core (last matching rule)", r.Owner)
+	}
+	if _, ok := o.OwnerForPath("go.mod"); ok {
+		t.Error("go.mod should have no owner")
+	}
+
+	got := o.ReviewersFor([]string{"internal/mq/stats.go", "docs/a.md", "go.mod"}, "gastown/crew/joe")
+	want := []string{"gastown/crew/ann", "gastown/crew/max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReviewersFor = %v, want %v", got, want)
+	}
+
+	if a := o.Assignee("core"); a != "gastown/crew/max" {
+		t.Errorf("Assignee(core) = %q, want the lead", a)
+	}
+	if a := o.Assignee("docs"); a != "gastown/crew/ann" {
+		t.Errorf("Assignee(docs) = %q, want the only member", a)
+	}
+	if a := o.Assignee("gastown/crew/lee"); a != "gastown/crew/lee" {
+		t.Errorf("Assignee(worker) = %q", a)
+	}
+}
+
+func TestOwnersConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		owners  OwnersConfig
+		wantErr string
+	}{
+		{"no owner", OwnersConfig{Rules: []OwnerRule{{Path: "a/"}}}, "owner is required"},
+		{"both conditions", OwnersConfig{Rules: []OwnerRule{{Path: "a/", Label: "x", Owner: "core"}}}, "exactly one"},
+		{"inner globstar", OwnersConfig{Rules: []OwnerRule{{Path: "a/**/b", Owner: "core"}}}, "trailing /**"},
+		{"duplicate team", OwnersConfig{Teams: []TeamDef{{Name: "core"}, {Name: "core"}}}, "defined twice"},
+		{"slash in team", OwnersConfig{Teams: []TeamDef{{Name: "a/b"}}}, "slashes"},
+		{"lead not member", OwnersConfig{Teams: []TeamDef{{Name: "core", Lead: "gastown/crew/max"}}}, "not a member"},
+		{"wrong type", OwnersConfig{Type: "labels"}, "expected type 'owners'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.owners.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,546 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// Settings are layered. A key is read from the most specific layer that
+// sets it — rig, then town, then user — and falls back to its default.
+// Each key declares the layers it may be set in; gt config get/set/list
+// work through this schema rather than editing the JSON files by hand.
+
+// Config scopes, most specific first.
+const (
+	ScopeRig  = "rig"  // <rig>/settings/config.json (refinery keys: <rig>/config.json)
+	ScopeTown = "town" // <town>/settings/config.json
+	ScopeUser = "user" // $XDG_CONFIG_HOME/gastown/config.json: personal defaults for every town
+)
+
+// KeyType is the type of a config value.
+type KeyType string
+
+// Config value types.
+const (
+	KeyString   KeyType = "string"
+	KeyBool     KeyType = "bool"
+	KeyInt      KeyType = "int"      // Non-negative
+	KeyDuration KeyType = "duration" // Positive, e.g. "30s"; stored as a string
+	KeyEnum     KeyType = "enum"     // One of Values
+)
+
+// ConfigKey describes one setting.
+type ConfigKey struct {
+	// Key is the dotted JSON path in the settings file. A final "*" matches
+	// any one name (log.*).
+	Key         string   `json:"key"`
+	Type        KeyType  `json:"type"`
+	Values      []string `json:"values,omitempty"` // Allowed values (enum)
+	Default     string   `json:"default,omitempty"`
+	Scopes      []string `json:"scopes"` // Layers it may be set in; the first is where set writes by default
+	Description string   `json:"description"`
+
+	// rigFile is the rig layer's file relative to the rig, if not
+	// settings/config.json. The refinery reads its tuning from config.json.
+	rigFile string
+
+	// normalize checks and canonicalizes a value in place of Type's check.
+	normalize func(string) (string, error)
+}
+
+// ConfigSchema lists the settings gt config manages.
+var ConfigSchema = []ConfigKey{
+	{Key: "cli_theme", Type: KeyEnum, Values: []string{"auto", "dark", "light"}, Default: "auto", Scopes: []string{ScopeTown, ScopeUser},
+		Description: "CLI color scheme (GT_THEME overrides)"},
+	{Key: "default_agent", Type: KeyString, Default: "claude", Scopes: []string{ScopeTown},
+		Description: "Default agent preset"},
+	{Key: "agent_email_domain", Type: KeyString, Default: "gastown.local", Scopes: []string{ScopeTown},
+		Description: "Domain of agent git identity emails"},
+	{Key: "convoy.notify_on_complete", Type: KeyBool, Default: "false", Scopes: []string{ScopeTown},
+		Description: "Notify the Mayor session when a convoy completes"},
+	{Key: "log.*", Type: KeyEnum, Values: []string{"debug", "info", "warn", "error", "off"}, Default: "info", Scopes: []string{ScopeTown},
+		Description: "Log level of a module in logs/gt.log (\"default\" for all others)", normalize: normalizeLogLevel},
+
+	{Key: "agent", Type: KeyString, Scopes: []string{ScopeRig},
+		Description: "Agent preset for the rig (overrides default_agent)"},
+	{Key: "merge_queue.test_command", Type: KeyString, Default: "go test ./...", Scopes: []string{ScopeRig},
+		Description: "Test command for polecats and integration branches"},
+	{Key: "merge_queue.lint_command", Type: KeyString, Scopes: []string{ScopeRig},
+		Description: "Lint command (used by formulas)"},
+	{Key: "merge_queue.build_command", Type: KeyString, Scopes: []string{ScopeRig},
+		Description: "Build command (used by formulas)"},
+	{Key: "merge_queue.setup_command", Type: KeyString, Scopes: []string{ScopeRig},
+		Description: "Project setup command (e.g., pnpm install)"},
+	{Key: "merge_queue.typecheck_command", Type: KeyString, Scopes: []string{ScopeRig},
+		Description: "Type check command (e.g., tsc --noEmit)"},
+	{Key: "merge_queue.integration_branch_template", Type: KeyString, Default: "integration/{epic}", Scopes: []string{ScopeRig},
+		Description: "Integration branch name pattern ({epic}, {prefix}, {user})"},
+	{Key: "merge_queue.integration_branch_auto_land", Type: KeyBool, Default: "false", Scopes: []string{ScopeRig},
+		Description: "Land integration branches when every child of the epic is closed"},
+	{Key: "merge_queue.integration_branch_polecat_enabled", Type: KeyBool, Default: "true", Scopes: []string{ScopeRig},
+		Description: "Polecats start from their epic's integration branch"},
+	{Key: "merge_queue.integration_branch_refinery_enabled", Type: KeyBool, Default: "true", Scopes: []string{ScopeRig},
+		Description: "MRs target their epic's integration branch"},
+
+	{Key: "merge_queue.enabled", Type: KeyBool, Default: "true", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "Refinery processes the merge queue"},
+	{Key: "merge_queue.on_conflict", Type: KeyEnum, Values: []string{OnConflictAssignBack, OnConflictAutoRebase}, Default: OnConflictAssignBack, Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "What the refinery does with a conflicting MR"},
+	{Key: "merge_queue.run_tests", Type: KeyBool, Default: "true", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "Refinery runs tests before merging"},
+	{Key: "merge_queue.delete_merged_branches", Type: KeyBool, Default: "true", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "Refinery deletes branches after merging"},
+	{Key: "merge_queue.retry_flaky_tests", Type: KeyInt, Default: "1", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "Times the refinery retries failing tests"},
+	{Key: "merge_queue.poll_interval", Type: KeyDuration, Default: "30s", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "How often the refinery polls for MRs"},
+	{Key: "merge_queue.max_concurrent", Type: KeyInt, Default: "1", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "Refinery workers ('gt refinery run')"},
+	{Key: "merge_queue.stale_claim_timeout", Type: KeyDuration, Default: "30m", Scopes: []string{ScopeRig}, rigFile: "config.json",
+		Description: "How long a claimed MR may go unrenewed before another worker takes it"},
+}
+
+// ErrUnknownConfigKey is returned for keys not in ConfigSchema.
+var ErrUnknownConfigKey = errors.New("unknown config key")
+
+// LookupConfigKey returns the schema entry for key.
+func LookupConfigKey(key string) (*ConfigKey, error) {
+	for i := range ConfigSchema {
+		k := &ConfigSchema[i]
+		if prefix, ok := strings.CutSuffix(k.Key, "*"); ok {
+			if name, ok := strings.CutPrefix(key, prefix); ok && name != "" && !strings.ContainsAny(name, ". ") {
+				return k, nil
+			}
+			continue
+		}
+		if k.Key == key {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q (see 'gt config list --schema')", ErrUnknownConfigKey, key)
+}
+
+// AllowsScope reports whether the key may be set in scope.
+func (k *ConfigKey) AllowsScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse checks value against the key's type and returns it as stored in
+// JSON.
+func (k *ConfigKey) Parse(key, value string) (any, error) {
+	if k.normalize != nil {
+		v, err := k.normalize(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return v, nil
+	}
+	switch k.Type {
+	case KeyBool:
+		b, err := parseConfigBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w (expected true/false)", key, err)
+		}
+		return b, nil
+	case KeyInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid value for %s: %q (expected a non-negative integer)", key, value)
+		}
+		return n, nil
+	case KeyDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid value for %s: %q (expected a positive duration, e.g. 30s)", key, value)
+		}
+		return value, nil
+	case KeyEnum:
+		for _, v := range k.Values {
+			if strings.EqualFold(v, value) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid %s: %q (expected %s)", key, value, strings.Join(k.Values, ", "))
+	}
+	return value, nil
+}
+
+func parseConfigBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1", "on":
+		return true, nil
+	case "false", "no", "0", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("cannot parse %q as boolean", s)
+}
+
+func normalizeLogLevel(s string) (string, error) {
+	level, err := logging.ParseLevel(s)
+	if err != nil {
+		return "", err
+	}
+	return logging.LevelName(level), nil
+}
+
+// UserConfigPath returns the user layer's file.
+func UserConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gastown", "config.json")
+}
+
+// ConfigLayers locates the settings files of a town and, if rigPath is
+// set, one of its rigs.
+type ConfigLayers struct {
+	TownRoot string
+	RigPath  string
+	UserPath string
+}
+
+// NewConfigLayers returns the layers for a town and rig (rigPath may be
+// empty).
+func NewConfigLayers(townRoot, rigPath string) ConfigLayers {
+	return ConfigLayers{TownRoot: townRoot, RigPath: rigPath, UserPath: UserConfigPath()}
+}
+
+// Path returns the file key is stored in at scope, or "" if the layer isn't
+// available (no rig selected, no user config directory).
+func (l ConfigLayers) Path(k *ConfigKey, scope string) string {
+	switch scope {
+	case ScopeRig:
+		if l.RigPath == "" {
+			return ""
+		}
+		if k.rigFile != "" {
+			return filepath.Join(l.RigPath, k.rigFile)
+		}
+		return RigSettingsPath(l.RigPath)
+	case ScopeTown:
+		if l.TownRoot == "" {
+			return ""
+		}
+		return TownSettingsPath(l.TownRoot)
+	case ScopeUser:
+		return l.UserPath
+	}
+	return ""
+}
+
+// ConfigValue is a setting's effective value and where it came from.
+type ConfigValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Scope string `json:"scope"`          // Layer that set it, or "default"
+	Path  string `json:"path,omitempty"` // File that set it
+}
+
+// ScopeDefault marks a ConfigValue that no layer sets.
+const ScopeDefault = "default"
+
+// Get resolves key across the layers, most specific first.
+func (l ConfigLayers) Get(key string) (*ConfigValue, error) {
+	k, err := LookupConfigKey(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range []string{ScopeRig, ScopeTown, ScopeUser} {
+		if !k.AllowsScope(scope) {
+			continue
+		}
+		path := l.Path(k, scope)
+		if path == "" {
+			continue
+		}
+		doc, err := readConfigDoc(path)
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := getConfigPath(doc, key); ok {
+			return &ConfigValue{Key: key, Value: formatConfigValue(v), Scope: scope, Path: path}, nil
+		}
+	}
+	value := k.Default
+	if key == "log."+logging.DefaultModule || !strings.HasSuffix(k.Key, "*") {
+		return &ConfigValue{Key: key, Value: value, Scope: ScopeDefault}, nil
+	}
+	// A module without its own level logs at the default module's level
+	if v, err := l.Get("log." + logging.DefaultModule); err == nil && v.Scope != ScopeDefault {
+		return &ConfigValue{Key: key, Value: v.Value, Scope: v.Scope, Path: v.Path}, nil
+	}
+	return &ConfigValue{Key: key, Value: value, Scope: ScopeDefault}, nil
+}
+
+// List resolves every schema key, plus each log.<module> a layer sets,
+// ordered by key.
+func (l ConfigLayers) List() ([]*ConfigValue, error) {
+	keys := make(map[string]bool)
+	for i := range ConfigSchema {
+		k := &ConfigSchema[i]
+		prefix, wildcard := strings.CutSuffix(k.Key, "*")
+		if !wildcard {
+			keys[k.Key] = true
+			continue
+		}
+		for _, scope := range k.Scopes {
+			path := l.Path(k, scope)
+			if path == "" {
+				continue
+			}
+			doc, err := readConfigDoc(path)
+			if err != nil {
+				return nil, err
+			}
+			section, _ := getConfigPath(doc, strings.TrimSuffix(prefix, "."))
+			if m, ok := section.(map[string]any); ok {
+				for name := range m {
+					keys[prefix+name] = true
+				}
+			}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var values []*ConfigValue
+	for _, key := range sorted {
+		v, err := l.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Set validates value and writes key at scope, returning the file written
+// and the value as stored.
+func (l ConfigLayers) Set(scope, key, value string) (path, stored string, err error) {
+	k, path, err := l.target(scope, key)
+	if err != nil {
+		return "", "", err
+	}
+	v, err := k.Parse(key, value)
+	if err != nil {
+		return "", "", err
+	}
+	doc, err := l.loadForWrite(scope, k, path)
+	if err != nil {
+		return "", "", err
+	}
+	setConfigPath(doc, key, v)
+	if err := writeConfigDoc(scope, k, path, doc); err != nil {
+		return "", "", err
+	}
+	return path, formatConfigValue(v), nil
+}
+
+// Unset removes key from scope, so a less specific layer (or the default)
+// applies. It reports whether the key was set there.
+func (l ConfigLayers) Unset(scope, key string) (path string, removed bool, err error) {
+	k, path, err := l.target(scope, key)
+	if err != nil {
+		return "", false, err
+	}
+	doc, err := readConfigDoc(path)
+	if err != nil || doc == nil {
+		return path, false, err
+	}
+	if !deleteConfigPath(doc, key) {
+		return path, false, nil
+	}
+	return path, true, writeConfigDoc(scope, k, path, doc)
+}
+
+// DefaultScope returns the scope Set writes key to when none is given.
+func DefaultScope(key string) (string, error) {
+	k, err := LookupConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	return k.Scopes[0], nil
+}
+
+func (l ConfigLayers) target(scope, key string) (*ConfigKey, string, error) {
+	k, err := LookupConfigKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if !k.AllowsScope(scope) {
+		return nil, "", fmt.Errorf("%s can't be set at %s scope (allowed: %s)", key, scope, strings.Join(k.Scopes, ", "))
+	}
+	path := l.Path(k, scope)
+	if path == "" {
+		if scope == ScopeRig {
+			return nil, "", fmt.Errorf("%s is a rig setting: run from inside a rig or use --rig", key)
+		}
+		return nil, "", fmt.Errorf("no %s config location", scope)
+	}
+	return k, path, nil
+}
+
+// loadForWrite reads the file to change. A missing settings file starts
+// from the defaults its loader would use, so writing one key doesn't zero
+// the rest.
+func (l ConfigLayers) loadForWrite(scope string, k *ConfigKey, path string) (map[string]any, error) {
+	doc, err := readConfigDoc(path)
+	if err != nil || doc != nil {
+		return doc, err
+	}
+	var seed any
+	switch {
+	case scope == ScopeTown:
+		seed = NewTownSettings()
+	case scope == ScopeRig && k.rigFile == "":
+		seed = NewRigSettings()
+	case scope == ScopeRig:
+		return nil, fmt.Errorf("rig config %s not found", path)
+	default:
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(seed)
+	if err != nil {
+		return nil, err
+	}
+	doc = map[string]any{}
+	return doc, json.Unmarshal(data, &doc)
+}
+
+func readConfigDoc(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a settings file location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// writeConfigDoc checks that the changed file still loads as its settings
+// type, then writes it.
+func writeConfigDoc(scope string, k *ConfigKey, path string, doc map[string]any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch {
+	case scope == ScopeTown:
+		var s TownSettings
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid town settings: %w", err)
+		}
+	case scope == ScopeRig && k.rigFile == "":
+		var s RigSettings
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid rig settings: %w", err)
+		}
+		if err := validateRigSettings(&s); err != nil {
+			return err
+		}
+	case scope == ScopeRig:
+		var c struct {
+			MergeQueue *MergeQueueConfig `json:"merge_queue"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("invalid rig config: %w", err)
+		}
+		if c.MergeQueue != nil {
+			if err := validateMergeQueueConfig(c.MergeQueue); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: settings are not secret
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func getConfigPath(doc map[string]any, key string) (any, bool) {
+	var cur any = doc
+	for _, part := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+func setConfigPath(doc map[string]any, key string, v any) {
+	parts := strings.Split(key, ".")
+	m := doc
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = v
+}
+
+func deleteConfigPath(doc map[string]any, key string) bool {
+	parts := strings.Split(key, ".")
+	m := doc
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			return false
+		}
+		m = next
+	}
+	last := parts[len(parts)-1]
+	if _, ok := m[last]; !ok {
+		return false
+	}
+	delete(m, last)
+	return true
+}
+
+func formatConfigValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
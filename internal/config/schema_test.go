@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestLayers(t *testing.T) ConfigLayers {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	if err := os.MkdirAll(rig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rig, "config.json"), []byte(`{"type":"rig","version":1,"name":"gastown"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewConfigLayers(town, rig)
+}
+
+func TestConfigLayers_Precedence(t *testing.T) {
+	l := newTestLayers(t)
+
+	v, err := l.Get("cli_theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "auto" || v.Scope != ScopeDefault {
+		t.Errorf("unset cli_theme = %+v, want auto from default", v)
+	}
+
+	if _, _, err := l.Set(ScopeUser, "cli_theme", "light"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := l.Get("cli_theme"); v.Value != "light" || v.Scope != ScopeUser || v.Path != l.UserPath {
+		t.Errorf("cli_theme = %+v, want light from user", v)
+	}
+
+	if _, _, err := l.Set(ScopeTown, "cli_theme", "dark"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := l.Get("cli_theme"); v.Value != "dark" || v.Scope != ScopeTown {
+		t.Errorf("cli_theme = %+v, want dark from town", v)
+	}
+
+	if _, removed, err := l.Unset(ScopeTown, "cli_theme"); err != nil || !removed {
+		t.Fatalf("Unset = %v, %v", removed, err)
+	}
+	if v, _ := l.Get("cli_theme"); v.Scope != ScopeUser {
+		t.Errorf("after unset, cli_theme = %+v, want user value", v)
+	}
+}
+
+func TestConfigLayers_SetValidates(t *testing.T) {
+	l := newTestLayers(t)
+
+	tests := []struct {
+		scope, key, value string
+	}{
+		{ScopeTown, "cli_theme", "neon"},
+		{ScopeTown, "convoy.notify_on_complete", "maybe"},
+		{ScopeTown, "log.mq", "loud"},
+		{ScopeRig, "merge_queue.poll_interval", "soon"},
+		{ScopeRig, "merge_queue.max_concurrent", "-1"},
+		{ScopeRig, "cli_theme", "dark"}, // Not a rig setting
+	}
+	for _, tt := range tests {
+		if _, _, err := l.Set(tt.scope, tt.key, tt.value); err == nil {
+			t.Errorf("Set(%s, %s, %s) should fail", tt.scope, tt.key, tt.value)
+		}
+	}
+	if _, _, err := l.Set(ScopeTown, "no.such.key", "x"); !errors.Is(err, ErrUnknownConfigKey) {
+		t.Errorf("unknown key error = %v", err)
+	}
+	if _, err := os.Stat(TownSettingsPath(l.TownRoot)); !os.IsNotExist(err) {
+		t.Error("rejected values were written")
+	}
+}
+
+func TestConfigLayers_RigFiles(t *testing.T) {
+	l := newTestLayers(t)
+
+	path, stored, err := l.Set(ScopeRig, "merge_queue.max_concurrent", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(l.RigPath, "config.json") || stored != "3" {
+		t.Errorf("Set wrote %s = %s, want the refinery's config.json", path, stored)
+	}
+	data, _ := os.ReadFile(path)
+	var rigCfg map[string]any
+	if err := json.Unmarshal(data, &rigCfg); err != nil {
+		t.Fatal(err)
+	}
+	if rigCfg["name"] != "gastown" {
+		t.Errorf("rig config lost its other fields: %s", data)
+	}
+
+	path, _, err = l.Set(ScopeRig, "merge_queue.test_command", "make test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != RigSettingsPath(l.RigPath) {
+		t.Errorf("test_command written to %s, want rig settings", path)
+	}
+	settings, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.MergeQueue.TestCommand != "make test" || settings.MergeQueue.OnConflict != OnConflictAssignBack {
+		t.Errorf("rig settings = %+v, want test_command set over the defaults", settings.MergeQueue)
+	}
+}
+
+func TestConfigLayers_LogModuleFallsBackToDefault(t *testing.T) {
+	l := newTestLayers(t)
+	if _, stored, err := l.Set(ScopeTown, "log.default", "WARNING"); err != nil || stored != "warn" {
+		t.Fatalf("Set log.default = %q, %v; want warn", stored, err)
+	}
+	v, err := l.Get("log.mq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Value != "warn" || v.Scope != ScopeTown {
+		t.Errorf("log.mq = %+v, want warn from log.default", v)
+	}
+
+	values, err := l.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, v := range values {
+		found = found || v.Key == "log.default"
+	}
+	if !found {
+		t.Error("List omits log.default, which the town sets")
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Staleness policy actions.
+const (
+	StaleActionEscalate = "escalate" // File an escalation about the bead
+	StaleActionReopen   = "reopen"   // Return the bead to open, unassigned
+)
+
+// Validate checks that every policy has a unique name, a valid age and
+// action, and a known severity.
+func (s *StalenessConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(s.Policies))
+	for i, p := range s.Policies {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("staleness policy %d: name is required", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("staleness policy %s: duplicate name", p.Name)
+		}
+		seen[p.Name] = true
+		if _, err := p.AgeDuration(); err != nil {
+			return fmt.Errorf("staleness policy %s: %w", p.Name, err)
+		}
+		if p.Since != "" && p.Since != "created" && p.Since != "updated" {
+			return fmt.Errorf("staleness policy %s: since must be created or updated, got %q", p.Name, p.Since)
+		}
+		switch p.Action {
+		case StaleActionEscalate:
+			if p.Severity != "" && !IsValidSeverity(p.Severity) {
+				return fmt.Errorf("staleness policy %s: invalid severity %q", p.Name, p.Severity)
+			}
+		case StaleActionReopen:
+			if p.MatchStatus() == "open" {
+				return fmt.Errorf("staleness policy %s: reopen needs a status other than open", p.Name)
+			}
+		default:
+			return fmt.Errorf("staleness policy %s: action must be %s or %s, got %q", p.Name, StaleActionEscalate, StaleActionReopen, p.Action)
+		}
+	}
+	return nil
+}
+
+// MatchStatus returns the status the policy applies to.
+func (p StalenessPolicy) MatchStatus() string {
+	if p.Status == "" {
+		return "open"
+	}
+	return p.Status
+}
+
+// AgeDuration parses Age: a Go duration, or a whole number of days ("2d")
+// or weeks ("1w").
+func (p StalenessPolicy) AgeDuration() (time.Duration, error) {
+	s := strings.TrimSpace(p.Age)
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit *= 7
+		}
+		var n int
+		n, err = strconv.Atoi(s[:len(s)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q: want a positive duration like 12h, 2d or 1w", p.Age)
+	}
+	return d, nil
+}
+
+// Describe returns the policy's conditions, e.g. "status=open priority<=P0 age>2d".
+func (p StalenessPolicy) Describe() string {
+	parts := []string{"status=" + p.MatchStatus()}
+	if p.Priority != nil {
+		parts = append(parts, fmt.Sprintf("priority<=P%d", *p.Priority))
+	}
+	if p.Type != "" {
+		parts = append(parts, "type="+p.Type)
+	}
+	if p.Label != "" {
+		parts = append(parts, "label="+p.Label)
+	}
+	since := "age"
+	if p.Since == "updated" {
+		since = "untouched"
+	}
+	return strings.Join(append(parts, since+">"+p.Age), " ")
+}
+
+// LoadRigStaleness returns a rig's staleness policies, or nil if the rig
+// has none (or its settings can't be read).
+func LoadRigStaleness(rigPath string) *StalenessConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Staleness == nil || len(settings.Staleness.Policies) == 0 {
+		return nil
+	}
+	return settings.Staleness
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStalenessConfigValidate(t *testing.T) {
+	valid := &StalenessConfig{Policies: []StalenessPolicy{
+		{Name: "p0-open", Age: "2d", Action: StaleActionEscalate, Severity: SeverityHigh},
+		{Name: "abandoned", Status: "in_progress", Age: "1w", Since: "updated", Action: StaleActionReopen},
+	}}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (*StalenessConfig)(nil).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		policy StalenessPolicy
+		want   string
+	}{
+		{StalenessPolicy{Age: "2d", Action: StaleActionEscalate}, "name is required"},
+		{StalenessPolicy{Name: "x", Age: "soon", Action: StaleActionEscalate}, "invalid age"},
+		{StalenessPolicy{Name: "x", Age: "2d", Action: "delete"}, "action must be"},
+		{StalenessPolicy{Name: "x", Age: "2d", Action: StaleActionReopen}, "status other than open"},
+		{StalenessPolicy{Name: "x", Age: "2d", Since: "closed", Action: StaleActionEscalate}, "since must be"},
+		{StalenessPolicy{Name: "x", Age: "2d", Action: StaleActionEscalate, Severity: "dire"}, "invalid severity"},
+	}
+	for _, tt := range tests {
+		err := (&StalenessConfig{Policies: []StalenessPolicy{tt.policy}}).Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.policy, err, tt.want)
+		}
+	}
+
+	dup := &StalenessConfig{Policies: []StalenessPolicy{valid.Policies[0], valid.Policies[0]}}
+	if err := dup.Validate(); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("duplicate names: %v", err)
+	}
+}
+
+func TestStalenessPolicyAgeDuration(t *testing.T) {
+	for age, want := range map[string]time.Duration{
+		"12h": 12 * time.Hour,
+		"2d":  48 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+	} {
+		if got, err := (StalenessPolicy{Age: age}).AgeDuration(); err != nil || got != want {
+			t.Errorf("AgeDuration(%s) = %v, %v; want %v", age, got, err, want)
+		}
+	}
+	for _, age := range []string{"", "0d", "-1h", "d"} {
+		if _, err := (StalenessPolicy{Age: age}).AgeDuration(); err == nil {
+			t.Errorf("AgeDuration(%q) accepted", age)
+		}
+	}
+}
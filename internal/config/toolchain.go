@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Toolchain names a rig can pin (gt rig pin <rig> <tool>=<version>).
+const (
+	ToolGo   = "go"
+	ToolNode = "node"
+	ToolDolt = "dolt"
+)
+
+// ToolchainTools lists the pinnable tools in display order.
+var ToolchainTools = []string{ToolGo, ToolNode, ToolDolt}
+
+// ErrInvalidToolchain indicates a malformed toolchain pin.
+var ErrInvalidToolchain = errors.New("invalid toolchain pin")
+
+// toolchainVersionRe accepts "1", "1.22", or "1.22.3".
+var toolchainVersionRe = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+
+// Get returns the pinned version of tool, or "" if it is not pinned.
+func (t *ToolchainConfig) Get(tool string) string {
+	if t == nil {
+		return ""
+	}
+	switch tool {
+	case ToolGo:
+		return t.Go
+	case ToolNode:
+		return t.Node
+	case ToolDolt:
+		return t.Dolt
+	}
+	return ""
+}
+
+// Set pins tool to version. An empty version removes the pin. A leading
+// "v" or "go" prefix is accepted ("go1.22.3", "v20.11.0").
+func (t *ToolchainConfig) Set(tool, version string) error {
+	version = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "go"), "v")
+	if version != "" && !toolchainVersionRe.MatchString(version) {
+		return fmt.Errorf("%w: %s version %q (want e.g. 1.22 or 1.22.3)", ErrInvalidToolchain, tool, version)
+	}
+	switch tool {
+	case ToolGo:
+		t.Go = version
+	case ToolNode:
+		t.Node = version
+	case ToolDolt:
+		t.Dolt = version
+	default:
+		return fmt.Errorf("%w: unknown tool %q (want %s)", ErrInvalidToolchain, tool, strings.Join(ToolchainTools, ", "))
+	}
+	return nil
+}
+
+// IsEmpty reports whether no tool is pinned.
+func (t *ToolchainConfig) IsEmpty() bool {
+	return t == nil || (t.Go == "" && t.Node == "" && t.Dolt == "")
+}
+
+// validateToolchainConfig validates the version format of every pin.
+func validateToolchainConfig(t *ToolchainConfig) error {
+	for _, tool := range ToolchainTools {
+		if v := t.Get(tool); v != "" && !toolchainVersionRe.MatchString(v) {
+			return fmt.Errorf("%w: %s version %q", ErrInvalidToolchain, tool, v)
+		}
+	}
+	return nil
+}
+
+// ToolchainEnv returns the environment that applies a rig's toolchain pins.
+// Each pin is exported as GT_TOOLCHAIN_<TOOL> for build scripts and version
+// managers. A full Go version is also exported as GOTOOLCHAIN, so the go
+// command runs exactly that release (downloading it on first use).
+func ToolchainEnv(t *ToolchainConfig) map[string]string {
+	env := make(map[string]string)
+	for _, tool := range ToolchainTools {
+		if v := t.Get(tool); v != "" {
+			env["GT_TOOLCHAIN_"+strings.ToUpper(tool)] = v
+		}
+	}
+	if t != nil && strings.Count(t.Go, ".") == 2 {
+		env["GOTOOLCHAIN"] = "go" + t.Go
+	}
+	return env
+}
+
+// LoadRigToolchain returns the toolchain pins from a rig's settings, or nil
+// if the rig pins nothing.
+func LoadRigToolchain(rigPath string) *ToolchainConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Toolchain.IsEmpty() {
+		return nil
+	}
+	return settings.Toolchain
+}
+
+// toolchainForRole returns a rig's toolchain pins for the roles that build
+// its code (refinery, polecats, crew). Other roles run without them.
+func toolchainForRole(role, rigPath string) *ToolchainConfig {
+	if rigPath == "" {
+		return nil
+	}
+	switch role {
+	case "refinery", "polecat", "crew":
+		return LoadRigToolchain(rigPath)
+	}
+	return nil
+}
@@ -86,6 +86,32 @@ type TownSettings struct {
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
+
+	// Dolt records which Dolt server versions this town supports.
+	// Checked by 'gt doctor' (dolt-server-version).
+	Dolt *DoltVersionConfig `json:"dolt,omitempty"`
+
+	// Log sets per-module log levels for logs/gt.log: keys are module names
+	// ("doltserver", "mq", "doctor", "cmd", ...) or "default"; values are
+	// "debug", "info", "warn", "error" or "off". Default: info.
+	// Set with: gt config set log.doltserver=debug
+	Log map[string]string `json:"log,omitempty"`
+
+	// Aging sets, per priority, when listings (gt ready, gt mq list,
+	// gt refinery ready/blocked) color an item's age as aging or stale.
+	Aging *AgingConfig `json:"aging,omitempty"`
+
+	// RateLimits caps how fast each agent identity (BD_ACTOR) can write
+	// beads, protecting the shared Dolt server from runaway retry loops.
+	// See 'gt ratelimit'.
+	RateLimits *RateLimitConfig `json:"rate_limits,omitempty"`
+}
+
+// DoltVersionConfig bounds the Dolt server versions a town supports.
+// Empty bounds are unchecked.
+type DoltVersionConfig struct {
+	MinVersion string `json:"min_version,omitempty"` // e.g. "1.40.0"
+	MaxVersion string `json:"max_version,omitempty"` // e.g. "1.59.99"
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -182,6 +208,46 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// AgingConfig sets the age thresholds of listings' age bands. Keys are
+// priorities "P0" to "P4"; a priority or threshold left out keeps its
+// default (P0 4h/1d, P1 1d/3d, P2 3d/7d, P3 7d/30d, P4 14d/90d).
+type AgingConfig struct {
+	Priorities map[string]AgeThresholdsConfig `json:"priorities,omitempty"`
+}
+
+// AgeThresholdsConfig are the ages at which an item of one priority turns
+// aging and stale, as durations with day support (e.g. "12h", "3d").
+type AgeThresholdsConfig struct {
+	Aging string `json:"aging,omitempty"`
+	Stale string `json:"stale,omitempty"`
+}
+
+// RateLimitConfig sets the bead write quotas of agent identities. Each
+// identity has a token bucket: Burst writes at once, refilled at
+// PerMinute. A write beyond it waits for a token, up to MaxWait, then
+// fails. Writes without a BD_ACTOR (humans at a shell) are not limited.
+type RateLimitConfig struct {
+	// Disabled turns rate limiting off.
+	Disabled bool `json:"disabled,omitempty"`
+	// PerMinute is the sustained write rate per identity. Default: 300.
+	PerMinute int `json:"per_minute,omitempty"`
+	// Burst is how many writes an idle identity can make at once. Default: 100.
+	Burst int `json:"burst,omitempty"`
+	// MaxWait is how long a write waits for a token before failing. Default: "30s".
+	MaxWait string `json:"max_wait,omitempty"`
+	// Agents overrides the quota of matching identities. Keys are
+	// identities or path.Match patterns ("gastown/polecats/*"); the most
+	// specific match wins. A PerMinute of -1 exempts the identity.
+	Agents map[string]RateLimitQuota `json:"agents,omitempty"`
+}
+
+// RateLimitQuota is one identity's write quota. Zero fields keep the
+// town default.
+type RateLimitQuota struct {
+	PerMinute int `json:"per_minute,omitempty"`
+	Burst     int `json:"burst,omitempty"`
+}
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -323,6 +389,127 @@ type WorkflowConfig struct {
 	DefaultFormula string `json:"default_formula,omitempty"`
 }
 
+// ToolchainConfig pins the toolchain versions a rig is built and tested with
+// (gt rig pin). Versions may be partial: "1.22" accepts any 1.22.x.
+type ToolchainConfig struct {
+	Go   string `json:"go,omitempty"`
+	Node string `json:"node,omitempty"`
+	Dolt string `json:"dolt,omitempty"`
+}
+
+// AssignmentConfig auto-assigns new work beads in a rig. Rules are checked
+// in order and the first match wins; Default applies when none match.
+type AssignmentConfig struct {
+	Default string           `json:"default,omitempty"` // assignee when no rule matches
+	Rules   []AssignmentRule `json:"rules,omitempty"`   // first match wins
+}
+
+// AssignmentRule assigns beads matching every non-empty condition.
+type AssignmentRule struct {
+	Type     string `json:"type,omitempty"`  // issue type (e.g., "bug")
+	Label    string `json:"label,omitempty"` // required label (e.g., "infra")
+	Path     string `json:"path,omitempty"`  // glob on the creator's address (e.g., "gastown/polecats/*")
+	Assignee string `json:"assignee"`        // address to assign (e.g., "gastown/crew/max")
+}
+
+// DispatchConfig tunes how 'gt bead next' picks the next bead for a worker.
+type DispatchConfig struct {
+	WIPLimit        int            `json:"wip_limit,omitempty"`        // beads a worker may have in progress; default 1
+	RigWIPLimit     int            `json:"rig_wip_limit,omitempty"`    // beads in progress across the rig; 0 = no limit
+	LabelWeights    map[string]int `json:"label_weights,omitempty"`    // added to the score of beads with the label
+	SkipLabels      []string       `json:"skip_labels,omitempty"`      // never dispatch beads with these labels
+	StrictOwnership bool           `json:"strict_ownership,omitempty"` // skip beads owned by another team
+}
+
+// VerifyConfig gives beads of some types a verification phase: they can't
+// close until evidence of independent verification is attached ('gt bead
+// verify'). The refinery moves their merged work to the verify status
+// instead of closing it.
+type VerifyConfig struct {
+	Types map[string][]string `json:"types"` // bead type ("*" for any) → evidence kinds required: test, artifact, signoff
+}
+
+// StalenessConfig is a rig's SLA policies for aging beads, enforced by 'gt
+// bead sweep' (which the daemon runs). A bead that has been past a
+// policy's age gets the policy's action once, recorded in the staleness log.
+type StalenessConfig struct {
+	Policies []StalenessPolicy `json:"policies"`
+}
+
+// StalenessPolicy acts on beads matching every non-empty condition once
+// they are older than Age.
+type StalenessPolicy struct {
+	Name     string `json:"name"`               // identifies the policy in comments and the log
+	Status   string `json:"status,omitempty"`   // bead status; default "open"
+	Priority *int   `json:"priority,omitempty"` // beads at this priority or more urgent (0 = P0)
+	Type     string `json:"type,omitempty"`     // issue type (e.g., "bug")
+	Label    string `json:"label,omitempty"`    // required label
+	Age      string `json:"age"`                // e.g., "12h", "2d", "1w"
+	Since    string `json:"since,omitempty"`    // "created" (default) or "updated": what Age is measured from
+	Action   string `json:"action"`             // "escalate" or "reopen"
+	Severity string `json:"severity,omitempty"` // escalate: escalation severity; default from the bead's priority
+}
+
+// MirrorConfig publishes a read-only static mirror of a rig's open beads
+// (beads.json and index.html) for people without gt, e.g. to a directory an
+// internal web server serves. 'gt bead mirror' writes it; the daemon
+// refreshes it every Interval.
+type MirrorConfig struct {
+	Dir                 string   `json:"dir"`                            // output directory; relative paths are under the rig
+	Interval            string   `json:"interval,omitempty"`             // refresh period, e.g. "10m"; default 15m
+	IncludeDescriptions bool     `json:"include_descriptions,omitempty"` // publish descriptions (sealed values masked)
+	ExcludeLabels       []string `json:"exclude_labels,omitempty"`       // leave out beads with any of these labels
+}
+
+// LabelRegistry is the town-wide bead label taxonomy (settings/labels.json).
+// When it defines any labels or prefixes, new beads may only carry labels
+// it lists, labels under an allowed prefix, or Gas Town's own gt: labels.
+type LabelRegistry struct {
+	Type            string     `json:"type"`                       // "labels"
+	Version         int        `json:"version"`                    // schema version
+	Labels          []LabelDef `json:"labels,omitempty"`           // registered labels
+	AllowedPrefixes []string   `json:"allowed_prefixes,omitempty"` // e.g. "sprint-" accepts sprint-4, sprint-5, ...
+}
+
+// LabelDef is a registered label.
+type LabelDef struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"` // hex, e.g. "#d73a4a"
+	Description string `json:"description,omitempty"`
+}
+
+// CurrentLabelRegistryVersion is the current schema version for LabelRegistry.
+const CurrentLabelRegistryVersion = 1
+
+// OwnersConfig is the town's component ownership map (settings/owners.json),
+// in the spirit of CODEOWNERS: rules map file paths and bead labels to a
+// team or a single worker. It picks default MR reviewers, routes beads no
+// rig assignment rule claims, and groups the workload report by team.
+type OwnersConfig struct {
+	Type    string      `json:"type"`            // "owners"
+	Version int         `json:"version"`         // schema version
+	Teams   []TeamDef   `json:"teams,omitempty"` // named groups of workers
+	Rules   []OwnerRule `json:"rules,omitempty"` // last match wins, as in CODEOWNERS
+}
+
+// TeamDef is a named group of workers.
+type TeamDef struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members,omitempty"` // worker addresses (e.g., "gastown/crew/max")
+	Lead    string   `json:"lead,omitempty"`    // receives beads routed to the team; defaults to the only member
+}
+
+// OwnerRule gives the owner of files matching Path or beads carrying Label.
+// Exactly one of Path and Label is set.
+type OwnerRule struct {
+	Path  string `json:"path,omitempty"`  // CODEOWNERS-style pattern (e.g., "internal/mq/", "*.md", "/docs/**")
+	Label string `json:"label,omitempty"` // bead label (e.g., "frontend")
+	Owner string `json:"owner"`           // team name or worker address
+}
+
+// CurrentOwnersVersion is the current schema version for OwnersConfig.
+const CurrentOwnersVersion = 1
+
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
 	Type       string            `json:"type"`                  // "rig-settings"
@@ -332,6 +519,12 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Toolchain  *ToolchainConfig  `json:"toolchain,omitempty"`   // pinned toolchain versions
+	Assignment *AssignmentConfig `json:"assignment,omitempty"`  // new-bead assignment rules
+	Dispatch   *DispatchConfig   `json:"dispatch,omitempty"`    // 'gt bead next' ranking and WIP limits
+	Mirror     *MirrorConfig     `json:"mirror,omitempty"`      // read-only static mirror of open beads
+	Verify     *VerifyConfig     `json:"verify,omitempty"`      // evidence required before beads close
+	Staleness  *StalenessConfig  `json:"staleness,omitempty"`   // SLA policies for aging beads ('gt bead sweep')
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Evidence kinds a rig's verify settings can require before a bead closes.
+const (
+	EvidenceTest     = "test"     // Link to a passing test run
+	EvidenceArtifact = "artifact" // Attachment or link to a build artifact
+	EvidenceSignoff  = "signoff"  // Sign-off by someone other than the assignee
+)
+
+// EvidenceKinds lists the evidence kinds.
+var EvidenceKinds = []string{EvidenceTest, EvidenceArtifact, EvidenceSignoff}
+
+// Validate checks that every type requires known evidence kinds.
+func (v *VerifyConfig) Validate() error {
+	if v == nil {
+		return nil
+	}
+	types := make([]string, 0, len(v.Types))
+	for t := range v.Types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		for _, kind := range v.Types[t] {
+			if !slices.Contains(EvidenceKinds, kind) {
+				return fmt.Errorf("verify: type %s requires unknown evidence %q (expected %s)", t, kind, strings.Join(EvidenceKinds, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// Required returns the evidence kinds beads of issueType need before they
+// close; none if the type isn't verified. "*" applies to every type without
+// its own entry.
+func (v *VerifyConfig) Required(issueType string) []string {
+	if v == nil {
+		return nil
+	}
+	if kinds, ok := v.Types[issueType]; ok {
+		return kinds
+	}
+	return v.Types["*"]
+}
+
+// LoadRigVerify returns a rig's verify settings, or an empty config (no
+// verification) if the rig has none or its settings can't be read.
+func LoadRigVerify(rigPath string) *VerifyConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Verify == nil {
+		return &VerifyConfig{}
+	}
+	return settings.Verify
+}
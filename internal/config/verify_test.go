@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyConfig(t *testing.T) {
+	v := &VerifyConfig{Types: map[string][]string{
+		"feature": {EvidenceTest, EvidenceSignoff},
+		"*":       {EvidenceTest},
+	}}
+	if err := v.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(v.Required("feature"), ","); got != "test,signoff" {
+		t.Errorf("Required(feature) = %s", got)
+	}
+	if got := strings.Join(v.Required("bug"), ","); got != "test" {
+		t.Errorf("Required(bug) = %s, want the * entry", got)
+	}
+	if got := (*VerifyConfig)(nil).Required("bug"); got != nil {
+		t.Errorf("nil config requires %v", got)
+	}
+
+	v.Types["bug"] = []string{"vibes"}
+	if err := v.Validate(); err == nil || !strings.Contains(err.Error(), "vibes") {
+		t.Errorf("Validate = %v, want unknown evidence error", err)
+	}
+}
@@ -694,6 +694,7 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		AgentName:        name,
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.ClaudeConfigDir,
+		Toolchain:        config.LoadRigToolchain(m.rig.Path),
 	})
 	if opts.AgentOverride != "" {
 		envVars["GT_AGENT"] = opts.AgentOverride
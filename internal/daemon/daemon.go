@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -75,6 +76,31 @@ type Daemon struct {
 
 	// Restart tracking with exponential backoff to prevent crash loops
 	restartTracker *RestartTracker
+
+	// mqAlertsRunning is set while a background merge queue alert check
+	// runs, so a slow check is skipped rather than stacked.
+	mqAlertsRunning atomic.Bool
+
+	// leaseReapRunning is set while expired bead leases are being reaped.
+	leaseReapRunning atomic.Bool
+
+	// trashPurgeRunning is set while expired trashed beads are being purged.
+	trashPurgeRunning atomic.Bool
+
+	// mirrorRunning is set while bead mirrors are being refreshed.
+	mirrorRunning atomic.Bool
+
+	// stalenessSweepRunning is set while staleness policies are being enforced.
+	stalenessSweepRunning atomic.Bool
+
+	// mqArchiveRunning is set while closed merge requests are being archived.
+	mqArchiveRunning atomic.Bool
+
+	// metricsSampleRunning is set while the town metrics are being sampled.
+	metricsSampleRunning atomic.Bool
+
+	// workersReapRunning is set while dead workers are being reaped.
+	workersReapRunning atomic.Bool
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -291,6 +317,45 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start the merge queue alert ticker. Checks run in the background on
+	// their own cadence so slow bd queries never delay the heartbeat.
+	mqAlertsTicker := time.NewTicker(mqAlertsInterval)
+	defer mqAlertsTicker.Stop()
+
+	// Start the bead lease reaper, which returns beads whose claims have
+	// expired to the pool.
+	leaseReapTicker := time.NewTicker(leaseReapInterval)
+	defer leaseReapTicker.Stop()
+
+	// Start the trash purger, which permanently deletes beads that have been
+	// in the trash past the retention period.
+	trashPurgeTicker := time.NewTicker(trashPurgeInterval)
+	defer trashPurgeTicker.Stop()
+
+	// Start the bead mirror publisher, which refreshes each rig's static
+	// mirror once its interval has passed.
+	mirrorTicker := time.NewTicker(mirrorCheckInterval)
+	defer mirrorTicker.Stop()
+
+	// Start the staleness sweeper, which escalates or reopens beads past
+	// their rig's SLA policies.
+	stalenessSweepTicker := time.NewTicker(stalenessSweepInterval)
+	defer stalenessSweepTicker.Stop()
+
+	// Start the merge queue archiver, which moves long-closed MRs to Dolt.
+	mqArchiveTicker := time.NewTicker(mqArchiveInterval)
+	defer mqArchiveTicker.Stop()
+
+	// Start the metrics sampler, which records the history behind the
+	// trends in gt status and gt town metrics.
+	metricsSampleTicker := time.NewTicker(metricsSampleInterval)
+	defer metricsSampleTicker.Stop()
+
+	// Start the workers reaper, which releases the beads claimed by
+	// polecats and crew whose agent died.
+	workersReapTicker := time.NewTicker(workersReapInterval)
+	defer workersReapTicker.Stop()
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -328,6 +393,54 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-mqAlertsTicker.C:
+			// Merge queue throughput/wait-time alerts, off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startMQAlertsCheck()
+			}
+
+		case <-leaseReapTicker.C:
+			// Expired bead leases (gt bead claim), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startLeaseReap()
+			}
+
+		case <-trashPurgeTicker.C:
+			// Trashed beads past retention (gt bead delete), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startTrashPurge()
+			}
+
+		case <-mirrorTicker.C:
+			// Static bead mirrors (gt bead mirror), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startMirrorRefresh()
+			}
+
+		case <-stalenessSweepTicker.C:
+			// Stale beads (gt bead sweep), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startStalenessSweep()
+			}
+
+		case <-mqArchiveTicker.C:
+			// Closed merge requests (gt mq archive), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startMQArchive()
+			}
+
+		case <-metricsSampleTicker.C:
+			// Town metrics history (gt town metrics), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startMetricsSample()
+			}
+
+		case <-workersReapTicker.C:
+			// Beads claimed by dead workers (gt workers reap), off the heartbeat path.
+			if !d.isShutdownInProgress() {
+				d.startWorkersReap()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
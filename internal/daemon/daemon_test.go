@@ -1,11 +1,14 @@
 package daemon
 
 import (
+	"bytes"
 	"encoding/json"
+	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -402,3 +405,20 @@ func TestDaemon_StopsManagerAndScanner(t *testing.T) {
 		t.Fatal("Stop() did not complete within 5s")
 	}
 }
+
+func TestStartMQAlertsCheck_SkipsWhileRunning(t *testing.T) {
+	var buf bytes.Buffer
+	d := &Daemon{
+		config: &Config{TownRoot: t.TempDir()},
+		logger: log.New(&buf, "", 0),
+	}
+
+	d.mqAlertsRunning.Store(true)
+	d.startMQAlertsCheck()
+	if !strings.Contains(buf.String(), "still running") {
+		t.Errorf("expected skip while a check runs, log = %q", buf.String())
+	}
+	if !d.mqAlertsRunning.Load() {
+		t.Error("skipped check must not clear the running flag")
+	}
+}
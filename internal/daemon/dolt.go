@@ -127,6 +127,11 @@ type DoltServerManager struct {
 	escalated       bool          // Whether we've already escalated (avoid spamming)
 	restarting      bool          // Whether a restart is in progress (guards against concurrent restarts)
 
+	// wantRunningFn reports whether the server is expected to be up. When it
+	// returns false the server was stopped on purpose and is left alone.
+	// Nil means always (daemon-managed server).
+	wantRunningFn func() bool
+
 	// Test hooks (nil = use real implementations; set only in tests)
 	healthCheckFn      func() error
 	writeProbeCheckFn  func() error
@@ -169,6 +174,10 @@ func (m *DoltServerManager) doSleep(d time.Duration) {
 	time.Sleep(d)
 }
 
+func (m *DoltServerManager) wantRunning() bool {
+	return m.wantRunningFn == nil || m.wantRunningFn()
+}
+
 // pidFile returns the path to the Dolt server PID file.
 func (m *DoltServerManager) pidFile() string {
 	return filepath.Join(m.townRoot, "daemon", "dolt.pid")
@@ -349,6 +358,10 @@ func (m *DoltServerManager) EnsureRunning() error {
 		return m.checkHealth()
 	}
 
+	if !m.wantRunning() {
+		return nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -440,6 +453,10 @@ func (m *DoltServerManager) restartWithBackoff() error {
 			m.logger("Dolt server started by another goroutine during backoff, skipping")
 			return nil
 		}
+		if !m.wantRunning() {
+			m.logger("Dolt server stopped on purpose during backoff, skipping restart")
+			return nil
+		}
 	}
 
 	// Record this restart attempt
@@ -716,6 +733,18 @@ func (m *DoltServerManager) startLocked() error {
 		return nil
 	}
 
+	// Fail fast on a config the server cannot start with
+	check := &doltserver.Config{
+		TownRoot: m.townRoot,
+		Host:     m.config.Host,
+		Port:     m.config.Port,
+		DataDir:  m.config.DataDir,
+		LogFile:  m.config.LogFile,
+	}
+	if err := check.Validate(); err != nil {
+		return err
+	}
+
 	// Ensure data directory exists
 	if err := os.MkdirAll(m.config.DataDir, 0755); err != nil {
 		return fmt.Errorf("creating data directory: %w", err)
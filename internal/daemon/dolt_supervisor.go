@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultDoltSuperviseInterval is how often 'gt dolt supervise' checks the server.
+const DefaultDoltSuperviseInterval = 10 * time.Second
+
+// NewSupervisedDoltServerManager returns a DoltServerManager for the server
+// started by 'gt dolt start' (rather than one owned by the daemon). It shares
+// the daemon's health checks, backoff and restart cap, but starts the server
+// through doltserver.Start and only restarts it while the town expects it to
+// be running (doltserver State.Running), so 'gt dolt stop' is never undone.
+//
+// Restarts and crashes are appended to doltserver.RestartLogFile and
+// published to the town event log.
+func NewSupervisedDoltServerManager(townRoot string) *DoltServerManager {
+	srv := doltserver.DefaultConfig(townRoot)
+	config := DefaultDoltServerConfig(townRoot)
+	config.Enabled = true
+	config.Host = srv.Host
+	if config.Host == "" {
+		config.Host = "127.0.0.1"
+	}
+	config.Port = srv.Port
+	config.User = srv.User
+	config.Password = srv.Password
+	config.DataDir = srv.DataDir
+	config.LogFile = srv.LogFile
+	config.RestartDelay = 2 * time.Second
+
+	m := NewDoltServerManager(townRoot, config, restartLogger(townRoot))
+	m.wantRunningFn = func() bool {
+		state, err := doltserver.LoadState(townRoot)
+		return err == nil && state.Running
+	}
+	m.runningFn = func() (int, bool) {
+		running, pid, err := doltserver.IsRunning(townRoot)
+		if err != nil {
+			return 0, false
+		}
+		return pid, running
+	}
+	m.startFn = func() error {
+		payload := map[string]interface{}{}
+		err := doltserver.Start(townRoot)
+		if err != nil {
+			m.logger("restart failed: %v", err)
+			payload["error"] = err.Error()
+		} else {
+			_, pid, _ := doltserver.IsRunning(townRoot)
+			m.logger("restart succeeded (PID %d)", pid)
+			payload["pid"] = pid
+		}
+		_ = events.Publish(townRoot, events.SourceDolt, events.TypeDoltRestarted, "doltserver/supervisor", payload, events.VisibilityBoth)
+		return err
+	}
+	m.crashAlertFn = func(pid int) {
+		_ = events.Publish(townRoot, events.SourceDolt, events.TypeDoltCrashed, "doltserver/supervisor", map[string]interface{}{
+			"pid": pid,
+		}, events.VisibilityBoth)
+	}
+	return m
+}
+
+// RunDoltSupervisor checks the town's Dolt server every interval until ctx
+// is done, restarting it if it dies. Only one supervisor runs per town; it
+// fails if another holds the lock.
+func RunDoltSupervisor(ctx context.Context, townRoot string, interval time.Duration) error {
+	if config := doltserver.DefaultConfig(townRoot); config.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — it cannot be supervised from here", config.HostPort())
+	}
+
+	pidFile := doltserver.SupervisorPidFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0755); err != nil {
+		return fmt.Errorf("creating daemon directory: %w", err)
+	}
+	fileLock := flock.New(filepath.Join(filepath.Dir(pidFile), "dolt-supervisor.lock"))
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return fmt.Errorf("acquiring supervisor lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("a Dolt supervisor is already running")
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return fmt.Errorf("writing supervisor PID file: %w", err)
+	}
+	defer func() { _ = os.Remove(pidFile) }()
+
+	m := NewSupervisedDoltServerManager(townRoot)
+	m.logger("supervisor started (PID %d)", os.Getpid())
+	defer m.logger("supervisor stopped")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := m.EnsureRunning(); err != nil {
+			m.logger("%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// restartLogger returns a logger that appends timestamped lines to the
+// town's Dolt restart log.
+func restartLogger(townRoot string) func(format string, v ...interface{}) {
+	return func(format string, v ...interface{}) {
+		path := doltserver.RestartLogFile(townRoot)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		_, _ = fmt.Fprintf(f, "%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, v...))
+	}
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func newWantRunningTestManager(t *testing.T, want *bool, starts *int) *DoltServerManager {
+	t.Helper()
+	config := DefaultDoltServerConfig(t.TempDir())
+	config.Enabled = true
+	m := NewDoltServerManager(t.TempDir(), config, func(string, ...interface{}) {})
+	m.wantRunningFn = func() bool { return *want }
+	m.runningFn = func() (int, bool) { return 0, false }
+	m.startFn = func() error { *starts++; return nil }
+	m.sleepFn = func(time.Duration) {}
+	return m
+}
+
+func TestEnsureRunning_LeavesDeliberateStopAlone(t *testing.T) {
+	want, starts := false, 0
+	m := newWantRunningTestManager(t, &want, &starts)
+
+	if err := m.EnsureRunning(); err != nil {
+		t.Fatal(err)
+	}
+	if starts != 0 {
+		t.Error("restarted a server stopped with gt dolt stop")
+	}
+
+	want = true
+	if err := m.EnsureRunning(); err != nil {
+		t.Fatal(err)
+	}
+	if starts != 1 {
+		t.Errorf("starts = %d, want 1 once the server should be running", starts)
+	}
+}
+
+func TestEnsureRunning_StoppedDuringBackoff(t *testing.T) {
+	want, starts := true, 0
+	m := newWantRunningTestManager(t, &want, &starts)
+	m.sleepFn = func(time.Duration) { want = false } // gt dolt stop ran while we waited
+
+	if err := m.EnsureRunning(); err != nil {
+		t.Fatal(err)
+	}
+	if starts != 0 {
+		t.Error("restarted a server stopped during the backoff")
+	}
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// leaseReapInterval is how often expired bead leases are reaped. Leases
+	// last hours, so a few minutes' lag returning a bead is fine.
+	leaseReapInterval = 5 * time.Minute
+	leaseReapTimeout  = 2 * time.Minute
+)
+
+// startLeaseReap runs reapExpiredLeases in the background unless a previous
+// run is still going.
+func (d *Daemon) startLeaseReap() {
+	if !d.leaseReapRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("lease reaper: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.leaseReapRunning.Store(false)
+		d.reapExpiredLeases()
+	}()
+}
+
+// reapExpiredLeases runs gt bead leases --reap for each rig, returning beads
+// whose claim has expired to the pool.
+func (d *Daemon) reapExpiredLeases() {
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, leaseReapTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "bead", "leases", "--reap", "--json", "--rig", rigName) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: lease reap failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
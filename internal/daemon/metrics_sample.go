@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// metricsSampleInterval is how often the town metrics history is
+	// sampled for trends (gt town metrics). A day is 288 samples, plenty
+	// for a sparkline.
+	metricsSampleInterval = 5 * time.Minute
+	metricsSampleTimeout  = 2 * time.Minute
+)
+
+// startMetricsSample runs sampleMetrics in the background unless a previous
+// sample is still being taken.
+func (d *Daemon) startMetricsSample() {
+	if !d.metricsSampleRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("metrics sample: previous sample still running, skipping")
+		return
+	}
+	go func() {
+		defer d.metricsSampleRunning.Store(false)
+		d.sampleMetrics()
+	}()
+}
+
+// sampleMetrics runs gt town metrics sample, which records queue depth,
+// bead counts, Dolt sizes and agent counts and prunes old samples.
+func (d *Daemon) sampleMetrics() {
+	ctx, cancel := context.WithTimeout(d.ctx, metricsSampleTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "town", "metrics", "sample") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Warning: metrics sample failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+}
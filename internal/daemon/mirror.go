@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const (
+	// mirrorCheckInterval is how often rigs are checked for a due mirror.
+	// Each rig's mirror.interval sets how often it is actually refreshed.
+	mirrorCheckInterval = 5 * time.Minute
+	mirrorTimeout       = 2 * time.Minute
+)
+
+// startMirrorRefresh runs refreshMirrors in the background unless a
+// previous run is still going.
+func (d *Daemon) startMirrorRefresh() {
+	if !d.mirrorRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("bead mirror: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.mirrorRunning.Store(false)
+		d.refreshMirrors()
+	}()
+}
+
+// refreshMirrors runs gt bead mirror for each rig that publishes a mirror
+// older than its interval.
+func (d *Daemon) refreshMirrors() {
+	now := time.Now()
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		cfg := config.LoadRigMirror(rigPath)
+		if cfg == nil {
+			continue
+		}
+		interval, err := cfg.IntervalDuration()
+		if err != nil {
+			d.logger.Printf("Warning: bead mirror for %s: %v", rigName, err)
+			continue
+		}
+		if !mirrorDue(filepath.Join(cfg.OutputDir(rigPath), beads.MirrorJSONFile), interval, now) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, mirrorTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "bead", "mirror", "--rig", rigName) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: bead mirror failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
+
+// mirrorDue reports whether the mirror file at path is missing or at least
+// interval old.
+func mirrorDue(path string, interval time.Duration, now time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	return now.Sub(info.ModTime()) >= interval
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+const (
+	// mqAlertsInterval is how often merge queue alerts are checked. Alert
+	// windows are measured in hours, so this need not track the heartbeat.
+	mqAlertsInterval = 10 * time.Minute
+	mqAlertsTimeout  = 2 * time.Minute
+)
+
+// startMQAlertsCheck runs checkMQAlerts in the background unless a previous
+// check is still running.
+func (d *Daemon) startMQAlertsCheck() {
+	if !d.mqAlertsRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("mq alerts: previous check still running, skipping")
+		return
+	}
+	go func() {
+		defer d.mqAlertsRunning.Store(false)
+		d.checkMQAlerts()
+	}()
+}
+
+// checkMQAlerts runs gt mq alerts for each rig with an mq-alerts.json.
+// Breaches are filed as escalations by gt mq alerts (which handles cooldowns).
+func (d *Daemon) checkMQAlerts() {
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		if _, err := os.Stat(mq.AlertsPath(rigPath)); err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(d.ctx, mqAlertsTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "mq", "alerts", rigName) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: mq alerts check failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
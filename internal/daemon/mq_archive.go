@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// mqArchiveInterval is how often closed merge requests are moved to the
+	// rigs' Dolt archive tables. gt mq archive only takes MRs closed over a
+	// week ago, so once a day keeps the queue small.
+	mqArchiveInterval = 24 * time.Hour
+	mqArchiveTimeout  = 10 * time.Minute
+)
+
+// startMQArchive runs archiveMergeRequests in the background unless a
+// previous run is still going.
+func (d *Daemon) startMQArchive() {
+	if !d.mqArchiveRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("mq archive: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.mqArchiveRunning.Store(false)
+		d.archiveMergeRequests()
+	}()
+}
+
+// archiveMergeRequests runs gt mq archive for each rig, moving merge
+// requests closed over a week ago out of the live queue.
+func (d *Daemon) archiveMergeRequests() {
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, mqArchiveTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "mq", "archive", rigName, "--json") //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: mq archive failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
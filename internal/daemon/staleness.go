@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const (
+	// stalenessSweepInterval is how often rigs' staleness policies are
+	// enforced. Policies measure ages in hours or days, so a quarter-hour
+	// lag is fine.
+	stalenessSweepInterval = 15 * time.Minute
+	stalenessSweepTimeout  = 5 * time.Minute
+)
+
+// startStalenessSweep runs sweepStaleBeads in the background unless a
+// previous run is still going.
+func (d *Daemon) startStalenessSweep() {
+	if !d.stalenessSweepRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("staleness sweep: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.stalenessSweepRunning.Store(false)
+		d.sweepStaleBeads()
+	}()
+}
+
+// sweepStaleBeads runs gt bead sweep for each rig with staleness policies,
+// escalating or reopening beads that have been left too long.
+func (d *Daemon) sweepStaleBeads() {
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		if config.LoadRigStaleness(filepath.Join(d.config.TownRoot, rigName)) == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, stalenessSweepTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "bead", "sweep", "--json", "--rig", rigName) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: staleness sweep failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// trashPurgeInterval is how often beads past the trash retention period
+	// are purged. Retention is measured in days, so hourly is plenty.
+	trashPurgeInterval = time.Hour
+	trashPurgeTimeout  = 5 * time.Minute
+)
+
+// startTrashPurge runs purgeTrash in the background unless a previous run
+// is still going.
+func (d *Daemon) startTrashPurge() {
+	if !d.trashPurgeRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("trash purge: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.trashPurgeRunning.Store(false)
+		d.purgeTrash()
+	}()
+}
+
+// purgeTrash runs gt bead trash purge for each rig, permanently deleting
+// beads that have been in the trash for longer than the retention period.
+func (d *Daemon) purgeTrash() {
+	for _, rigName := range d.getKnownRigs() {
+		if d.ctx.Err() != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(d.ctx, trashPurgeTimeout)
+		cmd := exec.CommandContext(ctx, d.gtPath, "bead", "trash", "purge", "--json", "--rig", rigName) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		output, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			d.logger.Printf("Warning: trash purge failed for %s: %v: %s", rigName, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
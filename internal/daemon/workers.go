@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// workersReapInterval is how often dead workers' claimed beads are
+	// released. A worker is only reaped 10 minutes after its last
+	// heartbeat, so a few more minutes' lag is fine.
+	workersReapInterval = 5 * time.Minute
+	workersReapTimeout  = 2 * time.Minute
+)
+
+// startWorkersReap runs reapDeadWorkers in the background unless a previous
+// run is still going.
+func (d *Daemon) startWorkersReap() {
+	if !d.workersReapRunning.CompareAndSwap(false, true) {
+		d.logger.Printf("workers reaper: previous run still going, skipping")
+		return
+	}
+	go func() {
+		defer d.workersReapRunning.Store(false)
+		d.reapDeadWorkers()
+	}()
+}
+
+// reapDeadWorkers runs gt workers reap, which releases the beads claimed
+// by workers whose agent died and drops them from the registry.
+func (d *Daemon) reapDeadWorkers() {
+	ctx, cancel := context.WithTimeout(d.ctx, workersReapTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "workers", "reap", "--json") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	output, err := cmd.Output()
+	if err != nil {
+		d.logger.Printf("Warning: workers reap failed: %v", err)
+		return
+	}
+	var releases []struct {
+		Address  string   `json:"address"`
+		Released []string `json:"released"`
+		Errors   []string `json:"errors"`
+	}
+	if err := json.Unmarshal(output, &releases); err != nil {
+		d.logger.Printf("Warning: parsing workers reap output: %v", err)
+		return
+	}
+	for _, r := range releases {
+		if len(r.Errors) > 0 {
+			d.logger.Printf("Warning: reaping worker %s: %s", r.Address, strings.Join(r.Errors, "; "))
+		} else {
+			d.logger.Printf("Reaped dead worker %s (released: %s)", r.Address, strings.Join(r.Released, ", "))
+		}
+	}
+}
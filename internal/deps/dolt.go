@@ -0,0 +1,178 @@
+package deps
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+)
+
+// MinDoltVersion is the oldest Dolt release this Gas Town release supports.
+// Older servers lack SQL features the beads schema and gt dolt rely on.
+// Towns can raise it (never lower it) with dolt.min_version in settings.
+const MinDoltVersion = "1.40.0"
+
+// DoltInstallCommand returns the command that installs or upgrades Dolt
+// on goos to the latest release.
+func DoltInstallCommand(goos string) string {
+	switch goos {
+	case "darwin":
+		return "brew install dolt"
+	case "windows":
+		return "choco install dolt"
+	default:
+		return "sudo bash -c 'curl -L https://github.com/dolthub/dolt/releases/latest/download/install.sh | bash'"
+	}
+}
+
+// DoltAdvisory describes a range of Dolt releases known to misbehave under
+// Gas Town. A version is affected if Introduced <= version < Fixed; an empty
+// Fixed means no release has fixed it yet.
+type DoltAdvisory struct {
+	Introduced string `json:"introduced"`
+	Fixed      string `json:"fixed,omitempty"`
+	Summary    string `json:"summary"`
+	Link       string `json:"link,omitempty"`
+}
+
+// Affects reports whether the advisory applies to a Dolt version.
+func (a DoltAdvisory) Affects(version string) bool {
+	if compareVersions(version, a.Introduced) < 0 {
+		return false
+	}
+	return a.Fixed == "" || compareVersions(version, a.Fixed) < 0
+}
+
+// String describes the advisory for check output, e.g.
+// "Breaks ready queries (affects 1.2.0, fixed in 1.2.3) https://...".
+func (a DoltAdvisory) String() string {
+	s := fmt.Sprintf("%s (affects %s", a.Summary, a.Introduced)
+	if a.Fixed != "" {
+		s += fmt.Sprintf(", fixed in %s)", a.Fixed)
+	} else {
+		s += " and later)"
+	}
+	if a.Link != "" {
+		s += " " + a.Link
+	}
+	return s
+}
+
+// DescribeAdvisories returns one String line per advisory.
+func DescribeAdvisories(advisories []DoltAdvisory) []string {
+	lines := make([]string, 0, len(advisories))
+	for _, a := range advisories {
+		lines = append(lines, a.String())
+	}
+	return lines
+}
+
+// doltAdvisoriesJSON is the advisory list shipped with this Gas Town release.
+// Add an entry when a Dolt release is found to break beads queries, e.g.:
+//
+//	{"introduced": "1.2.0", "fixed": "1.2.3", "summary": "...", "link": "https://..."}
+//
+//go:embed dolt_advisories.json
+var doltAdvisoriesJSON []byte
+
+// DoltAdvisories returns the embedded list of known-bad Dolt versions.
+func DoltAdvisories() ([]DoltAdvisory, error) {
+	var advisories []DoltAdvisory
+	if err := json.Unmarshal(doltAdvisoriesJSON, &advisories); err != nil {
+		return nil, fmt.Errorf("parsing embedded Dolt advisories: %w", err)
+	}
+	return advisories, nil
+}
+
+// DoltVersionReport is the result of checking a Dolt version against
+// town requirements and known advisories.
+type DoltVersionReport struct {
+	Version    string
+	TooOld     bool // Below the required minimum
+	TooNew     bool // Above the allowed maximum
+	Advisories []DoltAdvisory
+}
+
+// OK reports whether the version meets every requirement.
+func (r *DoltVersionReport) OK() bool {
+	return !r.TooOld && !r.TooNew && len(r.Advisories) == 0
+}
+
+// CheckDoltVersion compares a Dolt version against an optional minimum and
+// maximum (empty means unbounded) and the given advisories.
+func CheckDoltVersion(version, minVersion, maxVersion string, advisories []DoltAdvisory) *DoltVersionReport {
+	report := &DoltVersionReport{Version: version}
+	if minVersion != "" && compareVersions(version, minVersion) < 0 {
+		report.TooOld = true
+	}
+	if maxVersion != "" && compareVersions(version, maxVersion) > 0 {
+		report.TooNew = true
+	}
+	for _, a := range advisories {
+		if a.Affects(version) {
+			report.Advisories = append(report.Advisories, a)
+		}
+	}
+	return report
+}
+
+// SupportedDoltRange returns the effective Dolt version bounds given a
+// town's configured bounds: the town minimum applies only when it is
+// stricter than MinDoltVersion. An empty maximum is unbounded.
+func SupportedDoltRange(townMin, townMax string) (string, string) {
+	minVersion := MinDoltVersion
+	if townMin != "" && compareVersions(townMin, minVersion) > 0 {
+		minVersion = townMin
+	}
+	return minVersion, townMax
+}
+
+// InstalledDoltVersion runs 'dolt version' and returns the installed
+// release, e.g. "1.43.1".
+func InstalledDoltVersion() (string, error) {
+	path, err := exec.LookPath("dolt")
+	if err != nil {
+		return "", fmt.Errorf("dolt not found in PATH")
+	}
+
+	// Timeout guards against broken installs that hang.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("'dolt version' failed: %w", err)
+	}
+	version := parseDoltVersion(string(output))
+	if version == "" {
+		return "", fmt.Errorf("could not parse dolt version from %q", string(output))
+	}
+	return version, nil
+}
+
+// CheckInstalledDolt checks the installed dolt binary against the supported
+// range (see SupportedDoltRange) and the embedded advisories.
+func CheckInstalledDolt(townMin, townMax string) (*DoltVersionReport, error) {
+	version, err := InstalledDoltVersion()
+	if err != nil {
+		return nil, err
+	}
+	advisories, err := DoltAdvisories()
+	if err != nil {
+		return nil, err
+	}
+	minVersion, maxVersion := SupportedDoltRange(townMin, townMax)
+	return CheckDoltVersion(version, minVersion, maxVersion, advisories), nil
+}
+
+// parseDoltVersion extracts the version from "dolt version X.Y.Z" output,
+// which may be followed by an upgrade notice.
+func parseDoltVersion(output string) string {
+	re := regexp.MustCompile(`dolt version (\d+\.\d+\.\d+)`)
+	if m := re.FindStringSubmatch(output); len(m) >= 2 {
+		return m[1]
+	}
+	return ""
+}
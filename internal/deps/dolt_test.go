@@ -0,0 +1,81 @@
+package deps
+
+import "testing"
+
+func TestDoltAdvisories_Embedded(t *testing.T) {
+	advisories, err := DoltAdvisories()
+	if err != nil {
+		t.Fatalf("embedded advisories do not parse: %v", err)
+	}
+	if len(advisories) == 0 {
+		t.Fatal("embedded advisory list is empty")
+	}
+	for _, a := range advisories {
+		if a.Summary == "" || a.Introduced == "" {
+			t.Errorf("advisory %+v needs introduced and summary", a)
+		}
+		if a.Fixed != "" && compareVersions(a.Fixed, a.Introduced) <= 0 {
+			t.Errorf("advisory %+v: fixed must be after introduced", a)
+		}
+	}
+	// Releases below the minimum carry an explanation.
+	if r := CheckDoltVersion("1.39.0", "", "", advisories); len(r.Advisories) == 0 {
+		t.Error("no advisory covers releases below MinDoltVersion")
+	}
+}
+
+func TestCheckDoltVersion(t *testing.T) {
+	advisories := []DoltAdvisory{
+		{Introduced: "1.40.0", Fixed: "1.40.2", Summary: "fixed bug"},
+		{Introduced: "1.50.0", Summary: "open bug"},
+	}
+
+	tests := []struct {
+		version, min, max string
+		tooOld, tooNew    bool
+		advisories        int
+	}{
+		{"1.39.0", "", "", false, false, 0},
+		{"1.40.0", "", "", false, false, 1},
+		{"1.40.1", "", "", false, false, 1},
+		{"1.40.2", "", "", false, false, 0},
+		{"1.51.3", "", "", false, false, 1},
+		{"1.30.0", "1.35.0", "", true, false, 0},
+		{"1.45.0", "1.35.0", "1.44.9", false, true, 0},
+		{"1.44.9", "1.35.0", "1.44.9", false, false, 0},
+	}
+	for _, tt := range tests {
+		r := CheckDoltVersion(tt.version, tt.min, tt.max, advisories)
+		if r.TooOld != tt.tooOld || r.TooNew != tt.tooNew || len(r.Advisories) != tt.advisories {
+			t.Errorf("CheckDoltVersion(%q, %q, %q) = %+v", tt.version, tt.min, tt.max, r)
+		}
+		if r.OK() != (!tt.tooOld && !tt.tooNew && tt.advisories == 0) {
+			t.Errorf("OK() wrong for %q", tt.version)
+		}
+	}
+}
+
+func TestParseDoltVersion(t *testing.T) {
+	tests := map[string]string{
+		"dolt version 1.43.1\n": "1.43.1",
+		"dolt version 1.81.0\nWarning: you are on an old version of Dolt. The newest version is 1.82.0.\n": "1.81.0",
+		"dolt: command not found": "",
+	}
+	for output, want := range tests {
+		if got := parseDoltVersion(output); got != want {
+			t.Errorf("parseDoltVersion(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
+
+func TestSupportedDoltRange(t *testing.T) {
+	if lo, hi := SupportedDoltRange("", ""); lo != MinDoltVersion || hi != "" {
+		t.Errorf("defaults = %q, %q", lo, hi)
+	}
+	if lo, _ := SupportedDoltRange("1.0.0", ""); lo != MinDoltVersion {
+		t.Errorf("town minimum below MinDoltVersion should not lower it, got %q", lo)
+	}
+	if lo, hi := SupportedDoltRange("99.0.0", "99.9.9"); lo != "99.0.0" || hi != "99.9.9" {
+		t.Errorf("stricter town bounds = %q, %q", lo, hi)
+	}
+}
@@ -0,0 +1,69 @@
+package deps
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// toolVersionCommands are the commands that report each pinnable tool's
+// version. go runs under the caller's env, so a GOTOOLCHAIN pin is honored.
+var toolVersionCommands = map[string][]string{
+	"go":   {"go", "env", "GOVERSION"},
+	"node": {"node", "--version"},
+	"dolt": {"dolt", "version"},
+}
+
+var toolVersionRe = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?`)
+
+// ToolVersion returns the version of a toolchain tool ("go", "node", "dolt")
+// as resolved under env (nil = the current environment).
+func ToolVersion(tool string, env []string) (string, error) {
+	args, ok := toolVersionCommands[tool]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", tool)
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "", fmt.Errorf("%s not found in PATH", args[0])
+	}
+
+	// Generous timeout: a GOTOOLCHAIN pin may download a Go release
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // G204: args are fixed per tool
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	version := toolVersionRe.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("could not parse %s version from %q", tool, strings.TrimSpace(string(out)))
+	}
+	return version, nil
+}
+
+// VersionMatches reports whether an installed version satisfies a pin. A
+// pin matches every version it is a prefix of, component-wise: "1.22"
+// matches 1.22.0 and 1.22.3 but not 1.2.2 or 1.23.0. Missing components in
+// the installed version count as zero (Go 1.20 reports itself as "1.20").
+func VersionMatches(pin, installed string) bool {
+	want := strings.Split(pin, ".")
+	have := strings.Split(installed, ".")
+	for i, w := range want {
+		h := "0"
+		if i < len(have) {
+			h = have[i]
+		}
+		wn, err1 := strconv.Atoi(w)
+		hn, err2 := strconv.Atoi(h)
+		if err1 != nil || err2 != nil || wn != hn {
+			return false
+		}
+	}
+	return true
+}
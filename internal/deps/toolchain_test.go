@@ -0,0 +1,43 @@
+package deps
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		pin, installed string
+		want           bool
+	}{
+		{"1.22", "1.22.3", true},
+		{"1.22.3", "1.22.3", true},
+		{"1.22", "1.2.2", false},
+		{"1.22", "1.23.0", false},
+		{"1.22.3", "1.22.4", false},
+		{"1.20.0", "1.20", true},
+		{"20", "20.11.0", true},
+		{"1.22.3", "1.22", false},
+	}
+	for _, tt := range tests {
+		if got := VersionMatches(tt.pin, tt.installed); got != tt.want {
+			t.Errorf("VersionMatches(%q, %q) = %v, want %v", tt.pin, tt.installed, got, tt.want)
+		}
+	}
+}
+
+func TestToolVersion(t *testing.T) {
+	if _, err := ToolVersion("rust", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not in PATH")
+	}
+	v, err := ToolVersion("go", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !toolVersionRe.MatchString(v) || v != toolVersionRe.FindString(v) {
+		t.Errorf("ToolVersion(go) = %q", v)
+	}
+}
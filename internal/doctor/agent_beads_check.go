@@ -21,6 +21,8 @@ import (
 // Each rig uses its configured prefix (e.g., "gt-" for gastown, "bd-" for beads).
 type AgentBeadsCheck struct {
 	FixableCheck
+	missing      []string // Agent bead IDs not found (cached for PreviewFix)
+	missingLabel []string // Agent bead IDs lacking the gt:agent label
 }
 
 // NewAgentBeadsCheck creates a new agent beads check.
@@ -31,6 +33,7 @@ func NewAgentBeadsCheck() *AgentBeadsCheck {
 				CheckName:        "agent-beads-exist",
 				CheckDescription: "Verify agent beads exist for all agents",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"dolt-metadata", "beads-custom-types"},
 			},
 		},
 	}
@@ -96,6 +99,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 
 	checkAgentBead(townBd, deaconID)
 	checkAgentBead(townBd, mayorID)
+	c.missing, c.missingLabel = missing, missingLabel
 
 	if len(prefixToRig) == 0 {
 		// No rigs to check, but we still checked global agents
@@ -137,6 +141,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 			checkAgentBead(bd, crewID)
 		}
 	}
+	c.missing, c.missingLabel = missing, missingLabel
 
 	if len(missing) == 0 && len(missingLabel) == 0 {
 		return &CheckResult{
@@ -272,6 +277,18 @@ func (c *AgentBeadsCheck) Fix(ctx *CheckContext) error {
 	return errors.Join(errs...)
 }
 
+// PreviewFix lists the agent beads Fix would create or label.
+func (c *AgentBeadsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, id := range c.missing {
+		planned = append(planned, "create agent bead "+id)
+	}
+	for _, id := range c.missingLabel {
+		planned = append(planned, "add gt:agent label to "+id)
+	}
+	return planned
+}
+
 // addLabelToBead adds a label to an existing bead via bd update.
 func addLabelToBead(townRoot, id, label string) error {
 	cmd := exec.Command("bd", "update", id, "--add-label="+label)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -241,6 +242,40 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the rigs.json prefixes Fix would update to match routes.jsonl.
+func (c *PrefixMismatchCheck) PreviewFix(ctx *CheckContext) []string {
+	routes, err := beads.LoadRoutes(filepath.Join(ctx.TownRoot, ".beads"))
+	if err != nil || len(routes) == 0 {
+		return nil
+	}
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	rigsConfig, err := loadRigsConfig(rigsPath)
+	if err != nil {
+		return nil
+	}
+	routePrefixByPath := make(map[string]string)
+	for _, r := range routes {
+		routePrefixByPath[r.Path] = strings.TrimSuffix(r.Prefix, "-")
+	}
+
+	var planned []string
+	for rigName, rigEntry := range rigsConfig.Rigs {
+		routePrefix, hasRoute := routePrefixByPath[determineRigBeadsPath(ctx.TownRoot, rigName)]
+		if !hasRoute {
+			continue
+		}
+		current := ""
+		if rigEntry.BeadsConfig != nil {
+			current = rigEntry.BeadsConfig.Prefix
+		}
+		if current != routePrefix {
+			planned = append(planned, fmt.Sprintf("set %s prefix in %s: %q -> %q", rigName, rigsPath, current, routePrefix))
+		}
+	}
+	sort.Strings(planned)
+	return planned
+}
+
 // rigsConfigEntry is a local type for loading rigs.json without importing config package
 // to avoid circular dependencies and keep the check self-contained.
 type rigsConfigEntry struct {
@@ -328,6 +363,7 @@ func NewRoleLabelCheck() *RoleLabelCheck {
 				CheckName:        "role-bead-labels",
 				CheckDescription: "Check that role beads have gt:role label",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"dolt-metadata"},
 			},
 		},
 		labelAdder: &realLabelAdder{},
@@ -421,6 +457,15 @@ func (c *RoleLabelCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the role beads Fix would label.
+func (c *RoleLabelCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, roleID := range c.missingLabel {
+		planned = append(planned, "add gt:role label to "+roleID)
+	}
+	return planned
+}
+
 // DatabasePrefixCheck detects when a rig's database has a different issue_prefix
 // than what routes.jsonl specifies. This can happen when:
 // - The database was initialized with a different prefix
@@ -448,6 +493,7 @@ func NewDatabasePrefixCheck() *DatabasePrefixCheck {
 				CheckName:        "database-prefix",
 				CheckDescription: "Check rig database issue_prefix matches routes.jsonl",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"dolt-metadata"},
 			},
 		},
 	}
@@ -578,3 +624,13 @@ func (c *DatabasePrefixCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix lists the bd config commands Fix would run.
+func (c *DatabasePrefixCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, m := range c.mismatches {
+		planned = append(planned, fmt.Sprintf("bd config set issue_prefix %s (in %s, was %s)",
+			m.routesPrefix, filepath.Join(ctx.TownRoot, m.rigPath), m.dbPrefix))
+	}
+	return planned
+}
@@ -0,0 +1,134 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// BeadsFsckCheck runs the beads integrity pass (gt beads fsck) against the
+// town and every routed rig database. It exports each database in full, so
+// it is opt-in: gt doctor registers it only with --fsck.
+type BeadsFsckCheck struct {
+	FixableCheck
+	found []fsckFinding // Cached by Run for Fix and PreviewFix
+}
+
+type fsckFinding struct {
+	workDir string
+	problem beads.FsckProblem
+}
+
+// NewBeadsFsckCheck creates a new beads integrity check.
+func NewBeadsFsckCheck() *BeadsFsckCheck {
+	return &BeadsFsckCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "beads-fsck",
+				CheckDescription: "Detect dangling relations, MR links and attachments in beads",
+				CheckCategory:    CategoryCleanup,
+			},
+		},
+	}
+}
+
+// CacheTTL lets doctor reuse an integrity pass for a few hours; exporting
+// every database is the slowest thing doctor does.
+func (c *BeadsFsckCheck) CacheTTL() time.Duration {
+	return 6 * time.Hour
+}
+
+// Run checks each beads database for references that no longer resolve.
+func (c *BeadsFsckCheck) Run(ctx *CheckContext) *CheckResult {
+	c.found = nil
+
+	var details []string
+	var failed []string
+	for _, dir := range fsckWorkDirs(ctx.TownRoot) {
+		report, err := beads.New(dir).Fsck()
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		for _, p := range report.Problems {
+			c.found = append(c.found, fsckFinding{workDir: dir, problem: p})
+			details = append(details, fmt.Sprintf("%s: %s", p.IssueID, p.Detail))
+		}
+	}
+
+	if len(c.found) == 0 && len(failed) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No dangling bead references found",
+		}
+	}
+	if len(c.found) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not check %d beads database(s)", len(failed)),
+			Details: failed,
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d bead integrity problem(s)", len(c.found)),
+		Details: append(details, failed...),
+		FixHint: "Run 'gt doctor --fsck --fix' to remove dangling references (duplicates need manual review)",
+	}
+}
+
+// Fix removes the dangling references found by Run.
+func (c *BeadsFsckCheck) Fix(ctx *CheckContext) error {
+	var lastErr error
+	for _, f := range c.found {
+		if !f.problem.Fixable {
+			continue
+		}
+		if err := beads.New(f.workDir).FsckFix(f.problem); err != nil {
+			lastErr = fmt.Errorf("%s: %w", f.problem.IssueID, err)
+		}
+	}
+	return lastErr
+}
+
+// PreviewFix lists the repairs Fix would make.
+func (c *BeadsFsckCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, f := range c.found {
+		if f.problem.Fixable {
+			planned = append(planned, beads.FsckFixAction(f.problem))
+		}
+	}
+	return planned
+}
+
+// fsckWorkDirs returns the town root and each rig beads location from
+// routes.jsonl, deduplicated.
+func fsckWorkDirs(townRoot string) []string {
+	seen := map[string]bool{townRoot: true}
+	dirs := []string{townRoot}
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return dirs
+	}
+	var rigDirs []string
+	for _, r := range routes {
+		if r.Path == "" || r.Path == "." {
+			continue
+		}
+		dir := filepath.Join(townRoot, r.Path)
+		if !seen[dir] {
+			seen[dir] = true
+			rigDirs = append(rigDirs, dir)
+		}
+	}
+	sort.Strings(rigDirs)
+	return append(dirs, rigDirs...)
+}
@@ -0,0 +1,144 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// BeadsSyncHooksCheck verifies that every clone of a rig using the beads
+// sync git hooks (gt hooks install beads-sync) has them, at the current
+// version. Rigs where no clone has them are left alone: the hooks are
+// opt-in.
+type BeadsSyncHooksCheck struct {
+	FixableCheck
+	stale []string // Clones with missing or outdated hooks
+}
+
+// NewBeadsSyncHooksCheck creates a new beads sync hooks check.
+func NewBeadsSyncHooksCheck() *BeadsSyncHooksCheck {
+	return &BeadsSyncHooksCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "beads-sync-hooks",
+				CheckDescription: "Check beads sync git hooks are present and current",
+				CheckCategory:    CategoryHooks,
+			},
+		},
+	}
+}
+
+// Run checks the sync hooks of every clone in rigs that use them.
+func (c *BeadsSyncHooksCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+	var details []string
+	checked := 0
+
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		type cloneState struct {
+			path   string
+			states map[string]string
+			err    error
+		}
+		var clones []cloneState
+		inUse := false
+		for _, clonePath := range findRigClones(rigPath) {
+			states, err := beads.CheckSyncHooks(clonePath)
+			for _, s := range states {
+				if s != beads.SyncHookMissing {
+					inUse = true
+				}
+			}
+			clones = append(clones, cloneState{clonePath, states, err})
+		}
+		if !inUse {
+			continue
+		}
+
+		for _, cl := range clones {
+			checked++
+			rel, _ := filepath.Rel(ctx.TownRoot, cl.path)
+			if rel == "" {
+				rel = cl.path
+			}
+			if cl.err != nil {
+				if errors.Is(cl.err, beads.ErrSyncHooksBypassed) {
+					details = append(details, fmt.Sprintf("%s: hooks bypassed, %v", rel, cl.err))
+				} else {
+					details = append(details, fmt.Sprintf("%s: %v", rel, cl.err))
+				}
+				continue
+			}
+			var bad []string
+			for _, name := range beads.SyncHookNames {
+				if s := cl.states[name]; s != beads.SyncHookCurrent {
+					bad = append(bad, name+" "+s)
+				}
+			}
+			if len(bad) > 0 {
+				c.stale = append(c.stale, cl.path)
+				details = append(details, fmt.Sprintf("%s: %s", rel, strings.Join(bad, ", ")))
+			}
+		}
+	}
+
+	if len(details) == 0 {
+		msg := "Not installed in any rig"
+		if checked > 0 {
+			msg = fmt.Sprintf("All %d clone(s) have current hooks", checked)
+		}
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  msg,
+			Category: c.Category(),
+		}
+	}
+
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Message:  fmt.Sprintf("%d of %d clone(s) without current beads sync hooks", len(details), checked),
+		Details:  details,
+		FixHint:  "Run 'gt doctor --fix' or 'gt hooks install beads-sync --all-rigs'",
+		Category: c.Category(),
+	}
+}
+
+// Fix installs the current hooks in clones with missing or outdated ones.
+func (c *BeadsSyncHooksCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, clonePath := range c.stale {
+		if _, err := beads.InstallSyncHooks(clonePath, false); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", clonePath, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// PreviewFix lists the clones whose hooks Fix would write.
+func (c *BeadsSyncHooksCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, clonePath := range c.stale {
+		dir, err := beads.SyncHooksDir(clonePath)
+		if err != nil {
+			continue
+		}
+		results, err := beads.InstallSyncHooks(clonePath, true)
+		if err != nil {
+			continue
+		}
+		for _, name := range beads.SyncHookNames {
+			if r := results[name]; r != beads.SyncHookCurrent {
+				planned = append(planned, fmt.Sprintf("write %s (%s)", filepath.Join(dir, name), r))
+			}
+		}
+	}
+	return planned
+}
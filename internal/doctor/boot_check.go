@@ -43,6 +43,14 @@ func (c *BootHealthCheck) Fix(ctx *CheckContext) error {
 	return b.EnsureDir()
 }
 
+// PreviewFix describes the boot directory Fix would create.
+func (c *BootHealthCheck) PreviewFix(ctx *CheckContext) []string {
+	if !c.missingDir {
+		return nil
+	}
+	return []string{"create " + boot.New(ctx.TownRoot).Dir()}
+}
+
 // Run checks Boot health: directory, session, status, and marker freshness.
 func (c *BootHealthCheck) Run(ctx *CheckContext) *CheckResult {
 	b := boot.New(ctx.TownRoot)
@@ -117,6 +117,15 @@ func (c *BranchCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the checkouts Fix would switch back to main.
+func (c *BranchCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, dir := range c.offMainDirs {
+		planned = append(planned, fmt.Sprintf("git checkout main && git pull --rebase (in %s)", dir))
+	}
+	return planned
+}
+
 // isExpectedBranch checks if a directory is on the expected branch.
 // For rigs with a custom default_branch, that branch is expected.
 // Otherwise, main or master are expected.
@@ -0,0 +1,123 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CacheableCheck is implemented by expensive checks (Dolt integrity, remote
+// reachability) whose results stay valid for a while. Doctor reuses a cached
+// result younger than CacheTTL instead of re-running the check, so doctor
+// can run on every agent start without re-paying slow validation.
+type CacheableCheck interface {
+	CacheTTL() time.Duration
+}
+
+// cachedResult is a check result persisted between doctor runs.
+type cachedResult struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	Details   []string  `json:"details,omitempty"`
+	FixHint   string    `json:"fix_hint,omitempty"`
+	Category  string    `json:"category,omitempty"`
+}
+
+// checkCache holds cached results keyed by check name and rig scope.
+type checkCache struct {
+	path    string
+	entries map[string]cachedResult
+	dirty   bool
+}
+
+// CacheFile returns where cached check results are stored for a town.
+func CacheFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "doctor-cache.json")
+}
+
+// loadCheckCache reads the cache file. A missing or corrupt cache is empty.
+func loadCheckCache(townRoot string) *checkCache {
+	cache := &checkCache{path: CacheFile(townRoot), entries: make(map[string]cachedResult)}
+	data, err := os.ReadFile(cache.path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil || cache.entries == nil {
+		cache.entries = make(map[string]cachedResult)
+	}
+	return cache
+}
+
+func cacheKey(ctx *CheckContext, name string) string {
+	if ctx.RigName == "" {
+		return name
+	}
+	return ctx.RigName + "/" + name
+}
+
+// lookup returns a copy of the cached result for check if it is still fresh.
+func (c *checkCache) lookup(ctx *CheckContext, check Check, now time.Time) (*CheckResult, bool) {
+	cc, ok := check.(CacheableCheck)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := c.entries[cacheKey(ctx, check.Name())]
+	if !ok || now.Sub(entry.CheckedAt) >= cc.CacheTTL() {
+		return nil, false
+	}
+	return &CheckResult{
+		Name:     check.Name(),
+		Status:   parseCheckStatus(entry.Status),
+		Message:  entry.Message,
+		Details:  entry.Details,
+		FixHint:  entry.FixHint,
+		Category: entry.Category,
+		CachedAt: entry.CheckedAt,
+	}, true
+}
+
+// store records a freshly computed result for a cacheable check.
+func (c *checkCache) store(ctx *CheckContext, check Check, result *CheckResult, now time.Time) {
+	if _, ok := check.(CacheableCheck); !ok {
+		return
+	}
+	c.entries[cacheKey(ctx, check.Name())] = cachedResult{
+		CheckedAt: now,
+		Status:    result.Status.String(),
+		Message:   result.Message,
+		Details:   result.Details,
+		FixHint:   result.FixHint,
+		Category:  result.Category,
+	}
+	c.dirty = true
+}
+
+// save writes the cache back if anything changed. Errors are ignored: a
+// cache that can't be written only costs the next run some time.
+func (c *checkCache) save() {
+	if !c.dirty {
+		return
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path, data, 0644) //nolint:gosec // G306: diagnostics, not secret
+	c.dirty = false
+}
+
+func parseCheckStatus(s string) CheckStatus {
+	switch s {
+	case StatusWarning.String():
+		return StatusWarning
+	case StatusError.String():
+		return StatusError
+	default:
+		return StatusOK
+	}
+}
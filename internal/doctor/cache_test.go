@@ -0,0 +1,119 @@
+package doctor
+
+import (
+	"testing"
+	"time"
+)
+
+// cacheableMockCheck counts runs and opts into result caching.
+type cacheableMockCheck struct {
+	mockCheck
+	ttl  time.Duration
+	runs int
+}
+
+func (c *cacheableMockCheck) Run(ctx *CheckContext) *CheckResult {
+	c.runs++
+	return c.mockCheck.Run(ctx)
+}
+
+func (c *cacheableMockCheck) CacheTTL() time.Duration {
+	return c.ttl
+}
+
+func newCacheableMockCheck(name string, status CheckStatus, ttl time.Duration) *cacheableMockCheck {
+	return &cacheableMockCheck{mockCheck: *newMockCheck(name, status), ttl: ttl}
+}
+
+func TestDoctor_Run_ReusesCachedResult(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	check := newCacheableMockCheck("slow", StatusWarning, time.Hour)
+	d := NewDoctor()
+	d.Register(check)
+
+	d.Run(ctx)
+	report := d.Run(ctx)
+
+	if check.runs != 1 {
+		t.Fatalf("runs = %d, want 1 (second run should hit cache)", check.runs)
+	}
+	got := report.Checks[0]
+	if got.Status != StatusWarning || got.Message != "mock result" {
+		t.Errorf("cached result = %v %q, want Warning %q", got.Status, got.Message, "mock result")
+	}
+	if got.CachedAt.IsZero() {
+		t.Error("CachedAt not set on cached result")
+	}
+}
+
+func TestDoctor_Run_NoCache(t *testing.T) {
+	townRoot := t.TempDir()
+	check := newCacheableMockCheck("slow", StatusOK, time.Hour)
+	d := NewDoctor()
+	d.Register(check)
+
+	d.Run(&CheckContext{TownRoot: townRoot})
+	report := d.Run(&CheckContext{TownRoot: townRoot, NoCache: true})
+
+	if check.runs != 2 {
+		t.Errorf("runs = %d, want 2 with NoCache", check.runs)
+	}
+	if !report.Checks[0].CachedAt.IsZero() {
+		t.Error("fresh result should not have CachedAt set")
+	}
+}
+
+func TestDoctor_Run_CacheExpires(t *testing.T) {
+	townRoot := t.TempDir()
+	check := newCacheableMockCheck("slow", StatusOK, time.Nanosecond)
+	d := NewDoctor()
+	d.Register(check)
+
+	d.Run(&CheckContext{TownRoot: townRoot})
+	time.Sleep(time.Millisecond)
+	d.Run(&CheckContext{TownRoot: townRoot})
+
+	if check.runs != 2 {
+		t.Errorf("runs = %d, want 2 after TTL expiry", check.runs)
+	}
+}
+
+func TestDoctor_Run_CacheScopedByRig(t *testing.T) {
+	townRoot := t.TempDir()
+	check := newCacheableMockCheck("slow", StatusOK, time.Hour)
+	d := NewDoctor()
+	d.Register(check)
+
+	d.Run(&CheckContext{TownRoot: townRoot})
+	d.Run(&CheckContext{TownRoot: townRoot, RigName: "gastown"})
+
+	if check.runs != 2 {
+		t.Errorf("runs = %d, want 2 (rig scope must not share cache)", check.runs)
+	}
+}
+
+func TestDoctor_Fix_BypassesCache(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := &CheckContext{TownRoot: townRoot}
+	check := newCacheableMockCheck("slow", StatusError, time.Hour)
+	check.fixable = true
+	d := NewDoctor()
+	d.Register(check)
+
+	d.Run(ctx)
+	report := d.Fix(ctx)
+	if check.fixCount != 1 {
+		t.Fatalf("fixCount = %d, want 1", check.fixCount)
+	}
+	if !report.Checks[0].Fixed {
+		t.Error("expected check to be fixed")
+	}
+
+	// The fixed result refreshes the cache.
+	report = d.Run(ctx)
+	if report.Checks[0].Status != StatusOK {
+		t.Errorf("status after fix = %v, want OK", report.Checks[0].Status)
+	}
+}
@@ -771,6 +771,27 @@ func (c *ClaudeSettingsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the stale settings files Fix would delete and recreate.
+// Tracked files are reported as skipped, as Fix does.
+func (c *ClaudeSettingsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, sf := range c.staleSettings {
+		if (!sf.wrongLocation && len(sf.missing) == 0) || sf.missingFile {
+			continue
+		}
+		switch {
+		case sf.gitStatus == gitStatusTrackedModified || sf.gitStatus == gitStatusTrackedClean:
+			planned = append(planned, fmt.Sprintf("skip %s (tracked in git, needs manual review)", sf.path))
+		case sf.agentType == "mayor" && !strings.Contains(sf.path, "/mayor/"):
+			planned = append(planned, fmt.Sprintf("delete %s and recreate mayor settings in %s",
+				sf.path, filepath.Join(ctx.TownRoot, "mayor", ".claude")))
+		default:
+			planned = append(planned, fmt.Sprintf("delete %s and recreate %s settings", sf.path, sf.agentType))
+		}
+	}
+	return planned
+}
+
 // fileExists checks if a file exists.
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
@@ -66,3 +66,12 @@ func (c *CommandsCheck) Fix(ctx *CheckContext) error {
 
 	return templates.ProvisionCommands(c.townRoot)
 }
+
+// PreviewFix lists the slash commands Fix would provision.
+func (c *CommandsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, name := range c.missingCommands {
+		planned = append(planned, fmt.Sprintf("provision /%s in %s", name, c.townRoot))
+	}
+	return planned
+}
@@ -96,6 +96,15 @@ func (c *SettingsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the settings directories Fix would create.
+func (c *SettingsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, path := range c.missingSettings {
+		planned = append(planned, "create "+path)
+	}
+	return planned
+}
+
 // RuntimeGitignoreCheck verifies .runtime/ is gitignored at town and rig levels.
 type RuntimeGitignoreCheck struct {
 	BaseCheck
@@ -185,9 +194,11 @@ func (c *RuntimeGitignoreCheck) findRigs(townRoot string) []string {
 }
 
 // LegacyGastownCheck warns if old .gastown/ directories still exist.
+// .gastown/secrets/ (the secrets store, see gt secret) is current, not
+// legacy, and is left alone.
 type LegacyGastownCheck struct {
 	FixableCheck
-	legacyDirs []string // Cached during Run for use in Fix
+	legacyPaths []string // Cached during Run for use in Fix
 }
 
 // NewLegacyGastownCheck creates a new legacy gastown check.
@@ -203,35 +214,50 @@ func NewLegacyGastownCheck() *LegacyGastownCheck {
 	}
 }
 
+// legacyGastownPaths returns what is legacy in a .gastown/ directory: the
+// directory itself, or only its entries when it holds the secrets store.
+func legacyGastownPaths(dir string) []string {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "secrets")); err != nil {
+		return []string{dir}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.Name() != "secrets" {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths
+}
+
 // Run checks for legacy .gastown/ directories.
 func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 	var found []string
+	c.legacyPaths = nil
 
-	// Check town-level .gastown/
-	townGastown := filepath.Join(ctx.TownRoot, ".gastown")
-	if info, err := os.Stat(townGastown); err == nil && info.IsDir() {
-		found = append(found, ".gastown/ (town root)")
+	// Check town-level .gastown/, then each rig's
+	dirs := []string{filepath.Join(ctx.TownRoot, ".gastown")}
+	for _, rig := range c.findRigs(ctx.TownRoot) {
+		dirs = append(dirs, filepath.Join(rig, ".gastown"))
 	}
-
-	// Check each rig for .gastown/
-	rigs := c.findRigs(ctx.TownRoot)
-	for _, rig := range rigs {
-		rigGastown := filepath.Join(rig, ".gastown")
-		if info, err := os.Stat(rigGastown); err == nil && info.IsDir() {
-			relPath, _ := filepath.Rel(ctx.TownRoot, rig)
-			found = append(found, fmt.Sprintf("%s/.gastown/", relPath))
+	for _, dir := range dirs {
+		paths := legacyGastownPaths(dir)
+		if len(paths) == 0 {
+			continue
 		}
-	}
-
-	// Cache for Fix
-	c.legacyDirs = nil
-	if info, err := os.Stat(townGastown); err == nil && info.IsDir() {
-		c.legacyDirs = append(c.legacyDirs, townGastown)
-	}
-	for _, rig := range rigs {
-		rigGastown := filepath.Join(rig, ".gastown")
-		if info, err := os.Stat(rigGastown); err == nil && info.IsDir() {
-			c.legacyDirs = append(c.legacyDirs, rigGastown)
+		c.legacyPaths = append(c.legacyPaths, paths...)
+		relPath, _ := filepath.Rel(ctx.TownRoot, dir)
+		if relPath == ".gastown" {
+			found = append(found, ".gastown/ (town root)")
+		} else {
+			found = append(found, relPath+"/")
 		}
 	}
 
@@ -252,16 +278,25 @@ func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
-// Fix removes legacy .gastown/ directories.
+// Fix removes legacy .gastown/ directories, keeping the secrets store.
 func (c *LegacyGastownCheck) Fix(ctx *CheckContext) error {
-	for _, dir := range c.legacyDirs {
-		if err := os.RemoveAll(dir); err != nil {
-			return fmt.Errorf("failed to remove %s: %w", dir, err)
+	for _, path := range c.legacyPaths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
 		}
 	}
 	return nil
 }
 
+// PreviewFix lists the legacy paths Fix would remove.
+func (c *LegacyGastownCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, path := range c.legacyPaths {
+		planned = append(planned, "remove "+path)
+	}
+	return planned
+}
+
 // findRigs returns rig directories within the town.
 func (c *LegacyGastownCheck) findRigs(townRoot string) []string {
 	return findAllRigs(townRoot)
@@ -345,6 +380,16 @@ func (c *SessionHookCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the settings files whose gt prime hooks Fix would
+// rewrite to pass --hook.
+func (c *SessionHookCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, path := range c.filesToFix {
+		planned = append(planned, "add --hook to gt prime hooks in "+path)
+	}
+	return planned
+}
+
 // fixSettingsFile updates a single settings.json file.
 func (c *SessionHookCheck) fixSettingsFile(path string) error {
 	// Read file
@@ -616,6 +661,7 @@ func NewCustomTypesCheck() *CustomTypesCheck {
 				CheckName:        "beads-custom-types",
 				CheckDescription: "Check that Gas Town custom types are registered with beads",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"dolt-metadata"},
 			},
 		},
 	}
@@ -725,3 +771,8 @@ func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix describes the bd config command Fix would run.
+func (c *CustomTypesCheck) PreviewFix(ctx *CheckContext) []string {
+	return []string{fmt.Sprintf("bd config set types.custom %s (in %s)", constants.BeadsCustomTypes, c.townRoot)}
+}
@@ -450,3 +450,33 @@ func TestCustomTypesCheck_ParsesOutputWithNotePrefix(t *testing.T) {
 		t.Errorf("After parsing, missing types: %v", missing)
 	}
 }
+
+func TestLegacyGastownCheck_KeepsSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	legacy := filepath.Join(tmpDir, ".gastown")
+	if err := os.MkdirAll(filepath.Join(legacy, "secrets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &CheckContext{TownRoot: tmpDir}
+	check := NewLegacyGastownCheck()
+
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("secrets store alone reported as legacy: %+v", result)
+	}
+
+	if err := os.WriteFile(filepath.Join(legacy, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusWarning {
+		t.Fatalf("legacy config not reported: %+v", result)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "config.json")); !os.IsNotExist(err) {
+		t.Error("legacy config not removed")
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "secrets")); err != nil {
+		t.Errorf("secrets store removed: %v", err)
+	}
+}
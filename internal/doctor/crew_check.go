@@ -173,6 +173,15 @@ func (c *CrewStateCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the state files Fix would rewrite.
+func (c *CrewStateCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, ic := range c.invalidCrews {
+		planned = append(planned, fmt.Sprintf("write %s for %s/%s", ic.stateFile, ic.rigName, ic.crewName))
+	}
+	return planned
+}
+
 type crewDir struct {
 	path     string
 	rigName  string
@@ -295,6 +304,15 @@ func (c *CrewWorktreeCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the worktrees Fix would remove.
+func (c *CrewWorktreeCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, wt := range c.staleWorktrees {
+		planned = append(planned, "git worktree remove --force "+wt.path)
+	}
+	return planned
+}
+
 // findCrewWorktrees finds cross-rig worktrees in crew directories.
 // These are worktrees with hyphenated names (e.g., "beads-dave") that
 // indicate they were created via `gt worktree` for cross-rig work.
@@ -91,6 +91,11 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the daemon Fix would start.
+func (c *DaemonCheck) PreviewFix(ctx *CheckContext) []string {
+	return []string{"start gt daemon run (in " + ctx.TownRoot + ")"}
+}
+
 // itoa is a simple int to string helper
 func itoa(i int) string {
 	if i == 0 {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/steveyegge/gastown/internal/config"
 )
@@ -86,6 +88,21 @@ func (c *DeprecatedMergeQueueKeysCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the keys Fix would remove from each settings file.
+func (c *DeprecatedMergeQueueKeysCheck) PreviewFix(ctx *CheckContext) []string {
+	paths := make([]string, 0, len(c.affectedFiles))
+	for path := range c.affectedFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var planned []string
+	for _, path := range paths {
+		planned = append(planned, fmt.Sprintf("rewrite %s: remove merge_queue.%s",
+			path, strings.Join(c.affectedFiles[path], ", merge_queue.")))
+	}
+	return planned
+}
+
 // findDeprecatedKeys reads a settings file and returns any deprecated merge_queue keys found.
 func findDeprecatedKeys(path string) []string {
 	data, err := os.ReadFile(path)
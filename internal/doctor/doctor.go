@@ -3,14 +3,21 @@ package doctor
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/ui"
 )
 
+// logger is the doctor module's logger (gt config set log.doctor=debug).
+var logger = logging.For("doctor")
+
 // Doctor manages and executes health checks.
 type Doctor struct {
-	checks []Check
+	checks   []Check
+	suppress *Suppressions
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
@@ -30,6 +37,22 @@ func (d *Doctor) RegisterAll(checks ...Check) {
 	d.checks = append(d.checks, checks...)
 }
 
+// Filter keeps only the registered checks for which keep returns true.
+func (d *Doctor) Filter(keep func(Check) bool) {
+	kept := d.checks[:0]
+	for _, c := range d.checks {
+		if keep(c) {
+			kept = append(kept, c)
+		}
+	}
+	d.checks = kept
+}
+
+// SetSuppressions sets the known-acceptable warnings to hide from results.
+func (d *Doctor) SetSuppressions(s *Suppressions) {
+	d.suppress = s
+}
+
 // Checks returns the list of registered checks.
 func (d *Doctor) Checks() []Check {
 	return d.checks
@@ -40,6 +63,66 @@ type categoryGetter interface {
 	Category() string
 }
 
+// dependenciesOf returns the names of the checks check depends on.
+func dependenciesOf(check Check) []string {
+	if dep, ok := check.(Dependent); ok {
+		return dep.DependsOn()
+	}
+	return nil
+}
+
+// OrderByDependencies returns checks ordered so that every check comes after
+// the checks it depends on, otherwise keeping registration order.
+// Dependencies on checks that aren't registered (e.g., filtered out by
+// --category) are ignored. A dependency cycle is reported as an error; the
+// checks in it keep their registration order.
+func OrderByDependencies(checks []Check) ([]Check, error) {
+	index := make(map[string]int, len(checks))
+	for i, c := range checks {
+		index[c.Name()] = i
+	}
+	// pending[i] counts the unordered checks i depends on
+	pending := make([]int, len(checks))
+	dependents := make([][]int, len(checks))
+	for i, c := range checks {
+		for _, name := range dependenciesOf(c) {
+			if j, ok := index[name]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	ordered := make([]Check, 0, len(checks))
+	placed := make([]bool, len(checks))
+	for len(ordered) < len(checks) {
+		// Take the earliest registered check that is ready
+		next := -1
+		for i := range checks {
+			if !placed[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, c := range checks {
+				if !placed[i] {
+					cycle = append(cycle, c.Name())
+					ordered = append(ordered, c)
+				}
+			}
+			return ordered, fmt.Errorf("doctor check dependency cycle among: %s", strings.Join(cycle, ", "))
+		}
+		placed[next] = true
+		ordered = append(ordered, checks[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+	return ordered, nil
+}
+
 // Run executes all registered checks and returns a report.
 func (d *Doctor) Run(ctx *CheckContext) *Report {
 	return d.RunStreaming(ctx, nil, 0)
@@ -49,7 +132,15 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
+	return d.runStreaming(ctx, w, slowThreshold, !ctx.NoCache)
+}
+
+// runStreaming is RunStreaming with explicit control over reading cached
+// results. Fresh results of cacheable checks are always written back.
+func (d *Doctor) runStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration, useCache bool) *Report {
 	report := NewReport()
+	cache := loadCheckCache(ctx.TownRoot)
+	defer cache.save()
 
 	for _, check := range d.checks {
 		// Stream: print check name before running
@@ -58,17 +149,29 @@ func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 		}
 
 		start := time.Now()
-		result := check.Run(ctx)
+		var result *CheckResult
+		cached := false
+		if useCache {
+			result, cached = cache.lookup(ctx, check, start)
+		}
+		if !cached {
+			result = check.Run(ctx)
+		}
 		result.Elapsed = time.Since(start)
 
 		// Ensure check name is populated
 		if result.Name == "" {
 			result.Name = check.Name()
 		}
+		logger.Debug("check", "name", result.Name, "status", result.Status.String(), "elapsed", result.Elapsed, "cached", cached)
 		// Set category from check if available
 		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
 			result.Category = cg.Category()
 		}
+		if !cached {
+			cache.store(ctx, check, result, start)
+		}
+		result = d.suppress.Apply(ctx, result)
 
 		// Stream: overwrite line with result
 		if w != nil {
@@ -95,6 +198,9 @@ func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 			if isSlow {
 				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
 			}
+			if cached {
+				fmt.Fprintf(w, "%s", ui.RenderMuted(" (cached "+formatDuration(time.Since(result.CachedAt))+" ago)"))
+			}
 			fmt.Fprintln(w)
 		}
 
@@ -113,10 +219,23 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 // FixStreaming runs all checks with auto-fix and optional real-time output.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
+//
+// Checks run in dependency order (see Dependent), so a check is only run,
+// and fixed, once the fixes it relies on have been applied. A check whose
+// dependency still fails is not fixed.
+//
+// Fixes act on live state, so cached results are never reused here; the
+// final results of cacheable checks still refresh the cache.
 func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
 	report := NewReport()
+	cache := loadCheckCache(ctx.TownRoot)
+	defer cache.save()
 
-	for _, check := range d.checks {
+	// A cycle is a bug in the checks; fix in registration order rather than not at all
+	checks, _ := OrderByDependencies(d.checks)
+	failing := make(map[string]bool)
+
+	for _, check := range checks {
 		// Stream: print check name before running
 		if w != nil {
 			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
@@ -131,9 +250,22 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
 			result.Category = cg.Category()
 		}
+		// Suppressed warnings are known and acceptable: don't fix them
+		result = d.suppress.Apply(ctx, result)
+
+		// Don't fix on top of a dependency that is still broken
+		var blockedBy []string
+		for _, name := range dependenciesOf(check) {
+			if failing[name] {
+				blockedBy = append(blockedBy, name)
+			}
+		}
+		if result.Status != StatusOK && check.CanFix() && len(blockedBy) > 0 {
+			result.Details = append(result.Details, "Fix skipped: depends on "+strings.Join(blockedBy, ", ")+", which still fails")
+		}
 
 		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
+		if result.Status != StatusOK && check.CanFix() && len(blockedBy) == 0 {
 			// Stream: show the problem with fixing indicator (all on same line)
 			if w != nil {
 				var problemIcon string
@@ -151,6 +283,7 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 			}
 
 			err := check.Fix(ctx)
+			logger.Info("fix", "check", check.Name(), "err", err)
 			if err == nil {
 				// Re-run check to verify fix worked
 				result = check.Run(ctx)
@@ -161,6 +294,7 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
 					result.Category = cg.Category()
 				}
+				result = d.suppress.Apply(ctx, result)
 				// Update message to indicate fix was applied
 				if result.Status == StatusOK {
 					result.Message = result.Message + " (fixed)"
@@ -170,10 +304,14 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 				// Fix failed, add error to details
 				result.Details = append(result.Details, "Fix failed: "+err.Error())
 			}
+			publishFixEvent(ctx, check.Name(), result, err)
 		}
 
+		failing[check.Name()] = result.Status != StatusOK
+
 		// Record total elapsed time including any fix attempts
 		result.Elapsed = time.Since(start)
+		cache.store(ctx, check, result, time.Now())
 
 		// Stream: overwrite line with final result
 		if w != nil {
@@ -217,12 +355,70 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 	return report
 }
 
+// publishFixEvent records an attempted auto-fix in the town event log.
+// Without a town root there is no event log to write to; don't let the bus
+// fall back to whatever town the working directory happens to be in.
+func publishFixEvent(ctx *CheckContext, name string, result *CheckResult, fixErr error) {
+	if ctx.TownRoot == "" {
+		return
+	}
+	payload := map[string]interface{}{
+		"check":  name,
+		"fixed":  result.Fixed,
+		"status": result.Status.String(),
+	}
+	if fixErr != nil {
+		payload["error"] = fixErr.Error()
+	}
+	if ctx.RigName != "" {
+		payload["rig"] = ctx.RigName
+	}
+	_ = events.Publish(ctx.TownRoot, events.SourceDoctor, events.TypeDoctorFix, "doctor", payload, events.VisibilityAudit)
+}
+
+// FixDryRun previews fixes without applying them.
+func (d *Doctor) FixDryRun(ctx *CheckContext) *Report {
+	return d.FixDryRunStreaming(ctx, nil, 0)
+}
+
+// FixDryRunStreaming runs all checks and, for each failing fixable check,
+// records the changes its Fix would make in CheckResult.PlannedFixes.
+// Nothing is mutated. All built-in fixable checks implement FixPreviewer;
+// any other check gets a generic entry so operators still see that a fix
+// would run. Checks are
+// always run fresh since PreviewFix relies on state captured by Run.
+func (d *Doctor) FixDryRunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
+	report := d.runStreaming(ctx, w, slowThreshold, false)
+
+	// RunStreaming adds one result per check, in registration order.
+	for i, check := range d.checks {
+		result := report.Checks[i]
+		if result.Status == StatusOK || !check.CanFix() {
+			continue
+		}
+		var planned []string
+		if p, ok := check.(FixPreviewer); ok {
+			planned = p.PreviewFix(ctx)
+		} else {
+			planned = []string{"would run automatic fix: " + check.Description()}
+		}
+		if len(planned) == 0 {
+			continue
+		}
+		result.PlannedFixes = planned
+		report.Summary.WouldFix++
+	}
+
+	return report
+}
+
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
 // Embed this in custom checks to get default CanFix() and Fix() implementations.
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string   // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string // Checks whose fixes must be applied first (see Dependent)
 }
 
 // Category returns the check's category for grouping in output.
@@ -230,6 +426,11 @@ func (b *BaseCheck) Category() string {
 	return b.CheckCategory
 }
 
+// DependsOn returns the checks this check's fix relies on (see Dependent).
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
+}
+
 // Name returns the check name.
 func (b *BaseCheck) Name() string {
 	return b.CheckName
@@ -2,6 +2,8 @@ package doctor
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -330,6 +332,139 @@ func TestDoctor_Fix(t *testing.T) {
 	}
 }
 
+func checkNames(checks []Check) []string {
+	names := make([]string, len(checks))
+	for i, c := range checks {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	a := newMockCheck("a", StatusOK)
+	b := newMockCheck("b", StatusOK)
+	b.CheckDependsOn = []string{"c", "not-registered"}
+	c := newMockCheck("c", StatusOK)
+	c.CheckDependsOn = []string{"a"}
+	d := newMockCheck("d", StatusOK)
+
+	ordered, err := OrderByDependencies([]Check{b, a, d, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(checkNames(ordered), ","), "a,d,c,b"; got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+
+	// A cycle is reported, and every check is still returned.
+	a.CheckDependsOn = []string{"b"}
+	ordered, err = OrderByDependencies([]Check{b, a, d, c})
+	if err == nil {
+		t.Error("expected a cycle error")
+	}
+	if got, want := strings.Join(checkNames(ordered), ","), "d,b,a,c"; got != want {
+		t.Errorf("order with cycle = %s, want %s", got, want)
+	}
+}
+
+func TestDoctor_FixDependencyOrder(t *testing.T) {
+	d := NewDoctor()
+	// Registered before the check it depends on
+	dependent := newMockCheck("sync-branch", StatusError)
+	dependent.fixable = true
+	dependent.CheckDependsOn = []string{"metadata"}
+	d.Register(dependent)
+	metadata := newMockCheck("metadata", StatusWarning)
+	metadata.fixable = true
+	d.Register(metadata)
+
+	report := d.Fix(&CheckContext{})
+	if got, want := report.Checks[0].Name+","+report.Checks[1].Name, "metadata,sync-branch"; got != want {
+		t.Errorf("fix order = %s, want %s", got, want)
+	}
+	if !report.Checks[0].Fixed || !report.Checks[1].Fixed {
+		t.Errorf("fixed = %v, %v; want both", report.Checks[0].Fixed, report.Checks[1].Fixed)
+	}
+
+	// When the dependency can't be fixed, the dependent's fix is skipped.
+	d = NewDoctor()
+	dependent = newMockCheck("sync-branch", StatusError)
+	dependent.fixable = true
+	dependent.CheckDependsOn = []string{"metadata"}
+	d.Register(dependent)
+	metadata = newMockCheck("metadata", StatusError)
+	metadata.fixable = true
+	metadata.fixError = errors.New("read-only")
+	d.Register(metadata)
+
+	report = d.Fix(&CheckContext{})
+	if dependent.fixCount != 0 {
+		t.Errorf("dependent fixed %d times while its dependency failed", dependent.fixCount)
+	}
+	if details := strings.Join(report.Checks[1].Details, "\n"); !strings.Contains(details, "depends on metadata") {
+		t.Errorf("details = %q, want the skipped fix explained", details)
+	}
+}
+
+// previewCheck is a fixable mock that can describe its fix.
+type previewCheck struct {
+	*mockCheck
+	planned []string
+}
+
+func (p *previewCheck) PreviewFix(ctx *CheckContext) []string {
+	return p.planned
+}
+
+func TestDoctor_FixDryRun(t *testing.T) {
+	d := NewDoctor()
+
+	okCheck := newMockCheck("ok", StatusOK)
+	okCheck.fixable = true
+	d.Register(okCheck)
+
+	previewable := &previewCheck{
+		mockCheck: newMockCheck("previewable", StatusWarning),
+		planned:   []string{"append foo to /tmp/x"},
+	}
+	previewable.fixable = true
+	d.Register(previewable)
+
+	opaque := newMockCheck("opaque", StatusError)
+	opaque.fixable = true
+	d.Register(opaque)
+
+	unfixable := newMockCheck("unfixable", StatusError)
+	d.Register(unfixable)
+
+	report := d.FixDryRun(&CheckContext{TownRoot: "/test"})
+
+	if previewable.fixCount != 0 || opaque.fixCount != 0 {
+		t.Fatal("dry run must not call Fix()")
+	}
+	if report.Checks[1].Status != StatusWarning || report.Checks[2].Status != StatusError {
+		t.Error("dry run must leave check statuses unchanged")
+	}
+	if got := report.Checks[1].PlannedFixes; len(got) != 1 || got[0] != "append foo to /tmp/x" {
+		t.Errorf("previewable PlannedFixes = %v", got)
+	}
+	if got := report.Checks[2].PlannedFixes; len(got) != 1 {
+		t.Errorf("opaque check should get a generic planned fix, got %v", got)
+	}
+	if len(report.Checks[0].PlannedFixes) != 0 || len(report.Checks[3].PlannedFixes) != 0 {
+		t.Error("OK and unfixable checks should have no planned fixes")
+	}
+	if report.Summary.WouldFix != 2 {
+		t.Errorf("WouldFix = %d, want 2", report.Summary.WouldFix)
+	}
+
+	var buf bytes.Buffer
+	report.PrintSummaryOnly(&buf, false, 0)
+	if !bytes.Contains(buf.Bytes(), []byte("PLANNED FIXES")) || !bytes.Contains(buf.Bytes(), []byte("append foo to /tmp/x")) {
+		t.Errorf("summary missing planned fixes:\n%s", buf.String())
+	}
+}
+
 func TestBaseCheck(t *testing.T) {
 	b := &BaseCheck{
 		CheckName:        "test",
@@ -3,11 +3,18 @@ package doctor
 import (
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
 )
 
 // DoltBinaryCheck verifies that the dolt binary is installed and accessible in PATH.
 // Dolt is required for the beads storage backend (dolt sql-server).
+// It also checks the installed version against the supported range and the
+// embedded list of known-bad releases: 'gt dolt start' runs this binary, so a
+// skewed install otherwise shows up only as an opaque server startup failure.
 type DoltBinaryCheck struct {
 	BaseCheck
 }
@@ -17,7 +24,7 @@ func NewDoltBinaryCheck() *DoltBinaryCheck {
 	return &DoltBinaryCheck{
 		BaseCheck: BaseCheck{
 			CheckName:        "dolt-binary",
-			CheckDescription: "Check that dolt is installed and in PATH",
+			CheckDescription: "Check that dolt is installed, in PATH and a supported version",
 			CheckCategory:    CategoryInfrastructure,
 		},
 	}
@@ -56,9 +63,59 @@ func (c *DoltBinaryCheck) Run(ctx *CheckContext) *CheckResult {
 
 	ver := strings.TrimSpace(string(output))
 	// dolt version outputs "dolt version X.Y.Z"
-	return &CheckResult{
+	return c.checkVersion(ctx, ver)
+}
+
+// checkVersion evaluates the installed dolt against the supported range
+// (MinDoltVersion tightened by the town's dolt bounds) and the advisories.
+func (c *DoltBinaryCheck) checkVersion(ctx *CheckContext, ver string) *CheckResult {
+	var townMin, townMax string
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil && settings.Dolt != nil {
+		townMin, townMax = settings.Dolt.MinVersion, settings.Dolt.MaxVersion
+	}
+	minVersion, maxVersion := deps.SupportedDoltRange(townMin, townMax)
+	install := deps.DoltInstallCommand(runtime.GOOS)
+
+	report, err := deps.CheckInstalledDolt(townMin, townMax)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%s (could not check it against the supported range)", ver),
+			Details: []string{err.Error()},
+			FixHint: "Reinstall dolt: " + install,
+		}
+	}
+
+	supported := ">= " + minVersion
+	if maxVersion != "" {
+		supported += ", <= " + maxVersion
+	}
+	if report.OK() {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%s (supported: %s)", ver, supported),
+		}
+	}
+
+	result := &CheckResult{
 		Name:    c.Name(),
-		Status:  StatusOK,
-		Message: ver,
+		Status:  StatusError,
+		Details: []string{"Supported: " + supported},
+	}
+	switch {
+	case report.TooOld:
+		result.Message = fmt.Sprintf("dolt %s is older than the minimum %s", report.Version, minVersion)
+		result.FixHint = "Upgrade dolt: " + install
+	case report.TooNew:
+		result.Status = StatusWarning
+		result.Message = fmt.Sprintf("dolt %s is newer than the town maximum %s", report.Version, maxVersion)
+		result.FixHint = "Install dolt " + maxVersion + " or earlier, or raise dolt.max_version in settings/config.json once this version is verified"
+	default:
+		result.Message = fmt.Sprintf("dolt %s is a known-bad release (%d issue(s))", report.Version, len(report.Advisories))
+		result.FixHint = "Upgrade dolt: " + install
 	}
+	result.Details = append(result.Details, deps.DescribeAdvisories(report.Advisories)...)
+	return result
 }
@@ -7,6 +7,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/deps"
 )
 
 func TestDoltBinaryCheck_Metadata(t *testing.T) {
@@ -15,7 +17,7 @@ func TestDoltBinaryCheck_Metadata(t *testing.T) {
 	if check.Name() != "dolt-binary" {
 		t.Errorf("Name() = %q, want %q", check.Name(), "dolt-binary")
 	}
-	if check.Description() != "Check that dolt is installed and in PATH" {
+	if check.Description() != "Check that dolt is installed, in PATH and a supported version" {
 		t.Errorf("Description() = %q", check.Description())
 	}
 	if check.Category() != CategoryInfrastructure {
@@ -66,8 +68,8 @@ func TestDoltBinaryCheck_HermeticSuccess(t *testing.T) {
 	// Create a fake "dolt" binary that prints a version string
 	fakeDir := t.TempDir()
 	writeFakeDolt(t, fakeDir,
-		"#!/bin/sh\necho 'dolt version 1.0.0'\n",
-		"@echo off\r\necho dolt version 1.0.0\r\n",
+		"#!/bin/sh\necho 'dolt version 99.0.0'\n",
+		"@echo off\r\necho dolt version 99.0.0\r\n",
 	)
 
 	t.Setenv("PATH", fakeDir)
@@ -79,8 +81,8 @@ func TestDoltBinaryCheck_HermeticSuccess(t *testing.T) {
 	if result.Status != StatusOK {
 		t.Errorf("expected StatusOK with fake dolt, got %v: %s", result.Status, result.Message)
 	}
-	if result.Message != "dolt version 1.0.0" {
-		t.Errorf("expected 'dolt version 1.0.0', got %q", result.Message)
+	if !strings.HasPrefix(result.Message, "dolt version 99.0.0 (supported: >= ") {
+		t.Errorf("expected 'dolt version 99.0.0 (supported: ...)', got %q", result.Message)
 	}
 }
 
@@ -135,3 +137,26 @@ func TestDoltBinaryCheck_DoltVersionFails(t *testing.T) {
 		t.Error("expected a fix hint for broken dolt")
 	}
 }
+
+func TestDoltBinaryCheck_TooOld(t *testing.T) {
+	fakeDir := t.TempDir()
+	writeFakeDolt(t, fakeDir,
+		"#!/bin/sh\necho 'dolt version 1.0.0'\n",
+		"@echo off\r\necho dolt version 1.0.0\r\n",
+	)
+	t.Setenv("PATH", fakeDir)
+
+	result := NewDoltBinaryCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusError {
+		t.Fatalf("status = %v, want error: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(result.Message, deps.MinDoltVersion) {
+		t.Errorf("message should name the minimum, got %q", result.Message)
+	}
+	if !strings.Contains(result.FixHint, "install") {
+		t.Errorf("fix hint should give the install command, got %q", result.FixHint)
+	}
+	if len(result.Details) < 2 || !strings.Contains(result.Details[1], "fixed in") {
+		t.Errorf("details should list the advisory, got %q", result.Details)
+	}
+}
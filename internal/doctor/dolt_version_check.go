@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// DoltServerVersionCheck compares the running Dolt server's version against
+// deps.MinDoltVersion, the bounds in town settings (settings/config.json
+// "dolt") and the embedded list of known-bad Dolt releases. Subtle Dolt
+// behavior changes have broken beads queries before, so upgrades should be
+// deliberate.
+type DoltServerVersionCheck struct {
+	BaseCheck
+}
+
+// NewDoltServerVersionCheck creates a new Dolt server version check.
+func NewDoltServerVersionCheck() *DoltServerVersionCheck {
+	return &DoltServerVersionCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "dolt-server-version",
+			CheckDescription: "Check Dolt server version against town requirements and advisories",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run queries the server version and evaluates it.
+func (c *DoltServerVersionCheck) Run(ctx *CheckContext) *CheckResult {
+	// Reachability is dolt-server-reachable's job; don't double-report it.
+	if err := doltserver.CheckServerReachable(ctx.TownRoot); err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "Dolt server not reachable (version not checked)",
+			Category: c.CheckCategory,
+		}
+	}
+
+	version, err := doltserver.ServerVersion(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  "Could not query Dolt server version",
+			Details:  []string{err.Error()},
+			Category: c.CheckCategory,
+		}
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Dolt %s (could not read town settings: %v)", version, err),
+			Category: c.CheckCategory,
+		}
+	}
+
+	advisories, err := deps.DoltAdvisories()
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  err.Error(),
+			Category: c.CheckCategory,
+		}
+	}
+
+	return c.evaluate(version, settings.Dolt, advisories)
+}
+
+// evaluate checks a server version against MinDoltVersion, the town's
+// bounds (which may only tighten it) and the advisories.
+func (c *DoltServerVersionCheck) evaluate(version string, bounds *config.DoltVersionConfig, advisories []deps.DoltAdvisory) *CheckResult {
+	var townMin, townMax string
+	if bounds != nil {
+		townMin, townMax = bounds.MinVersion, bounds.MaxVersion
+	}
+	minVersion, maxVersion := deps.SupportedDoltRange(townMin, townMax)
+
+	report := deps.CheckDoltVersion(version, minVersion, maxVersion, advisories)
+	if report.OK() {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  fmt.Sprintf("Dolt server %s", version),
+			Category: c.CheckCategory,
+		}
+	}
+
+	result := &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Category: c.CheckCategory,
+	}
+	switch {
+	case report.TooOld:
+		result.Status = StatusError
+		result.Message = fmt.Sprintf("Dolt server %s is older than the supported minimum %s", version, minVersion)
+		result.FixHint = "Upgrade dolt, then restart the server ('gt dolt stop && gt dolt start')"
+	case report.TooNew:
+		result.Message = fmt.Sprintf("Dolt server %s is newer than the town maximum %s", version, maxVersion)
+		result.FixHint = "Downgrade dolt, or raise dolt.max_version in settings/config.json once this version is verified"
+	default:
+		result.Message = fmt.Sprintf("Dolt server %s has %d known issue(s)", version, len(report.Advisories))
+		result.FixHint = "Move to a Dolt release outside the affected range"
+	}
+	result.Details = append(result.Details, deps.DescribeAdvisories(report.Advisories)...)
+	return result
+}
@@ -0,0 +1,44 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+func TestDoltServerVersionCheck_Evaluate(t *testing.T) {
+	advisories := []deps.DoltAdvisory{
+		{Introduced: "1.50.0", Fixed: "1.50.2", Summary: "breaks beads ready"},
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		bounds  *config.DoltVersionConfig
+		status  CheckStatus
+		message string
+	}{
+		{"supported", "1.45.0", nil, StatusOK, "Dolt server 1.45.0"},
+		{"below built-in minimum", "1.30.0", nil, StatusError, "older than the supported minimum " + deps.MinDoltVersion},
+		{"town minimum cannot lower it", "1.30.0", &config.DoltVersionConfig{MinVersion: "1.0.0"}, StatusError, deps.MinDoltVersion},
+		{"town minimum raises it", "1.41.0", &config.DoltVersionConfig{MinVersion: "1.42.0"}, StatusError, "supported minimum 1.42.0"},
+		{"above town maximum", "1.60.0", &config.DoltVersionConfig{MaxVersion: "1.59.0"}, StatusWarning, "newer than the town maximum 1.59.0"},
+		{"advisory", "1.50.1", nil, StatusWarning, "1 known issue"},
+		{"advisory fixed", "1.50.2", nil, StatusOK, "Dolt server 1.50.2"},
+	}
+
+	c := NewDoltServerVersionCheck()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.evaluate(tt.version, tt.bounds, advisories)
+			if result.Status != tt.status {
+				t.Errorf("status = %v, want %v (%s)", result.Status, tt.status, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.message)
+			}
+		})
+	}
+}
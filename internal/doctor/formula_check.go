@@ -12,6 +12,7 @@ import (
 // and modified formulas (user customized). Can auto-fix outdated and missing.
 type FormulaCheck struct {
 	FixableCheck
+	planned []string // Formula updates found by Run, for PreviewFix
 }
 
 // NewFormulaCheck creates a new formula check.
@@ -29,6 +30,7 @@ func NewFormulaCheck() *FormulaCheck {
 
 // Run checks if formulas need updating.
 func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
+	c.planned = nil
 	report, err := formula.CheckFormulaHealth(ctx.TownRoot)
 	if err != nil {
 		return &CheckResult{
@@ -55,17 +57,21 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 		switch f.Status {
 		case "outdated":
 			details = append(details, fmt.Sprintf("  %s: update available", f.Name))
+			c.planned = append(c.planned, "update formula "+f.Name)
 			needsFix = true
 		case "missing":
 			details = append(details, fmt.Sprintf("  %s: missing (will reinstall)", f.Name))
+			c.planned = append(c.planned, "reinstall formula "+f.Name)
 			needsFix = true
 		case "modified":
 			details = append(details, fmt.Sprintf("  %s: locally modified (skipping)", f.Name))
 		case "new":
 			details = append(details, fmt.Sprintf("  %s: new formula available", f.Name))
+			c.planned = append(c.planned, "install formula "+f.Name)
 			needsFix = true
 		case "untracked":
 			details = append(details, fmt.Sprintf("  %s: untracked (will update)", f.Name))
+			c.planned = append(c.planned, "update formula "+f.Name)
 			needsFix = true
 		}
 	}
@@ -128,3 +134,9 @@ func (c *FormulaCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix lists the formulas Fix would install or update. Locally
+// modified formulas are left alone.
+func (c *FormulaCheck) PreviewFix(ctx *CheckContext) []string {
+	return c.planned
+}
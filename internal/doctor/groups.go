@@ -0,0 +1,234 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Check groups select subsets of checks by subsystem ('gt doctor --only').
+// Unlike categories, which only arrange the output, a check can be in
+// several groups.
+const (
+	GroupBeads  = "beads"
+	GroupDolt   = "dolt"
+	GroupGit    = "git"
+	GroupAgents = "agents"
+	GroupConfig = "config"
+)
+
+// Groups lists every check group, in display order.
+var Groups = []string{GroupBeads, GroupDolt, GroupGit, GroupAgents, GroupConfig}
+
+// checkGroups maps each built-in check to its groups.
+var checkGroups = map[string][]string{
+	// Town and rig layout
+	"town-config-exists":   {GroupConfig},
+	"town-config-valid":    {GroupConfig},
+	"rigs-registry-exists": {GroupConfig},
+	"rigs-registry-valid":  {GroupConfig},
+	"mayor-exists":         {GroupConfig, GroupAgents},
+	"global-state":         {GroupConfig},
+	"stale-binary":         {GroupConfig},
+	"rig-toolchain":        {GroupConfig},
+	"env-vars":             {GroupConfig, GroupAgents},
+	"commands-provisioned": {GroupConfig, GroupAgents},
+	"owners":               {GroupConfig},
+	"rig-settings":         {GroupConfig},
+	"role-config-valid":    {GroupConfig, GroupAgents},
+	"rig-name-mismatch":    {GroupConfig},
+	"legacy-gastown":       {GroupConfig},
+	"themes":               {GroupConfig, GroupAgents},
+	"formulas":             {GroupConfig},
+	"crash-reports":        {GroupAgents},
+
+	// Beads
+	"beads-binary":          {GroupBeads},
+	"beads-config-valid":    {GroupBeads},
+	"beads-custom-types":    {GroupBeads},
+	"beads-fsck":            {GroupBeads},
+	"beads-redirect":        {GroupBeads},
+	"stale-beads-redirect":  {GroupBeads},
+	"prefix-conflict":       {GroupBeads},
+	"prefix-mismatch":       {GroupBeads},
+	"database-prefix":       {GroupBeads, GroupDolt},
+	"routes-config":         {GroupBeads},
+	"rig-routes-jsonl":      {GroupBeads},
+	"routing-mode":          {GroupBeads},
+	"role-bead-labels":      {GroupBeads, GroupAgents},
+	"agent-beads-exist":     {GroupBeads, GroupAgents},
+	"stale-agent-beads":     {GroupBeads, GroupAgents},
+	"orphaned-mq-entries":   {GroupBeads, GroupGit},
+	"rig-beads-exist":       {GroupBeads},
+	"wisp-gc":               {GroupBeads},
+	"misclassified-wisps":   {GroupBeads},
+	"hook-attachment-valid": {GroupBeads, GroupAgents},
+	"hook-singleton":        {GroupBeads, GroupAgents},
+	"orphaned-attachments":  {GroupBeads, GroupAgents},
+
+	// Dolt
+	"dolt-binary":             {GroupDolt},
+	"dolt-metadata":           {GroupDolt},
+	"dolt-server-reachable":   {GroupDolt},
+	"dolt-server-version":     {GroupDolt},
+	"dolt-orphaned-databases": {GroupDolt},
+	"dolt-missing-databases":  {GroupDolt},
+
+	// Git
+	"town-git":                 {GroupGit},
+	"town-root-branch":         {GroupGit},
+	"pre-checkout-hook":        {GroupGit},
+	"branch-protection":        {GroupGit},
+	"persistent-role-branches": {GroupGit, GroupAgents},
+	"clone-divergence":         {GroupGit},
+	"default-branch-all-rigs":  {GroupGit},
+	"default-branch-exists":    {GroupGit},
+	"worktree-gitdir-valid":    {GroupGit},
+	"sparse-checkout":          {GroupGit},
+	"rig-is-git-repo":          {GroupGit},
+	"git-exclude-configured":   {GroupGit},
+	"bare-repo-exists":         {GroupGit},
+	"bare-repo-refspec":        {GroupGit},
+	"mayor-clone-exists":       {GroupGit},
+	"polecat-clones-valid":     {GroupGit, GroupAgents},
+	"crew-worktrees":           {GroupGit, GroupAgents},
+	"land-worktree-gitignore":  {GroupGit},
+	"runtime-gitignore":        {GroupGit},
+	"hooks-path-all-rigs":      {GroupGit},
+	"hooks-path-configured":    {GroupGit},
+	"beads-sync-hooks":         {GroupGit, GroupBeads},
+
+	// Agents, sessions and patrols
+	"daemon":                    {GroupAgents},
+	"boot-health":               {GroupAgents},
+	"identity-collision":        {GroupAgents},
+	"linked-panes":              {GroupAgents},
+	"orphan-sessions":           {GroupAgents},
+	"orphan-processes":          {GroupAgents},
+	"zombie-sessions":           {GroupAgents},
+	"session-name-format":       {GroupAgents},
+	"witness-exists":            {GroupAgents},
+	"refinery-exists":           {GroupAgents},
+	"crew-state":                {GroupAgents},
+	"priming":                   {GroupAgents},
+	"lifecycle-hygiene":         {GroupAgents},
+	"patrol-molecules-exist":    {GroupAgents},
+	"patrol-hooks-wired":        {GroupAgents},
+	"patrol-not-stuck":          {GroupAgents},
+	"patrol-plugins-accessible": {GroupAgents},
+
+	// Agent settings and hooks
+	"session-hooks":               {GroupAgents, GroupConfig},
+	"claude-settings":             {GroupAgents, GroupConfig},
+	"deprecated-merge-queue-keys": {GroupConfig},
+	"stale-task-dispatch":         {GroupAgents, GroupConfig},
+	"hooks-sync":                  {GroupAgents, GroupConfig},
+}
+
+// CheckGroups returns the groups of the named check. Checks not in the
+// table fall back to their category: configuration checks are in config,
+// anything else in no group.
+func CheckGroups(check Check) []string {
+	if groups, ok := checkGroups[check.Name()]; ok {
+		return groups
+	}
+	if cg, ok := check.(categoryGetter); ok && cg.Category() == CategoryConfig {
+		return []string{GroupConfig}
+	}
+	return nil
+}
+
+// Profile is a curated set of checks for one situation.
+type Profile struct {
+	Description string
+	Checks      []string // check names
+}
+
+// Profiles are the named check sets for 'gt doctor --profile'.
+var Profiles = map[string]Profile{
+	"pre-flight": {
+		Description: "Fast checks before starting agents: config, binaries, Dolt and the daemon",
+		Checks: []string{
+			"town-config-exists", "town-config-valid", "rigs-registry-valid",
+			"beads-binary", "dolt-binary", "stale-binary", "rig-toolchain",
+			"dolt-server-reachable", "dolt-server-version",
+			"routes-config", "daemon", "town-root-branch", "identity-collision",
+		},
+	},
+	"post-upgrade": {
+		Description: "After upgrading gt, bd or dolt: versions, settings templates and deprecated config",
+		Checks: []string{
+			"stale-binary", "beads-binary", "dolt-binary", "dolt-server-version",
+			"beads-custom-types", "claude-settings", "session-hooks",
+			"deprecated-merge-queue-keys", "stale-task-dispatch", "hooks-sync",
+			"beads-sync-hooks", "commands-provisioned", "legacy-gastown",
+		},
+	},
+	"agents": {
+		Description: "Sessions, patrols and agent state",
+		Checks: []string{
+			"daemon", "boot-health", "orphan-sessions", "zombie-sessions",
+			"orphan-processes", "session-name-format", "patrol-not-stuck",
+			"patrol-hooks-wired", "hook-singleton", "stale-agent-beads", "crew-state",
+		},
+	},
+}
+
+// ProfileNames returns the profile names, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Selection returns a filter for Doctor.Filter keeping the checks in any of
+// groups (--only) and, if profile is set, in that profile. An empty
+// selection keeps everything.
+func Selection(groups []string, profile string) (func(Check) bool, error) {
+	wantGroup := make(map[string]bool)
+	for _, g := range groups {
+		g = strings.TrimSpace(strings.ToLower(g))
+		if !isGroup(g) {
+			return nil, fmt.Errorf("unknown check group %q (groups: %s)", g, strings.Join(Groups, ", "))
+		}
+		wantGroup[g] = true
+	}
+	var inProfile map[string]bool
+	if profile != "" {
+		p, ok := Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q (profiles: %s)", profile, strings.Join(ProfileNames(), ", "))
+		}
+		inProfile = make(map[string]bool, len(p.Checks))
+		for _, name := range p.Checks {
+			inProfile[name] = true
+		}
+	}
+
+	return func(c Check) bool {
+		if inProfile != nil && !inProfile[c.Name()] {
+			return false
+		}
+		if len(wantGroup) == 0 {
+			return true
+		}
+		for _, g := range CheckGroups(c) {
+			if wantGroup[g] {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func isGroup(g string) bool {
+	for _, known := range Groups {
+		if g == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package doctor
+
+import "testing"
+
+func TestSelection(t *testing.T) {
+	dolt := newMockCheck("dolt-binary", StatusOK)
+	git := newMockCheck("town-git", StatusOK)
+	misc := newMockCheck("something-new", StatusOK)
+	misc.CheckCategory = CategoryConfig
+
+	keep, err := Selection([]string{"Dolt", "config"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keep(dolt) || keep(git) || !keep(misc) {
+		t.Errorf("--only dolt,config: dolt=%v git=%v misc=%v", keep(dolt), keep(git), keep(misc))
+	}
+
+	keep, err = Selection([]string{"git"}, "pre-flight")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keep(dolt) || keep(git) {
+		t.Error("--profile pre-flight --only git should keep neither dolt-binary nor town-git")
+	}
+	if !keep(newMockCheck("town-root-branch", StatusOK)) {
+		t.Error("--profile pre-flight --only git should keep town-root-branch")
+	}
+
+	keep, err = Selection(nil, "")
+	if err != nil || !keep(git) {
+		t.Errorf("empty selection should keep everything (err %v)", err)
+	}
+
+	if _, err := Selection([]string{"network"}, ""); err == nil {
+		t.Error("expected error for unknown group")
+	}
+	if _, err := Selection(nil, "nightly"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,209 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxHistoryEntries is how many doctor runs are kept; older runs are pruned on save.
+const MaxHistoryEntries = 100
+
+// historyTimeFormat names history files so they sort chronologically.
+const historyTimeFormat = "20060102T150405.000000000Z"
+
+// HistoryEntry is a persisted doctor run.
+type HistoryEntry struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Rig       string         `json:"rig,omitempty"` // Scope of the run (empty = whole town)
+	Fix       bool           `json:"fix,omitempty"` // Statuses are after --fix was applied
+	Checks    []HistoryCheck `json:"checks"`
+	Summary   HistorySummary `json:"summary"`
+}
+
+// HistoryCheck is one check's outcome within a run.
+type HistoryCheck struct {
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+}
+
+// HistorySummary counts outcomes for a run.
+type HistorySummary struct {
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+}
+
+// CheckChange is a check whose status differs between two runs.
+type CheckChange struct {
+	Name      string `json:"name"`
+	From      string `json:"from"` // Empty if the check was not in the earlier run
+	To        string `json:"to"`   // Empty if the check is not in the later run
+	Message   string `json:"message,omitempty"`
+	Regressed bool   `json:"regressed"`
+}
+
+// HistoryDir returns where doctor runs are recorded for a town.
+func HistoryDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "doctor-history")
+}
+
+// NewHistoryEntry converts a report into a history entry.
+func NewHistoryEntry(report *Report, rig string, fix bool) *HistoryEntry {
+	entry := &HistoryEntry{Timestamp: report.Timestamp, Rig: rig, Fix: fix}
+	for _, c := range report.Checks {
+		entry.Checks = append(entry.Checks, HistoryCheck{
+			Name:     c.Name,
+			Category: c.Category,
+			Status:   c.Status.String(),
+			Message:  c.Message,
+		})
+		switch c.Status {
+		case StatusOK:
+			entry.Summary.OK++
+		case StatusWarning:
+			entry.Summary.Warnings++
+		case StatusError:
+			entry.Summary.Errors++
+		}
+	}
+	return entry
+}
+
+// SaveHistory records a run and prunes the oldest runs beyond MaxHistoryEntries.
+func SaveHistory(townRoot string, entry *HistoryEntry) error {
+	dir := HistoryDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating doctor history dir: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := entry.Timestamp.UTC().Format(historyTimeFormat) + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil { //nolint:gosec // G306: diagnostics, not secret
+		return fmt.Errorf("writing doctor history: %w", err)
+	}
+
+	files, err := historyFiles(dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > MaxHistoryEntries {
+		_ = os.Remove(filepath.Join(dir, files[0]))
+		files = files[1:]
+	}
+	return nil
+}
+
+// LoadHistory returns recorded runs, oldest first. Unreadable files are skipped.
+func LoadHistory(townRoot string) ([]*HistoryEntry, error) {
+	dir := HistoryDir(townRoot)
+	files, err := historyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*HistoryEntry
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func historyFiles(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading doctor history: %w", err)
+	}
+	var files []string
+	for _, e := range dirEntries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// statusRank orders statuses by severity. A missing check ranks as OK so
+// that newly added failing checks show up as regressions.
+func statusRank(status string) int {
+	switch status {
+	case StatusWarning.String():
+		return 1
+	case StatusError.String():
+		return 2
+	default:
+		return 0
+	}
+}
+
+// DiffHistory lists checks whose status changed from prev to cur, regressions first.
+func DiffHistory(prev, cur *HistoryEntry) []CheckChange {
+	before := make(map[string]HistoryCheck, len(prev.Checks))
+	for _, c := range prev.Checks {
+		before[c.Name] = c
+	}
+
+	var changes []CheckChange
+	seen := make(map[string]bool, len(cur.Checks))
+	for _, c := range cur.Checks {
+		seen[c.Name] = true
+		old, existed := before[c.Name]
+		if existed && old.Status == c.Status {
+			continue
+		}
+		if !existed && c.Status == StatusOK.String() {
+			continue // New passing check is not interesting
+		}
+		changes = append(changes, CheckChange{
+			Name:      c.Name,
+			From:      old.Status,
+			To:        c.Status,
+			Message:   c.Message,
+			Regressed: statusRank(c.Status) > statusRank(old.Status),
+		})
+	}
+	for _, c := range prev.Checks {
+		if !seen[c.Name] && c.Status != StatusOK.String() {
+			changes = append(changes, CheckChange{Name: c.Name, From: c.Status})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Regressed != changes[j].Regressed {
+			return changes[i].Regressed
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// FindBaseline returns the newest run with the same scope (rig) recorded
+// strictly before the given time, or nil if there is none.
+func FindBaseline(entries []*HistoryEntry, rig string, before time.Time) *HistoryEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Rig == rig && entries[i].Timestamp.Before(before) {
+			return entries[i]
+		}
+	}
+	return nil
+}
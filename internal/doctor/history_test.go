@@ -0,0 +1,105 @@
+package doctor
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHistory_SaveLoadPrune(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < MaxHistoryEntries+3; i++ {
+		report := &Report{Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		report.Add(&CheckResult{Name: "town-config-exists", Status: StatusOK})
+		if err := SaveHistory(townRoot, NewHistoryEntry(report, "", false)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := LoadHistory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxHistoryEntries {
+		t.Fatalf("got %d entries, want %d", len(entries), MaxHistoryEntries)
+	}
+	if want := base.Add(3 * time.Minute); !entries[0].Timestamp.Equal(want) {
+		t.Errorf("oldest kept = %v, want %v", entries[0].Timestamp, want)
+	}
+	if entries[0].Summary.OK != 1 {
+		t.Errorf("summary = %+v", entries[0].Summary)
+	}
+
+	files, _ := os.ReadDir(HistoryDir(townRoot))
+	if len(files) != MaxHistoryEntries {
+		t.Errorf("%d files on disk after prune", len(files))
+	}
+}
+
+func TestLoadHistory_Missing(t *testing.T) {
+	entries, err := LoadHistory(t.TempDir())
+	if err != nil || len(entries) != 0 {
+		t.Errorf("LoadHistory on empty town = %v, %v", entries, err)
+	}
+}
+
+func TestDiffHistory(t *testing.T) {
+	prev := &HistoryEntry{Checks: []HistoryCheck{
+		{Name: "a", Status: "OK"},
+		{Name: "b", Status: "Error"},
+		{Name: "c", Status: "OK"},
+		{Name: "gone", Status: "Warning"},
+	}}
+	cur := &HistoryEntry{Checks: []HistoryCheck{
+		{Name: "a", Status: "Warning", Message: "drifted"},
+		{Name: "b", Status: "OK"},
+		{Name: "c", Status: "OK"},
+		{Name: "new-ok", Status: "OK"},
+		{Name: "new-bad", Status: "Error"},
+	}}
+
+	changes := DiffHistory(prev, cur)
+	got := make(map[string]CheckChange)
+	for _, c := range changes {
+		got[c.Name] = c
+	}
+	if len(changes) != 4 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if !changes[0].Regressed || !changes[1].Regressed {
+		t.Errorf("regressions not sorted first: %+v", changes)
+	}
+	if c := got["a"]; !c.Regressed || c.From != "OK" || c.To != "Warning" {
+		t.Errorf("a = %+v", c)
+	}
+	if c := got["new-bad"]; !c.Regressed || c.From != "" {
+		t.Errorf("new-bad = %+v", c)
+	}
+	if c := got["b"]; c.Regressed {
+		t.Errorf("b improved but marked regressed: %+v", c)
+	}
+	if c := got["gone"]; c.Regressed || c.To != "" {
+		t.Errorf("gone = %+v", c)
+	}
+}
+
+func TestFindBaseline(t *testing.T) {
+	base := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	entries := []*HistoryEntry{
+		{Timestamp: base},
+		{Timestamp: base.Add(time.Hour), Rig: "gastown"},
+		{Timestamp: base.Add(2 * time.Hour)},
+	}
+
+	if got := FindBaseline(entries, "", base.Add(2*time.Hour)); got != entries[0] {
+		t.Errorf("town baseline = %+v, want first entry", got)
+	}
+	if got := FindBaseline(entries, "gastown", base.Add(3*time.Hour)); got != entries[1] {
+		t.Errorf("rig baseline = %+v", got)
+	}
+	if got := FindBaseline(entries, "", base); got != nil {
+		t.Errorf("expected no baseline before the first run, got %+v", got)
+	}
+}
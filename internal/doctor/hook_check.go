@@ -88,8 +88,7 @@ func (c *HookAttachmentValidCheck) checkBeadsDir(beadsDir, _ string) []invalidAt
 
 	// List all pinned beads
 	pinnedBeads, err := b.List(beads.ListOptions{
-		Status:   beads.StatusPinned,
-		Priority: -1,
+		Status: beads.StatusPinned,
 	})
 	if err != nil {
 		// Can't list pinned beads - silently skip this directory
@@ -187,6 +186,15 @@ func (c *HookAttachmentValidCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the molecules Fix would detach.
+func (c *HookAttachmentValidCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, inv := range c.invalidAttachments {
+		planned = append(planned, "detach molecule from "+inv.pinnedBeadID)
+	}
+	return planned
+}
+
 // HookSingletonCheck ensures each agent has at most one handoff bead.
 // Detects when multiple pinned beads exist with the same "{role} Handoff" title,
 // which can cause confusion about which handoff is authoritative.
@@ -269,8 +277,7 @@ func (c *HookSingletonCheck) checkBeadsDir(beadsDir string) []duplicateHandoff {
 
 	// List all pinned beads
 	pinnedBeads, err := b.List(beads.ListOptions{
-		Status:   beads.StatusPinned,
-		Priority: -1,
+		Status: beads.StatusPinned,
 	})
 	if err != nil {
 		return nil
@@ -327,6 +334,18 @@ func (c *HookSingletonCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the duplicate handoff beads Fix would close.
+func (c *HookSingletonCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, dup := range c.duplicates {
+		if len(dup.beadIDs) > 1 {
+			planned = append(planned, fmt.Sprintf("close duplicate handoff beads %s (keep %s)",
+				strings.Join(dup.beadIDs[1:], ", "), dup.beadIDs[0]))
+		}
+	}
+	return planned
+}
+
 // OrphanedAttachmentsCheck detects handoff beads for agents that no longer exist.
 // This happens when a polecat worktree is deleted but its handoff bead remains,
 // leaving molecules attached to non-existent agents.
@@ -403,8 +422,7 @@ func (c *OrphanedAttachmentsCheck) checkBeadsDir(beadsDir, townRoot string) []or
 
 	// List all pinned beads
 	pinnedBeads, err := b.List(beads.ListOptions{
-		Status:   beads.StatusPinned,
-		Priority: -1,
+		Status: beads.StatusPinned,
 	})
 	if err != nil {
 		return nil
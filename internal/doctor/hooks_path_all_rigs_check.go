@@ -97,6 +97,20 @@ func (c *HooksPathAllRigsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the git config commands Fix would run.
+func (c *HooksPathAllRigsCheck) PreviewFix(ctx *CheckContext) []string {
+	return previewHooksPathFix(c.unconfiguredClones)
+}
+
+// previewHooksPathFix lists the core.hooksPath commands for each clone.
+func previewHooksPathFix(clones []string) []string {
+	var planned []string
+	for _, clonePath := range clones {
+		planned = append(planned, fmt.Sprintf("run: git -C %s config core.hooksPath .githooks", clonePath))
+	}
+	return planned
+}
+
 // findRigClones returns all git clone paths within a rig.
 func findRigClones(rigPath string) []string {
 	var clones []string
@@ -140,3 +140,12 @@ func (c *HooksSyncCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix lists the settings files Fix would regenerate.
+func (c *HooksSyncCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, target := range c.outOfSync {
+		planned = append(planned, fmt.Sprintf("rewrite hooks in %s (%s)", target.Path, target.DisplayKey()))
+	}
+	return planned
+}
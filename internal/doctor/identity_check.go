@@ -11,6 +11,7 @@ import (
 // IdentityCollisionCheck checks for agent identity collisions and stale locks.
 type IdentityCollisionCheck struct {
 	BaseCheck
+	staleLocks []string // Cached during Run for PreviewFix
 }
 
 // NewIdentityCollisionCheck creates a new identity collision check.
@@ -29,6 +30,8 @@ func (c *IdentityCollisionCheck) CanFix() bool {
 }
 
 func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
+	c.staleLocks = nil
+
 	// Find all locks
 	locks, err := lock.FindAllLocks(ctx.TownRoot)
 	if err != nil {
@@ -109,6 +112,7 @@ func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
 
 		healthyLocks++
 	}
+	c.staleLocks = staleLocks
 
 	// Build result
 	if len(staleLocks) == 0 && len(orphanedLocks) == 0 {
@@ -165,3 +169,12 @@ func (c *IdentityCollisionCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix lists the stale locks Fix would release.
+func (c *IdentityCollisionCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, s := range c.staleLocks {
+		planned = append(planned, "release stale lock "+s)
+	}
+	return planned
+}
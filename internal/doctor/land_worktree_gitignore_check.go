@@ -82,6 +82,15 @@ func (c *LandWorktreeGitignoreCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the .gitignore entries Fix would append.
+func (c *LandWorktreeGitignoreCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, rigPath := range c.affectedRigs {
+		planned = append(planned, fmt.Sprintf("append \".land-worktree/\" to %s", filepath.Join(rigPath, ".gitignore")))
+	}
+	return planned
+}
+
 // hasGitignoreEntry checks if a .gitignore file contains the given entry.
 func hasGitignoreEntry(gitignorePath, entry string) bool {
 	file, err := os.Open(gitignorePath)
@@ -105,6 +105,28 @@ func TestLandWorktreeGitignoreCheck_Fix(t *testing.T) {
 	}
 }
 
+func TestLandWorktreeGitignoreCheck_PreviewFix(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := createRigWithGitignore(t, townRoot, "myrig", "plugins/\n")
+
+	check := NewLandWorktreeGitignoreCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	check.Run(ctx)
+
+	planned := check.PreviewFix(ctx)
+	if len(planned) != 1 || !strings.Contains(planned[0], filepath.Join(rigPath, ".gitignore")) {
+		t.Errorf("unexpected preview: %v", planned)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rigPath, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), ".land-worktree/") {
+		t.Error("PreviewFix must not modify .gitignore")
+	}
+}
+
 func TestLandWorktreeGitignoreCheck_FixCreatesFile(t *testing.T) {
 	townRoot := t.TempDir()
 	rigPath := filepath.Join(townRoot, "myrig")
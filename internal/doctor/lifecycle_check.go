@@ -111,3 +111,12 @@ func (c *LifecycleHygieneCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix lists the stale lifecycle messages Fix would delete.
+func (c *LifecycleHygieneCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, msg := range c.staleMessages {
+		planned = append(planned, fmt.Sprintf("gt mail delete %s (%q from %s)", msg.ID, msg.Subject, msg.From))
+	}
+	return planned
+}
@@ -2,10 +2,12 @@ package doctor
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -31,6 +33,7 @@ func NewDoltMetadataCheck() *DoltMetadataCheck {
 				CheckName:        "dolt-metadata",
 				CheckDescription: "Check that metadata.json has Dolt server config",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"stale-beads-redirect"},
 			},
 		},
 	}
@@ -81,7 +84,7 @@ func (c *DoltMetadataCheck) Run(ctx *CheckContext) *CheckResult {
 			continue
 		}
 
-		if !c.hasDoltMetadata(beadsDir, rigName) {
+		if !c.hasDoltMetadata(beadsDir, doltserver.RouteDatabase(ctx.TownRoot, rigName)) {
 			relPath, _ := filepath.Rel(ctx.TownRoot, beadsDir)
 			missing = append(missing, rigName+" ("+relPath+")")
 			c.missingMetadata = append(c.missingMetadata, rigName)
@@ -129,6 +132,15 @@ func (c *DoltMetadataCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the rigs whose metadata.json Fix would write.
+func (c *DoltMetadataCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, rigName := range c.missingMetadata {
+		planned = append(planned, fmt.Sprintf("write dolt server config to %s beads metadata.json", rigName))
+	}
+	return planned
+}
+
 // hasDoltMetadata checks if a beads directory has proper dolt server config.
 func (c *DoltMetadataCheck) hasDoltMetadata(beadsDir, expectedDB string) bool {
 	metadataPath := filepath.Join(beadsDir, "metadata.json")
@@ -174,7 +186,7 @@ func (c *DoltMetadataCheck) writeDoltMetadata(townRoot, rigName string) error {
 	existing["database"] = "dolt"
 	existing["backend"] = "dolt"
 	existing["dolt_mode"] = "server"
-	existing["dolt_database"] = rigName
+	existing["dolt_database"] = doltserver.RouteDatabase(townRoot, rigName)
 
 	// Always set jsonl_export to the canonical filename.
 	existing["jsonl_export"] = "issues.jsonl"
@@ -429,6 +441,119 @@ func (c *DoltOrphanedDatabaseCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the databases Fix would drop.
+func (c *DoltOrphanedDatabaseCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, name := range c.orphanNames {
+		planned = append(planned, "drop orphaned database "+name+" and remove its data directory")
+	}
+	return planned
+}
+
+// DoltMissingDatabaseCheck detects rigs whose metadata.json declares Dolt
+// server mode but whose database is missing from .dolt-data/ (deleted,
+// lost in a restore, or never created). bd fails for these rigs, or falls
+// back to an isolated local database.
+type DoltMissingDatabaseCheck struct {
+	FixableCheck
+	broken []doltserver.BrokenWorkspace // Cached during Run for use in Fix
+}
+
+// NewDoltMissingDatabaseCheck creates a new missing database check.
+func NewDoltMissingDatabaseCheck() *DoltMissingDatabaseCheck {
+	return &DoltMissingDatabaseCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "dolt-missing-databases",
+				CheckDescription: "Check that every server-mode rig has its database in .dolt-data/",
+				CheckCategory:    CategoryInfrastructure,
+			},
+		},
+	}
+}
+
+// Run finds server-mode rigs without a database.
+func (c *DoltMissingDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
+	c.broken = nil
+
+	if cfg := doltserver.DefaultConfig(ctx.TownRoot); cfg.IsRemote() {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  fmt.Sprintf("Dolt server is remote (%s); databases not checked", cfg.HostPort()),
+			Category: c.CheckCategory,
+		}
+	}
+
+	c.broken = doltserver.FindBrokenWorkspaces(ctx.TownRoot)
+	if len(c.broken) == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "All server-mode rigs have a database in .dolt-data/",
+			Category: c.CheckCategory,
+		}
+	}
+	sort.Slice(c.broken, func(i, j int) bool { return c.broken[i].RigName < c.broken[j].RigName })
+
+	details := make([]string, len(c.broken))
+	for i, ws := range c.broken {
+		details[i] = fmt.Sprintf("%s: database %q missing, %s", ws.RigName, ws.ConfiguredDB, repairSource(ctx.TownRoot, ws))
+	}
+
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusError,
+		Message:  fmt.Sprintf("%d rig(s) in Dolt server mode missing their database", len(c.broken)),
+		Details:  details,
+		FixHint:  "Run 'gt doctor --fix' to recreate them (restoring from local data or issues.jsonl when present)",
+		Category: c.CheckCategory,
+	}
+}
+
+// Fix recreates each missing database from the rig's local Dolt data or
+// JSONL export, or empty if it has neither, and registers it with the
+// running server.
+func (c *DoltMissingDatabaseCheck) Fix(ctx *CheckContext) error {
+	var errs []error
+	for _, ws := range c.broken {
+		if _, err := doltserver.RepairWorkspace(ctx.TownRoot, ws); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PreviewFix lists the databases Fix would create, and from what.
+func (c *DoltMissingDatabaseCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, ws := range c.broken {
+		planned = append(planned, fmt.Sprintf("create database %s for %s, %s", ws.ConfiguredDB, ws.RigName, repairSource(ctx.TownRoot, ws)))
+	}
+	return planned
+}
+
+// repairSource describes what a broken workspace's database would be
+// recreated from.
+func repairSource(townRoot string, ws doltserver.BrokenWorkspace) string {
+	switch {
+	case ws.HasLocalData:
+		return "migrating local data from " + relOrAbs(townRoot, ws.LocalDataPath)
+	case ws.JSONLPath != "":
+		return "importing " + relOrAbs(townRoot, ws.JSONLPath)
+	default:
+		return "empty (no local data or issues.jsonl to restore from)"
+	}
+}
+
+// relOrAbs returns path relative to townRoot when it is inside it.
+func relOrAbs(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
 // formatBytes returns a human-readable size string.
 func formatBytes(b int64) string {
 	const unit = 1024
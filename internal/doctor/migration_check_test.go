@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -297,3 +298,79 @@ func TestDoltOrphanedDatabaseCheck_Name(t *testing.T) {
 		t.Errorf("expected name 'dolt-orphaned-databases', got %q", check.Name())
 	}
 }
+
+func TestDoltMissingDatabaseCheck(t *testing.T) {
+	townRoot := t.TempDir()
+
+	setupDoltDB(t, townRoot, "hq")
+	setupRigsJSON(t, townRoot, []string{"alpha", "beta"})
+	setupRigMetadata(t, townRoot, "hq", "hq")
+	setupRigMetadata(t, townRoot, "alpha", "alpha")
+	setupRigMetadata(t, townRoot, "beta", "beta")
+	export := filepath.Join(townRoot, "beta", "mayor", "rig", ".beads", "issues.jsonl")
+	if err := os.WriteFile(export, []byte(`{"id":"be-1","title":"one"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewDoltMissingDatabaseCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusError {
+		t.Fatalf("expected StatusError, got %v: %s", result.Status, result.Message)
+	}
+	if len(result.Details) != 2 {
+		t.Fatalf("expected 2 details, got %v", result.Details)
+	}
+	if !strings.Contains(result.Details[0], "alpha") || !strings.Contains(result.Details[0], "empty") {
+		t.Errorf("alpha detail: %q", result.Details[0])
+	}
+	if !strings.Contains(result.Details[1], "beta") || !strings.Contains(result.Details[1], "importing beta/mayor/rig/.beads/issues.jsonl") {
+		t.Errorf("beta detail: %q", result.Details[1])
+	}
+	if got := check.PreviewFix(ctx); len(got) != 2 {
+		t.Errorf("PreviewFix = %v", got)
+	}
+}
+
+func TestDoltMissingDatabaseCheck_ImportNeedsServer(t *testing.T) {
+	townRoot := t.TempDir()
+
+	setupDoltDB(t, townRoot, "hq")
+	setupRigsJSON(t, townRoot, []string{"beta"})
+	setupRigMetadata(t, townRoot, "hq", "hq")
+	setupRigMetadata(t, townRoot, "beta", "beta")
+	export := filepath.Join(townRoot, "beta", "mayor", "rig", ".beads", "issues.jsonl")
+	if err := os.WriteFile(export, []byte(`{"id":"be-1","title":"one"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewDoltMissingDatabaseCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	check.Run(ctx)
+	err := check.Fix(ctx)
+	if err == nil || !strings.Contains(err.Error(), "gt dolt start") {
+		t.Fatalf("Fix without a server: %v", err)
+	}
+	// Nothing is touched: the export stays where it was and no database is created.
+	if _, err := os.Stat(export); err != nil {
+		t.Errorf("export moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".dolt-data", "beta")); !os.IsNotExist(err) {
+		t.Errorf("database created without a server: %v", err)
+	}
+}
+
+func TestDoltMissingDatabaseCheck_AllPresent(t *testing.T) {
+	townRoot := t.TempDir()
+
+	setupDoltDB(t, townRoot, "hq")
+	setupDoltDB(t, townRoot, "alpha")
+	setupRigsJSON(t, townRoot, []string{"alpha"})
+	setupRigMetadata(t, townRoot, "hq", "hq")
+	setupRigMetadata(t, townRoot, "alpha", "alpha")
+
+	result := NewDoltMissingDatabaseCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK, got %v: %s", result.Status, result.Message)
+	}
+}
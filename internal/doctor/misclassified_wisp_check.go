@@ -278,3 +278,12 @@ func (c *CheckMisclassifiedWisps) Fix(ctx *CheckContext) error {
 
 	return lastErr
 }
+
+// PreviewFix lists the issues Fix would mark ephemeral.
+func (c *CheckMisclassifiedWisps) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, wisp := range c.misclassified {
+		planned = append(planned, fmt.Sprintf("bd update %s --ephemeral (%s)", wisp.id, wisp.rigName))
+	}
+	return planned
+}
@@ -0,0 +1,300 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// DefaultMonitorInterval is how often 'gt doctor serve' runs the checks
+// when neither the flag nor the settings file says.
+const DefaultMonitorInterval = 15 * time.Minute
+
+// MonitorEvent is the event name of monitor webhook payloads.
+const MonitorEvent = "doctor.check_failed"
+
+// monitorWebhookTimeout bounds each webhook delivery.
+const monitorWebhookTimeout = 5 * time.Second
+
+// MonitorPath returns the town's doctor monitor settings file.
+func MonitorPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "doctor-monitor.json")
+}
+
+// MonitorConfig says how 'gt doctor serve' raises alerts. A missing file
+// means no notifications: runs are still recorded in the doctor history.
+type MonitorConfig struct {
+	// Interval between runs, e.g. "10m". Defaults to DefaultMonitorInterval.
+	Interval string `json:"interval,omitempty"`
+
+	// Desktop shows a desktop notification (notify-send or osascript).
+	Desktop bool `json:"desktop,omitempty"`
+
+	// Webhooks receive a POST with a JSON MonitorPayload per alert.
+	Webhooks []MonitorWebhook `json:"webhooks,omitempty"`
+
+	// CreateBeads files a bug in the town beads for each failing check,
+	// unless one is already open.
+	CreateBeads bool `json:"create_beads,omitempty"`
+}
+
+// MonitorWebhook is one receiver of monitor alerts.
+type MonitorWebhook struct {
+	URL     string `json:"url"`               // may name a town secret ("secret:<name>")
+	Secret  string `json:"secret,omitempty"`  // signs the body (see mq.SignatureHeader); may name a secret
+	Timeout string `json:"timeout,omitempty"` // per delivery, e.g. "3s"; default 5s
+}
+
+// LoadMonitorConfig loads and validates the town's monitor settings,
+// resolving the town secrets they name. A missing file yields an empty
+// configuration.
+func LoadMonitorConfig(townRoot string) (*MonitorConfig, error) {
+	data, err := os.ReadFile(MonitorPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &MonitorConfig{}, nil
+		}
+		return nil, fmt.Errorf("reading doctor monitor settings: %w", err)
+	}
+	var cfg MonitorConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", MonitorPath(townRoot), err)
+	}
+	if _, err := cfg.IntervalDuration(); err != nil {
+		return nil, err
+	}
+	for i := range cfg.Webhooks {
+		w := &cfg.Webhooks[i]
+		if w.URL, err = secrets.Resolve(townRoot, w.URL); err != nil {
+			return nil, fmt.Errorf("webhook %d: url: %w", i, err)
+		}
+		if w.Secret, err = secrets.Resolve(townRoot, w.Secret); err != nil {
+			return nil, fmt.Errorf("webhook %d: secret: %w", i, err)
+		}
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook %d: invalid url: must be an http(s) URL", i)
+		}
+		if w.Timeout != "" {
+			if d, err := time.ParseDuration(w.Timeout); err != nil || d <= 0 {
+				return nil, fmt.Errorf("webhook %d: invalid timeout %q", i, w.Timeout)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// IntervalDuration returns the configured interval, or the default.
+func (c *MonitorConfig) IntervalDuration() (time.Duration, error) {
+	if c.Interval == "" {
+		return DefaultMonitorInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d < time.Minute {
+		return 0, fmt.Errorf("invalid monitor interval %q: must be a duration of at least 1m", c.Interval)
+	}
+	return d, nil
+}
+
+// Alert is a check that went from OK to error between two runs.
+type Alert struct {
+	Check    string    `json:"check"`
+	Category string    `json:"category,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// MonitorPayload is the JSON body POSTed to monitor webhooks.
+type MonitorPayload struct {
+	Event    string `json:"event"`
+	TownRoot string `json:"town_root"`
+	Alert
+}
+
+// Alerts returns the checks that were OK in prev and are errors in cur.
+// Warnings, and checks that were already failing, do not alert. With no
+// previous run there is nothing to compare and no alerts.
+func Alerts(prev, cur *HistoryEntry) []Alert {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	categories := make(map[string]string, len(cur.Checks))
+	for _, c := range cur.Checks {
+		categories[c.Name] = c.Category
+	}
+	var alerts []Alert
+	for _, c := range DiffHistory(prev, cur) {
+		if c.From == StatusOK.String() && c.To == StatusError.String() {
+			alerts = append(alerts, Alert{
+				Check:    c.Name,
+				Category: categories[c.Name],
+				Message:  c.Message,
+				At:       cur.Timestamp,
+			})
+		}
+	}
+	return alerts
+}
+
+// Monitor raises alerts through the channels a MonitorConfig enables.
+type Monitor struct {
+	townRoot string
+	cfg      *MonitorConfig
+	client   *http.Client
+
+	// desktop shows a notification; replaced in tests.
+	desktop func(title, body string) error
+}
+
+// NewMonitor creates a monitor for the town.
+func NewMonitor(townRoot string, cfg *MonitorConfig) *Monitor {
+	return &Monitor{
+		townRoot: townRoot,
+		cfg:      cfg,
+		client:   &http.Client{},
+		desktop:  desktopNotify,
+	}
+}
+
+// Notify sends each alert through every enabled channel. Channels are
+// independent; failures are joined into the returned error.
+func (m *Monitor) Notify(alerts []Alert) error {
+	var errs []error
+	for _, a := range alerts {
+		if m.cfg.Desktop {
+			title := "Gas Town: " + a.Check + " failing"
+			if err := m.desktop(title, a.Message); err != nil {
+				errs = append(errs, fmt.Errorf("desktop notification: %w", err))
+			}
+		}
+		if len(m.cfg.Webhooks) > 0 {
+			if err := m.post(a); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if m.cfg.CreateBeads {
+			if err := m.fileBead(a); err != nil {
+				errs = append(errs, fmt.Errorf("filing bead for %s: %w", a.Check, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Monitor) post(a Alert) error {
+	body, err := json.Marshal(MonitorPayload{Event: MonitorEvent, TownRoot: m.townRoot, Alert: a})
+	if err != nil {
+		return fmt.Errorf("encoding alert: %w", err)
+	}
+	var errs []error
+	for _, w := range m.cfg.Webhooks {
+		// Webhook URLs often carry a token in the path: report only the host
+		host := w.URL
+		if u, err := url.Parse(w.URL); err == nil {
+			host = u.Host
+		}
+		if err := m.deliver(w, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Monitor) deliver(w MonitorWebhook, body []byte) error {
+	client := *m.client
+	client.Timeout = monitorWebhookTimeout
+	if d, err := time.ParseDuration(w.Timeout); err == nil && d > 0 {
+		client.Timeout = d
+	}
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-doctor")
+	if w.Secret != "" {
+		req.Header.Set(mq.SignatureHeader, mq.SignPayload(w.Secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err // its message repeats the URL
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// alertBeadTitle is the title of the bug filed for a failing check; it is
+// also how an already-open one is recognized.
+func alertBeadTitle(check string) string {
+	return "doctor: " + check + " failing"
+}
+
+func (m *Monitor) fileBead(a Alert) error {
+	bd := beads.New(m.townRoot)
+	title := alertBeadTitle(a.Check)
+	open, err := bd.List(beads.ListOptions{Status: "open"})
+	if err != nil {
+		return err
+	}
+	for _, issue := range open {
+		if issue.Title == title {
+			return nil
+		}
+	}
+	desc := fmt.Sprintf("gt doctor serve saw %s go from OK to error at %s.\n\n%s\n\nRun 'gt doctor' for details, 'gt doctor --fix' if the check is fixable.",
+		a.Check, a.At.Format(time.RFC3339), a.Message)
+	_, err = bd.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "bug",
+		Priority:    1,
+		Description: desc,
+		Actor:       "gt doctor serve",
+	})
+	return err
+}
+
+// desktopNotify shows a desktop notification where the platform has a
+// notifier.
+func desktopNotify(title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		return exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send not found")
+		}
+		return exec.Command("notify-send", "--urgency=critical", title, body).Run()
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+}
+
+// FormatAlert renders an alert for a log line.
+func FormatAlert(a Alert) string {
+	msg := strings.TrimSpace(a.Message)
+	if msg == "" {
+		return a.Check
+	}
+	return a.Check + ": " + msg
+}
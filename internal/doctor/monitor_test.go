@@ -0,0 +1,130 @@
+package doctor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+func TestAlerts(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	prev := &HistoryEntry{Checks: []HistoryCheck{
+		{Name: "daemon", Status: "OK"},
+		{Name: "dolt-server-reachable", Status: "OK"},
+		{Name: "orphan-sessions", Status: "OK"},
+		{Name: "routes-config", Status: "Error"},
+	}}
+	cur := &HistoryEntry{Timestamp: at, Checks: []HistoryCheck{
+		{Name: "daemon", Status: "OK"},
+		{Name: "dolt-server-reachable", Category: "Infrastructure", Status: "Error", Message: "connection refused"},
+		{Name: "orphan-sessions", Status: "Warning"},
+		{Name: "routes-config", Status: "Error"},
+		{Name: "new-check", Status: "Error"},
+	}}
+
+	alerts := Alerts(prev, cur)
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %+v, want only dolt-server-reachable", alerts)
+	}
+	a := alerts[0]
+	if a.Check != "dolt-server-reachable" || a.Category != "Infrastructure" || a.Message != "connection refused" || !a.At.Equal(at) {
+		t.Errorf("alert = %+v", a)
+	}
+	if got := Alerts(nil, cur); got != nil {
+		t.Errorf("no baseline: alerts = %+v", got)
+	}
+}
+
+func TestLoadMonitorConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg, err := LoadMonitorConfig(townRoot)
+	if err != nil || cfg.Desktop || len(cfg.Webhooks) != 0 {
+		t.Fatalf("missing file = %+v, %v", cfg, err)
+	}
+	if d, _ := cfg.IntervalDuration(); d != DefaultMonitorInterval {
+		t.Errorf("default interval = %v", d)
+	}
+
+	write := func(body string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(MonitorPath(townRoot)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(MonitorPath(townRoot), []byte(body), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"interval": "5m", "webhooks": [{"url": "https://hooks.example.com/x"}]}`)
+	cfg, err = LoadMonitorConfig(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := cfg.IntervalDuration(); d != 5*time.Minute {
+		t.Errorf("interval = %v", d)
+	}
+
+	for _, bad := range []string{
+		`{"interval": "10s"}`,
+		`{"webhooks": [{"url": "ftp://example.com"}]}`,
+		`{"webhooks": [{"url": "https://example.com", "timeout": "soon"}]}`,
+	} {
+		write(bad)
+		if _, err := LoadMonitorConfig(townRoot); err == nil {
+			t.Errorf("%s: accepted", bad)
+		}
+	}
+}
+
+func TestMonitorNotify(t *testing.T) {
+	var got MonitorPayload
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(mq.SignatureHeader)
+		if signature != mq.SignPayload("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	m := NewMonitor("/town", &MonitorConfig{
+		Desktop:  true,
+		Webhooks: []MonitorWebhook{{URL: srv.URL, Secret: "s3cret"}},
+	})
+	var titles []string
+	m.desktop = func(title, body string) error {
+		titles = append(titles, title)
+		return nil
+	}
+
+	alert := Alert{Check: "daemon", Message: "not running", At: time.Now().UTC()}
+	if err := m.Notify([]Alert{alert}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(titles) != 1 || titles[0] != "Gas Town: daemon failing" {
+		t.Errorf("desktop titles = %q", titles)
+	}
+	if got.Event != MonitorEvent || got.Check != "daemon" || got.Message != "not running" || got.TownRoot != "/town" {
+		t.Errorf("payload = %+v", got)
+	}
+
+	// A failing receiver is reported by host only: URLs can carry tokens.
+	m.cfg = &MonitorConfig{Webhooks: []MonitorWebhook{{URL: srv.URL + "/token-abc", Secret: "wrong"}}}
+	err := m.Notify([]Alert{alert})
+	if err == nil {
+		t.Fatal("Notify with a rejected webhook succeeded")
+	}
+	if strings.Contains(err.Error(), "token-abc") {
+		t.Errorf("error leaks the URL path: %v", err)
+	}
+}
@@ -158,6 +158,19 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the orphan sessions Fix would kill. Crew sessions are
+// never killed.
+func (c *OrphanSessionCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, sess := range c.orphanSessions {
+		if isCrewSession(sess) {
+			continue
+		}
+		planned = append(planned, "kill tmux session "+sess)
+	}
+	return planned
+}
+
 // isCrewSession returns true if the session name matches the crew pattern.
 // Crew sessions are gt-<rig>-crew-<name> and are protected from auto-cleanup.
 func isCrewSession(sess string) bool {
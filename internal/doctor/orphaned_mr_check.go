@@ -0,0 +1,247 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// OrphanedMRCheck finds merge queue entries (open merge-request beads) that
+// can never land or never progress: the branch is gone from the refinery's
+// clone and origin, the source bead was closed, or the MR is claimed by an
+// agent that no longer exists. Such entries confuse the refinery and anyone
+// reading the queue.
+//
+// The fix closes MRs that can't land and releases claims held by missing
+// agents so the refinery can take the MR again.
+type OrphanedMRCheck struct {
+	FixableCheck
+	orphans []orphanedMR // Cached for Fix and PreviewFix
+}
+
+// orphanedMR is one merge queue entry and what to do about it.
+type orphanedMR struct {
+	id       string
+	rigPath  string // Beads path for the MR's rig
+	action   string // orphanPrune or orphanRequeue
+	reason   string
+	assignee string
+}
+
+const (
+	orphanPrune   = "prune"
+	orphanRequeue = "requeue"
+)
+
+// NewOrphanedMRCheck creates a new orphaned merge queue entry check.
+func NewOrphanedMRCheck() *OrphanedMRCheck {
+	return &OrphanedMRCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "orphaned-mq-entries",
+				CheckDescription: "Detect merge queue entries with a missing branch, closed bead, or missing claimant",
+				CheckCategory:    CategoryRig,
+			},
+		},
+	}
+}
+
+// Run checks every rig's open merge requests.
+func (c *OrphanedMRCheck) Run(ctx *CheckContext) *CheckResult {
+	c.orphans = nil
+
+	routes, err := beads.LoadRoutes(filepath.Join(ctx.TownRoot, ".beads"))
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not load routes.jsonl",
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range routes {
+		parts := strings.Split(r.Path, "/")
+		if len(parts) == 0 || parts[0] == "." || seen[r.Path] {
+			continue
+		}
+		seen[r.Path] = true
+		rigName := parts[0]
+		rigBeadsPath := filepath.Join(ctx.TownRoot, r.Path)
+		bd := beads.New(rigBeadsPath)
+
+		var issues []*beads.Issue
+		for _, status := range []string{"open", "in_progress"} {
+			found, err := bd.List(beads.ListOptions{Label: "gt:merge-request", Status: status})
+			if err != nil {
+				continue
+			}
+			issues = append(issues, found...)
+		}
+		if len(issues) == 0 {
+			continue
+		}
+
+		// Branches are checked in the refinery's clone, which tracks origin.
+		var branchExists func(string) bool
+		clone := filepath.Join(ctx.TownRoot, rigName, "refinery", "rig")
+		if _, err := os.Stat(clone); err == nil {
+			g := git.NewGit(clone)
+			branchExists = func(branch string) bool {
+				local, err := g.BranchExists(branch)
+				if err != nil || local {
+					return true // Unknown counts as present
+				}
+				remote, err := g.RemoteTrackingBranchExists("origin", branch)
+				return err != nil || remote
+			}
+		}
+		sourceStatus := func(id string) string {
+			if issue, err := bd.Show(id); err == nil {
+				return issue.Status
+			}
+			return ""
+		}
+		agentExists := func(assignee string) bool {
+			return agentDirExists(ctx.TownRoot, rigName, assignee)
+		}
+
+		for _, issue := range issues {
+			if o := classifyOrphanedMR(issue, branchExists, sourceStatus, agentExists); o != nil {
+				o.rigPath = rigBeadsPath
+				c.orphans = append(c.orphans, *o)
+			}
+		}
+	}
+	sort.Slice(c.orphans, func(i, j int) bool { return c.orphans[i].id < c.orphans[j].id })
+
+	if len(c.orphans) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No orphaned merge queue entries",
+		}
+	}
+
+	var details []string
+	for _, o := range c.orphans {
+		details = append(details, fmt.Sprintf("%s: %s (%s)", o.id, o.reason, o.action))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d orphaned merge queue entr%s", len(c.orphans), pluralY(len(c.orphans))),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to close MRs that can't land and release stuck claims",
+	}
+}
+
+// classifyOrphanedMR decides whether an open MR is orphaned. branchExists
+// is nil when branches can't be checked; sourceStatus returns "" for beads
+// it can't find. Returns nil for healthy MRs.
+func classifyOrphanedMR(issue *beads.Issue, branchExists func(string) bool, sourceStatus func(string) string, agentExists func(string) bool) *orphanedMR {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil
+	}
+	o := &orphanedMR{id: issue.ID, assignee: issue.Assignee}
+
+	// Dependency updates (gt mq bump) have no branch; the refinery makes one.
+	if fields.Branch != "" && branchExists != nil && !branchExists(fields.Branch) {
+		o.action, o.reason = orphanPrune, "branch "+fields.Branch+" no longer exists"
+		return o
+	}
+	if fields.SourceIssue != "" && sourceStatus(fields.SourceIssue) == "closed" {
+		o.action, o.reason = orphanPrune, "source bead "+fields.SourceIssue+" is closed"
+		return o
+	}
+	if issue.Assignee != "" && !agentExists(issue.Assignee) {
+		o.action, o.reason = orphanRequeue, "claimed by "+issue.Assignee+", who no longer exists"
+		return o
+	}
+	return nil
+}
+
+// agentDirExists reports whether the agent an MR is assigned to still has
+// its directory. Refinery workers claim MRs by worker ID: the default
+// worker works in rigName/refinery, others in rigName/refinery/workers/<id>.
+// Other assignees are addresses (rig/polecats/name, rig/crew/name,
+// rig/refinery, ...). Assignees it doesn't recognize count as existing.
+func agentDirExists(townRoot, rigName, assignee string) bool {
+	parts := strings.Split(assignee, "/")
+	var dir string
+	switch {
+	case len(parts) == 1 && assignee == refinery.DefaultWorker:
+		dir = filepath.Join(townRoot, rigName, "refinery")
+	case len(parts) == 1:
+		dir = filepath.Join(townRoot, rigName, "refinery", "workers", assignee)
+	case parts[0] == "":
+		return true
+	case len(parts) == 2 && (parts[1] == "refinery" || parts[1] == "witness"):
+		dir = filepath.Join(townRoot, parts[0], parts[1])
+	case len(parts) == 3 && (parts[1] == "polecats" || parts[1] == "crew"):
+		dir = filepath.Join(townRoot, parts[0], parts[1], parts[2])
+	case len(parts) == 2:
+		// rig/name is a polecat
+		dir = filepath.Join(townRoot, parts[0], "polecats", parts[1])
+	default:
+		return true
+	}
+	_, err := os.Stat(dir)
+	return err == nil
+}
+
+// Fix closes MRs that can't land and releases claims held by missing agents.
+func (c *OrphanedMRCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, o := range c.orphans {
+		bd := beads.New(o.rigPath)
+		switch o.action {
+		case orphanPrune:
+			if !ctx.Confirmed(fmt.Sprintf("Close %s (%s)?", o.id, o.reason)) {
+				continue
+			}
+			if err := bd.CloseWithReason("orphaned: "+o.reason, o.id); err != nil {
+				errs = append(errs, fmt.Sprintf("closing %s: %v", o.id, err))
+			}
+		case orphanRequeue:
+			if !ctx.Confirmed(fmt.Sprintf("Release %s from %s and requeue it?", o.id, o.assignee)) {
+				continue
+			}
+			empty, open := "", "open"
+			if err := bd.Update(o.id, beads.UpdateOptions{Assignee: &empty, Status: &open}); err != nil {
+				errs = append(errs, fmt.Sprintf("requeueing %s: %v", o.id, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// PreviewFix lists what Fix would do for each orphaned entry.
+func (c *OrphanedMRCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, o := range c.orphans {
+		if o.action == orphanPrune {
+			planned = append(planned, fmt.Sprintf("close %s: %s", o.id, o.reason))
+		} else {
+			planned = append(planned, fmt.Sprintf("release %s from %s and requeue it", o.id, o.assignee))
+		}
+	}
+	return planned
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
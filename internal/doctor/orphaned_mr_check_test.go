@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestClassifyOrphanedMR(t *testing.T) {
+	branches := map[string]bool{"polecat/Toast/gt-abc": true}
+	branchExists := func(b string) bool { return branches[b] }
+	statuses := map[string]string{"gt-abc": "in_progress", "gt-old": "closed"}
+	sourceStatus := func(id string) string { return statuses[id] }
+	agentExists := func(a string) bool { return a == "refinery-1" }
+
+	mr := func(assignee, desc string) *beads.Issue {
+		return &beads.Issue{ID: "gt-mr", Status: "open", Assignee: assignee,
+			Labels: []string{"gt:merge-request"}, Description: desc}
+	}
+	tests := []struct {
+		name       string
+		issue      *beads.Issue
+		wantAction string
+	}{
+		{"healthy", mr("refinery-1", "branch: polecat/Toast/gt-abc\nsource_issue: gt-abc"), ""},
+		{"branch gone", mr("", "branch: polecat/Nux/gt-xyz\nsource_issue: gt-abc"), orphanPrune},
+		{"source closed", mr("", "branch: polecat/Toast/gt-abc\nsource_issue: gt-old"), orphanPrune},
+		{"claimant gone", mr("refinery-3", "branch: polecat/Toast/gt-abc\nsource_issue: gt-abc"), orphanRequeue},
+		{"dependency update", mr("", "dep_update: go.mod example.com/lib v1.2.3"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := classifyOrphanedMR(tt.issue, branchExists, sourceStatus, agentExists)
+			got := ""
+			if o != nil {
+				got = o.action
+			}
+			if got != tt.wantAction {
+				t.Errorf("action = %q, want %q", got, tt.wantAction)
+			}
+		})
+	}
+
+	// Without a refinery clone, branches aren't judged.
+	if o := classifyOrphanedMR(mr("", "branch: polecat/Nux/gt-xyz"), nil, sourceStatus, agentExists); o != nil {
+		t.Errorf("branch judged without a clone: %+v", o)
+	}
+}
+
+func TestAgentDirExists(t *testing.T) {
+	town := t.TempDir()
+	for _, dir := range []string{"gastown/refinery/workers/w2", "gastown/polecats/Toast", "gastown/crew/max"} {
+		if err := os.MkdirAll(filepath.Join(town, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string]bool{
+		"refinery-1":              true,
+		"w2":                      true,
+		"w3":                      false,
+		"gastown/refinery":        true,
+		"gastown/witness":         false,
+		"gastown/polecats/Toast":  true,
+		"gastown/Toast":           true,
+		"gastown/polecats/Nux":    false,
+		"gastown/crew/max":        true,
+		"beads/crew/max":          false,
+		"mayor/some/deeper/thing": true,
+	}
+	for assignee, want := range tests {
+		if got := agentDirExists(town, "gastown", assignee); got != want {
+			t.Errorf("agentDirExists(%q) = %v, want %v", assignee, got, want)
+		}
+	}
+}
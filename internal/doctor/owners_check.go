@@ -0,0 +1,101 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// OwnersCheck validates the town ownership map (settings/owners.json): it
+// must parse, every rule's owner must be a defined team or a worker address,
+// workers must belong to an existing rig, and label rules should name
+// labels the town registry allows.
+type OwnersCheck struct {
+	BaseCheck
+}
+
+// NewOwnersCheck creates a new ownership map check.
+func NewOwnersCheck() *OwnersCheck {
+	return &OwnersCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "owners",
+			CheckDescription: "Validate the component ownership map (settings/owners.json)",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run validates the ownership map.
+func (c *OwnersCheck) Run(ctx *CheckContext) *CheckResult {
+	path := config.OwnersConfigPath(ctx.TownRoot)
+	owners, err := config.LoadOwnersConfig(path)
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusOK,
+				Message: "No ownership map",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Ownership map is invalid",
+			Details: []string{err.Error()},
+			FixHint: "Edit " + path + " or rebuild it with 'gt owners'",
+		}
+	}
+
+	var problems []string
+	checkWorker := func(where, address string) {
+		rigName, _, _ := strings.Cut(address, "/")
+		if rigName == "mayor" || rigName == "deacon" {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(ctx.TownRoot, rigName)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s is not in a rig of this town", where, address))
+		}
+	}
+	for _, t := range owners.Teams {
+		if len(t.Members) == 0 {
+			problems = append(problems, fmt.Sprintf("team %s has no members", t.Name))
+		}
+		for _, m := range t.Members {
+			checkWorker("team "+t.Name, m)
+		}
+	}
+
+	labels, _ := config.LoadOrCreateLabelRegistry(config.LabelsConfigPath(ctx.TownRoot))
+	for i, r := range owners.Rules {
+		where := fmt.Sprintf("rule %d", i+1)
+		switch {
+		case owners.FindTeam(r.Owner) != nil:
+		case strings.Contains(r.Owner, "/"):
+			checkWorker(where, r.Owner)
+		default:
+			problems = append(problems, fmt.Sprintf("%s: owner %s is neither a team nor a worker address", where, r.Owner))
+		}
+		if r.Label != "" && labels != nil && !labels.Allows(r.Label) {
+			problems = append(problems, fmt.Sprintf("%s: label %s is not in the label registry", where, r.Label))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d problem(s) in the ownership map", len(problems)),
+			Details: problems,
+			FixHint: "Fix them with 'gt owners' (see 'gt owners list')",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d team(s), %d rule(s)", len(owners.Teams), len(owners.Rules)),
+	}
+}
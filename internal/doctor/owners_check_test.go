@@ -0,0 +1,53 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestOwnersCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	check := NewOwnersCheck()
+
+	if r := check.Run(&CheckContext{TownRoot: townRoot}); r.Status != StatusOK {
+		t.Errorf("no map: status = %v, want OK", r.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	owners := &config.OwnersConfig{
+		Type:    "owners",
+		Version: config.CurrentOwnersVersion,
+		Teams:   []config.TeamDef{{Name: "core", Members: []string{"gastown/crew/max"}}},
+		Rules:   []config.OwnerRule{{Path: "internal/", Owner: "core"}},
+	}
+	path := config.OwnersConfigPath(townRoot)
+	if err := config.SaveOwnersConfig(path, owners); err != nil {
+		t.Fatal(err)
+	}
+	if r := check.Run(&CheckContext{TownRoot: townRoot}); r.Status != StatusOK {
+		t.Errorf("valid map: status = %v (%v), want OK", r.Status, r.Details)
+	}
+
+	owners.Rules = append(owners.Rules,
+		config.OwnerRule{Label: "infra", Owner: "platform"},
+		config.OwnerRule{Path: "docs/", Owner: "beads/crew/ann"})
+	if err := config.SaveOwnersConfig(path, owners); err != nil {
+		t.Fatal(err)
+	}
+	r := check.Run(&CheckContext{TownRoot: townRoot})
+	if r.Status != StatusWarning || len(r.Details) != 2 {
+		t.Errorf("unknown team and rig: status = %v, details = %v; want a warning with 2 details", r.Status, r.Details)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"type": "owners", "rules": [{"path": "a/"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := check.Run(&CheckContext{TownRoot: townRoot}); r.Status != StatusError {
+		t.Errorf("invalid map: status = %v, want error", r.Status)
+	}
+}
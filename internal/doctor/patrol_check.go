@@ -183,6 +183,15 @@ func (c *PatrolHooksWiredCheck) Fix(ctx *CheckContext) error {
 	return config.EnsureDaemonPatrolConfig(ctx.TownRoot)
 }
 
+// PreviewFix describes the daemon patrol config Fix would write.
+func (c *PatrolHooksWiredCheck) PreviewFix(ctx *CheckContext) []string {
+	path := config.DaemonPatrolConfigPath(ctx.TownRoot)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return []string{"write default patrol config to " + path}
+}
+
 // PatrolNotStuckCheck detects wisps that have been in_progress too long.
 type PatrolNotStuckCheck struct {
 	BaseCheck
@@ -376,6 +385,15 @@ func (c *PatrolPluginsAccessibleCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the plugin directories Fix would create.
+func (c *PatrolPluginsAccessibleCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, dir := range c.missingDirs {
+		planned = append(planned, "create "+dir)
+	}
+	return planned
+}
+
 // discoverRigs finds all registered rigs.
 func discoverRigs(townRoot string) ([]string, error) {
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
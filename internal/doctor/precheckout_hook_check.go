@@ -218,5 +218,19 @@ func (c *BranchProtectionCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the hook Fix would install.
+func (c *BranchProtectionCheck) PreviewFix(ctx *CheckContext) []string {
+	if !c.needsUpdate {
+		return nil
+	}
+	hooksDir := filepath.Join(ctx.TownRoot, ".git", "hooks")
+	var planned []string
+	if content, err := os.ReadFile(filepath.Join(hooksDir, "pre-checkout")); err == nil &&
+		strings.Contains(string(content), "Gas Town pre-checkout hook") {
+		planned = append(planned, "remove obsolete "+filepath.Join(hooksDir, "pre-checkout"))
+	}
+	return append(planned, "add branch protection to "+filepath.Join(hooksDir, "post-checkout"))
+}
+
 // Legacy type alias for backwards compatibility
 type PreCheckoutHookCheck = BranchProtectionCheck
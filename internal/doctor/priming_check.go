@@ -453,3 +453,29 @@ func (c *PrimingCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix lists the files Fix would write or remove.
+func (c *PrimingCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, issue := range c.issues {
+		if !issue.fixable {
+			continue
+		}
+		location := filepath.Join(ctx.TownRoot, issue.location)
+		switch issue.issueType {
+		case "missing_town_claude_md":
+			planned = append(planned, "write "+filepath.Join(ctx.TownRoot, "CLAUDE.md"))
+		case "orphaned_beads_dir":
+			planned = append(planned, "remove orphaned "+filepath.Join(location, ".beads"))
+		case "missing_prime_md":
+			planned = append(planned, "provision PRIME.md for "+issue.location)
+		case "stale_intermediate_instructions_md":
+			for _, filename := range []string{"CLAUDE.md", "AGENTS.md"} {
+				if filePath := filepath.Join(location, filename); fileExists(filePath) {
+					planned = append(planned, "remove stale "+filePath)
+				}
+			}
+		}
+	}
+	return planned
+}
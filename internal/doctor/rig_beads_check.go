@@ -15,6 +15,7 @@ import (
 // They are created by gt rig add (see gt-zmznh) but may be missing for legacy rigs.
 type RigBeadsCheck struct {
 	FixableCheck
+	missing []string // Rig bead IDs not found (cached for PreviewFix)
 }
 
 // NewRigBeadsCheck creates a new rig identity beads check.
@@ -25,6 +26,7 @@ func NewRigBeadsCheck() *RigBeadsCheck {
 				CheckName:        "rig-beads-exist",
 				CheckDescription: "Verify rig identity beads exist for all rigs",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"dolt-metadata", "beads-custom-types"},
 			},
 		},
 	}
@@ -89,6 +91,7 @@ func (c *RigBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 		checked++
 	}
+	c.missing = missing
 
 	if len(missing) == 0 {
 		return &CheckResult{
@@ -173,3 +176,12 @@ func (c *RigBeadsCheck) Fix(ctx *CheckContext) error {
 
 	return errors.Join(errs...)
 }
+
+// PreviewFix lists the rig identity beads Fix would create.
+func (c *RigBeadsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, id := range c.missing {
+		planned = append(planned, "create rig identity bead "+id)
+	}
+	return planned
+}
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -239,6 +240,14 @@ func (c *GitExcludeConfiguredCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the exclude entries Fix would append.
+func (c *GitExcludeConfiguredCheck) PreviewFix(ctx *CheckContext) []string {
+	if len(c.missingEntries) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("append %s to %s", strings.Join(c.missingEntries, ", "), c.excludePath)}
+}
+
 // HooksPathConfiguredCheck verifies all clones have core.hooksPath set to .githooks.
 // This ensures the pre-push hook blocks pushes to invalid branches (no internal PRs).
 type HooksPathConfiguredCheck struct {
@@ -360,6 +369,11 @@ func (c *HooksPathConfiguredCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the git config commands Fix would run.
+func (c *HooksPathConfiguredCheck) PreviewFix(ctx *CheckContext) []string {
+	return previewHooksPathFix(c.unconfiguredClones)
+}
+
 // WitnessExistsCheck verifies the witness directory structure exists.
 type WitnessExistsCheck struct {
 	FixableCheck
@@ -467,6 +481,20 @@ func (c *WitnessExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the directories and mailbox Fix would create. The
+// clone itself can't be fixed automatically.
+func (c *WitnessExistsCheck) PreviewFix(ctx *CheckContext) []string {
+	dir := filepath.Join(c.rigPath, "witness")
+	var planned []string
+	if c.needsCreate {
+		planned = append(planned, "create "+dir)
+	}
+	if c.needsMail {
+		planned = append(planned, "create "+filepath.Join(dir, "mail", "inbox.jsonl"))
+	}
+	return planned
+}
+
 // RefineryExistsCheck verifies the refinery directory structure exists.
 type RefineryExistsCheck struct {
 	FixableCheck
@@ -574,6 +602,20 @@ func (c *RefineryExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the directories and mailbox Fix would create. The
+// clone itself can't be fixed automatically.
+func (c *RefineryExistsCheck) PreviewFix(ctx *CheckContext) []string {
+	dir := filepath.Join(c.rigPath, "refinery")
+	var planned []string
+	if c.needsCreate {
+		planned = append(planned, "create "+dir)
+	}
+	if c.needsMail {
+		planned = append(planned, "create "+filepath.Join(dir, "mail", "inbox.jsonl"))
+	}
+	return planned
+}
+
 // MayorCloneExistsCheck verifies the mayor/rig clone exists.
 type MayorCloneExistsCheck struct {
 	FixableCheck
@@ -661,6 +703,15 @@ func (c *MayorCloneExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the directory Fix would create. The clone itself
+// can't be fixed automatically.
+func (c *MayorCloneExistsCheck) PreviewFix(ctx *CheckContext) []string {
+	if !c.needsCreate {
+		return nil
+	}
+	return []string{"create " + filepath.Join(c.rigPath, "mayor")}
+}
+
 // PolecatClonesValidCheck verifies each polecat directory is a valid clone.
 type PolecatClonesValidCheck struct {
 	BaseCheck
@@ -862,6 +913,11 @@ func (c *BeadsConfigValidCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix reports nothing: Fix is a no-op with the Dolt backend.
+func (c *BeadsConfigValidCheck) PreviewFix(ctx *CheckContext) []string {
+	return nil
+}
+
 // BeadsRedirectCheck verifies that rig-level beads redirect exists for tracked beads.
 // When a repo has .beads/ tracked in git (at mayor/rig/.beads), the rig root needs
 // a redirect file pointing to that location.
@@ -1063,6 +1119,32 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes whether Fix would initialize beads or write a redirect.
+func (c *BeadsRedirectCheck) PreviewFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+	rigPath := ctx.RigPath()
+	rigBeadsDir := filepath.Join(rigPath, ".beads")
+	_, trackedErr := os.Stat(filepath.Join(rigPath, "mayor", "rig", ".beads"))
+	_, localErr := os.Stat(rigBeadsDir)
+	hasTrackedBeads := !os.IsNotExist(trackedErr)
+	hasLocalBeads := !os.IsNotExist(localErr)
+
+	if !hasTrackedBeads && !hasLocalBeads {
+		prefix := config.GetRigPrefix(ctx.TownRoot, ctx.RigName)
+		return []string{fmt.Sprintf("bd init --prefix %s --server (in %s)", prefix, rigPath)}
+	}
+	if !hasTrackedBeads {
+		return nil
+	}
+	var planned []string
+	if hasLocalBeads && hasBeadsData(rigBeadsDir) {
+		planned = append(planned, "remove conflicting local beads "+rigBeadsDir)
+	}
+	return append(planned, fmt.Sprintf("write %s -> mayor/rig/.beads", filepath.Join(rigBeadsDir, "redirect")))
+}
+
 // hasBeadsData checks if a beads directory has actual data (issues.jsonl, issues.db, config.yaml)
 // as opposed to just being a redirect-only directory.
 func hasBeadsData(beadsDir string) bool {
@@ -1174,6 +1256,18 @@ func (c *BareRepoRefspecCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the git config command Fix would run.
+func (c *BareRepoRefspecCheck) PreviewFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+	bareRepoPath := filepath.Join(ctx.RigPath(), ".repo.git")
+	if _, err := os.Stat(bareRepoPath); os.IsNotExist(err) {
+		return nil
+	}
+	return []string{fmt.Sprintf("git -C %s config remote.origin.fetch +refs/heads/*:refs/remotes/origin/*", bareRepoPath)}
+}
+
 // DefaultBranchExistsCheck verifies that the configured default_branch exists
 // as a remote tracking ref in the bare repo.
 type DefaultBranchExistsCheck struct {
@@ -1290,6 +1384,12 @@ func NewDefaultBranchAllRigsCheck() *DefaultBranchAllRigsCheck {
 	}
 }
 
+// CacheTTL lets doctor reuse the result for an hour, since each rig costs a
+// network round trip to its remote.
+func (c *DefaultBranchAllRigsCheck) CacheTTL() time.Duration {
+	return time.Hour
+}
+
 // Run checks default_branch for every discovered rig.
 func (c *DefaultBranchAllRigsCheck) Run(ctx *CheckContext) *CheckResult {
 	entries, err := os.ReadDir(ctx.TownRoot)
@@ -1772,6 +1872,32 @@ func (c *BareRepoExistsCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the push URL update, clone, and worktree
+// re-registrations Fix would perform.
+func (c *BareRepoExistsCheck) PreviewFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+	bareRepoPath := filepath.Join(ctx.RigPath(), ".repo.git")
+	_, statErr := os.Stat(bareRepoPath)
+
+	var planned []string
+	if c.pushURLMismatch && statErr == nil {
+		planned = append(planned, fmt.Sprintf("set push URL of %s from config.json push_url", bareRepoPath))
+	}
+	if len(c.brokenWorktrees) == 0 {
+		return planned
+	}
+	if statErr != nil {
+		planned = append(planned, fmt.Sprintf("git clone --bare <config.json git_url> %s", bareRepoPath))
+	}
+	for _, relPath := range c.brokenWorktrees {
+		planned = append(planned, fmt.Sprintf("re-register worktree %s in %s",
+			filepath.Join(ctx.RigPath(), relPath), filepath.Join(bareRepoPath, "worktrees")))
+	}
+	return planned
+}
+
 // findWorktreeDirs returns paths to directories that may be git worktrees within a rig.
 // Checks refinery/rig and all polecat worktree directories.
 func (c *BareRepoExistsCheck) findWorktreeDirs(rigPath, rigName string) []string {
@@ -34,6 +34,7 @@ type rigConfigLocal struct {
 	Version   int                  `json:"version"`
 	Name      string               `json:"name"`
 	GitURL    string               `json:"git_url"`
+	PushURL   string               `json:"push_url,omitempty"`
 	LocalRepo string               `json:"local_repo,omitempty"`
 	CreatedAt json.RawMessage      `json:"created_at"`
 	Beads     *rigConfigBeadsLocal `json:"beads,omitempty"`
@@ -166,3 +167,29 @@ func (c *RigNameMismatchCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix describes the config.json fields Fix would rewrite.
+func (c *RigNameMismatchCheck) PreviewFix(ctx *CheckContext) []string {
+	if ctx.RigName == "" {
+		return nil
+	}
+	rigPath := ctx.RigPath()
+	cfg, err := loadRigConfigLocal(rigPath)
+	if err != nil {
+		return nil
+	}
+	configPath := filepath.Join(rigPath, "config.json")
+
+	var planned []string
+	if cfg.Name != ctx.RigName {
+		planned = append(planned, fmt.Sprintf("set name in %s: %q -> %q", configPath, cfg.Name, ctx.RigName))
+	}
+	rigsConfig, rigsErr := loadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json"))
+	if rigsErr == nil && cfg.Beads != nil && cfg.Beads.Prefix != "" {
+		if entry, ok := rigsConfig.Rigs[ctx.RigName]; ok && entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" &&
+			cfg.Beads.Prefix != entry.BeadsConfig.Prefix {
+			planned = append(planned, fmt.Sprintf("set beads prefix in %s: %q -> %q", configPath, cfg.Beads.Prefix, entry.BeadsConfig.Prefix))
+		}
+	}
+	return planned
+}
@@ -120,6 +120,15 @@ func (c *RigRoutesJSONLCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the rig-level routes.jsonl files Fix would delete.
+func (c *RigRoutesJSONLCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, info := range c.affectedRigs {
+		planned = append(planned, "remove "+info.routesPath)
+	}
+	return planned
+}
+
 // findRigDirectories finds all rig directories in the town.
 func (c *RigRoutesJSONLCheck) findRigDirectories(townRoot string) []string {
 	var rigDirs []string
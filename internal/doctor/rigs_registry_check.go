@@ -0,0 +1,375 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RigsRegistryValidCheck verifies the structure of mayor/rigs.json: the
+// schema, duplicate rig names, entries whose directory is gone, and rig
+// directories nobody registered.
+type RigsRegistryValidCheck struct {
+	FixableCheck
+	duplicates   []string // Names with more than one entry
+	missingRigs  []string // Registered, but no directory
+	unregistered []string // Rig directories not in the registry
+}
+
+// NewRigsRegistryValidCheck creates a new rigs registry validation check.
+func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
+	return &RigsRegistryValidCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "rigs-registry-valid",
+				CheckDescription: "Check mayor/rigs.json schema and that it matches the rig directories",
+				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"rigs-registry-exists"},
+			},
+		},
+	}
+}
+
+// reservedRigNames can't be rigs (see rig.Manager.AddRig).
+var reservedRigNames = []string{"hq"}
+
+// Run validates mayor/rigs.json against the rig directories.
+func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
+	c.duplicates, c.missingRigs, c.unregistered = nil, nil, nil
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+
+	data, err := os.ReadFile(rigsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusOK,
+				Message: "No rigs.json (skipping validation)",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Cannot read mayor/rigs.json",
+			Details: []string{err.Error()},
+		}
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "mayor/rigs.json is not valid JSON",
+			Details: []string{err.Error()},
+			FixHint: "Fix JSON syntax in mayor/rigs.json",
+		}
+	}
+
+	schemaErrs, warnings := validateRigsSchema(top)
+	if len(schemaErrs) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("mayor/rigs.json has %d schema error(s)", len(schemaErrs)),
+			Details: append(schemaErrs, warnings...),
+			FixHint: "Fix the listed fields in mayor/rigs.json by hand",
+		}
+	}
+
+	var registry config.RigsConfig
+	_ = json.Unmarshal(data, &registry) // Checked by validateRigsSchema
+
+	c.duplicates = duplicateRigNames(data)
+	for _, name := range sortedRigNames(registry.Rigs) {
+		if _, err := os.Stat(filepath.Join(ctx.TownRoot, name)); os.IsNotExist(err) {
+			c.missingRigs = append(c.missingRigs, name)
+		}
+	}
+	c.unregistered = unregisteredRigDirs(ctx.TownRoot, registry.Rigs)
+
+	var details []string
+	for _, name := range c.duplicates {
+		details = append(details, fmt.Sprintf("Duplicate entry: %s (only the last one is used)", name))
+	}
+	for _, name := range c.missingRigs {
+		details = append(details, fmt.Sprintf("Missing rig directory: %s/", name))
+	}
+	for _, name := range c.unregistered {
+		details = append(details, fmt.Sprintf("Unregistered rig directory: %s/", name))
+	}
+	fixable := len(details)
+	details = append(details, warnings...)
+
+	if len(details) == 0 {
+		if len(registry.Rigs) == 0 {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusOK,
+				Message: "No rigs registered",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("All %d registered rig(s) exist", len(registry.Rigs)),
+		}
+	}
+
+	var parts []string
+	if n := len(c.missingRigs); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d registered rig(s) missing", n, len(registry.Rigs)))
+	}
+	if n := len(c.unregistered); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unregistered rig(s)", n))
+	}
+	if n := len(c.duplicates); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d duplicate name(s)", n))
+	}
+	if n := len(warnings); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d entry problem(s)", n))
+	}
+	hint := ""
+	if fixable > 0 {
+		hint = "Run 'gt doctor --fix' to register or remove entries (asks before each change)"
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: strings.Join(parts, ", "),
+		Details: details,
+		FixHint: hint,
+	}
+}
+
+// Fix registers unregistered rig directories, removes entries whose
+// directory is gone and drops duplicate entries, asking before each change.
+func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
+	if len(c.duplicates) == 0 && len(c.missingRigs) == 0 && len(c.unregistered) == 0 {
+		return nil
+	}
+
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	data, err := os.ReadFile(rigsPath)
+	if err != nil {
+		return fmt.Errorf("reading rigs.json: %w", err)
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return fmt.Errorf("parsing rigs.json: %w", err)
+	}
+	rigs := map[string]json.RawMessage{}
+	if raw, ok := top["rigs"]; ok {
+		if err := json.Unmarshal(raw, &rigs); err != nil {
+			return fmt.Errorf("parsing rigs.json: %w", err)
+		}
+	}
+
+	// Rewriting the file keeps only the last entry of each duplicated name
+	changed := false
+	if len(c.duplicates) > 0 {
+		if !ctx.Confirmed(fmt.Sprintf("Rewrite rigs.json keeping only the last entry for %s?", strings.Join(c.duplicates, ", "))) {
+			return nil
+		}
+		changed = true
+	}
+	for _, name := range c.missingRigs {
+		if ctx.Confirmed(fmt.Sprintf("Remove rig %q from rigs.json (no %s/ directory)?", name, name)) {
+			delete(rigs, name)
+			changed = true
+		}
+	}
+	for _, name := range c.unregistered {
+		if !ctx.Confirmed(fmt.Sprintf("Register %s/ in rigs.json?", name)) {
+			continue
+		}
+		entry, err := rigEntryFromDir(filepath.Join(ctx.TownRoot, name))
+		if err != nil {
+			return fmt.Errorf("registering %s: %w", name, err)
+		}
+		rigs[name] = entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	raw, err := json.Marshal(rigs)
+	if err != nil {
+		return fmt.Errorf("marshaling rigs.json: %w", err)
+	}
+	top["rigs"] = raw
+	newData, err := json.MarshalIndent(top, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling rigs.json: %w", err)
+	}
+	return os.WriteFile(rigsPath, newData, 0644)
+}
+
+// PreviewFix lists the registry changes Fix would offer.
+func (c *RigsRegistryValidCheck) PreviewFix(ctx *CheckContext) []string {
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	var planned []string
+	for _, name := range c.duplicates {
+		planned = append(planned, fmt.Sprintf("drop all but the last %q entry from %s", name, rigsPath))
+	}
+	for _, rig := range c.missingRigs {
+		planned = append(planned, fmt.Sprintf("remove missing rig %q from %s", rig, rigsPath))
+	}
+	for _, name := range c.unregistered {
+		planned = append(planned, fmt.Sprintf("register %s/ in %s", name, rigsPath))
+	}
+	return planned
+}
+
+// validateRigsSchema checks rigs.json against config.RigsConfig. Errors make
+// the file unusable; warnings are entries gt can load but not use well.
+func validateRigsSchema(top map[string]json.RawMessage) (errs, warnings []string) {
+	var version int
+	if raw, ok := top["version"]; !ok {
+		errs = append(errs, "Missing field: version")
+	} else if err := json.Unmarshal(raw, &version); err != nil {
+		errs = append(errs, fmt.Sprintf("version: expected a number, got %s", raw))
+	} else if version < 1 || version > config.CurrentRigsVersion {
+		errs = append(errs, fmt.Sprintf("version: got %d, supported 1 to %d", version, config.CurrentRigsVersion))
+	}
+
+	raw, ok := top["rigs"]
+	if !ok {
+		return append(errs, "Missing field: rigs"), nil
+	}
+	var rigs map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rigs); err != nil {
+		return append(errs, "rigs: expected an object of rig entries"), nil
+	}
+
+	prefixes := map[string][]string{}
+	for _, name := range sortedRigNames(rigs) {
+		var entry config.RigEntry
+		if err := json.Unmarshal(rigs[name], &entry); err != nil {
+			errs = append(errs, fmt.Sprintf("rig %s: %v", name, err))
+			continue
+		}
+		if strings.ContainsAny(name, "-. ") || name == "" {
+			warnings = append(warnings, fmt.Sprintf("Invalid rig name %q: hyphens, dots and spaces are reserved", name))
+		}
+		for _, reserved := range reservedRigNames {
+			if strings.EqualFold(name, reserved) {
+				warnings = append(warnings, fmt.Sprintf("Invalid rig name %q: reserved for town-level infrastructure", name))
+			}
+		}
+		if entry.GitURL == "" && entry.LocalRepo == "" {
+			warnings = append(warnings, fmt.Sprintf("rig %s: no git_url or local_repo", name))
+		}
+		if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+			prefixes[entry.BeadsConfig.Prefix] = append(prefixes[entry.BeadsConfig.Prefix], name)
+		}
+	}
+	for _, prefix := range sortedRigNames(prefixes) {
+		if names := prefixes[prefix]; len(names) > 1 {
+			warnings = append(warnings, fmt.Sprintf("Beads prefix %q used by rigs %s", prefix, strings.Join(names, ", ")))
+		}
+	}
+	return errs, warnings
+}
+
+// duplicateRigNames returns the names that appear more than once in the
+// rigs object. encoding/json keeps the last silently.
+func duplicateRigNames(data []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if tok != "rigs" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil
+		}
+		seen := map[string]int{}
+		var dups []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return dups
+			}
+			name, _ := tok.(string)
+			if seen[name]++; seen[name] == 2 {
+				dups = append(dups, name)
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return dups
+			}
+		}
+		sort.Strings(dups)
+		return dups
+	}
+	return nil
+}
+
+// unregisteredRigDirs returns the town's directories holding a rig
+// config.json that aren't in the registry.
+func unregisteredRigDirs(townRoot string, registered map[string]config.RigEntry) []string {
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || strings.HasPrefix(name, ".") || name == "mayor" {
+			continue
+		}
+		if _, ok := registered[name]; ok {
+			continue
+		}
+		if cfg, err := loadRigConfigLocal(filepath.Join(townRoot, name)); err == nil && cfg.Type == "rig" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// rigEntryFromDir builds a registry entry from a rig's own config.json.
+func rigEntryFromDir(rigPath string) (json.RawMessage, error) {
+	cfg, err := loadRigConfigLocal(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	entry := config.RigEntry{
+		GitURL:    cfg.GitURL,
+		PushURL:   cfg.PushURL,
+		LocalRepo: cfg.LocalRepo,
+		AddedAt:   time.Now(),
+	}
+	if cfg.Beads != nil {
+		entry.BeadsConfig = &config.BeadsConfig{Prefix: cfg.Beads.Prefix}
+	}
+	return json.Marshal(entry)
+}
+
+func sortedRigNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
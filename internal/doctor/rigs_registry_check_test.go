@@ -0,0 +1,122 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRigsRegistry(t *testing.T, townRoot, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRigsRegistryValidCheck_FindsDriftAndFixesWhatIsConfirmed(t *testing.T) {
+	townRoot := t.TempDir()
+	writeRigsRegistry(t, townRoot, `{
+  "version": 1,
+  "rigs": {
+    "gastown": {"git_url": "https://example.com/old.git", "added_at": "2026-01-01T00:00:00Z"},
+    "gone": {"git_url": "https://example.com/gone.git", "added_at": "2026-01-01T00:00:00Z"},
+    "gastown": {"git_url": "https://example.com/gastown.git", "added_at": "2026-01-01T00:00:00Z"}
+  }
+}`)
+	for _, dir := range []string{"gastown", "beads", "docs"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigCfg := `{"type": "rig", "name": "beads", "git_url": "https://example.com/beads.git", "beads": {"prefix": "bd"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "beads", "config.json"), []byte(rigCfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewRigsRegistryValidCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want warning: %s", result.Status, result.Message)
+	}
+	got := strings.Join(result.Details, "\n")
+	for _, want := range []string{"Duplicate entry: gastown", "Missing rig directory: gone/", "Unregistered rig directory: beads/"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("details missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "docs/") {
+		t.Errorf("docs/ has no rig config.json but was reported:\n%s", got)
+	}
+
+	// Keep the stale entry, register the new rig
+	var asked []string
+	ctx.Confirm = func(q string) bool {
+		asked = append(asked, q)
+		return !strings.HasPrefix(q, "Remove")
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(asked) != 3 {
+		t.Errorf("asked %d questions, want 3: %v", len(asked), asked)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json"))
+	var registry struct {
+		Rigs map[string]struct {
+			GitURL string `json:"git_url"`
+			Beads  *struct {
+				Prefix string `json:"prefix"`
+			} `json:"beads"`
+		} `json:"rigs"`
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := registry.Rigs["gone"]; !ok {
+		t.Error("declined removal of gone was applied")
+	}
+	if registry.Rigs["gastown"].GitURL != "https://example.com/gastown.git" {
+		t.Errorf("duplicate resolved to %q, want the last entry", registry.Rigs["gastown"].GitURL)
+	}
+	if b := registry.Rigs["beads"]; b.GitURL != "https://example.com/beads.git" || b.Beads == nil || b.Beads.Prefix != "bd" {
+		t.Errorf("beads registered as %+v", b)
+	}
+
+	result = check.Run(&CheckContext{TownRoot: townRoot})
+	if strings.Contains(strings.Join(result.Details, "\n"), "Duplicate") || len(check.unregistered) != 0 {
+		t.Errorf("after fix: %s %v", result.Message, result.Details)
+	}
+}
+
+func TestRigsRegistryValidCheck_SchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing version", `{"rigs": {}}`, "Missing field: version"},
+		{"future version", `{"version": 99, "rigs": {}}`, "version: got 99"},
+		{"rigs not an object", `{"version": 1, "rigs": []}`, "rigs: expected an object"},
+		{"bad entry", `{"version": 1, "rigs": {"gastown": {"git_url": 7}}}`, "rig gastown:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			writeRigsRegistry(t, townRoot, tt.content)
+			result := NewRigsRegistryValidCheck().Run(&CheckContext{TownRoot: townRoot})
+			if result.Status != StatusError {
+				t.Fatalf("Status = %v, want error", result.Status)
+			}
+			if !strings.Contains(strings.Join(result.Details, "\n"), tt.want) {
+				t.Errorf("details %v, want %q", result.Details, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -302,11 +303,44 @@ func isRedirectDependent(townRoot, routePath string) bool {
 
 // Fix attempts to add missing routing entries and rewrite suboptimal ones.
 func (c *RoutesCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.planRoutes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, warning := range plan.warnings {
+		logger.Warn(warning)
+	}
+	if len(plan.changes) > 0 {
+		return beads.WriteRoutes(plan.beadsDir, plan.routes)
+	}
+	return nil
+}
+
+// PreviewFix lists the route additions and rewrites Fix would make.
+func (c *RoutesCheck) PreviewFix(ctx *CheckContext) []string {
+	plan, err := c.planRoutes(ctx)
+	if err != nil {
+		return nil
+	}
+	return append(plan.changes, plan.warnings...)
+}
+
+// routesPlan is the routes.jsonl content Fix would write, with one
+// description per change and any routes it has to leave alone.
+type routesPlan struct {
+	beadsDir string
+	routes   []beads.Route
+	changes  []string
+	warnings []string
+}
+
+// planRoutes computes the fixed routes.jsonl without writing it.
+func (c *RoutesCheck) planRoutes(ctx *CheckContext) (*routesPlan, error) {
 	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
 
 	// Ensure .beads directory exists
 	if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
-		return fmt.Errorf(".beads directory does not exist; run 'bd init' first")
+		return nil, fmt.Errorf(".beads directory does not exist; run 'bd init' first")
 	}
 
 	// Load existing routes
@@ -314,6 +348,11 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	if err != nil {
 		routes = []beads.Route{} // Start fresh if can't load
 	}
+	plan := &routesPlan{beadsDir: beadsDir}
+	addRoute := func(prefix, path string) {
+		routes = append(routes, beads.Route{Prefix: prefix, Path: path})
+		plan.changes = append(plan.changes, fmt.Sprintf("add route %s -> %s", prefix, path))
+	}
 
 	// Build map of existing prefixes to route index for fast lookup.
 	// NOTE: routeMap indices are only valid as long as routes is append-only
@@ -325,19 +364,16 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 
 	// Ensure town root route exists (hq- -> .)
 	// This is normally created by gt install but may be missing if routes.jsonl was corrupted
-	modified := false
 	if _, exists := routeMap["hq-"]; !exists {
 		routeMap["hq-"] = len(routes)
-		routes = append(routes, beads.Route{Prefix: "hq-", Path: "."})
-		modified = true
+		addRoute("hq-", ".")
 	}
 
 	// Ensure convoy route exists (hq-cv- -> .)
 	// Convoys use hq-cv-* IDs for visual distinction from other town beads
 	if _, exists := routeMap["hq-cv-"]; !exists {
 		routeMap["hq-cv-"] = len(routes)
-		routes = append(routes, beads.Route{Prefix: "hq-cv-", Path: "."})
-		modified = true
+		addRoute("hq-cv-", ".")
 	}
 
 	// Load rigs registry
@@ -345,10 +381,8 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
 	if err != nil {
 		// No rigs config - just write town root route if we added it
-		if modified {
-			return beads.WriteRoutes(beadsDir, routes)
-		}
-		return nil
+		plan.routes = routes
+		return plan, nil
 	}
 
 	// Collect prefixes from rigs to detect duplicates (finding #5).
@@ -366,7 +400,13 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	// the specific legacy pattern broken by beads#1749. Routes are rewritten
 	// to the canonical path (e.g., "crom/mayor/rig") which has a real .beads
 	// directory and needs no redirect resolution.
-	for rigName, rigEntry := range rigsConfig.Rigs {
+	rigNames := make([]string, 0, len(rigsConfig.Rigs))
+	for rigName := range rigsConfig.Rigs {
+		rigNames = append(rigNames, rigName)
+	}
+	sort.Strings(rigNames)
+	for _, rigName := range rigNames {
+		rigEntry := rigsConfig.Rigs[rigName]
 		prefix := ""
 		if rigEntry.BeadsConfig != nil && rigEntry.BeadsConfig.Prefix != "" {
 			prefix = rigEntry.BeadsConfig.Prefix + "-"
@@ -378,8 +418,8 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 
 		// Skip duplicate prefixes to avoid non-deterministic rewrites
 		if prefixCount[prefix] > 1 {
-			fmt.Fprintf(os.Stderr, "Warning: skipping route fix for duplicate prefix %s (%d rigs share it)\n",
-				prefix, prefixCount[prefix])
+			plan.warnings = append(plan.warnings, fmt.Sprintf("skipping route fix for duplicate prefix %s (%d rigs share it)",
+				prefix, prefixCount[prefix]))
 			continue
 		}
 
@@ -392,29 +432,23 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 			// and canonical target has a real .beads directory (not a redirect).
 			if routes[idx].Path != rigRoutePath && isRedirectDependent(ctx.TownRoot, routes[idx].Path) {
 				if hasRealBeadsDir(canonicalPath) {
+					plan.changes = append(plan.changes, fmt.Sprintf("rewrite route %s: %s -> %s",
+						prefix, routes[idx].Path, rigRoutePath))
 					routes[idx].Path = rigRoutePath
-					modified = true
 				} else {
-					fmt.Fprintf(os.Stderr, "Warning: cannot rewrite route %s -> %s to %s (canonical path has no .beads directory)\n",
-						prefix, routes[idx].Path, rigRoutePath)
+					plan.warnings = append(plan.warnings, fmt.Sprintf("cannot rewrite route %s -> %s to %s (canonical path has no .beads directory)",
+						prefix, routes[idx].Path, rigRoutePath))
 				}
 			}
 		} else {
 			// Route missing — add it if the canonical path has a real .beads dir
 			if hasRealBeadsDir(canonicalPath) {
 				routeMap[prefix] = len(routes)
-				routes = append(routes, beads.Route{
-					Prefix: prefix,
-					Path:   rigRoutePath,
-				})
-				modified = true
+				addRoute(prefix, rigRoutePath)
 			}
 		}
 	}
 
-	if modified {
-		return beads.WriteRoutes(beadsDir, routes)
-	}
-
-	return nil
+	plan.routes = routes
+	return plan, nil
 }
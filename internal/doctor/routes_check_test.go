@@ -226,6 +226,32 @@ func TestRoutesCheck_FixRestoresTownRoute(t *testing.T) {
 	})
 }
 
+func TestRoutesCheck_PreviewFix(t *testing.T) {
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	routesPath := filepath.Join(beadsDir, "routes.jsonl")
+	if err := os.WriteFile(routesPath, []byte(`{"prefix":"hq-","path":"."}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewRoutesCheck()
+	planned := check.PreviewFix(&CheckContext{TownRoot: tmpDir})
+	if len(planned) != 1 || planned[0] != "add route hq-cv- -> ." {
+		t.Errorf("planned = %q, want [add route hq-cv- -> .]", planned)
+	}
+
+	content, err := os.ReadFile(routesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != `{"prefix":"hq-","path":"."}`+"\n" {
+		t.Errorf("PreviewFix must not modify routes.jsonl, got %q", content)
+	}
+}
+
 func TestRoutesCheck_DirectLayoutRig(t *testing.T) {
 	t.Run("Run matches direct-layout rig correctly", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -133,6 +133,15 @@ func (c *RoutingModeCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix lists the beads databases Fix would switch to explicit routing.
+func (c *RoutingModeCheck) PreviewFix(ctx *CheckContext) []string {
+	planned := []string{"bd config set routing.mode explicit (town beads)"}
+	if ctx.RigName != "" {
+		planned = append(planned, fmt.Sprintf("bd config set routing.mode explicit (%s beads)", ctx.RigName))
+	}
+	return planned
+}
+
 // setRoutingMode sets routing.mode to "explicit" in the specified beads directory.
 func (c *RoutingModeCheck) setRoutingMode(beadsDir string) error {
 	cmd := exec.Command("bd", "config", "set", "routing.mode", "explicit")
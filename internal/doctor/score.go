@@ -0,0 +1,215 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ScoreConfigPath returns the town's doctor scoring config.
+func ScoreConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "doctor-score.json")
+}
+
+// ScoreConfig is the scoring model: how much each check counts toward the
+// health score and how much credit a warning earns. A check's weight is its
+// entry in Checks, else its category's in Categories, else 1. A weight of 0
+// leaves the check out of the score.
+type ScoreConfig struct {
+	WarningCredit *float64           `json:"warning_credit,omitempty"` // 0..1; default 0.5
+	Categories    map[string]float64 `json:"categories,omitempty"`
+	Checks        map[string]float64 `json:"checks,omitempty"`
+}
+
+// defaultCategoryWeights weigh checks that keep the town running above
+// housekeeping.
+var defaultCategoryWeights = map[string]float64{
+	CategoryCore:           3,
+	CategoryInfrastructure: 2,
+	CategoryRig:            2,
+	CategoryPatrol:         1,
+	CategoryConfig:         1,
+	CategoryCleanup:        0.5,
+	CategoryHooks:          1,
+}
+
+// defaultWarningCredit is the share of a check's weight a warning earns.
+const defaultWarningCredit = 0.5
+
+// LoadScoreConfig reads the town's scoring config. A missing file gives the
+// defaults; an unreadable one is an error so a typo doesn't silently change
+// the score.
+func LoadScoreConfig(townRoot string) (*ScoreConfig, error) {
+	cfg := &ScoreConfig{}
+	path := ScoreConfigPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if c := cfg.WarningCredit; c != nil && (*c < 0 || *c > 1) {
+		return nil, fmt.Errorf("%s: warning_credit must be between 0 and 1", path)
+	}
+	return cfg, nil
+}
+
+// Weight returns how much a check counts toward the score.
+func (c *ScoreConfig) Weight(name, category string) float64 {
+	if w, ok := c.Checks[name]; ok {
+		return w
+	}
+	if w, ok := c.Categories[category]; ok {
+		return w
+	}
+	if w, ok := defaultCategoryWeights[category]; ok {
+		return w
+	}
+	return 1
+}
+
+func (c *ScoreConfig) warningCredit() float64 {
+	if c.WarningCredit != nil {
+		return *c.WarningCredit
+	}
+	return defaultWarningCredit
+}
+
+// Score returns the weighted health of a run, 0 to 100: a passing check
+// earns its full weight, a warning the warning credit, an error nothing.
+// ok is false when no check carries weight.
+func (c *ScoreConfig) Score(checks []HistoryCheck) (score int, ok bool) {
+	var earned, total float64
+	for _, check := range checks {
+		w := c.Weight(check.Name, check.Category)
+		if w <= 0 {
+			continue
+		}
+		total += w
+		switch check.Status {
+		case StatusOK.String():
+			earned += w
+		case StatusWarning.String():
+			earned += w * c.warningCredit()
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return int(math.Round(100 * earned / total)), true
+}
+
+// HealthScore is the score of the latest doctor run for the town or a rig.
+type HealthScore struct {
+	Rig       string    `json:"rig,omitempty"` // empty for the town
+	Score     int       `json:"score"`
+	Errors    int       `json:"errors"`
+	Warnings  int       `json:"warnings"`
+	Timestamp time.Time `json:"timestamp"` // when the scored run happened
+}
+
+// LatestScores scores the newest run of each scope in history: the town
+// (plain 'gt doctor') first, then each rig checked with --rig, by name.
+func LatestScores(history []*HistoryEntry, cfg *ScoreConfig) []HealthScore {
+	latest := make(map[string]*HistoryEntry)
+	for _, e := range history {
+		if cur, ok := latest[e.Rig]; !ok || !e.Timestamp.Before(cur.Timestamp) {
+			latest[e.Rig] = e
+		}
+	}
+	var scores []HealthScore
+	for rig, e := range latest {
+		score, ok := cfg.Score(e.Checks)
+		if !ok {
+			continue
+		}
+		scores = append(scores, HealthScore{
+			Rig:       rig,
+			Score:     score,
+			Errors:    e.Summary.Errors,
+			Warnings:  e.Summary.Warnings,
+			Timestamp: e.Timestamp,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Rig < scores[j].Rig })
+	return scores
+}
+
+// ScoreColor returns the badge color for a score.
+func ScoreColor(score int) string {
+	switch {
+	case score >= 90:
+		return "#4c1" // bright green
+	case score >= 75:
+		return "#97ca00" // green
+	case score >= 60:
+		return "#dfb317" // yellow
+	case score >= 40:
+		return "#fe7d37" // orange
+	}
+	return "#e05d44" // red
+}
+
+// BadgeSVG renders a flat "label | score" badge in the common shields style,
+// for embedding in a README or wiki.
+func BadgeSVG(label string, score int) []byte {
+	value := fmt.Sprintf("%d%%", score)
+	// Approximate Verdana 11px text widths; badges need not be pixel exact.
+	textWidth := func(s string) int { return len(s)*7 + 10 }
+	lw, vw := textWidth(label), textWidth(value)
+	w := lw + vw
+	label, value = html.EscapeString(label), html.EscapeString(value)
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+  <title>%[4]s: %[5]s</title>
+  <linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+  <clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%[2]d" height="20" fill="#555"/>
+    <rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>
+    <rect width="%[1]d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%[7]d" y="14">%[4]s</text>
+    <text x="%[8]d" y="14">%[5]s</text>
+  </g>
+</svg>
+`, w, lw, vw, label, value, ScoreColor(score), lw/2, lw+vw/2))
+}
+
+// BadgePath returns where each doctor run writes the badge of its scope.
+func BadgePath(townRoot, rig string) string {
+	name := "doctor-badge.svg"
+	if rig != "" {
+		name = "doctor-badge-" + rig + ".svg"
+	}
+	return filepath.Join(townRoot, ".runtime", name)
+}
+
+// WriteBadge writes the badge of a score to path.
+func WriteBadge(path, label string, score int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating badge dir: %w", err)
+	}
+	if err := os.WriteFile(path, BadgeSVG(label, score), 0644); err != nil { //nolint:gosec // G306: meant to be published
+		return fmt.Errorf("writing badge: %w", err)
+	}
+	return nil
+}
+
+// BadgeLabel is the badge label of a scope.
+func BadgeLabel(rig string) string {
+	if rig == "" {
+		return "town health"
+	}
+	return rig + " health"
+}
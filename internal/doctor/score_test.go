@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScoreWeights(t *testing.T) {
+	checks := []HistoryCheck{
+		{Name: "town-config-valid", Category: CategoryCore, Status: "OK"},          // 3 of 3
+		{Name: "dolt-server", Category: CategoryInfrastructure, Status: "Warning"}, // 1 of 2
+		{Name: "orphan-sessions", Category: CategoryCleanup, Status: "Error"},      // 0 of 0.5
+	}
+	cfg := &ScoreConfig{}
+	if score, ok := cfg.Score(checks); !ok || score != 73 { // 4 / 5.5
+		t.Errorf("default score = %d, %v; want 73", score, ok)
+	}
+
+	credit := 0.0
+	cfg = &ScoreConfig{
+		WarningCredit: &credit,
+		Categories:    map[string]float64{CategoryCleanup: 0},
+		Checks:        map[string]float64{"dolt-server": 1},
+	}
+	if score, _ := cfg.Score(checks); score != 75 { // 3 / 4
+		t.Errorf("configured score = %d, want 75", score)
+	}
+
+	if _, ok := (&ScoreConfig{Checks: map[string]float64{"only": 0}}).Score([]HistoryCheck{{Name: "only", Status: "OK"}}); ok {
+		t.Error("run without weighted checks was scored")
+	}
+}
+
+func TestLoadScoreConfig(t *testing.T) {
+	town := t.TempDir()
+	if cfg, err := LoadScoreConfig(town); err != nil || cfg.Weight("x", CategoryCore) != 3 {
+		t.Fatalf("missing config = %+v, %v; want defaults", cfg, err)
+	}
+	if err := os.MkdirAll(filepath.Join(town, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ScoreConfigPath(town), []byte(`{"warning_credit": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadScoreConfig(town); err == nil {
+		t.Error("warning_credit 2 was accepted")
+	}
+}
+
+func TestLatestScores(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	history := []*HistoryEntry{
+		{Timestamp: t0, Checks: []HistoryCheck{{Name: "a", Status: "Error"}}},
+		{Timestamp: t0.Add(time.Hour), Rig: "gastown", Checks: []HistoryCheck{{Name: "a", Status: "Warning"}}},
+		{Timestamp: t0.Add(2 * time.Hour), Checks: []HistoryCheck{{Name: "a", Status: "OK"}}},
+	}
+	scores := LatestScores(history, &ScoreConfig{})
+	if len(scores) != 2 || scores[0].Rig != "" || scores[0].Score != 100 || scores[1].Rig != "gastown" || scores[1].Score != 50 {
+		t.Errorf("scores = %+v", scores)
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	svg := string(BadgeSVG("town <health>", 42))
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "42%") || !strings.Contains(svg, ScoreColor(42)) {
+		t.Errorf("badge = %s", svg)
+	}
+	if strings.Contains(svg, "<health>") {
+		t.Error("label was not escaped")
+	}
+}
@@ -176,6 +176,20 @@ func (c *MalformedSessionNameCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the sessions Fix would rename. Crew sessions are
+// reported as needing a manual rename.
+func (c *MalformedSessionNameCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, r := range c.malformed {
+		if r.isCrew {
+			planned = append(planned, fmt.Sprintf("skip crew session %s (rename manually to %s)", r.oldName, r.newName))
+			continue
+		}
+		planned = append(planned, fmt.Sprintf("rename tmux session %s -> %s", r.oldName, r.newName))
+	}
+	return planned
+}
+
 // knownRoleSuffixes are the simple role keywords that appear at the end of a
 // Gas Town session name (after the rig prefix).
 var knownRoleSuffixes = []string{"witness", "refinery"}
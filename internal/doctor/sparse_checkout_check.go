@@ -177,3 +177,12 @@ func (c *SparseCheckoutCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix lists the repos whose legacy sparse checkout Fix would remove.
+func (c *SparseCheckoutCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, repoPath := range c.affectedRepos {
+		planned = append(planned, "remove sparse checkout from "+repoPath)
+	}
+	return planned
+}
@@ -17,6 +17,7 @@ import (
 // The fix closes stale beads so they no longer pollute bd ready output.
 type StaleAgentBeadsCheck struct {
 	FixableCheck
+	stale []string // Stale agent bead IDs (cached for PreviewFix)
 }
 
 // NewStaleAgentBeadsCheck creates a new stale agent beads check.
@@ -34,6 +35,8 @@ func NewStaleAgentBeadsCheck() *StaleAgentBeadsCheck {
 
 // Run checks for agent beads that have no matching agent on disk.
 func (c *StaleAgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
 	// Load routes to get prefixes
 	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
 	routes, err := beads.LoadRoutes(beadsDir)
@@ -94,9 +97,8 @@ func (c *StaleAgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		crewPrefix := fmt.Sprintf("%s-%s-crew-", prefix, rigName)
 		polecatPrefix := fmt.Sprintf("%s-%s-polecat-", prefix, rigName)
 		allBeads, err := bd.List(beads.ListOptions{
-			Status:   "open",
-			Priority: -1,
-			Label:    "gt:agent",
+			Status: "open",
+			Label:  "gt:agent",
 		})
 		if err != nil {
 			continue
@@ -118,6 +120,7 @@ func (c *StaleAgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
+	c.stale = stale
 	if len(stale) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -184,3 +187,12 @@ func (c *StaleAgentBeadsCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix lists the stale agent beads Fix would close.
+func (c *StaleAgentBeadsCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, id := range c.stale {
+		planned = append(planned, "close stale agent bead "+id)
+	}
+	return planned
+}
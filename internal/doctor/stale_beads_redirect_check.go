@@ -180,6 +180,32 @@ func (c *StaleBeadsRedirectCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// PreviewFix describes the files Fix would remove and redirects it would write.
+func (c *StaleBeadsRedirectCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, relPath := range c.staleLocations {
+		beadsDir := filepath.Join(ctx.TownRoot, relPath)
+		for _, pattern := range staleFilePatterns {
+			matches, _ := filepath.Glob(filepath.Join(beadsDir, pattern))
+			for _, match := range matches {
+				planned = append(planned, "remove "+match)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(beadsDir, "mq")); err == nil {
+			planned = append(planned, "remove "+filepath.Join(beadsDir, "mq"))
+		}
+	}
+	for _, issue := range c.missingRedirects {
+		planned = append(planned, fmt.Sprintf("write %s -> %s",
+			filepath.Join(issue.worktreePath, ".beads", "redirect"), issue.expectedTarget))
+	}
+	for _, issue := range c.incorrectRedirects {
+		planned = append(planned, fmt.Sprintf("rewrite %s: %s -> %s",
+			filepath.Join(issue.worktreePath, ".beads", "redirect"), issue.currentTarget, issue.expectedTarget))
+	}
+	return planned
+}
+
 // findRigDirs returns all rig directories in the town.
 func findRigDirs(townRoot string) ([]string, error) {
 	var rigs []string
@@ -174,3 +174,13 @@ func (c *StaleTaskDispatchCheck) Fix(ctx *CheckContext) error {
 	}
 	return nil
 }
+
+// PreviewFix lists the settings files Fix would regenerate without the
+// task-dispatch hook.
+func (c *StaleTaskDispatchCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, target := range c.staleTargets {
+		planned = append(planned, fmt.Sprintf("rewrite hooks in %s without task-dispatch (%s)", target.Path, target.DisplayKey()))
+	}
+	return planned
+}
@@ -0,0 +1,152 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SuppressionsPath returns the doctor suppression file of a town or rig.
+func SuppressionsPath(dir string) string {
+	return filepath.Join(dir, "settings", "doctor-suppress.json")
+}
+
+// Suppression marks a warning as known and acceptable. Errors are never
+// suppressed.
+type Suppression struct {
+	Check  string `json:"check"`           // check name
+	Match  string `json:"match,omitempty"` // only findings containing this text; "" = every finding
+	Reason string `json:"reason"`          // why the warning is acceptable
+}
+
+type suppressionFile struct {
+	Suppress []Suppression `json:"suppress"`
+}
+
+// scopedSuppression is a Suppression and the rig whose file it came from
+// ("" for the town file). A rig's suppressions only cover findings about
+// that rig: details that mention it, or any finding when doctor runs with
+// --rig for it.
+type scopedSuppression struct {
+	Suppression
+	rig string
+}
+
+// Suppressions are the town's and rigs' suppression files, loaded together.
+type Suppressions struct {
+	rules []scopedSuppression
+}
+
+// LoadSuppressions reads the town suppression file and every rig's. Missing
+// files are fine; unreadable ones are errors so a typo doesn't silently
+// re-enable warnings.
+func LoadSuppressions(townRoot string) (*Suppressions, error) {
+	s := &Suppressions{}
+	dirs := map[string]string{townRoot: ""}
+	for _, rigPath := range findAllRigs(townRoot) {
+		dirs[rigPath] = filepath.Base(rigPath)
+	}
+	for dir, rig := range dirs {
+		path := SuppressionsPath(dir)
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		var f suppressionFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		for i, sup := range f.Suppress {
+			if sup.Check == "" {
+				return nil, fmt.Errorf("%s: suppression %d has no check", path, i+1)
+			}
+			s.rules = append(s.rules, scopedSuppression{Suppression: sup, rig: rig})
+		}
+	}
+	return s, nil
+}
+
+// covers reports whether rule suppresses finding (a detail line or, for a
+// result without details, its message) of check name.
+func (rule scopedSuppression) covers(ctx *CheckContext, name, finding string) bool {
+	if rule.Check != name {
+		return false
+	}
+	if rule.Match != "" && !strings.Contains(finding, rule.Match) {
+		return false
+	}
+	return rule.rig == "" || ctx.RigName == rule.rig || strings.Contains(finding, rule.rig)
+}
+
+// Apply returns result with its suppressed warnings removed. A warning whose
+// every finding is suppressed becomes OK; the result reports how many were
+// suppressed and why. result itself is not modified.
+func (s *Suppressions) Apply(ctx *CheckContext, result *CheckResult) *CheckResult {
+	if s == nil || len(s.rules) == 0 || result.Status != StatusWarning {
+		return result
+	}
+
+	var kept []string
+	var reasons []string
+	suppressed := 0
+	match := func(finding string) bool {
+		for _, rule := range s.rules {
+			if rule.covers(ctx, result.Name, finding) {
+				if rule.Reason != "" && !containsReason(reasons, rule.Reason) {
+					reasons = append(reasons, rule.Reason)
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(result.Details) == 0 {
+		if !match(result.Message) {
+			return result
+		}
+		suppressed = 1
+	} else {
+		for _, d := range result.Details {
+			if match(d) {
+				suppressed++
+			} else {
+				kept = append(kept, d)
+			}
+		}
+		if suppressed == 0 {
+			return result
+		}
+	}
+
+	out := *result
+	out.Suppressed = suppressed
+	if len(kept) == 0 {
+		out.Status = StatusOK
+		out.Message = "suppressed"
+		if len(reasons) > 0 {
+			out.Message += ": " + strings.Join(reasons, "; ")
+		}
+		out.Details = nil
+		out.FixHint = ""
+		return &out
+	}
+	out.Details = kept
+	out.Message = fmt.Sprintf("%s (%d suppressed)", result.Message, suppressed)
+	return &out
+}
+
+func containsReason(reasons []string, r string) bool {
+	for _, existing := range reasons {
+		if existing == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSuppressions(t *testing.T, dir, content string) {
+	t.Helper()
+	path := SuppressionsPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSuppressions_Apply(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeSuppressions(t, townRoot, `{"suppress": [{"check": "stale-binary", "reason": "pinned"}]}`)
+	writeSuppressions(t, rigPath, `{"suppress": [{"check": "orphan-sessions", "match": "legacy", "reason": "demo"}]}`)
+
+	s, err := LoadSuppressions(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	// Town rule: whole warning suppressed
+	got := s.Apply(ctx, &CheckResult{Name: "stale-binary", Status: StatusWarning, Message: "gt is stale"})
+	if got.Status != StatusOK || got.Suppressed != 1 || got.Message != "suppressed: pinned" {
+		t.Errorf("town rule: got %+v", got)
+	}
+
+	// Rig rule: only findings about the rig that match
+	warn := &CheckResult{
+		Name:    "orphan-sessions",
+		Status:  StatusWarning,
+		Message: "3 orphan sessions",
+		Details: []string{"gastown: legacy-1", "beads: legacy-2", "gastown: other"},
+	}
+	got = s.Apply(ctx, warn)
+	if got.Status != StatusWarning || got.Suppressed != 1 || len(got.Details) != 2 {
+		t.Errorf("rig rule: got %+v", got)
+	}
+	if got.Message != "3 orphan sessions (1 suppressed)" {
+		t.Errorf("message = %q", got.Message)
+	}
+	if len(warn.Details) != 3 {
+		t.Error("Apply modified its input")
+	}
+
+	// Errors are never suppressed
+	errResult := &CheckResult{Name: "stale-binary", Status: StatusError, Message: "gt missing"}
+	if got := s.Apply(ctx, errResult); got != errResult {
+		t.Errorf("error result was changed: %+v", got)
+	}
+}
+
+func TestLoadSuppressions_Invalid(t *testing.T) {
+	townRoot := t.TempDir()
+	writeSuppressions(t, townRoot, `{"suppress": [{"reason": "no check"}]}`)
+	if _, err := LoadSuppressions(townRoot); err == nil {
+		t.Error("expected error for suppression without a check")
+	}
+	writeSuppressions(t, townRoot, `{not json`)
+	if _, err := LoadSuppressions(townRoot); err == nil {
+		t.Error("expected error for malformed file")
+	}
+}
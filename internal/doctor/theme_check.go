@@ -99,6 +99,11 @@ func (c *ThemeCheck) Fix(ctx *CheckContext) error {
 	return cmd.Run()
 }
 
+// PreviewFix describes the theme command Fix would run.
+func (c *ThemeCheck) PreviewFix(ctx *CheckContext) []string {
+	return []string{"gt theme apply --all"}
+}
+
 // getSessionStatusLeft retrieves the status-left setting for a tmux session.
 func getSessionStatusLeft(session string) (string, error) {
 	cmd := exec.Command("tmux", "show-options", "-t", session, "status-left")
@@ -132,6 +132,15 @@ func (c *LinkedPaneCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix lists the sessions Fix would kill.
+func (c *LinkedPaneCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, session := range c.linkedSessions {
+		planned = append(planned, "kill tmux session "+session)
+	}
+	return planned
+}
+
 // getSessionPanes returns all pane IDs for a session.
 func (c *LinkedPaneCheck) getSessionPanes(session string) ([]string, error) {
 	// Get pane IDs using tmux list-panes with format
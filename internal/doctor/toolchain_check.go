@@ -0,0 +1,105 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// RigToolchainCheck verifies that the toolchain versions pinned in each
+// rig's settings (gt rig pin) are what the rig's agents actually get. The
+// tools are run with the same environment the refinery and polecats use,
+// so a GOTOOLCHAIN pin is honored.
+type RigToolchainCheck struct {
+	BaseCheck
+}
+
+// NewRigToolchainCheck creates a new rig toolchain check.
+func NewRigToolchainCheck() *RigToolchainCheck {
+	return &RigToolchainCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "rig-toolchain",
+			CheckDescription: "Verify installed toolchains match rig pins",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run compares pinned and installed versions for every rig with pins.
+func (c *RigToolchainCheck) Run(ctx *CheckContext) *CheckResult {
+	rigPaths := findAllRigs(ctx.TownRoot)
+	if ctx.RigName != "" {
+		rigPaths = []string{ctx.RigPath()}
+	}
+
+	var details []string
+	pinnedRigs := 0
+	for _, rigPath := range rigPaths {
+		tc := config.LoadRigToolchain(rigPath)
+		if tc == nil {
+			continue
+		}
+		pinnedRigs++
+		env := appendEnv(os.Environ(), config.ToolchainEnv(tc))
+
+		rigName := filepath.Base(rigPath)
+		for _, tool := range config.ToolchainTools {
+			pin := tc.Get(tool)
+			if pin == "" {
+				continue
+			}
+			installed, err := deps.ToolVersion(tool, env)
+			if err != nil {
+				details = append(details, fmt.Sprintf("%s: %s pinned to %s, %v", rigName, tool, pin, err))
+				continue
+			}
+			if !deps.VersionMatches(pin, installed) {
+				details = append(details, fmt.Sprintf("%s: %s pinned to %s, found %s", rigName, tool, pin, installed))
+			}
+		}
+	}
+
+	if pinnedRigs == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No rigs pin toolchain versions",
+			Category: c.CheckCategory,
+		}
+	}
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("%d toolchain pin(s) not satisfied", len(details)),
+			Details:  details,
+			FixHint:  "Install the pinned versions, or update the pins with 'gt rig pin <rig> <tool>=<version>'",
+			Category: c.CheckCategory,
+		}
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusOK,
+		Message:  fmt.Sprintf("Toolchain pins satisfied in %d rig(s)", pinnedRigs),
+		Category: c.CheckCategory,
+	}
+}
+
+// appendEnv returns base with vars appended as KEY=value entries. Later
+// entries take precedence when the environment is passed to exec.
+func appendEnv(base []string, vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := append([]string(nil), base...)
+	for _, k := range keys {
+		env = append(env, k+"="+vars[k])
+	}
+	return env
+}
@@ -0,0 +1,38 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRigToolchainCheck(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not in PATH")
+	}
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "myrig")
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	check := NewRigToolchainCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	if r := check.Run(ctx); r.Status != StatusOK {
+		t.Fatalf("unpinned rig: %+v", r)
+	}
+
+	settings := config.NewRigSettings()
+	settings.Toolchain = &config.ToolchainConfig{Go: "0.1"}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	r := check.Run(ctx)
+	if r.Status != StatusWarning || len(r.Details) != 1 || !strings.HasPrefix(r.Details[0], "myrig: go pinned to 0.1") {
+		t.Errorf("mismatched pin: %+v", r)
+	}
+}
@@ -116,3 +116,11 @@ func (c *TownRootBranchCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
+
+// PreviewFix describes the checkout Fix would run.
+func (c *TownRootBranchCheck) PreviewFix(ctx *CheckContext) []string {
+	if c.currentBranch == "main" || c.currentBranch == "master" {
+		return nil
+	}
+	return []string{fmt.Sprintf("git checkout main (from %s, in %s; refused if uncommitted changes)", c.currentBranch, ctx.TownRoot)}
+}
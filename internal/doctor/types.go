@@ -63,6 +63,19 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+	NoCache         bool   // Ignore cached results of CacheableChecks and re-run them
+
+	// Confirm asks the user whether Fix should make one change. Nil when
+	// nobody can answer (no terminal): fixes then make every change.
+	Confirm func(question string) bool
+}
+
+// Confirmed reports whether Fix may make the change described by question.
+func (ctx *CheckContext) Confirmed(question string) bool {
+	if ctx.Confirm == nil {
+		return true
+	}
+	return ctx.Confirm(question)
 }
 
 // RigPath returns the full path to the rig directory.
@@ -87,6 +100,14 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+	CachedAt time.Time     // When the reused result was computed (zero if run fresh)
+
+	// Suppressed counts findings hidden by doctor-suppress.json files.
+	Suppressed int
+
+	// PlannedFixes lists the changes Fix would make, populated only in
+	// dry-run mode (see Doctor.FixDryRunStreaming).
+	PlannedFixes []string
 }
 
 // Check defines the interface for a health check.
@@ -108,6 +129,24 @@ type Check interface {
 	CanFix() bool
 }
 
+// FixPreviewer is implemented by fixable checks that can describe what Fix
+// would do without doing it. PreviewFix is called after Run (so cached
+// findings are available) and must not mutate anything. Each returned line
+// is one concrete change: a file written, lines appended, a command run.
+// Every built-in check that can fix must implement it.
+type FixPreviewer interface {
+	PreviewFix(ctx *CheckContext) []string
+}
+
+// Dependent is implemented by checks whose fix relies on other checks
+// passing first (e.g., creating beads needs metadata.json pointing bd at the
+// server). DependsOn returns the names of those checks. 'gt doctor --fix'
+// runs a check only after the checks it depends on have been fixed, and
+// skips its fix while one of them still fails.
+type Dependent interface {
+	DependsOn() []string
+}
+
 // ReportSummary summarizes the results of all checks.
 type ReportSummary struct {
 	Total       int
@@ -115,6 +154,8 @@ type ReportSummary struct {
 	Warnings    int
 	Errors      int
 	Fixed       int           // Checks that were auto-fixed
+	WouldFix    int           // Checks with planned fixes (dry-run only)
+	Suppressed  int           // Findings hidden by suppression files
 	Slow        int           // Checks that took longer than threshold (counted during Print)
 	SlowestName string        // Name of the slowest check
 	SlowestTime time.Duration // Duration of the slowest check
@@ -153,6 +194,7 @@ func (r *Report) Add(result *CheckResult) {
 	if result.Fixed {
 		r.Summary.Fixed++
 	}
+	r.Summary.Suppressed += result.Suppressed
 
 	// Track the slowest check
 	if result.Elapsed > r.Summary.SlowestTime {
@@ -341,6 +383,12 @@ func (r *Report) printSummary(w io.Writer, slowThreshold time.Duration) {
 	if r.Summary.Fixed > 0 {
 		summary += fmt.Sprintf("  🔧 %d fixed", r.Summary.Fixed)
 	}
+	if r.Summary.WouldFix > 0 {
+		summary += fmt.Sprintf("  📝 %d would fix", r.Summary.WouldFix)
+	}
+	if r.Summary.Suppressed > 0 {
+		summary += fmt.Sprintf("  🔇 %d suppressed", r.Summary.Suppressed)
+	}
 	if slowThreshold > 0 && r.Summary.Slow > 0 {
 		summary += fmt.Sprintf("  ⏳ %d slow (slowest: %s %s)",
 			r.Summary.Slow,
@@ -415,9 +463,31 @@ func (r *Report) printWarningsSection(w io.Writer, issues []*CheckResult) {
 		}
 	}
 
+	r.printPlannedFixes(w)
+
 	// If only fixed items, show success message
 	if len(failures) == 0 && len(warnings) == 0 {
 		_, _ = fmt.Fprintln(w)
 		_, _ = fmt.Fprintln(w, ui.RenderPass(ui.IconPass+" All remaining checks passed"))
 	}
 }
+
+// printPlannedFixes outputs the changes a dry-run fix would make.
+func (r *Report) printPlannedFixes(w io.Writer) {
+	if r.Summary.WouldFix == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, ui.RenderWarn("📝  PLANNED FIXES (dry run, nothing changed)"))
+	n := 0
+	for _, check := range r.Checks {
+		if len(check.PlannedFixes) == 0 {
+			continue
+		}
+		n++
+		_, _ = fmt.Fprintf(w, "  %s %s\n", ui.RenderMuted(fmt.Sprintf("%d.", n)), check.Name)
+		for _, change := range check.PlannedFixes {
+			_, _ = fmt.Fprintf(w, "        %s%s\n", ui.MutedStyle.Render(ui.TreeLast), change)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -145,3 +146,13 @@ func (c *WispGCCheck) Fix(ctx *CheckContext) error {
 
 	return lastErr
 }
+
+// PreviewFix lists the rigs where Fix would garbage-collect wisps.
+func (c *WispGCCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for rigName, count := range c.abandonedRigs {
+		planned = append(planned, fmt.Sprintf("bd mol wisp gc in %s (%d abandoned)", rigName, count))
+	}
+	sort.Strings(planned)
+	return planned
+}
@@ -55,6 +55,7 @@ func NewTownConfigValidCheck() *TownConfigValidCheck {
 			CheckName:        "town-config-valid",
 			CheckDescription: "Check that mayor/town.json is valid with required fields",
 			CheckCategory:    CategoryCore,
+			CheckDependsOn:   []string{"town-config-exists"},
 		},
 	}
 }
@@ -178,139 +179,9 @@ func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 	return os.WriteFile(rigsPath, data, 0644)
 }
 
-// RigsRegistryValidCheck verifies mayor/rigs.json is valid and rigs exist.
-type RigsRegistryValidCheck struct {
-	FixableCheck
-	missingRigs []string // Cached for Fix
-}
-
-// NewRigsRegistryValidCheck creates a new rigs registry validation check.
-func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
-	return &RigsRegistryValidCheck{
-		FixableCheck: FixableCheck{
-			BaseCheck: BaseCheck{
-				CheckName:        "rigs-registry-valid",
-				CheckDescription: "Check that registered rigs exist on disk",
-				CheckCategory:    CategoryCore,
-			},
-		},
-	}
-}
-
-// rigsConfig represents the structure of mayor/rigs.json.
-type rigsConfig struct {
-	Version int                    `json:"version"`
-	Rigs    map[string]interface{} `json:"rigs"`
-}
-
-// Run validates mayor/rigs.json and checks that registered rigs exist.
-func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-
-	data, err := os.ReadFile(rigsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &CheckResult{
-				Name:    c.Name(),
-				Status:  StatusOK,
-				Message: "No rigs.json (skipping validation)",
-			}
-		}
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusError,
-			Message: "Cannot read mayor/rigs.json",
-			Details: []string{err.Error()},
-		}
-	}
-
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusError,
-			Message: "mayor/rigs.json is not valid JSON",
-			Details: []string{err.Error()},
-			FixHint: "Fix JSON syntax in mayor/rigs.json",
-		}
-	}
-
-	if len(config.Rigs) == 0 {
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusOK,
-			Message: "No rigs registered",
-		}
-	}
-
-	// Check each registered rig exists
-	var missing []string
-	var found int
-
-	for rigName := range config.Rigs {
-		rigPath := filepath.Join(ctx.TownRoot, rigName)
-		if _, err := os.Stat(rigPath); os.IsNotExist(err) {
-			missing = append(missing, rigName)
-		} else {
-			found++
-		}
-	}
-
-	// Cache for Fix
-	c.missingRigs = missing
-
-	if len(missing) > 0 {
-		details := make([]string, len(missing))
-		for i, m := range missing {
-			details[i] = fmt.Sprintf("Missing rig directory: %s/", m)
-		}
-
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusWarning,
-			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(config.Rigs)),
-			Details: details,
-			FixHint: "Run 'gt doctor --fix' to remove missing rigs from registry",
-		}
-	}
-
-	return &CheckResult{
-		Name:    c.Name(),
-		Status:  StatusOK,
-		Message: fmt.Sprintf("All %d registered rig(s) exist", found),
-	}
-}
-
-// Fix removes missing rigs from the registry.
-func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
-	if len(c.missingRigs) == 0 {
-		return nil
-	}
-
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-
-	data, err := os.ReadFile(rigsPath)
-	if err != nil {
-		return fmt.Errorf("reading rigs.json: %w", err)
-	}
-
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("parsing rigs.json: %w", err)
-	}
-
-	// Remove missing rigs
-	for _, rig := range c.missingRigs {
-		delete(config.Rigs, rig)
-	}
-
-	// Write back
-	newData, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling rigs.json: %w", err)
-	}
-
-	return os.WriteFile(rigsPath, newData, 0644)
+// PreviewFix describes the registry Fix would create.
+func (c *RigsRegistryExistsCheck) PreviewFix(ctx *CheckContext) []string {
+	return []string{"write empty rig registry " + filepath.Join(ctx.TownRoot, "mayor", "rigs.json")}
 }
 
 // MayorExistsCheck verifies the mayor/ directory structure.
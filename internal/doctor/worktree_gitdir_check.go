@@ -261,6 +261,25 @@ func (c *WorktreeGitdirCheck) Fix(ctx *CheckContext) error {
 	return lastErr
 }
 
+// PreviewFix describes how Fix would re-create each broken worktree.
+func (c *WorktreeGitdirCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, bw := range c.brokenWorktrees {
+		if bw.bareRepoPath == "" {
+			planned = append(planned, fmt.Sprintf("skip %s: not a .repo.git worktree", bw.worktreePath))
+			continue
+		}
+		if _, err := os.Stat(bw.bareRepoPath); os.IsNotExist(err) {
+			planned = append(planned, fmt.Sprintf("skip %s: .repo.git missing, needs 'gt rig install'", bw.worktreePath))
+			continue
+		}
+		planned = append(planned,
+			"remove "+filepath.Join(bw.worktreePath, ".git"),
+			fmt.Sprintf("run: git -C %s worktree add %s <default-branch>", bw.bareRepoPath, bw.worktreePath))
+	}
+	return planned
+}
+
 // isRigDir checks if a directory looks like a rig (has config.json or known subdirectories).
 func isRigDir(path string) bool {
 	// Check for config.json (most reliable indicator)
@@ -143,3 +143,16 @@ func (c *ZombieSessionCheck) Fix(ctx *CheckContext) error {
 
 	return lastErr
 }
+
+// PreviewFix lists the zombie sessions Fix would kill. Crew sessions are
+// never killed.
+func (c *ZombieSessionCheck) PreviewFix(ctx *CheckContext) []string {
+	var planned []string
+	for _, sess := range c.zombieSessions {
+		if isCrewSession(sess) {
+			continue
+		}
+		planned = append(planned, "kill tmux session "+sess)
+	}
+	return planned
+}
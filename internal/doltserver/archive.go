@@ -0,0 +1,73 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SnapshotDatabase returns a directory holding a consistent copy of rigDB
+// for archiving, and a cleanup func for it. While the server is running its
+// files can change mid-copy, so the copy is taken with DOLT_BACKUP and
+// restored offline into a temporary directory. With the server stopped, the
+// database directory itself is returned.
+func SnapshotDatabase(townRoot, rigDB string) (string, func(), error) {
+	noop := func() {}
+	if running, _, _ := IsRunning(townRoot); !running {
+		return RigDatabaseDir(townRoot, rigDB), noop, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "gt-dolt-snapshot-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+
+	backupURL := "file://" + filepath.ToSlash(filepath.Join(tmp, "backup"))
+	query := fmt.Sprintf("CALL DOLT_BACKUP('sync-url', '%s')", sqlEscape(backupURL))
+	if err := doltSQL(townRoot, rigDB, query); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("backing up %s: %w", rigDB, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", "backup", "restore", backupURL, rigDB)
+	cmd.Dir = tmp
+	if output, err := cmd.CombinedOutput(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("restoring backup of %s: %w (%s)", rigDB, err, strings.TrimSpace(string(output)))
+	}
+	return filepath.Join(tmp, rigDB), cleanup, nil
+}
+
+// VerifyDatabaseDir opens the database in dbDir offline and checks that it
+// has each of tables.
+func VerifyDatabaseDir(dbDir string, tables ...string) error {
+	rows, err := localDoltSQL(dbDir, "SHOW TABLES")
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", dbDir, err)
+	}
+	have := make(map[string]bool)
+	for _, row := range rows {
+		for _, v := range row {
+			if s, ok := v.(string); ok {
+				have[s] = true
+			}
+		}
+	}
+	var missing []string
+	for _, t := range tables {
+		if !have[t] {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s is missing table(s) %s", dbDir, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CloneOptions controls the behavior of CloneDatabases.
+type CloneOptions struct {
+	// Remote is the base Dolt remote URL; each database is cloned from
+	// Remote/<database> (e.g., file:///mnt/dolt-remotes or a relay host's
+	// remotesapi at http://relay:50051).
+	Remote string
+
+	// DoltHubOrg clones from DoltHub instead, using the repo names
+	// 'gt dolt sync' pushes to (see DoltHubRepoName).
+	DoltHubOrg string
+
+	// Databases restricts the clone to these databases. Empty means every
+	// database the town uses (see TownDatabases).
+	Databases []string
+
+	// DryRun reports what would be cloned without cloning.
+	DryRun bool
+}
+
+// CloneResult records the outcome of cloning a single database.
+type CloneResult struct {
+	// Database is the database name.
+	Database string
+
+	// Remote is the URL the database is (or would be) cloned from.
+	Remote string
+
+	// Cloned is true if dolt clone succeeded.
+	Cloned bool
+
+	// Exists is true if the database was skipped because it is already in .dolt-data/.
+	Exists bool
+
+	// DryRun is true if this was a dry-run (no actual clone).
+	DryRun bool
+
+	// Error is non-nil if the clone failed.
+	Error error
+}
+
+// TownDatabase is a database the town's beads live in.
+type TownDatabase struct {
+	// Rig is the rig name ("hq" for the town-level beads).
+	Rig string
+
+	// Database is the Dolt database name: the rig's metadata.json
+	// dolt_database when it has one, otherwise the rig name.
+	Database string
+}
+
+// TownDatabases returns the databases served by the town's server: hq plus
+// every rig in mayor/rigs.json, sorted by rig. Rigs mapped to their own
+// server in settings/dolt-servers.json are left out.
+func TownDatabases(townRoot string) []TownDatabase {
+	dbs := []TownDatabase{{Rig: "hq", Database: "hq"}}
+	if db := readExistingDoltDatabase(filepath.Join(townRoot, ".beads")); db != "" {
+		dbs[0].Database = db
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return dbs
+	}
+	var config struct {
+		Rigs map[string]interface{} `json:"rigs"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return dbs
+	}
+	servers, _ := LoadRigServers(townRoot)
+
+	var rigs []TownDatabase
+	for rigName := range config.Rigs {
+		if servers != nil {
+			if _, ok := servers.Rigs[rigName]; ok {
+				continue
+			}
+		}
+		db := TownDatabase{Rig: rigName, Database: rigName}
+		if existing := readExistingDoltDatabase(FindRigBeadsDir(townRoot, rigName)); existing != "" {
+			db.Database = existing
+		}
+		rigs = append(rigs, db)
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Rig < rigs[j].Rig })
+	return append(dbs, rigs...)
+}
+
+// TownDatabaseNames returns the distinct databases behind TownDatabases, in
+// the same order. Rigs on branches of the shared database (see LayoutFile)
+// are served by that one database, which appears once.
+func TownDatabaseNames(townRoot string) []string {
+	var names []string
+	for _, db := range TownDatabases(townRoot) {
+		base, _ := SplitRevisionDatabase(db.Database)
+		if !slices.Contains(names, base) {
+			names = append(names, base)
+		}
+	}
+	return names
+}
+
+// CloneRemoteURL returns the remote URL dbName is cloned from.
+func CloneRemoteURL(opts CloneOptions, dbName string) string {
+	if opts.DoltHubOrg != "" {
+		return DoltHubRemoteURL(opts.DoltHubOrg, DoltHubRepoName(dbName))
+	}
+	return strings.TrimRight(opts.Remote, "/") + "/" + dbName
+}
+
+// CloneDatabase clones remoteURL into .dolt-data/<dbName>. A failed clone
+// leaves no partial database behind.
+func CloneDatabase(townRoot, remoteURL, dbName string) error {
+	config := DefaultConfig(townRoot)
+	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+
+	cmd := exec.Command("dolt", "clone", remoteURL, dbName) //nolint:gosec // G204: remote URL is operator-supplied
+	cmd.Dir = config.DataDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		_ = os.RemoveAll(filepath.Join(config.DataDir, dbName))
+		return fmt.Errorf("dolt clone: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CloneDatabases clones the town's databases (or opts.Databases) from a Dolt
+// remote into .dolt-data/, skipping databases already there. The server must
+// not be running, since it only picks up databases at startup. Never fails
+// fast — collects all results.
+func CloneDatabases(townRoot string, opts CloneOptions) []CloneResult {
+	names := opts.Databases
+	if len(names) == 0 {
+		names = TownDatabaseNames(townRoot)
+	}
+	layout, _ := LoadLayout(townRoot)
+
+	var results []CloneResult
+	for _, db := range names {
+		result := CloneResult{Database: db, Remote: CloneRemoteURL(opts, db)}
+
+		if DatabaseExists(townRoot, db) {
+			result.Exists = true
+			results = append(results, result)
+			continue
+		}
+
+		if opts.DryRun {
+			result.DryRun = true
+			results = append(results, result)
+			continue
+		}
+
+		if err := CloneDatabase(townRoot, result.Remote, db); err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		if layout != nil && db == layout.Database {
+			// dolt clone only checks out main; the rigs' branches arrive
+			// as origin/<rig> and need local branches to be served.
+			for _, rigName := range layout.Rigs {
+				if _, err := runDolt(RigDatabaseDir(townRoot, db), "branch", rigName, "origin/"+rigName); err != nil &&
+					!strings.Contains(err.Error(), "already exists") {
+					result.Error = fmt.Errorf("creating branch %s: %w", rigName, err)
+				}
+			}
+		}
+
+		result.Cloned = true
+		results = append(results, result)
+	}
+	return results
+}
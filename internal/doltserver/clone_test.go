@@ -0,0 +1,74 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTownDatabases(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"rigs": {"gastown": {}, "beta": {}, "remote": {}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// gastown's metadata names its database.
+	beadsDir := filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_database": "beads_gt"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// remote lives on its own server.
+	if err := SaveRigServers(townRoot, &RigServers{Rigs: map[string]ServerEndpoint{"remote": {Host: "build-02.lan"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := TownDatabases(townRoot)
+	want := []TownDatabase{
+		{Rig: "hq", Database: "hq"},
+		{Rig: "beta", Database: "beta"},
+		{Rig: "gastown", Database: "beads_gt"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("TownDatabases() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TownDatabases()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCloneRemoteURL(t *testing.T) {
+	if got := CloneRemoteURL(CloneOptions{Remote: "http://relay:50051/"}, "beads_gt"); got != "http://relay:50051/beads_gt" {
+		t.Errorf("remote URL = %q", got)
+	}
+	if got := CloneRemoteURL(CloneOptions{DoltHubOrg: "acme"}, "hq"); got != DoltHubRemoteURL("acme", "gt-hq") {
+		t.Errorf("DoltHub URL = %q", got)
+	}
+}
+
+func TestCloneDatabases_SkipsExistingAndDryRun(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "hq", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	results := CloneDatabases(townRoot, CloneOptions{Remote: "file:///remotes", Databases: []string{"hq", "beta"}, DryRun: true})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Exists || results[0].DryRun {
+		t.Errorf("hq should be skipped as existing: %+v", results[0])
+	}
+	if !results[1].DryRun || results[1].Remote != "file:///remotes/beta" {
+		t.Errorf("beta should be a dry run from file:///remotes/beta: %+v", results[1])
+	}
+}
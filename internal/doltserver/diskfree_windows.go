@@ -0,0 +1,8 @@
+//go:build windows
+
+package doltserver
+
+// diskFree is not implemented on Windows; -1 means unknown.
+func diskFree(path string) (int64, error) {
+	return -1, nil
+}
@@ -4,7 +4,8 @@
 // avoiding the single-writer limitation of embedded Dolt mode.
 //
 // Server configuration:
-//   - Port: 3307 (avoids conflict with MySQL on 3306)
+//   - Port: 3307 (avoids conflict with MySQL on 3306); a town whose port
+//     another town's server holds takes the next free one (see AllocatePort)
 //   - User: root (default Dolt user, no password for localhost)
 //   - Data directory: ~/gt/.dolt-data/ (contains all rig databases)
 //
@@ -18,12 +19,13 @@
 //
 // Usage:
 //
-//	gt dolt start           # Start the server
-//	gt dolt stop            # Stop the server
-//	gt dolt status          # Check server status
-//	gt dolt logs            # View server logs
-//	gt dolt sql             # Open SQL shell
-//	gt dolt init-rig <name> # Initialize a new rig database
+//	gt dolt start             # Start the server
+//	gt dolt start --supervise # Start it and restart it if it dies
+//	gt dolt stop              # Stop the server
+//	gt dolt status            # Check server status
+//	gt dolt logs              # View server logs
+//	gt dolt sql               # Open SQL shell
+//	gt dolt init-rig <name>   # Initialize a new rig database
 package doltserver
 
 import (
@@ -38,6 +40,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,10 +49,18 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/secrets"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
+// logger is the doltserver module's logger (gt config set log.doltserver=debug).
+var logger = logging.For("doltserver")
+
 // EnsureDoltIdentity configures dolt global identity (user.name, user.email)
 // if not already set. Copies values from git config as a sensible default.
 // This must run before InitRig and Start, since dolt init requires identity.
@@ -158,6 +169,10 @@ type Config struct {
 	// Empty means no password (backward-compatible default for local access).
 	Password string
 
+	// TLS requires an encrypted connection to a remote server.
+	// False keeps the backward-compatible --no-tls.
+	TLS bool
+
 	// DataDir is the root directory containing all rig databases.
 	// Each subdirectory is a separate database that will be served.
 	DataDir string
@@ -175,11 +190,17 @@ type Config struct {
 }
 
 // DefaultConfig returns the default Dolt server configuration.
-// Environment variables override defaults when set:
+// A remote server recorded in the town's .beads/metadata.json (see
+// SetServerEndpoint) replaces the local defaults, as does the port a local
+// server was allocated (see AllocatePort). Environment variables
+// override both when set:
 //   - GT_DOLT_HOST → Host
 //   - GT_DOLT_PORT → Port
 //   - GT_DOLT_USER → User
 //   - GT_DOLT_PASSWORD → Password
+//
+// Without GT_DOLT_PASSWORD, the password is the town's "dolt.password"
+// secret (gt secret set dolt.password), if set.
 func DefaultConfig(townRoot string) *Config {
 	daemonDir := filepath.Join(townRoot, "daemon")
 	config := &Config{
@@ -192,6 +213,18 @@ func DefaultConfig(townRoot string) *Config {
 		MaxConnections: DefaultMaxConnections,
 	}
 
+	ep := LoadServerEndpoint(townRoot)
+	if ep.Port != 0 {
+		config.Port = ep.Port
+	}
+	if ep.Host != "" {
+		config.Host = ep.Host
+		if ep.User != "" {
+			config.User = ep.User
+		}
+		config.TLS = ep.TLS
+	}
+
 	if h := os.Getenv("GT_DOLT_HOST"); h != "" {
 		config.Host = h
 	}
@@ -205,6 +238,10 @@ func DefaultConfig(townRoot string) *Config {
 	}
 	if pw := os.Getenv("GT_DOLT_PASSWORD"); pw != "" {
 		config.Password = pw
+	} else if pw, ok, err := secrets.Lookup(townRoot, secrets.DoltPassword); err != nil {
+		logger.Warn("reading Dolt password secret", "err", err)
+	} else if ok {
+		config.Password = pw
 	}
 
 	return config
@@ -226,12 +263,15 @@ func (c *Config) SQLArgs() []string {
 	if !c.IsRemote() {
 		return nil
 	}
-	return []string{
+	args := []string{
 		"--host", c.Host,
 		"--port", strconv.Itoa(c.Port),
 		"--user", c.User,
-		"--no-tls",
 	}
+	if !c.TLS {
+		args = append(args, "--no-tls")
+	}
+	return args
 }
 
 // userDSN returns the user[:password] portion of a MySQL DSN.
@@ -274,10 +314,12 @@ func buildDoltSQLCmd(ctx context.Context, config *Config, args ...string) *exec.
 	return cmd
 }
 
-// RigDatabaseDir returns the database directory for a specific rig.
+// RigDatabaseDir returns the database directory for a specific rig. For a
+// revision database ("town/gastown") it is the shared database's directory.
 func RigDatabaseDir(townRoot, rigName string) string {
 	config := DefaultConfig(townRoot)
-	return filepath.Join(config.DataDir, rigName)
+	db, _ := SplitRevisionDatabase(rigName)
+	return filepath.Join(config.DataDir, db)
 }
 
 // State represents the Dolt server's runtime state.
@@ -376,9 +418,10 @@ func IsRunning(townRoot string) (bool, int, error) {
 	}
 
 	// No valid PID file - check if port is in use by dolt anyway
-	// This catches externally-started dolt servers
+	// This catches externally-started dolt servers, but not another
+	// town's server on this host.
 	pid := findDoltServerOnPort(config.Port)
-	if pid > 0 {
+	if pid > 0 && servesDataDir(doltCommandLine(pid), config.DataDir) {
 		return true, pid, nil
 	}
 
@@ -394,9 +437,9 @@ func CheckServerReachable(townRoot string) error {
 	addr := config.HostPort()
 	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
 	if err != nil {
-		hint := ""
-		if !config.IsRemote() {
-			hint = "\n\nStart with: gt dolt start"
+		hint := "\n\nStart with: gt dolt start"
+		if config.IsRemote() {
+			hint = "\n\nCheck the remote server, or change it with: gt dolt connect <host[:port]>"
 		}
 		return fmt.Errorf("Dolt server not reachable at %s: %w%s", addr, err, hint)
 	}
@@ -487,14 +530,18 @@ func findDoltServerOnPort(port int) int {
 
 // isDoltProcess checks if a PID is actually a dolt sql-server process.
 func isDoltProcess(pid int) bool {
+	cmdline := doltCommandLine(pid)
+	return strings.Contains(cmdline, "dolt") && strings.Contains(cmdline, "sql-server")
+}
+
+// doltCommandLine returns the command line of pid, or "" if it cannot be read.
+func doltCommandLine(pid int) string {
 	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=")
 	output, err := cmd.Output()
 	if err != nil {
-		return false
+		return ""
 	}
-
-	cmdline := strings.TrimSpace(string(output))
-	return strings.Contains(cmdline, "dolt") && strings.Contains(cmdline, "sql-server")
+	return strings.TrimSpace(string(output))
 }
 
 // Start starts the Dolt SQL server.
@@ -528,7 +575,7 @@ func Start(townRoot string) error {
 		// If data directory doesn't exist, this is an orphaned server (e.g., user
 		// deleted ~/gt and re-ran gt install). Kill it so we can start fresh.
 		if _, statErr := os.Stat(config.DataDir); os.IsNotExist(statErr) {
-			fmt.Fprintf(os.Stderr, "Warning: Dolt server (PID %d) is running but data directory %s does not exist — stopping orphaned server\n", pid, config.DataDir)
+			logger.Warn("Dolt server is running but its data directory does not exist — stopping orphaned server", "pid", pid, "data_dir", config.DataDir)
 			if stopErr := Stop(townRoot); stopErr != nil {
 				if pid > 0 {
 					if proc, findErr := os.FindProcess(pid); findErr == nil {
@@ -547,9 +594,9 @@ func Start(townRoot string) error {
 			}
 			if pidFromFile != pid {
 				// PID file is stale/wrong - update it
-				fmt.Printf("Updating stale PID file (was %d, actual %d)\n", pidFromFile, pid)
+				logger.Info("updating stale PID file", "was", pidFromFile, "pid", pid)
 				if err := os.WriteFile(config.PidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: could not update PID file: %v\n", err)
+					logger.Warn("could not update PID file", "err", err)
 				}
 				// Update state too
 				state, _ := LoadState(townRoot)
@@ -563,6 +610,18 @@ func Start(townRoot string) error {
 		}
 	}
 
+	// Take the town's port, moving off it if another town's server holds it.
+	port, err := AllocatePort(townRoot)
+	if err != nil {
+		return err
+	}
+	config.Port = port
+
+	// Fail fast on anything that would leave the server half-started
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
 	// Ensure data directory exists
 	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
 		return fmt.Errorf("creating data directory: %w", err)
@@ -574,7 +633,7 @@ func Start(townRoot string) error {
 		dbDir := filepath.Join(config.DataDir, db)
 		if err := cleanupStaleDoltLock(dbDir); err != nil {
 			// Non-fatal warning
-			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			logger.Warn("could not clean up stale Dolt LOCK file", "err", err)
 		}
 	}
 
@@ -603,15 +662,16 @@ func Start(townRoot string) error {
 
 	if err := cmd.Start(); err != nil {
 		if closeErr := logFile.Close(); closeErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to close dolt log file: %v\n", closeErr)
+			logger.Warn("failed to close dolt log file", "err", closeErr)
 		}
 		return fmt.Errorf("starting Dolt server: %w", err)
 	}
 
 	// Close log file in parent (child has its own handle)
 	if closeErr := logFile.Close(); closeErr != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to close dolt log file: %v\n", closeErr)
+		logger.Warn("failed to close dolt log file", "err", closeErr)
 	}
+	logger.Info("started Dolt server", "pid", cmd.Process.Pid, "port", config.Port, "data_dir", config.DataDir)
 
 	// Write PID file
 	if err := os.WriteFile(config.PidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
@@ -631,7 +691,7 @@ func Start(townRoot string) error {
 	}
 	if err := SaveState(townRoot, state); err != nil {
 		// Non-fatal - server is still running
-		fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
+		logger.Warn("failed to save state", "err", err)
 	}
 
 	// Wait for the server to be accepting connections, not just alive.
@@ -646,17 +706,59 @@ func Start(townRoot string) error {
 			return fmt.Errorf("verifying server started: %w", err)
 		}
 		if !running {
-			return fmt.Errorf("Dolt server failed to start (check logs with 'gt dolt logs')")
+			return fmt.Errorf("Dolt server failed to start (check logs with 'gt dolt logs')%s", versionSkewHint(townRoot))
 		}
 
 		if err := CheckServerReachable(townRoot); err == nil {
+			logger.Debug("Dolt server accepting connections", "attempt", attempt+1)
+			_ = events.Publish(townRoot, events.SourceDolt, events.TypeDoltStarted, "doltserver", map[string]interface{}{
+				"pid":       cmd.Process.Pid,
+				"port":      config.Port,
+				"databases": databases,
+			}, events.VisibilityAudit)
 			return nil // Server is up and accepting connections
 		} else {
 			lastErr = err
 		}
 	}
 
-	return fmt.Errorf("Dolt server process started (PID %d) but not accepting connections after 5s: %w\nCheck logs with: gt dolt logs", cmd.Process.Pid, lastErr)
+	return fmt.Errorf("Dolt server process started (PID %d) but not accepting connections after 5s: %w\nCheck logs with: gt dolt logs%s", cmd.Process.Pid, lastErr, versionSkewHint(townRoot))
+}
+
+// versionSkewHint explains a startup failure when the installed dolt binary
+// is outside the supported range or a known-bad release. Returns "" when the
+// version is fine or cannot be determined.
+func versionSkewHint(townRoot string) string {
+	townMin, townMax := townDoltRange(townRoot)
+	report, err := deps.CheckInstalledDolt(townMin, townMax)
+	if err != nil || report.OK() {
+		return ""
+	}
+	minVersion, maxVersion := deps.SupportedDoltRange(townMin, townMax)
+	var problem string
+	switch {
+	case report.TooOld:
+		problem = "older than the minimum " + minVersion
+	case report.TooNew:
+		problem = "newer than the town maximum " + maxVersion
+	default:
+		problem = "a known-bad release: " + report.Advisories[0].String()
+	}
+	return fmt.Sprintf("\nInstalled dolt %s is %s; this is a likely cause.\nInstall a supported version: %s",
+		report.Version, problem, deps.DoltInstallCommand(runtime.GOOS))
+}
+
+// townDoltRange returns the town's dolt version bounds from its settings,
+// or empty strings for the built-in range.
+func townDoltRange(townRoot string) (string, string) {
+	if townRoot == "" {
+		return "", ""
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Dolt == nil {
+		return "", ""
+	}
+	return settings.Dolt.MinVersion, settings.Dolt.MaxVersion
 }
 
 // cleanupStaleDoltLock removes a stale Dolt LOCK file if no process holds it.
@@ -710,6 +812,17 @@ func Stop(townRoot string) error {
 		return fmt.Errorf("finding process: %w", err)
 	}
 
+	// Update state first - preserve historical info. Recording the stop
+	// before signaling keeps a supervisor from restarting the server while
+	// it shuts down.
+	state, _ := LoadState(townRoot)
+	if state == nil {
+		state = &State{}
+	}
+	state.Running = false
+	state.PID = 0
+	_ = SaveState(townRoot, state)
+
 	// Send SIGTERM for graceful shutdown
 	if err := process.Signal(syscall.SIGTERM); err != nil {
 		return fmt.Errorf("sending SIGTERM: %w", err)
@@ -734,15 +847,9 @@ func Stop(townRoot string) error {
 	// Clean up PID file
 	_ = os.Remove(config.PidFile)
 
-	// Update state - preserve historical info
-	state, _ := LoadState(townRoot)
-	if state == nil {
-		state = &State{}
-	}
-	state.Running = false
-	state.PID = 0
-	_ = SaveState(townRoot, state)
-
+	_ = events.Publish(townRoot, events.SourceDolt, events.TypeDoltStopped, "doltserver", map[string]interface{}{
+		"pid": pid,
+	}, events.VisibilityAudit)
 	return nil
 }
 
@@ -755,7 +862,7 @@ func GetConnectionString(townRoot string) string {
 
 // GetConnectionStringForRig returns the MySQL connection string for a specific rig database.
 func GetConnectionStringForRig(townRoot, rigName string) string {
-	config := DefaultConfig(townRoot)
+	config := ConfigForRig(townRoot, rigName)
 	return fmt.Sprintf("%s@tcp(%s)/%s", config.displayDSN(), config.HostPort(), rigName)
 }
 
@@ -1034,7 +1141,9 @@ func jsonKeys(m map[string]json.RawMessage) []string {
 
 // InitRig initializes a new rig database in the data directory.
 // If the Dolt server is running, it executes CREATE DATABASE to register the
-// database with the live server (avoiding the need for a restart).
+// database with the live server (avoiding the need for a restart). In the
+// branch-per-rig layout (see LayoutFile), a new rig gets a branch of the
+// shared database instead.
 // Returns (serverWasRunning, created, err). created is false when the database
 // already existed on disk (idempotent no-op).
 func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err error) {
@@ -1051,18 +1160,53 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 		}
 	}
 
+	layout, err := LoadLayout(townRoot)
+	if err != nil {
+		return false, false, err
+	}
+
 	rigDir := filepath.Join(config.DataDir, rigName)
 
 	// Check if already exists on disk — idempotent for callers like gt install.
 	// Still run EnsureMetadata to repair missing/corrupt metadata.json.
-	if _, err := os.Stat(filepath.Join(rigDir, ".dolt")); err == nil {
+	_, statErr := os.Stat(filepath.Join(rigDir, ".dolt"))
+	if statErr == nil || layout.IsBranched(rigName) {
 		running, _, _ := IsRunning(townRoot)
 		if err := EnsureMetadata(townRoot, rigName); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: metadata.json update failed for existing database %q: %v\n", rigName, err)
+			logger.Warn("metadata.json update failed for existing database", "database", rigName, "err", err)
 		}
 		return running, false, nil
 	}
 
+	if layout.Mode == LayoutBranchPerRig && canBranch(townRoot, layout, rigName) == "" {
+		running, err := initRigBranch(townRoot, layout, rigName)
+		if err != nil {
+			return running, false, err
+		}
+		return running, true, nil
+	}
+
+	running, err := createDatabase(townRoot, rigName)
+	if err != nil {
+		return running, false, err
+	}
+
+	// Update metadata.json to point to the server
+	if err := EnsureMetadata(townRoot, rigName); err != nil {
+		// Non-fatal: init succeeded, metadata update failed
+		logger.Warn("database initialized but metadata.json update failed", "err", err)
+	}
+
+	return running, true, nil
+}
+
+// createDatabase creates dbName in the data directory: with CREATE DATABASE
+// when the server is running, otherwise with dolt init, to be picked up when
+// the server starts. Returns whether the server was running.
+func createDatabase(townRoot, dbName string) (bool, error) {
+	config := DefaultConfig(townRoot)
+	dbDir := filepath.Join(config.DataDir, dbName)
+
 	// Check if server is running
 	running, runningPID, _ := IsRunning(townRoot)
 
@@ -1071,7 +1215,7 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 		// deleted ~/gt and re-ran gt install while an old server was still running).
 		// Stop the orphaned server and fall through to the offline init path.
 		if _, err := os.Stat(config.DataDir); os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "Warning: Dolt server (PID %d) is running but data directory %s does not exist — stopping orphaned server\n", runningPID, config.DataDir)
+			logger.Warn("Dolt server is running but its data directory does not exist — stopping orphaned server", "pid", runningPID, "data_dir", config.DataDir)
 			if stopErr := Stop(townRoot); stopErr != nil {
 				// Force-kill if graceful stop fails (no PID file for orphaned server)
 				if runningPID > 0 {
@@ -1087,8 +1231,8 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 	if running {
 		// Server is running: use CREATE DATABASE which both creates the
 		// directory and registers the database with the live server.
-		if err := serverExecSQL(townRoot, fmt.Sprintf("CREATE DATABASE `%s`", rigName)); err != nil {
-			return true, false, fmt.Errorf("creating database on running server: %w", err)
+		if err := serverExecSQL(townRoot, fmt.Sprintf("CREATE DATABASE `%s`", dbName)); err != nil {
+			return true, fmt.Errorf("creating database on running server: %w", err)
 		}
 		// Wait for the new database to appear in the server's in-memory catalog.
 		// CREATE DATABASE returns before the catalog is fully updated, so
@@ -1096,31 +1240,25 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 		// Non-fatal: the database was created, so we log a warning and continue
 		// to EnsureMetadata. The retry wrappers (doltSQLWithRetry) will handle
 		// any residual catalog propagation delays in subsequent operations.
-		if err := waitForCatalog(townRoot, rigName); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: catalog visibility wait timed out (will retry on use): %v\n", err)
-		}
-	} else {
-		// Server not running: create directory and init manually.
-		// The database will be picked up when the server starts.
-		if err := os.MkdirAll(rigDir, 0755); err != nil {
-			return false, false, fmt.Errorf("creating rig directory: %w", err)
-		}
-
-		cmd := exec.Command("dolt", "init")
-		cmd.Dir = rigDir
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return false, false, fmt.Errorf("initializing Dolt database: %w\n%s", err, output)
+		if err := waitForCatalog(townRoot, dbName); err != nil {
+			logger.Warn("catalog visibility wait timed out (will retry on use)", "err", err)
 		}
+		return true, nil
 	}
 
-	// Update metadata.json to point to the server
-	if err := EnsureMetadata(townRoot, rigName); err != nil {
-		// Non-fatal: init succeeded, metadata update failed
-		fmt.Fprintf(os.Stderr, "Warning: database initialized but metadata.json update failed: %v\n", err)
+	// Server not running: create directory and init manually.
+	// The database will be picked up when the server starts.
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return false, fmt.Errorf("creating rig directory: %w", err)
 	}
 
-	return running, true, nil
+	cmd := exec.Command("dolt", "init")
+	cmd.Dir = dbDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("initializing Dolt database: %w\n%s", err, output)
+	}
+	return false, nil
 }
 
 // Migration represents a database migration from old to new location.
@@ -1172,12 +1310,12 @@ func findLocalDoltDB(beadsDir string) string {
 	}
 	if len(candidates) == 0 {
 		if len(entries) > 0 {
-			fmt.Fprintf(os.Stderr, "[doltserver] Warning: %s exists but contains no valid dolt database\n", doltParent)
+			logger.Warn("directory exists but contains no valid dolt database", "dir", doltParent)
 		}
 		return ""
 	}
 	if len(candidates) > 1 {
-		fmt.Fprintf(os.Stderr, "[doltserver] Warning: multiple dolt databases found in %s: %v — manual resolution required\n", doltParent, candidates)
+		logger.Warn("multiple dolt databases found — manual resolution required", "dir", doltParent, "databases", candidates)
 		return ""
 	}
 	return candidates[0]
@@ -1266,17 +1404,16 @@ func MigrateRigFromBeads(townRoot, rigName, sourcePath string) error {
 	// Update metadata.json to point to the server
 	if err := EnsureMetadata(townRoot, rigName); err != nil {
 		// Non-fatal: migration succeeded, metadata update failed
-		fmt.Fprintf(os.Stderr, "Warning: database migrated but metadata.json update failed: %v\n", err)
+		logger.Warn("database migrated but metadata.json update failed", "err", err)
 	}
 
 	return nil
 }
 
 // DatabaseExists checks whether a rig database exists in the centralized .dolt-data/ directory.
+// For a revision database ("town/gastown") it checks the shared database.
 func DatabaseExists(townRoot, rigName string) bool {
-	config := DefaultConfig(townRoot)
-	doltDir := filepath.Join(config.DataDir, rigName, ".dolt")
-	_, err := os.Stat(doltDir)
+	_, err := os.Stat(filepath.Join(RigDatabaseDir(townRoot, rigName), ".dolt"))
 	return err == nil
 }
 
@@ -1297,6 +1434,10 @@ type BrokenWorkspace struct {
 
 	// LocalDataPath is the path to local Dolt data, if present.
 	LocalDataPath string
+
+	// JSONLPath is the rig's beads export (issues.jsonl, or its preserved
+	// copy) to restore from, when there is no local data.
+	JSONLPath string
 }
 
 // OrphanedDatabase represents a database in .dolt-data/ that is not referenced
@@ -1366,7 +1507,8 @@ func readExistingDoltDatabase(beadsDir string) string {
 }
 
 // collectReferencedDatabases returns a set of database names referenced by
-// any rig's metadata.json dolt_database field.
+// any rig's metadata.json dolt_database field. A revision database
+// ("town/gastown") references the shared database it is a branch of.
 func collectReferencedDatabases(townRoot string) map[string]bool {
 	referenced := make(map[string]bool)
 
@@ -1375,6 +1517,9 @@ func collectReferencedDatabases(townRoot string) map[string]bool {
 	if db := readExistingDoltDatabase(townBeadsDir); db != "" {
 		referenced[db] = true
 	}
+	if layout, err := LoadLayout(townRoot); err == nil && len(layout.Rigs) > 0 {
+		referenced[layout.Database] = true
+	}
 
 	// Check all rigs from rigs.json
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
@@ -1395,7 +1540,8 @@ func collectReferencedDatabases(townRoot string) map[string]bool {
 			continue
 		}
 		if db := readExistingDoltDatabase(beadsDir); db != "" {
-			referenced[db] = true
+			base, _ := SplitRevisionDatabase(db)
+			referenced[base] = true
 		}
 	}
 
@@ -1518,13 +1664,16 @@ func checkWorkspace(townRoot, rigName, beadsDir string) *BrokenWorkspace {
 	if localDoltPath != "" {
 		ws.HasLocalData = true
 		ws.LocalDataPath = localDoltPath
+	} else {
+		ws.JSONLPath = findJSONLExport(beadsDir)
 	}
 
 	return ws
 }
 
-// RepairWorkspace fixes a broken workspace by creating the missing database
-// or migrating local data if present. Returns a description of what was done.
+// RepairWorkspace fixes a broken workspace by migrating its local data if
+// present, else restoring the database from its JSONL export, else creating
+// an empty database. Returns a description of what was done.
 func RepairWorkspace(townRoot string, ws BrokenWorkspace) (string, error) {
 	if ws.HasLocalData {
 		// Migrate local data to centralized location
@@ -1534,6 +1683,14 @@ func RepairWorkspace(townRoot string, ws BrokenWorkspace) (string, error) {
 		return fmt.Sprintf("migrated local data from %s", ws.LocalDataPath), nil
 	}
 
+	if ws.JSONLPath != "" {
+		action, err := restoreFromJSONL(townRoot, ws)
+		if err != nil {
+			return action, fmt.Errorf("restoring %s from %s: %w", ws.RigName, beads.SnapshotExport, err)
+		}
+		return action, nil
+	}
+
 	// No local data — create a fresh database
 	_, created, err := InitRig(townRoot, ws.ConfiguredDB)
 	if err != nil {
@@ -1586,6 +1743,18 @@ func EnsureMetadata(townRoot, rigName string) error {
 	if existing["dolt_database"] == nil || existing["dolt_database"] == "" {
 		existing["dolt_database"] = rigName
 	}
+	// A rig on a branch of the shared database is always routed to its
+	// revision database, whatever bd init wrote.
+	if db := RouteDatabase(townRoot, rigName); db != rigName {
+		existing["dolt_database"] = db
+	}
+
+	// Point bd at the rig's server: its settings/dolt-servers.json mapping,
+	// else the town's remote host/port/user when configured, otherwise clear
+	// stale keys so bd uses its local default. Use the configured endpoint,
+	// not DefaultConfig: GT_DOLT_HOST/PORT/USER are per-process overrides and
+	// must not leak into every rig's metadata.
+	applyServerEndpoint(existing, RigEndpoint(townRoot, rigName))
 
 	// Always set jsonl_export to the canonical filename.
 	// Historical migrations may have left stale values (e.g., "beads.jsonl").
@@ -1604,13 +1773,35 @@ func EnsureMetadata(townRoot, rigName string) error {
 }
 
 // EnsureAllMetadata updates metadata.json for all rig databases known to the
-// Dolt server. This is the fix for the split-brain problem where worktrees
-// each have their own isolated database.
+// Dolt server, plus rigs mapped to their own server in
+// settings/dolt-servers.json and rigs on branches of the shared database.
+// This is the fix for the split-brain problem where worktrees each have
+// their own isolated database.
 func EnsureAllMetadata(townRoot string) (updated []string, errs []error) {
 	databases, err := ListDatabases(townRoot)
 	if err != nil {
 		return nil, []error{fmt.Errorf("listing databases: %w", err)}
 	}
+	if layout, err := LoadLayout(townRoot); err != nil {
+		errs = append(errs, err)
+	} else {
+		// The shared database is no rig of its own.
+		databases = slices.DeleteFunc(databases, func(db string) bool { return db == layout.Database })
+		for _, rigName := range layout.Rigs {
+			if !slices.Contains(databases, rigName) {
+				databases = append(databases, rigName)
+			}
+		}
+	}
+	if servers, err := LoadRigServers(townRoot); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, rigName := range servers.Names() {
+			if !slices.Contains(databases, rigName) {
+				databases = append(databases, rigName)
+			}
+		}
+	}
 
 	for _, dbName := range databases {
 		if err := EnsureMetadata(townRoot, dbName); err != nil {
@@ -1853,6 +2044,11 @@ func GetHealthMetrics(townRoot string) *HealthMetrics {
 			"server is in READ-ONLY mode — requires restart to recover")
 	}
 
+	// 5. Size and growth alerts from the last 'gt dolt stats' run
+	if snap, err := LoadStatsSnapshot(townRoot); err == nil {
+		metrics.Warnings = append(metrics.Warnings, StatsWarnings(snap.Databases)...)
+	}
+
 	return metrics
 }
 
@@ -1916,7 +2112,7 @@ func RecoverReadOnly(townRoot string) error {
 		return nil // Server is writable, no recovery needed
 	}
 
-	fmt.Printf("Dolt server is in read-only mode, attempting recovery...\n")
+	logger.Warn("Dolt server is in read-only mode, attempting recovery")
 
 	// Stop the server
 	if err := Stop(townRoot); err != nil {
@@ -1956,7 +2152,7 @@ func RecoverReadOnly(townRoot string) error {
 			continue
 		}
 		if !readOnly {
-			fmt.Printf("Dolt server recovered from read-only state\n")
+			logger.Info("Dolt server recovered from read-only state")
 			return nil
 		}
 	}
@@ -2008,6 +2204,19 @@ func MeasureQueryLatency(townRoot string) (time.Duration, error) {
 	return elapsed, nil
 }
 
+// ServerVersion returns the version reported by the running Dolt server.
+func ServerVersion(townRoot string) (string, error) {
+	out, err := doltSQLQuery(townRoot, "SELECT dolt_version() AS version")
+	if err != nil {
+		return "", err
+	}
+	rows := parseSimpleCSV(out)
+	if len(rows) == 0 || rows[0]["version"] == "" {
+		return "", fmt.Errorf("no version returned by Dolt server")
+	}
+	return strings.TrimSpace(rows[0]["version"]), nil
+}
+
 // dirSize returns the total size of a directory tree in bytes.
 func dirSize(path string) int64 {
 	var total int64
@@ -2129,15 +2338,17 @@ func waitForCatalog(townRoot, dbName string) error {
 }
 
 // doltSQL executes a SQL statement against a specific rig database on the Dolt server.
-// Uses the dolt CLI from the data directory (auto-detects running server).
+// Uses the dolt CLI from the data directory (auto-detects running server), or
+// the rig's own server when it is mapped in settings/dolt-servers.json.
+// A rig on a branch of the shared database is routed to its revision database.
 // The USE prefix selects the database since --use-db is not available on all dolt versions.
 func doltSQL(townRoot, rigDB, query string) error {
-	config := DefaultConfig(townRoot)
+	config := ConfigForRig(townRoot, rigDB)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	// Prepend USE <db> to select the target database.
-	fullQuery := fmt.Sprintf("USE %s; %s", rigDB, query)
+	fullQuery := fmt.Sprintf("USE %s; %s", quoteIdentifier(RouteDatabase(townRoot, rigDB)), query)
 	cmd := buildDoltSQLCmd(ctx, config, "-q", fullQuery)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -2305,7 +2516,7 @@ CALL DOLT_MERGE('%s');
 		// Phase 2: Conflict detected. Re-run merge with autocommit disabled
 		// so conflicts are staged (not rolled back) and can be resolved.
 		// --theirs: polecat state wins (latest mutations, always authoritative).
-		fmt.Printf("Dolt merge conflict on %s, auto-resolving (--theirs)...\n", branchName)
+		logger.Warn("Dolt merge conflict, auto-resolving (--theirs)", "branch", branchName)
 		conflictScript := fmt.Sprintf(`USE %s;
 SET @@autocommit = 0;
 CALL DOLT_CHECKOUT('main');
@@ -403,6 +403,38 @@ func TestEnsureMetadata_Rig(t *testing.T) {
 	}
 }
 
+func TestEnsureMetadata_IgnoresEnvEndpointOverrides(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: "dolt.lan", Port: 3307, User: "gt"}); err != nil {
+		t.Fatal(err)
+	}
+	beadsDir := filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A one-off override must not be written into the rig's metadata.
+	t.Setenv("GT_DOLT_HOST", "10.0.0.9")
+	t.Setenv("GT_DOLT_PORT", "4400")
+	t.Setenv("GT_DOLT_USER", "someone-else")
+
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatalf("EnsureMetadata failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata[metaServerHost] != "dolt.lan" || metadata[metaServerPort] != float64(3307) || metadata[metaServerUser] != "gt" {
+		t.Errorf("endpoint = %v:%v user %v, want configured dolt.lan:3307 user gt",
+			metadata[metaServerHost], metadata[metaServerPort], metadata[metaServerUser])
+	}
+}
+
 func TestEnsureMetadata_Idempotent(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -3477,3 +3509,42 @@ func TestBuildDoltSQLCmd_RemoteNoPassword(t *testing.T) {
 		}
 	}
 }
+
+func TestFindBrokenWorkspaces_WithJSONLExport(t *testing.T) {
+	townRoot := t.TempDir()
+
+	beadsDir := filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := `{"backend":"dolt","dolt_mode":"server","dolt_database":"myrig"}`
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(`{"id":"mr-1"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"),
+		[]byte(`{"rigs":{"myrig":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	broken := FindBrokenWorkspaces(townRoot)
+	if len(broken) != 1 {
+		t.Fatalf("expected 1 broken workspace, got %d", len(broken))
+	}
+	if want := filepath.Join(beadsDir, "issues.jsonl"); broken[0].JSONLPath != want {
+		t.Errorf("JSONLPath = %q, want %q", broken[0].JSONLPath, want)
+	}
+
+	// Without a running server the import is refused before anything changes.
+	if _, err := RepairWorkspace(townRoot, broken[0]); err == nil {
+		t.Fatal("expected an error without a running server")
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "issues.jsonl")); err != nil {
+		t.Errorf("issues.jsonl moved: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DatabaseUsage reports the on-disk size of one Dolt database.
+//
+// Dolt appends every write to the chunk journal and newgen table files under
+// .dolt/noms/; dolt gc copies the chunks still reachable into noms/oldgen/
+// and drops the rest. Until the next gc, everything outside oldgen is
+// potentially garbage, so ReclaimableBytes is an upper bound.
+type DatabaseUsage struct {
+	Database         string `json:"database"`
+	Bytes            int64  `json:"bytes"`             // Whole database directory
+	LiveBytes        int64  `json:"live_bytes"`        // Compacted chunks (noms/oldgen)
+	ReclaimableBytes int64  `json:"reclaimable_bytes"` // At most this much is freed by gc
+}
+
+// GCResult records the outcome of garbage-collecting one database.
+type GCResult struct {
+	Database string
+	Before   int64 // Bytes on disk before gc
+	After    int64 // Bytes on disk after gc (0 if gc failed)
+	Error    error
+}
+
+// DiskUsage reports per-database disk usage for the local server's data
+// directory. filter restricts the report to one database; empty means all.
+func DiskUsage(townRoot, filter string) ([]DatabaseUsage, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — disk usage is only available for a local server", config.HostPort())
+	}
+
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+
+	var usage []DatabaseUsage
+	for _, db := range databases {
+		if filter != "" && db != filter {
+			continue
+		}
+		usage = append(usage, databaseUsage(db, RigDatabaseDir(townRoot, db)))
+	}
+	return usage, nil
+}
+
+// databaseUsage measures a single database directory.
+func databaseUsage(name, dbDir string) DatabaseUsage {
+	noms := filepath.Join(dbDir, ".dolt", "noms")
+	total := dirSize(dbDir)
+	nomsSize := dirSize(noms)
+	live := dirSize(filepath.Join(noms, "oldgen"))
+
+	// manifest and LOCK survive gc; they are tiny but keep the estimate honest.
+	var fixed int64
+	for _, f := range []string{"manifest", "LOCK"} {
+		if info, err := os.Stat(filepath.Join(noms, f)); err == nil {
+			fixed += info.Size()
+		}
+	}
+
+	reclaimable := nomsSize - live - fixed
+	if reclaimable < 0 {
+		reclaimable = 0
+	}
+	return DatabaseUsage{
+		Database:         name,
+		Bytes:            total,
+		LiveBytes:        live,
+		ReclaimableBytes: reclaimable,
+	}
+}
+
+// GCDatabases runs dolt gc on each database (or only filter, if set).
+// dolt gc needs exclusive access to the database files, so the caller must
+// stop the local server first. Never fails fast — collects all results.
+func GCDatabases(townRoot, filter string) []GCResult {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return []GCResult{{
+			Database: "(list)",
+			Error:    fmt.Errorf("listing databases: %w", err),
+		}}
+	}
+
+	var results []GCResult
+	for _, db := range databases {
+		if filter != "" && db != filter {
+			continue
+		}
+		dbDir := RigDatabaseDir(townRoot, db)
+		result := GCResult{Database: db, Before: dirSize(dbDir)}
+		if err := GCDatabase(dbDir); err != nil {
+			result.Error = err
+		} else {
+			result.After = dirSize(dbDir)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// GCDatabase runs dolt gc in a database directory.
+func GCDatabase(dbDir string) error {
+	cmd := exec.Command("dolt", "gc")
+	cmd.Dir = dbDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dolt gc: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSized(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	townRoot := t.TempDir()
+	noms := filepath.Join(townRoot, ".dolt-data", "hq", ".dolt", "noms")
+	writeSized(t, filepath.Join(noms, "manifest"), 100)
+	writeSized(t, filepath.Join(noms, "vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv"), 5000)
+	writeSized(t, filepath.Join(noms, "oldgen", "table1"), 2000)
+	writeSized(t, filepath.Join(townRoot, ".dolt-data", "hq", ".dolt", "config.json"), 50)
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "gastown", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := DiskUsage(townRoot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("got %d databases, want 2", len(usage))
+	}
+
+	var hq DatabaseUsage
+	for _, u := range usage {
+		if u.Database == "hq" {
+			hq = u
+		}
+	}
+	if hq.Bytes != 7150 || hq.LiveBytes != 2000 || hq.ReclaimableBytes != 5000 {
+		t.Errorf("hq usage = %+v", hq)
+	}
+
+	filtered, err := DiskUsage(townRoot, "gastown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 || filtered[0].Database != "gastown" || filtered[0].ReclaimableBytes != 0 {
+		t.Errorf("filtered usage = %+v", filtered)
+	}
+}
+
+func TestDiskUsage_Remote(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "dolt.example.com")
+	_, err := DiskUsage(t.TempDir(), "")
+	if err == nil || !strings.Contains(err.Error(), "remote") {
+		t.Errorf("expected remote error, got %v", err)
+	}
+}
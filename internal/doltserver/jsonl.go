@@ -0,0 +1,143 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// JSONLMigration is a rig whose beads only ever lived in issues.jsonl: no
+// SQLite database, no embedded Dolt database, and no server database.
+type JSONLMigration struct {
+	RigName    string
+	BeadsDir   string
+	SourcePath string // issues.jsonl, or its preserved copy after an interrupted run
+}
+
+// PreservedPath is where the source file is kept once migration starts.
+func (m JSONLMigration) PreservedPath() string {
+	return filepath.Join(m.BeadsDir, beads.SnapshotExport+beads.LegacyPreservedSuffix)
+}
+
+// FindJSONLOnlyRigs finds registered rigs that need a JSONL-to-Dolt
+// migration, sorted by name. A rig whose issues.jsonl was already moved
+// aside by an interrupted migration (and whose database was never
+// created) is still reported, with SourcePath at the preserved copy.
+func FindJSONLOnlyRigs(townRoot string) []JSONLMigration {
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil
+	}
+	var rigs struct {
+		Rigs map[string]json.RawMessage `json:"rigs"`
+	}
+	if err := json.Unmarshal(data, &rigs); err != nil {
+		return nil
+	}
+
+	var found []JSONLMigration
+	for rigName := range rigs.Rigs {
+		if m, ok := JSONLOnlyRig(townRoot, rigName); ok {
+			found = append(found, m)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].RigName < found[j].RigName })
+	return found
+}
+
+// JSONLOnlyRig reports whether a rig needs a JSONL-to-Dolt migration.
+func JSONLOnlyRig(townRoot, rigName string) (JSONLMigration, bool) {
+	beadsDir := beads.ResolveBeadsDir(filepath.Join(townRoot, rigName))
+	m := JSONLMigration{RigName: rigName, BeadsDir: beadsDir}
+
+	if DatabaseExists(townRoot, RigDatabaseName(townRoot, rigName)) || findLocalDoltDB(beadsDir) != "" {
+		return m, false
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "beads.db")); err == nil {
+		return m, false // SQLite: bd's own migration handles it
+	}
+
+	m.SourcePath = findJSONLExport(beadsDir)
+	return m, m.SourcePath != ""
+}
+
+// findJSONLExport returns a beads directory's non-empty issues.jsonl, or
+// its preserved copy, or "" if it has neither.
+func findJSONLExport(beadsDir string) string {
+	for _, name := range []string{beads.SnapshotExport, beads.SnapshotExport + beads.LegacyPreservedSuffix} {
+		path := filepath.Join(beadsDir, name)
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			return path
+		}
+	}
+	return ""
+}
+
+// restoreFromJSONL creates a broken workspace's missing database on the
+// running server and imports its JSONL export, the way 'gt dolt
+// migrate-jsonl' does: the export is kept as issues.jsonl.legacy (so
+// 'gt dolt migrate-jsonl <rig> --verify' can diff against it) and every
+// issue keeps its ID.
+func restoreFromJSONL(townRoot string, ws BrokenWorkspace) (string, error) {
+	if running, _, _ := IsRunning(townRoot); !running {
+		return "", fmt.Errorf("the Dolt server must be running to import %s (gt dolt start)", ws.JSONLPath)
+	}
+
+	f, err := os.Open(ws.JSONLPath) //nolint:gosec // G304: path is a rig's beads export
+	if err != nil {
+		return "", err
+	}
+	records, err := beads.ParseLegacyJSONL(f)
+	_ = f.Close()
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", ws.JSONLPath, err)
+	}
+	plan := beads.PlanLegacyImport(records)
+
+	m := JSONLMigration{RigName: ws.RigName, BeadsDir: ws.BeadsDir, SourcePath: ws.JSONLPath}
+	preserved := m.PreservedPath()
+	if m.SourcePath != preserved {
+		if _, err := os.Stat(preserved); err == nil {
+			return "", fmt.Errorf("%s already exists; move it away or restore from it", preserved)
+		}
+		if err := os.Rename(m.SourcePath, preserved); err != nil {
+			return "", fmt.Errorf("preserving %s: %w", m.SourcePath, err)
+		}
+	}
+
+	if _, _, err := InitRig(townRoot, ws.ConfiguredDB); err != nil {
+		return "", fmt.Errorf("creating database: %w", err)
+	}
+	prefix := "hq"
+	if ws.RigName != "hq" {
+		prefix = config.GetRigPrefix(townRoot, ws.RigName)
+	}
+	b := beads.New(filepath.Dir(ws.BeadsDir))
+	if err := b.ConfigSet("issue_prefix", prefix); err != nil {
+		return "", fmt.Errorf("setting issue_prefix: %w", err)
+	}
+	_ = b.ConfigSet("types.custom", constants.BeadsCustomTypes)
+
+	created, failed := 0, 0
+	for _, a := range beads.ImportLegacy(b, plan) {
+		switch a.Action {
+		case "created":
+			created++
+		case "failed":
+			failed++
+			logger.Warn("restoring issue failed", "rig", ws.RigName, "issue", a.SourceID, "err", a.Detail)
+		}
+	}
+	action := fmt.Sprintf("created database and imported %d of %d issues from %s (kept as %s)",
+		created, len(plan.Records), beads.SnapshotExport, filepath.Base(preserved))
+	if failed > 0 {
+		return action, fmt.Errorf("%d issue(s) failed to import from %s", failed, filepath.Base(preserved))
+	}
+	return action, nil
+}
@@ -0,0 +1,40 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindJSONLOnlyRigs(t *testing.T) {
+	townRoot := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(townRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("mayor/rigs.json", `{"rigs":{"legacy":{},"served":{},"empty":{},"sqlite":{},"resumed":{}}}`)
+	write("legacy/.beads/issues.jsonl", `{"id":"lg-1","title":"x"}`+"\n")
+	write("served/.beads/issues.jsonl", `{"id":"sv-1","title":"x"}`+"\n")
+	write(".dolt-data/served/.dolt/noms/manifest", "")
+	write("empty/.beads/issues.jsonl", "")
+	write("sqlite/.beads/issues.jsonl", `{"id":"sq-1","title":"x"}`+"\n")
+	write("sqlite/.beads/beads.db", "")
+	write("resumed/.beads/issues.jsonl.legacy", `{"id":"rs-1","title":"x"}`+"\n")
+
+	found := FindJSONLOnlyRigs(townRoot)
+	if len(found) != 2 {
+		t.Fatalf("found %+v, want legacy and resumed", found)
+	}
+	if found[0].RigName != "legacy" || found[0].SourcePath != filepath.Join(townRoot, "legacy", ".beads", "issues.jsonl") {
+		t.Errorf("found[0] = %+v", found[0])
+	}
+	if found[1].RigName != "resumed" || found[1].SourcePath != found[1].PreservedPath() {
+		t.Errorf("found[1] = %+v, want source at the preserved copy", found[1])
+	}
+}
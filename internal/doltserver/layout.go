@@ -0,0 +1,351 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// By default every rig's beads live in a database of their own, and the
+// server holds each one open: a town with dozens of rigs pays for dozens of
+// databases. settings/dolt-layout.json can instead put rigs on branches of
+// one shared database, named after the rig. Connections are routed with
+// Dolt's revision databases: EnsureMetadata writes "<shared>/<rig>" as the
+// rig's dolt_database, so bd and gt's own SQL land on the rig's branch
+// without knowing about the layout.
+//
+// hq and rigs mapped to their own server in settings/dolt-servers.json
+// always keep their own database.
+
+// Storage layouts.
+const (
+	LayoutDatabasePerRig = "database-per-rig"
+	LayoutBranchPerRig   = "branch-per-rig"
+)
+
+// LayoutFile is the storage layout, relative to the town root.
+const LayoutFile = "settings/dolt-layout.json"
+
+// DefaultSharedDatabase is the database branched rigs share by default.
+const DefaultSharedDatabase = "town"
+
+// Layout is the town's Dolt storage layout.
+type Layout struct {
+	// Mode is the layout new rigs get: LayoutDatabasePerRig (the default)
+	// or LayoutBranchPerRig.
+	Mode string `json:"mode"`
+
+	// Database is the shared database holding branched rigs.
+	Database string `json:"database,omitempty"`
+
+	// Rigs are the rigs whose beads live on a branch of Database, whatever
+	// the mode. Existing rigs join it through MigrateRigToBranch.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// LayoutPath returns the storage layout path for a town.
+func LayoutPath(townRoot string) string {
+	return filepath.Join(townRoot, filepath.FromSlash(LayoutFile))
+}
+
+// LoadLayout reads the storage layout. A missing file is database-per-rig.
+func LoadLayout(townRoot string) (*Layout, error) {
+	layout := &Layout{}
+	data, err := os.ReadFile(LayoutPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", LayoutFile, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, layout); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", LayoutFile, err)
+		}
+	}
+	if layout.Mode == "" {
+		layout.Mode = LayoutDatabasePerRig
+	}
+	if layout.Database == "" {
+		layout.Database = DefaultSharedDatabase
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", LayoutFile, err)
+	}
+	return layout, nil
+}
+
+// SaveLayout writes the storage layout.
+func SaveLayout(townRoot string, layout *Layout) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+	sort.Strings(layout.Rigs)
+	path := LayoutPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling layout: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", LayoutFile, err)
+	}
+	return nil
+}
+
+// Validate checks the mode, the shared database name, and that every
+// branched rig name is usable as a branch.
+func (l *Layout) Validate() error {
+	switch l.Mode {
+	case LayoutDatabasePerRig, LayoutBranchPerRig:
+	default:
+		return fmt.Errorf("mode must be %s or %s, not %q", LayoutDatabasePerRig, LayoutBranchPerRig, l.Mode)
+	}
+	if !validDatabaseNameRe.MatchString(l.Database) {
+		return fmt.Errorf("shared database %q is not a legal database name (letters, digits, _ and -, at most 64)", l.Database)
+	}
+	if IsSystemDatabase(l.Database) || l.Database == "hq" || l.Database == WLCommonsDB {
+		return fmt.Errorf("shared database cannot be %q", l.Database)
+	}
+	seen := make(map[string]bool, len(l.Rigs))
+	for _, rig := range l.Rigs {
+		if err := validateBranchName(rig); err != nil {
+			return fmt.Errorf("rig %q: %w", rig, err)
+		}
+		if rig == l.Database || rig == "hq" {
+			return fmt.Errorf("rig %q cannot be on a branch", rig)
+		}
+		if seen[rig] {
+			return fmt.Errorf("rig %q listed twice", rig)
+		}
+		seen[rig] = true
+	}
+	return nil
+}
+
+// IsBranched reports whether rigName's beads live on a branch of the shared
+// database.
+func (l *Layout) IsBranched(rigName string) bool {
+	return slices.Contains(l.Rigs, rigName)
+}
+
+// RevisionDatabase returns the name that selects branch of db, e.g.
+// "town/gastown".
+func RevisionDatabase(db, branch string) string {
+	return db + "/" + branch
+}
+
+// SplitRevisionDatabase splits a revision database name into the database
+// and branch. A plain name has no branch.
+func SplitRevisionDatabase(name string) (db, branch string) {
+	db, branch, _ = strings.Cut(name, "/")
+	return db, branch
+}
+
+// RouteDatabase returns the database to connect to for db: the revision
+// database of a branched rig, otherwise db itself.
+func RouteDatabase(townRoot, db string) string {
+	if strings.Contains(db, "/") {
+		return db
+	}
+	layout, err := LoadLayout(townRoot)
+	if err != nil || !layout.IsBranched(db) {
+		return db
+	}
+	return RevisionDatabase(layout.Database, db)
+}
+
+// quoteIdentifier backquotes a database name for SQL.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// canBranch reports why rigName must keep its own database, or "" if it can
+// live on a branch.
+func canBranch(townRoot string, layout *Layout, rigName string) string {
+	switch rigName {
+	case "hq":
+		return "hq always keeps its own database"
+	case layout.Database:
+		return fmt.Sprintf("rig %q has the shared database's name", rigName)
+	}
+	if servers, err := LoadRigServers(townRoot); err == nil {
+		if _, ok := servers.Rigs[rigName]; ok {
+			return fmt.Sprintf("rig %q is on its own server (%s)", rigName, RigServersFile)
+		}
+	}
+	return ""
+}
+
+// ensureSharedDatabase creates the shared database if it doesn't exist.
+func ensureSharedDatabase(townRoot string, layout *Layout) error {
+	if DatabaseExists(townRoot, layout.Database) {
+		return nil
+	}
+	if _, err := createDatabase(townRoot, layout.Database); err != nil {
+		return fmt.Errorf("creating shared database %s: %w", layout.Database, err)
+	}
+	return nil
+}
+
+// initRigBranch puts a new rig on a branch of the shared database, forked
+// from its empty main, and records it in the layout. bd creates the schema
+// on the branch when it first connects.
+func initRigBranch(townRoot string, layout *Layout, rigName string) (running bool, err error) {
+	if err := ensureSharedDatabase(townRoot, layout); err != nil {
+		return false, err
+	}
+	running, _, _ = IsRunning(townRoot)
+	if running {
+		err = serverExecSQL(townRoot, fmt.Sprintf("USE %s; CALL DOLT_BRANCH('%s')", quoteIdentifier(layout.Database), rigName))
+	} else {
+		_, err = runDolt(RigDatabaseDir(townRoot, layout.Database), "branch", rigName)
+	}
+	if err != nil && !strings.Contains(err.Error(), "already exists") {
+		return running, fmt.Errorf("creating branch %s in %s: %w", rigName, layout.Database, err)
+	}
+
+	layout.Rigs = append(layout.Rigs, rigName)
+	if err := SaveLayout(townRoot, layout); err != nil {
+		return running, err
+	}
+	if err := EnsureMetadata(townRoot, rigName); err != nil {
+		logger.Warn("rig branch created but metadata.json update failed", "rig", rigName, "err", err)
+	}
+	return running, nil
+}
+
+// MigrateRigToBranch moves a rig's database onto a branch of the shared
+// database, history included, and routes the rig there. The rig's own
+// database is left in place, unreferenced, for 'gt dolt cleanup' to remove
+// once the move is checked. A database with branches besides main (polecats
+// still at work) is refused unless force is set; their branches are not
+// carried over. Writes made to the old database while this runs are lost,
+// so park the rig first.
+func MigrateRigToBranch(townRoot, rigName string, force bool) error {
+	layout, err := LoadLayout(townRoot)
+	if err != nil {
+		return err
+	}
+	if layout.IsBranched(rigName) {
+		return fmt.Errorf("rig %s is already on branch %s of %s", rigName, rigName, layout.Database)
+	}
+	if why := canBranch(townRoot, layout, rigName); why != "" {
+		return fmt.Errorf("cannot move %s to a branch: %s", rigName, why)
+	}
+	src := RigDatabaseName(townRoot, rigName)
+	if strings.Contains(src, "/") || !DatabaseExists(townRoot, src) {
+		return fmt.Errorf("rig %s has no database of its own in %s", rigName, DefaultConfig(townRoot).DataDir)
+	}
+
+	running, _, _ := IsRunning(townRoot)
+	srcDir := RigDatabaseDir(townRoot, src)
+	if running {
+		if err := CommitServerWorkingSet(townRoot, src, "gt dolt layout migrate"); err != nil {
+			return err
+		}
+	} else if err := CommitWorkingSet(srcDir); err != nil {
+		return fmt.Errorf("committing %s: %w", src, err)
+	}
+
+	branches, err := listBranches(townRoot, src, running)
+	if err != nil {
+		return fmt.Errorf("listing branches of %s: %w", src, err)
+	}
+	if extra := slices.DeleteFunc(branches, func(b string) bool { return b == "main" }); len(extra) > 0 && !force {
+		return fmt.Errorf("%s has branches besides main (%s); merge or delete them, or pass --force to leave them behind",
+			src, strings.Join(extra, ", "))
+	}
+
+	if err := ensureSharedDatabase(townRoot, layout); err != nil {
+		return err
+	}
+
+	// Copy the database through a backup, fetched into the shared database
+	// as a remote, so the rig branch keeps its history.
+	tmp, err := os.MkdirTemp("", "gt-dolt-layout-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	backupURL := "file://" + filepath.ToSlash(filepath.Join(tmp, "backup"))
+	remote := "gt-migrate-" + rigName
+	shared := RigDatabaseDir(townRoot, layout.Database)
+
+	if running {
+		if err := doltSQL(townRoot, src, fmt.Sprintf("CALL DOLT_BACKUP('sync-url', '%s')", sqlEscape(backupURL))); err != nil {
+			return fmt.Errorf("backing up %s: %w", src, err)
+		}
+		db := quoteIdentifier(layout.Database)
+		_ = serverExecSQL(townRoot, fmt.Sprintf("USE %s; CALL DOLT_REMOTE('remove', '%s')", db, remote))
+		script := fmt.Sprintf("USE %s; CALL DOLT_REMOTE('add', '%s', '%s'); CALL DOLT_FETCH('%s'); CALL DOLT_BRANCH('%s', '%s/main')",
+			db, remote, sqlEscape(backupURL), remote, rigName, remote)
+		err = serverExecSQL(townRoot, script)
+		_ = serverExecSQL(townRoot, fmt.Sprintf("USE %s; CALL DOLT_REMOTE('remove', '%s')", db, remote))
+	} else {
+		if _, err := runDolt(srcDir, "backup", "sync-url", backupURL); err != nil {
+			return fmt.Errorf("backing up %s: %w", src, err)
+		}
+		_, _ = runDolt(shared, "remote", "remove", remote)
+		if _, err = runDolt(shared, "remote", "add", remote, backupURL); err == nil {
+			if _, err = runDolt(shared, "fetch", remote); err == nil {
+				_, err = runDolt(shared, "branch", rigName, remote+"/main")
+			}
+		}
+		_, _ = runDolt(shared, "remote", "remove", remote)
+	}
+	if err != nil {
+		return fmt.Errorf("copying %s to branch %s of %s: %w", src, rigName, layout.Database, err)
+	}
+
+	layout.Rigs = append(layout.Rigs, rigName)
+	if err := SaveLayout(townRoot, layout); err != nil {
+		return err
+	}
+	if err := EnsureMetadata(townRoot, rigName); err != nil {
+		return fmt.Errorf("moved %s, but rewriting its metadata.json failed: %w (run 'gt dolt fix-metadata')", rigName, err)
+	}
+	return nil
+}
+
+// listBranches returns the branch names of db, through the server when it
+// is running.
+func listBranches(townRoot, db string, running bool) ([]string, error) {
+	var rows []map[string]interface{}
+	if running {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		query := fmt.Sprintf("SELECT name FROM %s.dolt_branches", quoteIdentifier(db))
+		output, err := buildDoltSQLCmd(ctx, DefaultConfig(townRoot), "-r", "json", "-q", query).Output()
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Rows []map[string]interface{} `json:"rows"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("parsing branches: %w", err)
+		}
+		rows = result.Rows
+	} else {
+		var err error
+		if rows, err = localDoltSQL(RigDatabaseDir(townRoot, db), "SELECT name FROM dolt_branches"); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for _, row := range rows {
+		if name, ok := row["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,107 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadLayout_MissingFile(t *testing.T) {
+	layout, err := LoadLayout(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	if layout.Mode != LayoutDatabasePerRig || layout.Database != DefaultSharedDatabase || len(layout.Rigs) != 0 {
+		t.Errorf("default layout = %+v", layout)
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  Layout
+		wantErr bool
+	}{
+		{"branched", Layout{Mode: LayoutBranchPerRig, Database: "town", Rigs: []string{"gastown", "beta"}}, false},
+		{"bad mode", Layout{Mode: "one-big-db", Database: "town"}, true},
+		{"bad database", Layout{Mode: LayoutBranchPerRig, Database: "my town"}, true},
+		{"system database", Layout{Mode: LayoutBranchPerRig, Database: "mysql"}, true},
+		{"hq database", Layout{Mode: LayoutBranchPerRig, Database: "hq"}, true},
+		{"hq branched", Layout{Mode: LayoutBranchPerRig, Database: "town", Rigs: []string{"hq"}}, true},
+		{"rig named like database", Layout{Mode: LayoutBranchPerRig, Database: "town", Rigs: []string{"town"}}, true},
+		{"duplicate rig", Layout{Mode: LayoutBranchPerRig, Database: "town", Rigs: []string{"beta", "beta"}}, true},
+		{"bad branch", Layout{Mode: LayoutBranchPerRig, Database: "town", Rigs: []string{"a'b"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.layout.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRouteDatabase(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := SaveLayout(townRoot, &Layout{Mode: LayoutBranchPerRig, Database: "rigs", Rigs: []string{"gastown"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := RouteDatabase(townRoot, "gastown"); got != "rigs/gastown" {
+		t.Errorf("branched rig routed to %q", got)
+	}
+	if got := RouteDatabase(townRoot, "beta"); got != "beta" {
+		t.Errorf("own-database rig routed to %q", got)
+	}
+	if got := RouteDatabase(townRoot, "rigs/gastown"); got != "rigs/gastown" {
+		t.Errorf("revision database rerouted to %q", got)
+	}
+	if db, branch := SplitRevisionDatabase("rigs/gastown"); db != "rigs" || branch != "gastown" {
+		t.Errorf("SplitRevisionDatabase = %q, %q", db, branch)
+	}
+	if got := RigDatabaseDir(townRoot, "rigs/gastown"); got != filepath.Join(townRoot, ".dolt-data", "rigs") {
+		t.Errorf("RigDatabaseDir(revision) = %q", got)
+	}
+}
+
+func TestEnsureMetadata_BranchedRig(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// bd init wrote its own database name; the layout overrides it.
+	metaPath := filepath.Join(beadsDir, "metadata.json")
+	if err := os.WriteFile(metaPath, []byte(`{"dolt_database": "beads_gt"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLayout(townRoot, &Layout{Mode: LayoutBranchPerRig, Database: DefaultSharedDatabase, Rigs: []string{"gastown"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureMetadata(townRoot, "gastown"); err != nil {
+		t.Fatalf("EnsureMetadata: %v", err)
+	}
+	if db := readMetadata(t, metaPath)["dolt_database"]; db != "town/gastown" {
+		t.Errorf("dolt_database = %v, want town/gastown", db)
+	}
+
+	// The shared database is referenced through the branch; the rig's old
+	// database is not.
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(`{"rigs": {"gastown": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	referenced := collectReferencedDatabases(townRoot)
+	if !referenced["town"] || referenced["gastown"] || referenced["town/gastown"] {
+		t.Errorf("referenced = %v", referenced)
+	}
+	if names := TownDatabaseNames(townRoot); !slices.Equal(names, []string{"hq", "town"}) {
+		t.Errorf("TownDatabaseNames = %v", names)
+	}
+}
@@ -0,0 +1,46 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// listQueryTimeout bounds a beads.List query answered in SQL.
+const listQueryTimeout = 30 * time.Second
+
+func init() {
+	beads.RegisterListQuery(runListQuery)
+}
+
+// runListQuery runs a beads.List query on the server holding beadsDir's
+// database: the rig's own server when it has one, else the town's.
+func runListQuery(beadsDir, query string) ([]byte, error) {
+	townRoot := beads.FindTownRoot(beadsDir)
+	if townRoot == "" {
+		return nil, fmt.Errorf("%s is not in a town", beadsDir)
+	}
+	config := ConfigForRig(townRoot, rigForBeadsDir(townRoot, beadsDir))
+
+	ctx, cancel := context.WithTimeout(context.Background(), listQueryTimeout)
+	defer cancel()
+	output, err := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", query).Output()
+	if err != nil {
+		return nil, fmt.Errorf("querying beads: %w", err)
+	}
+	return output, nil
+}
+
+// rigForBeadsDir returns the rig whose beads live in beadsDir, or "hq" for
+// the town's.
+func rigForBeadsDir(townRoot, beadsDir string) string {
+	rel, err := filepath.Rel(townRoot, beadsDir)
+	if err != nil || rel == ".beads" || strings.HasPrefix(rel, "..") {
+		return "hq"
+	}
+	return strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+}
@@ -0,0 +1,21 @@
+package doltserver
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRigForBeadsDir(t *testing.T) {
+	town := filepath.Join(t.TempDir(), "town")
+	tests := map[string]string{
+		filepath.Join(town, ".beads"):                            "hq",
+		filepath.Join(town, "gastown", ".beads"):                 "gastown",
+		filepath.Join(town, "gastown", "mayor", "rig", ".beads"): "gastown",
+		filepath.Join(t.TempDir(), ".beads"):                     "hq",
+	}
+	for dir, want := range tests {
+		if got := rigForBeadsDir(town, dir); got != want {
+			t.Errorf("rigForBeadsDir(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
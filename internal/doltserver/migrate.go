@@ -0,0 +1,494 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// migrationStagingDir holds in-flight migration state under the data
+// directory: the journal and, for cross-filesystem moves, partial copies.
+// The server only loads databases from direct children of the data
+// directory, so nothing in here is ever served.
+const migrationStagingDir = ".migrating"
+
+// MigrationState is how far a journaled migration got.
+type MigrationState string
+
+const (
+	// MigrationPending means nothing has been moved yet.
+	MigrationPending MigrationState = "pending"
+
+	// MigrationCopying means a cross-filesystem copy is in progress. The
+	// partial copy is kept and resumed.
+	MigrationCopying MigrationState = "copying"
+
+	// MigrationMoved means the database is in place but metadata and
+	// verification are outstanding.
+	MigrationMoved MigrationState = "moved"
+
+	// MigrationDone means the migration completed.
+	MigrationDone MigrationState = "done"
+)
+
+// TableCount is a table's row count, recorded from the source database so
+// the migrated copy can be checked table by table.
+type TableCount struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// MigrationJournalEntry is one database in a migration run.
+type MigrationJournalEntry struct {
+	Migration
+	State  MigrationState `json:"state"`
+	Tables []TableCount   `json:"tables,omitempty"`
+}
+
+// MigrationJournal records a gt dolt migrate run so an interrupted run can
+// continue where it stopped.
+type MigrationJournal struct {
+	Started time.Time                `json:"started"`
+	Entries []*MigrationJournalEntry `json:"entries"`
+
+	path string
+	mu   sync.Mutex // Serializes changes and saves between migrations
+}
+
+// MigrationJournalPath returns the journal location for a town.
+func MigrationJournalPath(townRoot string) string {
+	return filepath.Join(DefaultConfig(townRoot).DataDir, migrationStagingDir, "journal.json")
+}
+
+// LoadMigrationJournal loads the town's migration journal. It returns nil
+// and no error if no migration is in progress.
+func LoadMigrationJournal(townRoot string) (*MigrationJournal, error) {
+	path := MigrationJournalPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town data dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var j MigrationJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	j.path = path
+	return &j, nil
+}
+
+// NewMigrationJournal starts a journal for migrations. Unfinished entries
+// of resumed, the journal of an interrupted run, are carried over first
+// with their state.
+func NewMigrationJournal(townRoot string, migrations []Migration, resumed *MigrationJournal) *MigrationJournal {
+	j := &MigrationJournal{Started: time.Now(), path: MigrationJournalPath(townRoot)}
+	if resumed != nil {
+		j.Started = resumed.Started
+		for _, e := range resumed.Entries {
+			if e.State != MigrationDone {
+				j.Entries = append(j.Entries, e)
+			}
+		}
+	}
+	for _, m := range migrations {
+		if j.Find(m.RigName) == nil {
+			j.Entries = append(j.Entries, &MigrationJournalEntry{Migration: m, State: MigrationPending})
+		}
+	}
+	return j
+}
+
+// Find returns the entry for a rig, or nil.
+func (j *MigrationJournal) Find(rigName string) *MigrationJournalEntry {
+	for _, e := range j.Entries {
+		if e.RigName == rigName {
+			return e
+		}
+	}
+	return nil
+}
+
+// Save writes the journal.
+func (j *MigrationJournal) Save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.save()
+}
+
+// record applies change to an entry and saves the journal. Migrations that
+// run side by side share the journal, so each change is made under its
+// lock.
+func (j *MigrationJournal) record(change func()) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	change()
+	return j.save()
+}
+
+func (j *MigrationJournal) save() error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// Finish removes the journal and the staging directory once every entry is
+// done. It leaves both in place otherwise.
+func (j *MigrationJournal) Finish() error {
+	for _, e := range j.Entries {
+		if e.State != MigrationDone {
+			return nil
+		}
+	}
+	return os.RemoveAll(filepath.Dir(j.path))
+}
+
+// MigrationProgress is reported while a database is migrated. Phase is
+// "tables" while row counts are taken from the source, "copy" while files
+// are moved, and "verify" while the migrated tables are checked. MigrateAll
+// also reports "done" when a database finishes, with Err set if it failed.
+type MigrationProgress struct {
+	RigName     string
+	Phase       string
+	Table       string // Table just counted ("tables" and "verify")
+	TableRows   int64
+	TablesDone  int
+	TablesTotal int
+	BytesDone   int64 // "copy" only
+	BytesTotal  int64
+	Remaining   time.Duration // Estimate for the phase; 0 if unknown
+	Err         error         // "done" only
+
+	// Set by MigrateAll: databases finished so far, and an estimate for
+	// the whole run (0 if unknown).
+	RigsDone       int
+	RigsTotal      int
+	TotalRemaining time.Duration
+}
+
+// ProgressFunc receives migration progress updates.
+type ProgressFunc func(MigrationProgress)
+
+// MigrateJournaled migrates one journaled database, saving the journal
+// after each step. It differs from MigrateRigFromBeads in three ways: row
+// counts are recorded per table before the move and checked after it; a
+// cross-filesystem move is a resumable copy staged under the data
+// directory; and progress, with an estimate of the time remaining, is
+// reported through progress (which may be nil).
+func MigrateJournaled(townRoot string, j *MigrationJournal, e *MigrationJournalEntry, progress ProgressFunc) error {
+	if progress == nil {
+		progress = func(MigrationProgress) {}
+	}
+
+	if e.State == MigrationPending && len(e.Tables) > 0 && !exists(e.SourcePath) && exists(filepath.Join(e.TargetPath, ".dolt")) {
+		// Interrupted right after the rename, before the journal was saved
+		j.mu.Lock()
+		e.State = MigrationMoved
+		j.mu.Unlock()
+	}
+
+	if e.State == MigrationPending {
+		if _, err := os.Stat(filepath.Join(e.TargetPath, ".dolt")); err == nil {
+			return fmt.Errorf("rig database %q already exists at %s", e.RigName, e.TargetPath)
+		}
+		if _, err := os.Stat(filepath.Join(e.SourcePath, ".dolt")); err != nil {
+			return fmt.Errorf("source database not found at %s", e.SourcePath)
+		}
+		tables, err := countTables(e.SourcePath, func(p MigrationProgress) {
+			p.RigName, p.Phase = e.RigName, "tables"
+			progress(p)
+		})
+		if err != nil {
+			return fmt.Errorf("counting source tables: %w", err)
+		}
+		if err := j.record(func() { e.Tables = tables }); err != nil {
+			return fmt.Errorf("saving journal: %w", err)
+		}
+	}
+
+	if e.State == MigrationPending || e.State == MigrationCopying {
+		if err := os.MkdirAll(filepath.Dir(e.TargetPath), 0755); err != nil {
+			return fmt.Errorf("creating data directory: %w", err)
+		}
+		if err := moveJournaled(j, e, progress); err != nil {
+			return err
+		}
+		if err := j.record(func() { e.State = MigrationMoved }); err != nil {
+			return fmt.Errorf("saving journal: %w", err)
+		}
+	}
+
+	if e.State == MigrationMoved {
+		if err := EnsureMetadata(townRoot, e.RigName); err != nil {
+			// Non-fatal: migration succeeded, metadata update failed
+			logger.Warn("database migrated but metadata.json update failed", "err", err)
+		}
+		if err := verifyTables(e.TargetPath, e.Tables, func(p MigrationProgress) {
+			p.RigName, p.Phase = e.RigName, "verify"
+			progress(p)
+		}); err != nil {
+			return err
+		}
+		if err := j.record(func() { e.State = MigrationDone }); err != nil {
+			return fmt.Errorf("saving journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// moveJournaled moves the source database to its target: a rename when
+// both are on one filesystem, otherwise a copy into the staging directory
+// that skips files an earlier, interrupted run already copied.
+func moveJournaled(j *MigrationJournal, e *MigrationJournalEntry, progress ProgressFunc) error {
+	if e.State == MigrationPending {
+		err := os.Rename(e.SourcePath, e.TargetPath)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("moving database: %w", err)
+		}
+		if err := j.record(func() { e.State = MigrationCopying }); err != nil {
+			return fmt.Errorf("saving journal: %w", err)
+		}
+	}
+
+	staging := filepath.Join(filepath.Dir(j.path), e.RigName)
+	if _, err := os.Stat(e.SourcePath); os.IsNotExist(err) {
+		// Interrupted after the source was removed: the copy is complete.
+		if _, err := os.Stat(e.TargetPath); err == nil {
+			return nil
+		}
+		if err := os.Rename(staging, e.TargetPath); err != nil {
+			return fmt.Errorf("source is gone and no staged copy at %s: %w", staging, err)
+		}
+		return nil
+	}
+
+	total := dirSize(e.SourcePath)
+	start := time.Now()
+	var done, copied int64
+	err := copyTreeResumable(staging, e.SourcePath, func(n int64, skipped bool) {
+		done += n
+		if !skipped {
+			copied += n
+		}
+		progress(MigrationProgress{
+			RigName:    e.RigName,
+			Phase:      "copy",
+			BytesDone:  done,
+			BytesTotal: total,
+			Remaining:  estimateRemaining(time.Since(start), copied, total-done),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("copying database (re-run to resume): %w", err)
+	}
+	if err := os.Rename(staging, e.TargetPath); err != nil {
+		return fmt.Errorf("moving staged copy into place: %w", err)
+	}
+	if err := os.RemoveAll(e.SourcePath); err != nil {
+		return fmt.Errorf("removing source after copy: %w", err)
+	}
+	return nil
+}
+
+// copyTreeResumable copies src into dst. A file already in dst with the
+// source's size and modification time is skipped, so an interrupted copy
+// picks up where it stopped. onFile is called with each file's size.
+func copyTreeResumable(dst, src string, onFile func(n int64, skipped bool)) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if existing, err := os.Stat(target); err == nil &&
+			existing.Size() == info.Size() && existing.ModTime().Equal(info.ModTime()) {
+			onFile(info.Size(), true)
+			return nil
+		}
+		if err := copyFileStreaming(target, path, info); err != nil {
+			return fmt.Errorf("copying %s: %w", rel, err)
+		}
+		onFile(info.Size(), false)
+		return nil
+	})
+}
+
+// copyFileStreaming copies a file without loading it into memory, syncs
+// it, and stamps it with the source's modification time last, so a file cut
+// short by an interruption never looks complete.
+func copyFileStreaming(dst, src string, info os.FileInfo) error {
+	in, err := os.Open(src) //nolint:gosec // G304: path is within the source database
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm()) //nolint:gosec // G304: path is within the staging dir
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// estimateRemaining extrapolates the time left from the rate so far.
+func estimateRemaining(elapsed time.Duration, done, left int64) time.Duration {
+	if done <= 0 || left <= 0 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * float64(left) / float64(done))
+}
+
+// countTables lists the tables of an embedded database (the server must be
+// stopped) and counts each one's rows, reporting each table as it goes.
+func countTables(dbDir string, progress ProgressFunc) ([]TableCount, error) {
+	out, err := localDoltSQL(dbDir, "SHOW TABLES")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, row := range out {
+		for _, v := range row {
+			if s, ok := v.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	tables := make([]TableCount, 0, len(names))
+	start := time.Now()
+	for i, name := range names {
+		rows, err := countRows(dbDir, name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, TableCount{Table: name, Rows: rows})
+		progress(MigrationProgress{
+			Table:       name,
+			TableRows:   rows,
+			TablesDone:  i + 1,
+			TablesTotal: len(names),
+			Remaining:   estimateRemaining(time.Since(start), int64(i+1), int64(len(names)-i-1)),
+		})
+	}
+	return tables, nil
+}
+
+// verifyTables checks that each recorded table has the same row count in
+// the migrated database.
+func verifyTables(dbDir string, tables []TableCount, progress ProgressFunc) error {
+	var total, done int64
+	for _, t := range tables {
+		total += t.Rows
+	}
+	start := time.Now()
+	var mismatched []string
+	for i, t := range tables {
+		rows, err := countRows(dbDir, t.Table)
+		if err != nil {
+			return fmt.Errorf("verifying %s: %w", t.Table, err)
+		}
+		if rows != t.Rows {
+			mismatched = append(mismatched, fmt.Sprintf("%s (%d rows, expected %d)", t.Table, rows, t.Rows))
+		}
+		done += t.Rows
+		progress(MigrationProgress{
+			Table:       t.Table,
+			TableRows:   rows,
+			TablesDone:  i + 1,
+			TablesTotal: len(tables),
+			Remaining:   estimateRemaining(time.Since(start), done, total-done),
+		})
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("row counts differ after migration: %s", strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+func countRows(dbDir, table string) (int64, error) {
+	out, err := localDoltSQL(dbDir, fmt.Sprintf("SELECT COUNT(*) AS n FROM `%s`", strings.ReplaceAll(table, "`", "``")))
+	if err != nil {
+		return 0, err
+	}
+	if len(out) != 1 {
+		return 0, fmt.Errorf("counting %s: unexpected result", table)
+	}
+	n, ok := out[0]["n"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("counting %s: unexpected result", table)
+	}
+	return int64(n), nil
+}
+
+// localDoltSQL runs a query against an embedded database directory and
+// returns its JSON rows.
+func localDoltSQL(dbDir, query string) ([]map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "json", "-q", query)
+	cmd.Dir = dbDir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("dolt sql: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	var result struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("parsing dolt sql output: %w", err)
+	}
+	return result.Rows, nil
+}
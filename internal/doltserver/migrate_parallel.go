@@ -0,0 +1,140 @@
+package doltserver
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMigrateJobs is how many databases MigrateAll migrates at once when
+// not told otherwise. Counting and verifying rows runs a dolt process per
+// table, so a few at a time keeps a town's CPUs busy without thrashing its
+// disk.
+const DefaultMigrateJobs = 4
+
+// MigrateAll migrates the journal's unfinished entries, up to jobs at a
+// time. A failed database doesn't stop the others; the errors are returned
+// by rig name. Calls to progress are serialized, so it needn't be safe for
+// concurrent use.
+func MigrateAll(townRoot string, j *MigrationJournal, jobs int, progress ProgressFunc) map[string]error {
+	if jobs < 1 {
+		jobs = 1
+	}
+	if progress == nil {
+		progress = func(MigrationProgress) {}
+	}
+
+	var pending []*MigrationJournalEntry
+	for _, e := range j.Entries {
+		if e.State != MigrationDone {
+			pending = append(pending, e)
+		}
+	}
+	tracker := newMigrationTracker(pending, time.Now())
+
+	var (
+		mu     sync.Mutex // Guards tracker, errs and calls to progress
+		errs   = make(map[string]error)
+		wg     sync.WaitGroup
+		queue  = make(chan *MigrationJournalEntry)
+		report = func(p MigrationProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			tracker.update(&p, time.Now())
+			progress(p)
+		}
+	)
+	for i := 0; i < min(jobs, len(pending)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range queue {
+				err := MigrateJournaled(townRoot, j, e, report)
+				if err != nil {
+					mu.Lock()
+					errs[e.RigName] = err
+					mu.Unlock()
+				}
+				report(MigrationProgress{RigName: e.RigName, Phase: "done", Err: err})
+			}
+		}()
+	}
+	for _, e := range pending {
+		queue <- e
+	}
+	close(queue)
+	wg.Wait()
+	return errs
+}
+
+// migrationTracker estimates the time left for a MigrateAll run. Each
+// database counts toward the total by its size, and within a database the
+// three phases (counting, moving and verifying) count equally, since a
+// same-filesystem move is instant but counting rows is not.
+type migrationTracker struct {
+	start   time.Time
+	size    map[string]float64
+	done    map[string]float64 // Fraction of each database migrated
+	total   float64
+	rigs    int
+	rigDone int
+}
+
+func newMigrationTracker(entries []*MigrationJournalEntry, now time.Time) *migrationTracker {
+	t := &migrationTracker{
+		start: now,
+		size:  make(map[string]float64),
+		done:  make(map[string]float64),
+		rigs:  len(entries),
+	}
+	for _, e := range entries {
+		path := e.SourcePath
+		if e.State == MigrationMoved {
+			path = e.TargetPath
+		}
+		// Every database counts for something, however small
+		size := float64(max(dirSize(path), 1))
+		t.size[e.RigName] = size
+		t.total += size
+	}
+	return t
+}
+
+// update records p and fills in its run-wide fields.
+func (t *migrationTracker) update(p *MigrationProgress, now time.Time) {
+	if f := phaseFraction(p); f > t.done[p.RigName] {
+		t.done[p.RigName] = f
+	}
+	if p.Phase == "done" {
+		t.rigDone++
+	}
+	var done float64
+	for rig, f := range t.done {
+		done += f * t.size[rig]
+	}
+	p.RigsDone, p.RigsTotal = t.rigDone, t.rigs
+	p.TotalRemaining = 0
+	if done > 0 && done < t.total {
+		p.TotalRemaining = time.Duration(float64(now.Sub(t.start)) * (t.total - done) / done)
+	}
+}
+
+// phaseFraction is how much of a database's migration is behind p.
+func phaseFraction(p *MigrationProgress) float64 {
+	within := func(done, total int64) float64 {
+		if total <= 0 {
+			return 1
+		}
+		return float64(done) / float64(total)
+	}
+	switch p.Phase {
+	case "tables":
+		return within(int64(p.TablesDone), int64(p.TablesTotal)) / 3
+	case "copy":
+		return (1 + within(p.BytesDone, p.BytesTotal)) / 3
+	case "verify":
+		return (2 + within(int64(p.TablesDone), int64(p.TablesTotal))) / 3
+	case "done":
+		return 1
+	}
+	return 0
+}
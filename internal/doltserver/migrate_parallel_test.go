@@ -0,0 +1,65 @@
+package doltserver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigrationTracker_WeighsDatabasesBySize(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "small", "data"), strings.Repeat("s", 100))
+	writeTestFile(t, filepath.Join(dir, "large", "data"), strings.Repeat("l", 300))
+	entries := []*MigrationJournalEntry{
+		{Migration: Migration{RigName: "small", SourcePath: filepath.Join(dir, "small")}, State: MigrationPending},
+		{Migration: Migration{RigName: "large", SourcePath: filepath.Join(dir, "large")}, State: MigrationPending},
+	}
+	start := time.Now()
+	tracker := newMigrationTracker(entries, start)
+
+	p := MigrationProgress{RigName: "small", Phase: "done"}
+	tracker.update(&p, start.Add(10*time.Second))
+	if p.RigsDone != 1 || p.RigsTotal != 2 {
+		t.Errorf("rigs = %d/%d, want 1/2", p.RigsDone, p.RigsTotal)
+	}
+	if p.TotalRemaining != 30*time.Second {
+		t.Errorf("TotalRemaining = %v, want 30s (a quarter of the bytes in 10s)", p.TotalRemaining)
+	}
+
+	// Halfway through counting the large database's tables is a sixth of it
+	p = MigrationProgress{RigName: "large", Phase: "tables", TablesDone: 1, TablesTotal: 2}
+	tracker.update(&p, start.Add(15*time.Second))
+	if p.TotalRemaining != 25*time.Second {
+		t.Errorf("TotalRemaining = %v, want 25s", p.TotalRemaining)
+	}
+}
+
+func TestMigrateAll_CollectsErrorsAndReportsEachDatabase(t *testing.T) {
+	townRoot := t.TempDir()
+	var migrations []Migration
+	for _, rig := range []string{"a", "b", "c"} {
+		migrations = append(migrations, Migration{
+			RigName:    rig,
+			SourcePath: filepath.Join(townRoot, rig, ".beads", "dolt"),
+			TargetPath: filepath.Join(townRoot, ".dolt-data", rig),
+		})
+	}
+	j := NewMigrationJournal(townRoot, migrations, nil)
+
+	var done []string
+	errs := MigrateAll(townRoot, j, 2, func(p MigrationProgress) {
+		if p.Phase == "done" {
+			done = append(done, p.RigName)
+			if p.Err == nil {
+				t.Errorf("%s reported done without error, but has no source database", p.RigName)
+			}
+		}
+	})
+	if len(errs) != 3 {
+		t.Errorf("errors = %v, want one per database (a failure mustn't stop the rest)", errs)
+	}
+	if len(done) != 3 {
+		t.Errorf("done reported for %v, want every database", done)
+	}
+}
@@ -0,0 +1,135 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMigrationJournal_SaveLoadResume(t *testing.T) {
+	townRoot := t.TempDir()
+	if j, err := LoadMigrationJournal(townRoot); err != nil || j != nil {
+		t.Fatalf("LoadMigrationJournal with no journal = %v, %v; want nil, nil", j, err)
+	}
+
+	j := NewMigrationJournal(townRoot, []Migration{{RigName: "hq"}, {RigName: "gastown"}}, nil)
+	j.Find("hq").State = MigrationDone
+	j.Find("gastown").State = MigrationCopying
+	j.Find("gastown").Tables = []TableCount{{Table: "issues", Rows: 42}}
+	if err := j.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadMigrationJournal(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := NewMigrationJournal(townRoot, []Migration{{RigName: "gastown"}, {RigName: "beads"}}, loaded)
+	if len(resumed.Entries) != 2 {
+		t.Fatalf("entries = %d, want 2 (done hq dropped)", len(resumed.Entries))
+	}
+	gastown := resumed.Entries[0]
+	if gastown.RigName != "gastown" || gastown.State != MigrationCopying || len(gastown.Tables) != 1 {
+		t.Errorf("resumed entry = %+v, want gastown kept in copying state", gastown)
+	}
+	if e := resumed.Find("beads"); e == nil || e.State != MigrationPending {
+		t.Errorf("new entry = %+v, want pending", e)
+	}
+	if !resumed.Started.Equal(loaded.Started) {
+		t.Errorf("Started = %v, want %v", resumed.Started, loaded.Started)
+	}
+
+	for _, e := range resumed.Entries {
+		e.State = MigrationDone
+	}
+	if err := resumed.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Dir(MigrationJournalPath(townRoot))); !os.IsNotExist(err) {
+		t.Errorf("staging dir still present after Finish: %v", err)
+	}
+}
+
+func TestCopyTreeResumable_SkipsCopiedFiles(t *testing.T) {
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "copy")
+	writeTestFile(t, filepath.Join(src, ".dolt", "noms", "a"), "aaaa")
+	writeTestFile(t, filepath.Join(src, ".dolt", "noms", "b"), "bbbbbbbb")
+	writeTestFile(t, filepath.Join(src, ".dolt", "repo_state.json"), "{}")
+
+	var copied, skipped int64
+	count := func(n int64, skip bool) {
+		if skip {
+			skipped += n
+		} else {
+			copied += n
+		}
+	}
+	if err := copyTreeResumable(dst, src, count); err != nil {
+		t.Fatal(err)
+	}
+	if copied != 14 || skipped != 0 {
+		t.Errorf("first copy: copied=%d skipped=%d, want 14/0", copied, skipped)
+	}
+
+	// Simulate an interrupted copy: one file cut short, one never written.
+	writeTestFile(t, filepath.Join(dst, ".dolt", "noms", "b"), "bb")
+	if err := os.Remove(filepath.Join(dst, ".dolt", "repo_state.json")); err != nil {
+		t.Fatal(err)
+	}
+	copied, skipped = 0, 0
+	if err := copyTreeResumable(dst, src, count); err != nil {
+		t.Fatal(err)
+	}
+	if copied != 10 || skipped != 4 {
+		t.Errorf("resumed copy: copied=%d skipped=%d, want 10/4", copied, skipped)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, ".dolt", "noms", "b")); string(data) != "bbbbbbbb" {
+		t.Errorf("b = %q, want recopied", data)
+	}
+}
+
+func TestMoveJournaled_FinishesStagedCopy(t *testing.T) {
+	townRoot := t.TempDir()
+	dataDir := DefaultConfig(townRoot).DataDir
+	e := &MigrationJournalEntry{
+		Migration: Migration{
+			RigName:    "gastown",
+			SourcePath: filepath.Join(townRoot, "gastown", ".beads", "dolt", "beads_gt"),
+			TargetPath: filepath.Join(dataDir, "gastown"),
+		},
+		State: MigrationCopying,
+	}
+	j := NewMigrationJournal(townRoot, nil, nil)
+	j.Entries = append(j.Entries, e)
+
+	// Interrupted after the source was removed: only the staged copy is left.
+	staged := filepath.Join(dataDir, migrationStagingDir, "gastown")
+	writeTestFile(t, filepath.Join(staged, ".dolt", "repo_state.json"), "{}")
+
+	if err := moveJournaled(j, e, func(MigrationProgress) {}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(e.TargetPath, ".dolt", "repo_state.json")); err != nil {
+		t.Errorf("staged copy not moved into place: %v", err)
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	if got := estimateRemaining(10*time.Second, 100, 300); got != 30*time.Second {
+		t.Errorf("estimateRemaining = %v, want 30s", got)
+	}
+	if got := estimateRemaining(10*time.Second, 0, 300); got != 0 {
+		t.Errorf("estimateRemaining with no progress = %v, want 0", got)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,218 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MQArchiveTable is the table in a rig's database holding merge requests
+// moved out of the live queue by gt mq archive. Rows keep the MR's full
+// description, so nothing is lost when the bead is deleted, and are plain
+// SQL for history queries:
+//
+//	SELECT worker, COUNT(*) FROM gt_mq_archive
+//	WHERE close_reason = 'merged' GROUP BY worker;
+const MQArchiveTable = "gt_mq_archive"
+
+// mqArchiveBatch is how many rows go in one REPLACE statement, keeping the
+// query passed to dolt sql well under argument size limits.
+const mqArchiveBatch = 50
+
+const mqArchiveSchema = `CREATE TABLE IF NOT EXISTS ` + MQArchiveTable + ` (
+    id VARCHAR(64) PRIMARY KEY,
+    rig VARCHAR(128) NOT NULL,
+    title TEXT,
+    branch VARCHAR(255),
+    target VARCHAR(255),
+    source_issue VARCHAR(64),
+    worker VARCHAR(255),
+    merge_commit VARCHAR(64),
+    close_reason VARCHAR(32),
+    priority INT,
+    created_at DATETIME,
+    closed_at DATETIME,
+    archived_at DATETIME NOT NULL,
+    description LONGTEXT,
+    INDEX idx_mq_archive_closed (closed_at),
+    INDEX idx_mq_archive_worker (worker)
+)`
+
+// ArchivedMR is a merge request row in MQArchiveTable.
+type ArchivedMR struct {
+	ID          string    `json:"id"`
+	Rig         string    `json:"rig"`
+	Title       string    `json:"title,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	Target      string    `json:"target,omitempty"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	MergeCommit string    `json:"merge_commit,omitempty"`
+	CloseReason string    `json:"close_reason,omitempty"`
+	Priority    int       `json:"priority"`
+	CreatedAt   time.Time `json:"created_at"`
+	ClosedAt    time.Time `json:"closed_at"`
+	ArchivedAt  time.Time `json:"archived_at"`
+	Description string    `json:"description,omitempty"`
+}
+
+// RigDatabase returns the Dolt database holding rigName's beads: the one
+// named in the rig's metadata.json, or the rig name.
+func RigDatabase(townRoot, rigName string) string {
+	if db := readExistingDoltDatabase(FindRigBeadsDir(townRoot, rigName)); db != "" {
+		return db
+	}
+	return rigName
+}
+
+// ArchiveMRs writes mrs to the rig's MQArchiveTable, creating the table on
+// first use, and commits them to Dolt history. Rows already archived are
+// replaced, so archiving is safe to retry.
+func ArchiveMRs(townRoot, rigName string, mrs []ArchivedMR) error {
+	if len(mrs) == 0 {
+		return nil
+	}
+	db := RigDatabase(townRoot, rigName)
+	config := ConfigForRig(townRoot, rigName)
+	if err := mqArchiveSQL(config, db, mqArchiveSchema); err != nil {
+		return fmt.Errorf("creating %s: %w", MQArchiveTable, err)
+	}
+	for start := 0; start < len(mrs); start += mqArchiveBatch {
+		end := min(start+mqArchiveBatch, len(mrs))
+		if err := mqArchiveSQL(config, db, buildMQArchiveInsert(mrs[start:end])); err != nil {
+			return fmt.Errorf("archiving merge requests: %w", err)
+		}
+	}
+	commit := fmt.Sprintf("CALL DOLT_ADD('%s'); CALL DOLT_COMMIT('-m', 'gt mq archive: %d merge request(s)')", MQArchiveTable, len(mrs))
+	if err := mqArchiveSQL(config, db, commit); err != nil && !strings.Contains(err.Error(), "nothing to commit") {
+		return fmt.Errorf("committing archive: %w", err)
+	}
+	return nil
+}
+
+// ArchivedMRs returns the rig's archived merge requests closed at or after
+// since, oldest first. A rig that has never archived has none.
+func ArchivedMRs(townRoot, rigName string, since time.Time) ([]ArchivedMR, error) {
+	db := RigDatabase(townRoot, rigName)
+	config := ConfigForRig(townRoot, rigName)
+	query := fmt.Sprintf("SELECT id, rig, title, branch, target, source_issue, worker, merge_commit, close_reason, priority, "+
+		"created_at, closed_at, archived_at, description FROM `%s`.%s WHERE closed_at >= '%s' ORDER BY closed_at",
+		db, MQArchiveTable, formatSQLTime(since))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	output, err := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", query).CombinedOutput()
+	if err != nil {
+		msg := strings.ToLower(string(output))
+		if strings.Contains(msg, "table not found") || strings.Contains(msg, "doesn't exist") ||
+			strings.Contains(msg, "database not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying %s: %w (output: %s)", MQArchiveTable, err, strings.TrimSpace(string(output)))
+	}
+	return parseArchivedMRs(output)
+}
+
+// mqArchiveSQL runs statements against db on the rig's server.
+func mqArchiveSQL(config *Config, db, query string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := buildDoltSQLCmd(ctx, config, "-q", fmt.Sprintf("USE `%s`; %s", db, query))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// buildMQArchiveInsert returns a REPLACE statement for mrs.
+func buildMQArchiveInsert(mrs []ArchivedMR) string {
+	var sb strings.Builder
+	sb.WriteString("REPLACE INTO " + MQArchiveTable + " (id, rig, title, branch, target, source_issue, worker, " +
+		"merge_commit, close_reason, priority, created_at, closed_at, archived_at, description) VALUES ")
+	for i, mr := range mrs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "('%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s', %d, %s, %s, '%s', '%s')",
+			sqlEscape(mr.ID), sqlEscape(mr.Rig), sqlEscape(mr.Title), sqlEscape(mr.Branch), sqlEscape(mr.Target),
+			sqlEscape(mr.SourceIssue), sqlEscape(mr.Worker), sqlEscape(mr.MergeCommit), sqlEscape(mr.CloseReason),
+			mr.Priority, sqlTimeOrNull(mr.CreatedAt), sqlTimeOrNull(mr.ClosedAt), formatSQLTime(mr.ArchivedAt),
+			sqlEscape(mr.Description))
+	}
+	return sb.String()
+}
+
+// parseArchivedMRs decodes dolt sql -r json output of an archive query.
+func parseArchivedMRs(output []byte) ([]ArchivedMR, error) {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []struct {
+			ID          string `json:"id"`
+			Rig         string `json:"rig"`
+			Title       string `json:"title"`
+			Branch      string `json:"branch"`
+			Target      string `json:"target"`
+			SourceIssue string `json:"source_issue"`
+			Worker      string `json:"worker"`
+			MergeCommit string `json:"merge_commit"`
+			CloseReason string `json:"close_reason"`
+			Priority    int    `json:"priority"`
+			CreatedAt   string `json:"created_at"`
+			ClosedAt    string `json:"closed_at"`
+			ArchivedAt  string `json:"archived_at"`
+			Description string `json:"description"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("parsing %s rows: %w", MQArchiveTable, err)
+	}
+	mrs := make([]ArchivedMR, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		mrs = append(mrs, ArchivedMR{
+			ID:          row.ID,
+			Rig:         row.Rig,
+			Title:       row.Title,
+			Branch:      row.Branch,
+			Target:      row.Target,
+			SourceIssue: row.SourceIssue,
+			Worker:      row.Worker,
+			MergeCommit: row.MergeCommit,
+			CloseReason: row.CloseReason,
+			Priority:    row.Priority,
+			CreatedAt:   parseSQLTime(row.CreatedAt),
+			ClosedAt:    parseSQLTime(row.ClosedAt),
+			ArchivedAt:  parseSQLTime(row.ArchivedAt),
+			Description: row.Description,
+		})
+	}
+	return mrs, nil
+}
+
+// sqlTimeLayout is the DATETIME literal format; archive times are UTC.
+const sqlTimeLayout = "2006-01-02 15:04:05"
+
+func formatSQLTime(t time.Time) string {
+	return t.UTC().Format(sqlTimeLayout)
+}
+
+func sqlTimeOrNull(t time.Time) string {
+	if t.IsZero() {
+		return "NULL"
+	}
+	return "'" + formatSQLTime(t) + "'"
+}
+
+// parseSQLTime parses a DATETIME as Dolt prints it, with or without
+// fractional seconds. NULL or unparseable values are the zero time.
+func parseSQLTime(s string) time.Time {
+	for _, layout := range []string{sqlTimeLayout, "2006-01-02 15:04:05.999999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
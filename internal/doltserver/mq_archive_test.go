@@ -0,0 +1,60 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMQArchiveInsert(t *testing.T) {
+	closed := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	got := buildMQArchiveInsert([]ArchivedMR{
+		{ID: "gt-mr1", Rig: "gastown", Title: "Merge: it's done", Worker: "gastown/polecats/nux",
+			CloseReason: "merged", Priority: 2, ClosedAt: closed, ArchivedAt: closed.Add(time.Hour)},
+		{ID: "gt-mr2", Rig: "gastown", CloseReason: "rejected", ArchivedAt: closed},
+	})
+
+	for _, want := range []string{
+		"REPLACE INTO gt_mq_archive",
+		"'Merge: it''s done'",
+		"'merged', 2, NULL, '2026-03-01 10:30:00', '2026-03-01 11:30:00'",
+		"('gt-mr2', 'gastown'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("insert missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseArchivedMRs(t *testing.T) {
+	output := []byte(`{"rows": [
+		{"id": "gt-mr1", "rig": "gastown", "worker": "gastown/polecats/nux", "close_reason": "merged",
+		 "priority": 1, "created_at": "2026-03-01 09:00:00", "closed_at": "2026-03-01 10:30:00.123456",
+		 "archived_at": "2026-03-08 00:00:00", "description": "branch: polecat/nux"},
+		{"id": "gt-mr2", "rig": "gastown", "created_at": null, "closed_at": "2026-03-02 00:00:00"}
+	]}`)
+	mrs, err := parseArchivedMRs(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mrs) != 2 {
+		t.Fatalf("got %d rows, want 2", len(mrs))
+	}
+	mr := mrs[0]
+	if mr.ID != "gt-mr1" || mr.Worker != "gastown/polecats/nux" || mr.Priority != 1 || mr.Description != "branch: polecat/nux" {
+		t.Errorf("row = %+v", mr)
+	}
+	if want := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC); !mr.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %s, want %s", mr.CreatedAt, want)
+	}
+	if mr.ClosedAt.Hour() != 10 || mr.ClosedAt.Minute() != 30 {
+		t.Errorf("ClosedAt = %s", mr.ClosedAt)
+	}
+	if !mrs[1].CreatedAt.IsZero() {
+		t.Errorf("NULL created_at = %s, want zero", mrs[1].CreatedAt)
+	}
+
+	if mrs, err := parseArchivedMRs(nil); err != nil || mrs != nil {
+		t.Errorf("empty output = %v, %v", mrs, err)
+	}
+}
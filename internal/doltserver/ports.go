@@ -0,0 +1,90 @@
+package doltserver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// portSearchRange is how many ports above DefaultPort are tried when the
+// town's port is taken by another town's server.
+const portSearchRange = 100
+
+// portFree reports whether nothing is listening on the local port. A
+// variable so tests can simulate other towns' servers.
+var portFree = func(port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	_ = ln.Close()
+	return true
+}
+
+// AllocatePort returns the port the town's local server should listen on
+// and records it in the town's .beads/metadata.json (and, when it changes,
+// every rig's), where bd and gt read it. The recorded port is kept while
+// it is free; when another town's server holds it, the first free port
+// from DefaultPort up is taken instead, so several towns can share a host.
+//
+// Call with the town's own server stopped: its port reads as taken.
+// A remote server or a GT_DOLT_PORT override is used as is.
+func AllocatePort(townRoot string) (int, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() || os.Getenv("GT_DOLT_PORT") != "" {
+		return config.Port, nil
+	}
+
+	recorded := LoadServerEndpoint(townRoot).Port
+	port := config.Port
+	if !portFree(port) {
+		port = 0
+		for p := DefaultPort; p < DefaultPort+portSearchRange; p++ {
+			if p != config.Port && portFree(p) {
+				port = p
+				break
+			}
+		}
+		if port == 0 {
+			return 0, fmt.Errorf("port %d is in use and no free port found in %d-%d",
+				config.Port, DefaultPort, DefaultPort+portSearchRange-1)
+		}
+	}
+	if port == recorded {
+		return port, nil
+	}
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Port: port}); err != nil {
+		return 0, fmt.Errorf("recording Dolt port: %w", err)
+	}
+	if recorded != 0 || port != DefaultPort {
+		// Rigs recorded the old port (or none, meaning the default).
+		if _, errs := EnsureAllMetadata(townRoot); len(errs) > 0 {
+			return 0, fmt.Errorf("recording Dolt port in rig metadata: %w", errs[0])
+		}
+	}
+	return port, nil
+}
+
+// servesDataDir reports whether the dolt sql-server command line serves
+// dataDir. A server started without --data-dir serves its working
+// directory, which cannot be checked here, so it is assumed to match.
+func servesDataDir(cmdline, dataDir string) bool {
+	fields := strings.Fields(cmdline)
+	for i, f := range fields {
+		var dir string
+		switch {
+		case f == "--data-dir" && i+1 < len(fields):
+			dir = fields[i+1]
+		case strings.HasPrefix(f, "--data-dir="):
+			dir = strings.TrimPrefix(f, "--data-dir=")
+		default:
+			continue
+		}
+		return filepath.Clean(dir) == filepath.Clean(dataDir)
+	}
+	return true
+}
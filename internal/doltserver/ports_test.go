@@ -0,0 +1,78 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllocatePort(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	rigDB := filepath.Join(townRoot, ".dolt-data", "myrig", ".dolt")
+	if err := os.MkdirAll(rigDB, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	taken := map[int]bool{}
+	orig := portFree
+	portFree = func(port int) bool { return !taken[port] }
+	t.Cleanup(func() { portFree = orig })
+
+	// A free default port is taken and recorded for the town.
+	port, err := AllocatePort(townRoot)
+	if err != nil || port != DefaultPort {
+		t.Fatalf("AllocatePort = %d, %v; want %d", port, err, DefaultPort)
+	}
+	if ep := LoadServerEndpoint(townRoot); ep.Port != DefaultPort || ep.Host != "" {
+		t.Errorf("recorded endpoint = %+v, want local port %d", ep, DefaultPort)
+	}
+
+	// Another town's server holds it: move to the next free port, and
+	// point the rigs at it.
+	taken[DefaultPort] = true
+	taken[DefaultPort+1] = true
+	port, err = AllocatePort(townRoot)
+	if err != nil || port != DefaultPort+2 {
+		t.Fatalf("AllocatePort = %d, %v; want %d", port, err, DefaultPort+2)
+	}
+	if got := DefaultConfig(townRoot).Port; got != port {
+		t.Errorf("DefaultConfig port = %d, want %d", got, port)
+	}
+	m := readMetadata(t, filepath.Join(FindRigBeadsDir(townRoot, "myrig"), "metadata.json"))
+	if m[metaServerPort] != float64(port) {
+		t.Errorf("rig metadata port = %v, want %d", m[metaServerPort], port)
+	}
+	if _, ok := m[metaServerHost]; ok {
+		t.Errorf("local town wrote a host to rig metadata: %v", m)
+	}
+
+	// The recorded port is kept while it is free.
+	taken[DefaultPort] = false
+	if port, err := AllocatePort(townRoot); err != nil || port != DefaultPort+2 {
+		t.Errorf("AllocatePort = %d, %v; want to keep %d", port, err, DefaultPort+2)
+	}
+
+	// An explicit override is used as is.
+	t.Setenv("GT_DOLT_PORT", "4406")
+	if port, err := AllocatePort(townRoot); err != nil || port != 4406 {
+		t.Errorf("AllocatePort with GT_DOLT_PORT = %d, %v; want 4406", port, err)
+	}
+}
+
+func TestServesDataDir(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    bool
+	}{
+		{"dolt sql-server --port 3307 --data-dir /home/a/gt/.dolt-data", true},
+		{"dolt sql-server --port 3307 --data-dir=/home/a/gt/.dolt-data/", true},
+		{"dolt sql-server --port 3307 --data-dir /home/b/gt/.dolt-data", false},
+		{"dolt sql-server --port 3307", true},
+	}
+	for _, tt := range tests {
+		if got := servesDataDir(tt.cmdline, "/home/a/gt/.dolt-data"); got != tt.want {
+			t.Errorf("servesDataDir(%q) = %v, want %v", tt.cmdline, got, tt.want)
+		}
+	}
+}
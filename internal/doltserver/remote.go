@@ -0,0 +1,144 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Metadata keys bd reads to locate the Dolt sql-server. The town-level
+// .beads/metadata.json holds the town's server; EnsureMetadata copies the
+// same keys into each rig so every bd client connects to one server.
+//
+// Passwords are never written to metadata.json (it is often committed).
+// Set GT_DOLT_PASSWORD; beads clients forward it as BEADS_DOLT_PASSWORD.
+const (
+	metaServerHost = "dolt_server_host"
+	metaServerPort = "dolt_server_port"
+	metaServerUser = "dolt_server_user"
+)
+
+// ServerEndpoint is the Dolt server location persisted in the town metadata.
+type ServerEndpoint struct {
+	Host string `json:"dolt_server_host,omitempty"`
+	Port int    `json:"dolt_server_port,omitempty"`
+	User string `json:"dolt_server_user,omitempty"`
+	TLS  bool   `json:"dolt_server_tls,omitempty"`
+
+	// PasswordEnv names the environment variable holding the password
+	// (rig endpoints only; the town server uses GT_DOLT_PASSWORD).
+	PasswordEnv string `json:"dolt_server_password_env,omitempty"`
+}
+
+// townMetadataPath returns the town-level (hq) beads metadata path.
+func townMetadataPath(townRoot string) string {
+	return filepath.Join(townRoot, ".beads", "metadata.json")
+}
+
+// LoadServerEndpoint reads the Dolt server endpoint from the town metadata.
+// Returns a zero endpoint when the file or keys are absent.
+func LoadServerEndpoint(townRoot string) ServerEndpoint {
+	var ep ServerEndpoint
+	data, err := os.ReadFile(townMetadataPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ep
+	}
+	_ = json.Unmarshal(data, &ep) // best effort: malformed metadata means local defaults
+	return ep
+}
+
+// SetServerEndpoint records the Dolt server endpoint in the town metadata.
+// An empty host clears the endpoint, returning the town to a local server.
+// Run EnsureAllMetadata afterwards to propagate the change to rigs.
+func SetServerEndpoint(townRoot string, ep ServerEndpoint) error {
+	metadataPath := townMetadataPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(metadataPath), 0755); err != nil {
+		return fmt.Errorf("creating beads directory: %w", err)
+	}
+
+	mu := getMetadataMu(metadataPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	existing := make(map[string]interface{})
+	if data, err := os.ReadFile(metadataPath); err == nil { //nolint:gosec // G304: path is constructed internally
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("parsing %s: %w", metadataPath, err)
+		}
+	}
+	applyServerEndpoint(existing, ep)
+
+	data, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+	if err := util.AtomicWriteFile(metadataPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing metadata.json: %w", err)
+	}
+	return nil
+}
+
+// applyServerEndpoint sets or clears the server keys in a metadata map.
+// Local endpoints are cleared so bd falls back to its 127.0.0.1 default,
+// keeping only the port the town's local server was allocated (see
+// AllocatePort).
+func applyServerEndpoint(metadata map[string]interface{}, ep ServerEndpoint) {
+	cfg := &Config{Host: ep.Host}
+	if !cfg.IsRemote() {
+		delete(metadata, metaServerHost)
+		delete(metadata, metaServerUser)
+		delete(metadata, metaServerTLS)
+		delete(metadata, metaServerPasswordEnv)
+		if ep.Port != 0 {
+			metadata[metaServerPort] = ep.Port
+		} else {
+			delete(metadata, metaServerPort)
+		}
+		return
+	}
+	metadata[metaServerHost] = ep.Host
+	if ep.Port != 0 {
+		metadata[metaServerPort] = ep.Port
+	} else {
+		delete(metadata, metaServerPort)
+	}
+	if ep.User != "" {
+		metadata[metaServerUser] = ep.User
+	} else {
+		delete(metadata, metaServerUser)
+	}
+	if ep.TLS {
+		metadata[metaServerTLS] = true
+	} else {
+		delete(metadata, metaServerTLS)
+	}
+	if ep.PasswordEnv != "" {
+		metadata[metaServerPasswordEnv] = ep.PasswordEnv
+	} else {
+		delete(metadata, metaServerPasswordEnv)
+	}
+}
+
+// ParseHostPort splits "host[:port]" into an endpoint, defaulting the port.
+func ParseHostPort(addr string) (ServerEndpoint, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return ServerEndpoint{}, fmt.Errorf("empty server address")
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		// No port given.
+		return ServerEndpoint{Host: strings.Trim(addr, "[]"), Port: DefaultPort}, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return ServerEndpoint{}, fmt.Errorf("invalid port in %q", addr)
+	}
+	return ServerEndpoint{Host: host, Port: port}, nil
+}
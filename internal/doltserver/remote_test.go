@@ -0,0 +1,139 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func clearDoltEnv(t *testing.T) {
+	t.Helper()
+	for _, k := range []string{"GT_DOLT_HOST", "GT_DOLT_PORT", "GT_DOLT_USER", "GT_DOLT_PASSWORD"} {
+		t.Setenv(k, "")
+	}
+}
+
+func readMetadata(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("parsing metadata: %v", err)
+	}
+	return m
+}
+
+func TestDefaultConfig_ServerEndpointFromMetadata(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: "dolt.lan", Port: 4406, User: "gastown"}); err != nil {
+		t.Fatalf("SetServerEndpoint: %v", err)
+	}
+
+	cfg := DefaultConfig(townRoot)
+	if !cfg.IsRemote() || cfg.Host != "dolt.lan" || cfg.Port != 4406 || cfg.User != "gastown" {
+		t.Errorf("config = %s:%d user %s, want dolt.lan:4406 user gastown", cfg.Host, cfg.Port, cfg.User)
+	}
+
+	// Environment still wins for a single shell.
+	t.Setenv("GT_DOLT_PORT", "5506")
+	if got := DefaultConfig(townRoot).Port; got != 5506 {
+		t.Errorf("GT_DOLT_PORT override: port = %d, want 5506", got)
+	}
+}
+
+func TestSetServerEndpoint_PreservesFieldsAndClears(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := EnsureMetadata(townRoot, "hq"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: "dolt.lan", Port: DefaultPort}); err != nil {
+		t.Fatal(err)
+	}
+	m := readMetadata(t, townMetadataPath(townRoot))
+	if m["dolt_mode"] != "server" || m[metaServerHost] != "dolt.lan" {
+		t.Errorf("metadata = %v", m)
+	}
+	if _, ok := m[metaServerUser]; ok {
+		t.Error("empty user should not be written")
+	}
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{}); err != nil {
+		t.Fatal(err)
+	}
+	m = readMetadata(t, townMetadataPath(townRoot))
+	if _, ok := m[metaServerHost]; ok {
+		t.Errorf("host not cleared: %v", m)
+	}
+	if DefaultConfig(townRoot).IsRemote() {
+		t.Error("config should be local after clearing")
+	}
+}
+
+func TestEnsureMetadata_PropagatesRemoteEndpoint(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: "10.0.0.5", Port: 3307, User: "gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatal(err)
+	}
+	m := readMetadata(t, filepath.Join(beadsDir, "metadata.json"))
+	if m[metaServerHost] != "10.0.0.5" || m[metaServerPort] != float64(3307) || m[metaServerUser] != "gastown" {
+		t.Errorf("rig metadata = %v", m)
+	}
+	if _, ok := m["dolt_server_password"]; ok {
+		t.Error("password must never be written to metadata.json")
+	}
+
+	// Returning to local clears the stale endpoint from rigs.
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatal(err)
+	}
+	m = readMetadata(t, filepath.Join(beadsDir, "metadata.json"))
+	if _, ok := m[metaServerHost]; ok {
+		t.Errorf("stale host left in rig metadata: %v", m)
+	}
+}
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantPort int
+		wantErr  bool
+	}{
+		{"dolt.lan", "dolt.lan", DefaultPort, false},
+		{"dolt.lan:4406", "dolt.lan", 4406, false},
+		{"[fd00::5]:3307", "fd00::5", 3307, false},
+		{"dolt.lan:abc", "", 0, true},
+		{"dolt.lan:70000", "", 0, true},
+		{"", "", 0, true},
+	}
+	for _, tt := range tests {
+		ep, err := ParseHostPort(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHostPort(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if ep.Host != tt.wantHost || ep.Port != tt.wantPort {
+			t.Errorf("ParseHostPort(%q) = %s:%d, want %s:%d", tt.in, ep.Host, ep.Port, tt.wantHost, tt.wantPort)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Towns can sync their databases with a Dolt remote: DoltHub, or a
+// self-hosted remotesrv. settings/dolt-remotes.json names the remote, as a
+// default for every database and per database, with the user to
+// authenticate as. As with server endpoints, passwords are never stored:
+// PasswordEnv names the environment variable holding the password, which
+// dolt reads from DOLT_REMOTE_PASSWORD. DoltHub remotes authenticate with
+// the machine's dolt creds instead and need neither.
+
+// RemotesFile is the Dolt remote configuration, relative to the town root.
+const RemotesFile = "settings/dolt-remotes.json"
+
+// RemoteConfig is the remote one database syncs with.
+type RemoteConfig struct {
+	// URL is the remote URL. "{db}" is replaced by the database name; a URL
+	// without it is a base that the database name is appended to.
+	URL         string `json:"url,omitempty"`
+	User        string `json:"user,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+}
+
+// Remotes is the town's Dolt remote configuration.
+type Remotes struct {
+	// Default applies to databases without their own entry, and fills in
+	// what their entries leave empty.
+	Default *RemoteConfig `json:"default,omitempty"`
+
+	// Databases overrides the default per database name.
+	Databases map[string]RemoteConfig `json:"databases,omitempty"`
+}
+
+// RemotesPath returns the Dolt remote configuration path for a town.
+func RemotesPath(townRoot string) string {
+	return filepath.Join(townRoot, filepath.FromSlash(RemotesFile))
+}
+
+// LoadRemotes reads the Dolt remote configuration. A missing file
+// configures no remotes.
+func LoadRemotes(townRoot string) (*Remotes, error) {
+	remotes := &Remotes{}
+	data, err := os.ReadFile(RemotesPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return remotes, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", RemotesFile, err)
+	}
+	if err := json.Unmarshal(data, remotes); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", RemotesFile, err)
+	}
+	if err := remotes.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", RemotesFile, err)
+	}
+	return remotes, nil
+}
+
+// Validate checks that every database resolves to a remote URL and that
+// password variables are usable names.
+func (r *Remotes) Validate() error {
+	if r.Default != nil {
+		if err := r.Default.validate(); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for db := range r.Databases {
+		if db == "" {
+			return fmt.Errorf("empty database name")
+		}
+		cfg, _ := r.For(db)
+		if cfg.URL == "" {
+			return fmt.Errorf("database %q: no url (set one, or a default)", db)
+		}
+		if err := cfg.validate(); err != nil {
+			return fmt.Errorf("database %q: %w", db, err)
+		}
+	}
+	return nil
+}
+
+func (c *RemoteConfig) validate() error {
+	if c.URL != "" && !strings.Contains(c.URL, "://") {
+		return fmt.Errorf("invalid url %q: expected scheme://host/path", c.URL)
+	}
+	if strings.ContainsAny(c.PasswordEnv, "= ") {
+		return fmt.Errorf("invalid password_env %q", c.PasswordEnv)
+	}
+	if c.PasswordEnv != "" && c.User == "" {
+		return fmt.Errorf("password_env needs a user")
+	}
+	return nil
+}
+
+// For returns the remote db syncs with, with its URL resolved. ok is false
+// when no remote is configured for db.
+func (r *Remotes) For(db string) (cfg RemoteConfig, ok bool) {
+	if r.Default != nil {
+		cfg = *r.Default
+	}
+	if own, found := r.Databases[db]; found {
+		if own.URL != "" {
+			cfg.URL = own.URL
+		}
+		if own.User != "" {
+			cfg.User = own.User
+			cfg.PasswordEnv = own.PasswordEnv
+		} else if own.PasswordEnv != "" {
+			cfg.PasswordEnv = own.PasswordEnv
+		}
+	}
+	if cfg.URL == "" {
+		return RemoteConfig{}, false
+	}
+	cfg.URL = remoteURLFor(cfg.URL, db)
+	return cfg, true
+}
+
+func remoteURLFor(url, db string) string {
+	if strings.Contains(url, "{db}") {
+		return strings.ReplaceAll(url, "{db}", db)
+	}
+	return strings.TrimSuffix(url, "/") + "/" + db
+}
+
+// credentials returns the dolt arguments and environment that authenticate
+// as c's user. It fails, naming the variable, if the password variable is
+// unset.
+func (c *RemoteConfig) credentials() (args, env []string, err error) {
+	if c == nil || c.User == "" {
+		return nil, nil, nil
+	}
+	args = []string{"--user", c.User}
+	if c.PasswordEnv != "" {
+		password := os.Getenv(c.PasswordEnv)
+		if password == "" {
+			return nil, nil, fmt.Errorf("%s is not set (password for remote user %s)", c.PasswordEnv, c.User)
+		}
+		env = []string{"DOLT_REMOTE_PASSWORD=" + password}
+	}
+	return args, env, nil
+}
@@ -0,0 +1,114 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadRemotes(t *testing.T) {
+	townRoot := t.TempDir()
+	remotes, err := LoadRemotes(townRoot)
+	if err != nil {
+		t.Fatalf("LoadRemotes (missing file): %v", err)
+	}
+	if _, ok := remotes.For("gastown"); ok {
+		t.Error("missing file should configure no remotes")
+	}
+
+	path := RemotesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{
+  "default": {"url": "https://doltremoteapi.dolthub.com/acme/"},
+  "databases": {
+    "gastown": {"url": "http://dolt.lan:50051/town-{db}", "user": "gt", "password_env": "GT_REMOTE_PW"},
+    "hq": {"user": "mayor"}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if remotes, err = LoadRemotes(townRoot); err != nil {
+		t.Fatalf("LoadRemotes: %v", err)
+	}
+
+	tests := []struct {
+		db   string
+		want RemoteConfig
+	}{
+		{"beads_x", RemoteConfig{URL: "https://doltremoteapi.dolthub.com/acme/beads_x"}},
+		{"gastown", RemoteConfig{URL: "http://dolt.lan:50051/town-gastown", User: "gt", PasswordEnv: "GT_REMOTE_PW"}},
+		{"hq", RemoteConfig{URL: "https://doltremoteapi.dolthub.com/acme/hq", User: "mayor"}},
+	}
+	for _, tt := range tests {
+		got, ok := remotes.For(tt.db)
+		if !ok || got != tt.want {
+			t.Errorf("For(%q) = %+v, %v; want %+v", tt.db, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRemotes_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		remotes Remotes
+		wantErr bool
+	}{
+		{"default only", Remotes{Default: &RemoteConfig{URL: "https://doltremoteapi.dolthub.com/acme"}}, false},
+		{"no url", Remotes{Databases: map[string]RemoteConfig{"gastown": {User: "gt"}}}, true},
+		{"bad url", Remotes{Default: &RemoteConfig{URL: "dolt.lan/db"}}, true},
+		{"password without user", Remotes{Default: &RemoteConfig{URL: "http://dolt.lan", PasswordEnv: "PW"}}, true},
+		{"bad password env", Remotes{Default: &RemoteConfig{URL: "http://dolt.lan", User: "gt", PasswordEnv: "A=B"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.remotes.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteConfig_Credentials(t *testing.T) {
+	cfg := &RemoteConfig{URL: "http://dolt.lan", User: "gt", PasswordEnv: "GT_TEST_REMOTE_PW"}
+
+	t.Setenv("GT_TEST_REMOTE_PW", "")
+	_, _, err := cfg.credentials()
+	if err == nil || !strings.Contains(err.Error(), "GT_TEST_REMOTE_PW") {
+		t.Fatalf("credentials() with unset password = %v, want error naming the variable", err)
+	}
+
+	t.Setenv("GT_TEST_REMOTE_PW", "s3cret")
+	args, env, err := cfg.credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args, []string{"--user", "gt"}) || !slices.Equal(env, []string{"DOLT_REMOTE_PASSWORD=s3cret"}) {
+		t.Errorf("credentials() = %v, %v", args, env)
+	}
+	if slices.Contains(args, "s3cret") {
+		t.Error("password leaked into command arguments")
+	}
+
+	var none *RemoteConfig
+	if args, env, err := none.credentials(); args != nil || env != nil || err != nil {
+		t.Errorf("nil config credentials() = %v, %v, %v", args, env, err)
+	}
+}
+
+func TestHasRemoteBranch(t *testing.T) {
+	out := "  remotes/origin/feature\n  remotes/origin/main\n"
+	if !hasRemoteBranch(out, "origin/main") {
+		t.Error("origin/main not found")
+	}
+	if hasRemoteBranch("  remotes/origin/mainline\n", "origin/main") {
+		t.Error("origin/mainline matched origin/main")
+	}
+	if n := countLines("abc123 one\ndef456 two\n\n"); n != 2 {
+		t.Errorf("countLines = %d, want 2", n)
+	}
+}
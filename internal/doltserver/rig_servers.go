@@ -0,0 +1,169 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Rigs that live on other machines can keep their beads database on a Dolt
+// server there. settings/dolt-servers.json maps those rigs to their server;
+// EnsureMetadata writes the mapped endpoint into the rig's metadata.json, so
+// bd connects to it, and ConfigForRig points gt's own SQL at it. Unmapped
+// rigs use the town's server (see SetServerEndpoint).
+//
+// As with the town endpoint, passwords are never stored: PasswordEnv names
+// the environment variable holding the password.
+
+// Metadata keys for the TLS and password parts of a rig endpoint.
+const (
+	metaServerTLS         = "dolt_server_tls"
+	metaServerPasswordEnv = "dolt_server_password_env"
+)
+
+// RigServersFile is the per-rig server map, relative to the town root.
+const RigServersFile = "settings/dolt-servers.json"
+
+// RigServers maps rig names to the Dolt server holding their database.
+type RigServers struct {
+	Rigs map[string]ServerEndpoint `json:"rigs"`
+}
+
+// RigServersPath returns the per-rig server map path for a town.
+func RigServersPath(townRoot string) string {
+	return filepath.Join(townRoot, filepath.FromSlash(RigServersFile))
+}
+
+// LoadRigServers reads the per-rig server map. A missing file is an empty map.
+func LoadRigServers(townRoot string) (*RigServers, error) {
+	servers := &RigServers{Rigs: map[string]ServerEndpoint{}}
+	data, err := os.ReadFile(RigServersPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return servers, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", RigServersFile, err)
+	}
+	if err := json.Unmarshal(data, servers); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", RigServersFile, err)
+	}
+	if servers.Rigs == nil {
+		servers.Rigs = map[string]ServerEndpoint{}
+	}
+	if err := servers.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", RigServersFile, err)
+	}
+	return servers, nil
+}
+
+// SaveRigServers writes the per-rig server map.
+func SaveRigServers(townRoot string, servers *RigServers) error {
+	if err := servers.Validate(); err != nil {
+		return err
+	}
+	path := RigServersPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(servers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling rig servers: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", RigServersFile, err)
+	}
+	return nil
+}
+
+// Validate checks that every mapping names a remote host and a usable port.
+func (s *RigServers) Validate() error {
+	for rig, ep := range s.Rigs {
+		if rig == "" {
+			return fmt.Errorf("empty rig name")
+		}
+		if !(&Config{Host: ep.Host}).IsRemote() {
+			return fmt.Errorf("rig %q: %q is not a remote host (remove the mapping to use the town server)", rig, ep.Host)
+		}
+		if ep.Port < 0 || ep.Port > 65535 {
+			return fmt.Errorf("rig %q: invalid port %d", rig, ep.Port)
+		}
+		if strings.ContainsAny(ep.PasswordEnv, "= ") {
+			return fmt.Errorf("rig %q: invalid password_env %q", rig, ep.PasswordEnv)
+		}
+	}
+	return nil
+}
+
+// Names returns the mapped rig names, sorted.
+func (s *RigServers) Names() []string {
+	names := make([]string, 0, len(s.Rigs))
+	for name := range s.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RigEndpoint returns the server endpoint for rigName: its mapping in
+// settings/dolt-servers.json, or the town endpoint when it has none.
+func RigEndpoint(townRoot, rigName string) ServerEndpoint {
+	if servers, err := LoadRigServers(townRoot); err == nil {
+		if ep, ok := servers.Rigs[rigName]; ok {
+			return ep
+		}
+	}
+	return LoadServerEndpoint(townRoot)
+}
+
+// ConfigForRig returns the server configuration for rigName's database.
+// Rigs mapped in settings/dolt-servers.json get their own server, with the
+// password read from the mapping's PasswordEnv; other rigs get DefaultConfig.
+func ConfigForRig(townRoot, rigName string) *Config {
+	config := DefaultConfig(townRoot)
+	servers, err := LoadRigServers(townRoot)
+	if err != nil {
+		return config
+	}
+	ep, ok := servers.Rigs[rigName]
+	if !ok {
+		return config
+	}
+
+	config.Host = ep.Host
+	config.Port = DefaultPort
+	if ep.Port != 0 {
+		config.Port = ep.Port
+	}
+	config.User = DefaultUser
+	if ep.User != "" {
+		config.User = ep.User
+	}
+	config.TLS = ep.TLS
+	// Never send the town's GT_DOLT_PASSWORD to another server.
+	config.Password = ""
+	if ep.PasswordEnv != "" {
+		config.Password = os.Getenv(ep.PasswordEnv)
+	}
+	return config
+}
+
+// CheckRigServerReachable is CheckServerReachable for the server holding
+// rigName's database.
+func CheckRigServerReachable(townRoot, rigName string) error {
+	config := ConfigForRig(townRoot, rigName)
+	addr := config.HostPort()
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		return fmt.Errorf("Dolt server for rig %s not reachable at %s: %w\n\nCheck the server, or change it with: gt dolt connect --rig %s <host[:port]>", rigName, addr, err, rigName)
+	}
+	_ = conn.Close()
+	return nil
+}
@@ -0,0 +1,116 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadRigServers_MissingFile(t *testing.T) {
+	servers, err := LoadRigServers(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadRigServers: %v", err)
+	}
+	if len(servers.Rigs) != 0 {
+		t.Errorf("expected no mappings, got %v", servers.Rigs)
+	}
+}
+
+func TestRigServers_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ep      ServerEndpoint
+		wantErr bool
+	}{
+		{"remote", ServerEndpoint{Host: "build-02.lan", Port: 3307}, false},
+		{"local host", ServerEndpoint{Host: "127.0.0.1"}, true},
+		{"empty host", ServerEndpoint{}, true},
+		{"bad port", ServerEndpoint{Host: "build-02.lan", Port: 70000}, true},
+		{"bad password env", ServerEndpoint{Host: "build-02.lan", PasswordEnv: "A=B"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &RigServers{Rigs: map[string]ServerEndpoint{"beta": tt.ep}}
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigForRig(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_PASSWORD", "town-secret")
+	t.Setenv("BETA_DOLT_PW", "beta-secret")
+
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: "dolt.lan", Port: 4406, User: "gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	servers := &RigServers{Rigs: map[string]ServerEndpoint{
+		"beta":  {Host: "build-02.lan", Port: 3307, TLS: true, PasswordEnv: "BETA_DOLT_PW"},
+		"gamma": {Host: "build-03.lan"},
+	}}
+	if err := SaveRigServers(townRoot, servers); err != nil {
+		t.Fatal(err)
+	}
+
+	beta := ConfigForRig(townRoot, "beta")
+	if beta.HostPort() != "build-02.lan:3307" || beta.User != DefaultUser || !beta.TLS || beta.Password != "beta-secret" {
+		t.Errorf("beta config = %s user %s tls %v password %q", beta.HostPort(), beta.User, beta.TLS, beta.Password)
+	}
+	if slices.Contains(beta.SQLArgs(), "--no-tls") {
+		t.Errorf("TLS rig should not pass --no-tls: %v", beta.SQLArgs())
+	}
+
+	// A mapping without a password variable must not get the town's password.
+	gamma := ConfigForRig(townRoot, "gamma")
+	if gamma.HostPort() != "build-03.lan:3307" || gamma.Password != "" {
+		t.Errorf("gamma config = %s password %q, want build-03.lan:3307 and no password", gamma.HostPort(), gamma.Password)
+	}
+
+	alpha := ConfigForRig(townRoot, "alpha")
+	if alpha.HostPort() != "dolt.lan:4406" || alpha.User != "gastown" || alpha.Password != "town-secret" {
+		t.Errorf("unmapped rig config = %s user %s, want the town server", alpha.HostPort(), alpha.User)
+	}
+}
+
+func TestEnsureMetadata_RigServer(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "beta", "mayor", "rig", ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	servers := &RigServers{Rigs: map[string]ServerEndpoint{
+		"beta": {Host: "build-02.lan", Port: 3307, TLS: true, PasswordEnv: "BETA_DOLT_PW"},
+	}}
+	if err := SaveRigServers(townRoot, servers); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureMetadata(townRoot, "beta"); err != nil {
+		t.Fatalf("EnsureMetadata: %v", err)
+	}
+	m := readMetadata(t, filepath.Join(townRoot, "beta", "mayor", "rig", ".beads", "metadata.json"))
+	if m[metaServerHost] != "build-02.lan" || m[metaServerPort] != float64(3307) {
+		t.Errorf("rig metadata endpoint = %v:%v, want build-02.lan:3307", m[metaServerHost], m[metaServerPort])
+	}
+	if m[metaServerTLS] != true || m[metaServerPasswordEnv] != "BETA_DOLT_PW" {
+		t.Errorf("rig metadata tls/password_env = %v/%v", m[metaServerTLS], m[metaServerPasswordEnv])
+	}
+
+	// Removing the mapping returns the rig to the (local) town server.
+	if err := SaveRigServers(townRoot, &RigServers{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "beta"); err != nil {
+		t.Fatal(err)
+	}
+	m = readMetadata(t, filepath.Join(townRoot, "beta", "mayor", "rig", ".beads", "metadata.json"))
+	for _, key := range []string{metaServerHost, metaServerPort, metaServerTLS, metaServerPasswordEnv} {
+		if _, ok := m[key]; ok {
+			t.Errorf("stale %s left after removing the mapping", key)
+		}
+	}
+}
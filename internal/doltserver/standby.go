@@ -0,0 +1,354 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// A warm standby is a second Dolt sql-server, on another machine, running as
+// a read replica of the town's server: the primary pushes every commit to a
+// Dolt remote (@@dolt_replicate_to_remote) and the standby pulls from it at
+// the start of each transaction (@@dolt_read_replica_remote). When the
+// primary wedges, Failover promotes the standby and repoints the town at it,
+// so rigs keep working while the primary is debugged.
+//
+// The standby is recorded in settings/dolt-standby.json. It authenticates
+// with the town's credentials (GT_DOLT_PASSWORD), since after a failover it
+// is the town's server.
+
+// StandbyFile is the standby record, relative to the town root.
+const StandbyFile = "settings/dolt-standby.json"
+
+// DefaultReplicationRemote is the Dolt remote replication goes through.
+const DefaultReplicationRemote = "origin"
+
+// StandbyState is the town's warm standby and the last failover.
+type StandbyState struct {
+	// Standby is the replica to fail over to; nil when none is configured
+	// (including right after a failover, which promotes it).
+	Standby *ServerEndpoint `json:"standby,omitempty"`
+
+	// Remote is the Dolt remote the primary pushes to and the standby
+	// pulls from.
+	Remote string `json:"remote,omitempty"`
+
+	// LastFailover records the most recent promotion.
+	LastFailover *FailoverRecord `json:"last_failover,omitempty"`
+}
+
+// FailoverRecord describes a completed failover.
+type FailoverRecord struct {
+	At   time.Time `json:"at"`
+	From string    `json:"from"` // previous primary, host:port
+	To   string    `json:"to"`   // promoted standby, host:port
+	By   string    `json:"by,omitempty"`
+}
+
+// StandbyPath returns the standby record path for a town.
+func StandbyPath(townRoot string) string {
+	return filepath.Join(townRoot, filepath.FromSlash(StandbyFile))
+}
+
+// LoadStandby reads the town's standby record. A missing file is an empty
+// record.
+func LoadStandby(townRoot string) (*StandbyState, error) {
+	state := &StandbyState{}
+	data, err := os.ReadFile(StandbyPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", StandbyFile, err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StandbyFile, err)
+	}
+	if err := state.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", StandbyFile, err)
+	}
+	return state, nil
+}
+
+// SaveStandby writes the town's standby record.
+func SaveStandby(townRoot string, state *StandbyState) error {
+	if err := state.Validate(); err != nil {
+		return err
+	}
+	path := StandbyPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling standby: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", StandbyFile, err)
+	}
+	return nil
+}
+
+// Validate checks that the standby is on another machine with a usable
+// port. A standby sharing the primary's host would fail with it.
+func (s *StandbyState) Validate() error {
+	if s.Standby == nil {
+		return nil
+	}
+	if !(&Config{Host: s.Standby.Host}).IsRemote() {
+		return fmt.Errorf("standby %q is not a remote host (a standby on this machine fails with the primary)", s.Standby.Host)
+	}
+	if s.Standby.Port < 0 || s.Standby.Port > 65535 {
+		return fmt.Errorf("standby: invalid port %d", s.Standby.Port)
+	}
+	if strings.ContainsAny(s.Remote, "'` ") {
+		return fmt.Errorf("invalid replication remote %q", s.Remote)
+	}
+	return nil
+}
+
+// ReplicationRemote returns the remote replication goes through.
+func (s *StandbyState) ReplicationRemote() string {
+	if s.Remote != "" {
+		return s.Remote
+	}
+	return DefaultReplicationRemote
+}
+
+// StandbyConfig returns the server configuration for the town's standby.
+func StandbyConfig(townRoot string, ep ServerEndpoint) *Config {
+	config := DefaultConfig(townRoot)
+	config.Host = ep.Host
+	config.Port = DefaultPort
+	if ep.Port != 0 {
+		config.Port = ep.Port
+	}
+	config.User = DefaultUser
+	if ep.User != "" {
+		config.User = ep.User
+	}
+	config.TLS = ep.TLS
+	return config
+}
+
+// ConfigureReplication makes the standby a read replica of the primary:
+// the primary pushes each commit to remote, and the standby pulls all
+// branches from it. Settings are persisted on both servers, so they
+// survive restarts. The primary skips replication errors rather than
+// failing writes when the remote is unreachable.
+func ConfigureReplication(townRoot string, state *StandbyState) error {
+	if state.Standby == nil {
+		return fmt.Errorf("no standby configured")
+	}
+	remote := state.ReplicationRemote()
+	primary := DefaultConfig(townRoot)
+	if err := runServerSQL(primary, fmt.Sprintf(
+		"SET @@PERSIST.dolt_replicate_to_remote = '%s'; SET @@PERSIST.dolt_skip_replication_errors = 1", remote)); err != nil {
+		return fmt.Errorf("configuring primary %s: %w", primary.HostPort(), err)
+	}
+	standby := StandbyConfig(townRoot, *state.Standby)
+	if err := runServerSQL(standby, fmt.Sprintf(
+		"SET @@PERSIST.dolt_read_replica_remote = '%s'; SET @@PERSIST.dolt_replicate_all_heads = 1", remote)); err != nil {
+		return fmt.Errorf("configuring standby %s: %w", standby.HostPort(), err)
+	}
+	return nil
+}
+
+// StandbyDatabase compares one database's main branch on both servers.
+type StandbyDatabase struct {
+	Name        string `json:"name"`
+	PrimaryHead string `json:"primary_head,omitempty"`
+	StandbyHead string `json:"standby_head,omitempty"`
+}
+
+// InSync reports whether the standby has the primary's head. It is false
+// when either head is unknown.
+func (d StandbyDatabase) InSync() bool {
+	return d.PrimaryHead != "" && d.PrimaryHead == d.StandbyHead
+}
+
+// StandbyStatus is the health of the standby relative to the primary.
+type StandbyStatus struct {
+	Primary          string            `json:"primary"`
+	Standby          string            `json:"standby"`
+	PrimaryReachable bool              `json:"primary_reachable"`
+	StandbyReachable bool              `json:"standby_reachable"`
+	PrimaryError     string            `json:"primary_error,omitempty"`
+	Databases        []StandbyDatabase `json:"databases"`
+}
+
+// Behind returns the databases whose standby head differs from the
+// primary's. Unknown when the primary cannot be queried.
+func (s *StandbyStatus) Behind() []string {
+	var behind []string
+	for _, db := range s.Databases {
+		if db.PrimaryHead != "" && !db.InSync() {
+			behind = append(behind, db.Name)
+		}
+	}
+	return behind
+}
+
+// CheckStandby compares the standby's databases with the primary's. The
+// primary being unreachable or wedged is not an error (that is when a
+// failover is wanted); the standby being unreachable is.
+func CheckStandby(townRoot string, state *StandbyState) (*StandbyStatus, error) {
+	if state.Standby == nil {
+		return nil, fmt.Errorf("no standby configured\n\nSet one with: gt dolt standby <host[:port]>")
+	}
+	primary := DefaultConfig(townRoot)
+	standby := StandbyConfig(townRoot, *state.Standby)
+	status := &StandbyStatus{Primary: primary.HostPort(), Standby: standby.HostPort()}
+
+	names := TownDatabaseNames(townRoot)
+
+	standbyHeads, err := mainHeads(standby, names)
+	if err != nil {
+		return status, fmt.Errorf("standby %s: %w", standby.HostPort(), err)
+	}
+	status.StandbyReachable = true
+
+	primaryHeads, err := mainHeads(primary, names)
+	if err != nil {
+		status.PrimaryError = err.Error()
+	} else {
+		status.PrimaryReachable = true
+	}
+	status.Databases = compareHeads(names, primaryHeads, standbyHeads)
+	return status, nil
+}
+
+// compareHeads pairs each database's heads on the two servers.
+func compareHeads(names []string, primary, standby map[string]string) []StandbyDatabase {
+	dbs := make([]StandbyDatabase, 0, len(names))
+	for _, name := range names {
+		dbs = append(dbs, StandbyDatabase{Name: name, PrimaryHead: primary[name], StandbyHead: standby[name]})
+	}
+	sort.Slice(dbs, func(i, j int) bool { return dbs[i].Name < dbs[j].Name })
+	return dbs
+}
+
+// mainHeads returns the main branch hash of each database the server has.
+func mainHeads(config *Config, names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		return map[string]string{}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", buildHeadsQuery(names)).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("no response in 10s (wedged?)")
+		}
+		return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return parseHeads(output)
+}
+
+// buildHeadsQuery selects the main branch hash of each database.
+func buildHeadsQuery(names []string) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("SELECT '%s' AS db, hash FROM `%s`.dolt_branches WHERE name = 'main'",
+			sqlEscape(name), strings.ReplaceAll(name, "`", "")))
+	}
+	return strings.Join(parts, " UNION ALL ")
+}
+
+func parseHeads(output []byte) (map[string]string, error) {
+	heads := map[string]string{}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return heads, nil
+	}
+	var result struct {
+		Rows []struct {
+			DB   string `json:"db"`
+			Hash string `json:"hash"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("parsing branch heads: %w", err)
+	}
+	for _, row := range result.Rows {
+		heads[row.DB] = row.Hash
+	}
+	return heads, nil
+}
+
+// promoteStandby stops the standby replicating so it accepts writes as the
+// town's server. A variable so tests can fail over without a server.
+var promoteStandby = func(config *Config) error {
+	conn, err := net.DialTimeout("tcp", config.HostPort(), 2*time.Second)
+	if err != nil {
+		return fmt.Errorf("not reachable: %w", err)
+	}
+	_ = conn.Close()
+	return runServerSQL(config, "SET @@PERSIST.dolt_read_replica_remote = ''; SET @@PERSIST.dolt_replicate_all_heads = 0")
+}
+
+// Failover promotes the standby and repoints the town at it: the town
+// endpoint becomes the standby and each rig's metadata.json is rewritten,
+// so bd and gt connect there. Rigs mapped to their own server (see
+// settings/dolt-servers.json) are unaffected. The standby record is
+// cleared and the failover recorded; configure a new standby once the old
+// primary is repaired.
+func Failover(townRoot, by string) (*FailoverRecord, error) {
+	state, err := LoadStandby(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if state.Standby == nil {
+		return nil, fmt.Errorf("no standby configured\n\nSet one with: gt dolt standby <host[:port]>")
+	}
+	ep := *state.Standby
+	standby := StandbyConfig(townRoot, ep)
+	if err := promoteStandby(standby); err != nil {
+		return nil, fmt.Errorf("promoting standby %s: %w", standby.HostPort(), err)
+	}
+
+	record := &FailoverRecord{
+		At:   time.Now().UTC(),
+		From: DefaultConfig(townRoot).HostPort(),
+		To:   standby.HostPort(),
+		By:   by,
+	}
+	// The town endpoint never carries a password variable; the town server
+	// uses GT_DOLT_PASSWORD.
+	if err := SetServerEndpoint(townRoot, ServerEndpoint{Host: ep.Host, Port: standby.Port, User: ep.User, TLS: ep.TLS}); err != nil {
+		return nil, fmt.Errorf("repointing town: %w", err)
+	}
+	for _, db := range TownDatabases(townRoot) {
+		if db.Rig == "hq" {
+			continue // the town metadata, already repointed
+		}
+		if err := EnsureMetadata(townRoot, db.Rig); err != nil {
+			return record, fmt.Errorf("standby promoted, but rewriting %s metadata failed: %w (run 'gt dolt fix-metadata')", db.Rig, err)
+		}
+	}
+
+	state.Standby = nil
+	state.LastFailover = record
+	if err := SaveStandby(townRoot, state); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// runServerSQL runs statements on a server without selecting a database.
+func runServerSQL(config *Config, query string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if output, err := buildDoltSQLCmd(ctx, config, "-q", query).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
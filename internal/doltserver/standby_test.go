@@ -0,0 +1,107 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStandbyRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadStandby(townRoot)
+	if err != nil || state.Standby != nil {
+		t.Fatalf("missing file = %+v, %v; want empty", state, err)
+	}
+
+	state.Standby = &ServerEndpoint{Host: "dolt-b.lan", Port: 3307, User: "gastown"}
+	if err := SaveStandby(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadStandby(townRoot)
+	if err != nil || got.Standby == nil || got.Standby.Host != "dolt-b.lan" || got.ReplicationRemote() != DefaultReplicationRemote {
+		t.Fatalf("LoadStandby = %+v, %v", got, err)
+	}
+
+	for _, bad := range []StandbyState{
+		{Standby: &ServerEndpoint{Host: "127.0.0.1"}},
+		{Standby: &ServerEndpoint{Host: "dolt-b.lan", Port: 70000}},
+		{Standby: &ServerEndpoint{Host: "dolt-b.lan"}, Remote: "x'; DROP"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) accepted", bad)
+		}
+	}
+}
+
+func TestStandbyHeads(t *testing.T) {
+	q := buildHeadsQuery([]string{"hq", "gastown"})
+	if !strings.Contains(q, "FROM `hq`.dolt_branches") || !strings.Contains(q, " UNION ALL ") {
+		t.Errorf("query = %s", q)
+	}
+
+	standby, err := parseHeads([]byte(`{"rows": [{"db": "hq", "hash": "aaa"}, {"db": "gastown", "hash": "bbb"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := &StandbyStatus{Databases: compareHeads([]string{"hq", "gastown", "beads"},
+		map[string]string{"hq": "aaa", "gastown": "ccc", "beads": "ddd"}, standby)}
+	if behind := status.Behind(); len(behind) != 2 || behind[0] != "beads" || behind[1] != "gastown" {
+		t.Errorf("Behind = %v, want [beads gastown]", behind)
+	}
+
+	// A wedged primary has no heads: nothing can be called behind.
+	status = &StandbyStatus{Databases: compareHeads([]string{"hq"}, nil, standby)}
+	if behind := status.Behind(); len(behind) != 0 {
+		t.Errorf("Behind with unknown primary = %v", behind)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	clearDoltEnv(t)
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(`{"rigs": {"gastown": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var promoted *Config
+	orig := promoteStandby
+	promoteStandby = func(c *Config) error { promoted = c; return nil }
+	t.Cleanup(func() { promoteStandby = orig })
+
+	if _, err := Failover(townRoot, "mayor"); err == nil {
+		t.Fatal("failover without a standby succeeded")
+	}
+
+	if err := SaveStandby(townRoot, &StandbyState{Standby: &ServerEndpoint{Host: "dolt-b.lan", Port: 4406, User: "gastown"}}); err != nil {
+		t.Fatal(err)
+	}
+	record, err := Failover(townRoot, "mayor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promoted == nil || promoted.HostPort() != "dolt-b.lan:4406" {
+		t.Errorf("promoted %+v, want dolt-b.lan:4406", promoted)
+	}
+	if record.From != "127.0.0.1:3307" || record.To != "dolt-b.lan:4406" || record.By != "mayor" {
+		t.Errorf("record = %+v", record)
+	}
+
+	// The town and its rigs now point at the standby.
+	if config := DefaultConfig(townRoot); config.HostPort() != "dolt-b.lan:4406" || config.User != "gastown" {
+		t.Errorf("town config = %s user %s", config.HostPort(), config.User)
+	}
+	m := readMetadata(t, filepath.Join(FindRigBeadsDir(townRoot, "gastown"), "metadata.json"))
+	if m[metaServerHost] != "dolt-b.lan" || m[metaServerPort] != float64(4406) {
+		t.Errorf("rig metadata = %v", m)
+	}
+
+	// The standby was promoted, so none is left; the failover is recorded.
+	state, err := LoadStandby(townRoot)
+	if err != nil || state.Standby != nil || state.LastFailover == nil || state.LastFailover.To != "dolt-b.lan:4406" {
+		t.Errorf("standby after failover = %+v, %v", state, err)
+	}
+}
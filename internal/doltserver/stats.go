@@ -0,0 +1,279 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Stats alert thresholds, surfaced as warnings by GetHealthMetrics.
+const (
+	// DatabaseSizeWarnBytes flags a database large enough to slow the server.
+	DatabaseSizeWarnBytes = 1 << 30 // 1 GB
+
+	// DatabaseGrowthWarnBytes flags a database that grew this much between
+	// two stats runs, usually a runaway writer.
+	DatabaseGrowthWarnBytes = 256 << 20 // 256 MB
+)
+
+// TableStats is the row count of one table.
+type TableStats struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// StatsGrowth is how much a database changed since the previous stats run.
+type StatsGrowth struct {
+	Since   time.Time `json:"since"`
+	Bytes   int64     `json:"bytes"`
+	Rows    int64     `json:"rows"`
+	Commits int64     `json:"commits"`
+}
+
+// DatabaseStats reports the size and contents of one database.
+type DatabaseStats struct {
+	Database    string       `json:"database"`
+	CollectedAt time.Time    `json:"collected_at"`
+	Bytes       int64        `json:"bytes"` // On disk; 0 for a remote server
+	Rows        int64        `json:"rows"`  // Across all tables
+	Commits     int64        `json:"commits"`
+	Tables      []TableStats `json:"tables,omitempty"`
+	Growth      *StatsGrowth `json:"growth,omitempty"` // nil on the first run
+	Error       string       `json:"error,omitempty"`
+}
+
+// StatsSnapshot is the cached result of the last stats run per database.
+type StatsSnapshot struct {
+	Databases []DatabaseStats `json:"databases"`
+}
+
+// statsQuery runs a query against the server; replaced in tests.
+var statsQuery = doltSQLQuery
+
+// StatsCacheFile returns the path to the cached stats snapshot.
+func StatsCacheFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-stats.json")
+}
+
+// LoadStatsSnapshot loads the cached stats. Returns an empty snapshot if
+// stats have never been collected.
+func LoadStatsSnapshot(townRoot string) (*StatsSnapshot, error) {
+	data, err := os.ReadFile(StatsCacheFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &StatsSnapshot{}, nil
+		}
+		return nil, err
+	}
+	var snap StatsSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StatsCacheFile(townRoot), err)
+	}
+	return &snap, nil
+}
+
+// SaveStatsSnapshot merges stats into the cache, replacing entries for the
+// same databases and keeping the rest. Databases whose collection failed
+// are not cached, so growth is measured from the last good run.
+func SaveStatsSnapshot(townRoot string, stats []DatabaseStats) error {
+	snap, err := LoadStatsSnapshot(townRoot)
+	if err != nil {
+		snap = &StatsSnapshot{} // Corrupt cache: start over
+	}
+	for _, s := range stats {
+		if s.Error != "" {
+			continue
+		}
+		if i := snap.index(s.Database); i >= 0 {
+			snap.Databases[i] = s
+		} else {
+			snap.Databases = append(snap.Databases, s)
+		}
+	}
+	sort.Slice(snap.Databases, func(i, j int) bool { return snap.Databases[i].Database < snap.Databases[j].Database })
+
+	path := StatsCacheFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, snap)
+}
+
+func (s *StatsSnapshot) index(database string) int {
+	for i := range s.Databases {
+		if s.Databases[i].Database == database {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the cached stats for a database, or nil.
+func (s *StatsSnapshot) Get(database string) *DatabaseStats {
+	if s == nil {
+		return nil
+	}
+	if i := s.index(database); i >= 0 {
+		return &s.Databases[i]
+	}
+	return nil
+}
+
+// CollectStats queries per-table row counts and commit counts for each
+// database (or only filter, if set) and measures on-disk size for a local
+// server. Growth is computed against prev, typically the cached snapshot.
+// A database that can't be queried is reported with Error set.
+func CollectStats(townRoot, filter string, prev *StatsSnapshot) ([]DatabaseStats, error) {
+	if err := CheckServerReachable(townRoot); err != nil {
+		return nil, err
+	}
+	config := DefaultConfig(townRoot)
+
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if filter != "" {
+		found := false
+		for _, db := range databases {
+			found = found || db == filter
+		}
+		if !found {
+			return nil, fmt.Errorf("database %q not found (have: %s)", filter, strings.Join(databases, ", "))
+		}
+		databases = []string{filter}
+	}
+
+	stats := make([]DatabaseStats, 0, len(databases))
+	for _, db := range databases {
+		s := collectDatabaseStats(townRoot, db)
+		if !config.IsRemote() {
+			s.Bytes = dirSize(RigDatabaseDir(townRoot, db))
+		}
+		s.Growth = statsGrowth(prev.Get(db), s)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// DatabaseSizes returns the on-disk size of each database of a local
+// server by database name. Unlike CollectStats it doesn't query the server,
+// so it is cheap enough for periodic sampling. A remote server has none.
+func DatabaseSizes(townRoot string) (map[string]int64, error) {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil, nil
+	}
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	sizes := make(map[string]int64, len(databases))
+	for _, db := range databases {
+		sizes[db] = dirSize(RigDatabaseDir(townRoot, db))
+	}
+	return sizes, nil
+}
+
+// collectDatabaseStats queries one database's tables and commit count.
+func collectDatabaseStats(townRoot, db string) DatabaseStats {
+	s := DatabaseStats{Database: db, CollectedAt: time.Now().UTC()}
+
+	out, err := statsQuery(townRoot, fmt.Sprintf(
+		"SELECT table_name AS name FROM information_schema.tables WHERE table_schema = %s AND table_type = 'BASE TABLE' ORDER BY table_name",
+		sqlString(db)))
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	var tables []string
+	for _, row := range parseSimpleCSV(out) {
+		if name := row["name"]; name != "" {
+			tables = append(tables, name)
+		}
+	}
+
+	if len(tables) > 0 {
+		counts := make([]string, len(tables))
+		for i, t := range tables {
+			counts[i] = fmt.Sprintf("SELECT %s AS name, COUNT(*) AS n FROM %s.%s", sqlString(t), sqlIdent(db), sqlIdent(t))
+		}
+		out, err = statsQuery(townRoot, strings.Join(counts, " UNION ALL "))
+		if err != nil {
+			s.Error = err.Error()
+			return s
+		}
+		for _, row := range parseSimpleCSV(out) {
+			n, _ := strconv.ParseInt(row["n"], 10, 64)
+			s.Tables = append(s.Tables, TableStats{Name: row["name"], Rows: n})
+			s.Rows += n
+		}
+	}
+
+	out, err = statsQuery(townRoot, fmt.Sprintf("SELECT COUNT(*) AS n FROM %s.dolt_log", sqlIdent(db)))
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	if rows := parseSimpleCSV(out); len(rows) > 0 {
+		s.Commits, _ = strconv.ParseInt(rows[0]["n"], 10, 64)
+	}
+	return s
+}
+
+// statsGrowth returns the change from old to cur, or nil if there is no
+// previous run or cur failed.
+func statsGrowth(old *DatabaseStats, cur DatabaseStats) *StatsGrowth {
+	if old == nil || cur.Error != "" {
+		return nil
+	}
+	return &StatsGrowth{
+		Since:   old.CollectedAt,
+		Bytes:   cur.Bytes - old.Bytes,
+		Rows:    cur.Rows - old.Rows,
+		Commits: cur.Commits - old.Commits,
+	}
+}
+
+// sqlIdent quotes a MySQL identifier.
+func sqlIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// sqlString quotes a MySQL string literal.
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''") + "'"
+}
+
+// StatsWarnings returns alerts for databases over DatabaseSizeWarnBytes or
+// that grew by more than DatabaseGrowthWarnBytes since the previous run.
+func StatsWarnings(stats []DatabaseStats) []string {
+	var warnings []string
+	for _, s := range stats {
+		if s.Bytes >= DatabaseSizeWarnBytes {
+			warnings = append(warnings, fmt.Sprintf("database %s is %s — consider 'gt dolt gc --db %s'",
+				s.Database, formatBytes(s.Bytes), s.Database))
+		}
+		if s.Growth != nil && s.Growth.Bytes >= DatabaseGrowthWarnBytes {
+			warnings = append(warnings, fmt.Sprintf("database %s grew %s since %s",
+				s.Database, formatBytes(s.Growth.Bytes), s.Growth.Since.Local().Format("2006-01-02 15:04")))
+		}
+	}
+	return warnings
+}
+
+// RigDatabaseName returns the Dolt database backing a rig's beads: the
+// dolt_database in its metadata.json, or the rig name.
+func RigDatabaseName(townRoot, rigName string) string {
+	if db := readExistingDoltDatabase(FindRigBeadsDir(townRoot, rigName)); db != "" {
+		return db
+	}
+	return rigName
+}
@@ -0,0 +1,115 @@
+package doltserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectDatabaseStats(t *testing.T) {
+	var queries []string
+	orig := statsQuery
+	statsQuery = func(_, query string) (string, error) {
+		queries = append(queries, query)
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			return "name\ndependencies\nissues\n", nil
+		case strings.Contains(query, "UNION ALL"):
+			return "name,n\ndependencies,12\nissues,30\n", nil
+		case strings.Contains(query, "dolt_log"):
+			return "n\n7\n", nil
+		}
+		return "", errors.New("unexpected query")
+	}
+	defer func() { statsQuery = orig }()
+
+	s := collectDatabaseStats("/town", "gastown")
+	if s.Error != "" {
+		t.Fatalf("unexpected error: %s", s.Error)
+	}
+	if s.Rows != 42 || s.Commits != 7 || len(s.Tables) != 2 || s.Tables[1] != (TableStats{Name: "issues", Rows: 30}) {
+		t.Errorf("got %+v", s)
+	}
+	if !strings.Contains(queries[1], "FROM `gastown`.`issues`") {
+		t.Errorf("count query not qualified: %s", queries[1])
+	}
+
+	statsQuery = func(_, _ string) (string, error) { return "", errors.New("server gone") }
+	if s := collectDatabaseStats("/town", "gastown"); s.Error == "" {
+		t.Error("expected Error to be set when the query fails")
+	}
+}
+
+func TestStatsGrowth(t *testing.T) {
+	then := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := &DatabaseStats{Database: "gastown", CollectedAt: then, Bytes: 100, Rows: 10, Commits: 3}
+	cur := DatabaseStats{Database: "gastown", Bytes: 250, Rows: 8, Commits: 5}
+
+	g := statsGrowth(old, cur)
+	if g == nil || g.Since != then || g.Bytes != 150 || g.Rows != -2 || g.Commits != 2 {
+		t.Errorf("statsGrowth = %+v", g)
+	}
+	if statsGrowth(nil, cur) != nil {
+		t.Error("first run should have no growth")
+	}
+	cur.Error = "boom"
+	if statsGrowth(old, cur) != nil {
+		t.Error("failed run should have no growth")
+	}
+}
+
+func TestSaveStatsSnapshot_Merges(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SaveStatsSnapshot(townRoot, []DatabaseStats{
+		{Database: "hq", Rows: 1},
+		{Database: "gastown", Rows: 2},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveStatsSnapshot(townRoot, []DatabaseStats{
+		{Database: "gastown", Rows: 5},
+		{Database: "beads", Error: "unreachable"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := LoadStatsSnapshot(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Databases) != 2 || snap.Databases[0].Database != "gastown" || snap.Databases[1].Database != "hq" {
+		t.Fatalf("snapshot = %+v, want gastown and hq", snap.Databases)
+	}
+	if snap.Get("gastown").Rows != 5 || snap.Get("hq").Rows != 1 || snap.Get("beads") != nil {
+		t.Errorf("snapshot = %+v", snap.Databases)
+	}
+
+	empty, err := LoadStatsSnapshot(t.TempDir())
+	if err != nil || len(empty.Databases) != 0 {
+		t.Errorf("missing cache: %+v, %v", empty, err)
+	}
+}
+
+func TestStatsWarnings(t *testing.T) {
+	warnings := StatsWarnings([]DatabaseStats{
+		{Database: "small", Bytes: 1 << 20, Growth: &StatsGrowth{Bytes: 1 << 20}},
+		{Database: "big", Bytes: 2 << 30},
+		{Database: "runaway", Bytes: 300 << 20, Growth: &StatsGrowth{Bytes: 300 << 20}},
+	})
+	if len(warnings) != 2 {
+		t.Fatalf("warnings = %q, want 2", warnings)
+	}
+	if !strings.Contains(warnings[0], "big is 2.0 GB") || !strings.Contains(warnings[1], "runaway grew 300.0 MB") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+func TestSQLQuoting(t *testing.T) {
+	if got := sqlIdent("we`ird"); got != "`we``ird`" {
+		t.Errorf("sqlIdent = %s", got)
+	}
+	if got := sqlString(`it's\`); got != `'it''s\\'` {
+		t.Errorf("sqlString = %s", got)
+	}
+}
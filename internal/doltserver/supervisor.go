@@ -0,0 +1,64 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// The supervisor itself ('gt dolt supervise') is the daemon's
+// DoltServerManager; see daemon.RunDoltSupervisor. These helpers locate and
+// signal it without importing the daemon.
+
+// SupervisorPidFile returns the path to the supervisor's PID file.
+func SupervisorPidFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-supervisor.pid")
+}
+
+// RestartLogFile returns the path to the supervisor's restart log.
+func RestartLogFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-restarts.log")
+}
+
+// SupervisorRunning reports whether a supervisor is running for the town.
+func SupervisorRunning(townRoot string) (bool, int) {
+	data, err := os.ReadFile(SupervisorPidFile(townRoot))
+	if err != nil {
+		return false, 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return false, 0
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil || process.Signal(syscall.Signal(0)) != nil {
+		return false, 0
+	}
+	return true, pid
+}
+
+// StopSupervisor stops the town's supervisor, if one is running.
+func StopSupervisor(townRoot string) (bool, error) {
+	running, pid := SupervisorRunning(townRoot)
+	if !running {
+		return false, nil
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false, fmt.Errorf("finding supervisor process: %w", err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return false, fmt.Errorf("stopping supervisor: %w", err)
+	}
+	for i := 0; i < 20; i++ {
+		if process.Signal(syscall.Signal(0)) != nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true, nil
+}
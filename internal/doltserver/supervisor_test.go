@@ -0,0 +1,9 @@
+package doltserver
+
+import "testing"
+
+func TestSupervisorRunning_NoPidFile(t *testing.T) {
+	if running, pid := SupervisorRunning(t.TempDir()); running || pid != 0 {
+		t.Errorf("SupervisorRunning = %v, %d; want false, 0", running, pid)
+	}
+}
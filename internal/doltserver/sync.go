@@ -22,6 +22,11 @@ type SyncOptions struct {
 
 	// Filter restricts sync to a single database name. Empty means all.
 	Filter string
+
+	// PushOnly skips pulling remote changes; PullOnly skips pushing local
+	// ones. Either way the remote is fetched to report ahead/behind.
+	PushOnly bool
+	PullOnly bool
 }
 
 // SyncResult records the outcome of syncing a single database.
@@ -32,6 +37,21 @@ type SyncResult struct {
 	// Pushed is true if dolt push succeeded.
 	Pushed bool
 
+	// Pulled is true if dolt pull brought in remote commits.
+	Pulled bool
+
+	// Ahead and Behind count the commits main has that origin/main lacks,
+	// and the reverse, as of the fetch before syncing.
+	Ahead  int
+	Behind int
+
+	// NewRemote is true if the remote had no main branch yet.
+	NewRemote bool
+
+	// AddedRemote is true if origin was added from RemotesFile (in a dry
+	// run, if it would be, and nothing was fetched).
+	AddedRemote bool
+
 	// Skipped is true if the database was skipped (e.g., no remote configured).
 	Skipped bool
 
@@ -102,23 +122,103 @@ func CommitWorkingSet(dbDir string) error {
 // PushDatabase pushes a Dolt database directory to origin main.
 // If force is true, uses --force.
 func PushDatabase(dbDir string, force bool) error {
-	args := []string{"push", "origin", "main"}
-	if force {
-		args = append(args, "--force")
+	return pushDatabase(dbDir, force, nil)
+}
+
+func pushDatabase(dbDir string, force bool, remote *RemoteConfig) error {
+	return pushBranch(dbDir, "main", force, remote)
+}
+
+// FetchDatabase fetches origin into a Dolt database directory, updating its
+// remote-tracking branches.
+func FetchDatabase(dbDir string, remote *RemoteConfig) error {
+	_, err := runRemoteCommand(dbDir, remote, []string{"fetch"}, "origin")
+	return err
+}
+
+// PullDatabase merges origin main into a Dolt database directory's main.
+func PullDatabase(dbDir string, remote *RemoteConfig) error {
+	_, err := runRemoteCommand(dbDir, remote, []string{"pull"}, "origin", "main")
+	return err
+}
+
+// runRemoteCommand runs a dolt command that talks to a remote,
+// authenticating as remote's user. Credentials go in flags and the
+// environment and never appear in errors.
+func runRemoteCommand(dbDir string, remote *RemoteConfig, args []string, operands ...string) (string, error) {
+	credArgs, credEnv, err := remote.credentials()
+	if err != nil {
+		return "", err
+	}
+	args = append(append(args, credArgs...), operands...)
+	cmd := exec.Command("dolt", args...)
+	cmd.Dir = dbDir
+	if len(credEnv) > 0 {
+		cmd.Env = append(os.Environ(), credEnv...)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("dolt %s: %w (%s)", args[0], err, strings.TrimSpace(string(output)))
 	}
+	return string(output), nil
+}
 
+// AheadBehind compares main with the fetched origin/main. newRemote is true
+// if origin has no main branch yet, in which case every commit is ahead.
+func AheadBehind(dbDir string) (ahead, behind int, newRemote bool, err error) {
+	branches, err := runDolt(dbDir, "branch", "-r")
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if !hasRemoteBranch(branches, "origin/main") {
+		return 0, 0, true, nil
+	}
+	out, err := runDolt(dbDir, "log", "--oneline", "origin/main..main")
+	if err != nil {
+		return 0, 0, false, err
+	}
+	ahead = countLines(out)
+	if out, err = runDolt(dbDir, "log", "--oneline", "main..origin/main"); err != nil {
+		return 0, 0, false, err
+	}
+	return ahead, countLines(out), false, nil
+}
+
+func runDolt(dbDir string, args ...string) (string, error) {
 	cmd := exec.Command("dolt", args...)
 	cmd.Dir = dbDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("dolt push: %w (%s)", err, strings.TrimSpace(string(output)))
+		return "", fmt.Errorf("dolt %s: %w (%s)", args[0], err, strings.TrimSpace(string(output)))
 	}
+	return string(output), nil
+}
 
-	return nil
+// hasRemoteBranch reports whether dolt branch -r output lists branch
+// (e.g. "origin/main", listed as "remotes/origin/main").
+func hasRemoteBranch(output, branch string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		name := strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*")), "remotes/")
+		if name == branch {
+			return true
+		}
+	}
+	return false
+}
+
+func countLines(output string) int {
+	n := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
 }
 
 // SyncDatabases iterates all databases (or a filtered subset), checks for remotes,
-// commits working changes, and pushes to origin. Never fails fast — collects all results.
+// commits working changes, fetches origin, pulls what the remote has and pushes
+// what it lacks. Never fails fast — collects all results.
 func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 	databases, err := ListDatabases(townRoot)
 	if err != nil {
@@ -127,6 +227,10 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 			Error:    fmt.Errorf("listing databases: %w", err),
 		}}
 	}
+	remotes, err := LoadRemotes(townRoot)
+	if err != nil {
+		return []SyncResult{{Database: "(config)", Error: err}}
+	}
 
 	var results []SyncResult
 
@@ -135,70 +239,137 @@ func SyncDatabases(townRoot string, opts SyncOptions) []SyncResult {
 		if opts.Filter != "" && db != opts.Filter {
 			continue
 		}
+		results = append(results, syncDatabase(townRoot, db, remotes, opts))
+	}
 
-		dbDir := RigDatabaseDir(townRoot, db)
-		result := SyncResult{Database: db}
+	return results
+}
 
-		// Check for remote
-		remote, err := HasRemote(dbDir)
-		if err != nil {
-			result.Error = fmt.Errorf("checking remote: %w", err)
-			results = append(results, result)
-			continue
-		}
-		result.Remote = remote
+func syncDatabase(townRoot, db string, remotes *Remotes, opts SyncOptions) SyncResult {
+	dbDir := RigDatabaseDir(townRoot, db)
+	result := SyncResult{Database: db}
 
-		if remote == "" {
-			// Auto-setup DoltHub remote if credentials are available.
-			token := DoltHubToken()
-			org := DoltHubOrg()
-			if token != "" && org != "" {
-				if err := SetupDoltHubRemote(dbDir, org, db, token); err != nil {
-					// Setup failed — skip this database for now.
-					result.Error = fmt.Errorf("auto-setup DoltHub remote: %w", err)
-					results = append(results, result)
-					continue
-				}
-				// Remote is now configured; re-read it.
-				remote, err = HasRemote(dbDir)
-				if err != nil || remote == "" {
-					result.Error = fmt.Errorf("remote not found after auto-setup")
-					results = append(results, result)
-					continue
-				}
-				result.Remote = remote
-			} else {
-				result.Skipped = true
-				results = append(results, result)
-				continue
+	// Check for remote
+	remote, err := HasRemote(dbDir)
+	if err != nil {
+		result.Error = fmt.Errorf("checking remote: %w", err)
+		return result
+	}
+	result.Remote = remote
+
+	var creds *RemoteConfig
+	if cfg, ok := remotes.For(db); ok {
+		creds = &cfg
+		switch {
+		case remote == "":
+			result.Remote = cfg.URL
+			result.AddedRemote = true
+			if opts.DryRun {
+				result.DryRun = true
+				return result
+			}
+			if err := addOriginRemote(dbDir, cfg.URL); err != nil {
+				result.Error = err
+				return result
 			}
+		case remote != cfg.URL:
+			result.Error = fmt.Errorf("origin is %s but %s configures %s (fix with: dolt remote remove origin)", remote, RemotesFile, cfg.URL)
+			return result
 		}
-
-		if opts.DryRun {
-			result.DryRun = true
-			results = append(results, result)
-			continue
+	} else if remote == "" {
+		// Auto-setup DoltHub remote if credentials are available.
+		token := DoltHubToken()
+		org := DoltHubOrg()
+		if token == "" || org == "" || opts.PullOnly || opts.DryRun {
+			result.Skipped = true
+			return result
 		}
+		if err := SetupDoltHubRemote(dbDir, org, db, token); err != nil {
+			// Setup failed — skip this database for now.
+			result.Error = fmt.Errorf("auto-setup DoltHub remote: %w", err)
+			return result
+		}
+		// Remote is now configured; re-read it.
+		remote, err = HasRemote(dbDir)
+		if err != nil || remote == "" {
+			result.Error = fmt.Errorf("remote not found after auto-setup")
+			return result
+		}
+		result.Remote = remote
+	}
 
-		// Commit working set
+	// Commit working set, so it is counted and can be merged with the remote
+	if !opts.DryRun {
 		if err := CommitWorkingSet(dbDir); err != nil {
 			result.Error = fmt.Errorf("committing: %w", err)
-			results = append(results, result)
-			continue
+			return result
 		}
+	}
+
+	if err := FetchDatabase(dbDir, creds); err != nil {
+		result.Error = err
+		return result
+	}
+	result.Ahead, result.Behind, result.NewRemote, err = AheadBehind(dbDir)
+	if err != nil {
+		result.Error = fmt.Errorf("comparing with origin: %w", err)
+		return result
+	}
 
-		// Push
-		if err := PushDatabase(dbDir, opts.Force); err != nil {
+	if opts.DryRun {
+		result.DryRun = true
+		return result
+	}
+
+	// A force-push overwrites the remote, so there is nothing to pull first.
+	skipPull := opts.PushOnly || (opts.Force && !opts.PullOnly)
+	if !skipPull && result.Behind > 0 {
+		if err := PullDatabase(dbDir, creds); err != nil {
 			result.Error = err
-			results = append(results, result)
-			continue
+			return result
 		}
+		result.Pulled = true
+	}
 
+	if !opts.PullOnly && (result.Ahead > 0 || result.NewRemote || opts.Force) {
+		if err := pushDatabase(dbDir, opts.Force, creds); err != nil {
+			result.Error = err
+			return result
+		}
 		result.Pushed = true
-		results = append(results, result)
 	}
 
-	return results
+	// The shared database's rig branches travel with it (see LayoutFile).
+	if !opts.PullOnly {
+		if layout, err := LoadLayout(townRoot); err == nil && db == layout.Database {
+			for _, rigName := range layout.Rigs {
+				if err := pushBranch(dbDir, rigName, opts.Force, creds); err != nil {
+					result.Error = fmt.Errorf("pushing branch %s: %w", rigName, err)
+					return result
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// pushBranch pushes a branch of a Dolt database directory to origin.
+func pushBranch(dbDir, branch string, force bool, remote *RemoteConfig) error {
+	args := []string{"push"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := runRemoteCommand(dbDir, remote, args, "origin", branch)
+	return err
+}
+
+// addOriginRemote points a database's origin at url.
+func addOriginRemote(dbDir, url string) error {
+	if _, err := runDolt(dbDir, "remote", "add", "origin", url); err != nil {
+		return fmt.Errorf("adding origin %s: %w", url, err)
+	}
+	return nil
 }
 
 // PurgeClosedEphemerals runs "bd purge" for a specific rig database to remove
@@ -280,7 +451,7 @@ func PurgeClosedEphemerals(townRoot, dbName string, dryRun bool) (int, error) {
 	// Warn if purged_count field was missing from the JSON response — may indicate
 	// a schema mismatch (e.g., field renamed). An explicit 0 is a valid success case.
 	if result.PurgedCount == nil {
-		fmt.Fprintf(os.Stderr, "Warning: bd purge for %s: purged_count field missing (raw: %s)\n", dbName, strings.TrimSpace(stdout.String()))
+		logger.Warn("bd purge: purged_count field missing", "database", dbName, "raw", strings.TrimSpace(stdout.String()))
 		return 0, nil
 	}
 
@@ -0,0 +1,236 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RowSet names the rows of one table a write may touch: those whose Column
+// is one of Values.
+type RowSet struct {
+	Table  string
+	Column string
+	Values []string
+}
+
+// MultiDBTx coordinates a write that spans more than one rig's database,
+// such as moving a bead from a rig into hq. Dolt has no two-phase commit
+// across databases, so each step declares the rows it may write; before the
+// step runs the coordinator saves those rows, and if a later step fails it
+// puts back exactly the saved rows in every database already written. Rows
+// written by anyone else in the meantime are left alone.
+//
+// Usage:
+//
+//	tx := NewMultiDBTx(townRoot)
+//	tx.Add("hq", createInHQ, RowSet{Table: "issues", Column: "id", Values: []string{"hq-abc"}})
+//	tx.Add("gastown", closeInRig, RowSet{Table: "issues", Column: "id", Values: []string{"gt-abc"}})
+//	if err := tx.Run("move gt-abc to hq"); err != nil { ... }
+type MultiDBTx struct {
+	townRoot string
+	steps    []txStep
+
+	// Injectable for testing; default to the server-backed implementations.
+	query func(rig, db, query string) ([]map[string]any, error)
+	exec  func(rig, db, script string) error
+}
+
+type txStep struct {
+	rig   string
+	apply func() error
+	rows  []RowSet
+}
+
+// txSnapshot is the saved state of one step's rows.
+type txSnapshot struct {
+	rig, db string
+	tables  []RowSet
+	rows    [][]map[string]any // rows[i] are the saved rows of tables[i]
+}
+
+// NewMultiDBTx creates a coordinator for writes against the town's rigs.
+func NewMultiDBTx(townRoot string) *MultiDBTx {
+	return &MultiDBTx{
+		townRoot: townRoot,
+		query: func(rig, db, query string) ([]map[string]any, error) {
+			return txQuery(ConfigForRig(townRoot, rig), db, query)
+		},
+		exec: func(rig, db, script string) error {
+			return txExec(ConfigForRig(townRoot, rig), db, script)
+		},
+	}
+}
+
+// Add queues a write to rig's database. apply performs it (typically by
+// running bd); rows are the rows it may create, change or delete.
+func (tx *MultiDBTx) Add(rig string, apply func() error, rows ...RowSet) {
+	tx.steps = append(tx.steps, txStep{rig: rig, apply: apply, rows: rows})
+}
+
+// Run applies the queued writes in order. If a step fails, the rows of that
+// step and of every step before it are restored to what they were before
+// Run, newest first, and committed to each database's history with message.
+// The returned error wraps the failure and any restore failures, since a
+// failed restore leaves the databases inconsistent.
+func (tx *MultiDBTx) Run(message string) error {
+	var done []*txSnapshot
+	for _, step := range tx.steps {
+		snap, err := tx.snapshot(step)
+		if err == nil {
+			err = step.apply()
+			if err != nil {
+				err = fmt.Errorf("writing to %s: %w", step.rig, err)
+			}
+			done = append(done, snap)
+		}
+		if err != nil {
+			return errors.Join(err, tx.rollback(done, message))
+		}
+	}
+	return nil
+}
+
+// snapshot saves the rows step may write.
+func (tx *MultiDBTx) snapshot(step txStep) (*txSnapshot, error) {
+	db := RigDatabase(tx.townRoot, step.rig)
+	snap := &txSnapshot{rig: step.rig, db: db}
+	for _, set := range step.rows {
+		if len(set.Values) == 0 {
+			continue
+		}
+		rows, err := tx.query(step.rig, db, fmt.Sprintf("SELECT * FROM %s WHERE %s",
+			quoteIdentifier(set.Table), txWhere(set)))
+		if err != nil {
+			if strings.Contains(err.Error(), "table not found") {
+				// Older bd schemas lack some tables; nothing to save.
+				continue
+			}
+			return nil, fmt.Errorf("saving %s rows in %s: %w", set.Table, step.rig, err)
+		}
+		snap.tables = append(snap.tables, set)
+		snap.rows = append(snap.rows, rows)
+	}
+	return snap, nil
+}
+
+// rollback restores the saved rows of done, newest first.
+func (tx *MultiDBTx) rollback(done []*txSnapshot, message string) error {
+	var errs []error
+	for _, snap := range slices.Backward(done) {
+		if len(snap.tables) == 0 {
+			continue
+		}
+		if err := tx.exec(snap.rig, snap.db, buildRestoreScript(snap, message)); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", snap.rig, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// buildRestoreScript returns SQL putting back snap's rows: the rows now
+// matching each RowSet are deleted, the saved ones inserted, and the tables
+// committed. Deletes go in reverse order and inserts in order, so child
+// tables listed after their parent keep their foreign keys satisfied.
+func buildRestoreScript(snap *txSnapshot, message string) string {
+	var sb strings.Builder
+	sb.WriteString("START TRANSACTION;\n")
+	for _, set := range slices.Backward(snap.tables) {
+		fmt.Fprintf(&sb, "DELETE FROM %s WHERE %s;\n", quoteIdentifier(set.Table), txWhere(set))
+	}
+	for i, set := range snap.tables {
+		for _, row := range snap.rows[i] {
+			cols := make([]string, 0, len(row))
+			for col := range row {
+				cols = append(cols, col)
+			}
+			slices.Sort(cols)
+			vals := make([]string, len(cols))
+			for j, col := range cols {
+				vals[j] = txLiteral(row[col])
+				cols[j] = quoteIdentifier(col)
+			}
+			fmt.Fprintf(&sb, "INSERT INTO %s (%s) VALUES (%s);\n",
+				quoteIdentifier(set.Table), strings.Join(cols, ", "), strings.Join(vals, ", "))
+		}
+	}
+	sb.WriteString("COMMIT;\n")
+	for _, set := range snap.tables {
+		fmt.Fprintf(&sb, "CALL DOLT_ADD('%s');\n", sqlEscape(set.Table))
+	}
+	fmt.Fprintf(&sb, "CALL DOLT_COMMIT('--skip-empty', '-m', '%s');\n", sqlEscape("gt: revert "+message))
+	return sb.String()
+}
+
+// txWhere returns the condition matching set's rows.
+func txWhere(set RowSet) string {
+	vals := make([]string, len(set.Values))
+	for i, v := range set.Values {
+		vals[i] = "'" + sqlEscape(v) + "'"
+	}
+	return fmt.Sprintf("%s IN (%s)", quoteIdentifier(set.Column), strings.Join(vals, ", "))
+}
+
+// txLiteral returns v, as decoded from dolt's JSON output, as a SQL literal.
+func txLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		return "'" + sqlEscape(v) + "'"
+	default:
+		// JSON columns come back as objects or arrays.
+		data, _ := json.Marshal(v)
+		return "'" + sqlEscape(string(data)) + "'"
+	}
+}
+
+// txQuery runs query against db and returns its rows. Dolt leaves NULL
+// columns out of its JSON rows; restoring them leaves the column default,
+// which for bd's nullable columns is NULL.
+func txQuery(config *Config, db, query string) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := buildDoltSQLCmd(ctx, config, "-r", "json", "-q", fmt.Sprintf("USE %s; %s", quoteIdentifier(db), query))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(output))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("parsing query output: %w", err)
+	}
+	return result.Rows, nil
+}
+
+// txExec runs script against db.
+func txExec(config *Config, db, script string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := buildDoltSQLCmd(ctx, config, "-q", fmt.Sprintf("USE %s;\n%s", quoteIdentifier(db), script))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeTxServer stands in for the Dolt server: it answers snapshot queries
+// from rows and records the restore scripts run against each database.
+type fakeTxServer struct {
+	rows     map[string][]map[string]any // "db table" -> rows
+	restored []string                    // databases, in restore order
+	scripts  map[string]string
+}
+
+func newFakeTx(t *testing.T, server *fakeTxServer) *MultiDBTx {
+	t.Helper()
+	tx := NewMultiDBTx(t.TempDir())
+	server.scripts = make(map[string]string)
+	tx.query = func(rig, db, query string) ([]map[string]any, error) {
+		for key, rows := range server.rows {
+			db2, table, _ := strings.Cut(key, " ")
+			if db2 == db && strings.Contains(query, "FROM `"+table+"`") {
+				return rows, nil
+			}
+		}
+		if strings.Contains(query, "FROM `events`") {
+			return nil, errors.New("table not found: events")
+		}
+		return nil, nil
+	}
+	tx.exec = func(rig, db, script string) error {
+		server.restored = append(server.restored, db)
+		server.scripts[db] = script
+		return nil
+	}
+	return tx
+}
+
+func moveRows(id string) []RowSet {
+	return []RowSet{
+		{Table: "issues", Column: "id", Values: []string{id}},
+		{Table: "labels", Column: "issue_id", Values: []string{id}},
+		{Table: "events", Column: "issue_id", Values: []string{id}},
+	}
+}
+
+func TestMultiDBTx_SecondWriteFailsRevertsFirst(t *testing.T) {
+	server := &fakeTxServer{rows: map[string][]map[string]any{
+		"gastown issues": {{"id": "gt-abc", "title": "Fix 'it'", "status": "open", "priority": json.Number("2"), "pinned": false}},
+		"gastown labels": {{"issue_id": "gt-abc", "label": "bug"}},
+	}}
+	tx := newFakeTx(t, server)
+
+	var created bool
+	tx.Add("hq", func() error { created = true; return nil }, moveRows("hq-xyz")...)
+	tx.Add("gastown", func() error { return errors.New("bd close failed") }, moveRows("gt-abc")...)
+
+	err := tx.Run("move gt-abc to hq-xyz")
+	if err == nil || !strings.Contains(err.Error(), "bd close failed") {
+		t.Fatalf("Run() error = %v, want the close failure", err)
+	}
+	if !created {
+		t.Fatal("first write never ran")
+	}
+	if len(server.restored) != 2 || server.restored[0] != "gastown" || server.restored[1] != "hq" {
+		t.Fatalf("restored %v, want [gastown hq]", server.restored)
+	}
+
+	// hq had none of the new bead's rows, so they are deleted and nothing
+	// else in hq is touched.
+	hq := server.scripts["hq"]
+	for _, want := range []string{
+		"DELETE FROM `labels` WHERE `issue_id` IN ('hq-xyz');",
+		"DELETE FROM `issues` WHERE `id` IN ('hq-xyz');",
+		"CALL DOLT_COMMIT('--skip-empty', '-m', 'gt: revert move gt-abc to hq-xyz');",
+	} {
+		if !strings.Contains(hq, want) {
+			t.Errorf("hq restore script missing %q:\n%s", want, hq)
+		}
+	}
+	if strings.Contains(hq, "INSERT") || strings.Contains(hq, "events") {
+		t.Errorf("hq restore script writes more than the new bead's rows:\n%s", hq)
+	}
+	if strings.Index(hq, "`labels`") > strings.Index(hq, "DELETE FROM `issues`") {
+		t.Errorf("child rows must be deleted before the issue:\n%s", hq)
+	}
+
+	// The failing step's rows are put back as they were.
+	gastown := server.scripts["gastown"]
+	for _, want := range []string{
+		"INSERT INTO `issues` (`id`, `pinned`, `priority`, `status`, `title`) VALUES ('gt-abc', 0, 2, 'open', 'Fix ''it''');",
+		"INSERT INTO `labels` (`issue_id`, `label`) VALUES ('gt-abc', 'bug');",
+	} {
+		if !strings.Contains(gastown, want) {
+			t.Errorf("gastown restore script missing %q:\n%s", want, gastown)
+		}
+	}
+
+	for db, script := range server.scripts {
+		if strings.Contains(script, "DOLT_RESET") {
+			t.Errorf("%s restore resets the database:\n%s", db, script)
+		}
+	}
+}
+
+func TestMultiDBTx_SuccessRestoresNothing(t *testing.T) {
+	server := &fakeTxServer{}
+	tx := newFakeTx(t, server)
+	var ran []string
+	tx.Add("hq", func() error { ran = append(ran, "hq"); return nil }, moveRows("hq-xyz")...)
+	tx.Add("gastown", func() error { ran = append(ran, "gastown"); return nil }, moveRows("gt-abc")...)
+
+	if err := tx.Run("move"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(ran) != 2 || ran[0] != "hq" || ran[1] != "gastown" {
+		t.Errorf("ran %v, want [hq gastown]", ran)
+	}
+	if len(server.restored) != 0 {
+		t.Errorf("restored %v after success", server.restored)
+	}
+}
+
+func TestMultiDBTx_SnapshotFailureSkipsWrite(t *testing.T) {
+	tx := newFakeTx(t, &fakeTxServer{})
+	var restored []string
+	tx.exec = func(rig, db, script string) error {
+		restored = append(restored, db)
+		return errors.New("connection refused")
+	}
+	tx.query = func(rig, db, query string) ([]map[string]any, error) {
+		if db == "gastown" {
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	}
+	var closed bool
+	tx.Add("hq", func() error { return nil }, moveRows("hq-xyz")...)
+	tx.Add("gastown", func() error { closed = true; return nil }, moveRows("gt-abc")...)
+
+	err := tx.Run("move")
+	if err == nil || !strings.Contains(err.Error(), "saving issues rows in gastown") {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if closed {
+		t.Error("write ran without a snapshot")
+	}
+	if len(restored) != 1 || restored[0] != "hq" {
+		t.Errorf("restored %v, want [hq]", restored)
+	}
+	if !strings.Contains(err.Error(), "restoring hq") {
+		t.Errorf("restore failure not reported: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package doltserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InviteUserPrefix marks SQL users created for 'gt town invite'. Only users
+// with this prefix can be dropped by DropScopedUser, so revocation can never
+// remove the server's own account.
+const InviteUserPrefix = "gt_invite_"
+
+// CreateScopedUser creates a new SQL user on the town's Dolt server with
+// privileges limited to the given databases, and returns its name and a
+// random password. The user can't create users or grant privileges, and is
+// revoked with DropScopedUser.
+func CreateScopedUser(townRoot string, databases []string) (user, password string, err error) {
+	if len(databases) == 0 {
+		return "", "", fmt.Errorf("no databases to grant")
+	}
+	suffix := make([]byte, 4)
+	secret := make([]byte, 24)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", "", fmt.Errorf("generating user name: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("generating password: %w", err)
+	}
+	user = InviteUserPrefix + hex.EncodeToString(suffix)
+	password = base64.RawURLEncoding.EncodeToString(secret)
+
+	if err := serverExecSQL(townRoot, scopedUserSQL(user, password, databases)); err != nil {
+		return "", "", fmt.Errorf("creating user %s: %w", user, err)
+	}
+	return user, password, nil
+}
+
+// DropScopedUser revokes an invite credential created by CreateScopedUser.
+func DropScopedUser(townRoot, user string) error {
+	if !strings.HasPrefix(user, InviteUserPrefix) {
+		return fmt.Errorf("%q is not an invite user (expected prefix %s)", user, InviteUserPrefix)
+	}
+	if err := serverExecSQL(townRoot, fmt.Sprintf("DROP USER IF EXISTS %s", sqlAccount(user))); err != nil {
+		return fmt.Errorf("dropping user %s: %w", user, err)
+	}
+	return nil
+}
+
+// ListScopedUsers returns the invite users present on the server.
+func ListScopedUsers(townRoot string) ([]string, error) {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf("SELECT user FROM mysql.user WHERE user LIKE '%s%%'", strings.ReplaceAll(InviteUserPrefix, "_", `\_`))
+	output, err := buildDoltSQLCmd(ctx, config, "-r", "csv", "-q", query).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	var users []string
+	for i, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		line = strings.TrimSpace(line)
+		if i == 0 || line == "" { // header row
+			continue
+		}
+		users = append(users, line)
+	}
+	return users, nil
+}
+
+// scopedUserSQL builds the statements that create user and grant it full
+// access to each database, and nothing server-wide.
+func scopedUserSQL(user, password string, databases []string) string {
+	account := sqlAccount(user)
+	stmts := []string{fmt.Sprintf("CREATE USER %s IDENTIFIED BY '%s'", account, sqlEscape(password))}
+	for _, db := range databases {
+		stmts = append(stmts, fmt.Sprintf("GRANT ALL PRIVILEGES ON `%s`.* TO %s",
+			strings.ReplaceAll(db, "`", "``"), account))
+	}
+	return strings.Join(stmts, "; ")
+}
+
+// sqlAccount returns the quoted 'user'@'%' account name.
+func sqlAccount(user string) string {
+	return fmt.Sprintf("'%s'@'%%'", sqlEscape(user))
+}
+
+func sqlEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''")
+}
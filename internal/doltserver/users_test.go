@@ -0,0 +1,27 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScopedUserSQL(t *testing.T) {
+	got := scopedUserSQL("gt_invite_ab12", "s3cr'et", []string{"hq", "gas`town"})
+	want := "CREATE USER 'gt_invite_ab12'@'%' IDENTIFIED BY 's3cr''et'; " +
+		"GRANT ALL PRIVILEGES ON `hq`.* TO 'gt_invite_ab12'@'%'; " +
+		"GRANT ALL PRIVILEGES ON `gas``town`.* TO 'gt_invite_ab12'@'%'"
+	if got != want {
+		t.Errorf("scopedUserSQL =\n  %s\nwant\n  %s", got, want)
+	}
+	if strings.Contains(got, "*.*") || strings.Contains(got, "GRANT OPTION") {
+		t.Error("invite user must not get server-wide privileges")
+	}
+}
+
+func TestDropScopedUser_RefusesOtherUsers(t *testing.T) {
+	for _, user := range []string{"root", "gt_invitex", ""} {
+		if err := DropScopedUser(t.TempDir(), user); err == nil {
+			t.Errorf("DropScopedUser(%q) should refuse non-invite users", user)
+		}
+	}
+}
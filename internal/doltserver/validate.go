@@ -0,0 +1,206 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// MinFreeDisk is the free space the data directory's filesystem needs for
+// the server to start. Dolt writes journals and chunk files on every
+// commit; a full disk corrupts them rather than failing cleanly.
+var MinFreeDisk int64 = 256 << 20
+
+// validDatabaseNameRe matches the database names Dolt serves from the
+// subdirectories of --data-dir (see InitRig).
+var validDatabaseNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ConfigProblem is one thing that would stop the server starting, with
+// what to do about it.
+type ConfigProblem struct {
+	Field  string // port, data_dir, log_file, database, disk, dolt
+	Detail string
+	Fix    string
+}
+
+// ConfigError lists every problem found by Validate.
+type ConfigError struct {
+	Problems []ConfigProblem
+}
+
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dolt server configuration is invalid (%d problem(s)):", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Field, p.Detail)
+		if p.Fix != "" {
+			fmt.Fprintf(&b, "\n    fix: %s", p.Fix)
+		}
+	}
+	return b.String()
+}
+
+// Validate checks everything a local server needs before it is started:
+// the dolt binary and its version, the port, the data, log and PID paths,
+// the database directory names, and free disk space. It returns a
+// *ConfigError naming every problem, so that a misconfigured server fails
+// here rather than half-starting and failing on the first query. Remote
+// servers are not started by gt and are not checked.
+func (c *Config) Validate() error {
+	if c.IsRemote() {
+		return nil
+	}
+	var problems []ConfigProblem
+	add := func(field, detail, fix string) {
+		problems = append(problems, ConfigProblem{Field: field, Detail: detail, Fix: fix})
+	}
+
+	// Binary and version
+	if _, err := exec.LookPath("dolt"); err != nil {
+		add("dolt", "dolt not found in PATH", "install it: "+deps.DoltInstallCommand(runtime.GOOS))
+	} else {
+		townMin, townMax := townDoltRange(c.TownRoot)
+		report, err := deps.CheckInstalledDolt(townMin, townMax)
+		switch {
+		case err != nil:
+			add("dolt", err.Error(), "reinstall dolt: "+deps.DoltInstallCommand(runtime.GOOS))
+		case report.TooOld:
+			minVersion, _ := deps.SupportedDoltRange(townMin, townMax)
+			add("dolt", fmt.Sprintf("dolt %s is older than the minimum %s", report.Version, minVersion),
+				"upgrade: "+deps.DoltInstallCommand(runtime.GOOS))
+		case !report.OK():
+			// Newer than the town allows, or a known-bad release: it may
+			// still serve, so warn rather than refuse.
+			logger.Warn("unsupported dolt version", "version", report.Version,
+				"hint", strings.TrimSpace(versionSkewHint(c.TownRoot)))
+		}
+	}
+
+	// Port
+	switch {
+	case c.Port < 1 || c.Port > 65535:
+		add("port", fmt.Sprintf("%d is not a valid TCP port", c.Port),
+			"set GT_DOLT_PORT to a port between 1024 and 65535, or unset it")
+	case c.Port < 1024 && os.Geteuid() != 0:
+		add("port", fmt.Sprintf("%d is a privileged port; the server cannot bind it without root", c.Port),
+			"set GT_DOLT_PORT to a port between 1024 and 65535, or unset it")
+	case !portFree(c.Port):
+		if pid := findDoltServerOnPort(c.Port); pid != 0 {
+			if servesDataDir(doltCommandLine(pid), c.DataDir) {
+				break // this town's server, started meanwhile
+			}
+			add("port", fmt.Sprintf("%d is in use by another Dolt server (PID %d) with a different data directory", c.Port, pid),
+				"unset GT_DOLT_PORT so gt picks a free port, or stop that server")
+		} else {
+			add("port", fmt.Sprintf("%d is in use by another process", c.Port),
+				fmt.Sprintf("find it with 'lsof -i :%d' and stop it, or set GT_DOLT_PORT to a free port", c.Port))
+		}
+	}
+
+	// Paths
+	if p := checkWritableDir(c.DataDir); p != "" {
+		add("data_dir", p, "fix the path or its permissions, or move the data with 'gt dolt migrate'")
+	}
+	for field, path := range map[string]string{"log_file": c.LogFile, "pid_file": c.PidFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			add(field, path+" is a directory", "remove it")
+		} else if p := checkWritableDir(filepath.Dir(path)); p != "" {
+			add(field, p, "fix the directory's permissions")
+		}
+	}
+
+	// Database names
+	if entries, err := os.ReadDir(c.DataDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(c.DataDir, e.Name(), ".dolt")); err != nil {
+				continue
+			}
+			name := e.Name()
+			switch {
+			case !validDatabaseNameRe.MatchString(name):
+				add("database", fmt.Sprintf("%q in %s is not a legal database name (letters, digits, _ and -, at most 64)", name, c.DataDir),
+					"rename the directory, then update dolt_database in the rig's .beads/metadata.json")
+			case IsSystemDatabase(name):
+				add("database", fmt.Sprintf("%q in %s collides with a system database", name, c.DataDir),
+					"move it out of the data directory")
+			}
+		}
+	}
+
+	// Disk space, on the filesystem the data directory is (or will be) on
+	if free, err := diskFree(existingAncestor(c.DataDir)); err == nil && free >= 0 && free < MinFreeDisk {
+		add("disk", fmt.Sprintf("only %s free on the filesystem holding %s (need %s)", formatBytes(free), c.DataDir, formatBytes(MinFreeDisk)),
+			"free up space, or reclaim some with 'gt dolt gc'")
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// checkWritableDir returns what is wrong with dir as a place to write
+// files, or "" if nothing is. A directory that does not exist yet is fine
+// if it can be created.
+func checkWritableDir(dir string) string {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		parent := existingAncestor(dir)
+		if info, err := os.Stat(parent); err != nil || !info.IsDir() {
+			return fmt.Sprintf("%s cannot be created: %s is not a directory", dir, parent)
+		}
+		if !canWrite(parent) {
+			return fmt.Sprintf("%s cannot be created: %s is not writable", dir, parent)
+		}
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf("%s: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return dir + " is not a directory"
+	}
+	if !canWrite(dir) {
+		return dir + " is not writable"
+	}
+	return ""
+}
+
+// canWrite reports whether a file can be created in dir.
+func canWrite(dir string) bool {
+	f, err := os.CreateTemp(dir, ".gt-write-check-")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	return true
+}
+
+// existingAncestor returns path or its nearest ancestor that exists.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,106 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// validationFields returns the fields Validate found problems with.
+func validationFields(t *testing.T, c *Config) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	err := c.Validate()
+	if err == nil {
+		return fields
+	}
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Validate = %v, want *ConfigError", err)
+	}
+	for _, p := range cerr.Problems {
+		if p.Fix == "" {
+			t.Errorf("%s problem has no fix: %s", p.Field, p.Detail)
+		}
+		fields[p.Field] = p.Detail
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	for _, db := range []string{"gastown", "bad.name", "mysql"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, db, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := portFree
+	portFree = func(port int) bool { return port != 3399 }
+	t.Cleanup(func() { portFree = orig })
+
+	c := &Config{
+		TownRoot: townRoot,
+		Port:     3307,
+		DataDir:  dataDir,
+		LogFile:  filepath.Join(townRoot, "daemon", "dolt.log"),
+		PidFile:  filepath.Join(townRoot, "daemon", "dolt.pid"),
+	}
+	fields := validationFields(t, c)
+	if d := fields["database"]; !strings.Contains(d, "bad.name") && !strings.Contains(d, "mysql") {
+		t.Errorf("database problem = %q, want bad.name or mysql", d)
+	}
+	for _, f := range []string{"port", "data_dir", "log_file", "pid_file", "disk"} {
+		if d, ok := fields[f]; ok {
+			t.Errorf("unexpected %s problem: %s", f, d)
+		}
+	}
+
+	c.Port = 70000
+	if _, ok := validationFields(t, c)["port"]; !ok {
+		t.Error("port 70000 accepted")
+	}
+
+	c.Port = 3307
+	c.DataDir = filepath.Join(townRoot, "daemon", "dolt.log")
+	if err := os.MkdirAll(filepath.Dir(c.DataDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.DataDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if d := validationFields(t, c)["data_dir"]; !strings.Contains(d, "not a directory") {
+		t.Errorf("data_dir problem = %q, want not a directory", d)
+	}
+
+	c.DataDir = dataDir
+	origMin := MinFreeDisk
+	MinFreeDisk = 1 << 62
+	t.Cleanup(func() { MinFreeDisk = origMin })
+	if _, ok := validationFields(t, c)["disk"]; !ok && runtime.GOOS != "windows" {
+		t.Error("low disk space not reported")
+	}
+}
+
+func TestValidateRemoteSkipped(t *testing.T) {
+	c := &Config{Host: "db.example.com", Port: 0, DataDir: "/nonexistent"}
+	if err := c.Validate(); err != nil {
+		t.Errorf("remote Validate = %v, want nil", err)
+	}
+}
+
+func TestConfigErrorListsFixes(t *testing.T) {
+	err := &ConfigError{Problems: []ConfigProblem{
+		{Field: "port", Detail: "3307 is in use by another process", Fix: "stop it"},
+		{Field: "disk", Detail: "only 10 MB free", Fix: "free up space"},
+	}}
+	msg := err.Error()
+	for _, want := range []string{"2 problem(s)", "port: 3307", "fix: stop it", "disk: only", "fix: free up space"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() missing %q:\n%s", want, msg)
+		}
+	}
+}
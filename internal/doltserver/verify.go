@@ -0,0 +1,163 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// VerifyResult is the outcome of verifying one database.
+//
+// Two checks run. dolt fsck walks the database's chunk store and fails on
+// missing or corrupt chunks. Then the beads in the rig's JSONL export
+// (issues.jsonl) are looked up in the database's issues table: a bead in
+// the export but not the database was lost. The database may hold more
+// than the export, since bd leaves wisps and merge requests out of it, and
+// the export may lag a few writes behind.
+type VerifyResult struct {
+	Database   string   `json:"database"`
+	Fsck       string   `json:"fsck"`                  // ok, failed, or skipped
+	FsckOutput string   `json:"fsck_output,omitempty"` // What dolt fsck reported on failure
+	Rows       int64    `json:"rows"`                  // Rows in the issues table
+	Export     string   `json:"export,omitempty"`      // JSONL export compared against
+	ExportRows int64    `json:"export_rows"`           // Beads in the export
+	Missing    []string `json:"missing,omitempty"`     // In the export but not the database
+	Problems   []string `json:"problems,omitempty"`
+	Notes      []string `json:"notes,omitempty"` // Checks that were skipped, and why
+}
+
+// OK reports whether the database passed verification.
+func (r *VerifyResult) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Fsck outcomes.
+const (
+	FsckOK      = "ok"
+	FsckFailed  = "failed"
+	FsckSkipped = "skipped"
+)
+
+// verifyFsck and verifyQuery run dolt; replaced in tests.
+var (
+	verifyFsck  = fsckDatabase
+	verifyQuery = doltSQLQuery
+)
+
+// CheckIntegrity checks the integrity of each database (or only filter, if
+// set). dolt fsck only reads, so the server can stay up; the export check
+// queries the server and needs it running. Never fails fast — collects all
+// results.
+func CheckIntegrity(townRoot, filter string) ([]VerifyResult, error) {
+	config := DefaultConfig(townRoot)
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	exports := databaseBeadsDirs(townRoot)
+
+	var results []VerifyResult
+	for _, db := range databases {
+		if filter != "" && db != filter {
+			continue
+		}
+		r := VerifyResult{Database: db, Fsck: FsckSkipped}
+		if config.IsRemote() {
+			r.Notes = append(r.Notes, fmt.Sprintf("fsck skipped: server is remote (%s)", config.HostPort()))
+		} else if out, err := verifyFsck(RigDatabaseDir(townRoot, db)); err != nil {
+			r.Fsck = FsckFailed
+			r.FsckOutput = out
+			r.Problems = append(r.Problems, fmt.Sprintf("dolt fsck: %v", err))
+		} else {
+			r.Fsck = FsckOK
+		}
+		verifyExport(townRoot, &r, exports[db])
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// verifyExport compares the database's issues table with the JSONL export
+// in beadsDir.
+func verifyExport(townRoot string, r *VerifyResult, beadsDir string) {
+	if beadsDir == "" {
+		r.Notes = append(r.Notes, "export check skipped: no rig uses this database")
+		return
+	}
+	path := filepath.Join(beadsDir, beads.SnapshotExport)
+	if _, err := os.Stat(path); err != nil {
+		r.Notes = append(r.Notes, fmt.Sprintf("export check skipped: no %s", beads.SnapshotExport))
+		return
+	}
+	r.Export = path
+	snap, err := beads.LoadSnapshot(path)
+	if err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("reading export: %v", err))
+		return
+	}
+	r.ExportRows = int64(len(snap.Issues))
+
+	out, err := verifyQuery(townRoot, fmt.Sprintf("SELECT id FROM %s.issues", sqlIdent(r.Database)))
+	if err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("reading issues table: %v", err))
+		return
+	}
+	inDB := make(map[string]bool)
+	for _, row := range parseSimpleCSV(out) {
+		inDB[row["id"]] = true
+	}
+	r.Rows = int64(len(inDB))
+	for _, issue := range snap.Issues {
+		if !inDB[issue.ID] {
+			r.Missing = append(r.Missing, issue.ID)
+		}
+	}
+	if len(r.Missing) > 0 {
+		sort.Strings(r.Missing)
+		r.Problems = append(r.Problems, fmt.Sprintf("%d bead(s) in the export are missing from the database", len(r.Missing)))
+	}
+}
+
+// databaseBeadsDirs maps each database to the .beads directory of the rig
+// (or town) it backs.
+func databaseBeadsDirs(townRoot string) map[string]string {
+	names := []string{"hq"}
+	if data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		var config struct {
+			Rigs map[string]json.RawMessage `json:"rigs"`
+		}
+		if json.Unmarshal(data, &config) == nil {
+			for name := range config.Rigs {
+				names = append(names, name)
+			}
+		}
+	}
+	dirs := make(map[string]string)
+	for _, name := range names {
+		dirs[RigDatabaseName(townRoot, name)] = FindRigBeadsDir(townRoot, name)
+	}
+	return dirs
+}
+
+// fsckDatabase runs dolt fsck in a database directory, returning its output
+// when it finds a problem.
+func fsckDatabase(dbDir string) (string, error) {
+	cmd := exec.Command("dolt", "fsck")
+	cmd.Dir = dbDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		summary := out
+		if i := strings.IndexByte(summary, '\n'); i >= 0 {
+			summary = summary[:i]
+		}
+		return out, fmt.Errorf("%w (%s)", err, summary)
+	}
+	return "", nil
+}
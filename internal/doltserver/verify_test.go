@@ -0,0 +1,84 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	townRoot := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(townRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, db := range []string{"hq", "gastown", "orphan"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", db, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("mayor/rigs.json", `{"rigs": {"gastown": {}}}`)
+	write(".beads/issues.jsonl", `{"id":"hq-1","title":"a"}`+"\n")
+	write("gastown/.beads/issues.jsonl", `{"id":"gt-1","title":"a"}`+"\n"+`{"id":"gt-2","title":"b"}`+"\n"+`{"id":"gt-3","title":"c"}`+"\n")
+
+	oldFsck, oldQuery := verifyFsck, verifyQuery
+	t.Cleanup(func() { verifyFsck, verifyQuery = oldFsck, oldQuery })
+	verifyFsck = func(dbDir string) (string, error) {
+		if filepath.Base(dbDir) == "gastown" {
+			return "chunk abc123 is missing", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+	verifyQuery = func(_, query string) (string, error) {
+		switch {
+		case strings.Contains(query, "`hq`"):
+			return "id\nhq-1\nhq-wisp-1\n", nil
+		case strings.Contains(query, "`gastown`"):
+			return "id\ngt-1\ngt-2\ngt-mr-1\n", nil
+		}
+		return "", errors.New("unexpected query " + query)
+	}
+
+	results, err := CheckIntegrity(townRoot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byDB := make(map[string]VerifyResult)
+	for _, r := range results {
+		byDB[r.Database] = r
+	}
+	if len(byDB) != 3 {
+		t.Fatalf("verified %d databases, want 3", len(byDB))
+	}
+
+	hq := byDB["hq"]
+	if !hq.OK() || hq.Fsck != FsckOK || hq.Rows != 2 || hq.ExportRows != 1 {
+		t.Errorf("hq = %+v, want OK (extra rows in the database are fine)", hq)
+	}
+
+	gt := byDB["gastown"]
+	if gt.OK() || gt.Fsck != FsckFailed || gt.FsckOutput != "chunk abc123 is missing" {
+		t.Errorf("gastown fsck = %+v", gt)
+	}
+	if len(gt.Missing) != 1 || gt.Missing[0] != "gt-3" || len(gt.Problems) != 2 {
+		t.Errorf("gastown export check = %+v, want gt-3 missing", gt)
+	}
+
+	orphan := byDB["orphan"]
+	if !orphan.OK() || len(orphan.Notes) != 1 {
+		t.Errorf("orphan = %+v, want export check skipped", orphan)
+	}
+
+	only, err := CheckIntegrity(townRoot, "hq")
+	if err != nil || len(only) != 1 || only[0].Database != "hq" {
+		t.Errorf("filtered = %+v, %v", only, err)
+	}
+}
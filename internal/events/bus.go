@@ -0,0 +1,264 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Event sources. Commands log with Source "gt"; long-running subsystems
+// publish under their own name so their events can be filtered.
+const (
+	SourceGT       = "gt"
+	SourceRefinery = "refinery"
+	SourceDolt     = "doltserver"
+	SourcePolecat  = "polecat"
+	SourceDoctor   = "doctor"
+)
+
+// Subsystem event types.
+const (
+	TypeMergeConflicted = "merge_conflicted"
+	TypeDoltStarted     = "dolt_started"
+	TypeDoltStopped     = "dolt_stopped"
+	TypeDoltCrashed     = "dolt_crashed"
+	TypeDoltRestarted   = "dolt_restarted"
+	TypeDoltFailover    = "dolt_failover"
+	TypePolecatAdded    = "polecat_added"
+	TypePolecatRemoved  = "polecat_removed"
+	TypeDoctorFix       = "doctor_fix"
+	TypeBeadUnlocked    = "bead_unlocked"
+)
+
+// Publish appends an event from a subsystem to the town's event log. dir
+// is any directory inside the town (the town root, a rig, ...); if empty,
+// the working directory is used. Outside a town nothing is written. Like
+// Log, publishing is best-effort: callers should ignore the error.
+func Publish(dir, source, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	event := Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     source,
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: visibility,
+	}
+	if dir == "" {
+		return write(event)
+	}
+	townRoot, err := workspace.Find(dir)
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	return writeTo(townRoot, event)
+}
+
+// Filter selects events by field. Conditions are ANDed; each condition
+// matches if the field equals any of its values, starts with one of them
+// (so type=merge matches merged and merge_failed), or matches one as a
+// glob.
+type Filter struct {
+	conds []filterCond
+	Since time.Time // zero = no lower bound
+	Until time.Time // zero = no upper bound; exclusive
+}
+
+type filterCond struct {
+	key    string
+	values []string
+}
+
+// ParseFilter parses key=value conditions. Keys are type, source, actor,
+// visibility, or payload.<key> (a bare unknown key is looked up in the
+// payload). Values may be comma-separated alternatives.
+func ParseFilter(specs []string) (Filter, error) {
+	var f Filter
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || value == "" {
+			return Filter{}, fmt.Errorf("invalid filter %q: expected key=value", spec)
+		}
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				if _, err := path.Match(v, ""); err != nil {
+					return Filter{}, fmt.Errorf("invalid filter %q: %w", spec, err)
+				}
+				values = append(values, v)
+			}
+		}
+		f.conds = append(f.conds, filterCond{key: key, values: values})
+	}
+	return f, nil
+}
+
+// Matches reports whether e satisfies every condition.
+func (f Filter) Matches(e Event) bool {
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || (!f.Since.IsZero() && ts.Before(f.Since)) || (!f.Until.IsZero() && !ts.Before(f.Until)) {
+			return false
+		}
+	}
+	for _, c := range f.conds {
+		field, ok := eventField(e, c.key)
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, v := range c.values {
+			if field == v || strings.HasPrefix(field, v) {
+				matched = true
+				break
+			}
+			if ok, _ := path.Match(v, field); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// eventField returns the string form of a filterable event field.
+func eventField(e Event, key string) (string, bool) {
+	switch key {
+	case "type":
+		return e.Type, true
+	case "source":
+		return e.Source, true
+	case "actor":
+		return e.Actor, true
+	case "visibility":
+		return e.Visibility, true
+	}
+	key = strings.TrimPrefix(key, "payload.")
+	v, ok := e.Payload[key]
+	if !ok {
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	return fmt.Sprint(v), true
+}
+
+// ReadLast returns the last n events in the log matching f (all matches
+// if n <= 0), oldest first, and the log offset reading stopped at for a
+// subsequent Follow. Malformed lines are skipped.
+func ReadLast(logPath string, n int, f Filter) ([]Event, int64, error) {
+	file, err := os.Open(logPath) //nolint:gosec // G304: path is the town events log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var matched []Event
+	offset, err := scanEvents(file, 0, func(e Event) {
+		if !f.Matches(e) {
+			return
+		}
+		matched = append(matched, e)
+		if n > 0 && len(matched) > n {
+			matched = matched[1:]
+		}
+	})
+	return matched, offset, err
+}
+
+// ReadFrom returns the events matching f appended to the log after offset,
+// oldest first, and the offset to pass next time. A log shorter than offset
+// was truncated or replaced and is read from the beginning. offset need not
+// be at a line start: the partial first line is skipped as malformed.
+func ReadFrom(logPath string, offset int64, f Filter) ([]Event, int64, error) {
+	info, err := os.Stat(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, offset, err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return nil, offset, nil
+	}
+
+	file, err := os.Open(logPath) //nolint:gosec // G304: path is the town events log
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	var matched []Event
+	offset, err = scanEvents(file, offset, func(e Event) {
+		if f.Matches(e) {
+			matched = append(matched, e)
+		}
+	})
+	return matched, offset, err
+}
+
+// Follow polls the log from offset and calls fn for every new event
+// matching f until ctx is done. If the log is truncated or replaced it
+// restarts from the beginning.
+func Follow(ctx context.Context, logPath string, offset int64, interval time.Duration, f Filter, fn func(Event)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		matched, next, err := ReadFrom(logPath, offset, f)
+		if err != nil {
+			return err
+		}
+		offset = next
+		for _, e := range matched {
+			fn(e)
+		}
+	}
+}
+
+// scanEvents decodes complete lines from r starting at offset and returns
+// the offset after the last complete line, so a line still being written
+// is read in full on the next call.
+func scanEvents(r io.ReadSeeker, offset int64, fn func(Event)) (int64, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, nil // Partial trailing line: leave for next time
+		}
+		if err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+		var e Event
+		if json.Unmarshal(line, &e) == nil {
+			fn(e)
+		}
+	}
+}
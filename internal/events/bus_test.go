@@ -0,0 +1,196 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	merged := Event{Timestamp: "2026-01-02T03:04:05Z", Source: SourceRefinery, Type: TypeMerged, Actor: "gastown/refinery",
+		Payload: map[string]interface{}{"rig": "gastown", "attempt": float64(2)}}
+	dolt := Event{Timestamp: "2026-01-02T03:04:05Z", Source: SourceDolt, Type: TypeDoltStarted, Actor: "gt"}
+
+	tests := []struct {
+		specs []string
+		event Event
+		want  bool
+	}{
+		{nil, dolt, true},
+		{[]string{"type=merge"}, merged, true},
+		{[]string{"type=merge"}, dolt, false},
+		{[]string{"type=merged"}, merged, true},
+		{[]string{"source=doctor,doltserver"}, dolt, true},
+		{[]string{"actor=*/refinery"}, merged, true},
+		{[]string{"rig=gastown"}, merged, true},
+		{[]string{"payload.rig=beads"}, merged, false},
+		{[]string{"rig=gastown"}, dolt, false},
+		{[]string{"attempt=2"}, merged, true},
+		{[]string{"type=merge", "source=doltserver"}, merged, false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.specs)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", tt.specs, err)
+		}
+		if got := f.Matches(tt.event); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.specs, tt.event.Type, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"type", "=merge", "type=", "type=[a"} {
+		if _, err := ParseFilter([]string{bad}); err == nil {
+			t.Errorf("ParseFilter(%q): expected error", bad)
+		}
+	}
+}
+
+func TestFilter_Since(t *testing.T) {
+	f := Filter{Since: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	if f.Matches(Event{Timestamp: "2026-01-01T23:59:59Z"}) {
+		t.Error("event before Since should not match")
+	}
+	if !f.Matches(Event{Timestamp: "2026-01-02T00:00:01Z"}) {
+		t.Error("event after Since should match")
+	}
+}
+
+func TestFilter_Until(t *testing.T) {
+	f := Filter{Until: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	if !f.Matches(Event{Timestamp: "2026-01-01T23:59:59Z"}) {
+		t.Error("event before Until should match")
+	}
+	if f.Matches(Event{Timestamp: "2026-01-02T00:00:00Z"}) {
+		t.Error("event at Until should not match")
+	}
+}
+
+func TestReadLast(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), EventsFile)
+	data := `{"ts":"2026-01-01T00:00:00Z","source":"gt","type":"sling"}
+not json
+{"ts":"2026-01-01T00:00:01Z","source":"refinery","type":"merged"}
+{"ts":"2026-01-01T00:00:02Z","source":"refinery","type":"merge_failed"}
+{"ts":"2026-01-01T00:00:03Z","source":"gt","type":"partial"`
+	if err := os.WriteFile(logPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, offset, err := ReadLast(logPath, 1, Filter{})
+	if err != nil {
+		t.Fatalf("ReadLast: %v", err)
+	}
+	if len(got) != 1 || got[0].Type != "merge_failed" {
+		t.Errorf("last event = %+v, want merge_failed (partial line skipped)", got)
+	}
+	if want := int64(len(data) - len(`{"ts":"2026-01-01T00:00:03Z","source":"gt","type":"partial"`)); offset != want {
+		t.Errorf("offset = %d, want %d", offset, want)
+	}
+
+	f, _ := ParseFilter([]string{"type=merge"})
+	got, _, err = ReadLast(logPath, 0, f)
+	if err != nil || len(got) != 2 {
+		t.Errorf("ReadLast(type=merge) = %d events, %v; want 2", len(got), err)
+	}
+
+	if got, offset, err := ReadLast(filepath.Join(t.TempDir(), "missing"), 5, Filter{}); err != nil || got != nil || offset != 0 {
+		t.Errorf("missing log: got %v, %d, %v", got, offset, err)
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), EventsFile)
+	first := `{"ts":"2026-01-01T00:00:00Z","source":"gt","type":"sling","payload":{"bead":"gt-1"}}` + "\n"
+	second := `{"ts":"2026-01-01T00:00:01Z","source":"refinery","type":"merged"}` + "\n"
+	if err := os.WriteFile(logPath, []byte(first+second), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Starting mid-line skips the partial line.
+	got, offset, err := ReadFrom(logPath, 10, Filter{})
+	if err != nil || len(got) != 1 || got[0].Type != "merged" || offset != int64(len(first+second)) {
+		t.Fatalf("ReadFrom(10) = %+v, %d, %v", got, offset, err)
+	}
+	if got, next, err := ReadFrom(logPath, offset, Filter{}); err != nil || got != nil || next != offset {
+		t.Errorf("ReadFrom(end) = %+v, %d, %v; want nothing new", got, next, err)
+	}
+
+	// A truncated log is read from the start.
+	if err := os.WriteFile(logPath, []byte(second), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, next, err := ReadFrom(logPath, offset, Filter{}); err != nil || len(got) != 1 || next != int64(len(second)) {
+		t.Errorf("ReadFrom after truncation = %+v, %d, %v", got, next, err)
+	}
+}
+
+func TestPublishAndFollow(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, filepath.Dir(workspace.PrimaryMarker)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, workspace.PrimaryMarker), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigDir := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(rigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(townRoot, EventsFile)
+
+	if err := Publish(rigDir, SourceDolt, TypeDoltStarted, "gt", map[string]interface{}{"port": 3307}, VisibilityAudit); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	got, offset, err := ReadLast(logPath, 0, Filter{})
+	if err != nil || len(got) != 1 || got[0].Source != SourceDolt {
+		t.Fatalf("after Publish: %+v, %v", got, err)
+	}
+
+	f, _ := ParseFilter([]string{"source=polecat"})
+	var mu sync.Mutex
+	var followed []Event
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, logPath, offset, 10*time.Millisecond, f, func(e Event) {
+			mu.Lock()
+			followed = append(followed, e)
+			mu.Unlock()
+		})
+	}()
+
+	_ = Publish(rigDir, SourceDolt, TypeDoltStopped, "gt", nil, VisibilityAudit)
+	_ = Publish(rigDir, SourcePolecat, TypePolecatAdded, "gastown/polecats/Toast", nil, VisibilityBoth)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(followed)
+		mu.Unlock()
+		if n >= 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+	if len(followed) != 1 || followed[0].Type != TypePolecatAdded {
+		t.Errorf("followed = %+v, want one polecat_added", followed)
+	}
+
+	// Outside a town nothing is written.
+	outside := t.TempDir()
+	if err := Publish(outside, SourceGT, "test", "gt", nil, VisibilityAudit); err != nil {
+		t.Errorf("Publish outside town: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, EventsFile)); !os.IsNotExist(err) {
+		t.Error("Publish wrote an events file outside a town")
+	}
+}
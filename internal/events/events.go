@@ -1,7 +1,10 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). Besides
+// gt commands, subsystems such as the refinery, Dolt server, polecat
+// manager, and doctor publish structured events via Publish; 'gt events
+// tail' reads them back.
 package events
 
 import (
@@ -108,7 +111,11 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
+	return writeTo(townRoot, event)
+}
 
+// writeTo appends an event to the events file in townRoot.
+func writeTo(townRoot string, event Event) error {
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
 	// Marshal event to JSON
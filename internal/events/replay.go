@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// replayWait sleeps for d or until ctx is done. Tests replace it.
+var replayWait = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Replay calls fn for each event in order, waiting before each for the time
+// that separated it from the previous event divided by speed, so the stream
+// plays back at its original pace (speed 1) or faster. Waits are capped at
+// maxGap when it is positive, so quiet stretches don't stall a review; speed
+// <= 0 replays without waiting. Events with unparseable timestamps are
+// played immediately. Replay stops at the first error from fn, or when ctx
+// is done.
+func Replay(ctx context.Context, events []Event, speed float64, maxGap time.Duration, fn func(Event) error) error {
+	var prev time.Time
+	for _, e := range events {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err == nil {
+			if !prev.IsZero() && speed > 0 {
+				if wait := replayDelay(ts.Sub(prev), speed, maxGap); wait > 0 {
+					if err := replayWait(ctx, wait); err != nil {
+						return err
+					}
+				}
+			}
+			prev = ts
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayDelay scales the gap between two events for playback at speed,
+// capped at maxGap when it is positive.
+func replayDelay(gap time.Duration, speed float64, maxGap time.Duration) time.Duration {
+	if gap <= 0 {
+		return 0
+	}
+	wait := time.Duration(float64(gap) / speed)
+	if maxGap > 0 && wait > maxGap {
+		wait = maxGap
+	}
+	return wait
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	var waits []time.Duration
+	orig := replayWait
+	replayWait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { replayWait = orig }()
+
+	evs := []Event{
+		{Timestamp: "2026-01-02T03:00:00Z", Type: "a"},
+		{Timestamp: "2026-01-02T03:00:10Z", Type: "b"},
+		{Timestamp: "2026-01-02T03:00:10Z", Type: "c"},
+		{Timestamp: "2026-01-02T04:00:10Z", Type: "d"},
+	}
+	var played []string
+	err := Replay(context.Background(), evs, 10, 5*time.Second, func(e Event) error {
+		played = append(played, e.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(played, []string{"a", "b", "c", "d"}) {
+		t.Errorf("played %v", played)
+	}
+	// 10s at 10x is 1s; simultaneous events don't wait; the hour-long gap is capped.
+	if !slices.Equal(waits, []time.Duration{time.Second, 5 * time.Second}) {
+		t.Errorf("waits = %v, want [1s 5s]", waits)
+	}
+
+	waits = nil
+	if err := Replay(context.Background(), evs, 0, 0, func(Event) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 0 {
+		t.Errorf("speed 0 waited %v", waits)
+	}
+}
+
+func TestReplay_StopsOnError(t *testing.T) {
+	stop := errors.New("sink closed")
+	n := 0
+	err := Replay(context.Background(), []Event{{Type: "a"}, {Type: "b"}}, 0, 0, func(Event) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Errorf("Replay = %v after %d events, want sink error after 1", err, n)
+	}
+}
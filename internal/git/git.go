@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -369,6 +370,13 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// DetachHead detaches HEAD at the current commit, so the branch that was
+// checked out can be checked out in another worktree.
+func (g *Git) DetachHead() error {
+	_, err := g.run("checkout", "--detach")
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -602,6 +610,31 @@ func (g *Git) Remotes() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// HooksDir returns the absolute path of the directory git runs hooks from,
+// which core.hooksPath may point outside the git directory.
+func (g *Git) HooksDir() (string, error) {
+	return g.absGitPath("--git-path", "hooks")
+}
+
+// CommonDir returns the absolute path of the git directory shared by all
+// worktrees of the repository.
+func (g *Git) CommonDir() (string, error) {
+	return g.absGitPath("--git-common-dir")
+}
+
+// absGitPath runs git rev-parse with args and makes the path it prints
+// absolute (git may print it relative to the working directory).
+func (g *Git) absGitPath(args ...string) (string, error) {
+	out, err := g.run(append([]string{"rev-parse"}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(out) {
+		return filepath.Clean(out), nil
+	}
+	return filepath.Join(g.workDir, out), nil
+}
+
 // ConfigGet returns the value of a git config key.
 // Returns empty string if the key is not set.
 func (g *Git) ConfigGet(key string) (string, error) {
@@ -652,6 +685,16 @@ func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	return err
 }
 
+// CreateTag creates tag name at ref. A non-empty message makes an annotated tag.
+func (g *Git) CreateTag(name, ref, message string) error {
+	args := []string{"tag", name, ref}
+	if message != "" {
+		args = []string{"tag", "-a", "-m", message, name, ref}
+	}
+	_, err := g.run(args...)
+	return err
+}
+
 // ListRemoteRefs returns remote ref names matching a prefix using ls-remote.
 // The prefix filters refs (e.g., "refs/heads/polecat/" for all polecat branches).
 // Returns full ref names like "refs/heads/polecat/furiosa-abc123".
@@ -684,6 +727,13 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// RebaseOnto replays the commits of branch after upstream onto newBase
+// (git rebase --onto newBase upstream branch), leaving branch checked out.
+func (g *Git) RebaseOnto(newBase, upstream, branch string) error {
+	_, err := g.run("rebase", "--onto", newBase, upstream, branch)
+	return err
+}
+
 // AbortMerge aborts a merge in progress.
 func (g *Git) AbortMerge() error {
 	_, err := g.run("merge", "--abort")
@@ -919,6 +969,15 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// CommitTime returns the committer date of the commit a ref (e.g., a tag) points to.
+func (g *Git) CommitTime(ref string) (time.Time, error) {
+	out, err := g.run("log", "-1", "--format=%cI", ref+"^{commit}", "--")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -932,6 +991,93 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	return true, nil
 }
 
+// MergeBase returns the best common ancestor of two commits.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.run("merge-base", a, b)
+}
+
+// ShowFile returns the contents of path as of ref, untrimmed.
+func (g *Git) ShowFile(ref, path string) ([]byte, error) {
+	args := []string{"show", ref + ":" + path}
+	if g.gitDir != "" {
+		args = append([]string{"--git-dir=" + g.gitDir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	if g.workDir != "" {
+		cmd.Dir = g.workDir
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, g.wrapError(err, stdout.String(), stderr.String(), args)
+	}
+	return stdout.Bytes(), nil
+}
+
+// CommitFile creates a commit whose tree is baseRef's tree with path replaced
+// by content, without touching the index or working tree. The new commit's
+// parents are given explicitly (pass two for a merge). Returns the commit hash;
+// no ref is moved.
+func (g *Git) CommitFile(baseRef, path string, content []byte, message string, parents ...string) (string, error) {
+	blob, err := g.runWithInput(content, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return "", err
+	}
+
+	index, err := os.CreateTemp("", "gt-index-*")
+	if err != nil {
+		return "", err
+	}
+	indexPath := index.Name()
+	_ = index.Close()
+	_ = os.Remove(indexPath) // git read-tree creates it
+	defer func() { _ = os.Remove(indexPath) }()
+	env := []string{"GIT_INDEX_FILE=" + indexPath}
+
+	if _, err := g.runWithEnv([]string{"read-tree", baseRef}, env); err != nil {
+		return "", err
+	}
+	if _, err := g.runWithEnv([]string{"update-index", "--add", "--cacheinfo", "100644," + blob + "," + path}, env); err != nil {
+		return "", err
+	}
+	tree, err := g.runWithEnv([]string{"write-tree"}, env)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+	return g.run(args...)
+}
+
+// UpdateRef moves ref to newValue, failing if it no longer points at oldValue.
+func (g *Git) UpdateRef(ref, newValue, oldValue string) error {
+	_, err := g.run("update-ref", ref, newValue, oldValue)
+	return err
+}
+
+// runWithInput executes a git command with input on stdin and returns stdout.
+func (g *Git) runWithInput(input []byte, args ...string) (string, error) {
+	if g.gitDir != "" {
+		args = append([]string{"--git-dir=" + g.gitDir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	if g.workDir != "" {
+		cmd.Dir = g.workDir
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
 // WorktreeAdd creates a new worktree at the given path with a new branch.
 // The new branch is created from the current HEAD.
 func (g *Git) WorktreeAdd(path, branch string) error {
@@ -1112,6 +1258,76 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// CommitSubjects returns the subject lines of commits in branch but not in
+// base, oldest first.
+func (g *Git) CommitSubjects(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--reverse", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// DiffShortStat returns git's one-line change summary for branch relative to
+// its merge base with base (e.g., "3 files changed, 40 insertions(+)").
+func (g *Git) DiffShortStat(base, branch string) (string, error) {
+	return g.run("diff", "--shortstat", base+"..."+branch)
+}
+
+// ChangedFiles returns the paths changed on branch since its merge base with base.
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// AddNote attaches message to commit under the notes ref (e.g.,
+// "refs/notes/provenance"), replacing any note already there.
+func (g *Git) AddNote(notesRef, commit, message string) error {
+	_, err := g.runWithInput([]byte(message), "notes", "--ref="+notesRef, "add", "-f", "-F", "-", commit)
+	return err
+}
+
+// Note returns the note attached to commit under the notes ref. found is
+// false when the commit has no note.
+func (g *Git) Note(notesRef, commit string) (note string, found bool, err error) {
+	// "notes list <commit>" exits non-zero when there is no note, so check
+	// it first to tell "no note" apart from real failures of "notes show".
+	if _, err := g.run("notes", "--ref="+notesRef, "list", commit); err != nil {
+		if _, revErr := g.run("rev-parse", "--verify", commit+"^{commit}"); revErr != nil {
+			return "", false, revErr
+		}
+		return "", false, nil
+	}
+	note, err = g.run("notes", "--ref="+notesRef, "show", commit)
+	if err != nil {
+		return "", false, err
+	}
+	return note, true, nil
+}
+
+// FetchNotes replaces the local notes ref with the remote's. Notes are not
+// fetched by default, so clones must ask for them.
+func (g *Git) FetchNotes(remote, notesRef string) error {
+	_, err := g.run("fetch", remote, "+"+notesRef+":"+notesRef)
+	return err
+}
+
+// PushNotes pushes the notes ref to remote. It fails if the remote ref has
+// notes the local one lacks; fetch, re-add and push again.
+func (g *Git) PushNotes(remote, notesRef string) error {
+	_, err := g.run("push", remote, notesRef+":"+notesRef)
+	return err
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -1625,6 +1841,37 @@ func (g *Git) PushSubmoduleCommit(submodulePath, sha, remote string) error {
 	return nil
 }
 
+// CheckoutSubmodule fetches remote in the submodule at submodulePath, checks
+// out ref (a commit, tag, or branch of remote) detached, and stages the new
+// pointer in the parent repo.
+func (g *Git) CheckoutSubmodule(submodulePath, ref, remote string) error {
+	absPath := filepath.Join(g.workDir, submodulePath)
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", absPath}, args...)...) //nolint:gosec // G204: args are constructed internally
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}
+	if _, err := run("fetch", "--tags", remote); err != nil {
+		return fmt.Errorf("fetching submodule %s: %w", submodulePath, err)
+	}
+	// Prefer the remote's branch of that name over a stale local one
+	sha, err := run("rev-parse", "--verify", "--quiet", remote+"/"+ref+"^{commit}")
+	if err != nil {
+		if sha, err = run("rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+			return fmt.Errorf("submodule %s has no commit %s", submodulePath, ref)
+		}
+	}
+	if _, err := run("checkout", "--detach", sha); err != nil {
+		return fmt.Errorf("checking out %s in submodule %s: %w", ref, submodulePath, err)
+	}
+	return g.Add(submodulePath)
+}
+
 // submoduleDefaultBranch detects the default branch of a submodule's remote.
 // Tries local refs first to avoid network round-trips, falling back to remote queries.
 func submoduleDefaultBranch(submodulePath, remote string) (string, error) {
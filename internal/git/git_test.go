@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -298,6 +299,29 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestCommitTime(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	cmd := exec.Command("git", "tag", "-a", "v1.0.0", "-m", "release")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git tag: %v", err)
+	}
+
+	when, err := g.CommitTime("v1.0.0")
+	if err != nil {
+		t.Fatalf("CommitTime: %v", err)
+	}
+	if when.IsZero() || time.Since(when) > time.Hour {
+		t.Errorf("CommitTime = %v, want a recent time", when)
+	}
+
+	if _, err := g.CommitTime("no-such-tag"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
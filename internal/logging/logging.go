@@ -0,0 +1,197 @@
+// Package logging provides structured, per-module logging for gt.
+//
+// Each subsystem takes a logger once:
+//
+//	var logger = logging.For("doltserver")
+//
+// Records at or above a module's level (default info) go to the town's
+// rotated log file, logs/gt.log, as JSON lines. Warnings and errors are
+// also printed to stderr, as gt always has. Levels are set per module in
+// town settings (gt config set log.doltserver=debug) or, for one command,
+// with GT_LOG=doltserver=debug,cmd=info.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileName is the module log in the town's logs directory.
+const FileName = "gt.log"
+
+// DefaultModule names the level used by modules without their own.
+const DefaultModule = "default"
+
+// EnvVar overrides configured levels: comma-separated module=level pairs.
+const EnvVar = "GT_LOG"
+
+// LevelOff silences a module, including its warnings on stderr.
+const LevelOff = slog.Level(100)
+
+var (
+	mu     sync.RWMutex
+	levels = map[string]slog.Level{}
+	sink   = &fileSink{}
+
+	// stderr is where warnings and errors are printed; nil means os.Stderr
+	// as it is at the time (overridable in tests).
+	stderr io.Writer
+)
+
+// Init opens the town's log file and applies per-module levels from
+// config (module → level name), with GT_LOG taking precedence. Loggers
+// taken with For before Init pick up the change.
+func Init(townRoot string, config map[string]string) error {
+	parsed := map[string]slog.Level{}
+	for module, name := range config {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("log.%s: %w", module, err)
+		}
+		parsed[module] = level
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		for _, pair := range strings.Split(env, ",") {
+			module, name, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				module, name = DefaultModule, pair
+			}
+			level, err := ParseLevel(name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", EnvVar, err)
+			}
+			parsed[strings.TrimSpace(module)] = level
+		}
+	}
+
+	mu.Lock()
+	levels = parsed
+	mu.Unlock()
+	if townRoot == "" {
+		return nil
+	}
+	return sink.open(filepath.Join(townRoot, "logs", FileName))
+}
+
+// Close closes the log file; later records go to stderr only.
+func Close() error {
+	return sink.close()
+}
+
+// ParseLevel parses a level name: debug, info, warn, error, or off.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "off", "none":
+		return LevelOff, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn, error, or off)", name)
+}
+
+// LevelName returns the name ParseLevel accepts for level.
+func LevelName(level slog.Level) string {
+	if level >= LevelOff {
+		return "off"
+	}
+	return strings.ToLower(level.String())
+}
+
+// Level returns the level in effect for module.
+func Level(module string) slog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	if level, ok := levels[module]; ok {
+		return level
+	}
+	if level, ok := levels[DefaultModule]; ok {
+		return level
+	}
+	return slog.LevelInfo
+}
+
+// For returns the logger for module.
+func For(module string) *slog.Logger {
+	return slog.New(&handler{
+		module: module,
+		file: slog.NewJSONHandler(sink, &slog.HandlerOptions{Level: slog.LevelDebug}).
+			WithAttrs([]slog.Attr{slog.String("module", module)}),
+	})
+}
+
+// handler sends a record to the log file at the module's level, and
+// warnings and errors to stderr as well.
+type handler struct {
+	module string
+	file   slog.Handler
+	attrs  []slog.Attr // for the stderr line
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	if level < Level(h.module) {
+		return false
+	}
+	return level >= slog.LevelWarn || sink.active()
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		printRecord(r, h.attrs)
+	}
+	if sink.active() {
+		return h.file.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{
+		module: h.module,
+		file:   h.file.WithAttrs(attrs),
+		attrs:  append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{module: h.module, file: h.file.WithGroup(name), attrs: h.attrs}
+}
+
+// printRecord writes a warning or error to stderr the way gt prints them:
+// "Warning: message key=value ...".
+func printRecord(r slog.Record, attrs []slog.Attr) {
+	prefix := "Warning"
+	if r.Level >= slog.LevelError {
+		prefix = "Error"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", prefix, r.Message)
+	add := func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value.Resolve())
+		return true
+	}
+	for _, a := range attrs {
+		add(a)
+	}
+	r.Attrs(add)
+	b.WriteByte('\n')
+
+	mu.RLock()
+	w := stderr
+	mu.RUnlock()
+	if w == nil {
+		w = os.Stderr
+	}
+	_, _ = io.WriteString(w, b.String())
+}
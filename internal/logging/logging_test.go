@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setup(t *testing.T, config map[string]string) (string, *bytes.Buffer) {
+	t.Helper()
+	townRoot := t.TempDir()
+	var errOut bytes.Buffer
+	orig := stderr
+	stderr = &errOut
+	t.Cleanup(func() {
+		stderr = orig
+		_ = Close()
+		levels = map[string]slog.Level{}
+	})
+	t.Setenv(EnvVar, "")
+	if err := Init(townRoot, config); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(townRoot, "logs", FileName), &errOut
+}
+
+func readRecords(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("bad log line %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestModuleLevels(t *testing.T) {
+	path, errOut := setup(t, map[string]string{"doltserver": "debug", "doctor": "off"})
+
+	For("doltserver").Debug("dialing", "port", 3307)
+	For("cmd").Debug("dropped at the default info level")
+	For("cmd").Info("run", "command", "gt status")
+	For("doctor").Warn("silenced")
+	For("doltserver").Warn("stale PID file", "pid", 42)
+
+	records := readRecords(t, path)
+	if len(records) != 3 {
+		t.Fatalf("records = %v, want 3", records)
+	}
+	if records[0]["module"] != "doltserver" || records[0]["msg"] != "dialing" || records[0]["port"] != float64(3307) {
+		t.Errorf("record = %v", records[0])
+	}
+	if records[1]["module"] != "cmd" || records[1]["level"] != "INFO" {
+		t.Errorf("record = %v", records[1])
+	}
+	if got := errOut.String(); got != "Warning: stale PID file pid=42\n" {
+		t.Errorf("stderr = %q", got)
+	}
+}
+
+func TestEnvOverride(t *testing.T) {
+	t.Setenv(EnvVar, "cmd=debug,warn")
+	if err := Init("", map[string]string{"cmd": "error"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { levels = map[string]slog.Level{} })
+	if Level("cmd") != slog.LevelDebug || Level("doltserver") != slog.LevelWarn {
+		t.Errorf("levels = cmd %v, doltserver %v", Level("cmd"), Level("doltserver"))
+	}
+
+	t.Setenv(EnvVar, "cmd=loud")
+	if err := Init("", nil); err == nil {
+		t.Error("Init accepted a bad level")
+	}
+}
+
+func TestWarningsWithoutFile(t *testing.T) {
+	var errOut bytes.Buffer
+	orig := stderr
+	stderr = &errOut
+	t.Cleanup(func() { stderr = orig })
+
+	For("doltserver").With("db", "hq").Error("commit failed")
+	For("doltserver").Info("not printed")
+	if got := errOut.String(); got != "Error: commit failed db=hq\n" {
+		t.Errorf("stderr = %q", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	origSize, origKeep := MaxSize, Keep
+	MaxSize, Keep = 200, 2
+	t.Cleanup(func() { MaxSize, Keep = origSize, origKeep })
+	path, _ := setup(t, nil)
+
+	logger := For("cmd")
+	for i := 0; i < 20; i++ {
+		logger.Info("a record long enough to rotate the log file quickly", "i", i)
+	}
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(p), err)
+		}
+		if info.Size() > MaxSize {
+			t.Errorf("%s is %d bytes, over %d", filepath.Base(p), info.Size(), MaxSize)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("kept more than 2 rotated files")
+	}
+}
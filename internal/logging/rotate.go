@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Rotation: when the log reaches MaxSize it is renamed to gt.log.1 (older
+// ones shift up to gt.log.<Keep>) and a new one is started.
+var (
+	MaxSize int64 = 10 << 20
+	Keep          = 3
+)
+
+// fileSink is the log file shared by every module's logger. Until it is
+// opened, writes are dropped.
+type fileSink struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+func (s *fileSink) open(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f != nil {
+		if s.path == path {
+			return nil
+		}
+		_ = s.f.Close()
+		s.f = nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	s.path = path
+	return s.reopen()
+}
+
+// reopen opens s.path for appending. The caller holds s.mu.
+func (s *fileSink) reopen() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: logs are readable like the town's other logs
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	s.f, s.size = f, info.Size()
+	return nil
+}
+
+func (s *fileSink) active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f != nil
+}
+
+func (s *fileSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// Write appends p, rotating first if it would take the file past MaxSize.
+// Each record is one Write, so records from concurrent gt processes do not
+// interleave.
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return len(p), nil
+	}
+	if s.size > 0 && s.size+int64(len(p)) > MaxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.f.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// rotate shifts gt.log → gt.log.1 → ... → gt.log.<Keep>, dropping the
+// oldest, and starts a new file. The caller holds s.mu.
+func (s *fileSink) rotate() error {
+	_ = s.f.Close()
+	s.f = nil
+	// Another process may have rotated already; only rotate a full file.
+	if info, err := os.Stat(s.path); err == nil && info.Size() >= MaxSize/2 {
+		for i := Keep - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		}
+		if Keep > 0 {
+			_ = os.Rename(s.path, s.path+".1")
+		} else {
+			_ = os.Remove(s.path)
+		}
+	}
+	return s.reopen()
+}
@@ -0,0 +1,140 @@
+// Package metrics renders Gas Town metrics in the Prometheus text exposition
+// format (version 0.0.4). It has no client library dependency: collectors
+// build Families and Write renders them. It also keeps a small local
+// history of town metrics for trends (see SeriesFile), so sparklines work
+// without a Prometheus server.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Content-Type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types.
+const (
+	Gauge     = "gauge"
+	Counter   = "counter"
+	Histogram = "histogram"
+)
+
+// Labels are a sample's label pairs. They are rendered sorted by name.
+type Labels map[string]string
+
+// Sample is one value of a gauge or counter.
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// HistogramSample is one histogram series: cumulative bucket counts for the
+// family's upper bounds, plus the sum and count of observations.
+type HistogramSample struct {
+	Labels  Labels
+	Buckets []uint64 // cumulative, one per Family.Buckets bound
+	Sum     float64
+	Count   uint64
+}
+
+// Family is a named metric with its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+
+	// Histograms only.
+	Buckets    []float64 // upper bounds, ascending; +Inf is implied
+	Histograms []HistogramSample
+}
+
+// Add appends a sample to a gauge or counter family.
+func (f *Family) Add(value float64, labels Labels) {
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// Observe appends a histogram series built from observations.
+func (f *Family) Observe(values []float64, labels Labels) {
+	h := HistogramSample{Labels: labels, Buckets: make([]uint64, len(f.Buckets))}
+	for _, v := range values {
+		h.Sum += v
+		h.Count++
+		for i, bound := range f.Buckets {
+			if v <= bound {
+				h.Buckets[i]++
+			}
+		}
+	}
+	f.Histograms = append(f.Histograms, h)
+}
+
+// Write renders families in the text exposition format.
+func Write(w io.Writer, families []*Family) error {
+	var b strings.Builder
+	for _, f := range families {
+		if len(f.Samples) == 0 && len(f.Histograms) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			fmt.Fprintf(&b, "%s%s %s\n", f.Name, formatLabels(s.Labels, "", ""), formatValue(s.Value))
+		}
+		for _, h := range f.Histograms {
+			for i, bound := range f.Buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", f.Name, formatLabels(h.Labels, "le", formatValue(bound)), h.Buckets[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", f.Name, formatLabels(h.Labels, "le", "+Inf"), h.Count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", f.Name, formatLabels(h.Labels, "", ""), formatValue(h.Sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", f.Name, formatLabels(h.Labels, "", ""), h.Count)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels renders {a="1",b="2"}, with an extra pair (e.g. le) last.
+func formatLabels(labels Labels, extraName, extraValue string) string {
+	if len(labels) == 0 && extraName == "" {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(labels[name])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	depth := &Family{Name: "gastown_mq_depth", Type: Gauge, Help: "Merge requests in the queue."}
+	depth.Add(3, Labels{"state": "ready", "rig": "gastown"})
+	depth.Add(1, Labels{"state": "blocked", "rig": `we"ird`})
+	empty := &Family{Name: "gastown_unused", Type: Gauge, Help: "Never set."}
+	up := &Family{Name: "gastown_dolt_up", Type: Gauge, Help: "Up."}
+	up.Add(1, nil)
+
+	var b strings.Builder
+	if err := Write(&b, []*Family{depth, empty, up}); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP gastown_mq_depth Merge requests in the queue.
+# TYPE gastown_mq_depth gauge
+gastown_mq_depth{rig="gastown",state="ready"} 3
+gastown_mq_depth{rig="we\"ird",state="blocked"} 1
+# HELP gastown_dolt_up Up.
+# TYPE gastown_dolt_up gauge
+gastown_dolt_up 1
+`
+	if b.String() != want {
+		t.Errorf("Write =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := &Family{Name: "latency_seconds", Type: Histogram, Help: "Latency.", Buckets: []float64{60, 3600}}
+	h.Observe([]float64{30, 60, 120, 7200}, Labels{"rig": "gastown"})
+	h.Observe(nil, Labels{"rig": "idle"})
+
+	var b strings.Builder
+	if err := Write(&b, []*Family{h}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`latency_seconds_bucket{rig="gastown",le="60"} 2`,
+		`latency_seconds_bucket{rig="gastown",le="3600"} 3`,
+		`latency_seconds_bucket{rig="gastown",le="+Inf"} 4`,
+		`latency_seconds_sum{rig="gastown"} 7410`,
+		`latency_seconds_count{rig="gastown"} 4`,
+		`latency_seconds_count{rig="idle"} 0`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("missing %q in\n%s", line, b.String())
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SeriesFile is the town's metrics history, relative to the town root: one
+// JSON Point per line, oldest first. It is small enough to read whole; the
+// sampler prunes points past the retention period.
+const SeriesFile = "daemon/metrics.jsonl"
+
+// DefaultRetention is how long samples are kept.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Series recorded by 'gt town metrics sample'. Per-rig values use SeriesKey.
+const (
+	SeriesMQDepth   = "mq_depth"   // Merge requests ready, blocked or in flight
+	SeriesBeadsOpen = "beads_open" // Beads not closed
+	SeriesDoltBytes = "dolt_bytes" // Dolt databases on disk
+	SeriesAgents    = "agents"     // Agent sessions with a live agent
+)
+
+// Point is one sample of every series, by key.
+type Point struct {
+	Time   time.Time          `json:"t"`
+	Values map[string]float64 `json:"v"`
+}
+
+// SeriesKey returns the key of a series for one rig, or the town-wide key
+// when rig is empty.
+func SeriesKey(series, rig string) string {
+	if rig == "" {
+		return series
+	}
+	return series + "/" + rig
+}
+
+// SeriesPath returns the metrics history file of a town.
+func SeriesPath(townRoot string) string {
+	return filepath.Join(townRoot, SeriesFile)
+}
+
+// AppendPoint adds p to the history at path.
+func AppendPoint(path string, p Point) error {
+	line, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: metrics are not secret
+	if err != nil {
+		return fmt.Errorf("opening metrics history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing metrics history: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadPoints returns the points at path taken at or after since, oldest
+// first. A missing history has no points; malformed lines are skipped.
+func LoadPoints(path string, since time.Time) ([]Point, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening metrics history: %w", err)
+	}
+	defer f.Close()
+
+	var points []Point
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var p Point
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil || p.Time.IsZero() {
+			continue
+		}
+		if !p.Time.Before(since) {
+			points = append(points, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics history: %w", err)
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
+
+// PrunePoints drops the points at path older than retention before now and
+// returns how many were dropped. The file is only rewritten when something
+// is dropped.
+func PrunePoints(path string, retention time.Duration, now time.Time) (int, error) {
+	all, err := LoadPoints(path, time.Time{})
+	if err != nil || len(all) == 0 {
+		return 0, err
+	}
+	cutoff := now.Add(-retention)
+	keep := sort.Search(len(all), func(i int) bool { return !all[i].Time.Before(cutoff) })
+	if keep == 0 {
+		return 0, nil
+	}
+
+	var b strings.Builder
+	for _, p := range all[keep:] {
+		line, err := json.Marshal(p)
+		if err != nil {
+			return 0, err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := util.AtomicWriteFile(path, []byte(b.String()), 0644); err != nil {
+		return 0, fmt.Errorf("pruning metrics history: %w", err)
+	}
+	return keep, nil
+}
+
+// SeriesValues returns the values of key across points, skipping points
+// that lack it.
+func SeriesValues(points []Point, key string) []float64 {
+	var values []float64
+	for _, p := range points {
+		if v, ok := p.Values[key]; ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as at most width block characters, averaging
+// neighbours when there are more values than columns. Each column is
+// scaled between the smallest and largest value; a flat series is drawn
+// at the bottom.
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			start := i * len(values) / width
+			end := (i + 1) * len(values) / width
+			sum := 0.0
+			for _, v := range values[start:end] {
+				sum += v
+			}
+			buckets[i] = sum / float64(end-start)
+		}
+		values = buckets
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// seriesLabels are the recorded series in display order.
+var seriesLabels = []struct{ series, label string }{
+	{SeriesMQDepth, "MQ depth"},
+	{SeriesBeadsOpen, "Open beads"},
+	{SeriesDoltBytes, "Dolt size"},
+	{SeriesAgents, "Agents"},
+}
+
+// Trend summarizes one series over a window of points.
+type Trend struct {
+	Series    string  `json:"series"`
+	Label     string  `json:"label"`
+	Sparkline string  `json:"sparkline"`
+	Latest    float64 `json:"latest"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Samples   int     `json:"samples"`
+}
+
+// Trends summarizes each series of points for rig (town-wide when empty)
+// with sparklines of at most width columns. Series without samples are
+// left out.
+func Trends(points []Point, rig string, width int) []Trend {
+	var trends []Trend
+	for _, s := range seriesLabels {
+		values := SeriesValues(points, SeriesKey(s.series, rig))
+		if len(values) == 0 {
+			continue
+		}
+		t := Trend{
+			Series:    s.series,
+			Label:     s.label,
+			Sparkline: Sparkline(values, width),
+			Latest:    values[len(values)-1],
+			Min:       values[0],
+			Max:       values[0],
+			Samples:   len(values),
+		}
+		for _, v := range values {
+			t.Min = math.Min(t.Min, v)
+			t.Max = math.Max(t.Max, v)
+		}
+		trends = append(trends, t)
+	}
+	return trends
+}
+
+// Format renders a value of t's series: sizes in bytes as KB, MB, ...,
+// counts as integers.
+func (t Trend) Format(v float64) string {
+	if t.Series != SeriesDoltBytes {
+		return fmt.Sprintf("%.0f", v)
+	}
+	const unit = 1024
+	if v < unit {
+		return fmt.Sprintf("%.0f B", v)
+	}
+	div, exp := float64(unit), 0
+	for n := v / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", v/div, "KMGTPE"[exp])
+}
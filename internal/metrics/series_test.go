@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSeriesHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon", "metrics.jsonl")
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	if points, err := LoadPoints(path, time.Time{}); err != nil || points != nil {
+		t.Fatalf("missing history = %v, %v", points, err)
+	}
+	for i, depth := range []float64{4, 2, 7} {
+		p := Point{Time: now.Add(time.Duration(i-2) * 24 * time.Hour), Values: map[string]float64{
+			SeriesMQDepth:                       depth,
+			SeriesKey(SeriesMQDepth, "gastown"): depth,
+		}}
+		if err := AppendPoint(path, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+
+	points, err := LoadPoints(path, now.Add(-36*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := SeriesValues(points, SeriesKey(SeriesMQDepth, "gastown")); len(got) != 2 || got[0] != 2 || got[1] != 7 {
+		t.Errorf("values since 36h = %v, want [2 7]", got)
+	}
+
+	pruned, err := PrunePoints(path, 12*time.Hour, now)
+	if err != nil || pruned != 2 {
+		t.Fatalf("PrunePoints = %d, %v; want 2", pruned, err)
+	}
+	if pruned, _ := PrunePoints(path, 12*time.Hour, now); pruned != 0 {
+		t.Errorf("second prune dropped %d", pruned)
+	}
+	points, _ = LoadPoints(path, time.Time{})
+	if len(points) != 1 || points[0].Values[SeriesMQDepth] != 7 {
+		t.Errorf("after prune = %+v", points)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{nil, 10, ""},
+		{[]float64{0, 7}, 10, "▁█"},
+		{[]float64{3, 3, 3}, 10, "▁▁▁"},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 8, "▁▂▃▄▅▆▇█"},
+		{[]float64{0, 0, 7, 7}, 2, "▁█"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTrends(t *testing.T) {
+	points := []Point{
+		{Values: map[string]float64{SeriesDoltBytes: 1 << 20, SeriesAgents: 3}},
+		{Values: map[string]float64{SeriesDoltBytes: 3 << 20, SeriesAgents: 5}},
+		{Values: map[string]float64{SeriesDoltBytes: 2 << 20}},
+	}
+	trends := Trends(points, "", 10)
+	if len(trends) != 2 || trends[0].Series != SeriesDoltBytes || trends[1].Series != SeriesAgents {
+		t.Fatalf("trends = %+v", trends)
+	}
+	dolt := trends[0]
+	if dolt.Format(dolt.Latest) != "2.0 MB" || dolt.Format(dolt.Max) != "3.0 MB" || dolt.Samples != 3 {
+		t.Errorf("dolt trend = %+v", dolt)
+	}
+	if agents := trends[1]; agents.Format(agents.Latest) != "5" || agents.Min != 3 || agents.Sparkline != "▁█" {
+		t.Errorf("agents trend = %+v", agents)
+	}
+	if got := Trends(points, "gastown", 10); got != nil {
+		t.Errorf("rig without series = %+v", got)
+	}
+}
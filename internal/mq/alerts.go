@@ -0,0 +1,243 @@
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// AlertsFileName is the per-rig queue alert configuration, stored under <rig>/settings/.
+const AlertsFileName = "mq-alerts.json"
+
+// alertStateFile records when each alert last fired, under <rig>/.runtime/.
+const alertStateFile = "mq-alerts-state.json"
+
+// Alert kinds.
+const (
+	AlertLowThroughput = "low-throughput" // Queue has work but too few MRs were processed in the window
+	AlertHighWait      = "high-wait"      // Oldest open MR has waited longer than MaxWait
+)
+
+// AlertConfig is the on-disk alert configuration for a rig.
+//
+// Example:
+//
+//	{"window": "1h", "min_processed": 1, "max_wait": "2h", "severity": "high"}
+type AlertConfig struct {
+	// Window is the sliding window throughput is measured over, e.g. "1h".
+	Window string `json:"window"`
+
+	// MinProcessed is the minimum number of MRs that must leave the queue
+	// (merged or closed) within Window while work is waiting. 0 disables
+	// the throughput alert.
+	MinProcessed int `json:"min_processed,omitempty"`
+
+	// MaxWait is the longest an open MR may wait, e.g. "2h". Empty disables
+	// the wait-time alert.
+	MaxWait string `json:"max_wait,omitempty"`
+
+	// Severity of the escalation filed for an alert. Defaults to "high".
+	Severity string `json:"severity,omitempty"`
+
+	// Cooldown suppresses re-filing the same alert, e.g. "4h". Defaults to Window.
+	Cooldown string `json:"cooldown,omitempty"`
+
+	window, maxWait, cooldown time.Duration
+}
+
+// AlertsPath returns the alert configuration path for a rig.
+func AlertsPath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", AlertsFileName)
+}
+
+// LoadAlertConfig loads and validates a rig's alert configuration.
+// Returns nil (and no error) if the rig has no alert configuration.
+func LoadAlertConfig(rigPath string) (*AlertConfig, error) {
+	data, err := os.ReadFile(AlertsPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading alert config: %w", err)
+	}
+
+	var cfg AlertConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing alert config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks the configuration and parses its durations.
+func (c *AlertConfig) Validate() error {
+	var err error
+	if c.Window == "" {
+		return fmt.Errorf("alert config: window is required")
+	}
+	if c.window, err = parsePositiveDuration("window", c.Window); err != nil {
+		return err
+	}
+	if c.MaxWait != "" {
+		if c.maxWait, err = parsePositiveDuration("max_wait", c.MaxWait); err != nil {
+			return err
+		}
+	}
+	c.cooldown = c.window
+	if c.Cooldown != "" {
+		if c.cooldown, err = parsePositiveDuration("cooldown", c.Cooldown); err != nil {
+			return err
+		}
+	}
+	if c.MinProcessed < 0 {
+		return fmt.Errorf("alert config: min_processed must not be negative")
+	}
+	if c.MinProcessed == 0 && c.maxWait == 0 {
+		return fmt.Errorf("alert config: set min_processed and/or max_wait")
+	}
+	switch c.Severity {
+	case "":
+		c.Severity = "high"
+	case "critical", "high", "medium", "low":
+	default:
+		return fmt.Errorf("alert config: invalid severity %q: must be critical, high, medium, or low", c.Severity)
+	}
+	return nil
+}
+
+func parsePositiveDuration(field, s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("alert config: invalid %s %q: %w", field, s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("alert config: %s must be positive, got %v", field, d)
+	}
+	return d, nil
+}
+
+// QueueSample is a point-in-time view of a rig's queue.
+type QueueSample struct {
+	// Waiting maps each open MR to when it entered the queue.
+	Waiting map[string]time.Time
+
+	// Processed holds when each MR that left the queue was closed.
+	Processed []time.Time
+}
+
+// Alert is a threshold breach found by Evaluate.
+type Alert struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	MRID     string `json:"mr_id,omitempty"` // Oldest waiting MR
+}
+
+// Evaluate checks a queue sample against the thresholds.
+func (c *AlertConfig) Evaluate(sample QueueSample, now time.Time) []Alert {
+	if len(sample.Waiting) == 0 {
+		return nil // Nothing waiting: low throughput is just an idle queue
+	}
+
+	// Oldest waiting MR (ties broken by ID for stable output)
+	ids := make([]string, 0, len(sample.Waiting))
+	for id := range sample.Waiting {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ti, tj := sample.Waiting[ids[i]], sample.Waiting[ids[j]]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ids[i] < ids[j]
+	})
+	oldest := ids[0]
+	wait := now.Sub(sample.Waiting[oldest])
+
+	var alerts []Alert
+	if c.MinProcessed > 0 && wait >= c.window {
+		processed := 0
+		for _, t := range sample.Processed {
+			if now.Sub(t) <= c.window {
+				processed++
+			}
+		}
+		if processed < c.MinProcessed {
+			alerts = append(alerts, Alert{
+				Kind:     AlertLowThroughput,
+				Severity: c.Severity,
+				MRID:     oldest,
+				Message: fmt.Sprintf("%d MR(s) processed in the last %v (minimum %d) with %d waiting; refinery may be stuck",
+					processed, c.window, c.MinProcessed, len(sample.Waiting)),
+			})
+		}
+	}
+	if c.maxWait > 0 && wait > c.maxWait {
+		alerts = append(alerts, Alert{
+			Kind:     AlertHighWait,
+			Severity: c.Severity,
+			MRID:     oldest,
+			Message: fmt.Sprintf("%s has waited %v (maximum %v); a test gate may be hanging",
+				oldest, wait.Round(time.Minute), c.maxWait),
+		})
+	}
+	return alerts
+}
+
+// AlertState records when each alert kind last fired for a rig.
+type AlertState struct {
+	LastFired map[string]time.Time `json:"last_fired"`
+}
+
+func alertStatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", alertStateFile)
+}
+
+// LoadAlertState reads a rig's alert state. A missing file yields empty state.
+func LoadAlertState(rigPath string) (*AlertState, error) {
+	state := &AlertState{LastFired: map[string]time.Time{}}
+	data, err := os.ReadFile(alertStatePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing alert state: %w", err)
+	}
+	if state.LastFired == nil {
+		state.LastFired = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// Save writes the alert state for a rig.
+func (s *AlertState) Save(rigPath string) error {
+	path := alertStatePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: runtime state, not secret
+}
+
+// Due filters alerts to those outside their cooldown.
+func (c *AlertConfig) Due(state *AlertState, alerts []Alert, now time.Time) []Alert {
+	var due []Alert
+	for _, a := range alerts {
+		if last, ok := state.LastFired[a.Kind]; ok && now.Sub(last) < c.cooldown {
+			continue
+		}
+		due = append(due, a)
+	}
+	return due
+}
@@ -0,0 +1,130 @@
+package mq
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadAlertConfig(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg, err := LoadAlertConfig(rigPath)
+	if err != nil || cfg != nil {
+		t.Fatalf("missing config = %v, %v; want nil, nil", cfg, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(s string) {
+		if err := os.WriteFile(AlertsPath(rigPath), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"window": "1h", "min_processed": 2, "max_wait": "3h"}`)
+	cfg, err = LoadAlertConfig(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Severity != "high" || cfg.cooldown != time.Hour {
+		t.Errorf("defaults: severity=%q cooldown=%v", cfg.Severity, cfg.cooldown)
+	}
+
+	for _, bad := range []string{
+		`{"min_processed": 1}`,
+		`{"window": "soon", "min_processed": 1}`,
+		`{"window": "1h"}`,
+		`{"window": "1h", "max_wait": "-1h"}`,
+		`{"window": "1h", "min_processed": 1, "severity": "urgent"}`,
+	} {
+		write(bad)
+		if _, err := LoadAlertConfig(rigPath); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestAlertConfig_Evaluate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &AlertConfig{Window: "1h", MinProcessed: 1, MaxWait: "2h"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := func(alerts []Alert) map[string]bool {
+		m := map[string]bool{}
+		for _, a := range alerts {
+			m[a.Kind] = true
+		}
+		return m
+	}
+
+	// Idle queue never alerts.
+	if got := cfg.Evaluate(QueueSample{}, now); len(got) != 0 {
+		t.Errorf("idle queue alerts = %+v", got)
+	}
+
+	// Work waiting 90m and nothing processed in the window: stuck.
+	sample := QueueSample{
+		Waiting:   map[string]time.Time{"gt-mr1": now.Add(-90 * time.Minute), "gt-mr2": now.Add(-10 * time.Minute)},
+		Processed: []time.Time{now.Add(-3 * time.Hour)},
+	}
+	got := cfg.Evaluate(sample, now)
+	if k := kinds(got); !k[AlertLowThroughput] || k[AlertHighWait] {
+		t.Errorf("alerts = %+v", got)
+	}
+	if got[0].MRID != "gt-mr1" {
+		t.Errorf("oldest MR = %q, want gt-mr1", got[0].MRID)
+	}
+
+	// A recent merge satisfies throughput.
+	sample.Processed = append(sample.Processed, now.Add(-20*time.Minute))
+	if got := cfg.Evaluate(sample, now); len(got) != 0 {
+		t.Errorf("healthy queue alerts = %+v", got)
+	}
+
+	// Queue not yet non-empty for a full window: no throughput alert.
+	fresh := QueueSample{Waiting: map[string]time.Time{"gt-mr3": now.Add(-30 * time.Minute)}}
+	if got := cfg.Evaluate(fresh, now); len(got) != 0 {
+		t.Errorf("fresh queue alerts = %+v", got)
+	}
+
+	// Long wait trips the wait-time alert even with throughput.
+	sample.Waiting["gt-mr0"] = now.Add(-3 * time.Hour)
+	if k := kinds(cfg.Evaluate(sample, now)); !k[AlertHighWait] || k[AlertLowThroughput] {
+		t.Errorf("expected only high-wait, got %v", k)
+	}
+}
+
+func TestAlertState_Cooldown(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &AlertConfig{Window: "1h", MinProcessed: 1, Cooldown: "4h"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadAlertState(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts := []Alert{{Kind: AlertLowThroughput}, {Kind: AlertHighWait}}
+	state.LastFired[AlertLowThroughput] = now.Add(-time.Hour)
+	if err := state.Save(rigPath); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadAlertState(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := cfg.Due(reloaded, alerts, now)
+	if len(due) != 1 || due[0].Kind != AlertHighWait {
+		t.Errorf("due = %+v, want only high-wait", due)
+	}
+	if due := cfg.Due(reloaded, alerts, now.Add(4*time.Hour)); len(due) != 2 {
+		t.Errorf("after cooldown due = %+v", due)
+	}
+}
@@ -0,0 +1,267 @@
+package mq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// Commit status providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// DefaultStatusContext names the status on the hosting side. Branch
+// protection rules require it by this name.
+const DefaultStatusContext = "gastown/merge-queue"
+
+// maxStatusDescription is GitHub's limit on a status description.
+const maxStatusDescription = 140
+
+// CommitStatusConfig publishes queue state as a commit status on the head
+// of each MR's branch, so branch protection and the hosting side's own
+// views follow the refinery. It is the "commit_status" section of the rig's
+// webhook configuration file.
+type CommitStatusConfig struct {
+	// Provider is "github" or "gitlab".
+	Provider string `json:"provider"`
+
+	// Repo is the GitHub "owner/repo", or the GitLab project path or ID.
+	Repo string `json:"repo"`
+
+	// APIURL overrides the API base for GitHub Enterprise or self-hosted
+	// GitLab. Defaults to https://api.github.com or https://gitlab.com/api/v4.
+	APIURL string `json:"api_url,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token. Use a
+	// token scoped to commit statuses. Defaults to GITHUB_TOKEN or GITLAB_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// TokenSecret names the town secret holding the API token (gt secret
+	// set), used instead of TokenEnv when set.
+	TokenSecret string `json:"token_secret,omitempty"`
+
+	// Context is the status name. Defaults to DefaultStatusContext.
+	Context string `json:"context,omitempty"`
+
+	// TargetURL links the status somewhere useful; {rig} and {mr} are
+	// replaced with the rig name and MR ID.
+	TargetURL string `json:"target_url,omitempty"`
+
+	// Timeout per request, e.g. "3s". Defaults to 5s.
+	Timeout string `json:"timeout,omitempty"`
+
+	token string // TokenSecret's value, once resolved
+}
+
+// Validate checks the provider, repository, API URL, token variable and
+// secret name, and timeout.
+func (c *CommitStatusConfig) Validate() error {
+	switch c.Provider {
+	case ProviderGitHub:
+		if owner, name, ok := strings.Cut(c.Repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid repo %q: must be owner/repo", c.Repo)
+		}
+	case ProviderGitLab:
+		if c.Repo == "" {
+			return fmt.Errorf("repo is required")
+		}
+	default:
+		return fmt.Errorf("unknown provider %q: must be %s or %s", c.Provider, ProviderGitHub, ProviderGitLab)
+	}
+	if c.APIURL != "" {
+		u, err := url.Parse(c.APIURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid api_url %q: must be an http(s) URL", c.APIURL)
+		}
+	}
+	if c.TokenEnv != "" && strings.ContainsAny(c.TokenEnv, "= ") {
+		return fmt.Errorf("invalid token_env %q: must be a variable name", c.TokenEnv)
+	}
+	if c.TokenSecret != "" {
+		if err := secrets.ValidateName(c.TokenSecret); err != nil {
+			return fmt.Errorf("token_secret: %w", err)
+		}
+	}
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", c.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive, got %v", d)
+		}
+	}
+	return nil
+}
+
+// resolveToken reads the token from TokenSecret, if set.
+func (c *CommitStatusConfig) resolveToken(townRoot string) error {
+	if c.TokenSecret == "" {
+		return nil
+	}
+	token, err := secrets.Get(townRoot, c.TokenSecret)
+	if err != nil {
+		return fmt.Errorf("token_secret: %w", err)
+	}
+	c.token = token
+	return nil
+}
+
+func (c *CommitStatusConfig) tokenEnv() string {
+	if c.TokenEnv != "" {
+		return c.TokenEnv
+	}
+	if c.Provider == ProviderGitLab {
+		return "GITLAB_TOKEN"
+	}
+	return "GITHUB_TOKEN"
+}
+
+func (c *CommitStatusConfig) apiURL() string {
+	if c.APIURL != "" {
+		return strings.TrimSuffix(c.APIURL, "/")
+	}
+	if c.Provider == ProviderGitLab {
+		return "https://gitlab.com/api/v4"
+	}
+	return "https://api.github.com"
+}
+
+func (c *CommitStatusConfig) context() string {
+	if c.Context != "" {
+		return c.Context
+	}
+	return DefaultStatusContext
+}
+
+// commitStatusState maps a queue event to the provider's status state and
+// a description of where the MR stands.
+func commitStatusState(provider, event string, p WebhookPayload) (state, description string) {
+	failed := "failure"
+	running := "pending"
+	if provider == ProviderGitLab {
+		failed, running = "failed", "running"
+	}
+	target := p.Target
+	if target == "" {
+		target = "target"
+	}
+
+	switch event {
+	case EventSubmitted:
+		return "pending", "Queued for merge into " + target
+	case EventMerging:
+		return running, "Refinery is testing the merge into " + target
+	case EventMerged:
+		if p.MergeCommit != "" {
+			return "success", fmt.Sprintf("Merged into %s as %s", target, shortSHA(p.MergeCommit))
+		}
+		return "success", "Merged into " + target
+	case EventConflicted:
+		return failed, "Conflicts with " + target + "; waiting on a resolution"
+	default:
+		if p.Error != "" {
+			return failed, "Merge failed: " + p.Error
+		}
+		return failed, "Merge failed"
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// truncateDescription shortens s to the provider's description limit.
+func truncateDescription(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= maxStatusDescription {
+		return s
+	}
+	return string(r[:maxStatusDescription-1]) + "…"
+}
+
+// publishStatus sets the MR's commit status for event on payload.HeadSHA.
+func (d *Dispatcher) publishStatus(event string, p WebhookPayload) error {
+	c := d.status
+	token := c.token
+	if c.TokenSecret == "" {
+		token = os.Getenv(c.tokenEnv())
+	}
+	if token == "" {
+		if c.TokenSecret != "" {
+			return fmt.Errorf("secret %s is empty", c.TokenSecret)
+		}
+		return fmt.Errorf("%s is not set", c.tokenEnv())
+	}
+
+	state, description := commitStatusState(c.Provider, event, p)
+	targetURL := strings.NewReplacer("{rig}", d.rig, "{mr}", p.MRID).Replace(c.TargetURL)
+	body := map[string]string{
+		"state":       state,
+		"description": truncateDescription(description),
+	}
+	if targetURL != "" {
+		body["target_url"] = targetURL
+	}
+
+	var endpoint string
+	if c.Provider == ProviderGitLab {
+		endpoint = fmt.Sprintf("%s/projects/%s/statuses/%s", c.apiURL(), url.PathEscape(c.Repo), p.HeadSHA)
+		body["name"] = c.context()
+		if p.Branch != "" {
+			body["ref"] = p.Branch
+		}
+	} else {
+		endpoint = fmt.Sprintf("%s/repos/%s/statuses/%s", c.apiURL(), c.Repo, p.HeadSHA)
+		body["context"] = c.context()
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-mq")
+	if c.Provider == ProviderGitLab {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	} else {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	timeout := defaultWebhookTimeout
+	if c.Timeout != "" {
+		if t, err := time.ParseDuration(c.Timeout); err == nil && t > 0 {
+			timeout = t
+		}
+	}
+	client := *d.client
+	client.Timeout = timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s status API returned %s", c.Provider, resp.Status)
+	}
+	logger.Debug("commit status published", "rig", d.rig, "event", event, "state", state, "sha", shortSHA(p.HeadSHA))
+	return nil
+}
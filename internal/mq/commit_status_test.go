@@ -0,0 +1,135 @@
+package mq
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type statusRequest struct {
+	path  string
+	auth  string
+	token string
+	body  map[string]string
+}
+
+type statusRecorder struct {
+	mu   sync.Mutex
+	reqs []statusRequest
+}
+
+func (rec *statusRecorder) handler(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body)
+	var body map[string]string
+	_ = json.Unmarshal(data, &body)
+	rec.mu.Lock()
+	rec.reqs = append(rec.reqs, statusRequest{
+		path:  r.URL.EscapedPath(),
+		auth:  r.Header.Get("Authorization"),
+		token: r.Header.Get("PRIVATE-TOKEN"),
+		body:  body,
+	})
+	rec.mu.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestDispatcher_PublishesGitHubStatuses(t *testing.T) {
+	rec := &statusRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+	t.Setenv("GT_TEST_STATUS_TOKEN", "tok")
+
+	d := NewDispatcher("gastown", nil)
+	d.status = &CommitStatusConfig{
+		Provider:  ProviderGitHub,
+		Repo:      "acme/widgets",
+		APIURL:    srv.URL,
+		TokenEnv:  "GT_TEST_STATUS_TOKEN",
+		TargetURL: "https://dash.example.com/{rig}/{mr}",
+	}
+	if !d.PublishesCommitStatus() {
+		t.Fatal("PublishesCommitStatus() = false")
+	}
+
+	p := WebhookPayload{MRID: "gt-mr-1", Branch: "polecat/nux", Target: "main", HeadSHA: "abc123"}
+	for _, event := range []string{EventSubmitted, EventMerging} {
+		if err := d.Dispatch(event, p); err != nil {
+			t.Fatalf("Dispatch %s: %v", event, err)
+		}
+	}
+	p.MergeCommit = "def4567890"
+	if err := d.Dispatch(EventMerged, p); err != nil {
+		t.Fatal(err)
+	}
+	// Without a head there is nothing to put a status on.
+	if err := d.Dispatch(EventSubmitted, WebhookPayload{MRID: "gt-mr-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.reqs) != 3 {
+		t.Fatalf("got %d status requests, want 3", len(rec.reqs))
+	}
+	want := []string{"pending", "pending", "success"}
+	for i, req := range rec.reqs {
+		if req.path != "/repos/acme/widgets/statuses/abc123" || req.auth != "Bearer tok" {
+			t.Errorf("request %d: path %q auth %q", i, req.path, req.auth)
+		}
+		if req.body["state"] != want[i] || req.body["context"] != DefaultStatusContext {
+			t.Errorf("request %d body = %v, want state %s", i, req.body, want[i])
+		}
+		if req.body["target_url"] != "https://dash.example.com/gastown/gt-mr-1" {
+			t.Errorf("request %d target_url = %q", i, req.body["target_url"])
+		}
+	}
+	if got := rec.reqs[2].body["description"]; got != "Merged into main as def45678" {
+		t.Errorf("merged description = %q", got)
+	}
+}
+
+func TestDispatcher_PublishesGitLabStatuses(t *testing.T) {
+	rec := &statusRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+	t.Setenv("GITLAB_TOKEN", "tok")
+
+	d := NewDispatcher("gastown", nil)
+	d.status = &CommitStatusConfig{Provider: ProviderGitLab, Repo: "acme/tools/widgets", APIURL: srv.URL}
+	err := d.Dispatch(EventFailed, WebhookPayload{MRID: "gt-mr-1", Branch: "polecat/nux", HeadSHA: "abc123", Error: "tests failed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.reqs) != 1 {
+		t.Fatalf("got %d status requests, want 1", len(rec.reqs))
+	}
+	req := rec.reqs[0]
+	if req.path != "/projects/acme%2Ftools%2Fwidgets/statuses/abc123" || req.token != "tok" || req.auth != "" {
+		t.Errorf("path %q token %q auth %q", req.path, req.token, req.auth)
+	}
+	if req.body["state"] != "failed" || req.body["name"] != DefaultStatusContext || req.body["ref"] != "polecat/nux" {
+		t.Errorf("body = %v", req.body)
+	}
+}
+
+func TestDispatcher_CommitStatusNeedsToken(t *testing.T) {
+	t.Setenv("GT_TEST_STATUS_TOKEN", "")
+	d := NewDispatcher("gastown", nil)
+	d.status = &CommitStatusConfig{Provider: ProviderGitHub, Repo: "acme/widgets", APIURL: "http://127.0.0.1:1", TokenEnv: "GT_TEST_STATUS_TOKEN"}
+	err := d.Dispatch(EventSubmitted, WebhookPayload{MRID: "gt-mr-1", HeadSHA: "abc123"})
+	if err == nil || !strings.Contains(err.Error(), "GT_TEST_STATUS_TOKEN is not set") {
+		t.Errorf("Dispatch without token = %v, want error naming the variable", err)
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	if got := []rune(truncateDescription("Merge failed: " + long)); len(got) != maxStatusDescription {
+		t.Errorf("truncated to %d runes, want %d", len(got), maxStatusDescription)
+	}
+	if got := truncateDescription("line one\nline  two"); got != "line one line two" {
+		t.Errorf("truncateDescription = %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// freezeFile holds a rig's queue freeze, under <rig>/.runtime/.
+const freezeFile = "mq-freeze.json"
+
+// FreezeState is a merge queue freeze. A frozen queue accepts no new
+// submissions. Without Drain the refinery holds every MR too; with Drain it
+// keeps merging what is already queued, so the queue empties for a release
+// cut. A freeze with an Until deadline lifts itself once it passes.
+type FreezeState struct {
+	// Drain lets the refinery finish in-flight and queued MRs.
+	Drain bool `json:"drain"`
+
+	// Reason explains the freeze (e.g., "cutting v1.4").
+	Reason string `json:"reason,omitempty"`
+
+	// FrozenAt is when the queue was frozen.
+	FrozenAt time.Time `json:"frozen_at"`
+
+	// FrozenBy identifies who froze the queue.
+	FrozenBy string `json:"frozen_by,omitempty"`
+
+	// Until is when the freeze lifts. Zero means it stays until unfrozen.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// FreezePath returns the freeze file path for a rig.
+func FreezePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", freezeFile)
+}
+
+// ActiveFreeze returns the rig's freeze in effect at now, or nil when the
+// queue is not frozen. A freeze whose deadline has passed is removed.
+func ActiveFreeze(rigPath string, now time.Time) (*FreezeState, error) {
+	data, err := os.ReadFile(FreezePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state FreezeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing queue freeze: %w", err)
+	}
+	if state.Expired(now) {
+		_ = Unfreeze(rigPath) // best-effort: a stale file reads as unfrozen anyway
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Freeze writes the rig's freeze, replacing any existing one.
+func Freeze(rigPath string, state *FreezeState) error {
+	path := FreezePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: runtime state, not secret
+}
+
+// Unfreeze lifts the rig's freeze. Lifting an unfrozen queue is not an error.
+func Unfreeze(rigPath string) error {
+	if err := os.Remove(FreezePath(rigPath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Expired reports whether the freeze's deadline has passed at now.
+func (s *FreezeState) Expired(now time.Time) bool {
+	return !s.Until.IsZero() && !now.Before(s.Until)
+}
+
+// Banner is a one-line description of the freeze for status output.
+func (s *FreezeState) Banner(now time.Time) string {
+	msg := "Merge queue FROZEN: not accepting submissions, merges held"
+	if s.Drain {
+		msg = "Merge queue FROZEN (draining): not accepting submissions, queued MRs still merge"
+	}
+	if s.Reason != "" {
+		msg += " — " + s.Reason
+	}
+	if !s.Until.IsZero() {
+		msg += fmt.Sprintf(" (unfreezes in %s)", s.Until.Sub(now).Round(time.Minute))
+	}
+	return msg
+}
+
+// SubmitError is the error returned to a submission while the queue is frozen.
+func (s *FreezeState) SubmitError(rigName string) error {
+	msg := fmt.Sprintf("merge queue for '%s' is frozen", rigName)
+	if s.Reason != "" {
+		msg += ": " + s.Reason
+	}
+	if !s.Until.IsZero() {
+		msg += fmt.Sprintf(" (until %s)", s.Until.Local().Format("2006-01-02 15:04"))
+	}
+	return fmt.Errorf("%s\nRun 'gt mq unfreeze %s' to lift it", msg, rigName)
+}
+
+// ParseDeadline parses a freeze deadline relative to now: a duration
+// ("2h", "90m"), a clock time today or, once passed, tomorrow ("17:30"), or
+// an RFC 3339 timestamp. The deadline must be in the future.
+func ParseDeadline(s string, now time.Time) (time.Time, error) {
+	var until time.Time
+	if d, err := time.ParseDuration(s); err == nil {
+		until = now.Add(d)
+	} else if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		until = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !until.After(now) {
+			until = until.AddDate(0, 0, 1)
+		}
+	} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+		until = t
+	} else {
+		return time.Time{}, fmt.Errorf("invalid deadline %q: want a duration (2h), a time (17:30) or RFC 3339", s)
+	}
+	if !until.After(now) {
+		return time.Time{}, fmt.Errorf("deadline %q is not in the future", s)
+	}
+	return until, nil
+}
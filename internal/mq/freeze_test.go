@@ -0,0 +1,95 @@
+package mq
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFreezeLifecycle(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	freeze, err := ActiveFreeze(rigPath, now)
+	if err != nil || freeze != nil {
+		t.Fatalf("unfrozen queue = %v, %v; want nil, nil", freeze, err)
+	}
+
+	if err := Freeze(rigPath, &FreezeState{Drain: true, Reason: "cutting v1.4", FrozenAt: now, Until: now.Add(2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	freeze, err = ActiveFreeze(rigPath, now.Add(time.Hour))
+	if err != nil || freeze == nil {
+		t.Fatalf("frozen queue = %v, %v; want a freeze", freeze, err)
+	}
+	if !freeze.Drain || freeze.Reason != "cutting v1.4" {
+		t.Errorf("freeze = %+v", freeze)
+	}
+
+	// Past the deadline the freeze lifts and its file is removed.
+	freeze, err = ActiveFreeze(rigPath, now.Add(2*time.Hour))
+	if err != nil || freeze != nil {
+		t.Fatalf("expired freeze = %v, %v; want nil, nil", freeze, err)
+	}
+	if _, err := os.Stat(FreezePath(rigPath)); !os.IsNotExist(err) {
+		t.Errorf("expired freeze file still present: %v", err)
+	}
+
+	// Without a deadline it stays until unfrozen.
+	if err := Freeze(rigPath, &FreezeState{FrozenAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if freeze, _ := ActiveFreeze(rigPath, now.AddDate(1, 0, 0)); freeze == nil {
+		t.Error("freeze without a deadline lifted itself")
+	}
+	if err := Unfreeze(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := Unfreeze(rigPath); err != nil {
+		t.Errorf("unfreezing an unfrozen queue: %v", err)
+	}
+	if freeze, _ := ActiveFreeze(rigPath, now); freeze != nil {
+		t.Error("queue still frozen after Unfreeze")
+	}
+}
+
+func TestFreezeBanner(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	held := (&FreezeState{Reason: "main is broken"}).Banner(now)
+	if !strings.Contains(held, "merges held") || !strings.Contains(held, "main is broken") {
+		t.Errorf("held banner = %q", held)
+	}
+	drain := (&FreezeState{Drain: true, Until: now.Add(90 * time.Minute)}).Banner(now)
+	if !strings.Contains(drain, "draining") || !strings.Contains(drain, "1h30m") {
+		t.Errorf("drain banner = %q", drain)
+	}
+}
+
+func TestParseDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2h", now.Add(2 * time.Hour)},
+		{"17:30", time.Date(2026, 3, 2, 17, 30, 0, 0, time.UTC)},
+		{"09:00", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"2026-03-05T12:00:00Z", time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeadline(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseDeadline(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDeadline(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"soon", "-1h", "2026-03-01T12:00:00Z"} {
+		if _, err := ParseDeadline(bad, now); err == nil {
+			t.Errorf("ParseDeadline(%q) succeeded, want error", bad)
+		}
+	}
+}
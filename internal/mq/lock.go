@@ -0,0 +1,210 @@
+package mq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// targetLocksDir holds a rig's merge target locks, under <rig>/.runtime/.
+const targetLocksDir = "mq-locks"
+
+// ErrTargetLocked is matched (errors.Is) by the LockedError returned when
+// another worker holds a merge target.
+var ErrTargetLocked = errors.New("merge target locked")
+
+// TargetLock is a refinery worker's hold on a merge target. Merges into the
+// same target are serialized by it: a worker rebases, gates and pushes only
+// while it holds the target, so it never races another worker's push.
+// Merges into different targets (main and an integration branch, say) go
+// ahead concurrently.
+//
+// A lock is a lease. A holder that crashes mid-merge never releases it, so
+// a lock not renewed within the stale timeout can be taken over.
+type TargetLock struct {
+	// Target is the branch the holder is merging into.
+	Target string `json:"target"`
+
+	// Holder identifies the refinery worker (e.g., "refinery-2").
+	Holder string `json:"holder"`
+
+	// MRID is the merge request being merged.
+	MRID string `json:"mr_id,omitempty"`
+
+	// AcquiredAt is when the holder took the lock.
+	AcquiredAt time.Time `json:"acquired_at"`
+
+	// RenewedAt is when the holder last renewed the lock.
+	RenewedAt time.Time `json:"renewed_at"`
+}
+
+// Stale reports whether the lock has gone unrenewed for staleAfter at now.
+// A zero staleAfter never expires a lock.
+func (l *TargetLock) Stale(staleAfter time.Duration, now time.Time) bool {
+	return staleAfter > 0 && now.Sub(l.RenewedAt) >= staleAfter
+}
+
+// LockedError reports the lock held by another worker.
+type LockedError struct {
+	Lock *TargetLock
+}
+
+func (e *LockedError) Error() string {
+	s := fmt.Sprintf("merge target %s is locked by %s", e.Lock.Target, e.Lock.Holder)
+	if e.Lock.MRID != "" {
+		s += " (merging " + e.Lock.MRID + ")"
+	}
+	return s
+}
+
+// Is makes errors.Is(err, ErrTargetLocked) match.
+func (e *LockedError) Is(target error) bool {
+	return target == ErrTargetLocked
+}
+
+// TargetLockPath returns the lock file path for a rig's merge target.
+func TargetLockPath(rigPath, target string) string {
+	return filepath.Join(rigPath, ".runtime", targetLocksDir, url.PathEscape(target)+".json")
+}
+
+// targetLocksMu serializes lock changes between goroutines; the flock in
+// guardTargetLocks serializes them between processes. flock alone can't do
+// both: a process may take the same flock twice without blocking.
+var targetLocksMu sync.Mutex
+
+// guardTargetLocks takes the rig's lock-table guard. Call the returned
+// function to release it.
+func guardTargetLocks(rigPath string) (func(), error) {
+	dir := filepath.Join(rigPath, ".runtime", targetLocksDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	targetLocksMu.Lock()
+	fl := flock.New(filepath.Join(dir, ".guard"))
+	if err := fl.Lock(); err != nil {
+		targetLocksMu.Unlock()
+		return nil, fmt.Errorf("acquiring merge lock guard: %w", err)
+	}
+	return func() {
+		_ = fl.Unlock()
+		targetLocksMu.Unlock()
+	}, nil
+}
+
+// AcquireTargetLock takes target for holder, merging mrID. A holder that
+// already has the lock renews it, and a stale lock is taken over; any other
+// held lock fails with a *LockedError.
+func AcquireTargetLock(rigPath, target, holder, mrID string, staleAfter time.Duration, now time.Time) (*TargetLock, error) {
+	if target == "" || holder == "" {
+		return nil, fmt.Errorf("merge lock needs a target and a holder")
+	}
+	release, err := guardTargetLocks(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	path := TargetLockPath(rigPath, target)
+	lock := &TargetLock{Target: target, Holder: holder, MRID: mrID, AcquiredAt: now, RenewedAt: now}
+	cur, err := readTargetLock(path)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		switch {
+		case cur.Holder == holder:
+			lock.AcquiredAt = cur.AcquiredAt
+		case !cur.Stale(staleAfter, now):
+			return nil, &LockedError{Lock: cur}
+		}
+	}
+	if err := writeTargetLock(path, lock); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// ReleaseTargetLock releases holder's lock on target. Releasing a lock that
+// is not held, or is held by someone else, does nothing.
+func ReleaseTargetLock(rigPath, target, holder string) error {
+	release, err := guardTargetLocks(rigPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	path := TargetLockPath(rigPath, target)
+	cur, err := readTargetLock(path)
+	if err != nil || cur == nil || cur.Holder != holder {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetTargetLock returns the lock on target, or nil if it is free.
+func GetTargetLock(rigPath, target string) (*TargetLock, error) {
+	return readTargetLock(TargetLockPath(rigPath, target))
+}
+
+// ListTargetLocks returns a rig's merge target locks, stale ones included,
+// ordered by target.
+func ListTargetLocks(rigPath string) ([]*TargetLock, error) {
+	paths, err := filepath.Glob(filepath.Join(rigPath, ".runtime", targetLocksDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var locks []*TargetLock
+	for _, path := range paths {
+		lock, err := readTargetLock(path)
+		if err != nil {
+			return nil, err
+		}
+		if lock != nil {
+			locks = append(locks, lock)
+		}
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Target < locks[j].Target })
+	return locks, nil
+}
+
+func readTargetLock(path string) (*TargetLock, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var lock TargetLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing merge lock %s: %w", filepath.Base(path), err)
+	}
+	return &lock, nil
+}
+
+func writeTargetLock(path string, lock *TargetLock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: runtime state, not secret
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package mq
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetLock(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stale := 30 * time.Minute
+
+	lock, err := AcquireTargetLock(rigPath, "main", "refinery-1", "gt-mr1", stale, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Holder != "refinery-1" || lock.MRID != "gt-mr1" {
+		t.Errorf("lock = %+v", lock)
+	}
+
+	// Another worker can't take it; a different target is free.
+	_, err = AcquireTargetLock(rigPath, "main", "refinery-2", "gt-mr2", stale, now.Add(time.Minute))
+	var locked *LockedError
+	if !errors.Is(err, ErrTargetLocked) || !errors.As(err, &locked) || locked.Lock.MRID != "gt-mr1" {
+		t.Fatalf("second holder: %v", err)
+	}
+	if _, err := AcquireTargetLock(rigPath, "integration/gt-epic", "refinery-2", "gt-mr2", stale, now); err != nil {
+		t.Fatalf("other target: %v", err)
+	}
+
+	// The holder renews its lock, keeping when it took it.
+	renewed, err := AcquireTargetLock(rigPath, "main", "refinery-1", "gt-mr1", stale, now.Add(20*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renewed.AcquiredAt.Equal(now) || !renewed.RenewedAt.Equal(now.Add(20*time.Minute)) {
+		t.Errorf("renewed = %+v", renewed)
+	}
+	if _, err := AcquireTargetLock(rigPath, "main", "refinery-2", "gt-mr2", stale, now.Add(40*time.Minute)); !errors.Is(err, ErrTargetLocked) {
+		t.Errorf("renewed lock taken over: %v", err)
+	}
+
+	locks, err := ListTargetLocks(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 2 || locks[0].Target != "integration/gt-epic" || locks[1].Target != "main" {
+		t.Errorf("locks = %+v", locks)
+	}
+
+	// Only the holder releases it.
+	if err := ReleaseTargetLock(rigPath, "main", "refinery-2"); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := GetTargetLock(rigPath, "main"); cur == nil {
+		t.Fatal("lock released by a worker that didn't hold it")
+	}
+	if err := ReleaseTargetLock(rigPath, "main", "refinery-1"); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := GetTargetLock(rigPath, "main"); cur != nil {
+		t.Errorf("lock still held after release: %+v", cur)
+	}
+	if err := ReleaseTargetLock(rigPath, "main", "refinery-1"); err != nil {
+		t.Errorf("releasing a free target: %v", err)
+	}
+}
+
+func TestTargetLock_StaleTakeover(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := AcquireTargetLock(rigPath, "main", "refinery-1", "gt-mr1", time.Hour, now); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := AcquireTargetLock(rigPath, "main", "refinery-2", "gt-mr2", time.Hour, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("stale lock not taken over: %v", err)
+	}
+	if lock.Holder != "refinery-2" || !lock.AcquiredAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("lock = %+v", lock)
+	}
+}
+
+func TestTargetLock_Concurrent(t *testing.T) {
+	rigPath := t.TempDir()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			holder := "refinery-" + string(rune('1'+i))
+			if _, err := AcquireTargetLock(rigPath, "main", holder, "", time.Hour, time.Now()); err == nil {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrTargetLocked) {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("%d workers got the lock, want 1", winners)
+	}
+}
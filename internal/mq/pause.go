@@ -0,0 +1,239 @@
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pauseFile holds a rig's refinery pause, under <rig>/.runtime/.
+const pauseFile = "mq-pause.json"
+
+// PauseState is a refinery pause. Unlike a freeze, a paused queue keeps
+// accepting submissions; the refinery just defers merging them until the
+// pause is lifted or its Until deadline passes.
+type PauseState struct {
+	// Reason explains the pause (e.g., "CI outage").
+	Reason string `json:"reason,omitempty"`
+
+	// PausedAt is when the refinery was paused.
+	PausedAt time.Time `json:"paused_at"`
+
+	// PausedBy identifies who paused the refinery.
+	PausedBy string `json:"paused_by,omitempty"`
+
+	// Until is when the pause lifts. Zero means it stays until resumed.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// PausePath returns the pause file path for a rig.
+func PausePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", pauseFile)
+}
+
+// ActivePause returns the rig's pause in effect at now, or nil when the
+// refinery is not paused. A pause whose deadline has passed is removed.
+func ActivePause(rigPath string, now time.Time) (*PauseState, error) {
+	data, err := os.ReadFile(PausePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state PauseState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing refinery pause: %w", err)
+	}
+	if !state.Until.IsZero() && !now.Before(state.Until) {
+		_ = Resume(rigPath) // best-effort: a stale file reads as resumed anyway
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Pause writes the rig's pause, replacing any existing one.
+func Pause(rigPath string, state *PauseState) error {
+	path := PausePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: runtime state, not secret
+}
+
+// Resume lifts the rig's pause. Resuming a running refinery is not an error.
+func Resume(rigPath string) error {
+	if err := os.Remove(PausePath(rigPath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MaintenanceWindow is a scheduled period during which the refinery defers
+// merges (e.g., a weekly deploy or a database migration). Windows are set
+// per rig in the merge_queue section of the rig's config.json:
+//
+//	"maintenance_windows": [
+//	  {"days": ["sun"], "start": "02:00", "end": "04:00", "timezone": "America/New_York", "reason": "backups"}
+//	]
+//
+// Days are three-letter lowercase names ("mon".."sun"); empty means every
+// day. Start and End are "HH:MM" in Timezone (default UTC); End before Start
+// wraps midnight, and Start equal to End is the whole day.
+type MaintenanceWindow struct {
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Timezone string   `json:"timezone,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// rule returns the window as a merge-window policy rule, whose evaluation
+// and validation it shares.
+func (w *MaintenanceWindow) rule() *Rule {
+	return &Rule{Type: RuleWindow, Days: w.Days, Start: w.Start, End: w.End, Timezone: w.Timezone}
+}
+
+// Validate checks the window's days, times and timezone.
+func (w *MaintenanceWindow) Validate() error {
+	if err := w.rule().validate(); err != nil {
+		return fmt.Errorf("maintenance window %s: %w", w.Describe(), err)
+	}
+	return nil
+}
+
+// Describe is a short description of the window, e.g. "sun 02:00-04:00 UTC".
+func (w *MaintenanceWindow) Describe() string {
+	s := w.Start + "-" + w.End
+	if len(w.Days) > 0 {
+		s = strings.Join(w.Days, ",") + " " + s
+	}
+	tz := w.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	return s + " " + tz
+}
+
+// Active reports whether now falls inside the window, and if so when it
+// ends.
+func (w *MaintenanceWindow) Active(now time.Time) (bool, time.Time) {
+	r := w.rule()
+	if in, _ := r.evaluateWindow(now); !in {
+		return false, time.Time{}
+	}
+	loc, _ := r.location()
+	local := now.In(loc)
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if start == end {
+		return true, midnight.AddDate(0, 0, 1)
+	}
+	ends := midnight.Add(time.Duration(end) * time.Minute)
+	if !ends.After(local) {
+		ends = ends.AddDate(0, 0, 1)
+	}
+	return true, ends
+}
+
+// LoadMaintenanceWindows reads the maintenance windows from the merge_queue
+// section of the rig's config.json (the file the refinery reads its merge
+// settings from). A rig without the file or section has none; an invalid
+// window is an error so the refinery holds merges rather than ignore it.
+func LoadMaintenanceWindows(rigPath string) ([]MaintenanceWindow, error) {
+	path := filepath.Join(rigPath, "config.json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var raw struct {
+		MergeQueue *struct {
+			MaintenanceWindows []MaintenanceWindow `json:"maintenance_windows"`
+		} `json:"merge_queue"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if raw.MergeQueue == nil {
+		return nil, nil
+	}
+	for i := range raw.MergeQueue.MaintenanceWindows {
+		if err := raw.MergeQueue.MaintenanceWindows[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return raw.MergeQueue.MaintenanceWindows, nil
+}
+
+// Hold kinds.
+const (
+	HoldPaused      = "paused"
+	HoldMaintenance = "maintenance"
+)
+
+// Hold is why the refinery is deferring merges right now: a pause or a
+// maintenance window. Submissions are still accepted.
+type Hold struct {
+	Kind   string    `json:"kind"` // HoldPaused or HoldMaintenance
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`     // who paused (pauses only)
+	Window string    `json:"window,omitempty"` // the window (maintenance only)
+	Until  time.Time `json:"until,omitempty"`  // zero for an open-ended pause
+}
+
+// ActiveHold returns the hold on the rig's merges at now, or nil. A pause
+// takes precedence over a maintenance window.
+func ActiveHold(rigPath string, now time.Time) (*Hold, error) {
+	pause, err := ActivePause(rigPath, now)
+	if err != nil {
+		return nil, err
+	}
+	if pause != nil {
+		return &Hold{Kind: HoldPaused, Reason: pause.Reason, By: pause.PausedBy, Until: pause.Until}, nil
+	}
+	windows, err := LoadMaintenanceWindows(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	return MaintenanceHold(windows, now), nil
+}
+
+// MaintenanceHold returns the hold of the first window active at now, or nil.
+func MaintenanceHold(windows []MaintenanceWindow, now time.Time) *Hold {
+	for i := range windows {
+		w := &windows[i]
+		if active, until := w.Active(now); active {
+			return &Hold{Kind: HoldMaintenance, Reason: w.Reason, Window: w.Describe(), Until: until}
+		}
+	}
+	return nil
+}
+
+// Banner is a one-line description of the hold for status output.
+func (h *Hold) Banner(now time.Time) string {
+	var msg string
+	switch h.Kind {
+	case HoldMaintenance:
+		msg = "Refinery in MAINTENANCE (" + h.Window + "): merges deferred"
+	default:
+		msg = "Refinery PAUSED: merges deferred, submissions still accepted"
+	}
+	if h.Reason != "" {
+		msg += " — " + h.Reason
+	}
+	if !h.Until.IsZero() {
+		msg += fmt.Sprintf(" (resumes in %s)", h.Until.Sub(now).Round(time.Minute))
+	}
+	return msg
+}
@@ -0,0 +1,122 @@
+package mq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseLifecycle(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if hold, err := ActiveHold(rigPath, now); err != nil || hold != nil {
+		t.Fatalf("running refinery hold = %v, %v; want nil, nil", hold, err)
+	}
+
+	if err := Pause(rigPath, &PauseState{Reason: "CI is down", PausedAt: now, PausedBy: "mayor", Until: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	hold, err := ActiveHold(rigPath, now.Add(30*time.Minute))
+	if err != nil || hold == nil {
+		t.Fatalf("paused refinery hold = %v, %v; want a pause", hold, err)
+	}
+	if hold.Kind != HoldPaused || hold.Reason != "CI is down" || hold.By != "mayor" {
+		t.Errorf("hold = %+v", hold)
+	}
+	if banner := hold.Banner(now.Add(30 * time.Minute)); !strings.Contains(banner, "PAUSED") || !strings.Contains(banner, "resumes in 30m") {
+		t.Errorf("banner = %q", banner)
+	}
+
+	// Past the deadline the pause lifts and its file is removed.
+	if pause, err := ActivePause(rigPath, now.Add(time.Hour)); err != nil || pause != nil {
+		t.Fatalf("expired pause = %v, %v; want nil, nil", pause, err)
+	}
+	if _, err := os.Stat(PausePath(rigPath)); !os.IsNotExist(err) {
+		t.Errorf("expired pause file still present: %v", err)
+	}
+
+	if err := Pause(rigPath, &PauseState{PausedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if pause, _ := ActivePause(rigPath, now.AddDate(1, 0, 0)); pause == nil {
+		t.Error("pause without a deadline lifted itself")
+	}
+	if err := Resume(rigPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := Resume(rigPath); err != nil {
+		t.Errorf("resuming a running refinery: %v", err)
+	}
+}
+
+func TestMaintenanceWindowActive(t *testing.T) {
+	// 2026-03-01 is a Sunday.
+	sunday := func(h, m int) time.Time { return time.Date(2026, 3, 1, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		now    time.Time
+		active bool
+		until  time.Time
+	}{
+		{"inside", MaintenanceWindow{Days: []string{"sun"}, Start: "02:00", End: "04:00"}, sunday(3, 0), true, sunday(4, 0)},
+		{"before", MaintenanceWindow{Days: []string{"sun"}, Start: "02:00", End: "04:00"}, sunday(1, 59), false, time.Time{}},
+		{"other day", MaintenanceWindow{Days: []string{"sat"}, Start: "02:00", End: "04:00"}, sunday(3, 0), false, time.Time{}},
+		{"wraps midnight, late", MaintenanceWindow{Start: "22:00", End: "01:00"}, sunday(23, 0), true, sunday(25, 0)},
+		{"wraps midnight, early", MaintenanceWindow{Start: "22:00", End: "01:00"}, sunday(0, 30), true, sunday(1, 0)},
+		{"all day", MaintenanceWindow{Days: []string{"sun"}, Start: "00:00", End: "00:00"}, sunday(15, 0), true, sunday(24, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, until := tt.window.Active(tt.now)
+			if active != tt.active || !until.Equal(tt.until) {
+				t.Errorf("Active(%s) = %v, %s; want %v, %s", tt.now, active, until, tt.active, tt.until)
+			}
+		})
+	}
+}
+
+func TestLoadMaintenanceWindows(t *testing.T) {
+	rigPath := t.TempDir()
+	if windows, err := LoadMaintenanceWindows(rigPath); err != nil || windows != nil {
+		t.Fatalf("no config = %v, %v; want nil, nil", windows, err)
+	}
+
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"merge_queue": {"enabled": true, "maintenance_windows": [
+		{"days": ["sun"], "start": "02:00", "end": "04:00", "reason": "backups"}
+	]}}`)
+	windows, err := LoadMaintenanceWindows(rigPath)
+	if err != nil || len(windows) != 1 || windows[0].Reason != "backups" {
+		t.Fatalf("windows = %+v, %v", windows, err)
+	}
+
+	hold, err := ActiveHold(rigPath, time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC))
+	if err != nil || hold == nil || hold.Kind != HoldMaintenance {
+		t.Fatalf("hold in window = %+v, %v; want maintenance", hold, err)
+	}
+	if banner := hold.Banner(time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)); !strings.Contains(banner, "sun 02:00-04:00 UTC") || !strings.Contains(banner, "backups") {
+		t.Errorf("banner = %q", banner)
+	}
+
+	// A pause wins over the window.
+	if err := Pause(rigPath, &PauseState{Reason: "incident"}); err != nil {
+		t.Fatal(err)
+	}
+	if hold, _ := ActiveHold(rigPath, time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)); hold == nil || hold.Kind != HoldPaused {
+		t.Errorf("hold = %+v, want the pause", hold)
+	}
+
+	write(`{"merge_queue": {"maintenance_windows": [{"start": "25:00", "end": "04:00"}]}}`)
+	if _, err := LoadMaintenanceWindows(rigPath); err == nil {
+		t.Error("invalid window accepted")
+	}
+}
@@ -0,0 +1,420 @@
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PolicyFileName is the per-rig merge policy file, stored under <rig>/settings/.
+const PolicyFileName = "merge-policy.json"
+
+// CurrentPolicyVersion is the current schema version for Policy.
+const CurrentPolicyVersion = 1
+
+// Rule types understood by the policy engine.
+const (
+	RuleApprovals       = "approvals"        // Minimum number of approved-by:<who> labels
+	RuleFreshness       = "freshness"        // MR must have been updated within MaxAge
+	RuleTestGate        = "test_gate"        // MR must not carry failing-gate labels
+	RuleWindow          = "window"           // Merges only allowed during a time window
+	RuleProtectedBranch = "protected_branch" // Target branch is protected unless allow-labeled
+	RuleLane            = "lane"             // Only MRs at or above a priority may target Branches
+)
+
+// ApprovalLabelPrefix is the label prefix that records an approval on an MR
+// (e.g., "approved-by:mayor").
+const ApprovalLabelPrefix = "approved-by:"
+
+// Entry is the view of a merge request the policy engine evaluates.
+// Callers build it from the MR bead; the engine never touches beads directly.
+type Entry struct {
+	ID        string
+	Branch    string
+	Target    string
+	Worker    string
+	Priority  int
+	Labels    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Rule is a single declarative policy rule. Only the fields relevant to the
+// rule's Type are consulted.
+type Rule struct {
+	// Name identifies the rule in check output. Defaults to Type.
+	Name string `json:"name,omitempty"`
+
+	// Type selects the rule implementation (see Rule* constants).
+	Type string `json:"type"`
+
+	// Branches are target branch globs (path.Match syntax, e.g. "release/*").
+	// Used by protected_branch and lane. Lane rules with no branches apply to all targets.
+	Branches []string `json:"branches,omitempty"`
+
+	// MinApprovals is the number of approved-by:* labels required (approvals).
+	MinApprovals int `json:"min_approvals,omitempty"`
+
+	// CarryOver lets the approvals of a superseded MR count for the MR that
+	// replaced it (approvals; see gt mq supersede).
+	CarryOver bool `json:"carry_over,omitempty"`
+
+	// MaxAge is the maximum time since the MR was last updated (freshness), e.g. "72h".
+	MaxAge string `json:"max_age,omitempty"`
+
+	// FailLabels are labels that mark a failed gate (test_gate). Defaults to ["needs-fix"].
+	FailLabels []string `json:"fail_labels,omitempty"`
+
+	// AllowLabels exempt an MR from a protected_branch rule.
+	AllowLabels []string `json:"allow_labels,omitempty"`
+
+	// MaxPriority is the lowest-urgency priority allowed into a lane (0 = P0 only).
+	MaxPriority *int `json:"max_priority,omitempty"`
+
+	// Days, Start, End, and Timezone describe a merge window (window).
+	// Days are three-letter lowercase names ("mon".."sun"); empty means every day.
+	// Start and End are "HH:MM" in Timezone (default UTC). End before Start wraps midnight;
+	// Start equal to End is the whole day (so a days-only window is start=end="00:00").
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start,omitempty"`
+	End      string   `json:"end,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+// DisplayName returns the rule name, falling back to its type.
+func (r *Rule) DisplayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Type
+}
+
+// Policy is the declarative merge policy for a rig.
+type Policy struct {
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// RuleResult is the outcome of evaluating one rule against an entry.
+type RuleResult struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"`
+}
+
+// Evaluation is the outcome of evaluating a full policy against an entry.
+type Evaluation struct {
+	EntryID string       `json:"entry_id"`
+	Results []RuleResult `json:"results"`
+}
+
+// Allowed returns true if every rule passed.
+func (e *Evaluation) Allowed() bool {
+	for _, r := range e.Results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results of rules that did not pass.
+func (e *Evaluation) Failed() []RuleResult {
+	var failed []RuleResult
+	for _, r := range e.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// PolicyPath returns the merge policy path for a rig.
+func PolicyPath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", PolicyFileName)
+}
+
+// LoadPolicy loads and validates the merge policy for a rig.
+// A missing file yields an empty policy (everything is allowed).
+func LoadPolicy(rigPath string) (*Policy, error) {
+	data, err := os.ReadFile(PolicyPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Policy{Version: CurrentPolicyVersion}, nil
+		}
+		return nil, fmt.Errorf("reading merge policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing merge policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid merge policy %s: %w", PolicyPath(rigPath), err)
+	}
+	return &p, nil
+}
+
+// Validate checks that every rule is well-formed.
+func (p *Policy) Validate() error {
+	if p.Version > CurrentPolicyVersion {
+		return fmt.Errorf("unsupported policy version %d (max %d)", p.Version, CurrentPolicyVersion)
+	}
+	for i := range p.Rules {
+		if err := p.Rules[i].validate(); err != nil {
+			return fmt.Errorf("rule %d (%s): %w", i, p.Rules[i].DisplayName(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	for _, pattern := range r.Branches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad branch pattern %q: %w", pattern, err)
+		}
+	}
+	switch r.Type {
+	case RuleApprovals:
+		if r.MinApprovals <= 0 {
+			return fmt.Errorf("min_approvals must be positive")
+		}
+	case RuleFreshness:
+		d, err := time.ParseDuration(r.MaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid max_age %q: %w", r.MaxAge, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("max_age must be positive")
+		}
+	case RuleTestGate:
+	case RuleWindow:
+		if _, err := parseClock(r.Start); err != nil {
+			return fmt.Errorf("invalid start: %w", err)
+		}
+		if _, err := parseClock(r.End); err != nil {
+			return fmt.Errorf("invalid end: %w", err)
+		}
+		for _, d := range r.Days {
+			if _, ok := weekdayNames[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("unknown day %q", d)
+			}
+		}
+		if _, err := r.location(); err != nil {
+			return err
+		}
+	case RuleProtectedBranch:
+		if len(r.Branches) == 0 {
+			return fmt.Errorf("protected_branch requires branches")
+		}
+	case RuleLane:
+		if r.MaxPriority == nil {
+			return fmt.Errorf("lane requires max_priority")
+		}
+	case "":
+		return fmt.Errorf("missing type")
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// CarriesApprovals reports whether an MR's approvals move to the MR that
+// supersedes it. Every approvals rule must allow it with carry_over, since a
+// rewrite is new code that a rule would otherwise want reviewed again.
+func (p *Policy) CarriesApprovals() bool {
+	for i := range p.Rules {
+		if p.Rules[i].Type == RuleApprovals && !p.Rules[i].CarryOver {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate runs every rule against the entry at the given time.
+func (p *Policy) Evaluate(entry *Entry, now time.Time) *Evaluation {
+	eval := &Evaluation{EntryID: entry.ID}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		passed, reason := rule.evaluate(entry, now)
+		eval.Results = append(eval.Results, RuleResult{
+			Name:   rule.DisplayName(),
+			Type:   rule.Type,
+			Passed: passed,
+			Reason: reason,
+		})
+	}
+	return eval
+}
+
+func (r *Rule) evaluate(e *Entry, now time.Time) (bool, string) {
+	switch r.Type {
+	case RuleApprovals:
+		var approvers []string
+		for _, l := range e.Labels {
+			if strings.HasPrefix(l, ApprovalLabelPrefix) {
+				approvers = append(approvers, strings.TrimPrefix(l, ApprovalLabelPrefix))
+			}
+		}
+		if len(approvers) >= r.MinApprovals {
+			return true, fmt.Sprintf("%d/%d approvals (%s)", len(approvers), r.MinApprovals, strings.Join(approvers, ", "))
+		}
+		return false, fmt.Sprintf("%d/%d approvals", len(approvers), r.MinApprovals)
+
+	case RuleFreshness:
+		maxAge, _ := time.ParseDuration(r.MaxAge)
+		last := e.UpdatedAt
+		if last.IsZero() {
+			last = e.CreatedAt
+		}
+		if last.IsZero() {
+			return false, "no update timestamp on entry"
+		}
+		age := now.Sub(last).Round(time.Minute)
+		if age <= maxAge {
+			return true, fmt.Sprintf("updated %s ago (max %s)", age, r.MaxAge)
+		}
+		return false, fmt.Sprintf("last updated %s ago, exceeds %s", age, r.MaxAge)
+
+	case RuleTestGate:
+		failLabels := r.FailLabels
+		if len(failLabels) == 0 {
+			failLabels = []string{"needs-fix"}
+		}
+		for _, fl := range failLabels {
+			if hasLabel(e.Labels, fl) {
+				return false, fmt.Sprintf("gate failure recorded (label %s)", fl)
+			}
+		}
+		return true, "no failing gates recorded"
+
+	case RuleWindow:
+		return r.evaluateWindow(now)
+
+	case RuleProtectedBranch:
+		pattern, matched := matchAny(r.Branches, e.Target)
+		if !matched {
+			return true, fmt.Sprintf("target %s is not protected", e.Target)
+		}
+		for _, al := range r.AllowLabels {
+			if hasLabel(e.Labels, al) {
+				return true, fmt.Sprintf("target %s is protected (%s) but MR has %s", e.Target, pattern, al)
+			}
+		}
+		if len(r.AllowLabels) > 0 {
+			return false, fmt.Sprintf("target %s is protected (%s); requires one of: %s", e.Target, pattern, strings.Join(r.AllowLabels, ", "))
+		}
+		return false, fmt.Sprintf("target %s is protected (%s)", e.Target, pattern)
+
+	case RuleLane:
+		if len(r.Branches) > 0 {
+			if _, matched := matchAny(r.Branches, e.Target); !matched {
+				return true, fmt.Sprintf("target %s is outside this lane", e.Target)
+			}
+		}
+		if e.Priority <= *r.MaxPriority {
+			return true, fmt.Sprintf("P%d is within lane limit P%d", e.Priority, *r.MaxPriority)
+		}
+		return false, fmt.Sprintf("P%d exceeds lane limit P%d for %s", e.Priority, *r.MaxPriority, e.Target)
+	}
+	return false, fmt.Sprintf("unknown rule type %q", r.Type)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (r *Rule) location() (*time.Location, error) {
+	if r.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", r.Timezone, err)
+	}
+	return loc, nil
+}
+
+func (r *Rule) evaluateWindow(now time.Time) (bool, string) {
+	loc, err := r.location()
+	if err != nil {
+		return false, err.Error()
+	}
+	local := now.In(loc)
+	start, _ := parseClock(r.Start)
+	end, _ := parseClock(r.End)
+	minute := local.Hour()*60 + local.Minute()
+	window := fmt.Sprintf("%s-%s %s", r.Start, r.End, loc)
+
+	// For windows that wrap midnight, the early-morning part belongs to the
+	// previous day's window.
+	day := local.Weekday()
+	var inWindow bool
+	if start == end {
+		inWindow = true
+		window = "all day " + loc.String()
+	} else if start < end {
+		inWindow = minute >= start && minute < end
+	} else {
+		inWindow = minute >= start || minute < end
+		if minute < end {
+			day = (day + 6) % 7
+		}
+	}
+
+	if len(r.Days) > 0 {
+		dayAllowed := false
+		for _, d := range r.Days {
+			if weekdayNames[strings.ToLower(d)] == day {
+				dayAllowed = true
+				break
+			}
+		}
+		if !dayAllowed {
+			return false, fmt.Sprintf("%s is outside merge days (%s)", day, strings.Join(r.Days, ","))
+		}
+	}
+	if !inWindow {
+		return false, fmt.Sprintf("%s is outside merge window %s", local.Format("15:04"), window)
+	}
+	return true, fmt.Sprintf("inside merge window %s", window)
+}
+
+// parseClock parses "HH:MM" into minutes past midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// matchAny reports the first pattern that matches name.
+func matchAny(patterns []string, name string) (string, bool) {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package mq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestLoadPolicy_MissingFileAllowsEverything(t *testing.T) {
+	p, err := LoadPolicy(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	eval := p.Evaluate(&Entry{ID: "gt-mr-1", Target: "main"}, time.Now())
+	if !eval.Allowed() {
+		t.Errorf("empty policy should allow entry, got %+v", eval.Results)
+	}
+}
+
+func TestLoadPolicy_RejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"unknown type", `{"rules":[{"type":"vibes"}]}`, "unknown rule type"},
+		{"missing type", `{"rules":[{"name":"x"}]}`, "missing type"},
+		{"bad duration", `{"rules":[{"type":"freshness","max_age":"soon"}]}`, "invalid max_age"},
+		{"bad window", `{"rules":[{"type":"window","start":"9am","end":"17:00"}]}`, "invalid start"},
+		{"protected without branches", `{"rules":[{"type":"protected_branch"}]}`, "requires branches"},
+		{"lane without priority", `{"rules":[{"type":"lane"}]}`, "requires max_priority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rigPath := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(PolicyPath(rigPath), []byte(tt.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadPolicy(rigPath)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadPolicy error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate_Rules(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC) // Wednesday
+	entry := &Entry{
+		ID:        "gt-mr-1",
+		Target:    "release/1.2",
+		Priority:  2,
+		Labels:    []string{"approved-by:mayor"},
+		UpdatedAt: now.Add(-2 * time.Hour),
+	}
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"approvals met", Rule{Type: RuleApprovals, MinApprovals: 1}, true},
+		{"approvals short", Rule{Type: RuleApprovals, MinApprovals: 2}, false},
+		{"fresh", Rule{Type: RuleFreshness, MaxAge: "3h"}, true},
+		{"stale", Rule{Type: RuleFreshness, MaxAge: "1h"}, false},
+		{"gate clean", Rule{Type: RuleTestGate}, true},
+		{"in window", Rule{Type: RuleWindow, Start: "09:00", End: "17:00", Days: []string{"mon", "wed"}}, true},
+		{"wrong day", Rule{Type: RuleWindow, Start: "09:00", End: "17:00", Days: []string{"sat"}}, false},
+		{"outside hours", Rule{Type: RuleWindow, Start: "12:00", End: "17:00"}, false},
+		{"full-day window", Rule{Type: RuleWindow, Start: "00:00", End: "00:00", Days: []string{"wed"}}, true},
+		{"full-day window wrong day", Rule{Type: RuleWindow, Start: "08:00", End: "08:00", Days: []string{"thu"}}, false},
+		{"overnight window early morning", Rule{Type: RuleWindow, Start: "22:00", End: "11:00", Days: []string{"tue"}}, true},
+		{"protected", Rule{Type: RuleProtectedBranch, Branches: []string{"release/*"}}, false},
+		{"protected but allowed", Rule{Type: RuleProtectedBranch, Branches: []string{"release/*"}, AllowLabels: []string{"approved-by:mayor"}}, true},
+		{"unprotected", Rule{Type: RuleProtectedBranch, Branches: []string{"prod"}}, true},
+		{"lane blocks low priority", Rule{Type: RuleLane, Branches: []string{"release/*"}, MaxPriority: intPtr(1)}, false},
+		{"lane other target", Rule{Type: RuleLane, Branches: []string{"hotfix/*"}, MaxPriority: intPtr(0)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Policy{Rules: []Rule{tt.rule}}
+			if err := p.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			eval := p.Evaluate(entry, now)
+			if eval.Allowed() != tt.want {
+				t.Errorf("Allowed() = %v, want %v (reason: %s)", eval.Allowed(), tt.want, eval.Results[0].Reason)
+			}
+			if eval.Results[0].Reason == "" {
+				t.Error("expected a reason to explain the result")
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate_TestGateFailure(t *testing.T) {
+	p := &Policy{Rules: []Rule{{Name: "tests-green", Type: RuleTestGate}}}
+	eval := p.Evaluate(&Entry{ID: "gt-mr-2", Labels: []string{"needs-fix"}}, time.Now())
+	if eval.Allowed() {
+		t.Fatal("entry with needs-fix should fail test gate")
+	}
+	failed := eval.Failed()
+	if len(failed) != 1 || failed[0].Name != "tests-green" {
+		t.Errorf("Failed() = %+v, want tests-green", failed)
+	}
+}
+
+func TestPolicy_CarriesApprovals(t *testing.T) {
+	if !(&Policy{}).CarriesApprovals() {
+		t.Error("a policy without approvals rules should carry approvals")
+	}
+	p := &Policy{Rules: []Rule{
+		{Type: RuleApprovals, MinApprovals: 1, CarryOver: true},
+		{Type: RuleApprovals, MinApprovals: 2, Branches: []string{"release/*"}},
+	}}
+	if p.CarriesApprovals() {
+		t.Error("carried approvals though one approvals rule doesn't allow it")
+	}
+	p.Rules[1].CarryOver = true
+	if !p.CarriesApprovals() {
+		t.Error("every approvals rule allows carry_over")
+	}
+}
@@ -0,0 +1,175 @@
+package mq
+
+import (
+	"sort"
+	"time"
+)
+
+// MRRecord is one merge request's history, as used for queue statistics.
+type MRRecord struct {
+	ID          string
+	Rig         string
+	Worker      string
+	CreatedAt   time.Time // Entered the queue
+	ClosedAt    time.Time // Zero while still open
+	CloseReason string    // merged, rejected, conflict, superseded
+	Failures    int       // Failed merge attempts (build, test, push) in the window
+	Conflicts   int       // Merge attempts that hit a conflict in the window
+}
+
+// Merged reports whether the MR left the queue by merging. Only the refinery
+// records close_reason "merged"; an MR closed without a reason (e.g., by
+// gt mq reject or cancel) did not merge.
+func (r *MRRecord) Merged() bool {
+	return !r.ClosedAt.IsZero() && r.CloseReason == "merged"
+}
+
+// GroupStats aggregates queue outcomes for one worker, one rig, or the whole queue.
+type GroupStats struct {
+	Name       string        `json:"name,omitempty"`
+	Merged     int           `json:"merged"`
+	Rejected   int           `json:"rejected"` // Closed without merging
+	Failed     int           `json:"failed"`   // Failed attempts
+	Conflicts  int           `json:"conflicts"`
+	Attempts   int           `json:"attempts"` // Merges plus failed and conflicted attempts
+	MedianWait time.Duration `json:"median_wait_ns"`
+	P90Wait    time.Duration `json:"p90_wait_ns"`
+
+	FailureRate  float64 `json:"failure_rate"`  // Failed / Attempts
+	ConflictRate float64 `json:"conflict_rate"` // Conflicts / Attempts
+
+	waits []time.Duration
+}
+
+// DayStats counts merges on one calendar day.
+type DayStats struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Merged int    `json:"merged"`
+}
+
+// QueueStats summarizes merge queue throughput and latency over a window.
+type QueueStats struct {
+	Since      time.Time     `json:"since"`
+	Until      time.Time     `json:"until"`
+	Total      GroupStats    `json:"total"`
+	PerDay     float64       `json:"merges_per_day"`
+	Daily      []DayStats    `json:"daily"`
+	Open       int           `json:"open"`
+	OldestWait time.Duration `json:"oldest_wait_ns,omitempty"`
+	ByWorker   []GroupStats  `json:"by_worker"`
+	ByRig      []GroupStats  `json:"by_rig"`
+}
+
+// ComputeStats aggregates records into queue statistics for [since, until).
+// MRs closed in the window count toward merges and wait times; failures and
+// conflicts are counted for every record, since callers only attribute
+// attempts that happened in the window. Days are calendar days in until's
+// location.
+func ComputeStats(records []MRRecord, since, until time.Time) *QueueStats {
+	s := &QueueStats{Since: since, Until: until}
+	byWorker := make(map[string]*GroupStats)
+	byRig := make(map[string]*GroupStats)
+	daily := make(map[string]int)
+
+	for i := range records {
+		r := &records[i]
+		if r.ClosedAt.IsZero() {
+			s.Open++
+			if wait := until.Sub(r.CreatedAt); !r.CreatedAt.IsZero() && wait > s.OldestWait {
+				s.OldestWait = wait
+			}
+		}
+		closedInWindow := !r.ClosedAt.IsZero() && !r.ClosedAt.Before(since) && r.ClosedAt.Before(until)
+		if !closedInWindow && r.Failures == 0 && r.Conflicts == 0 {
+			continue
+		}
+
+		worker := r.Worker
+		if worker == "" {
+			worker = "(unknown)"
+		}
+		groups := []*GroupStats{&s.Total, group(byWorker, worker), group(byRig, r.Rig)}
+		for _, g := range groups {
+			g.Failed += r.Failures
+			g.Conflicts += r.Conflicts
+		}
+		if !closedInWindow {
+			continue
+		}
+		if r.Merged() {
+			daily[r.ClosedAt.In(until.Location()).Format("2006-01-02")]++
+			for _, g := range groups {
+				g.Merged++
+				if !r.CreatedAt.IsZero() {
+					g.waits = append(g.waits, r.ClosedAt.Sub(r.CreatedAt))
+				}
+			}
+		} else {
+			for _, g := range groups {
+				g.Rejected++
+			}
+		}
+	}
+
+	s.Total.finish()
+	s.ByWorker = finishGroups(byWorker)
+	s.ByRig = finishGroups(byRig)
+
+	start := since.In(until.Location())
+	for d := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, until.Location()); d.Before(until); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		s.Daily = append(s.Daily, DayStats{Date: date, Merged: daily[date]})
+	}
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		s.PerDay = float64(s.Total.Merged) / days
+	}
+	return s
+}
+
+func group(m map[string]*GroupStats, name string) *GroupStats {
+	g, ok := m[name]
+	if !ok {
+		g = &GroupStats{Name: name}
+		m[name] = g
+	}
+	return g
+}
+
+// finishGroups computes rates for each group and sorts by merges, then name.
+func finishGroups(m map[string]*GroupStats) []GroupStats {
+	out := make([]GroupStats, 0, len(m))
+	for _, g := range m {
+		g.finish()
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Merged != out[j].Merged {
+			return out[i].Merged > out[j].Merged
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func (g *GroupStats) finish() {
+	g.Attempts = g.Merged + g.Failed + g.Conflicts
+	if g.Attempts > 0 {
+		g.FailureRate = float64(g.Failed) / float64(g.Attempts)
+		g.ConflictRate = float64(g.Conflicts) / float64(g.Attempts)
+	}
+	if len(g.waits) > 0 {
+		sort.Slice(g.waits, func(i, j int) bool { return g.waits[i] < g.waits[j] })
+		g.MedianWait = percentile(g.waits, 50)
+		g.P90Wait = percentile(g.waits, 90)
+	}
+	g.waits = nil
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
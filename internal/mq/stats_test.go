@@ -0,0 +1,65 @@
+package mq
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	until := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	since := until.Add(-72 * time.Hour) // Mar 1 12:00 → Mar 4 12:00
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+
+	records := []MRRecord{
+		{ID: "gt-1", Rig: "gastown", Worker: "Toast", CreatedAt: at(2, 9), ClosedAt: at(2, 10), CloseReason: "merged"},
+		{ID: "gt-2", Rig: "gastown", Worker: "Toast", CreatedAt: at(3, 9), ClosedAt: at(3, 12), CloseReason: "merged", Failures: 1},
+		{ID: "gt-3", Rig: "beads", Worker: "Nux", CreatedAt: at(3, 9), ClosedAt: at(3, 9).Add(30 * time.Minute), CloseReason: "merged", Conflicts: 2},
+		{ID: "gt-4", Rig: "beads", Worker: "Nux", CreatedAt: at(2, 0), ClosedAt: at(3, 0), CloseReason: "rejected", Failures: 1},
+		{ID: "gt-5", Rig: "gastown", Worker: "Toast", CreatedAt: at(4, 10)},                                           // Open, waiting 2h
+		{ID: "gt-6", Rig: "gastown", Worker: "Toast", CreatedAt: at(1, 0), ClosedAt: at(1, 6), CloseReason: "merged"}, // Before the window
+		{ID: "gt-7", Rig: "beads", Worker: "Nux", CreatedAt: at(3, 0), ClosedAt: at(3, 1)},                            // Rejected: no close_reason
+	}
+
+	s := ComputeStats(records, since, until)
+
+	tot := s.Total
+	if tot.Merged != 3 || tot.Rejected != 2 || tot.Failed != 2 || tot.Conflicts != 2 || tot.Attempts != 7 {
+		t.Errorf("total = %+v", tot)
+	}
+	if tot.MedianWait != time.Hour || tot.P90Wait != 3*time.Hour {
+		t.Errorf("waits: median %v, p90 %v", tot.MedianWait, tot.P90Wait)
+	}
+	if s.Open != 1 || s.OldestWait != 2*time.Hour {
+		t.Errorf("open = %d, oldest %v", s.Open, s.OldestWait)
+	}
+	if s.PerDay != 1 {
+		t.Errorf("per day = %v, want 1", s.PerDay)
+	}
+
+	var daily []int
+	for _, d := range s.Daily {
+		daily = append(daily, d.Merged)
+	}
+	if len(s.Daily) != 4 || s.Daily[0].Date != "2026-03-01" || daily[1] != 1 || daily[2] != 2 {
+		t.Errorf("daily = %+v", s.Daily)
+	}
+
+	if len(s.ByWorker) != 2 || s.ByWorker[0].Name != "Toast" || s.ByWorker[0].Merged != 2 {
+		t.Fatalf("by worker = %+v", s.ByWorker)
+	}
+	nux := s.ByWorker[1]
+	if nux.Attempts != 4 || nux.FailureRate != 0.25 || nux.ConflictRate != 0.5 {
+		t.Errorf("Nux = %+v", nux)
+	}
+	if len(s.ByRig) != 2 || s.ByRig[0].Name != "gastown" {
+		t.Errorf("by rig = %+v", s.ByRig)
+	}
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	until := time.Now()
+	s := ComputeStats(nil, until.Add(-24*time.Hour), until)
+	if s.Total.Attempts != 0 || s.Total.FailureRate != 0 || len(s.ByWorker) != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
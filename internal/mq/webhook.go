@@ -0,0 +1,311 @@
+package mq
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// logger is the mq module's logger (gt config set log.mq=debug).
+var logger = logging.For("mq")
+
+// WebhooksFileName is the per-rig webhook configuration file, stored under <rig>/settings/.
+const WebhooksFileName = "mq-webhooks.json"
+
+// Queue state transition events delivered to webhooks.
+const (
+	EventSubmitted  = "submitted"  // MR entered the queue
+	EventMerging    = "merging"    // Refinery started processing the MR
+	EventMerged     = "merged"     // MR merged to its target
+	EventFailed     = "failed"     // Build, test, or push failure
+	EventConflicted = "conflicted" // Merge conflict; MR blocked on a resolution task
+)
+
+// AllEvents lists every webhook event in lifecycle order.
+var AllEvents = []string{EventSubmitted, EventMerging, EventMerged, EventFailed, EventConflicted}
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body when a
+// webhook has a secret configured.
+const SignatureHeader = "X-Gastown-Signature"
+
+// defaultWebhookTimeout bounds each delivery so a slow receiver cannot stall the refinery.
+const defaultWebhookTimeout = 5 * time.Second
+
+// Webhook is a single receiver of queue events.
+type Webhook struct {
+	// URL receives a POST with a JSON WebhookPayload body. It may name a
+	// town secret instead ("secret:<name>", see gt secret).
+	URL string `json:"url"`
+
+	// Events filters which events are delivered. Empty means all events.
+	Events []string `json:"events,omitempty"`
+
+	// Secret, if set, signs each body with HMAC-SHA256 (see SignatureHeader).
+	// It may name a town secret too.
+	Secret string `json:"secret,omitempty"`
+
+	// Timeout per delivery, e.g. "3s". Defaults to 5s.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// WebhookConfig is the on-disk webhook configuration for a rig.
+type WebhookConfig struct {
+	Webhooks []Webhook `json:"webhooks"`
+
+	// CommitStatus, if set, also publishes queue state as commit statuses.
+	CommitStatus *CommitStatusConfig `json:"commit_status,omitempty"`
+}
+
+// WebhookPayload is the JSON body POSTed to webhooks.
+type WebhookPayload struct {
+	Event       string    `json:"event"`
+	Rig         string    `json:"rig"`
+	MRID        string    `json:"mr_id"`
+	Branch      string    `json:"branch,omitempty"`
+	Target      string    `json:"target,omitempty"`
+	SourceIssue string    `json:"source_issue,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	MergeCommit string    `json:"merge_commit,omitempty"`
+	HeadSHA     string    `json:"head_sha,omitempty"` // Branch head the MR was submitted at
+	Summary     string    `json:"summary,omitempty"`  // Pre-merge change summary, when enabled
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// WebhooksPath returns the webhook configuration path for a rig.
+func WebhooksPath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", WebhooksFileName)
+}
+
+// LoadWebhookConfig loads and validates a rig's webhook configuration,
+// resolving the town secrets it names. A missing file yields an empty
+// configuration.
+func LoadWebhookConfig(rigPath string) (*WebhookConfig, error) {
+	data, err := os.ReadFile(WebhooksPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &WebhookConfig{}, nil
+		}
+		return nil, fmt.Errorf("reading webhook config: %w", err)
+	}
+
+	var cfg WebhookConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing webhook config: %w", err)
+	}
+	townRoot := filepath.Dir(rigPath)
+	for i := range cfg.Webhooks {
+		w := &cfg.Webhooks[i]
+		if err := w.resolveSecrets(townRoot); err != nil {
+			return nil, fmt.Errorf("webhook %d: %w", i, err)
+		}
+		if err := w.Validate(); err != nil {
+			return nil, fmt.Errorf("webhook %d: %w", i, err)
+		}
+	}
+	if cfg.CommitStatus != nil {
+		if err := cfg.CommitStatus.Validate(); err != nil {
+			return nil, fmt.Errorf("commit_status: %w", err)
+		}
+		if err := cfg.CommitStatus.resolveToken(townRoot); err != nil {
+			return nil, fmt.Errorf("commit_status: %w", err)
+		}
+	}
+	return &cfg, nil
+}
+
+// resolveSecrets replaces a URL or signing secret that names a town secret
+// with the secret's value.
+func (w *Webhook) resolveSecrets(townRoot string) error {
+	var err error
+	if w.URL, err = secrets.Resolve(townRoot, w.URL); err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	if w.Secret, err = secrets.Resolve(townRoot, w.Secret); err != nil {
+		return fmt.Errorf("secret: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the webhook URL, event filter, and timeout.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url: must be an http(s) URL")
+	}
+	for _, ev := range w.Events {
+		if !isKnownEvent(ev) {
+			return fmt.Errorf("unknown event %q", ev)
+		}
+	}
+	if w.Timeout != "" {
+		d, err := time.ParseDuration(w.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", w.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive, got %v", d)
+		}
+	}
+	return nil
+}
+
+// Wants reports whether the webhook subscribes to event.
+func (w *Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, ev := range w.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownEvent(event string) bool {
+	for _, ev := range AllEvents {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher delivers queue events to a rig's webhooks and, when
+// configured, its commit status provider.
+// A nil or empty Dispatcher is valid and delivers nothing.
+type Dispatcher struct {
+	rig      string
+	webhooks []Webhook
+	status   *CommitStatusConfig
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewDispatcher creates a dispatcher for the given rig and webhooks.
+func NewDispatcher(rigName string, webhooks []Webhook) *Dispatcher {
+	return &Dispatcher{
+		rig:      rigName,
+		webhooks: webhooks,
+		client:   &http.Client{},
+		now:      time.Now,
+	}
+}
+
+// LoadDispatcher creates a dispatcher from the rig's webhook configuration.
+func LoadDispatcher(rigName, rigPath string) (*Dispatcher, error) {
+	cfg, err := LoadWebhookConfig(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	d := NewDispatcher(rigName, cfg.Webhooks)
+	d.status = cfg.CommitStatus
+	return d, nil
+}
+
+// PublishesCommitStatus reports whether Dispatch sets commit statuses, so
+// callers only resolve the branch head (WebhookPayload.HeadSHA) when needed.
+func (d *Dispatcher) PublishesCommitStatus() bool {
+	return d != nil && d.status != nil
+}
+
+// Dispatch POSTs the payload to every webhook subscribed to its event and
+// sets the commit status on payload.HeadSHA, if there is one.
+// Event, Rig, and Timestamp are filled in by the dispatcher. Deliveries are
+// attempted independently; failures are joined into the returned error so
+// callers can log them without aborting queue processing.
+func (d *Dispatcher) Dispatch(event string, payload WebhookPayload) error {
+	if d == nil || (len(d.webhooks) == 0 && d.status == nil) {
+		return nil
+	}
+	payload.Event = event
+	payload.Rig = d.rig
+	payload.Timestamp = d.now().UTC()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	var errs []error
+	if d.status != nil && payload.HeadSHA != "" {
+		if err := d.publishStatus(event, payload); err != nil {
+			logger.Debug("commit status failed", "rig", d.rig, "event", event, "err", err)
+			errs = append(errs, fmt.Errorf("commit status: %w", err))
+		}
+	}
+	for i := range d.webhooks {
+		w := &d.webhooks[i]
+		if !w.Wants(event) {
+			continue
+		}
+		// Webhook URLs often carry a token in the path: log only the host
+		host := w.URL
+		if u, err := url.Parse(w.URL); err == nil {
+			host = u.Host
+		}
+		if err := d.deliver(w, body); err != nil {
+			logger.Debug("webhook delivery failed", "rig", d.rig, "event", event, "host", host, "err", err)
+			errs = append(errs, fmt.Errorf("webhook %s: %w", host, err))
+			continue
+		}
+		logger.Debug("webhook delivered", "rig", d.rig, "event", event, "host", host)
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) deliver(w *Webhook, body []byte) error {
+	timeout := defaultWebhookTimeout
+	if w.Timeout != "" {
+		if t, err := time.ParseDuration(w.Timeout); err == nil && t > 0 {
+			timeout = t
+		}
+	}
+	client := *d.client
+	client.Timeout = timeout
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-mq")
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, SignPayload(w.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err // its message repeats the URL, which may be a secret
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// SignPayload returns the hex HMAC-SHA256 of body keyed by secret, as sent
+// in SignatureHeader. Receivers recompute it to authenticate deliveries.
+func SignPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
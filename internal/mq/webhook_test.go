@@ -0,0 +1,137 @@
+package mq
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type webhookRecorder struct {
+	mu       sync.Mutex
+	payloads []WebhookPayload
+	sigs     []string
+}
+
+func (rec *webhookRecorder) handler(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var p WebhookPayload
+		_ = json.Unmarshal(body, &p)
+		rec.mu.Lock()
+		rec.payloads = append(rec.payloads, p)
+		rec.sigs = append(rec.sigs, r.Header.Get(SignatureHeader))
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+	}
+}
+
+func TestDispatcher_DeliversSubscribedEvents(t *testing.T) {
+	all := &webhookRecorder{}
+	allSrv := httptest.NewServer(all.handler(http.StatusOK))
+	defer allSrv.Close()
+
+	mergedOnly := &webhookRecorder{}
+	mergedSrv := httptest.NewServer(mergedOnly.handler(http.StatusNoContent))
+	defer mergedSrv.Close()
+
+	d := NewDispatcher("gastown", []Webhook{
+		{URL: allSrv.URL},
+		{URL: mergedSrv.URL, Events: []string{EventMerged}, Secret: "s3cret"},
+	})
+
+	if err := d.Dispatch(EventSubmitted, WebhookPayload{MRID: "gt-mr-1", Branch: "polecat/nux"}); err != nil {
+		t.Fatalf("Dispatch submitted: %v", err)
+	}
+	if err := d.Dispatch(EventMerged, WebhookPayload{MRID: "gt-mr-1", MergeCommit: "abc123"}); err != nil {
+		t.Fatalf("Dispatch merged: %v", err)
+	}
+
+	if len(all.payloads) != 2 {
+		t.Fatalf("catch-all webhook got %d payloads, want 2", len(all.payloads))
+	}
+	if all.payloads[0].Event != EventSubmitted || all.payloads[0].Rig != "gastown" || all.payloads[0].Branch != "polecat/nux" {
+		t.Errorf("unexpected submitted payload: %+v", all.payloads[0])
+	}
+	if all.sigs[0] != "" {
+		t.Errorf("unsigned webhook should not carry signature, got %q", all.sigs[0])
+	}
+
+	if len(mergedOnly.payloads) != 1 || mergedOnly.payloads[0].MergeCommit != "abc123" {
+		t.Fatalf("merged-only webhook got %+v", mergedOnly.payloads)
+	}
+	if !strings.HasPrefix(mergedOnly.sigs[0], "sha256=") {
+		t.Errorf("expected signature header, got %q", mergedOnly.sigs[0])
+	}
+}
+
+func TestDispatcher_ReportsFailuresWithoutStopping(t *testing.T) {
+	bad := httptest.NewServer((&webhookRecorder{}).handler(http.StatusInternalServerError))
+	defer bad.Close()
+	good := &webhookRecorder{}
+	goodSrv := httptest.NewServer(good.handler(http.StatusOK))
+	defer goodSrv.Close()
+
+	d := NewDispatcher("gastown", []Webhook{{URL: bad.URL}, {URL: goodSrv.URL}})
+	err := d.Dispatch(EventFailed, WebhookPayload{MRID: "gt-mr-2", Error: "tests failed"})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected 500 error, got %v", err)
+	}
+	if len(good.payloads) != 1 {
+		t.Errorf("later webhook should still be delivered, got %d", len(good.payloads))
+	}
+}
+
+func TestDispatcher_NilIsNoop(t *testing.T) {
+	var d *Dispatcher
+	if err := d.Dispatch(EventMerged, WebhookPayload{MRID: "x"}); err != nil {
+		t.Errorf("nil dispatcher should be a no-op, got %v", err)
+	}
+}
+
+func TestLoadWebhookConfig(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg, err := LoadWebhookConfig(rigPath)
+	if err != nil || len(cfg.Webhooks) != 0 {
+		t.Fatalf("missing file: cfg=%+v err=%v", cfg, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"valid", `{"webhooks":[{"url":"https://hooks.example.com/x","events":["merged","failed"],"timeout":"2s"}]}`, ""},
+		{"bad scheme", `{"webhooks":[{"url":"ftp://example.com"}]}`, "invalid url"},
+		{"unknown event", `{"webhooks":[{"url":"http://example.com","events":["exploded"]}]}`, "unknown event"},
+		{"bad timeout", `{"webhooks":[{"url":"http://example.com","timeout":"soon"}]}`, "invalid timeout"},
+		{"commit status", `{"webhooks":[],"commit_status":{"provider":"github","repo":"acme/widgets"}}`, ""},
+		{"bad provider", `{"commit_status":{"provider":"bitbucket","repo":"acme/widgets"}}`, "unknown provider"},
+		{"bad github repo", `{"commit_status":{"provider":"github","repo":"widgets"}}`, "owner/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(WebhooksPath(rigPath), []byte(tt.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadWebhookConfig(rigPath)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -791,6 +792,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		UpdatedAt: now,
 	}
 
+	_ = events.Publish(m.rig.Path, events.SourcePolecat, events.TypePolecatAdded, m.assigneeID(name),
+		map[string]interface{}{"rig": m.rig.Name, "polecat": name, "branch": branchName, "bead": opts.HookBead},
+		events.VisibilityAudit)
 	return polecat, nil
 }
 
@@ -963,6 +967,9 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 	m.namePool.Release(name)
 	_ = m.namePool.Save()
 
+	_ = events.Publish(m.rig.Path, events.SourcePolecat, events.TypePolecatRemoved, m.assigneeID(name),
+		map[string]interface{}{"rig": m.rig.Name, "polecat": name, "nuclear": nuclear, "self_nuke": selfNuke},
+		events.VisibilityAudit)
 	return nil
 }
 
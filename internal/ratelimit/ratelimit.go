@@ -0,0 +1,355 @@
+// Package ratelimit caps how fast each agent identity writes beads, so a
+// misconfigured agent stuck in a retry loop can't flood the shared Dolt
+// server with thousands of writes a minute.
+//
+// Each identity (its BD_ACTOR, e.g. "gastown/polecats/nux") has a token
+// bucket in <town>/.runtime/ratelimit/, shared by every gt process on the
+// host under a file lock. A write takes a token; without one it waits for
+// the next, up to the configured MaxWait, so a tight loop is slowed to the
+// quota rather than failing faster. Quotas come from the town settings'
+// rate_limits (see config.RateLimitConfig).
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var logger = logging.For("ratelimit")
+
+// Defaults for identities without a configured quota.
+const (
+	DefaultPerMinute = 300
+	DefaultBurst     = 100
+	DefaultMaxWait   = 30 * time.Second
+)
+
+// ErrLimited is returned for a write over its identity's quota.
+var ErrLimited = errors.New("rate limited")
+
+// Quota is an identity's write quota: Burst writes at once, refilled at
+// PerMinute. A negative PerMinute means unlimited.
+type Quota struct {
+	PerMinute int `json:"per_minute"`
+	Burst     int `json:"burst"`
+}
+
+// Unlimited reports whether the quota exempts its identity.
+func (q Quota) Unlimited() bool {
+	return q.PerMinute < 0
+}
+
+func (q Quota) String() string {
+	if q.Unlimited() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/min, burst %d", q.PerMinute, q.Burst)
+}
+
+// Bucket is an identity's saved bucket.
+type Bucket struct {
+	Identity   string    `json:"identity"`
+	Tokens     float64   `json:"tokens"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Throttled  int       `json:"throttled"`             // writes that waited for a token
+	Denied     int       `json:"denied"`                // writes refused after MaxWait
+	LastDenied time.Time `json:"last_denied,omitempty"` // zero if never denied
+}
+
+// Limiter enforces a town's write quotas.
+type Limiter struct {
+	townRoot string
+	cfg      config.RateLimitConfig
+	maxWait  time.Duration
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+var (
+	townLimitersMu sync.Mutex
+	townLimiters   = map[string]*Limiter{}
+)
+
+// ForTown returns the town's limiter, loading its settings once per process.
+func ForTown(townRoot string) *Limiter {
+	townLimitersMu.Lock()
+	defer townLimitersMu.Unlock()
+	if l, ok := townLimiters[townRoot]; ok {
+		return l
+	}
+	var cfg *config.RateLimitConfig
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		logger.Warn("loading town settings; using default rate limits", "err", err)
+	} else {
+		cfg = settings.RateLimits
+	}
+	l := New(townRoot, cfg)
+	townLimiters[townRoot] = l
+	return l
+}
+
+// New returns a limiter for the town with the given settings (nil for the
+// defaults).
+func New(townRoot string, cfg *config.RateLimitConfig) *Limiter {
+	l := &Limiter{townRoot: townRoot, maxWait: DefaultMaxWait, now: time.Now, sleep: time.Sleep}
+	if cfg != nil {
+		l.cfg = *cfg
+	}
+	if l.cfg.MaxWait != "" {
+		if d, err := time.ParseDuration(l.cfg.MaxWait); err == nil && d >= 0 {
+			l.maxWait = d
+		} else {
+			logger.Warn("invalid rate_limits.max_wait; using the default", "max_wait", l.cfg.MaxWait)
+		}
+	}
+	return l
+}
+
+// QuotaFor returns identity's quota. Identities are exempt when limiting is
+// disabled or the identity is empty (a human at a shell).
+func (l *Limiter) QuotaFor(identity string) Quota {
+	if l.cfg.Disabled || identity == "" {
+		return Quota{PerMinute: -1}
+	}
+	q := Quota{PerMinute: l.cfg.PerMinute, Burst: l.cfg.Burst}
+	if q.PerMinute == 0 {
+		q.PerMinute = DefaultPerMinute
+	}
+	if q.Burst == 0 {
+		q.Burst = DefaultBurst
+	}
+	if key, ok := l.matchAgent(identity); ok {
+		o := l.cfg.Agents[key]
+		if o.PerMinute != 0 {
+			q.PerMinute = o.PerMinute
+		}
+		if o.Burst != 0 {
+			q.Burst = o.Burst
+		}
+	}
+	if q.Unlimited() {
+		return Quota{PerMinute: -1}
+	}
+	if q.Burst < 1 {
+		q.Burst = 1
+	}
+	return q
+}
+
+// matchAgent returns the Agents key for identity: an exact match, else
+// the longest matching pattern.
+func (l *Limiter) matchAgent(identity string) (string, bool) {
+	if _, ok := l.cfg.Agents[identity]; ok {
+		return identity, true
+	}
+	best := ""
+	for key := range l.cfg.Agents {
+		if ok, _ := path.Match(key, identity); !ok {
+			continue
+		}
+		if len(key) > len(best) || (len(key) == len(best) && key < best) {
+			best = key
+		}
+	}
+	return best, best != ""
+}
+
+// Wait takes a write token for identity, sleeping until one is free. It
+// returns an ErrLimited error, without taking a token, if that would be
+// longer than MaxWait. Problems with the bucket files are logged and the
+// write allowed: the limiter never blocks writes by failing itself.
+func (l *Limiter) Wait(identity string) error {
+	wait, err := l.take(identity, true)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		logger.Debug("write throttled", "identity", identity, "wait", wait)
+		l.sleep(wait)
+	}
+	return nil
+}
+
+// Allow takes a write token for identity if one is free now, else returns
+// an ErrLimited error. For callers that answer "try later" rather than
+// wait, like the dashboard API.
+func (l *Limiter) Allow(identity string) error {
+	_, err := l.take(identity, false)
+	return err
+}
+
+// RetryAfter is how long an ErrLimited error says to wait.
+func RetryAfter(err error) time.Duration {
+	var le *limitedError
+	if errors.As(err, &le) {
+		return le.retryAfter
+	}
+	return 0
+}
+
+type limitedError struct {
+	identity   string
+	quota      Quota
+	retryAfter time.Duration
+}
+
+func (e *limitedError) Error() string {
+	return fmt.Sprintf("%s: %s is over its bead write quota (%s); retry in %s",
+		ErrLimited, e.identity, e.quota, e.retryAfter.Round(time.Second))
+}
+
+func (e *limitedError) Unwrap() error { return ErrLimited }
+
+// take takes a token, returning how long to sleep for it. With wait set a
+// token up to MaxWait away is reserved (the bucket goes negative);
+// otherwise only a token available now is taken.
+func (l *Limiter) take(identity string, wait bool) (time.Duration, error) {
+	q := l.QuotaFor(identity)
+	if q.Unlimited() {
+		return 0, nil
+	}
+	var sleep time.Duration
+	var limited error
+	err := l.update(identity, func(b *Bucket, now time.Time) {
+		l.refill(b, q, now)
+		if b.Tokens >= 1 {
+			b.Tokens--
+			return
+		}
+		rate := float64(q.PerMinute) / 60 // tokens per second
+		need := time.Duration((1 - b.Tokens) / rate * float64(time.Second))
+		if wait && need <= l.maxWait {
+			b.Tokens--
+			b.Throttled++
+			sleep = need
+			return
+		}
+		b.Denied++
+		b.LastDenied = now
+		limited = &limitedError{identity: identity, quota: q, retryAfter: need}
+	})
+	if err != nil {
+		logger.Warn("rate limit bucket unavailable; allowing write", "identity", identity, "err", err)
+		return 0, nil
+	}
+	if limited != nil {
+		// The caller reports the error; this keeps a record in gt.log.
+		logger.Info("write denied", "identity", identity, "quota", q.String())
+	}
+	return sleep, limited
+}
+
+// refill adds the tokens earned since the bucket was last updated.
+func (l *Limiter) refill(b *Bucket, q Quota, now time.Time) {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(q.Burst)
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens += elapsed.Seconds() * float64(q.PerMinute) / 60
+	}
+	b.Tokens = math.Min(b.Tokens, float64(q.Burst))
+	b.UpdatedAt = now
+}
+
+// Dir is the bucket directory, relative to the town root.
+const Dir = ".runtime/ratelimit"
+
+func (l *Limiter) bucketPath(identity string) string {
+	return filepath.Join(l.townRoot, Dir, fileName(identity)+".json")
+}
+
+// fileName maps an identity to a file name: "gastown/polecats/nux"
+// becomes "gastown%2Fpolecats%2Fnux". Escaping keeps distinct identities
+// in distinct files.
+func fileName(identity string) string {
+	return url.PathEscape(identity)
+}
+
+// update applies fn to identity's bucket under its file lock.
+func (l *Limiter) update(identity string, fn func(b *Bucket, now time.Time)) error {
+	p := l.bucketPath(identity)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	unlock, err := lock.FlockAcquire(p + ".flock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	b := Bucket{Identity: identity}
+	if data, err := os.ReadFile(p); err == nil { //nolint:gosec // G304: path is constructed internally
+		if err := json.Unmarshal(data, &b); err != nil {
+			b = Bucket{Identity: identity} // corrupt: start over
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	fn(&b, l.now())
+	return util.AtomicWriteJSON(p, b)
+}
+
+// BucketStatus is a bucket as of now, with its identity's quota.
+type BucketStatus struct {
+	Bucket
+	Quota Quota `json:"quota"`
+}
+
+// Status returns every saved bucket, refilled to now, sorted by identity.
+func (l *Limiter) Status() ([]BucketStatus, error) {
+	paths, err := filepath.Glob(filepath.Join(l.townRoot, Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	now := l.now()
+	var out []BucketStatus
+	for _, p := range paths {
+		data, err := os.ReadFile(p) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		var b Bucket
+		if json.Unmarshal(data, &b) != nil || b.Identity == "" {
+			continue
+		}
+		q := l.QuotaFor(b.Identity)
+		if !q.Unlimited() {
+			l.refill(&b, q, now)
+		}
+		out = append(out, BucketStatus{Bucket: b, Quota: q})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Identity < out[j].Identity })
+	return out, nil
+}
+
+// Reset refills identity's bucket and clears its counters.
+func (l *Limiter) Reset(identity string) error {
+	p := l.bucketPath(identity)
+	if _, err := os.Stat(filepath.Dir(p)); os.IsNotExist(err) {
+		return nil
+	}
+	unlock, err := lock.FlockAcquire(p + ".flock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
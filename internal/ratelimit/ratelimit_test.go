@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeClock drives a limiter's time: sleeping advances it.
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func newTestLimiter(t *testing.T, town string, cfg *config.RateLimitConfig) (*Limiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	l := New(town, cfg)
+	l.now = func() time.Time { return clock.now }
+	l.sleep = func(d time.Duration) {
+		clock.slept += d
+		clock.now = clock.now.Add(d)
+	}
+	return l, clock
+}
+
+func TestWaitBurstThenThrottle(t *testing.T) {
+	l, clock := newTestLimiter(t, t.TempDir(), &config.RateLimitConfig{PerMinute: 60, Burst: 3, MaxWait: "5s"})
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait("rig/polecats/nux"); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+	if clock.slept != 0 {
+		t.Fatalf("burst slept %v", clock.slept)
+	}
+
+	// The fourth write waits a second for its token.
+	if err := l.Wait("rig/polecats/nux"); err != nil {
+		t.Fatal(err)
+	}
+	if clock.slept != time.Second {
+		t.Errorf("slept %v, want 1s", clock.slept)
+	}
+
+	// Another identity has its own bucket.
+	clock.slept = 0
+	if err := l.Wait("rig/polecats/max"); err != nil || clock.slept != 0 {
+		t.Errorf("other identity: slept %v, %v", clock.slept, err)
+	}
+}
+
+func TestWaitDeniedPastMaxWait(t *testing.T) {
+	town := t.TempDir()
+	l, clock := newTestLimiter(t, town, &config.RateLimitConfig{PerMinute: 60, Burst: 1, MaxWait: "2s"})
+	// Don't let sleeping refill the bucket: the writes arrive at once.
+	l.sleep = func(d time.Duration) { clock.slept += d }
+
+	var denied error
+	for i := 0; i < 5 && denied == nil; i++ {
+		denied = l.Wait("loop")
+	}
+	if !errors.Is(denied, ErrLimited) {
+		t.Fatalf("expected ErrLimited, got %v", denied)
+	}
+	if RetryAfter(denied) <= 2*time.Second {
+		t.Errorf("RetryAfter = %v", RetryAfter(denied))
+	}
+
+	status, err := l.Status()
+	if err != nil || len(status) != 1 {
+		t.Fatalf("Status = %+v, %v", status, err)
+	}
+	if b := status[0]; b.Identity != "loop" || b.Denied != 1 || b.Throttled != 2 || b.LastDenied.IsZero() {
+		t.Errorf("bucket = %+v", b)
+	}
+
+	if err := l.Reset("loop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Allow("loop"); err != nil {
+		t.Errorf("Allow after reset: %v", err)
+	}
+}
+
+func TestAllowDoesNotWait(t *testing.T) {
+	l, clock := newTestLimiter(t, t.TempDir(), &config.RateLimitConfig{PerMinute: 60, Burst: 1})
+	if err := l.Allow("dashboard"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Allow("dashboard"); !errors.Is(err, ErrLimited) {
+		t.Fatalf("second Allow: %v", err)
+	}
+	if clock.slept != 0 {
+		t.Errorf("Allow slept %v", clock.slept)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if err := l.Allow("dashboard"); err != nil {
+		t.Errorf("Allow after refill: %v", err)
+	}
+}
+
+func TestBucketsSharedAcrossLimiters(t *testing.T) {
+	town := t.TempDir()
+	cfg := &config.RateLimitConfig{PerMinute: 60, Burst: 2}
+	a, clockA := newTestLimiter(t, town, cfg)
+	b, clockB := newTestLimiter(t, town, cfg)
+	clockB.now = clockA.now
+
+	if err := a.Allow("nux"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Allow("nux"); err != nil {
+		t.Fatal(err)
+	}
+	// Another process, same identity: the bucket is empty.
+	if err := a.Allow("nux"); !errors.Is(err, ErrLimited) {
+		t.Errorf("third write across limiters: %v", err)
+	}
+}
+
+func TestQuotaFor(t *testing.T) {
+	l := New(t.TempDir(), &config.RateLimitConfig{
+		PerMinute: 100,
+		Agents: map[string]config.RateLimitQuota{
+			"*/polecats/*":       {PerMinute: 20},
+			"gastown/polecats/*": {Burst: 5},
+			"gastown/refinery":   {PerMinute: 1000, Burst: 200},
+			"mayor":              {PerMinute: -1},
+		},
+	})
+	tests := []struct {
+		identity string
+		want     Quota
+	}{
+		{"", Quota{PerMinute: -1}},
+		{"mayor", Quota{PerMinute: -1}},
+		{"gastown/refinery", Quota{PerMinute: 1000, Burst: 200}},
+		{"beads/polecats/nux", Quota{PerMinute: 20, Burst: DefaultBurst}},
+		{"gastown/polecats/nux", Quota{PerMinute: 100, Burst: 5}}, // most specific pattern only
+		{"gastown/witness", Quota{PerMinute: 100, Burst: DefaultBurst}},
+	}
+	for _, tt := range tests {
+		if got := l.QuotaFor(tt.identity); got != tt.want {
+			t.Errorf("QuotaFor(%q) = %+v, want %+v", tt.identity, got, tt.want)
+		}
+	}
+
+	if q := New(t.TempDir(), &config.RateLimitConfig{Disabled: true}).QuotaFor("nux"); !q.Unlimited() {
+		t.Errorf("disabled: %+v", q)
+	}
+	if q := New(t.TempDir(), nil).QuotaFor("nux"); q != (Quota{PerMinute: DefaultPerMinute, Burst: DefaultBurst}) {
+		t.Errorf("defaults: %+v", q)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if got := fileName("gastown/polecats/nux"); got != "gastown%2Fpolecats%2Fnux" {
+		t.Errorf("fileName = %q", got)
+	}
+	if got := fileName("../x y"); got != "..%2Fx%20y" {
+		t.Errorf("fileName = %q", got)
+	}
+
+	// Identities that differ only in separators or punctuation get their
+	// own buckets.
+	seen := map[string]string{}
+	for _, identity := range []string{"rig/polecat", "rig--polecat", "rig%2Fpolecat", "a b", "a_b", "a%20b"} {
+		name := fileName(identity)
+		if other, ok := seen[name]; ok {
+			t.Errorf("%q and %q share file %q", identity, other, name)
+		}
+		seen[name] = identity
+	}
+}
@@ -0,0 +1,258 @@
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// HeldLabel marks an MR the refinery must not process until it is released
+// (gt mq hold / gt mq unhold).
+const HeldLabel = "gt:mq-held"
+
+// QueueFilter selects queued MRs for bulk operations. Every non-empty field
+// must match. Target and Branch are globs (e.g. "release/*").
+type QueueFilter struct {
+	Worker string
+	Target string
+	Branch string
+	Issue  string // source issue
+}
+
+// queueFilterKeys lists the keys ParseQueueFilter accepts, for error messages.
+var queueFilterKeys = []string{"worker", "target", "branch", "issue"}
+
+// ParseQueueFilter parses key=value expressions such as "worker=Toast" and
+// "target=release/1.3". Repeated keys are an error.
+func ParseQueueFilter(exprs []string) (QueueFilter, error) {
+	var f QueueFilter
+	seen := make(map[string]bool)
+	for _, expr := range exprs {
+		key, value, ok := strings.Cut(expr, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return f, fmt.Errorf("invalid filter %q: expected key=value", expr)
+		}
+		if seen[key] {
+			return f, fmt.Errorf("filter key %q given twice", key)
+		}
+		seen[key] = true
+		switch key {
+		case "worker":
+			f.Worker = value
+		case "target":
+			f.Target = value
+		case "branch":
+			f.Branch = value
+		case "issue":
+			f.Issue = value
+		default:
+			return f, fmt.Errorf("unknown filter key %q (valid: %s)", key, strings.Join(queueFilterKeys, ", "))
+		}
+		if key == "target" || key == "branch" {
+			if _, err := path.Match(value, ""); err != nil {
+				return f, fmt.Errorf("invalid %s glob %q: %w", key, value, err)
+			}
+		}
+	}
+	return f, nil
+}
+
+// IsEmpty reports whether the filter matches everything.
+func (f QueueFilter) IsEmpty() bool {
+	return f == QueueFilter{}
+}
+
+// Matches reports whether mr passes the filter. Worker matches the worker
+// name or the last element of its address, case-insensitively.
+func (f QueueFilter) Matches(mr *MergeRequest) bool {
+	if f.Worker != "" && !strings.EqualFold(mr.Worker, f.Worker) && !strings.EqualFold(path.Base(mr.Worker), f.Worker) {
+		return false
+	}
+	if f.Target != "" && !globMatch(f.Target, mr.TargetBranch) {
+		return false
+	}
+	if f.Branch != "" && !globMatch(f.Branch, mr.Branch) {
+		return false
+	}
+	if f.Issue != "" && mr.IssueID != f.Issue {
+		return false
+	}
+	return true
+}
+
+func globMatch(pattern, s string) bool {
+	ok, err := path.Match(pattern, s)
+	return err == nil && ok
+}
+
+// SelectMRs returns the queued MRs matching filter, in queue order.
+func (m *Manager) SelectMRs(filter QueueFilter) ([]*MergeRequest, error) {
+	queue, err := m.Queue()
+	if err != nil {
+		return nil, err
+	}
+	var mrs []*MergeRequest
+	for _, item := range queue {
+		if filter.Matches(item.MR) {
+			mrs = append(mrs, item.MR)
+		}
+	}
+	return mrs, nil
+}
+
+// BulkMRResult reports the outcome of a bulk MR operation.
+type BulkMRResult struct {
+	Done   []string          // MR IDs changed
+	Failed map[string]string // MR ID → error
+}
+
+// CancelOptions controls CancelMR.
+type CancelOptions struct {
+	Reason     string
+	CloseIssue bool // Also close the MR's source issue
+	KeepBranch bool // Leave the polecat branch on origin
+}
+
+// CancelResult reports what CancelMR did besides closing the MR.
+type CancelResult struct {
+	IssueClosed   bool
+	BranchDeleted bool
+}
+
+// cancelBeads and cancelGit are the subsets of Beads and Git CancelMR uses.
+type cancelBeads interface {
+	CloseWithReason(reason string, ids ...string) error
+	Reopen(id, reason string) error
+}
+
+type cancelGit interface {
+	RemoteBranchExists(remote, branch string) (bool, error)
+	DeleteRemoteBranch(remote, branch string) error
+}
+
+// CancelMR removes mr from the queue by closing it as cancelled, optionally
+// closes its source issue, and deletes its polecat branch from origin.
+// Unlike RejectMR, the worker is not notified. The steps are all-or-nothing:
+// if one fails, the beads already closed are reopened. Branch deletion goes
+// last because it can't be undone.
+func (m *Manager) CancelMR(mr *MergeRequest, opts CancelOptions) (*CancelResult, error) {
+	return cancelMR(beads.New(m.rig.BeadsPath()), rigRepoGit(m.rig.Path), mr, opts)
+}
+
+func cancelMR(b cancelBeads, g cancelGit, mr *MergeRequest, opts CancelOptions) (*CancelResult, error) {
+	result := &CancelResult{}
+	reason := string(CloseReasonCancelled) + ": " + opts.Reason
+	if err := b.CloseWithReason(reason, mr.ID); err != nil {
+		return nil, fmt.Errorf("closing MR: %w", err)
+	}
+	rollback := func(cause error) (*CancelResult, error) {
+		var errs []string
+		if result.IssueClosed {
+			if err := b.Reopen(mr.IssueID, "MR cancel rolled back"); err != nil {
+				errs = append(errs, fmt.Sprintf("reopening %s: %v", mr.IssueID, err))
+			}
+		}
+		if err := b.Reopen(mr.ID, "MR cancel rolled back"); err != nil {
+			errs = append(errs, fmt.Sprintf("reopening %s: %v", mr.ID, err))
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%w (rollback failed, repair manually: %s)", cause, strings.Join(errs, "; "))
+		}
+		return nil, cause
+	}
+
+	if opts.CloseIssue && mr.IssueID != "" {
+		if err := b.CloseWithReason(fmt.Sprintf("MR %s %s", mr.ID, reason), mr.IssueID); err != nil {
+			return rollback(fmt.Errorf("closing %s: %w", mr.IssueID, err))
+		}
+		result.IssueClosed = true
+	}
+
+	if !opts.KeepBranch && isPolecatBranch(mr) {
+		exists, err := g.RemoteBranchExists("origin", mr.Branch)
+		if err != nil {
+			return rollback(fmt.Errorf("checking origin/%s: %w", mr.Branch, err))
+		}
+		if exists {
+			if err := g.DeleteRemoteBranch("origin", mr.Branch); err != nil {
+				return rollback(fmt.Errorf("deleting origin/%s: %w", mr.Branch, err))
+			}
+			result.BranchDeleted = true
+		}
+	}
+
+	if err := mr.Close(CloseReasonCancelled); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// isPolecatBranch reports whether mr's branch is a polecat work branch,
+// the only kind CancelMR deletes.
+func isPolecatBranch(mr *MergeRequest) bool {
+	return strings.HasPrefix(mr.Branch, constants.BranchPolecatPrefix) && mr.Branch != mr.TargetBranch
+}
+
+// rigRepoGit returns a Git for ref operations on the rig's repository: the
+// shared bare repo if the rig has one, otherwise the mayor's clone.
+func rigRepoGit(rigPath string) *git.Git {
+	bare := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bare); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bare, "")
+	}
+	return git.NewGit(filepath.Join(rigPath, "mayor", "rig"))
+}
+
+// CancelMRs cancels each MR with CancelMR. Failures don't stop the sweep.
+func (m *Manager) CancelMRs(mrs []*MergeRequest, opts CancelOptions) *BulkMRResult {
+	return bulkMRs(mrs, func(mr *MergeRequest) error {
+		_, err := m.CancelMR(mr, opts)
+		return err
+	})
+}
+
+// HoldMRs sets or clears HeldLabel on each MR. The refinery skips held MRs.
+func (m *Manager) HoldMRs(mrs []*MergeRequest, hold bool) *BulkMRResult {
+	b := beads.New(m.rig.BeadsPath())
+	opts := beads.UpdateOptions{RemoveLabels: []string{HeldLabel}}
+	if hold {
+		opts = beads.UpdateOptions{AddLabels: []string{HeldLabel}}
+	}
+	return bulkMRs(mrs, func(mr *MergeRequest) error {
+		if err := b.Update(mr.ID, opts); err != nil {
+			return err
+		}
+		mr.Held = hold
+		return nil
+	})
+}
+
+func bulkMRs(mrs []*MergeRequest, fn func(*MergeRequest) error) *BulkMRResult {
+	result := &BulkMRResult{Failed: make(map[string]string)}
+	for _, mr := range mrs {
+		if err := fn(mr); err != nil {
+			result.Failed[mr.ID] = err.Error()
+			continue
+		}
+		result.Done = append(result.Done, mr.ID)
+	}
+	return result
+}
+
+// FailedIDs returns the IDs of MRs that failed, sorted.
+func (r *BulkMRResult) FailedIDs() []string {
+	ids := make([]string, 0, len(r.Failed))
+	for id := range r.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,178 @@
+package refinery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseQueueFilter(t *testing.T) {
+	f, err := ParseQueueFilter([]string{"worker=polecat-7", "Target = release/1.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Worker != "polecat-7" || f.Target != "release/1.3" {
+		t.Errorf("filter = %+v", f)
+	}
+
+	for expr, want := range map[string]string{
+		"worker":       "expected key=value",
+		"worker=":      "expected key=value",
+		"color=red":    "unknown filter key",
+		"branch=[oops": "invalid branch glob",
+	} {
+		if _, err := ParseQueueFilter([]string{expr}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseQueueFilter(%q) error = %v, want containing %q", expr, err, want)
+		}
+	}
+	if _, err := ParseQueueFilter([]string{"worker=a", "worker=b"}); err == nil {
+		t.Error("repeated key should be an error")
+	}
+}
+
+func TestQueueFilter_Matches(t *testing.T) {
+	mr := &MergeRequest{
+		ID:           "gt-mr1",
+		Branch:       "polecat/Toast/gt-abc",
+		Worker:       "gastown/polecats/Toast",
+		IssueID:      "gt-abc",
+		TargetBranch: "release/1.3",
+	}
+	tests := []struct {
+		filter QueueFilter
+		want   bool
+	}{
+		{QueueFilter{}, true},
+		{QueueFilter{Worker: "toast"}, true},
+		{QueueFilter{Worker: "gastown/polecats/Toast"}, true},
+		{QueueFilter{Worker: "Nux"}, false},
+		{QueueFilter{Target: "release/*"}, true},
+		{QueueFilter{Target: "main"}, false},
+		{QueueFilter{Branch: "polecat/Toast/*"}, true},
+		{QueueFilter{Issue: "gt-abc", Target: "release/1.3"}, true},
+		{QueueFilter{Issue: "gt-abc", Target: "main"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Matches(mr); got != tt.want {
+			t.Errorf("%+v.Matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestBulkMRs_ContinuesPastFailures(t *testing.T) {
+	mrs := []*MergeRequest{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	result := bulkMRs(mrs, func(mr *MergeRequest) error {
+		if mr.ID == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if strings.Join(result.Done, ",") != "a,c" {
+		t.Errorf("Done = %v, want [a c]", result.Done)
+	}
+	if ids := result.FailedIDs(); len(ids) != 1 || ids[0] != "b" || result.Failed["b"] != "boom" {
+		t.Errorf("Failed = %v", result.Failed)
+	}
+}
+
+type fakeCancelBeads struct {
+	closed   []string
+	reopened []string
+	failOn   string
+}
+
+func (f *fakeCancelBeads) CloseWithReason(reason string, ids ...string) error {
+	for _, id := range ids {
+		if id == f.failOn {
+			return errors.New("close failed")
+		}
+		f.closed = append(f.closed, id)
+	}
+	return nil
+}
+
+func (f *fakeCancelBeads) Reopen(id, reason string) error {
+	f.reopened = append(f.reopened, id)
+	return nil
+}
+
+type fakeCancelGit struct {
+	branches map[string]bool
+	failDel  bool
+}
+
+func (f *fakeCancelGit) RemoteBranchExists(remote, branch string) (bool, error) {
+	return f.branches[branch], nil
+}
+
+func (f *fakeCancelGit) DeleteRemoteBranch(remote, branch string) error {
+	if f.failDel {
+		return errors.New("push rejected")
+	}
+	delete(f.branches, branch)
+	return nil
+}
+
+func TestCancelMR(t *testing.T) {
+	b := &fakeCancelBeads{}
+	g := &fakeCancelGit{branches: map[string]bool{"polecat/Toast/gt-abc": true}}
+	mr := &MergeRequest{ID: "gt-mr1", IssueID: "gt-abc", Branch: "polecat/Toast/gt-abc", TargetBranch: "main", Status: MROpen}
+
+	result, err := cancelMR(b, g, mr, CancelOptions{Reason: "abandoned", CloseIssue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IssueClosed || !result.BranchDeleted {
+		t.Errorf("result = %+v, want issue closed and branch deleted", result)
+	}
+	if strings.Join(b.closed, ",") != "gt-mr1,gt-abc" {
+		t.Errorf("closed = %v", b.closed)
+	}
+	if g.branches["polecat/Toast/gt-abc"] {
+		t.Error("branch still on origin")
+	}
+	if mr.Status != MRClosed || mr.CloseReason != CloseReasonCancelled {
+		t.Errorf("mr = %s/%s, want closed/cancelled", mr.Status, mr.CloseReason)
+	}
+}
+
+func TestCancelMR_KeepBranchAndNonPolecatBranch(t *testing.T) {
+	for _, mr := range []*MergeRequest{
+		{ID: "gt-mr1", Branch: "polecat/Toast/gt-abc", TargetBranch: "main", Status: MROpen},
+		{ID: "gt-mr2", Branch: "feature/x", TargetBranch: "main", Status: MROpen},
+	} {
+		g := &fakeCancelGit{branches: map[string]bool{mr.Branch: true}}
+		opts := CancelOptions{KeepBranch: mr.ID == "gt-mr1"}
+		result, err := cancelMR(&fakeCancelBeads{}, g, mr, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.BranchDeleted || !g.branches[mr.Branch] {
+			t.Errorf("%s: branch %s was deleted", mr.ID, mr.Branch)
+		}
+	}
+}
+
+func TestCancelMR_RollsBackOnFailure(t *testing.T) {
+	b := &fakeCancelBeads{}
+	g := &fakeCancelGit{branches: map[string]bool{"polecat/Toast/gt-abc": true}, failDel: true}
+	mr := &MergeRequest{ID: "gt-mr1", IssueID: "gt-abc", Branch: "polecat/Toast/gt-abc", TargetBranch: "main", Status: MROpen}
+
+	if _, err := cancelMR(b, g, mr, CancelOptions{CloseIssue: true}); err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Join(b.reopened, ",") != "gt-abc,gt-mr1" {
+		t.Errorf("reopened = %v, want issue then MR", b.reopened)
+	}
+	if mr.Status != MROpen {
+		t.Errorf("mr status = %s, want open", mr.Status)
+	}
+
+	b = &fakeCancelBeads{failOn: "gt-abc"}
+	if _, err := cancelMR(b, g, mr, CancelOptions{CloseIssue: true}); err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Join(b.reopened, ",") != "gt-mr1" {
+		t.Errorf("reopened = %v, want only the MR", b.reopened)
+	}
+}
@@ -0,0 +1,216 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// ChaosEnvVar enables merge queue fault injection for resilience testing.
+// Never set it on a production rig. The value is either "1" (default rates)
+// or a comma-separated spec:
+//
+//	GT_REFINERY_CHAOS="delay=0.3,fail=0.1,dup=0.1,kill=0.2,max-delay=2s,seed=42"
+//
+// Rates are probabilities (0-1) applied per operation:
+//   - delay: sleep up to max-delay before a queue operation
+//   - fail: fail a queue operation, either before it runs or after it has
+//     taken effect (a lost response)
+//   - dup: run a queue operation twice, as a retried request would
+//   - kill: kill a test or gate command after up to max-delay
+//
+// Queue operations are merge slot ensure/acquire/release and MR claim/release.
+const ChaosEnvVar = "GT_REFINERY_CHAOS"
+
+// errChaosInjected marks failures injected by chaos mode.
+var errChaosInjected = errors.New("chaos: injected failure")
+
+// ChaosConfig holds fault injection rates for chaos mode.
+type ChaosConfig struct {
+	DelayRate float64
+	FailRate  float64
+	DupRate   float64
+	KillRate  float64
+	MaxDelay  time.Duration
+	Seed      int64 // 0 = seed from the clock
+}
+
+// DefaultChaosConfig returns the rates used for GT_REFINERY_CHAOS=1.
+func DefaultChaosConfig() ChaosConfig {
+	return ChaosConfig{
+		DelayRate: 0.2,
+		FailRate:  0.1,
+		DupRate:   0.1,
+		KillRate:  0.1,
+		MaxDelay:  time.Second,
+	}
+}
+
+// ParseChaosConfig parses a GT_REFINERY_CHAOS value. Keys not given in the
+// spec are zero, so "kill=1" only kills gates.
+func ParseChaosConfig(spec string) (ChaosConfig, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "1", "true", "on":
+		return DefaultChaosConfig(), nil
+	}
+
+	cfg := ChaosConfig{MaxDelay: DefaultChaosConfig().MaxDelay}
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos setting %q: expected key=value", part)
+		}
+		var rate *float64
+		switch key {
+		case "delay":
+			rate = &cfg.DelayRate
+		case "fail":
+			rate = &cfg.FailRate
+		case "dup":
+			rate = &cfg.DupRate
+		case "kill":
+			rate = &cfg.KillRate
+		case "max-delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return ChaosConfig{}, fmt.Errorf("invalid chaos max-delay %q", value)
+			}
+			cfg.MaxDelay = d
+			continue
+		case "seed":
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ChaosConfig{}, fmt.Errorf("invalid chaos seed %q", value)
+			}
+			cfg.Seed = seed
+			continue
+		default:
+			return ChaosConfig{}, fmt.Errorf("unknown chaos setting %q (want delay, fail, dup, kill, max-delay, seed)", key)
+		}
+		r, err := strconv.ParseFloat(value, 64)
+		if err != nil || r < 0 || r > 1 {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos %s rate %q: must be between 0 and 1", key, value)
+		}
+		*rate = r
+	}
+	return cfg, nil
+}
+
+// chaos injects faults into queue operations. A nil *chaos injects nothing.
+type chaos struct {
+	cfg  ChaosConfig
+	logf func(format string, args ...interface{})
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newChaos(cfg ChaosConfig, logf func(format string, args ...interface{})) *chaos {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &chaos{cfg: cfg, logf: logf, rng: rand.New(rand.NewSource(seed))} //nolint:gosec // G404: fault injection, not security
+}
+
+// roll reports whether an event with the given probability fires.
+func (c *chaos) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+// randDelay returns a random duration in [0, MaxDelay).
+func (c *chaos) randDelay() time.Duration {
+	if c.cfg.MaxDelay <= 0 {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Duration(c.rng.Int63n(int64(c.cfg.MaxDelay)))
+}
+
+// do runs a queue operation with injected delay, failure, and duplication.
+func (c *chaos) do(op string, fn func() error) error {
+	if c == nil {
+		return fn()
+	}
+	if c.roll(c.cfg.DelayRate) {
+		d := c.randDelay()
+		c.logf("delaying %s by %v", op, d)
+		time.Sleep(d)
+	}
+	fail := c.roll(c.cfg.FailRate)
+	lostResponse := fail && c.roll(0.5)
+	if fail && !lostResponse {
+		c.logf("failing %s before it runs", op)
+		return fmt.Errorf("%s: %w", op, errChaosInjected)
+	}
+	err := fn()
+	if c.roll(c.cfg.DupRate) {
+		c.logf("duplicating %s", op)
+		_ = fn() // A retried request; the caller sees the first response
+	}
+	if lostResponse && err == nil {
+		c.logf("failing %s after it took effect", op)
+		return fmt.Errorf("%s: %w", op, errChaosInjected)
+	}
+	return err
+}
+
+// killContext returns a context that, when chaos decides to kill the
+// command, is canceled after a random delay.
+func (c *chaos) killContext(ctx context.Context, what string) (context.Context, context.CancelFunc) {
+	if c == nil || !c.roll(c.cfg.KillRate) {
+		return ctx, func() {}
+	}
+	d := c.randDelay()
+	c.logf("killing %s after %v", what, d)
+	return context.WithTimeout(ctx, d)
+}
+
+// EnableChaos turns on fault injection for this engineer's queue operations
+// and test/gate runs. NewEngineer calls it when GT_REFINERY_CHAOS is set;
+// tests call it after installing their merge slot fakes.
+func (e *Engineer) EnableChaos(cfg ChaosConfig) {
+	c := newChaos(cfg, func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] chaos: "+format+"\n", args...)
+	})
+	e.chaos = c
+
+	ensure, acquire, release := e.mergeSlotEnsureExists, e.mergeSlotAcquire, e.mergeSlotRelease
+	e.mergeSlotEnsureExists = func() (id string, err error) {
+		err = c.do("merge-slot ensure", func() error {
+			id, err = ensure()
+			return err
+		})
+		return id, err
+	}
+	e.mergeSlotAcquire = func(holder string, addWaiter bool) (status *beads.MergeSlotStatus, err error) {
+		err = c.do("merge-slot acquire", func() error {
+			s, acqErr := acquire(holder, addWaiter)
+			if status == nil {
+				status = s // Keep the first response when duplicated
+			}
+			return acqErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+	e.mergeSlotRelease = func(holder string) error {
+		return c.do("merge-slot release", func() error { return release(holder) })
+	}
+}
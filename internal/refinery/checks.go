@@ -0,0 +1,399 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/version"
+)
+
+// Required check kinds an MR can declare (MR field "required_checks").
+const (
+	CheckKindTest   = "test"   // "test" runs all rig gates (or test_command); "test:<gate>" runs one gate
+	CheckKindGitHub = "gh"     // "gh:<workflow>" requires a successful GitHub Actions run for the branch head
+	CheckKindScript = "script" // "script:<path>" runs a script committed in the repo
+)
+
+// Required check states.
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckPending = "pending"
+)
+
+// CheckSpec is one required check declared by an MR.
+type CheckSpec struct {
+	Kind string
+	Arg  string // Gate name, workflow, or script path (may be empty for "test")
+}
+
+// String returns the spec in its declared form (e.g., "gh:ci.yml").
+func (c CheckSpec) String() string {
+	if c.Arg == "" {
+		return c.Kind
+	}
+	return c.Kind + ":" + c.Arg
+}
+
+// safeCheckArg restricts check arguments so they can be passed to a shell
+// without quoting.
+var safeCheckArg = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// ParseCheckSpec parses a single check spec.
+func ParseCheckSpec(s string) (CheckSpec, error) {
+	s = strings.TrimSpace(s)
+	kind, arg, _ := strings.Cut(s, ":")
+	spec := CheckSpec{Kind: kind, Arg: arg}
+
+	if arg != "" && !safeCheckArg.MatchString(arg) {
+		return spec, fmt.Errorf("check %q: argument may only contain letters, digits, '.', '_', '-', '/'", s)
+	}
+	switch kind {
+	case CheckKindTest:
+	case CheckKindGitHub:
+		if arg == "" {
+			return spec, fmt.Errorf("check %q: gh requires a workflow (gh:<workflow>)", s)
+		}
+	case CheckKindScript:
+		if arg == "" || !filepath.IsLocal(arg) {
+			return spec, fmt.Errorf("check %q: script requires a path inside the repo (script:<path>)", s)
+		}
+	default:
+		return spec, fmt.Errorf("check %q: unknown kind %q (want test, gh, or script)", s, kind)
+	}
+	return spec, nil
+}
+
+// ParseCheckSpecs parses a comma-separated list of check specs.
+func ParseCheckSpecs(list string) ([]CheckSpec, error) {
+	var specs []CheckSpec
+	for _, part := range strings.Split(list, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		spec, err := ParseCheckSpec(part)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// CheckResult is the outcome of one required check.
+type CheckResult struct {
+	Check  string `json:"check"`
+	State  string `json:"state"` // pass | fail | pending
+	Detail string `json:"detail,omitempty"`
+}
+
+// FormatCheckResults renders results for the MR's check_results field.
+func FormatCheckResults(results []CheckResult) string {
+	parts := make([]string, len(results))
+	for i, r := range results {
+		parts[i] = r.Check + "=" + r.State
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseCheckResults parses an MR's check_results field.
+func ParseCheckResults(s string) []CheckResult {
+	var results []CheckResult
+	for _, part := range strings.Split(s, ",") {
+		check, state, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || check == "" {
+			continue
+		}
+		results = append(results, CheckResult{Check: check, State: state})
+	}
+	return results
+}
+
+// runRemoteChecks reads an MR's GitHub status checks. The refinery runs it
+// before the merge gates (step 4), so an MR waiting on CI doesn't re-run its
+// test suite on every poll. The other checks are marked pending while any
+// GitHub check is pending or failed, and left blank for runLocalChecks
+// otherwise.
+func (e *Engineer) runRemoteChecks(ctx context.Context, branch string, specs []CheckSpec) []CheckResult {
+	results := make([]CheckResult, len(specs))
+	remoteOK := true
+
+	var sha string
+	for i, spec := range specs {
+		results[i] = CheckResult{Check: spec.String()}
+		if spec.Kind != CheckKindGitHub {
+			continue
+		}
+		if sha == "" {
+			var err error
+			if sha, err = e.git.Rev(branch); err != nil {
+				results[i].State, results[i].Detail = CheckFail, fmt.Sprintf("resolving %s: %v", branch, err)
+				e.logCheckResult(results[i])
+				remoteOK = false
+				continue
+			}
+		}
+		status := e.ghRunStatus
+		if status == nil {
+			status = githubRunStatus
+		}
+		results[i].State, results[i].Detail = status(ctx, e.workDir, spec.Arg, sha)
+		e.logCheckResult(results[i])
+		if results[i].State != CheckPass {
+			remoteOK = false
+		}
+	}
+
+	if !remoteOK {
+		for i, spec := range specs {
+			if spec.Kind != CheckKindGitHub {
+				results[i].State, results[i].Detail = CheckPending, "waiting for GitHub checks"
+			}
+		}
+	}
+	return results
+}
+
+// runLocalChecks fills in the test and script checks left blank by
+// runRemoteChecks. A test check whose gates all passed in step 4
+// (passedGates) reuses that result; the others run in a temporary worktree
+// of the branch.
+func (e *Engineer) runLocalChecks(ctx context.Context, branch string, specs []CheckSpec, results []CheckResult, passedGates map[string]bool) []CheckResult {
+	var worktree string
+	defer func() {
+		if worktree != "" {
+			_ = e.git.WorktreeRemove(worktree, true)
+			_ = os.RemoveAll(worktree)
+		}
+	}()
+
+	for i, spec := range specs {
+		if spec.Kind == CheckKindGitHub || results[i].State != "" {
+			continue
+		}
+		results[i] = CheckResult{Check: spec.String()}
+		if spec.Kind == CheckKindTest {
+			if gates, detail := e.testCheckGates(spec); gates == nil {
+				results[i].State, results[i].Detail = CheckFail, detail
+				e.logCheckResult(results[i])
+				continue
+			} else if allPassed(gates, passedGates) {
+				results[i].State, results[i].Detail = CheckPass, "passed in merge gates"
+				e.logCheckResult(results[i])
+				continue
+			}
+		}
+		if worktree == "" {
+			dir, err := os.MkdirTemp("", "gt-checks-")
+			if err == nil {
+				err = e.git.WorktreeAddDetached(dir, branch)
+			}
+			if err != nil {
+				results[i].State, results[i].Detail = CheckFail, fmt.Sprintf("creating worktree for %s: %v", branch, err)
+				_ = os.RemoveAll(dir)
+				e.logCheckResult(results[i])
+				continue
+			}
+			worktree = dir
+		}
+		results[i].State, results[i].Detail = e.runLocalCheck(ctx, worktree, spec)
+		e.logCheckResult(results[i])
+	}
+	return results
+}
+
+func (e *Engineer) logCheckResult(r CheckResult) {
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Required check %q: %s", r.Check, r.State)
+	if r.Detail != "" {
+		_, _ = fmt.Fprintf(e.output, " (%s)", r.Detail)
+	}
+	_, _ = fmt.Fprintln(e.output)
+}
+
+// allPassed reports whether every gate name in gates is in passed.
+func allPassed(gates map[string]*GateConfig, passed map[string]bool) bool {
+	for name := range gates {
+		if !passed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// testCheckGates returns the gates a test check runs: the named gate, or
+// all rig gates (falling back to test_command). On error it returns nil and
+// a failure detail.
+func (e *Engineer) testCheckGates(spec CheckSpec) (map[string]*GateConfig, string) {
+	switch {
+	case spec.Arg != "":
+		gate, ok := e.config.Gates[spec.Arg]
+		if !ok {
+			return nil, fmt.Sprintf("no gate named %q in rig merge_queue config", spec.Arg)
+		}
+		return map[string]*GateConfig{spec.Arg: gate}, ""
+	case len(e.config.Gates) > 0:
+		return e.config.Gates, ""
+	case e.config.TestCommand != "":
+		return map[string]*GateConfig{"test_command": {Cmd: e.config.TestCommand}}, ""
+	default:
+		return nil, "rig has no gates or test_command configured"
+	}
+}
+
+// runLocalCheck runs a test or script check in dir.
+func (e *Engineer) runLocalCheck(ctx context.Context, dir string, spec CheckSpec) (state, detail string) {
+	var gates map[string]*GateConfig
+	if spec.Kind == CheckKindScript {
+		path := filepath.Join(dir, spec.Arg)
+		info, err := os.Stat(path)
+		if err != nil {
+			return CheckFail, fmt.Sprintf("script not found in branch: %s", spec.Arg)
+		}
+		cmd := "sh " + spec.Arg
+		if info.Mode()&0111 != 0 {
+			cmd = "./" + spec.Arg // Executable: honor its shebang
+		}
+		gates = map[string]*GateConfig{spec.String(): {Cmd: cmd}}
+	} else {
+		var detail string
+		if gates, detail = e.testCheckGates(spec); gates == nil {
+			return CheckFail, detail
+		}
+	}
+
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if r := e.runGateIn(ctx, dir, name, gates[name]); !r.Success {
+			return CheckFail, fmt.Sprintf("%s: %s", name, r.Error)
+		}
+	}
+	return CheckPass, ""
+}
+
+// githubRunStatus reports the latest GitHub Actions run of workflow for a
+// commit, using the gh CLI from the repo in dir.
+func githubRunStatus(ctx context.Context, dir, workflow, sha string) (state, detail string) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "gh", "run", "list", //nolint:gosec // G204: workflow is validated by ParseCheckSpec
+		"--workflow", workflow, "--commit", sha,
+		"--json", "status,conclusion,url", "--limit", "1")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		msg := err.Error()
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			msg = strings.TrimSpace(string(ee.Stderr))
+		}
+		return CheckFail, fmt.Sprintf("gh run list: %s", msg)
+	}
+
+	var runs []struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+		URL        string `json:"url"`
+	}
+	if err := json.Unmarshal(out, &runs); err != nil {
+		return CheckFail, fmt.Sprintf("parsing gh output: %v", err)
+	}
+	if len(runs) == 0 {
+		return CheckPending, fmt.Sprintf("no run yet for %s", version.ShortCommit(sha))
+	}
+	run := runs[0]
+	if run.Status != "completed" {
+		return CheckPending, run.Status + " " + run.URL
+	}
+	switch run.Conclusion {
+	case "success", "skipped", "neutral":
+		return CheckPass, ""
+	default:
+		return CheckFail, run.Conclusion + " " + run.URL
+	}
+}
+
+// checksProcessResult turns check results into a blocking ProcessResult.
+// Any failure fails the merge; otherwise any pending check defers it.
+func checksProcessResult(results []CheckResult) (ProcessResult, bool) {
+	var failed, pending []string
+	for _, r := range results {
+		switch r.State {
+		case CheckFail:
+			failed = append(failed, fmt.Sprintf("%s: %s", r.Check, r.Detail))
+		case CheckPending:
+			pending = append(pending, r.Check)
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		return ProcessResult{
+			TestsFailed: true,
+			Checks:      results,
+			Error:       fmt.Sprintf("required checks failed: %s", strings.Join(failed, "; ")),
+		}, false
+	case len(pending) > 0:
+		return ProcessResult{
+			ChecksPending: true,
+			Checks:        results,
+			Error:         fmt.Sprintf("waiting on required checks: %s", strings.Join(pending, ", ")),
+		}, false
+	}
+	return ProcessResult{}, true
+}
+
+// recordCheckResults stores check results on the MR bead. Failure details
+// are attached as a comment when the results change, so a retried MR does
+// not accumulate identical comments.
+func (e *Engineer) recordCheckResults(mrID string, results []CheckResult) {
+	if mrID == "" || len(results) == 0 {
+		return
+	}
+	mrBead, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR %s to record checks: %v\n", mrID, err)
+		return
+	}
+	fields := beads.ParseMRFields(mrBead)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+
+	formatted := FormatCheckResults(results)
+	changed := fields.CheckResults != formatted
+	fields.CheckResults = formatted
+	fields.ChecksAt = time.Now().UTC().Format(time.RFC3339)
+	newDesc := beads.SetMRFields(mrBead, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record check results on MR %s: %v\n", mrID, err)
+		return
+	}
+
+	if !changed {
+		return
+	}
+	var lines []string
+	for _, r := range results {
+		if r.State == CheckFail {
+			lines = append(lines, fmt.Sprintf("- %s: %s", r.Check, r.Detail))
+		}
+	}
+	if len(lines) > 0 {
+		comment := "Required checks failed:\n" + strings.Join(lines, "\n")
+		if err := e.beads.AddComment(mrID, comment); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to comment check failures on MR %s: %v\n", mrID, err)
+		}
+	}
+}
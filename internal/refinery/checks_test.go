@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestParseCheckSpecs(t *testing.T) {
+	specs, err := ParseCheckSpecs("test, test:lint, gh:ci.yml, script:scripts/e2e.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range specs {
+		got = append(got, s.String())
+	}
+	if strings.Join(got, ",") != "test,test:lint,gh:ci.yml,script:scripts/e2e.sh" {
+		t.Errorf("specs = %v", got)
+	}
+
+	for _, bad := range []string{
+		"deploy",
+		"gh",
+		"script:../outside.sh",
+		"script:/etc/passwd",
+		"script:x.sh;rm -rf /",
+		"gh:ci.yml $(id)",
+	} {
+		if _, err := ParseCheckSpecs(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCheckResultsRoundTrip(t *testing.T) {
+	results := []CheckResult{
+		{Check: "test", State: CheckPass},
+		{Check: "gh:ci.yml", State: CheckPending, Detail: "in_progress"},
+	}
+	formatted := FormatCheckResults(results)
+	if formatted != "test=pass, gh:ci.yml=pending" {
+		t.Errorf("formatted = %q", formatted)
+	}
+	parsed := ParseCheckResults(formatted)
+	if len(parsed) != 2 || parsed[1].Check != "gh:ci.yml" || parsed[1].State != CheckPending {
+		t.Errorf("parsed = %+v", parsed)
+	}
+}
+
+// initChecksRepo creates a repo with a "feature" branch carrying check scripts.
+func initChecksRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test User")
+	run("commit", "-q", "--allow-empty", "-m", "initial")
+	run("checkout", "-q", "-b", "feature")
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scripts", "ok.sh"), []byte("test -f scripts/ok.sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scripts", "bad.sh"), []byte("#!/bin/sh\nexit 3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "add scripts")
+	run("checkout", "-q", "-")
+	return dir
+}
+
+func TestRunRequiredChecks(t *testing.T) {
+	repo := initChecksRepo(t)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.git = git.NewGit(repo)
+	e.workDir = repo
+	e.output = io.Discard
+	e.config.Gates = map[string]*GateConfig{"unit": {Cmd: "true"}}
+
+	ghState := CheckPending
+	e.ghRunStatus = func(_ context.Context, _, workflow, sha string) (string, string) {
+		if workflow != "ci.yml" || len(sha) != 40 {
+			t.Errorf("gh status called with %q, %q", workflow, sha)
+		}
+		return ghState, ""
+	}
+
+	specs, err := ParseCheckSpecs("gh:ci.yml, test, script:scripts/ok.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// CI still running: local checks wait, merge is deferred.
+	results := e.runRemoteChecks(context.Background(), "feature", specs)
+	if got := FormatCheckResults(results); got != "gh:ci.yml=pending, test=pending, script:scripts/ok.sh=pending" {
+		t.Errorf("pending results = %q", got)
+	}
+	if res, ok := checksProcessResult(results); ok || !res.ChecksPending || res.TestsFailed {
+		t.Errorf("pending ProcessResult = %+v", res)
+	}
+
+	// CI passed: local checks run against the branch.
+	ghState = CheckPass
+	results = e.runRemoteChecks(context.Background(), "feature", specs)
+	if got := FormatCheckResults(results); got != "gh:ci.yml=pass, test=, script:scripts/ok.sh=" {
+		t.Errorf("remote results = %q", got)
+	}
+	results = e.runLocalChecks(context.Background(), "feature", specs, results, nil)
+	if got := FormatCheckResults(results); got != "gh:ci.yml=pass, test=pass, script:scripts/ok.sh=pass" {
+		t.Errorf("passing results = %q (%+v)", got, results)
+	}
+	if _, ok := checksProcessResult(results); !ok {
+		t.Error("expected all checks to pass")
+	}
+
+	// A failing script fails the merge.
+	specs, _ = ParseCheckSpecs("script:scripts/bad.sh, script:scripts/missing.sh")
+	results = e.runLocalChecks(context.Background(), "feature", specs, e.runRemoteChecks(context.Background(), "feature", specs), nil)
+	res, ok := checksProcessResult(results)
+	if ok || !res.TestsFailed || res.ChecksPending {
+		t.Errorf("failing ProcessResult = %+v", res)
+	}
+	if !strings.Contains(res.Error, "missing.sh") {
+		t.Errorf("error = %q", res.Error)
+	}
+
+	// Test checks reuse the merge gates' results instead of re-running.
+	e.config.Gates = map[string]*GateConfig{"unit": {Cmd: "exit 1"}}
+	specs, _ = ParseCheckSpecs("test, test:unit")
+	results = e.runLocalChecks(context.Background(), "feature", specs, make([]CheckResult, len(specs)), map[string]bool{"unit": true})
+	if got := FormatCheckResults(results); got != "test=pass, test:unit=pass" {
+		t.Errorf("reused gate results = %q", got)
+	}
+
+	// Worktrees are cleaned up.
+	worktrees, err := e.git.WorktreeList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 1 {
+		t.Errorf("leftover worktrees: %+v", worktrees)
+	}
+}
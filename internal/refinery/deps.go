@@ -0,0 +1,212 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Dependency updates: an MR with a dep_update spec (gt mq bump) has no
+// worker branch. Bumping a submodule pointer or a go.mod requirement on a
+// branch conflicts whenever anything else touches the same lines, so the
+// refinery makes the change itself: on every attempt it cuts a scratch
+// branch from the current target and applies the spec there. A retry after
+// the target moved regenerates the change instead of conflicting. The
+// result goes through the gates and the merge like any other branch.
+
+// depsBranchPrefix names the scratch branch a dependency update is made on.
+const depsBranchPrefix = "gt-deps/"
+
+// Dependency update kinds.
+const (
+	DepKindSubmodule = "submodule" // Move a submodule pointer to a ref
+	DepKindGoMod     = "gomod"     // go get module@version, then go mod tidy
+)
+
+// DepSpec is one dependency update: "submodule <path>@<ref>" or
+// "gomod <module>@<version> [dir=<dir>]".
+type DepSpec struct {
+	Kind    string
+	Path    string // Submodule path, or Go module path
+	Version string // Submodule ref (commit, tag, branch), or module version
+	Dir     string // gomod: directory of the go.mod, relative to the repo root
+}
+
+// ParseDepSpecs parses a semicolon-separated list of dependency specs.
+func ParseDepSpecs(s string) ([]DepSpec, error) {
+	var specs []DepSpec
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		spec, err := parseDepSpec(part)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no dependency updates given")
+	}
+	return specs, nil
+}
+
+func parseDepSpec(s string) (DepSpec, error) {
+	words := strings.Fields(s)
+	if len(words) < 2 {
+		return DepSpec{}, fmt.Errorf("invalid dependency update %q: want \"<kind> <path>@<version>\"", s)
+	}
+	spec := DepSpec{Kind: words[0]}
+	at := strings.LastIndex(words[1], "@")
+	if at <= 0 || at == len(words[1])-1 {
+		return DepSpec{}, fmt.Errorf("invalid dependency update %q: want <path>@<version>", s)
+	}
+	spec.Path, spec.Version = words[1][:at], words[1][at+1:]
+
+	for _, opt := range words[2:] {
+		dir, ok := strings.CutPrefix(opt, "dir=")
+		if !ok || spec.Kind != DepKindGoMod {
+			return DepSpec{}, fmt.Errorf("invalid dependency update %q: unexpected %q", s, opt)
+		}
+		spec.Dir = dir
+	}
+
+	switch spec.Kind {
+	case DepKindSubmodule, DepKindGoMod:
+	default:
+		return DepSpec{}, fmt.Errorf("invalid dependency update %q: kind must be %s or %s", s, DepKindSubmodule, DepKindGoMod)
+	}
+	for _, p := range []string{spec.Path, spec.Dir} {
+		if filepath.IsAbs(p) || strings.HasPrefix(p, "-") || strings.Contains(filepath.ToSlash(p), "..") {
+			return DepSpec{}, fmt.Errorf("invalid dependency update %q: paths must be relative to the repo", s)
+		}
+	}
+	if strings.HasPrefix(spec.Version, "-") {
+		return DepSpec{}, fmt.Errorf("invalid dependency update %q: bad version", s)
+	}
+	return spec, nil
+}
+
+// String formats the spec as ParseDepSpecs reads it.
+func (d DepSpec) String() string {
+	s := d.Kind + " " + d.Path + "@" + d.Version
+	if d.Dir != "" {
+		s += " dir=" + d.Dir
+	}
+	return s
+}
+
+// FormatDepSpecs formats specs for the dep_update MR field.
+func FormatDepSpecs(specs []DepSpec) string {
+	parts := make([]string, len(specs))
+	for i, spec := range specs {
+		parts[i] = spec.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DepSummary describes specs in a few words, e.g. "golang.org/x/net to
+// v0.30.0".
+func DepSummary(specs []DepSpec) string {
+	parts := make([]string, len(specs))
+	for i, spec := range specs {
+		parts[i] = spec.Path + " to " + spec.Version
+	}
+	return strings.Join(parts, ", ")
+}
+
+// depCommitMessage is the commit message of a dependency update.
+func depCommitMessage(specs []DepSpec) string {
+	return "chore(deps): bump " + DepSummary(specs)
+}
+
+// regenerateDeps makes mr's dependency update on a scratch branch cut from
+// target and returns the branch to merge, with a cleanup to run once the
+// merge is done. The target is checked out again before it returns.
+func (e *Engineer) regenerateDeps(ctx context.Context, mr *MRInfo, target string) (string, func(), *ProcessResult) {
+	specs, err := ParseDepSpecs(mr.DepUpdate)
+	if err != nil {
+		return "", nil, &ProcessResult{Error: fmt.Sprintf("invalid dep_update: %v", err)}
+	}
+
+	scratch := depsBranchPrefix + mr.ID
+	cleanup := func() {
+		if err := e.git.DeleteBranch(scratch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete %s: %v\n", scratch, err)
+		}
+	}
+	fail := func(msg string) (string, func(), *ProcessResult) {
+		_ = e.git.ResetHard("HEAD")
+		_ = e.git.Checkout(target)
+		cleanup()
+		return "", nil, &ProcessResult{Error: msg}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Regenerating dependency update on %s: %s\n", target, FormatDepSpecs(specs))
+	_ = e.git.DeleteBranch(scratch, true) // left over from an interrupted run
+	if err := e.git.CreateBranchFrom(scratch, target); err != nil {
+		return "", nil, &ProcessResult{Error: fmt.Sprintf("creating %s: %v", scratch, err)}
+	}
+	if err := e.git.Checkout(scratch); err != nil {
+		return fail(fmt.Sprintf("checking out %s: %v", scratch, err))
+	}
+	for _, spec := range specs {
+		if err := e.applyDep(ctx, spec); err != nil {
+			return fail(fmt.Sprintf("applying %s: %v", spec, err))
+		}
+	}
+
+	changed, err := e.git.HasUncommittedChanges()
+	if err != nil {
+		return fail(fmt.Sprintf("checking dependency update: %v", err))
+	}
+	if !changed {
+		return fail(fmt.Sprintf("nothing to update: %s already has %s", target, FormatDepSpecs(specs)))
+	}
+	if err := e.git.Add("-A"); err != nil {
+		return fail(fmt.Sprintf("staging dependency update: %v", err))
+	}
+	if err := e.git.Commit(depCommitMessage(specs)); err != nil {
+		return fail(fmt.Sprintf("committing dependency update: %v", err))
+	}
+	if err := e.git.Checkout(target); err != nil {
+		cleanup()
+		return "", nil, &ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", target, err)}
+	}
+	return scratch, cleanup, nil
+}
+
+// applyDep applies one dependency update in the refinery worktree.
+func (e *Engineer) applyDep(ctx context.Context, spec DepSpec) error {
+	switch spec.Kind {
+	case DepKindSubmodule:
+		if err := git.InitSubmodules(e.git.WorkDir()); err != nil {
+			return err
+		}
+		return e.git.CheckoutSubmodule(spec.Path, spec.Version, "origin")
+	case DepKindGoMod:
+		dir := filepath.Join(e.git.WorkDir(), spec.Dir)
+		if err := e.runGo(ctx, dir, "get", spec.Path+"@"+spec.Version); err != nil {
+			return err
+		}
+		return e.runGo(ctx, dir, "mod", "tidy")
+	}
+	return fmt.Errorf("unknown dependency kind %q", spec.Kind)
+}
+
+// runGo runs a go command in dir with the rig's toolchain pins.
+func (e *Engineer) runGo(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "go", args...) //nolint:gosec // G204: args come from a validated DepSpec
+	cmd.Dir = dir
+	cmd.Env = e.commandEnv()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
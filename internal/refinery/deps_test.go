@@ -0,0 +1,152 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestParseDepSpecs(t *testing.T) {
+	specs, err := ParseDepSpecs("submodule vendor/lib@v1.2.0; gomod golang.org/x/net@v0.30.0 dir=tools;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DepSpec{
+		{Kind: DepKindSubmodule, Path: "vendor/lib", Version: "v1.2.0"},
+		{Kind: DepKindGoMod, Path: "golang.org/x/net", Version: "v0.30.0", Dir: "tools"},
+	}
+	if len(specs) != len(want) || specs[0] != want[0] || specs[1] != want[1] {
+		t.Fatalf("specs = %+v", specs)
+	}
+	if got := FormatDepSpecs(specs); got != "submodule vendor/lib@v1.2.0; gomod golang.org/x/net@v0.30.0 dir=tools" {
+		t.Errorf("FormatDepSpecs = %q", got)
+	}
+	if got := DepSummary(specs); got != "vendor/lib to v1.2.0, golang.org/x/net to v0.30.0" {
+		t.Errorf("DepSummary = %q", got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"gomod golang.org/x/net",
+		"gomod @v1",
+		"gomod golang.org/x/net@",
+		"npm left-pad@1.0.0",
+		"submodule lib@v1 dir=x",
+		"gomod golang.org/x/net@v1 extra",
+		"submodule ../outside@v1",
+		"submodule /abs@v1",
+		"gomod golang.org/x/net@-u",
+		"gomod golang.org/x/net@v1 dir=../up",
+	} {
+		if _, err := ParseDepSpecs(bad); err == nil {
+			t.Errorf("ParseDepSpecs(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestRegenerateDepsSubmodule(t *testing.T) {
+	// Submodule clones and fetches from local paths need the file protocol.
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	runIn := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commitIn := func(dir, name, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runIn(dir, "add", ".")
+		runIn(dir, "commit", "-q", "-m", msg)
+	}
+
+	lib := t.TempDir()
+	runIn(lib, "init", "-q")
+	runIn(lib, "config", "user.email", "test@test.com")
+	runIn(lib, "config", "user.name", "Test User")
+	commitIn(lib, "lib.go", "package lib // v1\n", "v1")
+	runIn(lib, "tag", "v1")
+	commitIn(lib, "lib.go", "package lib // v2\n", "v2")
+	runIn(lib, "tag", "v2")
+
+	dir, base := summaryTestRepo(t)
+	runIn(dir, "checkout", "-q", base)
+	runIn(dir, "submodule", "add", "-q", lib, "vendor/lib")
+	runIn(dir+"/vendor/lib", "checkout", "-q", "v1")
+	runIn(dir, "add", "vendor/lib")
+	runIn(dir, "commit", "-q", "-m", "add lib at v1")
+	libV1, libV2 := runIn(lib, "rev-parse", "v1"), runIn(lib, "rev-parse", "v2")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.git = git.NewGit(dir)
+	mr := &MRInfo{ID: "gt-mr1", DepUpdate: "submodule vendor/lib@v2"}
+	pointer := func(ref string) string {
+		t.Helper()
+		return strings.Fields(runIn(dir, "ls-tree", ref, "vendor/lib"))[2]
+	}
+
+	branch, cleanup, res := e.regenerateDeps(context.Background(), mr, base)
+	if res != nil {
+		t.Fatalf("regenerateDeps: %s", res.Error)
+	}
+	if branch != depsBranchPrefix+"gt-mr1" {
+		t.Errorf("branch = %q", branch)
+	}
+	if cur := runIn(dir, "rev-parse", "--abbrev-ref", "HEAD"); cur != base {
+		t.Errorf("checked out %q, want %s", cur, base)
+	}
+	if got := pointer(branch); got != libV2 {
+		t.Errorf("pointer on %s = %s, want v2 %s", branch, got, libV2)
+	}
+	if got := runIn(dir, "log", "-1", "--format=%s", branch); got != "chore(deps): bump vendor/lib to v2" {
+		t.Errorf("commit message = %q", got)
+	}
+	cleanup()
+
+	// The target moves on, touching the pointer: the retry is made afresh on
+	// the new target rather than conflicting.
+	runIn(dir+"/vendor/lib", "checkout", "-q", libV2)
+	runIn(dir, "add", "vendor/lib")
+	runIn(dir, "commit", "-q", "-m", "lib to v2 by hand")
+	mr.DepUpdate = "submodule vendor/lib@v1"
+	branch, cleanup, res = e.regenerateDeps(context.Background(), mr, base)
+	if res != nil {
+		t.Fatalf("retry: %s", res.Error)
+	}
+	if got := pointer(branch); got != libV1 {
+		t.Errorf("pointer after retry = %s, want v1 %s", got, libV1)
+	}
+	if got := runIn(dir, "rev-parse", branch+"^"); got != runIn(dir, "rev-parse", base) {
+		t.Error("regenerated branch is not cut from the current target")
+	}
+	cleanup()
+
+	// Already there: nothing to merge, and nothing left behind.
+	mr.DepUpdate = "submodule vendor/lib@" + libV2
+	if _, _, res := e.regenerateDeps(context.Background(), mr, base); res == nil || !strings.Contains(res.Error, "nothing to update") {
+		t.Errorf("no-op update = %+v", res)
+	}
+	if got := runIn(dir, "branch", "--list", depsBranchPrefix+"*"); got != "" {
+		t.Errorf("scratch branch left behind: %q", got)
+	}
+	if cur := runIn(dir, "rev-parse", "--abbrev-ref", "HEAD"); cur != base {
+		t.Errorf("checked out %q after failure, want %s", cur, base)
+	}
+}
@@ -18,9 +18,12 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mq"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 )
@@ -58,10 +61,11 @@ type GateConfig struct {
 
 // GateResult holds the outcome of a single gate execution.
 type GateResult struct {
-	Name    string
-	Success bool
-	Error   string
-	Elapsed time.Duration
+	Name         string
+	Success      bool
+	Error        string
+	Elapsed      time.Duration
+	OutputDigest string // sha256 of the command's stdout and stderr
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -74,7 +78,9 @@ type MergeQueueConfig struct {
 	// Enabled controls whether the merge queue is active.
 	Enabled bool `json:"enabled"`
 
-	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
+	// OnConflict is the strategy for handling conflicts: "assign_back" or
+	// "auto_rebase". With auto_rebase, a branch the target has moved past is
+	// rebased onto it before merging (see rebase.go).
 	OnConflict string `json:"on_conflict"`
 
 	// RunTests controls whether to run tests before merging.
@@ -92,15 +98,15 @@ type MergeQueueConfig struct {
 	// PollInterval is how often to check for new MRs.
 	PollInterval time.Duration `json:"poll_interval"`
 
-	// MaxConcurrent is the maximum number of MRs to process concurrently.
+	// MaxConcurrent is the maximum number of MRs to process concurrently:
+	// the number of refinery workers (see workers.go).
 	MaxConcurrent int `json:"max_concurrent"`
 
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim. This handles the
 	// case where a refinery crashes mid-merge, leaving an MR permanently claimed.
 	// Set conservatively to avoid re-claiming MRs with long-running test suites.
-	// NOTE: Only one refinery instance runs per rig (enforced by ErrAlreadyRunning
-	// in manager.go), so concurrent re-claim is not a concern in practice.
+	// The same timeout expires a crashed worker's merge target lock.
 	StaleClaimTimeout time.Duration `json:"stale_claim_timeout"`
 
 	// Gates defines named quality gate commands to run before merging.
@@ -111,6 +117,14 @@ type MergeQueueConfig struct {
 	// GatesParallel controls whether gates run concurrently.
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// Summary enables pre-merge change summaries. Nil disables them.
+	Summary *SummaryConfig `json:"summary"`
+
+	// PostMerge is the ordered pipeline run after each merge. Nil keeps the
+	// built-in behavior (delete the branch per DeleteMergedBranches); an
+	// empty list runs nothing.
+	PostMerge []PostMergeStep `json:"post_merge"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -145,6 +159,14 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	RequiredChecks  string     // Checks that must pass before merge (see ParseCheckSpecs)
+	Labels          []string   // MR bead labels (approvals, gate markers) for the merge policy
+	Parent          string     // Parent MR this one is stacked on (see stack.go)
+	StackBase       string     // Parent branch head when the parent landed
+	MergeAfter      []string   // MRs that must merge before this one (see order.go)
+	OrderCycle      []string   // Merge order cycle this MR is caught in (ListBlockedMRs)
+	DepUpdate       string     // Dependency update spec; no branch (see deps.go)
+	HeadSHA         string     // Branch head, resolved for commit statuses (see notifyQueueEvent)
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -193,8 +215,25 @@ type Engineer struct {
 	mergeSlotEnsureExists func() (string, error)
 	mergeSlotAcquire      func(holder string, addWaiter bool) (*beads.MergeSlotStatus, error)
 	mergeSlotRelease      func(holder string) error
-	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
-	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	mergeSlotMaxRetries   int                     // Max retries for slot acquisition (0 = no retry)
+	mergeSlotRetryBackoff time.Duration           // Initial backoff between retries
+	webhooks              *mq.Dispatcher          // Queue event webhooks (nil = none configured)
+	toolchain             *config.ToolchainConfig // Rig toolchain pins applied to gate commands
+	policy                *mq.Policy              // Declarative merge policy (settings/merge-policy.json)
+	policyErr             error                   // Set when the policy file can't be loaded; blocks merges
+	chaos                 *chaos                  // Fault injection (nil = off; see ChaosEnvVar)
+	worker                string                  // Worker ID target locks are held as (see workers.go)
+	detachAfterMerge      bool                    // Let go of the target branch after each merge (multi-worker)
+
+	// Post-merge pipelines run one at a time on a background worker so a
+	// slow webhook or command never holds up the next merge.
+	postMergeOnce sync.Once
+	postMergeJobs chan postMergeJob
+	postMergeWG   sync.WaitGroup
+
+	// ghRunStatus reports a GitHub Actions workflow's state for a commit
+	// (nil = githubRunStatus; overridden in tests).
+	ghRunStatus func(ctx context.Context, dir, workflow, sha string) (state, detail string)
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -210,7 +249,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	}
 	beadsClient := beads.New(r.Path)
 
-	return &Engineer{
+	e := &Engineer{
 		rig:     r,
 		beads:   beadsClient,
 		git:     git.NewGit(gitDir),
@@ -229,7 +268,18 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		worker:                WorkerID(),
+	}
+
+	if spec := os.Getenv(ChaosEnvVar); spec != "" {
+		if cfg, err := ParseChaosConfig(spec); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: ignoring %s: %v\n", ChaosEnvVar, err)
+		} else {
+			e.EnableChaos(cfg)
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Chaos mode enabled (%s=%s)\n", ChaosEnvVar, spec)
+		}
 	}
+	return e
 }
 
 // SetOutput sets the output writer for user-facing messages.
@@ -238,8 +288,25 @@ func (e *Engineer) SetOutput(w io.Writer) {
 	e.output = w
 }
 
-// LoadConfig loads merge queue configuration from the rig's config.json.
+// LoadConfig loads merge queue configuration from the rig's config.json
+// and queue webhooks and commit statuses from settings/mq-webhooks.json.
 func (e *Engineer) LoadConfig() error {
+	// Webhooks are notifications only: a malformed file disables them but
+	// must not keep the refinery from processing the queue.
+	webhooks, err := mq.LoadDispatcher(e.rig.Name, e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (webhooks disabled, and commit statuses with them)\n", err)
+		webhooks = nil
+	}
+	e.webhooks = webhooks
+	e.toolchain = config.LoadRigToolchain(e.rig.Path)
+	// A broken policy must not silently drop its gates: keep the refinery
+	// running but hold every MR until the file is fixed.
+	e.policy, e.policyErr = mq.LoadPolicy(e.rig.Path)
+	if e.policyErr != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (merges held until fixed)\n", e.policyErr)
+	}
+
 	configPath := filepath.Join(e.rig.Path, "config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -277,6 +344,8 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		Summary              *summaryConfigRaw          `json:"summary"`
+		PostMerge            []postMergeStepRaw         `json:"post_merge"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -345,6 +414,31 @@ func (e *Engineer) LoadConfig() error {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
 
+	// Parse summary configuration ("summary": {} enables the built-in summary)
+	if raw := mqRaw.Summary; raw != nil && (raw.Enabled == nil || *raw.Enabled) {
+		sc := &SummaryConfig{Cmd: raw.Cmd}
+		if raw.Timeout != "" {
+			dur, err := time.ParseDuration(raw.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid summary timeout %q: %w", raw.Timeout, err)
+			}
+			if dur <= 0 {
+				return fmt.Errorf("summary timeout must be positive, got %v", dur)
+			}
+			sc.Timeout = dur
+		}
+		e.config.Summary = sc
+	}
+
+	// Parse post-merge pipeline ("post_merge": [] disables all post-merge steps)
+	if mqRaw.PostMerge != nil {
+		steps, err := parsePostMergeSteps(mqRaw.PostMerge)
+		if err != nil {
+			return err
+		}
+		e.config.PostMerge = steps
+	}
+
 	return nil
 }
 
@@ -367,24 +461,115 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
-	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	SlotTimeout bool   // Merge slot contention timeout (distinct from build/test failure)
+	Summary     string // Pre-merge change summary (empty when summaries are disabled)
+
+	// Required checks declared by the MR (CI gate)
+	ChecksPending bool          // Some required check has not finished; MR waits in queue
+	PolicyBlocked bool          // The rig's merge policy does not allow this MR yet; MR waits in queue
+	StackBlocked  bool          // Stacked on a parent MR that has not landed; MR waits in queue
+	OrderBlocked  bool          // Ordered after an MR that has not merged; MR waits in queue
+	Checks        []CheckResult // Results of the MR's required checks, if any were run
+
+	// Auto-rebase onto a target that moved (on_conflict: auto_rebase)
+	Rebase *RebaseRecord
+
+	// Recorded in the merge provenance note
+	Gates  []GateResult     // Quality gates (or the legacy test command) that ran
+	Policy []mq.RuleResult // Merge policy evaluation, if the rig has a policy
+}
+
+// checkPolicy evaluates the rig's merge policy against mr. It returns
+// ok=false with a PolicyBlocked result when any rule fails or the policy
+// file could not be loaded.
+func (e *Engineer) checkPolicy(mr *MRInfo, now time.Time) (ProcessResult, bool) {
+	if e.policyErr != nil {
+		return ProcessResult{PolicyBlocked: true, Error: e.policyErr.Error()}, false
+	}
+	if e.policy == nil || len(e.policy.Rules) == 0 {
+		return ProcessResult{}, true
+	}
+	eval := e.policy.Evaluate(&mq.Entry{
+		ID:        mr.ID,
+		Branch:    mr.Branch,
+		Target:    mr.Target,
+		Worker:    mr.Worker,
+		Priority:  mr.Priority,
+		Labels:    mr.Labels,
+		CreatedAt: mr.CreatedAt,
+		UpdatedAt: mr.UpdatedAt,
+	}, now)
+	if eval.Allowed() {
+		return ProcessResult{Policy: eval.Results}, true
+	}
+	var reasons []string
+	for _, r := range eval.Failed() {
+		reasons = append(reasons, r.Name+": "+r.Reason)
+	}
+	return ProcessResult{
+		PolicyBlocked: true,
+		Error:         "merge policy: " + strings.Join(reasons, "; "),
+	}, false
 }
 
 // doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
-	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
-	exists, err := e.git.BranchExists(branch)
-	if err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to check branch %s: %v", branch, err),
-		}
+func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo) ProcessResult {
+	branch, target, sourceIssue := mr.Branch, mr.Target, mr.SourceIssue
+
+	// Step 0: A frozen queue holds merges unless it is draining
+	if freeze, err := mq.ActiveFreeze(e.rig.Path, time.Now()); err != nil {
+		return ProcessResult{PolicyBlocked: true, Error: err.Error()}
+	} else if freeze != nil && !freeze.Drain {
+		return ProcessResult{PolicyBlocked: true, Error: freeze.Banner(time.Now())}
 	}
-	if !exists {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("branch %s not found locally", branch),
+
+	// Step 0.1: A paused refinery or a maintenance window defers merges
+	if hold, err := mq.ActiveHold(e.rig.Path, time.Now()); err != nil {
+		return ProcessResult{PolicyBlocked: true, Error: err.Error()}
+	} else if hold != nil {
+		return ProcessResult{PolicyBlocked: true, Error: hold.Banner(time.Now())}
+	}
+
+	// Step 0.25: Evaluate the rig's merge policy (approvals, windows, lanes, ...)
+	policyResult, ok := e.checkPolicy(mr, time.Now())
+	if !ok {
+		return policyResult
+	}
+
+	// Step 0.5: A stacked MR waits for its parent to land
+	if stackResult, ok := e.checkStackParent(mr); !ok {
+		return stackResult
+	}
+
+	// Step 0.6: An MR ordered after others waits for them to merge
+	if orderResult, ok := e.checkMergeOrder(mr); !ok {
+		return orderResult
+	}
+
+	// Step 0.9: Take the target's lock, so no other refinery worker merges
+	// into it until this merge is done. Other targets are unaffected.
+	renewLock, unlockTarget, lockErr := e.lockTarget(ctx, mr)
+	if lockErr != nil {
+		return *lockErr
+	}
+	defer unlockTarget()
+
+	// Step 1: Verify source branch exists locally (shared .repo.git with polecats).
+	// A dependency update has no branch; it is made in step 2.4.
+	if mr.DepUpdate == "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
+		exists, err := e.git.BranchExists(branch)
+		if err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to check branch %s: %v", branch, err),
+			}
+		}
+		if !exists {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("branch %s not found locally", branch),
+			}
 		}
 	}
 
@@ -403,6 +588,33 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
 	}
 
+	// Step 2.4: Make a dependency update from its spec on the current target,
+	// so a retry regenerates it rather than conflicting.
+	if mr.DepUpdate != "" {
+		depBranch, cleanupDeps, depErr := e.regenerateDeps(ctx, mr, target)
+		if depErr != nil {
+			return *depErr
+		}
+		defer cleanupDeps()
+		branch = depBranch
+	}
+
+	// Step 2.5: Rebase a stacked MR whose parent has landed onto the target,
+	// dropping the parent's commits. The rebased copy is merged from here on.
+	branch, cleanupStack, stackErr := e.rebaseStacked(mr, target)
+	if stackErr != nil {
+		return *stackErr
+	}
+	defer cleanupStack()
+
+	// Step 2.6: With auto_rebase, bring a branch the target has moved past
+	// up to date, so the checks below see what will land.
+	branch, cleanupRebase, rebase, rebaseErr := e.autoRebase(mr, branch, target)
+	if rebaseErr != nil {
+		return *rebaseErr
+	}
+	defer cleanupRebase()
+
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
@@ -428,7 +640,9 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	if err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
 	}
-	if len(subChanges) > 0 {
+	// A dependency update moves pointers to commits that are already
+	// upstream; there is nothing to push.
+	if len(subChanges) > 0 && mr.DepUpdate == "" {
 		// Ensure submodules are initialized in the refinery worktree
 		if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
 			return ProcessResult{
@@ -451,13 +665,40 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
+	// Step 3.9: Read the MR's required GitHub checks (CI gate) before the
+	// local gates, so an MR waiting on CI is deferred without running them.
+	// A pending check defers the merge; the MR is retried on the next poll.
+	var checkSpecs []CheckSpec
+	var checkResults []CheckResult
+	if mr.RequiredChecks != "" {
+		specs, err := ParseCheckSpecs(mr.RequiredChecks)
+		if err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("invalid required_checks: %v", err),
+			}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Evaluating %d required check(s)...\n", len(specs))
+		checkSpecs = specs
+		checkResults = e.runRemoteChecks(ctx, mr.Branch, specs) // CI ran on the pushed branch
+		if blocked, ok := checksProcessResult(checkResults); !ok {
+			return blocked
+		}
+	}
+
 	// Step 4: Run quality gates (or legacy tests) if configured
+	passedGates := make(map[string]bool)
+	var gates []GateResult
 	if len(e.config.Gates) > 0 {
 		// New gates system: run configured quality gates
 		gateResult := e.runGates(ctx)
 		if !gateResult.Success {
 			return gateResult
 		}
+		for name := range e.config.Gates {
+			passedGates[name] = true
+		}
+		gates = gateResult.Gates
 	} else if e.config.RunTests && e.config.TestCommand != "" {
 		// Legacy test command path (backward compatible)
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
@@ -470,6 +711,33 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			}
 		}
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		passedGates["test_command"] = true
+		gates = result.Gates
+	}
+
+	// Step 4.2: Run the MR's remaining required checks. Test checks reuse
+	// the step 4 gate results; the rest run against the branch.
+	if len(checkSpecs) > 0 {
+		checkResults = e.runLocalChecks(ctx, branch, checkSpecs, checkResults, passedGates)
+		if blocked, ok := checksProcessResult(checkResults); !ok {
+			return blocked
+		}
+	}
+
+	// Step 4.5: Generate the pre-merge change summary if configured and
+	// attach it to the MR before merging, so reviewers see what is about to
+	// land. Kept out of the description so its text is never parsed as MR
+	// key: value fields. The squash commit carries it too (step 5).
+	var summary string
+	if e.config.Summary != nil {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Generating merge summary...")
+		summary = e.generateSummary(ctx, branch, target, sourceIssue)
+		if summary != "" && mr.ID != "" && e.beads != nil {
+			comment := fmt.Sprintf("Merge summary (%s into %s):\n%s", branch, target, summary)
+			if err := e.beads.AddComment(mr.ID, comment); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to attach summary to MR %s: %v\n", mr.ID, err)
+			}
+		}
 	}
 
 	// Step 5: Perform the actual merge using squash merge
@@ -485,7 +753,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
 	}
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
+	if err := e.git.MergeSquash(branch, appendSummary(originalMsg, summary)); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
 		conflicts, conflictErr := e.git.GetConflictingFiles()
@@ -538,13 +806,26 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			// pushHolder is empty when the self-conflict bypass fires — conflict-resolution
 			// owns the slot, so we must not release it here.
 			if pushHolder != "" {
-				if releaseErr := e.mergeSlotRelease(pushHolder); releaseErr != nil {
+				if releaseErr := e.releaseMergeSlot(pushHolder); releaseErr != nil {
 					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", pushHolder, releaseErr)
 				}
 			}
 		}()
 	}
 
+	// Step 7.5: Renew the target lock. Gates can outlast the stale timeout;
+	// if another worker has taken the target over meanwhile, don't push.
+	if err := renewLock(); err != nil {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after losing the target lock: %v\n", target, resetErr)
+		}
+		return ProcessResult{
+			Success:     false,
+			SlotTimeout: errors.Is(err, mq.ErrTargetLocked),
+			Error:       fmt.Sprintf("lost target lock before push: %v", err),
+		}
+	}
+
 	// Step 8: Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
 	if err := e.git.Push("origin", target, false); err != nil {
@@ -560,10 +841,22 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
-	return ProcessResult{
+	result := ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		Summary:     summary,
+		Checks:      checkResults,
+		Gates:       gates,
+		Policy:      policyResult.Policy,
+		Rebase:      rebase,
 	}
+
+	// Step 9: Record provenance on the merge commit. The merge has landed,
+	// so a failure here is only a warning.
+	if err := WriteProvenance(e.git, "origin", mergeCommit, newProvenance(mr, result, time.Now())); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record merge provenance: %v\n", err)
+	}
+	return result
 }
 
 func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
@@ -576,9 +869,10 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	holder := fmt.Sprintf("%s/refinery/push/%d-%d", e.rig.Name, time.Now().UnixNano(), seq)
 
 	// The conflict-resolution path holds the slot with holder "rigName/refinery".
-	// Both push and conflict-resolution run in the same single-threaded refinery
-	// agent, so if our own rig holds the slot for conflict resolution, we can
-	// safely proceed without re-acquiring — no concurrent push is possible.
+	// If our own rig holds the slot for conflict resolution, we can safely
+	// proceed without re-acquiring: our refinery's pushes to this target are
+	// already serialized by the target lock (see lockTarget), so no concurrent
+	// push is possible.
 	selfConflictHolder := e.rig.Name + "/refinery"
 
 	backoff := e.mergeSlotRetryBackoff
@@ -599,6 +893,11 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 
 		status, err := e.mergeSlotAcquire(holder, false)
 		if err != nil {
+			// The acquire may have landed even though we saw an error (e.g., bd
+			// timed out after writing the slot). Release our holder so the slot
+			// isn't stranded; release verifies the holder, so this is otherwise
+			// a no-op.
+			_ = e.releaseMergeSlot(holder)
 			return "", fmt.Errorf("acquire merge slot %s (%s): %w", slotID, holder, err)
 		}
 		if status == nil {
@@ -617,6 +916,21 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	return "", fmt.Errorf("merge slot %s: %w after %d retries", slotID, errMergeSlotTimeout, e.mergeSlotMaxRetries)
 }
 
+// releaseMergeSlot releases the merge slot held by holder, retrying a few
+// times: a failed release leaves the slot held and blocks every later push.
+func (e *Engineer) releaseMergeSlot(holder string) error {
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+		if err = e.mergeSlotRelease(holder); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 // ValidateTestCommand validates that a test command is safe to execute.
 // TestCommand comes from the rig's operator-controlled config.json, not from
 // user input or PR branches. This validation provides defense-in-depth for the
@@ -653,15 +967,23 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		// infrastructure config), not from PR branches or user input. Shell execution
 		// is intentional for flexibility (pipes, env vars, etc).
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", e.config.TestCommand)
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
+		runCtx, cancel := e.chaos.killContext(ctx, "test command")
+		cmd := exec.CommandContext(runCtx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
 		cmd.Dir = e.workDir
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
+		start := time.Now()
 		err := cmd.Run()
+		cancel()
 		if err == nil {
-			return ProcessResult{Success: true}
+			return ProcessResult{Success: true, Gates: []GateResult{{
+				Name:         "test_command",
+				Success:      true,
+				Elapsed:      time.Since(start),
+				OutputDigest: outputDigest(stdout.Bytes(), stderr.Bytes()),
+			}}}
 		}
 		lastErr = err
 
@@ -683,6 +1005,11 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 // runGate executes a single quality gate command and returns the result.
 func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	return e.runGateIn(ctx, e.workDir, name, gate)
+}
+
+// runGateIn executes a gate command in dir (e.g., a worktree of the MR branch).
+func (e *Engineer) runGateIn(ctx context.Context, dir, name string, gate *GateConfig) GateResult {
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -701,9 +1028,12 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		gateCtx, cancel = context.WithTimeout(ctx, gate.Timeout)
 		defer cancel()
 	}
+	cmdCtx, cancelKill := e.chaos.killContext(gateCtx, "gate "+name)
+	defer cancelKill()
 
-	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
-	cmd.Dir = e.workDir
+	cmd := exec.CommandContext(cmdCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
+	cmd.Dir = dir
+	cmd.Env = e.commandEnv()
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -713,9 +1043,10 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 
 	if err == nil {
 		return GateResult{
-			Name:    name,
-			Success: true,
-			Elapsed: elapsed,
+			Name:         name,
+			Success:      true,
+			Elapsed:      elapsed,
+			OutputDigest: outputDigest(stdout.Bytes(), stderr.Bytes()),
 		}
 	}
 
@@ -739,6 +1070,22 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 	}
 }
 
+// commandEnv returns the environment for rig commands (gates, post-merge
+// commands): the process environment plus the rig's toolchain pins and
+// extra. It returns nil, meaning the inherited environment, when there is
+// nothing to add.
+func (e *Engineer) commandEnv(extra ...string) []string {
+	toolchain := config.ToolchainEnv(e.toolchain)
+	if len(toolchain) == 0 && len(extra) == 0 {
+		return nil
+	}
+	env := os.Environ()
+	for k, v := range toolchain {
+		env = append(env, k+"="+v)
+	}
+	return append(env, extra...)
+}
+
 // runGates executes all configured quality gates and returns a ProcessResult.
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
@@ -803,7 +1150,7 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	}
 
 	_, _ = fmt.Fprintln(e.output, "[Engineer] All quality gates passed")
-	return ProcessResult{Success: true}
+	return ProcessResult{Success: true, Gates: results}
 }
 
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
@@ -846,9 +1193,16 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mr.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
+	if mr.DepUpdate != "" {
+		_, _ = fmt.Fprintf(e.output, "  Update: %s\n", mr.DepUpdate)
+	}
+
+	e.notifyQueueEvent(mq.EventMerging, mr, ProcessResult{})
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	result := e.doMerge(ctx, mr)
+	e.recordCheckResults(mr.ID, result.Checks)
+	return result
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -864,6 +1218,16 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Released merge slot\n")
 	}
 
+	// Record where the branch ended for MRs stacked on this one, before the
+	// post-merge pipeline deletes it
+	if mr.ID != "" && mr.Branch != "" {
+		if head, err := e.git.Rev(mr.Branch); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to read %s head for stacked MRs: %v\n", mr.Branch, err)
+		} else {
+			e.restackChildren(mr, head)
+		}
+	}
+
 	// Update and close the MR bead
 	if mr.ID != "" {
 		// Fetch the MR bead to update its fields
@@ -892,13 +1256,17 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 1. Close source issue with reference to MR
+	// 1. Close source issue with reference to MR (or, if the rig requires
+	// evidence it doesn't have yet, move it to verify)
 	if mr.SourceIssue != "" {
 		closeReason := fmt.Sprintf("Merged in %s", mr.ID)
-		if err := e.beads.CloseWithReason(closeReason, mr.SourceIssue); err != nil {
+		closed, err := e.beads.CloseOrVerify(closeReason, config.LoadRigVerify(e.rig.Path), mr.SourceIssue)
+		if err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mr.SourceIssue, err)
-		} else {
+		} else if closed {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Closed source issue: %s\n", mr.SourceIssue)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Source issue %s awaits verification\n", mr.SourceIssue)
 		}
 	}
 
@@ -909,26 +1277,18 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 2. Delete source branch if configured (local and remote)
-	if e.config.DeleteMergedBranches && mr.Branch != "" {
-		if err := e.git.DeleteBranch(mr.Branch, true); err != nil {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete local branch %s: %v\n", mr.Branch, err)
-		} else {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Deleted local branch: %s\n", mr.Branch)
-		}
-		if err := e.git.DeleteRemoteBranch("origin", mr.Branch); err != nil {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete remote branch %s: %v\n", mr.Branch, err)
-		} else {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Deleted remote branch: %s\n", mr.Branch)
-		}
-	}
+	// 2. Queue the post-merge pipeline (by default, delete the source branch)
+	e.enqueuePostMerge(mr, result)
 
 	// 3. Check and auto-close completed convoys
 	// After closing a source issue, its parent convoy may now be complete.
 	// Run convoy check to auto-close and notify subscribers.
 	e.postMergeConvoyCheck(mr)
 
-	// 4. Log success
+	// 4. Publish the merge and notify webhooks
+	e.notifyQueueEvent(mq.EventMerged, mr, result)
+
+	// 5. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
@@ -946,6 +1306,32 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// A merge policy hold (missing approvals, outside the merge window, ...)
+	// is resolved by people or time, not by the worker.
+	if result.PolicyBlocked {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] … Held by merge policy: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// Pending required checks (e.g., CI still running) are not a failure either:
+	// the merge is blocked until they pass, with nothing for the worker to do yet.
+	if result.ChecksPending {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] … Waiting: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// So is a parent MR that has not landed yet.
+	if result.StackBlocked {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] … Waiting for parent: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// Or one it is ordered after.
+	if result.OrderBlocked {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] … Waiting on merge order: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -978,6 +1364,17 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	if result.Conflict {
+		e.notifyQueueEvent(mq.EventConflicted, mr, result)
+	} else {
+		e.notifyQueueEvent(mq.EventFailed, mr, result)
+	}
+
+	// MRs stacked on this one can't land until it does
+	if mr.ID != "" {
+		e.failStackedChildren(mr, result.Error)
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
@@ -987,6 +1384,62 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// queueEventTypes maps webhook queue events to event log types.
+var queueEventTypes = map[string]string{
+	mq.EventMerging:    events.TypeMergeStarted,
+	mq.EventMerged:     events.TypeMerged,
+	mq.EventFailed:     events.TypeMergeFailed,
+	mq.EventConflicted: events.TypeMergeConflicted,
+}
+
+// notifyQueueEvent publishes a queue event to the town event log and
+// delivers it to the rig's configured webhooks. Failures are logged and
+// never affect queue processing.
+func (e *Engineer) notifyQueueEvent(event string, mr *MRInfo, result ProcessResult) {
+	if eventType, ok := queueEventTypes[event]; ok {
+		payload := events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error)
+		payload["rig"] = e.rig.Name
+		payload["target"] = mr.Target
+		if result.MergeCommit != "" {
+			payload["commit"] = result.MergeCommit
+		}
+		_ = events.Publish(e.rig.Path, events.SourceRefinery, eventType,
+			e.rig.Name+"/refinery", payload, events.VisibilityBoth)
+	}
+
+	// Commit statuses go on the branch head. Resolve it once: by the time
+	// the MR is merged, the post-merge pipeline may have deleted the branch.
+	if mr.HeadSHA == "" && mr.Branch != "" && e.webhooks.PublishesCommitStatus() {
+		mr.HeadSHA = e.branchHead(mr.Branch)
+	}
+
+	err := e.webhooks.Dispatch(event, mq.WebhookPayload{
+		MRID:        mr.ID,
+		Branch:      mr.Branch,
+		Target:      mr.Target,
+		SourceIssue: mr.SourceIssue,
+		Worker:      mr.Worker,
+		MergeCommit: result.MergeCommit,
+		HeadSHA:     mr.HeadSHA,
+		Summary:     result.Summary,
+		Error:       result.Error,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %s webhook delivery: %v\n", event, err)
+	}
+}
+
+// branchHead returns the commit an MR branch points to, preferring the local
+// branch the refinery merges from, or "" if neither it nor origin's has it.
+func (e *Engineer) branchHead(branch string) string {
+	for _, ref := range []string{"refs/heads/" + branch, "origin/" + branch} {
+		if sha, err := e.git.Rev(ref); err == nil {
+			return sha
+		}
+	}
+	return ""
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1164,6 +1617,12 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		RetryCount:      fields.RetryCount,
 		ConvoyID:        fields.ConvoyID,
 		ConvoyCreatedAt: convoyCreatedAt,
+		RequiredChecks:  fields.RequiredChecks,
+		Labels:          issue.Labels,
+		Parent:          fields.Parent,
+		StackBase:       fields.StackBase,
+		MergeAfter:      ParseMergeAfter(fields.MergeAfter),
+		DepUpdate:       fields.DepUpdate,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
@@ -1185,6 +1644,7 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (checked via firstOpenBlocker)
+// - Not ordered after an open MR (see order.go)
 // Sorted by priority (highest first).
 //
 // Uses bd list instead of bd ready because MRs are ephemeral beads and
@@ -1195,15 +1655,15 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 	// Cannot use ReadyWithType here because bd ready excludes ephemeral beads,
 	// and MRs are ephemeral by design. Use List + manual blocker check instead.
 	issues, err := e.beads.List(beads.ListOptions{
-		Status:   "open",
-		Label:    "gt:merge-request",
-		Priority: -1, // No priority filter
+		Status: "open",
+		Label:  "gt:merge-request",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
 	// Convert beads issues to MRInfo
+	open := openMRIDs(issues)
 	var mrs []*MRInfo
 	for _, issue := range issues {
 		// Skip closed MRs (workaround for bd list not respecting --status filter)
@@ -1216,6 +1676,11 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue
 		}
 
+		// Skip MRs put on hold (gt mq hold)
+		if beads.HasLabel(issue, HeldLabel) {
+			continue
+		}
+
 		// Belt-and-suspenders: skip MRs labeled gt:owned-direct.
 		// These MRs shouldn't exist (gt done skips MR creation for owned+direct
 		// convoys), but if one slips through, the refinery should not process it.
@@ -1229,9 +1694,13 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue // Skip issues without MR fields
 		}
 
+		// Skip MRs that must merge after an MR still open
+		if firstOpenPredecessor(ParseMergeAfter(fields.MergeAfter), open) != "" {
+			continue
+		}
+
 		// Skip if already assigned, unless claim is stale (allows re-claim after crash).
-		// NOTE: Only one refinery runs per rig (enforced by ErrAlreadyRunning in
-		// manager.go), so concurrent re-claim race conditions are not a concern.
+		// Workers racing to re-claim are settled by the target lock (ClaimNextMR).
 		if issue.Assignee != "" {
 			stale, parseErr := isClaimStale(issue.UpdatedAt, e.config.StaleClaimTimeout)
 			if parseErr != nil {
@@ -1251,33 +1720,37 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 	return mrs, nil
 }
 
-// ListBlockedMRs returns MRs that are blocked by open tasks.
+// ListBlockedMRs returns MRs that are blocked by open tasks, or ordered
+// after open MRs (with the merge order cycle they are caught in, if any).
 // Useful for monitoring/reporting.
 //
 // This queries beads for blocked merge-request issues.
 func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 	// Query all merge-request issues (both ready and blocked)
 	issues, err := e.beads.List(beads.ListOptions{
-		Status:   "open",
-		Label:    "gt:merge-request",
-		Priority: -1, // No priority filter
+		Status: "open",
+		Label:  "gt:merge-request",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
-	// Filter for blocked issues (those with open blockers)
+	// Filter for blocked issues (those with open blockers or predecessors)
+	open := openMRIDs(issues)
+	after := mergeOrderGraph(issues)
 	var mrs []*MRInfo
 	for _, issue := range issues {
-		// Skip if not blocked
-		if len(issue.BlockedBy) == 0 {
-			continue
-		}
-
 		// Check if any blocker is still open
 		blockedBy := e.firstOpenBlocker(issue)
+		var cycle []string
+		if blockedBy == "" {
+			blockedBy = firstOpenPredecessor(after[issue.ID], open)
+			if blockedBy != "" {
+				cycle = cycleFrom(after, issue.ID)
+			}
+		}
 		if blockedBy == "" {
-			continue // All blockers are closed, not blocked
+			continue // Not blocked
 		}
 
 		fields := beads.ParseMRFields(issue)
@@ -1287,6 +1760,7 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 
 		mr := issueToMRInfo(issue, fields)
 		mr.BlockedBy = blockedBy
+		mr.OrderCycle = cycle
 		mrs = append(mrs, mr)
 	}
 
@@ -1300,9 +1774,8 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 // (ZFC: Go transports data, agent decides what's interesting).
 func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 	issues, err := e.beads.List(beads.ListOptions{
-		Status:   "open",
-		Label:    "gt:merge-request",
-		Priority: -1,
+		Status: "open",
+		Label:  "gt:merge-request",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
@@ -1321,9 +1794,12 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 
 		mr := issueToMRInfo(issue, fields)
 
-		// Check branch existence (local + remote tracking refs)
-		mr.BranchExistsLocal, _ = e.git.BranchExists(fields.Branch)
-		mr.BranchExistsRemote, _ = e.git.RemoteTrackingBranchExists("origin", fields.Branch)
+		// Check branch existence (local + remote tracking refs). Dependency
+		// updates have no branch.
+		if fields.Branch != "" {
+			mr.BranchExistsLocal, _ = e.git.BranchExists(fields.Branch)
+			mr.BranchExistsRemote, _ = e.git.RemoteTrackingBranchExists("origin", fields.Branch)
+		}
 		mr.BlockedBy = e.firstOpenBlocker(issue)
 
 		mrs = append(mrs, mr)
@@ -1336,9 +1812,8 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 // This gives Witness/Refinery patrols deterministic signals for deadlock risk.
 func (e *Engineer) ListQueueAnomalies(now time.Time) ([]*MRAnomaly, error) {
 	issues, err := e.beads.List(beads.ListOptions{
-		Status:   "open",
-		Label:    "gt:merge-request",
-		Priority: -1,
+		Status: "open",
+		Label:  "gt:merge-request",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
@@ -1369,7 +1844,7 @@ func detectQueueAnomalies(
 			continue
 		}
 		fields := beads.ParseMRFields(issue)
-		if fields == nil || fields.Branch == "" {
+		if fields == nil || (fields.Branch == "" && fields.DepUpdate == "") {
 			continue
 		}
 
@@ -1396,7 +1871,10 @@ func detectQueueAnomalies(
 			}
 		}
 
-		// 2) Orphaned branch detection.
+		// 2) Orphaned branch detection. Dependency updates have no branch.
+		if fields.Branch == "" {
+			continue
+		}
 		localExists, remoteTrackingExists, err := branchExistsFn(fields.Branch)
 		if err == nil && !localExists && !remoteTrackingExists {
 			anomalies = append(anomalies, &MRAnomaly{
@@ -1416,18 +1894,29 @@ func detectQueueAnomalies(
 // This replaces mrqueue.Claim() for beads-based MRs.
 // The workerID is typically the refinery's identifier (e.g., "gastown/refinery").
 func (e *Engineer) ClaimMR(mrID, workerID string) error {
-	return e.beads.Update(mrID, beads.UpdateOptions{
-		Assignee: &workerID,
+	return e.chaos.do("claim "+mrID, func() error {
+		return e.beads.Update(mrID, beads.UpdateOptions{
+			Assignee: &workerID,
+		})
 	})
 }
 
-// ReleaseMR releases a claimed MR back to the queue by clearing the assignee.
+// ReleaseMR releases a claimed MR back to the queue by clearing the assignee,
+// and releases this worker's lock on its target, if held.
 // This replaces mrqueue.Release() for beads-based MRs.
 func (e *Engineer) ReleaseMR(mrID string) error {
 	empty := ""
-	return e.beads.Update(mrID, beads.UpdateOptions{
-		Assignee: &empty,
-	})
+	if err := e.chaos.do("release "+mrID, func() error {
+		return e.beads.Update(mrID, beads.UpdateOptions{
+			Assignee: &empty,
+		})
+	}); err != nil {
+		return err
+	}
+	if err := e.releaseTargetOf(mrID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release target lock for %s: %v\n", mrID, err)
+	}
+	return nil
 }
 
 // postMergeConvoyCheck runs convoy completion checks after a successful merge.
@@ -94,3 +94,26 @@ worker: nux`,
 		t.Fatalf("anomaly ID = %q, want gt-orphan", anomalies[0].ID)
 	}
 }
+
+func TestDetectQueueAnomalies_DepUpdateHasNoBranch(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	issues := []*beads.Issue{
+		{
+			ID:        "gt-deps",
+			Status:    "open",
+			Assignee:  "rig/refinery",
+			UpdatedAt: now.Add(-3 * time.Hour).Format(time.RFC3339),
+			Description: `target: main
+dep_update: gomod golang.org/x/net@v0.30.0`,
+		},
+	}
+
+	anomalies := detectQueueAnomalies(issues, now, func(branch string) (bool, bool, error) {
+		t.Fatalf("branch %q checked for a dependency update", branch)
+		return false, false, nil
+	})
+
+	if len(anomalies) != 1 || anomalies[0].Type != "stale-claim" {
+		t.Fatalf("expected only a stale-claim anomaly, got %+v", anomalies)
+	}
+}
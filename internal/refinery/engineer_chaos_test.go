@@ -0,0 +1,284 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestParseChaosConfig(t *testing.T) {
+	cfg, err := ParseChaosConfig("1")
+	if err != nil || cfg != DefaultChaosConfig() {
+		t.Errorf("ParseChaosConfig(1) = %+v, %v; want defaults", cfg, err)
+	}
+
+	cfg, err = ParseChaosConfig("delay=0.5, fail=0.25,kill=1,max-delay=20ms,seed=7")
+	if err != nil {
+		t.Fatalf("ParseChaosConfig: %v", err)
+	}
+	want := ChaosConfig{DelayRate: 0.5, FailRate: 0.25, KillRate: 1, MaxDelay: 20 * time.Millisecond, Seed: 7}
+	if cfg != want {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+
+	for _, bad := range []string{"fail", "fail=2", "boom=0.1", "max-delay=soon", "seed=x"} {
+		if _, err := ParseChaosConfig(bad); err == nil {
+			t.Errorf("ParseChaosConfig(%q): expected error", bad)
+		}
+	}
+}
+
+func TestChaos_NilIsPassthrough(t *testing.T) {
+	var c *chaos
+	calls := 0
+	if err := c.do("op", func() error { calls++; return nil }); err != nil || calls != 1 {
+		t.Errorf("nil chaos: err=%v calls=%d", err, calls)
+	}
+	ctx, cancel := c.killContext(context.Background(), "gate")
+	defer cancel()
+	if ctx != context.Background() {
+		t.Error("nil chaos should return the parent context")
+	}
+}
+
+func TestChaos_InjectsEachFault(t *testing.T) {
+	var log bytes.Buffer
+	c := newChaos(ChaosConfig{FailRate: 1, DupRate: 1, Seed: 1}, func(format string, args ...interface{}) {
+		fmt.Fprintf(&log, format+"\n", args...)
+	})
+
+	calls := 0
+	for i := 0; i < 20; i++ {
+		err := c.do("op", func() error { calls++; return nil })
+		if !errors.Is(err, errChaosInjected) {
+			t.Fatalf("expected injected failure, got %v", err)
+		}
+	}
+	// Injected failures split between before and after: the "after" half
+	// runs the operation (twice, with dup=1) and still reports failure.
+	if !strings.Contains(log.String(), "before it runs") || !strings.Contains(log.String(), "after it took effect") {
+		t.Errorf("expected both failure modes, log:\n%s", log.String())
+	}
+	if calls == 0 || calls%2 != 0 {
+		t.Errorf("calls = %d, want a nonzero even number (every run duplicated)", calls)
+	}
+}
+
+// chaosSlot is an in-memory merge slot with bd's holder semantics. It
+// records any moment two holders are inside the critical section.
+type chaosSlot struct {
+	mu      sync.Mutex
+	holder  string
+	inside  int
+	overlap bool
+}
+
+func (s *chaosSlot) acquire(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.holder == "" || s.holder == holder {
+		s.holder = holder
+		return &beads.MergeSlotStatus{ID: "merge-slot", Available: true, Holder: holder}, nil
+	}
+	return &beads.MergeSlotStatus{ID: "merge-slot", Holder: s.holder}, nil
+}
+
+func (s *chaosSlot) release(holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.holder {
+	case holder:
+		s.holder = ""
+		return nil
+	case "":
+		return nil // Already released (e.g., a duplicated release)
+	default:
+		return fmt.Errorf("slot held by %s, not %s", s.holder, holder)
+	}
+}
+
+func (s *chaosSlot) enter() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inside++
+	if s.inside > 1 {
+		s.overlap = true
+	}
+}
+
+func (s *chaosSlot) leave() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inside--
+}
+
+// TestChaos_MergeSlotHoldsUnderFaults runs several refineries against one
+// merge slot with delays, duplicated requests, and failures (including
+// lost responses) injected, and checks that the slot never has two
+// holders and that a slot left held is always one whose failure surfaced.
+func TestChaos_MergeSlotHoldsUnderFaults(t *testing.T) {
+	slot := &chaosSlot{}
+	var mu sync.Mutex
+	surfaced := make(map[string]bool) // Holders whose acquire or release reported an error
+	var successes int
+
+	var wg sync.WaitGroup
+	for w := 0; w < 6; w++ {
+		e := &Engineer{
+			rig:                   &rig.Rig{Name: fmt.Sprintf("rig%d", w)},
+			output:                io.Discard,
+			mergeSlotMaxRetries:   12,
+			mergeSlotRetryBackoff: time.Millisecond,
+			mergeSlotEnsureExists: func() (string, error) { return "merge-slot", nil },
+			mergeSlotAcquire:      slot.acquire,
+			mergeSlotRelease:      slot.release,
+		}
+		e.EnableChaos(ChaosConfig{
+			DelayRate: 0.3,
+			FailRate:  0.1,
+			DupRate:   0.3,
+			MaxDelay:  2 * time.Millisecond,
+			Seed:      int64(w + 1),
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				holder, err := e.acquireMainPushSlot(context.Background())
+				if err != nil {
+					// The failed attempt's holder is internal; any slot it
+					// stranded shows up below under its rig's prefix.
+					mu.Lock()
+					surfaced[e.rig.Name] = true
+					mu.Unlock()
+					continue
+				}
+				slot.enter()
+				time.Sleep(100 * time.Microsecond)
+				slot.leave()
+				if err := e.releaseMergeSlot(holder); err != nil {
+					mu.Lock()
+					surfaced[e.rig.Name] = true
+					mu.Unlock()
+					continue
+				}
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if slot.overlap {
+		t.Fatal("two refineries held the merge slot at once")
+	}
+	if successes == 0 {
+		t.Fatal("no push ever completed under chaos")
+	}
+	if slot.holder != "" {
+		rigName, _, _ := strings.Cut(slot.holder, "/")
+		if !surfaced[rigName] {
+			t.Errorf("slot stranded by %s without any error being reported", slot.holder)
+		}
+	}
+}
+
+// TestChaos_AcquireLostResponseReleasesSlot checks recovery from an acquire
+// that took effect but reported failure: the slot must not stay held.
+func TestChaos_AcquireLostResponseReleasesSlot(t *testing.T) {
+	slot := &chaosSlot{}
+	e := &Engineer{
+		rig:                   &rig.Rig{Name: "testrig"},
+		output:                io.Discard,
+		mergeSlotEnsureExists: func() (string, error) { return "merge-slot", nil },
+		mergeSlotAcquire: func(holder string, addWaiter bool) (*beads.MergeSlotStatus, error) {
+			_, _ = slot.acquire(holder, addWaiter)
+			return nil, fmt.Errorf("bd timed out")
+		},
+		mergeSlotRelease: slot.release,
+	}
+
+	if _, err := e.acquireMainPushSlot(context.Background()); err == nil {
+		t.Fatal("expected acquire error")
+	}
+	if slot.holder != "" {
+		t.Errorf("slot stranded by %s after failed acquire", slot.holder)
+	}
+}
+
+func TestChaos_ReleaseRetriesTransientFailure(t *testing.T) {
+	attempts := 0
+	e := &Engineer{
+		mergeSlotRelease: func(string) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transient")
+			}
+			return nil
+		},
+	}
+	if err := e.releaseMergeSlot("h"); err != nil {
+		t.Fatalf("releaseMergeSlot: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+// TestChaos_KilledTestRunIsRetried checks that a test command killed
+// mid-run counts as a failed attempt and is recovered by flaky retries,
+// and that a run killed every time is reported as a failure.
+func TestChaos_KilledTestRunIsRetried(t *testing.T) {
+	newEngineer := func(killRate float64) (*Engineer, *bytes.Buffer) {
+		var out bytes.Buffer
+		e := &Engineer{
+			output:  &out,
+			workDir: t.TempDir(),
+			config: &MergeQueueConfig{
+				TestCommand:     "exec sleep 0.2",
+				RetryFlakyTests: 20,
+			},
+		}
+		e.EnableChaos(ChaosConfig{KillRate: killRate, MaxDelay: 50 * time.Millisecond, Seed: 2})
+		return e, &out
+	}
+
+	e, out := newEngineer(0.5)
+	if result := e.runTests(context.Background()); !result.Success {
+		t.Fatalf("expected retries to recover from killed runs, got %q\n%s", result.Error, out.String())
+	}
+	if !strings.Contains(out.String(), "chaos: killing test command") {
+		t.Errorf("expected at least one killed run with seed 2, output:\n%s", out.String())
+	}
+
+	e, _ = newEngineer(1)
+	e.config.RetryFlakyTests = 2
+	result := e.runTests(context.Background())
+	if result.Success || !result.TestsFailed {
+		t.Errorf("run killed every time: got %+v, want TestsFailed", result)
+	}
+}
+
+func TestChaos_KilledGateFails(t *testing.T) {
+	e := &Engineer{output: io.Discard}
+	e.EnableChaos(ChaosConfig{KillRate: 1, MaxDelay: 10 * time.Millisecond, Seed: 1})
+
+	result := e.runGateIn(context.Background(), t.TempDir(), "lint", &GateConfig{Cmd: "exec sleep 1"})
+	if result.Success {
+		t.Fatal("expected killed gate to fail")
+	}
+	if result.Elapsed >= time.Second {
+		t.Errorf("gate ran to completion (%v); expected it killed mid-run", result.Elapsed)
+	}
+}
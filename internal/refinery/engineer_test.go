@@ -747,3 +747,82 @@ func TestIsClaimStale(t *testing.T) {
 		})
 	}
 }
+
+func TestEngineer_DoMerge_HeldByMergePolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsDir := filepath.Join(tmpDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	policy := `{"version": 1, "rules": [{"name": "two-approvals", "type": "approvals", "min_approvals": 2}]}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "merge-policy.json"), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	e.SetOutput(io.Discard)
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	mr := &MRInfo{ID: "gt-mr-1", Branch: "polecat/nux", Target: "main", Labels: []string{"approved-by:mayor"}}
+	result := e.doMerge(context.Background(), mr)
+	if result.Success || !result.PolicyBlocked {
+		t.Fatalf("expected MR to be held by policy, got %+v", result)
+	}
+	if !strings.Contains(result.Error, "two-approvals") {
+		t.Errorf("error should name the failing rule, got %q", result.Error)
+	}
+
+	// Policy passes: doMerge moves on to git (which fails here, since the
+	// temp rig has no repo) instead of holding the MR.
+	mr.Labels = append(mr.Labels, "approved-by:witness")
+	result = e.doMerge(context.Background(), mr)
+	if result.PolicyBlocked {
+		t.Errorf("MR with two approvals should pass policy, got %q", result.Error)
+	}
+}
+
+func TestEngineer_LoadConfig_InvalidPolicyHoldsMerges(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsDir := filepath.Join(tmpDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settingsDir, "merge-policy.json"), []byte(`{"rules": [{"type": "bogus"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	e.SetOutput(io.Discard)
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("invalid policy should not stop the refinery: %v", err)
+	}
+	if _, ok := e.checkPolicy(&MRInfo{ID: "gt-mr-1"}, time.Now()); ok {
+		t.Error("expected merges to be held while the policy is invalid")
+	}
+}
+
+func TestEngineer_LoadConfig_MalformedWebhooksDisablesThem(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsDir := filepath.Join(tmpDir, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settingsDir, "mq-webhooks.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	e.SetOutput(&out)
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("malformed webhooks should not stop the refinery: %v", err)
+	}
+	if e.webhooks != nil {
+		t.Error("expected webhooks to be disabled")
+	}
+	if !strings.Contains(out.String(), "webhooks disabled") {
+		t.Errorf("expected a warning, got %q", out.String())
+	}
+}
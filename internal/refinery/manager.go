@@ -176,9 +176,10 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "refinery",
-		Rig:      m.rig.Name,
-		TownRoot: townRoot,
+		Role:      "refinery",
+		Rig:       m.rig.Name,
+		TownRoot:  townRoot,
+		Toolchain: config.LoadRigToolchain(m.rig.Path),
 	})
 
 	// Add refinery-specific flag
@@ -236,9 +237,8 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	// BeadsPath() returns the git-synced beads location
 	b := beads.New(m.rig.BeadsPath())
 	issues, err := b.List(beads.ListOptions{
-		Label:    "gt:merge-request",
-		Status:   "open",
-		Priority: -1, // No priority filter
+		Label:  "gt:merge-request",
+		Status: "open",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("querying merge queue from beads: %w", err)
@@ -333,6 +333,7 @@ func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 			Status:       MROpen,
 			CreatedAt:    parseTime(issue.CreatedAt),
 			TargetBranch: defaultBranch,
+			Held:         beads.HasLabel(issue, HeldLabel),
 		}
 	}
 
@@ -350,6 +351,7 @@ func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 		TargetBranch: target,
 		Status:       MROpen,
 		CreatedAt:    parseTime(issue.CreatedAt),
+		Held:         beads.HasLabel(issue, HeldLabel),
 	}
 }
 
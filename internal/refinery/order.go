@@ -0,0 +1,276 @@
+package refinery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Merge ordering: a submitter can require that an MR merge only after
+// others ("merge A before B") without stacking it on them. B lists the MRs
+// it must follow in its merge_after field (gt mq submit --after, gt mq
+// order). The refinery passes B over while any of them is open, then
+// merges it like any other MR. A predecessor closed without merging holds
+// B until the constraint is cleared, since B may rely on it; a superseded
+// one is followed to its replacement. Constraints that would form a cycle
+// are refused when declared; a cycle made anyway (two declarations racing)
+// is reported on the MRs caught in it rather than leaving them waiting
+// silently.
+
+// ParseMergeAfter splits a merge_after field into MR IDs.
+func ParseMergeAfter(s string) []string {
+	return splitReviewers(s)
+}
+
+// formatCycle renders a merge order cycle as "a → b → a".
+func formatCycle(cycle []string) string {
+	return strings.Join(cycle, " → ")
+}
+
+// mergeOrderGraph returns the merge order constraints among the open MR
+// issues.
+func mergeOrderGraph(issues []*beads.Issue) map[string][]string {
+	after := make(map[string][]string)
+	for _, issue := range issues {
+		if fields := beads.ParseMRFields(issue); fields != nil && fields.MergeAfter != "" {
+			after[issue.ID] = ParseMergeAfter(fields.MergeAfter)
+		}
+	}
+	return after
+}
+
+// openMRIDs returns the IDs of the open MR issues.
+func openMRIDs(issues []*beads.Issue) map[string]bool {
+	open := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		if issue.Status == "open" {
+			open[issue.ID] = true
+		}
+	}
+	return open
+}
+
+// firstOpenPredecessor returns the first of the MRs in after that is
+// still open, or "".
+func firstOpenPredecessor(after []string, open map[string]bool) string {
+	for _, id := range after {
+		if open[id] {
+			return id
+		}
+	}
+	return ""
+}
+
+// checkMergeOrder returns ok=false with the result to report when mr must
+// wait for an MR it is ordered after.
+func (e *Engineer) checkMergeOrder(mr *MRInfo) (ProcessResult, bool) {
+	if len(mr.MergeAfter) == 0 || e.beads == nil {
+		return ProcessResult{}, true
+	}
+	for _, id := range mr.MergeAfter {
+		if msg := e.pendingPredecessor(mr.ID, id); msg != "" {
+			return ProcessResult{OrderBlocked: true, Error: msg}, false
+		}
+	}
+	return ProcessResult{}, true
+}
+
+// pendingPredecessor says why MR id, which mrID is ordered after, holds it
+// back, or returns "" once it has merged. A superseded predecessor is
+// followed to its replacement.
+func (e *Engineer) pendingPredecessor(mrID, id string) string {
+	seen := make(map[string]bool)
+	for {
+		if id == mrID {
+			return "" // this MR replaced the one it was ordered after
+		}
+		if seen[id] {
+			return fmt.Sprintf("ordered after %s, whose replacements loop; clear it with 'gt mq order --clear %s'", id, mrID)
+		}
+		seen[id] = true
+		issue, err := e.beads.Show(id)
+		if err != nil {
+			return fmt.Sprintf("looking up %s, which this MR is ordered after: %v", id, err)
+		}
+		switch stackParentState(issue) {
+		case parentLanded:
+			return ""
+		case parentPending:
+			if cycle := e.orderCycleFrom(mrID); cycle != nil {
+				return fmt.Sprintf("merge order cycle %s; clear it with 'gt mq order --clear %s'", formatCycle(cycle), mrID)
+			}
+			return fmt.Sprintf("ordered after %s, which has not merged", id)
+		}
+		if fields := beads.ParseMRFields(issue); fields != nil && fields.SupersededBy != "" {
+			id = fields.SupersededBy
+			continue
+		}
+		return fmt.Sprintf("ordered after %s, which was closed without merging; clear it with 'gt mq order --clear %s'", id, mrID)
+	}
+}
+
+// orderCycleFrom returns the merge order cycle among the open MRs that
+// leads from mrID back to itself, or nil.
+func (e *Engineer) orderCycleFrom(mrID string) []string {
+	issues, err := e.beads.List(beads.ListOptions{Status: "open", Label: "gt:merge-request"})
+	if err != nil {
+		return nil
+	}
+	return cycleFrom(mergeOrderGraph(issues), mrID)
+}
+
+// cycleFrom returns a path in the merge order graph after, which maps each
+// MR to the MRs it must merge after, from id back to itself (e.g. [a b a]),
+// or nil if id is in no cycle.
+func cycleFrom(after map[string][]string, id string) []string {
+	seen := make(map[string]bool)
+	var walk func(path []string) []string
+	walk = func(path []string) []string {
+		for _, next := range after[path[len(path)-1]] {
+			if next == id {
+				return append(path, next)
+			}
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			if cycle := walk(append(slices.Clone(path), next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk([]string{id})
+}
+
+// mrShower looks up MR beads.
+type mrShower interface {
+	Show(id string) (*beads.Issue, error)
+}
+
+// orderBeads is the subset of Beads OrderMRs and ClearMergeOrder use.
+type orderBeads interface {
+	Show(id string) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+}
+
+// OrderMRs requires the given open MRs to merge in the order given: each
+// after the one before it. Constraints already declared are kept. It
+// returns the MRs whose constraints changed, and fails without changing
+// anything if the new constraints would form a cycle.
+func (m *Manager) OrderMRs(ids []string) ([]string, error) {
+	return orderMRs(beads.New(m.rig.BeadsPath()), ids)
+}
+
+func orderMRs(b orderBeads, ids []string) ([]string, error) {
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("give at least two MRs, in merge order")
+	}
+	issues := make(map[string]*beads.Issue, len(ids))
+	for _, id := range ids {
+		if _, dup := issues[id]; dup {
+			return nil, fmt.Errorf("%s is given twice", id)
+		}
+		issue, err := showOpenMR(b, id)
+		if err != nil {
+			return nil, err
+		}
+		issues[id] = issue
+	}
+
+	open, err := b.List(beads.ListOptions{Status: "open", Label: "gt:merge-request"})
+	if err != nil {
+		return nil, fmt.Errorf("listing open MRs: %w", err)
+	}
+	after := mergeOrderGraph(open)
+	var changed []string
+	for i := 1; i < len(ids); i++ {
+		id, pred := ids[i], ids[i-1]
+		if !slices.Contains(after[id], pred) {
+			after[id] = append(after[id], pred)
+			changed = append(changed, id)
+		}
+	}
+	for _, id := range changed {
+		if cycle := cycleFrom(after, id); cycle != nil {
+			return nil, fmt.Errorf("merge order cycle %s: an MR can't merge before itself", formatCycle(cycle))
+		}
+	}
+
+	for _, id := range changed {
+		if err := setMergeAfter(b, issues[id], after[id]); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// ClearMergeOrder drops the merge order constraints of MR id and returns
+// the MRs it was ordered after.
+func (m *Manager) ClearMergeOrder(id string) ([]string, error) {
+	return clearMergeOrder(beads.New(m.rig.BeadsPath()), id)
+}
+
+func clearMergeOrder(b orderBeads, id string) ([]string, error) {
+	issue, err := showOpenMR(b, id)
+	if err != nil {
+		return nil, err
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.MergeAfter == "" {
+		return nil, nil
+	}
+	cleared := ParseMergeAfter(fields.MergeAfter)
+	if err := setMergeAfter(b, issue, nil); err != nil {
+		return nil, err
+	}
+	return cleared, nil
+}
+
+// setMergeAfter records the MRs issue must merge after.
+func setMergeAfter(b orderBeads, issue *beads.Issue, after []string) error {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	fields.MergeAfter = strings.Join(after, ", ")
+	desc := beads.SetMRFields(issue, fields)
+	if err := b.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("updating %s: %w", issue.ID, err)
+	}
+	return nil
+}
+
+// CheckMergeAfter validates the MRs a new MR is to merge after: each must
+// be an MR that is open or has merged. Merged ones are dropped, as there
+// is nothing left to wait for; the rest are returned. A new MR can't close
+// a cycle, since nothing is ordered after it yet.
+func CheckMergeAfter(b *beads.Beads, ids []string) ([]string, error) {
+	return checkMergeAfter(b, ids)
+}
+
+func checkMergeAfter(b mrShower, ids []string) ([]string, error) {
+	var keep []string
+	for _, id := range ids {
+		if slices.Contains(keep, id) {
+			continue
+		}
+		issue, err := b.Show(id)
+		if err != nil {
+			return nil, fmt.Errorf("looking up %s: %w", id, err)
+		}
+		if !beads.HasLabel(issue, "gt:merge-request") {
+			return nil, fmt.Errorf("%s is not a merge request", id)
+		}
+		switch stackParentState(issue) {
+		case parentPending:
+			keep = append(keep, id)
+		case parentAbandoned:
+			return nil, fmt.Errorf("%s was closed without merging", id)
+		}
+	}
+	return keep, nil
+}
@@ -0,0 +1,137 @@
+package refinery
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeOrderBeads adds List to the in-memory supersede fake.
+type fakeOrderBeads struct {
+	*fakeSupersedeBeads
+}
+
+func (f fakeOrderBeads) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	var out []*beads.Issue
+	for _, issue := range f.issues {
+		if opts.Match(issue) {
+			out = append(out, issue)
+		}
+	}
+	return out, nil
+}
+
+func newFakeOrderBeads() fakeOrderBeads {
+	f := fakeOrderBeads{&fakeSupersedeBeads{issues: map[string]*beads.Issue{}}}
+	for _, id := range []string{"gt-a", "gt-b", "gt-c"} {
+		f.issues[id] = &beads.Issue{ID: id, Status: "open", Labels: []string{"gt:merge-request"},
+			Description: "branch: polecat/" + id + "\ntarget: main"}
+	}
+	return f
+}
+
+func mergeAfterOf(t *testing.T, f fakeOrderBeads, id string) []string {
+	t.Helper()
+	fields := beads.ParseMRFields(f.issues[id])
+	if fields == nil {
+		t.Fatalf("%s has no MR fields", id)
+	}
+	return ParseMergeAfter(fields.MergeAfter)
+}
+
+func TestOrderMRs(t *testing.T) {
+	f := newFakeOrderBeads()
+
+	changed, err := orderMRs(f, []string{"gt-a", "gt-b", "gt-c"})
+	if err != nil {
+		t.Fatalf("orderMRs: %v", err)
+	}
+	if !slices.Equal(changed, []string{"gt-b", "gt-c"}) {
+		t.Errorf("changed = %v", changed)
+	}
+	if got := mergeAfterOf(t, f, "gt-c"); !slices.Equal(got, []string{"gt-b"}) {
+		t.Errorf("gt-c merge_after = %v", got)
+	}
+	if !strings.Contains(f.issues["gt-b"].Description, "branch: polecat/gt-b") {
+		t.Errorf("other fields lost: %q", f.issues["gt-b"].Description)
+	}
+
+	// Repeating a declared order changes nothing.
+	if changed, err := orderMRs(f, []string{"gt-a", "gt-b"}); err != nil || len(changed) != 0 {
+		t.Errorf("repeat = %v, %v", changed, err)
+	}
+
+	// Closing the loop is refused, naming the cycle, and changes nothing.
+	before := f.issues["gt-a"].Description
+	_, err = orderMRs(f, []string{"gt-c", "gt-a"})
+	if err == nil || !strings.Contains(err.Error(), "gt-a → gt-c → gt-b → gt-a") {
+		t.Errorf("cycle error = %v", err)
+	}
+	if f.issues["gt-a"].Description != before {
+		t.Error("refused order was recorded")
+	}
+
+	f.issues["gt-c"].Status = "closed"
+	if _, err := orderMRs(f, []string{"gt-a", "gt-c"}); err == nil {
+		t.Error("ordered a closed MR")
+	}
+	if _, err := orderMRs(f, []string{"gt-a", "gt-a"}); err == nil {
+		t.Error("ordered an MR after itself")
+	}
+}
+
+func TestClearMergeOrder(t *testing.T) {
+	f := newFakeOrderBeads()
+	if _, err := orderMRs(f, []string{"gt-a", "gt-b"}); err != nil {
+		t.Fatal(err)
+	}
+	cleared, err := clearMergeOrder(f, "gt-b")
+	if err != nil || !slices.Equal(cleared, []string{"gt-a"}) {
+		t.Fatalf("clearMergeOrder = %v, %v", cleared, err)
+	}
+	if got := mergeAfterOf(t, f, "gt-b"); got != nil {
+		t.Errorf("merge_after after clear = %v", got)
+	}
+	if cleared, err := clearMergeOrder(f, "gt-b"); err != nil || cleared != nil {
+		t.Errorf("clearing again = %v, %v", cleared, err)
+	}
+}
+
+func TestCheckMergeAfter(t *testing.T) {
+	f := newFakeOrderBeads()
+	f.issues["gt-merged"] = &beads.Issue{ID: "gt-merged", Status: "closed", Labels: []string{"gt:merge-request"},
+		Description: "branch: x\nclose_reason: merged"}
+	f.issues["gt-rejected"] = &beads.Issue{ID: "gt-rejected", Status: "closed", Labels: []string{"gt:merge-request"},
+		Description: "branch: y\nclose_reason: rejected"}
+	f.issues["gt-task"] = &beads.Issue{ID: "gt-task", Status: "open"}
+
+	keep, err := checkMergeAfter(f, []string{"gt-a", "gt-merged", "gt-a"})
+	if err != nil || !slices.Equal(keep, []string{"gt-a"}) {
+		t.Errorf("checkMergeAfter = %v, %v; want [gt-a]", keep, err)
+	}
+	for _, bad := range []string{"gt-rejected", "gt-task", "gt-missing"} {
+		if _, err := checkMergeAfter(f, []string{bad}); err == nil {
+			t.Errorf("checkMergeAfter accepted %s", bad)
+		}
+	}
+}
+
+func TestCycleFrom(t *testing.T) {
+	after := map[string][]string{
+		"a": {"b"},
+		"b": {"c", "d"},
+		"d": {"a"},
+		"e": {"a"},
+	}
+	if got := cycleFrom(after, "a"); !slices.Equal(got, []string{"a", "b", "d", "a"}) {
+		t.Errorf("cycleFrom(a) = %v", got)
+	}
+	if got := cycleFrom(after, "e"); got != nil {
+		t.Errorf("cycleFrom(e) = %v, want nil: e leads into a cycle but isn't in it", got)
+	}
+	if got := firstOpenPredecessor(after["b"], map[string]bool{"d": true}); got != "d" {
+		t.Errorf("firstOpenPredecessor = %q", got)
+	}
+}
@@ -0,0 +1,412 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/version"
+)
+
+// Post-merge actions. Each step in a rig's post_merge pipeline names one.
+const (
+	PostMergeDeleteBranch = "delete_branch" // Delete the source branch, local and remote
+	PostMergeTag          = "tag"           // Tag the merge commit and push the tag
+	PostMergeWebhook      = "webhook"       // POST the merged MR to a URL (e.g., a deploy trigger)
+	PostMergeChangelog    = "changelog"     // Append a line to a changelog bead as a comment
+	PostMergeNotify       = "notify"        // Send mail to an address
+	PostMergeCommand      = "command"       // Run a shell command in the refinery worktree
+)
+
+const (
+	defaultPostMergeRetryDelay = 2 * time.Second
+	defaultPostMergeTimeout    = 60 * time.Second
+)
+
+// PostMergeStep is one action in a rig's post-merge pipeline.
+//
+// Steps run in order, in the background, after an MR is merged and its beads
+// are closed. A step that fails is retried up to Retries more times, then
+// logged; later steps still run unless StopOnFailure is set.
+//
+// Text fields (Name, Message, To, Subject) expand the placeholders {rig},
+// {mr}, {branch}, {target}, {commit}, {short_commit}, {issue}, {worker} and
+// {date}. Commands get the same values as GT_MR_* environment variables,
+// plus the rig's toolchain pins like gate commands.
+type PostMergeStep struct {
+	Action string
+
+	// Name is the tag name for tag steps (e.g., "merged/{mr}").
+	Name string
+
+	// Message is the tag annotation, changelog line, or mail body.
+	Message string
+
+	// URL and Secret configure webhook steps. The body is the same JSON
+	// payload queue webhooks receive for the "merged" event.
+	URL    string
+	Secret string
+
+	// Bead is the changelog bead for changelog steps.
+	Bead string
+
+	// To and Subject address notify steps.
+	To      string
+	Subject string
+
+	// Cmd is the shell command for command steps.
+	Cmd string
+
+	// Remote for delete_branch and tag steps. Defaults to "origin".
+	Remote string
+
+	// Retries is the number of extra attempts after a failure.
+	Retries int
+
+	// RetryDelay is the wait between attempts. Zero uses defaultPostMergeRetryDelay.
+	RetryDelay time.Duration
+
+	// Timeout bounds webhook and command steps. Zero uses defaultPostMergeTimeout.
+	Timeout time.Duration
+
+	// StopOnFailure skips the remaining steps when this one fails.
+	StopOnFailure bool
+}
+
+// postMergeStepRaw is the JSON-friendly representation of PostMergeStep.
+type postMergeStepRaw struct {
+	Action        string `json:"action"`
+	Name          string `json:"name"`
+	Message       string `json:"message"`
+	URL           string `json:"url"`
+	Secret        string `json:"secret"`
+	Bead          string `json:"bead"`
+	To            string `json:"to"`
+	Subject       string `json:"subject"`
+	Cmd           string `json:"cmd"`
+	Remote        string `json:"remote"`
+	Retries       int    `json:"retries"`
+	RetryDelay    string `json:"retry_delay"`
+	Timeout       string `json:"timeout"`
+	StopOnFailure bool   `json:"stop_on_failure"`
+}
+
+// parsePostMergeSteps converts and validates the post_merge config section.
+func parsePostMergeSteps(raw []postMergeStepRaw) ([]PostMergeStep, error) {
+	steps := make([]PostMergeStep, 0, len(raw))
+	for i, r := range raw {
+		s := PostMergeStep{
+			Action:        r.Action,
+			Name:          r.Name,
+			Message:       r.Message,
+			URL:           r.URL,
+			Secret:        r.Secret,
+			Bead:          r.Bead,
+			To:            r.To,
+			Subject:       r.Subject,
+			Cmd:           r.Cmd,
+			Remote:        r.Remote,
+			Retries:       r.Retries,
+			StopOnFailure: r.StopOnFailure,
+		}
+		if r.RetryDelay != "" {
+			dur, err := time.ParseDuration(r.RetryDelay)
+			if err != nil || dur < 0 {
+				return nil, fmt.Errorf("post_merge step %d: invalid retry_delay %q", i+1, r.RetryDelay)
+			}
+			s.RetryDelay = dur
+		}
+		if r.Timeout != "" {
+			dur, err := time.ParseDuration(r.Timeout)
+			if err != nil || dur <= 0 {
+				return nil, fmt.Errorf("post_merge step %d: invalid timeout %q", i+1, r.Timeout)
+			}
+			s.Timeout = dur
+		}
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("post_merge step %d (%s): %w", i+1, r.Action, err)
+		}
+		steps = append(steps, s)
+	}
+	return steps, nil
+}
+
+func (s *PostMergeStep) validate() error {
+	if s.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	switch s.Action {
+	case PostMergeDeleteBranch:
+	case PostMergeTag:
+		if s.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+	case PostMergeWebhook:
+		w := mq.Webhook{URL: s.URL}
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	case PostMergeChangelog:
+		if s.Bead == "" {
+			return fmt.Errorf("bead is required")
+		}
+	case PostMergeNotify:
+		if s.To == "" {
+			return fmt.Errorf("to is required")
+		}
+	case PostMergeCommand:
+		if s.Cmd == "" {
+			return fmt.Errorf("cmd is required")
+		}
+	case "":
+		return fmt.Errorf("action is required")
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
+
+// PostMergeResult records the outcome of one post-merge step.
+type PostMergeResult struct {
+	Action   string
+	Attempts int
+	Err      error
+	Skipped  bool // An earlier StopOnFailure step failed
+}
+
+// postMergeSteps returns the pipeline for this rig. Without a configured
+// post_merge section it is the legacy behavior: delete the merged branch
+// when delete_merged_branches is set.
+func (e *Engineer) postMergeSteps() []PostMergeStep {
+	if e.config.PostMerge != nil {
+		return e.config.PostMerge
+	}
+	if e.config.DeleteMergedBranches {
+		return []PostMergeStep{{Action: PostMergeDeleteBranch}}
+	}
+	return nil
+}
+
+// postMergeJob is a merged MR waiting for its post-merge pipeline.
+type postMergeJob struct {
+	mr     MRInfo
+	result ProcessResult
+}
+
+// enqueuePostMerge queues the post-merge pipeline for a merged MR and returns
+// without waiting for it. Pipelines run one at a time, in merge order, so
+// steps that touch the refinery worktree never race each other. Call
+// WaitPostMerge before exiting to let queued pipelines finish.
+func (e *Engineer) enqueuePostMerge(mr *MRInfo, result ProcessResult) {
+	if len(e.postMergeSteps()) == 0 {
+		return
+	}
+	e.postMergeOnce.Do(func() {
+		e.postMergeJobs = make(chan postMergeJob, 64)
+		go func() {
+			for job := range e.postMergeJobs {
+				e.runPostMerge(context.Background(), &job.mr, job.result)
+				e.postMergeWG.Done()
+			}
+		}()
+	})
+	e.postMergeWG.Add(1)
+	e.postMergeJobs <- postMergeJob{mr: *mr, result: result}
+}
+
+// WaitPostMerge blocks until every queued post-merge pipeline has finished.
+func (e *Engineer) WaitPostMerge() {
+	e.postMergeWG.Wait()
+}
+
+// runPostMerge runs the post-merge pipeline for a merged MR, logging each step.
+func (e *Engineer) runPostMerge(ctx context.Context, mr *MRInfo, result ProcessResult) []PostMergeResult {
+	steps := e.postMergeSteps()
+	vars := postMergeVars(e.rig.Name, mr, result.MergeCommit, time.Now())
+	results := make([]PostMergeResult, 0, len(steps))
+	stopped := false
+
+	for i := range steps {
+		step := &steps[i]
+		label := fmt.Sprintf("post-merge %d/%d %s", i+1, len(steps), step.Action)
+		if stopped {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s: skipped\n", label)
+			results = append(results, PostMergeResult{Action: step.Action, Skipped: true})
+			continue
+		}
+
+		res := PostMergeResult{Action: step.Action}
+		delay := step.RetryDelay
+		if delay <= 0 {
+			delay = defaultPostMergeRetryDelay
+		}
+		for attempt := 1; attempt <= step.Retries+1; attempt++ {
+			res.Attempts = attempt
+			var detail string
+			detail, res.Err = e.runPostMergeStep(ctx, step, mr, result, vars)
+			if res.Err == nil {
+				if detail != "" {
+					label += " (" + detail + ")"
+				}
+				break
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s: attempt %d/%d failed: %v\n", label, attempt, step.Retries+1, res.Err)
+			if attempt <= step.Retries {
+				select {
+				case <-ctx.Done():
+					res.Err = ctx.Err()
+					attempt = step.Retries + 1
+				case <-time.After(delay):
+				}
+			}
+		}
+
+		if res.Err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %s failed: %v\n", label, res.Err)
+			stopped = step.StopOnFailure
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s: ok\n", label)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// runPostMergeStep performs a single attempt of step and returns a short
+// description of what it did.
+func (e *Engineer) runPostMergeStep(ctx context.Context, step *PostMergeStep, mr *MRInfo, result ProcessResult, vars *strings.Replacer) (string, error) {
+	remote := step.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultPostMergeTimeout
+	}
+
+	switch step.Action {
+	case PostMergeDeleteBranch:
+		if mr.Branch == "" {
+			return "no branch", nil
+		}
+		// The local branch may never have existed in this worktree; only the
+		// remote deletion is worth retrying.
+		if err := e.git.DeleteBranch(mr.Branch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Note: local branch %s: %v\n", mr.Branch, err)
+		}
+		if err := e.git.DeleteRemoteBranch(remote, mr.Branch); err != nil {
+			return "", fmt.Errorf("deleting %s/%s: %w", remote, mr.Branch, err)
+		}
+		return mr.Branch, nil
+
+	case PostMergeTag:
+		if result.MergeCommit == "" {
+			return "", fmt.Errorf("no merge commit to tag")
+		}
+		name := vars.Replace(step.Name)
+		// A tag left by an earlier attempt is reused rather than recreated.
+		if err := e.git.CreateTag(name, result.MergeCommit, vars.Replace(step.Message)); err != nil && !strings.Contains(err.Error(), "already exists") {
+			return "", fmt.Errorf("creating tag %s: %w", name, err)
+		}
+		if err := e.git.Push(remote, "refs/tags/"+name, false); err != nil {
+			return "", fmt.Errorf("pushing tag %s: %w", name, err)
+		}
+		return name, nil
+
+	case PostMergeWebhook:
+		d := mq.NewDispatcher(e.rig.Name, []mq.Webhook{{URL: step.URL, Secret: step.Secret, Timeout: timeout.String()}})
+		err := d.Dispatch(mq.EventMerged, mq.WebhookPayload{
+			MRID:        mr.ID,
+			Branch:      mr.Branch,
+			Target:      mr.Target,
+			SourceIssue: mr.SourceIssue,
+			Worker:      mr.Worker,
+			MergeCommit: result.MergeCommit,
+			Summary:     result.Summary,
+		})
+		return step.URL, err
+
+	case PostMergeChangelog:
+		line := step.Message
+		if line == "" {
+			line = "{short_commit} {mr} ({issue}) merged to {target}"
+		}
+		if err := e.beads.AddComment(step.Bead, vars.Replace(line)); err != nil {
+			return "", fmt.Errorf("updating changelog bead %s: %w", step.Bead, err)
+		}
+		return step.Bead, nil
+
+	case PostMergeNotify:
+		subject, body := step.Subject, step.Message
+		if subject == "" {
+			subject = "Merged {mr} to {target}"
+		}
+		if body == "" {
+			body = "MR: {mr}\nIssue: {issue}\nBranch: {branch}\nCommit: {commit}\nWorker: {worker}"
+		}
+		to := vars.Replace(step.To)
+		msg := mail.NewMessage(e.rig.Name+"/refinery", to, vars.Replace(subject), vars.Replace(body))
+		if err := e.router.Send(msg); err != nil {
+			return "", fmt.Errorf("mailing %s: %w", to, err)
+		}
+		return to, nil
+
+	case PostMergeCommand:
+		return "", e.runPostMergeCommand(ctx, step.Cmd, timeout, mr, result)
+	}
+	return "", fmt.Errorf("unknown action %q", step.Action)
+}
+
+// runPostMergeCommand runs a command step in the refinery worktree.
+func (e *Engineer) runPostMergeCommand(ctx context.Context, command string, timeout time.Duration, mr *MRInfo, result ProcessResult) error {
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, "sh", "-c", command) //nolint:gosec // G204: post-merge command is from trusted rig config
+	cmd.Dir = e.workDir
+	cmd.Env = e.commandEnv(
+		"GT_MR_ID="+mr.ID,
+		"GT_MR_BRANCH="+mr.Branch,
+		"GT_MR_TARGET="+mr.Target,
+		"GT_MR_SOURCE_ISSUE="+mr.SourceIssue,
+		"GT_MR_WORKER="+mr.Worker,
+		"GT_MR_MERGE_COMMIT="+result.MergeCommit,
+		"GT_RIG="+e.rig.Name,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if cmdCtx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > 500 {
+				msg = msg[:500] + "..."
+			}
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// postMergeVars returns the placeholder replacer for step text fields.
+func postMergeVars(rigName string, mr *MRInfo, commit string, now time.Time) *strings.Replacer {
+	return strings.NewReplacer(
+		"{rig}", rigName,
+		"{mr}", mr.ID,
+		"{branch}", mr.Branch,
+		"{target}", mr.Target,
+		"{commit}", commit,
+		"{short_commit}", version.ShortCommit(commit),
+		"{issue}", mr.SourceIssue,
+		"{worker}", mr.Worker,
+		"{date}", now.Format("2006-01-02"),
+	)
+}
@@ -0,0 +1,242 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestEngineer_LoadConfig_PostMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		postMerge interface{}
+		wantSteps int
+		wantErr   string
+	}{
+		{"pipeline", []map[string]interface{}{
+			{"action": "delete_branch"},
+			{"action": "tag", "name": "merged/{mr}", "retries": 2, "retry_delay": "5s"},
+			{"action": "webhook", "url": "https://deploy.example.com/hook", "timeout": "10s", "stop_on_failure": true},
+			{"action": "changelog", "bead": "gt-changelog"},
+			{"action": "notify", "to": "mayor/"},
+			{"action": "command", "cmd": "make release-notes"},
+		}, 6, ""},
+		{"empty disables", []interface{}{}, 0, ""},
+		{"unknown action", []map[string]interface{}{{"action": "deploy"}}, 0, "unknown action"},
+		{"missing field", []map[string]interface{}{{"action": "tag"}}, 0, "name is required"},
+		{"bad url", []map[string]interface{}{{"action": "webhook", "url": "ftp://x"}}, 0, "invalid url"},
+		{"bad retry delay", []map[string]interface{}{{"action": "delete_branch", "retry_delay": "soon"}}, 0, "retry_delay"},
+		{"negative retries", []map[string]interface{}{{"action": "delete_branch", "retries": -1}}, 0, "retries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			data, _ := json.Marshal(map[string]interface{}{
+				"merge_queue": map[string]interface{}{"post_merge": tt.postMerge},
+			})
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+			err := e.LoadConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.config.PostMerge == nil || len(e.config.PostMerge) != tt.wantSteps {
+				t.Fatalf("PostMerge = %+v, want %d steps", e.config.PostMerge, tt.wantSteps)
+			}
+			if tt.wantSteps > 0 {
+				tag := e.config.PostMerge[1]
+				if tag.Retries != 2 || tag.RetryDelay != 5*time.Second {
+					t.Errorf("tag step = %+v", tag)
+				}
+				if !e.config.PostMerge[2].StopOnFailure || e.config.PostMerge[2].Timeout != 10*time.Second {
+					t.Errorf("webhook step = %+v", e.config.PostMerge[2])
+				}
+			}
+		})
+	}
+}
+
+func TestPostMergeSteps_Default(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	if steps := e.postMergeSteps(); len(steps) != 1 || steps[0].Action != PostMergeDeleteBranch {
+		t.Errorf("default steps = %+v, want delete_branch", steps)
+	}
+	e.config.DeleteMergedBranches = false
+	if steps := e.postMergeSteps(); len(steps) != 0 {
+		t.Errorf("steps = %+v, want none", steps)
+	}
+	e.config.PostMerge = []PostMergeStep{}
+	e.config.DeleteMergedBranches = true
+	if steps := e.postMergeSteps(); len(steps) != 0 {
+		t.Errorf("an empty post_merge should disable branch deletion, got %+v", steps)
+	}
+}
+
+func TestRunPostMerge_RetriesAndStop(t *testing.T) {
+	dir := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.workDir = dir
+
+	// Fails on the first attempt, succeeds on the second.
+	flaky := `test -f attempted || { touch attempted; exit 1; }; echo "$GT_MR_ID $GT_MR_MERGE_COMMIT" > ran`
+	e.config.PostMerge = []PostMergeStep{
+		{Action: PostMergeCommand, Cmd: flaky, Retries: 1, RetryDelay: time.Millisecond},
+		{Action: PostMergeCommand, Cmd: "exit 1", Retries: 2, RetryDelay: time.Millisecond},
+		{Action: PostMergeCommand, Cmd: "exit 1", StopOnFailure: true},
+		{Action: PostMergeCommand, Cmd: "touch never"},
+	}
+
+	results := e.runPostMerge(context.Background(), &MRInfo{ID: "gt-mr1"}, ProcessResult{MergeCommit: "abc123"})
+	if len(results) != 4 {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[0].Err != nil || results[0].Attempts != 2 {
+		t.Errorf("flaky step = %+v, want success on attempt 2", results[0])
+	}
+	if results[1].Err == nil || results[1].Attempts != 3 {
+		t.Errorf("failing step = %+v, want 3 failed attempts", results[1])
+	}
+	if results[2].Err == nil || results[2].Skipped {
+		t.Errorf("stop step = %+v", results[2])
+	}
+	if !results[3].Skipped {
+		t.Errorf("step after stop_on_failure should be skipped: %+v", results[3])
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "ran"))
+	if err != nil || strings.TrimSpace(string(out)) != "gt-mr1 abc123" {
+		t.Errorf("command env: %q, %v", out, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); err == nil {
+		t.Error("skipped step ran")
+	}
+}
+
+func TestEnqueuePostMerge_RunsInBackground(t *testing.T) {
+	dir := t.TempDir()
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.workDir = dir
+	e.toolchain = &config.ToolchainConfig{Node: "20.11.1"}
+	// Blocks until the test lets it go: a synchronous pipeline would time out.
+	e.config.PostMerge = []PostMergeStep{{
+		Action:  PostMergeCommand,
+		Cmd:     `while [ ! -f go ]; do sleep 0.01; done; echo "$GT_MR_ID $GT_TOOLCHAIN_NODE" > ran`,
+		Timeout: 5 * time.Second,
+	}}
+
+	mr := &MRInfo{ID: "gt-mr1"}
+	e.enqueuePostMerge(mr, ProcessResult{MergeCommit: "abc123"})
+	mr.ID = "gt-mr2" // The queued job keeps its own copy
+	if err := os.WriteFile(filepath.Join(dir, "go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.WaitPostMerge()
+
+	out, err := os.ReadFile(filepath.Join(dir, "ran"))
+	if err != nil || strings.TrimSpace(string(out)) != "gt-mr1 20.11.1" {
+		t.Errorf("command output = %q, %v; want MR ID and toolchain pin", out, err)
+	}
+}
+
+func TestRunPostMerge_Webhook(t *testing.T) {
+	var got mq.WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.config.PostMerge = []PostMergeStep{{Action: PostMergeWebhook, URL: srv.URL}}
+
+	results := e.runPostMerge(context.Background(),
+		&MRInfo{ID: "gt-mr1", Branch: "polecat/Toast/gt-abc", Target: "main"},
+		ProcessResult{MergeCommit: "abc123", Summary: "Commits (1)"})
+	if results[0].Err != nil {
+		t.Fatal(results[0].Err)
+	}
+	if got.Event != mq.EventMerged || got.Rig != "test-rig" || got.MRID != "gt-mr1" || got.MergeCommit != "abc123" || got.Summary != "Commits (1)" {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestRunPostMerge_TagAndDeleteBranch(t *testing.T) {
+	dir, _ := summaryTestRepo(t)
+	origin := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run(origin, "init", "-q", "--bare")
+	run(dir, "remote", "add", "origin", origin)
+	run(dir, "push", "-q", "origin", "feature")
+	commit := run(dir, "rev-parse", "feature")
+	run(dir, "checkout", "-q", "-")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.git = git.NewGit(dir)
+	e.workDir = dir
+	e.config.PostMerge = []PostMergeStep{
+		{Action: PostMergeTag, Name: "merged/{mr}", Message: "{issue} via {branch}"},
+		{Action: PostMergeDeleteBranch},
+	}
+
+	results := e.runPostMerge(context.Background(),
+		&MRInfo{ID: "gt-mr1", Branch: "feature", SourceIssue: "gt-abc"},
+		ProcessResult{MergeCommit: commit})
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%s: %v", r.Action, r.Err)
+		}
+	}
+
+	if got := run(origin, "rev-parse", "merged/gt-mr1^{commit}"); got != commit {
+		t.Errorf("pushed tag points at %s, want %s", got, commit)
+	}
+	if got := run(dir, "tag", "-n1", "merged/gt-mr1"); !strings.Contains(got, "gt-abc via feature") {
+		t.Errorf("tag annotation = %q", got)
+	}
+	if got := run(origin, "branch", "--list", "feature"); got != "" {
+		t.Errorf("remote branch not deleted: %q", got)
+	}
+}
+
+func TestPostMergeVars(t *testing.T) {
+	vars := postMergeVars("gastown", &MRInfo{ID: "gt-mr1", Branch: "b", Target: "main", SourceIssue: "gt-1", Worker: "Toast"},
+		"0123456789abcdef", time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	got := vars.Replace("{rig} {mr} {branch} {target} {short_commit} {issue} {worker} {date} {unknown}")
+	want := "gastown gt-mr1 b main 0123456789ab gt-1 Toast 2026-03-04 {unknown}"
+	if got != want {
+		t.Errorf("Replace = %q, want %q", got, want)
+	}
+}
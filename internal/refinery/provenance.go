@@ -0,0 +1,177 @@
+package refinery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+// Merge provenance is recorded as a git note on each squash commit the
+// refinery pushes, so the history says who merged what, with which approvals
+// and test results, without access to the town's beads. Read it back with
+// 'gt mq provenance <commit>' or 'git log --notes=gastown-provenance'.
+const (
+	// ProvenanceNotesRef is the notes ref holding merge provenance.
+	ProvenanceNotesRef = "refs/notes/gastown-provenance"
+
+	// ProvenanceSchema identifies the note format.
+	ProvenanceSchema = "gastown.merge-provenance/v1"
+)
+
+// ErrNoProvenance is returned when a commit has no provenance note.
+var ErrNoProvenance = errors.New("no merge provenance recorded for commit")
+
+// Provenance describes how a commit landed through the merge queue.
+type Provenance struct {
+	Schema      string          `json:"schema"`
+	MR          string          `json:"mr"`
+	SourceIssue string          `json:"source_issue,omitempty"`
+	Worker      string          `json:"worker,omitempty"`
+	Rig         string          `json:"rig,omitempty"`
+	Branch      string          `json:"branch"`
+	Target      string          `json:"target"`
+	Approvals   []string        `json:"approvals,omitempty"`
+	Tests       []TestRecord    `json:"tests,omitempty"`
+	TestsDigest string          `json:"tests_digest,omitempty"` // sha256 over Tests
+	Checks      []CheckResult   `json:"checks,omitempty"`       // MR required checks
+	Policy      []mq.RuleResult `json:"policy,omitempty"`       // merge policy evaluation
+	Rebase      *RebaseRecord   `json:"rebase,omitempty"`       // auto-rebase before the merge
+	MergedAt    time.Time       `json:"merged_at"`
+}
+
+// TestRecord is one quality gate (or the legacy test command) that passed
+// before the merge.
+type TestRecord struct {
+	Name         string `json:"name"`
+	Passed       bool   `json:"passed"`
+	Elapsed      string `json:"elapsed"`
+	OutputSHA256 string `json:"output_sha256,omitempty"`
+}
+
+// newProvenance builds the provenance of mr from a successful merge result.
+func newProvenance(mr *MRInfo, result ProcessResult, now time.Time) *Provenance {
+	p := &Provenance{
+		Schema:      ProvenanceSchema,
+		MR:          mr.ID,
+		SourceIssue: mr.SourceIssue,
+		Worker:      mr.Worker,
+		Rig:         mr.Rig,
+		Branch:      mr.Branch,
+		Target:      mr.Target,
+		Checks:      result.Checks,
+		Policy:      result.Policy,
+		Rebase:      result.Rebase,
+		MergedAt:    now.UTC(),
+	}
+	for _, l := range mr.Labels {
+		if strings.HasPrefix(l, mq.ApprovalLabelPrefix) {
+			p.Approvals = append(p.Approvals, strings.TrimPrefix(l, mq.ApprovalLabelPrefix))
+		}
+	}
+	for _, g := range result.Gates {
+		p.Tests = append(p.Tests, TestRecord{
+			Name:         g.Name,
+			Passed:       g.Success,
+			Elapsed:      g.Elapsed.Truncate(time.Millisecond).String(),
+			OutputSHA256: g.OutputDigest,
+		})
+	}
+	sort.Slice(p.Tests, func(i, j int) bool { return p.Tests[i].Name < p.Tests[j].Name })
+	p.TestsDigest = testsDigest(p.Tests)
+	return p
+}
+
+// testsDigest hashes the test names, outcomes and output digests, so two
+// notes can be compared at a glance. Elapsed times are left out.
+func testsDigest(tests []TestRecord) string {
+	if len(tests) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, t := range tests {
+		_, _ = fmt.Fprintf(h, "%s\t%t\t%s\n", t.Name, t.Passed, t.OutputSHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// outputDigest returns the hex sha256 of a command's output.
+func outputDigest(output ...[]byte) string {
+	h := sha256.New()
+	for _, o := range output {
+		_, _ = h.Write(o)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// EncodeProvenance renders p as the note text.
+func EncodeProvenance(p *Provenance) (string, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// DecodeProvenance parses a provenance note.
+func DecodeProvenance(note string) (*Provenance, error) {
+	var p Provenance
+	if err := json.Unmarshal([]byte(note), &p); err != nil {
+		return nil, fmt.Errorf("parsing provenance note: %w", err)
+	}
+	if p.Schema != ProvenanceSchema {
+		return nil, fmt.Errorf("unsupported provenance schema %q", p.Schema)
+	}
+	return &p, nil
+}
+
+// WriteProvenance attaches p to commit and publishes the notes ref to
+// remote. The remote's notes are fetched first so notes from other
+// refineries are kept; if another push wins the race, it retries once.
+func WriteProvenance(g *git.Git, remote, commit string, p *Provenance) error {
+	note, err := EncodeProvenance(p)
+	if err != nil {
+		return err
+	}
+	var pushErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		// The ref doesn't exist on the remote until the first note is pushed
+		_ = g.FetchNotes(remote, ProvenanceNotesRef)
+		if err := g.AddNote(ProvenanceNotesRef, commit, note); err != nil {
+			return fmt.Errorf("adding provenance note: %w", err)
+		}
+		if pushErr = g.PushNotes(remote, ProvenanceNotesRef); pushErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("pushing provenance notes: %w", pushErr)
+}
+
+// ReadProvenance returns the provenance of commit, fetching the notes ref
+// from remote when the local one doesn't have it. It returns ErrNoProvenance
+// if neither does.
+func ReadProvenance(g *git.Git, remote, commit string) (*Provenance, error) {
+	note, found, err := g.Note(ProvenanceNotesRef, commit)
+	if err != nil {
+		return nil, err
+	}
+	if !found && remote != "" {
+		if fetchErr := g.FetchNotes(remote, ProvenanceNotesRef); fetchErr == nil {
+			note, found, err = g.Note(ProvenanceNotesRef, commit)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !found {
+		return nil, ErrNoProvenance
+	}
+	return DecodeProvenance(note)
+}
@@ -0,0 +1,104 @@
+package refinery
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+func TestNewProvenance(t *testing.T) {
+	mr := &MRInfo{
+		ID:          "gt-mr-1",
+		SourceIssue: "gt-abc",
+		Worker:      "gastown/polecats/nux",
+		Branch:      "polecat/nux/gt-abc",
+		Target:      "main",
+		Labels:      []string{"gt:merge-request", "approved-by:mayor", "approved-by:gastown/crew/joe"},
+	}
+	result := ProcessResult{
+		Gates: []GateResult{
+			{Name: "unit", Success: true, Elapsed: 1500 * time.Millisecond, OutputDigest: "aa"},
+			{Name: "lint", Success: true, Elapsed: time.Second, OutputDigest: "bb"},
+		},
+		Policy: []mq.RuleResult{{Name: "approvals", Type: mq.RuleApprovals, Passed: true, Reason: "2/2 approvals"}},
+	}
+	p := newProvenance(mr, result, time.Now())
+
+	if len(p.Approvals) != 2 || p.Approvals[0] != "mayor" {
+		t.Errorf("Approvals = %v", p.Approvals)
+	}
+	if len(p.Tests) != 2 || p.Tests[0].Name != "lint" {
+		t.Errorf("Tests not sorted by name: %+v", p.Tests)
+	}
+	if p.TestsDigest == "" {
+		t.Error("TestsDigest is empty")
+	}
+
+	// The digest ignores timing but not outcomes
+	result.Gates[0].Elapsed = time.Minute
+	if got := newProvenance(mr, result, time.Now()).TestsDigest; got != p.TestsDigest {
+		t.Error("TestsDigest changed with elapsed time")
+	}
+	result.Gates[0].OutputDigest = "cc"
+	if got := newProvenance(mr, result, time.Now()).TestsDigest; got == p.TestsDigest {
+		t.Error("TestsDigest did not change with gate output")
+	}
+}
+
+func TestWriteReadProvenance(t *testing.T) {
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	work := filepath.Join(root, "work")
+	other := filepath.Join(root, "other")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	run(root, "init", "-q", "--bare", origin)
+	run(root, "clone", "-q", origin, work)
+	run(work, "config", "user.email", "test@test.com")
+	run(work, "config", "user.name", "Test User")
+	run(work, "commit", "-q", "--allow-empty", "-m", "merged work")
+	run(work, "push", "-q", "origin", "HEAD")
+	run(root, "clone", "-q", origin, other)
+
+	g := git.NewGit(work)
+	commit, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := newProvenance(&MRInfo{ID: "gt-mr-1", Branch: "polecat/nux", Target: "main"}, ProcessResult{}, time.Now())
+	if err := WriteProvenance(g, "origin", commit, want); err != nil {
+		t.Fatalf("WriteProvenance: %v", err)
+	}
+
+	// Another clone fetches the notes on demand
+	got, err := ReadProvenance(git.NewGit(other), "origin", commit)
+	if err != nil {
+		t.Fatalf("ReadProvenance: %v", err)
+	}
+	if got.MR != "gt-mr-1" || got.Branch != "polecat/nux" || got.Schema != ProvenanceSchema {
+		t.Errorf("got %+v", got)
+	}
+
+	// Commits without a note
+	run(work, "commit", "-q", "--allow-empty", "-m", "direct push")
+	head, _ := g.Rev("HEAD")
+	if _, err := ReadProvenance(g, "", head); !errors.Is(err, ErrNoProvenance) {
+		t.Errorf("err = %v, want ErrNoProvenance", err)
+	}
+	if _, err := ReadProvenance(g, "", "no-such-commit"); err == nil || errors.Is(err, ErrNoProvenance) {
+		t.Errorf("err = %v, want a bad-revision error", err)
+	}
+}
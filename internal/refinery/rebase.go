@@ -0,0 +1,116 @@
+package refinery
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Auto-rebase: with merge_queue.on_conflict set to "auto_rebase", an MR
+// whose target has moved on since the branch was cut is rebased onto the
+// target before the conflict check, gates and merge, so they run against
+// what will actually land. The rebase happens on a scratch branch, like a
+// stacked MR's; the worker's branch is untouched. A rebase that conflicts
+// fails the MR as a conflict, as it would have failed at merge time. Every
+// outcome is commented on the MR, and a successful rebase is recorded in
+// the merge provenance.
+
+// rebaseBranchPrefix names the scratch branch an MR is auto-rebased on.
+const rebaseBranchPrefix = "gt-rebase/"
+
+// Auto-rebase outcomes.
+const (
+	RebaseOutcomeRebased  = "rebased"
+	RebaseOutcomeConflict = "conflict"
+)
+
+// RebaseRecord describes an auto-rebase of an MR branch onto its target.
+type RebaseRecord struct {
+	Outcome string `json:"outcome"`          // rebased | conflict
+	Onto    string `json:"onto"`             // Target commit the branch was rebased onto
+	Behind  int    `json:"behind,omitempty"` // Target commits the branch was missing
+	Error   string `json:"error,omitempty"`  // Why a conflicted rebase failed
+}
+
+// autoRebase rebases branch onto target when the rig uses auto_rebase and
+// target has moved past the branch. It returns the branch to merge, with a
+// cleanup to run once the merge is done, and the record of what it did (nil
+// when no rebase was needed). The target is checked out again before it
+// returns.
+func (e *Engineer) autoRebase(mr *MRInfo, branch, target string) (string, func(), *RebaseRecord, *ProcessResult) {
+	noop := func() {}
+	if e.config.OnConflict != config.OnConflictAutoRebase {
+		return branch, noop, nil, nil
+	}
+	upToDate, err := e.git.IsAncestor(target, branch)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not tell whether %s is behind %s: %v\n", branch, target, err)
+		return branch, noop, nil, nil
+	}
+	if upToDate {
+		return branch, noop, nil, nil
+	}
+
+	onto, err := e.git.Rev(target)
+	if err != nil {
+		return "", nil, nil, &ProcessResult{Error: fmt.Sprintf("resolving %s: %v", target, err)}
+	}
+	behind, _ := e.git.CommitsAhead(branch, target)
+	record := &RebaseRecord{Onto: onto, Behind: behind}
+
+	scratch := rebaseBranchPrefix + mr.ID
+	cleanup := func() {
+		if err := e.git.DeleteBranch(scratch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete %s: %v\n", scratch, err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %s is %d commit(s) behind %s, rebasing...\n", branch, behind, target)
+	_ = e.git.DeleteBranch(scratch, true) // left over from an interrupted run
+	if err := e.git.CreateBranchFrom(scratch, branch); err != nil {
+		return "", nil, nil, &ProcessResult{Error: fmt.Sprintf("creating %s: %v", scratch, err)}
+	}
+	if err := e.git.RebaseOnto(target, target, scratch); err != nil {
+		_ = e.git.AbortRebase()
+		_ = e.git.Checkout(target)
+		cleanup()
+		record.Outcome = RebaseOutcomeConflict
+		record.Error = err.Error()
+		e.recordRebase(mr, target, record)
+		return "", nil, record, &ProcessResult{
+			Conflict: true,
+			Rebase:   record,
+			Error:    fmt.Sprintf("auto-rebase onto %s (%d commit(s) behind) conflicted: %v", target, behind, err),
+		}
+	}
+	if err := e.git.Checkout(target); err != nil {
+		cleanup()
+		return "", nil, nil, &ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", target, err)}
+	}
+	record.Outcome = RebaseOutcomeRebased
+	e.recordRebase(mr, target, record)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rebased %s onto %s (%s)\n", branch, target, shortSHA(onto))
+	return scratch, cleanup, record, nil
+}
+
+// recordRebase comments an auto-rebase outcome on the MR bead.
+func (e *Engineer) recordRebase(mr *MRInfo, target string, r *RebaseRecord) {
+	if e.beads == nil || mr.ID == "" {
+		return
+	}
+	msg := fmt.Sprintf("Auto-rebased onto %s at %s (%d commit(s) behind)", target, shortSHA(r.Onto), r.Behind)
+	if r.Outcome == RebaseOutcomeConflict {
+		msg = fmt.Sprintf("Auto-rebase onto %s at %s (%d commit(s) behind) conflicted: %s", target, shortSHA(r.Onto), r.Behind, r.Error)
+	}
+	if err := e.beads.AddComment(mr.ID, msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record rebase on MR %s: %v\n", mr.ID, err)
+	}
+}
+
+// shortSHA abbreviates a commit hash for messages.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
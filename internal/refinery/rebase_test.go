@@ -0,0 +1,95 @@
+package refinery
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestAutoRebase(t *testing.T) {
+	dir, base := summaryTestRepo(t) // feature: two commits on base
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", ".")
+		run("commit", "-q", "-m", msg)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.git = git.NewGit(dir)
+	mr := &MRInfo{ID: "gt-mr1", Branch: "feature"}
+
+	// The target has not moved: nothing to do.
+	run("checkout", "-q", base)
+	e.config.OnConflict = config.OnConflictAutoRebase
+	if branch, _, rec, res := e.autoRebase(mr, "feature", base); res != nil || rec != nil || branch != "feature" {
+		t.Fatalf("up to date = %q, %+v, %v", branch, rec, res)
+	}
+
+	// The target moves on.
+	commit("c.go", "package c\n", "feat: add c")
+
+	// Off by default.
+	e.config.OnConflict = config.OnConflictAssignBack
+	if branch, _, rec, res := e.autoRebase(mr, "feature", base); res != nil || rec != nil || branch != "feature" {
+		t.Fatalf("assign_back = %q, %+v, %v", branch, rec, res)
+	}
+
+	e.config.OnConflict = config.OnConflictAutoRebase
+	branch, cleanup, rec, res := e.autoRebase(mr, "feature", base)
+	if res != nil {
+		t.Fatalf("autoRebase: %s", res.Error)
+	}
+	if branch != rebaseBranchPrefix+"gt-mr1" {
+		t.Errorf("branch = %q", branch)
+	}
+	if rec == nil || rec.Outcome != RebaseOutcomeRebased || rec.Behind != 1 || rec.Onto != run("rev-parse", base) {
+		t.Errorf("record = %+v", rec)
+	}
+	if cur := run("rev-parse", "--abbrev-ref", "HEAD"); cur != base {
+		t.Errorf("checked out %q after rebase, want %s", cur, base)
+	}
+	if got := run("merge-base", base, branch); got != run("rev-parse", base) {
+		t.Error("rebased branch does not contain the target")
+	}
+	if got := run("diff", "--name-only", base, branch); got != "a.go\nb.go" {
+		t.Errorf("rebased changes %q, want a.go and b.go", got)
+	}
+	cleanup()
+	if got := run("branch", "--list", branch); got != "" {
+		t.Errorf("scratch branch not deleted: %q", got)
+	}
+
+	// A rebase that conflicts fails as a conflict and leaves no mess.
+	commit("a.go", "package a // target\n", "fix: change a")
+	_, _, rec, res = e.autoRebase(mr, "feature", base)
+	if res == nil || !res.Conflict || rec == nil || rec.Outcome != RebaseOutcomeConflict || res.Rebase != rec {
+		t.Fatalf("conflicting rebase = %+v, %+v", rec, res)
+	}
+	if cur := run("rev-parse", "--abbrev-ref", "HEAD"); cur != base {
+		t.Errorf("checked out %q after failed rebase, want %s", cur, base)
+	}
+	if got := run("status", "--porcelain"); got != "" {
+		t.Errorf("worktree not clean: %q", got)
+	}
+}
@@ -0,0 +1,251 @@
+package refinery
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Merge order simulation ('gt mq merge-order'): the open queue is played
+// forward the way the refinery takes it, without claiming or merging
+// anything. MRs claimed by a worker merge first; then each round takes the
+// highest-scoring MR whose blockers (a stacked MR is blocked on its parent)
+// and merge order predecessors have merged, as claimNextMR does. MRs that
+// no merge in the queue can free (held, blocked by a task, or caught in a
+// cycle) are reported as stuck. An MR that changes files an MR predicted
+// to merge before it into the same target also changes is flagged as
+// colliding with it: it will have to be rebased, and may conflict.
+
+// PredictedMerge is one MR in the predicted merge order.
+type PredictedMerge struct {
+	ID         string      `json:"id"`
+	Branch     string      `json:"branch"`
+	Target     string      `json:"target"`
+	Priority   int         `json:"priority"`
+	Score      float64     `json:"score"`
+	InFlight   bool        `json:"in_flight,omitempty"` // Claimed by a refinery worker
+	After      []string    `json:"after,omitempty"`     // Queued MRs it waits for
+	Collisions []Collision `json:"collisions,omitempty"`
+}
+
+// Collision is an MR predicted to merge first that changes the same files.
+type Collision struct {
+	With  string   `json:"with"`
+	Files []string `json:"files"`
+}
+
+// StuckMR is an open MR the queue can't get to merge on its own.
+type StuckMR struct {
+	ID     string `json:"id"`
+	Branch string `json:"branch"`
+	Reason string `json:"reason"`
+}
+
+// MergeSimulation is the predicted outcome of processing the open queue.
+type MergeSimulation struct {
+	Order        []PredictedMerge `json:"order"`
+	Stuck        []StuckMR        `json:"stuck,omitempty"`
+	FilesUnknown []string         `json:"files_unknown,omitempty"` // MRs whose changes couldn't be read
+}
+
+// simulateOptions supplies what the simulation looks up outside the queue.
+type simulateOptions struct {
+	now        time.Time
+	staleClaim time.Duration
+	// isOpen reports whether a bead that is not a queued MR is open.
+	isOpen func(id string) bool
+	// changedFiles returns the files mr changes relative to its target.
+	changedFiles func(mr *MRInfo) ([]string, error)
+}
+
+// SimulateMergeOrder predicts the order the open queue will merge in and
+// which MRs will collide, from the refinery clone's view of the branches.
+func (e *Engineer) SimulateMergeOrder() (*MergeSimulation, error) {
+	issues, err := e.beads.List(beads.ListOptions{
+		Status: "open",
+		Label:  "gt:merge-request",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	return simulateMergeOrder(issues, simulateOptions{
+		now:        time.Now(),
+		staleClaim: e.config.StaleClaimTimeout,
+		isOpen: func(id string) bool {
+			open, err := e.IsBeadOpen(id)
+			return err == nil && open
+		},
+		changedFiles: func(mr *MRInfo) ([]string, error) {
+			files, err := e.git.ChangedFiles("origin/"+mr.Target, "origin/"+mr.Branch)
+			if err != nil {
+				files, err = e.git.ChangedFiles("origin/"+mr.Target, mr.Branch)
+			}
+			return files, err
+		},
+	}), nil
+}
+
+func simulateMergeOrder(issues []*beads.Issue, opts simulateOptions) *MergeSimulation {
+	mrs := make(map[string]*MRInfo)
+	var queued []*beads.Issue
+	for _, issue := range issues {
+		if issue.Status != "open" || beads.HasLabel(issue, "gt:owned-direct") {
+			continue
+		}
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			continue
+		}
+		mrs[issue.ID] = issueToMRInfo(issue, fields)
+		queued = append(queued, issue)
+	}
+
+	var ids []string // queue order, for stable results
+	waits := make(map[string][]string)
+	stuck := make(map[string]string)
+	var inFlight []string
+	for _, issue := range queued {
+		id, mr := issue.ID, mrs[issue.ID]
+		ids = append(ids, id)
+		for _, blocker := range issue.BlockedBy {
+			if _, ok := mrs[blocker]; ok {
+				waits[id] = appendUnique(waits[id], blocker)
+			} else if stuck[id] == "" && opts.isOpen(blocker) {
+				stuck[id] = fmt.Sprintf("blocked by %s, which is not a queued MR", blocker)
+			}
+		}
+		for _, pred := range mr.MergeAfter {
+			if _, ok := mrs[pred]; ok {
+				waits[id] = appendUnique(waits[id], pred)
+			}
+		}
+		if beads.HasLabel(issue, HeldLabel) {
+			stuck[id] = "held (gt mq hold)"
+		}
+		if issue.Assignee != "" && stuck[id] == "" {
+			if stale, _ := isClaimStale(issue.UpdatedAt, opts.staleClaim); !stale {
+				inFlight = append(inFlight, id)
+			}
+		}
+	}
+
+	sim := &MergeSimulation{Order: []PredictedMerge{}}
+	merged := make(map[string]bool)
+	take := func(id string, claimed bool) {
+		mr := mrs[id]
+		merged[id] = true
+		sim.Order = append(sim.Order, PredictedMerge{
+			ID:       id,
+			Branch:   mr.Branch,
+			Target:   mr.Target,
+			Priority: mr.Priority,
+			Score:    mr.ScoreAt(opts.now),
+			InFlight: claimed,
+			After:    waits[id],
+		})
+	}
+	byScore := func(a, b string) bool {
+		if sa, sb := mrs[a].ScoreAt(opts.now), mrs[b].ScoreAt(opts.now); sa != sb {
+			return sa > sb
+		}
+		return a < b
+	}
+
+	// Claimed MRs are being merged already.
+	sort.SliceStable(inFlight, func(i, j int) bool { return byScore(inFlight[i], inFlight[j]) })
+	for _, id := range inFlight {
+		take(id, true)
+	}
+	for {
+		next := ""
+		for _, id := range ids {
+			if merged[id] || stuck[id] != "" || !allMerged(waits[id], merged) {
+				continue
+			}
+			if next == "" || byScore(id, next) {
+				next = id
+			}
+		}
+		if next == "" {
+			break
+		}
+		take(next, false)
+	}
+
+	for _, id := range ids {
+		if merged[id] {
+			continue
+		}
+		reason := stuck[id]
+		if reason == "" {
+			if cycle := cycleFrom(waits, id); cycle != nil {
+				reason = "merge order cycle " + formatCycle(cycle)
+			} else {
+				reason = "waits on " + firstUnmerged(waits[id], merged) + ", which is stuck"
+			}
+		}
+		sim.Stuck = append(sim.Stuck, StuckMR{ID: id, Branch: mrs[id].Branch, Reason: reason})
+	}
+
+	predictCollisions(sim, mrs, opts.changedFiles)
+	return sim
+}
+
+// predictCollisions records, for each MR in the predicted order, the MRs
+// merging before it into the same target that change the same files.
+func predictCollisions(sim *MergeSimulation, mrs map[string]*MRInfo, changedFiles func(*MRInfo) ([]string, error)) {
+	files := make(map[string][]string, len(sim.Order))
+	for _, p := range sim.Order {
+		mr := mrs[p.ID]
+		if mr.Branch == "" {
+			continue // dependency updates have no branch
+		}
+		changed, err := changedFiles(mr)
+		if err != nil {
+			sim.FilesUnknown = append(sim.FilesUnknown, p.ID)
+			continue
+		}
+		files[p.ID] = changed
+	}
+	for i := range sim.Order {
+		p := &sim.Order[i]
+		for _, earlier := range sim.Order[:i] {
+			if earlier.Target != p.Target {
+				continue
+			}
+			var shared []string
+			for _, f := range files[p.ID] {
+				if slices.Contains(files[earlier.ID], f) {
+					shared = append(shared, f)
+				}
+			}
+			if len(shared) > 0 {
+				sort.Strings(shared)
+				p.Collisions = append(p.Collisions, Collision{With: earlier.ID, Files: shared})
+			}
+		}
+	}
+}
+
+func appendUnique(list []string, id string) []string {
+	if slices.Contains(list, id) {
+		return list
+	}
+	return append(list, id)
+}
+
+func allMerged(ids []string, merged map[string]bool) bool {
+	return firstUnmerged(ids, merged) == ""
+}
+
+func firstUnmerged(ids []string, merged map[string]bool) string {
+	for _, id := range ids {
+		if !merged[id] {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package refinery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func simMR(id string, priority int, extra string) *beads.Issue {
+	return &beads.Issue{
+		ID:          id,
+		Status:      "open",
+		Priority:    priority,
+		Labels:      []string{"gt:merge-request"},
+		CreatedAt:   "2026-03-01T10:00:00Z",
+		Description: "branch: polecat/" + id + "\ntarget: main\n" + extra,
+	}
+}
+
+func simOrder(sim *MergeSimulation) []string {
+	var ids []string
+	for _, p := range sim.Order {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestSimulateMergeOrder(t *testing.T) {
+	task := "gt-task"
+	issues := []*beads.Issue{
+		simMR("gt-low", 3, ""),
+		simMR("gt-high", 1, ""),
+		simMR("gt-after", 0, "merge_after: gt-low"),
+		simMR("gt-child", 1, ""),
+		simMR("gt-held", 0, ""),
+		simMR("gt-blocked", 0, ""),
+		simMR("gt-claimed", 4, ""),
+	}
+	issues[3].BlockedBy = []string{"gt-high"} // stacked on gt-high
+	issues[4].Labels = append(issues[4].Labels, HeldLabel)
+	issues[5].BlockedBy = []string{task}
+	issues[6].Assignee = "refinery-1"
+	issues[6].UpdatedAt = time.Now().Format(time.RFC3339)
+
+	files := map[string][]string{
+		"gt-high":  {"a.go", "b.go"},
+		"gt-child": {"b.go", "c.go"},
+		"gt-low":   {"d.go"},
+	}
+	sim := simulateMergeOrder(issues, simulateOptions{
+		now:        time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		staleClaim: time.Hour,
+		isOpen:     func(id string) bool { return id == task },
+		changedFiles: func(mr *MRInfo) ([]string, error) {
+			if mr.ID == "gt-after" {
+				return nil, fmt.Errorf("no such branch")
+			}
+			return files[mr.ID], nil
+		},
+	})
+
+	want := []string{"gt-claimed", "gt-high", "gt-child", "gt-low", "gt-after"}
+	if got := simOrder(sim); !slices.Equal(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	if !sim.Order[0].InFlight {
+		t.Errorf("gt-claimed not in flight")
+	}
+	if got := sim.Order[4].After; !slices.Equal(got, []string{"gt-low"}) {
+		t.Errorf("gt-after waits for %v", got)
+	}
+
+	child := sim.Order[2]
+	if len(child.Collisions) != 1 || child.Collisions[0].With != "gt-high" || !slices.Equal(child.Collisions[0].Files, []string{"b.go"}) {
+		t.Errorf("gt-child collisions = %+v", child.Collisions)
+	}
+	if len(sim.Order[3].Collisions) != 0 {
+		t.Errorf("gt-low collisions = %+v", sim.Order[3].Collisions)
+	}
+	if !slices.Equal(sim.FilesUnknown, []string{"gt-after"}) {
+		t.Errorf("files unknown = %v", sim.FilesUnknown)
+	}
+
+	stuck := map[string]string{}
+	for _, s := range sim.Stuck {
+		stuck[s.ID] = s.Reason
+	}
+	if !strings.Contains(stuck["gt-held"], "held") {
+		t.Errorf("gt-held: %q", stuck["gt-held"])
+	}
+	if !strings.Contains(stuck["gt-blocked"], "blocked by gt-task") {
+		t.Errorf("gt-blocked: %q", stuck["gt-blocked"])
+	}
+}
+
+func TestSimulateMergeOrderStuckChains(t *testing.T) {
+	issues := []*beads.Issue{
+		simMR("gt-a", 2, "merge_after: gt-b"),
+		simMR("gt-b", 2, "merge_after: gt-a"),
+		simMR("gt-c", 2, "merge_after: gt-a"),
+		simMR("gt-d", 2, ""),
+	}
+	sim := simulateMergeOrder(issues, simulateOptions{
+		now:          time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		isOpen:       func(string) bool { return false },
+		changedFiles: func(*MRInfo) ([]string, error) { return nil, nil },
+	})
+
+	if got := simOrder(sim); !slices.Equal(got, []string{"gt-d"}) {
+		t.Errorf("order = %v", got)
+	}
+	want := map[string]string{
+		"gt-a": "merge order cycle gt-a → gt-b → gt-a",
+		"gt-b": "merge order cycle gt-b → gt-a → gt-b",
+		"gt-c": "waits on gt-a, which is stuck",
+	}
+	if len(sim.Stuck) != len(want) {
+		t.Fatalf("stuck = %+v", sim.Stuck)
+	}
+	for _, s := range sim.Stuck {
+		if s.Reason != want[s.ID] {
+			t.Errorf("%s: reason %q, want %q", s.ID, s.Reason, want[s.ID])
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package refinery
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+// Stacked MRs: an MR submitted with a parent (gt mq submit --parent) is
+// built on the parent's branch. The submit blocks it on the parent MR bead,
+// so the refinery only sees it once the parent has closed. When the parent
+// lands, its branch head is recorded on each child as stack_base; the child
+// is then rebased from stack_base onto the target (dropping the parent's
+// commits, which landed as one squash commit) before its own merge. If the
+// parent fails, its children are told the chain is stuck; if the parent is
+// closed without landing, the children fail rather than merge on their own.
+
+// stackBranchPrefix names the scratch branch a stacked MR is rebased on, so
+// the worker's branch, which may be checked out in its worktree, is untouched.
+const stackBranchPrefix = "gt-stack/"
+
+// parentState describes where a stacked MR's parent is.
+type parentState int
+
+const (
+	parentPending   parentState = iota // still open: wait
+	parentLanded                       // merged: rebase and merge the child
+	parentAbandoned                    // closed without merging: the child fails
+)
+
+// stackParentState classifies a parent MR bead.
+func stackParentState(parent *beads.Issue) parentState {
+	if parent.Status != "closed" {
+		return parentPending
+	}
+	if fields := beads.ParseMRFields(parent); fields != nil && fields.CloseReason == "merged" {
+		return parentLanded
+	}
+	return parentAbandoned
+}
+
+// checkStackParent returns ok=false with the result to report when mr is
+// stacked on a parent that has not landed.
+func (e *Engineer) checkStackParent(mr *MRInfo) (ProcessResult, bool) {
+	if mr.Parent == "" || e.beads == nil {
+		return ProcessResult{}, true
+	}
+	parent, err := e.beads.Show(mr.Parent)
+	if err != nil {
+		return ProcessResult{StackBlocked: true, Error: fmt.Sprintf("looking up parent MR %s: %v", mr.Parent, err)}, false
+	}
+	switch stackParentState(parent) {
+	case parentPending:
+		return ProcessResult{StackBlocked: true, Error: fmt.Sprintf("stacked on %s, which has not landed", mr.Parent)}, false
+	case parentAbandoned:
+		return ProcessResult{Error: fmt.Sprintf("stacked on %s, which was closed without merging", mr.Parent)}, false
+	}
+	return ProcessResult{}, true
+}
+
+// rebaseStacked rebases a landed stack's child onto target on a scratch
+// branch and returns the branch to merge instead of mr.Branch, with a
+// cleanup to run once the merge is done. The target is checked out again
+// before it returns. An MR that isn't stacked merges mr.Branch as is.
+func (e *Engineer) rebaseStacked(mr *MRInfo, target string) (string, func(), *ProcessResult) {
+	if mr.StackBase == "" {
+		return mr.Branch, func() {}, nil
+	}
+	scratch := stackBranchPrefix + mr.ID
+	cleanup := func() {
+		if err := e.git.DeleteBranch(scratch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete %s: %v\n", scratch, err)
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rebasing stacked %s onto %s (parent %s landed)...\n", mr.Branch, target, mr.Parent)
+	_ = e.git.DeleteBranch(scratch, true) // left over from an interrupted run
+	if err := e.git.CreateBranchFrom(scratch, mr.Branch); err != nil {
+		return "", nil, &ProcessResult{Error: fmt.Sprintf("creating %s: %v", scratch, err)}
+	}
+	if err := e.git.RebaseOnto(target, mr.StackBase, scratch); err != nil {
+		_ = e.git.AbortRebase()
+		_ = e.git.Checkout(target)
+		cleanup()
+		return "", nil, &ProcessResult{
+			Conflict: true,
+			Error:    fmt.Sprintf("rebasing onto %s after parent %s landed: %v", target, mr.Parent, err),
+		}
+	}
+	if err := e.git.Checkout(target); err != nil {
+		cleanup()
+		return "", nil, &ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", target, err)}
+	}
+	return scratch, cleanup, nil
+}
+
+// stackedChildren returns the open MRs stacked directly on parentID.
+func (e *Engineer) stackedChildren(parentID string) ([]*MRInfo, error) {
+	if e.beads == nil {
+		return nil, nil
+	}
+	issues, err := e.beads.List(beads.ListOptions{
+		Status: "open",
+		Label:  "gt:merge-request",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	var children []*MRInfo
+	for _, issue := range issues {
+		if fields := beads.ParseMRFields(issue); fields != nil && fields.Parent == parentID {
+			children = append(children, issueToMRInfo(issue, fields))
+		}
+	}
+	return children, nil
+}
+
+// restackChildren records on each child of a landed parent where the parent
+// branch ended, so the child can be rebased onto the target. A child that
+// targeted the parent's branch is retargeted to the parent's target.
+func (e *Engineer) restackChildren(parent *MRInfo, parentHead string) {
+	children, err := e.stackedChildren(parent.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to find MRs stacked on %s: %v\n", parent.ID, err)
+		return
+	}
+	for _, child := range children {
+		issue, err := e.beads.Show(child.ID)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch stacked MR %s: %v\n", child.ID, err)
+			continue
+		}
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			continue
+		}
+		fields.StackBase = parentHead
+		if fields.Target == parent.Branch {
+			fields.Target = parent.Target
+		}
+		desc := beads.SetMRFields(issue, fields)
+		if err := e.beads.Update(child.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to restack MR %s: %v\n", child.ID, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stacked MR %s will be rebased onto %s\n", child.ID, fields.Target)
+	}
+}
+
+// failStackedChildren tells the MRs stacked on parent, and theirs in turn,
+// that the chain is stuck. They stay blocked on their parent, so nothing
+// merges out of order; the comment and event say why.
+func (e *Engineer) failStackedChildren(parent *MRInfo, reason string) {
+	e.failStackedChildrenSeen(parent, reason, map[string]bool{parent.ID: true})
+}
+
+func (e *Engineer) failStackedChildrenSeen(parent *MRInfo, reason string, seen map[string]bool) {
+	children, err := e.stackedChildren(parent.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to find MRs stacked on %s: %v\n", parent.ID, err)
+		return
+	}
+	for _, child := range children {
+		if seen[child.ID] {
+			continue // a cycle would be a bad submit; don't loop on it
+		}
+		seen[child.ID] = true
+		msg := fmt.Sprintf("stacked on %s, which failed to merge: %s", parent.ID, reason)
+		if err := e.beads.AddComment(child.ID, msg); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to comment on stacked MR %s: %v\n", child.ID, err)
+		}
+		e.notifyQueueEvent(mq.EventFailed, child, ProcessResult{Error: msg})
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Stacked MR %s stuck behind %s\n", child.ID, parent.ID)
+		e.failStackedChildrenSeen(child, reason, seen)
+	}
+}
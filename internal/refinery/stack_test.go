@@ -0,0 +1,94 @@
+package refinery
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestStackParentState(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent *beads.Issue
+		want   parentState
+	}{
+		{"open", &beads.Issue{Status: "open", Description: "branch: a"}, parentPending},
+		{"in progress", &beads.Issue{Status: "in_progress"}, parentPending},
+		{"merged", &beads.Issue{Status: "closed", Description: "branch: a\nclose_reason: merged"}, parentLanded},
+		{"rejected", &beads.Issue{Status: "closed", Description: "branch: a"}, parentAbandoned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stackParentState(tt.parent); got != tt.want {
+				t.Errorf("stackParentState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebaseStacked(t *testing.T) {
+	dir, base := summaryTestRepo(t) // feature = parent branch, two commits on base
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	// The child is built on the parent's branch.
+	run("checkout", "-q", "-b", "child")
+	if err := os.WriteFile(filepath.Join(dir, "c.go"), []byte("package c\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "feat: add c")
+	parentHead := run("rev-parse", "feature")
+
+	// The parent lands as one squash commit.
+	run("checkout", "-q", base)
+	run("merge", "-q", "--squash", "feature")
+	run("commit", "-q", "-m", "feat: parent")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.git = git.NewGit(dir)
+
+	mr := &MRInfo{ID: "gt-mr2", Branch: "child", Parent: "gt-mr1", StackBase: parentHead}
+	branch, cleanup, res := e.rebaseStacked(mr, base)
+	if res != nil {
+		t.Fatalf("rebaseStacked: %s", res.Error)
+	}
+	if branch != stackBranchPrefix+"gt-mr2" {
+		t.Errorf("branch = %q", branch)
+	}
+	if cur := run("rev-parse", "--abbrev-ref", "HEAD"); cur != base {
+		t.Errorf("checked out %q after rebase, want %s", cur, base)
+	}
+	if got := run("diff", "--name-only", base, branch); got != "c.go" {
+		t.Errorf("rebased child changes %q, want only c.go", got)
+	}
+	if got := run("rev-parse", "child"); got == run("rev-parse", branch) {
+		t.Error("worker's branch should be left untouched")
+	}
+
+	cleanup()
+	if got := run("branch", "--list", branch); got != "" {
+		t.Errorf("scratch branch not deleted: %q", got)
+	}
+
+	// An MR that isn't stacked merges its own branch.
+	if branch, _, res := e.rebaseStacked(&MRInfo{ID: "gt-mr3", Branch: "feature"}, base); res != nil || branch != "feature" {
+		t.Errorf("unstacked = %q, %v", branch, res)
+	}
+}
@@ -0,0 +1,183 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SummaryConfig configures pre-merge change summaries.
+//
+// Before merging, the refinery writes a short human-readable summary of what
+// the branch changes. It is posted as a comment on the MR bead before the
+// merge and appended to the squash commit message, so the record of what agents merged does not
+// depend on the quality of polecat commit messages.
+type SummaryConfig struct {
+	// Cmd is an optional hook that writes the summary to stdout. It runs in
+	// the refinery worktree with the built-in summary on stdin and
+	// GT_MR_BRANCH, GT_MR_TARGET, GT_MR_SOURCE_ISSUE and GT_RIG set.
+	// Empty output or failure falls back to the built-in summary.
+	Cmd string
+
+	// Timeout bounds the hook. Zero uses defaultSummaryTimeout.
+	Timeout time.Duration
+}
+
+// summaryConfigRaw is the JSON-friendly representation of SummaryConfig.
+type summaryConfigRaw struct {
+	Enabled *bool  `json:"enabled"`
+	Cmd     string `json:"cmd"`
+	Timeout string `json:"timeout"`
+}
+
+const (
+	defaultSummaryTimeout = 60 * time.Second
+
+	// Caps keep summaries readable in commit logs.
+	maxSummaryCommits = 10
+	maxSummaryFiles   = 8
+	maxSummaryLen     = 4000
+)
+
+// summaryInput is the raw material for a change summary.
+type summaryInput struct {
+	SourceIssue string
+	IssueTitle  string
+	Commits     []string // Subjects, oldest first
+	ShortStat   string
+	Files       []string
+}
+
+// buildSummary renders the built-in change summary.
+func buildSummary(in summaryInput) string {
+	var sb strings.Builder
+	if in.SourceIssue != "" {
+		if in.IssueTitle != "" {
+			fmt.Fprintf(&sb, "%s: %s\n", in.SourceIssue, in.IssueTitle)
+		} else {
+			fmt.Fprintf(&sb, "%s\n", in.SourceIssue)
+		}
+	}
+
+	if len(in.Commits) > 0 {
+		fmt.Fprintf(&sb, "Commits (%d):\n", len(in.Commits))
+		for i, subject := range in.Commits {
+			if i == maxSummaryCommits {
+				fmt.Fprintf(&sb, "  ... %d more\n", len(in.Commits)-maxSummaryCommits)
+				break
+			}
+			fmt.Fprintf(&sb, "  - %s\n", subject)
+		}
+	}
+
+	if in.ShortStat != "" {
+		fmt.Fprintf(&sb, "Changes: %s\n", in.ShortStat)
+	}
+	if len(in.Files) > 0 {
+		files := in.Files
+		more := ""
+		if len(files) > maxSummaryFiles {
+			more = fmt.Sprintf(" (+%d more)", len(files)-maxSummaryFiles)
+			files = files[:maxSummaryFiles]
+		}
+		fmt.Fprintf(&sb, "Files: %s%s\n", strings.Join(files, ", "), more)
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// generateSummary produces the pre-merge summary for branch against target.
+// Failures degrade to whatever information is available; summaries never
+// block a merge.
+func (e *Engineer) generateSummary(ctx context.Context, branch, target, sourceIssue string) string {
+	in := summaryInput{SourceIssue: sourceIssue}
+	if sourceIssue != "" {
+		if issue, err := e.beads.Show(sourceIssue); err == nil {
+			in.IssueTitle = issue.Title
+		}
+	}
+	if subjects, err := e.git.CommitSubjects(target, branch); err == nil {
+		in.Commits = subjects
+	}
+	if stat, err := e.git.DiffShortStat(target, branch); err == nil {
+		in.ShortStat = stat
+	}
+	if files, err := e.git.ChangedFiles(target, branch); err == nil {
+		in.Files = files
+	}
+	summary := buildSummary(in)
+
+	if cfg := e.config.Summary; cfg != nil && strings.TrimSpace(cfg.Cmd) != "" {
+		out, err := e.runSummaryHook(ctx, cfg, summary, branch, target, sourceIssue)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: summary hook failed, using built-in summary: %v\n", err)
+		} else if out != "" {
+			summary = out
+		}
+	}
+
+	return truncateSummary(summary)
+}
+
+// truncateSummary caps summary at maxSummaryLen bytes without splitting a
+// UTF-8 rune.
+func truncateSummary(summary string) string {
+	if len(summary) <= maxSummaryLen {
+		return summary
+	}
+	n := maxSummaryLen
+	for n > 0 && !utf8.RuneStart(summary[n]) {
+		n--
+	}
+	return summary[:n] + "\n..."
+}
+
+// runSummaryHook runs the configured summary command and returns its trimmed stdout.
+func (e *Engineer) runSummaryHook(ctx context.Context, cfg *SummaryConfig, builtin, branch, target, sourceIssue string) (string, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultSummaryTimeout
+	}
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, "sh", "-c", cfg.Cmd) //nolint:gosec // G204: summary hook is from trusted rig config
+	cmd.Dir = e.workDir
+	cmd.Env = append(os.Environ(),
+		"GT_MR_BRANCH="+branch,
+		"GT_MR_TARGET="+target,
+		"GT_MR_SOURCE_ISSUE="+sourceIssue,
+		"GT_RIG="+e.rig.Name,
+	)
+	cmd.Stdin = strings.NewReader(builtin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if hookCtx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timed out after %v", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > 500 {
+				msg = msg[:500] + "..."
+			}
+			return "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// appendSummary adds the summary to a squash commit message as a trailing section.
+func appendSummary(message, summary string) string {
+	if summary == "" {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\nMerge summary:\n" + summary + "\n"
+}
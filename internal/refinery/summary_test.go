@@ -0,0 +1,186 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestBuildSummary(t *testing.T) {
+	commits := make([]string, 12)
+	for i := range commits {
+		commits[i] = "fix: step " + string(rune('a'+i))
+	}
+	got := buildSummary(summaryInput{
+		SourceIssue: "gt-abc",
+		IssueTitle:  "Fix flaky polling",
+		Commits:     commits,
+		ShortStat:   "2 files changed, 10 insertions(+)",
+		Files:       []string{"a.go", "b.go"},
+	})
+
+	for _, want := range []string{
+		"gt-abc: Fix flaky polling",
+		"Commits (12):",
+		"  - fix: step a",
+		"  ... 2 more",
+		"Changes: 2 files changed, 10 insertions(+)",
+		"Files: a.go, b.go",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "fix: step k") {
+		t.Errorf("summary should cap commits at %d:\n%s", maxSummaryCommits, got)
+	}
+
+	if got := buildSummary(summaryInput{}); got != "" {
+		t.Errorf("empty input should give empty summary, got %q", got)
+	}
+}
+
+func TestAppendSummary(t *testing.T) {
+	if got := appendSummary("feat: thing\n", ""); got != "feat: thing\n" {
+		t.Errorf("no summary should leave message unchanged, got %q", got)
+	}
+	got := appendSummary("feat: thing\n\nbody\n", "Commits (1):\n  - feat: thing")
+	want := "feat: thing\n\nbody\n\nMerge summary:\nCommits (1):\n  - feat: thing\n"
+	if got != want {
+		t.Errorf("appendSummary = %q, want %q", got, want)
+	}
+}
+
+func TestEngineer_LoadConfig_Summary(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary interface{}
+		wantNil bool
+		wantCmd string
+		wantErr bool
+	}{
+		{"empty object enables built-in", map[string]interface{}{}, false, "", false},
+		{"hook with timeout", map[string]interface{}{"cmd": "summarize", "timeout": "30s"}, false, "summarize", false},
+		{"disabled", map[string]interface{}{"enabled": false, "cmd": "summarize"}, true, "", false},
+		{"bad timeout", map[string]interface{}{"timeout": "soon"}, true, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			data, _ := json.Marshal(map[string]interface{}{
+				"merge_queue": map[string]interface{}{"summary": tt.summary},
+			})
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+			err := e.LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (e.config.Summary == nil) != tt.wantNil {
+				t.Fatalf("Summary = %+v, wantNil %v", e.config.Summary, tt.wantNil)
+			}
+			if e.config.Summary != nil && e.config.Summary.Cmd != tt.wantCmd {
+				t.Errorf("Cmd = %q, want %q", e.config.Summary.Cmd, tt.wantCmd)
+			}
+		})
+	}
+}
+
+// summaryTestRepo creates a repo with a "feature" branch two commits ahead of
+// the initial branch, which is returned.
+func summaryTestRepo(t *testing.T) (dir, base string) {
+	t.Helper()
+	dir = t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test User")
+	write("README.md", "# test\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	base, err := git.NewGit(dir).CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	run("checkout", "-q", "-b", "feature")
+	write("a.go", "package a\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "feat: add a")
+	write("b.go", "package b\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "feat: add b")
+	return dir, base
+}
+
+func TestTruncateSummary(t *testing.T) {
+	if got := truncateSummary("short"); got != "short" {
+		t.Errorf("short summary changed: %q", got)
+	}
+	// A three-byte rune straddles the limit.
+	long := strings.Repeat("a", maxSummaryLen-1) + "€" + "tail"
+	got := truncateSummary(long)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated summary is not valid UTF-8: %q", got[len(got)-8:])
+	}
+	if want := strings.Repeat("a", maxSummaryLen-1) + "\n..."; got != want {
+		t.Errorf("truncateSummary cut at %d bytes, want %d", len(got)-4, maxSummaryLen-1)
+	}
+}
+
+func TestGenerateSummary(t *testing.T) {
+	dir, base := summaryTestRepo(t)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.SetOutput(io.Discard)
+	e.git = git.NewGit(dir)
+	e.workDir = dir
+	e.config.Summary = &SummaryConfig{}
+
+	got := e.generateSummary(context.Background(), "feature", base, "")
+	for _, want := range []string{"Commits (2):", "  - feat: add a", "Files: a.go, b.go", "2 files changed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("built-in summary missing %q:\n%s", want, got)
+		}
+	}
+
+	// The hook sees the built-in summary on stdin and the MR in its env.
+	e.config.Summary = &SummaryConfig{Cmd: `echo "$GT_MR_BRANCH -> $GT_MR_TARGET"; wc -l | tr -d ' '`}
+	got = e.generateSummary(context.Background(), "feature", base, "")
+	if !strings.HasPrefix(got, "feature -> "+base) {
+		t.Errorf("hook summary = %q", got)
+	}
+
+	// A failing hook falls back to the built-in summary.
+	e.config.Summary = &SummaryConfig{Cmd: "exit 3", Timeout: time.Second}
+	got = e.generateSummary(context.Background(), "feature", base, "")
+	if !strings.Contains(got, "Commits (2):") {
+		t.Errorf("failed hook should fall back to built-in summary, got %q", got)
+	}
+}
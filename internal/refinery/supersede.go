@@ -0,0 +1,196 @@
+package refinery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+// Superseding: after a rewrite, a worker submits a new MR for the same work.
+// gt mq supersede closes the old MR as superseded, so the refinery doesn't
+// merge both, and links the two beads both ways: a "supersedes" dependency
+// and the supersedes/superseded_by MR fields. Reviewers always carry over
+// to the new MR; approvals only if the rig's merge policy allows it (see
+// mq.Policy.CarriesApprovals).
+
+// SupersedeDepType is the dependency type linking an MR to the MR it
+// replaced. It doesn't block.
+const SupersedeDepType = "supersedes"
+
+// SupersedeOptions controls SupersedeMR.
+type SupersedeOptions struct {
+	Reason         string // Recorded on the old MR's close reason
+	CarryApprovals bool   // Copy the old MR's approved-by labels to the new one
+}
+
+// SupersedeResult reports what SupersedeMR carried over.
+type SupersedeResult struct {
+	Reviewers        []string // Reviewers added to the new MR
+	Approvals        []string // Approvers carried to the new MR
+	DroppedApprovals []string // Approvers left behind, as policy requires
+}
+
+// supersedeBeads is the subset of Beads SupersedeMR uses.
+type supersedeBeads interface {
+	Show(id string) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+	Reopen(id, reason string) error
+	AddTypedDependency(issue, dependsOn, depType string) error
+}
+
+// SupersedeMR replaces MR oldID with newID in the queue. The old MR must not
+// be mid-merge: a refinery worker holding its target lock for it would land
+// it anyway. The steps are all-or-nothing: if one fails, the ones already
+// made are undone.
+func (m *Manager) SupersedeMR(oldID, newID string, opts SupersedeOptions) (*SupersedeResult, error) {
+	b := beads.New(m.rig.BeadsPath())
+	old, err := b.Show(oldID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", oldID, err)
+	}
+	if fields := beads.ParseMRFields(old); fields != nil && fields.Target != "" {
+		lock, err := mq.GetTargetLock(m.rig.Path, fields.Target)
+		if err != nil {
+			return nil, fmt.Errorf("checking target lock: %w", err)
+		}
+		if lock != nil && lock.MRID == oldID {
+			return nil, fmt.Errorf("%s is being merged by %s; wait for it to finish", oldID, lock.Holder)
+		}
+	}
+	return supersedeMR(b, oldID, newID, opts)
+}
+
+func supersedeMR(b supersedeBeads, oldID, newID string, opts SupersedeOptions) (*SupersedeResult, error) {
+	if oldID == newID {
+		return nil, fmt.Errorf("an MR can't supersede itself")
+	}
+	old, err := showOpenMR(b, oldID)
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := showOpenMR(b, newID)
+	if err != nil {
+		return nil, err
+	}
+	oldFields := beads.ParseMRFields(old)
+	if oldFields == nil {
+		oldFields = &beads.MRFields{}
+	}
+	newFields := beads.ParseMRFields(replacement)
+	if newFields == nil {
+		newFields = &beads.MRFields{}
+	}
+
+	result := &SupersedeResult{}
+	reviewers := splitReviewers(newFields.Reviewers)
+	for _, r := range splitReviewers(oldFields.Reviewers) {
+		if !slices.Contains(reviewers, r) {
+			reviewers = append(reviewers, r)
+			result.Reviewers = append(result.Reviewers, r)
+		}
+	}
+	var approvalLabels []string
+	for _, l := range old.Labels {
+		if !strings.HasPrefix(l, mq.ApprovalLabelPrefix) || beads.HasLabel(replacement, l) {
+			continue
+		}
+		approver := strings.TrimPrefix(l, mq.ApprovalLabelPrefix)
+		if opts.CarryApprovals {
+			approvalLabels = append(approvalLabels, l)
+			result.Approvals = append(result.Approvals, approver)
+		} else {
+			result.DroppedApprovals = append(result.DroppedApprovals, approver)
+		}
+	}
+
+	var undo []func() error
+	rollback := func(cause error) (*SupersedeResult, error) {
+		var errs []string
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%w (rollback failed, repair manually: %s)", cause, strings.Join(errs, "; "))
+		}
+		return nil, cause
+	}
+
+	// 1. Mark the old MR superseded and close it
+	oldFields.SupersededBy = newID
+	oldFields.CloseReason = string(CloseReasonSuperseded)
+	oldDesc := beads.SetMRFields(old, oldFields)
+	if err := b.Update(oldID, beads.UpdateOptions{Description: &oldDesc}); err != nil {
+		return nil, fmt.Errorf("updating %s: %w", oldID, err)
+	}
+	undo = append(undo, func() error {
+		if err := b.Update(oldID, beads.UpdateOptions{Description: &old.Description}); err != nil {
+			return fmt.Errorf("restoring %s: %v", oldID, err)
+		}
+		return nil
+	})
+	reason := fmt.Sprintf("%s: replaced by %s", CloseReasonSuperseded, newID)
+	if opts.Reason != "" {
+		reason += " (" + opts.Reason + ")"
+	}
+	if err := b.CloseWithReason(reason, oldID); err != nil {
+		return rollback(fmt.Errorf("closing %s: %w", oldID, err))
+	}
+	undo = append(undo, func() error {
+		if err := b.Reopen(oldID, "MR supersede rolled back"); err != nil {
+			return fmt.Errorf("reopening %s: %v", oldID, err)
+		}
+		return nil
+	})
+
+	// 2. Carry reviewers (and approvals) to the new MR
+	newFields.Supersedes = oldID
+	newFields.Reviewers = strings.Join(reviewers, ", ")
+	newDesc := beads.SetMRFields(replacement, newFields)
+	if err := b.Update(newID, beads.UpdateOptions{Description: &newDesc, AddLabels: approvalLabels}); err != nil {
+		return rollback(fmt.Errorf("updating %s: %w", newID, err))
+	}
+	undo = append(undo, func() error {
+		if err := b.Update(newID, beads.UpdateOptions{Description: &replacement.Description, RemoveLabels: approvalLabels}); err != nil {
+			return fmt.Errorf("restoring %s: %v", newID, err)
+		}
+		return nil
+	})
+
+	// 3. Link the beads
+	if err := b.AddTypedDependency(newID, oldID, SupersedeDepType); err != nil {
+		return rollback(fmt.Errorf("linking %s to %s: %w", newID, oldID, err))
+	}
+	return result, nil
+}
+
+// showOpenMR fetches an MR bead, failing if it isn't an open MR.
+func showOpenMR(b mrShower, id string) (*beads.Issue, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", id, err)
+	}
+	if !beads.HasLabel(issue, "gt:merge-request") {
+		return nil, fmt.Errorf("%s is not a merge request", id)
+	}
+	if issue.Status == "closed" {
+		return nil, fmt.Errorf("%w: %s is already closed", ErrClosedImmutable, id)
+	}
+	return issue, nil
+}
+
+// splitReviewers splits a comma-separated reviewers field.
+func splitReviewers(s string) []string {
+	var out []string
+	for _, r := range strings.Split(s, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
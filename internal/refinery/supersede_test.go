@@ -0,0 +1,152 @@
+package refinery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeSupersedeBeads keeps issues in memory and fails on a chosen dependency.
+type fakeSupersedeBeads struct {
+	issues  map[string]*beads.Issue
+	deps    []string
+	failDep bool
+}
+
+func (f *fakeSupersedeBeads) Show(id string) (*beads.Issue, error) {
+	issue, ok := f.issues[id]
+	if !ok {
+		return nil, beads.ErrNotFound
+	}
+	cp := *issue
+	cp.Labels = append([]string(nil), issue.Labels...)
+	return &cp, nil
+}
+
+func (f *fakeSupersedeBeads) Update(id string, opts beads.UpdateOptions) error {
+	issue := f.issues[id]
+	if opts.Description != nil {
+		issue.Description = *opts.Description
+	}
+	issue.Labels = append(issue.Labels, opts.AddLabels...)
+	for _, l := range opts.RemoveLabels {
+		for i, have := range issue.Labels {
+			if have == l {
+				issue.Labels = append(issue.Labels[:i], issue.Labels[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeSupersedeBeads) CloseWithReason(reason string, ids ...string) error {
+	for _, id := range ids {
+		f.issues[id].Status = "closed"
+	}
+	return nil
+}
+
+func (f *fakeSupersedeBeads) Reopen(id, reason string) error {
+	f.issues[id].Status = "open"
+	return nil
+}
+
+func (f *fakeSupersedeBeads) AddTypedDependency(issue, dependsOn, depType string) error {
+	if f.failDep {
+		return errors.New("dep add failed")
+	}
+	f.deps = append(f.deps, issue+" "+depType+" "+dependsOn)
+	return nil
+}
+
+func newFakeSupersedeBeads() *fakeSupersedeBeads {
+	return &fakeSupersedeBeads{issues: map[string]*beads.Issue{
+		"gt-mr1": {
+			ID:          "gt-mr1",
+			Status:      "open",
+			Labels:      []string{"gt:merge-request", "approved-by:mayor"},
+			Description: "branch: polecat/Toast/gt-abc\ntarget: main\nreviewers: gastown/crew/max",
+		},
+		"gt-mr2": {
+			ID:          "gt-mr2",
+			Status:      "open",
+			Labels:      []string{"gt:merge-request"},
+			Description: "branch: polecat/Toast/gt-abc-v2\ntarget: main\nreviewers: gastown/crew/joe",
+		},
+	}}
+}
+
+func TestSupersedeMR(t *testing.T) {
+	b := newFakeSupersedeBeads()
+	result, err := supersedeMR(b, "gt-mr1", "gt-mr2", SupersedeOptions{CarryApprovals: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, replacement := b.issues["gt-mr1"], b.issues["gt-mr2"]
+	if old.Status != "closed" {
+		t.Errorf("old MR status = %s, want closed", old.Status)
+	}
+	if f := beads.ParseMRFields(old); f.SupersededBy != "gt-mr2" || f.CloseReason != "superseded" {
+		t.Errorf("old MR fields = %+v, want superseded by gt-mr2", f)
+	}
+	f := beads.ParseMRFields(replacement)
+	if f.Supersedes != "gt-mr1" || f.Reviewers != "gastown/crew/joe, gastown/crew/max" {
+		t.Errorf("new MR fields = %+v, want supersedes gt-mr1 with both reviewers", f)
+	}
+	if !beads.HasLabel(replacement, "approved-by:mayor") || strings.Join(result.Approvals, ",") != "mayor" {
+		t.Errorf("approval not carried: labels %v, result %+v", replacement.Labels, result)
+	}
+	if strings.Join(b.deps, ",") != "gt-mr2 supersedes gt-mr1" {
+		t.Errorf("deps = %v", b.deps)
+	}
+}
+
+func TestSupersedeMR_PolicyKeepsApprovals(t *testing.T) {
+	b := newFakeSupersedeBeads()
+	result, err := supersedeMR(b, "gt-mr1", "gt-mr2", SupersedeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beads.HasLabel(b.issues["gt-mr2"], "approved-by:mayor") {
+		t.Error("approval carried over though policy doesn't allow it")
+	}
+	if strings.Join(result.DroppedApprovals, ",") != "mayor" {
+		t.Errorf("DroppedApprovals = %v, want [mayor]", result.DroppedApprovals)
+	}
+}
+
+func TestSupersedeMR_RollsBackOnFailure(t *testing.T) {
+	b := newFakeSupersedeBeads()
+	oldDesc, newDesc := b.issues["gt-mr1"].Description, b.issues["gt-mr2"].Description
+	b.failDep = true
+
+	if _, err := supersedeMR(b, "gt-mr1", "gt-mr2", SupersedeOptions{CarryApprovals: true}); err == nil {
+		t.Fatal("expected failure")
+	}
+	old, replacement := b.issues["gt-mr1"], b.issues["gt-mr2"]
+	if old.Status != "open" || old.Description != oldDesc {
+		t.Errorf("old MR not restored: %s, %q", old.Status, old.Description)
+	}
+	if replacement.Description != newDesc || beads.HasLabel(replacement, "approved-by:mayor") {
+		t.Errorf("new MR not restored: %q, %v", replacement.Description, replacement.Labels)
+	}
+}
+
+func TestSupersedeMR_Refuses(t *testing.T) {
+	b := newFakeSupersedeBeads()
+	b.issues["gt-task"] = &beads.Issue{ID: "gt-task", Status: "open"}
+	b.issues["gt-mr3"] = &beads.Issue{ID: "gt-mr3", Status: "closed", Labels: []string{"gt:merge-request"}}
+
+	for _, ids := range [][2]string{{"gt-mr1", "gt-mr1"}, {"gt-mr1", "gt-task"}, {"gt-mr3", "gt-mr2"}} {
+		if _, err := supersedeMR(b, ids[0], ids[1], SupersedeOptions{}); err == nil {
+			t.Errorf("supersedeMR(%s, %s) should fail", ids[0], ids[1])
+		}
+	}
+	if b.issues["gt-mr1"].Status != "open" {
+		t.Error("refused supersede changed the old MR")
+	}
+}
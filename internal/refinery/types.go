@@ -41,6 +41,9 @@ type MergeRequest struct {
 
 	// Error contains error details if the MR failed.
 	Error string `json:"error,omitempty"`
+
+	// Held is true while the MR is on hold (see HeldLabel).
+	Held bool `json:"held,omitempty"`
 }
 
 // MRStatus represents the status of a merge request.
@@ -138,6 +141,9 @@ const (
 
 	// CloseReasonSuperseded means the MR was replaced by another.
 	CloseReasonSuperseded CloseReason = "superseded"
+
+	// CloseReasonCancelled means the MR was swept from the queue (gt mq cancel).
+	CloseReasonCancelled CloseReason = "cancelled"
 )
 
 // QueueItem represents an item in the merge queue for display.
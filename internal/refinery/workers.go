@@ -0,0 +1,267 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mq"
+)
+
+// A refinery can run several workers, each merging one MR at a time in its
+// own worktree. Workers serialize merges into the same target with the
+// rig's target locks (see mq.TargetLock); merges into different targets go
+// ahead side by side, so one long gate run on main no longer holds up an
+// integration branch.
+
+// WorkerEnvVar names the refinery worker a process acts as.
+const WorkerEnvVar = "GT_REFINERY_WORKER"
+
+// DefaultWorker is the worker ID of a single-worker refinery. It works in
+// the refinery's own worktree; other workers get one under
+// refinery/workers/<id>.
+const DefaultWorker = "refinery-1"
+
+// WorkerID returns the refinery worker ID from the environment, or
+// DefaultWorker.
+func WorkerID() string {
+	if id := os.Getenv(WorkerEnvVar); id != "" {
+		return id
+	}
+	return DefaultWorker
+}
+
+// Worker returns the worker ID this engineer locks merge targets as.
+func (e *Engineer) Worker() string {
+	return e.worker
+}
+
+// lockTarget takes mr's target lock for this worker, waiting with backoff
+// while another worker merges into the same target. It returns a renew
+// function, which fails once another worker has taken the lock over, and
+// a release function. A nil result means the lock is held.
+func (e *Engineer) lockTarget(ctx context.Context, mr *MRInfo) (renew func() error, release func(), res *ProcessResult) {
+	acquire := func() error {
+		_, err := mq.AcquireTargetLock(e.rig.Path, mr.Target, e.worker, mr.ID, e.config.StaleClaimTimeout, time.Now())
+		return err
+	}
+	backoff := e.mergeSlotRetryBackoff
+	if backoff == 0 {
+		backoff = 500 * time.Millisecond
+	}
+	for attempt := 0; ; attempt++ {
+		err := acquire()
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, mq.ErrTargetLocked) {
+			return nil, nil, &ProcessResult{Error: fmt.Sprintf("failed to lock target %s: %v", mr.Target, err)}
+		}
+		if attempt >= e.mergeSlotMaxRetries {
+			// Another worker is merging into the target: not a failure of
+			// this MR, which stays in the queue for the next pass.
+			return nil, nil, &ProcessResult{SlotTimeout: true, Error: err.Error()}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %v, retrying in %v...\n", err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, nil, &ProcessResult{Error: ctx.Err().Error()}
+		}
+		backoff = min(backoff*2, 10*time.Second)
+	}
+	release = func() {
+		// A worker sharing the repository can't check out a branch that is
+		// checked out here, so let go of the target before another worker
+		// can lock it.
+		if e.detachAfterMerge {
+			if err := e.git.DetachHead(); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to detach from %s: %v\n", mr.Target, err)
+			}
+		}
+		if err := mq.ReleaseTargetLock(e.rig.Path, mr.Target, e.worker); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release target lock on %s: %v\n", mr.Target, err)
+		}
+	}
+	return acquire, release, nil
+}
+
+// ClaimNextMR claims the highest-scoring ready MR whose target no other
+// worker holds, taking the target's lock for this worker. It returns nil
+// when there is nothing this worker can take.
+func (e *Engineer) ClaimNextMR() (*MRInfo, error) {
+	return e.claimNextMR(nil)
+}
+
+// claimNextMR is ClaimNextMR, passing over MRs for which skip is true.
+func (e *Engineer) claimNextMR(skip func(*MRInfo) bool) (*MRInfo, error) {
+	ready, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].ScoreAt(now) > ready[j].ScoreAt(now)
+	})
+	for _, mr := range ready {
+		if skip != nil && skip(mr) {
+			continue
+		}
+		// The target lock also settles races between workers for the same
+		// MR: only the worker that gets the lock claims it.
+		if _, err := mq.AcquireTargetLock(e.rig.Path, mr.Target, e.worker, mr.ID, e.config.StaleClaimTimeout, now); err != nil {
+			if errors.Is(err, mq.ErrTargetLocked) {
+				continue
+			}
+			return nil, fmt.Errorf("locking target %s: %w", mr.Target, err)
+		}
+		if err := e.ClaimMR(mr.ID, e.worker); err != nil {
+			_ = mq.ReleaseTargetLock(e.rig.Path, mr.Target, e.worker)
+			return nil, fmt.Errorf("claiming %s: %w", mr.ID, err)
+		}
+		mr.Assignee = e.worker
+		return mr, nil
+	}
+	return nil, nil
+}
+
+// ClaimMRAndTarget claims MR mrID for this worker together with its
+// target's lock, so no other worker merges into the target meanwhile. It
+// fails with an error matching mq.ErrTargetLocked if another worker holds
+// the target.
+func (e *Engineer) ClaimMRAndTarget(mrID string) error {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		return err
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Target == "" {
+		return e.ClaimMR(mrID, e.worker)
+	}
+	if _, err := mq.AcquireTargetLock(e.rig.Path, fields.Target, e.worker, mrID, e.config.StaleClaimTimeout, time.Now()); err != nil {
+		return err
+	}
+	if err := e.ClaimMR(mrID, e.worker); err != nil {
+		_ = mq.ReleaseTargetLock(e.rig.Path, fields.Target, e.worker)
+		return err
+	}
+	return nil
+}
+
+// releaseTargetOf releases this worker's lock on the target of MR mrID, if
+// it holds one.
+func (e *Engineer) releaseTargetOf(mrID string) error {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		return err
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Target == "" {
+		return nil
+	}
+	return mq.ReleaseTargetLock(e.rig.Path, fields.Target, e.worker)
+}
+
+// RunWorkers processes the merge queue with n workers until ctx is done.
+// This engineer is the first worker; the others work in their own
+// worktrees under refinery/workers/, created on first use.
+func (e *Engineer) RunWorkers(ctx context.Context, n int) error {
+	if n < 1 {
+		n = 1
+	}
+	workers := []*Engineer{e}
+	for i := 1; len(workers) < n; i++ {
+		id := fmt.Sprintf("refinery-%d", i)
+		if id == e.worker {
+			continue
+		}
+		w, err := e.newWorker(id)
+		if err != nil {
+			return err
+		}
+		workers = append(workers, w)
+	}
+	if n > 1 {
+		e.detachAfterMerge = true
+	}
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *Engineer) {
+			defer wg.Done()
+			w.runWorker(ctx)
+		}(w)
+	}
+	wg.Wait()
+	for _, w := range workers {
+		w.WaitPostMerge()
+	}
+	return nil
+}
+
+// newWorker returns an engineer for worker id, working in its own worktree
+// of the refinery's repository.
+func (e *Engineer) newWorker(id string) (*Engineer, error) {
+	dir := filepath.Join(e.rig.Path, "refinery", "workers", id)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, fmt.Errorf("creating worker directory: %w", err)
+		}
+		if err := e.git.WorktreeAddDetached(dir, "HEAD"); err != nil {
+			return nil, fmt.Errorf("creating worktree for %s: %w", id, err)
+		}
+	}
+	w := NewEngineer(e.rig)
+	w.SetOutput(e.output)
+	if err := w.LoadConfig(); err != nil {
+		return nil, fmt.Errorf("loading config for %s: %w", id, err)
+	}
+	w.git = git.NewGit(dir)
+	w.workDir = dir
+	w.worker = id
+	w.detachAfterMerge = true
+	return w, nil
+}
+
+// runWorker claims and merges MRs until ctx is done. An MR that doesn't
+// merge is passed over until the next poll, so a worker doesn't spin on an
+// MR that is waiting for CI or approvals.
+func (e *Engineer) runWorker(ctx context.Context) {
+	retryAt := make(map[string]time.Time)
+	skip := func(mr *MRInfo) bool {
+		return time.Now().Before(retryAt[mr.ID])
+	}
+	for ctx.Err() == nil {
+		mr, err := e.claimNextMR(skip)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s: %v\n", e.worker, err)
+		}
+		if mr == nil {
+			select {
+			case <-time.After(e.config.PollInterval):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s took %s (%s)\n", e.worker, mr.ID, mr.Target)
+		result := e.ProcessMRInfo(ctx, mr)
+		if result.Success {
+			e.HandleMRInfoSuccess(mr, result)
+			continue
+		}
+		e.HandleMRInfoFailure(mr, result)
+		retryAt[mr.ID] = time.Now().Add(e.config.PollInterval)
+		if err := e.ReleaseMR(mr.ID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release %s: %v\n", mr.ID, err)
+		}
+	}
+}
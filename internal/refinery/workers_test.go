@@ -0,0 +1,72 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mq"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestLockTarget_SerializesSameTarget(t *testing.T) {
+	r := &rig.Rig{Name: "testrig", Path: t.TempDir()}
+	worker := func(id string) *Engineer {
+		return &Engineer{
+			rig:                   r,
+			output:                io.Discard,
+			config:                DefaultMergeQueueConfig(),
+			worker:                id,
+			mergeSlotMaxRetries:   2,
+			mergeSlotRetryBackoff: time.Millisecond,
+		}
+	}
+	w1, w2 := worker("refinery-1"), worker("refinery-2")
+	ctx := context.Background()
+
+	renew1, release1, res := w1.lockTarget(ctx, &MRInfo{ID: "gt-mr1", Target: "main"})
+	if res != nil {
+		t.Fatalf("w1 lock main: %s", res.Error)
+	}
+
+	// Same target: w2 waits, then leaves its MR in the queue.
+	if _, _, res := w2.lockTarget(ctx, &MRInfo{ID: "gt-mr2", Target: "main"}); res == nil || !res.SlotTimeout {
+		t.Fatalf("w2 lock main while held = %+v, want slot timeout", res)
+	}
+
+	// Different target: w2 goes ahead.
+	_, release2, res := w2.lockTarget(ctx, &MRInfo{ID: "gt-mr3", Target: "integration/gt-epic"})
+	if res != nil {
+		t.Fatalf("w2 lock integration branch: %s", res.Error)
+	}
+	release2()
+
+	if err := renew1(); err != nil {
+		t.Errorf("renewing held lock: %v", err)
+	}
+	release1()
+	if _, release, res := w2.lockTarget(ctx, &MRInfo{ID: "gt-mr2", Target: "main"}); res != nil {
+		t.Fatalf("w2 lock main after release: %s", res.Error)
+	} else {
+		release()
+	}
+}
+
+func TestLockTarget_RenewFailsAfterTakeover(t *testing.T) {
+	r := &rig.Rig{Name: "testrig", Path: t.TempDir()}
+	e := &Engineer{rig: r, output: io.Discard, config: DefaultMergeQueueConfig(), worker: "refinery-1"}
+	renew, _, res := e.lockTarget(context.Background(), &MRInfo{ID: "gt-mr1", Target: "main"})
+	if res != nil {
+		t.Fatal(res.Error)
+	}
+
+	// The lock went stale (e.g., very long gates) and another worker took it.
+	if _, err := mq.AcquireTargetLock(r.Path, "main", "refinery-2", "gt-mr2", time.Minute, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := renew(); !errors.Is(err, mq.ErrTargetLocked) {
+		t.Errorf("renew after takeover = %v, want ErrTargetLocked", err)
+	}
+}
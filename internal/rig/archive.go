@@ -0,0 +1,272 @@
+package rig
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ArchiveVersion is the archive format version written by WriteArchive.
+const ArchiveVersion = 1
+
+// Paths inside a rig archive.
+const (
+	archiveManifest = "manifest.json"
+	archiveBeads    = "beads.jsonl"
+	archiveRigDir   = "rig"
+	archiveDoltDir  = "dolt"
+)
+
+// ArchiveManifest describes an archived rig: enough to re-register it and
+// put its Dolt database back under .dolt-data/.
+type ArchiveManifest struct {
+	Version    int             `json:"version"`
+	Rig        string          `json:"rig"`
+	Entry      config.RigEntry `json:"entry"`              // rigs.json entry
+	RoutePath  string          `json:"route_path"`         // routes.jsonl path for the beads prefix
+	Database   string          `json:"database,omitempty"` // Dolt database name, if archived
+	BeadCount  int             `json:"bead_count"`         // Beads in beads.jsonl (-1 if not exported)
+	ArchivedAt time.Time       `json:"archived_at"`
+}
+
+// ArchiveSources are the inputs to WriteArchive. DoltDir and Beads are
+// optional.
+type ArchiveSources struct {
+	RigDir  string
+	DoltDir string
+	Beads   []byte // JSONL export of every bead
+}
+
+// WriteArchive writes a gzipped tarball at path holding the manifest, the
+// rig directory, the Dolt database directory, and the beads export.
+func WriteArchive(path string, m *ArchiveManifest, src ArchiveSources) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	m.Version = ArchiveVersion
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, archiveManifest, manifest); err != nil {
+		return err
+	}
+	if src.Beads != nil {
+		if err := writeTarFile(tw, archiveBeads, src.Beads); err != nil {
+			return err
+		}
+	}
+	if err := addTarDir(tw, src.RigDir, archiveRigDir); err != nil {
+		return fmt.Errorf("archiving rig directory: %w", err)
+	}
+	if src.DoltDir != "" {
+		if err := addTarDir(tw, src.DoltDir, archiveDoltDir); err != nil {
+			return fmt.Errorf("archiving Dolt database: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// addTarDir adds dir's tree under prefix. Regular files, directories, and
+// symlinks are kept; sockets and other special files are skipped.
+func addTarDir(tw *tar.Writer, dir, prefix string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(filepath.Join(prefix, rel))
+
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case info.IsDir(), info.Mode().IsRegular():
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// ReadArchiveManifest reads the manifest from an archive without extracting it.
+func ReadArchiveManifest(path string) (*ArchiveManifest, error) {
+	var m *ArchiveManifest
+	err := walkArchive(path, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if hdr.Name != archiveManifest {
+			return true, nil
+		}
+		m = &ArchiveManifest{}
+		if err := json.NewDecoder(r).Decode(m); err != nil {
+			return false, fmt.Errorf("parsing manifest: %w", err)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%s is not a rig archive (no %s)", path, archiveManifest)
+	}
+	if m.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive format v%d is newer than this gt supports (v%d)", m.Version, ArchiveVersion)
+	}
+	return m, nil
+}
+
+// ExtractArchive restores the rig directory to rigDest and the Dolt database
+// to doltDest; an empty doltDest skips the database. Neither destination may
+// exist.
+func ExtractArchive(path, rigDest, doltDest string) error {
+	for _, dest := range []string{rigDest, doltDest} {
+		if dest == "" {
+			continue
+		}
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("%s already exists", dest)
+		}
+	}
+	return walkArchive(path, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		top, rest, _ := strings.Cut(strings.TrimSuffix(hdr.Name, "/"), "/")
+		var root string
+		switch top {
+		case archiveRigDir:
+			root = rigDest
+		case archiveDoltDir:
+			root = doltDest
+		}
+		if root == "" {
+			return true, nil
+		}
+		target := filepath.Join(root, filepath.FromSlash(rest))
+		if target != root && !strings.HasPrefix(target, root+string(filepath.Separator)) {
+			return false, fmt.Errorf("archive entry %q escapes its directory", hdr.Name)
+		}
+		return true, extractTarEntry(hdr, r, target)
+	})
+}
+
+func extractTarEntry(hdr *tar.Header, r io.Reader, target string) error {
+	mode := fs.FileMode(hdr.Mode).Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode|0700)
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(hdr.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	}
+	return nil
+}
+
+// walkArchive calls fn for each entry until fn returns false or an error.
+func walkArchive(path string, fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		more, err := fn(hdr, tr)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	rigDir := filepath.Join(src, "gastown")
+	doltDir := filepath.Join(src, "dolt", "gastown")
+	for path, content := range map[string]string{
+		filepath.Join(rigDir, "config.json"):                "{}",
+		filepath.Join(rigDir, "crew", "max", "main.go"):     "package main",
+		filepath.Join(doltDir, ".dolt", "noms", "manifest"): "dolt",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(rigDir, "polecats"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("crew/max", filepath.Join(rigDir, "current")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive := filepath.Join(t.TempDir(), "gastown.tar.gz")
+	manifest := &ArchiveManifest{
+		Rig:       "gastown",
+		Entry:     config.RigEntry{GitURL: "https://example.com/gastown.git", BeadsConfig: &config.BeadsConfig{Prefix: "gt"}},
+		RoutePath: "gastown/mayor/rig",
+		Database:  "gastown",
+		BeadCount: 1,
+	}
+	beads := []byte(`{"id":"gt-1"}` + "\n")
+	if err := WriteArchive(archive, manifest, ArchiveSources{RigDir: rigDir, DoltDir: doltDir, Beads: beads}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteArchive(archive, manifest, ArchiveSources{RigDir: rigDir}); err == nil {
+		t.Error("WriteArchive should not overwrite an existing archive")
+	}
+
+	got, err := ReadArchiveManifest(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != ArchiveVersion || got.Rig != "gastown" || got.Database != "gastown" ||
+		got.Entry.BeadsConfig == nil || got.Entry.BeadsConfig.Prefix != "gt" {
+		t.Errorf("manifest = %+v", got)
+	}
+
+	dest := t.TempDir()
+	rigDest := filepath.Join(dest, "gastown")
+	doltDest := filepath.Join(dest, ".dolt-data", "gastown")
+	if err := ExtractArchive(archive, rigDest, doltDest); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(rigDest, "crew", "max", "main.go"):     "package main",
+		filepath.Join(doltDest, ".dolt", "noms", "manifest"): "dolt",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v", path, data, err)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(rigDest, "polecats")); err != nil || !info.IsDir() {
+		t.Errorf("empty directory not restored: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if link, err := os.Readlink(filepath.Join(rigDest, "current")); err != nil || link != "crew/max" {
+			t.Errorf("symlink = %q, %v", link, err)
+		}
+	}
+
+	if err := ExtractArchive(archive, rigDest, filepath.Join(dest, "other")); err == nil {
+		t.Error("ExtractArchive should refuse an existing destination")
+	}
+}
+
+func TestReadArchiveManifest_NotAnArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junk.tar.gz")
+	if err := os.WriteFile(path, []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadArchiveManifest(path); err == nil {
+		t.Error("expected an error for a non-archive")
+	}
+}
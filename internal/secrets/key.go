@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// The key sealing the secrets is 32 random bytes, looked up in order:
+//
+//  1. GT_SECRETS_KEY, base64: for a daemon or CI host without a keychain
+//  2. The OS keychain (macOS security, Linux secret-tool)
+//  3. The key file, <user config dir>/gastown/secrets.key (mode 0600)
+//
+// The first 'gt secret set' creates one, in the keychain when there is
+// one, else in the key file. Either way it stays out of the town, so a
+// copy or commit of the town doesn't carry the key with the secrets.
+
+// KeyEnv holds a base64 key that overrides the keychain and key file.
+const KeyEnv = "GT_SECRETS_KEY"
+
+const (
+	keySize         = 32 // AES-256
+	keychainService = "gastown-secrets"
+	keychainAccount = "secrets-key"
+)
+
+// useKeychain is cleared by tests to keep away from the user's keychain.
+var useKeychain = true
+
+// cachedKey is the keychain or key file's key once read, so a process
+// reading several secrets asks the keychain once.
+var (
+	cachedKeyMu sync.Mutex
+	cachedKey   []byte
+)
+
+// KeyFilePath returns the key file used when there is no keychain.
+func KeyFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gastown", "secrets.key")
+}
+
+// KeySource describes where the key is found: "GT_SECRETS_KEY",
+// "keychain", the key file's path, or "" if there is no key yet.
+func KeySource() string {
+	if os.Getenv(KeyEnv) != "" {
+		return KeyEnv
+	}
+	if kc := keychain(); kc != nil {
+		if _, err := kc.lookup(); err == nil {
+			return "keychain"
+		}
+	}
+	if p := KeyFilePath(); p != "" {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// loadKey returns the key, creating one if create is set and none exists.
+func loadKey(create bool) ([]byte, error) {
+	if v := os.Getenv(KeyEnv); v != "" {
+		return decodeKey(v, KeyEnv)
+	}
+	cachedKeyMu.Lock()
+	defer cachedKeyMu.Unlock()
+	if cachedKey != nil {
+		return cachedKey, nil
+	}
+	key, err := readOrCreateKey(create)
+	if err == nil {
+		cachedKey = key
+	}
+	return key, err
+}
+
+func readOrCreateKey(create bool) ([]byte, error) {
+	kc := keychain()
+	if kc != nil {
+		if key, err := kc.lookup(); err == nil {
+			return key, nil
+		}
+	}
+	keyFile := KeyFilePath()
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile) //nolint:gosec // G304: path is constructed internally
+		if err == nil {
+			return decodeKey(string(data), keyFile)
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading secrets key: %w", err)
+		}
+	}
+	if !create {
+		return nil, fmt.Errorf("no secrets key on this host: set %s to the key the secrets were stored with", KeyEnv)
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secrets key: %w", err)
+	}
+	if kc != nil && kc.store(key) == nil {
+		return key, nil
+	}
+	if keyFile == "" {
+		return nil, fmt.Errorf("no keychain or config directory for the secrets key: set %s", KeyEnv)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	return key, nil
+}
+
+func decodeKey(s, source string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("secrets key from %s must be %d bytes, base64-encoded", source, keySize)
+	}
+	return key, nil
+}
+
+// keychainTool reads and writes the key in the OS keychain.
+type keychainTool struct {
+	lookup func() ([]byte, error)
+	store  func(key []byte) error
+}
+
+// keychain returns the OS keychain, or nil if there is none to use. The
+// key is passed to the tools on stdin, never on the command line.
+func keychain() *keychainTool {
+	if !useKeychain {
+		return nil
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err != nil {
+			return nil
+		}
+		return &keychainTool{
+			lookup: func() ([]byte, error) {
+				out, err := exec.Command("security", "find-generic-password",
+					"-s", keychainService, "-a", keychainAccount, "-w").Output()
+				if err != nil {
+					return nil, err
+				}
+				return decodeKey(string(out), "keychain")
+			},
+			store: func(key []byte) error {
+				// security -i reads its commands from stdin.
+				cmd := exec.Command("security", "-i")
+				cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+					keychainService, keychainAccount, base64.StdEncoding.EncodeToString(key)))
+				return cmd.Run()
+			},
+		}
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil
+		}
+		return &keychainTool{
+			lookup: func() ([]byte, error) {
+				out, err := exec.Command("secret-tool", "lookup",
+					"service", keychainService, "account", keychainAccount).Output()
+				if err != nil {
+					return nil, err
+				}
+				return decodeKey(string(out), "keychain")
+			},
+			store: func(key []byte) error {
+				cmd := exec.Command("secret-tool", "store", "--label", "Gas Town secrets key",
+					"service", keychainService, "account", keychainAccount)
+				cmd.Stdin = bytes.NewReader([]byte(base64.StdEncoding.EncodeToString(key)))
+				return cmd.Run()
+			},
+		}
+	}
+	return nil
+}
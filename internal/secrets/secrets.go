@@ -0,0 +1,205 @@
+// Package secrets keeps a town's credentials (API tokens, the Dolt
+// password, webhook URLs) encrypted at rest, so settings files can refer
+// to them instead of holding them in plaintext.
+//
+// Each secret is one file under <town>/.gastown/secrets/, sealed with
+// AES-256-GCM under a key that never lives in the town: it comes from
+// GT_SECRETS_KEY, the OS keychain, or a key file in the user's config
+// directory (see key.go). Settings refer to a secret as "secret:<name>"
+// (see Resolve).
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Dir is the secrets directory, relative to the town root.
+const Dir = ".gastown/secrets"
+
+// RefPrefix marks a settings value that names a secret, e.g.
+// "secret:dolt.password".
+const RefPrefix = "secret:"
+
+// DoltPassword is the secret the Dolt server password is read from when
+// GT_DOLT_PASSWORD is not set.
+const DoltPassword = "dolt.password"
+
+const fileSuffix = ".secret"
+
+// ErrNotFound is returned for a secret that isn't set.
+var ErrNotFound = errors.New("secret not set")
+
+// validName matches secret names: letters, digits, '.', '_' and '-'.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName checks that name can be used as a secret name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) || len(name) > 128 {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// sealed is a secret's file.
+type sealed struct {
+	Version    int       `json:"version"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Info describes a secret without its value.
+type Info struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func path(townRoot, name string) string {
+	return filepath.Join(townRoot, Dir, name+fileSuffix)
+}
+
+// Set encrypts value and stores it as secret name, creating the key on
+// first use.
+func Set(townRoot, name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	key, err := loadKey(true)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	s := sealed{
+		Version:    1,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, []byte(value), []byte(name)),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, Dir), 0700); err != nil {
+		return fmt.Errorf("creating secrets directory: %w", err)
+	}
+	return util.AtomicWriteJSONWithPerm(path(townRoot, name), s, 0600)
+}
+
+// Get decrypts secret name. It returns ErrNotFound if it isn't set.
+func Get(townRoot, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path(townRoot, name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s: %w", name, ErrNotFound)
+		}
+		return "", fmt.Errorf("reading secret %s: %w", name, err)
+	}
+	var s sealed
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", fmt.Errorf("parsing secret %s: %w", name, err)
+	}
+	key, err := loadKey(false)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return "", fmt.Errorf("secret %s is corrupt", name)
+	}
+	plain, err := gcm.Open(nil, s.Nonce, s.Ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret %s: wrong key or corrupt file", name)
+	}
+	return string(plain), nil
+}
+
+// Lookup is Get for optional secrets: ok is false, without an error, when
+// name isn't set. The key is only loaded for a secret that exists.
+func Lookup(townRoot, name string) (value string, ok bool, err error) {
+	value, err = Get(townRoot, name)
+	if errors.Is(err, ErrNotFound) {
+		return "", false, nil
+	}
+	return value, err == nil, err
+}
+
+// Delete removes secret name. It returns ErrNotFound if it isn't set.
+func Delete(townRoot, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(path(townRoot, name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", name, ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// List returns the town's secrets, sorted by name, without decrypting
+// them.
+func List(townRoot string) ([]Info, error) {
+	paths, err := filepath.Glob(filepath.Join(townRoot, Dir, "*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var infos []Info
+	for _, p := range paths {
+		info := Info{Name: strings.TrimSuffix(filepath.Base(p), fileSuffix)}
+		if data, err := os.ReadFile(p); err == nil { //nolint:gosec // G304: path is constructed internally
+			var s sealed
+			if json.Unmarshal(data, &s) == nil {
+				info.UpdatedAt = s.UpdatedAt
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// IsRef reports whether a settings value names a secret.
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// Resolve returns the secret a settings value names ("secret:<name>"), or
+// the value itself when it isn't a reference.
+func Resolve(townRoot, value string) (string, error) {
+	name, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok {
+		return value, nil
+	}
+	return Get(townRoot, name)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
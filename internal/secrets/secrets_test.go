@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withKeyFile points the key file at a temporary config directory.
+func withKeyFile(t *testing.T) string {
+	t.Helper()
+	useKeychain, cachedKey = false, nil
+	t.Cleanup(func() { useKeychain, cachedKey = true, nil })
+	t.Setenv(KeyEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	return KeyFilePath()
+}
+
+func TestSetGetRoundTrip(t *testing.T) {
+	keyFile := withKeyFile(t)
+	town := t.TempDir()
+
+	if err := Set(town, "dolt.password", "hunter2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := Get(town, "dolt.password")
+	if err != nil || got != "hunter2" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(town, Dir, "dolt.password.secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Error("secret stored in plaintext")
+	}
+	for _, p := range []string{keyFile, filepath.Join(town, Dir, "dolt.password.secret")} {
+		if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("%s: mode %v, %v", p, info.Mode().Perm(), err)
+		}
+	}
+	if KeySource() != keyFile {
+		t.Errorf("KeySource = %q, want %q", KeySource(), keyFile)
+	}
+}
+
+func TestGetMissingAndWrongKey(t *testing.T) {
+	withKeyFile(t)
+	town := t.TempDir()
+
+	if _, err := Get(town, "nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing: %v", err)
+	}
+	if _, ok, err := Lookup(town, "nope"); ok || err != nil {
+		t.Errorf("Lookup missing = %v, %v", ok, err)
+	}
+
+	if err := Set(town, "token", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(KeyEnv, base64.StdEncoding.EncodeToString(make([]byte, keySize)))
+	if _, err := Get(town, "token"); err == nil || !strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("Get with another key: %v", err)
+	}
+}
+
+func TestSecretBoundToName(t *testing.T) {
+	withKeyFile(t)
+	town := t.TempDir()
+	if err := Set(town, "a", "value-a"); err != nil {
+		t.Fatal(err)
+	}
+	// A file copied over another secret's doesn't decrypt as that secret.
+	data, _ := os.ReadFile(path(town, "a"))
+	if err := os.WriteFile(path(town, "b"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(town, "b"); err == nil {
+		t.Error("swapped secret decrypted")
+	}
+}
+
+func TestListDeleteResolve(t *testing.T) {
+	withKeyFile(t)
+	town := t.TempDir()
+	for _, name := range []string{"webhook.ci", "dolt.password"} {
+		if err := Set(town, name, "v-"+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := List(town)
+	if err != nil || len(infos) != 2 || infos[0].Name != "dolt.password" || infos[1].Name != "webhook.ci" {
+		t.Fatalf("List = %+v, %v", infos, err)
+	}
+
+	if got, err := Resolve(town, "secret:webhook.ci"); err != nil || got != "v-webhook.ci" {
+		t.Errorf("Resolve ref = %q, %v", got, err)
+	}
+	if got, err := Resolve(town, "https://example.com"); err != nil || got != "https://example.com" {
+		t.Errorf("Resolve plain = %q, %v", got, err)
+	}
+
+	if err := Delete(town, "webhook.ci"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(town, "webhook.ci"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete twice: %v", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"dolt.password", "gh_token", "a-1"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "../x", "a/b", "a b"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("%q accepted", name)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package style
+
+import (
+	"fmt"
+	"time"
+)
+
+// Age bands show at a glance how long an item has been waiting, relative to
+// its priority: a P0 a day old is stale where a P3 is still fresh. Tables
+// render a column marked Age this way (Table.AddAgedRow); listings that
+// aren't tables use RenderAge.
+
+// AgeBand classifies an item's age against its priority's thresholds.
+type AgeBand int
+
+const (
+	AgeFresh AgeBand = iota // Younger than the aging threshold
+	AgeAging                // Past the aging threshold
+	AgeStale                // Past the stale threshold
+)
+
+// AgeThresholds are the ages at which an item turns aging and stale.
+type AgeThresholds struct {
+	Aging time.Duration
+	Stale time.Duration
+}
+
+const day = 24 * time.Hour
+
+// DefaultAgeThresholds are the thresholds of priorities P0 to P4.
+var DefaultAgeThresholds = [5]AgeThresholds{
+	{Aging: 4 * time.Hour, Stale: day},
+	{Aging: day, Stale: 3 * day},
+	{Aging: 3 * day, Stale: 7 * day},
+	{Aging: 7 * day, Stale: 30 * day},
+	{Aging: 14 * day, Stale: 90 * day},
+}
+
+// ageThresholds are the thresholds in effect, set from town settings at
+// startup.
+var ageThresholds = DefaultAgeThresholds
+
+// SetAgeThresholds overrides the thresholds of priority (0-4). A zero
+// field keeps the current value; other priorities are ignored.
+func SetAgeThresholds(priority int, th AgeThresholds) {
+	if priority < 0 || priority >= len(ageThresholds) {
+		return
+	}
+	if th.Aging > 0 {
+		ageThresholds[priority].Aging = th.Aging
+	}
+	if th.Stale > 0 {
+		ageThresholds[priority].Stale = th.Stale
+	}
+}
+
+// AgeThresholdsFor returns the thresholds of priority. Priorities past P4
+// use P4's, and negative ones P0's.
+func AgeThresholdsFor(priority int) AgeThresholds {
+	priority = max(0, min(priority, len(ageThresholds)-1))
+	return ageThresholds[priority]
+}
+
+// AgeBandOf returns the band of an item of priority that is age old.
+func AgeBandOf(age time.Duration, priority int) AgeBand {
+	th := AgeThresholdsFor(priority)
+	switch {
+	case age >= th.Stale:
+		return AgeStale
+	case age >= th.Aging:
+		return AgeAging
+	default:
+		return AgeFresh
+	}
+}
+
+// HumanizeAge renders d in its largest whole unit: "45s", "12m", "5h",
+// "3d", "6w", "4mo" or "2y".
+func HumanizeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(0, int(d.Seconds())))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 60*day:
+		return fmt.Sprintf("%dw", int(d/(7*day)))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	default:
+		return fmt.Sprintf("%dy", int(d/(365*day)))
+	}
+}
+
+// RenderAge returns the humanized age at now of an item of priority created
+// at since, colored by its band: dim when fresh, yellow when aging, red when
+// stale. An unknown (zero) since renders as "?".
+func RenderAge(since, now time.Time, priority int) string {
+	if since.IsZero() {
+		return Dim.Render("?")
+	}
+	age := now.Sub(since)
+	text := HumanizeAge(age)
+	switch AgeBandOf(age, priority) {
+	case AgeStale:
+		return Error.Render(text)
+	case AgeAging:
+		return Warning.Render(text)
+	default:
+		return Dim.Render(text)
+	}
+}
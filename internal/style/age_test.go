@@ -0,0 +1,88 @@
+package style
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHumanizeAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "0s"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{5*time.Hour + 59*time.Minute, "5h"},
+		{3 * day, "3d"},
+		{13 * day, "13d"},
+		{20 * day, "2w"},
+		{100 * day, "3mo"},
+		{800 * day, "2y"},
+	}
+	for _, tt := range tests {
+		if got := HumanizeAge(tt.d); got != tt.want {
+			t.Errorf("HumanizeAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestAgeBandOf(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		priority int
+		want     AgeBand
+	}{
+		{time.Hour, 0, AgeFresh},
+		{5 * time.Hour, 0, AgeAging},
+		{2 * day, 0, AgeStale},
+		{2 * day, 2, AgeFresh},
+		{4 * day, 2, AgeAging},
+		{8 * day, 2, AgeStale},
+		{20 * day, 9, AgeAging}, // past P4 uses P4's thresholds
+		{2 * day, -1, AgeStale}, // negative uses P0's
+	}
+	for _, tt := range tests {
+		if got := AgeBandOf(tt.age, tt.priority); got != tt.want {
+			t.Errorf("AgeBandOf(%v, P%d) = %d, want %d", tt.age, tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestSetAgeThresholds(t *testing.T) {
+	defer func() { ageThresholds = DefaultAgeThresholds }()
+
+	SetAgeThresholds(1, AgeThresholds{Stale: 2 * day})
+	if got := AgeThresholdsFor(1); got.Aging != DefaultAgeThresholds[1].Aging || got.Stale != 2*day {
+		t.Errorf("P1 thresholds = %+v, want aging kept and stale 2d", got)
+	}
+	SetAgeThresholds(7, AgeThresholds{Aging: time.Minute})
+	if got := AgeThresholdsFor(7); got != DefaultAgeThresholds[4] {
+		t.Errorf("out-of-range priority changed P4's thresholds: %+v", got)
+	}
+}
+
+func TestTableAgedRow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	table := NewTable(
+		Column{Name: "ID", Width: 8},
+		Column{Name: "AGE", Width: 4, Align: AlignRight, Age: true},
+		Column{Name: "TITLE", Width: 10},
+	).SetNow(now)
+	table.AddAgedRow(now.Add(-3*day), 2, "gt-abc", "Fix it")
+	table.AddAgedRow(time.Time{}, 2, "gt-def", "No date")
+	table.AddRow("gt-ghi", "n/a", "Plain")
+
+	lines := strings.Split(stripAnsi(table.Render()), "\n")
+	want := []string{
+		"  gt-abc     3d Fix it    ",
+		"  gt-def      ? No date   ",
+		"  gt-ghi    n/a Plain     ",
+	}
+	for i, w := range want {
+		if got := lines[i+2]; got != w {
+			t.Errorf("row %d = %q, want %q", i, got, w)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package style
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -14,6 +15,7 @@ type Column struct {
 	Width int
 	Align Alignment
 	Style lipgloss.Style
+	Age   bool // Show the row's age, colored by band (see AddAgedRow)
 }
 
 // Alignment specifies column text alignment.
@@ -29,6 +31,8 @@ const (
 type Table struct {
 	columns    []Column
 	rows       [][]string
+	ages       []*rowAge // Per row; nil for rows added with AddRow
+	now        time.Time
 	headerSep  bool
 	indent     string
 	headerStyle lipgloss.Style
@@ -63,6 +67,40 @@ func (t *Table) AddRow(values ...string) *Table {
 		values = append(values, "")
 	}
 	t.rows = append(t.rows, values)
+	t.ages = append(t.ages, nil)
+	return t
+}
+
+// rowAge is what an Age column renders for a row.
+type rowAge struct {
+	since    time.Time
+	priority int
+}
+
+// AddAgedRow adds a row for an item of priority created at since. values
+// fill the columns other than Age ones, in order; Age columns show the
+// item's age (see RenderAge).
+func (t *Table) AddAgedRow(since time.Time, priority int, values ...string) *Table {
+	row := make([]string, 0, len(t.columns))
+	for _, col := range t.columns {
+		switch {
+		case col.Age:
+			row = append(row, "")
+		case len(values) > 0:
+			row = append(row, values[0])
+			values = values[1:]
+		default:
+			row = append(row, "")
+		}
+	}
+	t.rows = append(t.rows, row)
+	t.ages = append(t.ages, &rowAge{since: since, priority: priority})
+	return t
+}
+
+// SetNow sets the time ages are measured at (default: when rendered).
+func (t *Table) SetNow(now time.Time) *Table {
+	t.now = now
 	return t
 }
 
@@ -99,14 +137,22 @@ func (t *Table) Render() string {
 		sb.WriteString("\n")
 	}
 
+	now := t.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
 	// Render rows
-	for _, row := range t.rows {
+	for r, row := range t.rows {
 		sb.WriteString(t.indent)
 		for i, col := range t.columns {
 			val := ""
 			if i < len(row) {
 				val = row[i]
 			}
+			if age := t.ages[r]; col.Age && age != nil {
+				val = RenderAge(age.since, now, age.priority)
+			}
 			// Truncate if too long
 			plainVal := stripAnsi(val)
 			if len(plainVal) > col.Width {
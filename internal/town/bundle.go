@@ -0,0 +1,240 @@
+// Package town provides join bundles that let a new machine become a member
+// of an existing Gas Town.
+package town
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// BundleFormat identifies a town join bundle file.
+const BundleFormat = "gt-town-bundle"
+
+// CurrentBundleVersion is the current schema version for Bundle.
+const CurrentBundleVersion = 1
+
+// pbkdf2Iterations is the key-derivation work factor for encrypted bundles.
+const pbkdf2Iterations = 600_000
+
+var (
+	// ErrBundleExpired is returned when decoding a bundle past its expiry.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrPassphraseRequired is returned when decoding an encrypted bundle without a passphrase.
+	ErrPassphraseRequired = errors.New("bundle is encrypted: passphrase required")
+
+	// ErrSecretsUnencrypted is returned when encoding a bundle that carries
+	// credentials without a passphrase.
+	ErrSecretsUnencrypted = errors.New("bundle contains credentials: a passphrase is required")
+)
+
+// DoltEndpoint describes how a member machine reaches the town's Dolt server.
+type DoltEndpoint struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"` // Scoped invite credential, only with --include-credentials
+}
+
+// Bundle is everything a new machine needs to join a town.
+type Bundle struct {
+	Version   int                        `json:"version"`
+	CreatedAt time.Time                  `json:"created_at"`
+	ExpiresAt time.Time                  `json:"expires_at,omitempty"`
+	Town      config.TownConfig          `json:"town"`
+	Rigs      map[string]config.RigEntry `json:"rigs"`
+
+	// Dolt is the shared Dolt server endpoint, if one is advertised.
+	Dolt *DoltEndpoint `json:"dolt,omitempty"`
+
+	// Remotes maps database name to its Dolt remote (origin) URL.
+	Remotes map[string]string `json:"remotes,omitempty"`
+}
+
+// HasSecrets reports whether the bundle carries credentials.
+func (b *Bundle) HasSecrets() bool {
+	return b.Dolt != nil && b.Dolt.Password != ""
+}
+
+// envelope is the on-disk bundle wrapper. Payload is the JSON bundle,
+// AES-256-GCM sealed when Encrypted is set.
+type envelope struct {
+	Format    string `json:"format"`
+	Version   int    `json:"version"`
+	Encrypted bool   `json:"encrypted"`
+	Salt      []byte `json:"salt,omitempty"`
+	Nonce     []byte `json:"nonce,omitempty"`
+	Payload   []byte `json:"payload"`
+}
+
+// NewBundle builds a bundle from the town's identity and rig registry.
+// A ttl of zero means the bundle never expires.
+func NewBundle(townRoot string, ttl time.Duration) (*Bundle, error) {
+	townCfg, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading town config: %w", err)
+	}
+	rigsCfg, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("loading rigs registry: %w", err)
+	}
+
+	b := &Bundle{
+		Version:   CurrentBundleVersion,
+		CreatedAt: time.Now().UTC(),
+		Town:      *townCfg,
+		Rigs:      map[string]config.RigEntry{},
+	}
+	if rigsCfg != nil {
+		for name, entry := range rigsCfg.Rigs {
+			// LocalRepo is a path on the inviting machine; meaningless elsewhere.
+			entry.LocalRepo = ""
+			b.Rigs[name] = entry
+		}
+	}
+	if ttl > 0 {
+		b.ExpiresAt = b.CreatedAt.Add(ttl)
+	}
+	return b, nil
+}
+
+// Encode serializes a bundle. With a non-empty passphrase the payload is
+// encrypted; bundles carrying secrets refuse to encode without one.
+func Encode(b *Bundle, passphrase string) ([]byte, error) {
+	if b.HasSecrets() && passphrase == "" {
+		return nil, ErrSecretsUnencrypted
+	}
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("encoding bundle: %w", err)
+	}
+
+	env := envelope{Format: BundleFormat, Version: CurrentBundleVersion, Payload: payload}
+	if passphrase != "" {
+		env.Encrypted = true
+		env.Salt = make([]byte, 16)
+		if _, err := rand.Read(env.Salt); err != nil {
+			return nil, fmt.Errorf("generating salt: %w", err)
+		}
+		gcm, err := newGCM(passphrase, env.Salt)
+		if err != nil {
+			return nil, err
+		}
+		env.Nonce = make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(env.Nonce); err != nil {
+			return nil, fmt.Errorf("generating nonce: %w", err)
+		}
+		env.Payload = gcm.Seal(nil, env.Nonce, payload, []byte(BundleFormat))
+	}
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Decode parses a bundle, decrypting it if needed, and rejects expired bundles.
+func Decode(data []byte, passphrase string, now time.Time) (*Bundle, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing bundle: %w", err)
+	}
+	if env.Format != BundleFormat {
+		return nil, fmt.Errorf("not a town bundle (format %q)", env.Format)
+	}
+	if env.Version > CurrentBundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported %d; upgrade gt", env.Version, CurrentBundleVersion)
+	}
+
+	payload := env.Payload
+	if env.Encrypted {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		gcm, err := newGCM(passphrase, env.Salt)
+		if err != nil {
+			return nil, err
+		}
+		payload, err = gcm.Open(nil, env.Nonce, env.Payload, []byte(BundleFormat))
+		if err != nil {
+			return nil, errors.New("decrypting bundle: wrong passphrase or corrupted bundle")
+		}
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(payload, &b); err != nil {
+		return nil, fmt.Errorf("parsing bundle payload: %w", err)
+	}
+	if !b.ExpiresAt.IsZero() && now.After(b.ExpiresAt) {
+		return nil, fmt.Errorf("%w at %s", ErrBundleExpired, b.ExpiresAt.Format(time.RFC3339))
+	}
+	return &b, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Apply writes the bundle's town identity into townRoot and returns the
+// bundle rigs not yet registered locally, sorted by name. Rigs are not
+// registered directly: each needs a real clone, so callers hand the list to
+// 'gt rig add'. An existing town.json must belong to the same town.
+func Apply(b *Bundle, townRoot string) ([]string, error) {
+	townPath := filepath.Join(townRoot, "mayor", "town.json")
+	existing, err := config.LoadTownConfig(townPath)
+	switch {
+	case err == nil:
+		if existing.Name != b.Town.Name {
+			return nil, fmt.Errorf("%s already belongs to town %q (bundle is for %q)", townRoot, existing.Name, b.Town.Name)
+		}
+	case errors.Is(err, config.ErrNotFound):
+		if err := config.SaveTownConfig(townPath, &b.Town); err != nil {
+			return nil, fmt.Errorf("writing town config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("loading town config: %w", err)
+	}
+
+	local := map[string]config.RigEntry{}
+	rigsCfg, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err == nil {
+		local = rigsCfg.Rigs
+	} else if !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("loading rigs registry: %w", err)
+	}
+
+	var missing []string
+	for name := range b.Rigs {
+		if _, ok := local[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// WriteFile encodes a bundle to path with owner-only permissions.
+func WriteFile(path string, b *Bundle, passphrase string) error {
+	data, err := Encode(b, passphrase)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,144 @@
+package town
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeTestTown(t *testing.T, name string, rigs map[string]config.RigEntry) string {
+	t.Helper()
+	root := t.TempDir()
+	townCfg := &config.TownConfig{Type: "town", Version: config.CurrentTownVersion, Name: name}
+	if err := config.SaveTownConfig(filepath.Join(root, "mayor", "town.json"), townCfg); err != nil {
+		t.Fatal(err)
+	}
+	if rigs != nil {
+		rigsCfg := &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: rigs}
+		if err := config.SaveRigsConfig(filepath.Join(root, "mayor", "rigs.json"), rigsCfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestNewBundle_StripsLocalPaths(t *testing.T) {
+	root := writeTestTown(t, "hq", map[string]config.RigEntry{
+		"gastown": {GitURL: "https://example.com/gastown.git", LocalRepo: "/home/me/src/gastown"},
+	})
+	b, err := NewBundle(root, time.Hour)
+	if err != nil {
+		t.Fatalf("NewBundle: %v", err)
+	}
+	if b.Town.Name != "hq" {
+		t.Errorf("Town.Name = %q", b.Town.Name)
+	}
+	if got := b.Rigs["gastown"]; got.GitURL == "" || got.LocalRepo != "" {
+		t.Errorf("unexpected rig entry %+v", got)
+	}
+	if b.ExpiresAt.IsZero() {
+		t.Error("expected expiry to be set")
+	}
+}
+
+func TestEncodeDecode_Plain(t *testing.T) {
+	b := &Bundle{Version: CurrentBundleVersion, Town: config.TownConfig{Name: "hq"},
+		Dolt: &DoltEndpoint{Host: "dolt.lan", Port: 3307}}
+	data, err := Encode(b, "")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := Decode(data, "", time.Now())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Dolt == nil || got.Dolt.Host != "dolt.lan" {
+		t.Errorf("round trip lost dolt endpoint: %+v", got.Dolt)
+	}
+}
+
+func TestEncodeDecode_Encrypted(t *testing.T) {
+	b := &Bundle{Version: CurrentBundleVersion, Town: config.TownConfig{Name: "hq"},
+		Dolt: &DoltEndpoint{Host: "dolt.lan", Port: 3307, User: "gt_invite_ab12", Password: "tok-123"}}
+
+	if _, err := Encode(b, ""); !errors.Is(err, ErrSecretsUnencrypted) {
+		t.Fatalf("Encode without passphrase = %v, want ErrSecretsUnencrypted", err)
+	}
+
+	data, err := Encode(b, "correct horse")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if strings.Contains(string(data), "tok-123") {
+		t.Fatal("encrypted bundle leaks password in plaintext")
+	}
+
+	if _, err := Decode(data, "", time.Now()); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Decode without passphrase = %v, want ErrPassphraseRequired", err)
+	}
+	if _, err := Decode(data, "wrong", time.Now()); err == nil {
+		t.Error("Decode with wrong passphrase should fail")
+	}
+	got, err := Decode(data, "correct horse", time.Now())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Dolt == nil || got.Dolt.Password != "tok-123" {
+		t.Errorf("dolt endpoint = %+v", got.Dolt)
+	}
+}
+
+func TestDecode_Expired(t *testing.T) {
+	now := time.Now()
+	b := &Bundle{Version: CurrentBundleVersion, Town: config.TownConfig{Name: "hq"}, ExpiresAt: now.Add(-time.Minute)}
+	data, err := Encode(b, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decode(data, "", now); !errors.Is(err, ErrBundleExpired) {
+		t.Errorf("Decode = %v, want ErrBundleExpired", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	b := &Bundle{
+		Town: config.TownConfig{Type: "town", Version: config.CurrentTownVersion, Name: "hq"},
+		Rigs: map[string]config.RigEntry{
+			"gastown": {GitURL: "https://example.com/gastown.git"},
+			"beads":   {GitURL: "https://example.com/beads.git"},
+		},
+	}
+
+	// Fresh directory: town.json is created, every rig still needs adding.
+	root := t.TempDir()
+	missing, err := Apply(b, root)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(missing) != 2 || missing[0] != "beads" || missing[1] != "gastown" {
+		t.Errorf("missing = %v", missing)
+	}
+	if _, err := config.LoadTownConfig(filepath.Join(root, "mayor", "town.json")); err != nil {
+		t.Errorf("town.json not written: %v", err)
+	}
+
+	// Existing member of the same town: only unregistered rigs are reported.
+	root = writeTestTown(t, "hq", map[string]config.RigEntry{"gastown": {GitURL: "https://mirror/gastown.git"}})
+	missing, err = Apply(b, root)
+	if err != nil {
+		t.Fatalf("Apply to existing: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != "beads" {
+		t.Errorf("missing = %v, want [beads]", missing)
+	}
+
+	// A different town is refused.
+	root = writeTestTown(t, "other", nil)
+	if _, err := Apply(b, root); err == nil {
+		t.Error("Apply into a different town should fail")
+	}
+}
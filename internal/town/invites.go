@@ -0,0 +1,122 @@
+package town
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Invite records a scoped Dolt credential issued by 'gt town invite' so it
+// can be listed and revoked later. The password is never stored.
+type Invite struct {
+	User      string    `json:"user"`
+	Databases []string  `json:"databases"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the invite's bundle has expired.
+func (i Invite) Expired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && now.After(i.ExpiresAt)
+}
+
+// InvitesFile returns the path of the town's invite registry.
+func InvitesFile(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "invites.json")
+}
+
+// LoadInvites reads the invite registry. A missing file is an empty registry.
+func LoadInvites(townRoot string) ([]Invite, error) {
+	data, err := os.ReadFile(InvitesFile(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading invites: %w", err)
+	}
+	var invites []Invite
+	if err := json.Unmarshal(data, &invites); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", InvitesFile(townRoot), err)
+	}
+	return invites, nil
+}
+
+// SaveInvites writes the invite registry.
+func SaveInvites(townRoot string, invites []Invite) error {
+	data, err := json.MarshalIndent(invites, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := InvitesFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: no secrets, user names only
+}
+
+// SecretsFile returns where 'gt town join' writes the credentials from a
+// bundle. It lives under .runtime/, which the town .gitignore excludes.
+func SecretsFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "secrets.env")
+}
+
+// WriteSecrets writes vars as shell exports to SecretsFile with owner-only
+// permissions, replacing earlier values for the same names. It returns the
+// file path.
+func WriteSecrets(townRoot string, vars map[string]string) (string, error) {
+	path := SecretsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	existing := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		for _, line := range strings.Split(string(data), "\n") {
+			if name, value, ok := parseExport(line); ok {
+				existing[name] = value
+			}
+		}
+	}
+	for name, value := range vars {
+		existing[name] = shellQuote(value)
+	}
+
+	names := make([]string, 0, len(existing))
+	for name := range existing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []byte
+	for _, name := range names {
+		out = append(out, fmt.Sprintf("export %s=%s\n", name, existing[name])...)
+	}
+	// WriteFile keeps the mode of an existing file: tighten it before the
+	// secrets land in it.
+	if err := os.Chmod(path, 0600); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.WriteFile(path, out, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// parseExport splits an "export NAME=value" line, keeping value quoted.
+func parseExport(line string) (name, value string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "export ")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, "=")
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,68 @@
+package town
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSecrets_OwnerOnlyAndMerged(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, err := WriteSecrets(townRoot, map[string]string{"GT_DOLT_PASSWORD": "old"}); err != nil {
+		t.Fatal(err)
+	}
+	// Loosen the mode to check it is tightened again on rewrite.
+	if err := os.Chmod(SecretsFile(townRoot), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path, err := WriteSecrets(townRoot, map[string]string{"GT_DOLT_PASSWORD": "it's new", "GT_DOLT_USER": "gt_invite_ab12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "export GT_DOLT_PASSWORD='it'\\''s new'\nexport GT_DOLT_USER='gt_invite_ab12'\n"
+	if string(data) != want {
+		t.Errorf("secrets file =\n%s\nwant\n%s", data, want)
+	}
+	if strings.Contains(string(data), "old") {
+		t.Error("old value not replaced")
+	}
+}
+
+func TestInvites_RoundTripAndExpiry(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+
+	invites, err := LoadInvites(townRoot)
+	if err != nil || len(invites) != 0 {
+		t.Fatalf("LoadInvites on empty town = %v, %v", invites, err)
+	}
+
+	want := []Invite{
+		{User: "gt_invite_a", Databases: []string{"hq"}, CreatedAt: now, ExpiresAt: now.Add(-time.Hour)},
+		{User: "gt_invite_b", Databases: []string{"hq"}, CreatedAt: now},
+	}
+	if err := SaveInvites(townRoot, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadInvites(townRoot)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("LoadInvites = %v, %v", got, err)
+	}
+	if !got[0].Expired(now) || got[1].Expired(now) {
+		t.Errorf("Expired = %v, %v; want true, false", got[0].Expired(now), got[1].Expired(now))
+	}
+}
@@ -7,14 +7,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -125,6 +128,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSSE(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/beads/schema" && r.Method == http.MethodGet:
+		h.handleBeadsSchema(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -259,6 +264,28 @@ func (h *APIHandler) sendError(w http.ResponseWriter, message string, status int
 	})
 }
 
+// dashboardIdentity is the rate limit identity of writes made through the
+// dashboard API.
+const dashboardIdentity = "dashboard"
+
+// allowWrite checks the dashboard's bead write quota (see the ratelimit
+// package), answering 429 with Retry-After when it is used up. Unlike the
+// CLI, the API doesn't wait for a token.
+func (h *APIHandler) allowWrite(w http.ResponseWriter) bool {
+	townRoot := beads.FindTownRoot(h.workDir)
+	if townRoot == "" {
+		return true
+	}
+	err := ratelimit.ForTown(townRoot).Allow(dashboardIdentity)
+	if err == nil {
+		return true
+	}
+	retry := int(math.Ceil(ratelimit.RetryAfter(err).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retry, 1)))
+	h.sendError(w, err.Error(), http.StatusTooManyRequests)
+	return false
+}
+
 // MailMessage represents a mail message for the API.
 type MailMessage struct {
 	ID        string `json:"id"`
@@ -554,6 +581,9 @@ func (h *APIHandler) handleMailSend(w http.ResponseWriter, r *http.Request) {
 	}
 	args = append(args, "--", req.To)
 
+	if !h.allowWrite(w) {
+		return
+	}
 	output, err := h.runGtCommand(r.Context(), 30*time.Second, args)
 	if err != nil {
 		h.sendError(w, "Failed to send message: "+err.Error()+"\n"+output, http.StatusInternalServerError)
@@ -1094,6 +1124,10 @@ func (h *APIHandler) handleIssueCreate(w http.ResponseWriter, r *http.Request) {
 
 	args = append(args, "--", req.Title)
 
+	if !h.allowWrite(w) {
+		return
+	}
+
 	// Run bd create
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
@@ -1149,6 +1183,9 @@ func (h *APIHandler) handleIssueClose(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.allowWrite(w) {
+		return
+	}
 	output, err := h.runBdCommand(r.Context(), 12*time.Second, []string{"close", req.ID})
 
 	w.Header().Set("Content-Type", "application/json")
@@ -1226,6 +1263,9 @@ func (h *APIHandler) handleIssueUpdate(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, "No update fields provided", http.StatusBadRequest)
 		return
 	}
+	if !h.allowWrite(w) {
+		return
+	}
 
 	output, err := h.runBdCommand(r.Context(), 12*time.Second, args)
 
@@ -1947,6 +1987,34 @@ func (h *APIHandler) handleReady(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// handleBeadsSchema returns the live bead schema (gt bead schema --json),
+// optionally for ?rig=<name>.
+func (h *APIHandler) handleBeadsSchema(w http.ResponseWriter, r *http.Request) {
+	args := []string{"bead", "schema", "--json"}
+	if rigName := r.URL.Query().Get("rig"); rigName != "" {
+		if !isValidRigName(rigName) {
+			h.sendError(w, "Invalid rig name", http.StatusBadRequest)
+			return
+		}
+		args = append(args, "--rig", rigName)
+	}
+
+	output, err := h.runGtCommand(r.Context(), 15*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Failed to load schema: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Decode only the first JSON value; stderr warnings are appended after it.
+	var schema beads.Schema
+	if err := json.NewDecoder(strings.NewReader(output)).Decode(&schema); err != nil {
+		h.sendError(w, "Failed to parse schema: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(schema)
+}
+
 // SessionPreviewResponse is the response for /api/session/preview.
 type SessionPreviewResponse struct {
 	Session   string `json:"session"`
@@ -6,10 +6,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestValidateCommand(t *testing.T) {
@@ -360,6 +365,41 @@ func TestAPIHandler_NotFound(t *testing.T) {
 	}
 }
 
+func TestAPIHandler_BeadsSchema(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gt is a shell script")
+	}
+	fakeGt := filepath.Join(t.TempDir(), "gt")
+	script := "#!/bin/sh\necho '{\"version\":1,\"types\":[{\"name\":\"bug\",\"source\":\"builtin\"}]}'\necho 'Note: stale' >&2\n"
+	if err := os.WriteFile(fakeGt, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler.gtPath = fakeGt
+
+	req := httptest.NewRequest(http.MethodGet, "/api/beads/schema", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/beads/schema status = %d: %s", w.Code, w.Body.String())
+	}
+	var schema beads.Schema
+	if err := json.Unmarshal(w.Body.Bytes(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Version != 1 || len(schema.Types) != 1 || schema.Types[0].Name != "bug" {
+		t.Errorf("schema = %+v", schema)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/beads/schema?rig=../etc", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid rig status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestGetCommandList(t *testing.T) {
 	commands := GetCommandList()
 
@@ -1003,3 +1043,35 @@ func TestRunGtCommandSemaphoreTimeoutBudget(t *testing.T) {
 		t.Errorf("elapsed = %v, want < 500ms (timeout should bound semaphore wait)", elapsed)
 	}
 }
+
+func TestAPIHandler_AllowWrite_RateLimited(t *testing.T) {
+	town := t.TempDir()
+	for path, content := range map[string]string{
+		"mayor/town.json":      `{"name":"test"}`,
+		"settings/config.json": `{"rate_limits":{"per_minute":1,"burst":1}}`,
+	} {
+		p := filepath.Join(town, path)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler.workDir = town
+
+	if w := httptest.NewRecorder(); !handler.allowWrite(w) {
+		t.Fatalf("first write refused: %d %s", w.Code, w.Body.String())
+	}
+	w := httptest.NewRecorder()
+	if handler.allowWrite(w) {
+		t.Fatal("second write allowed over a burst of 1")
+	}
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After header")
+	}
+}
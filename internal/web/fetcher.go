@@ -16,6 +16,8 @@ import (
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/metrics"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -44,6 +46,12 @@ func runCmd(timeout time.Duration, name string, args ...string) (*bytes.Buffer,
 
 var fetcherRunCmd = runCmd
 
+// Health panel trends: the last day of metrics, in sparklines this wide.
+const (
+	trendWindow = 24 * time.Hour
+	trendWidth  = 32
+)
+
 // runBdCmd executes a bd command with the configured cmdTimeout in the specified beads directory.
 func (f *LiveConvoyFetcher) runBdCmd(beadsDir string, args ...string) (*bytes.Buffer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), f.cmdTimeout)
@@ -1048,12 +1056,17 @@ func (f *LiveConvoyFetcher) FetchRigs() ([]RigRow, error) {
 		return nil, fmt.Errorf("loading rigs config: %w", err)
 	}
 
+	scores := f.doctorScores()
+
 	var rows []RigRow
 	for name, entry := range rigsConfig.Rigs {
 		row := RigRow{
 			Name:   name,
 			GitURL: entry.GitURL,
 		}
+		if score, ok := scores[name]; ok {
+			row.HealthScore = fmt.Sprintf("%d", score.Score)
+		}
 
 		rigPath := filepath.Join(f.townRoot, name)
 
@@ -1258,9 +1271,46 @@ func (f *LiveConvoyFetcher) FetchHealth() (*HealthRow, error) {
 		}
 	}
 
+	// Score of the latest town-wide doctor run
+	if score, ok := f.doctorScores()[""]; ok {
+		row.HasDoctorScore = true
+		row.DoctorScore = score.Score
+		row.DoctorScoreAge = formatTimestamp(score.Timestamp)
+	}
+
+	// Last day of the metrics history the daemon samples
+	if points, err := metrics.LoadPoints(metrics.SeriesPath(f.townRoot), time.Now().Add(-trendWindow)); err == nil && len(points) > 1 {
+		for _, t := range metrics.Trends(points, "", trendWidth) {
+			row.Trends = append(row.Trends, TrendRow{
+				Label:     t.Label,
+				Sparkline: t.Sparkline,
+				Latest:    t.Format(t.Latest),
+				Range:     t.Format(t.Min) + " – " + t.Format(t.Max),
+			})
+		}
+	}
+
 	return row, nil
 }
 
+// doctorScores returns the health score of the latest doctor run per scope
+// ("" for the town, else the rig name). Empty if doctor never ran.
+func (f *LiveConvoyFetcher) doctorScores() map[string]doctor.HealthScore {
+	scores := make(map[string]doctor.HealthScore)
+	cfg, err := doctor.LoadScoreConfig(f.townRoot)
+	if err != nil {
+		return scores
+	}
+	history, err := doctor.LoadHistory(f.townRoot)
+	if err != nil {
+		return scores
+	}
+	for _, s := range doctor.LatestScores(history, cfg) {
+		scores[s.Rig] = s
+	}
+	return scores
+}
+
 // FetchQueues returns work queues and their status.
 func (f *LiveConvoyFetcher) FetchQueues() ([]QueueRow, error) {
 	// List queue beads
@@ -41,6 +41,7 @@ type RigRow struct {
 	CrewCount    int
 	HasWitness   bool
 	HasRefinery  bool
+	HealthScore  string // Latest 'gt doctor --rig' score, "" if never checked
 }
 
 // DogRow represents a Deacon helper worker.
@@ -71,6 +72,18 @@ type HealthRow struct {
 	IsPaused        bool
 	PauseReason     string
 	HeartbeatFresh  bool // true if < 5min old
+	HasDoctorScore  bool
+	DoctorScore     int    // Latest 'gt doctor' health score (0-100)
+	DoctorScoreAge  string // When that doctor run happened
+	Trends          []TrendRow
+}
+
+// TrendRow is one series of the last day of 'gt town metrics'.
+type TrendRow struct {
+	Label     string
+	Sparkline string
+	Latest    string
+	Range     string // "min – max" over the window
 }
 
 // QueueRow represents a work queue.
@@ -236,3 +236,31 @@ func TestConvoyTemplate_EmptyState(t *testing.T) {
 		t.Error("Template should show empty state message when no convoys")
 	}
 }
+
+func TestConvoyTemplate_RendersTrends(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "convoy.html", ConvoyData{Health: &HealthRow{}}); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "📈 Trends") {
+		t.Error("Trends panel should be hidden without samples")
+	}
+
+	data := ConvoyData{Health: &HealthRow{Trends: []TrendRow{
+		{Label: "MQ depth", Sparkline: "▁▃█▅", Latest: "5", Range: "0 – 9"},
+	}}}
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "convoy.html", data); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+	for _, want := range []string{"📈 Trends", "MQ depth", "▁▃█▅", "0 – 9"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Template should contain %q", want)
+		}
+	}
+}
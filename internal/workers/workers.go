@@ -0,0 +1,241 @@
+// Package workers keeps a registry of the town's workers: the polecat and
+// crew agents that claim and work beads.
+//
+// Each worker has one record under <town>/.runtime/workers/<rig>/<role>/,
+// refreshed by every gt command the worker runs (its heartbeat). Records
+// are separate files so concurrent workers never contend for a write.
+//
+// A worker is active while its session's agent runs and it heartbeats,
+// stale when the agent runs but has not run gt for StaleAfter, and dead
+// when the agent is gone. A worker dead for ReleaseAfter has its claimed
+// beads returned to the pool and its record dropped ('gt workers reap',
+// run by the daemon); it registers again on its next heartbeat.
+package workers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Dir is the registry directory, relative to the town root.
+const Dir = ".runtime/workers"
+
+const (
+	// StaleAfter is how long a live worker can go without a heartbeat
+	// before it is reported stale: stuck, or waiting on input.
+	StaleAfter = 30 * time.Minute
+
+	// ReleaseAfter is how long after its last heartbeat a dead worker's
+	// beads are released. The grace covers a session being restarted
+	// (handoff), whose new agent heartbeats within seconds.
+	ReleaseAfter = 10 * time.Minute
+
+	// heartbeatInterval throttles heartbeat writes: a record touched more
+	// recently with the same details is left alone.
+	heartbeatInterval = 30 * time.Second
+)
+
+// Worker roles, as in their addresses.
+const (
+	RolePolecat = "polecat"
+	RoleCrew    = "crew"
+)
+
+// State is a worker's liveness.
+type State string
+
+const (
+	StateActive State = "active" // Agent running and heartbeating
+	StateStale  State = "stale"  // Agent running, no heartbeat for StaleAfter
+	StateDead   State = "dead"   // Session or agent gone
+)
+
+// Record is one worker's registry entry.
+type Record struct {
+	Rig           string    `json:"rig"`
+	Role          string    `json:"role"` // RolePolecat or RoleCrew
+	Name          string    `json:"name"`
+	Session       string    `json:"session"`
+	PID           int       `json:"pid,omitempty"` // Agent pane's process, as last seen
+	Workdir       string    `json:"workdir,omitempty"`
+	Branch        string    `json:"branch,omitempty"`
+	Bead          string    `json:"bead,omitempty"` // Hooked or in-progress bead, as last seen
+	RegisteredAt  time.Time `json:"registered_at"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	LastCommand   string    `json:"last_command,omitempty"`
+}
+
+// Address returns the worker's mail address, e.g. gastown/polecats/toast.
+func (r *Record) Address() string {
+	return r.Rig + "/" + roleDir(r.Role) + "/" + r.Name
+}
+
+// Holders returns the identities the worker may claim beads as: its
+// address, and for a polecat the short rig/name form as well.
+func (r *Record) Holders() []string {
+	if r.Role == RolePolecat {
+		return []string{r.Address(), r.Rig + "/" + r.Name}
+	}
+	return []string{r.Address()}
+}
+
+// State returns the worker's liveness at now, given whether its agent is
+// running.
+func (r *Record) State(alive bool, now time.Time) State {
+	switch {
+	case !alive:
+		return StateDead
+	case now.Sub(r.LastHeartbeat) >= StaleAfter:
+		return StateStale
+	default:
+		return StateActive
+	}
+}
+
+// Releasable reports whether a dead worker's beads can be released at now:
+// its last heartbeat is at least ReleaseAfter old.
+func (r *Record) Releasable(alive bool, now time.Time) bool {
+	return !alive && now.Sub(r.LastHeartbeat) >= ReleaseAfter
+}
+
+// roleDir returns the directory of role's workers in a rig.
+func roleDir(role string) string {
+	if role == RolePolecat {
+		return "polecats"
+	}
+	return role
+}
+
+// Path returns the record file of a worker.
+func Path(townRoot, rig, role, name string) string {
+	return filepath.Join(townRoot, Dir, rig, roleDir(role), name+".json")
+}
+
+// Read returns a worker's record, or nil if it has none or it can't be
+// parsed.
+func Read(townRoot, rig, role, name string) *Record {
+	return readRecord(Path(townRoot, rig, role, name))
+}
+
+func readRecord(path string) *Record {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil || r.Rig == "" || r.Name == "" {
+		return nil
+	}
+	return &r
+}
+
+// Heartbeat records that the worker described by r is alive at now,
+// registering it if needed. Its registration time and last seen PID and
+// bead are kept; the rest of the record is replaced by r's.
+func Heartbeat(townRoot string, r Record, now time.Time) error {
+	if r.Role != RolePolecat && r.Role != RoleCrew {
+		return fmt.Errorf("worker %s/%s: unknown role %q", r.Rig, r.Name, r.Role)
+	}
+	path := Path(townRoot, r.Rig, r.Role, r.Name)
+	r.RegisteredAt = now
+	r.LastHeartbeat = now
+	if old := readRecord(path); old != nil {
+		if now.Sub(old.LastHeartbeat) < heartbeatInterval && old.Session == r.Session &&
+			old.Branch == r.Branch && old.Workdir == r.Workdir {
+			return nil
+		}
+		r.RegisteredAt = old.RegisteredAt
+		if r.PID == 0 {
+			r.PID = old.PID
+		}
+		if r.Bead == "" {
+			r.Bead = old.Bead
+		}
+	}
+	return Save(townRoot, &r)
+}
+
+// Save writes r to the registry.
+func Save(townRoot string, r *Record) error {
+	path := Path(townRoot, r.Rig, r.Role, r.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating workers directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, r)
+}
+
+// Remove drops r from the registry. A missing record is not an error.
+func Remove(townRoot string, r *Record) error {
+	err := os.Remove(Path(townRoot, r.Rig, r.Role, r.Name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the registered workers, of one rig when rig is set, sorted
+// by address. Unreadable records are skipped.
+func List(townRoot, rig string) ([]*Record, error) {
+	if rig == "" {
+		rig = "*"
+	}
+	paths, err := filepath.Glob(filepath.Join(townRoot, Dir, rig, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, path := range paths {
+		if r := readRecord(path); r != nil {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Address() < records[j].Address() })
+	return records, nil
+}
+
+// GitBranch returns the branch checked out in the git work tree containing
+// dir, or "" if there is none or HEAD is detached. It reads HEAD directly
+// rather than running git, so it is cheap enough for every heartbeat.
+func GitBranch(dir string) string {
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		gitPath := filepath.Join(d, ".git")
+		info, err := os.Stat(gitPath)
+		if err == nil {
+			gitDir := gitPath
+			if !info.IsDir() {
+				// A worktree: .git is a file pointing at its git dir.
+				data, err := os.ReadFile(gitPath) //nolint:gosec // G304: path is constructed internally
+				if err != nil {
+					return ""
+				}
+				target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+				if !ok {
+					return ""
+				}
+				gitDir = strings.TrimSpace(target)
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(d, gitDir)
+				}
+			}
+			head, err := os.ReadFile(filepath.Join(gitDir, "HEAD")) //nolint:gosec // G304: path is constructed internally
+			if err != nil {
+				return ""
+			}
+			branch, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+			if !ok {
+				return "" // detached
+			}
+			return branch
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package workers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatRegistersAndRefreshes(t *testing.T) {
+	town := t.TempDir()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := Record{Rig: "gastown", Role: RolePolecat, Name: "toast", Session: "gt-toast", Branch: "polecat/toast"}
+
+	if err := Heartbeat(town, rec, start); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(town, ".runtime", "workers", "gastown", "polecats", "toast.json")); err != nil {
+		t.Fatalf("record not written: %v", err)
+	}
+
+	// Within the interval and unchanged: left alone.
+	if err := Heartbeat(town, rec, start.Add(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if got := Read(town, "gastown", RolePolecat, "toast"); !got.LastHeartbeat.Equal(start) {
+		t.Errorf("LastHeartbeat = %v, want throttled at %v", got.LastHeartbeat, start)
+	}
+
+	// Later: refreshed, keeping the registration time and last seen bead.
+	saved := Read(town, "gastown", RolePolecat, "toast")
+	saved.Bead, saved.PID = "gt-abc", 4242
+	if err := Save(town, saved); err != nil {
+		t.Fatal(err)
+	}
+	later := start.Add(time.Minute)
+	if err := Heartbeat(town, rec, later); err != nil {
+		t.Fatal(err)
+	}
+	got := Read(town, "gastown", RolePolecat, "toast")
+	if !got.LastHeartbeat.Equal(later) || !got.RegisteredAt.Equal(start) || got.Bead != "gt-abc" || got.PID != 4242 {
+		t.Errorf("record = %+v", got)
+	}
+
+	if err := Heartbeat(town, Record{Rig: "gastown", Role: "witness", Name: "w"}, start); err == nil {
+		t.Error("Heartbeat accepted a witness")
+	}
+}
+
+func TestListAndRemove(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	for _, r := range []Record{
+		{Rig: "gastown", Role: RoleCrew, Name: "max"},
+		{Rig: "gastown", Role: RolePolecat, Name: "toast"},
+		{Rig: "beads", Role: RolePolecat, Name: "nux"},
+	} {
+		if err := Heartbeat(town, r, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(town, Dir, "beads", "polecats", "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := List(town, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []string
+	for _, r := range all {
+		addrs = append(addrs, r.Address())
+	}
+	want := []string{"beads/polecats/nux", "gastown/crew/max", "gastown/polecats/toast"}
+	if !slices.Equal(addrs, want) {
+		t.Errorf("List = %v, want %v", addrs, want)
+	}
+
+	if err := Remove(town, all[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(town, all[1]); err != nil {
+		t.Errorf("removing a missing record: %v", err)
+	}
+	rig, err := List(town, "gastown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rig) != 1 || rig[0].Name != "toast" {
+		t.Errorf("List(gastown) = %+v", rig)
+	}
+}
+
+func TestStateAndReleasable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		age        time.Duration
+		alive      bool
+		want       State
+		releasable bool
+	}{
+		{"active", time.Minute, true, StateActive, false},
+		{"stale", StaleAfter, true, StateStale, false},
+		{"just died", time.Minute, false, StateDead, false},
+		{"dead past grace", ReleaseAfter, false, StateDead, true},
+	}
+	for _, tt := range tests {
+		r := &Record{LastHeartbeat: now.Add(-tt.age)}
+		if got := r.State(tt.alive, now); got != tt.want {
+			t.Errorf("%s: State = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := r.Releasable(tt.alive, now); got != tt.releasable {
+			t.Errorf("%s: Releasable = %v, want %v", tt.name, got, tt.releasable)
+		}
+	}
+}
+
+func TestHolders(t *testing.T) {
+	polecat := &Record{Rig: "gastown", Role: RolePolecat, Name: "toast"}
+	if got := polecat.Holders(); !slices.Equal(got, []string{"gastown/polecats/toast", "gastown/toast"}) {
+		t.Errorf("polecat holders = %v", got)
+	}
+	crew := &Record{Rig: "gastown", Role: RoleCrew, Name: "max"}
+	if got := crew.Holders(); !slices.Equal(got, []string{"gastown/crew/max"}) {
+		t.Errorf("crew holders = %v", got)
+	}
+}
+
+func TestGitBranch(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	write(filepath.Join(repo, "src", "x.go"), "")
+	if got := GitBranch(filepath.Join(repo, "src")); got != "main" {
+		t.Errorf("GitBranch(repo/src) = %q, want main", got)
+	}
+
+	// A worktree's .git file points at its git dir, relative or absolute.
+	wt := filepath.Join(root, "wt")
+	write(filepath.Join(repo, ".git", "worktrees", "wt", "HEAD"), "ref: refs/heads/polecat/toast\n")
+	write(filepath.Join(wt, ".git"), "gitdir: ../repo/.git/worktrees/wt\n")
+	if got := GitBranch(wt); got != "polecat/toast" {
+		t.Errorf("GitBranch(worktree) = %q, want polecat/toast", got)
+	}
+
+	write(filepath.Join(repo, ".git", "HEAD"), "0123456789abcdef0123456789abcdef01234567\n")
+	if got := GitBranch(repo); got != "" {
+		t.Errorf("GitBranch(detached) = %q, want empty", got)
+	}
+}
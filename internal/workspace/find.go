@@ -84,8 +84,12 @@ func FindOrError(startDir string) (string, error) {
 	return root, nil
 }
 
-// FindFromCwd locates the town root from the current working directory.
+// FindFromCwd locates the town root from the current working directory,
+// unless a town was selected with --town or GT_TOWN (see SetOverride).
 func FindFromCwd() (string, error) {
+	if root, err := selectedTown(); root != "" || err != nil {
+		return root, err
+	}
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("getting current directory: %w", err)
@@ -96,6 +100,9 @@ func FindFromCwd() (string, error) {
 // FindFromCwdOrError is like FindFromCwd but returns an error if not found.
 // If getcwd fails (e.g., worktree deleted), falls back to GT_TOWN_ROOT env var.
 func FindFromCwdOrError() (string, error) {
+	if root, err := selectedTown(); root != "" || err != nil {
+		return root, err
+	}
 	cwd, err := os.Getwd()
 	if err != nil {
 		// Fallback: try GT_TOWN_ROOT env var (set by polecat sessions)
@@ -115,6 +122,13 @@ func FindFromCwdOrError() (string, error) {
 // This is useful for commands like `gt done` that need to continue even if the
 // working directory is deleted (e.g., polecat worktree nuked by Witness).
 func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
+	if townRoot, err = selectedTown(); err != nil {
+		return "", "", err
+	}
+	if townRoot != "" {
+		cwd, _ = os.Getwd()
+		return townRoot, cwd, nil
+	}
 	cwd, err = os.Getwd()
 	if err != nil {
 		// Fallback: try GT_TOWN_ROOT env var
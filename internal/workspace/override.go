@@ -0,0 +1,175 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// TownEnvVar selects a town by path or name for every command, overriding
+// discovery from the current directory. The --town flag takes precedence.
+const TownEnvVar = "GT_TOWN"
+
+var (
+	overrideMu   sync.Mutex
+	overrideRoot string // town selected with SetOverride (--town)
+	envSpec      string // TownEnvVar value last resolved into envRoot/envErr
+	envRoot      string
+	envErr       error
+)
+
+// SetOverride selects the town for the rest of the process, as the --town
+// flag does: the FindFromCwd family returns it instead of walking up from
+// the current directory. spec is a path or a town name (see Resolve). The
+// resolved root is exported as GT_TOWN so gt subprocesses use it too. An
+// empty spec clears the override.
+func SetOverride(spec string) error {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	if spec == "" {
+		overrideRoot = ""
+		return nil
+	}
+	root, err := Resolve(spec)
+	if err != nil {
+		return err
+	}
+	overrideRoot = root
+	return os.Setenv(TownEnvVar, root)
+}
+
+// selectedTown returns the town chosen with SetOverride or TownEnvVar, or
+// "" when the town should be discovered from the current directory.
+func selectedTown() (string, error) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	if overrideRoot != "" {
+		return overrideRoot, nil
+	}
+	spec := os.Getenv(TownEnvVar)
+	if spec == "" {
+		return "", nil
+	}
+	if spec != envSpec {
+		envRoot, envErr = Resolve(spec)
+		if envErr != nil {
+			envErr = fmt.Errorf("%s: %w", TownEnvVar, envErr)
+		}
+		envSpec = spec
+	}
+	return envRoot, envErr
+}
+
+// Resolve turns a town spec into a town root. A spec that names an existing
+// directory, or looks like a path (contains a separator, starts with "." or
+// "~"), must be a town root. Anything else is a town name (mayor/town.json
+// "name"), looked up among the towns enclosing the current directory, their
+// siblings, and the directories in $HOME.
+func Resolve(spec string) (string, error) {
+	if looksLikePath(spec) {
+		path := spec
+		if strings.HasPrefix(path, "~") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("resolving %s: %w", spec, err)
+			}
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+		root, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("resolving path: %w", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, PrimaryMarker)); err != nil {
+			return "", fmt.Errorf("%s is not a town root (no %s)", root, PrimaryMarker)
+		}
+		return root, nil
+	}
+
+	var matches []string
+	for _, dir := range candidateTowns() {
+		if name, err := GetTownName(dir); err == nil && name == spec {
+			matches = append(matches, dir)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no town named %q found; give its path instead", spec)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("town name %q is ambiguous (%s); give a path instead", spec, strings.Join(matches, ", "))
+}
+
+// Enclosing returns every town root containing dir, innermost first. Find
+// returns only one of them; nested towns need the rest.
+func Enclosing(dir string) []string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	var roots []string
+	for current := absDir; ; {
+		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
+			roots = append(roots, current)
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return roots
+		}
+		current = parent
+	}
+}
+
+func looksLikePath(spec string) bool {
+	if strings.ContainsRune(spec, filepath.Separator) || strings.ContainsRune(spec, '/') ||
+		strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "~") {
+		return true
+	}
+	info, err := os.Stat(spec)
+	return err == nil && info.IsDir()
+}
+
+// candidateTowns lists the town roots a town name is looked up in, without
+// duplicates: the towns enclosing the current directory, their siblings,
+// and the towns directly under $HOME.
+func candidateTowns() []string {
+	seen := make(map[string]bool)
+	var towns []string
+	add := func(dir string) {
+		if seen[dir] {
+			return
+		}
+		seen[dir] = true
+		if _, err := os.Stat(filepath.Join(dir, PrimaryMarker)); err == nil {
+			towns = append(towns, dir)
+		}
+	}
+	addChildren := func(parent string) {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				add(filepath.Join(parent, e.Name()))
+			}
+		}
+	}
+
+	var parents []string
+	if cwd, err := os.Getwd(); err == nil {
+		for _, root := range Enclosing(cwd) {
+			add(root)
+			parents = append(parents, filepath.Dir(root))
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		parents = append(parents, home)
+	}
+	for _, p := range parents {
+		addChildren(p)
+	}
+	return towns
+}
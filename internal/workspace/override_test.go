@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeTown(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PrimaryMarker), []byte(`{"type":"town","name":"`+name+`"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSelectedTown_NestedAndSibling(t *testing.T) {
+	base := realPath(t, t.TempDir())
+	outer := makeTown(t, filepath.Join(base, "outer"), "outer")
+	inner := makeTown(t, filepath.Join(outer, "labs", "inner"), "inner")
+	sibling := makeTown(t, filepath.Join(base, "sibling"), "sibling")
+	t.Chdir(inner)
+	t.Cleanup(func() { _ = SetOverride("") })
+
+	if got, _ := FindFromCwdOrError(); got != inner {
+		t.Fatalf("discovery = %q, want the innermost town %q", got, inner)
+	}
+	if got := Enclosing(inner); len(got) != 2 || got[1] != outer {
+		t.Errorf("Enclosing = %v, want [inner outer]", got)
+	}
+
+	// GT_TOWN by name reaches the enclosing town and a sibling of it.
+	t.Setenv(TownEnvVar, "outer")
+	if got, err := FindFromCwdOrError(); err != nil || got != outer {
+		t.Errorf("GT_TOWN=outer: %q, %v", got, err)
+	}
+	t.Setenv(TownEnvVar, "sibling")
+	if got, err := FindFromCwd(); err != nil || got != sibling {
+		t.Errorf("GT_TOWN=sibling: %q, %v", got, err)
+	}
+	t.Setenv(TownEnvVar, "nowhere")
+	if _, err := FindFromCwdOrError(); err == nil {
+		t.Error("unknown GT_TOWN should be an error, not a fallback to discovery")
+	}
+
+	// --town wins over GT_TOWN, and is exported for subprocesses.
+	if err := SetOverride(filepath.Join("..", "..")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, err := FindFromCwdWithFallback(); err != nil || got != outer {
+		t.Errorf("--town ../..: %q, %v", got, err)
+	}
+	if os.Getenv(TownEnvVar) != outer {
+		t.Errorf("%s = %q, want %q", TownEnvVar, os.Getenv(TownEnvVar), outer)
+	}
+	if err := SetOverride(filepath.Join(base, "missing")); err == nil {
+		t.Error("--town with a non-town path should fail")
+	}
+}
+
+func TestResolve_AmbiguousName(t *testing.T) {
+	base := realPath(t, t.TempDir())
+	makeTown(t, filepath.Join(base, "a"), "dup")
+	makeTown(t, filepath.Join(base, "b"), "dup")
+	here := makeTown(t, filepath.Join(base, "here"), "here")
+	t.Chdir(here)
+
+	if _, err := Resolve("dup"); err == nil {
+		t.Error("two sibling towns named dup should be ambiguous")
+	}
+}